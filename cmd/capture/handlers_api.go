@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
 )
 
 func (s *server) handleSessions(w http.ResponseWriter, r *http.Request) {
@@ -109,6 +111,24 @@ func (s *server) handleSession(w http.ResponseWriter, r *http.Request) {
 		}
 		writeJSON(w, http.StatusOK, out)
 		return
+	case "gantt":
+		events, err := s.store.DeployEvents(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		bars := deploy.BuildGantt(events)
+		if r.URL.Query().Get("format") == "svg" {
+			if len(bars) == 0 {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+			_, _ = w.Write([]byte(deploy.RenderGanttSVG(bars)))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"bars": bars})
+		return
 	default:
 		http.NotFound(w, r)
 		return
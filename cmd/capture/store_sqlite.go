@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
@@ -11,6 +12,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kubekattle/ktl/internal/capture"
+	"github.com/kubekattle/ktl/internal/deploy"
 	_ "modernc.org/sqlite"
 )
 
@@ -551,3 +554,38 @@ LIMIT %d
 	}
 	return out, rows.Err()
 }
+
+// DeployEvents returns the session's recorded deploy stream events in capture order.
+func (s *sqliteStore) DeployEvents(ctx context.Context, sessionID string) ([]deploy.StreamEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+SELECT COALESCE(payload_type, ''), payload_blob, COALESCE(payload_json, '')
+FROM ktl_capture_events
+WHERE session_id = ? AND kind = 'deploy'
+ORDER BY COALESCE(seq, id)
+`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []deploy.StreamEvent
+	for rows.Next() {
+		var (
+			payloadType string
+			payloadBlob []byte
+			payloadJSON string
+		)
+		if err := rows.Scan(&payloadType, &payloadBlob, &payloadJSON); err != nil {
+			return nil, err
+		}
+		raw, err := capture.DecodePayload(payloadType, payloadBlob, payloadJSON)
+		if err != nil || len(raw) == 0 {
+			continue
+		}
+		var evt deploy.StreamEvent
+		if err := json.Unmarshal(raw, &evt); err != nil {
+			continue
+		}
+		out = append(out, evt)
+	}
+	return out, rows.Err()
+}
@@ -127,4 +127,12 @@ func TestSQLiteStore_ReadsKtlCaptureDB(t *testing.T) {
 	if len(eventsPage.Events) < 2 {
 		t.Fatalf("Events=%d, want >=2", len(eventsPage.Events))
 	}
+
+	deployEvents, err := st.DeployEvents(context.Background(), sessionID)
+	if err != nil {
+		t.Fatalf("DeployEvents: %v", err)
+	}
+	if len(deployEvents) != 1 || deployEvents[0].Log == nil || deployEvents[0].Log.Message != "waiting for rollout" {
+		t.Fatalf("DeployEvents=%+v, want the recorded log event", deployEvents)
+	}
 }
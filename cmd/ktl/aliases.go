@@ -0,0 +1,308 @@
+// File: cmd/ktl/aliases.go
+// Brief: CLI command wiring and implementation for 'aliases'.
+
+// aliases.go expands user-defined command aliases (the aliases block in .ktl.yaml or
+// ~/.ktl/config.yaml) before Cobra parses the command line.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/mattn/go-shellwords"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// maxAliasDepth bounds alias-to-alias expansion.
+const maxAliasDepth = 8
+
+var aliasPlaceholder = regexp.MustCompile(`\$([0-9]+)`)
+
+func loadAliases(ctx context.Context) (map[string]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	repoRoot := appconfig.FindRepoRoot(cwd)
+	cfg, err := appconfig.Load(ctx, appconfig.DefaultGlobalPath(), appconfig.DefaultRepoPath(repoRoot))
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Aliases, nil
+}
+
+// isBuiltinCommand reports whether name resolves to a ktl subcommand. Aliases never shadow them.
+func isBuiltinCommand(root *cobra.Command, name string) bool {
+	switch name {
+	case "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	for _, c := range root.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// aliasCandidate returns the index of the argument that may name an alias: the first argument
+// after any leading global flags, or the one after Cobra's hidden completion command.
+func aliasCandidate(args []string, globals *pflag.FlagSet) (int, bool) {
+	idx := 1
+	completing := isCompletionRequest(args)
+	if completing {
+		idx = 2
+	}
+	idx = skipGlobalFlags(args, idx, globals)
+	if len(args) <= idx || !looksLikeAliasName(args[idx]) {
+		return 0, false
+	}
+	// While completing the alias name itself there is nothing to expand yet.
+	if completing && idx == len(args)-1 {
+		return 0, false
+	}
+	return idx, true
+}
+
+func isCompletionRequest(args []string) bool {
+	return len(args) > 1 && (args[1] == cobra.ShellCompRequestCmd || args[1] == cobra.ShellCompNoDescRequestCmd)
+}
+
+// skipGlobalFlags returns the index of the first argument at or after i that is neither one of
+// the globals flags nor the value of one, so `ktl --context prod pprod` still finds pprod. An
+// unknown flag stops the scan because it is not known whether it takes a value.
+func skipGlobalFlags(args []string, i int, globals *pflag.FlagSet) int {
+	if globals == nil {
+		return i
+	}
+	for i < len(args) {
+		arg := args[i]
+		if arg == "-" || arg == "--" || !strings.HasPrefix(arg, "-") {
+			return i
+		}
+		var (
+			flag     *pflag.Flag
+			hasValue bool
+		)
+		if name, ok := strings.CutPrefix(arg, "--"); ok {
+			name, _, hasValue = strings.Cut(name, "=")
+			flag = globals.Lookup(name)
+		} else {
+			// -K prod, -Kprod, or -K=prod.
+			short := strings.TrimPrefix(arg, "-")
+			flag = globals.ShorthandLookup(short[:1])
+			hasValue = len(short) > 1
+		}
+		if flag == nil {
+			return i
+		}
+		i++
+		if !hasValue && flag.NoOptDefVal == "" {
+			i++
+		}
+	}
+	return i
+}
+
+func looksLikeAliasName(arg string) bool {
+	return arg != "" && !strings.HasPrefix(arg, "-") && !strings.ContainsAny(arg, " \t/=")
+}
+
+// expandAliasArgs rewrites os.Args-style args when they invoke an alias. builtin reports names
+// that must not be treated as aliases; globals are the flags that may precede the alias.
+func expandAliasArgs(args []string, globals *pflag.FlagSet, builtin func(string) bool, aliases map[string]string) ([]string, error) {
+	idx, ok := aliasCandidate(args, globals)
+	if !ok || builtin(args[idx]) {
+		return args, nil
+	}
+	if _, ok := aliases[args[idx]]; !ok {
+		return args, nil
+	}
+	expanded, err := expandAlias(args[idx], args[idx+1:], builtin, aliases, 0)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, idx+len(expanded))
+	out = append(out, args[:idx]...)
+	return append(out, expanded...), nil
+}
+
+func expandAlias(name string, rest []string, builtin func(string) bool, aliases map[string]string, depth int) ([]string, error) {
+	if depth >= maxAliasDepth {
+		return nil, fmt.Errorf("alias %q: expansion is recursive or nested more than %d levels deep", name, maxAliasDepth)
+	}
+	words, err := shellwords.Parse(aliases[name])
+	if err != nil {
+		return nil, fmt.Errorf("alias %q: %w", name, err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("alias %q expands to an empty command", name)
+	}
+	expanded, err := substituteAliasArgs(name, words, rest)
+	if err != nil {
+		return nil, err
+	}
+	head := expanded[0]
+	if _, ok := aliases[head]; ok && !builtin(head) {
+		return expandAlias(head, expanded[1:], builtin, aliases, depth+1)
+	}
+	return expanded, nil
+}
+
+// substituteAliasArgs replaces $1..$N with the matching extra argument and $@ with all of them.
+// Arguments not referenced by a placeholder are appended, so `ktl pprod --wait` still works.
+func substituteAliasArgs(name string, words, rest []string) ([]string, error) {
+	used := make([]bool, len(rest))
+	spread := false
+	out := make([]string, 0, len(words)+len(rest))
+	for _, word := range words {
+		if word == "$@" {
+			out = append(out, rest...)
+			spread = true
+			continue
+		}
+		var missing error
+		word = aliasPlaceholder.ReplaceAllStringFunc(word, func(m string) string {
+			n, _ := strconv.Atoi(m[1:])
+			if n < 1 || n > len(rest) {
+				if missing == nil {
+					missing = fmt.Errorf("alias %q expects at least %d argument(s), got %d", name, n, len(rest))
+				}
+				return m
+			}
+			used[n-1] = true
+			return rest[n-1]
+		})
+		if missing != nil {
+			return nil, missing
+		}
+		out = append(out, word)
+	}
+	if spread {
+		return out, nil
+	}
+	for i, arg := range rest {
+		if !used[i] {
+			out = append(out, arg)
+		}
+	}
+	return out, nil
+}
+
+// resolveAliasArgs expands args against the configured aliases. Config is only read when the
+// first argument after the global flags is not a built-in command, so regular invocations pay
+// nothing. Completion requests ignore config errors rather than breaking the shell.
+func resolveAliasArgs(ctx context.Context, root *cobra.Command, args []string) ([]string, error) {
+	globals := root.PersistentFlags()
+	idx, ok := aliasCandidate(args, globals)
+	if !ok || isBuiltinCommand(root, args[idx]) {
+		return args, nil
+	}
+	aliases, err := loadAliases(ctx)
+	if err != nil {
+		if isCompletionRequest(args) {
+			return args, nil
+		}
+		return nil, err
+	}
+	builtin := func(name string) bool { return isBuiltinCommand(root, name) }
+	return expandAliasArgs(args, globals, builtin, aliases)
+}
+
+// completeAliases offers alias names, with their expansion as the description, next to the
+// built-in subcommands.
+func completeAliases(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	aliases, err := loadAliases(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	root := cmd.Root()
+	cfg := appconfig.Config{Aliases: aliases}
+	var out []string
+	for _, name := range cfg.AliasNames() {
+		if !strings.HasPrefix(name, toComplete) || isBuiltinCommand(root, name) {
+			continue
+		}
+		out = append(out, name+"\t"+aliases[name])
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+func newAliasCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage user-defined command aliases",
+		Long: `Aliases encode a team's standard invocations under the aliases block in .ktl.yaml
+(or ~/.ktl/config.yaml; repo entries win). Run one as ktl [global flags] <alias> [args...]. Inside an
+expansion $1..$N refer to the extra arguments and $@ to all of them; arguments that are not
+referenced are appended. Aliases cannot shadow built-in commands.
+
+Example .ktl.yaml:
+  aliases:
+    pprod: apply --chart ./chart --release foo -n prod --diff
+    tail: logs $1 -n prod --highlight ERROR`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newAliasListCommand())
+	return cmd
+}
+
+func newAliasListCommand() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:           "list",
+		Short:         "List user-defined command aliases",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			aliases, err := loadAliases(cmd.Context())
+			if err != nil {
+				return err
+			}
+			cfg := appconfig.Config{Aliases: aliases}
+			out := cmd.OutOrStdout()
+			if output == "yaml" {
+				enc := yaml.NewEncoder(out)
+				enc.SetIndent(2)
+				if err := enc.Encode(map[string]map[string]string{"aliases": aliases}); err != nil {
+					return err
+				}
+				return enc.Close()
+			}
+			names := cfg.AliasNames()
+			if len(names) == 0 {
+				fmt.Fprintln(cmd.ErrOrStderr(), "No aliases defined (add aliases to .ktl.yaml)")
+				return nil
+			}
+			root := cmd.Root()
+			tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tEXPANSION")
+			for _, name := range names {
+				expansion := aliases[name]
+				if isBuiltinCommand(root, name) {
+					expansion += "  (ignored: shadows a built-in command)"
+				}
+				fmt.Fprintf(tw, "%s\t%s\n", name, expansion)
+			}
+			return tw.Flush()
+		},
+	}
+	cmd.Flags().Var(newEnumStringValue(&output, "table", "yaml"), "output", "Output format: table or yaml")
+	decorateCommandHelp(cmd, "Alias Flags")
+	return cmd
+}
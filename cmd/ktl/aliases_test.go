@@ -0,0 +1,89 @@
+// File: cmd/ktl/aliases_test.go
+// Brief: CLI command wiring and implementation for 'aliases'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandAliasArgs(t *testing.T) {
+	builtin := func(name string) bool { return name == "apply" || name == "logs" || name == "version" }
+	aliases := map[string]string{
+		"pprod":   "apply --chart ./chart --release foo -n prod --diff",
+		"tail":    `logs $1 -n prod --highlight "ERROR|panic"`,
+		"rel":     "apply --release=$1 $@",
+		"p2":      "pprod --wait",
+		"version": "logs",
+	}
+	cases := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"plain", []string{"ktl", "pprod"}, []string{"ktl", "apply", "--chart", "./chart", "--release", "foo", "-n", "prod", "--diff"}},
+		{"extra args appended", []string{"ktl", "pprod", "--wait"}, []string{"ktl", "apply", "--chart", "./chart", "--release", "foo", "-n", "prod", "--diff", "--wait"}},
+		{"placeholder and quoting", []string{"ktl", "tail", "checkout", "-f"}, []string{"ktl", "logs", "checkout", "-n", "prod", "--highlight", "ERROR|panic", "-f"}},
+		{"spread", []string{"ktl", "rel", "foo", "--wait"}, []string{"ktl", "apply", "--release=foo", "foo", "--wait"}},
+		{"nested", []string{"ktl", "p2"}, []string{"ktl", "apply", "--chart", "./chart", "--release", "foo", "-n", "prod", "--diff", "--wait"}},
+		{"builtin wins", []string{"ktl", "version"}, []string{"ktl", "version"}},
+		{"unknown", []string{"ktl", "nope"}, []string{"ktl", "nope"}},
+		{"bool global flag first", []string{"ktl", "--no-color", "pprod"}, []string{"ktl", "--no-color", "apply", "--chart", "./chart", "--release", "foo", "-n", "prod", "--diff"}},
+		{"global flags with values first", []string{"ktl", "--context", "prod", "-k=/tmp/kc", "--log-level=debug", "-Kstage", "tail", "web"}, []string{"ktl", "--context", "prod", "-k=/tmp/kc", "--log-level=debug", "-Kstage", "logs", "web", "-n", "prod", "--highlight", "ERROR|panic"}},
+		{"global flag value is not an alias", []string{"ktl", "--context", "pprod"}, []string{"ktl", "--context", "pprod"}},
+		{"unknown flag stops the scan", []string{"ktl", "--nope", "pprod"}, []string{"ktl", "--nope", "pprod"}},
+		{"completion after global flags", []string{"ktl", "__complete", "--context", "prod", "pprod", "--w"}, []string{"ktl", "__complete", "--context", "prod", "apply", "--chart", "./chart", "--release", "foo", "-n", "prod", "--diff", "--w"}},
+		{"completion", []string{"ktl", "__complete", "pprod", "--w"}, []string{"ktl", "__complete", "apply", "--chart", "./chart", "--release", "foo", "-n", "prod", "--diff", "--w"}},
+		{"completing alias name", []string{"ktl", "__complete", "pprod"}, []string{"ktl", "__complete", "pprod"}},
+	}
+	globals := newRootCommand().PersistentFlags()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := expandAliasArgs(tc.args, globals, builtin, aliases)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExpandAliasArgsErrors(t *testing.T) {
+	builtin := func(string) bool { return false }
+	aliases := map[string]string{
+		"loop":  "loop",
+		"tail":  "logs $2",
+		"empty": "  ",
+	}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"ktl", "loop"}, "recursive"},
+		{[]string{"ktl", "tail", "one"}, "expects at least 2 argument(s), got 1"},
+		{[]string{"ktl", "empty"}, "empty command"},
+	} {
+		_, err := expandAliasArgs(tc.args, nil, builtin, aliases)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%q: expected error containing %q, got %v", tc.args, tc.want, err)
+		}
+	}
+}
+
+func TestIsBuiltinCommand(t *testing.T) {
+	root := newRootCommand()
+	for _, name := range []string{"apply", "logs", "help", "__complete", "alias"} {
+		if !isBuiltinCommand(root, name) {
+			t.Fatalf("expected %q to be a built-in command", name)
+		}
+	}
+	if isBuiltinCommand(root, "pprod") {
+		t.Fatalf("did not expect pprod to be a built-in command")
+	}
+}
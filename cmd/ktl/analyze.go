@@ -11,7 +11,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/glamour"
 	"github.com/fatih/color"
 	"github.com/kubekattle/ktl/internal/analyze"
 	"github.com/kubekattle/ktl/internal/kube"
@@ -71,7 +70,7 @@ Examples:
 			if len(args) > 0 {
 				targetPod = args[0]
 			}
-			return runAnalyze(cmd.Context(), kubeconfig, kubeContext, targetPod, namespace, useAI, aiProvider, aiModel, drift, cost, fix, cluster, profile, rbac, duration)
+			return runAnalyze(cmd.Context(), kubeconfig, kubeContext, targetPod, namespace, useAI, aiProvider, aiModel, drift, cost, fix, readOnlyProfileActive(cmd), cluster, profile, rbac, duration)
 		},
 	}
 
@@ -90,7 +89,7 @@ Examples:
 	return cmd
 }
 
-func runAnalyze(ctx context.Context, kubeconfig, kubeContext *string, podName, namespace string, useAI bool, provider string, model string, drift bool, cost bool, fix bool, cluster bool, profile bool, rbac bool, duration time.Duration) error {
+func runAnalyze(ctx context.Context, kubeconfig, kubeContext *string, podName, namespace string, useAI bool, provider string, model string, drift bool, cost bool, fix bool, readOnly bool, cluster bool, profile bool, rbac bool, duration time.Duration) error {
 	ctx, cancel := context.WithTimeout(ctx, duration)
 	defer cancel()
 
@@ -363,7 +362,10 @@ func runAnalyze(ctx context.Context, kubeconfig, kubeContext *string, podName, n
 		fmt.Printf("Suggested Patch:\n%s\n", diagnosis.Patch)
 
 		apply := fix
-		if !fix {
+		if readOnly {
+			// --fix is rejected up front; never offer to patch under the read-only profile either.
+			fmt.Printf("Not applying the patch: --profile %s is read-only.\n", readOnlyProfile)
+		} else if !fix {
 			fmt.Print("Apply this patch? [y/N]: ")
 			reader := bufio.NewReader(os.Stdin)
 			input, _ := reader.ReadString('\n')
@@ -536,20 +538,13 @@ func startChatLoop(ctx context.Context, ai *analyze.AIAnalyzer, initialDiagnosis
 			continue
 		}
 
-		// Render markdown nicely using Glamour
+		// Render markdown nicely for the terminal.
 		// If the response contains markdown features, we re-print the styled version
 		// This might be a bit duplicated, but it's much easier to read for code blocks.
 		if strings.Contains(response, "```") || strings.Contains(response, "# ") {
-			r, _ := glamour.NewTermRenderer(
-				glamour.WithAutoStyle(),
-				glamour.WithWordWrap(100),
-			)
-			out, err := r.Render(response)
-			if err == nil {
-				fmt.Println()
-				color.New(color.FgHiBlack).Println("--- Formatted View ---")
-				fmt.Println(out)
-			}
+			fmt.Println()
+			color.New(color.FgHiBlack).Println("--- Formatted View ---")
+			fmt.Println(ui.RenderMarkdown(response, 100))
 		}
 
 		// Assistant response
@@ -12,6 +12,8 @@ type approvalDecision struct {
 	Approved       bool
 	InteractiveTTY bool
 	NonInteractive bool
+	// Plain spells out prompts and their outcome on separate lines (--console=plain).
+	Plain bool
 }
 
 func approvedFromEnv() bool {
@@ -41,5 +43,6 @@ func approvalMode(cmd *cobra.Command, approved bool, nonInteractive bool) (appro
 		Approved:       approved,
 		InteractiveTTY: interactive,
 		NonInteractive: nonInteractive,
+		Plain:          plainConsole(cmd),
 	}, nil
 }
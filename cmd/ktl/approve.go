@@ -0,0 +1,287 @@
+// File: cmd/ktl/approve.go
+// Brief: CLI command wiring and implementation for 'approve'.
+
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/audit"
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/spf13/cobra"
+)
+
+// approvalAuditAnnotation carries the approval of an apply to its audit entry.
+const approvalAuditAnnotation = "ktl.dev/audit-approval"
+
+// approvalTokenEnv is read when --approval-token is not set.
+const approvalTokenEnv = "KTL_APPROVAL_TOKEN"
+
+func defaultApproverKeyPath() string {
+	home, _ := os.UserHomeDir()
+	if strings.TrimSpace(home) == "" {
+		return ""
+	}
+	return filepath.Join(home, ".ktl", "keys", "approver.json")
+}
+
+func currentUserName() string {
+	if u, err := user.Current(); err == nil && u != nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+func newApproveCommand() *cobra.Command {
+	var keyPath string
+	var name string
+	var ttl time.Duration
+	cmd := &cobra.Command{
+		Use:   "approve <plan-digest>",
+		Short: "Approve someone else's apply under --approval-policy",
+		Long: `Sign an approval token for the plan digest printed by 'ktl apply --approval-policy'.
+The person applying passes the token with --approval-token. Tokens are only accepted when signed
+by a key listed under deploy.approvers in the repo's .ktl.yaml (keys in ~/.ktl/config.yaml are
+ignored), not by the applier's own approver key or the approver listed under the applier's user
+name, for the exact plan digest, and before they expire.`,
+		Example: `  # Once: create your approver key and add the printed entry to .ktl.yaml
+  ktl approve keygen
+
+  # Approve a teammate's apply
+  ktl approve sha256:4f1c...`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			digest := strings.TrimSpace(args[0])
+			if !strings.HasPrefix(digest, "sha256:") {
+				return fmt.Errorf("plan digest must look like sha256:<hex>, got %q", digest)
+			}
+			if ttl <= 0 {
+				return fmt.Errorf("--ttl must be > 0")
+			}
+			_, _, priv, err := stack.LoadBundleKey(keyPath)
+			if err != nil {
+				return fmt.Errorf("load approver key %s: %w (create one with ktl approve keygen)", keyPath, err)
+			}
+			now := time.Now().UTC()
+			host, _ := os.Hostname()
+			token, err := deploy.SignApproval(deploy.Approval{
+				PlanDigest: digest,
+				Approver:   strings.TrimSpace(name),
+				Host:       host,
+				IssuedAt:   now,
+				ExpiresAt:  now.Add(ttl),
+			}, priv)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), token)
+			fmt.Fprintf(cmd.ErrOrStderr(), "Approved %s until %s. Hand the token above to the person applying (--approval-token).\n", digest, now.Add(ttl).Format(time.RFC3339))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&keyPath, "key", defaultApproverKeyPath(), "Approver key file")
+	cmd.Flags().StringVar(&name, "name", currentUserName(), "Name recorded in the token (the verified name comes from deploy.approvers)")
+	cmd.Flags().DurationVar(&ttl, "ttl", time.Hour, "How long the approval stays valid")
+	cmd.AddCommand(newApproveKeygenCommand())
+	decorateCommandHelp(cmd, "Approve Flags")
+	return cmd
+}
+
+func newApproveKeygenCommand() *cobra.Command {
+	var outPath string
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Create your approver key and print its .ktl.yaml entry",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			out := strings.TrimSpace(outPath)
+			if out == "" {
+				return fmt.Errorf("--out is required (no home directory)")
+			}
+			if _, err := os.Stat(out); err == nil && !force {
+				return fmt.Errorf("%s already exists (use --force to replace it)", out)
+			}
+			k, err := stack.GenerateEd25519Key()
+			if err != nil {
+				return err
+			}
+			raw, err := json.MarshalIndent(k, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(out), 0o700); err != nil {
+				return err
+			}
+			if err := os.WriteFile(out, append(raw, '\n'), 0o600); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s. Add yourself to deploy.approvers in .ktl.yaml:\n", out)
+			fmt.Fprintf(cmd.OutOrStdout(), "- name: %s\n  publicKey: %s\n", currentUserName(), k.PublicKey)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&outPath, "out", defaultApproverKeyPath(), "Key file to write")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing key")
+	return cmd
+}
+
+// loadRepoDeployConfig reads only the deploy section of the repo's .ktl.yaml. Approval policy and
+// approvers come from here: ~/.ktl/config.yaml belongs to the person applying, who must not be
+// able to add their own key or loosen the repo's policy.
+func loadRepoDeployConfig(ctx context.Context) (appconfig.DeployConfig, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return appconfig.DeployConfig{}, err
+	}
+	cfg, err := appconfig.Load(ctx, "", appconfig.DefaultRepoPath(appconfig.FindRepoRoot(cwd)))
+	if err != nil {
+		return appconfig.DeployConfig{}, err
+	}
+	return cfg.Deploy, nil
+}
+
+// repoApprovalPolicy returns the strictest deploy.approvalPolicy of the repo around the working
+// directory and the repos around dirs (for example a stack root or a checkout elsewhere), and the
+// approvers those repos trust.
+func repoApprovalPolicy(ctx context.Context, dirs ...string) (string, []appconfig.Approver, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", nil, err
+	}
+	policy := deploy.ApprovalPolicyNone
+	var approvers []appconfig.Approver
+	seen := map[string]bool{}
+	for _, dir := range append([]string{cwd}, dirs...) {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		path := appconfig.DefaultRepoPath(appconfig.FindRepoRoot(dir))
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		cfg, err := appconfig.Load(ctx, "", path)
+		if err != nil {
+			return "", nil, err
+		}
+		p, err := deploy.ParseApprovalPolicy(cfg.Deploy.ApprovalPolicy)
+		if err != nil {
+			return "", nil, fmt.Errorf("deploy.approvalPolicy in %s: %w", path, err)
+		}
+		policy = deploy.StricterApprovalPolicy(policy, p)
+		approvers = append(approvers, cfg.Deploy.Approvers...)
+	}
+	return policy, approvers, nil
+}
+
+// refuseUnderRepoApprovalPolicy fails while a repo requires a second approver. command applies
+// releases without checking an approval token for each plan, so running it would skip the
+// two-person rule.
+func refuseUnderRepoApprovalPolicy(ctx context.Context, command string, dirs ...string) error {
+	policy, _, err := repoApprovalPolicy(ctx, dirs...)
+	if err != nil {
+		return err
+	}
+	if policy == deploy.ApprovalPolicyNone {
+		return nil
+	}
+	return fmt.Errorf("%s is disabled while deploy.approvalPolicy is %q in .ktl.yaml: it cannot check a second approver's token for each release; deploy them with ktl apply --approval-token instead", command, policy)
+}
+
+// approvalApplier identifies the person applying, so their own approvals can be rejected.
+type approvalApplier struct {
+	// Name is the local user name, compared against the deploy.approvers name that verified the
+	// token. ktl approve keygen prints the same name, so an approver signing their own apply with
+	// a key kept elsewhere is still caught.
+	Name string
+	// Key is the public half of ~/.ktl/keys/approver.json, or nil when there is none.
+	Key ed25519.PublicKey
+}
+
+func currentApprovalApplier() approvalApplier {
+	applier := approvalApplier{Name: currentUserName()}
+	if path := defaultApproverKeyPath(); path != "" {
+		if _, pub, _, err := stack.LoadBundleKey(path); err == nil {
+			applier.Key = pub
+		}
+	}
+	return applier
+}
+
+// loadDeployConfig reads the deploy section of ~/.ktl/config.yaml and the repo's .ktl.yaml.
+func loadDeployConfig(ctx context.Context) (appconfig.DeployConfig, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return appconfig.DeployConfig{}, err
+	}
+	cfg, err := appconfig.Load(ctx, appconfig.DefaultGlobalPath(), appconfig.DefaultRepoPath(appconfig.FindRepoRoot(cwd)))
+	if err != nil {
+		return appconfig.DeployConfig{}, err
+	}
+	return cfg.Deploy, nil
+}
+
+// enforceApprovalPolicy requires a valid approval token when policy applies to the previewed plan.
+// Tokens signed with the applier's own approver key, or by the approver listed under the
+// applier's name, are rejected. The accepted approval is attached to cmd for the audit log.
+func enforceApprovalPolicy(cmd *cobra.Command, errOut io.Writer, policy, token string, approvers []appconfig.Approver, applier approvalApplier, release, namespace string, preview *deploy.InstallResult) error {
+	if preview == nil || preview.Release == nil {
+		return fmt.Errorf("--approval-policy %s: no plan preview to approve", policy)
+	}
+	var tags []string
+	for _, tag := range preview.PlanSummary.RiskTagsPresent() {
+		tags = append(tags, string(tag))
+	}
+	if !deploy.ApprovalRequired(policy, len(tags) > 0) {
+		return nil
+	}
+	digest := deploy.PlanDigest(release, namespace, preview.Release.Manifest)
+	if strings.TrimSpace(token) == "" {
+		reason := "every apply"
+		if policy == deploy.ApprovalPolicyRisky {
+			reason = "risky changes (" + strings.Join(tags, ", ") + ")"
+		}
+		fmt.Fprintf(errOut, "Plan digest: %s\n", digest)
+		return fmt.Errorf("approval policy %q requires a second approver for %s: ask someone else to run `ktl approve %s` and re-run with --approval-token", policy, reason, digest)
+	}
+	approval, err := deploy.VerifyApproval(token, digest, approvers, time.Now())
+	if err != nil {
+		return fmt.Errorf("approval policy %q: %w", policy, err)
+	}
+	if len(applier.Key) > 0 && applier.Key.Equal(approval.SignerKey) {
+		return fmt.Errorf("approval policy %q: the approval must come from someone other than the person applying (the token is signed with your own approver key, listed as %s)", policy, approval.Approver)
+	}
+	if name := strings.TrimSpace(applier.Name); name != "" && strings.EqualFold(name, strings.TrimSpace(approval.Approver)) {
+		return fmt.Errorf("approval policy %q: the approval must come from someone other than the person applying (the token is signed by %s, the approver listed under your user name)", policy, approval.Approver)
+	}
+	fmt.Fprintf(errOut, "Approved by %s at %s (plan %s).\n", approval.Approver, approval.IssuedAt.Local().Format(time.RFC3339), digest)
+	raw, err := json.Marshal(audit.Approval{
+		Policy:     policy,
+		PlanDigest: digest,
+		Approver:   approval.Approver,
+		IssuedAt:   approval.IssuedAt,
+		RiskTags:   tags,
+	})
+	if err != nil {
+		return err
+	}
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[approvalAuditAnnotation] = string(raw)
+	return nil
+}
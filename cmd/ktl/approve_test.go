@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/deploy"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestEnforceApprovalPolicyRecordsApprover(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	approvers := []appconfig.Approver{{Name: "second-approver", PublicKey: base64.StdEncoding.EncodeToString(pub)}}
+	preview := &deploy.InstallResult{
+		Release: &release.Release{Manifest: "kind: Deployment\n"},
+		PlanSummary: &deploy.PlanSummary{Changes: []deploy.PlanChange{{
+			Kind: "Deployment", Name: "api",
+			Risks: []deploy.PlanRisk{{Tag: deploy.RiskImageChange, Detail: "container api: v1 -> v2"}},
+		}}},
+	}
+	digest := deploy.PlanDigest("web", "prod", preview.Release.Manifest)
+
+	root := newRootCommand()
+	apply, _, err := root.Find([]string{"apply"})
+	if err != nil {
+		t.Fatalf("find apply: %v", err)
+	}
+	var errOut bytes.Buffer
+	err = enforceApprovalPolicy(apply, &errOut, deploy.ApprovalPolicyRisky, "", approvers, approvalApplier{}, "web", "prod", preview)
+	if err == nil || !strings.Contains(err.Error(), "ktl approve "+digest) || !strings.Contains(errOut.String(), digest) {
+		t.Fatalf("expected the digest and approve instructions, got %v / %q", err, errOut.String())
+	}
+	if err := enforceApprovalPolicy(apply, &errOut, deploy.ApprovalPolicyRisky, "", approvers, approvalApplier{}, "web", "prod", &deploy.InstallResult{Release: preview.Release, PlanSummary: &deploy.PlanSummary{}}); err != nil {
+		t.Fatalf("expected plans without risky changes to pass the risky policy: %v", err)
+	}
+
+	now := time.Now().UTC()
+	token, err := deploy.SignApproval(deploy.Approval{PlanDigest: digest, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}, priv)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if err := enforceApprovalPolicy(apply, &errOut, deploy.ApprovalPolicyRisky, token, approvers, approvalApplier{}, "web", "prod", preview); err != nil {
+		t.Fatalf("enforce: %v", err)
+	}
+	entry := buildAuditEntry(apply, now, nil)
+	if entry.Approval == nil || entry.Approval.Approver != "second-approver" || entry.Approval.PlanDigest != digest || strings.Join(entry.Approval.RiskTags, ",") != "image-change" {
+		t.Fatalf("unexpected audit approval %+v", entry.Approval)
+	}
+
+	if err := enforceApprovalPolicy(apply, &errOut, deploy.ApprovalPolicyAlways, token, approvers, approvalApplier{Key: pub}, "web", "prod", preview); err == nil || !strings.Contains(err.Error(), "someone other than") {
+		t.Fatalf("expected a token signed with the applier's own key to be rejected, got %v", err)
+	}
+	// The same approver signing with a key kept outside ~/.ktl/keys is caught by name.
+	if err := enforceApprovalPolicy(apply, &errOut, deploy.ApprovalPolicyAlways, token, approvers, approvalApplier{Name: "Second-Approver"}, "web", "prod", preview); err == nil || !strings.Contains(err.Error(), "listed under your user name") {
+		t.Fatalf("expected a token from the approver named like the applier to be rejected, got %v", err)
+	}
+}
+
+func TestRefuseUnderRepoApprovalPolicy(t *testing.T) {
+	t.Chdir(t.TempDir())
+	ctx := context.Background()
+	repo := t.TempDir()
+	stackRoot := filepath.Join(repo, "stacks", "prod")
+	if err := os.MkdirAll(stackRoot, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := refuseUnderRepoApprovalPolicy(ctx, "ktl stack apply", stackRoot); err != nil {
+		t.Fatalf("expected no policy outside a repo, got %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, ".ktl.yaml"), []byte("deploy:\n  approvalPolicy: risky\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := refuseUnderRepoApprovalPolicy(ctx, "ktl stack apply", stackRoot)
+	if err == nil || !strings.Contains(err.Error(), `ktl stack apply is disabled while deploy.approvalPolicy is "risky"`) {
+		t.Fatalf("expected the stack repo's policy to block the command, got %v", err)
+	}
+	// The working directory's repo counts too, wherever the stack lives.
+	t.Chdir(repo)
+	if err := refuseUnderRepoApprovalPolicy(ctx, "ktl bootstrap"); err == nil {
+		t.Fatalf("expected the working directory's policy to block the command")
+	}
+}
+
+func TestRemoteAgentApplyHonorsRepoApprovalPolicy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, ".ktl.yaml"), []byte("deploy:\n  approvalPolicy: always\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(repo)
+
+	root := newRootCommand()
+	root.SetArgs([]string{"apply", "--chart", "./chart", "--release", "web", "--remote-agent", "127.0.0.1:1", "--yes"})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	err := root.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "ktl apply --remote-agent is disabled") {
+		t.Fatalf("expected --remote-agent to be refused under the repo policy, got %v", err)
+	}
+}
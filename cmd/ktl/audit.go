@@ -0,0 +1,377 @@
+// File: cmd/ktl/audit.go
+// Brief: CLI command wiring and implementation for 'audit'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/audit"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// auditedCommands are the mutating command paths recorded in the audit log.
+var auditedCommands = map[string]bool{
+	"ktl apply":              true,
+	"ktl approve":            true,
+	"ktl delete":             true,
+	"ktl revert":             true,
+	"ktl bootstrap":          true,
+	"ktl debug":              true,
+	"ktl up":                 true,
+	"ktl promote":            true,
+	"ktl bundle apply":       true,
+	"ktl stack apply":        true,
+	"ktl stack delete":       true,
+	"ktl stack rerun-failed": true,
+	"ktl stack reconcile":    true,
+	"ktl secrets set":        true,
+	"ktl secrets rotate":     true,
+	"ktl tunnel intercept":   true,
+	"ktl tunnel reverse":     true,
+	"ktl tunnel share":       true,
+}
+
+var sensitiveFlagMarkers = []string{"token", "password", "secret-id", "key", "auth", "literal"}
+
+// setStyleFlags take key=value pairs whose values can be secrets; only their keys are recorded.
+var setStyleFlags = map[string]bool{"set": true, "set-string": true, "set-json": true}
+
+// setFlagKey matches a Helm value path; anything else before "=" is a fragment of a value.
+var setFlagKey = regexp.MustCompile(`^[A-Za-z0-9_.\-\[\]\\/]+$`)
+
+// redactSetValues keeps the keys of --set style entries and drops their values, so
+// --set db.password=hunter2 is recorded as db.password=<redacted>.
+func redactSetValues(entries []string) string {
+	var keys []string
+	for _, entry := range entries {
+		for _, part := range splitUnescapedCommas(entry) {
+			key, _, ok := strings.Cut(part, "=")
+			key = strings.TrimSpace(key)
+			if !ok || !setFlagKey.MatchString(key) {
+				continue
+			}
+			keys = append(keys, key+"=<redacted>")
+		}
+	}
+	return "[" + strings.Join(keys, ",") + "]"
+}
+
+func splitUnescapedCommas(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// recordAudit appends an audit entry for executed when it is a mutating command. Failures to write
+// the audit log are reported on stderr but never change the command's exit status.
+func recordAudit(executed *cobra.Command, startedAt time.Time, runErr error, errOut io.Writer) {
+	if executed == nil || !auditedCommands[executed.CommandPath()] {
+		return
+	}
+	if runErr != nil && errors.Is(runErr, pflag.ErrHelp) {
+		return
+	}
+	opts := audit.OptionsFromEnv()
+	if opts.Disabled {
+		return
+	}
+	entry := buildAuditEntry(executed, startedAt, runErr)
+	if err := audit.Append(context.Background(), opts, entry); err != nil && errOut != nil {
+		fmt.Fprintf(errOut, "Warning: %v\n", err)
+	}
+}
+
+func buildAuditEntry(executed *cobra.Command, startedAt time.Time, runErr error) audit.Entry {
+	entry := audit.Entry{
+		Time:       startedAt.UTC(),
+		Command:    executed.CommandPath(),
+		Args:       append([]string(nil), executed.Flags().Args()...),
+		Result:     audit.ResultSuccess,
+		DurationMS: time.Since(startedAt).Milliseconds(),
+	}
+	if u, err := user.Current(); err == nil && u != nil {
+		entry.User = u.Username
+	} else {
+		entry.User = os.Getenv("USER")
+	}
+	entry.Host, _ = os.Hostname()
+	flags := map[string]string{}
+	executed.Flags().Visit(func(f *pflag.Flag) {
+		value := f.Value.String()
+		name := strings.ToLower(f.Name)
+		if setStyleFlags[name] {
+			entries := []string{value}
+			if slice, ok := f.Value.(pflag.SliceValue); ok {
+				entries = slice.GetSlice()
+			}
+			flags[f.Name] = redactSetValues(entries)
+			return
+		}
+		for _, marker := range sensitiveFlagMarkers {
+			if strings.Contains(name, marker) {
+				value = "<redacted>"
+				break
+			}
+		}
+		flags[f.Name] = value
+	})
+	if len(flags) > 0 {
+		entry.Flags = flags
+	}
+	entry.DryRun = flags["dry-run"] == "true"
+	entry.Namespace = flags["namespace"]
+	entry.KubeContext = flags["context"]
+	if entry.KubeContext == "" {
+		entry.KubeContext = currentKubeContext(flags["kubeconfig"])
+	}
+	switch {
+	case runErr == nil:
+	case errors.Is(runErr, context.Canceled):
+		entry.Result = audit.ResultCancelled
+		entry.Error = runErr.Error()
+	default:
+		entry.Result = audit.ResultFailure
+		entry.Error = runErr.Error()
+	}
+	if raw := executed.Annotations[approvalAuditAnnotation]; raw != "" {
+		var approval audit.Approval
+		if err := json.Unmarshal([]byte(raw), &approval); err == nil {
+			entry.Approval = &approval
+		}
+	}
+	if raw := executed.Annotations[windowOverrideAuditAnnotation]; raw != "" {
+		var override audit.WindowOverride
+		if err := json.Unmarshal([]byte(raw), &override); err == nil {
+			entry.WindowOverride = &override
+		}
+	}
+	return entry
+}
+
+func currentKubeContext(kubeconfig string) string {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if strings.TrimSpace(kubeconfig) != "" {
+		rules.ExplicitPath = kubeconfig
+	}
+	raw, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return ""
+	}
+	return raw.CurrentContext
+}
+
+func newAuditCommand() *cobra.Command {
+	var path string
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the local audit log of mutating ktl commands",
+		Long: `Every mutating ktl command (apply, delete, revert, bootstrap, stack apply/delete/reconcile, secrets set/rotate, ...) appends
+a JSON line with user, kube context, flags, and result to ~/.ktl/audit.jsonl.
+
+Environment:
+  KTL_AUDIT_LOG       override the log path
+  KTL_AUDIT_ENDPOINT  also POST each entry as JSON to this URL
+  KTL_AUDIT_TOKEN     bearer token for KTL_AUDIT_ENDPOINT
+  KTL_AUDIT=off       disable auditing`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.PersistentFlags().StringVar(&path, "file", "", "Audit log path (defaults to KTL_AUDIT_LOG or ~/.ktl/audit.jsonl)")
+	cmd.AddCommand(newAuditTailCommand(&path), newAuditSearchCommand(&path))
+	return cmd
+}
+
+func resolveAuditPath(path string) string {
+	if p := strings.TrimSpace(path); p != "" {
+		return p
+	}
+	return audit.OptionsFromEnv().Path
+}
+
+func newAuditTailCommand(path *string) *cobra.Command {
+	var lines int
+	var follow bool
+	var output string
+	cmd := &cobra.Command{
+		Use:   "tail",
+		Short: "Show the most recent audit entries",
+		Example: `  # Last 20 mutating commands
+  ktl audit tail
+
+  # Stream new entries as JSON
+  ktl audit tail --follow --output json`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			file := resolveAuditPath(*path)
+			entries, err := audit.Read(file)
+			if err != nil {
+				return err
+			}
+			shown := audit.Search(entries, audit.Filter{}, lines)
+			if err := writeAuditEntries(cmd.OutOrStdout(), shown, output, true); err != nil {
+				return err
+			}
+			if !follow {
+				return nil
+			}
+			seen := len(entries)
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-ticker.C:
+				}
+				entries, err := audit.Read(file)
+				if err != nil {
+					return err
+				}
+				if len(entries) < seen {
+					// Log was rotated or truncated; start over.
+					seen = 0
+				}
+				if len(entries) > seen {
+					if err := writeAuditEntries(cmd.OutOrStdout(), entries[seen:], output, false); err != nil {
+						return err
+					}
+					seen = len(entries)
+				}
+			}
+		},
+	}
+	cmd.Flags().IntVar(&lines, "lines", 20, "Number of entries to show (0 for all)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep streaming new entries")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table or json")
+	decorateCommandHelp(cmd, "Audit Flags")
+	return cmd
+}
+
+func newAuditSearchCommand(path *string) *cobra.Command {
+	var filter audit.Filter
+	var since time.Duration
+	var limit int
+	var output string
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: "Search audit entries by command, user, context, result, or text",
+		Example: `  # Failed applies against prod in the last day
+  ktl audit search --command apply --kube-context prod --result failure --since 24h
+
+  # Everything that mentioned a release
+  ktl audit search --grep checkout`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if since > 0 {
+				filter.Since = time.Now().Add(-since)
+			}
+			entries, err := audit.Read(resolveAuditPath(*path))
+			if err != nil {
+				return err
+			}
+			return writeAuditEntries(cmd.OutOrStdout(), audit.Search(entries, filter, limit), output, true)
+		},
+	}
+	cmd.Flags().StringVar(&filter.Command, "command", "", "Match command path substring (e.g. apply, stack delete)")
+	cmd.Flags().StringVar(&filter.User, "user", "", "Match user")
+	cmd.Flags().StringVar(&filter.Context, "kube-context", "", "Match kube context")
+	cmd.Flags().StringVar(&filter.Result, "result", "", "Match result: success, failure, or cancelled")
+	cmd.Flags().StringVar(&filter.Contains, "grep", "", "Match text anywhere in the entry (flags, args, error)")
+	cmd.Flags().DurationVar(&since, "since", 0, "Only entries newer than this duration (e.g. 24h)")
+	cmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of (newest) entries to show (0 for all)")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table or json")
+	decorateCommandHelp(cmd, "Audit Flags")
+	return cmd
+}
+
+func writeAuditEntries(out io.Writer, entries []audit.Entry, format string, header bool) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "json":
+		enc := json.NewEncoder(out)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "", "table":
+	default:
+		return fmt.Errorf("unsupported --output %q (use table or json)", format)
+	}
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	if header {
+		fmt.Fprintln(tw, "TIME\tUSER\tCONTEXT\tCOMMAND\tRESULT\tDURATION\tDETAILS")
+	}
+	for _, e := range entries {
+		details := auditDetails(e)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Time.Local().Format(time.RFC3339),
+			dashIfEmpty(e.User),
+			dashIfEmpty(e.KubeContext),
+			e.Command,
+			e.Result,
+			(time.Duration(e.DurationMS) * time.Millisecond).Round(time.Millisecond),
+			details,
+		)
+	}
+	return tw.Flush()
+}
+
+func auditDetails(e audit.Entry) string {
+	var parts []string
+	for _, key := range []string{"release", "chart", "namespace"} {
+		if v := strings.TrimSpace(e.Flags[key]); v != "" {
+			parts = append(parts, key+"="+v)
+		}
+	}
+	if e.DryRun {
+		parts = append(parts, "dry-run")
+	}
+	if len(parts) == 0 && len(e.Flags) > 0 {
+		keys := make([]string, 0, len(e.Flags))
+		for k := range e.Flags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts = append(parts, "flags="+strings.Join(keys, ","))
+	}
+	if e.Error != "" {
+		msg := e.Error
+		if len(msg) > 80 {
+			msg = msg[:77] + "..."
+		}
+		parts = append(parts, "error="+msg)
+	}
+	return dashIfEmpty(strings.Join(parts, " "))
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/audit"
+)
+
+func TestRecordAuditWritesMutatingCommandsOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	t.Setenv(audit.EnvPath, path)
+	t.Setenv(audit.EnvEndpoint, "")
+	t.Setenv(audit.EnvDisable, "")
+
+	root := newRootCommand()
+	apply, _, err := root.Find([]string{"apply"})
+	if err != nil {
+		t.Fatalf("find apply: %v", err)
+	}
+	if err := apply.ParseFlags([]string{"--release", "web", "--chart", "./chart", "--remote-token", "s3cr3t", "--context", "prod", "--dry-run"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	recordAudit(apply, time.Now().Add(-time.Second), errors.New("boom"), nil)
+
+	list, _, err := root.Find([]string{"list"})
+	if err != nil {
+		t.Fatalf("find list: %v", err)
+	}
+	recordAudit(list, time.Now(), nil, nil)
+
+	entries, err := audit.Read(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the apply entry, got %+v", entries)
+	}
+	e := entries[0]
+	if e.Command != "ktl apply" || e.Result != audit.ResultFailure || e.Error != "boom" || !e.DryRun || e.KubeContext != "prod" {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+	if e.Flags["release"] != "web" || e.Flags["remote-token"] != "<redacted>" {
+		t.Fatalf("unexpected flags %+v", e.Flags)
+	}
+	if e.DurationMS < 1000 {
+		t.Fatalf("expected duration to be recorded, got %d", e.DurationMS)
+	}
+}
+
+func TestBuildAuditEntryRedactsSetValues(t *testing.T) {
+	root := newRootCommand()
+	apply, _, err := root.Find([]string{"apply"})
+	if err != nil {
+		t.Fatalf("find apply: %v", err)
+	}
+	if err := apply.ParseFlags([]string{
+		"--release", "web", "--chart", "./chart", "--context", "prod",
+		"--set", "db.password=hunter2,image.tag=v1",
+		"--set-string", `annotations.note=a\,b`,
+		"--set-json", `creds={"user":"admin","pass":"x=hunter3"}`,
+	}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	entry := buildAuditEntry(apply, time.Now(), nil)
+	want := map[string]string{
+		"set":        "[db.password=<redacted>,image.tag=<redacted>]",
+		"set-string": "[annotations.note=<redacted>]",
+		"set-json":   "[creds=<redacted>]",
+	}
+	for name, value := range want {
+		if got := entry.Flags[name]; got != value {
+			t.Errorf("--%s recorded as %q, want %q", name, got, value)
+		}
+	}
+	raw := strings.Join([]string{entry.Flags["set"], entry.Flags["set-string"], entry.Flags["set-json"]}, " ")
+	for _, secret := range []string{"hunter", "admin", "v1"} {
+		if strings.Contains(raw, secret) {
+			t.Fatalf("audit entry leaks %q: %s", secret, raw)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// File: cmd/ktl/bootstrap.go
+// Brief: CLI command wiring and implementation for 'bootstrap'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/bootstrap"
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+func newBootstrapCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
+	var file string
+	var dryRun bool
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Apply an ordered bundle of foundational cluster components",
+		Long: `Apply the ordered steps in bootstrap.yaml: namespaces, raw manifests (CRDs, RBAC), and Helm charts
+(ingress controllers, operators). Steps can wait for CRDs to be established before the next step runs.
+
+Every step is idempotent (server-side apply and helm upgrade --install), so the bundle can be re-run
+before each stack apply.`,
+		Example: `  # Apply ./bootstrap.yaml
+  ktl bootstrap
+
+  # Preview a bundle with server-side dry-run
+  ktl bootstrap --file clusters/prod/bootstrap.yaml --dry-run`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			out := cmd.OutOrStdout()
+			bundle, err := bootstrap.Load(file)
+			if err != nil {
+				return err
+			}
+			if !dryRun {
+				if err := refuseUnderRepoApprovalPolicy(ctx, "ktl bootstrap", file); err != nil {
+					return err
+				}
+			}
+			client, err := kube.New(ctx, derefString(kubeconfig), derefString(kubeContext))
+			if err != nil {
+				return err
+			}
+			installer := func(ctx context.Context, namespace string, chart bootstrap.ChartStep) error {
+				if strings.TrimSpace(namespace) == "" {
+					namespace = client.Namespace
+				}
+				if namespace == "" {
+					namespace = "default"
+				}
+				settings := cli.New()
+				if kc := derefString(kubeconfig); kc != "" {
+					settings.KubeConfig = kc
+				}
+				if kctx := derefString(kubeContext); kctx != "" {
+					settings.KubeContext = kctx
+				}
+				settings.SetNamespace(namespace)
+				actionCfg := new(action.Configuration)
+				if err := actionCfg.Init(settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+					return fmt.Errorf("init helm action config: %w", err)
+				}
+				chartTimeout := chart.Timeout
+				if chartTimeout <= 0 {
+					chartTimeout = timeout
+				}
+				_, err := deploy.InstallOrUpgrade(ctx, actionCfg, settings, deploy.InstallOptions{
+					Chart:           chart.Chart,
+					Version:         chart.Version,
+					ReleaseName:     chart.Release,
+					Namespace:       namespace,
+					ValuesFiles:     chart.Values,
+					SetValues:       sortedSetPairs(chart.Set),
+					Timeout:         chartTimeout,
+					Wait:            chart.Wait == nil || *chart.Wait,
+					Atomic:          false,
+					CreateNamespace: chart.CreateNamespace == nil || *chart.CreateNamespace,
+					DryRun:          dryRun,
+				})
+				return err
+			}
+			started := time.Now()
+			results, err := bootstrap.Run(ctx, bundle, bootstrap.Options{
+				Client:       client,
+				InstallChart: installer,
+				DryRun:       dryRun,
+				Out:          out,
+			})
+			if err != nil {
+				return err
+			}
+			verb := "applied"
+			if dryRun {
+				verb = "validated (dry-run)"
+			}
+			fmt.Fprintf(out, "Bootstrap %s: %d step(s) in %s\n", verb, len(results), time.Since(started).Round(time.Millisecond))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", bootstrap.DefaultFileName, "Bootstrap bundle file (or a directory containing bootstrap.yaml)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Server-side dry-run manifests and charts without changing the cluster")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute, "Default Helm timeout for chart steps")
+	decorateCommandHelp(cmd, "Bootstrap Flags")
+	return cmd
+}
+
+func sortedSetPairs(set map[string]string) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, k+"="+set[k])
+	}
+	return out
+}
@@ -39,10 +39,18 @@ type buildCLIOptions struct {
 	kubeconfig       *string
 	kubeContext      *string
 	contextDir       string
+	contextFlag      string
+	gitSubmodules    bool
+	gitKeepDir       bool
+	gitLFS           bool
 	dockerfile       string
 	tags             []string
 	platforms        []string
 	buildArgs        []string
+	labels           []string
+	injectLabels     bool
+	injectBuildArgs  bool
+	imageVersion     string
 	secrets          []string
 	cacheFrom        []string
 	cacheTo          []string
@@ -74,6 +82,8 @@ type buildCLIOptions struct {
 	cacheIntelTop    int
 	cacheIntelFormat string
 	cacheIntelOutput string
+	contextReport    bool
+	maxContextSize   int64
 	logLevel         string
 	sign             bool
 	signKey          string
@@ -103,6 +113,7 @@ type buildCLIOptions struct {
 	wsListenAddr     string
 	remoteAddr       string
 	remote           string
+	attachJob        string
 }
 
 var defaultBuildService buildsvc.Service = buildsvc.New(buildsvc.Dependencies{})
@@ -129,6 +140,8 @@ func newBuildCommandWithService(service buildsvc.Service, globalProfile *string,
 		kubeconfig:       kubeconfig,
 		kubeContext:      kubeContext,
 		contextDir:       ".",
+		gitSubmodules:    true,
+		injectLabels:     true,
 		dockerfile:       "Dockerfile",
 		builder:          buildkit.DefaultBuilderAddress(),
 		cacheDir:         buildkit.DefaultCacheDir(),
@@ -146,6 +159,14 @@ func newBuildCommandWithService(service buildsvc.Service, globalProfile *string,
 	cmd := &cobra.Command{
 		Use:   "build CONTEXT",
 		Short: "Build container images with BuildKit",
+		Long: `Build container images with BuildKit from a local directory, a compose project, or a remote
+git repository.
+
+CONTEXT can be a git URL (https://, ssh://, git://, or git@host:path) with an optional
+#ref:subdir fragment. BuildKit then fetches that ref itself, so CI agents can rebuild an image
+without a checkout. Submodules are fetched unless --git-submodules=false. Git LFS, --hermetic,
+--sandbox, and compose builds need the files on the host, so for those ktl makes a shallow
+checkout of the ref first.`,
 		Example: `  # Build the current directory
   ktl build .
 
@@ -158,8 +179,17 @@ func newBuildCommandWithService(service buildsvc.Service, globalProfile *string,
   # Build from a compose file directly and limit parallelism
   ktl build ./testdata/build/compose/docker-compose.yml --compose-parallelism 2
 
+  # Report context size and fail when it exceeds 500Mi
+  ktl build . --context-report --max-context-size 500Mi
+
   # Build with tags and push
-  ktl build . -f Dockerfile -t ghcr.io/acme/app:latest --push`,
+  ktl build . -f Dockerfile -t ghcr.io/acme/app:latest --push
+
+  # Reattach to a remote build after the client disconnected
+  ktl build --attach remote-build-1718031337000000000 --remote-build agent.internal:7443
+
+  # Build services/api at tag v1.4.2 straight from git (no checkout)
+  ktl build --context https://github.com/acme/app.git#v1.4.2:services/api -t ghcr.io/acme/api:v1.4.2 --push`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := requireBuildContextArg(cmd, args); err != nil {
 				if errors.Is(err, errMissingBuildContext) {
@@ -176,6 +206,8 @@ func newBuildCommandWithService(service buildsvc.Service, globalProfile *string,
 			runOpts := opts
 			if len(args) > 0 {
 				runOpts.contextDir = args[0]
+			} else if opts.contextFlag != "" {
+				runOpts.contextDir = opts.contextFlag
 			}
 			runOpts.profile = *globalProfile
 			runOpts.logLevel = *globalLogLevel
@@ -193,6 +225,16 @@ func newBuildCommandWithService(service buildsvc.Service, globalProfile *string,
 		return err
 	})
 
+	attachValidatedStringArray(cmd.Flags(), "label", &opts.labels, "Set an image label (KEY=VALUE). Repeatable; overrides injected and .ktl.yaml labels", func(raw string) error {
+		if key, _, ok := strings.Cut(raw, "="); !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid label %q (expected KEY=VALUE)", raw)
+		}
+		return nil
+	})
+	cmd.Flags().BoolVar(&opts.injectLabels, "inject-labels", true, "Add OCI created/revision/source/version labels from git and .ktl.yaml")
+	cmd.Flags().BoolVar(&opts.injectBuildArgs, "inject-build-args", false, "Also pass BUILD_DATE, VCS_REF, VCS_URL, and VERSION as build args (explicit --build-arg wins)")
+	cmd.Flags().Var(&validatedStringValue{dest: &opts.imageVersion, name: "--image-version", allowEmpty: true, validator: nil}, "image-version", "Image version for the org.opencontainers.image.version label (default: build.version in .ktl.yaml, else an exact git tag)")
+
 	attachValidatedStringArray(cmd.Flags(), "secret", &opts.secrets, "Expose an environment variable as a BuildKit secret (NAME)", validateEnvVarName)
 
 	attachValidatedStringArray(cmd.Flags(), "cache-from", &opts.cacheFrom, "Cache import sources (comma-separated key=value pairs)", func(raw string) error {
@@ -204,6 +246,10 @@ func newBuildCommandWithService(service buildsvc.Service, globalProfile *string,
 		return err
 	})
 
+	cmd.Flags().Var(&validatedStringValue{dest: &opts.contextFlag, name: "--context", validator: nil}, "context", "Build context: a directory or a git URL with an optional #ref:subdir (alternative to the CONTEXT argument)")
+	cmd.Flags().BoolVar(&opts.gitSubmodules, "git-submodules", true, "Check out submodules of a git build context")
+	cmd.Flags().BoolVar(&opts.gitKeepDir, "git-keep-dir", false, "Keep the .git directory in a git build context")
+	cmd.Flags().BoolVar(&opts.gitLFS, "git-lfs", false, "Pull Git LFS objects for a git build context (requires git-lfs on the host)")
 	cmd.Flags().VarP(&validatedStringValue{dest: &opts.dockerfile, name: "--file", validator: func(raw string) error {
 		if strings.TrimSpace(raw) == "" {
 			return fmt.Errorf("dockerfile path cannot be empty")
@@ -239,10 +285,12 @@ func newBuildCommandWithService(service buildsvc.Service, globalProfile *string,
 	cmd.Flags().BoolVar(&opts.push, "push", false, "Push all tags to their registries after a successful build")
 	cmd.Flags().BoolVar(&opts.load, "load", false, "Load the resulting image into the local container runtime (docker build --load)")
 	cmd.Flags().BoolVar(&opts.noCache, "no-cache", false, "Disable BuildKit cache usage")
-	cmd.Flags().BoolVar(&opts.cacheIntel, "cache-intel", true, "Print a post-build cache summary (cache misses, slow steps, changed inputs)")
+	cmd.Flags().BoolVar(&opts.cacheIntel, "cache-intel", true, "Print a post-build cache report (per-stage timings, cache-busting instructions, slow steps, layer sizes, changed inputs)")
 	cmd.Flags().Var(&nonNegativeIntValue{dest: &opts.cacheIntelTop}, "cache-intel-top", "Max entries to show in the cache intelligence summary")
 	cmd.Flags().Var(newEnumStringValue(&opts.cacheIntelFormat, "human", "human", "json"), "cache-intel-format", "Cache intelligence output format: human or json")
 	cmd.Flags().Var(&validatedStringValue{dest: &opts.cacheIntelOutput, name: "--cache-intel-output", allowEmpty: true, validator: nil}, "cache-intel-output", "Write cache intelligence report to this path ('-' for stdout). Defaults to stderr in human mode.")
+	cmd.Flags().BoolVar(&opts.contextReport, "context-report", false, "Print a pre-build context report (size, largest files/directories, .dockerignore exclusions)")
+	cmd.Flags().Var(&byteSizeValue{dest: &opts.maxContextSize}, "max-context-size", "Fail before building when the context (after .dockerignore) exceeds this size (e.g. 500Mi, 2G)")
 	cmd.Flags().Var(&validatedStringValue{dest: &opts.builder, name: "--builder", validator: validateBuildkitAddr}, "builder", "BuildKit address (override with KTL_BUILDKIT_HOST)")
 	cmd.Flags().Var(&validatedStringValue{dest: &opts.dockerContext, name: "--docker-context", allowEmpty: true, validator: nil}, "docker-context", "Docker context to use for buildx fallback (override with KTL_DOCKER_CONTEXT)")
 	cmd.Flags().Var(&validatedStringValue{dest: &opts.cacheDir, name: "--cache-dir", allowEmpty: false, validator: nil}, "cache-dir", "Local cache directory for BuildKit metadata")
@@ -267,6 +315,7 @@ func newBuildCommandWithService(service buildsvc.Service, globalProfile *string,
 	cmd.Flags().Var(&validatedStringValue{dest: &opts.sandboxProbePath, name: "--sandbox-probe-path", allowEmpty: true, validator: nil}, "sandbox-probe-path", "Probe filesystem visibility before building by attempting to stat this host path")
 	cmd.Flags().Var(&validatedStringValue{dest: &opts.wsListenAddr, name: "--ws-listen", allowEmpty: true, validator: validateWSListenAddr}, "ws-listen", "Serve the raw BuildKit event stream over WebSocket at this address (e.g. :9085)")
 	cmd.Flags().Var(&validatedStringValue{dest: &opts.remoteAddr, name: "--remote-build", allowEmpty: true, validator: validateRemoteAddr}, "remote-build", "Execute this build via a remote ktl-agent gRPC endpoint (host:port)")
+	cmd.Flags().Var(&validatedStringValue{dest: &opts.attachJob, name: "--attach", allowEmpty: true, validator: nil}, "attach", "Reattach to a running (or recently finished) remote build by job ID; requires --remote-build")
 	cmd.Flags().Var(&validatedStringValue{dest: &opts.remote, name: "--remote", allowEmpty: true, validator: nil}, "remote", "Remote builder address or 'auto' to provision ephemeral builder")
 	cmd.PersistentFlags().Var(&validatedStringValue{dest: &opts.authFile, name: "--authfile", allowEmpty: true, validator: nil}, "authfile", "Path to the authentication file (Docker config.json)")
 	cmd.PersistentFlags().Var(&validatedStringValue{dest: &opts.sandboxConfig, name: "--sandbox-config", allowEmpty: true, validator: nil}, "sandbox-config", "Path to a sandbox runtime config file")
@@ -322,8 +371,19 @@ func attachValidatedStringArray(flags *pflag.FlagSet, name string, dest *[]strin
 
 var errMissingBuildContext = errors.New("'ktl build' requires 1 argument (CONTEXT). Try '.' for the current directory")
 
-func requireBuildContextArg(_ *cobra.Command, args []string) error {
+func requireBuildContextArg(cmd *cobra.Command, args []string) error {
+	contextFlag := cmd != nil && cmd.Flags().Changed("context")
+	if cmd != nil && cmd.Flags().Changed("attach") {
+		if len(args) > 0 || contextFlag {
+			return fmt.Errorf("'ktl build --attach' does not take a build context")
+		}
+		return nil
+	}
 	switch {
+	case contextFlag && len(args) > 0:
+		return fmt.Errorf("'ktl build' takes the context either as an argument or via --context, not both")
+	case contextFlag:
+		return nil
 	case len(args) == 0:
 		return errMissingBuildContext
 	case len(args) > 1:
@@ -352,6 +412,19 @@ func runBuildCommand(cmd *cobra.Command, service buildsvc.Service, opts buildCLI
 		return cmd.Help()
 	}
 
+	if strings.TrimSpace(opts.attachJob) != "" {
+		if strings.TrimSpace(opts.remoteAddr) == "" {
+			if err := applyBuildDefaults(cmd, &opts); err != nil {
+				return err
+			}
+		}
+		addr := strings.TrimSpace(opts.remoteAddr)
+		if addr == "" {
+			return fmt.Errorf("--attach requires --remote-build (or build.remoteBuild in .ktl.yaml)")
+		}
+		return runRemoteBuild(cmd, opts, addr)
+	}
+
 	// Handle ephemeral remote builder
 	if opts.remote != "" {
 		useRemote := false
@@ -512,53 +585,77 @@ func runRemoteBuild(cmd *cobra.Command, opts buildCLIOptions, remoteAddr string)
 	observers = append(observers, extraObservers...)
 
 	client := apiv1.NewBuildServiceClient(conn)
-	buildOpts := cliOptionsToServiceOptions(opts)
-	sessionID := newSessionID("remote-build")
-	trySetRemoteMirrorSessionMeta(ctx, conn, sessionID, &apiv1.MirrorSessionMeta{
-		Command:   cmd.CommandPath(),
-		Args:      append([]string(nil), os.Args[1:]...),
-		Requester: defaultRequester(),
-	}, map[string]string{
-		"build.context_dir": opts.contextDir,
-		"build.dockerfile":  opts.dockerfile,
-	})
-	req := &apiv1.RunBuildRequest{
-		SessionId: sessionID,
-		Requester: defaultRequester(),
-		Options:   convert.BuildOptionsToProto(buildOpts),
+	var stream apiv1.BuildService_RunBuildClient
+	attaching := strings.TrimSpace(opts.attachJob) != ""
+	if attaching {
+		stream, err = client.AttachBuild(ctx, &apiv1.AttachBuildRequest{JobId: strings.TrimSpace(opts.attachJob)})
+	} else {
+		buildOpts := cliOptionsToServiceOptions(opts)
+		sessionID := newSessionID("remote-build")
+		trySetRemoteMirrorSessionMeta(ctx, conn, sessionID, &apiv1.MirrorSessionMeta{
+			Command:   cmd.CommandPath(),
+			Args:      append([]string(nil), os.Args[1:]...),
+			Requester: defaultRequester(),
+		}, map[string]string{
+			"build.context_dir": opts.contextDir,
+			"build.dockerfile":  opts.dockerfile,
+		})
+		stream, err = client.RunBuild(ctx, &apiv1.RunBuildRequest{
+			SessionId: sessionID,
+			Requester: defaultRequester(),
+			Options:   convert.BuildOptionsToProto(buildOpts),
+			// The agent keeps the build running if this client drops; Ctrl-C cancels it below.
+			DetachOnDisconnect: true,
+		})
 	}
-	stream, err := client.RunBuild(ctx, req)
 	if err != nil {
 		return err
 	}
+	done := func() {
+		for _, obs := range observers {
+			if done, ok := obs.(interface{ Done() }); ok {
+				done.Done()
+			}
+		}
+	}
+	jobID := strings.TrimSpace(opts.attachJob)
 	for {
 		event, err := stream.Recv()
 		if err == io.EOF {
-			for _, obs := range observers {
-				if done, ok := obs.(interface{ Done() }); ok {
-					done.Done()
-				}
-			}
+			done()
 			return nil
 		}
 		if err != nil {
-			for _, obs := range observers {
-				if done, ok := obs.(interface{ Done() }); ok {
-					done.Done()
+			done()
+			if ctx.Err() != nil && jobID != "" {
+				if attaching {
+					fmt.Fprintf(errOut, "Detached from remote build job %s; it keeps running on the agent\n", jobID)
+				} else {
+					cancelCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					_, _ = client.CancelBuild(cancelCtx, &apiv1.CancelBuildRequest{JobId: jobID})
+					cancel()
 				}
+				return ctx.Err()
+			}
+			if jobID != "" {
+				return fmt.Errorf("remote build stream lost: %w (reattach with: ktl build --attach %s --remote-build %s)", err, jobID, remoteAddr)
 			}
 			return err
 		}
+		if job := event.GetJob(); job != nil {
+			jobID = job.GetJobId()
+			if attaching {
+				fmt.Fprintf(errOut, "Attached to remote build job %s (%s)\n", jobID, job.GetState())
+			} else {
+				fmt.Fprintf(errOut, "Remote build job %s (reattach with: ktl build --attach %s --remote-build %s)\n", jobID, jobID, remoteAddr)
+			}
+		}
 		if log := event.GetLog(); log != nil {
 			rec := convert.FromProtoLogLine(log)
 			notifyBuildObservers(observers, rec)
 		}
 		if res := event.GetResult(); res != nil {
-			for _, obs := range observers {
-				if done, ok := obs.(interface{ Done() }); ok {
-					done.Done()
-				}
-			}
+			done()
 			if res.GetError() != "" {
 				return fmt.Errorf("remote build failed: %s", res.GetError())
 			}
@@ -574,10 +671,17 @@ func runRemoteBuild(cmd *cobra.Command, opts buildCLIOptions, remoteAddr string)
 func cliOptionsToServiceOptions(opts buildCLIOptions) buildsvc.Options {
 	return buildsvc.Options{
 		ContextDir:         opts.contextDir,
+		GitSkipSubmodules:  !opts.gitSubmodules,
+		GitKeepDir:         opts.gitKeepDir,
+		GitLFS:             opts.gitLFS,
 		Dockerfile:         opts.dockerfile,
 		Tags:               append([]string(nil), opts.tags...),
 		Platforms:          append([]string(nil), opts.platforms...),
 		BuildArgs:          append([]string(nil), opts.buildArgs...),
+		Labels:             append([]string(nil), opts.labels...),
+		InjectLabels:       opts.injectLabels,
+		InjectBuildArgs:    opts.injectBuildArgs,
+		ImageVersion:       opts.imageVersion,
 		Secrets:            append([]string(nil), opts.secrets...),
 		CacheFrom:          append([]string(nil), opts.cacheFrom...),
 		CacheTo:            append([]string(nil), opts.cacheTo...),
@@ -605,6 +709,8 @@ func cliOptionsToServiceOptions(opts buildCLIOptions) buildsvc.Options {
 		CacheIntelTop:      opts.cacheIntelTop,
 		CacheIntelFormat:   opts.cacheIntelFormat,
 		CacheIntelOutput:   opts.cacheIntelOutput,
+		ContextReport:      opts.contextReport,
+		MaxContextSize:     opts.maxContextSize,
 		LogLevel:           opts.logLevel,
 		Sign:               opts.sign,
 		SignKey:            opts.signKey,
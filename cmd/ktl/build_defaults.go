@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/kubekattle/ktl/internal/appconfig"
@@ -81,6 +82,28 @@ func applyBuildConfig(cmd *cobra.Command, opts *buildCLIOptions, cfg appconfig.B
 	if !flagChanged(cmd, "remote-build") && strings.TrimSpace(cfg.RemoteBuild) != "" {
 		opts.remoteAddr = cfg.RemoteBuild
 	}
+	if !flagChanged(cmd, "image-version") && strings.TrimSpace(cfg.Version) != "" {
+		opts.imageVersion = cfg.Version
+	}
+	if len(cfg.Labels) > 0 {
+		// Config labels go first so --label values for the same key override them.
+		keys := make([]string, 0, len(cfg.Labels))
+		for k := range cfg.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		labels := make([]string, 0, len(keys)+len(opts.labels))
+		for _, k := range keys {
+			labels = append(labels, k+"="+cfg.Labels[k])
+		}
+		opts.labels = append(labels, opts.labels...)
+	}
+	if !flagChanged(cmd, "inject-labels") && cfg.InjectLabels != nil {
+		opts.injectLabels = *cfg.InjectLabels
+	}
+	if !flagChanged(cmd, "inject-build-args") && cfg.InjectBuildArgs != nil {
+		opts.injectBuildArgs = *cfg.InjectBuildArgs
+	}
 }
 
 func applyBuildProfile(cmd *cobra.Command, opts *buildCLIOptions) {
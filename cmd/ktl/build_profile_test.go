@@ -5,6 +5,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -83,3 +84,36 @@ func TestBuildConfigProfileDefaultsAppliedWhenFlagUnset(t *testing.T) {
 		t.Fatalf("expected config profile to default to push")
 	}
 }
+
+func TestBuildConfigLabelsAndVersion(t *testing.T) {
+	disableSandboxForTests(t)
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("{}\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("KTL_CONFIG", cfgPath)
+	t.Setenv("HOME", t.TempDir())
+
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "go.mod"), "module example.com/app\n")
+	writeFile(t, filepath.Join(repo, ".ktl.yaml"), "build:\n  version: 1.4.2\n  injectBuildArgs: true\n  labels:\n    org.opencontainers.image.vendor: Acme\n    team: web\n")
+
+	rec := &recordingBuildService{}
+	root := newRootCommandWithBuildService(rec)
+	root.SetIn(newFakeTTY())
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"build", repo, "--label", "team=api"})
+
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	got := rec.lastOpts
+	if got.ImageVersion != "1.4.2" || !got.InjectLabels || !got.InjectBuildArgs {
+		t.Fatalf("unexpected injection options: version=%q labels=%v args=%v", got.ImageVersion, got.InjectLabels, got.InjectBuildArgs)
+	}
+	want := []string{"org.opencontainers.image.vendor=Acme", "team=web", "team=api"}
+	if strings.Join(got.Labels, ",") != strings.Join(want, ",") {
+		t.Fatalf("labels = %v, want %v (config first so --label wins)", got.Labels, want)
+	}
+}
@@ -37,4 +37,29 @@ func TestRequireBuildContextArg(t *testing.T) {
 			t.Fatalf("expected nil error, got %v", err)
 		}
 	})
+
+	t.Run("context flag", func(t *testing.T) {
+		cmd := newBuildCommand()
+		if err := cmd.Flags().Set("context", "https://github.com/acme/app.git#main:api"); err != nil {
+			t.Fatal(err)
+		}
+		if err := requireBuildContextArg(cmd, nil); err != nil {
+			t.Fatalf("expected --context to satisfy the context requirement, got %v", err)
+		}
+		if err := requireBuildContextArg(cmd, []string{"."}); err == nil {
+			t.Fatal("expected error when both --context and CONTEXT are given")
+		}
+	})
+	t.Run("attach flag", func(t *testing.T) {
+		cmd := newBuildCommand()
+		if err := cmd.Flags().Set("attach", "remote-build-1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := requireBuildContextArg(cmd, nil); err != nil {
+			t.Fatalf("expected --attach to need no context, got %v", err)
+		}
+		if err := requireBuildContextArg(cmd, []string{"."}); err == nil {
+			t.Fatal("expected error when --attach is combined with CONTEXT")
+		}
+	})
 }
@@ -0,0 +1,518 @@
+// File: cmd/ktl/bundle.go
+// Brief: CLI command wiring and implementation for 'bundle'.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/secretstore"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func newBundleCommand(kubeconfig *string, kubeContext *string, logLevel *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Move a release into disconnected environments as a single archive",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(
+		newBundleExportCommand(kubeconfig, kubeContext),
+		newBundleApplyCommand(kubeconfig, kubeContext, logLevel),
+	)
+	return cmd
+}
+
+func newBundleExportCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
+	var namespace string
+	var releaseName string
+	var revision int
+	var outPath string
+	var secretPrefix string
+	var stripValues []string
+	var noPinImages bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a deployed release as a self-contained bundle",
+		Long: `Write a deployed release to a .tgz that can be carried into an air-gapped environment
+and applied there with 'ktl bundle apply'. The bundle contains:
+
+  chart.tgz    the chart exactly as the release stored it
+  values.yaml  the release's values, with credentials replaced by secret:// references
+  images.txt   every container image, pinned to the digest its registry serves now
+  plan.json    the install plan of the bundle, usable as a 'ktl plan --compare' baseline
+  bundle.json  release metadata and the SHA-256 of each file
+
+Values under keys that look like credentials (password, token, clientSecret, apiKey, ...)
+and every --strip-value path become secret:///<prefix>/<path> references, resolved by the
+target environment's secrets provider when the bundle is applied.`,
+		Example: `  # Export the latest revision of shop
+  ktl bundle export --release shop -n prod --out shop-bundle.tgz
+
+  # Also strip a value the key heuristic does not catch
+  ktl bundle export --release shop -n prod --strip-value smtp.user --out shop-bundle.tgz`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			errOut := cmd.ErrOrStderr()
+			kubeClient, err := kube.New(ctx, *kubeconfig, *kubeContext)
+			if err != nil {
+				return err
+			}
+			resolvedNamespace := strings.TrimSpace(namespace)
+			if resolvedNamespace == "" {
+				resolvedNamespace = kubeClient.Namespace
+			}
+			if resolvedNamespace == "" {
+				resolvedNamespace = "default"
+			}
+			settings := cli.New()
+			if *kubeconfig != "" {
+				settings.KubeConfig = *kubeconfig
+			}
+			if *kubeContext != "" {
+				settings.KubeContext = *kubeContext
+			}
+			settings.SetNamespace(resolvedNamespace)
+			actionCfg := new(action.Configuration)
+			if err := actionCfg.Init(settings.RESTClientGetter(), resolvedNamespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+				return fmt.Errorf("init helm action config: %w", err)
+			}
+			get := action.NewGet(actionCfg)
+			get.Version = revision
+			rel, err := get.Run(releaseName)
+			if err != nil {
+				if errors.Is(err, driver.ErrReleaseNotFound) {
+					return fmt.Errorf("release %s not found in %s", releaseName, resolvedNamespace)
+				}
+				return fmt.Errorf("helm get %s: %w", releaseName, err)
+			}
+			if rel.Chart == nil || rel.Chart.Metadata == nil {
+				return fmt.Errorf("release %s has no stored chart", releaseName)
+			}
+
+			chartData, err := deploy.PackChart(rel.Chart)
+			if err != nil {
+				return err
+			}
+			prefix := secretPrefix
+			if !cmd.Flags().Changed("secret-prefix") {
+				prefix = rel.Name
+			}
+			values, secretRefs := deploy.StripSecretValues(rel.Config, prefix, stripValues)
+			valuesData, err := deploy.EncodeBundleValues(values)
+			if err != nil {
+				return fmt.Errorf("encode values: %w", err)
+			}
+
+			var images []deploy.BundleImage
+			if noPinImages {
+				for _, image := range deploy.ManifestImages(rel.Manifest) {
+					images = append(images, deploy.BundleImage{Image: image, Error: "not resolved (--no-pin-images)"})
+				}
+			} else {
+				images = deploy.PinBundleImages(ctx, deploy.ManifestImages(rel.Manifest))
+			}
+
+			plan, err := bundlePlan(cmd, rel.Name, resolvedNamespace, chartData, values, secretRefs)
+			if err != nil {
+				return err
+			}
+
+			b := &deploy.Bundle{
+				Manifest: deploy.BundleManifest{
+					Release:      rel.Name,
+					Namespace:    rel.Namespace,
+					Chart:        rel.Chart.Metadata.Name,
+					ChartVersion: rel.Chart.Metadata.Version,
+					AppVersion:   rel.Chart.Metadata.AppVersion,
+					Source: deploy.BundleSource{
+						Context:  *kubeContext,
+						Cluster:  kubeClient.RESTConfig.Host,
+						Revision: rel.Version,
+					},
+					CreatedAt:  time.Now().UTC(),
+					SecretRefs: secretRefs,
+					Images:     images,
+				},
+				Chart:  chartData,
+				Values: valuesData,
+				Plan:   plan,
+			}
+			path := strings.TrimSpace(outPath)
+			if path == "" {
+				path = rel.Name + "-bundle.tgz"
+			}
+			var buf bytes.Buffer
+			if err := deploy.WriteBundle(&buf, b); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(errOut, "Chart %s %s, revision %d\n", b.Manifest.Chart, b.Manifest.ChartVersion, rel.Version)
+			fmt.Fprintf(errOut, "Stripped values: %d replaced by secret references\n", len(secretRefs))
+			for _, ref := range secretRefs {
+				fmt.Fprintf(errOut, "  - %s -> %s\n", ref.Path, ref.Reference)
+			}
+			unpinned := b.Manifest.UnpinnedImages()
+			fmt.Fprintf(errOut, "Images: %d (%d pinned)\n", len(images), len(images)-len(unpinned))
+			for _, img := range images {
+				if img.Digest == "" && !noPinImages {
+					fmt.Fprintf(errOut, "  ! %s: %s\n", img.Image, img.Error)
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote bundle %s (%s)\n", path, formatBytes(int64(buf.Len())))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&releaseName, "release", "", "Helm release to export")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the Helm release (defaults to active context)")
+	cmd.Flags().IntVar(&revision, "revision", 0, "Release revision to export (default: the latest)")
+	cmd.Flags().StringVar(&outPath, "out", "", "Bundle file to write (default: <release>-bundle.tgz)")
+	cmd.Flags().StringVar(&secretPrefix, "secret-prefix", "", "Path prefix of the secret references that replace stripped values (default: the release name)")
+	cmd.Flags().StringArrayVar(&stripValues, "strip-value", nil, "Dotted values path to strip in addition to credential-like keys (repeatable)")
+	cmd.Flags().BoolVar(&noPinImages, "no-pin-images", false, "List images without resolving their digests")
+	_ = cmd.MarkFlagRequired("release")
+	decorateCommandHelp(cmd, "Bundle Flags")
+	return cmd
+}
+
+// bundlePlan renders the bundled chart and values as an install plan. It runs client-only, so
+// every object is a create and the plan can be reviewed before the target cluster is reachable.
+// Secret references render as masked placeholders.
+func bundlePlan(cmd *cobra.Command, releaseName, namespace string, chartData []byte, values map[string]any, refs []deploy.BundleSecretRef) ([]byte, error) {
+	valuesData, err := deploy.EncodeBundleValues(maskSecretRefs(values).(map[string]any))
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "ktl-bundle-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	chartPath, valuesPath, err := writeBundleInputs(dir, chartData, valuesData)
+	if err != nil {
+		return nil, err
+	}
+	actionCfg := &action.Configuration{
+		Releases:     storage.Init(driver.NewMemory()),
+		KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          func(string, ...interface{}) {},
+	}
+	settings := cli.New()
+	settings.SetNamespace(namespace)
+	result, err := executeDeployPlan(cmd.Context(), actionCfg, settings, nil, deployPlanOptions{
+		Chart:       chartPath,
+		Release:     releaseName,
+		Namespace:   namespace,
+		ValuesFiles: []string{valuesPath},
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("plan bundle: %w", err)
+	}
+	// Refer to the bundled files rather than their temporary copies.
+	result.ChartRef = deploy.BundleChartFile
+	result.RequestedChart = deploy.BundleChartFile
+	result.ValuesFiles = []string{deploy.BundleValuesFile}
+	result.InstallCmd = ""
+	for _, ref := range refs {
+		result.Secrets = append(result.Secrets, planSecretRef{Path: ref.Path, Reference: ref.Reference, Masked: true})
+	}
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// maskSecretRefs replaces every secret:// reference in v with a [secret://...] placeholder, so
+// the plan renders without a secrets provider.
+func maskSecretRefs(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, child := range t {
+			out[k] = maskSecretRefs(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, child := range t {
+			out[i] = maskSecretRefs(child)
+		}
+		return out
+	case string:
+		if strings.HasPrefix(t, "secret://") {
+			return "[" + t + "]"
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// writeBundleInputs writes the bundled chart and values to dir for Helm to load.
+func writeBundleInputs(dir string, chartData, valuesData []byte) (string, string, error) {
+	chartPath := filepath.Join(dir, deploy.BundleChartFile)
+	if err := os.WriteFile(chartPath, chartData, 0o600); err != nil {
+		return "", "", err
+	}
+	valuesPath := filepath.Join(dir, deploy.BundleValuesFile)
+	if err := os.WriteFile(valuesPath, valuesData, 0o600); err != nil {
+		return "", "", err
+	}
+	return chartPath, valuesPath, nil
+}
+
+func newBundleApplyCommand(kubeconfig *string, kubeContext *string, logLevel *string) *cobra.Command {
+	var namespace string
+	var releaseName string
+	var valuesFiles []string
+	var setValues []string
+	var imageMirrors []string
+	var requirePinned bool
+	var secretProvider string
+	var secretConfig string
+	var wait bool
+	var timeout time.Duration
+	var dryRun bool
+	var yes bool
+	var nonInteractive bool
+	var postRender postRenderFlags
+	var gates deployGateFlags
+
+	wait = true
+	timeout = 5 * time.Minute
+
+	cmd := &cobra.Command{
+		Use:   "apply BUNDLE",
+		Short: "Apply a release bundle without network access to its sources",
+		Long: `Install or upgrade the release in a bundle written by 'ktl bundle export'. The chart and
+values come from the bundle, so no chart repository is contacted. Every file is checked against
+the SHA-256 recorded in bundle.json first.
+
+Containers are pinned to the image digests recorded at export. Use --image-mirror to pull
+them from the registry of the disconnected environment instead. Stripped values are
+secret:// references, resolved by the target's secrets provider (--secret-provider,
+--secret-config). They can also be given directly with --set.
+
+Like ktl apply, bundle apply respects deploy.windows and the approval policy in .ktl.yaml.`,
+		Example: `  # Review, then apply to prod
+  ktl bundle apply shop-bundle.tgz --context prod --dry-run
+  ktl bundle apply shop-bundle.tgz --context prod
+
+  # Pull images from the air-gapped registry
+  ktl bundle apply shop-bundle.tgz --context prod --image-mirror docker.io=registry.prod.local/hub`,
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateNonInteractive(cmd, nonInteractive, yes); err != nil {
+				return err
+			}
+			if timeout <= 0 {
+				return fmt.Errorf("--timeout must be > 0")
+			}
+			if _, err := parseImageMirrors(imageMirrors); err != nil {
+				return err
+			}
+			return gates.validate()
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			ctx := cmd.Context()
+			errOut := cmd.ErrOrStderr()
+			currentLogLevel := effectiveLogLevel(logLevel)
+			startedAt := time.Now()
+			report := reportLine{Kind: "bundle-apply", DryRun: dryRun, Wait: wait}
+			defer func() {
+				report.Result = "success"
+				if runErr != nil {
+					report.Result = "fail"
+				}
+				report.ElapsedMS = time.Since(startedAt).Milliseconds()
+				writeReportTable(errOut, report)
+			}()
+
+			dec, err := approvalMode(cmd, yes, nonInteractive)
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			b, err := deploy.ReadBundle(f)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+			m := b.Manifest
+			if unpinned := m.UnpinnedImages(); requirePinned && len(unpinned) > 0 {
+				return fmt.Errorf("bundle has %d image(s) without a pinned digest: %s", len(unpinned), strings.Join(unpinned, ", "))
+			}
+			if strings.TrimSpace(releaseName) == "" {
+				releaseName = m.Release
+			}
+			resolvedNamespace := strings.TrimSpace(namespace)
+			if resolvedNamespace == "" {
+				resolvedNamespace = m.Namespace
+			}
+			report.Release, report.Namespace = releaseName, resolvedNamespace
+			report.Chart, report.Version = m.Chart, m.ChartVersion
+
+			dir, err := os.MkdirTemp("", "ktl-bundle-*")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+			chartPath, valuesPath, err := writeBundleInputs(dir, b.Chart, b.Values)
+			if err != nil {
+				return err
+			}
+
+			settings := cli.New()
+			if *kubeconfig != "" {
+				settings.KubeConfig = *kubeconfig
+			}
+			if *kubeContext != "" {
+				settings.KubeContext = *kubeContext
+			}
+			settings.SetNamespace(resolvedNamespace)
+			actionCfg := new(action.Configuration)
+			if err := actionCfg.Init(settings.RESTClientGetter(), resolvedNamespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+				return fmt.Errorf("init helm action config: %w", err)
+			}
+
+			var secretOptions *deploy.SecretOptions
+			if len(m.SecretRefs) > 0 {
+				resolver, auditSink, err := buildDeploySecretResolver(ctx, deploySecretConfig{
+					Chart:      ".",
+					ConfigPath: secretConfig,
+					Provider:   secretProvider,
+					Mode:       secretstore.ResolveModeValue,
+					ErrOut:     errOut,
+				})
+				if err != nil {
+					return err
+				}
+				secretOptions = &deploy.SecretOptions{Resolver: resolver, AuditSink: auditSink}
+			}
+
+			mirrors, _ := parseImageMirrors(imageMirrors)
+			postRenderer, err := loadPostRenderer(ctx, settings, postRender, appconfig.PostRendererConfig{
+				Name:   "bundle.images",
+				Images: &appconfig.ImageRewriteConfig{Mirrors: mirrors, Digests: m.ImageDigests()},
+			})
+			if err != nil {
+				return err
+			}
+			opts := deploy.InstallOptions{
+				Chart:        chartPath,
+				ReleaseName:  releaseName,
+				Namespace:    resolvedNamespace,
+				ValuesFiles:  append([]string{valuesPath}, valuesFiles...),
+				SetValues:    setValues,
+				Secrets:      secretOptions,
+				Timeout:      timeout,
+				Wait:         wait,
+				Atomic:       true,
+				PostRenderer: postRenderer,
+			}
+
+			fmt.Fprintf(errOut, "Bundle %s: %s %s from revision %d of %s (exported %s)\n", args[0], m.Chart, m.ChartVersion, m.Source.Revision, orDash(m.Source.Context), m.CreatedAt.Format(time.RFC3339))
+			fmt.Fprintf(errOut, "Target: %s/%s\n", resolvedNamespace, releaseName)
+			if n := len(m.UnpinnedImages()); n > 0 {
+				fmt.Fprintf(errOut, "Warning: %d image(s) are not pinned to a digest\n", n)
+			}
+
+			preview, err := deploy.GeneratePlanPreview(ctx, actionCfg, settings, nil, opts, false)
+			if err != nil {
+				return err
+			}
+			printPlanPreview(errOut, preview, currentLogLevel)
+			printImageReport(errOut, postRender, postRenderer)
+
+			if dryRun {
+				fmt.Fprintf(cmd.OutOrStdout(), "Bundle plan: would apply %s %s to %s/%s\n", m.Chart, m.ChartVersion, resolvedNamespace, releaseName)
+				return nil
+			}
+			contextName := derefString(kubeContext)
+			if strings.TrimSpace(contextName) == "" {
+				contextName = currentKubeContext(derefString(kubeconfig))
+			}
+			target := windowTarget{Context: contextName, Namespace: resolvedNamespace}
+			if err := enforceDeployGates(cmd, errOut, gates, target, releaseName, preview); err != nil {
+				return err
+			}
+			if err := confirmAction(ctx, cmd.InOrStdin(), errOut, dec, fmt.Sprintf("Apply bundle %s to %s/%s? Only 'yes' will be accepted:", m.Release, resolvedNamespace, releaseName), confirmModeYes, ""); err != nil {
+				return err
+			}
+			result, err := deploy.InstallOrUpgrade(ctx, actionCfg, settings, opts)
+			if err != nil {
+				return err
+			}
+			status := "unknown"
+			if rel := result.Release; rel != nil {
+				if rel.Info != nil {
+					status = rel.Info.Status.String()
+				}
+				report.Revision = rel.Version
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Release %s %s (revision %d)\n", releaseName, status, report.Revision)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&releaseName, "release", "", "Release name (default: the bundled release)")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to install into (default: the bundled release's namespace)")
+	cmd.Flags().StringArrayVarP(&valuesFiles, "values", "f", nil, "Values file applied on top of the bundled values (repeatable)")
+	cmd.Flags().StringArrayVar(&setValues, "set", nil, "Set values on top of the bundled values (key=val, repeatable)")
+	cmd.Flags().StringArrayVar(&imageMirrors, "image-mirror", nil, "Pull images from a mirror: from=to registry or repository prefix (repeatable)")
+	cmd.Flags().BoolVar(&requirePinned, "require-pinned-images", false, "Fail when any bundled image has no pinned digest")
+	cmd.Flags().StringVar(&secretProvider, "secret-provider", "", "Secret provider name for secret:// references")
+	cmd.Flags().StringVar(&secretConfig, "secret-config", "", "Secrets provider config file (defaults to ~/.ktl/config.yaml and repo .ktl.yaml)")
+	cmd.Flags().BoolVar(&wait, "wait", true, "Wait for resources to become ready")
+	cmd.Flags().DurationVar(&timeout, "timeout", timeout, "How long to wait for the upgrade")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the plan against the target and exit without changing the cluster")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Auto-approve confirmation prompts")
+	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Fail instead of prompting (requires --yes)")
+	addPostRenderFlags(cmd, &postRender)
+	addDeployGateFlags(cmd, &gates)
+	decorateCommandHelp(cmd, "Bundle Flags")
+	return cmd
+}
+
+// parseImageMirrors parses --image-mirror from=to entries.
+func parseImageMirrors(raw []string) ([]appconfig.ImageMirror, error) {
+	out := make([]appconfig.ImageMirror, 0, len(raw))
+	for _, entry := range raw {
+		from, to, ok := strings.Cut(entry, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("--image-mirror %q: expected from=to", entry)
+		}
+		out = append(out, appconfig.ImageMirror{From: from, To: to})
+	}
+	return out, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestBundlePlanRendersBundledInputs(t *testing.T) {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: "v2", Name: "shop", Version: "1.2.3"},
+		Templates: []*chart.File{{
+			Name: "templates/cm.yaml",
+			Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\ndata:\n  password: {{ .Values.db.password | quote }}\n"),
+		}},
+	}
+	chartData, err := deploy.PackChart(ch)
+	if err != nil {
+		t.Fatalf("pack chart: %v", err)
+	}
+	values, refs := deploy.StripSecretValues(map[string]any{"db": map[string]any{"password": "hunter2"}}, "shop", nil)
+	if len(refs) != 1 {
+		t.Fatalf("refs = %v", refs)
+	}
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	raw, err := bundlePlan(cmd, "shop", "prod", chartData, values, refs)
+	if err != nil {
+		t.Fatalf("bundle plan: %v", err)
+	}
+	var plan deployPlanResult
+	if err := json.Unmarshal(raw, &plan); err != nil {
+		t.Fatalf("decode plan: %v", err)
+	}
+	if plan.ReleaseName != "shop" || plan.ChartVersion != "1.2.3" || plan.RequestedChart != deploy.BundleChartFile {
+		t.Fatalf("plan = %+v", plan)
+	}
+	if len(plan.Secrets) != 1 || !plan.Secrets[0].Masked {
+		t.Fatalf("secrets = %+v", plan.Secrets)
+	}
+	if plan.Summary.Creates != 1 || len(plan.Changes) != 1 {
+		t.Fatalf("expected one create, got %+v", plan.Summary)
+	}
+	for _, blob := range plan.ManifestBlobs.Strings() {
+		if !strings.Contains(blob, "secret:///shop/db/password") || strings.Contains(blob, "hunter2") {
+			t.Fatalf("expected the stripped reference in the plan:\n%s", blob)
+		}
+	}
+}
+
+func TestParseImageMirrors(t *testing.T) {
+	got, err := parseImageMirrors([]string{"docker.io=registry.local/hub", " ghcr.io = registry.local/ghcr "})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(got) != 2 || got[1].From != "ghcr.io" || got[1].To != "registry.local/ghcr" {
+		t.Fatalf("mirrors = %+v", got)
+	}
+	if _, err := parseImageMirrors([]string{"docker.io"}); err == nil {
+		t.Fatalf("expected an error for a mirror without '='")
+	}
+}
+
+func TestBundleApplyIsGatedLikeApply(t *testing.T) {
+	if !auditedCommands["ktl bundle apply"] {
+		t.Fatalf("ktl bundle apply must be recorded in the audit log")
+	}
+	root := newRootCommand()
+	apply, _, err := root.Find([]string{"bundle", "apply"})
+	if err != nil {
+		t.Fatalf("find bundle apply: %v", err)
+	}
+	for _, name := range []string{"approval-policy", "approval-token", "override-window", "reason"} {
+		if apply.Flags().Lookup(name) == nil {
+			t.Errorf("bundle apply has no --%s flag", name)
+		}
+	}
+	if err := apply.ParseFlags([]string{"--override-window"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if err := apply.PreRunE(apply, []string{"bundle.tgz"}); err == nil || !strings.Contains(err.Error(), "--override-window requires --reason") {
+		t.Fatalf("expected --override-window without --reason to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,395 @@
+// File: cmd/ktl/capacity.go
+// Brief: CLI command wiring and implementation for 'capacity'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+// capacityReport is the `ktl capacity` snapshot: node headroom (cluster-wide), plus pending
+// pods and quota headroom for the selected namespace.
+type capacityReport struct {
+	Namespace        string                `json:"namespace,omitempty"`
+	MetricsAvailable bool                  `json:"metricsAvailable"`
+	Nodes            []capacityNode        `json:"nodes"`
+	Pending          []capacityPendingPod  `json:"pending,omitempty"`
+	Quotas           []capacityQuotaRow    `json:"quotas,omitempty"`
+	Totals           capacityClusterTotals `json:"totals"`
+	Warnings         []string              `json:"warnings,omitempty"`
+}
+
+type capacityNode struct {
+	Name        string           `json:"name"`
+	Ready       bool             `json:"ready"`
+	Schedulable bool             `json:"schedulable"`
+	CPU         capacityResource `json:"cpu"`
+	Memory      capacityResource `json:"memory"`
+	Pods        int64            `json:"pods"`
+	PodLimit    int64            `json:"podLimit"`
+}
+
+// capacityResource holds one resource of a node. CPU is in millicores and memory in bytes.
+type capacityResource struct {
+	Allocatable int64 `json:"allocatable"`
+	Requested   int64 `json:"requested"`
+	// Used is nil when metrics-server is unavailable.
+	Used *int64 `json:"used,omitempty"`
+}
+
+type capacityClusterTotals struct {
+	CPU    capacityResource `json:"cpu"`
+	Memory capacityResource `json:"memory"`
+}
+
+type capacityPendingPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+type capacityQuotaRow struct {
+	Quota    string `json:"quota"`
+	Resource string `json:"resource"`
+	Hard     string `json:"hard"`
+	Used     string `json:"used"`
+	Headroom string `json:"headroom"`
+	Status   string `json:"status"` // pass|warn|fail|unknown
+}
+
+func newCapacityCommand(kubeconfig, kubeContext *string) *cobra.Command {
+	var namespace string
+	var allNamespaces bool
+	var format string
+	cmd := &cobra.Command{
+		Use:   "capacity",
+		Short: "Snapshot node capacity, pending pods, and quota headroom",
+		Long: `Capacity summarizes what decides whether an apply will fit: per-node allocatable vs
+requested vs used (used needs metrics-server), pods that cannot be scheduled and why, and the
+headroom left in the namespace's ResourceQuotas.
+
+Nodes are always cluster-wide; pending pods and quotas follow -n/-A.`,
+		Example: `  # Check headroom before applying to prod
+  ktl capacity -n prod
+
+  # Machine-readable snapshot for a pipeline gate
+  ktl capacity -n prod --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch format {
+			case "table", "json":
+			default:
+				return fmt.Errorf("unsupported format %q (expected table or json)", format)
+			}
+			ctx := cmd.Context()
+			kClient, err := kube.New(ctx, *kubeconfig, *kubeContext)
+			if err != nil {
+				return err
+			}
+			ns := strings.TrimSpace(namespace)
+			if allNamespaces {
+				ns = ""
+			} else if ns == "" {
+				ns = kClient.Namespace
+				if ns == "" {
+					ns = "default"
+				}
+			}
+			metrics, err := kClient.MetricsClient()
+			if err != nil {
+				return err
+			}
+			report, err := buildCapacityReport(ctx, kClient.Clientset, metrics, ns)
+			if err != nil {
+				return err
+			}
+			if format == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			return writeCapacityTable(cmd.OutOrStdout(), report)
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace for pending pods and quotas (defaults to active context)")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Report pending pods and quotas across all namespaces")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table or json")
+	decorateCommandHelp(cmd, "Capacity Flags")
+	return cmd
+}
+
+// buildCapacityReport reads nodes and pods cluster-wide and pending pods and quotas in
+// namespace ("" for all). A failing metrics API is reported as a warning, not an error.
+func buildCapacityReport(ctx context.Context, client kubernetes.Interface, metrics metricsclient.Interface, namespace string) (*capacityReport, error) {
+	report := &capacityReport{Namespace: namespace}
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+
+	type requested struct{ cpu, mem, pods int64 }
+	byNode := map[string]*requested{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if pod.Spec.NodeName == "" {
+			if pod.Status.Phase == corev1.PodPending && (namespace == "" || pod.Namespace == namespace) {
+				report.Pending = append(report.Pending, pendingPod(pod))
+			}
+			continue
+		}
+		r := byNode[pod.Spec.NodeName]
+		if r == nil {
+			r = &requested{}
+			byNode[pod.Spec.NodeName] = r
+		}
+		cpu, mem := podRequests(pod)
+		r.cpu += cpu
+		r.mem += mem
+		r.pods++
+	}
+
+	used := map[string]corev1.ResourceList{}
+	if metrics != nil {
+		list, err := metrics.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("node usage unavailable (is metrics-server installed?): %v", err))
+		} else {
+			report.MetricsAvailable = true
+			for _, m := range list.Items {
+				used[m.Name] = m.Usage
+			}
+		}
+	}
+
+	for _, n := range nodes.Items {
+		row := capacityNode{
+			Name:        n.Name,
+			Schedulable: !n.Spec.Unschedulable,
+			CPU:         capacityResource{Allocatable: n.Status.Allocatable.Cpu().MilliValue()},
+			Memory:      capacityResource{Allocatable: n.Status.Allocatable.Memory().Value()},
+			PodLimit:    n.Status.Allocatable.Pods().Value(),
+		}
+		for _, c := range n.Status.Conditions {
+			if c.Type == corev1.NodeReady {
+				row.Ready = c.Status == corev1.ConditionTrue
+			}
+		}
+		if r := byNode[n.Name]; r != nil {
+			row.CPU.Requested, row.Memory.Requested, row.Pods = r.cpu, r.mem, r.pods
+		}
+		if u, ok := used[n.Name]; ok {
+			cpu, mem := u.Cpu().MilliValue(), u.Memory().Value()
+			row.CPU.Used, row.Memory.Used = &cpu, &mem
+		}
+		report.Nodes = append(report.Nodes, row)
+		report.Totals.CPU = addCapacity(report.Totals.CPU, row.CPU)
+		report.Totals.Memory = addCapacity(report.Totals.Memory, row.Memory)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Name < report.Nodes[j].Name })
+	sort.Slice(report.Pending, func(i, j int) bool {
+		if report.Pending[i].Namespace != report.Pending[j].Namespace {
+			return report.Pending[i].Namespace < report.Pending[j].Namespace
+		}
+		return report.Pending[i].Name < report.Pending[j].Name
+	})
+
+	quotas, err := client.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("resource quotas unavailable: %v", err))
+		return report, nil
+	}
+	for _, rq := range quotas.Items {
+		quota := rq.Name
+		if namespace == "" {
+			quota = rq.Namespace + "/" + rq.Name
+		}
+		hard := rq.Status.Hard
+		if len(hard) == 0 {
+			hard = rq.Spec.Hard
+		}
+		for name, hardQty := range hard {
+			usedQty := rq.Status.Used[name]
+			headroom := hardQty.DeepCopy()
+			headroom.Sub(usedQty)
+			report.Quotas = append(report.Quotas, capacityQuotaRow{
+				Quota:    quota,
+				Resource: string(name),
+				Hard:     hardQty.String(),
+				Used:     usedQty.String(),
+				Headroom: headroom.String(),
+				Status:   headroomStatus(hardQty, headroom),
+			})
+		}
+	}
+	sort.Slice(report.Quotas, func(i, j int) bool {
+		if report.Quotas[i].Quota != report.Quotas[j].Quota {
+			return report.Quotas[i].Quota < report.Quotas[j].Quota
+		}
+		return report.Quotas[i].Resource < report.Quotas[j].Resource
+	})
+	return report, nil
+}
+
+// podRequests returns the pod's effective CPU (millicores) and memory (bytes) requests: the
+// larger of the app containers' sum and any single init container, plus pod overhead.
+func podRequests(pod *corev1.Pod) (int64, int64) {
+	var cpu, mem resource.Quantity
+	for _, c := range pod.Spec.Containers {
+		cpu.Add(c.Resources.Requests[corev1.ResourceCPU])
+		mem.Add(c.Resources.Requests[corev1.ResourceMemory])
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if q := c.Resources.Requests[corev1.ResourceCPU]; q.Cmp(cpu) > 0 {
+			cpu = q.DeepCopy()
+		}
+		if q := c.Resources.Requests[corev1.ResourceMemory]; q.Cmp(mem) > 0 {
+			mem = q.DeepCopy()
+		}
+	}
+	cpu.Add(pod.Spec.Overhead[corev1.ResourceCPU])
+	mem.Add(pod.Spec.Overhead[corev1.ResourceMemory])
+	return cpu.MilliValue(), mem.Value()
+}
+
+func pendingPod(pod *corev1.Pod) capacityPendingPod {
+	out := capacityPendingPod{Namespace: pod.Namespace, Name: pod.Name, Reason: "Pending"}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+			if strings.TrimSpace(c.Reason) != "" {
+				out.Reason = c.Reason
+			}
+			out.Message = strings.TrimSpace(c.Message)
+		}
+	}
+	return out
+}
+
+func addCapacity(total, r capacityResource) capacityResource {
+	total.Allocatable += r.Allocatable
+	total.Requested += r.Requested
+	if r.Used != nil {
+		sum := *r.Used
+		if total.Used != nil {
+			sum += *total.Used
+		}
+		total.Used = &sum
+	}
+	return total
+}
+
+func writeCapacityTable(out io.Writer, report *capacityReport) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tSTATUS\tCPU REQ/ALLOC\tCPU USED\tMEM REQ/ALLOC\tMEM USED\tPODS")
+	printRow := func(name, status string, cpu, mem capacityResource, pods string) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", name, status,
+			capacityRatio(cpu, formatMilliCPU), capacityUsed(cpu, formatMilliCPU),
+			capacityRatio(mem, formatMemoryBytes), capacityUsed(mem, formatMemoryBytes), pods)
+	}
+	for _, n := range report.Nodes {
+		status := "Ready"
+		if !n.Ready {
+			status = "NotReady"
+		}
+		if !n.Schedulable {
+			status += ",SchedulingDisabled"
+		}
+		printRow(n.Name, status, n.CPU, n.Memory, fmt.Sprintf("%d/%d", n.Pods, n.PodLimit))
+	}
+	if len(report.Nodes) > 1 {
+		printRow("TOTAL", "", report.Totals.CPU, report.Totals.Memory, "")
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	scope := report.Namespace
+	if scope == "" {
+		scope = "all namespaces"
+	}
+	fmt.Fprintf(out, "\nPending pods (%s): %d\n", scope, len(report.Pending))
+	if len(report.Pending) > 0 {
+		tw = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "POD\tREASON\tMESSAGE")
+		for _, p := range report.Pending {
+			fmt.Fprintf(tw, "%s/%s\t%s\t%s\n", p.Namespace, p.Name, p.Reason, p.Message)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(report.Quotas) == 0 {
+		fmt.Fprintf(out, "\nQuotas (%s): none\n", scope)
+	} else {
+		fmt.Fprintf(out, "\nQuotas (%s):\n", scope)
+		tw = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "QUOTA\tRESOURCE\tUSED/HARD\tHEADROOM\tSTATUS")
+		for _, q := range report.Quotas {
+			fmt.Fprintf(tw, "%s\t%s\t%s/%s\t%s\t%s\n", q.Quota, q.Resource, q.Used, q.Hard, q.Headroom, q.Status)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	for _, w := range report.Warnings {
+		fmt.Fprintf(out, "\nwarning: %s\n", w)
+	}
+	return nil
+}
+
+func capacityRatio(r capacityResource, format func(int64) string) string {
+	return fmt.Sprintf("%s/%s (%s)", format(r.Requested), format(r.Allocatable), capacityPercent(r.Requested, r.Allocatable))
+}
+
+func capacityUsed(r capacityResource, format func(int64) string) string {
+	if r.Used == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s)", format(*r.Used), capacityPercent(*r.Used, r.Allocatable))
+}
+
+func capacityPercent(v, of int64) string {
+	if of <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", v*100/of)
+}
+
+func formatMilliCPU(m int64) string {
+	if m%1000 == 0 {
+		return fmt.Sprintf("%d", m/1000)
+	}
+	return fmt.Sprintf("%dm", m)
+}
+
+func formatMemoryBytes(b int64) string {
+	const gi, mi = 1 << 30, 1 << 20
+	if b >= 10*gi {
+		return fmt.Sprintf("%dGi", b/gi)
+	}
+	return fmt.Sprintf("%dMi", b/mi)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func capacityTestClient() *fake.Clientset {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	requests := func(cpu, mem string) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(mem),
+		}}
+	}
+	running := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "prod"},
+		Spec: corev1.PodSpec{
+			NodeName:       "node-a",
+			Containers:     []corev1.Container{{Name: "app", Resources: requests("500m", "1Gi")}, {Name: "sidecar", Resources: requests("100m", "128Mi")}},
+			InitContainers: []corev1.Container{{Name: "init", Resources: requests("1", "64Mi")}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	done := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-1", Namespace: "prod"},
+		Spec:       corev1.PodSpec{NodeName: "node-a", Containers: []corev1.Container{{Name: "job", Resources: requests("2", "2Gi")}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+	}
+	pending := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-2", Namespace: "prod"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Resources: requests("8", "1Gi")}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable",
+				Message: "0/1 nodes are available: 1 Insufficient cpu.",
+			}},
+		},
+	}
+	otherNS := pending.DeepCopy()
+	otherNS.Namespace = "dev"
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "prod"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2")},
+			Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1900m")},
+		},
+	}
+	return fake.NewSimpleClientset(node, running, done, pending, otherNS, quota)
+}
+
+func TestBuildCapacityReport(t *testing.T) {
+	metrics := metricsfake.NewSimpleClientset()
+	metrics.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &metricsv1beta1.NodeMetricsList{Items: []metricsv1beta1.NodeMetrics{{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("300m"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		}}}, nil
+	})
+
+	report, err := buildCapacityReport(context.Background(), capacityTestClient(), metrics, "prod")
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if !report.MetricsAvailable || len(report.Nodes) != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	n := report.Nodes[0]
+	// The init container's 1 CPU outweighs the 600m app containers; the succeeded pod is ignored.
+	if n.CPU.Allocatable != 4000 || n.CPU.Requested != 1000 || n.Memory.Requested != (1<<30)+(128<<20) || n.Pods != 1 {
+		t.Fatalf("unexpected node: %+v", n)
+	}
+	if n.CPU.Used == nil || *n.CPU.Used != 300 || n.Memory.Used == nil || *n.Memory.Used != 2<<30 {
+		t.Fatalf("unexpected usage: %+v", n)
+	}
+	if len(report.Pending) != 1 || report.Pending[0].Name != "api-2" || report.Pending[0].Reason != "Unschedulable" {
+		t.Fatalf("unexpected pending pods: %+v", report.Pending)
+	}
+	if len(report.Quotas) != 1 || report.Quotas[0].Headroom != "100m" || report.Quotas[0].Status != "warn" {
+		t.Fatalf("unexpected quotas: %+v", report.Quotas)
+	}
+
+	var out bytes.Buffer
+	if err := writeCapacityTable(&out, report); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for _, want := range []string{"1/4 (25%)", "300m (7%)", "Insufficient cpu", "1900m/2", "warn"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestBuildCapacityReportWithoutMetricsServer(t *testing.T) {
+	metrics := metricsfake.NewSimpleClientset()
+	metrics.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("the server could not find the requested resource")
+	})
+	report, err := buildCapacityReport(context.Background(), capacityTestClient(), metrics, "")
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if report.MetricsAvailable || report.Nodes[0].CPU.Used != nil || len(report.Warnings) != 1 {
+		t.Fatalf("expected usage to be reported unavailable: %+v", report)
+	}
+	if len(report.Pending) != 2 || report.Quotas[0].Quota != "prod/compute" {
+		t.Fatalf("expected all-namespace pending pods and quotas: %+v", report)
+	}
+}
@@ -0,0 +1,150 @@
+// File: cmd/ktl/capture.go
+// Brief: CLI command wiring and implementation for 'capture'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kubekattle/ktl/internal/capture"
+	"github.com/spf13/cobra"
+)
+
+func newCaptureCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capture",
+		Short: "Inspect capture databases written with --capture",
+		Long: `Commands such as ktl apply, ktl logs, and ktl tunnel record a SQLite capture when run with --capture.
+ktl capture works on those files without opening sqlite3.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newCaptureQueryCommand(), newCaptureMergeCommand())
+	return cmd
+}
+
+func newCaptureQueryCommand() *cobra.Command {
+	var query string
+	var report string
+	var output string
+	cmd := &cobra.Command{
+		Use:   "query <capture.sqlite>",
+		Short: "Run SQL or a canned report against a capture",
+		Example: `  # Slowest deploy phases across every session in the capture
+  ktl capture query ./ktl-capture-apply.sqlite --report slowest-phases
+
+  # Recurring errors as CSV
+  ktl capture query ./cap.sqlite --report error-summary -o csv > errors.csv
+
+  # Ad-hoc SQL (the database is opened read-only)
+  ktl capture query ./cap.sqlite --sql "select kind, count(*) from ktl_capture_events group by kind"`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query = strings.TrimSpace(query)
+			report = strings.TrimSpace(report)
+			if (query == "") == (report == "") {
+				return fmt.Errorf("specify exactly one of --sql or --report")
+			}
+			if report != "" {
+				r, ok := capture.Reports[report]
+				if !ok {
+					return fmt.Errorf("unknown --report %q (available: %s)", report, strings.Join(capture.ReportNames(), ", "))
+				}
+				query = r.SQL
+			}
+			db, err := capture.OpenReadOnly(args[0])
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			res, err := capture.Query(cmd.Context(), db, query)
+			if err != nil {
+				return fmt.Errorf("query capture: %w", err)
+			}
+			return writeCaptureQueryResult(cmd.OutOrStdout(), res, output)
+		},
+	}
+	cmd.Flags().StringVar(&query, "sql", "", "SQL to run (tables: ktl_capture_sessions, ktl_capture_events, ktl_capture_artifacts, ktl_capture_tags)")
+	cmd.Flags().StringVar(&report, "report", "", "Canned report: "+strings.Join(capture.ReportNames(), ", "))
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, csv, or json")
+	decorateCommandHelp(cmd, "Capture Flags")
+	return cmd
+}
+
+func newCaptureMergeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merge <out.sqlite> <capture.sqlite>...",
+		Short: "Merge capture sessions into one database",
+		Long: `Copy every session from the input captures into out.sqlite (created if missing), so a whole
+release train or incident can be browsed and queried in one place. Sessions whose ID already exists
+in the output get a new ID; inputs are not modified.`,
+		Example: `  # Collect an incident's captures
+  ktl capture merge incident.sqlite ./captures/*.sqlite`,
+		Args:          cobra.MinimumNArgs(2),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stats, err := capture.Merge(cmd.Context(), args[0], args[1:])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Merged %d sessions (%d events, %d artifacts) into %s", stats.Sessions, stats.Events, stats.Artifacts, args[0])
+			if stats.Rekeyed > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "; %d sessions got new IDs", stats.Rekeyed)
+			}
+			fmt.Fprintln(cmd.OutOrStdout())
+			return nil
+		},
+	}
+	decorateCommandHelp(cmd, "Capture Flags")
+	return cmd
+}
+
+func writeCaptureQueryResult(out io.Writer, res capture.QueryResult, format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "json":
+		rows := make([]map[string]any, 0, len(res.Rows))
+		for _, row := range res.Rows {
+			obj := make(map[string]any, len(row))
+			for i, v := range row {
+				obj[res.Columns[i]] = v
+			}
+			rows = append(rows, obj)
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "csv":
+		w := csv.NewWriter(out)
+		if err := w.Write(res.Columns); err != nil {
+			return err
+		}
+		if err := w.WriteAll(res.Records()); err != nil {
+			return err
+		}
+		return w.Error()
+	case "", "table":
+	default:
+		return fmt.Errorf("unsupported --output %q (use table, csv, or json)", format)
+	}
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(res.Columns, "\t")))
+	for _, rec := range res.Records() {
+		for i := range rec {
+			rec[i] = dashIfEmpty(strings.ReplaceAll(rec[i], "\n", " "))
+		}
+		fmt.Fprintln(tw, strings.Join(rec, "\t"))
+	}
+	return tw.Flush()
+}
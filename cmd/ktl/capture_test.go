@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/capture"
+)
+
+func TestWriteCaptureQueryResult(t *testing.T) {
+	res := capture.QueryResult{
+		Columns: []string{"phase", "seconds", "note"},
+		Rows:    [][]any{{"wait", 42.5, nil}, {"render, hooks", int64(1), "x"}},
+	}
+	cases := map[string]string{
+		"csv":   "phase,seconds,note\nwait,42.5,\n\"render, hooks\",1,x\n",
+		"json":  "[\n  {\n    \"note\": null,\n    \"phase\": \"wait\",\n    \"seconds\": 42.5\n  },\n  {\n    \"note\": \"x\",\n    \"phase\": \"render, hooks\",\n    \"seconds\": 1\n  }\n]\n",
+		"table": "PHASE          SECONDS  NOTE\nwait           42.5     -\nrender, hooks  1        x\n",
+	}
+	for format, want := range cases {
+		var buf bytes.Buffer
+		if err := writeCaptureQueryResult(&buf, res, format); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if buf.String() != want {
+			t.Fatalf("%s output:\n%q\nwant:\n%q", format, buf.String(), want)
+		}
+	}
+	if err := writeCaptureQueryResult(&bytes.Buffer{}, res, "xml"); err == nil || !strings.Contains(err.Error(), "unsupported --output") {
+		t.Fatalf("expected format error, got %v", err)
+	}
+}
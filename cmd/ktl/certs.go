@@ -0,0 +1,140 @@
+// File: cmd/ktl/certs.go
+// Brief: CLI command wiring and implementation for 'certs'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/verify"
+	"github.com/spf13/cobra"
+)
+
+func newCertsCommand(kubeconfig, kubeContext *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Inspect TLS certificates used by a namespace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newCertsStatusCommand(kubeconfig, kubeContext))
+	return cmd
+}
+
+func newCertsStatusCommand(kubeconfig, kubeContext *string) *cobra.Command {
+	var namespace string
+	var within time.Duration
+	var format string
+	var strict bool
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report certificate expiry and webhook CA mismatches",
+		Long: `Status reads the live kubernetes.io/tls Secrets, cert-manager Certificates, and the
+admission webhooks served from the namespace, and reports every certificate that is expired,
+expiring within --within, unparseable, or (for webhooks) not signed by the CA cert-manager was
+asked to inject.
+
+The same checks run in ktl verify as the k8s/tls_secret_certificate_expiring,
+k8s/certmanager_certificate_expiring, k8s/webhook_ca_bundle_expiring, and
+k8s/webhook_ca_bundle_mismatch rules.
+
+Exits non-zero when a certificate is expired, mismatched, or invalid; --strict also fails on
+expiring and not-ready certificates.`,
+		Example: `  # Certificates in prod expiring within the next 30 days
+  ktl certs status -n prod
+
+  # Gate a pipeline on a two-week window
+  ktl certs status -n prod --within 336h --strict --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch format {
+			case "table", "json":
+			default:
+				return fmt.Errorf("unsupported format %q (expected table or json)", format)
+			}
+			ctx := cmd.Context()
+			kClient, err := kube.New(ctx, *kubeconfig, *kubeContext)
+			if err != nil {
+				return err
+			}
+			ns := strings.TrimSpace(namespace)
+			if ns == "" {
+				ns = kClient.Namespace
+				if ns == "" {
+					ns = "default"
+				}
+			}
+			objs, err := collectNamespacedObjects(ctx, kClient, ns)
+			if err != nil {
+				return err
+			}
+			checks := verify.InspectCertificates(objs, time.Now(), within)
+			if format == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(checks); err != nil {
+					return err
+				}
+			} else if err := writeCertsTable(cmd.OutOrStdout(), checks); err != nil {
+				return err
+			}
+			return certsStatusError(checks, strict)
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to inspect (defaults to active context)")
+	cmd.Flags().DurationVar(&within, "within", 30*24*time.Hour, "Report certificates expiring within this window")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table or json")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Exit non-zero on expiring and not-ready certificates too")
+	decorateCommandHelp(cmd, "Certs Flags")
+	return cmd
+}
+
+func writeCertsTable(out io.Writer, checks []verify.CertCheck) error {
+	if len(checks) == 0 {
+		_, err := fmt.Fprintln(out, "No TLS Secrets, cert-manager Certificates, or webhook caBundles found.")
+		return err
+	}
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RESOURCE\tSOURCE\tSUBJECT\tNOT AFTER\tSTATUS\tMESSAGE")
+	for _, c := range checks {
+		notAfter := "-"
+		if c.NotAfter != nil {
+			notAfter = c.NotAfter.Format("2006-01-02")
+		}
+		subject := c.Subject
+		if subject == "" {
+			subject = "-"
+		}
+		fmt.Fprintf(tw, "%s/%s\t%s\t%s\t%s\t%s\t%s\n", strings.ToLower(c.Kind), c.Name, c.Source, subject, notAfter, c.Status, c.Message)
+	}
+	return tw.Flush()
+}
+
+func certsStatusError(checks []verify.CertCheck, strict bool) error {
+	var failed, warned int
+	for _, c := range checks {
+		switch c.Status {
+		case verify.CertExpired, verify.CertMismatch, verify.CertInvalid:
+			failed++
+		case verify.CertExpiring, verify.CertNotReady:
+			warned++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d certificate issue(s) need attention", failed)
+	}
+	if strict && warned > 0 {
+		return fmt.Errorf("%d certificate(s) expiring or not ready (--strict)", warned)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/verify"
+)
+
+func TestWriteCertsTableAndStatusError(t *testing.T) {
+	notAfter := time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC)
+	checks := []verify.CertCheck{
+		{Kind: "Secret", Namespace: "prod", Name: "api-tls", Source: "tls.crt", Subject: "api.example.com", NotAfter: &notAfter, Status: verify.CertExpiring, Message: "expires in 10 days"},
+		{Kind: "ValidatingWebhookConfiguration", Name: "policy", Source: "webhook validate.policy.example.com", Status: verify.CertOK},
+	}
+	var out bytes.Buffer
+	if err := writeCertsTable(&out, checks); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for _, want := range []string{"secret/api-tls", "2024-06-11", "expiring", "expires in 10 days", "validatingwebhookconfiguration/policy"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+
+	if err := certsStatusError(checks, false); err != nil {
+		t.Fatalf("expiring certificates should only fail with --strict: %v", err)
+	}
+	if err := certsStatusError(checks, true); err == nil {
+		t.Fatalf("expected --strict to fail on expiring certificates")
+	}
+	checks[1].Status = verify.CertMismatch
+	if err := certsStatusError(checks, false); err == nil || !strings.Contains(err.Error(), "1 certificate issue") {
+		t.Fatalf("expected mismatch to fail, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+// File: cmd/ktl/chart_tests.go
+// Brief: `ktl test` command wiring and the chart test runner shared with apply --run-chart-tests.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap/zapcore"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"k8s.io/client-go/kubernetes"
+)
+
+// exitChartTestsFailed is the exit code when chart tests ran and at least one failed, so CI can
+// tell failing tests apart from errors that kept them from running (exit code 1).
+const exitChartTestsFailed = 3
+
+func newTestCommand(kubeconfig *string, kubeContext *string, logLevel *string) *cobra.Command {
+	var namespace string
+	var releaseName string
+	var filter []string
+	var noLogs bool
+	var format string
+	timeout := 5 * time.Minute
+
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Run a release's Helm chart tests",
+		Long: `Run the helm.sh/hook: test hooks of the release's latest revision (like helm test), streaming
+the test pods' logs while they run, and print a pass/fail summary.
+
+Exits 0 when every test passed, 3 when a test failed, and 1 when the tests could not be run.`,
+		Example: `  ktl test --release shop -n prod
+  ktl test --release shop -n prod --filter shop-test-db --timeout 2m
+  ktl test --release shop -n prod --format json > chart-tests.json`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(releaseName) == "" {
+				return fmt.Errorf("--release is required")
+			}
+			if timeout <= 0 {
+				return fmt.Errorf("--timeout must be > 0")
+			}
+			switch format {
+			case "table", "json":
+			default:
+				return fmt.Errorf("--format must be table or json")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			kubeClient, err := kube.New(ctx, *kubeconfig, *kubeContext)
+			if err != nil {
+				return err
+			}
+			resolvedNamespace := strings.TrimSpace(namespace)
+			if resolvedNamespace == "" {
+				resolvedNamespace = kubeClient.Namespace
+			}
+			if resolvedNamespace == "" {
+				resolvedNamespace = "default"
+			}
+			settings := cli.New()
+			if kubeconfig != nil && *kubeconfig != "" {
+				settings.KubeConfig = *kubeconfig
+			}
+			if kubeContext != nil && *kubeContext != "" {
+				settings.KubeContext = *kubeContext
+			}
+			settings.SetNamespace(resolvedNamespace)
+			settings.Debug = shouldLogAtLevel(effectiveLogLevel(logLevel), zapcore.DebugLevel)
+			actionCfg := new(action.Configuration)
+			if err := actionCfg.Init(settings.RESTClientGetter(), resolvedNamespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+				return fmt.Errorf("init helm action config: %w", err)
+			}
+
+			var logs io.Writer
+			if !noLogs {
+				logs = cmd.ErrOrStderr()
+			}
+			report, err := runChartTests(cmd, actionCfg, kubeClient.Clientset, nil, deploy.ChartTestOptions{
+				Release:   releaseName,
+				Namespace: resolvedNamespace,
+				Timeout:   timeout,
+				Filter:    filter,
+				Logs:      logs,
+			})
+			if format == "json" && report != nil {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if encErr := enc.Encode(report); encErr != nil {
+					return encErr
+				}
+			}
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&releaseName, "release", "", "Helm release name")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the release (default: current context)")
+	cmd.Flags().DurationVar(&timeout, "timeout", timeout, "Time to wait for each test to finish")
+	cmd.Flags().StringSliceVar(&filter, "filter", nil, "Only run these tests by name; prefix a name with ! to skip it instead (repeatable)")
+	cmd.Flags().BoolVar(&noLogs, "no-logs", false, "Do not stream the test pods' logs")
+	cmd.Flags().StringVar(&format, "format", "table", "Summary format: table or json (json goes to stdout)")
+	decorateCommandHelp(cmd, "Test Flags")
+	return cmd
+}
+
+// runChartTests runs the release's chart tests, prints the summary table to stderr and mirrors
+// the results to stream when set.
+func runChartTests(cmd *cobra.Command, actionCfg *action.Configuration, clientset kubernetes.Interface, stream *deploy.StreamBroadcaster, opts deploy.ChartTestOptions) (*deploy.ChartTestReport, error) {
+	errOut := cmd.ErrOrStderr()
+	fmt.Fprintf(errOut, "Running chart tests for %s/%s...\n", opts.Namespace, opts.Release)
+	report, err := deploy.RunChartTests(cmd.Context(), actionCfg, clientset, opts)
+	if report == nil {
+		return nil, err
+	}
+	writeChartTestSummary(errOut, report)
+	for _, t := range report.Tests {
+		level := "info"
+		if t.Phase != "Succeeded" {
+			level = "warn"
+		}
+		stream.EmitEvent(level, fmt.Sprintf("chart test %s: %s", t.Name, t.Phase))
+	}
+	return report, err
+}
+
+func writeChartTestSummary(w io.Writer, report *deploy.ChartTestReport) {
+	if len(report.Tests) == 0 {
+		fmt.Fprintf(w, "Chart tests: release %s has no tests\n", report.Release)
+		return
+	}
+	fmt.Fprintf(w, "Chart tests (%d passed, %d failed, %s):\n", report.Passed, report.Failed, report.Duration)
+	for _, t := range report.Tests {
+		mark := "-"
+		switch t.Phase {
+		case "Succeeded":
+			mark = "ok"
+		case "Failed":
+			mark = "FAIL"
+		}
+		line := fmt.Sprintf("  %-4s %s %s %s", mark, t.Kind, t.Name, t.Phase)
+		if t.Duration != "" {
+			line += " (" + t.Duration + ")"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
@@ -0,0 +1,240 @@
+// File: cmd/ktl/completion.go
+// Brief: CLI command wiring and implementation for 'completion'.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/kubectx"
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+func newCompletionCommand() *cobra.Command {
+	var noDescriptions bool
+	cmd := &cobra.Command{
+		Use:   "completion bash|zsh|fish|powershell",
+		Short: "Generate shell completion scripts",
+		Long: `Generate a completion script for bash, zsh, fish, or PowerShell.
+
+Besides commands and flags, the scripts complete kube contexts (--context), namespaces
+(--namespace), Helm releases (--release), and stack releases (ktl stack --release). Cluster
+lookups are cached for a short time (KTL_COMPLETION_CACHE_TTL, default 60s) so repeated TAB
+presses stay fast.`,
+		Example: `  # bash (needs bash-completion v2)
+  source <(ktl completion bash)
+
+  # zsh
+  ktl completion zsh > "${fpath[1]}/_ktl"
+
+  # fish
+  ktl completion fish > ~/.config/fish/completions/ktl.fish
+
+  # PowerShell
+  ktl completion powershell | Out-String | Invoke-Expression`,
+		Args:                  cobra.ExactArgs(1),
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+			switch strings.ToLower(args[0]) {
+			case "bash":
+				return root.GenBashCompletionV2(out, !noDescriptions)
+			case "zsh":
+				if noDescriptions {
+					return root.GenZshCompletionNoDesc(out)
+				}
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, !noDescriptions)
+			case "powershell", "pwsh":
+				if noDescriptions {
+					return root.GenPowerShellCompletion(out)
+				}
+				return root.GenPowerShellCompletionWithDesc(out)
+			default:
+				return fmt.Errorf("unsupported shell %q (expected bash, zsh, fish, or powershell)", args[0])
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&noDescriptions, "no-descriptions", false, "Omit completion descriptions")
+	return cmd
+}
+
+// registerDynamicCompletions attaches the cluster-aware completers to every command below root:
+// --context everywhere, --namespace on commands that define it, and --release on commands that
+// pair it with --namespace (Helm releases).
+func registerDynamicCompletions(root *cobra.Command, kubeconfig, kubeContext *string) {
+	_ = root.RegisterFlagCompletionFunc("context", completeKubeContexts(kubeconfig))
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		if ownFlag(c, "namespace") {
+			_ = c.RegisterFlagCompletionFunc("namespace", completeNamespaces(kubeconfig, kubeContext))
+			if ownFlag(c, "release") {
+				_ = c.RegisterFlagCompletionFunc("release", completeHelmReleases(kubeconfig, kubeContext))
+			}
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
+
+// ownFlag reports whether c declares the flag itself, without merging inherited flags.
+func ownFlag(c *cobra.Command, name string) bool {
+	return c.Flags().Lookup(name) != nil || c.PersistentFlags().Lookup(name) != nil
+}
+
+func completeKubeContexts(kubeconfig *string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		kc, err := kubectx.LoadKubeconfig(derefString(kubeconfig))
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return filterCompletions(kc.Contexts(), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeNamespaces(kubeconfig, kubeContext *string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		kubeconfigPath, contextName := completionTarget(kubeconfig, kubeContext)
+		names, err := cachedCompletions("namespaces", []string{kubeconfigPath, contextName}, func(ctx context.Context) ([]string, error) {
+			return listNamespaceNames(ctx, kubeconfigPath, contextName)
+		})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeHelmReleases(kubeconfig, kubeContext *string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		kubeconfigPath, contextName := completionTarget(kubeconfig, kubeContext)
+		namespace := ""
+		if f := cmd.Flag("namespace"); f != nil {
+			namespace = strings.TrimSpace(f.Value.String())
+		}
+		if namespace == "" {
+			if kc, err := kubectx.LoadKubeconfig(kubeconfigPath); err == nil {
+				namespace = kc.Namespace(contextName)
+			}
+		}
+		if namespace == "" {
+			namespace = "default"
+		}
+		names, err := cachedCompletions("releases", []string{kubeconfigPath, contextName, namespace}, func(ctx context.Context) ([]string, error) {
+			return listReleaseNames(ctx, kubeconfigPath, contextName, namespace)
+		})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completionTarget resolves the kubeconfig and context a completion talks to, so cache entries
+// follow `ktl ctx` switches.
+func completionTarget(kubeconfig, kubeContext *string) (string, string) {
+	path := derefString(kubeconfig)
+	contextName := strings.TrimSpace(derefString(kubeContext))
+	if contextName == "" {
+		contextName = strings.TrimSpace(os.Getenv("KTL_CONTEXT"))
+	}
+	if contextName == "" {
+		if kc, err := kubectx.LoadKubeconfig(path); err == nil {
+			contextName = kc.CurrentContext()
+		}
+	}
+	return path, contextName
+}
+
+func listReleaseNames(ctx context.Context, kubeconfig, contextName, namespace string) ([]string, error) {
+	settings := cli.New()
+	if kubeconfig != "" {
+		settings.KubeConfig = kubeconfig
+	}
+	settings.KubeContext = contextName
+	settings.SetNamespace(namespace)
+	actionCfg := new(action.Configuration)
+	if err := actionCfg.Init(settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+		return nil, fmt.Errorf("init helm action config: %w", err)
+	}
+	list := action.NewList(actionCfg)
+	list.All = true
+	list.SetStateMask()
+	done := make(chan struct{})
+	var (
+		names []string
+		err   error
+	)
+	go func() {
+		defer close(done)
+		releases, runErr := list.Run()
+		if runErr != nil {
+			err = runErr
+			return
+		}
+		for _, rel := range releases {
+			names = append(names, rel.Name)
+		}
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func filterCompletions(candidates []string, toComplete string) []string {
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, toComplete) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// completeStackReleases offers the release names of the compiled stack for ktl stack --release.
+func completeStackReleases(cmd *cobra.Command, common stackCommandCommon, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := resolveStackCommandConfig(cmd, common)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	p, err := stack.Compile(cfg.Universe, stack.CompileOptions{Profile: cfg.Profile, Overlays: cfg.Overlays})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	// --release takes a comma-separated list; complete the last element.
+	prefix, current := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, current = toComplete[:i+1], toComplete[i+1:]
+	}
+	seen := map[string]struct{}{}
+	var out []string
+	for _, node := range p.Nodes {
+		if _, ok := seen[node.Name]; ok || !strings.HasPrefix(node.Name, current) {
+			continue
+		}
+		seen[node.Name] = struct{}{}
+		out = append(out, prefix+node.Name+"\t"+node.Cluster.Name+"/"+node.Namespace)
+	}
+	sort.Strings(out)
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,114 @@
+// File: cmd/ktl/completion_cache.go
+// Brief: CLI command wiring and implementation for 'completion cache'.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultCompletionCacheTTL keeps cluster lookups fresh enough for a burst of TAB presses.
+const defaultCompletionCacheTTL = 60 * time.Second
+
+// completionLookupTimeout bounds a cluster lookup behind a TAB press.
+const completionLookupTimeout = 5 * time.Second
+
+type completionCacheEntry struct {
+	At     time.Time `json:"at"`
+	Values []string  `json:"values"`
+}
+
+type completionCacheFile struct {
+	Entries map[string]completionCacheEntry `json:"entries"`
+}
+
+// completionCachePath is overridden in tests.
+var completionCachePath = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ktl", "completion.json"), nil
+}
+
+// completionCacheTTL reads KTL_COMPLETION_CACHE_TTL; 0 disables the cache.
+func completionCacheTTL() time.Duration {
+	raw := strings.TrimSpace(os.Getenv("KTL_COMPLETION_CACHE_TTL"))
+	if raw == "" {
+		return defaultCompletionCacheTTL
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl < 0 {
+		return defaultCompletionCacheTTL
+	}
+	return ttl
+}
+
+// cachedCompletions returns the values cached under kind and scope when they are younger than
+// the TTL, and otherwise calls fetch and stores its result. Cache read/write failures only cost
+// the cache, never the completion.
+func cachedCompletions(kind string, scope []string, fetch func(context.Context) ([]string, error)) ([]string, error) {
+	ttl := completionCacheTTL()
+	key := kind + "|" + strings.Join(scope, "|")
+	now := time.Now()
+	path, pathErr := completionCachePath()
+	var cache completionCacheFile
+	if ttl > 0 && pathErr == nil {
+		if raw, err := os.ReadFile(path); err == nil {
+			_ = json.Unmarshal(raw, &cache)
+		}
+		if entry, ok := cache.Entries[key]; ok && now.Sub(entry.At) >= 0 && now.Sub(entry.At) < ttl {
+			return entry.Values, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionLookupTimeout)
+	defer cancel()
+	values, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ttl <= 0 || pathErr != nil {
+		return values, nil
+	}
+	if cache.Entries == nil {
+		cache.Entries = map[string]completionCacheEntry{}
+	}
+	for k, entry := range cache.Entries {
+		if now.Sub(entry.At) >= ttl {
+			delete(cache.Entries, k)
+		}
+	}
+	cache.Entries[key] = completionCacheEntry{At: now, Values: values}
+	_ = writeCompletionCache(path, cache)
+	return values, nil
+}
+
+func writeCompletionCache(path string, cache completionCacheFile) error {
+	raw, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".completion-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
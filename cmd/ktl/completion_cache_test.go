@@ -0,0 +1,59 @@
+// File: cmd/ktl/completion_cache_test.go
+// Brief: Tests for the shell completion lookup cache.
+
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestCachedCompletionsReusesFreshEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "completion.json")
+	prev := completionCachePath
+	completionCachePath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { completionCachePath = prev })
+	t.Setenv("KTL_COMPLETION_CACHE_TTL", "")
+
+	calls := 0
+	fetch := func(context.Context) ([]string, error) {
+		calls++
+		return []string{"default", "prod"}, nil
+	}
+	for i := 0; i < 2; i++ {
+		got, err := cachedCompletions("namespaces", []string{"", "prod-eu"}, fetch)
+		if err != nil {
+			t.Fatalf("cachedCompletions: %v", err)
+		}
+		if len(got) != 2 || got[1] != "prod" {
+			t.Fatalf("unexpected values %v", got)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected one lookup, got %d", calls)
+	}
+
+	// Another scope is a separate entry.
+	if _, err := cachedCompletions("namespaces", []string{"", "staging"}, fetch); err != nil {
+		t.Fatalf("cachedCompletions: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected a lookup for the new scope, got %d calls", calls)
+	}
+
+	t.Setenv("KTL_COMPLETION_CACHE_TTL", "0")
+	if _, err := cachedCompletions("namespaces", []string{"", "prod-eu"}, fetch); err != nil {
+		t.Fatalf("cachedCompletions: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected TTL 0 to bypass the cache, got %d calls", calls)
+	}
+}
+
+func TestFilterCompletions(t *testing.T) {
+	got := filterCompletions([]string{"kube-system", "kube-public", "default"}, "kube-s")
+	if len(got) != 1 || got[0] != "kube-system" {
+		t.Fatalf("unexpected completions %v", got)
+	}
+}
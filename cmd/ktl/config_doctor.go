@@ -0,0 +1,106 @@
+// File: cmd/ktl/config_doctor.go
+// Brief: CLI command wiring and implementation for 'config doctor'.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/configdoctor"
+	"github.com/spf13/cobra"
+)
+
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect and validate ktl configuration files",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newConfigDoctorCommand(), newConfigSchemaCommand())
+	decorateCommandHelp(cmd, "Config Flags")
+	return cmd
+}
+
+func newConfigDoctorCommand() *cobra.Command {
+	var root string
+	var stackDir string
+	var verifyConfigs []string
+	var offline bool
+	var strict bool
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Validate .ktl.yaml, stack.yaml, and verify configs together",
+		Long: `Load every repo-level ktl config and cross-check what they reference:
+
+  .ktl.yaml       unknown keys, secret providers, deploy.postRenderers
+  stack.yaml      unknown keys, compile errors, local chart paths, values files,
+  release.yaml    and script hooks (present and executable)
+  values files    secret:// references resolve against the configured providers
+  verify*.yaml    target, chart, values, manifest, and rulesDir
+
+Problems are printed as file:line: severity: message. The command exits non-zero when any
+error is found (or any warning with --strict), so it can gate CI.`,
+		Example: `  ktl config doctor
+  ktl config doctor --offline --strict
+  ktl config doctor --stack ./stacks/prod --verify ci/verify-prod.yaml --format json`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(root) == "" {
+				cwd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				root = appconfig.FindRepoRoot(cwd)
+				if root == "" {
+					root = cwd
+				}
+			}
+			report := configdoctor.Run(cmd.Context(), configdoctor.Options{
+				Root:          root,
+				StackDir:      stackDir,
+				VerifyConfigs: verifyConfigs,
+				GlobalConfig:  appconfig.DefaultGlobalPath(),
+				Offline:       offline,
+			})
+			switch strings.ToLower(strings.TrimSpace(format)) {
+			case "", "text":
+				out := cmd.OutOrStdout()
+				for _, issue := range report.Issues {
+					fmt.Fprintln(out, issue.String())
+				}
+				fmt.Fprintf(out, "Checked %d file(s): %d error(s), %d warning(s)\n", len(report.Files), report.Errors, report.Warnings)
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unsupported --format %q (want text or json)", format)
+			}
+			if report.Errors > 0 || (strict && report.Warnings > 0) {
+				return fmt.Errorf("config doctor found %d error(s), %d warning(s)", report.Errors, report.Warnings)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&root, "root", "", "Repository root to check (default: git root of the current directory)")
+	cmd.Flags().StringVar(&stackDir, "stack", "", "Stack root to check (default: the repository root when it has a stack.yaml)")
+	cmd.Flags().StringArrayVar(&verifyConfigs, "verify", nil, "Additional verify config to check (can be repeated)")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Do not contact remote secret providers; only check that referenced providers exist")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Exit non-zero on warnings too")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
+	decorateCommandHelp(cmd, "Doctor Flags")
+	return cmd
+}
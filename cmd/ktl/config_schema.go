@@ -0,0 +1,99 @@
+// File: cmd/ktl/config_schema.go
+// Brief: CLI command wiring and implementation for 'config schema'.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kubekattle/ktl/internal/configschema"
+	"github.com/spf13/cobra"
+)
+
+func newConfigSchemaCommand() *cobra.Command {
+	var outputDir string
+	var modeline bool
+
+	cmd := &cobra.Command{
+		Use:   "schema [ktl|stack|release|verify]",
+		Short: "Print the JSON Schema for a ktl config file",
+		Long: `Print the JSON Schema (draft-07) for one of ktl's config files. The schemas are generated from
+the structs this build loads, so they match the installed version:
+
+  ktl       .ktl.yaml and ~/.ktl/config.yaml
+  stack     stack.yaml
+  release   release.yaml
+  verify    ktl verify configs
+
+Editors that use yaml-language-server (VS Code YAML, neovim, Helix, JetBrains) pick a schema up
+from a modeline comment at the top of the file. ktl init writes it for .ktl.yaml and stack.yaml;
+print it for other files with --modeline. To pin the schema to this ktl version instead of the
+published one, write the files with --output-dir and point the modeline at the local copy.
+
+Without a kind, lists the available schemas.`,
+		Example: `  ktl config schema stack > stack.schema.json
+  ktl config schema release --modeline
+  ktl config schema --output-dir .ktl/schemas`,
+		Args:          cobra.MaximumNArgs(1),
+		ValidArgs:     configschema.Names(),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			kinds := configschema.Kinds()
+			if len(args) == 1 {
+				kind, ok := configschema.Lookup(args[0])
+				if !ok {
+					return fmt.Errorf("unknown config kind %q (want one of %s)", args[0], strings.Join(configschema.Names(), ", "))
+				}
+				kinds = []configschema.Kind{kind}
+			}
+			switch {
+			case modeline:
+				if len(args) == 0 {
+					return fmt.Errorf("--modeline requires a kind")
+				}
+				_, err := fmt.Fprintln(out, kinds[0].Modeline())
+				return err
+			case outputDir != "":
+				if err := os.MkdirAll(outputDir, 0o755); err != nil {
+					return err
+				}
+				for _, kind := range kinds {
+					raw, err := configschema.Schema(kind.Name)
+					if err != nil {
+						return err
+					}
+					path := filepath.Join(outputDir, kind.FileName())
+					if err := os.WriteFile(path, raw, 0o644); err != nil {
+						return err
+					}
+					fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s\n", path)
+				}
+				return nil
+			case len(args) == 1:
+				raw, err := configschema.Schema(kinds[0].Name)
+				if err != nil {
+					return err
+				}
+				_, err = out.Write(raw)
+				return err
+			default:
+				tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "KIND\tFILES\tURL")
+				for _, kind := range kinds {
+					fmt.Fprintf(tw, "%s\t%s\t%s\n", kind.Name, strings.Join(kind.Files, ", "), kind.URL())
+				}
+				return tw.Flush()
+			}
+		},
+	}
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Write <kind>.schema.json files to this directory (all kinds unless one is given)")
+	cmd.Flags().BoolVar(&modeline, "modeline", false, "Print the yaml-language-server comment that selects the schema")
+	decorateCommandHelp(cmd, "Schema Flags")
+	return cmd
+}
@@ -18,8 +18,15 @@ type confirmMode string
 const (
 	confirmModeYes   confirmMode = "yes"
 	confirmModeExact confirmMode = "exact"
+	// confirmModeYesOrEdit accepts yes, or e to edit the values (errEditValues).
+	confirmModeYesOrEdit confirmMode = "yes-or-edit"
+	// confirmModeEdit only accepts e to edit the values (errEditValues).
+	confirmModeEdit confirmMode = "edit"
 )
 
+// errEditValues is returned by confirmAction when the user asked to edit the values instead.
+var errEditValues = errors.New("edit values")
+
 func confirmAction(ctx context.Context, in io.Reader, out io.Writer, dec approvalDecision, prompt string, mode confirmMode, expected string) error {
 	if out == nil {
 		return errors.New("confirmation output is nil")
@@ -35,7 +42,21 @@ func confirmAction(ctx context.Context, in io.Reader, out io.Writer, dec approva
 	if prompt == "" {
 		prompt = "Confirm:"
 	}
-	fmt.Fprint(out, prompt+" ")
+	if dec.Plain {
+		fmt.Fprintln(out, prompt)
+		switch mode {
+		case confirmModeExact:
+			fmt.Fprintf(out, "Type %s and press Enter to continue; any other answer cancels.\n", expected)
+		case confirmModeYesOrEdit:
+			fmt.Fprintln(out, "Type yes and press Enter to continue, or e to edit the values; any other answer cancels.")
+		case confirmModeEdit:
+			fmt.Fprintln(out, "Type e and press Enter to edit the values; any other answer cancels.")
+		default:
+			fmt.Fprintln(out, "Type yes and press Enter to continue; any other answer cancels.")
+		}
+	} else {
+		fmt.Fprint(out, prompt+" ")
+	}
 
 	closeInputOnCancel := func() {
 		rc, ok := in.(io.ReadCloser)
@@ -76,12 +97,35 @@ func confirmAction(ctx context.Context, in io.Reader, out io.Writer, dec approva
 		return err
 	}
 	reply := strings.TrimSpace(line)
+	err = checkConfirmReply(mode, reply, expected)
+	if dec.Plain {
+		switch {
+		case err == nil:
+			fmt.Fprintln(out, "Confirmed.")
+		case errors.Is(err, errEditValues):
+			fmt.Fprintln(out, "Editing values.")
+		default:
+			fmt.Fprintln(out, "Cancelled.")
+		}
+	}
+	return err
+}
+
+func checkConfirmReply(mode confirmMode, reply, expected string) error {
 	switch mode {
 	case confirmModeYes:
 		if !strings.EqualFold(reply, "yes") {
 			return errors.New("aborted")
 		}
 		return nil
+	case confirmModeYesOrEdit, confirmModeEdit:
+		if strings.EqualFold(reply, "e") || strings.EqualFold(reply, "edit") {
+			return errEditValues
+		}
+		if mode == confirmModeYesOrEdit && strings.EqualFold(reply, "yes") {
+			return nil
+		}
+		return errors.New("aborted")
 	case confirmModeExact:
 		if strings.TrimSpace(expected) == "" {
 			return errors.New("confirmation token missing")
@@ -6,6 +6,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"strings"
 	"testing"
@@ -86,3 +87,37 @@ func TestConfirmActionCanceledReturnsContextCanceled(t *testing.T) {
 		t.Fatalf("timed out waiting for confirmAction to return")
 	}
 }
+
+func TestConfirmActionPlainSpellsOutAnswerAndOutcome(t *testing.T) {
+	out := &bytes.Buffer{}
+	dec := approvalDecision{InteractiveTTY: true, Plain: true}
+	if err := confirmAction(context.Background(), strings.NewReader("monitoring\n"), out, dec, "Type release:", confirmModeExact, "monitoring"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	want := "Type release:\nType monitoring and press Enter to continue; any other answer cancels.\nConfirmed.\n"
+	if out.String() != want {
+		t.Fatalf("unexpected plain prompt:\n%q", out.String())
+	}
+
+	out.Reset()
+	if err := confirmAction(context.Background(), strings.NewReader("no\n"), out, dec, "Confirm?", confirmModeYes, ""); err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.HasSuffix(out.String(), "Cancelled.\n") {
+		t.Fatalf("expected cancellation notice, got %q", out.String())
+	}
+}
+
+func TestConfirmActionYesOrEditReturnsEdit(t *testing.T) {
+	out := &bytes.Buffer{}
+	dec := approvalDecision{InteractiveTTY: true}
+	if err := confirmAction(context.Background(), strings.NewReader("e\n"), out, dec, "Confirm?", confirmModeYesOrEdit, ""); !errors.Is(err, errEditValues) {
+		t.Fatalf("expected errEditValues, got %v", err)
+	}
+	if err := confirmAction(context.Background(), strings.NewReader("yes\n"), out, dec, "Confirm?", confirmModeYesOrEdit, ""); err != nil {
+		t.Fatalf("expected yes to confirm, got %v", err)
+	}
+	if err := confirmAction(context.Background(), strings.NewReader("yes\n"), out, dec, "Edit?", confirmModeEdit, ""); err == nil || errors.Is(err, errEditValues) {
+		t.Fatalf("expected edit-only mode to abort on yes, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+// File: cmd/ktl/console_mode.go
+// Brief: CLI command wiring and implementation for 'console mode'.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	consoleModeAuto  = "auto"
+	consoleModePlain = "plain"
+)
+
+// plainConsole reports whether --console=plain (or KTL_CONSOLE=plain) is in effect for cmd: live
+// panels print sequential lines instead of repainting in place, spinners are skipped, and
+// confirmation prompts spell out the expected answer.
+func plainConsole(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+	f := cmd.Flag("console")
+	if f == nil {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(f.Value.String()), consoleModePlain)
+}
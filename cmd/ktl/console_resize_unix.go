@@ -0,0 +1,30 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchConsoleResize calls onResize on every SIGWINCH until the returned stop func is called.
+func watchConsoleResize(onResize func()) func() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGWINCH)
+	stopCh := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-sigCh:
+				onResize()
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigCh)
+		close(stopCh)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package main
+
+// watchConsoleResize is a no-op: Windows consoles do not deliver SIGWINCH.
+func watchConsoleResize(onResize func()) func() {
+	return func() {}
+}
@@ -0,0 +1,330 @@
+// File: cmd/ktl/ctx.go
+// Brief: CLI command wiring and implementation for 'ctx' and 'ns'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/kubectx"
+	"github.com/kubekattle/ktl/internal/ui"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type switcherOptions struct {
+	list  bool
+	env   bool
+	shell string
+}
+
+func (o *switcherOptions) bind(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.list, "list", false, "List candidates (recently used first) instead of switching")
+	cmd.Flags().BoolVar(&o.env, "env", false, "Print a shell snippet that exports KTL_CONTEXT/KTL_NAMESPACE instead of editing the kubeconfig")
+	cmd.Flags().StringVar(&o.shell, "shell", "posix", "Shell syntax for --env: posix, fish, or powershell")
+}
+
+func newCtxCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
+	var opts switcherOptions
+	cmd := &cobra.Command{
+		Use:   "ctx [NAME|-]",
+		Short: "Switch kubeconfig context with a fuzzy picker",
+		Long: `Switch the current kubeconfig context. Without arguments an interactive fuzzy picker opens (recently
+used contexts first). NAME may be any fuzzy fragment; "-" switches back to the previous context.
+
+With --env, the kubeconfig is left untouched and a snippet exporting KTL_CONTEXT is printed instead, so
+only ktl commands in the current shell follow the switch:
+
+  eval "$(ktl ctx --env prod)"`,
+		Example: `  # Pick a context interactively
+  ktl ctx
+
+  # Jump to the context matching "prod-eu"
+  ktl ctx prod-eu
+
+  # Go back to the previous context
+  ktl ctx -
+
+  # Switch ktl (not kubectl) in this shell only
+  eval "$(ktl ctx --env staging)"`,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kc, err := kubectx.LoadKubeconfig(derefString(kubeconfig))
+			if err != nil {
+				return err
+			}
+			contexts := kc.Contexts()
+			if len(contexts) == 0 {
+				return fmt.Errorf("no contexts found in kubeconfig")
+			}
+			current := kc.CurrentContext()
+			if opts.env {
+				if kctx := strings.TrimSpace(derefString(kubeContext)); kctx != "" {
+					current = kctx
+				}
+			}
+			historyPath, hist := loadSwitchHistory(cmd.ErrOrStderr())
+			if opts.list {
+				writeSwitchList(cmd.OutOrStdout(), kubectx.Rank("", contexts, hist.Contexts), current)
+				return nil
+			}
+			query := ""
+			if len(args) == 1 {
+				query = strings.TrimSpace(args[0])
+			}
+			var target string
+			if query == "-" {
+				if target = hist.PreviousContext(current); target == "" {
+					return fmt.Errorf("no previous context recorded")
+				}
+			} else {
+				target, err = pickSwitchTarget(cmd, "context>", query, contexts, hist.Contexts, current)
+				if err != nil {
+					return err
+				}
+			}
+			if target == "" {
+				return nil
+			}
+
+			if opts.env {
+				snippet, err := kubectx.ShellSnippet(opts.shell, target, "")
+				if err != nil {
+					return err
+				}
+				fmt.Fprint(cmd.OutOrStdout(), snippet)
+			} else {
+				if err := kc.UseContext(target); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Switched to context %q.\n", target)
+				if env := strings.TrimSpace(os.Getenv("KTL_CONTEXT")); env != "" && env != target {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: KTL_CONTEXT=%s still overrides the context for ktl in this shell.\n", env)
+				}
+			}
+			if target != current {
+				hist.RecordContext(current)
+			}
+			hist.RecordContext(target)
+			saveSwitchHistory(cmd.ErrOrStderr(), historyPath, hist)
+			return nil
+		},
+	}
+	opts.bind(cmd)
+	decorateCommandHelp(cmd, "Context Flags")
+	return cmd
+}
+
+func newNsCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
+	var opts switcherOptions
+	cmd := &cobra.Command{
+		Use:   "ns [NAME|-]",
+		Short: "Switch the default namespace with a fuzzy picker",
+		Long: `Switch the default namespace of the active context. Without arguments an interactive fuzzy picker
+lists the cluster's namespaces (recently used first). NAME may be any fuzzy fragment; "-" switches back
+to the previous namespace.
+
+With --env, the kubeconfig is left untouched and a snippet exporting KTL_NAMESPACE is printed instead:
+
+  eval "$(ktl ns --env payments)"`,
+		Example: `  # Pick a namespace interactively
+  ktl ns
+
+  # Switch to the namespace matching "pay"
+  ktl ns pay
+
+  # Point ktl commands in this shell at kube-system
+  eval "$(ktl ns --env kube-system)"`,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kc, err := kubectx.LoadKubeconfig(derefString(kubeconfig))
+			if err != nil {
+				return err
+			}
+			contextName := strings.TrimSpace(derefString(kubeContext))
+			if contextName == "" {
+				contextName = kc.CurrentContext()
+			}
+			if contextName == "" {
+				return fmt.Errorf("no current context; run 'ktl ctx' first")
+			}
+			current := kc.Namespace(contextName)
+			if opts.env {
+				if env := strings.TrimSpace(os.Getenv("KTL_NAMESPACE")); env != "" {
+					current = env
+				}
+			}
+			if current == "" {
+				current = "default"
+			}
+			historyPath, hist := loadSwitchHistory(cmd.ErrOrStderr())
+			recent := hist.Namespaces[contextName]
+			namespaces, err := listNamespaceNames(cmd.Context(), derefString(kubeconfig), contextName)
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: list namespaces: %v (showing recently used namespaces only)\n", err)
+				namespaces = mergeNames(recent, []string{current})
+			}
+			if opts.list {
+				writeSwitchList(cmd.OutOrStdout(), kubectx.Rank("", namespaces, recent), current)
+				return nil
+			}
+			query := ""
+			if len(args) == 1 {
+				query = strings.TrimSpace(args[0])
+			}
+			var target string
+			if query == "-" {
+				if target = hist.PreviousNamespace(contextName, current); target == "" {
+					return fmt.Errorf("no previous namespace recorded for context %q", contextName)
+				}
+			} else {
+				target, err = pickSwitchTarget(cmd, "namespace>", query, namespaces, recent, current)
+				if err != nil {
+					return err
+				}
+			}
+			if target == "" {
+				return nil
+			}
+
+			if opts.env {
+				snippet, err := kubectx.ShellSnippet(opts.shell, "", target)
+				if err != nil {
+					return err
+				}
+				fmt.Fprint(cmd.OutOrStdout(), snippet)
+			} else {
+				if err := kc.SetNamespace(contextName, target); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Context %q now defaults to namespace %q.\n", contextName, target)
+				if env := strings.TrimSpace(os.Getenv("KTL_NAMESPACE")); env != "" && env != target {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: KTL_NAMESPACE=%s still overrides the namespace for ktl in this shell.\n", env)
+				}
+			}
+			if target != current {
+				hist.RecordNamespace(contextName, current)
+			}
+			hist.RecordNamespace(contextName, target)
+			saveSwitchHistory(cmd.ErrOrStderr(), historyPath, hist)
+			return nil
+		},
+	}
+	opts.bind(cmd)
+	decorateCommandHelp(cmd, "Namespace Flags")
+	return cmd
+}
+
+// pickSwitchTarget resolves query against candidates: an exact or single fuzzy match is used
+// directly, otherwise the interactive picker opens (on a terminal) pre-filled with query.
+func pickSwitchTarget(cmd *cobra.Command, prompt, query string, candidates, recent []string, current string) (string, error) {
+	for _, c := range candidates {
+		if query != "" && c == query {
+			return c, nil
+		}
+	}
+	matches := kubectx.Rank(query, candidates, recent)
+	if query != "" && len(matches) == 1 {
+		return matches[0], nil
+	}
+	if query != "" && len(matches) == 0 {
+		return "", fmt.Errorf("no match for %q", query)
+	}
+	in, ok := cmd.InOrStdin().(*os.File)
+	if !ok || !ui.IsTerminalReader(in) || !ui.IsTerminalWriter(cmd.ErrOrStderr()) {
+		if query == "" {
+			writeSwitchList(cmd.OutOrStdout(), matches, current)
+			return "", nil
+		}
+		return "", fmt.Errorf("%q matches %d candidates: %s", query, len(matches), strings.Join(matches, ", "))
+	}
+	picker := &ui.Picker{
+		Prompt:  prompt,
+		Query:   query,
+		Current: current,
+		Filter: func(q string) []string {
+			return kubectx.Rank(q, candidates, recent)
+		},
+	}
+	return picker.Run(in, cmd.ErrOrStderr())
+}
+
+func writeSwitchList(out io.Writer, names []string, current string) {
+	for _, name := range names {
+		marker := "  "
+		if name == current {
+			marker = "* "
+		}
+		fmt.Fprintf(out, "%s%s\n", marker, name)
+	}
+}
+
+func loadSwitchHistory(errOut io.Writer) (string, *kubectx.History) {
+	path, err := kubectx.DefaultHistoryPath()
+	if err != nil {
+		return "", &kubectx.History{}
+	}
+	hist, err := kubectx.LoadHistory(path)
+	if err != nil {
+		fmt.Fprintf(errOut, "Warning: %v\n", err)
+		return path, &kubectx.History{}
+	}
+	return path, hist
+}
+
+func saveSwitchHistory(errOut io.Writer, path string, hist *kubectx.History) {
+	if path == "" {
+		return
+	}
+	if err := hist.Save(path); err != nil {
+		fmt.Fprintf(errOut, "Warning: save context history: %v\n", err)
+	}
+}
+
+func listNamespaceNames(ctx context.Context, kubeconfig, contextName string) ([]string, error) {
+	client, err := kube.New(ctx, kubeconfig, contextName)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	list, err := client.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		names = append(names, ns.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func mergeNames(lists ...[]string) []string {
+	seen := map[string]struct{}{}
+	var out []string
+	for _, list := range lists {
+		for _, name := range list {
+			if _, ok := seen[name]; ok || name == "" {
+				continue
+			}
+			seen[name] = struct{}{}
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,291 @@
+// File: cmd/ktl/debug.go
+// Brief: CLI command wiring and implementation for 'debug'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/kubekattle/ktl/internal/kube"
+)
+
+type debugOptions struct {
+	release    string
+	workload   string
+	namespace  string
+	selector   string
+	target     string
+	image      string
+	command    []string
+	pullPolicy string
+	copyPod    bool
+	keepCopy   bool
+	timeout    time.Duration
+}
+
+func newDebugCommand(kubeconfig, kubeContext *string) *cobra.Command {
+	opts := debugOptions{
+		image:   kube.DefaultDebugImage,
+		timeout: 2 * time.Minute,
+	}
+	cmd := &cobra.Command{
+		Use:   "debug [POD_QUERY] [-- COMMAND...]",
+		Short: "Attach an ephemeral debug container to a pod",
+		Long: `Inject an ephemeral debug container into a pod and attach an interactive shell.
+
+The debug container targets the app container's process namespace (when the runtime supports it),
+so distroless images can be inspected with a full toolbox. Use --copy to debug a disposable copy
+of the pod with a shared process namespace instead; the copy is deleted when the session ends.`,
+		Example: `  # Debug the first running pod of release foo
+  ktl debug --release foo -n prod
+
+  # Pick a pod by regex and use a richer toolbox image
+  ktl debug 'checkout-.*' -n prod --image nicolaka/netshoot
+
+  # Debug a throwaway copy of the pod and run a one-off command
+  ktl debug --release foo --copy -- ps aux`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := ""
+			dash := cmd.ArgsLenAtDash()
+			switch {
+			case dash == -1 && len(args) > 1:
+				return fmt.Errorf("accepts at most one POD_QUERY argument (use -- before a command)")
+			case dash == -1 && len(args) == 1:
+				query = args[0]
+			case dash > 1:
+				return fmt.Errorf("accepts at most one POD_QUERY argument before --")
+			case dash == 1:
+				query = args[0]
+				opts.command = args[1:]
+			case dash == 0:
+				opts.command = args
+			}
+			return runDebug(cmd, kubeconfig, kubeContext, query, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.release, "release", "", "Select pods of this Helm release (matches app.kubernetes.io/instance)")
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "Namespace of the target pod (defaults to the kubeconfig namespace)")
+	cmd.Flags().StringVarP(&opts.selector, "selector", "l", "", "Additional label selector for candidate pods")
+	cmd.Flags().StringVar(&opts.workload, "for", "", "Select pods of a workload (e.g. deployment/checkout, statefulset/db)")
+	cmd.Flags().StringVar(&opts.target, "target", "", "Container whose process namespace to join (defaults to the first container)")
+	cmd.Flags().StringVar(&opts.image, "image", opts.image, "Debug container image (Windows pods default to "+kube.DefaultWindowsDebugImage+")")
+	cmd.Flags().Var(newEnumStringValue(&opts.pullPolicy, "Always", "IfNotPresent", "Never"), "image-pull-policy", "Image pull policy for the debug container: Always, IfNotPresent, or Never")
+	cmd.Flags().BoolVar(&opts.copyPod, "copy", false, "Debug a copy of the pod with a shared process namespace (deleted on exit)")
+	cmd.Flags().BoolVar(&opts.keepCopy, "keep", false, "Keep the pod copy after the session ends (with --copy)")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", opts.timeout, "How long to wait for the debug container to start")
+	decorateCommandHelp(cmd, "Debug Flags")
+	return cmd
+}
+
+func runDebug(cmd *cobra.Command, kubeconfig, kubeContext *string, query string, opts debugOptions) error {
+	if strings.TrimSpace(opts.release) == "" && strings.TrimSpace(opts.selector) == "" && strings.TrimSpace(opts.workload) == "" && strings.TrimSpace(query) == "" {
+		return errors.New("select a pod with POD_QUERY, --release, --selector, or --for")
+	}
+	if opts.keepCopy && !opts.copyPod {
+		return errors.New("--keep requires --copy")
+	}
+	ctx := cmd.Context()
+	var kc, kctx string
+	if kubeconfig != nil {
+		kc = *kubeconfig
+	}
+	if kubeContext != nil {
+		kctx = *kubeContext
+	}
+	client, err := kube.New(ctx, kc, kctx)
+	if err != nil {
+		return err
+	}
+	namespace := strings.TrimSpace(opts.namespace)
+	if namespace == "" {
+		namespace = client.Namespace
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	errOut := cmd.ErrOrStderr()
+
+	selector, err := podSelectorFor(ctx, client.Clientset, namespace, opts.release, opts.selector, opts.workload)
+	if err != nil {
+		return err
+	}
+	pod, matched, err := selectDebugPod(ctx, client.Clientset, namespace, selector, query)
+	if err != nil {
+		return err
+	}
+	if matched > 1 {
+		fmt.Fprintf(errOut, "%d pods matched; debugging %s\n", matched, pod.Name)
+	}
+	target := strings.TrimSpace(opts.target)
+	if target == "" && len(pod.Spec.Containers) > 0 {
+		target = pod.Spec.Containers[0].Name
+	}
+	podOS := kube.ResolvePodOS(ctx, client.Clientset, pod)
+	image := opts.image
+	if kube.IsWindows(podOS) {
+		if !cmd.Flags().Changed("image") {
+			image = kube.DefaultWindowsDebugImage
+		}
+		fmt.Fprintf(errOut, "%s runs on Windows: using %s with cmd.exe; Windows containers cannot share the target's process namespace\n", pod.Name, image)
+		target = "-"
+	}
+	name := kube.NewDebugContainerName()
+	ec := kube.BuildEphemeralContainer(name, kube.DebugContainerOptions{
+		Image:           image,
+		Command:         opts.command,
+		TargetContainer: target,
+		ImagePullPolicy: corev1.PullPolicy(opts.pullPolicy),
+		OS:              podOS,
+	})
+
+	podName := pod.Name
+	if opts.copyPod {
+		ec.TargetContainerName = ""
+		copyPod := kube.BuildDebugPodCopy(pod, pod.Name+"-"+name, podOS, ec)
+		created, err := client.Clientset.CoreV1().Pods(namespace).Create(ctx, copyPod, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create debug pod copy: %w", err)
+		}
+		podName = created.Name
+		fmt.Fprintf(errOut, "Created debug copy %s/%s of %s\n", namespace, podName, pod.Name)
+		if !opts.keepCopy {
+			defer func() {
+				// Use a fresh context so cleanup still runs after Ctrl+C cancelled ctx.
+				cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				defer cancel()
+				if err := client.Clientset.CoreV1().Pods(namespace).Delete(cleanupCtx, podName, metav1.DeleteOptions{}); err != nil {
+					fmt.Fprintf(errOut, "warning: delete debug pod %s: %v\n", podName, err)
+					return
+				}
+				fmt.Fprintf(errOut, "Deleted debug pod %s/%s\n", namespace, podName)
+			}()
+		}
+	} else {
+		if err := client.AddEphemeralContainer(ctx, namespace, podName, ec); err != nil {
+			return err
+		}
+		fmt.Fprintf(errOut, "Injected debug container %s (image %s, target %s) into %s/%s\n", name, ec.Image, target, namespace, podName)
+	}
+
+	fmt.Fprintf(errOut, "Waiting for %s to start...\n", name)
+	if err := client.WaitForContainerRunning(ctx, namespace, podName, name, opts.timeout); err != nil {
+		return err
+	}
+	return attachDebugSession(ctx, client, namespace, podName, name, cmd.InOrStdin(), cmd.OutOrStdout(), errOut)
+}
+
+// selectDebugPod returns the first running pod (by name) matching selector and the optional name regex.
+func selectDebugPod(ctx context.Context, client kubernetes.Interface, namespace, selector, query string) (*corev1.Pod, int, error) {
+	var re *regexp.Regexp
+	if q := strings.TrimSpace(query); q != "" {
+		compiled, err := regexp.Compile(q)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid pod query %q: %w", q, err)
+		}
+		re = compiled
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, 0, fmt.Errorf("list pods: %w", err)
+	}
+	var candidates []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if re != nil && !re.MatchString(pod.Name) {
+			continue
+		}
+		candidates = append(candidates, pod)
+	}
+	if len(candidates) == 0 {
+		return nil, 0, fmt.Errorf("no running pods in %s match the selection", namespace)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+	return &candidates[0], len(candidates), nil
+}
+
+func attachDebugSession(ctx context.Context, client *kube.Client, namespace, pod, container string, in io.Reader, out, errOut io.Writer) error {
+	stdinFile, isFile := in.(*os.File)
+	tty := isFile && term.IsTerminal(int(stdinFile.Fd()))
+	var sizes remotecommand.TerminalSizeQueue
+	if tty {
+		fd := int(stdinFile.Fd())
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("enable raw terminal: %w", err)
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+		queue, stop := startTerminalSizeQueue(fd)
+		defer stop()
+		sizes = queue
+		fmt.Fprint(errOut, "Attached; exit the shell to end the session.\r\n")
+	}
+	err := client.Attach(ctx, namespace, pod, container, in, out, errOut, tty, sizes)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// terminalSizeQueue feeds terminal dimensions to remotecommand.
+type terminalSizeQueue struct {
+	fd     int
+	ch     chan remotecommand.TerminalSize
+	once   sync.Once
+	closed chan struct{}
+}
+
+func newTerminalSizeQueue(fd int) *terminalSizeQueue {
+	return &terminalSizeQueue{fd: fd, ch: make(chan remotecommand.TerminalSize, 1), closed: make(chan struct{})}
+}
+
+func (q *terminalSizeQueue) push() {
+	width, height, err := term.GetSize(q.fd)
+	if err != nil || width <= 0 || height <= 0 {
+		return
+	}
+	size := remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+	select {
+	case <-q.ch:
+	default:
+	}
+	select {
+	case q.ch <- size:
+	case <-q.closed:
+	default:
+	}
+}
+
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-q.ch:
+		return &size
+	case <-q.closed:
+		return nil
+	}
+}
+
+func (q *terminalSizeQueue) close() {
+	q.once.Do(func() { close(q.closed) })
+}
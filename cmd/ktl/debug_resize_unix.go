@@ -0,0 +1,36 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// startTerminalSizeQueue reports the current terminal size and re-sends it on SIGWINCH.
+func startTerminalSizeQueue(fd int) (remotecommand.TerminalSizeQueue, func()) {
+	q := newTerminalSizeQueue(fd)
+	q.push()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGWINCH)
+	stopCh := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-sigCh:
+				q.push()
+			}
+		}
+	}()
+	return q, func() {
+		close(stopCh)
+		signal.Stop(sigCh)
+		q.close()
+	}
+}
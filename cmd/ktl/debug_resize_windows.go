@@ -0,0 +1,12 @@
+//go:build windows
+
+package main
+
+import "k8s.io/client-go/tools/remotecommand"
+
+// startTerminalSizeQueue reports the initial terminal size; Windows consoles do not deliver SIGWINCH.
+func startTerminalSizeQueue(fd int) (remotecommand.TerminalSizeQueue, func()) {
+	q := newTerminalSizeQueue(fd)
+	q.push()
+	return q, q.close
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func debugTestPod(name, release string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "prod",
+			Labels:    map[string]string{"app.kubernetes.io/instance": release},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   "node-a",
+			Containers: []corev1.Container{{Name: "app", Image: "gcr.io/distroless/static", ReadinessProbe: &corev1.Probe{}}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestSelectDebugPod(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		debugTestPod("api-b", "foo", corev1.PodRunning),
+		debugTestPod("api-a", "foo", corev1.PodRunning),
+		debugTestPod("api-pending", "foo", corev1.PodPending),
+		debugTestPod("worker-a", "foo", corev1.PodRunning),
+		debugTestPod("api-other", "bar", corev1.PodRunning),
+	)
+	ctx := context.Background()
+
+	pod, matched, err := selectDebugPod(ctx, client, "prod", syncSelector("foo", ""), "^api-")
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if pod.Name != "api-a" || matched != 2 {
+		t.Fatalf("got pod %s (matched %d), want api-a (matched 2)", pod.Name, matched)
+	}
+
+	if _, _, err := selectDebugPod(ctx, client, "prod", syncSelector("missing", ""), ""); err == nil {
+		t.Fatalf("expected error when nothing matches")
+	}
+	if _, _, err := selectDebugPod(ctx, client, "prod", "", "("); err == nil {
+		t.Fatalf("expected invalid regex error")
+	}
+}
+
+func TestBuildDebugPodCopySharesProcessNamespace(t *testing.T) {
+	src := debugTestPod("api-a", "foo", corev1.PodRunning)
+	ec := kube.BuildEphemeralContainer("ktl-debug-abcde", kube.DebugContainerOptions{Image: "busybox"})
+	copyPod := kube.BuildDebugPodCopy(src, "api-a-ktl-debug-abcde", kube.OSLinux, ec)
+
+	if copyPod.Spec.ShareProcessNamespace == nil || !*copyPod.Spec.ShareProcessNamespace {
+		t.Fatalf("expected shared process namespace")
+	}
+	if copyPod.Spec.NodeName != "" {
+		t.Fatalf("expected node binding to be cleared")
+	}
+	if len(copyPod.Spec.Containers) != 2 || copyPod.Spec.Containers[1].Name != "ktl-debug-abcde" || !copyPod.Spec.Containers[1].TTY {
+		t.Fatalf("unexpected containers: %+v", copyPod.Spec.Containers)
+	}
+	if copyPod.Spec.Containers[0].ReadinessProbe != nil {
+		t.Fatalf("expected probes to be stripped from the copy")
+	}
+	if src.Spec.Containers[0].ReadinessProbe == nil || len(src.Spec.Containers) != 1 {
+		t.Fatalf("source pod must not be mutated")
+	}
+}
+
+func TestDebugContainerForWindowsPod(t *testing.T) {
+	src := debugTestPod("api-a", "foo", corev1.PodRunning)
+	src.Spec.NodeName = "win-node-1"
+	ec := kube.BuildEphemeralContainer("ktl-debug-abcde", kube.DebugContainerOptions{TargetContainer: "app", OS: kube.OSWindows})
+	if ec.Image != kube.DefaultWindowsDebugImage || len(ec.Command) != 1 || ec.Command[0] != "cmd.exe" {
+		t.Fatalf("expected Windows image and cmd.exe, got %s %v", ec.Image, ec.Command)
+	}
+	if ec.TargetContainerName != "" {
+		t.Fatalf("Windows debug containers must not target a process namespace, got %q", ec.TargetContainerName)
+	}
+	copyPod := kube.BuildDebugPodCopy(src, "api-a-ktl-debug-abcde", kube.OSWindows, ec)
+	if copyPod.Spec.ShareProcessNamespace != nil {
+		t.Fatalf("Windows pod copies cannot share a process namespace")
+	}
+	if copyPod.Spec.NodeSelector["kubernetes.io/os"] != "windows" {
+		t.Fatalf("expected copy to be pinned to Windows nodes, got %v", copyPod.Spec.NodeSelector)
+	}
+}
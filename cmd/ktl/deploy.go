@@ -5,17 +5,20 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/kubekattle/ktl/internal/appconfig"
 	"github.com/kubekattle/ktl/internal/capture"
 	"github.com/kubekattle/ktl/internal/caststream"
 	"github.com/kubekattle/ktl/internal/castutil"
@@ -26,16 +29,19 @@ import (
 	"github.com/kubekattle/ktl/internal/tailer"
 	"github.com/kubekattle/ktl/internal/telemetry"
 	"github.com/kubekattle/ktl/internal/ui"
+	"github.com/kubekattle/ktl/internal/valuesource"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"go.uber.org/zap/zapcore"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/cli"
 	helmkube "helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/postrender"
 	"helm.sh/helm/v3/pkg/release"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -51,9 +57,12 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 	var releaseName string
 	var version string
 	var valuesFiles []string
+	var postRender postRenderFlags
 	var setValues []string
 	var setStringValues []string
 	var setFileValues []string
+	var setJSONValues []string
+	var setLiteralValues []string
 	var secretProvider string
 	var secretConfig string
 	wait := true
@@ -70,9 +79,29 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 	var planServer bool
 	var capturePath string
 	var captureTags []string
+	var captureValues bool
 	var driftGuard bool
 	var driftGuardMode string
+	var resolveLiveConflicts bool
+	var conflictValuesOut string
+	var editedValuesOut string
+	var approvalPolicy string
+	var approvalToken string
+	var retryAttempts int
+	var retryBackoff time.Duration
+	var overrideWindow bool
+	var overrideReason string
 	var requireVerified string
+	var noGitMetadata bool
+	var trackerMode string
+	var probeReachability bool
+	var requireReachable bool
+	var runChartTestsAfter bool
+	chartTestsTimeout := 5 * time.Minute
+	var fromCapture string
+	var fromCaptureSession string
+	var tenantsFilePath string
+	tenantsConcurrency := 4
 	timeout := 5 * time.Minute
 
 	cmd := &cobra.Command{
@@ -83,6 +112,38 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 			if err := validateVerboseLogLevel(cmd, verbose, logLevel); err != nil {
 				return err
 			}
+			if strings.TrimSpace(fromCapture) != "" {
+				for _, name := range []string{"chart", "version", "values", "set", "set-string", "set-file", "set-json", "set-literal"} {
+					if cmd.Flags().Changed(name) {
+						return fmt.Errorf("--%s cannot be combined with --from-capture: the captured inputs are replayed as recorded", name)
+					}
+				}
+			} else {
+				var missing []string
+				for _, name := range []string{"chart", "release"} {
+					// Tenants may name their own releases; loadTenantsFile checks each has one.
+					if name == "release" && strings.TrimSpace(tenantsFilePath) != "" {
+						continue
+					}
+					if !cmd.Flags().Changed(name) {
+						missing = append(missing, name)
+					}
+				}
+				if len(missing) > 0 {
+					return fmt.Errorf(`required flag(s) "%s" not set`, strings.Join(missing, `", "`))
+				}
+			}
+			if strings.TrimSpace(fromCaptureSession) != "" && strings.TrimSpace(fromCapture) == "" {
+				return fmt.Errorf("--from-capture-session requires --from-capture")
+			}
+			if captureValues && strings.TrimSpace(capturePath) == "" {
+				return fmt.Errorf("--capture-values requires --capture")
+			}
+			if strings.TrimSpace(tenantsFilePath) != "" {
+				if err := validateTenantsFlags(cmd, tenantsConcurrency, autoApprove, dryRun, remoteAgent); err != nil {
+					return err
+				}
+			}
 			if remoteAgent != nil && strings.TrimSpace(*remoteAgent) != "" {
 				if watchDuration > 0 {
 					return fmt.Errorf("--watch is not supported with --remote-agent")
@@ -93,19 +154,49 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				if strings.TrimSpace(requireVerified) != "" {
 					return fmt.Errorf("--require-verified is not supported with --remote-agent")
 				}
+				if resolveLiveConflicts {
+					return fmt.Errorf("--resolve-conflicts is not supported with --remote-agent")
+				}
+				if cmd.Flags().Changed("approval-policy") {
+					return fmt.Errorf("--approval-policy is not supported with --remote-agent")
+				}
+				if cmd.Flags().Changed("retry") || cmd.Flags().Changed("retry-backoff") {
+					return fmt.Errorf("--retry/--retry-backoff are not supported with --remote-agent")
+				}
 				if strings.TrimSpace(secretProvider) != "" || strings.TrimSpace(secretConfig) != "" {
 					return fmt.Errorf("--secret-provider/--secret-config are not supported with --remote-agent")
 				}
 			}
+			if slices.Contains(valuesFiles, deploy.ValuesStdin) && !autoApprove && !dryRun {
+				return fmt.Errorf("values from stdin (-f -) require --yes or --dry-run: stdin is not available for the confirmation prompt")
+			}
 			if watchDuration > 0 && dryRun {
 				return fmt.Errorf("--watch cannot be combined with --dry-run")
 			}
 			if err := validateNonInteractive(cmd, nonInteractive, autoApprove); err != nil {
 				return fmt.Errorf("%w (or use --dry-run)", err)
 			}
+			if resolveLiveConflicts && (autoApprove || nonInteractive) {
+				return fmt.Errorf("--resolve-conflicts prompts for every conflict and cannot be combined with --yes or --non-interactive")
+			}
+			if _, err := deploy.ParseApprovalPolicy(approvalPolicy); err != nil {
+				return err
+			}
+			if err := validateWindowOverride(overrideWindow, overrideReason); err != nil {
+				return err
+			}
 			if timeout <= 0 {
 				return fmt.Errorf("--timeout must be > 0")
 			}
+			if retryAttempts < 1 {
+				return fmt.Errorf("--retry must be >= 1")
+			}
+			if retryBackoff < 0 {
+				return fmt.Errorf("--retry-backoff must be >= 0")
+			}
+			if _, err := deploy.ParseTrackerMode(trackerMode); err != nil {
+				return err
+			}
 			return nil
 		},
 		SilenceUsage:  true,
@@ -123,7 +214,91 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				console            *ui.DeployConsole
 			)
 			ctx := cmd.Context()
+			if path := strings.TrimSpace(tenantsFilePath); path != "" {
+				tenants, err := loadTenantsFile(path, releaseName)
+				if err != nil {
+					return err
+				}
+				var stdinValues string
+				if slices.Contains(valuesFiles, deploy.ValuesStdin) {
+					// Read the shared -f - once; every tenant gets the same file.
+					files, cleanupValues, err := resolveValuesFlag(cmd, nil, []string{deploy.ValuesStdin}, nil)
+					if err != nil {
+						return err
+					}
+					defer cleanupValues()
+					stdinValues = files[0]
+				}
+				shared := tenantApplyArgs(cmd.Flags(), func(name string) bool { return cmd.LocalFlags().Lookup(name) != nil }, stdinValues)
+				fmt.Fprintf(errOut, "Applying %s to %d tenants (%d at a time)\n", chart, len(tenants), min(tenantsConcurrency, len(tenants)))
+				_, err = runTenantApplies(ctx, errOut, tenants, tenantsConcurrency, func(ctx context.Context, t tenantSpec, out io.Writer) error {
+					tenantNamespace := t.Namespace
+					child := newDeployApplyCommand(&tenantNamespace, kubeconfig, kubeContext, logLevel, remoteAgent, helpSection)
+					child.SetArgs(t.tenantArgs(shared))
+					child.SetIn(strings.NewReader(""))
+					child.SetOut(out)
+					child.SetErr(out)
+					return child.ExecuteContext(ctx)
+				})
+				return err
+			}
+			var replay *applyCaptureInputs
+			if path := strings.TrimSpace(fromCapture); path != "" {
+				in, err := loadApplyCaptureInputs(ctx, path, fromCaptureSession)
+				if err != nil {
+					return err
+				}
+				valuesDir, err := os.MkdirTemp("", "ktl-replay-values-*")
+				if err != nil {
+					return err
+				}
+				defer os.RemoveAll(valuesDir)
+				files, err := in.materializeValues(valuesDir)
+				if err != nil {
+					return fmt.Errorf("replay capture session %s: %w", in.SessionID, err)
+				}
+				replay = in
+				chart, version = in.Chart, in.Version
+				valuesFiles = files
+				setValues, setStringValues, setFileValues = in.SetValues, in.SetStringValues, in.SetFileValues
+				setJSONValues, setLiteralValues = in.SetJSONValues, in.SetLiteralValues
+				if !cmd.Flags().Changed("release") {
+					releaseName = in.Release
+				}
+				if namespace != nil && strings.TrimSpace(*namespace) == "" {
+					*namespace = in.Namespace
+				}
+				fmt.Fprintf(errOut, "Replaying capture session %s: chart %s", in.SessionID, in.Chart)
+				if in.Version != "" {
+					fmt.Fprintf(errOut, "@%s", in.Version)
+				}
+				fmt.Fprintf(errOut, ", release %s, %d values file(s)\n", releaseName, len(files))
+			}
 			if remoteAgent != nil && strings.TrimSpace(*remoteAgent) != "" {
+				if len(setJSONValues) > 0 || len(setLiteralValues) > 0 {
+					return fmt.Errorf("--set-json and --set-literal are not supported with --remote-agent")
+				}
+				// The agent applies without ktl's local gates: check the deploy windows here, and refuse
+				// outright under a repo approval policy since there is no local plan to approve.
+				if !dryRun {
+					deployCfg, err := loadDeployConfig(ctx)
+					if err != nil {
+						return err
+					}
+					target := kubeconfigWindowTarget(derefString(kubeconfig), derefString(kubeContext), derefString(namespace))
+					if err := enforceDeployWindows(cmd, errOut, deployCfg.Windows, []windowTarget{target}, overrideWindow, overrideReason, time.Now()); err != nil {
+						return err
+					}
+					if err := refuseUnderRepoApprovalPolicy(ctx, "ktl apply --remote-agent"); err != nil {
+						return err
+					}
+				}
+				resolvedValues, cleanupValues, err := resolveValuesFlag(cmd, nil, valuesFiles, nil)
+				if err != nil {
+					return err
+				}
+				defer cleanupValues()
+				valuesFiles = resolvedValues
 				return runRemoteDeployApply(cmd, remoteDeployApplyArgs{
 					Chart:           chart,
 					Release:         releaseName,
@@ -198,7 +373,11 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				}
 				fmt.Fprintf(errOut, "[helm] "+format+"\n", v...)
 			}
-			if err := actionCfg.Init(settings.RESTClientGetter(), resolvedNamespace, os.Getenv("HELM_DRIVER"), logFunc); err != nil {
+			// Share discovery with kubeClient and cache the chart download, values, and preview
+			// render so the preview, tracker, and real upgrade don't repeat that work.
+			restGetter := kubeClient.HelmRESTClientGetter(settings.RESTClientGetter())
+			runCache := deploy.NewRunCache()
+			if err := actionCfg.Init(restGetter, resolvedNamespace, os.Getenv("HELM_DRIVER"), logFunc); err != nil {
 				return fmt.Errorf("init helm action config: %w", err)
 			}
 
@@ -219,127 +398,209 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 					secretAuditSink(report)
 				}
 			}
-			secretOptions := &deploy.SecretOptions{Resolver: secretResolver, AuditSink: auditSink}
+			valueSources, err := valuesource.LoadFromApp(ctx, chart)
+			if err != nil {
+				return err
+			}
+			secretOptions := &deploy.SecretOptions{Resolver: secretResolver, AuditSink: auditSink, ValueSources: valueSources}
+
+			resolvedValues, cleanupValues, err := resolveValuesFlag(cmd, settings, valuesFiles, secretOptions)
+			if err != nil {
+				return err
+			}
+			defer cleanupValues()
+			valuesFiles = resolvedValues
+			postRenderer, err := loadPostRenderer(ctx, settings, postRender)
+			if err != nil {
+				return err
+			}
 
 			if driftGuard {
 				driftOpts := deploy.InstallOptions{
-					Chart:           chart,
-					Version:         version,
-					ReleaseName:     releaseName,
-					Namespace:       resolvedNamespace,
-					ValuesFiles:     valuesFiles,
-					SetValues:       setValues,
-					SetStringValues: setStringValues,
-					SetFileValues:   setFileValues,
-					Secrets:         secretOptions,
-					Timeout:         timeout,
-					Wait:            false,
-					Atomic:          false,
-					CreateNamespace: createNamespace,
-					DryRun:          true,
-					Diff:            false,
-					UpgradeOnly:     upgrade,
+					Chart:            chart,
+					Version:          version,
+					ReleaseName:      releaseName,
+					Namespace:        resolvedNamespace,
+					ValuesFiles:      valuesFiles,
+					SetValues:        setValues,
+					SetStringValues:  setStringValues,
+					SetFileValues:    setFileValues,
+					SetJSONValues:    setJSONValues,
+					SetLiteralValues: setLiteralValues,
+					Secrets:          secretOptions,
+					Timeout:          timeout,
+					Wait:             false,
+					Atomic:           false,
+					CreateNamespace:  createNamespace,
+					DryRun:           true,
+					Diff:             false,
+					UpgradeOnly:      upgrade,
+					Cache:            runCache,
+					PostRenderer:     postRenderer,
 				}
 				if err := deploy.RunDriftCheck(ctx, actionCfg, settings, kubeClient, driftGuardMode, releaseName, driftOpts); err != nil {
 					return err
 				}
 			}
 
+			if resolveLiveConflicts {
+				if !dec.InteractiveTTY {
+					return fmt.Errorf("--resolve-conflicts requires an interactive terminal")
+				}
+				conflicts, err := findApplyConflicts(ctx, actionCfg, settings, kubeClient, deploy.InstallOptions{
+					Chart:            chart,
+					Version:          version,
+					ReleaseName:      releaseName,
+					Namespace:        resolvedNamespace,
+					ValuesFiles:      valuesFiles,
+					SetValues:        setValues,
+					SetStringValues:  setStringValues,
+					SetFileValues:    setFileValues,
+					SetJSONValues:    setJSONValues,
+					SetLiteralValues: setLiteralValues,
+					Secrets:          secretOptions,
+					Timeout:          timeout,
+					CreateNamespace:  createNamespace,
+					UpgradeOnly:      upgrade,
+					Cache:            runCache,
+					PostRenderer:     postRenderer,
+				})
+				if err != nil {
+					return fmt.Errorf("check live conflicts: %w", err)
+				}
+				if len(conflicts) == 0 {
+					fmt.Fprintln(errOut, "No live edits conflict with the chart.")
+				}
+				resolution, err := resolveConflicts(bufio.NewReader(cmd.InOrStdin()), errOut, conflicts)
+				if err != nil {
+					return err
+				}
+				if err := reportConflictResolution(errOut, resolution, conflictValuesOut); err != nil {
+					return err
+				}
+				setValues = append(setValues, resolution.SetValues...)
+				setStringValues = append(setStringValues, resolution.SetStringValues...)
+			}
+
+			// Two-person rule: --approval-policy (at least deploy.approvalPolicy in .ktl.yaml) requires a
+			// token from a second approver for the exact plan below, even with --yes.
+			policy, _ := deploy.ParseApprovalPolicy(approvalPolicy)
+			var approvers []appconfig.Approver
+			var owner *deploy.ReleaseOwner
+			if !dryRun {
+				deployCfg, err := loadDeployConfig(ctx)
+				if err != nil {
+					return err
+				}
+				repoCfg, err := loadRepoDeployConfig(ctx)
+				if err != nil {
+					return err
+				}
+				approvers = repoCfg.Approvers
+				owner = deploy.MatchReleaseOwner(deployCfg.Owners, releaseName, resolvedNamespace)
+				contextName := derefString(kubeContext)
+				if strings.TrimSpace(contextName) == "" {
+					contextName = currentKubeContext(derefString(kubeconfig))
+				}
+				target := windowTarget{Context: contextName, Namespace: resolvedNamespace}
+				if err := enforceDeployWindows(cmd, errOut, deployCfg.Windows, []windowTarget{target}, overrideWindow, overrideReason, time.Now()); err != nil {
+					return err
+				}
+				// The repo's policy is a floor: --approval-policy can tighten it but not turn it off.
+				repoPolicy, err := deploy.ParseApprovalPolicy(repoCfg.ApprovalPolicy)
+				if err != nil {
+					return fmt.Errorf("deploy.approvalPolicy: %w", err)
+				}
+				policy = deploy.StricterApprovalPolicy(policy, repoPolicy)
+			}
+			token := strings.TrimSpace(approvalToken)
+			if token == "" {
+				token = strings.TrimSpace(os.Getenv(approvalTokenEnv))
+			}
+
 			// Terraform-like safety rail: show a concise plan summary and ask for confirmation
-			// before making any cluster changes (unless --auto-approve or in dry-run mode).
-			if !dryRun && !autoApprove {
+			// before making any cluster changes (unless --auto-approve or in dry-run mode). At the
+			// prompt, or when the values fail schema validation, "e" opens the merged values in
+			// $EDITOR and re-plans with the edited overlay.
+			var valuesEdit *valuesEditSession
+			editValues := func(problem string) error {
+				vals, err := deploy.MergeUserValues(settings, valuesFiles, setValues, setStringValues, setFileValues, setJSONValues, setLiteralValues)
+				if err != nil {
+					return err
+				}
+				if valuesEdit == nil {
+					valuesEdit = newValuesEditSession(cmd.InOrStdin(), errOut, editedValuesOut)
+				}
+				changed, err := valuesEdit.Edit(ctx, vals, problem)
+				if err != nil || !changed {
+					return err
+				}
+				valuesFiles = []string{valuesEdit.path}
+				setValues, setStringValues, setFileValues, setJSONValues, setLiteralValues = nil, nil, nil, nil, nil
+				return nil
+			}
+			for !dryRun && (!autoApprove || policy != deploy.ApprovalPolicyNone) {
 				preview, previewErr := deploy.GeneratePlanPreview(ctx, actionCfg, settings, kubeClient, deploy.InstallOptions{
-					Chart:           chart,
-					Version:         version,
-					ReleaseName:     releaseName,
-					Namespace:       resolvedNamespace,
-					ValuesFiles:     valuesFiles,
-					SetValues:       setValues,
-					SetStringValues: setStringValues,
-					SetFileValues:   setFileValues,
-					Secrets:         secretOptions,
-					Timeout:         timeout,
-					Wait:            false,
-					Atomic:          false,
-					CreateNamespace: createNamespace,
-					DryRun:          true,
-					Diff:            true,
-					UpgradeOnly:     upgrade,
+					Chart:            chart,
+					Version:          version,
+					ReleaseName:      releaseName,
+					Namespace:        resolvedNamespace,
+					ValuesFiles:      valuesFiles,
+					SetValues:        setValues,
+					SetStringValues:  setStringValues,
+					SetFileValues:    setFileValues,
+					SetJSONValues:    setJSONValues,
+					SetLiteralValues: setLiteralValues,
+					Secrets:          secretOptions,
+					Timeout:          timeout,
+					Wait:             false,
+					Atomic:           false,
+					CreateNamespace:  createNamespace,
+					DryRun:           true,
+					Diff:             true,
+					UpgradeOnly:      upgrade,
+					Cache:            runCache,
+					PostRenderer:     postRenderer,
 				}, planServer)
 				if previewErr != nil {
-					return previewErr
-				}
-				if preview != nil && preview.PlanSummary != nil {
-					fmt.Fprintf(errOut, "Plan: %d to add, %d to change, %d to replace, %d to destroy.\n", preview.PlanSummary.Add, preview.PlanSummary.Change, preview.PlanSummary.Replace, preview.PlanSummary.Destroy)
-					if preview.PlanSummary.Hooks.Add > 0 || preview.PlanSummary.Hooks.Change > 0 || preview.PlanSummary.Hooks.Replace > 0 || preview.PlanSummary.Hooks.Destroy > 0 {
-						fmt.Fprintf(errOut, "Hooks: %d to add, %d to change, %d to replace, %d to destroy.\n", preview.PlanSummary.Hooks.Add, preview.PlanSummary.Hooks.Change, preview.PlanSummary.Hooks.Replace, preview.PlanSummary.Hooks.Destroy)
-					}
-					if preview.PlanSummarizeError != "" && shouldLogAtLevel(currentLogLevel, zapcore.WarnLevel) {
-						fmt.Fprintf(errOut, "Warning: unable to fully summarize plan: %s\n", preview.PlanSummarizeError)
+					if autoApprove || !dec.InteractiveTTY || !deploy.IsValuesSchemaError(previewErr) {
+						return previewErr
 					}
-					limit := 12
-					if len(preview.PlanSummary.Changes) > 0 {
-						if len(preview.PlanSummary.Changes) < limit {
-							limit = len(preview.PlanSummary.Changes)
-						}
-						for _, ch := range preview.PlanSummary.Changes[:limit] {
-							prefix := "~"
-							switch ch.Action {
-							case deploy.PlanAdd:
-								prefix = "+"
-							case deploy.PlanDestroy:
-								prefix = "-"
-							case deploy.PlanUpdate:
-								prefix = "~"
-							case deploy.PlanReplace:
-								prefix = "±"
-							}
-							nsLabel := ch.Namespace
-							if nsLabel == "" {
-								nsLabel = "-"
-							}
-							fmt.Fprintf(errOut, "  %s %s/%s (ns: %s)\n", prefix, ch.Kind, ch.Name, nsLabel)
-						}
-						if len(preview.PlanSummary.Changes) > limit {
-							fmt.Fprintf(errOut, "  (and %d more)\n", len(preview.PlanSummary.Changes)-limit)
-						}
+					fmt.Fprintln(errOut, previewErr)
+					if err := confirmAction(cmd.Context(), cmd.InOrStdin(), errOut, dec, "Edit the values and re-plan? Type 'e' to open $EDITOR:", confirmModeEdit, ""); !errors.Is(err, errEditValues) {
+						return previewErr
 					}
-					if len(preview.PlanSummary.Hooks.Changes) > 0 {
-						fmt.Fprintln(errOut, "Hook changes:")
-						limitHooks := 8
-						if len(preview.PlanSummary.Hooks.Changes) < limitHooks {
-							limitHooks = len(preview.PlanSummary.Hooks.Changes)
-						}
-						for _, ch := range preview.PlanSummary.Hooks.Changes[:limitHooks] {
-							prefix := "~"
-							switch ch.Action {
-							case deploy.PlanAdd:
-								prefix = "+"
-							case deploy.PlanDestroy:
-								prefix = "-"
-							case deploy.PlanReplace:
-								prefix = "±"
-							}
-							nsLabel := ch.Namespace
-							if nsLabel == "" {
-								nsLabel = "-"
-							}
-							hookLabel := strings.TrimSpace(ch.Hook)
-							if hookLabel == "" {
-								hookLabel = "hook"
-							}
-							fmt.Fprintf(errOut, "  %s %s/%s (ns: %s, %s)\n", prefix, ch.Kind, ch.Name, nsLabel, hookLabel)
-						}
-						if len(preview.PlanSummary.Hooks.Changes) > limitHooks {
-							fmt.Fprintf(errOut, "  (and %d more)\n", len(preview.PlanSummary.Hooks.Changes)-limitHooks)
-						}
+					if err := editValues(previewErr.Error()); err != nil {
+						return err
 					}
+					continue
 				}
-				if err := confirmAction(cmd.Context(), cmd.InOrStdin(), errOut, dec, "Do you want to perform these actions? Only 'yes' will be accepted:", confirmModeYes, ""); err != nil {
+				if !autoApprove {
+					printPlanPreview(errOut, preview, currentLogLevel)
+				}
+				if err := enforceApprovalPolicy(cmd, errOut, policy, token, approvers, currentApprovalApplier(), releaseName, resolvedNamespace, preview); err != nil {
 					return err
 				}
+				if !autoApprove {
+					err := confirmAction(cmd.Context(), cmd.InOrStdin(), errOut, dec, "Do you want to perform these actions? Only 'yes' will be accepted ('e' edits the values):", confirmModeYesOrEdit, "")
+					if errors.Is(err, errEditValues) {
+						if err := editValues(""); err != nil {
+							return err
+						}
+						continue
+					}
+					if err != nil {
+						return err
+					}
+				}
+				break
 			}
+			valuesEdit.Report()
 
+			var gitMeta *deploy.GitMetadata
+			if !noGitMetadata {
+				gitMeta = deploy.DetectGitMetadata(chart)
+			}
 			stream := deploy.NewStreamBroadcaster(releaseName, resolvedNamespace, chart)
 			var captureRecorder *capture.Recorder
 			if path := strings.TrimSpace(capturePath); path != "" {
@@ -352,12 +613,18 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				if err != nil {
 					return err
 				}
+				var parentRunID string
+				if replay != nil {
+					parentRunID = replay.RunID
+				}
 				rec, err := capture.Open(path, capture.SessionMeta{
-					Command:   cmd.CommandPath(),
-					Args:      append([]string(nil), os.Args[1:]...),
-					StartedAt: time.Now().UTC(),
-					Host:      host,
-					Tags:      tagMap,
+					Command:     cmd.CommandPath(),
+					Args:        append([]string(nil), os.Args[1:]...),
+					ParentRunID: parentRunID,
+					StartedAt:   time.Now().UTC(),
+					Host:        host,
+					Extra:       gitMeta.Fields(),
+					Tags:        tagMap,
 					Entities: capture.Entities{
 						KubeContext:  derefString(kubeContext),
 						Namespace:    resolvedNamespace,
@@ -375,6 +642,8 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 			}
 			timerObserver := newPhaseTimerObserver()
 			var deployedRelease *release.Release
+			var reachability []deploy.ProbeResult
+			var chartTests *deploy.ChartTestReport
 			defer func() {
 				if captureRecorder != nil {
 					_ = captureRecorder.Close()
@@ -387,6 +656,13 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 					summary.Status = "failed"
 					summary.Error = runErr.Error()
 				}
+				var interrupted *deploy.InterruptedError
+				if errors.As(runErr, &interrupted) {
+					summary.Status = "interrupted"
+					summary.Error = ""
+					summary.Interrupted = interrupted.Summary
+					interrupted.Summary.Write(errOut)
+				}
 				if deployedRelease != nil {
 					if deployedRelease.Info != nil {
 						summary.Status = deployedRelease.Info.Status.String()
@@ -398,6 +674,7 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 					}
 				}
 				summary.Secrets = cloneSecretRefs(secretRefs)
+				summary.Git = gitMeta.Clone()
 				historyCopy := deploy.CloneBreadcrumbs(historyBreadcrumbs)
 				lastSuccessCopy := deploy.CloneBreadcrumbPointer(lastSuccessful)
 				if deployedRelease != nil {
@@ -431,6 +708,8 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				summary.History = historyCopy
 				summary.LastSuccessful = lastSuccessCopy
 				summary.PhaseDurations = formatPhaseDurations(timerObserver.snapshot())
+				summary.Reachability = reachability
+				summary.ChartTests = chartTests
 				if stream != nil {
 					stream.EmitSummary(summary)
 				}
@@ -450,12 +729,27 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				Diff:      false,
 			})
 
-			trackerManifest, err := renderManifestForTracking(ctx, settings, resolvedNamespace, chart, version, releaseName, valuesFiles, setValues, setStringValues, setFileValues, secretOptions)
-			if err != nil && shouldLogAtLevel(currentLogLevel, zapcore.InfoLevel) {
-				fmt.Fprintf(errOut, "Warning: failed to pre-render manifest for deploy tracker: %v\n", err)
+			trackerManifest, ok := runCache.PreviewManifest(true)
+			hookSteps, hookManifest, _ := runCache.PreviewHooks()
+			if !ok {
+				rendered, err := renderManifestForTracking(ctx, settings, restGetter, runCache, resolvedNamespace, chart, version, releaseName, valuesFiles, setValues, setStringValues, setFileValues, setJSONValues, setLiteralValues, secretOptions, postRenderer)
+				if err != nil {
+					if shouldLogAtLevel(currentLogLevel, zapcore.InfoLevel) {
+						fmt.Fprintf(errOut, "Warning: failed to pre-render manifest for deploy tracker: %v\n", err)
+					}
+				} else {
+					trackerManifest, hookSteps, hookManifest = rendered.Manifest, rendered.Hooks, rendered.HookManifest
+				}
 			}
-			if strings.TrimSpace(requireVerified) != "" && strings.TrimSpace(trackerManifest) != "" {
-				if verr := enforceVerifiedDigest(requireVerified, trackerManifest, releaseName, resolvedNamespace); verr != nil {
+			if strings.TrimSpace(requireVerified) != "" {
+				// ktl verify hashes the template render of the release, so check that render rather
+				// than the cached preview, whose CRDs and layout come from the dry-run upgrade. A
+				// render failure blocks the apply instead of skipping the check.
+				verified, err := renderManifestForTracking(ctx, settings, restGetter, runCache, resolvedNamespace, chart, version, releaseName, valuesFiles, setValues, setStringValues, setFileValues, setJSONValues, setLiteralValues, secretOptions, postRenderer)
+				if err != nil {
+					return fmt.Errorf("render manifest for --require-verified: %w", err)
+				}
+				if verr := enforceVerifiedDigest(requireVerified, verified.Manifest, releaseName, resolvedNamespace); verr != nil {
 					return verr
 				}
 			}
@@ -470,7 +764,18 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				_ = captureRecorder.RecordArtifact(ctx, "apply.inputs.set_values_json", captureJSON(setValues))
 				_ = captureRecorder.RecordArtifact(ctx, "apply.inputs.set_string_values_json", captureJSON(setStringValues))
 				_ = captureRecorder.RecordArtifact(ctx, "apply.inputs.set_file_values_json", captureJSON(setFileValues))
+				_ = captureRecorder.RecordArtifact(ctx, "apply.inputs.set_json_values_json", captureJSON(setJSONValues))
+				_ = captureRecorder.RecordArtifact(ctx, "apply.inputs.set_literal_values_json", captureJSON(setLiteralValues))
 				_ = captureRecorder.RecordArtifact(ctx, "apply.inputs.values_files_json", captureJSON(deploy.HashFiles(valuesFiles)))
+				// Values files often hold credentials, so their contents are only recorded on request.
+				if captureValues {
+					_ = captureRecorder.RecordArtifact(ctx, "apply.inputs.values_files_content_json", captureJSON(captureValuesFiles(valuesFiles)))
+				}
+			}
+			if replay != nil && strings.TrimSpace(replay.RenderedManifest) != "" && strings.TrimSpace(trackerManifest) != "" {
+				if msg := replayRenderMismatch(replay.RenderedManifest, trackerManifest); msg != "" {
+					fmt.Fprintf(errOut, "Warning: replayed render differs from capture session %s: %s\n", replay.SessionID, msg)
+				}
 			}
 
 			if stream != nil && (strings.TrimSpace(uiAddr) != "" || strings.TrimSpace(wsListenAddr) != "") {
@@ -516,10 +821,12 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				initialSummary.History = deploy.CloneBreadcrumbs(historyBreadcrumbs)
 				initialSummary.LastSuccessful = deploy.CloneBreadcrumbPointer(lastSuccessful)
 				initialSummary.Secrets = cloneSecretRefs(secretRefs)
+				initialSummary.Git = gitMeta.Clone()
 				stream.EmitSummary(initialSummary)
 			}
 
-			if console == nil && shouldLogAtLevel(currentLogLevel, zapcore.InfoLevel) && isTerminalWriter(errOut) {
+			plain := plainConsole(cmd)
+			if console == nil && shouldLogAtLevel(currentLogLevel, zapcore.InfoLevel) && (plain || isTerminalWriter(errOut)) {
 				width, _ := ui.TerminalWidth(errOut)
 				meta := ui.DeployMetadata{
 					Release:         releaseName,
@@ -533,6 +840,7 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				console = ui.NewDeployConsole(errOut, meta, ui.DeployConsoleOptions{
 					Enabled: true,
 					Width:   width,
+					Plain:   plain,
 				})
 			}
 
@@ -590,7 +898,8 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 							}
 						}
 					}
-					tracker := deploy.NewResourceTracker(kubeClient, resolvedNamespace, releaseName, trackerManifest, multiUpdate)
+					mode, _ := deploy.ParseTrackerMode(trackerMode)
+					tracker := deploy.NewResourceTracker(kubeClient, resolvedNamespace, releaseName, trackerManifest, multiUpdate).WithMode(mode)
 					go tracker.Run(trackerCtx)
 					cancelTrack = cancel
 				}
@@ -625,6 +934,14 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				progressObservers = append(progressObservers, stream)
 			}
 
+			stopHooks := func(bool) {}
+			if !dryRun {
+				feed := hookFeed{observers: progressObservers, stream: stream}
+				if console == nil && shouldLogAtLevel(currentLogLevel, zapcore.WarnLevel) {
+					feed.plain = errOut
+				}
+				stopHooks = startHookTracker(ctx, kubeClient.Clientset, resolvedNamespace, hookSteps, hookManifest, lastSuccessful != nil, console, feed)
+			}
 			result, err := deploy.InstallOrUpgrade(ctx, actionCfg, settings, deploy.InstallOptions{
 				Chart:             chart,
 				Version:           version,
@@ -634,6 +951,8 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				SetValues:         setValues,
 				SetStringValues:   setStringValues,
 				SetFileValues:     setFileValues,
+				SetJSONValues:     setJSONValues,
+				SetLiteralValues:  setLiteralValues,
 				Secrets:           secretOptions,
 				Timeout:           timeout,
 				Wait:              wait,
@@ -643,12 +962,25 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 				Diff:              false,
 				UpgradeOnly:       upgrade,
 				ProgressObservers: progressObservers,
+				GitMetadata:       gitMeta,
+				Owner:             owner,
+				Cache:             runCache,
+				PostRenderer:      postRenderer,
+				Retry:             deploy.RetryPolicy{Attempts: retryAttempts, Backoff: retryBackoff},
 			})
+			stopHooks(err != nil)
 			if err != nil {
+				if ctx.Err() != nil && !dryRun {
+					// Ctrl+C: Helm has seen the cancellation; give it a moment to record the
+					// release state, then report what is known instead of the raw error.
+					releaseStatus := settledReleaseStatus(actionCfg, releaseName, applyInterruptSettle)
+					return &deploy.InterruptedError{Summary: applyInterruptSummary(timerObserver.phaseStates(), releaseName, resolvedNamespace, chart, releaseStatus)}
+				}
 				return err
 			}
 
 			deployedRelease = result.Release
+			printImageReport(cmd.ErrOrStderr(), postRender, postRenderer)
 			if deployedRelease != nil && deployedRelease.Chart != nil && deployedRelease.Chart.Metadata != nil {
 				if deployedRelease.Chart.Metadata.Name != "" {
 					chart = deployedRelease.Chart.Metadata.Name
@@ -682,6 +1014,23 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 					_ = captureRecorder.RecordArtifact(ctx, "apply.status", status)
 				}
 			}
+			if (probeReachability || requireReachable) && !dryRun {
+				reachability, err = probeReleaseReachability(ctx, errOut, stream, kubeClient, rel.Manifest, resolvedNamespace, requireReachable)
+				if err != nil {
+					return err
+				}
+			}
+			if runChartTestsAfter && !dryRun {
+				chartTests, err = runChartTests(cmd, actionCfg, kubeClient.Clientset, stream, deploy.ChartTestOptions{
+					Release:   rel.Name,
+					Namespace: resolvedNamespace,
+					Timeout:   chartTestsTimeout,
+					Logs:      errOut,
+				})
+				if err != nil {
+					return err
+				}
+			}
 			if watchDuration > 0 && !dryRun {
 				fmt.Fprintf(errOut, "Watching release %s for %s...\n", rel.Name, watchDuration)
 				var watchObserver tailer.LogObserver
@@ -726,10 +1075,13 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 	cmd.Flags().StringVar(&chart, "chart", "", "Chart reference (path, repo/name, or OCI ref)")
 	cmd.Flags().StringVar(&releaseName, "release", "", "Helm release name")
 	cmd.Flags().StringVar(&version, "version", "", "Chart version (default: latest)")
-	cmd.Flags().StringSliceVarP(&valuesFiles, "values", "f", nil, "Values files to apply (can be repeated)")
+	cmd.Flags().StringSliceVarP(&valuesFiles, "values", "f", nil, "Values files to apply (can be repeated; - reads stdin, https:// and oci:// are fetched, append #sha256=<hex> to pin)")
+	addPostRenderFlags(cmd, &postRender)
 	cmd.Flags().StringArrayVar(&setValues, "set", nil, "Set values on the command line (key=val)")
 	cmd.Flags().StringArrayVar(&setStringValues, "set-string", nil, "Set STRING values on the command line")
 	cmd.Flags().StringArrayVar(&setFileValues, "set-file", nil, "Set values from files (key=path)")
+	cmd.Flags().StringArrayVar(&setJSONValues, "set-json", nil, "Set JSON values on the command line (key=<json>)")
+	cmd.Flags().StringArrayVar(&setLiteralValues, "set-literal", nil, "Set a literal STRING value on the command line (no escaping or list parsing)")
 	cmd.Flags().StringVar(&secretProvider, "secret-provider", "", "Secret provider name for secret:// references")
 	cmd.Flags().StringVar(&secretConfig, "secret-config", "", "Secrets provider config file (defaults to ~/.ktl/config.yaml and repo .ktl.yaml)")
 	cmd.Flags().BoolVar(&wait, "wait", wait, "Wait for resources to be ready")
@@ -737,6 +1089,7 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 	cmd.Flags().BoolVar(&upgrade, "upgrade", upgrade, "Only perform the upgrade path (skip install fallback)")
 	cmd.Flags().BoolVar(&createNamespace, "create-namespace", false, "Create the release namespace if it does not exist")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Render the chart without applying it")
+	cmd.Flags().BoolVar(&noGitMetadata, "no-git-metadata", false, "Do not record the chart's git commit, branch, dirty state, and author on the release")
 	cmd.Flags().StringVar(&requireVerified, "require-verified", "", "Require a matching verify report (JSON) for this exact render before applying")
 	cmd.Flags().BoolVar(&autoApprove, "auto-approve", false, "Skip interactive confirmation prompts")
 	_ = cmd.Flags().MarkHidden("auto-approve")
@@ -745,6 +1098,8 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 	cmd.Flags().BoolVar(&planServer, "plan-server", false, "Use server-side dry-run to classify replacements (slower; requires RBAC)")
 	cmd.Flags().DurationVar(&watchDuration, "watch", 0, "After a successful deploy, stream logs/events for this long (e.g. 2m)")
 	cmd.Flags().DurationVar(&timeout, "timeout", timeout, "Time to wait for any Kubernetes operation")
+	cmd.Flags().IntVar(&retryAttempts, "retry", 3, "Maximum helm upgrade/install attempts when they fail with a transient error such as a conflict, etcd leader change, or webhook timeout (includes the initial attempt)")
+	cmd.Flags().DurationVar(&retryBackoff, "retry-backoff", 2*time.Second, "Delay before the first retry; doubles for each further retry (capped at 30s)")
 	cmd.Flags().StringVar(&uiAddr, "ui", "", "Serve the live deploy viewer at this address (e.g. :8080)")
 	if flag := cmd.Flags().Lookup("ui"); flag != nil {
 		flag.NoOptDefVal = ":8080"
@@ -752,15 +1107,29 @@ func newDeployApplyCommand(namespace *string, kubeconfig *string, kubeContext *s
 	cmd.Flags().StringVar(&wsListenAddr, "ws-listen", "", "Serve the raw deploy event stream over WebSocket at this address (e.g. :9086)")
 	cmd.Flags().BoolVar(&driftGuard, "drift-guard", false, "Fail if live cluster resources drift from the last applied Helm release state")
 	cmd.Flags().StringVar(&driftGuardMode, "drift-guard-mode", "last-applied", "Drift guard mode: last-applied (compare to current Helm release) or desired (compare to newly rendered manifest)")
+	cmd.Flags().BoolVar(&resolveLiveConflicts, "resolve-conflicts", false, "Review fields edited in the cluster that this apply would overwrite and choose keep-live, take-chart, or abort for each")
+	cmd.Flags().StringVar(&editedValuesOut, "edited-values-out", "", "Also write values edited at the apply prompt (answer 'e') to this file")
+	cmd.Flags().StringVar(&conflictValuesOut, "conflict-values-out", "", "Write the values that keep the live fields chosen with --resolve-conflicts to this file")
+	cmd.Flags().StringVar(&approvalPolicy, "approval-policy", deploy.ApprovalPolicyNone, "Require a second approver's token (from ktl approve) before applying: none, risky (plans with risky changes), or always (deploy.approvalPolicy in .ktl.yaml is the minimum)")
+	cmd.Flags().StringVar(&approvalToken, "approval-token", "", "Approval token from 'ktl approve <plan-digest>' run by someone else (or set "+approvalTokenEnv+")")
+	cmd.Flags().BoolVar(&overrideWindow, "override-window", false, "Apply outside the deploy window configured under deploy.windows in .ktl.yaml (requires --reason; recorded in the audit log)")
+	cmd.Flags().StringVar(&overrideReason, "reason", "", "Why the deploy window is overridden (with --override-window)")
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging (equivalent to --log-level=debug)")
 	cmd.Flags().StringVar(&capturePath, "capture", "", "Capture deploy events/logs/manifests to a SQLite database at this path")
 	if flag := cmd.Flags().Lookup("capture"); flag != nil {
 		flag.NoOptDefVal = "__auto__"
 	}
 	cmd.Flags().StringArrayVar(&captureTags, "capture-tag", nil, "Tag the capture session (KEY=VALUE). Repeatable.")
-
-	_ = cmd.MarkFlagRequired("chart")
-	_ = cmd.MarkFlagRequired("release")
+	cmd.Flags().BoolVar(&captureValues, "capture-values", false, "With --capture: also record the contents of the values files so --from-capture can replay them after they change or on another machine (values often contain credentials; only hashes are recorded otherwise)")
+	cmd.Flags().StringVar(&trackerMode, "tracker", string(deploy.TrackerModeWatch), "How resource status is tracked: watch (informers, falls back to poll without list/watch RBAC) or poll")
+	cmd.Flags().BoolVar(&probeReachability, "probe-reachability", false, "After a successful apply, probe the release's Ingress/HTTPRoute hosts over HTTP(S) and report status, latency, and cert expiry")
+	cmd.Flags().BoolVar(&requireReachable, "require-reachable", false, "Like --probe-reachability, but fail the apply when a host does not answer with a status below 500")
+	cmd.Flags().BoolVar(&runChartTestsAfter, "run-chart-tests", false, "After a successful apply, run the chart's helm.sh/hook: test hooks, stream their logs, and fail the apply (exit code 3) when a test fails")
+	cmd.Flags().DurationVar(&chartTestsTimeout, "chart-tests-timeout", chartTestsTimeout, "With --run-chart-tests: time to wait for each test to finish")
+	cmd.Flags().StringVar(&fromCapture, "from-capture", "", "Re-apply the chart, version, and values recorded in this capture database (replaces --chart/--version/--values/--set*)")
+	cmd.Flags().StringVar(&tenantsFilePath, "tenants-file", "", "Apply the chart once per tenant listed in this YAML file (namespace, optional release, and values layered on the shared -f/--set inputs); requires --yes or --dry-run")
+	cmd.Flags().IntVar(&tenantsConcurrency, "tenants-concurrency", tenantsConcurrency, "With --tenants-file: maximum number of tenants applied at once")
+	cmd.Flags().StringVar(&fromCaptureSession, "from-capture-session", "", "Session or run id to replay from --from-capture (defaults to the most recent apply)")
 
 	if ownNamespaceFlag {
 		cmd.Flags().StringVarP(namespace, "namespace", "n", "", "Namespace for the Helm release (defaults to active context)")
@@ -800,6 +1169,7 @@ func newDeployRemovalCommand(cfg deployRemovalConfig, namespace *string, kubecon
 	var verbose bool
 	var capturePath string
 	var captureTags []string
+	var trackerMode string
 	timeout := 5 * time.Minute
 
 	cmd := &cobra.Command{
@@ -822,6 +1192,9 @@ func newDeployRemovalCommand(cfg deployRemovalConfig, namespace *string, kubecon
 			if timeout <= 0 {
 				return fmt.Errorf("--timeout must be > 0")
 			}
+			if _, err := deploy.ParseTrackerMode(trackerMode); err != nil {
+				return err
+			}
 			return nil
 		},
 		SilenceUsage:  true,
@@ -970,11 +1343,13 @@ func newDeployRemovalCommand(cfg deployRemovalConfig, namespace *string, kubecon
 				}
 			}()
 
-			if shouldLogAtLevel(currentLogLevel, zapcore.InfoLevel) && isTerminalWriter(errOut) {
+			plain := plainConsole(cmd)
+			if shouldLogAtLevel(currentLogLevel, zapcore.InfoLevel) && (plain || isTerminalWriter(errOut)) {
 				width, _ := ui.TerminalWidth(errOut)
 				console = ui.NewDeployConsole(errOut, meta, ui.DeployConsoleOptions{
 					Enabled: true,
 					Width:   width,
+					Plain:   plain,
 				})
 				console.UpdateMetadata(meta)
 			} else if shouldLogAtLevel(currentLogLevel, zapcore.InfoLevel) {
@@ -1171,7 +1546,8 @@ func newDeployRemovalCommand(cfg deployRemovalConfig, namespace *string, kubecon
 						}
 					}
 				}
-				tracker := deploy.NewResourceTracker(kubeClient, resolvedNamespace, release, "", multiUpdate)
+				mode, _ := deploy.ParseTrackerMode(trackerMode)
+				tracker := deploy.NewResourceTracker(kubeClient, resolvedNamespace, release, "", multiUpdate).WithMode(mode)
 				go tracker.Run(trackerCtx)
 				cancelTrack = cancel
 			}
@@ -1276,6 +1652,7 @@ func newDeployRemovalCommand(cfg deployRemovalConfig, namespace *string, kubecon
 	}
 	cmd.Flags().StringVar(&wsListenAddr, "ws-listen", "", "Serve the destroy event stream over WebSocket (e.g. :9087)")
 	cmd.Flags().BoolVar(&force, "force", false, "Force uninstall even if Kubernetes resources are in a bad state")
+	cmd.Flags().StringVar(&trackerMode, "tracker", string(deploy.TrackerModeWatch), "How resource status is tracked: watch (informers, falls back to poll without list/watch RBAC) or poll")
 	cmd.Flags().BoolVar(&disableHooks, "disable-hooks", false, "Disable Helm hooks while destroying the release")
 	// --console-wide/--console-details removed.
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging (equivalent to --log-level=debug)")
@@ -1297,31 +1674,122 @@ func newDeployRemovalCommand(cfg deployRemovalConfig, namespace *string, kubecon
 	return cmd
 }
 
-func renderManifestForTracking(ctx context.Context, settings *cli.EnvSettings, namespace, chart, version, release string, valuesFiles, setValues, setStringValues, setFileValues []string, secrets *deploy.SecretOptions) (string, error) {
+// printPlanPreview prints the resource and hook counts of a dry-run preview and the first
+// changes, the way terraform summarizes a plan before asking for approval.
+func printPlanPreview(errOut io.Writer, preview *deploy.InstallResult, currentLogLevel string) {
+	if preview == nil || preview.PlanSummary == nil {
+		return
+	}
+	fmt.Fprintf(errOut, "Plan: %d to add, %d to change, %d to replace, %d to destroy.\n", preview.PlanSummary.Add, preview.PlanSummary.Change, preview.PlanSummary.Replace, preview.PlanSummary.Destroy)
+	if preview.PlanSummary.Hooks.Add > 0 || preview.PlanSummary.Hooks.Change > 0 || preview.PlanSummary.Hooks.Replace > 0 || preview.PlanSummary.Hooks.Destroy > 0 {
+		fmt.Fprintf(errOut, "Hooks: %d to add, %d to change, %d to replace, %d to destroy.\n", preview.PlanSummary.Hooks.Add, preview.PlanSummary.Hooks.Change, preview.PlanSummary.Hooks.Replace, preview.PlanSummary.Hooks.Destroy)
+	}
+	if preview.PlanSummarizeError != "" && shouldLogAtLevel(currentLogLevel, zapcore.WarnLevel) {
+		fmt.Fprintf(errOut, "Warning: unable to fully summarize plan: %s\n", preview.PlanSummarizeError)
+	}
+	limit := 12
+	if len(preview.PlanSummary.Changes) > 0 {
+		if len(preview.PlanSummary.Changes) < limit {
+			limit = len(preview.PlanSummary.Changes)
+		}
+		for _, ch := range preview.PlanSummary.Changes[:limit] {
+			prefix := "~"
+			switch ch.Action {
+			case deploy.PlanAdd:
+				prefix = "+"
+			case deploy.PlanDestroy:
+				prefix = "-"
+			case deploy.PlanUpdate:
+				prefix = "~"
+			case deploy.PlanReplace:
+				prefix = "±"
+			}
+			nsLabel := ch.Namespace
+			if nsLabel == "" {
+				nsLabel = "-"
+			}
+			fmt.Fprintf(errOut, "  %s %s/%s (ns: %s)\n", prefix, ch.Kind, ch.Name, nsLabel)
+		}
+		if len(preview.PlanSummary.Changes) > limit {
+			fmt.Fprintf(errOut, "  (and %d more)\n", len(preview.PlanSummary.Changes)-limit)
+		}
+	}
+	if len(preview.PlanSummary.Hooks.Changes) > 0 {
+		fmt.Fprintln(errOut, "Hook changes:")
+		limitHooks := 8
+		if len(preview.PlanSummary.Hooks.Changes) < limitHooks {
+			limitHooks = len(preview.PlanSummary.Hooks.Changes)
+		}
+		for _, ch := range preview.PlanSummary.Hooks.Changes[:limitHooks] {
+			prefix := "~"
+			switch ch.Action {
+			case deploy.PlanAdd:
+				prefix = "+"
+			case deploy.PlanDestroy:
+				prefix = "-"
+			case deploy.PlanReplace:
+				prefix = "±"
+			}
+			nsLabel := ch.Namespace
+			if nsLabel == "" {
+				nsLabel = "-"
+			}
+			hookLabel := strings.TrimSpace(ch.Hook)
+			if hookLabel == "" {
+				hookLabel = "hook"
+			}
+			fmt.Fprintf(errOut, "  %s %s/%s (ns: %s, %s)\n", prefix, ch.Kind, ch.Name, nsLabel, hookLabel)
+		}
+		if len(preview.PlanSummary.Hooks.Changes) > limitHooks {
+			fmt.Fprintf(errOut, "  (and %d more)\n", len(preview.PlanSummary.Hooks.Changes)-limitHooks)
+		}
+	}
+	printRiskyChanges(errOut, preview.PlanSummary.RiskyChanges())
+}
+
+// printRiskyChanges prints the "Requires extra approval" section: one line per risk of every
+// risky change, tagged so reviewers and policy gates see the same classification.
+func printRiskyChanges(w io.Writer, changes []deploy.PlanChange) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Requires extra approval:")
+	for _, ch := range changes {
+		for _, risk := range ch.Risks {
+			fmt.Fprintf(w, "  ! %s/%s (ns: %s) [%s] %s\n", ch.Kind, ch.Name, orDash(ch.Namespace), risk.Tag, risk.Detail)
+		}
+	}
+}
+
+func renderManifestForTracking(ctx context.Context, settings *cli.EnvSettings, getter genericclioptions.RESTClientGetter, cache *deploy.RunCache, namespace, chart, version, release string, valuesFiles, setValues, setStringValues, setFileValues, setJSONValues, setLiteralValues []string, secrets *deploy.SecretOptions, postRenderer postrender.PostRenderer) (*deploy.TemplateResult, error) {
 	if chart == "" || release == "" {
-		return "", fmt.Errorf("chart and release are required")
+		return nil, fmt.Errorf("chart and release are required")
 	}
 	templateCfg := new(action.Configuration)
-	if err := templateCfg.Init(settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
-		return "", fmt.Errorf("init template config: %w", err)
+	if err := templateCfg.Init(getter, namespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+		return nil, fmt.Errorf("init template config: %w", err)
 	}
 	result, err := deploy.RenderTemplate(ctx, templateCfg, settings, deploy.TemplateOptions{
-		Chart:           chart,
-		Version:         version,
-		ReleaseName:     release,
-		Namespace:       namespace,
-		ValuesFiles:     valuesFiles,
-		SetValues:       setValues,
-		SetStringValues: setStringValues,
-		SetFileValues:   setFileValues,
-		Secrets:         secrets,
-		IncludeCRDs:     true,
-		UseCluster:      true,
+		Chart:            chart,
+		Version:          version,
+		ReleaseName:      release,
+		Namespace:        namespace,
+		ValuesFiles:      valuesFiles,
+		SetValues:        setValues,
+		SetStringValues:  setStringValues,
+		SetFileValues:    setFileValues,
+		SetJSONValues:    setJSONValues,
+		SetLiteralValues: setLiteralValues,
+		Secrets:          secrets,
+		IncludeCRDs:      true,
+		UseCluster:       true,
+		Cache:            cache,
+		PostRenderer:     postRenderer,
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return result.Manifest, nil
+	return result, nil
 }
 
 func ensureNamespace(ctx context.Context, client kubernetes.Interface, namespace string) error {
@@ -1463,12 +1931,14 @@ type phaseTimerObserver struct {
 	mu        sync.Mutex
 	starts    map[string]time.Time
 	durations map[string]time.Duration
+	states    map[string]string
 }
 
 func newPhaseTimerObserver() *phaseTimerObserver {
 	return &phaseTimerObserver{
 		starts:    make(map[string]time.Time),
 		durations: make(map[string]time.Duration),
+		states:    make(map[string]string),
 	}
 }
 
@@ -1479,10 +1949,11 @@ func (o *phaseTimerObserver) PhaseStarted(name string) {
 	}
 	o.mu.Lock()
 	o.starts[name] = time.Now()
+	o.states[name] = "running"
 	o.mu.Unlock()
 }
 
-func (o *phaseTimerObserver) PhaseCompleted(name, _ string, _ string) {
+func (o *phaseTimerObserver) PhaseCompleted(name, status string, _ string) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return
@@ -1493,6 +1964,7 @@ func (o *phaseTimerObserver) PhaseCompleted(name, _ string, _ string) {
 	if ok && !start.IsZero() {
 		o.durations[name] = now.Sub(start)
 	}
+	o.states[name] = strings.ToLower(strings.TrimSpace(status))
 	o.mu.Unlock()
 }
 
@@ -1510,6 +1982,17 @@ func (o *phaseTimerObserver) snapshot() map[string]time.Duration {
 	return out
 }
 
+// phaseStates returns the last reported state (running, succeeded, failed, skipped) per phase.
+func (o *phaseTimerObserver) phaseStates() map[string]string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := make(map[string]string, len(o.states))
+	for k, v := range o.states {
+		out[k] = v
+	}
+	return out
+}
+
 func formatPhaseDurations(durations map[string]time.Duration) map[string]string {
 	if len(durations) == 0 {
 		return nil
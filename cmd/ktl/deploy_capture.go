@@ -2,10 +2,16 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/kubekattle/ktl/internal/capture"
+	"github.com/kubekattle/ktl/internal/deploy"
 	"helm.sh/helm/v3/pkg/release"
 )
 
@@ -53,3 +59,186 @@ func captureHelmRelease(ctx context.Context, rec artifactRecorder, rel *release.
 		_ = rec.RecordArtifact(ctx, "apply.release.json", string(raw))
 	}
 }
+
+// capturedValuesFile is the content of one values file recorded at apply time with
+// --capture-values, so a capture can be replayed after the file changed or from a machine that
+// never had it.
+type capturedValuesFile struct {
+	Path    string `json:"path"`
+	SHA256  string `json:"sha256"`
+	Content string `json:"content"`
+}
+
+func captureValuesFiles(paths []string) []capturedValuesFile {
+	out := make([]capturedValuesFile, 0, len(paths))
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		out = append(out, capturedValuesFile{Path: p, SHA256: hex.EncodeToString(sum[:]), Content: string(data)})
+	}
+	return out
+}
+
+// applyCaptureInputs are the inputs an apply recorded in its capture session. `ktl apply
+// --from-capture` re-applies them, typically against another context.
+type applyCaptureInputs struct {
+	SessionID        string
+	RunID            string
+	Chart            string
+	Version          string
+	Release          string
+	Namespace        string
+	SetValues        []string
+	SetStringValues  []string
+	SetFileValues    []string
+	SetJSONValues    []string
+	SetLiteralValues []string
+	ValuesFiles      []deploy.CaptureFileHash
+	ValuesContent    []capturedValuesFile
+	RenderedManifest string
+}
+
+// loadApplyCaptureInputs reads the apply inputs of session (a session or run id) from a capture
+// database, or of the most recent apply session when session is empty.
+func loadApplyCaptureInputs(ctx context.Context, path, session string) (*applyCaptureInputs, error) {
+	db, err := capture.OpenReadOnly(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	session = strings.TrimSpace(session)
+	res, err := capture.Query(ctx, db, `
+SELECT s.session_id, COALESCE(s.run_id, '')
+FROM ktl_capture_sessions s
+WHERE EXISTS (SELECT 1 FROM ktl_capture_artifacts a WHERE a.session_id = s.session_id AND a.name = 'apply.inputs.chart')
+  AND (? = '' OR s.session_id = ? OR s.run_id = ?)
+ORDER BY COALESCE(s.started_at_ns, 0) DESC, s.started_at DESC
+LIMIT 1`, session, session, session)
+	if err != nil {
+		return nil, fmt.Errorf("read capture sessions: %w", err)
+	}
+	rows := res.Records()
+	if len(rows) == 0 {
+		if session != "" {
+			return nil, fmt.Errorf("capture %s has no apply session %q", path, session)
+		}
+		return nil, fmt.Errorf("capture %s has no apply session with recorded inputs", path)
+	}
+	in := &applyCaptureInputs{SessionID: rows[0][0], RunID: rows[0][1]}
+
+	res, err = capture.Query(ctx, db, `
+SELECT name, text FROM ktl_capture_artifacts
+WHERE session_id = ?
+ORDER BY COALESCE(seq, id)`, in.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("read capture artifacts: %w", err)
+	}
+	var releaseJSON string
+	for _, row := range res.Records() {
+		name, text := row[0], row[1]
+		var decodeErr error
+		switch name {
+		case "apply.inputs.chart":
+			in.Chart = strings.TrimSpace(text)
+		case "apply.inputs.version":
+			in.Version = strings.TrimSpace(text)
+		case "apply.inputs.release":
+			in.Release = strings.TrimSpace(text)
+		case "apply.inputs.namespace":
+			in.Namespace = strings.TrimSpace(text)
+		case "apply.inputs.set_values_json":
+			decodeErr = decodeCaptureJSON(text, &in.SetValues)
+		case "apply.inputs.set_string_values_json":
+			decodeErr = decodeCaptureJSON(text, &in.SetStringValues)
+		case "apply.inputs.set_file_values_json":
+			decodeErr = decodeCaptureJSON(text, &in.SetFileValues)
+		case "apply.inputs.set_json_values_json":
+			decodeErr = decodeCaptureJSON(text, &in.SetJSONValues)
+		case "apply.inputs.set_literal_values_json":
+			decodeErr = decodeCaptureJSON(text, &in.SetLiteralValues)
+		case "apply.inputs.values_files_json":
+			decodeErr = decodeCaptureJSON(text, &in.ValuesFiles)
+		case "apply.inputs.values_files_content_json":
+			decodeErr = decodeCaptureJSON(text, &in.ValuesContent)
+		case "rendered_manifest":
+			in.RenderedManifest = text
+		case "apply.release.json":
+			releaseJSON = text
+		}
+		if decodeErr != nil {
+			return nil, fmt.Errorf("capture artifact %s: %w", name, decodeErr)
+		}
+	}
+	if in.Chart == "" || in.Release == "" {
+		return nil, fmt.Errorf("capture session %s is missing the recorded chart or release", in.SessionID)
+	}
+	// Pin the chart version the original apply resolved when it was not given explicitly.
+	if in.Version == "" && strings.TrimSpace(releaseJSON) != "" {
+		var rel capturedHelmRelease
+		if err := json.Unmarshal([]byte(releaseJSON), &rel); err == nil {
+			in.Version = rel.Version
+		}
+	}
+	return in, nil
+}
+
+func decodeCaptureJSON(text string, out any) error {
+	if strings.TrimSpace(text) == "" || strings.TrimSpace(text) == "null" {
+		return nil
+	}
+	return json.Unmarshal([]byte(text), out)
+}
+
+// materializeValues returns local values files equal to the ones the captured apply used. Recorded
+// contents are written to dir. Captures made without --capture-values only hashed their values,
+// so they fall back to the original paths, which must still match the recorded SHA-256.
+func (in *applyCaptureInputs) materializeValues(dir string) ([]string, error) {
+	byHash := map[string]capturedValuesFile{}
+	for _, f := range in.ValuesContent {
+		byHash[f.SHA256] = f
+	}
+	files := make([]string, 0, len(in.ValuesFiles))
+	for _, want := range in.ValuesFiles {
+		if want.Error != "" || want.SHA256 == "" {
+			return nil, fmt.Errorf("values %s was not readable when captured: %s", want.Path, want.Error)
+		}
+		if f, ok := byHash[want.SHA256]; ok {
+			path, err := deploy.WriteValuesCacheFile(dir, []byte(f.Content))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, path)
+			continue
+		}
+		got := deploy.HashFiles([]string{want.Path})[0]
+		if got.Error != "" {
+			return nil, fmt.Errorf("values %s: its contents were not recorded (capture with --capture-values to replay them) and the file is not readable: %s", want.Path, got.Error)
+		}
+		if got.SHA256 != want.SHA256 {
+			return nil, fmt.Errorf("values %s changed since it was captured (sha256 %s, captured %s) and its contents were not recorded (capture with --capture-values to replay them)", want.Path, got.SHA256, want.SHA256)
+		}
+		files = append(files, want.Path)
+	}
+	return files, nil
+}
+
+// replayRenderMismatch compares a replayed render with the captured one. Renders differ when the
+// chart uses lookup or .Capabilities and the target cluster is not like the original.
+func replayRenderMismatch(captured, rendered string) string {
+	want, _, err := deploy.DigestNormalizedManifest(captured)
+	if err != nil {
+		return fmt.Sprintf("captured manifest: %v", err)
+	}
+	got, _, err := deploy.DigestNormalizedManifest(rendered)
+	if err != nil {
+		return fmt.Sprintf("rendered manifest: %v", err)
+	}
+	return deploy.FormatDigestMismatch(want, got)
+}
@@ -3,9 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/kubekattle/ktl/internal/capture"
+	"github.com/kubekattle/ktl/internal/deploy"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/release"
 	helmtime "helm.sh/helm/v3/pkg/time"
@@ -66,3 +71,123 @@ func TestCaptureHelmRelease_WritesManifestAndJSON(t *testing.T) {
 		t.Fatalf("decoded updatedAt = %q", decoded.UpdatedAt)
 	}
 }
+
+func recordApplyCapture(t *testing.T, path string, started time.Time, release string, valuesFiles []string, withContent bool) string {
+	t.Helper()
+	rec, err := capture.Open(path, capture.SessionMeta{Command: "ktl apply", StartedAt: started})
+	if err != nil {
+		t.Fatalf("open capture: %v", err)
+	}
+	ctx := context.Background()
+	_ = rec.RecordArtifact(ctx, "apply.inputs.chart", "oci://registry.example.com/charts/api")
+	_ = rec.RecordArtifact(ctx, "apply.inputs.version", "")
+	_ = rec.RecordArtifact(ctx, "apply.inputs.release", release)
+	_ = rec.RecordArtifact(ctx, "apply.inputs.namespace", "prod")
+	_ = rec.RecordArtifact(ctx, "apply.inputs.set_values_json", captureJSON([]string{"replicas=3"}))
+	_ = rec.RecordArtifact(ctx, "apply.inputs.set_string_values_json", captureJSON([]string(nil)))
+	_ = rec.RecordArtifact(ctx, "apply.inputs.values_files_json", captureJSON(deploy.HashFiles(valuesFiles)))
+	if withContent {
+		_ = rec.RecordArtifact(ctx, "apply.inputs.values_files_content_json", captureJSON(captureValuesFiles(valuesFiles)))
+	}
+	_ = rec.RecordArtifact(ctx, "apply.release.json", `{"name":"`+release+`","chart":"api","version":"1.4.2"}`)
+	runID := rec.RunID()
+	if err := rec.Close(); err != nil {
+		t.Fatalf("close capture: %v", err)
+	}
+	return runID
+}
+
+func TestLoadApplyCaptureInputsReplaysRecordedValues(t *testing.T) {
+	dir := t.TempDir()
+	values := filepath.Join(dir, "prod.yaml")
+	if err := os.WriteFile(values, []byte("image:\n  tag: v1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	db := filepath.Join(dir, "apply.sqlite")
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	firstRun := recordApplyCapture(t, db, base, "api-old", []string{values}, true)
+	recordApplyCapture(t, db, base.Add(time.Hour), "api", []string{values}, true)
+
+	// The values file changes after the capture; the replay must still use the recorded content.
+	if err := os.WriteFile(values, []byte("image:\n  tag: v2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	in, err := loadApplyCaptureInputs(context.Background(), db, "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if in.Release != "api" || in.Namespace != "prod" || in.Version != "1.4.2" || strings.Join(in.SetValues, ",") != "replicas=3" {
+		t.Fatalf("unexpected inputs: %+v", in)
+	}
+	files, err := in.materializeValues(t.TempDir())
+	if err != nil {
+		t.Fatalf("materialize: %v", err)
+	}
+	if data, _ := os.ReadFile(files[0]); len(files) != 1 || string(data) != "image:\n  tag: v1\n" {
+		t.Fatalf("expected the captured values, got %v %q", files, data)
+	}
+
+	old, err := loadApplyCaptureInputs(context.Background(), db, firstRun)
+	if err != nil || old.Release != "api-old" {
+		t.Fatalf("expected the selected run, got %+v %v", old, err)
+	}
+	if _, err := loadApplyCaptureInputs(context.Background(), db, "missing"); err == nil {
+		t.Fatalf("expected an unknown session to fail")
+	}
+}
+
+func TestMaterializeValuesChecksHashOnlyCaptures(t *testing.T) {
+	dir := t.TempDir()
+	values := filepath.Join(dir, "prod.yaml")
+	if err := os.WriteFile(values, []byte("a: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	db := filepath.Join(dir, "apply.sqlite")
+	recordApplyCapture(t, db, time.Now(), "api", []string{values}, false)
+	in, err := loadApplyCaptureInputs(context.Background(), db, "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if files, err := in.materializeValues(t.TempDir()); err != nil || len(files) != 1 || files[0] != values {
+		t.Fatalf("expected the unchanged original path, got %v %v", files, err)
+	}
+	if err := os.WriteFile(values, []byte("a: 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := in.materializeValues(t.TempDir()); err == nil || !strings.Contains(err.Error(), "changed since it was captured") || !strings.Contains(err.Error(), "--capture-values") {
+		t.Fatalf("expected a changed-values error pointing at --capture-values, got %v", err)
+	}
+}
+
+func TestCaptureValuesRequiresCapture(t *testing.T) {
+	var ns, kubeconfig, kubeContext, remoteAgent string
+	logLevel := "info"
+	cmd := newDeployApplyCommand(&ns, &kubeconfig, &kubeContext, &logLevel, &remoteAgent, "")
+	cmd.SetArgs([]string{"--chart", "./chart", "--release", "api", "--capture-values"})
+	cmd.SetOut(new(strings.Builder))
+	cmd.SetErr(new(strings.Builder))
+	if err := cmd.Execute(); err == nil || err.Error() != "--capture-values requires --capture" {
+		t.Fatalf("expected --capture-values to require --capture, got %v", err)
+	}
+}
+
+func TestApplyFromCaptureRejectsExplicitInputs(t *testing.T) {
+	var ns, kubeconfig, kubeContext, remoteAgent string
+	logLevel := "info"
+	cmd := newDeployApplyCommand(&ns, &kubeconfig, &kubeContext, &logLevel, &remoteAgent, "")
+	cmd.SetArgs([]string{"--from-capture", "apply.sqlite", "--chart", "./chart"})
+	cmd.SetOut(new(strings.Builder))
+	cmd.SetErr(new(strings.Builder))
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--chart cannot be combined with --from-capture") {
+		t.Fatalf("expected a conflict error, got %v", err)
+	}
+
+	cmd = newDeployApplyCommand(&ns, &kubeconfig, &kubeContext, &logLevel, &remoteAgent, "")
+	cmd.SetArgs([]string{"--release", "api"})
+	cmd.SetOut(new(strings.Builder))
+	cmd.SetErr(new(strings.Builder))
+	if err := cmd.Execute(); err == nil || err.Error() != `required flag(s) "chart" not set` {
+		t.Fatalf("expected the chart flag to stay required, got %v", err)
+	}
+}
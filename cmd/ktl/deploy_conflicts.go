@@ -0,0 +1,148 @@
+// File: cmd/ktl/deploy_conflicts.go
+// Brief: CLI command wiring and implementation for 'deploy conflicts'.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	conflictKeepLive  = "keep-live"
+	conflictTakeChart = "take-chart"
+	conflictAbort     = "abort"
+)
+
+var errConflictAborted = errors.New("aborted: live edits conflict with the chart")
+
+// conflictResolution is the outcome of the interactive conflict review: --set and --set-string
+// values that keep the live value of every keep-live choice.
+type conflictResolution struct {
+	Keep            []deploy.LiveConflict
+	SetValues       []string
+	SetStringValues []string
+}
+
+// findApplyConflicts renders opts and returns the live edits the render would overwrite, with
+// values suggestions filled in. A release that was never installed has no conflicts.
+func findApplyConflicts(ctx context.Context, actionCfg *action.Configuration, settings *cli.EnvSettings, kubeClient *kube.Client, opts deploy.InstallOptions) ([]deploy.LiveConflict, error) {
+	current, err := action.NewGet(actionCfg).Run(opts.ReleaseName)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read current release: %w", err)
+	}
+	previewOpts := opts
+	previewOpts.DryRun = true
+	previewOpts.Wait = false
+	previewOpts.Atomic = false
+	previewOpts.Diff = false
+	preview, err := deploy.InstallOrUpgrade(ctx, actionCfg, settings, previewOpts)
+	if err != nil {
+		return nil, fmt.Errorf("render desired: %w", err)
+	}
+	if preview == nil || preview.Release == nil {
+		return nil, nil
+	}
+	conflicts, err := deploy.FindLiveConflicts(ctx, opts.ReleaseName, current.Manifest, preview.Release.Manifest, deploy.DriftLiveGetterFromKube(kubeClient))
+	if err != nil {
+		return nil, err
+	}
+	values := preview.Release.Config
+	if preview.Release.Chart != nil {
+		if coalesced, err := chartutil.CoalesceValues(preview.Release.Chart, preview.Release.Config); err == nil {
+			values = coalesced
+		}
+	}
+	deploy.SuggestConflictValues(conflicts, values)
+	return conflicts, nil
+}
+
+// resolveConflicts asks for keep-live, take-chart, or abort on every conflict. keep-live is only
+// offered when a values key renders the field.
+func resolveConflicts(reader *bufio.Reader, w io.Writer, conflicts []deploy.LiveConflict) (*conflictResolution, error) {
+	res := &conflictResolution{}
+	if len(conflicts) == 0 {
+		return res, nil
+	}
+	fmt.Fprintf(w, "%d field(s) were edited in the cluster and would be overwritten by this apply.\n", len(conflicts))
+	for i, c := range conflicts {
+		fmt.Fprintf(w, "\nConflict %d/%d: %s (ns: %s) %s\n", i+1, len(conflicts), c.Resource(), orDash(c.Namespace), c.Field)
+		fmt.Fprintf(w, "  last applied: %v\n", c.LastApplied)
+		fmt.Fprintf(w, "  live:         %v\n", c.Live)
+		fmt.Fprintf(w, "  chart:        %v\n", c.Desired)
+		choices := []string{conflictTakeChart, conflictAbort}
+		if arg := c.SetArg(); arg != "" {
+			fmt.Fprintf(w, "  keep-live sets %s\n", arg)
+			choices = []string{conflictKeepLive, conflictTakeChart, conflictAbort}
+		} else {
+			fmt.Fprintln(w, "  keep-live unavailable: no values key renders this field")
+		}
+		choice, err := promptChoice(reader, w, "Resolve ("+strings.Join(choices, "|")+")", choices, "")
+		if err != nil {
+			return nil, err
+		}
+		switch choice {
+		case conflictAbort:
+			return nil, errConflictAborted
+		case conflictKeepLive:
+			res.Keep = append(res.Keep, c)
+			if c.SetString() {
+				res.SetStringValues = append(res.SetStringValues, c.SetArg())
+			} else {
+				res.SetValues = append(res.SetValues, c.SetArg())
+			}
+		}
+	}
+	return res, nil
+}
+
+// reportConflictResolution prints the values that persist the keep-live choices and writes them
+// to patchPath when set.
+func reportConflictResolution(w io.Writer, res *conflictResolution, patchPath string) error {
+	if res == nil || len(res.Keep) == 0 {
+		return nil
+	}
+	patch, err := deploy.ConflictValuesPatch(res.Keep)
+	if err != nil {
+		return err
+	}
+	raw, err := yaml.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nKeeping live values for this apply. To keep them in later applies, add to your values:")
+	for _, line := range strings.Split(strings.TrimRight(string(raw), "\n"), "\n") {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+	var flags []string
+	for _, v := range res.SetValues {
+		flags = append(flags, "--set "+shellQuote(v))
+	}
+	for _, v := range res.SetStringValues {
+		flags = append(flags, "--set-string "+shellQuote(v))
+	}
+	fmt.Fprintf(w, "or pass: %s\n", strings.Join(flags, " "))
+	if path := strings.TrimSpace(patchPath); path != "" {
+		if err := os.WriteFile(path, raw, 0o600); err != nil {
+			return fmt.Errorf("write conflict values: %w", err)
+		}
+		fmt.Fprintf(w, "Wrote values patch to %s\n", path)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+)
+
+func testConflicts() []deploy.LiveConflict {
+	return []deploy.LiveConflict{
+		{Kind: "Deployment", Namespace: "prod", Name: "api", Field: "spec.replicas", LastApplied: int64(2), Live: int64(5), Desired: int64(2), ValuesPath: "replicaCount", ValuesLive: int64(5)},
+		{Kind: "Deployment", Namespace: "prod", Name: "api", Field: "spec.template.spec.containers[api].image", LastApplied: "api:v1", Live: "api:v1-hotfix", Desired: "api:v2", ValuesPath: "image.tag", ValuesLive: "v1-hotfix"},
+		{Kind: "Service", Namespace: "prod", Name: "api", Field: "spec.sessionAffinity", LastApplied: "None", Live: "ClientIP", Desired: "None"},
+	}
+}
+
+func TestResolveConflictsCollectsKeepLiveValues(t *testing.T) {
+	var out bytes.Buffer
+	in := bufio.NewReader(strings.NewReader("keep-live\nkeep-live\ntake-chart\n"))
+	res, err := resolveConflicts(in, &out, testConflicts())
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if !reflect.DeepEqual(res.SetValues, []string{"replicaCount=5"}) || !reflect.DeepEqual(res.SetStringValues, []string{"image.tag=v1-hotfix"}) {
+		t.Fatalf("set = %v, set-string = %v", res.SetValues, res.SetStringValues)
+	}
+	if !strings.Contains(out.String(), "keep-live unavailable") {
+		t.Fatalf("expected the Service field to explain why keep-live is missing:\n%s", out.String())
+	}
+
+	out.Reset()
+	patchPath := filepath.Join(t.TempDir(), "keep.yaml")
+	if err := reportConflictResolution(&out, res, patchPath); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if !strings.Contains(out.String(), "--set 'replicaCount=5' --set-string 'image.tag=v1-hotfix'") {
+		t.Fatalf("expected flag suggestions:\n%s", out.String())
+	}
+	raw, err := os.ReadFile(patchPath)
+	if err != nil {
+		t.Fatalf("read patch: %v", err)
+	}
+	if string(raw) != "image:\n  tag: v1-hotfix\nreplicaCount: 5\n" {
+		t.Fatalf("patch = %q", raw)
+	}
+}
+
+func TestResolveConflictsAbort(t *testing.T) {
+	in := bufio.NewReader(strings.NewReader("take-chart\nabort\n"))
+	if _, err := resolveConflicts(in, &bytes.Buffer{}, testConflicts()); !errors.Is(err, errConflictAborted) {
+		t.Fatalf("expected abort, got %v", err)
+	}
+	// keep-live is not offered for a field no values key renders.
+	in = bufio.NewReader(strings.NewReader("take-chart\ntake-chart\nkeep-live\nkeep-live\nkeep-live\n"))
+	if _, err := resolveConflicts(in, &bytes.Buffer{}, testConflicts()); err == nil {
+		t.Fatalf("expected keep-live to be rejected for the Service field")
+	}
+}
@@ -0,0 +1,63 @@
+// File: cmd/ktl/deploy_gates.go
+// Brief: CLI command wiring and implementation for 'deploy gates'.
+
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/spf13/cobra"
+)
+
+// deployGateFlags are the deploy-window and approval flags of single-release deploys outside
+// ktl apply, such as ktl promote and ktl bundle apply.
+type deployGateFlags struct {
+	approvalPolicy string
+	approvalToken  string
+	overrideWindow bool
+	overrideReason string
+}
+
+func addDeployGateFlags(cmd *cobra.Command, f *deployGateFlags) {
+	cmd.Flags().StringVar(&f.approvalPolicy, "approval-policy", deploy.ApprovalPolicyNone, "Require a second approver's token (from ktl approve) before applying: none, risky (plans with risky changes), or always (deploy.approvalPolicy in .ktl.yaml is the minimum)")
+	cmd.Flags().StringVar(&f.approvalToken, "approval-token", "", "Approval token from 'ktl approve <plan-digest>' run by someone else (or set "+approvalTokenEnv+")")
+	cmd.Flags().BoolVar(&f.overrideWindow, "override-window", false, "Apply outside the deploy window configured under deploy.windows in .ktl.yaml (requires --reason; recorded in the audit log)")
+	cmd.Flags().StringVar(&f.overrideReason, "reason", "", "Why the deploy window is overridden (with --override-window)")
+}
+
+// validate checks the flags before anything is rendered.
+func (f deployGateFlags) validate() error {
+	if _, err := deploy.ParseApprovalPolicy(f.approvalPolicy); err != nil {
+		return err
+	}
+	return validateWindowOverride(f.overrideWindow, f.overrideReason)
+}
+
+// enforceDeployGates runs the checks ktl apply runs before changing a release: the deploy.windows
+// covering target, then the approval policy (at least the repo's deploy.approvalPolicy) for the
+// previewed plan. dirs add the repos around them to the policy lookup, like a stack root.
+func enforceDeployGates(cmd *cobra.Command, errOut io.Writer, f deployGateFlags, target windowTarget, release string, preview *deploy.InstallResult, dirs ...string) error {
+	ctx := cmd.Context()
+	deployCfg, err := loadDeployConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if err := enforceDeployWindows(cmd, errOut, deployCfg.Windows, []windowTarget{target}, f.overrideWindow, f.overrideReason, time.Now()); err != nil {
+		return err
+	}
+	repoPolicy, approvers, err := repoApprovalPolicy(ctx, dirs...)
+	if err != nil {
+		return err
+	}
+	policy, _ := deploy.ParseApprovalPolicy(f.approvalPolicy)
+	policy = deploy.StricterApprovalPolicy(policy, repoPolicy)
+	token := strings.TrimSpace(f.approvalToken)
+	if token == "" {
+		token = strings.TrimSpace(os.Getenv(approvalTokenEnv))
+	}
+	return enforceApprovalPolicy(cmd, errOut, policy, token, approvers, currentApprovalApplier(), release, target.Namespace, preview)
+}
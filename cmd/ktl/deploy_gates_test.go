@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestEnforceDeployGatesAppliesRepoPolicyAndWindows(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(approvalTokenEnv, "")
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	repo := t.TempDir()
+	writeRepoConfig := func(extra string) {
+		t.Helper()
+		cfg := fmt.Sprintf("deploy:\n  approvalPolicy: always\n  approvers:\n    - name: reviewer\n      publicKey: %s\n%s", base64.StdEncoding.EncodeToString(pub), extra)
+		if err := os.WriteFile(filepath.Join(repo, ".ktl.yaml"), []byte(cfg), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRepoConfig("")
+	t.Chdir(t.TempDir())
+
+	root := newRootCommand()
+	promote, _, err := root.Find([]string{"promote"})
+	if err != nil {
+		t.Fatalf("find promote: %v", err)
+	}
+	preview := &deploy.InstallResult{Release: &release.Release{Manifest: "kind: Deployment\n"}, PlanSummary: &deploy.PlanSummary{}}
+	target := windowTarget{Context: "prod", Namespace: "shop"}
+	var errOut bytes.Buffer
+
+	// The stack's repo policy applies even though --approval-policy is left at none.
+	err = enforceDeployGates(promote, &errOut, deployGateFlags{}, target, "api", preview, repo)
+	if err == nil || !strings.Contains(err.Error(), "requires a second approver") {
+		t.Fatalf("expected the repo policy to require a token, got %v", err)
+	}
+
+	now := time.Now().UTC()
+	token, err := deploy.SignApproval(deploy.Approval{PlanDigest: deploy.PlanDigest("api", "shop", preview.Release.Manifest), IssuedAt: now, ExpiresAt: now.Add(time.Hour)}, priv)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if err := enforceDeployGates(promote, &errOut, deployGateFlags{approvalToken: token}, target, "api", preview, repo); err != nil {
+		t.Fatalf("expected the approved promotion to pass: %v", err)
+	}
+	if entry := buildAuditEntry(promote, now, nil); entry.Approval == nil || entry.Approval.Approver != "reviewer" {
+		t.Fatalf("expected the approval in the audit entry, got %+v", entry.Approval)
+	}
+
+	// A window that never opens blocks the target before the token is looked at.
+	t.Chdir(repo)
+	writeRepoConfig("  windows:\n    - name: never\n      contexts: [prod]\n      schedule: \"* * 31 2 *\"\n")
+	err = enforceDeployGates(promote, &errOut, deployGateFlags{approvalToken: token}, target, "api", preview)
+	if err == nil || !strings.Contains(err.Error(), "outside the deploy window") {
+		t.Fatalf("expected the deploy window to block promote, got %v", err)
+	}
+}
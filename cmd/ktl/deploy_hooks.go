@@ -0,0 +1,88 @@
+// File: cmd/ktl/deploy_hooks.go
+// Brief: CLI command wiring and implementation for 'deploy hooks'.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/ui"
+	"k8s.io/client-go/kubernetes"
+)
+
+// hookFeed is where hook progress of ktl apply goes: state changes to every progress observer
+// (console, web UI stream, capture), failure logs only to the stream and, without a console, to
+// the terminal.
+type hookFeed struct {
+	observers []deploy.ProgressObserver
+	stream    deploy.ProgressObserver
+	plain     io.Writer
+}
+
+func (f hookFeed) report(prev, next deploy.HookStatus) {
+	level, msg := "info", ""
+	switch next.State {
+	case deploy.HookRunning:
+		msg = fmt.Sprintf("Hook %s %s running", next.Event, next.Label())
+	case deploy.HookSucceeded:
+		msg = fmt.Sprintf("Hook %s %s succeeded", next.Event, next.Label())
+		if next.Duration > 0 {
+			msg += " in " + next.Duration.String()
+		}
+	case deploy.HookFailed:
+		level = "error"
+		msg = fmt.Sprintf("Hook %s %s failed: %s", next.Event, next.Label(), next.Message)
+	default:
+		return
+	}
+	for _, obs := range f.observers {
+		if obs != nil {
+			obs.EmitEvent(level, msg)
+		}
+	}
+	if next.State != deploy.HookFailed {
+		return
+	}
+	if f.plain != nil {
+		fmt.Fprintln(f.plain, msg)
+	}
+	for _, line := range next.Logs {
+		if f.stream != nil {
+			f.stream.EmitEvent("error", next.Label()+" | "+line)
+		}
+		if f.plain != nil {
+			fmt.Fprintf(f.plain, "  %s | %s\n", next.Label(), line)
+		}
+	}
+}
+
+// startHookTracker follows the Job/Pod hooks Helm runs for this apply. The returned stop func
+// ends tracking; after a failed apply it polls once more so a hook that just failed still has its
+// logs reported.
+func startHookTracker(ctx context.Context, client kubernetes.Interface, namespace string, steps []deploy.HookStep, hookManifest string, upgrade bool, console *ui.DeployConsole, feed hookFeed) func(failed bool) {
+	var update deploy.HookUpdateFunc
+	if console != nil {
+		update = console.UpdateHooks
+	}
+	tracker := deploy.NewHookTracker(client, namespace, steps, hookManifest, deploy.HookEventsFor(upgrade), update).OnChange(feed.report)
+	if tracker.Empty() || client == nil {
+		return func(bool) {}
+	}
+	tracker.Prime(ctx)
+	trackCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tracker.Run(trackCtx)
+	}()
+	return func(failed bool) {
+		cancel()
+		<-done
+		if failed && ctx.Err() == nil && tracker.Poll(ctx) && update != nil {
+			update(tracker.Snapshot())
+		}
+	}
+}
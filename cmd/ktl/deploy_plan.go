@@ -28,16 +28,14 @@ import (
 	"github.com/kubekattle/ktl/internal/secretstore"
 	"github.com/kubekattle/ktl/internal/telemetry"
 	"github.com/kubekattle/ktl/internal/ui"
+	"github.com/kubekattle/ktl/internal/valuesource"
 	"github.com/pmezard/go-difflib/difflib"
 	"github.com/spf13/cobra"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/postrender"
 	"helm.sh/helm/v3/pkg/storage/driver"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/yaml"
 )
 
@@ -51,9 +49,12 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 	var release string
 	var version string
 	var valuesFiles []string
+	var postRender postRenderFlags
 	var setValues []string
 	var setStringValues []string
 	var setFileValues []string
+	var setJSONValues []string
+	var setLiteralValues []string
 	var secretProvider string
 	var secretConfig string
 	var includeCRDs bool
@@ -65,6 +66,9 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 	var compareTo string
 	var compareExit bool
 	var baselinePath string
+	var maxDiffBytes int
+	var allReleases bool
+	var serveAddr string
 	resolvedFormat := ""
 	resolveFormat := func() string {
 		return resolveDeployPlanFormat(format, visualize)
@@ -76,12 +80,29 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 		Long:  "Render the chart, diff it against live cluster resources, and summarize the net creates/updates/deletes before running ktl apply.",
 		Args:  cobra.NoArgs,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if allReleases {
+				return validateNamespacePlanFlags(cmd, format, outputPath)
+			}
+			var missing []string
+			for _, name := range []string{"chart", "release"} {
+				if strings.TrimSpace(cmd.Flags().Lookup(name).Value.String()) == "" {
+					missing = append(missing, fmt.Sprintf("%q", name))
+				}
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("required flag(s) %s not set (or use --all-releases)", strings.Join(missing, ", "))
+			}
 			resolvedFormat = resolveFormat()
 			switch resolvedFormat {
 			case "text", "json", "yaml", "html", "visualize-html", "visualize-json", "visualize-yaml":
 			default:
 				return fmt.Errorf("unsupported format %q (expected text, json, yaml, html, or visualize)", resolvedFormat)
 			}
+			if strings.TrimSpace(serveAddr) != "" {
+				if err := validatePlanServeFlags(cmd, visualize, resolvedFormat, chart, valuesFiles, setFileValues); err != nil {
+					return err
+				}
+			}
 			if resolvedFormat == "text" && strings.TrimSpace(outputPath) != "" {
 				return fmt.Errorf("--output is only supported with --format=html, --format=json, --format=yaml, or --visualize")
 			}
@@ -136,9 +157,12 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 			logFunc := func(format string, v ...interface{}) {
 				fmt.Fprintf(cmd.ErrOrStderr(), format+"\n", v...)
 			}
-			if err := actionCfg.Init(settings.RESTClientGetter(), resolvedNamespace, os.Getenv("HELM_DRIVER"), logFunc); err != nil {
+			if err := actionCfg.Init(kubeClient.HelmRESTClientGetter(settings.RESTClientGetter()), resolvedNamespace, os.Getenv("HELM_DRIVER"), logFunc); err != nil {
 				return fmt.Errorf("init helm action config: %w", err)
 			}
+			if allReleases {
+				return runNamespacePlan(ctx, cmd, actionCfg, kubeClient, resolvedNamespace, format, outputPath)
+			}
 
 			var secretAudit secretstore.AuditReport
 			secretResolver, secretAuditSink, err := buildDeploySecretResolver(ctx, deploySecretConfig{
@@ -157,52 +181,102 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 					secretAuditSink(report)
 				}
 			}
-			secretOptions := &deploy.SecretOptions{Resolver: secretResolver, AuditSink: auditSink, Validate: true}
-
-			stopSpinner := ui.StartSpinner(cmd.ErrOrStderr(), fmt.Sprintf("Planning release %s", release))
-			defer func() {
-				if stopSpinner != nil {
-					stopSpinner(false)
-				}
-			}()
-
-			timer := telemetry.NewPhaseTimer()
-			options := deployPlanOptions{
-				Chart:           chart,
-				Release:         release,
-				Version:         version,
-				Namespace:       resolvedNamespace,
-				ValuesFiles:     valuesFiles,
-				SetValues:       setValues,
-				SetStringValues: setStringValues,
-				SetFileValues:   setFileValues,
-				Secrets:         secretOptions,
-				IncludeCRDs:     includeCRDs,
-			}
-			planResult, err := executeDeployPlan(ctx, actionCfg, settings, kubeClient, options, timer)
+			valueSources, err := valuesource.LoadFromApp(ctx, chart)
 			if err != nil {
 				return err
 			}
-			planResult.Secrets = planSecretsFromAudit(secretAudit)
-			if timer != nil {
-				summary := telemetry.Summary{
-					Total:  timer.Total(),
-					Phases: timer.Snapshot(),
+			secretOptions := &deploy.SecretOptions{Resolver: secretResolver, AuditSink: auditSink, Validate: true, ValueSources: valueSources, Mask: true}
+
+			// runPlan renders and diffs the chart once; --serve calls it again after every change.
+			runPlan := func() (*deployPlanResult, error) {
+				resolvedValues, cleanupValues, err := resolveValuesFlag(cmd, settings, valuesFiles, secretOptions)
+				if err != nil {
+					return nil, err
+				}
+				defer cleanupValues()
+				postRenderer, err := loadPostRenderer(ctx, settings, postRender)
+				if err != nil {
+					return nil, err
 				}
-				if kubeClient != nil && kubeClient.APIStats != nil {
-					metrics := kubeClient.APIStats.Snapshot()
-					summary.KubeRequests = metrics.Count
-					summary.KubeAvg = metrics.Avg()
-					summary.KubeMax = metrics.Max
+
+				setSpinnerStatus, stopSpinner := ui.StartSpinnerWithStatus(cmd.ErrOrStderr(), fmt.Sprintf("Planning release %s", release))
+				defer func() {
+					if stopSpinner != nil {
+						stopSpinner(false)
+					}
+				}()
+
+				timer := telemetry.NewPhaseTimer()
+				options := deployPlanOptions{
+					Chart:            chart,
+					Release:          release,
+					Version:          version,
+					Namespace:        resolvedNamespace,
+					ValuesFiles:      resolvedValues,
+					SetValues:        setValues,
+					SetStringValues:  setStringValues,
+					SetFileValues:    setFileValues,
+					SetJSONValues:    setJSONValues,
+					SetLiteralValues: setLiteralValues,
+					Secrets:          secretOptions,
+					IncludeCRDs:      includeCRDs,
+					MaxDiffBytes:     maxDiffBytes,
+					PostRenderer:     postRenderer,
+					LiveProgress: func(done, total int) {
+						setSpinnerStatus(fmt.Sprintf("live %d/%d", done, total))
+					},
 				}
-				planResult.Telemetry = buildPlanTelemetry(summary)
-				if line := summary.Line(); line != "" {
-					fmt.Fprintln(cmd.ErrOrStderr(), line)
+				planResult, err := executeDeployPlan(ctx, actionCfg, settings, kubeClient, options, timer)
+				if err != nil {
+					return nil, err
+				}
+				printImageReport(cmd.ErrOrStderr(), postRender, postRenderer)
+				// Report the sources as given rather than their temporary copies.
+				options.ValuesFiles = valuesFiles
+				planResult.ValuesFiles = append([]string(nil), valuesFiles...)
+				planResult.InstallCmd = buildInstallCommand(options)
+				planResult.Secrets = planSecretsFromAudit(secretAudit)
+				if timer != nil {
+					summary := telemetry.Summary{
+						Total:  timer.Total(),
+						Phases: timer.Snapshot(),
+					}
+					if kubeClient != nil && kubeClient.APIStats != nil {
+						metrics := kubeClient.APIStats.Snapshot()
+						summary.KubeRequests = metrics.Count
+						summary.KubeAvg = metrics.Avg()
+						summary.KubeMax = metrics.Max
+					}
+					planResult.Telemetry = buildPlanTelemetry(summary)
+					if line := summary.Line(); line != "" {
+						fmt.Fprintln(cmd.ErrOrStderr(), line)
+					}
 				}
-			}
 
-			stopSpinner(true)
-			stopSpinner = nil
+				stopSpinner(true)
+				stopSpinner = nil
+				return planResult, nil
+			}
+			if strings.TrimSpace(serveAddr) != "" {
+				var visualizeCompare *deployPlanResult
+				if strings.TrimSpace(compareSource) != "" {
+					visualizeCompare, err = loadPlanResultFromSource(ctx, compareSource)
+					if err != nil {
+						return fmt.Errorf("load compare artifact: %w", err)
+					}
+				}
+				return servePlanPreview(ctx, cmd.ErrOrStderr(), serveAddr, planWatchPaths(chart, valuesFiles, setFileValues), planPreviewInterval, func() (string, error) {
+					result, err := runPlan()
+					if err != nil {
+						return "", err
+					}
+					return renderDeployVisualizeHTML(result, visualizeCompare, deployVisualizeFeatures{ExplainDiff: visualizeExplain})
+				})
+			}
+			planResult, err := runPlan()
+			if err != nil {
+				return err
+			}
 
 			var compareResult *deployPlanResult
 			if strings.TrimSpace(compareTo) != "" {
@@ -229,6 +303,7 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 				fmt.Fprintf(cmd.ErrOrStderr(), "Baseline written to %s\n", baselinePath)
 			}
 
+			writeToFile := strings.TrimSpace(outputPath) != "" && outputPath != "-"
 			switch selectedFormat {
 			case "html":
 				path := outputPath
@@ -239,10 +314,23 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 					}
 					path = fmt.Sprintf("ktl-deploy-plan-%s-%s.html", slug, planResult.GeneratedAt.Format("20060102-150405"))
 				}
+				// Full diffs are spilled next to the report file; on stdout the report keeps the
+				// truncated diffs only.
+				if path != "-" {
+					if err := writePlanFullDiffs(planResult, path); err != nil {
+						return err
+					}
+				} else {
+					notePlanTruncatedDiffs(cmd.ErrOrStderr(), planResult)
+				}
 				html, err := renderDeployPlanHTML(planResult)
 				if err != nil {
 					return err
 				}
+				if path == "-" {
+					fmt.Fprintln(cmd.OutOrStdout(), html)
+					return nil
+				}
 				if err := os.WriteFile(path, []byte(html), 0o644); err != nil {
 					return fmt.Errorf("write html: %w", err)
 				}
@@ -253,6 +341,13 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 				if path == "" {
 					path = defaultDeployVisualizeOutputPath(release, planResult.GeneratedAt)
 				}
+				if path != "-" {
+					if err := writePlanFullDiffs(planResult, path); err != nil {
+						return err
+					}
+				} else {
+					notePlanTruncatedDiffs(cmd.ErrOrStderr(), planResult)
+				}
 				var visualizeCompare *deployPlanResult
 				if strings.TrimSpace(compareSource) != "" {
 					var cerr error
@@ -290,6 +385,13 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 					}
 					path = defaultDeployVisualizeDataOutputPath(release, planResult.GeneratedAt, ext)
 				}
+				if path != "-" {
+					if err := writePlanFullDiffs(planResult, path); err != nil {
+						return err
+					}
+				} else {
+					notePlanTruncatedDiffs(cmd.ErrOrStderr(), planResult)
+				}
 				var visualizeCompare *deployPlanResult
 				if strings.TrimSpace(compareSource) != "" {
 					var cerr error
@@ -329,11 +431,18 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 				fmt.Fprintf(cmd.OutOrStdout(), "Visualization data written to %s\n", path)
 				return nil
 			case "json":
+				if writeToFile {
+					if err := writePlanFullDiffs(planResult, outputPath); err != nil {
+						return err
+					}
+				} else {
+					notePlanTruncatedDiffs(cmd.ErrOrStderr(), planResult)
+				}
 				data, err := json.MarshalIndent(planResult, "", "  ")
 				if err != nil {
 					return fmt.Errorf("marshal plan json: %w", err)
 				}
-				if strings.TrimSpace(outputPath) != "" {
+				if writeToFile {
 					if err := os.WriteFile(outputPath, data, 0o644); err != nil {
 						return fmt.Errorf("write json: %w", err)
 					}
@@ -343,11 +452,18 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 				}
 				return nil
 			case "yaml":
+				if writeToFile {
+					if err := writePlanFullDiffs(planResult, outputPath); err != nil {
+						return err
+					}
+				} else {
+					notePlanTruncatedDiffs(cmd.ErrOrStderr(), planResult)
+				}
 				data, err := yaml.Marshal(planResult)
 				if err != nil {
 					return fmt.Errorf("marshal plan yaml: %w", err)
 				}
-				if strings.TrimSpace(outputPath) != "" {
+				if writeToFile {
 					if err := os.WriteFile(outputPath, data, 0o644); err != nil {
 						return fmt.Errorf("write yaml: %w", err)
 					}
@@ -357,7 +473,20 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 				}
 				return nil
 			default:
-				renderDeployPlan(cmd.OutOrStdout(), planResult)
+				if !writeToFile {
+					notePlanTruncatedDiffs(cmd.ErrOrStderr(), planResult)
+					renderDeployPlan(cmd.OutOrStdout(), planResult)
+					return nil
+				}
+				if err := writePlanFullDiffs(planResult, outputPath); err != nil {
+					return err
+				}
+				var buf bytes.Buffer
+				renderDeployPlan(&buf, planResult)
+				if err := os.WriteFile(outputPath, buf.Bytes(), 0o644); err != nil {
+					return fmt.Errorf("write plan: %w", err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Plan written to %s\n", outputPath)
 				return nil
 			}
 		},
@@ -366,10 +495,13 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 	cmd.Flags().StringVar(&chart, "chart", "", "Chart reference (path, repo/name, or OCI ref)")
 	cmd.Flags().StringVar(&release, "release", "", "Helm release name")
 	cmd.Flags().StringVar(&version, "version", "", "Chart version (default: latest)")
-	cmd.Flags().StringSliceVarP(&valuesFiles, "values", "f", nil, "Values files to apply (can be repeated)")
+	cmd.Flags().StringSliceVarP(&valuesFiles, "values", "f", nil, "Values files to apply (can be repeated; - reads stdin, https:// and oci:// are fetched, append #sha256=<hex> to pin)")
+	addPostRenderFlags(cmd, &postRender)
 	cmd.Flags().StringArrayVar(&setValues, "set", nil, "Set values on the command line (key=val)")
 	cmd.Flags().StringArrayVar(&setStringValues, "set-string", nil, "Set STRING values on the command line")
 	cmd.Flags().StringArrayVar(&setFileValues, "set-file", nil, "Set values from files (key=path)")
+	cmd.Flags().StringArrayVar(&setJSONValues, "set-json", nil, "Set JSON values on the command line (key=<json>)")
+	cmd.Flags().StringArrayVar(&setLiteralValues, "set-literal", nil, "Set a literal STRING value on the command line (no escaping or list parsing)")
 	cmd.Flags().StringVar(&secretProvider, "secret-provider", "", "Secret provider name for secret:// references")
 	cmd.Flags().StringVar(&secretConfig, "secret-config", "", "Secrets provider config file (defaults to ~/.ktl/config.yaml and repo .ktl.yaml)")
 	cmd.Flags().BoolVar(&includeCRDs, "include-crds", false, "Render CRDs in addition to the main chart objects")
@@ -377,12 +509,13 @@ func newDeployPlanCommand(namespace *string, kubeconfig *string, kubeContext *st
 	cmd.Flags().StringVar(&compareTo, "compare-to", "", "Compare against a previous plan (path or URL) and report regressions")
 	cmd.Flags().BoolVar(&compareExit, "compare-exit", true, "Exit non-zero when --compare-to detects regressions")
 	cmd.Flags().StringVar(&baselinePath, "baseline", "", "Write plan JSON baseline to this path")
+	cmd.Flags().IntVar(&maxDiffBytes, "max-diff-bytes", defaultPlanDiffMaxBytes, "Truncate per-resource diffs larger than this (0 keeps full diffs); plans written with --output link the full diffs")
 	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json, yaml, or html")
-	cmd.Flags().StringVar(&outputPath, "output", "", "Write the rendered plan to this path (HTML defaults to ./ktl-deploy-plan-<release>-<timestamp>.html)")
+	cmd.Flags().StringVar(&outputPath, "output", "", "Write the rendered plan to this path (HTML defaults to ./ktl-deploy-plan-<release>-<timestamp>.html; - writes to stdout)")
 	cmd.Flags().BoolVar(&visualize, "visualize", false, "Render the interactive visualization")
 	cmd.Flags().BoolVar(&visualizeExplain, "visualize-explain", false, "Add an Explain Diff tab in --visualize output (experimental)")
-	_ = cmd.MarkFlagRequired("chart")
-	_ = cmd.MarkFlagRequired("release")
+	cmd.Flags().StringVar(&serveAddr, "serve", "", "Serve the --visualize output on this address (e.g. :8088) and re-plan when the chart or values files change")
+	cmd.Flags().BoolVar(&allReleases, "all-releases", false, "Re-render every deployed release in the namespace from its stored chart and values and report drift from live state (replaces --chart/--release)")
 
 	if ownNamespaceFlag {
 		cmd.Flags().StringVarP(namespace, "namespace", "n", "", "Namespace for the Helm release (defaults to active context)")
@@ -418,48 +551,67 @@ func resolveDeployPlanFormat(format string, visualize bool) string {
 }
 
 type deployPlanOptions struct {
-	Chart           string
-	Release         string
-	Version         string
-	Namespace       string
-	ValuesFiles     []string
-	SetValues       []string
-	SetStringValues []string
-	SetFileValues   []string
-	Secrets         *deploy.SecretOptions
-	IncludeCRDs     bool
+	Chart            string
+	Release          string
+	Version          string
+	Namespace        string
+	ValuesFiles      []string
+	SetValues        []string
+	SetStringValues  []string
+	SetFileValues    []string
+	SetJSONValues    []string
+	SetLiteralValues []string
+	Secrets          *deploy.SecretOptions
+	IncludeCRDs      bool
+	// MaxDiffBytes caps each resource diff kept inline (0 disables the cap).
+	MaxDiffBytes int
+	// LiveProgress, when set, reports live lookup progress.
+	LiveProgress liveLookupProgress
+	// PostRenderer runs the same post-render pipeline as apply.
+	PostRenderer postrender.PostRenderer
 }
 
 type deployPlanResult struct {
-	ReleaseName       string                  `json:"release"`
-	Namespace         string                  `json:"namespace"`
-	ChartVersion      string                  `json:"chartVersion,omitempty"`
-	ChartRef          string                  `json:"chartReference,omitempty"`
-	RequestedChart    string                  `json:"requestedChart,omitempty"`
-	RequestedVersion  string                  `json:"requestedVersion,omitempty"`
-	ValuesFiles       []string                `json:"valuesFiles,omitempty"`
-	SetValues         []string                `json:"setValues,omitempty"`
-	SetStringValues   []string                `json:"setStringValues,omitempty"`
-	SetFileValues     []string                `json:"setFileValues,omitempty"`
-	Secrets           []planSecretRef         `json:"secrets,omitempty"`
-	GraphNodes        []deployGraphNode       `json:"graphNodes,omitempty"`
-	GraphEdges        []deployGraphEdge       `json:"graphEdges,omitempty"`
-	ManifestBlobs     map[string]string       `json:"manifestBlobs,omitempty"`
-	LiveManifests     map[string]string       `json:"liveManifestBlobs,omitempty"`
-	ManifestDiffs     map[string]string       `json:"manifestDiffs,omitempty"`
-	ManifestTemplates map[string]string       `json:"manifestTemplates,omitempty"`
-	TemplateSources   map[string]string       `json:"templateSources,omitempty"`
-	Changes           []planResourceChange    `json:"changes"`
-	Summary           planSummary             `json:"summary"`
-	Warnings          []string                `json:"warnings,omitempty"`
-	DesiredQuota      *quotaReport            `json:"desiredQuota,omitempty"`
-	DesiredQuotaByNS  map[string]*quotaReport `json:"desiredQuotaByNamespace,omitempty"`
-	ClusterHost       string                  `json:"clusterHost,omitempty"`
-	InstallCmd        string                  `json:"installCommand,omitempty"`
-	GeneratedAt       time.Time               `json:"generatedAt"`
-	OfflineFallback   bool                    `json:"offlineFallback"`
-	Compare           *planCompare            `json:"compare,omitempty"`
-	Telemetry         *planTelemetry          `json:"telemetry,omitempty"`
+	ReleaseName       string                   `json:"release"`
+	Namespace         string                   `json:"namespace"`
+	ChartVersion      string                   `json:"chartVersion,omitempty"`
+	ChartRef          string                   `json:"chartReference,omitempty"`
+	RequestedChart    string                   `json:"requestedChart,omitempty"`
+	RequestedVersion  string                   `json:"requestedVersion,omitempty"`
+	ValuesFiles       []string                 `json:"valuesFiles,omitempty"`
+	SetValues         []string                 `json:"setValues,omitempty"`
+	SetStringValues   []string                 `json:"setStringValues,omitempty"`
+	SetFileValues     []string                 `json:"setFileValues,omitempty"`
+	SetJSONValues     []string                 `json:"setJSONValues,omitempty"`
+	SetLiteralValues  []string                 `json:"setLiteralValues,omitempty"`
+	Secrets           []planSecretRef          `json:"secrets,omitempty"`
+	GraphNodes        []deployGraphNode        `json:"graphNodes,omitempty"`
+	GraphEdges        []deployGraphEdge        `json:"graphEdges,omitempty"`
+	ManifestBlobs     planBlobs                `json:"manifestBlobs,omitempty"`
+	LiveManifests     planBlobs                `json:"liveManifestBlobs,omitempty"`
+	ManifestDiffs     map[string]string        `json:"manifestDiffs,omitempty"`
+	ManifestDiffFiles map[string]string        `json:"manifestDiffFiles,omitempty"`
+	ManifestTemplates map[string]string        `json:"manifestTemplates,omitempty"`
+	TemplateSources   map[string]string        `json:"templateSources,omitempty"`
+	Values            []deploy.ValueProvenance `json:"values,omitempty"`
+	Notes             string                   `json:"notes,omitempty"`
+	Hooks             []deploy.HookStep        `json:"hooks,omitempty"`
+	Changes           []planResourceChange     `json:"changes"`
+	Summary           planSummary              `json:"summary"`
+	Warnings          []string                 `json:"warnings,omitempty"`
+	DesiredQuota      *quotaReport             `json:"desiredQuota,omitempty"`
+	DesiredQuotaByNS  map[string]*quotaReport  `json:"desiredQuotaByNamespace,omitempty"`
+	ClusterHost       string                   `json:"clusterHost,omitempty"`
+	InstallCmd        string                   `json:"installCommand,omitempty"`
+	GeneratedAt       time.Time                `json:"generatedAt"`
+	OfflineFallback   bool                     `json:"offlineFallback"`
+	Compare           *planCompare             `json:"compare,omitempty"`
+	Telemetry         *planTelemetry           `json:"telemetry,omitempty"`
+
+	// fullDiffs holds the compressed full text of diffs truncated in Changes.
+	fullDiffs *planDiffArchive
+	// fullManifestDiffs holds the compressed full text of diffs truncated in ManifestDiffs.
+	fullManifestDiffs *planDiffArchive
 }
 
 type planChangeKind string
@@ -471,9 +623,13 @@ const (
 )
 
 type planResourceChange struct {
-	Key  resourceKey    `json:"resource"`
-	Kind planChangeKind `json:"change"`
-	Diff string         `json:"diff,omitempty"`
+	Key           resourceKey    `json:"resource"`
+	Kind          planChangeKind `json:"change"`
+	Diff          string         `json:"diff,omitempty"`
+	DiffTruncated bool           `json:"diffTruncated,omitempty"`
+	FullDiffPath  string         `json:"fullDiffPath,omitempty"`
+	// Risks tags the change for the "Requires extra approval" section.
+	Risks []deploy.PlanRisk `json:"risks,omitempty"`
 }
 
 type deployGraphNode struct {
@@ -497,6 +653,8 @@ type planSummary struct {
 	Updates   int `json:"updates"`
 	Deletes   int `json:"deletes"`
 	Unchanged int `json:"unchanged"`
+	// Risky counts changes with at least one risk tag.
+	Risky int `json:"risky,omitempty"`
 }
 
 type planSecretRef struct {
@@ -817,16 +975,20 @@ func executeDeployPlan(ctx context.Context, actionCfg *action.Configuration, set
 	if err := trackPlanPhase(timer, "render", func() error {
 		var err error
 		templateResult, err = deploy.RenderTemplate(ctx, actionCfg, settings, deploy.TemplateOptions{
-			Chart:           opts.Chart,
-			Version:         opts.Version,
-			ReleaseName:     opts.Release,
-			Namespace:       opts.Namespace,
-			ValuesFiles:     opts.ValuesFiles,
-			SetValues:       opts.SetValues,
-			SetStringValues: opts.SetStringValues,
-			SetFileValues:   opts.SetFileValues,
-			Secrets:         opts.Secrets,
-			IncludeCRDs:     opts.IncludeCRDs,
+			Chart:            opts.Chart,
+			Version:          opts.Version,
+			ReleaseName:      opts.Release,
+			Namespace:        opts.Namespace,
+			ValuesFiles:      opts.ValuesFiles,
+			SetValues:        opts.SetValues,
+			SetStringValues:  opts.SetStringValues,
+			SetFileValues:    opts.SetFileValues,
+			SetJSONValues:    opts.SetJSONValues,
+			SetLiteralValues: opts.SetLiteralValues,
+			Secrets:          opts.Secrets,
+			IncludeCRDs:      opts.IncludeCRDs,
+			ValueProvenance:  true,
+			PostRenderer:     opts.PostRenderer,
 		})
 		return err
 	}); err != nil {
@@ -859,7 +1021,7 @@ func executeDeployPlan(ctx context.Context, actionCfg *action.Configuration, set
 	var lookupWarnings []string
 	err := trackPlanPhase(timer, "live", func() error {
 		var err error
-		liveState, lookupWarnings, err = collectLiveResources(ctx, kubeClient, desiredDocs, opts.Namespace)
+		liveState, lookupWarnings, err = collectLiveResources(ctx, kubeClient, desiredDocs, opts.Namespace, opts.LiveProgress)
 		return err
 	})
 	offlineFallback := false
@@ -874,17 +1036,19 @@ func executeDeployPlan(ctx context.Context, actionCfg *action.Configuration, set
 		summary           planSummary
 		graphNodes        []deployGraphNode
 		graphEdges        []deployGraphEdge
-		manifestBlobs     map[string]string
-		liveManifestBlobs map[string]string
+		manifestBlobs     planBlobs
+		liveManifestBlobs planBlobs
 		manifestDiffs     map[string]string
 		warnings          []string
+		fullDiffs         = newPlanDiffArchive(opts.MaxDiffBytes)
+		fullManifestDiffs = newPlanDiffArchive(opts.MaxDiffBytes)
 	)
 	trackPlanPhaseFunc(timer, "diff", func() {
-		changes, summary = buildPlanChanges(desiredDocs, previousDocs, liveState)
+		changes, summary = buildPlanChanges(desiredDocs, previousDocs, liveState, fullDiffs)
 		graphNodes, graphEdges = buildDependencyGraph(desiredDocs, liveState)
 		manifestBlobs = buildManifestBlobs(desiredDocs)
 		liveManifestBlobs = buildLiveManifestBlobs(liveState)
-		manifestDiffs = buildManifestDiffs(liveManifestBlobs, manifestBlobs)
+		manifestDiffs = buildManifestDiffs(liveManifestBlobs, manifestBlobs, fullManifestDiffs)
 		warnings = append([]string{}, lookupWarnings...)
 		warnings = append(warnings, planWarnings(changes)...)
 	})
@@ -945,6 +1109,8 @@ func executeDeployPlan(ctx context.Context, actionCfg *action.Configuration, set
 		SetValues:         append([]string(nil), opts.SetValues...),
 		SetStringValues:   append([]string(nil), opts.SetStringValues...),
 		SetFileValues:     append([]string(nil), opts.SetFileValues...),
+		SetJSONValues:     append([]string(nil), opts.SetJSONValues...),
+		SetLiteralValues:  append([]string(nil), opts.SetLiteralValues...),
 		GraphNodes:        graphNodes,
 		GraphEdges:        graphEdges,
 		ManifestBlobs:     manifestBlobs,
@@ -952,6 +1118,9 @@ func executeDeployPlan(ctx context.Context, actionCfg *action.Configuration, set
 		ManifestDiffs:     manifestDiffs,
 		ManifestTemplates: manifestTemplates,
 		TemplateSources:   templateResult.Templates,
+		Values:            templateResult.Values,
+		Notes:             templateResult.Notes,
+		Hooks:             templateResult.Hooks,
 		Changes:           changes,
 		Summary:           summary,
 		Warnings:          warnings,
@@ -961,65 +1130,11 @@ func executeDeployPlan(ctx context.Context, actionCfg *action.Configuration, set
 		InstallCmd:        buildInstallCommand(opts),
 		GeneratedAt:       time.Now().UTC(),
 		OfflineFallback:   offlineFallback,
+		fullDiffs:         fullDiffs,
+		fullManifestDiffs: fullManifestDiffs,
 	}, nil
 }
 
-func collectLiveResources(ctx context.Context, kubeClient *kube.Client, desired map[resourceKey]manifestDoc, defaultNamespace string) (map[resourceKey]*unstructured.Unstructured, []string, error) {
-	if kubeClient == nil || kubeClient.Dynamic == nil || kubeClient.RESTMapper == nil {
-		return nil, nil, fmt.Errorf("kubernetes client is not initialized")
-	}
-	live := make(map[resourceKey]*unstructured.Unstructured, len(desired))
-	var warnings []string
-	for key, doc := range desired {
-		res, warn, err := fetchLiveResource(ctx, kubeClient, doc.Obj, defaultNamespace)
-		if err != nil {
-			return nil, nil, fmt.Errorf("fetch %s: %w", key.String(), err)
-		}
-		if warn != "" {
-			warnings = append(warnings, warn)
-		}
-		live[key] = res
-	}
-	return live, warnings, nil
-}
-
-func fetchLiveResource(ctx context.Context, kubeClient *kube.Client, obj *unstructured.Unstructured, defaultNamespace string) (*unstructured.Unstructured, string, error) {
-	if obj == nil {
-		return nil, "", nil
-	}
-	gvk := schema.FromAPIVersionAndKind(obj.GetAPIVersion(), obj.GetKind())
-	mapping, err := kubeClient.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
-	if err != nil {
-		if meta.IsNoMatchError(err) {
-			return nil, fmt.Sprintf("Skipping live lookup for %s: %v", obj.GetName(), err), nil
-		}
-		return nil, "", err
-	}
-
-	resource := kubeClient.Dynamic.Resource(mapping.Resource)
-	namespace := obj.GetNamespace()
-	if namespace == "" && mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-		namespace = defaultNamespace
-	}
-
-	var live *unstructured.Unstructured
-	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
-		if namespace == "" {
-			namespace = "default"
-		}
-		live, err = resource.Namespace(namespace).Get(ctx, obj.GetName(), metav1.GetOptions{})
-	} else {
-		live, err = resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
-	}
-	if apierrors.IsNotFound(err) {
-		return nil, "", nil
-	}
-	if err != nil {
-		return nil, "", err
-	}
-	return live, "", nil
-}
-
 func docsToMap(docs []manifestDoc) map[resourceKey]manifestDoc {
 	result := make(map[resourceKey]manifestDoc, len(docs))
 	for _, doc := range docs {
@@ -1031,12 +1146,18 @@ func docsToMap(docs []manifestDoc) map[resourceKey]manifestDoc {
 	return result
 }
 
-func buildPlanChanges(desired map[resourceKey]manifestDoc, previous map[resourceKey]manifestDoc, live map[resourceKey]*unstructured.Unstructured) ([]planResourceChange, planSummary) {
+// buildPlanChanges diffs desired against live (or previous) state. Diffs larger than the
+// archive's cap are truncated inline and kept compressed in archive (nil keeps full diffs).
+func buildPlanChanges(desired map[resourceKey]manifestDoc, previous map[resourceKey]manifestDoc, live map[resourceKey]*unstructured.Unstructured, archive *planDiffArchive) ([]planResourceChange, planSummary) {
 	if live == nil {
 		live = map[resourceKey]*unstructured.Unstructured{}
 	}
 	changes := make([]planResourceChange, 0, len(desired))
 	summary := planSummary{}
+	newChange := func(key resourceKey, kind planChangeKind, diff string) planResourceChange {
+		diff, truncated := archive.limit(graphNodeID(key), diff)
+		return planResourceChange{Key: key, Kind: kind, Diff: diff, DiffTruncated: truncated}
+	}
 
 	for key, doc := range desired {
 		liveObj := live[key]
@@ -1048,7 +1169,9 @@ func buildPlanChanges(desired map[resourceKey]manifestDoc, previous map[resource
 		desiredStr := objectYAML(doc.Obj)
 		if liveObj == nil {
 			summary.Creates++
-			changes = append(changes, planResourceChange{Key: key, Kind: changeCreate, Diff: diffStrings("", desiredStr)})
+			ch := newChange(key, changeCreate, diffStrings("", desiredStr))
+			ch.Risks = deploy.ClassifyRisks(nil, doc.Obj)
+			changes = append(changes, ch)
 			continue
 		}
 		liveStr := objectYAML(liveObj)
@@ -1057,7 +1180,9 @@ func buildPlanChanges(desired map[resourceKey]manifestDoc, previous map[resource
 			continue
 		}
 		summary.Updates++
-		changes = append(changes, planResourceChange{Key: key, Kind: changeUpdate, Diff: diffStrings(liveStr, desiredStr)})
+		ch := newChange(key, changeUpdate, diffStrings(liveStr, desiredStr))
+		ch.Risks = deploy.ClassifyRisks(liveObj, doc.Obj)
+		changes = append(changes, ch)
 	}
 
 	for key, doc := range previous {
@@ -1065,7 +1190,14 @@ func buildPlanChanges(desired map[resourceKey]manifestDoc, previous map[resource
 			continue
 		}
 		summary.Deletes++
-		changes = append(changes, planResourceChange{Key: key, Kind: changeDelete, Diff: diffStrings(objectYAML(doc.Obj), "")})
+		ch := newChange(key, changeDelete, diffStrings(objectYAML(doc.Obj), ""))
+		ch.Risks = deploy.ClassifyRisks(doc.Obj, nil)
+		changes = append(changes, ch)
+	}
+	for _, ch := range changes {
+		if len(ch.Risks) > 0 {
+			summary.Risky++
+		}
 	}
 
 	sort.Slice(changes, func(i, j int) bool {
@@ -1098,16 +1230,16 @@ func planWarnings(changes []planResourceChange) []string {
 	return warnings
 }
 
-func buildManifestBlobs(desired map[resourceKey]manifestDoc) map[string]string {
+func buildManifestBlobs(desired map[resourceKey]manifestDoc) planBlobs {
 	if len(desired) == 0 {
 		return nil
 	}
-	out := make(map[string]string, len(desired))
+	out := make(planBlobs, len(desired))
 	for key, doc := range desired {
 		if doc.Body == "" {
 			doc.Body = objectYAML(doc.Obj)
 		}
-		out[graphNodeID(key)] = doc.Body
+		out.set(graphNodeID(key), doc.Body)
 	}
 	return out
 }
@@ -1129,16 +1261,16 @@ func buildManifestTemplateIndex(desired map[resourceKey]manifestDoc) map[string]
 	return out
 }
 
-func buildLiveManifestBlobs(live map[resourceKey]*unstructured.Unstructured) map[string]string {
+func buildLiveManifestBlobs(live map[resourceKey]*unstructured.Unstructured) planBlobs {
 	if len(live) == 0 {
 		return nil
 	}
-	out := make(map[string]string, len(live))
+	out := make(planBlobs, len(live))
 	for key, obj := range live {
 		if obj == nil {
 			continue
 		}
-		out[graphNodeID(key)] = objectYAML(obj.DeepCopy())
+		out.set(graphNodeID(key), objectYAML(obj))
 	}
 	if len(out) == 0 {
 		return nil
@@ -1146,18 +1278,19 @@ func buildLiveManifestBlobs(live map[resourceKey]*unstructured.Unstructured) map
 	return out
 }
 
-func buildManifestDiffs(live, rendered map[string]string) map[string]string {
+func buildManifestDiffs(live, rendered planBlobs, archive *planDiffArchive) map[string]string {
 	if len(live) == 0 || len(rendered) == 0 {
 		return nil
 	}
 	diffs := make(map[string]string)
-	for id, desired := range rendered {
-		liveBody, ok := live[id]
+	for id := range rendered {
+		liveBody, ok := live.Get(id)
 		if !ok || strings.TrimSpace(liveBody) == "" {
 			continue
 		}
+		desired, _ := rendered.Get(id)
 		if diff := diffStrings(liveBody, desired); strings.TrimSpace(diff) != "" {
-			diffs[id] = diff
+			diffs[id], _ = archive.limit(id, diff)
 		}
 	}
 	if len(diffs) == 0 {
@@ -1167,11 +1300,7 @@ func buildManifestDiffs(live, rendered map[string]string) map[string]string {
 }
 
 func graphNodeID(key resourceKey) string {
-	ns := key.Namespace
-	if ns == "" {
-		ns = "cluster"
-	}
-	return fmt.Sprintf("%s|%s|%s", strings.ToLower(ns), strings.ToLower(key.Kind), strings.ToLower(key.Name))
+	return deploy.ResourceID(key.Kind, key.Namespace, key.Name)
 }
 
 func referenceToResourceKey(ref graphRef, fallbackNamespace string) resourceKey {
@@ -1556,6 +1685,12 @@ func renderDeployPlan(out io.Writer, result *deployPlanResult) {
 	if len(result.SetFileValues) > 0 {
 		fmt.Fprintf(out, "Set-file values:\n%s\n", indent(strings.Join(result.SetFileValues, "\n"), "  - "))
 	}
+	if len(result.SetJSONValues) > 0 {
+		fmt.Fprintf(out, "Set-json values:\n%s\n", indent(strings.Join(result.SetJSONValues, "\n"), "  - "))
+	}
+	if len(result.SetLiteralValues) > 0 {
+		fmt.Fprintf(out, "Set-literal values:\n%s\n", indent(strings.Join(result.SetLiteralValues, "\n"), "  - "))
+	}
 	if result.InstallCmd != "" {
 		fmt.Fprintf(out, "Install command: %s\n", result.InstallCmd)
 	}
@@ -1570,9 +1705,20 @@ func renderDeployPlan(out io.Writer, result *deployPlanResult) {
 			if change.Diff != "" {
 				fmt.Fprintf(out, "%s\n", indent(change.Diff, "    "))
 			}
+			if change.FullDiffPath != "" {
+				fmt.Fprintf(out, "    full diff: %s\n", change.FullDiffPath)
+			}
 		}
 	}
 
+	if result.Summary.Risky > 0 {
+		fmt.Fprintln(out, "\nRequires extra approval:")
+		for _, change := range result.Changes {
+			for _, risk := range change.Risks {
+				fmt.Fprintf(out, "- %s [%s] %s\n", change.Key.String(), risk.Tag, risk.Detail)
+			}
+		}
+	}
 	if len(result.Warnings) > 0 {
 		fmt.Fprintln(out, "\nWarnings:")
 		for _, warn := range result.Warnings {
@@ -1602,9 +1748,10 @@ func renderDeployPlan(out io.Writer, result *deployPlanResult) {
 			fmt.Fprintf(out, "- %s %s/%s (%s)\n", node.Kind, ns, node.Name, node.Source)
 		}
 	}
-	writeStringMapSection(out, "\nRendered manifests:", result.ManifestBlobs)
-	writeStringMapSection(out, "\nLive manifests:", result.LiveManifests)
+	writeStringMapSection(out, "\nRendered manifests:", result.ManifestBlobs.Strings())
+	writeStringMapSection(out, "\nLive manifests:", result.LiveManifests.Strings())
 	writeStringMapSection(out, "\nManifest diffs:", result.ManifestDiffs)
+	writeStringMapSection(out, "\nFull manifest diffs:", result.ManifestDiffFiles)
 	writeStringMapSection(out, "\nTemplate sources:", result.TemplateSources)
 	writeStringMapSection(out, "\nManifest templates:", result.ManifestTemplates)
 	if result.OfflineFallback {
@@ -1733,13 +1880,14 @@ type deployVisualizePayload struct {
 	Secrets           []planSecretRef         `json:"secrets,omitempty"`
 	Nodes             []deployGraphNode       `json:"nodes"`
 	Edges             []deployGraphEdge       `json:"edges"`
-	Manifests         map[string]string       `json:"manifests"`
-	LiveManifests     map[string]string       `json:"liveManifests,omitempty"`
+	Manifests         planBlobs               `json:"manifests"`
+	LiveManifests     planBlobs               `json:"liveManifests,omitempty"`
 	ManifestDiffs     map[string]string       `json:"manifestDiffs,omitempty"`
+	ManifestDiffFiles map[string]string       `json:"manifestDiffFiles,omitempty"`
 	ManifestTemplates map[string]string       `json:"manifestTemplates,omitempty"`
 	TemplateSources   map[string]string       `json:"templateSources,omitempty"`
 	ChangeKinds       map[string]string       `json:"changeKinds,omitempty"`
-	CompareManifests  map[string]string       `json:"compareManifests,omitempty"`
+	CompareManifests  planBlobs               `json:"compareManifests,omitempty"`
 	CompareSummary    string                  `json:"compareSummary,omitempty"`
 	Summary           planSummary             `json:"summary,omitempty"`
 	Warnings          []string                `json:"warnings,omitempty"`
@@ -1763,28 +1911,29 @@ func buildDeployVisualizePayload(result *deployPlanResult, compare *deployPlanRe
 	}
 	changeKinds := buildChangeKindIndex(result.Changes)
 	payload := deployVisualizePayload{
-		Release:          result.ReleaseName,
-		Namespace:        result.Namespace,
-		Chart:            result.ChartRef,
-		ClusterHost:      result.ClusterHost,
-		InstallCommand:   result.InstallCmd,
-		ValuesFiles:      append([]string(nil), result.ValuesFiles...),
-		SetValues:        append([]string(nil), result.SetValues...),
-		SetStringValues:  append([]string(nil), result.SetStringValues...),
-		SetFileValues:    append([]string(nil), result.SetFileValues...),
-		Secrets:          append([]planSecretRef(nil), result.Secrets...),
-		Nodes:            result.GraphNodes,
-		Edges:            result.GraphEdges,
-		Manifests:        result.ManifestBlobs,
-		LiveManifests:    result.LiveManifests,
-		ManifestDiffs:    result.ManifestDiffs,
-		ChangeKinds:      changeKinds,
-		Warnings:         append([]string(nil), result.Warnings...),
-		Summary:          result.Summary,
-		DesiredQuota:     result.DesiredQuota,
-		DesiredQuotaByNS: result.DesiredQuotaByNS,
-		GeneratedAt:      result.GeneratedAt,
-		OfflineFallback:  result.OfflineFallback,
+		Release:           result.ReleaseName,
+		Namespace:         result.Namespace,
+		Chart:             result.ChartRef,
+		ClusterHost:       result.ClusterHost,
+		InstallCommand:    result.InstallCmd,
+		ValuesFiles:       append([]string(nil), result.ValuesFiles...),
+		SetValues:         append([]string(nil), result.SetValues...),
+		SetStringValues:   append([]string(nil), result.SetStringValues...),
+		SetFileValues:     append([]string(nil), result.SetFileValues...),
+		Secrets:           append([]planSecretRef(nil), result.Secrets...),
+		Nodes:             result.GraphNodes,
+		Edges:             result.GraphEdges,
+		Manifests:         result.ManifestBlobs,
+		LiveManifests:     result.LiveManifests,
+		ManifestDiffs:     result.ManifestDiffs,
+		ManifestDiffFiles: result.ManifestDiffFiles,
+		ChangeKinds:       changeKinds,
+		Warnings:          append([]string(nil), result.Warnings...),
+		Summary:           result.Summary,
+		DesiredQuota:      result.DesiredQuota,
+		DesiredQuotaByNS:  result.DesiredQuotaByNS,
+		GeneratedAt:       result.GeneratedAt,
+		OfflineFallback:   result.OfflineFallback,
 	}
 	if compare != nil {
 		payload.CompareManifests = compare.ManifestBlobs
@@ -2060,6 +2209,12 @@ func buildInstallCommand(opts deployPlanOptions) string {
 	for _, val := range opts.SetFileValues {
 		parts = append(parts, "--set-file", shellQuote(val))
 	}
+	for _, val := range opts.SetJSONValues {
+		parts = append(parts, "--set-json", shellQuote(val))
+	}
+	for _, val := range opts.SetLiteralValues {
+		parts = append(parts, "--set-literal", shellQuote(val))
+	}
 	return strings.Join(parts, " ")
 }
 
@@ -2077,6 +2232,23 @@ const deployPlanHTMLTemplate = `<!DOCTYPE html>
   <meta charset="UTF-8" />
   <meta name="viewport" content="width=device-width, initial-scale=1" />
   <title>ktl Apply Plan</title>
+  <script>
+    (function(){
+      // ?theme=dark|light|auto and ?embed=1 let portals (e.g. Backstage) iframe the report.
+      var params = new URLSearchParams(window.location.search);
+      var theme = (params.get('theme') || '').toLowerCase();
+      if (theme === 'auto') {
+        theme = window.matchMedia && window.matchMedia('(prefers-color-scheme: dark)').matches ? 'dark' : 'light';
+      }
+      if (theme === 'dark') {
+        document.documentElement.setAttribute('data-theme', 'dark');
+      }
+      var embed = (params.get('embed') || '').toLowerCase();
+      if (embed === '1' || embed === 'true') {
+        document.documentElement.setAttribute('data-embed', '1');
+      }
+    })();
+  </script>
   <style>
     :root {
       --surface: rgba(255,255,255,0.9);
@@ -2310,6 +2482,41 @@ const deployPlanHTMLTemplate = `<!DOCTYPE html>
       transition:box-shadow 0.2s ease, transform 0.2s ease;
     }
     .cta:hover { box-shadow:0 12px 24px rgba(37,99,235,0.25); transform:translateY(-1px); }
+    .tab-bar { display:flex; gap:8px; margin-top:32px; flex-wrap:wrap; }
+    .tab {
+      border:1px solid var(--border);
+      border-radius:999px;
+      background:var(--surface-soft);
+      color:var(--muted);
+      font-size:0.9rem;
+      padding:0.45rem 1.2rem;
+      cursor:pointer;
+    }
+    .tab.active { background:var(--accent); border-color:var(--accent); color:#fff; }
+    .tab-panel[hidden] { display:none; }
+    .tab-panel.panel { margin-top:16px; }
+    table.values-table { width:100%; border-collapse:collapse; font-size:0.88rem; margin-top:1rem; }
+    table.values-table th {
+      text-align:left;
+      text-transform:uppercase;
+      font-size:0.72rem;
+      letter-spacing:0.16em;
+      color:var(--muted);
+      padding:0.5rem 0.6rem;
+      border-bottom:1px solid var(--border);
+    }
+    table.values-table td { padding:0.45rem 0.6rem; border-bottom:1px solid rgba(15,23,42,0.06); vertical-align:top; }
+    table.values-table td.mono { font-family:"SFMono-Regular","JetBrains Mono","Menlo","Source Code Pro",monospace; word-break:break-all; }
+    table.values-table td.source { color:var(--muted); }
+    table.values-table tr.user-set td.source { color:var(--accent); }
+    .values-filter {
+      width:100%;
+      margin-top:1rem;
+      padding:0.55rem 0.9rem;
+      border-radius:12px;
+      border:1px solid var(--border);
+      font-size:0.9rem;
+    }
     .toast {
       position:fixed; bottom:24px; right:24px;
       padding:0.6rem 1.2rem;
@@ -2322,11 +2529,32 @@ const deployPlanHTMLTemplate = `<!DOCTYPE html>
       pointer-events:none;
     }
     .toast.visible { opacity:1; transform:translateY(0); }
+    html[data-theme="dark"] {
+      color-scheme: dark;
+      --surface: rgba(17,24,39,0.92);
+      --surface-soft: rgba(30,41,59,0.85);
+      --border: rgba(148,163,184,0.22);
+      --text: #e2e8f0;
+      --muted: rgba(226,232,240,0.62);
+      --accent: #60a5fa;
+    }
+    html[data-theme="dark"] body { background:radial-gradient(circle at 20% 20%, #1e293b, #0f172a 45%, #020617); }
+    html[data-theme="dark"] .card { background:var(--surface-soft); border-color:var(--border); }
+    html[data-theme="dark"] .graph-canvas { background:#0f172a; }
+    html[data-theme="dark"] .values-filter { background:#0f172a; color:var(--text); }
+    html[data-theme="dark"] .tab.active { color:#0f172a; }
+    html[data-embed="1"] body { padding:16px; min-height:0; background:transparent; }
+    html[data-embed="1"] .chrome { max-width:none; }
+    html[data-embed="1"] .chrome > header,
+    html[data-embed="1"] .insight-stack { display:none; }
+    html[data-embed="1"] .panel { border-radius:16px; padding:20px; box-shadow:none; backdrop-filter:none; }
+    html[data-embed="1"] .tab-bar { margin-top:16px; }
     @media print {
       body { background:#fff; padding:24px; }
       .insight-stack { display:none; }
       .panel, .insight-panel { box-shadow:none !important; border-color:#000 !important; }
-      .cta, #copyToast { display:none !important; }
+      .cta, #copyToast, .tab-bar, .values-filter { display:none !important; }
+      .tab-panel[hidden] { display:block; }
     }
   </style>
 </head>
@@ -2347,8 +2575,22 @@ const deployPlanHTMLTemplate = `<!DOCTYPE html>
             <div class="card"><span>Deletes</span><strong>{{.Summary.Deletes}}</strong></div>
             <div class="card"><span>Unchanged</span><strong>{{.Summary.Unchanged}}</strong></div>
           </div>
+          {{if .Summary.Risky}}
+          <h2>Requires extra approval</h2>
+          <ul class="warning-list">
+            {{range .Changes}}{{$key := .Key.String}}{{range .Risks}}
+            <li><span class="mono">{{$key}}</span> [{{.Tag}}] {{.Detail}}</li>
+            {{end}}{{end}}
+          </ul>
+          {{end}}
         </section>
-        <section class="panel diff-panel">
+        <nav class="tab-bar" role="tablist">
+          <button class="tab active" type="button" role="tab" data-tab="changes">Changes</button>
+          <button class="tab" type="button" role="tab" data-tab="values">Values{{if .Values}} ({{len .Values}}){{end}}</button>
+          <button class="tab" type="button" role="tab" data-tab="notes">Notes</button>
+          <button class="tab" type="button" role="tab" data-tab="hooks">Hooks{{if .Hooks}} ({{len .Hooks}}){{end}}</button>
+        </nav>
+        <section class="panel diff-panel tab-panel" data-panel="changes">
           <div class="diff-header">
             <div>
               <h2>Planned changes</h2>
@@ -2366,9 +2608,15 @@ const deployPlanHTMLTemplate = `<!DOCTYPE html>
                 </div>
                 <span class="diff-kind">{{changeLabel .Kind}}</span>
               </header>
+              {{if .Risks}}
+              <ul class="warning-list">{{range .Risks}}<li>[{{.Tag}}] {{.Detail}}</li>{{end}}</ul>
+              {{end}}
               {{if .Diff}}
               <pre class="diff-snippet">{{diffHTML .Diff}}</pre>
               {{end}}
+              {{if .DiffTruncated}}
+              <p class="summary-meta diff-truncated">Diff truncated{{if .FullDiffPath}}, <a href="{{.FullDiffPath}}">see full diff</a>{{else}}; re-run with --max-diff-bytes 0 for the full diff{{end}}.</p>
+              {{end}}
             </article>
             {{end}}
           </div>
@@ -2376,6 +2624,46 @@ const deployPlanHTMLTemplate = `<!DOCTYPE html>
           <p class="summary-meta diff-empty">No drift detected between the rendered chart and the cluster.</p>
           {{end}}
         </section>
+        <section class="panel tab-panel" data-panel="values" hidden>
+          <h2>Merged values</h2>
+          <p class="summary-meta">Chart defaults overlaid with{{if .ValuesFiles}} {{len .ValuesFiles}} values file(s){{end}}{{if or .SetValues .SetStringValues .SetFileValues}} and --set overrides{{end}}{{if not (or .ValuesFiles .SetValues .SetStringValues .SetFileValues)}} no user overrides{{end}}. Secret references are shown unresolved.</p>
+          {{if .Values}}
+          <input class="values-filter" type="search" placeholder="Filter by path, value, or source" aria-label="Filter values" />
+          <table class="values-table">
+            <thead><tr><th>Path</th><th>Value</th><th>Source</th></tr></thead>
+            <tbody>
+              {{range .Values}}
+              <tr class="{{if ne .Source "chart defaults"}}user-set{{end}}"><td class="mono">{{.Path}}</td><td class="mono">{{.Value}}</td><td class="source">{{.Source}}</td></tr>
+              {{end}}
+            </tbody>
+          </table>
+          {{else}}
+          <p class="summary-meta">No values were recorded for this plan.</p>
+          {{end}}
+        </section>
+        <section class="panel tab-panel" data-panel="notes" hidden>
+          <h2>NOTES.txt</h2>
+          {{if .Notes}}
+          <pre class="diff-snippet">{{.Notes}}</pre>
+          {{else}}
+          <p class="summary-meta">The chart does not render any notes.</p>
+          {{end}}
+        </section>
+        <section class="panel tab-panel" data-panel="hooks" hidden>
+          <h2>Hook execution order</h2>
+          {{if .Hooks}}
+          <table class="values-table">
+            <thead><tr><th>Event</th><th>#</th><th>Weight</th><th>Hook</th><th>Delete policy</th></tr></thead>
+            <tbody>
+              {{range .Hooks}}
+              <tr><td>{{.Event}}</td><td>{{.Order}}</td><td>{{.Weight}}</td><td class="mono">{{.Kind}}/{{.Name}}{{if .Path}}<br /><span class="summary-meta">{{.Path}}</span>{{end}}</td><td class="source">{{range $i, $p := .DeletePolicies}}{{if $i}}, {{end}}{{$p}}{{end}}</td></tr>
+              {{end}}
+            </tbody>
+          </table>
+          {{else}}
+          <p class="summary-meta">The chart defines no Helm hooks.</p>
+          {{end}}
+        </section>
       </div>
     </div>
   </div>
@@ -2390,6 +2678,25 @@ const deployPlanHTMLTemplate = `<!DOCTYPE html>
         clearTimeout(showToast._timer);
         showToast._timer = setTimeout(() => toast.classList.remove('visible'), 1400);
       }
+      const tabs = document.querySelectorAll('.tab-bar .tab');
+      tabs.forEach(tab => {
+        tab.addEventListener('click', () => {
+          const name = tab.getAttribute('data-tab');
+          tabs.forEach(t => t.classList.toggle('active', t === tab));
+          document.querySelectorAll('.tab-panel').forEach(panel => {
+            panel.hidden = panel.getAttribute('data-panel') !== name;
+          });
+        });
+      });
+      const filter = document.querySelector('.values-filter');
+      if(filter){
+        filter.addEventListener('input', () => {
+          const needle = filter.value.trim().toLowerCase();
+          document.querySelectorAll('[data-panel="values"] .values-table tbody tr').forEach(row => {
+            row.hidden = needle !== '' && !row.textContent.toLowerCase().includes(needle);
+          });
+        });
+      }
       document.querySelectorAll('.cta.copy').forEach(btn => {
         btn.addEventListener('click', async () => {
           const cmd = btn.getAttribute('data-command');
@@ -2404,6 +2711,21 @@ const deployPlanHTMLTemplate = `<!DOCTYPE html>
       });
     })();
   </script>
+  <script>
+    (function(){
+      // In embed mode, report the document height so the host page can size the iframe.
+      if (document.documentElement.getAttribute('data-embed') !== '1' || window.parent === window || typeof ResizeObserver === 'undefined') {
+        return;
+      }
+      var last = 0;
+      new ResizeObserver(function(){
+        var height = document.documentElement.scrollHeight;
+        if (height === last) { return; }
+        last = height;
+        window.parent.postMessage({ type: 'ktl:resize', height: height }, '*');
+      }).observe(document.body);
+    })();
+  </script>
   <script id="ktlPlanData" type="application/json">{{.PlanJSON}}</script>
 </body>
 </html>`
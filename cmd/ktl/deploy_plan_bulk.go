@@ -0,0 +1,168 @@
+// File: cmd/ktl/deploy_plan_bulk.go
+// Brief: CLI command wiring and implementation for 'deploy plan bulk'.
+
+// deploy_plan_bulk.go implements `ktl apply plan --all-releases`: a namespace-wide drift report
+// built from every release's stored chart and values.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/ui"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"sigs.k8s.io/yaml"
+)
+
+var namespacePlanOnlyFlags = []string{"chart", "release", "version", "values", "set", "set-string", "set-file", "set-json", "set-literal", "visualize", "visualize-explain", "compare", "compare-to", "baseline", "serve"}
+
+func validateNamespacePlanFlags(cmd *cobra.Command, format, outputPath string) error {
+	for _, name := range namespacePlanOnlyFlags {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			return fmt.Errorf("--%s cannot be combined with --all-releases", name)
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "text":
+		if strings.TrimSpace(outputPath) != "" {
+			return fmt.Errorf("--output is only supported with --format=html, --format=json, or --format=yaml")
+		}
+	case "json", "yaml", "html":
+	default:
+		return fmt.Errorf("unsupported format %q for --all-releases (expected text, json, yaml, or html)", format)
+	}
+	return nil
+}
+
+func runNamespacePlan(ctx context.Context, cmd *cobra.Command, actionCfg *action.Configuration, kubeClient *kube.Client, namespace, format, outputPath string) error {
+	_, stopSpinner := ui.StartSpinnerWithStatus(cmd.ErrOrStderr(), fmt.Sprintf("Planning releases in ns/%s", namespace))
+	report, err := deploy.CheckNamespaceDrift(ctx, actionCfg, deploy.NamespaceDriftOptions{
+		Namespace: namespace,
+		Get:       deploy.DriftLiveGetterFromKube(kubeClient),
+	})
+	stopSpinner(err == nil)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "json":
+		data, err = json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal plan json: %w", err)
+		}
+		data = append(data, '\n')
+	case "yaml":
+		data, err = yaml.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("marshal plan yaml: %w", err)
+		}
+	case "html":
+		page, err := renderNamespacePlanHTML(report)
+		if err != nil {
+			return err
+		}
+		path := strings.TrimSpace(outputPath)
+		if path == "" {
+			path = fmt.Sprintf("ktl-namespace-plan-%s-%s.html", sanitizeFilename(namespace), report.GeneratedAt.Format("20060102-150405"))
+		}
+		if err := os.WriteFile(path, []byte(page), 0o644); err != nil {
+			return fmt.Errorf("write html: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Plan written to %s\n", path)
+		return nil
+	default:
+		renderNamespacePlan(cmd.OutOrStdout(), report)
+		return nil
+	}
+	if strings.TrimSpace(outputPath) != "" {
+		if err := os.WriteFile(outputPath, data, 0o644); err != nil {
+			return fmt.Errorf("write plan: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Plan written to %s\n", outputPath)
+		return nil
+	}
+	_, err = cmd.OutOrStdout().Write(data)
+	return err
+}
+
+func renderNamespacePlan(out io.Writer, report deploy.NamespaceDriftReport) {
+	fmt.Fprintf(out, "Namespace %s: %d releases, %d drifted\n", report.Namespace, len(report.Releases), report.Drifted())
+	for _, rel := range report.Releases {
+		switch {
+		case rel.Error != "":
+			fmt.Fprintf(out, "\n✖ %s (rev %d): %s\n", rel.Release, rel.Revision, rel.Error)
+		case len(rel.Items) == 0:
+			fmt.Fprintf(out, "✔ %s (rev %d): in sync\n", rel.Release, rel.Revision)
+		default:
+			fmt.Fprintf(out, "\n≠ %s (rev %d): %d objects drifted\n", rel.Release, rel.Revision, len(rel.Items))
+			fmt.Fprintln(out, indent(deploy.FormatDriftReport(deploy.DriftReport{Items: rel.Items}, 10, 40), "  "))
+		}
+	}
+}
+
+func renderNamespacePlanHTML(report deploy.NamespaceDriftReport) (string, error) {
+	tmpl, err := template.New("namespacePlanHTML").Funcs(template.FuncMap{
+		"diffHTML": diffStringToHTML,
+	}).Parse(namespacePlanHTMLTemplate)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct {
+		deploy.NamespaceDriftReport
+		Drifted int
+	}{report, report.Drifted()}); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+const namespacePlanHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8" />
+  <meta name="viewport" content="width=device-width, initial-scale=1" />
+  <title>ktl Namespace Plan · {{.Namespace}}</title>
+  <style>
+    body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; margin: 0; padding: 40px 48px; background: #f1f4f9; color: #0f172a; }
+    h1 { font-size: 2rem; margin: 0 0 0.3rem; }
+    .subtitle { color: rgba(15,23,42,0.65); margin-bottom: 24px; }
+    .release { background: #fff; border: 1px solid rgba(15,23,42,0.12); border-radius: 16px; padding: 16px 20px; margin-bottom: 14px; }
+    .release.ok { border-left: 4px solid #22c55e; }
+    .release.drift { border-left: 4px solid #fbbf24; }
+    .release.error { border-left: 4px solid #ef4444; }
+    .meta { color: rgba(15,23,42,0.65); font-size: 0.9rem; }
+    pre.diff-snippet { background: #0f172a; color: #e2e8f0; padding: 1rem; border-radius: 12px; overflow: auto; font-size: 0.85rem; }
+    pre.diff-snippet .diff-line { display: block; white-space: pre; }
+    pre.diff-snippet .diff-line--added { color: #bbf7d0; }
+    pre.diff-snippet .diff-line--removed { color: #fecaca; }
+    pre.diff-snippet .diff-line--header { color: #fbbf24; }
+  </style>
+</head>
+<body>
+  <h1>Namespace {{.Namespace}}</h1>
+  <div class="subtitle">{{len .Releases}} releases · {{.Drifted}} drifted · generated {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</div>
+  {{range .Releases}}
+  <section class="release {{if .Error}}error{{else if .Items}}drift{{else}}ok{{end}}">
+    <strong>{{.Release}}</strong> <span class="meta">rev {{.Revision}}{{if .Chart}} · {{.Chart}}{{end}}</span>
+    {{if .Error}}<p>{{.Error}}</p>{{else if not .Items}}<p class="meta">In sync</p>{{end}}
+    {{range .Items}}
+    <p>{{.Kind}}/{{.Name}}{{if .Namespace}} <span class="meta">ns: {{.Namespace}}</span>{{end}} — {{.Reason}}</p>
+    {{if .Diff}}<pre class="diff-snippet">{{diffHTML .Diff}}</pre>{{end}}
+    {{end}}
+  </section>
+  {{end}}
+</body>
+</html>
+`
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+)
+
+func TestDeployPlanAllReleasesFlagValidation(t *testing.T) {
+	var kubeconfig, kubeContext string
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{args: nil, want: `required flag(s) "chart", "release" not set`},
+		{args: []string{"--all-releases", "--chart", "./chart"}, want: "--chart cannot be combined with --all-releases"},
+		{args: []string{"--all-releases", "--format", "visualize"}, want: `unsupported format "visualize" for --all-releases`},
+		{args: []string{"--all-releases", "--output", "plan.txt"}, want: "--output is only supported"},
+	}
+	for _, tc := range cases {
+		cmd := newDeployPlanCommand(nil, &kubeconfig, &kubeContext, "")
+		cmd.SetArgs(tc.args)
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("args %v: expected %q, got %v", tc.args, tc.want, err)
+		}
+	}
+}
+
+func TestRenderNamespacePlan(t *testing.T) {
+	report := deploy.NamespaceDriftReport{
+		Namespace: "prod",
+		Releases: []deploy.ReleaseDriftResult{
+			{Release: "api", Revision: 3},
+			{Release: "web", Revision: 7, Items: []deploy.DriftItem{{Kind: "Deployment", Name: "web", Namespace: "prod", Reason: "changed", Diff: "-  \"replicas\": 2\n+  \"replicas\": 5"}}},
+		},
+	}
+	var buf bytes.Buffer
+	renderNamespacePlan(&buf, report)
+	out := buf.String()
+	for _, want := range []string{"Namespace prod: 2 releases, 1 drifted", "✔ api (rev 3): in sync", "≠ web (rev 7): 1 objects drifted", "- Deployment/web (ns: prod): changed"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+	page, err := renderNamespacePlanHTML(report)
+	if err != nil || !strings.Contains(page, "diff-line--added") {
+		t.Fatalf("html err=%v", err)
+	}
+}
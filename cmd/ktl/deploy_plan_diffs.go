@@ -0,0 +1,256 @@
+// File: cmd/ktl/deploy_plan_diffs.go
+// Brief: CLI command wiring and implementation for 'deploy plan diffs'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultPlanDiffMaxBytes caps the diff kept inline per resource. Charts that render tens of
+// megabytes of YAML otherwise hold several full copies of every object in memory (and in the
+// HTML report); anything beyond the cap is kept gzip-compressed and only written out on demand.
+const defaultPlanDiffMaxBytes = 64 << 10
+
+// planDiffArchive keeps the full text of truncated diffs gzip-compressed, keyed by graph node ID.
+type planDiffArchive struct {
+	mu       sync.Mutex
+	maxBytes int
+	entries  map[string][]byte
+}
+
+func newPlanDiffArchive(maxBytes int) *planDiffArchive {
+	return &planDiffArchive{maxBytes: maxBytes, entries: map[string][]byte{}}
+}
+
+// limit returns diff unchanged when it fits the cap. Otherwise the full diff is archived under id
+// and a prefix cut at a line boundary is returned with a trailing truncation marker.
+func (a *planDiffArchive) limit(id, diff string) (string, bool) {
+	if a == nil || a.maxBytes <= 0 || len(diff) <= a.maxBytes {
+		return diff, false
+	}
+	if raw, err := gzipText(diff); err == nil {
+		a.mu.Lock()
+		a.entries[id] = raw
+		a.mu.Unlock()
+	}
+	return truncatePlanDiff(diff, a.maxBytes), true
+}
+
+// Full returns the untruncated diff archived under id.
+func (a *planDiffArchive) Full(id string) (string, bool) {
+	if a == nil {
+		return "", false
+	}
+	a.mu.Lock()
+	raw, ok := a.entries[id]
+	a.mu.Unlock()
+	if !ok {
+		return "", false
+	}
+	out, err := gunzipText(raw)
+	if err != nil {
+		return "", false
+	}
+	return out, true
+}
+
+// Len reports how many diffs were archived.
+func (a *planDiffArchive) Len() int {
+	if a == nil {
+		return 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.entries)
+}
+
+// WriteFiles writes every archived diff to dir as <id>.diff and returns the file name per id.
+func (a *planDiffArchive) WriteFiles(dir string) (map[string]string, error) {
+	if a.Len() == 0 {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create diff dir: %w", err)
+	}
+	a.mu.Lock()
+	ids := make([]string, 0, len(a.entries))
+	for id := range a.entries {
+		ids = append(ids, id)
+	}
+	a.mu.Unlock()
+	sort.Strings(ids)
+	files := make(map[string]string, len(ids))
+	for _, id := range ids {
+		full, ok := a.Full(id)
+		if !ok {
+			continue
+		}
+		name := sanitizeFilename(strings.ReplaceAll(id, "|", "_")) + ".diff"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(full), 0o644); err != nil {
+			return nil, fmt.Errorf("write full diff: %w", err)
+		}
+		files[id] = name
+	}
+	return files, nil
+}
+
+// planBlobs holds rendered and live manifests gzip-compressed, keyed by graph node ID, so a plan of
+// a huge chart keeps one compressed copy of each object instead of several YAML strings. It
+// encodes as a plain map of strings, so plan artifacts keep their format.
+type planBlobs map[string][]byte
+
+func newPlanBlobs(m map[string]string) planBlobs {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(planBlobs, len(m))
+	for id, body := range m {
+		out.set(id, body)
+	}
+	return out
+}
+
+func (b planBlobs) set(id, body string) {
+	raw, err := gzipText(body)
+	if err != nil {
+		return
+	}
+	b[id] = raw
+}
+
+// Get returns the manifest stored under id.
+func (b planBlobs) Get(id string) (string, bool) {
+	raw, ok := b[id]
+	if !ok {
+		return "", false
+	}
+	out, err := gunzipText(raw)
+	if err != nil {
+		return "", false
+	}
+	return out, true
+}
+
+// Strings expands every manifest; callers use it only while encoding or printing.
+func (b planBlobs) Strings() map[string]string {
+	if len(b) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(b))
+	for id := range b {
+		if body, ok := b.Get(id); ok {
+			out[id] = body
+		}
+	}
+	return out
+}
+
+func (b planBlobs) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.Strings())
+}
+
+func (b *planBlobs) UnmarshalJSON(data []byte) error {
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*b = newPlanBlobs(m)
+	return nil
+}
+
+var planGzipWriters = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+
+func gzipText(s string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := planGzipWriters.Get().(*gzip.Writer)
+	defer planGzipWriters.Put(zw)
+	zw.Reset(&buf)
+	if _, err := io.WriteString(zw, s); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzipText(raw []byte) (string, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return "", err
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func truncatePlanDiff(diff string, maxBytes int) string {
+	cut := diff[:maxBytes]
+	if idx := strings.LastIndexByte(cut, '\n'); idx > 0 {
+		cut = cut[:idx+1]
+	}
+	omitted := strings.Count(diff[len(cut):], "\n")
+	if !strings.HasSuffix(cut, "\n") {
+		cut += "\n"
+	}
+	return fmt.Sprintf("%s... diff truncated (%d more lines, %d bytes)\n", cut, omitted, len(diff)-len(cut))
+}
+
+// writePlanFullDiffs spills truncated diffs next to a plan written to outputPath (<plan>-diffs/)
+// and points each truncated change and manifest diff at its file so the output can link to it.
+func writePlanFullDiffs(result *deployPlanResult, outputPath string) error {
+	if result == nil || (result.fullDiffs.Len() == 0 && result.fullManifestDiffs.Len() == 0) {
+		return nil
+	}
+	base := strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath)) + "-diffs"
+	dir := filepath.Join(filepath.Dir(outputPath), base)
+	files, err := result.fullDiffs.WriteFiles(dir)
+	if err != nil {
+		return err
+	}
+	for i := range result.Changes {
+		ch := &result.Changes[i]
+		if !ch.DiffTruncated {
+			continue
+		}
+		if name, ok := files[graphNodeID(ch.Key)]; ok {
+			ch.FullDiffPath = base + "/" + name
+		}
+	}
+	// Manifest diffs compare against the live object rather than the previous release, so they
+	// share node IDs with the change diffs and get their own directory.
+	liveFiles, err := result.fullManifestDiffs.WriteFiles(filepath.Join(dir, "live"))
+	if err != nil {
+		return err
+	}
+	for id, name := range liveFiles {
+		if result.ManifestDiffFiles == nil {
+			result.ManifestDiffFiles = map[string]string{}
+		}
+		result.ManifestDiffFiles[id] = base + "/live/" + name
+	}
+	return nil
+}
+
+// notePlanTruncatedDiffs tells the user how to keep the full diffs of a plan written to stdout.
+func notePlanTruncatedDiffs(w io.Writer, result *deployPlanResult) {
+	if result == nil || (result.fullDiffs.Len() == 0 && result.fullManifestDiffs.Len() == 0) {
+		return
+	}
+	fmt.Fprintln(w, "Note: large diffs are truncated; write the plan to a file with --output to keep the full diffs next to it, or re-run with --max-diff-bytes 0.")
+}
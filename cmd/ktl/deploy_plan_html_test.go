@@ -11,6 +11,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
 )
 
 func TestRenderDeployPlanHTML(t *testing.T) {
@@ -41,6 +43,45 @@ func TestRenderDeployPlanHTML(t *testing.T) {
 	}
 }
 
+func TestRenderDeployPlanHTMLValuesNotesHooksTabs(t *testing.T) {
+	result := &deployPlanResult{
+		ReleaseName: "demo",
+		Namespace:   "prod",
+		ValuesFiles: []string{"values-prod.yaml"},
+		Values: []deploy.ValueProvenance{
+			{Path: "image.tag", Value: "v2", Source: "values file values-prod.yaml"},
+			{Path: "replicas", Value: "1", Source: deploy.ValueSourceChartDefaults},
+		},
+		Notes:       "Visit https://demo.example.com",
+		Hooks:       []deploy.HookStep{{Event: "pre-upgrade", Order: 1, Weight: -5, Kind: "Job", Name: "demo-migrate", DeletePolicies: []string{"before-hook-creation"}}},
+		GeneratedAt: time.Now(),
+	}
+	html, err := renderDeployPlanHTML(result)
+	if err != nil {
+		t.Fatalf("render HTML: %v", err)
+	}
+	for _, want := range []string{
+		`data-tab="values"`,
+		`data-panel="notes"`,
+		"image.tag",
+		"values file values-prod.yaml",
+		"Visit https://demo.example.com",
+		"Job/demo-migrate",
+		"before-hook-creation",
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("expected %q in html", want)
+		}
+	}
+	parsed, err := parsePlanHTML([]byte(html))
+	if err != nil {
+		t.Fatalf("parse plan HTML: %v", err)
+	}
+	if len(parsed.Values) != 2 || parsed.Notes == "" || len(parsed.Hooks) != 1 {
+		t.Fatalf("expected values/notes/hooks to round-trip, got %+v", parsed)
+	}
+}
+
 func TestPlanHTMLEmbedsJSON(t *testing.T) {
 	result := &deployPlanResult{
 		ReleaseName:  "demo",
@@ -72,8 +113,8 @@ func TestRenderDeployVisualizeHTML(t *testing.T) {
 		ChartRef:      "./chart",
 		GraphNodes:    []deployGraphNode{{ID: "prod|deployment|web", Kind: "Deployment", Name: "web", Namespace: "prod"}},
 		GraphEdges:    []deployGraphEdge{{From: "prod|deployment|web", To: "prod|configmap|cfg"}},
-		ManifestBlobs: map[string]string{"prod|deployment|web": "kind: Deployment\nmetadata:\n  name: web"},
-		LiveManifests: map[string]string{"prod|deployment|web": "kind: Deployment\nmetadata:\n  name: web"},
+		ManifestBlobs: newPlanBlobs(map[string]string{"prod|deployment|web": "kind: Deployment\nmetadata:\n  name: web"}),
+		LiveManifests: newPlanBlobs(map[string]string{"prod|deployment|web": "kind: Deployment\nmetadata:\n  name: web"}),
 		ManifestDiffs: map[string]string{"prod|deployment|web": "--- live\n+++ rendered\n"},
 		Summary:       planSummary{Creates: 1, Updates: 2, Deletes: 0, Unchanged: 3},
 		GeneratedAt:   time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
@@ -156,7 +197,7 @@ func TestBuildDeployVisualizePayloadMatchesEmbeddedJSON(t *testing.T) {
 		ChartRef:      "./chart",
 		GraphNodes:    []deployGraphNode{{ID: "prod|deployment|web", Kind: "Deployment", Name: "web", Namespace: "prod"}},
 		GraphEdges:    []deployGraphEdge{{From: "prod|deployment|web", To: "prod|configmap|cfg"}},
-		ManifestBlobs: map[string]string{"prod|deployment|web": "kind: Deployment\nmetadata:\n  name: web"},
+		ManifestBlobs: newPlanBlobs(map[string]string{"prod|deployment|web": "kind: Deployment\nmetadata:\n  name: web"}),
 		ManifestDiffs: map[string]string{"prod|deployment|web": "--- live\n+++ rendered\n"},
 		Changes: []planResourceChange{
 			{Key: resourceKey{Namespace: "prod", Kind: "Deployment", Name: "web"}, Kind: changeUpdate},
@@ -204,7 +245,7 @@ func TestRenderDeployVisualizeHTMLWithCompare(t *testing.T) {
 		ChartRef:      "./chart",
 		GraphNodes:    []deployGraphNode{{ID: "prod|configmap|cfg", Kind: "ConfigMap", Name: "cfg", Namespace: "prod"}},
 		GraphEdges:    nil,
-		ManifestBlobs: map[string]string{"prod|configmap|cfg": "kind: ConfigMap\nmetadata:\n  name: cfg"},
+		ManifestBlobs: newPlanBlobs(map[string]string{"prod|configmap|cfg": "kind: ConfigMap\nmetadata:\n  name: cfg"}),
 		Summary:       planSummary{Creates: 1, Updates: 0, Deletes: 0, Unchanged: 0},
 		Changes: []planResourceChange{
 			{Key: resourceKey{Namespace: "prod", Kind: "ConfigMap", Name: "cfg"}, Kind: changeCreate},
@@ -214,7 +255,7 @@ func TestRenderDeployVisualizeHTMLWithCompare(t *testing.T) {
 		ReleaseName:   "demo-prev",
 		Namespace:     "prod",
 		ChartRef:      "./chart",
-		ManifestBlobs: map[string]string{"prod|configmap|cfg": "kind: ConfigMap\nmetadata:\n  name: cfg-old"},
+		ManifestBlobs: newPlanBlobs(map[string]string{"prod|configmap|cfg": "kind: ConfigMap\nmetadata:\n  name: cfg-old"}),
 		GeneratedAt:   time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC),
 	}
 	html, err := renderDeployVisualizeHTML(base, compare, deployVisualizeFeatures{})
@@ -226,7 +267,7 @@ func TestRenderDeployVisualizeHTMLWithCompare(t *testing.T) {
 	if err := json.Unmarshal([]byte(vizData), &payload); err != nil {
 		t.Fatalf("parse payload: %v", err)
 	}
-	if payload.CompareManifests == nil || payload.CompareManifests["prod|configmap|cfg"] == "" {
+	if body, _ := payload.CompareManifests.Get("prod|configmap|cfg"); body == "" {
 		t.Fatalf("expected compare manifests embedded: %+v", payload.CompareManifests)
 	}
 	if payload.CompareSummary == "" || !strings.Contains(payload.CompareSummary, "demo-prev") {
@@ -240,7 +281,7 @@ func TestRenderDeployVisualizeHTMLWithExplainDiffFeature(t *testing.T) {
 		Namespace:     "prod",
 		ChartRef:      "./chart",
 		GraphNodes:    []deployGraphNode{{ID: "prod|deployment|web", Kind: "Deployment", Name: "web", Namespace: "prod"}},
-		ManifestBlobs: map[string]string{"prod|deployment|web": "kind: Deployment\nmetadata:\n  name: web"},
+		ManifestBlobs: newPlanBlobs(map[string]string{"prod|deployment|web": "kind: Deployment\nmetadata:\n  name: web"}),
 		ManifestDiffs: map[string]string{"prod|deployment|web": "--- live\n+++ rendered\n+  image: nginx:2\n-  image: nginx:1\n"},
 		Changes: []planResourceChange{
 			{Key: resourceKey{Namespace: "prod", Kind: "Deployment", Name: "web"}, Kind: changeUpdate},
@@ -271,3 +312,28 @@ func extractVizData(t *testing.T, html string) string {
 	}
 	return match[1]
 }
+
+func TestPlanHTMLSupportsThemeAndEmbedModes(t *testing.T) {
+	result := &deployPlanResult{
+		ReleaseName:   "demo",
+		Namespace:     "prod",
+		GraphNodes:    []deployGraphNode{{ID: "prod|deployment|web", Kind: "Deployment", Name: "web", Namespace: "prod"}},
+		ManifestBlobs: newPlanBlobs(map[string]string{"prod|deployment|web": "kind: Deployment\nmetadata:\n  name: web"}),
+		GeneratedAt:   time.Now(),
+	}
+	planHTML, err := renderDeployPlanHTML(result)
+	if err != nil {
+		t.Fatalf("render HTML: %v", err)
+	}
+	vizHTML, err := renderDeployVisualizeHTML(result, nil, deployVisualizeFeatures{})
+	if err != nil {
+		t.Fatalf("render visualize HTML: %v", err)
+	}
+	for name, doc := range map[string]string{"plan": planHTML, "visualize": vizHTML} {
+		for _, want := range []string{`params.get('theme')`, `params.get('embed')`, `html[data-theme="dark"]`, `html[data-embed="1"]`, "ktl:resize"} {
+			if !strings.Contains(doc, want) {
+				t.Fatalf("%s html missing %q", name, want)
+			}
+		}
+	}
+}
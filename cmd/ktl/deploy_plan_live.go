@@ -0,0 +1,204 @@
+// File: cmd/ktl/deploy_plan_live.go
+// Brief: CLI command wiring and implementation for 'deploy plan live lookup'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"golang.org/x/sync/errgroup"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// planLiveLookupWorkers bounds concurrent API calls while collecting live state.
+	planLiveLookupWorkers = 8
+	// planLiveListThreshold is the number of objects of one resource/namespace from which a single
+	// paginated List (indexed by name) replaces individual GETs.
+	planLiveListThreshold = 5
+	planLiveListPageSize  = 500
+)
+
+// liveLookupProgress is called after each batch of live lookups finishes.
+type liveLookupProgress func(done, total int)
+
+type liveLookupGroup struct {
+	gvr        schema.GroupVersionResource
+	namespace  string
+	namespaced bool
+	keys       map[string]resourceKey
+}
+
+func collectLiveResources(ctx context.Context, kubeClient *kube.Client, desired map[resourceKey]manifestDoc, defaultNamespace string, progress liveLookupProgress) (map[resourceKey]*unstructured.Unstructured, []string, error) {
+	if kubeClient == nil || kubeClient.Dynamic == nil || kubeClient.RESTMapper == nil {
+		return nil, nil, fmt.Errorf("kubernetes client is not initialized")
+	}
+	return lookupLiveResources(ctx, kubeClient.Dynamic, kubeClient.RESTMapper, desired, defaultNamespace, progress)
+}
+
+// lookupLiveResources fetches the live counterpart of every desired object. Objects are grouped by
+// resource and namespace; large groups are served by one List, the rest by GETs, all through a
+// bounded worker pool.
+func lookupLiveResources(ctx context.Context, dyn dynamic.Interface, mapper meta.RESTMapper, desired map[resourceKey]manifestDoc, defaultNamespace string, progress liveLookupProgress) (map[resourceKey]*unstructured.Unstructured, []string, error) {
+	live := make(map[resourceKey]*unstructured.Unstructured, len(desired))
+	var warnings []string
+	groups := map[string]*liveLookupGroup{}
+	for key, doc := range desired {
+		obj := doc.Obj
+		if obj == nil {
+			live[key] = nil
+			continue
+		}
+		gvk := schema.FromAPIVersionAndKind(obj.GetAPIVersion(), obj.GetKind())
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				warnings = append(warnings, fmt.Sprintf("Skipping live lookup for %s: %v", obj.GetName(), err))
+				live[key] = nil
+				continue
+			}
+			return nil, nil, fmt.Errorf("fetch %s: %w", key.String(), err)
+		}
+		namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+		namespace := ""
+		if namespaced {
+			namespace = obj.GetNamespace()
+			if namespace == "" {
+				namespace = defaultNamespace
+			}
+			if namespace == "" {
+				namespace = "default"
+			}
+		}
+		id := mapping.Resource.String() + "|" + namespace
+		group := groups[id]
+		if group == nil {
+			group = &liveLookupGroup{gvr: mapping.Resource, namespace: namespace, namespaced: namespaced, keys: map[string]resourceKey{}}
+			groups[id] = group
+		}
+		group.keys[obj.GetName()] = key
+	}
+	sort.Strings(warnings)
+
+	total := 0
+	for _, group := range groups {
+		total += len(group.keys)
+	}
+	var (
+		mu   sync.Mutex
+		done int
+	)
+	record := func(found map[resourceKey]*unstructured.Unstructured) {
+		mu.Lock()
+		for key, obj := range found {
+			live[key] = obj
+		}
+		done += len(found)
+		current := done
+		mu.Unlock()
+		if progress != nil {
+			progress(current, total)
+		}
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(planLiveLookupWorkers)
+	for _, group := range groups {
+		group := group
+		client := dyn.Resource(group.gvr)
+		var resource dynamic.ResourceInterface = client
+		if group.namespaced {
+			resource = client.Namespace(group.namespace)
+		}
+		if len(group.keys) >= planLiveListThreshold {
+			eg.Go(func() error {
+				found, err := listLiveGroup(egCtx, resource, group)
+				if err != nil {
+					return err
+				}
+				record(found)
+				return nil
+			})
+			continue
+		}
+		for name, key := range group.keys {
+			name, key := name, key
+			eg.Go(func() error {
+				obj, err := getLiveObject(egCtx, resource, name)
+				if err != nil {
+					return fmt.Errorf("fetch %s: %w", key.String(), err)
+				}
+				record(map[resourceKey]*unstructured.Unstructured{key: obj})
+				return nil
+			})
+		}
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, nil, err
+	}
+	return live, warnings, nil
+}
+
+// listLiveGroup pages through the group's resource, keeping only the wanted names. Callers
+// without list permission fall back to individual GETs.
+func listLiveGroup(ctx context.Context, resource dynamic.ResourceInterface, group *liveLookupGroup) (map[resourceKey]*unstructured.Unstructured, error) {
+	found := make(map[resourceKey]*unstructured.Unstructured, len(group.keys))
+	for _, key := range group.keys {
+		found[key] = nil
+	}
+	opts := metav1.ListOptions{Limit: planLiveListPageSize}
+	for {
+		list, err := resource.List(ctx, opts)
+		if err != nil {
+			if apierrors.IsForbidden(err) || apierrors.IsMethodNotSupported(err) {
+				return getLiveGroup(ctx, resource, group)
+			}
+			return nil, fmt.Errorf("list %s: %w", group.gvr.String(), err)
+		}
+		for i := range list.Items {
+			if key, ok := group.keys[list.Items[i].GetName()]; ok {
+				// Copy the item so the rest of the page can be collected.
+				item := list.Items[i]
+				found[key] = &item
+			}
+		}
+		if list.GetContinue() == "" {
+			return found, nil
+		}
+		opts.Continue = list.GetContinue()
+	}
+}
+
+func getLiveGroup(ctx context.Context, resource dynamic.ResourceInterface, group *liveLookupGroup) (map[resourceKey]*unstructured.Unstructured, error) {
+	found := make(map[resourceKey]*unstructured.Unstructured, len(group.keys))
+	for name, key := range group.keys {
+		obj, err := getLiveObject(ctx, resource, name)
+		if err != nil {
+			return nil, fmt.Errorf("fetch %s: %w", key.String(), err)
+		}
+		found[key] = obj
+	}
+	return found, nil
+}
+
+func getLiveObject(ctx context.Context, resource dynamic.ResourceInterface, name string) (*unstructured.Unstructured, error) {
+	obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
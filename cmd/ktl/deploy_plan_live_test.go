@@ -0,0 +1,101 @@
+// File: cmd/ktl/deploy_plan_live_test.go
+// Brief: CLI command wiring and implementation for 'deploy plan live lookup'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestLookupLiveResourcesBatchesAndReportsProgress(t *testing.T) {
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	var manifest strings.Builder
+	var liveObjs []runtime.Object
+	for i := 0; i < planLiveListThreshold+1; i++ {
+		fmt.Fprintf(&manifest, "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm-%d\n", i)
+		if i > 0 { // cm-0 does not exist yet
+			liveObjs = append(liveObjs, liveObject("v1", "ConfigMap", "prod", fmt.Sprintf("cm-%d", i)))
+		}
+	}
+	manifest.WriteString("---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n")
+	manifest.WriteString("---\napiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: w\n")
+	liveObjs = append(liveObjs, liveObject("apps/v1", "Deployment", "prod", "web"))
+	desired := docsToMap(parseManifestDocs(manifest.String()))
+
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		configMaps:  "ConfigMapList",
+		deployments: "DeploymentList",
+	}, liveObjs...)
+
+	var mu sync.Mutex
+	lastDone, lastTotal := 0, 0
+	live, warnings, err := lookupLiveResources(context.Background(), dyn, mapper, desired, "prod", func(done, total int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if done > lastDone {
+			lastDone = done
+		}
+		lastTotal = total
+	})
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "Skipping live lookup for w") {
+		t.Fatalf("expected a skip warning for the unknown kind, got %v", warnings)
+	}
+	if lastDone != lastTotal || lastTotal != planLiveListThreshold+2 {
+		t.Fatalf("expected progress to reach %d, got %d/%d", planLiveListThreshold+2, lastDone, lastTotal)
+	}
+	found := 0
+	for key, obj := range live {
+		if obj != nil {
+			found++
+			if obj.GetName() != key.Name {
+				t.Fatalf("live object %s indexed under %s", obj.GetName(), key.Name)
+			}
+		}
+	}
+	if found != planLiveListThreshold+1 {
+		t.Fatalf("expected %d live objects, got %d", planLiveListThreshold+1, found)
+	}
+
+	var lists, gets int
+	for _, action := range dyn.Actions() {
+		switch action.(type) {
+		case k8stesting.ListAction:
+			lists++
+		case k8stesting.GetAction:
+			gets++
+		}
+	}
+	if lists != 1 || gets != 1 {
+		t.Fatalf("expected one list (configmaps) and one get (deployment), got lists=%d gets=%d", lists, gets)
+	}
+}
+
+func liveObject(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
@@ -0,0 +1,168 @@
+// File: cmd/ktl/deploy_plan_serve.go
+// Brief: CLI command wiring and implementation for 'deploy plan serve'.
+
+// deploy_plan_serve.go turns `ktl apply plan --visualize --serve` into a chart-authoring preview: the
+// visualization is served over HTTP and re-planned whenever the chart or its values files change.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/caststream"
+	"github.com/kubekattle/ktl/internal/castutil"
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/devsync"
+	"github.com/spf13/cobra"
+)
+
+// planPreviewInterval is how often --serve polls the watched files, matching ktl sync.
+const planPreviewInterval = 500 * time.Millisecond
+
+// planServeConflicts are one-shot outputs that make no sense for a long-running preview.
+var planServeConflicts = []string{"output", "compare-to", "baseline"}
+
+// validatePlanServeFlags checks --serve. The preview is always the HTML visualization, so only
+// --format=text (the default) or html may accompany it.
+func validatePlanServeFlags(cmd *cobra.Command, visualize bool, resolvedFormat string, chart string, valuesFiles []string, setFileValues []string) error {
+	if !visualize {
+		return fmt.Errorf("--serve requires --visualize")
+	}
+	if resolvedFormat != "text" && resolvedFormat != "visualize-html" {
+		return fmt.Errorf("--serve always serves HTML; drop --format")
+	}
+	for _, name := range planServeConflicts {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			return fmt.Errorf("--%s cannot be combined with --serve", name)
+		}
+	}
+	for _, src := range valuesFiles {
+		if strings.TrimSpace(src) == "-" {
+			return fmt.Errorf("--serve cannot re-read values from stdin (-); pass a values file instead")
+		}
+	}
+	if len(planWatchPaths(chart, valuesFiles, setFileValues)) == 0 {
+		return fmt.Errorf("--serve needs a local chart directory or values file to watch")
+	}
+	return nil
+}
+
+// planWatchPaths returns the local inputs of a plan: the chart directory (when it is one), local
+// values files, and --set-file sources. Remote charts and values are not watched.
+func planWatchPaths(chart string, valuesFiles []string, setFileValues []string) []string {
+	var paths []string
+	seen := map[string]bool{}
+	add := func(path string) {
+		path = strings.TrimSpace(path)
+		if path == "" || path == "-" || seen[path] {
+			return
+		}
+		if _, err := os.Stat(path); err != nil {
+			return
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	if info, err := os.Stat(chart); err == nil && info.IsDir() {
+		add(chart)
+	}
+	for _, src := range valuesFiles {
+		if deploy.IsRemoteValuesSource(src) {
+			continue
+		}
+		ref, _ := deploy.SplitValuesPin(src)
+		add(ref)
+	}
+	for _, kv := range setFileValues {
+		if _, path, ok := strings.Cut(kv, "="); ok {
+			add(path)
+		}
+	}
+	return paths
+}
+
+// planWatchSnapshot stamps every watched file. Directories are walked like ktl sync does (skipping
+// .git); keys are the file paths so changes can be reported as the user wrote them.
+func planWatchSnapshot(paths []string) devsync.Snapshot {
+	snap := devsync.Snapshot{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			snap[path] = devsync.Stamp{Size: info.Size(), ModTime: info.ModTime(), Mode: info.Mode().Perm()}
+			continue
+		}
+		dirSnap, err := devsync.Scan(path, nil)
+		if err != nil {
+			continue
+		}
+		for rel, stamp := range dirSnap {
+			snap[filepath.Join(path, filepath.FromSlash(rel))] = stamp
+		}
+	}
+	return snap
+}
+
+// watchPlanInputs polls paths every interval and calls onChange with the changed files until ctx
+// is done. onChange runs on the polling goroutine, so edits made during a re-plan are picked up by
+// the next poll.
+func watchPlanInputs(ctx context.Context, paths []string, interval time.Duration, onChange func(changed []string)) {
+	prev := planWatchSnapshot(paths)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cur := planWatchSnapshot(paths)
+		change := devsync.Diff(prev, cur)
+		prev = cur
+		if change.Empty() {
+			continue
+		}
+		onChange(append(change.Upload, change.Delete...))
+	}
+}
+
+// servePlanPreview serves the visualization on addr and re-renders it whenever a watched file
+// changes. A failed re-plan keeps the last good visualization and shows the error to viewers.
+func servePlanPreview(ctx context.Context, errOut io.Writer, addr string, paths []string, interval time.Duration, render func() (string, error)) error {
+	logger, err := buildLogger("info")
+	if err != nil {
+		return err
+	}
+	server := caststream.New(addr, caststream.ModeWeb, "ktl plan preview", logger.WithName("plan-preview"), caststream.WithPlanPreview())
+	if err := castutil.StartCastServer(ctx, server, "ktl plan preview", logger.WithName("plan-preview"), errOut); err != nil {
+		return err
+	}
+	fmt.Fprintf(errOut, "Serving plan preview on %s (watching %s; Ctrl+C to stop)\n", addr, strings.Join(paths, ", "))
+
+	replan := func(changed []string) {
+		html, err := render()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(errOut, "Re-plan failed: %v\n", err)
+			server.PublishPlanError(err, changed)
+			return
+		}
+		server.PublishPlan(html, changed)
+		fmt.Fprintf(errOut, "Plan updated at %s\n", time.Now().Format("15:04:05"))
+	}
+	replan(nil)
+	watchPlanInputs(ctx, paths, interval, func(changed []string) {
+		fmt.Fprintf(errOut, "Changed: %s\n", strings.Join(changed, ", "))
+		replan(changed)
+	})
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlanWatchPaths(t *testing.T) {
+	dir := t.TempDir()
+	chartDir := filepath.Join(dir, "chart")
+	if err := os.MkdirAll(filepath.Join(chartDir, "templates"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	values := filepath.Join(dir, "values.yaml")
+	cert := filepath.Join(dir, "tls.crt")
+	for _, path := range []string{values, cert, filepath.Join(chartDir, "templates", "cm.yaml")} {
+		if err := os.WriteFile(path, []byte("a: 1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := planWatchPaths(chartDir, []string{values + "#sha256=abc", "https://example.com/v.yaml", "-", filepath.Join(dir, "missing.yaml"), values}, []string{"tls.cert=" + cert})
+	want := []string{chartDir, values, cert}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected paths:\n got %v\nwant %v", got, want)
+	}
+	if got := planWatchPaths("bitnami/nginx", nil, nil); len(got) != 0 {
+		t.Fatalf("expected remote charts not to be watched, got %v", got)
+	}
+}
+
+func TestWatchPlanInputs(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "templates", "cm.yaml")
+	if err := os.MkdirAll(filepath.Dir(template), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(template, []byte("a: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changes := make(chan []string, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchPlanInputs(ctx, []string{dir}, 10*time.Millisecond, func(changed []string) {
+			changes <- changed
+			cancel()
+		})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(template, []byte("a: 22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case changed := <-changes:
+		if !reflect.DeepEqual(changed, []string{template}) {
+			t.Fatalf("unexpected changes: %v", changed)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the change")
+	}
+	<-done
+}
+
+func TestPlanServeFlagValidation(t *testing.T) {
+	chartDir := t.TempDir()
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--serve", ":8088"}, want: "--serve requires --visualize"},
+		{args: []string{"--visualize", "--serve", ":8088", "--output", "plan.html"}, want: "--output cannot be combined with --serve"},
+		{args: []string{"--visualize", "--serve", ":8088", "-f", "-"}, want: "cannot re-read values from stdin"},
+		{args: []string{"--visualize", "--serve", ":8088", "--chart", "bitnami/nginx"}, want: "needs a local chart directory or values file"},
+	}
+	for _, tc := range cases {
+		var ns, kubeconfig, kubeContext string
+		cmd := newDeployPlanCommand(&ns, &kubeconfig, &kubeContext, "")
+		cmd.SetArgs(append([]string{"--chart", chartDir, "--release", "api"}, tc.args...))
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("args %v: expected %q, got %v", tc.args, tc.want, err)
+		}
+	}
+}
@@ -6,6 +6,10 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -77,7 +81,7 @@ data:
 		}
 	}
 
-	changes, summary := buildPlanChanges(desired, previous, live)
+	changes, summary := buildPlanChanges(desired, previous, live, nil)
 
 	if summary.Creates != 1 || summary.Updates != 1 || summary.Deletes != 1 || summary.Unchanged != 1 {
 		t.Fatalf("unexpected summary: %+v", summary)
@@ -141,7 +145,7 @@ data:
 	desired := docsToMap(parseManifestDocs(desiredManifest))
 	previous := docsToMap(parseManifestDocs(previousManifest))
 
-	changes, summary := buildPlanChanges(desired, previous, nil)
+	changes, summary := buildPlanChanges(desired, previous, nil, nil)
 	if summary.Updates != 1 || summary.Creates != 0 {
 		t.Fatalf("expected one update when falling back, got summary %+v", summary)
 	}
@@ -153,6 +157,99 @@ data:
 	}
 }
 
+func TestBuildPlanChangesTruncatesLargeDiffs(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: huge\n  namespace: default\ndata:\n")
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&b, "  key%04d: %s\n", i, strings.Repeat("x", 40))
+	}
+	desired := docsToMap(parseManifestDocs("---\n" + b.String() + "---\n# empty\n"))
+	if len(desired) != 1 {
+		t.Fatalf("expected one parsed doc, got %d", len(desired))
+	}
+	archive := newPlanDiffArchive(4 << 10)
+	changes, _ := buildPlanChanges(desired, nil, nil, archive)
+	if len(changes) != 1 || !changes[0].DiffTruncated {
+		t.Fatalf("expected a truncated create, got %+v", changes)
+	}
+	if len(changes[0].Diff) > 5<<10 || !strings.Contains(changes[0].Diff, "diff truncated") {
+		t.Fatalf("expected capped diff with marker, got %d bytes", len(changes[0].Diff))
+	}
+	full, ok := archive.Full(graphNodeID(changes[0].Key))
+	if !ok || !strings.Contains(full, "key1999") {
+		t.Fatalf("expected archived full diff, ok=%v", ok)
+	}
+
+	dir := t.TempDir()
+	result := &deployPlanResult{Changes: changes, fullDiffs: archive}
+	if err := writePlanFullDiffs(result, filepath.Join(dir, "plan.html")); err != nil {
+		t.Fatalf("write full diffs: %v", err)
+	}
+	link := result.Changes[0].FullDiffPath
+	if !strings.HasPrefix(link, "plan-diffs/") {
+		t.Fatalf("unexpected full diff link %q", link)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, link))
+	if err != nil || string(raw) != full {
+		t.Fatalf("expected full diff on disk, err=%v", err)
+	}
+	html, err := renderDeployPlanHTML(result)
+	if err != nil {
+		t.Fatalf("render html: %v", err)
+	}
+	if !strings.Contains(html, `href="`+link+`"`) {
+		t.Fatalf("expected html to link the full diff")
+	}
+}
+
+func TestBuildManifestDiffsLinksTruncatedLiveDiffs(t *testing.T) {
+	var live, rendered strings.Builder
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&live, "key%04d: old\n", i)
+		fmt.Fprintf(&rendered, "key%04d: new\n", i)
+	}
+	id := "default|configmap|huge"
+	archive := newPlanDiffArchive(4 << 10)
+	diffs := buildManifestDiffs(
+		newPlanBlobs(map[string]string{id: live.String()}),
+		newPlanBlobs(map[string]string{id: rendered.String()}),
+		archive,
+	)
+	if !strings.Contains(diffs[id], "diff truncated") {
+		t.Fatalf("expected a truncated manifest diff, got %d bytes", len(diffs[id]))
+	}
+
+	dir := t.TempDir()
+	result := &deployPlanResult{
+		ManifestBlobs:     newPlanBlobs(map[string]string{id: rendered.String()}),
+		ManifestDiffs:     diffs,
+		fullManifestDiffs: archive,
+	}
+	if err := writePlanFullDiffs(result, filepath.Join(dir, "plan.json")); err != nil {
+		t.Fatalf("write full diffs: %v", err)
+	}
+	link := result.ManifestDiffFiles[id]
+	if !strings.HasPrefix(link, "plan-diffs/live/") {
+		t.Fatalf("unexpected manifest diff link %q", link)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, link))
+	if err != nil || !strings.Contains(string(raw), "+key1999: new") {
+		t.Fatalf("expected full manifest diff on disk, err=%v", err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded deployPlanResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if body, _ := decoded.ManifestBlobs.Get(id); body != rendered.String() || decoded.ManifestDiffFiles[id] != link {
+		t.Fatalf("expected manifests and links to survive the artifact round trip")
+	}
+}
+
 func mustUnstructured(t *testing.T, body string) *unstructured.Unstructured {
 	t.Helper()
 	var obj map[string]interface{}
@@ -0,0 +1,69 @@
+// File: cmd/ktl/deploy_post_render.go
+// Brief: CLI command wiring and implementation for 'deploy post render'.
+
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/postrender"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// postRenderFlags adds a one-off executable after the deploy.postRenderers from .ktl.yaml.
+type postRenderFlags struct {
+	exec        string
+	args        []string
+	skip        bool
+	imageReport bool
+}
+
+func addPostRenderFlags(cmd *cobra.Command, f *postRenderFlags) {
+	cmd.Flags().StringVar(&f.exec, "post-renderer", "", "Executable that rewrites the rendered manifests (stdin to stdout), run after deploy.postRenderers from .ktl.yaml")
+	cmd.Flags().StringArrayVar(&f.args, "post-renderer-args", nil, "Argument for --post-renderer (can be repeated)")
+	cmd.Flags().BoolVar(&f.skip, "no-post-renderers", false, "Ignore deploy.postRenderers from .ktl.yaml")
+	cmd.Flags().BoolVar(&f.imageReport, "image-report", false, "Print the images rewritten by images post-renderers to stderr")
+}
+
+// printImageReport prints the image rewrites of the last render when --image-report is set.
+func printImageReport(w io.Writer, f postRenderFlags, pr postrender.PostRenderer) {
+	if !f.imageReport {
+		return
+	}
+	deploy.PrintImageRewrites(w, deploy.ImageRewrites(pr))
+}
+
+// loadPostRenderer builds the post-render pipeline shared by template, plan, and apply. extra
+// steps run last, after --post-renderer. settings, when set, supplies the cluster's RESTMapper so
+// inject steps know which kinds are cluster-scoped; discovery only runs if a step asks.
+func loadPostRenderer(ctx context.Context, settings *cli.EnvSettings, f postRenderFlags, extra ...appconfig.PostRendererConfig) (postrender.PostRenderer, error) {
+	var steps []appconfig.PostRendererConfig
+	if !f.skip {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := appconfig.Load(ctx, appconfig.DefaultGlobalPath(), appconfig.DefaultRepoPath(appconfig.FindRepoRoot(cwd)))
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, cfg.Deploy.PostRenderers...)
+	}
+	if exec := strings.TrimSpace(f.exec); exec != "" {
+		steps = append(steps, appconfig.PostRendererConfig{Name: "--post-renderer", Exec: exec, Args: f.args})
+	}
+	steps = append(steps, extra...)
+	var mapper meta.RESTMapper
+	if settings != nil {
+		// Without a usable kubeconfig inject steps fall back to the built-in scope list.
+		mapper, _ = settings.RESTClientGetter().ToRESTMapper()
+	}
+	return deploy.BuildPostRenderer(ctx, steps, mapper)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
+)
+
+// probeReleaseReachability probes the release's Ingress/HTTPRoute hosts, prints one line per
+// host, and mirrors the lines to the deploy stream. HTTPRoute parent Gateways outside the release
+// are read through kubeClient. With require set, an unreachable host fails the apply; a release
+// without probeable hosts only warns.
+func probeReleaseReachability(ctx context.Context, errOut io.Writer, stream *deploy.StreamBroadcaster, kubeClient *kube.Client, manifest, namespace string, require bool) ([]deploy.ProbeResult, error) {
+	var gateways deploy.GatewayGetter
+	if kubeClient != nil {
+		gateways = deploy.DynamicGatewayGetter(ctx, kubeClient.Dynamic)
+	}
+	targets := deploy.ReachabilityTargets(manifest, namespace, gateways)
+	if len(targets) == 0 {
+		fmt.Fprintln(errOut, "Reachability: no Ingress or HTTPRoute hosts to probe")
+		return nil, nil
+	}
+	results := deploy.ProbeReachability(ctx, targets, deploy.ProbeOptions{})
+	now := time.Now()
+	failed := 0
+	fmt.Fprintf(errOut, "Reachability (%d host(s)):\n", len(results))
+	for _, r := range results {
+		mark, level := "ok", "info"
+		if !r.Reachable {
+			mark, level = "FAIL", "warn"
+			failed++
+		}
+		line := fmt.Sprintf("%s %s/%s %s", r.Kind, r.Namespace, r.Name, r.Line(now))
+		fmt.Fprintf(errOut, "  %-4s %s\n", mark, line)
+		stream.EmitEvent(level, "reachability: "+line)
+	}
+	if require && failed > 0 {
+		return results, fmt.Errorf("--require-reachable: %d of %d host(s) unreachable", failed, len(results))
+	}
+	return results, nil
+}
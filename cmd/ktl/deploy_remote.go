@@ -15,6 +15,7 @@ import (
 	"github.com/kubekattle/ktl/internal/api/convert"
 	"github.com/kubekattle/ktl/internal/deploy"
 	"github.com/kubekattle/ktl/internal/grpcutil"
+	"github.com/kubekattle/ktl/internal/ui"
 	apiv1 "github.com/kubekattle/ktl/pkg/api/ktl/api/v1"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
@@ -192,7 +193,7 @@ func renderDeployEvent(out io.Writer, errOut io.Writer, evt deploy.StreamEvent)
 		}
 	case deploy.StreamEventLog:
 		if evt.Log != nil {
-			fmt.Fprintf(errOut, "[%s] %s\n", evt.Log.Level, evt.Log.Message)
+			fmt.Fprintf(errOut, "[%s] %s\n", ui.ColorizeSeverity(evt.Log.Level, evt.Log.Level), evt.Log.Message)
 		}
 	case deploy.StreamEventSummary:
 		if evt.Summary != nil {
@@ -200,6 +201,9 @@ func renderDeployEvent(out io.Writer, errOut io.Writer, evt deploy.StreamEvent)
 			if evt.Summary.Error != "" {
 				fmt.Fprintf(out, "Error: %s\n", evt.Summary.Error)
 			}
+			for _, rs := range evt.Summary.Storage {
+				fmt.Fprintf(out, "Storage: %s %s/%s %s: %s\n", rs.Kind, rs.Namespace, rs.Name, rs.Status, rs.Message)
+			}
 		}
 	default:
 		// ignore other event kinds for remote output
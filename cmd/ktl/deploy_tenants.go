@@ -0,0 +1,305 @@
+// File: cmd/ktl/deploy_tenants.go
+// Brief: `ktl apply --tenants-file`: apply one chart once per tenant namespace.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
+	"sigs.k8s.io/yaml"
+)
+
+// tenantSpec is one entry of a tenants file: where the chart goes and the values layered on top
+// of the shared -f/--set inputs for that tenant.
+type tenantSpec struct {
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Release   string   `json:"release,omitempty"`
+	Values    []string `json:"values,omitempty"`
+	Set       []string `json:"set,omitempty"`
+	SetString []string `json:"setString,omitempty"`
+}
+
+type tenantsFile struct {
+	Tenants []tenantSpec `json:"tenants"`
+}
+
+// tenantsOnlyConflicts are apply flags that only make sense for a single release.
+var tenantsOnlyConflicts = []string{"namespace", "from-capture", "from-capture-session", "ui", "ws-listen", "watch", "capture", "resolve-conflicts", "edited-values-out", "conflict-values-out"}
+
+// tenantsSkipFlags are not forwarded to the per-tenant applies.
+var tenantsSkipFlags = map[string]bool{"tenants-file": true, "tenants-concurrency": true, "namespace": true}
+
+func validateTenantsFlags(cmd *cobra.Command, concurrency int, autoApprove, dryRun bool, remoteAgent *string) error {
+	for _, name := range tenantsOnlyConflicts {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			return fmt.Errorf("--%s cannot be combined with --tenants-file", name)
+		}
+	}
+	// An approval token signs one release's plan, so a shared token cannot approve every tenant.
+	if (cmd.Flags().Changed("approval-token") || strings.TrimSpace(os.Getenv(approvalTokenEnv)) != "") && !dryRun {
+		return fmt.Errorf("--approval-token (and %s) cannot be combined with --tenants-file: a token approves a single tenant's plan, so apply tenants that need approval one at a time", approvalTokenEnv)
+	}
+	if remoteAgent != nil && strings.TrimSpace(*remoteAgent) != "" {
+		return fmt.Errorf("--tenants-file is not supported with --remote-agent")
+	}
+	if concurrency < 1 {
+		return fmt.Errorf("--tenants-concurrency must be >= 1")
+	}
+	if !autoApprove && !dryRun {
+		return fmt.Errorf("--tenants-file applies tenants unattended and requires --yes or --dry-run")
+	}
+	return nil
+}
+
+// loadTenantsFile reads the tenants file, defaults each release to defaultRelease and resolves
+// values paths relative to the file.
+func loadTenantsFile(path, defaultRelease string) ([]tenantSpec, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read tenants file: %w", err)
+	}
+	var file tenantsFile
+	if err := yaml.UnmarshalStrict(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse tenants file %s: %w", path, err)
+	}
+	if len(file.Tenants) == 0 {
+		return nil, fmt.Errorf("tenants file %s lists no tenants", path)
+	}
+	dir := filepath.Dir(path)
+	names := map[string]bool{}
+	targets := map[string]string{}
+	for i := range file.Tenants {
+		t := &file.Tenants[i]
+		t.Namespace = strings.TrimSpace(t.Namespace)
+		if t.Namespace == "" {
+			return nil, fmt.Errorf("tenants file %s: tenant #%d has no namespace", path, i+1)
+		}
+		if t.Name = strings.TrimSpace(t.Name); t.Name == "" {
+			t.Name = t.Namespace
+		}
+		if names[t.Name] {
+			return nil, fmt.Errorf("tenants file %s: duplicate tenant %q", path, t.Name)
+		}
+		names[t.Name] = true
+		if t.Release = strings.TrimSpace(t.Release); t.Release == "" {
+			t.Release = strings.TrimSpace(defaultRelease)
+		}
+		if t.Release == "" {
+			return nil, fmt.Errorf("tenants file %s: tenant %q has no release (set release: or pass --release)", path, t.Name)
+		}
+		target := t.Namespace + "/" + t.Release
+		if other, ok := targets[target]; ok {
+			return nil, fmt.Errorf("tenants file %s: tenants %q and %q both apply release %s", path, other, t.Name, target)
+		}
+		targets[target] = t.Name
+		for j, v := range t.Values {
+			if v == deploy.ValuesStdin {
+				return nil, fmt.Errorf("tenants file %s: tenant %q cannot read values from stdin", path, t.Name)
+			}
+			if !strings.Contains(v, "://") && !filepath.IsAbs(v) {
+				t.Values[j] = filepath.Join(dir, v)
+			}
+		}
+	}
+	return file.Tenants, nil
+}
+
+// tenantApplyArgs rebuilds the flags the user passed to apply, minus the fan-out flags, so every
+// tenant runs with the same shared inputs. The tenants get no stdin, so a shared -f - is replaced
+// with stdinValues, the file the parent read stdin into.
+func tenantApplyArgs(flags *pflag.FlagSet, accepts func(name string) bool, stdinValues string) []string {
+	var args []string
+	flags.Visit(func(f *pflag.Flag) {
+		if tenantsSkipFlags[f.Name] || !accepts(f.Name) {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				if f.Name == "values" && v == deploy.ValuesStdin && stdinValues != "" {
+					v = stdinValues
+				}
+				// StringSlice flags split their value as CSV; quote it so a comma stays in one element.
+				if f.Value.Type() == "stringSlice" && strings.ContainsAny(v, ",\"") {
+					v = `"` + strings.ReplaceAll(v, `"`, `""`) + `"`
+				}
+				args = append(args, fmt.Sprintf("--%s=%s", f.Name, v))
+			}
+			return
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+	return args
+}
+
+// tenantArgs appends the tenant's release and values after the shared ones, so they win.
+func (t tenantSpec) tenantArgs(shared []string) []string {
+	args := append([]string{}, shared...)
+	args = append(args, "--release="+t.Release)
+	for _, v := range t.Values {
+		args = append(args, "--values="+v)
+	}
+	for _, v := range t.Set {
+		args = append(args, "--set="+v)
+	}
+	for _, v := range t.SetString {
+		args = append(args, "--set-string="+v)
+	}
+	return args
+}
+
+// tenantApplyResult is one row of the combined summary.
+type tenantApplyResult struct {
+	Tenant    string
+	Namespace string
+	Release   string
+	Duration  time.Duration
+	Err       error
+}
+
+// tenantApplyFunc applies the chart for one tenant, writing its console output to out.
+type tenantApplyFunc func(ctx context.Context, tenant tenantSpec, out io.Writer) error
+
+// runTenantApplies applies every tenant with at most concurrency applies in flight. Output lines
+// are prefixed with the tenant name; a failed tenant does not stop the others.
+func runTenantApplies(ctx context.Context, errOut io.Writer, tenants []tenantSpec, concurrency int, apply tenantApplyFunc) ([]tenantApplyResult, error) {
+	console := &tenantConsole{w: errOut}
+	results := make([]tenantApplyResult, len(tenants))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(concurrency)
+	for i, t := range tenants {
+		i, t := i, t
+		eg.Go(func() error {
+			results[i] = tenantApplyResult{Tenant: t.Name, Namespace: t.Namespace, Release: t.Release}
+			if egCtx.Err() != nil {
+				results[i].Err = egCtx.Err()
+				return nil
+			}
+			out := console.writer(t.Name)
+			started := time.Now()
+			err := apply(egCtx, t, out)
+			out.Flush()
+			results[i].Duration = time.Since(started)
+			results[i].Err = err
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	writeTenantSummary(errOut, results)
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	return results, tenantAppliesError(results)
+}
+
+// tenantAppliesError summarizes the failed tenants. It wraps deploy.ErrChartTestsFailed only when
+// every failure was a chart test failure, so the exit code stays meaningful.
+func tenantAppliesError(results []tenantApplyResult) error {
+	var failed []string
+	testsOnly := true
+	for _, r := range results {
+		if r.Err == nil {
+			continue
+		}
+		failed = append(failed, r.Tenant)
+		if !errors.Is(r.Err, deploy.ErrChartTestsFailed) {
+			testsOnly = false
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("%d of %d tenants failed: %s", len(failed), len(results), strings.Join(failed, ", "))
+	if testsOnly {
+		return fmt.Errorf("%w: %s", deploy.ErrChartTestsFailed, msg)
+	}
+	return errors.New(msg)
+}
+
+func writeTenantSummary(w io.Writer, results []tenantApplyResult) {
+	ok := 0
+	for _, r := range results {
+		if r.Err == nil {
+			ok++
+		}
+	}
+	fmt.Fprintf(w, "\nTenants: %d succeeded, %d failed\n", ok, len(results)-ok)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TENANT\tNAMESPACE\tRELEASE\tRESULT\tDURATION\tERROR")
+	for _, r := range results {
+		result, msg := "ok", ""
+		if r.Err != nil {
+			result, msg = "FAIL", firstLine(r.Err.Error())
+		}
+		duration := "-"
+		if r.Duration > 0 {
+			duration = r.Duration.Round(time.Second).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Tenant, r.Namespace, r.Release, result, duration, msg)
+	}
+	_ = tw.Flush()
+}
+
+func firstLine(s string) string {
+	if line, _, ok := strings.Cut(s, "\n"); ok {
+		return line
+	}
+	return s
+}
+
+// tenantConsole interleaves the output of concurrent applies line by line.
+type tenantConsole struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (c *tenantConsole) writer(tenant string) *tenantLineWriter {
+	return &tenantLineWriter{console: c, prefix: "[" + tenant + "] "}
+}
+
+// tenantLineWriter buffers partial lines and writes complete ones with the tenant prefix. The
+// apply's stdout and stderr share one writer.
+type tenantLineWriter struct {
+	console *tenantConsole
+	prefix  string
+	buf     bytes.Buffer
+}
+
+func (w *tenantLineWriter) Write(p []byte) (int, error) {
+	w.console.mu.Lock()
+	defer w.console.mu.Unlock()
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			// Keep the incomplete line for the next write.
+			return len(p), nil
+		}
+		fmt.Fprintf(w.console.w, "%s%s", w.prefix, w.buf.Next(i+1))
+	}
+}
+
+// Flush writes a trailing line that did not end in a newline.
+func (w *tenantLineWriter) Flush() {
+	w.console.mu.Lock()
+	defer w.console.mu.Unlock()
+	if w.buf.Len() == 0 {
+		return
+	}
+	fmt.Fprintf(w.console.w, "%s%s\n", w.prefix, w.buf.Bytes())
+	w.buf.Reset()
+}
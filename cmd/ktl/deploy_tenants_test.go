@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+)
+
+func TestLoadTenantsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tenants.yaml")
+	write := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`tenants:
+  - name: acme
+    namespace: acme
+    values: [tenants/acme.yaml, /abs/common.yaml, https://example.com/v.yaml]
+    set: [ingress.host=acme.example.com]
+  - namespace: globex
+    release: shop-globex
+`)
+	tenants, err := loadTenantsFile(path, "shop")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	want := []tenantSpec{
+		{Name: "acme", Namespace: "acme", Release: "shop", Values: []string{filepath.Join(dir, "tenants/acme.yaml"), "/abs/common.yaml", "https://example.com/v.yaml"}, Set: []string{"ingress.host=acme.example.com"}},
+		{Name: "globex", Namespace: "globex", Release: "shop-globex"},
+	}
+	if !reflect.DeepEqual(tenants, want) {
+		t.Fatalf("unexpected tenants:\n got %#v\nwant %#v", tenants, want)
+	}
+
+	for body, msg := range map[string]string{
+		"tenants: []\n":           "lists no tenants",
+		"tenants:\n  - name: a\n": "tenant #1 has no namespace",
+		"tenants:\n  - namespace: a\n  - namespace: a\n    name: a\n": `duplicate tenant "a"`,
+		"tenants:\n  - namespace: a\n  - namespace: a\n    name: b\n": `tenants "a" and "b" both apply release a/shop`,
+		"tenants:\n  - namespace: a\n    valuez: [x]\n":               "unknown field",
+	} {
+		write(body)
+		if _, err := loadTenantsFile(path, "shop"); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%q: expected %q, got %v", body, msg, err)
+		}
+	}
+	write("tenants:\n  - namespace: a\n")
+	if _, err := loadTenantsFile(path, ""); err == nil || !strings.Contains(err.Error(), "has no release") {
+		t.Fatalf("expected a missing release error, got %v", err)
+	}
+}
+
+func TestTenantApplyArgs(t *testing.T) {
+	var ns, kubeconfig, kubeContext, remoteAgent string
+	logLevel := "info"
+	cmd := newDeployApplyCommand(&ns, &kubeconfig, &kubeContext, &logLevel, &remoteAgent, "")
+	if err := cmd.ParseFlags([]string{"--chart", "./chart", "--tenants-file", "t.yaml", "-f", "base.yaml", "--set", "a=1", "--set", "b=2", "--yes", "--timeout", "2m"}); err != nil {
+		t.Fatal(err)
+	}
+	shared := tenantApplyArgs(cmd.Flags(), func(name string) bool { return cmd.LocalFlags().Lookup(name) != nil }, "")
+	tenant := tenantSpec{Name: "acme", Namespace: "acme", Release: "shop", Values: []string{"acme.yaml"}, Set: []string{"b=3"}}
+	got := tenant.tenantArgs(shared)
+	want := []string{"--chart=./chart", "--set=a=1", "--set=b=2", "--timeout=2m0s", "--values=base.yaml", "--yes=true", "--release=shop", "--values=acme.yaml", "--set=b=3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected args:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestTenantApplyArgsRoundTripStdinAndCommas(t *testing.T) {
+	var ns, kubeconfig, kubeContext, remoteAgent string
+	logLevel := "info"
+	parent := newDeployApplyCommand(&ns, &kubeconfig, &kubeContext, &logLevel, &remoteAgent, "")
+	if err := parent.ParseFlags([]string{"--chart", "./chart", "--tenants-file", "t.yaml", "-f", "-", "-f", `"dir,with,commas/values.yaml"`, "--yes"}); err != nil {
+		t.Fatal(err)
+	}
+	shared := tenantApplyArgs(parent.Flags(), func(name string) bool { return parent.LocalFlags().Lookup(name) != nil }, "/tmp/ktl-values-1/stdin.yaml")
+
+	child := newDeployApplyCommand(&ns, &kubeconfig, &kubeContext, &logLevel, &remoteAgent, "")
+	if err := child.ParseFlags(shared); err != nil {
+		t.Fatalf("parse forwarded args %v: %v", shared, err)
+	}
+	got, err := child.Flags().GetStringSlice("values")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/tmp/ktl-values-1/stdin.yaml", "dir,with,commas/values.yaml"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tenant values = %v, want %v", got, want)
+	}
+}
+
+func TestApplyTenantsFlagValidation(t *testing.T) {
+	t.Setenv(approvalTokenEnv, "")
+	var kubeconfig, kubeContext, remoteAgent string
+	logLevel := "info"
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--chart", "./chart", "--tenants-file", "t.yaml"}, want: "requires --yes or --dry-run"},
+		{args: []string{"--chart", "./chart", "--tenants-file", "t.yaml", "--yes", "--watch", "1m"}, want: "--watch cannot be combined with --tenants-file"},
+		{args: []string{"--chart", "./chart", "--tenants-file", "t.yaml", "--dry-run", "--tenants-concurrency", "0"}, want: "--tenants-concurrency must be >= 1"},
+		{args: []string{"--chart", "./chart", "--tenants-file", "t.yaml", "--yes", "--approval-token", "ktlapprove1.x.y"}, want: "--approval-token (and KTL_APPROVAL_TOKEN) cannot be combined with --tenants-file"},
+		{args: []string{"--chart", "./chart", "--tenants-file", "missing.yaml", "--dry-run"}, want: "read tenants file"},
+	}
+	for _, tc := range cases {
+		var ns string
+		cmd := newDeployApplyCommand(&ns, &kubeconfig, &kubeContext, &logLevel, &remoteAgent, "")
+		cmd.SetArgs(tc.args)
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("args %v: expected %q, got %v", tc.args, tc.want, err)
+		}
+	}
+}
+
+func TestRunTenantApplies(t *testing.T) {
+	tenants := []tenantSpec{
+		{Name: "acme", Namespace: "acme", Release: "shop"},
+		{Name: "globex", Namespace: "globex", Release: "shop"},
+		{Name: "initech", Namespace: "initech", Release: "shop"},
+	}
+	apply := func(fail map[string]error) tenantApplyFunc {
+		return func(ctx context.Context, tenant tenantSpec, out io.Writer) error {
+			fmt.Fprintf(out, "deploying %s\npartial", tenant.Namespace)
+			return fail[tenant.Name]
+		}
+	}
+
+	var out bytes.Buffer
+	results, err := runTenantApplies(context.Background(), &out, tenants, 2, apply(map[string]error{
+		"globex": errors.New("upgrade failed\ndetails"),
+	}))
+	if err == nil || err.Error() != "1 of 3 tenants failed: globex" || errors.Is(err, deploy.ErrChartTestsFailed) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 3 || results[1].Err == nil || results[0].Err != nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	for _, want := range []string{
+		"[acme] deploying acme\n[acme] partial\n",
+		"[initech] deploying initech\n",
+		"Tenants: 2 succeeded, 1 failed",
+		"globex   globex     shop     FAIL",
+		"upgrade failed\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "details") {
+		t.Errorf("expected only the first error line in the summary:\n%s", out.String())
+	}
+
+	_, err = runTenantApplies(context.Background(), io.Discard, tenants, 1, apply(map[string]error{
+		"acme": fmt.Errorf("%w: 1 of 2 failed", deploy.ErrChartTestsFailed),
+	}))
+	if !errors.Is(err, deploy.ErrChartTestsFailed) {
+		t.Fatalf("expected chart test failures to keep their exit code, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+// File: cmd/ktl/deploy_values.go
+// Brief: CLI command wiring and implementation for 'deploy values'.
+
+package main
+
+import (
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+// resolveValuesFlag materializes `-f -`, `-f https://...`, and `-f oci://...` into local files and
+// checks `#sha256=` pins. Call cleanup once the files are no longer needed.
+func resolveValuesFlag(cmd *cobra.Command, settings *cli.EnvSettings, sources []string, secrets *deploy.SecretOptions) ([]string, func(), error) {
+	return deploy.ResolveValuesSources(cmd.Context(), sources, deploy.ValuesSourceOptions{
+		Settings: settings,
+		Stdin:    cmd.InOrStdin(),
+		Secrets:  secrets,
+	})
+}
@@ -0,0 +1,146 @@
+// File: cmd/ktl/deploy_values_edit.go
+// Brief: Edit the merged apply values in $EDITOR and re-plan with them.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// valuesEditSession keeps the overlay file that replaces the values inputs once they were edited.
+type valuesEditSession struct {
+	path    string
+	saveTo  string
+	editor  string
+	in      io.Reader
+	out     io.Writer
+	written bool
+}
+
+func newValuesEditSession(in io.Reader, out io.Writer, saveTo string) *valuesEditSession {
+	return &valuesEditSession{in: in, out: out, saveTo: strings.TrimSpace(saveTo), editor: valuesEditor()}
+}
+
+// valuesEditor picks the editor command: $VISUAL, then $EDITOR, then vi.
+func valuesEditor() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return v
+		}
+	}
+	return "vi"
+}
+
+// Edit writes vals (the merged user values) to the overlay, opens it in the editor until it parses
+// as YAML and reports whether the content changed. problem, when set, is shown as a comment at
+// the top of the file.
+func (s *valuesEditSession) Edit(ctx context.Context, vals map[string]interface{}, problem string) (bool, error) {
+	body, err := yaml.Marshal(vals)
+	if err != nil {
+		return false, fmt.Errorf("encode values: %w", err)
+	}
+	if len(vals) == 0 {
+		body = nil
+	}
+	if s.path == "" {
+		f, err := os.CreateTemp("", "ktl-values-edit-*.yaml")
+		if err != nil {
+			return false, err
+		}
+		s.path = f.Name()
+		_ = f.Close()
+	}
+	original := string(body)
+	content := valuesEditHeader(problem) + original
+	for {
+		if err := os.WriteFile(s.path, []byte(content), 0o600); err != nil {
+			return false, err
+		}
+		if err := s.runEditor(ctx); err != nil {
+			return false, err
+		}
+		raw, err := os.ReadFile(s.path)
+		if err != nil {
+			return false, err
+		}
+		edited := stripValuesEditHeader(string(raw))
+		var parsed map[string]interface{}
+		if err := yaml.Unmarshal([]byte(edited), &parsed); err != nil {
+			content = valuesEditHeader("invalid YAML: "+err.Error()) + edited
+			fmt.Fprintf(s.out, "Edited values are not valid YAML (%v); reopening the editor.\n", err)
+			continue
+		}
+		if edited == original {
+			fmt.Fprintln(s.out, "Values unchanged.")
+			return false, os.WriteFile(s.path, []byte(edited), 0o600)
+		}
+		if err := os.WriteFile(s.path, []byte(edited), 0o600); err != nil {
+			return false, err
+		}
+		s.written = true
+		if s.saveTo != "" {
+			if err := os.WriteFile(s.saveTo, []byte(edited), 0o600); err != nil {
+				return false, fmt.Errorf("save edited values: %w", err)
+			}
+		}
+		return true, nil
+	}
+}
+
+func (s *valuesEditSession) runEditor(ctx context.Context) error {
+	fields := strings.Fields(s.editor)
+	if len(fields) == 0 {
+		return fmt.Errorf("no editor configured (set $EDITOR)")
+	}
+	cmd := exec.CommandContext(ctx, fields[0], append(fields[1:], s.path)...)
+	cmd.Stdin = s.in
+	cmd.Stdout = s.out
+	cmd.Stderr = s.out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s: %w", fields[0], err)
+	}
+	return nil
+}
+
+// Report tells the user where the edited values are, so they can be kept with -f.
+func (s *valuesEditSession) Report() {
+	if s == nil || !s.written {
+		return
+	}
+	if s.saveTo != "" {
+		fmt.Fprintf(s.out, "Edited values saved to %s (reuse them with -f %s).\n", s.saveTo, s.saveTo)
+		return
+	}
+	fmt.Fprintf(s.out, "Edited values are in %s (reuse them with -f, or pass --edited-values-out to choose the path).\n", s.path)
+}
+
+const valuesEditMarker = "# --- ktl: lines above this marker are ignored ---"
+
+func valuesEditHeader(problem string) string {
+	var b bytes.Buffer
+	b.WriteString("# Merged values from -f and --set flags (chart defaults are not shown).\n")
+	b.WriteString("# Saving replaces those inputs for this apply; ktl re-validates and re-plans.\n")
+	if problem = strings.TrimSpace(problem); problem != "" {
+		b.WriteString("#\n")
+		for _, line := range strings.Split(problem, "\n") {
+			b.WriteString("# " + line + "\n")
+		}
+	}
+	b.WriteString(valuesEditMarker + "\n")
+	return b.String()
+}
+
+func stripValuesEditHeader(content string) string {
+	if _, rest, ok := strings.Cut(content, valuesEditMarker+"\n"); ok {
+		return rest
+	}
+	return content
+}
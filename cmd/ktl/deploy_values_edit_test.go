@@ -0,0 +1,84 @@
+// File: cmd/ktl/deploy_values_edit_test.go
+// Brief: Tests for editing apply values in $EDITOR.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestValuesEditSession_ReopensOnInvalidYAMLAndSaves(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the editor")
+	}
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	// The first edit breaks the YAML, the second fixes the type the schema complained about.
+	editor := filepath.Join(dir, "edit.sh")
+	script := `#!/bin/sh
+echo x >> "` + calls + `"
+n=$(wc -l < "` + calls + `")
+if [ "$n" -eq 1 ]; then
+  grep -q 'expects boolean' "$1" || exit 3
+  printf 'enabled: [\n' >> "$1"
+else
+  grep -q 'invalid YAML' "$1" || exit 4
+  sed -i.bak -e '/^enabled: \[$/d' -e 's/^enabled: .*/enabled: true/' "$1"
+fi
+`
+	if err := os.WriteFile(editor, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", editor)
+	saveTo := filepath.Join(dir, "edited.yaml")
+	out := &bytes.Buffer{}
+	s := newValuesEditSession(strings.NewReader(""), out, saveTo)
+	defer func() {
+		_ = os.Remove(s.path)
+		_ = os.Remove(s.path + ".bak")
+	}()
+
+	changed, err := s.Edit(context.Background(), map[string]interface{}{"enabled": "true", "replicas": 2}, `--set-string enabled=true: values.schema.json expects boolean at enabled`)
+	if err != nil || !changed {
+		t.Fatalf("Edit: changed=%v err=%v\n%s", changed, err, out.String())
+	}
+	got, err := os.ReadFile(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "enabled: true\nreplicas: 2\n" {
+		t.Fatalf("unexpected overlay:\n%s", got)
+	}
+	if saved, _ := os.ReadFile(saveTo); string(saved) != string(got) {
+		t.Fatalf("expected the overlay to be saved to %s, got %q", saveTo, saved)
+	}
+	if !strings.Contains(out.String(), "not valid YAML") {
+		t.Fatalf("expected a reopen notice, got %q", out.String())
+	}
+	s.Report()
+	if !strings.Contains(out.String(), "Edited values saved to "+saveTo) {
+		t.Fatalf("expected the saved path to be reported, got %q", out.String())
+	}
+}
+
+func TestValuesEditSession_UnchangedKeepsInputs(t *testing.T) {
+	t.Setenv("VISUAL", "true")
+	out := &bytes.Buffer{}
+	s := newValuesEditSession(strings.NewReader(""), out, "")
+	defer func() { _ = os.Remove(s.path) }()
+	changed, err := s.Edit(context.Background(), map[string]interface{}{"a": 1}, "")
+	if err != nil || changed {
+		t.Fatalf("expected an unchanged edit, got changed=%v err=%v", changed, err)
+	}
+	s.Report()
+	if strings.Contains(out.String(), "Edited values") {
+		t.Fatalf("nothing should be reported without an edit, got %q", out.String())
+	}
+}
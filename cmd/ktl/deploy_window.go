@@ -0,0 +1,179 @@
+// File: cmd/ktl/deploy_window.go
+// Brief: CLI command wiring and implementation for 'deploy window'.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/audit"
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// windowOverrideAuditAnnotation carries a --override-window to the command's audit entry.
+const windowOverrideAuditAnnotation = "ktl.dev/audit-window-override"
+
+// windowTarget is a kube context and namespace a command is about to change.
+type windowTarget struct {
+	Context   string
+	Namespace string
+}
+
+func (t windowTarget) String() string {
+	return orDash(t.Context) + "/" + orDash(t.Namespace)
+}
+
+// stackWindowTargets lists the context and namespace of every node in the plan, resolving the
+// kube context the same way the stack runner does.
+func stackWindowTargets(p *stack.Plan, kubeconfig, kubeContext string) []windowTarget {
+	return releaseWindowTargets(p.Nodes, kubeconfig, kubeContext)
+}
+
+// releaseWindowTargets lists the context and namespace of each resolved stack release.
+func releaseWindowTargets(nodes []*stack.ResolvedRelease, kubeconfig, kubeContext string) []windowTarget {
+	current := map[string]string{}
+	var out []windowTarget
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+		kctx := strings.TrimSpace(n.Cluster.Context)
+		if kctx == "" {
+			kctx = strings.TrimSpace(kubeContext)
+		}
+		if kctx == "" {
+			path := strings.TrimSpace(n.Cluster.Kubeconfig)
+			if path == "" {
+				path = strings.TrimSpace(kubeconfig)
+			} else if rest, ok := strings.CutPrefix(path, "~/"); ok {
+				if home, err := os.UserHomeDir(); err == nil {
+					path = filepath.Join(home, rest)
+				}
+			}
+			name, ok := current[path]
+			if !ok {
+				name = currentKubeContext(path)
+				current[path] = name
+			}
+			kctx = name
+		}
+		out = append(out, windowTarget{Context: kctx, Namespace: strings.TrimSpace(n.Namespace)})
+	}
+	return out
+}
+
+// kubeconfigWindowTarget resolves the context and namespace from the local kubeconfig, for
+// applies that a remote agent carries out.
+func kubeconfigWindowTarget(kubeconfig, kubeContext, namespace string) windowTarget {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if strings.TrimSpace(kubeconfig) != "" {
+		rules.ExplicitPath = kubeconfig
+	}
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: strings.TrimSpace(kubeContext)})
+	target := windowTarget{Context: strings.TrimSpace(kubeContext), Namespace: strings.TrimSpace(namespace)}
+	if target.Context == "" {
+		if raw, err := loader.RawConfig(); err == nil {
+			target.Context = raw.CurrentContext
+		}
+	}
+	if target.Namespace == "" {
+		if ns, _, err := loader.Namespace(); err == nil {
+			target.Namespace = ns
+		}
+	}
+	return target
+}
+
+// validateWindowOverride requires --override-window and --reason to be used together.
+func validateWindowOverride(override bool, reason string) error {
+	switch {
+	case override && strings.TrimSpace(reason) == "":
+		return fmt.Errorf("--override-window requires --reason")
+	case !override && strings.TrimSpace(reason) != "":
+		return fmt.Errorf("--reason is only used with --override-window")
+	}
+	return nil
+}
+
+// enforceDeployWindows fails when a target is outside its deploy.windows, naming the next
+// allowed slot. With override the deploy proceeds and the reason is attached to cmd for the
+// audit log.
+func enforceDeployWindows(cmd *cobra.Command, errOut io.Writer, cfg []appconfig.DeployWindow, targets []windowTarget, override bool, reason string, now time.Time) error {
+	if len(cfg) == 0 {
+		return nil
+	}
+	windows, err := deploy.CompileWindows(cfg)
+	if err != nil {
+		return err
+	}
+	var blocked []string
+	names := map[string]bool{}
+	var next time.Time
+	nextKnown := true
+	seen := map[windowTarget]bool{}
+	for _, t := range targets {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		res := deploy.CheckWindows(windows, t.Context, t.Namespace, now)
+		if res.Allowed {
+			continue
+		}
+		for _, n := range res.Windows {
+			names[n] = true
+		}
+		slot := "no allowed slot in the next five years"
+		if res.Next.IsZero() {
+			nextKnown = false
+		} else {
+			slot = "next allowed " + formatWindowSlot(res.Next, now)
+			if res.Next.After(next) {
+				next = res.Next
+			}
+		}
+		blocked = append(blocked, fmt.Sprintf("%s (%s): %s", t, strings.Join(res.Windows, ", "), slot))
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+	if !override {
+		return fmt.Errorf("outside the deploy window (re-run with --override-window --reason \"...\" to deploy anyway):\n  %s", strings.Join(blocked, "\n  "))
+	}
+	fmt.Fprintf(errOut, "Warning: deploying outside the deploy window (%s): %s\n", strings.TrimSpace(reason), strings.Join(blocked, "; "))
+	record := audit.WindowOverride{Reason: strings.TrimSpace(reason)}
+	for n := range names {
+		record.Windows = append(record.Windows, n)
+	}
+	sort.Strings(record.Windows)
+	for _, b := range blocked {
+		record.Targets = append(record.Targets, strings.SplitN(b, " ", 2)[0])
+	}
+	if nextKnown {
+		record.NextSlot = next.UTC()
+	}
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[windowOverrideAuditAnnotation] = string(raw)
+	return nil
+}
+
+func formatWindowSlot(next, now time.Time) string {
+	return fmt.Sprintf("%s (in %s)", next.Format("Mon 2006-01-02 15:04 MST"), next.Sub(now).Round(time.Minute))
+}
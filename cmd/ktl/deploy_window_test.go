@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/audit"
+	"github.com/spf13/cobra"
+)
+
+func TestEnforceDeployWindows(t *testing.T) {
+	windows := []appconfig.DeployWindow{{Name: "weekdays", Contexts: []string{"prod"}, Schedule: "* 9-16 * * mon-fri", Timezone: "UTC"}}
+	saturday := time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC)
+	targets := []windowTarget{{Context: "prod", Namespace: "web"}, {Context: "dev", Namespace: "web"}}
+
+	cmd := &cobra.Command{}
+	err := enforceDeployWindows(cmd, &bytes.Buffer{}, windows, targets, false, "", saturday)
+	if err == nil || !strings.Contains(err.Error(), "prod/web (weekdays): next allowed Mon 2026-03-09 09:00 UTC (in 45h0m0s)") {
+		t.Fatalf("expected window error, got %v", err)
+	}
+	if err := enforceDeployWindows(cmd, &bytes.Buffer{}, windows, targets[1:], false, "", saturday); err != nil {
+		t.Fatalf("unselected context: %v", err)
+	}
+
+	var warn bytes.Buffer
+	if err := enforceDeployWindows(cmd, &warn, windows, targets, true, "hotfix INC-42", saturday); err != nil {
+		t.Fatalf("override: %v", err)
+	}
+	if !strings.Contains(warn.String(), "hotfix INC-42") {
+		t.Fatalf("expected a warning, got %q", warn.String())
+	}
+	var record audit.WindowOverride
+	if err := json.Unmarshal([]byte(cmd.Annotations[windowOverrideAuditAnnotation]), &record); err != nil {
+		t.Fatalf("annotation: %v", err)
+	}
+	if record.Reason != "hotfix INC-42" || strings.Join(record.Windows, ",") != "weekdays" || strings.Join(record.Targets, ",") != "prod/web" {
+		t.Fatalf("record = %+v", record)
+	}
+	if !record.NextSlot.Equal(time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("next slot = %s", record.NextSlot)
+	}
+}
+
+func TestValidateWindowOverride(t *testing.T) {
+	if err := validateWindowOverride(true, " "); err == nil {
+		t.Fatalf("expected --reason to be required")
+	}
+	if err := validateWindowOverride(false, "why"); err == nil {
+		t.Fatalf("expected --reason without --override-window to fail")
+	}
+	if err := validateWindowOverride(true, "why"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRemoteAgentApplyChecksDeployWindows(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	cfg := `apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: prod
+  cluster: {server: "https://127.0.0.1:1"}
+contexts:
+- name: prod
+  context: {cluster: prod, namespace: shop}
+users: []
+`
+	if err := os.WriteFile(kubeconfig, []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := kubeconfigWindowTarget(kubeconfig, "", ""); got != (windowTarget{Context: "prod", Namespace: "shop"}) {
+		t.Fatalf("kubeconfig target = %+v", got)
+	}
+	repo := t.TempDir()
+	window := "deploy:\n  windows:\n    - name: never\n      contexts: [prod]\n      namespaces: [shop]\n      schedule: \"* * 31 2 *\"\n"
+	if err := os.WriteFile(filepath.Join(repo, ".ktl.yaml"), []byte(window), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(repo)
+
+	root := newRootCommand()
+	root.SetArgs([]string{"apply", "--kubeconfig", kubeconfig, "--chart", "./chart", "--release", "web", "--remote-agent", "127.0.0.1:1", "--yes"})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	err := root.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "outside the deploy window") || !strings.Contains(err.Error(), "prod/shop") {
+		t.Fatalf("expected the deploy window to block the remote apply, got %v", err)
+	}
+}
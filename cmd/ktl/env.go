@@ -113,6 +113,7 @@ func newEnvCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&onlySet, "set", false, "Show only variables with a non-empty value")
 	cmd.Flags().StringVar(&category, "category", "", "Filter to a category (case-insensitive)")
 	cmd.Flags().StringVar(&match, "match", "", "Filter by substring match against name/category/description")
+	cmd.AddCommand(newEnvDiffCommand())
 	decorateCommandHelp(cmd, "Diagnostics")
 	return cmd
 }
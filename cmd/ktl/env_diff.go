@@ -0,0 +1,120 @@
+// File: cmd/ktl/env_diff.go
+// Brief: CLI command wiring and implementation for 'env diff'.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/spf13/cobra"
+)
+
+func newEnvDiffCommand() *cobra.Command {
+	var stackDir string
+	var left, right stack.EnvSide
+	format := "text"
+	var outputPath string
+	var showValues bool
+
+	cmd := &cobra.Command{
+		Use:   "diff [LEFT RIGHT]",
+		Short: "Compare a stack compiled for two environments",
+		Long: `Compile the stack twice, once per environment, and report what differs: releases that only
+exist on one side, cluster/namespace/chart/chartVersion/tags/needs changes, and Helm values
+keys that were added, removed, or changed after merging each release's values files and set.
+
+An environment is a profile plus overlay files (-f). LEFT and RIGHT are shorthand for
+--left-profile and --right-profile and also label the output. Values are hidden unless
+--show-values is set, since values files often carry credentials.`,
+		Example: `  # Profiles from stack.yaml
+  ktl env diff staging prod --stack ./stacks/platform
+
+  # Overlay files, as passed to ktl stack -f
+  ktl env diff --left-overlay envs/staging.yaml --right-overlay envs/prod.yaml
+
+  # Shareable report
+  ktl env diff staging prod --format html --output env-diff.html`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 && len(args) != 2 {
+				return fmt.Errorf("'ktl env diff' takes two environments (LEFT RIGHT) or none, got %d", len(args))
+			}
+			if len(args) == 2 && (cmd.Flags().Changed("left-profile") || cmd.Flags().Changed("right-profile")) {
+				return fmt.Errorf("LEFT RIGHT are profile names; do not combine them with --left-profile/--right-profile")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 2 {
+				left.Profile, right.Profile = args[0], args[1]
+			}
+			left.Label = envSideLabel(left, "left")
+			right.Label = envSideLabel(right, "right")
+			if left.Profile == right.Profile && strings.Join(left.Overlays, "\x00") == strings.Join(right.Overlays, "\x00") {
+				return fmt.Errorf("both sides use the same profile and overlays; pass two profiles or --left-overlay/--right-overlay")
+			}
+
+			u, err := stack.Discover(stackDir)
+			if err != nil {
+				return err
+			}
+			diff, err := stack.DiffEnvironments(u, left, right, stack.EnvDiffOptions{ShowValues: showValues})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if path := strings.TrimSpace(outputPath); path != "" && path != "-" {
+				f, err := os.Create(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+			return writeEnvDiff(out, diff, format)
+		},
+	}
+	cmd.Flags().StringVar(&stackDir, "stack", ".", "Stack root directory")
+	cmd.Flags().StringVar(&left.Profile, "left-profile", "", "Profile for the left environment")
+	cmd.Flags().StringVar(&right.Profile, "right-profile", "", "Profile for the right environment")
+	cmd.Flags().StringArrayVar(&left.Overlays, "left-overlay", nil, "Overlay file for the left environment (repeatable; later files win)")
+	cmd.Flags().StringArrayVar(&right.Overlays, "right-overlay", nil, "Overlay file for the right environment (repeatable; later files win)")
+	cmd.Flags().Var(newEnumStringValue(&format, "text", "json", "html"), "format", "Output format: text, json, or html")
+	cmd.Flags().StringVar(&outputPath, "output", "", "Write the report to this file instead of stdout")
+	cmd.Flags().BoolVar(&showValues, "show-values", false, "Include the differing values, not just their keys")
+	decorateCommandHelp(cmd, "Diff Flags")
+	return cmd
+}
+
+// envSideLabel names a side after its profile, else its last overlay file, else fallback.
+func envSideLabel(side stack.EnvSide, fallback string) string {
+	if side.Profile != "" {
+		return side.Profile
+	}
+	if n := len(side.Overlays); n > 0 {
+		base := filepath.Base(side.Overlays[n-1])
+		return strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	return fallback
+}
+
+func writeEnvDiff(w io.Writer, diff *stack.EnvDiff, format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "text":
+		return stack.PrintEnvDiff(w, diff)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff)
+	case "html":
+		return stack.PrintEnvDiffHTML(w, diff)
+	default:
+		return fmt.Errorf("unsupported --format %q (expected text, json, or html)", format)
+	}
+}
@@ -141,3 +141,47 @@ func TestEnvCommandJSONFormat(t *testing.T) {
 		t.Fatalf("expected JSON output to include KTL_CONFIG, got:\n%s", got)
 	}
 }
+
+func TestEnvDiffCommandComparesProfiles(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("{}\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("KTL_CONFIG", cfgPath)
+	stackDir := t.TempDir()
+	stackYAML := `apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+profiles:
+  staging:
+    defaults:
+      set: { replicas: "1" }
+  prod:
+    defaults:
+      namespace: apps-prod
+      set: { replicas: "3" }
+defaults:
+  cluster: { name: c1 }
+  namespace: apps
+releases:
+  - name: api
+    chart: ./charts/api
+`
+	if err := os.WriteFile(filepath.Join(stackDir, "stack.yaml"), []byte(stackYAML), 0o644); err != nil {
+		t.Fatalf("write stack: %v", err)
+	}
+
+	root := newRootCommand()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"env", "diff", "staging", "prod", "--stack", stackDir})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	for _, want := range []string{"Comparing staging → prod", "namespace: apps → apps-prod", "~ values.replicas", "1 release(s) differ"} {
+		if !bytes.Contains(out.Bytes(), []byte(want)) {
+			t.Fatalf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/containerd/platforms"
 	"github.com/distribution/reference"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 type enumStringValue struct {
@@ -109,6 +110,44 @@ func (v *nonNegativeIntValue) Set(s string) error {
 
 func (v *nonNegativeIntValue) Type() string { return "int" }
 
+// byteSizeValue parses Kubernetes-style quantities (e.g. 500Mi, 2G) into a byte count.
+type byteSizeValue struct {
+	dest *int64
+	raw  string
+}
+
+func (v *byteSizeValue) String() string {
+	if v == nil || v.dest == nil || *v.dest == 0 {
+		return ""
+	}
+	if v.raw != "" {
+		return v.raw
+	}
+	return strconv.FormatInt(*v.dest, 10)
+}
+
+func (v *byteSizeValue) Set(s string) error {
+	raw := strings.TrimSpace(s)
+	if raw == "" || raw == "0" {
+		*v.dest = 0
+		v.raw = ""
+		return nil
+	}
+	q, err := resource.ParseQuantity(raw)
+	if err != nil {
+		return fmt.Errorf("must be a size like 500Mi or 2G")
+	}
+	n, ok := q.AsInt64()
+	if !ok || n < 0 {
+		return fmt.Errorf("must be a non-negative size")
+	}
+	*v.dest = n
+	v.raw = raw
+	return nil
+}
+
+func (v *byteSizeValue) Type() string { return "size" }
+
 type validatedStringArrayValue struct {
 	dest      *[]string
 	validator func(string) error
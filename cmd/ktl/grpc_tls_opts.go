@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/kubekattle/ktl/internal/netconfig"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
@@ -46,7 +47,10 @@ func remoteTransportCredentials(cmd *cobra.Command, target string) (credentials.
 		tlsCfg.ServerName = strings.TrimSpace(serverName)
 	}
 	if !skipVerify {
-		pool, _ := x509.SystemCertPool()
+		pool := netconfig.RootCAs()
+		if pool == nil {
+			pool, _ = x509.SystemCertPool()
+		}
 		if pool == nil {
 			pool = x509.NewCertPool()
 		}
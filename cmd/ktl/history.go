@@ -0,0 +1,380 @@
+// File: cmd/ktl/history.go
+// Brief: CLI command wiring and implementation for 'history'.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func newHistoryCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Inspect the revision history of a Helm release",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newHistoryDiffCommand(kubeconfig, kubeContext))
+	return cmd
+}
+
+func newHistoryDiffCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
+	var namespace string
+	var releaseName string
+	var fromRevision, toRevision int
+	format := "text"
+	var outputPath string
+	var showValues bool
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show what changed between two revisions of a Helm release",
+		Long: `Compare two stored revisions of a release: chart, chart version, and app version changes,
+the Helm values keys that were added, removed, or changed, and a per-resource manifest diff.
+
+--to defaults to the latest revision and --from to the one before it. Values are hidden unless
+--show-values is set, since they often carry credentials. Use --format html for a report to share.`,
+		Example: `  # What changed in the last upgrade
+  ktl history diff --release checkout -n prod
+
+  # Changelog between two revisions, as a shareable page
+  ktl history diff --release checkout -n prod --from 12 --to 14 --format html --output checkout-12-14.html`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			kubeClient, err := kube.New(ctx, *kubeconfig, *kubeContext)
+			if err != nil {
+				return err
+			}
+			resolvedNamespace := strings.TrimSpace(namespace)
+			if resolvedNamespace == "" {
+				resolvedNamespace = kubeClient.Namespace
+			}
+			if resolvedNamespace == "" {
+				resolvedNamespace = "default"
+			}
+
+			settings := cli.New()
+			if kubeconfig != nil && *kubeconfig != "" {
+				settings.KubeConfig = *kubeconfig
+			}
+			if kubeContext != nil && *kubeContext != "" {
+				settings.KubeContext = *kubeContext
+			}
+			settings.SetNamespace(resolvedNamespace)
+			actionCfg := new(action.Configuration)
+			if err := actionCfg.Init(settings.RESTClientGetter(), resolvedNamespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+				return fmt.Errorf("init helm action config: %w", err)
+			}
+
+			from, to, err := loadHistoryRevisions(actionCfg, releaseName, fromRevision, toRevision)
+			if err != nil {
+				return err
+			}
+			diff := buildHistoryDiff(from, to, showValues)
+
+			out := cmd.OutOrStdout()
+			if path := strings.TrimSpace(outputPath); path != "" && path != "-" {
+				f, err := os.Create(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+			return writeHistoryDiff(out, diff, format)
+		},
+	}
+	cmd.Flags().StringVar(&releaseName, "release", "", "Helm release name")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the Helm release (defaults to active context)")
+	cmd.Flags().IntVar(&fromRevision, "from", 0, "Older revision (default: the revision before --to)")
+	cmd.Flags().IntVar(&toRevision, "to", 0, "Newer revision (default: the latest revision)")
+	cmd.Flags().Var(newEnumStringValue(&format, "text", "json", "html"), "format", "Output format: text, json, or html")
+	cmd.Flags().StringVar(&outputPath, "output", "", "Write the report to this file instead of stdout")
+	cmd.Flags().BoolVar(&showValues, "show-values", false, "Include the differing values, not just their keys")
+	_ = cmd.MarkFlagRequired("release")
+	decorateCommandHelp(cmd, "History Flags")
+	return cmd
+}
+
+// loadHistoryRevisions fetches the two revisions to compare, defaulting to the latest revision
+// and the one before it.
+func loadHistoryRevisions(actionCfg *action.Configuration, releaseName string, fromRevision, toRevision int) (*release.Release, *release.Release, error) {
+	releaseName = strings.TrimSpace(releaseName)
+	if releaseName == "" {
+		return nil, nil, fmt.Errorf("--release is required")
+	}
+	if fromRevision < 0 || toRevision < 0 {
+		return nil, nil, fmt.Errorf("revisions must be positive")
+	}
+	get := func(version int) (*release.Release, error) {
+		getAction := action.NewGet(actionCfg)
+		getAction.Version = version
+		rel, err := getAction.Run(releaseName)
+		if err != nil {
+			if errors.Is(err, driver.ErrReleaseNotFound) {
+				if version == 0 {
+					return nil, fmt.Errorf("release %s not found", releaseName)
+				}
+				return nil, fmt.Errorf("release %s has no revision %d", releaseName, version)
+			}
+			return nil, fmt.Errorf("helm get %s: %w", releaseName, err)
+		}
+		return rel, nil
+	}
+	to, err := get(toRevision)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fromRevision == 0 {
+		fromRevision = to.Version - 1
+		if fromRevision < 1 {
+			return nil, nil, fmt.Errorf("release %s has a single revision; nothing to compare", releaseName)
+		}
+	}
+	if fromRevision == to.Version {
+		return nil, nil, fmt.Errorf("--from and --to are both revision %d", fromRevision)
+	}
+	from, err := get(fromRevision)
+	if err != nil {
+		return nil, nil, err
+	}
+	return from, to, nil
+}
+
+// historyRevision describes one side of a history diff.
+type historyRevision struct {
+	Revision     int       `json:"revision"`
+	Status       string    `json:"status,omitempty"`
+	Chart        string    `json:"chart,omitempty"`
+	ChartVersion string    `json:"chartVersion,omitempty"`
+	AppVersion   string    `json:"appVersion,omitempty"`
+	Deployed     time.Time `json:"deployed"`
+	Description  string    `json:"description,omitempty"`
+}
+
+// historyDiff is the changelog between two revisions of a release. Changes and Summary use the
+// same shapes as apply plan, so both share the diff rendering.
+type historyDiff struct {
+	APIVersion string               `json:"apiVersion"`
+	Release    string               `json:"release"`
+	Namespace  string               `json:"namespace"`
+	From       historyRevision      `json:"from"`
+	To         historyRevision      `json:"to"`
+	Fields     []stack.EnvFieldDiff `json:"fields,omitempty"`
+	Values     []stack.EnvValueDiff `json:"values,omitempty"`
+	Changes    []planResourceChange `json:"changes"`
+	Summary    planSummary          `json:"summary"`
+}
+
+func newHistoryRevision(rel *release.Release) historyRevision {
+	out := historyRevision{Revision: rel.Version}
+	if rel.Info != nil {
+		out.Status = rel.Info.Status.String()
+		out.Deployed = rel.Info.LastDeployed.Time
+		out.Description = rel.Info.Description
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		out.Chart = rel.Chart.Metadata.Name
+		out.ChartVersion = rel.Chart.Metadata.Version
+		out.AppVersion = rel.Chart.Metadata.AppVersion
+	}
+	return out
+}
+
+func buildHistoryDiff(from, to *release.Release, showValues bool) *historyDiff {
+	d := &historyDiff{
+		APIVersion: "ktl.dev/history-diff/v1",
+		Release:    to.Name,
+		Namespace:  to.Namespace,
+		From:       newHistoryRevision(from),
+		To:         newHistoryRevision(to),
+	}
+	for _, f := range []stack.EnvFieldDiff{
+		{Field: "chart", Left: d.From.Chart, Right: d.To.Chart},
+		{Field: "chartVersion", Left: d.From.ChartVersion, Right: d.To.ChartVersion},
+		{Field: "appVersion", Left: d.From.AppVersion, Right: d.To.AppVersion},
+	} {
+		if f.Left != f.Right {
+			d.Fields = append(d.Fields, f)
+		}
+	}
+	d.Values = stack.DiffValues(from.Config, to.Config, showValues)
+	d.Changes, d.Summary = buildPlanChanges(docsToMap(parseManifestDocs(to.Manifest)), docsToMap(parseManifestDocs(from.Manifest)), nil, nil)
+	// The plan diff compares live against desired; here both sides are stored revisions.
+	header := fmt.Sprintf("--- revision %d\n+++ revision %d\n", from.Version, to.Version)
+	for i := range d.Changes {
+		d.Changes[i].Diff = strings.Replace(d.Changes[i].Diff, "--- live\n+++ desired\n", header, 1)
+	}
+	return d
+}
+
+func writeHistoryDiff(w io.Writer, d *historyDiff, format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "text":
+		renderHistoryDiff(w, d)
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	case "html":
+		page, err := renderHistoryDiffHTML(d)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, page)
+		return err
+	default:
+		return fmt.Errorf("unsupported --format %q (expected text, json, or html)", format)
+	}
+}
+
+var historyValueMarks = map[string]string{
+	stack.EnvValueAdded:   "+",
+	stack.EnvValueRemoved: "-",
+	stack.EnvValueChanged: "~",
+}
+
+func renderHistoryDiff(out io.Writer, d *historyDiff) {
+	fmt.Fprintf(out, "Release %s @ %s: revision %d → %d\n", d.Release, d.Namespace, d.From.Revision, d.To.Revision)
+	for _, rev := range []historyRevision{d.From, d.To} {
+		deployed := "-"
+		if !rev.Deployed.IsZero() {
+			deployed = rev.Deployed.Format(time.RFC3339)
+		}
+		fmt.Fprintf(out, "  %d  %-10s  %s  %s\n", rev.Revision, orDash(rev.Status), deployed, rev.Description)
+	}
+	fmt.Fprintln(out)
+	if len(d.Fields) == 0 {
+		fmt.Fprintf(out, "Chart: %s %s (app %s), unchanged\n", d.To.Chart, d.To.ChartVersion, orDash(d.To.AppVersion))
+	}
+	for _, f := range d.Fields {
+		fmt.Fprintf(out, "%s: %s → %s\n", f.Field, orDash(f.Left), orDash(f.Right))
+	}
+
+	if len(d.Values) == 0 {
+		fmt.Fprintln(out, "Values: unchanged")
+	} else {
+		fmt.Fprintln(out, "Values:")
+		for _, v := range d.Values {
+			switch {
+			case v.Left == "" && v.Right == "":
+				fmt.Fprintf(out, "  %s %s\n", historyValueMarks[v.Change], v.Key)
+			case v.Change == stack.EnvValueChanged:
+				fmt.Fprintf(out, "  %s %s: %s → %s\n", historyValueMarks[v.Change], v.Key, v.Left, v.Right)
+			default:
+				fmt.Fprintf(out, "  %s %s: %s\n", historyValueMarks[v.Change], v.Key, v.Left+v.Right)
+			}
+		}
+	}
+
+	fmt.Fprintf(out, "\nCreates: %d, Updates: %d, Deletes: %d, Unchanged: %d\n", d.Summary.Creates, d.Summary.Updates, d.Summary.Deletes, d.Summary.Unchanged)
+	for _, change := range d.Changes {
+		fmt.Fprintf(out, "- %s %s\n", planChangeLabel(change.Kind), change.Key.String())
+		if change.Diff != "" {
+			fmt.Fprintf(out, "%s\n", indent(change.Diff, "    "))
+		}
+	}
+}
+
+func renderHistoryDiffHTML(d *historyDiff) (string, error) {
+	tmpl, err := template.New("historyDiffHTML").Funcs(template.FuncMap{
+		"changeClass": planChangeClass,
+		"changeLabel": planChangeLabel,
+		"diffHTML":    diffStringToHTML,
+		"valueMark":   func(change string) string { return historyValueMarks[change] },
+	}).Parse(historyDiffHTMLTemplate)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+const historyDiffHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8" />
+  <meta name="viewport" content="width=device-width, initial-scale=1" />
+  <title>ktl history diff {{ .Release }} {{ .From.Revision }} → {{ .To.Revision }}</title>
+  <style>
+    :root { color-scheme: light; --border: rgba(15,23,42,0.12); --muted: rgba(15,23,42,0.65); --warn: #fbbf24; --fail: #ef4444; }
+    body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; margin: 24px; color: #0f172a; background: #f8fafc; }
+    .panel { background: rgba(255,255,255,0.95); border: 1px solid var(--border); border-radius: 16px; padding: 16px; margin-bottom: 16px; box-shadow: 0 18px 40px rgba(15,23,42,0.08); }
+    h1 { font-size: 20px; margin: 0 0 8px; }
+    h2 { font-size: 14px; margin: 0 0 8px; letter-spacing: .04em; }
+    .meta { font-size: 13px; color: var(--muted); }
+    table { border-collapse: collapse; width: 100%; font-size: 13px; }
+    th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid rgba(15,23,42,0.08); vertical-align: top; }
+    th { color: var(--muted); font-weight: 500; }
+    code { font-family: ui-monospace, SFMono-Regular, Menlo, Monaco, Consolas, "Liberation Mono", monospace; }
+    .diff-item { border: 1px solid var(--border); border-radius: 12px; padding: 12px; margin-top: 12px; }
+    .diff-item.added { border-left: 4px solid #22c55e; }
+    .diff-item.changed { border-left: 4px solid var(--warn); }
+    .diff-item.removed { border-left: 4px solid var(--fail); }
+    tr.added { color: #15803d; }
+    tr.removed { color: #b91c1c; }
+    tr.changed { color: #b45309; }
+    pre.diff-snippet { background: #0f172a; color: #e2e8f0; padding: 1rem; border-radius: 12px; overflow: auto; font-size: 12px; line-height: 1.4; }
+    pre.diff-snippet .diff-line { display: block; white-space: pre; margin: 0 -1rem; padding: 0 1rem; border-left: 4px solid transparent; }
+    pre.diff-snippet .diff-line--added { color: #bbf7d0; background: rgba(34,197,94,0.15); border-left-color: #22c55e; }
+    pre.diff-snippet .diff-line--removed { color: #fecaca; background: rgba(239,68,68,0.18); border-left-color: #ef4444; }
+    pre.diff-snippet .diff-line--header { color: #fbbf24; font-weight: 600; }
+  </style>
+</head>
+<body>
+  <div class="panel">
+    <h1>{{ .Release }} @ {{ .Namespace }}: revision {{ .From.Revision }} → {{ .To.Revision }}</h1>
+    <table>
+      <tr><th>Revision</th><th>Status</th><th>Chart</th><th>App version</th><th>Deployed</th><th>Description</th></tr>
+      {{ template "revision" .From }}
+      {{ template "revision" .To }}
+    </table>
+  </div>
+  <div class="panel">
+    <h2>Values</h2>
+    {{ if .Values }}
+    <table>
+      <tr><th></th><th>Key</th><th>{{ .From.Revision }}</th><th>{{ .To.Revision }}</th></tr>
+      {{ range .Values }}<tr class="{{ .Change }}"><td>{{ valueMark .Change }}</td><td><code>{{ .Key }}</code></td><td><code>{{ .Left }}</code></td><td><code>{{ .Right }}</code></td></tr>
+      {{ end }}
+    </table>
+    {{ else }}<div class="meta">No values changes.</div>{{ end }}
+  </div>
+  <div class="panel">
+    <h2>Manifest</h2>
+    <div class="meta">Creates: {{ .Summary.Creates }}, Updates: {{ .Summary.Updates }}, Deletes: {{ .Summary.Deletes }}, Unchanged: {{ .Summary.Unchanged }}</div>
+    {{ range .Changes }}
+    <div class="diff-item {{ changeClass .Kind }}">
+      <div><strong>{{ changeLabel .Kind }}</strong> <code>{{ .Key.String }}</code></div>
+      {{ if .Diff }}<pre class="diff-snippet">{{ diffHTML .Diff }}</pre>{{ end }}
+    </div>
+    {{ end }}
+  </div>
+</body>
+</html>
+{{ define "revision" }}<tr><td>{{ .Revision }}</td><td>{{ .Status }}</td><td><code>{{ .Chart }}-{{ .ChartVersion }}</code></td><td>{{ .AppVersion }}</td><td>{{ if not .Deployed.IsZero }}{{ .Deployed.Format "2006-01-02T15:04:05Z07:00" }}{{ end }}</td><td>{{ .Description }}</td></tr>{{ end }}`
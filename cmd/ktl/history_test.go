@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func historyTestRelease(version int, chartVersion, appVersion string, config map[string]any, manifest string) *release.Release {
+	return &release.Release{
+		Name:      "checkout",
+		Namespace: "prod",
+		Version:   version,
+		Info:      &release.Info{Status: release.StatusSuperseded, Description: "Upgrade complete"},
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "checkout", Version: chartVersion, AppVersion: appVersion}},
+		Config:    config,
+		Manifest:  manifest,
+	}
+}
+
+func TestBuildHistoryDiff(t *testing.T) {
+	from := historyTestRelease(12, "1.4.0", "2.0.0",
+		map[string]any{"image": map[string]any{"tag": "2.0.0"}, "debug": true},
+		`---
+# Source: checkout/templates/cm.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: checkout
+  namespace: prod
+data:
+  mode: blue
+---
+# Source: checkout/templates/svc.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: checkout
+  namespace: prod
+`)
+	to := historyTestRelease(14, "1.5.0", "2.1.0",
+		map[string]any{"image": map[string]any{"tag": "2.1.0"}, "replicas": 3},
+		`---
+# Source: checkout/templates/cm.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: checkout
+  namespace: prod
+data:
+  mode: green
+---
+# Source: checkout/templates/svc.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: checkout
+  namespace: prod
+---
+# Source: checkout/templates/pdb.yaml
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: checkout
+  namespace: prod
+`)
+
+	d := buildHistoryDiff(from, to, false)
+	if len(d.Fields) != 2 || d.Fields[0].Field != "chartVersion" || d.Fields[1].Field != "appVersion" {
+		t.Fatalf("unexpected fields %+v", d.Fields)
+	}
+	if len(d.Values) != 3 || d.Values[0].Key != "debug" || d.Values[1].Key != "image.tag" || d.Values[1].Right != "" {
+		t.Fatalf("expected values keys without values, got %+v", d.Values)
+	}
+	if d.Summary.Creates != 1 || d.Summary.Updates != 1 || d.Summary.Unchanged != 1 {
+		t.Fatalf("unexpected summary %+v", d.Summary)
+	}
+
+	d = buildHistoryDiff(from, to, true)
+	var text bytes.Buffer
+	if err := writeHistoryDiff(&text, d, "text"); err != nil {
+		t.Fatalf("text: %v", err)
+	}
+	for _, want := range []string{
+		"Release checkout @ prod: revision 12 → 14",
+		"chartVersion: 1.4.0 → 1.5.0",
+		`~ image.tag: "2.0.0" → "2.1.0"`,
+		"Creates: 1, Updates: 1, Deletes: 0, Unchanged: 1",
+		"+  mode: green",
+		"--- revision 12\n",
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, text.String())
+		}
+	}
+
+	var page bytes.Buffer
+	if err := writeHistoryDiff(&page, d, "html"); err != nil {
+		t.Fatalf("html: %v", err)
+	}
+	if !strings.Contains(page.String(), "diff-line--added") || !strings.Contains(page.String(), "<code>checkout-1.5.0</code>") {
+		t.Fatalf("unexpected html output:\n%s", page.String())
+	}
+
+	var raw bytes.Buffer
+	if err := writeHistoryDiff(&raw, d, "json"); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(raw.Bytes(), &decoded); err != nil || decoded["apiVersion"] != "ktl.dev/history-diff/v1" {
+		t.Fatalf("unexpected json output (%v):\n%s", err, raw.String())
+	}
+}
@@ -0,0 +1,61 @@
+// File: cmd/ktl/impersonation.go
+// Brief: Global --as/--as-group impersonation wiring.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/spf13/pflag"
+)
+
+type impersonationFlags struct {
+	user   string
+	groups []string
+}
+
+func (f *impersonationFlags) bind(flags *pflag.FlagSet) {
+	flags.StringVar(&f.user, "as", "", "Username to impersonate for Kubernetes and Helm operations (like kubectl --as)")
+	flags.StringArrayVar(&f.groups, "as-group", nil, "Group to impersonate (repeatable; requires --as)")
+}
+
+// apply configures impersonation for kube.New clients and for Helm, which reads HELM_KUBEASUSER
+// and HELM_KUBEASGROUPS whenever ktl builds a cli.EnvSettings.
+func (f *impersonationFlags) apply() error {
+	user := strings.TrimSpace(f.user)
+	var groups []string
+	for _, g := range f.groups {
+		if g = strings.TrimSpace(g); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	if user == "" {
+		if len(groups) > 0 {
+			return fmt.Errorf("--as-group requires --as")
+		}
+		return nil
+	}
+	kube.SetImpersonation(kube.Impersonation{User: user, Groups: groups})
+	if err := os.Setenv("HELM_KUBEASUSER", user); err != nil {
+		return err
+	}
+	return os.Setenv("HELM_KUBEASGROUPS", strings.Join(groups, ","))
+}
+
+// applyInheritedImpersonation applies --as/--as-group for commands whose own PersistentPreRunE
+// shadows the root hook.
+func applyInheritedImpersonation(flags *pflag.FlagSet) error {
+	var f impersonationFlags
+	if flag := flags.Lookup("as"); flag != nil {
+		f.user = flag.Value.String()
+	}
+	if groups, err := flags.GetStringArray("as-group"); err == nil {
+		f.groups = groups
+	}
+	return f.apply()
+}
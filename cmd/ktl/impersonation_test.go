@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+const impersonationTestKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: c
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: c
+  context:
+    cluster: c
+    user: u
+current-context: c
+users:
+- name: u
+  user:
+    token: t
+`
+
+func TestImpersonationFlagsConfigureKubeAndHelm(t *testing.T) {
+	t.Setenv("HELM_KUBEASUSER", "")
+	t.Setenv("HELM_KUBEASGROUPS", "")
+	t.Cleanup(func() { kube.SetImpersonation(kube.Impersonation{}) })
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfig, []byte(impersonationTestKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	root := newRootCommand()
+	root.SetArgs([]string{"noop", "--as", "system:serviceaccount:ci:deployer", "--as-group", "ci", "--as-group", "deployers"})
+	root.AddCommand(&cobra.Command{Use: "noop", RunE: func(cmd *cobra.Command, args []string) error { return nil }})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	client, err := kube.New(context.Background(), kubeconfig, "")
+	if err != nil {
+		t.Fatalf("kube.New: %v", err)
+	}
+	if got := client.RESTConfig.Impersonate.UserName; got != "system:serviceaccount:ci:deployer" {
+		t.Fatalf("expected impersonated user, got %q", got)
+	}
+	if got := client.RESTConfig.Impersonate.Groups; len(got) != 2 || got[0] != "ci" || got[1] != "deployers" {
+		t.Fatalf("unexpected impersonated groups %v", got)
+	}
+	settings := cli.New()
+	if settings.KubeAsUser != "system:serviceaccount:ci:deployer" || len(settings.KubeAsGroups) != 2 {
+		t.Fatalf("helm settings not impersonating: user=%q groups=%v", settings.KubeAsUser, settings.KubeAsGroups)
+	}
+}
+
+func TestImpersonationGroupRequiresUser(t *testing.T) {
+	t.Cleanup(func() { kube.SetImpersonation(kube.Impersonation{}) })
+	f := impersonationFlags{groups: []string{"ci"}}
+	if err := f.apply(); err == nil {
+		t.Fatalf("expected --as-group without --as to fail")
+	}
+}
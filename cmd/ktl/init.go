@@ -5,6 +5,7 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/configschema"
 	"github.com/mitchellh/go-homedir"
 	"github.com/pmezard/go-difflib/difflib"
 	"github.com/spf13/cobra"
@@ -198,6 +200,7 @@ getting-started checklist.`),
 				header := []byte("# ktl repo config\n")
 				payload = append(header, payload...)
 			}
+			payload = withSchemaModeline(payload, "ktl")
 			if len(payload) > 0 && payload[len(payload)-1] != '\n' {
 				payload = append(payload, '\n')
 			}
@@ -1150,6 +1153,7 @@ func renderStackTemplate(repoRoot string, project projectLayout, valuesEnabled b
 	}
 
 	var b strings.Builder
+	b.Write(withSchemaModeline(nil, "stack"))
 	fmt.Fprintf(&b, "name: %s\n\n", releaseName)
 	b.WriteString("cli:\n")
 	b.WriteString("  output: table\n")
@@ -1164,6 +1168,16 @@ func renderStackTemplate(repoRoot string, project projectLayout, valuesEnabled b
 	return b.String()
 }
 
+// withSchemaModeline prefixes payload with the yaml-language-server comment for the config kind,
+// so editors validate and complete the file against ktl's JSON Schema.
+func withSchemaModeline(payload []byte, kind string) []byte {
+	k, ok := configschema.Lookup(kind)
+	if !ok || bytes.Contains(payload, []byte("yaml-language-server: $schema=")) {
+		return payload
+	}
+	return append([]byte(k.Modeline()+"\n"), payload...)
+}
+
 func buildValuesTemplate(secretsProvider string, env string) string {
 	secretsProvider = strings.ToLower(strings.TrimSpace(secretsProvider))
 	if secretsProvider == "" {
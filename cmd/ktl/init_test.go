@@ -128,6 +128,9 @@ users:
 	if provider.Path != "./secrets.local.yaml" {
 		t.Fatalf("expected local provider path ./secrets.local.yaml, got %q", provider.Path)
 	}
+	if !strings.HasPrefix(string(raw), "# yaml-language-server: $schema=") || !strings.Contains(string(raw), "ktl.schema.json") {
+		t.Fatalf("expected schema modeline at the top of .ktl.yaml, got:\n%s", raw)
+	}
 }
 
 func TestInitDryRunDoesNotWrite(t *testing.T) {
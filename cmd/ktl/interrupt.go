@@ -0,0 +1,83 @@
+// File: cmd/ktl/interrupt.go
+// Brief: CLI command wiring and implementation for 'interrupt'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// applyInterruptSettle bounds how long an interrupted apply waits for Helm to record the release
+// state after the cancellation reached it.
+const applyInterruptSettle = 5 * time.Second
+
+// applyInterruptPhases is the order phases are reported in; install only appears when Helm fell
+// back to it.
+var applyInterruptPhases = []string{deploy.PhaseRender, deploy.PhaseUpgrade, deploy.PhaseInstall, deploy.PhaseWait, deploy.PhasePostHooks}
+
+// writeInterruptSummary prints the partial-state summary carried by an interrupted run. It must be
+// called after any live console is torn down so the summary is not repainted over.
+func writeInterruptSummary(w io.Writer, err error) {
+	var interrupted *deploy.InterruptedError
+	if errors.As(err, &interrupted) {
+		interrupted.Summary.Write(w)
+	}
+}
+
+// settledReleaseStatus polls the release until Helm leaves the pending-* states or wait elapses,
+// and returns the last status seen.
+func settledReleaseStatus(actionCfg *action.Configuration, releaseName string, wait time.Duration) string {
+	deadline := time.Now().Add(wait)
+	status := "unknown"
+	for {
+		rel, err := action.NewStatus(actionCfg).Run(releaseName)
+		switch {
+		case errors.Is(err, driver.ErrReleaseNotFound):
+			return "not installed"
+		case err == nil && rel != nil && rel.Info != nil:
+			status = rel.Info.Status.String()
+			if !rel.Info.Status.IsPending() {
+				return status
+			}
+		}
+		if !time.Now().Before(deadline) {
+			return status
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+// applyInterruptSummary reports phases by outcome: succeeded or skipped phases are complete,
+// phases that were running or failed on cancellation are unknown.
+func applyInterruptSummary(phases map[string]string, releaseName, namespace, chart, releaseStatus string) *deploy.InterruptSummary {
+	summary := &deploy.InterruptSummary{}
+	for _, phase := range applyInterruptPhases {
+		state, seen := phases[phase]
+		switch {
+		case !seen:
+			if phase != deploy.PhaseInstall {
+				summary.NotStarted = append(summary.NotStarted, phase)
+			}
+		case state == "succeeded":
+			summary.Completed = append(summary.Completed, phase)
+		case state == "skipped":
+		default:
+			summary.Unknown = append(summary.Unknown, phase)
+		}
+	}
+	summary.Hint = fmt.Sprintf("Helm reports release %s as %s.", releaseName, releaseStatus)
+	if releaseStatus != "deployed" && releaseStatus != "not installed" {
+		summary.Hint += fmt.Sprintf(" If the re-run reports another operation in progress, run: helm rollback %s -n %s", releaseName, namespace)
+	}
+	summary.Resume = fmt.Sprintf("ktl apply --chart %s --release %s -n %s (with the same values flags)", chart, releaseName, namespace)
+	return summary
+}
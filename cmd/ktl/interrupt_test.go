@@ -0,0 +1,46 @@
+// File: cmd/ktl/interrupt_test.go
+// Brief: CLI command wiring and implementation for 'interrupt'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+)
+
+func TestApplyInterruptSummary(t *testing.T) {
+	phases := map[string]string{
+		deploy.PhaseRender:  "succeeded",
+		deploy.PhaseDiff:    "skipped",
+		deploy.PhaseUpgrade: "failed",
+		deploy.PhaseWait:    "failed",
+	}
+	summary := applyInterruptSummary(phases, "web", "prod", "./chart", "pending-upgrade")
+	if strings.Join(summary.Completed, ",") != "render" {
+		t.Fatalf("unexpected completed: %v", summary.Completed)
+	}
+	if strings.Join(summary.Unknown, ",") != "upgrade,wait" {
+		t.Fatalf("unexpected unknown: %v", summary.Unknown)
+	}
+	if strings.Join(summary.NotStarted, ",") != "post-hooks" {
+		t.Fatalf("unexpected not started: %v", summary.NotStarted)
+	}
+	if !strings.Contains(summary.Hint, "helm rollback web -n prod") {
+		t.Fatalf("expected rollback hint for pending release, got %q", summary.Hint)
+	}
+
+	var buf bytes.Buffer
+	writeInterruptSummary(&buf, fmt.Errorf("apply: %w", &deploy.InterruptedError{Summary: summary}))
+	out := buf.String()
+	for _, want := range []string{"Interrupted: 1 completed, 2 in unknown state, 1 not started", "Resume with: ktl apply --chart ./chart --release web -n prod"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/netconfig"
 	"github.com/spf13/cobra"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chartutil"
@@ -110,7 +111,7 @@ func newLintCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
 				}
 			}
 
-			vals, err := valueOpts.MergeValues(getter.All(envSettings))
+			vals, err := valueOpts.MergeValues(getter.All(envSettings, netconfig.GetterOptions()...))
 			if err != nil {
 				return err
 			}
@@ -172,6 +173,8 @@ func newLintCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
 	f.StringArrayVar(&valueOpts.Values, "set", nil, "Set values on the command line (key=val)")
 	f.StringArrayVar(&valueOpts.StringValues, "set-string", nil, "Set STRING values on the command line")
 	f.StringArrayVar(&valueOpts.FileValues, "set-file", nil, "Set values from files (key=path)")
+	f.StringArrayVar(&valueOpts.JSONValues, "set-json", nil, "Set JSON values on the command line (key=<json>)")
+	f.StringArrayVar(&valueOpts.LiteralValues, "set-literal", nil, "Set a literal STRING value on the command line (no escaping or list parsing)")
 
 	decorateCommandHelp(cmd, "Lint Flags")
 	return cmd
@@ -15,6 +15,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/fatih/color"
+	"github.com/kubekattle/ktl/internal/deploy"
 	"github.com/spf13/cobra"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
@@ -27,6 +28,7 @@ var (
 	listStatusDeployed = color.New(color.FgGreen).SprintFunc()
 	listStatusFailed   = color.New(color.FgRed).SprintFunc()
 	listStatusPending  = color.New(color.FgYellow).SprintFunc()
+	listDriftModified  = color.New(color.FgRed).SprintFunc()
 )
 
 func newListCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
@@ -50,6 +52,8 @@ func newListCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
 	var offset int
 	var filter string
 	var selector string
+	var drift bool
+	var ownerTeam string
 
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -63,7 +67,13 @@ func newListCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
   ktl list -A
 
   # Emit structured output
-  ktl list --format json`,
+  ktl list --format json
+
+  # Flag releases changed outside ktl since it last applied them
+  ktl list -A --drift
+
+  # Releases owned by a team (see ktl owners)
+  ktl list -A --owner team-payments`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
@@ -135,6 +145,17 @@ func newListCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			// Owners live on the release storage objects; list their metadata once when needed.
+			var annotations map[string]map[string]string
+			if strings.TrimSpace(ownerTeam) != "" || (!client.Short && (selectedFormat == "json" || selectedFormat == "yaml")) {
+				annotations, err = deploy.ReleaseStorageAnnotations(cmd.Context(), actionCfg, initNamespace)
+				if err != nil {
+					return err
+				}
+			}
+			if strings.TrimSpace(ownerTeam) != "" {
+				results = releasesOwnedBy(results, annotations, ownerTeam)
+			}
 
 			if client.Short {
 				names := releaseNames(results)
@@ -151,14 +172,22 @@ func newListCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
 				}
 			}
 
+			var drifts map[string]string
+			if drift {
+				drifts, err = releaseDrifts(cmd.Context(), settings, actionCfg, results, initNamespace)
+				if err != nil {
+					return err
+				}
+			}
+
 			switch selectedFormat {
 			case "json":
-				return output.EncodeJSON(cmd.OutOrStdout(), releaseListElements(results, client.TimeFormat))
+				return output.EncodeJSON(cmd.OutOrStdout(), releaseListElements(results, client.TimeFormat, drifts, annotations))
 			case "yaml":
-				return output.EncodeYAML(cmd.OutOrStdout(), releaseListElements(results, client.TimeFormat))
+				return output.EncodeYAML(cmd.OutOrStdout(), releaseListElements(results, client.TimeFormat, drifts, annotations))
 			default:
 				colorize := isTerminalWriter(cmd.OutOrStdout()) && !color.NoColor
-				return writeReleaseListTable(cmd.OutOrStdout(), results, client.TimeFormat, client.NoHeaders, colorize)
+				return writeReleaseListTable(cmd.OutOrStdout(), results, client.TimeFormat, client.NoHeaders, colorize, drifts)
 			}
 		},
 	}
@@ -182,6 +211,8 @@ func newListCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
 	cmd.Flags().IntVar(&offset, "offset", 0, "Next release index in the list, used to offset from start")
 	cmd.Flags().StringVarP(&filter, "filter", "f", "", "A regular expression (Perl compatible) to filter releases by name")
 	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Label selector to filter releases by label query (works only for secret/configmap backends)")
+	cmd.Flags().StringVar(&ownerTeam, "owner", "", "Only list releases owned by this team (recorded from deploy.owners in .ktl.yaml or owner in stack.yaml)")
+	cmd.Flags().BoolVar(&drift, "drift", false, "Add a DRIFT column comparing a fresh render of each release with the manifest digest recorded when ktl applied it")
 
 	decorateCommandHelp(cmd, "List Flags")
 	return cmd
@@ -215,6 +246,31 @@ type releaseListElement struct {
 	Status     string `json:"status" yaml:"status"`
 	Chart      string `json:"chart" yaml:"chart"`
 	AppVersion string `json:"app_version" yaml:"app_version"`
+	// Drift is set with --drift: in-sync, modified (a fresh render no longer matches what ktl
+	// applied), or unknown (not applied by ktl, or the render failed).
+	Drift string `json:"drift,omitempty" yaml:"drift,omitempty"`
+	// Owner is the team recorded on the release, if any.
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+}
+
+func (e releaseListElement) columns(drift bool) []string {
+	cols := []string{e.Name, e.Namespace, e.Revision, e.Updated, e.Status, e.Chart, e.AppVersion}
+	if drift {
+		cols = append(cols, e.Drift)
+	}
+	return cols
+}
+
+// releasesOwnedBy keeps the releases whose recorded owner team matches team. annotations holds
+// the storage object annotations keyed by deploy.ReleaseStorageKey.
+func releasesOwnedBy(releases []*release.Release, annotations map[string]map[string]string, team string) []*release.Release {
+	var out []*release.Release
+	for _, rel := range releases {
+		if rel != nil && deploy.ReleaseOwnerOf(rel, annotations[deploy.ReleaseStorageKey(rel)]).MatchesTeam(team) {
+			out = append(out, rel)
+		}
+	}
+	return out
 }
 
 func releaseNames(releases []*release.Release) []string {
@@ -227,14 +283,61 @@ func releaseNames(releases []*release.Release) []string {
 	return names
 }
 
-func releaseListElements(releases []*release.Release, timeFormat string) []releaseListElement {
+// releaseDrifts re-renders each release ktl applied from its stored chart and values, through the
+// repo's post-render pipeline, and compares the result with the digest recorded at apply time.
+// Releases without a recorded digest are not rendered. The result is keyed by
+// deploy.ReleaseStorageKey.
+func releaseDrifts(ctx context.Context, settings *cli.EnvSettings, actionCfg *action.Configuration, releases []*release.Release, namespace string) (map[string]string, error) {
+	recorded, err := deploy.RecordedManifestDigests(ctx, actionCfg, namespace)
+	if err != nil {
+		return nil, err
+	}
+	postRenderer, err := loadPostRenderer(ctx, settings, postRenderFlags{})
+	if err != nil {
+		return nil, err
+	}
+	// Rendering needs storage scoped to the release's namespace: with -A, Helm would otherwise
+	// pick up a release of the same name from another namespace.
+	configs := map[string]*action.Configuration{}
+	drifts := make(map[string]string, len(releases))
+	for _, rel := range releases {
+		if rel == nil {
+			continue
+		}
+		key := deploy.ReleaseStorageKey(rel)
+		digest := recorded[key]
+		if digest == "" {
+			drifts[key] = deploy.DriftUnknown
+			continue
+		}
+		cfg := configs[rel.Namespace]
+		if cfg == nil {
+			cfg = new(action.Configuration)
+			if err := cfg.Init(settings.RESTClientGetter(), rel.Namespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+				return nil, fmt.Errorf("init helm action config: %w", err)
+			}
+			configs[rel.Namespace] = cfg
+		}
+		rendered, err := deploy.RenderStoredRelease(ctx, cfg, rel, postRenderer)
+		if err != nil {
+			drifts[key] = deploy.DriftUnknown
+			continue
+		}
+		drifts[key] = deploy.ReleaseDrift(digest, rendered)
+	}
+	return drifts, nil
+}
+
+// releaseListElements builds the list rows. drifts, when non-nil, fills the Drift column (see
+// releaseDrifts); annotations, keyed by deploy.ReleaseStorageKey, fills the owner.
+func releaseListElements(releases []*release.Release, timeFormat string, drifts map[string]string, annotations map[string]map[string]string) []releaseListElement {
 	elements := make([]releaseListElement, 0, len(releases))
 	for _, rel := range releases {
 		if rel == nil {
 			continue
 		}
 		updated, status := releaseTimingAndStatus(rel, timeFormat)
-		elements = append(elements, releaseListElement{
+		el := releaseListElement{
 			Name:       rel.Name,
 			Namespace:  rel.Namespace,
 			Revision:   strconv.Itoa(rel.Version),
@@ -242,29 +345,39 @@ func releaseListElements(releases []*release.Release, timeFormat string) []relea
 			Status:     status,
 			Chart:      formatChartName(rel.Chart),
 			AppVersion: formatAppVersion(rel.Chart),
-		})
+		}
+		if drifts != nil {
+			el.Drift = drifts[deploy.ReleaseStorageKey(rel)]
+			if el.Drift == "" {
+				el.Drift = deploy.DriftUnknown
+			}
+		}
+		if owner := deploy.ReleaseOwnerOf(rel, annotations[deploy.ReleaseStorageKey(rel)]); owner != nil {
+			el.Owner = owner.Team
+		}
+		elements = append(elements, el)
 	}
 	return elements
 }
 
-func writeReleaseListTable(out io.Writer, releases []*release.Release, timeFormat string, noHeaders bool, colorize bool) error {
+func writeReleaseListTable(out io.Writer, releases []*release.Release, timeFormat string, noHeaders bool, colorize bool, drifts map[string]string) error {
+	drift := drifts != nil
 	headers := []string{"NAME", "NAMESPACE", "REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION"}
-	if noHeaders {
-		headers = nil
+	if drift {
+		headers = append(headers, "DRIFT")
 	}
-
-	rows := releaseListElements(releases, timeFormat)
-
 	widths := make([]int, len(headers))
 	for i, header := range headers {
 		widths[i] = utf8.RuneCountInString(header)
 	}
-	if len(headers) == 0 {
-		widths = make([]int, 7)
+	if noHeaders {
+		headers = nil
+		widths = make([]int, len(widths))
 	}
+
+	rows := releaseListElements(releases, timeFormat, drifts, nil)
 	for _, row := range rows {
-		cols := []string{row.Name, row.Namespace, row.Revision, row.Updated, row.Status, row.Chart, row.AppVersion}
-		for i, col := range cols {
+		for i, col := range row.columns(drift) {
 			if w := utf8.RuneCountInString(col); w > widths[i] {
 				widths[i] = w
 			}
@@ -294,14 +407,17 @@ func writeReleaseListTable(out io.Writer, releases []*release.Release, timeForma
 		renderRow(headers, headers)
 	}
 	for _, row := range rows {
-		status := row.Status
-		if colorize {
-			status = colorizeReleaseStatus(status)
+		plain := row.columns(drift)
+		if !colorize {
+			renderRow(plain, plain)
+			continue
+		}
+		colored := append([]string(nil), plain...)
+		colored[4] = colorizeReleaseStatus(row.Status)
+		if drift && row.Drift == deploy.DriftModified {
+			colored[7] = listDriftModified(row.Drift)
 		}
-		renderRow(
-			[]string{row.Name, row.Namespace, row.Revision, row.Updated, status, row.Chart, row.AppVersion},
-			[]string{row.Name, row.Namespace, row.Revision, row.Updated, row.Status, row.Chart, row.AppVersion},
-		)
+		renderRow(colored, plain)
 	}
 	return nil
 }
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/kubekattle/ktl/internal/deploy"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/release"
 	helmtime "helm.sh/helm/v3/pkg/time"
@@ -37,7 +38,7 @@ func TestReleaseListElementsFormatsFields(t *testing.T) {
 		},
 	}
 
-	els := releaseListElements([]*release.Release{rel}, "2006-01-02")
+	els := releaseListElements([]*release.Release{rel}, "2006-01-02", nil, nil)
 	if len(els) != 1 {
 		t.Fatalf("expected 1 element, got %d", len(els))
 	}
@@ -72,7 +73,7 @@ func TestWriteReleaseListTableDoesNotColorWhenDisabled(t *testing.T) {
 		},
 	}
 	var buf bytes.Buffer
-	if err := writeReleaseListTable(&buf, []*release.Release{rel}, "", false, false); err != nil {
+	if err := writeReleaseListTable(&buf, []*release.Release{rel}, "", false, false, nil); err != nil {
 		t.Fatalf("write table: %v", err)
 	}
 	out := buf.String()
@@ -100,3 +101,39 @@ func TestColorizeReleaseStatusAddsANSI(t *testing.T) {
 		t.Fatalf("expected ANSI escape sequence, got %q", colored)
 	}
 }
+
+func TestWriteReleaseListTableDriftColumn(t *testing.T) {
+	manifest := "---\nkind: ConfigMap\napiVersion: v1\nmetadata:\n  name: web\n"
+	digest, _, err := deploy.DigestNormalizedManifest(manifest)
+	if err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	releases := []*release.Release{
+		{Name: "in-sync", Namespace: "prod", Version: 1},
+		{Name: "edited", Namespace: "prod", Version: 2},
+		{Name: "helm-only", Namespace: "prod", Version: 1},
+	}
+	drifts := map[string]string{
+		deploy.ReleaseStorageKey(releases[0]): deploy.ReleaseDrift(digest, manifest),
+		deploy.ReleaseStorageKey(releases[1]): deploy.ReleaseDrift(digest, manifest+"data:\n  k: v\n"),
+	}
+
+	var buf bytes.Buffer
+	if err := writeReleaseListTable(&buf, releases, "", false, false, drifts); err != nil {
+		t.Fatalf("write table: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasSuffix(lines[0], "DRIFT") {
+		t.Fatalf("unexpected table: %q", buf.String())
+	}
+	for i, want := range []string{deploy.DriftInSync, deploy.DriftModified, deploy.DriftUnknown} {
+		if !strings.HasSuffix(lines[i+1], want) {
+			t.Fatalf("row %d: expected drift %q, got %q", i, want, lines[i+1])
+		}
+	}
+
+	els := releaseListElements(releases, "", nil, nil)
+	if els[0].Drift != "" {
+		t.Fatalf("expected no drift without --drift, got %q", els[0].Drift)
+	}
+}
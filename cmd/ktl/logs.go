@@ -5,6 +5,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -25,6 +26,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
+	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -39,13 +41,14 @@ func newLogsCommand(opts *config.Options, kubeconfigPath, kubeContext, logLevel,
 	var stackConfig string
 	var jsonQuery string
 	cmd := &cobra.Command{
-		Use:           "logs [POD_QUERY]",
-		Aliases:       []string{"tail"},
-		Short:         "Tail Kubernetes pod logs",
-		Long:          "Stream pod logs with ktl's high-performance tailer. Accepts the same query/flag set as the legacy ktl entrypoint.",
-		Args:          cobra.MaximumNArgs(1),
-		SilenceUsage:  true,
-		SilenceErrors: true,
+		Use:               "logs [POD_QUERY|@PROFILE]",
+		Aliases:           []string{"tail"},
+		Short:             "Tail Kubernetes pod logs",
+		Long:              "Stream pod logs with ktl's high-performance tailer. Accepts the same query/flag set as the legacy ktl entrypoint.\n\nPass @<name> to run a named tail profile from .ktl.yaml (see 'ktl logs profiles list').",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeLogProfiles,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runLogs(cmd, args, opts, kubeconfigPath, kubeContext, logLevel, remoteAgent, mirrorBus, capturePath, captureTags, deployPin, deployMode, deployRefresh, deployPruneGrace, deps, stackConfig, jsonQuery)
 		},
@@ -64,6 +67,7 @@ func newLogsCommand(opts *config.Options, kubeconfigPath, kubeContext, logLevel,
 	cmd.Flags().BoolVar(&deps, "deps", false, "Include logs from dependencies defined in stack.yaml")
 	cmd.Flags().StringVar(&stackConfig, "config", "", "Path to stack.yaml (used with --deps)")
 	cmd.Flags().StringVar(&jsonQuery, "filter", "", "Filter JSON logs by key=value (e.g. level=error, status=500)")
+	cmd.AddCommand(newLogsProfilesCommand())
 	decorateCommandHelp(cmd, "Log Flags")
 	return cmd
 }
@@ -80,6 +84,18 @@ func runLogs(cmd *cobra.Command, args []string, opts *config.Options, kubeconfig
 		return cmd.Help()
 	}
 
+	if len(args) > 0 && strings.HasPrefix(args[0], logProfilePrefix) {
+		profile, err := resolveLogProfile(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		applyLogProfile(cmd.Flags(), opts, profile, &jsonQuery)
+		args = nil
+		if q := strings.TrimSpace(profile.Query); q != "" {
+			args = []string{q}
+		}
+	}
+
 	opts.KubeConfigPath = *kubeconfigPath
 	opts.Context = *kubeContext
 	if len(args) > 0 {
@@ -193,6 +209,9 @@ func runLogs(cmd *cobra.Command, args []string, opts *config.Options, kubeconfig
 		remoteAddr = strings.TrimSpace(*remoteAgent)
 	}
 	if remoteAddr != "" {
+		if strings.TrimSpace(opts.Workload) != "" {
+			return fmt.Errorf("--for is not supported with --remote-agent (use --selector)")
+		}
 		return runRemoteLogs(cmd, opts, remoteAddr)
 	}
 	logger, err := buildLogger(*logLevel)
@@ -216,6 +235,15 @@ func runLogs(cmd *cobra.Command, args []string, opts *config.Options, kubeconfig
 		}
 	}
 
+	if workload := strings.TrimSpace(opts.Workload); workload != "" {
+		if err := applyWorkloadSelector(ctx, cmd.ErrOrStderr(), kubeClient.Clientset, opts, workload, args); err != nil {
+			return err
+		}
+	}
+	if opts.Encode == tailer.EncodeOTLP && opts.Context != "" {
+		opts.EncodeResource = map[string]string{"k8s.cluster.name": opts.Context}
+	}
+
 	var tailerOpts []tailer.Option
 	tailerOptions := opts
 	var deployLensStart func(*tailer.Tailer) error
@@ -334,6 +362,15 @@ func runLogs(cmd *cobra.Command, args []string, opts *config.Options, kubeconfig
 		tailerOpts = append(tailerOpts, tailer.WithJSONFilter(filters))
 	}
 
+	if opts.Summary {
+		summary := tailer.NewSessionSummary()
+		tailerOpts = append(tailerOpts, tailer.WithLogObserver(summary), tailer.WithSelectionObserver(summary))
+		if opts.SummaryInterval > 0 {
+			go printPeriodicSummary(ctx, summary, opts.SummaryInterval, cmd.ErrOrStderr())
+		}
+		defer func() { _ = summary.Write(cmd.ErrOrStderr(), logsSummaryTopMessages) }()
+	}
+
 	t, err := tailer.New(kubeClient.Clientset, tailerOptions, logger, tailerOpts...)
 	if err != nil {
 		return err
@@ -353,6 +390,21 @@ func runLogs(cmd *cobra.Command, args []string, opts *config.Options, kubeconfig
 	return t.Run(ctx)
 }
 
+const logsSummaryTopMessages = 5
+
+func printPeriodicSummary(ctx context.Context, summary *tailer.SessionSummary, interval time.Duration, w io.Writer) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = summary.Write(w, logsSummaryTopMessages)
+		}
+	}
+}
+
 func runRemoteLogs(cmd *cobra.Command, opts *config.Options, remoteAddr string) error {
 	ctx := cmd.Context()
 	creds, err := remoteTransportCredentials(cmd, remoteAddr)
@@ -453,3 +505,27 @@ func requestedHelp(value string) bool {
 		return false
 	}
 }
+
+// applyWorkloadSelector narrows the tail to the pods selected by a --for workload. The tailer's
+// informers watch that selector, so pods created or removed during a rollout are picked up.
+func applyWorkloadSelector(ctx context.Context, errOut io.Writer, client kubernetes.Interface, opts *config.Options, workload string, args []string) error {
+	if len(args) > 0 {
+		if _, _, ok := parseDeployLogsTarget(args[0]); ok {
+			return fmt.Errorf("cannot combine --for with a deploy/<name> query")
+		}
+	}
+	if opts.AllNamespaces || len(opts.Namespaces) != 1 {
+		return fmt.Errorf("--for requires exactly one namespace (use -n)")
+	}
+	ref, err := kube.ParseWorkloadRef(workload)
+	if err != nil {
+		return err
+	}
+	sel, err := kube.WorkloadSelector(ctx, client, opts.Namespaces[0], ref)
+	if err != nil {
+		return err
+	}
+	opts.LabelSelector = kube.CombineSelectors(sel.String(), opts.LabelSelector)
+	fmt.Fprintf(errOut, "Following %s in %s (selector %s)\n", ref, opts.Namespaces[0], opts.LabelSelector)
+	return nil
+}
@@ -0,0 +1,211 @@
+// File: cmd/ktl/logs_profiles.go
+// Brief: CLI command wiring and implementation for 'logs profiles'.
+
+// logs_profiles.go resolves `ktl logs @<profile>` against named tail profiles in .ktl.yaml.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const logProfilePrefix = "@"
+
+func loadLogsConfig(ctx context.Context) (appconfig.LogsConfig, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return appconfig.LogsConfig{}, err
+	}
+	repoRoot := appconfig.FindRepoRoot(cwd)
+	cfg, err := appconfig.Load(ctx, appconfig.DefaultGlobalPath(), appconfig.DefaultRepoPath(repoRoot))
+	if err != nil {
+		return appconfig.LogsConfig{}, err
+	}
+	return cfg.Logs, nil
+}
+
+// resolveLogProfile looks up `@name` and returns the profile to apply.
+func resolveLogProfile(ctx context.Context, arg string) (appconfig.LogProfile, error) {
+	name := strings.TrimSpace(strings.TrimPrefix(arg, logProfilePrefix))
+	if name == "" {
+		return appconfig.LogProfile{}, fmt.Errorf("log profile name is required after %q", logProfilePrefix)
+	}
+	cfg, err := loadLogsConfig(ctx)
+	if err != nil {
+		return appconfig.LogProfile{}, err
+	}
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		known := cfg.ProfileNames()
+		if len(known) == 0 {
+			return appconfig.LogProfile{}, fmt.Errorf("unknown log profile %q (no logs.profiles defined in .ktl.yaml or ~/.ktl/config.yaml)", name)
+		}
+		return appconfig.LogProfile{}, fmt.Errorf("unknown log profile %q (available: %s)", name, strings.Join(known, ", "))
+	}
+	return profile, nil
+}
+
+// applyLogProfile copies profile settings into opts for every flag the user did not set explicitly.
+func applyLogProfile(fs *pflag.FlagSet, opts *config.Options, profile appconfig.LogProfile, jsonQuery *string) {
+	unset := func(name string) bool {
+		return fs == nil || !fs.Changed(name)
+	}
+	if len(profile.Namespaces) > 0 && unset("namespace") && unset("all-namespaces") {
+		opts.Namespaces = append([]string(nil), profile.Namespaces...)
+	}
+	if profile.AllNamespaces != nil && unset("all-namespaces") && unset("namespace") {
+		opts.AllNamespaces = *profile.AllNamespaces
+	}
+	if profile.Selector != "" && unset("selector") {
+		opts.LabelSelector = profile.Selector
+	}
+	if profile.Workload != "" && unset("for") {
+		opts.Workload = profile.Workload
+	}
+	if len(profile.Containers) > 0 && unset("container") {
+		opts.ContainerFilters = append([]string(nil), profile.Containers...)
+	}
+	if len(profile.ExcludeContainers) > 0 && unset("exclude-container") {
+		opts.ExcludeContainers = append([]string(nil), profile.ExcludeContainers...)
+	}
+	if len(profile.ExcludePods) > 0 && unset("exclude-pod") {
+		opts.ExcludePods = append([]string(nil), profile.ExcludePods...)
+	}
+	if profile.Exclude != "" && unset("exclude") {
+		opts.ExcludeLine = profile.Exclude
+	}
+	if len(profile.Highlight) > 0 && unset("highlight") {
+		opts.HighlightTerms = append([]string(nil), profile.Highlight...)
+	}
+	if profile.Template != "" && unset("template") && unset("template-file") {
+		opts.Template = profile.Template
+	}
+	if profile.Output != "" && unset("output") {
+		opts.OutputFormat = profile.Output
+	}
+	if profile.Since != "" && unset("since") {
+		opts.SinceRaw = profile.Since
+	}
+	if profile.Tail != nil && unset("tail") {
+		opts.TailLines = *profile.Tail
+	}
+	if profile.Events != nil && unset("events") {
+		opts.Events = *profile.Events
+	}
+	if profile.Anomalies != nil && unset("anomalies") {
+		opts.Anomalies = *profile.Anomalies
+	}
+	if profile.Filter != "" && unset("filter") && jsonQuery != nil {
+		*jsonQuery = profile.Filter
+	}
+}
+
+func newLogsProfilesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage named log tail profiles",
+		Long: `Named tail profiles are saved log queries defined under logs.profiles in .ktl.yaml
+(or ~/.ktl/config.yaml). Invoke one with ktl logs @<name>; flags passed on the command line
+override the profile.
+
+Example .ktl.yaml:
+  logs:
+    profiles:
+      checkout-errors:
+        description: Checkout errors in prod
+        query: checkout-.*
+        namespaces: [prod-payments]
+        highlight: [ERROR, panic]
+        exclude: healthz`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newLogsProfilesListCommand())
+	return cmd
+}
+
+func newLogsProfilesListCommand() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:           "list",
+		Short:         "List named log tail profiles",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadLogsConfig(cmd.Context())
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if output == "yaml" {
+				enc := yaml.NewEncoder(out)
+				enc.SetIndent(2)
+				if err := enc.Encode(cfg); err != nil {
+					return err
+				}
+				return enc.Close()
+			}
+			names := cfg.ProfileNames()
+			if len(names) == 0 {
+				fmt.Fprintln(cmd.ErrOrStderr(), "No log profiles defined (add logs.profiles to .ktl.yaml)")
+				return nil
+			}
+			tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tQUERY\tNAMESPACES\tDESCRIPTION")
+			for _, name := range names {
+				p := cfg.Profiles[name]
+				namespaces := strings.Join(p.Namespaces, ",")
+				if p.AllNamespaces != nil && *p.AllNamespaces {
+					namespaces = "*"
+				}
+				fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\n", logProfilePrefix, name, dashIfEmpty(p.Query), dashIfEmpty(namespaces), p.Description)
+			}
+			return tw.Flush()
+		},
+	}
+	cmd.Flags().Var(newEnumStringValue(&output, "table", "yaml"), "output", "Output format: table or yaml (the logs config block)")
+	decorateCommandHelp(cmd, "Profile Flags")
+	return cmd
+}
+
+// completeLogProfiles offers `@name` completions for the logs POD_QUERY argument.
+func completeLogProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 || !strings.HasPrefix(toComplete, logProfilePrefix) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg, err := loadLogsConfig(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var out []string
+	for _, name := range cfg.ProfileNames() {
+		candidate := logProfilePrefix + name
+		if !strings.HasPrefix(candidate, toComplete) {
+			continue
+		}
+		if desc := strings.TrimSpace(cfg.Profiles[name].Description); desc != "" {
+			candidate += "\t" + desc
+		}
+		out = append(out, candidate)
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+func dashIfEmpty(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func writeLogProfilesRepo(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	cfg := `logs:
+  profiles:
+    checkout-errors:
+      description: Checkout errors in prod
+      query: checkout-.*
+      namespaces: [prod-payments]
+      highlight: [ERROR]
+      exclude: healthz
+      tail: 50
+      filter: level=error
+`
+	if err := os.WriteFile(filepath.Join(dir, ".ktl.yaml"), []byte(cfg), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+}
+
+func TestApplyLogProfileRespectsExplicitFlags(t *testing.T) {
+	writeLogProfilesRepo(t)
+	profile, err := resolveLogProfile(context.Background(), "@checkout-errors")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	opts := config.NewOptions()
+	cmd := &cobra.Command{Use: "logs"}
+	opts.AddFlags(cmd)
+	if err := cmd.Flags().Parse([]string{"--tail=5"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	jsonQuery := ""
+	applyLogProfile(cmd.Flags(), opts, profile, &jsonQuery)
+
+	if strings.Join(opts.Namespaces, ",") != "prod-payments" {
+		t.Fatalf("expected profile namespaces, got %v", opts.Namespaces)
+	}
+	if len(opts.HighlightTerms) != 1 || opts.HighlightTerms[0] != "ERROR" || opts.ExcludeLine != "healthz" {
+		t.Fatalf("expected profile filters, got highlight=%v exclude=%q", opts.HighlightTerms, opts.ExcludeLine)
+	}
+	if opts.TailLines != 5 {
+		t.Fatalf("explicit --tail must win over the profile, got %d", opts.TailLines)
+	}
+	if jsonQuery != "level=error" {
+		t.Fatalf("expected profile filter, got %q", jsonQuery)
+	}
+}
+
+func TestResolveLogProfileUnknown(t *testing.T) {
+	writeLogProfilesRepo(t)
+	_, err := resolveLogProfile(context.Background(), "@missing")
+	if err == nil || !strings.Contains(err.Error(), "checkout-errors") {
+		t.Fatalf("expected unknown profile error listing available profiles, got %v", err)
+	}
+}
+
+func TestCompleteLogProfiles(t *testing.T) {
+	writeLogProfilesRepo(t)
+	cmd := &cobra.Command{Use: "logs"}
+	cmd.SetContext(context.Background())
+	got, _ := completeLogProfiles(cmd, nil, "@che")
+	if len(got) != 1 || !strings.HasPrefix(got[0], "@checkout-errors\t") {
+		t.Fatalf("unexpected completions: %v", got)
+	}
+	if got, _ := completeLogProfiles(cmd, nil, "check"); len(got) != 0 {
+		t.Fatalf("expected no profile completions without @ prefix, got %v", got)
+	}
+}
@@ -37,6 +37,8 @@ func newLogsRootCommand() *cobra.Command {
 	var remoteKey string
 	var remoteServerName string
 	var mirrorBusAddr string
+	var impersonate impersonationFlags
+	var network networkFlags
 
 	cmd := newLogsCommand(opts, &kubeconfigPath, &kubeContext, &logLevel, &remoteAgentAddr, &remoteToken, &remoteTLS, &remoteInsecure, &remoteCA, &remoteCert, &remoteKey, &remoteServerName, &mirrorBusAddr)
 	cmd.Use = "ktl-logs [POD_QUERY]"
@@ -78,6 +80,12 @@ func newLogsRootCommand() *cobra.Command {
 			color.NoColor = true
 			_ = os.Setenv("NO_COLOR", "1")
 		}
+		if err := impersonate.apply(); err != nil {
+			return err
+		}
+		if err := network.apply(cmd.ErrOrStderr()); err != nil {
+			return err
+		}
 		flags, err := featureflags.Resolve(featureFlagValues, featureflags.EnabledFromEnv(nil))
 		if err != nil {
 			return err
@@ -92,6 +100,8 @@ func newLogsRootCommand() *cobra.Command {
 	cmd.PersistentFlags().StringVar(&logLevel, "log-level", logLevel, "Log level for ktl output (debug, info, warn, error)")
 	cmd.PersistentFlags().IntVar(&kubeLogLevel, "kube-log-level", 0, "Kubernetes client-go verbosity (klog -v); at >=6 enables HTTP request/response tracing; can also set KTL_KUBE_LOG_LEVEL")
 	cmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	impersonate.bind(cmd.PersistentFlags())
+	network.bind(cmd.PersistentFlags())
 	cmd.PersistentFlags().StringSliceVar(&featureFlagValues, "feature", nil, "Enable experimental ktl features (repeat or pass comma-separated names)")
 	if err := cmd.PersistentFlags().MarkHidden("feature"); err != nil {
 		cobra.CheckErr(err)
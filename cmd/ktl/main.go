@@ -26,8 +26,11 @@ import (
 	"github.com/fatih/color"
 	"github.com/go-logr/logr"
 	"github.com/kubekattle/ktl/internal/config"
+	"github.com/kubekattle/ktl/internal/deploy"
 	"github.com/kubekattle/ktl/internal/featureflags"
+	"github.com/kubekattle/ktl/internal/i18n"
 	"github.com/kubekattle/ktl/internal/logging"
+	"github.com/kubekattle/ktl/internal/tailer"
 	"github.com/kubekattle/ktl/internal/workflows/buildsvc"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -56,6 +59,18 @@ func initKlogFlags() {
 }
 
 func main() {
+	mainStarted := time.Now()
+	stopProfile := setupProfiling()
+	defer stopProfile()
+	rootCmd := newRootCommand()
+	// Aliases expand before flag normalization so their expansions get the same treatment as
+	// typed arguments, and before Cobra reads os.Args.
+	expandedArgs, err := resolveAliasArgs(context.Background(), rootCmd, os.Args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	os.Args = expandedArgs
 	normalizedArgs := normalizeOptionalValueArgs(os.Args)
 	if len(normalizedArgs) != len(os.Args) {
 		os.Args = normalizedArgs
@@ -80,17 +95,21 @@ func main() {
 
 	initKlogFlags()
 
-	stopProfile := setupProfiling()
-	defer stopProfile()
-
-	rootCmd := newRootCommand()
-	err := rootCmd.ExecuteContext(ctx)
+	startedAt := time.Now()
+	executed, err := rootCmd.ExecuteContextC(ctx)
+	finishTracing(err, os.Stderr)
+	reportStartupPhases(os.Stderr, startedAt.Sub(mainStarted), time.Since(startedAt))
+	recordAudit(executed, startedAt, err, os.Stderr)
+	recordUsage(executed, startedAt, err)
 	handleError(err)
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			// Match conventional SIGINT exit code while keeping output clean.
 			os.Exit(130)
 		}
+		if errors.Is(err, deploy.ErrChartTestsFailed) {
+			os.Exit(exitChartTestsFailed)
+		}
 		os.Exit(1)
 	}
 }
@@ -122,6 +141,10 @@ func newRootCommandWithBuildService(buildService buildsvc.Service) *cobra.Comman
 	var remoteTLSClientKey string
 	var remoteTLSServerName string
 	globalProfile := "dev"
+	consoleMode := consoleModeAuto
+	var impersonate impersonationFlags
+	var network networkFlags
+	var otel otelFlags
 
 	cmd := &cobra.Command{
 		Use:           "ktl <command>",
@@ -134,6 +157,9 @@ func newRootCommandWithBuildService(buildService buildsvc.Service) *cobra.Comman
 			if commandNamespaceHelpRequested(cmd) {
 				return pflag.ErrHelp
 			}
+			if err := enforceReadOnlyProfile(cmd); err != nil {
+				return err
+			}
 			if kubeLogLevel == 0 {
 				if val := strings.TrimSpace(os.Getenv("KTL_KUBE_LOG_LEVEL")); val != "" {
 					if n, err := strconv.Atoi(val); err == nil {
@@ -162,13 +188,19 @@ func newRootCommandWithBuildService(buildService buildsvc.Service) *cobra.Comman
 				color.NoColor = true
 				_ = os.Setenv("NO_COLOR", "1")
 			}
+			if err := impersonate.apply(); err != nil {
+				return err
+			}
+			if err := network.apply(cmd.ErrOrStderr()); err != nil {
+				return err
+			}
 			flags, err := featureflags.Resolve(featureFlagValues, featureflags.EnabledFromEnv(nil))
 			if err != nil {
 				return err
 			}
 			ctx := featureflags.ContextWithFlags(cmd.Context(), flags)
 			cmd.Root().SetContext(ctx)
-			return nil
+			return otel.start(cmd)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 && looksLikeSubcommandToken(args[0]) {
@@ -191,7 +223,11 @@ func newRootCommandWithBuildService(buildService buildsvc.Service) *cobra.Comman
 	cmd.PersistentFlags().StringVar(&logLevel, "log-level", logLevel, "Log level for ktl output (debug, info, warn, error)")
 	cmd.PersistentFlags().IntVar(&kubeLogLevel, "kube-log-level", 0, "Kubernetes client-go verbosity (klog -v); at >=6 enables HTTP request/response tracing; can also set KTL_KUBE_LOG_LEVEL")
 	cmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
-	cmd.PersistentFlags().Var(newEnumStringValue(&globalProfile, "dev", "ci", "secure", "remote"), "profile", "Execution profile: dev, ci, secure, or remote (sets sensible defaults for supported commands)")
+	cmd.PersistentFlags().Var(newEnumStringValue(&consoleMode, consoleModeAuto, consoleModePlain), "console", "Console style: auto (live panels redrawn in place) or plain (sequential, screen-reader-friendly lines; also via KTL_CONSOLE)")
+	impersonate.bind(cmd.PersistentFlags())
+	network.bind(cmd.PersistentFlags())
+	otel.bind(cmd.PersistentFlags())
+	cmd.PersistentFlags().Var(newEnumStringValue(&globalProfile, "dev", "ci", "secure", "remote"), "profile", "Execution profile: dev, ci, secure, or remote (sets sensible defaults for supported commands; secure only allows read-only commands; env: KTL_EXECUTION_PROFILE)")
 	cmd.PersistentFlags().StringSliceVar(&featureFlagValues, "feature", nil, "Enable experimental ktl features (repeat or pass comma-separated names)")
 	if err := cmd.PersistentFlags().MarkHidden("feature"); err != nil {
 		cobra.CheckErr(err)
@@ -214,25 +250,50 @@ func newRootCommandWithBuildService(buildService buildsvc.Service) *cobra.Comman
 	listCmd := newListCommand(&kubeconfigPath, &kubeContext)
 	lintCmd := newLintCommand(&kubeconfigPath, &kubeContext)
 	envCmd := newEnvCommand()
-	versionCmd := newVersionCommand()
+	versionCmd := newVersionCommand(&kubeconfigPath, &kubeContext)
 	secretsCmd := newSecretsCommand(&kubeconfigPath, &kubeContext)
 	waitCmd := newWaitCommand(&kubeconfigPath, &kubeContext)
+	watchCmd := newWatchCommand(&kubeconfigPath, &kubeContext)
+	capacityCmd := newCapacityCommand(&kubeconfigPath, &kubeContext)
+	certsCmd := newCertsCommand(&kubeconfigPath, &kubeContext)
 	revertCmd := newRevertCommand(&kubeconfigPath, &kubeContext, &logLevel)
+	promoteCmd := newPromoteCommand(&kubeconfigPath, &kubeContext, &logLevel)
+	bundleCmd := newBundleCommand(&kubeconfigPath, &kubeContext, &logLevel)
+	historyCmd := newHistoryCommand(&kubeconfigPath, &kubeContext)
+	ownersCmd := newOwnersCommand(&kubeconfigPath, &kubeContext)
 	tunnelCmd := newTunnelCommand(&kubeconfigPath, &kubeContext)
 	applyCmd := newApplyCommand(&kubeconfigPath, &kubeContext, &logLevel, &remoteAgentAddr)
 	deleteCmd := newDeleteCommand(&kubeconfigPath, &kubeContext, &logLevel, &remoteAgentAddr)
 	stackCmd := newStackCommand(&kubeconfigPath, &kubeContext, &logLevel, &remoteAgentAddr)
 	upCmd := newUpCommand(&kubeconfigPath, &kubeContext)
+	syncCmd := newSyncCommand(&kubeconfigPath, &kubeContext)
+	debugCmd := newDebugCommand(&kubeconfigPath, &kubeContext)
+	trafficCmd := newTrafficCommand(&kubeconfigPath, &kubeContext)
+	serveCmd := newServeCommand(&kubeconfigPath, &kubeContext, &logLevel)
+	bootstrapCmd := newBootstrapCommand(&kubeconfigPath, &kubeContext)
+	auditCmd := newAuditCommand()
+	approveCmd := newApproveCommand()
+	rbacCmd := newRBACCommand(&kubeconfigPath, &kubeContext)
+	templateCmd := newTemplateCommand(&kubeconfigPath, &kubeContext)
+	testCmd := newTestCommand(&kubeconfigPath, &kubeContext, &logLevel)
+	ctxCmd := newCtxCommand(&kubeconfigPath, &kubeContext)
+	nsCmd := newNsCommand(&kubeconfigPath, &kubeContext)
 	cmd.AddCommand(
 		initCmd,
 		buildCmd,
 		analyzeCmd,
 		revertCmd,
+		promoteCmd,
+		bundleCmd,
+		historyCmd,
 		applyCmd,
+		templateCmd,
+		testCmd,
 		tunnelCmd,
 		deleteCmd,
 		stackCmd,
 		listCmd,
+		ownersCmd,
 		lintCmd,
 		logsCmd,
 		envCmd,
@@ -240,7 +301,27 @@ func newRootCommandWithBuildService(buildService buildsvc.Service) *cobra.Comman
 		versionCmd,
 		upCmd,
 		waitCmd,
+		watchCmd,
+		capacityCmd,
+		certsCmd,
+		syncCmd,
+		debugCmd,
+		trafficCmd,
+		serveCmd,
+		bootstrapCmd,
+		auditCmd,
+		approveCmd,
+		newCaptureCommand(),
+		rbacCmd,
+		ctxCmd,
+		nsCmd,
+		newAliasCommand(),
+		newConfigCommand(),
+		newSelfUpdateCommand(),
+		newTelemetryCommand(),
+		newCompletionCommand(),
 	)
+	cmd.ValidArgsFunction = completeAliases
 	cmd.SetHelpCommand(newHelpCommand(cmd))
 	cmd.Example = `  # Tail checkout pods in prod-payments and highlight errors
 	  ktl logs 'checkout-.*' --namespace prod-payments --highlight ERROR
@@ -264,6 +345,10 @@ func newRootCommandWithBuildService(buildService buildsvc.Service) *cobra.Comman
 
 	_ = cmd.RegisterFlagCompletionFunc("profile", cobra.FixedCompletions([]string{"dev", "ci", "secure", "remote"}, cobra.ShellCompDirectiveNoFileComp))
 	_ = cmd.RegisterFlagCompletionFunc("log-level", cobra.FixedCompletions([]string{"debug", "info", "warn", "error"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("console", cobra.FixedCompletions([]string{consoleModeAuto, consoleModePlain}, cobra.ShellCompDirectiveNoFileComp))
+	registerDynamicCompletions(cmd, &kubeconfigPath, &kubeContext)
+	ctxCmd.ValidArgsFunction = completeKubeContexts(&kubeconfigPath)
+	nsCmd.ValidArgsFunction = completeNamespaces(&kubeconfigPath, &kubeContext)
 
 	// Keep the root help output stable and grouped for scanability.
 	cmd.SetHelpTemplate(rootHelpTemplate())
@@ -280,7 +365,7 @@ Usage:
   {{.UseLine}}
 
 Subcommands:
-{{- range $i, $n := (list "init" "build" "apply" "delete" "stack" "revert" "list" "lint" "logs" "tunnel" "env" "secrets" "version") }}
+{{- range $i, $n := (list "init" "build" "apply" "delete" "stack" "revert" "promote" "history" "list" "lint" "logs" "tunnel" "env" "secrets" "version") }}
 {{- with (indexCommand $.Commands $n) }}
   {{rpad .Name .NamePadding }} {{.Short}}
 {{- end }}
@@ -372,6 +457,15 @@ func registerViperCommands(commands ...*cobra.Command) {
 			configFile := os.Getenv("KTL_CONFIG")
 			configureConfigFile(v, configFile)
 
+			if err := readConfigFile(v, configFile != ""); err != nil {
+				cobra.CheckErr(err)
+			}
+			// Without a config file or KTL_* variables there is nothing to apply; skip binding
+			// every registered flag so local commands stay cheap.
+			if v.ConfigFileUsed() == "" && !ktlEnvPresent() {
+				return
+			}
+
 			viperMu.Lock()
 			cmds := append([]*cobra.Command(nil), viperCmds...)
 			viperMu.Unlock()
@@ -384,14 +478,13 @@ func registerViperCommands(commands ...*cobra.Command) {
 					cobra.CheckErr(err)
 				}
 			}
-			if err := readConfigFile(v, configFile != ""); err != nil {
-				cobra.CheckErr(err)
-			}
 			for _, cmd := range cmds {
 				flagSets := []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()}
 				for _, fs := range flagSets {
 					fs.VisitAll(func(f *pflag.Flag) {
-						if f.Changed {
+						// KTL_PROFILE is the startup profiler; the execution profile reads
+						// KTL_EXECUTION_PROFILE instead (see applyExecutionProfileEnv).
+						if f.Changed || f.Name == "profile" {
 							return
 						}
 						if !v.IsSet(f.Name) {
@@ -418,11 +511,11 @@ func handleError(err error) {
 	message := err.Error()
 	switch {
 	case errors.Is(err, context.DeadlineExceeded):
-		message = fmt.Sprintf("%s\nHint: increase --duration or verify network connectivity to the cluster.", err)
+		message = fmt.Sprintf("%s\n%s %s", err, i18n.T(i18n.HintLabel), i18n.T(i18n.HintDeadlineExceeded))
 	case apierrors.IsUnauthorized(err):
-		message = fmt.Sprintf("%s\nHint: kubeconfig credentials were rejected. Run 'kubectl config view' to confirm the active user.", err)
+		message = fmt.Sprintf("%s\n%s %s", err, i18n.T(i18n.HintLabel), i18n.T(i18n.HintUnauthorized))
 	case apierrors.IsForbidden(err):
-		message = fmt.Sprintf("%s\nHint: missing Kubernetes permissions. See docs/rbac.md for the verbs ktl requires.", err)
+		message = fmt.Sprintf("%s\n%s %s", err, i18n.T(i18n.HintLabel), i18n.T(i18n.HintForbidden))
 	}
 	writeHighlightedError(os.Stderr, message)
 }
@@ -432,8 +525,9 @@ func writeHighlightedError(w io.Writer, message string) {
 		return
 	}
 	if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) && !color.NoColor {
-		errPrefix := color.New(color.FgRed, color.Bold).Sprint("Error:")
-		hintPrefix := color.New(color.FgYellow, color.Bold).Sprint("Hint:")
+		errPrefix := color.New(color.FgRed, color.Bold).Sprint(i18n.T(i18n.ErrorLabel))
+		hintLabel := i18n.T(i18n.HintLabel)
+		hintPrefix := color.New(color.FgYellow, color.Bold).Sprint(hintLabel)
 		lines := strings.Split(message, "\n")
 		if len(lines) == 0 {
 			fmt.Fprintf(w, "%s\n", errPrefix)
@@ -442,8 +536,8 @@ func writeHighlightedError(w io.Writer, message string) {
 		fmt.Fprintf(w, "%s %s\n", errPrefix, lines[0])
 		for _, line := range lines[1:] {
 			trimmed := strings.TrimSpace(line)
-			if strings.HasPrefix(trimmed, "Hint:") {
-				rest := strings.TrimSpace(strings.TrimPrefix(trimmed, "Hint:"))
+			if strings.HasPrefix(trimmed, hintLabel) {
+				rest := strings.TrimSpace(strings.TrimPrefix(trimmed, hintLabel))
 				if rest != "" {
 					fmt.Fprintf(w, "%s %s\n", hintPrefix, rest)
 				} else {
@@ -455,7 +549,7 @@ func writeHighlightedError(w io.Writer, message string) {
 		}
 		return
 	}
-	fmt.Fprintf(w, "Error: %s\n", message)
+	fmt.Fprintf(w, "%s %s\n", i18n.T(i18n.ErrorLabel), message)
 }
 
 func streamFromStdin(ctx context.Context, opts *config.Options, in io.Reader, out io.Writer) error {
@@ -463,8 +557,13 @@ func streamFromStdin(ctx context.Context, opts *config.Options, in io.Reader, ou
 	if err != nil {
 		return fmt.Errorf("parse template: %w", err)
 	}
+	defaultTemplate := strings.TrimSpace(opts.Template) == config.DefaultTemplate()
 	scanner := bufio.NewScanner(in)
 	highlight := color.New(color.BgYellow, color.FgBlack)
+	var anomalies *tailer.AnomalyDetector
+	if opts.Anomalies {
+		anomalies = tailer.NewAnomalyDetector(opts.AnomalyWarmup)
+	}
 	for scanner.Scan() {
 		select {
 		case <-ctx.Done():
@@ -483,6 +582,7 @@ func streamFromStdin(ctx context.Context, opts *config.Options, in io.Reader, ou
 				})
 			}
 		}
+		anomaly := anomalies.Observe(line, time.Now())
 		timestamp := ""
 		if opts.ShowTimestamp {
 			now := time.Now()
@@ -500,6 +600,7 @@ func streamFromStdin(ctx context.Context, opts *config.Options, in io.Reader, ou
 			ContainerTag  string
 			Message       string
 			Raw           string
+			Anomaly       string
 		}{
 			Timestamp:     timestamp,
 			Namespace:     "-",
@@ -509,16 +610,30 @@ func streamFromStdin(ctx context.Context, opts *config.Options, in io.Reader, ou
 			ContainerTag:  "[stdin]",
 			Message:       message,
 			Raw:           line,
+			Anomaly:       string(anomaly),
 		}
 		var b strings.Builder
 		if err := tmpl.Execute(&b, entry); err != nil {
 			return fmt.Errorf("execute template: %w", err)
 		}
-		fmt.Fprintln(out, b.String())
+		rendered := b.String()
+		if defaultTemplate {
+			rendered = tailer.AnomalyMarker(anomaly, !color.NoColor) + rendered
+		}
+		fmt.Fprintln(out, rendered)
 	}
 	return scanner.Err()
 }
 
+func ktlEnvPresent() bool {
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "KTL_") {
+			return true
+		}
+	}
+	return false
+}
+
 func configureConfigFile(v *viper.Viper, explicitPath string) {
 	if explicitPath != "" {
 		v.SetConfigFile(explicitPath)
@@ -614,6 +729,18 @@ func setupProfiling() func() {
 	}
 }
 
+// reportStartupPhases prints how long building the command tree and executing the command took
+// when KTL_PROFILE=startup. Kubernetes/Helm clients are only constructed inside the commands that
+// need them, so local commands should spend well under a millisecond in both phases; package
+// initialization happens before main and is reported by GODEBUG=inittrace=1.
+func reportStartupPhases(w io.Writer, build, execute time.Duration) {
+	if strings.ToLower(os.Getenv("KTL_PROFILE")) != "startup" {
+		return
+	}
+	fmt.Fprintf(w, "KTL_PROFILE=startup: build=%s execute=%s (package init excluded; see GODEBUG=inittrace=1)\n",
+		build.Round(time.Microsecond), execute.Round(time.Microsecond))
+}
+
 func newUpCommand(kubeconfig, kubeContext *string) *cobra.Command {
 	return &cobra.Command{
 		Use:   "up",
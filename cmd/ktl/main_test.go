@@ -8,13 +8,16 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 	"testing"
 
+	"github.com/kubekattle/ktl/internal/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -263,3 +266,39 @@ func TestRootIncludesLintCommand(t *testing.T) {
 
 func TestRootIncludesPackageCommand(t *testing.T) {
 }
+
+func TestStreamFromStdinJSONOutputStaysValidWithAnomalies(t *testing.T) {
+	for _, format := range []string{"json", "extjson", "ppextjson"} {
+		opts := config.NewOptions()
+		opts.OutputFormat = format
+		opts.Anomalies = true
+		opts.AnomalyWarmup = 20
+		if err := opts.Validate(); err != nil {
+			t.Fatalf("%s: validate: %v", format, err)
+		}
+		var in strings.Builder
+		for i := 0; i < 40; i++ {
+			fmt.Fprintf(&in, "GET /api/orders/%d served status=200\n", i)
+		}
+		in.WriteString("connection pool exhausted; dropping upstream socket\n")
+
+		var out bytes.Buffer
+		if err := streamFromStdin(context.Background(), opts, strings.NewReader(in.String()), &out); err != nil {
+			t.Fatalf("%s: stream: %v", format, err)
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		var flagged int
+		for _, line := range lines {
+			var rec map[string]any
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatalf("%s: output line is not JSON: %q: %v", format, line, err)
+			}
+			if rec["anomaly"] != nil {
+				flagged++
+			}
+		}
+		if flagged == 0 {
+			t.Fatalf("%s: expected the novel line to carry an anomaly field, got %s", format, out.String())
+		}
+	}
+}
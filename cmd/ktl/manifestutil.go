@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"strings"
 
-	"helm.sh/helm/v3/pkg/releaseutil"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/yaml"
 )
@@ -40,30 +39,53 @@ type manifestDoc struct {
 	TemplateSource string
 }
 
-// parseManifestDocs converts a Helm manifest blob into structured entries.
+// parseManifestDocs converts a Helm manifest blob into structured entries. Bodies are slices of
+// manifest rather than copies, so huge renders are not held in memory twice.
 func parseManifestDocs(manifest string) []manifestDoc {
-	files := releaseutil.SplitManifests(manifest)
-	docs := make([]manifestDoc, 0, len(files))
-	for name, doc := range files {
-		trimmed := strings.TrimSpace(doc)
-		if trimmed == "" {
-			continue
-		}
+	var docs []manifestDoc
+	forEachManifestDoc(manifest, func(name, body string) {
 		var obj map[string]interface{}
-		if err := yaml.Unmarshal([]byte(trimmed), &obj); err != nil {
-			continue
+		if err := yaml.Unmarshal([]byte(body), &obj); err != nil {
+			return
 		}
 		u := &unstructured.Unstructured{Object: obj}
 		docs = append(docs, manifestDoc{
 			Key:            toResourceKey(u),
-			Body:           trimmed,
+			Body:           body,
 			Obj:            u,
-			TemplateSource: pickTemplateSource(trimmed, name),
+			TemplateSource: pickTemplateSource(body, name),
 		})
-	}
+	})
 	return docs
 }
 
+// forEachManifestDoc streams the YAML documents of manifest (split on "---" lines) to fn, naming
+// them manifest-N like releaseutil.SplitManifests without building an intermediate map.
+func forEachManifestDoc(manifest string, fn func(name, body string)) {
+	count := 0
+	emit := func(doc string) {
+		if doc = strings.TrimSpace(doc); doc == "" {
+			return
+		}
+		fn(fmt.Sprintf("manifest-%d", count), doc)
+		count++
+	}
+	start := 0
+	for pos := 0; pos < len(manifest); {
+		end := strings.IndexByte(manifest[pos:], '\n')
+		next := len(manifest)
+		if end >= 0 {
+			next = pos + end + 1
+		}
+		if strings.HasPrefix(manifest[pos:next], "---") {
+			emit(manifest[start:pos])
+			start = next
+		}
+		pos = next
+	}
+	emit(manifest[start:])
+}
+
 func pickTemplateSource(manifestBody, fallback string) string {
 	lines := strings.Split(manifestBody, "\n")
 	for _, line := range lines {
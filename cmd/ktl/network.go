@@ -0,0 +1,71 @@
+// File: cmd/ktl/network.go
+// Brief: Global --proxy/--tls-ca-file/--insecure-skip-tls-verify/--airgap wiring.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kubekattle/ktl/internal/netconfig"
+	"github.com/spf13/pflag"
+)
+
+type networkFlags struct {
+	proxy    string
+	caFile   string
+	insecure bool
+	airgap   bool
+	allow    []string
+}
+
+func (f *networkFlags) bind(flags *pflag.FlagSet) {
+	flags.StringVar(&f.proxy, "proxy", "", "Proxy URL for Kubernetes, Helm repository/registry, and URL fetches in this command (NO_PROXY still applies)")
+	flags.StringVar(&f.caFile, "tls-ca-file", "", "PEM bundle to trust in addition to the system and kubeconfig CAs (for example a TLS-intercepting proxy's CA)")
+	flags.BoolVar(&f.insecure, "insecure-skip-tls-verify", false, "DANGEROUS: skip TLS certificate verification for the Kubernetes API, chart repositories, and registries in this command")
+	flags.BoolVar(&f.airgap, "airgap", false, "Air-gapped mode: fail any fetch other than the Kubernetes API and --airgap-allow hosts; charts must be local with vendored dependencies")
+	flags.StringSliceVar(&f.allow, "airgap-allow", nil, "Host (or .domain suffix) still reachable with --airgap, e.g. an internal registry (repeatable)")
+}
+
+// apply installs the settings for this invocation only (nothing is persisted) and prints a
+// warning for each override so an insecure run is never silent.
+func (f *networkFlags) apply(errOut io.Writer) error {
+	opts := netconfig.Options{Proxy: f.proxy, CAFile: f.caFile, InsecureSkipVerify: f.insecure, Airgap: f.airgap, AirgapAllow: f.allow}
+	if len(f.allow) > 0 && !f.airgap {
+		return fmt.Errorf("--airgap-allow requires --airgap")
+	}
+	if !opts.Active() {
+		return nil
+	}
+	if err := netconfig.Set(opts); err != nil {
+		return err
+	}
+	for _, w := range netconfig.Warnings(opts) {
+		fmt.Fprintf(errOut, "WARNING: %s\n", w)
+	}
+	return nil
+}
+
+// applyInheritedNetwork applies the network flags for commands whose own PersistentPreRunE
+// shadows the root hook.
+func applyInheritedNetwork(flags *pflag.FlagSet, errOut io.Writer) error {
+	var f networkFlags
+	if flag := flags.Lookup("proxy"); flag != nil {
+		f.proxy = flag.Value.String()
+	}
+	if flag := flags.Lookup("tls-ca-file"); flag != nil {
+		f.caFile = flag.Value.String()
+	}
+	if insecure, err := flags.GetBool("insecure-skip-tls-verify"); err == nil {
+		f.insecure = insecure
+	}
+	if airgap, err := flags.GetBool("airgap"); err == nil {
+		f.airgap = airgap
+	}
+	if allow, err := flags.GetStringSlice("airgap-allow"); err == nil {
+		f.allow = allow
+	}
+	return f.apply(errOut)
+}
@@ -0,0 +1,90 @@
+// File: cmd/ktl/otel.go
+// Brief: Global --otel-endpoint wiring.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/tracing"
+	"github.com/kubekattle/ktl/internal/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// otelFlushTimeout bounds how long exiting waits for the collector to take buffered spans.
+const otelFlushTimeout = 5 * time.Second
+
+type otelFlags struct {
+	endpoint string
+}
+
+func (f *otelFlags) bind(flags *pflag.FlagSet) {
+	flags.StringVar(&f.endpoint, "otel-endpoint", "", "Export OpenTelemetry traces of deploy phases, Helm and Kubernetes API calls, and stack nodes to this OTLP/HTTP collector, e.g. http://localhost:4318 (also via KTL_OTEL_ENDPOINT)")
+}
+
+// activeTrace is the command span started by the pre-run hook; main ends it and flushes the
+// exporter before exiting.
+var activeTrace struct {
+	span     trace.Span
+	shutdown func(context.Context) error
+}
+
+// start installs the exporter and opens the command span, which becomes the parent of every
+// span the command records.
+func (f *otelFlags) start(cmd *cobra.Command) error {
+	endpoint := strings.TrimSpace(f.endpoint)
+	if endpoint == "" {
+		endpoint = strings.TrimSpace(os.Getenv(tracing.EnvEndpoint))
+	}
+	if endpoint == "" || activeTrace.span != nil {
+		return nil
+	}
+	shutdown, err := tracing.Setup(cmd.Context(), tracing.Options{Endpoint: endpoint, ServiceVersion: version.Get().Version})
+	if err != nil {
+		return fmt.Errorf("--otel-endpoint: %w", err)
+	}
+	ctx, span := tracing.StartRoot(cmd.Context(), cmd.CommandPath(), attribute.String("ktl.command", cmd.CommandPath()))
+	activeTrace.span = span
+	activeTrace.shutdown = shutdown
+	cmd.SetContext(ctx)
+	return nil
+}
+
+// startInheritedTracing starts tracing for commands whose own PersistentPreRunE shadows the
+// root hook.
+func startInheritedTracing(cmd *cobra.Command) error {
+	var f otelFlags
+	if flag := cmd.Flags().Lookup("otel-endpoint"); flag != nil {
+		f.endpoint = flag.Value.String()
+	}
+	return f.start(cmd)
+}
+
+// finishTracing ends the command span with runErr and flushes it. An export failure is only
+// reported: it must not change the command's exit status.
+func finishTracing(runErr error, errOut io.Writer) {
+	if activeTrace.span == nil {
+		return
+	}
+	if runErr != nil && errors.Is(runErr, pflag.ErrHelp) {
+		runErr = nil
+	}
+	tracing.End(activeTrace.span, runErr)
+	ctx, cancel := context.WithTimeout(context.Background(), otelFlushTimeout)
+	defer cancel()
+	if err := activeTrace.shutdown(ctx); err != nil {
+		fmt.Fprintf(errOut, "WARNING: export traces: %v\n", err)
+	}
+	activeTrace.span = nil
+}
@@ -0,0 +1,125 @@
+// File: cmd/ktl/owners.go
+// Brief: CLI command wiring and implementation for 'owners'.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// releaseOwnerReport is the `ktl owners` output for one release.
+type releaseOwnerReport struct {
+	Release   string `json:"release"`
+	Namespace string `json:"namespace"`
+	Revision  int    `json:"revision"`
+	Status    string `json:"status,omitempty"`
+	deploy.ReleaseOwner
+}
+
+func newOwnersCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
+	var namespace string
+	var releaseName string
+	format := "text"
+
+	cmd := &cobra.Command{
+		Use:   "owners",
+		Short: "Show who owns a Helm release",
+		Long: `Print the team, Slack channel, and on-call contact recorded on a release.
+
+Owners are recorded when ktl apply or ktl stack apply deploys the release: from the first
+deploy.owners rule in .ktl.yaml that selects it, or from owner in stack.yaml. Use
+ktl list --owner <team> to find every release a team owns.`,
+		Example: `  ktl owners --release payments-api -n prod
+  ktl owners --release payments-api -n prod --format json`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			kubeClient, err := kube.New(ctx, *kubeconfig, *kubeContext)
+			if err != nil {
+				return err
+			}
+			resolvedNamespace := strings.TrimSpace(namespace)
+			if resolvedNamespace == "" {
+				resolvedNamespace = kubeClient.Namespace
+			}
+			if resolvedNamespace == "" {
+				resolvedNamespace = "default"
+			}
+
+			settings := cli.New()
+			if kubeconfig != nil && *kubeconfig != "" {
+				settings.KubeConfig = *kubeconfig
+			}
+			if kubeContext != nil && *kubeContext != "" {
+				settings.KubeContext = *kubeContext
+			}
+			settings.SetNamespace(resolvedNamespace)
+			actionCfg := new(action.Configuration)
+			if err := actionCfg.Init(settings.RESTClientGetter(), resolvedNamespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+				return fmt.Errorf("init helm action config: %w", err)
+			}
+
+			rel, err := actionCfg.Releases.Last(releaseName)
+			if err != nil {
+				return fmt.Errorf("release %s/%s: %w", resolvedNamespace, releaseName, err)
+			}
+			annotations, err := deploy.StoredReleaseAnnotations(cmd.Context(), actionCfg, rel)
+			if err != nil {
+				return err
+			}
+			report, err := releaseOwners(rel, annotations)
+			if err != nil {
+				return err
+			}
+			return writeReleaseOwners(cmd.OutOrStdout(), report, format)
+		},
+	}
+	cmd.Flags().StringVar(&releaseName, "release", "", "Helm release name")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the Helm release (defaults to active context)")
+	cmd.Flags().Var(newEnumStringValue(&format, "text", "json"), "format", "Output format: text or json")
+	_ = cmd.MarkFlagRequired("release")
+	decorateCommandHelp(cmd, "Owners Flags")
+	return cmd
+}
+
+// releaseOwners builds the report for rel from the annotations of its storage object, or explains
+// how to record an owner when it has none.
+func releaseOwners(rel *release.Release, annotations map[string]string) (releaseOwnerReport, error) {
+	owner := deploy.ReleaseOwnerOf(rel, annotations)
+	if owner == nil {
+		return releaseOwnerReport{}, fmt.Errorf("release %s/%s has no owner recorded (add a deploy.owners rule to .ktl.yaml or owner to stack.yaml and re-apply)", rel.Namespace, rel.Name)
+	}
+	report := releaseOwnerReport{Release: rel.Name, Namespace: rel.Namespace, Revision: rel.Version, ReleaseOwner: *owner}
+	if rel.Info != nil {
+		report.Status = rel.Info.Status.String()
+	}
+	return report, nil
+}
+
+func writeReleaseOwners(w io.Writer, report releaseOwnerReport, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Release\t%s/%s (rev %d, %s)\n", report.Namespace, report.Release, report.Revision, orDash(report.Status))
+	fmt.Fprintf(tw, "Team\t%s\n", orDash(report.Team))
+	fmt.Fprintf(tw, "Slack\t%s\n", orDash(report.Slack))
+	fmt.Fprintf(tw, "On-call\t%s\n", orDash(report.Oncall))
+	return tw.Flush()
+}
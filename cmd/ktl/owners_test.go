@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func ownedRelease(name string) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: "prod",
+		Version:   4,
+		Info:      &release.Info{Status: release.StatusFailed},
+	}
+}
+
+func ownerAnnotations(team, slack string) map[string]string {
+	return map[string]string{deploy.OwnerTeamAnnotation: team, deploy.OwnerSlackAnnotation: slack}
+}
+
+func TestReleaseOwnersOutput(t *testing.T) {
+	report, err := releaseOwners(ownedRelease("payments-api"), ownerAnnotations("team-payments", "#payments-oncall"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := writeReleaseOwners(&out, report, "text"); err != nil {
+		t.Fatal(err)
+	}
+	want := "Release  prod/payments-api (rev 4, failed)\nTeam     team-payments\nSlack    #payments-oncall\nOn-call  -\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out.String(), want)
+	}
+	out.Reset()
+	if err := writeReleaseOwners(&out, report, "json"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"team": "team-payments"`) || strings.Contains(out.String(), `"oncall"`) {
+		t.Fatalf("unexpected json:\n%s", out.String())
+	}
+
+	_, err = releaseOwners(&release.Release{Name: "search", Namespace: "prod"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "release prod/search has no owner recorded") {
+		t.Fatalf("expected a missing owner error, got %v", err)
+	}
+}
+
+func TestReleasesOwnedBy(t *testing.T) {
+	releases := []*release.Release{
+		ownedRelease("payments-api"),
+		ownedRelease("search"),
+		{Name: "legacy", Labels: map[string]string{deploy.OwnerTeamLabel: "team-payments"}},
+		{Name: "unowned"},
+	}
+	annotations := map[string]map[string]string{
+		deploy.ReleaseStorageKey(releases[0]): ownerAnnotations("team-payments", ""),
+		deploy.ReleaseStorageKey(releases[1]): ownerAnnotations("team-search", ""),
+	}
+	got := releaseNames(releasesOwnedBy(releases, annotations, "Team-Payments"))
+	if strings.Join(got, ",") != "payments-api,legacy" {
+		t.Fatalf("unexpected releases: %v", got)
+	}
+}
@@ -0,0 +1,359 @@
+// File: cmd/ktl/promote.go
+// Brief: CLI command wiring and implementation for 'promote'.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap/zapcore"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+)
+
+func newPromoteCommand(kubeconfig *string, kubeContext *string, logLevel *string) *cobra.Command {
+	var stackDir string
+	var releaseName string
+	var from, to stack.EnvSide
+	var wait bool
+	var timeout time.Duration
+	var dryRun bool
+	var yes bool
+	var nonInteractive bool
+	var verbose bool
+	var postRender postRenderFlags
+	var gates deployGateFlags
+
+	wait = true
+	timeout = 5 * time.Minute
+
+	cmd := &cobra.Command{
+		Use:   "promote",
+		Short: "Promote a stack release from one environment to another",
+		Long: `Copy what is running in one environment to the next: the chart version and Helm values
+deployed in --from are applied to the same release in --to.
+
+Values keys that either environment sets differently from the base stack (its profile or
+overlay values, set entries, ...) stay environment-specific: they are dropped from the
+promoted values and the target's own values are used instead. Everything else comes from
+the deployed source release.
+
+The target plan is shown before anything changes and must be approved. Like ktl apply, the
+promotion respects deploy.windows and the approval policy in .ktl.yaml. Every promoted object
+is annotated with ktl.dev/promoted-from, ktl.dev/promoted-at, and ktl.dev/promotion-chain,
+which lists each environment the release passed through (for example dev@12,staging@7).`,
+		Example: `  # Promote the api release from staging to prod
+  ktl promote --release api --from staging --to prod --stack ./stacks/platform
+
+  # Environments defined by overlay files
+  ktl promote --release api --from staging --from-overlay envs/staging.yaml --to prod --to-overlay envs/prod.yaml
+
+  # Only show the plan
+  ktl promote --release api --from staging --to prod --dry-run`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateVerboseLogLevel(cmd, verbose, logLevel); err != nil {
+				return err
+			}
+			if err := validateNonInteractive(cmd, nonInteractive, yes); err != nil {
+				return err
+			}
+			if timeout <= 0 {
+				return fmt.Errorf("--timeout must be > 0")
+			}
+			if strings.TrimSpace(from.Label) == "" || strings.TrimSpace(to.Label) == "" {
+				return fmt.Errorf("--from and --to are required")
+			}
+			if from.Label == to.Label && strings.Join(from.Overlays, "\x00") == strings.Join(to.Overlays, "\x00") {
+				return fmt.Errorf("--from and --to are the same environment")
+			}
+			return gates.validate()
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			currentLogLevel := effectiveLogLevel(logLevel)
+			errOut := cmd.ErrOrStderr()
+			startedAt := time.Now()
+			report := reportLine{
+				Kind:    "promote",
+				Release: releaseName,
+				DryRun:  dryRun,
+				Wait:    wait,
+			}
+			defer func() {
+				report.Result = "success"
+				if runErr != nil {
+					report.Result = "fail"
+				}
+				report.ElapsedMS = time.Since(startedAt).Milliseconds()
+				writeReportTable(errOut, report)
+			}()
+
+			dec, err := approvalMode(cmd, yes, nonInteractive)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			debug := shouldLogAtLevel(currentLogLevel, zapcore.DebugLevel)
+
+			from.Profile, to.Profile = from.Label, to.Label
+			u, err := stack.Discover(stackDir)
+			if err != nil {
+				return err
+			}
+			p, err := stack.PlanPromotion(u, releaseName, from, to)
+			if err != nil {
+				return err
+			}
+			report.Namespace = p.Target.Namespace
+
+			srcCfg, _, err := promotionActionConfig(p.Source, *kubeconfig, *kubeContext, debug)
+			if err != nil {
+				return fmt.Errorf("%s: %w", from.Label, err)
+			}
+			srcRel, err := action.NewGet(srcCfg).Run(p.Source.Name)
+			if err != nil {
+				if errors.Is(err, driver.ErrReleaseNotFound) {
+					return fmt.Errorf("release %s is not deployed in %s (%s/%s)", p.Source.Name, from.Label, p.Source.Cluster.Name, p.Source.Namespace)
+				}
+				return fmt.Errorf("helm get %s in %s: %w", p.Source.Name, from.Label, err)
+			}
+			source := deploy.PromotionSource{
+				Env:       from.Label,
+				Cluster:   p.Source.Cluster.Name,
+				Namespace: srcRel.Namespace,
+				Release:   srcRel.Name,
+				Revision:  srcRel.Version,
+			}
+			chartVersion := releaseChartVersion(srcRel)
+			promoted := p.PromotedValues(srcRel.Config)
+			annotations := deploy.PromotionAnnotations(source, deploy.ReadPromotionChain(srcRel.Manifest), startedAt)
+
+			valuesFile, err := writePromotedValues(promoted)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(valuesFile)
+
+			dstCfg, settings, err := promotionActionConfig(p.Target, *kubeconfig, *kubeContext, debug)
+			if err != nil {
+				return fmt.Errorf("%s: %w", to.Label, err)
+			}
+			currentVersion := ""
+			if rel, err := action.NewGet(dstCfg).Run(p.Target.Name); err == nil {
+				currentVersion = releaseChartVersion(rel)
+			}
+
+			var extra []appconfig.PostRendererConfig
+			if p.Target.Apply.Images != nil {
+				extra = append(extra, appconfig.PostRendererConfig{Name: "apply.images", Images: p.Target.Apply.Images})
+			}
+			extra = append(extra, deploy.PromotionPostRenderStep(annotations))
+			postRenderer, err := loadPostRenderer(ctx, settings, postRender, extra...)
+			if err != nil {
+				return err
+			}
+			atomic := true
+			if p.Target.Apply.Atomic != nil {
+				atomic = *p.Target.Apply.Atomic
+			}
+			createNamespace := false
+			if p.Target.Apply.CreateNamespace != nil {
+				createNamespace = *p.Target.Apply.CreateNamespace
+			}
+			opts := deploy.InstallOptions{
+				Chart:           p.Target.Chart,
+				Version:         chartVersion,
+				ReleaseName:     p.Target.Name,
+				Namespace:       p.Target.Namespace,
+				ValuesFiles:     append(append([]string(nil), p.Target.Values...), valuesFile),
+				SetValues:       promotionSetValues(p.TargetSet()),
+				Timeout:         timeout,
+				Wait:            wait,
+				Atomic:          atomic,
+				CreateNamespace: createNamespace,
+				PostRenderer:    postRenderer,
+			}
+
+			fmt.Fprintf(errOut, "Promoting %s from %s to %s (%s/%s)\n", p.Release, source, to.Label, p.Target.Cluster.Name, p.Target.Namespace)
+			fmt.Fprintf(errOut, "Chart %s: %s → %s\n", p.Target.Chart, orDash(currentVersion), orDash(chartVersion))
+			printPromotedKeys(errOut, promoted, p.EnvKeys)
+			fmt.Fprintf(errOut, "Promotion chain: %s\n", annotations[deploy.PromotionChainAnnotation])
+
+			preview, err := deploy.GeneratePlanPreview(ctx, dstCfg, settings, nil, opts, false)
+			if err != nil {
+				return err
+			}
+			printPlanPreview(errOut, preview, currentLogLevel)
+			printImageReport(errOut, postRender, postRenderer)
+
+			if dryRun {
+				fmt.Fprintf(cmd.OutOrStdout(), "Promotion plan: would promote %s revision %d from %s to %s\n", p.Release, source.Revision, from.Label, to.Label)
+				return nil
+			}
+			target := releaseWindowTargets([]*stack.ResolvedRelease{p.Target}, *kubeconfig, *kubeContext)[0]
+			if err := enforceDeployGates(cmd, errOut, gates, target, p.Target.Name, preview, u.RootDir); err != nil {
+				return err
+			}
+			if err := confirmAction(ctx, cmd.InOrStdin(), errOut, dec, fmt.Sprintf("Promote %s from %s to %s? Only 'yes' will be accepted:", p.Release, from.Label, to.Label), confirmModeYes, ""); err != nil {
+				return err
+			}
+
+			result, err := deploy.InstallOrUpgrade(ctx, dstCfg, settings, opts)
+			if err != nil {
+				return err
+			}
+			status := "unknown"
+			if rel := result.Release; rel != nil {
+				if rel.Info != nil {
+					status = rel.Info.Status.String()
+				}
+				if rel.Chart != nil && rel.Chart.Metadata != nil {
+					report.Chart = rel.Chart.Metadata.Name
+					report.Version = rel.Chart.Metadata.Version
+				}
+				report.Revision = rel.Version
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Release %s %s in %s (revision %d)\n", p.Target.Name, status, to.Label, report.Revision)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&releaseName, "release", "", "Stack release to promote (name, or name@cluster when it runs in several clusters)")
+	cmd.Flags().StringVar(&stackDir, "stack", ".", "Stack root directory")
+	cmd.Flags().StringVar(&from.Label, "from", "", "Source environment (stack profile)")
+	cmd.Flags().StringVar(&to.Label, "to", "", "Target environment (stack profile)")
+	cmd.Flags().StringArrayVar(&from.Overlays, "from-overlay", nil, "Overlay file for the source environment (repeatable; later files win)")
+	cmd.Flags().StringArrayVar(&to.Overlays, "to-overlay", nil, "Overlay file for the target environment (repeatable; later files win)")
+	cmd.Flags().BoolVar(&wait, "wait", true, "Wait for resources to become ready")
+	cmd.Flags().DurationVar(&timeout, "timeout", timeout, "How long to wait for the upgrade")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the promotion plan and exit without changing the cluster")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Auto-approve confirmation prompts")
+	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Fail instead of prompting (requires --yes)")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging (equivalent to --log-level=debug)")
+	addPostRenderFlags(cmd, &postRender)
+	addDeployGateFlags(cmd, &gates)
+	_ = cmd.MarkFlagRequired("release")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+	decorateCommandHelp(cmd, "Deploy Operations")
+	return cmd
+}
+
+// promotionActionConfig initializes Helm for the cluster and namespace a stack release
+// resolves to, falling back to the global --kubeconfig/--context.
+func promotionActionConfig(node *stack.ResolvedRelease, kubeconfig, kubeContext string, debug bool) (*action.Configuration, *cli.EnvSettings, error) {
+	settings := cli.New()
+	if path := strings.TrimSpace(node.Cluster.Kubeconfig); path != "" {
+		expanded, err := homedir.Expand(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("expand kubeconfig path: %w", err)
+		}
+		settings.KubeConfig = expanded
+	} else if kubeconfig != "" {
+		settings.KubeConfig = kubeconfig
+	}
+	if node.Cluster.Context != "" {
+		settings.KubeContext = node.Cluster.Context
+	} else if kubeContext != "" {
+		settings.KubeContext = kubeContext
+	}
+	if node.Namespace != "" {
+		settings.SetNamespace(node.Namespace)
+	}
+	settings.Debug = debug
+
+	getter := settings.RESTClientGetter()
+	if cfgFlags, ok := getter.(*genericclioptions.ConfigFlags); ok && cfgFlags != nil && len(node.Cluster.ExecCredentialEnv) > 0 {
+		prev := cfgFlags.WrapConfigFn
+		cfgFlags.WrapConfigFn = func(cfg *rest.Config) *rest.Config {
+			if prev != nil {
+				cfg = prev(cfg)
+			}
+			if cfg != nil {
+				kube.ApplyExecEnv(cfg, node.Cluster.ExecCredentialEnv)
+			}
+			return cfg
+		}
+	}
+	actionCfg := new(action.Configuration)
+	if err := actionCfg.Init(getter, settings.Namespace(), os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+		return nil, nil, fmt.Errorf("init helm action config: %w", err)
+	}
+	return actionCfg, settings, nil
+}
+
+func releaseChartVersion(rel *release.Release) string {
+	if rel == nil || rel.Chart == nil || rel.Chart.Metadata == nil {
+		return ""
+	}
+	return rel.Chart.Metadata.Version
+}
+
+// writePromotedValues writes the promoted values to a private temp file for Helm to load.
+func writePromotedValues(values map[string]any) (string, error) {
+	raw, err := yaml.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("encode promoted values: %w", err)
+	}
+	f, err := os.CreateTemp("", "ktl-promote-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := f.Chmod(0o600); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	if _, err := f.Write(raw); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func promotionSetValues(set map[string]string) []string {
+	out := make([]string, 0, len(set))
+	for k, v := range set {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// printPromotedKeys lists the top-level values keys carried over and the environment-specific
+// keys the target keeps.
+func printPromotedKeys(w io.Writer, promoted map[string]any, kept []string) {
+	top := make([]string, 0, len(promoted))
+	for k := range promoted {
+		top = append(top, k)
+	}
+	sort.Strings(top)
+	fmt.Fprintf(w, "Promoted values: %s\n", orDash(strings.Join(top, ", ")))
+	fmt.Fprintf(w, "Kept environment-specific: %s\n", orDash(strings.Join(kept, ", ")))
+}
+
+func orDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,198 @@
+// File: cmd/ktl/rbac.go
+// Brief: CLI command wiring and implementation for 'rbac'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/rbac"
+	"github.com/kubekattle/ktl/internal/secretstore"
+	"github.com/kubekattle/ktl/internal/valuesource"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+func newRBACCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rbac",
+		Short: "Compute RBAC required to run ktl",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newRBACPlanCommand(kubeconfig, kubeContext))
+	return cmd
+}
+
+func newRBACPlanCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
+	var chart string
+	var release string
+	var version string
+	var namespace string
+	var valuesFiles []string
+	var setValues []string
+	var setStringValues []string
+	var setFileValues []string
+	var setJSONValues []string
+	var setLiteralValues []string
+	var secretProvider string
+	var secretConfig string
+	var includeCRDs bool
+	var createNamespace bool
+	var useCluster bool
+	var serviceAccount string
+	var roleName string
+	var outputPath string
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Emit the minimal Role/ClusterRole needed to apply a chart",
+		Long: `Render the chart (including hooks) and emit the least-privilege Role and ClusterRole YAML a CI
+service account needs for ktl apply/delete: manage verbs on every rendered kind, Helm release storage
+in the release namespace, and read access to pods, replica sets, and events for rollout tracking.
+
+Rendering is offline by default. Use --use-cluster to resolve custom resource kinds and their scope
+through API discovery.`,
+		Example: `  # Role/ClusterRole for a CI deployer, bound to ci/deployer
+  ktl rbac plan --chart ./charts/web --release web-prod --namespace prod -f values/prod.yaml --service-account ci/deployer
+
+  # Resolve CRD kinds against the live cluster and write the result to a file
+  ktl rbac plan --chart oci://registry.example.com/charts/operator --release operator --use-cluster --output rbac.yaml`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if strings.TrimSpace(chart) == "" {
+				return fmt.Errorf("--chart is required")
+			}
+			if strings.TrimSpace(release) == "" {
+				return fmt.Errorf("--release is required")
+			}
+			settings := cli.New()
+			if kc := derefString(kubeconfig); kc != "" {
+				settings.KubeConfig = kc
+			}
+			if kctx := derefString(kubeContext); kctx != "" {
+				settings.KubeContext = kctx
+			}
+			var kubeClient *kube.Client
+			if useCluster {
+				client, err := kube.New(ctx, derefString(kubeconfig), derefString(kubeContext))
+				if err != nil {
+					return err
+				}
+				kubeClient = client
+				if namespace == "" {
+					namespace = client.Namespace
+				}
+			}
+			if namespace == "" {
+				namespace = "default"
+			}
+			settings.SetNamespace(namespace)
+
+			actionCfg := new(action.Configuration)
+			if err := actionCfg.Init(settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+				return fmt.Errorf("init helm action config: %w", err)
+			}
+			secretResolver, secretAuditSink, err := buildDeploySecretResolver(ctx, deploySecretConfig{
+				Chart:      chart,
+				ConfigPath: secretConfig,
+				Provider:   secretProvider,
+				Mode:       secretstore.ResolveModeMask,
+				ErrOut:     cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return err
+			}
+			valueSources, err := valuesource.LoadFromApp(ctx, chart)
+			if err != nil {
+				return err
+			}
+			rendered, err := deploy.RenderTemplate(ctx, actionCfg, settings, deploy.TemplateOptions{
+				Chart:            chart,
+				Version:          version,
+				ReleaseName:      release,
+				Namespace:        namespace,
+				ValuesFiles:      valuesFiles,
+				SetValues:        setValues,
+				SetStringValues:  setStringValues,
+				SetFileValues:    setFileValues,
+				SetJSONValues:    setJSONValues,
+				SetLiteralValues: setLiteralValues,
+				Secrets:          &deploy.SecretOptions{Resolver: secretResolver, AuditSink: secretAuditSink, ValueSources: valueSources, Mask: true},
+				IncludeCRDs:      includeCRDs,
+				UseCluster:       useCluster,
+			})
+			if err != nil {
+				return err
+			}
+			objs, err := kube.DecodeManifest([]byte(rendered.Manifest + "\n" + rendered.HookManifest))
+			if err != nil {
+				return err
+			}
+			opts := rbac.Options{
+				Name:            roleName,
+				Release:         release,
+				Namespace:       namespace,
+				HelmDriver:      os.Getenv("HELM_DRIVER"),
+				CreateNamespace: createNamespace,
+				ServiceAccount:  serviceAccount,
+			}
+			if kubeClient != nil && kubeClient.RESTMapper != nil {
+				opts.Mapper = kubeClient.RESTMapper
+			}
+			result, err := rbac.Plan(objs, opts)
+			if err != nil {
+				return err
+			}
+			for _, kind := range result.UnresolvedResources {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: guessed resource and scope for %s (use --use-cluster to resolve it via discovery)\n", kind)
+			}
+			raw, err := result.YAML()
+			if err != nil {
+				return err
+			}
+			if path := strings.TrimSpace(outputPath); path != "" && path != "-" {
+				if err := os.WriteFile(path, raw, 0o644); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Wrote RBAC for release %s to %s\n", release, path)
+				return nil
+			}
+			_, err = cmd.OutOrStdout().Write(raw)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&chart, "chart", "", "Chart reference (path, repo/name, or OCI ref)")
+	cmd.Flags().StringVar(&release, "release", "", "Helm release name")
+	cmd.Flags().StringVar(&version, "version", "", "Chart version (default: latest)")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace for the Helm release (defaults to active context with --use-cluster, else default)")
+	cmd.Flags().StringSliceVarP(&valuesFiles, "values", "f", nil, "Values files to render with (can be repeated)")
+	cmd.Flags().StringArrayVar(&setValues, "set", nil, "Set values on the command line (key=val)")
+	cmd.Flags().StringArrayVar(&setStringValues, "set-string", nil, "Set STRING values on the command line")
+	cmd.Flags().StringArrayVar(&setFileValues, "set-file", nil, "Set values from files (key=path)")
+	cmd.Flags().StringArrayVar(&setJSONValues, "set-json", nil, "Set JSON values on the command line (key=<json>)")
+	cmd.Flags().StringArrayVar(&setLiteralValues, "set-literal", nil, "Set a literal STRING value on the command line (no escaping or list parsing)")
+	cmd.Flags().StringVar(&secretProvider, "secret-provider", "", "Secret provider name for secret:// references")
+	cmd.Flags().StringVar(&secretConfig, "secret-config", "", "Secrets provider config file (defaults to ~/.ktl/config.yaml and repo .ktl.yaml)")
+	cmd.Flags().BoolVar(&includeCRDs, "include-crds", true, "Include chart CRDs (crds/ directory) in the calculation")
+	cmd.Flags().BoolVar(&createNamespace, "create-namespace", false, "Grant namespace creation for ktl apply --create-namespace")
+	cmd.Flags().BoolVar(&useCluster, "use-cluster", false, "Render against the cluster and resolve kinds via API discovery")
+	cmd.Flags().StringVar(&serviceAccount, "service-account", "", "Also emit bindings for this service account (name or namespace/name)")
+	cmd.Flags().StringVar(&roleName, "name", "", "Name for the generated roles and bindings (default ktl-<release>)")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write the YAML to this file instead of stdout")
+	decorateCommandHelp(cmd, "RBAC Flags")
+	return cmd
+}
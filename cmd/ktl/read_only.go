@@ -0,0 +1,178 @@
+// File: cmd/ktl/read_only.go
+// Brief: Read-only enforcement for the secure profile.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// readOnlyProfile is the --profile value that blocks every mutating command, so auditors can run
+// ktl against production credentials without being able to change anything.
+const readOnlyProfile = "secure"
+
+// executionProfileEnv sets --profile when the flag is not passed. KTL_PROFILE is taken by the
+// startup profiler, so the execution profile has its own variable.
+const executionProfileEnv = "KTL_EXECUTION_PROFILE"
+
+// readOnlyCommands are the command paths --profile secure allows, mapped to the flags that make
+// them mutating. Every other command is blocked, so a new command stays blocked until it is
+// reviewed and listed here.
+var readOnlyCommands = map[string][]string{
+	"ktl":                      nil,
+	"ktl help":                 nil,
+	"ktl __complete":           nil,
+	"ktl __completeNoDesc":     nil,
+	"ktl completion":           nil,
+	"ktl version":              nil,
+	"ktl alias":                nil,
+	"ktl alias list":           nil,
+	"ktl analyze":              {"fix"},
+	"ktl apply plan":           nil,
+	"ktl approve keygen":       nil,
+	"ktl audit":                nil,
+	"ktl audit search":         nil,
+	"ktl audit tail":           nil,
+	"ktl build":                {"push", "remote", "remote-build"},
+	"ktl build login":          nil,
+	"ktl build logout":         nil,
+	"ktl build sandbox doctor": nil,
+	"ktl bundle":               nil,
+	"ktl bundle export":        nil,
+	"ktl capacity":             nil,
+	"ktl capture":              nil,
+	"ktl capture merge":        nil,
+	"ktl capture query":        nil,
+	"ktl certs":                nil,
+	"ktl certs status":         nil,
+	"ktl config":               nil,
+	"ktl config doctor":        nil,
+	"ktl config schema":        nil,
+	"ktl ctx":                  nil,
+	"ktl env":                  nil,
+	"ktl env diff":             nil,
+	"ktl history":              nil,
+	"ktl history diff":         nil,
+	"ktl init":                 nil,
+	"ktl lint":                 nil,
+	"ktl list":                 nil,
+	"ktl logs":                 nil,
+	"ktl logs profiles":        nil,
+	"ktl logs profiles list":   nil,
+	"ktl ns":                   nil,
+	"ktl owners":               nil,
+	"ktl rbac":                 nil,
+	"ktl rbac plan":            nil,
+	"ktl secrets":              nil,
+	"ktl secrets discover":     nil,
+	"ktl secrets get":          nil,
+	"ktl secrets list":         nil,
+	"ktl secrets seal":         nil,
+	"ktl secrets test":         nil,
+	"ktl self-update":          nil,
+	"ktl serve":                nil,
+	"ktl serve api":            nil,
+	"ktl stack":                nil,
+	"ktl stack audit":          nil,
+	"ktl stack debug":          {"rerun"},
+	"ktl stack errors":         nil,
+	"ktl stack explain":        nil,
+	"ktl stack export":         nil,
+	"ktl stack graph":          nil,
+	"ktl stack keygen":         nil,
+	"ktl stack lint":           nil,
+	"ktl stack plan":           nil,
+	"ktl stack render":         nil,
+	"ktl stack replay":         nil,
+	"ktl stack runs":           nil,
+	"ktl stack seal":           nil,
+	"ktl stack sign":           nil,
+	"ktl stack status":         nil,
+	"ktl stack verify":         nil,
+	"ktl telemetry":            nil,
+	"ktl telemetry off":        nil,
+	"ktl telemetry on":         nil,
+	"ktl telemetry status":     nil,
+	"ktl template":             nil,
+	"ktl traffic":              nil,
+	"ktl traffic tap":          nil,
+	"ktl tunnel":               nil,
+	"ktl tunnel list":          nil,
+	"ktl tunnel save":          nil,
+	"ktl wait":                 nil,
+	"ktl watch":                nil,
+}
+
+// applyExecutionProfileEnv copies KTL_EXECUTION_PROFILE into the root --profile flag so every
+// command sees the same profile.
+func applyExecutionProfileEnv(cmd *cobra.Command) error {
+	flag := cmd.Root().PersistentFlags().Lookup("profile")
+	env := strings.TrimSpace(os.Getenv(executionProfileEnv))
+	if flag == nil || flag.Changed || env == "" {
+		return nil
+	}
+	if err := flag.Value.Set(env); err != nil {
+		return fmt.Errorf("invalid %s: %w", executionProfileEnv, err)
+	}
+	return nil
+}
+
+// enforceReadOnlyProfile applies KTL_EXECUTION_PROFILE and then rejects cmd under --profile secure
+// unless it is a listed read-only command. Commands whose own PersistentPreRunE shadows the root
+// hook call it too.
+func enforceReadOnlyProfile(cmd *cobra.Command) error {
+	if cmd == nil || cmd.Root() == nil {
+		return nil
+	}
+	if err := applyExecutionProfileEnv(cmd); err != nil {
+		return err
+	}
+	if !readOnlyProfileActive(cmd) {
+		return nil
+	}
+	path := cmd.CommandPath()
+	mutatingFlags, ok := readOnlyCommands[path]
+	if !ok {
+		return readOnlyError(path)
+	}
+	for _, name := range mutatingFlags {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			return readOnlyError(path + " --" + name)
+		}
+	}
+	return nil
+}
+
+// readOnlyProfileActive reports whether --profile secure (or KTL_EXECUTION_PROFILE=secure, once
+// applied) is in effect, for commands that must refuse mutating steps of their own.
+func readOnlyProfileActive(cmd *cobra.Command) bool {
+	if flag := cmd.Root().PersistentFlags().Lookup("profile"); flag != nil && strings.TrimSpace(flag.Value.String()) == readOnlyProfile {
+		return true
+	}
+	return argvProfileBeforeStack(os.Args)
+}
+
+func readOnlyError(operation string) error {
+	return fmt.Errorf("%s is blocked: --profile %s only allows read-only commands such as ktl list, ktl history, or ktl apply plan (run without --profile %s or %s=%s to change the cluster)", operation, readOnlyProfile, readOnlyProfile, executionProfileEnv, readOnlyProfile)
+}
+
+// argvProfileBeforeStack reports whether args pass --profile secure before the stack token. ktl
+// stack declares its own --profile (the stack.yaml overlay), which shadows the root flag, so
+// `ktl --profile secure stack apply` would otherwise select an overlay instead of blocking.
+func argvProfileBeforeStack(args []string) bool {
+	for i, a := range args {
+		switch {
+		case a == "stack":
+			return false
+		case a == "--profile="+readOnlyProfile:
+			return true
+		case a == "--profile" && i+1 < len(args) && args[i+1] == readOnlyProfile:
+			return true
+		}
+	}
+	return false
+}
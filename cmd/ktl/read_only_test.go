@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// cobraGenerated are commands cobra only adds when the root executes.
+var cobraGenerated = map[string]bool{"ktl help": true, "ktl __complete": true, "ktl __completeNoDesc": true}
+
+func TestReadOnlyCommandsExcludeAuditedCommands(t *testing.T) {
+	root := newRootCommand()
+	for path := range auditedCommands {
+		if _, ok := readOnlyCommands[path]; ok {
+			t.Errorf("audited command %q is allowed by --profile secure", path)
+		}
+	}
+	for path, flags := range readOnlyCommands {
+		if cobraGenerated[path] {
+			continue
+		}
+		found, _, err := root.Find(strings.Fields(path)[1:])
+		if err != nil || found.CommandPath() != path {
+			t.Errorf("read-only command %q does not exist", path)
+			continue
+		}
+		for _, name := range flags {
+			if found.Flags().Lookup(name) == nil {
+				t.Errorf("read-only command %q has no --%s flag", path, name)
+			}
+		}
+	}
+}
+
+func TestEnforceReadOnlyProfile(t *testing.T) {
+	t.Setenv(executionProfileEnv, "")
+	root := newRootCommand()
+	root.SetArgs([]string{"--profile", "secure", "delete", "--release", "api", "--yes"})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "ktl delete is blocked: --profile secure only allows read-only commands") {
+		t.Fatalf("expected delete to be blocked, got %v", err)
+	}
+
+	for _, args := range [][]string{{"apply", "plan"}, {"list"}, {"secrets", "get"}, {"stack", "plan"}, {"stack", "debug"}} {
+		cmd, _, err := root.Find(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := enforceReadOnlyProfile(cmd); err != nil {
+			t.Errorf("%v: expected read-only commands to run, got %v", args, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		args []string
+		flag string
+		want string
+	}{
+		{args: []string{"stack", "debug"}, flag: "rerun", want: "ktl stack debug --rerun is blocked"},
+		{args: []string{"analyze"}, flag: "fix", want: "ktl analyze --fix is blocked"},
+		{args: []string{"build"}, flag: "push", want: "ktl build --push is blocked"},
+	} {
+		cmd, _, _ := root.Find(tc.args)
+		if err := cmd.Flags().Set(tc.flag, "true"); err != nil {
+			t.Fatal(err)
+		}
+		if err := enforceReadOnlyProfile(cmd); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("expected %q, got %v", tc.want, err)
+		}
+	}
+	secretsExec, _, _ := root.Find([]string{"secrets", "exec"})
+	if err := enforceReadOnlyProfile(secretsExec); err == nil || !strings.Contains(err.Error(), "ktl secrets exec is blocked") {
+		t.Fatalf("expected secrets exec to be blocked, got %v", err)
+	}
+
+	root = newRootCommand()
+	cmd, _, _ := root.Find([]string{"stack", "apply"})
+	if err := enforceReadOnlyProfile(cmd); err != nil {
+		t.Fatalf("expected the dev profile to allow stack apply, got %v", err)
+	}
+}
+
+func TestEnforceReadOnlyProfileBlocksUnlistedCommands(t *testing.T) {
+	t.Setenv(executionProfileEnv, readOnlyProfile)
+	root := newRootCommand()
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		_, listed := readOnlyCommands[cmd.CommandPath()]
+		if err := enforceReadOnlyProfile(cmd); (err == nil) != listed {
+			t.Errorf("%s: listed=%t but enforceReadOnlyProfile returned %v", cmd.CommandPath(), listed, err)
+		}
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+	}
+	walk(root)
+}
+
+func TestExecutionProfileEnv(t *testing.T) {
+	t.Setenv(executionProfileEnv, "bogus")
+	cmd, _, _ := newRootCommand().Find([]string{"list"})
+	if err := enforceReadOnlyProfile(cmd); err == nil || !strings.Contains(err.Error(), "invalid KTL_EXECUTION_PROFILE") {
+		t.Fatalf("expected an invalid profile error, got %v", err)
+	}
+
+	t.Setenv(executionProfileEnv, readOnlyProfile)
+	cmd, _, _ = newRootCommand().Find([]string{"delete"})
+	if err := cmd.Root().PersistentFlags().Set("profile", "dev"); err != nil {
+		t.Fatal(err)
+	}
+	if err := enforceReadOnlyProfile(cmd); err != nil {
+		t.Fatalf("expected --profile to override %s, got %v", executionProfileEnv, err)
+	}
+}
+
+func TestArgvProfileBeforeStack(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"ktl", "--profile", "secure", "stack", "apply"}, want: true},
+		{args: []string{"ktl", "--profile=secure", "stack", "apply"}, want: true},
+		{args: []string{"ktl", "stack", "apply", "--profile", "secure"}, want: false},
+		{args: []string{"ktl", "--profile", "ci", "stack", "apply"}, want: false},
+	} {
+		if got := argvProfileBeforeStack(tc.args); got != tc.want {
+			t.Errorf("%v: got %t, want %t", tc.args, got, tc.want)
+		}
+	}
+}
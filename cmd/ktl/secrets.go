@@ -19,12 +19,16 @@ import (
 func newSecretsCommand(kubeconfig, kubeContext *string) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "secrets",
-		Short: "Inspect and validate deploy-time secret providers",
+		Short: "Inspect, update, and rotate deploy-time secrets",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
 	}
+	cmd.AddCommand(newSecretsGetCommand())
+	cmd.AddCommand(newSecretsSetCommand())
+	cmd.AddCommand(newSecretsRotateCommand(kubeconfig, kubeContext))
+	cmd.AddCommand(newSecretsSealCommand(kubeconfig, kubeContext))
 	cmd.AddCommand(newSecretsTestCommand())
 	cmd.AddCommand(newSecretsListCommand())
 	cmd.AddCommand(newSecretsDiscoverCommand())
@@ -14,6 +14,7 @@ import (
 	"text/tabwriter"
 
 	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/netconfig"
 	"github.com/kubekattle/ktl/internal/secretstore"
 	"github.com/kubekattle/ktl/internal/stack"
 	"github.com/spf13/cobra"
@@ -509,6 +510,10 @@ func loadChart(ctx context.Context, chartRef string, version string, repoURL str
 	_ = ctx
 	settings := cli.New()
 	chartOpts := action.ChartPathOptions{RepoURL: strings.TrimSpace(repoURL), Version: strings.TrimSpace(version)}
+	if err := netconfig.CheckChartRef(chartRef, chartOpts.RepoURL); err != nil {
+		return nil, "", err
+	}
+	netconfig.ApplyChartPathOptions(&chartOpts)
 	chartPath, err := chartOpts.LocateChart(chartRef, settings)
 	if err != nil {
 		return nil, "", fmt.Errorf("locate chart %q: %w", chartRef, err)
@@ -755,6 +760,6 @@ func mergeValues(settings *cli.EnvSettings, valueFiles, setVals, setStringVals,
 		StringValues: setStringVals,
 		FileValues:   setFileVals,
 	}
-	providers := getter.All(settings)
+	providers := getter.All(settings, netconfig.GetterOptions()...)
 	return valOpts.MergeValues(providers)
 }
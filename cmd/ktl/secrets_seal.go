@@ -0,0 +1,259 @@
+// File: cmd/ktl/secrets_seal.go
+// Brief: CLI command wiring and implementation for 'secrets seal'.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/secretstore"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type secretsSealOptions struct {
+	secretProvider      string
+	secretConfig        string
+	literals            []string
+	files               []string
+	scope               string
+	sealingScope        string
+	format              string
+	secretType          string
+	cert                string
+	controllerNamespace string
+	controllerName      string
+	age                 []string
+	kms                 []string
+	output              string
+	env                 string
+}
+
+func newSecretsSealCommand(kubeconfig, kubeContext *string) *cobra.Command {
+	var opts secretsSealOptions
+
+	cmd := &cobra.Command{
+		Use:   "seal",
+		Short: "Generate an encrypted Secret manifest that is safe to commit",
+		Long: `Build a Secret from literals and files and encrypt it for Git:
+
+  sealedsecret  a SealedSecret encrypted with the sealed-secrets controller's public key
+                (fetched through the API server, or --cert for offline use)
+  sops          a Secret whose data is encrypted by the sops binary for age or KMS recipients
+
+Literal values may be secret:// references; they are resolved through the configured providers
+before sealing. Defaults come from secrets.seal in .ktl.yaml. With --env the manifest is written
+to secrets/<env>/<name>.yaml next to the values/ directory created by ktl init.`,
+		Example: `  # Seal for the cluster's sealed-secrets controller
+  ktl secrets seal --scope prod/db --from-literal password=s3cr3t > db.sealed.yaml
+
+  # Copy a Vault secret into a SOPS-encrypted manifest under secrets/prod/
+  ktl secrets seal --scope prod/db --format sops --age age1... \
+    --from-literal password=secret://vault/app/db#password --env prod`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runSecretsSeal(cmd, kubeconfig, kubeContext, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.secretProvider, "secret-provider", "", "Default secret provider for secret:///path references in literals")
+	cmd.Flags().StringVar(&opts.secretConfig, "secret-config", "", "Secrets provider config file (defaults to ~/.ktl/config.yaml and repo .ktl.yaml)")
+	cmd.Flags().StringArrayVar(&opts.literals, "from-literal", nil, "Secret key and value (key=value or key=secret://...; can be repeated)")
+	cmd.Flags().StringArrayVar(&opts.files, "from-file", nil, "Secret key and file contents (key=path; can be repeated)")
+	cmd.Flags().StringVar(&opts.scope, "scope", "", "Namespace and name of the Secret (namespace/name)")
+	cmd.Flags().StringVar(&opts.sealingScope, "sealing-scope", "", "sealedsecret scope: strict, namespace-wide, or cluster-wide (default strict)")
+	cmd.Flags().StringVar(&opts.format, "format", "", "Output format: sealedsecret or sops (default from secrets.seal.format, else sealedsecret)")
+	cmd.Flags().StringVar(&opts.secretType, "type", "Opaque", "Secret type")
+	cmd.Flags().StringVar(&opts.cert, "cert", "", "sealed-secrets certificate (PEM) to seal with instead of fetching it from the cluster")
+	cmd.Flags().StringVar(&opts.controllerNamespace, "controller-namespace", "", "Namespace of the sealed-secrets controller (default kube-system)")
+	cmd.Flags().StringVar(&opts.controllerName, "controller-name", "", "Service name of the sealed-secrets controller (default sealed-secrets-controller)")
+	cmd.Flags().StringArrayVar(&opts.age, "age", nil, "sops age recipient (can be repeated)")
+	cmd.Flags().StringArrayVar(&opts.kms, "kms", nil, "sops AWS KMS key ARN (can be repeated)")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "Write the manifest to this file instead of stdout")
+	cmd.Flags().StringVar(&opts.env, "env", "", "Write the manifest to secrets/<env>/<name>.yaml in the project layout")
+	decorateCommandHelp(cmd, "Seal Flags")
+	return cmd
+}
+
+func runSecretsSeal(cmd *cobra.Command, kubeconfig, kubeContext *string, opts secretsSealOptions) error {
+	ctx := cmd.Context()
+	namespace, name, ok := strings.Cut(strings.TrimSpace(opts.scope), "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("--scope must be namespace/name")
+	}
+	if opts.output != "" && opts.env != "" {
+		return fmt.Errorf("--output and --env are mutually exclusive")
+	}
+	if opts.env != "" && (strings.ContainsAny(opts.env, `/\`) || opts.env == "." || opts.env == "..") {
+		return fmt.Errorf("--env %q must be a plain environment name", opts.env)
+	}
+	secretsCfg, baseDir, err := secretstore.LoadConfigFromApp(ctx, ".", opts.secretConfig)
+	if err != nil {
+		return err
+	}
+	seal := secretsCfg.Seal
+	format := strings.ToLower(strings.TrimSpace(firstNonEmpty(opts.format, seal.Format, secretstore.SealFormatSealedSecret)))
+	scope, err := secretstore.ParseSealScope(opts.sealingScope)
+	if err != nil {
+		return err
+	}
+	data, err := sealData(ctx, cmd, secretsCfg, baseDir, opts)
+	if err != nil {
+		return err
+	}
+	req := secretstore.SealRequest{Namespace: namespace, Name: name, Type: opts.secretType, Scope: scope, Data: data}
+
+	var manifest []byte
+	switch format {
+	case secretstore.SealFormatSealedSecret:
+		certPEM, err := sealingCert(ctx, kubeconfig, kubeContext, opts, seal, baseDir)
+		if err != nil {
+			return err
+		}
+		key, err := secretstore.ParseSealingCert(certPEM)
+		if err != nil {
+			return err
+		}
+		if manifest, err = secretstore.SealSecret(key, req); err != nil {
+			return err
+		}
+	case secretstore.SealFormatSOPS:
+		age, kms := opts.age, opts.kms
+		if len(age) == 0 && len(kms) == 0 {
+			age, kms = seal.Age, seal.KMS
+		}
+		if manifest, err = secretstore.SOPSEncrypt(ctx, req, secretstore.SOPSOptions{Age: age, KMS: kms}); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported --format %q (want sealedsecret or sops)", format)
+	}
+
+	out := opts.output
+	if opts.env != "" {
+		out = sealLayoutPath(opts.env, name)
+	}
+	if out == "" || out == "-" {
+		_, err := cmd.OutOrStdout().Write(manifest)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, manifest, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s Secret %s/%s (%d key(s)) to %s\n", format, namespace, name, len(data), out)
+	return nil
+}
+
+// sealData collects --from-literal and --from-file values, resolving secret:// references.
+func sealData(ctx context.Context, cmd *cobra.Command, cfg secretstore.Config, baseDir string, opts secretsSealOptions) (map[string]string, error) {
+	data := map[string]string{}
+	var resolver *secretstore.Resolver
+	for _, raw := range opts.literals {
+		key, value, ok := strings.Cut(raw, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("--from-literal %q must be key=value", raw)
+		}
+		if strings.HasPrefix(value, "secret://") {
+			if resolver == nil {
+				var err error
+				resolver, err = secretstore.NewResolver(cfg, secretstore.ResolverOptions{
+					DefaultProvider: opts.secretProvider,
+					Mode:            secretstore.ResolveModeValue,
+					BaseDir:         baseDir,
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+			resolved, _, err := resolver.ResolveString(ctx, value)
+			if err != nil {
+				return nil, err
+			}
+			value = resolved
+		}
+		data[strings.TrimSpace(key)] = value
+	}
+	for _, raw := range opts.files {
+		key, path, ok := strings.Cut(raw, "=")
+		if !ok || strings.TrimSpace(key) == "" || strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("--from-file %q must be key=path", raw)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		data[strings.TrimSpace(key)] = string(content)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("--from-literal or --from-file is required")
+	}
+	if resolver != nil {
+		newSecretAuditLogger(cmd.ErrOrStderr(), secretstore.ResolveModeValue)(resolver.Audit())
+	}
+	return data, nil
+}
+
+// sealingCert reads --cert (or secrets.seal.cert) or fetches the controller's certificate through
+// the API server's service proxy, as kubeseal does.
+func sealingCert(ctx context.Context, kubeconfig, kubeContext *string, opts secretsSealOptions, seal secretstore.SealConfig, baseDir string) ([]byte, error) {
+	if path := firstNonEmpty(opts.cert, seal.Cert); path != "" {
+		if opts.cert == "" && baseDir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		return os.ReadFile(path)
+	}
+	namespace := firstNonEmpty(opts.controllerNamespace, seal.ControllerNamespace, secretstore.DefaultSealControllerNamespace)
+	name := firstNonEmpty(opts.controllerName, seal.ControllerName, secretstore.DefaultSealControllerName)
+	kClient, err := kube.New(ctx, derefString(kubeconfig), derefString(kubeContext))
+	if err != nil {
+		return nil, err
+	}
+	services := kClient.Clientset.CoreV1().Services(namespace)
+	svc, err := services.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("find sealed-secrets controller %s/%s (use --cert to seal offline): %w", namespace, name, err)
+	}
+	port := ""
+	if len(svc.Spec.Ports) > 0 {
+		port = svc.Spec.Ports[0].Name
+	}
+	raw, err := services.ProxyGet("http", name, port, "/v1/cert.pem", nil).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch sealing certificate from %s/%s: %w", namespace, name, err)
+	}
+	return raw, nil
+}
+
+// sealLayoutPath places sealed manifests in secrets/<env>/ beside the values/ directory of the
+// project layout (the repo root when there is none).
+func sealLayoutPath(env, name string) string {
+	root := "."
+	if cwd, err := os.Getwd(); err == nil {
+		if repoRoot := appconfig.FindRepoRoot(cwd); repoRoot != "" {
+			root = repoRoot
+			if layout := detectProjectLayout(repoRoot); layout.ValuesDir != "" {
+				root = filepath.Dir(filepath.Join(repoRoot, layout.ValuesDir))
+			}
+		}
+	}
+	return filepath.Join(root, "secrets", env, name+".yaml")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeSealingCert(t *testing.T) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sealed-secret"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cert.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSecretsSealWithCert(t *testing.T) {
+	configPath := writeSecretsTestConfig(t)
+	certPath := writeSealingCert(t)
+
+	root := newRootCommand()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"secrets", "seal", "--scope", "prod/api", "--cert", certPath, "--secret-config", configPath,
+		"--from-literal", "user=admin", "--from-literal", "token=secret://file/api/token"})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"kind: SealedSecret", "namespace: prod", "name: api", "token:", "user:"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("manifest missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "s3cr3t") || strings.Contains(got, "admin") {
+		t.Fatalf("manifest leaks plaintext:\n%s", got)
+	}
+
+	bad := newRootCommand()
+	bad.SetOut(&bytes.Buffer{})
+	bad.SetErr(&bytes.Buffer{})
+	bad.SetArgs([]string{"secrets", "seal", "--scope", "api", "--cert", certPath, "--from-literal", "a=b"})
+	if err := bad.ExecuteContext(context.Background()); err == nil || !strings.Contains(err.Error(), "namespace/name") {
+		t.Fatalf("expected scope error, got %v", err)
+	}
+}
@@ -0,0 +1,323 @@
+// File: cmd/ktl/secrets_write.go
+// Brief: CLI command wiring and implementation for 'secrets get/set/rotate'.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/secretstore"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// secretValueFlags select where set and rotate take the new secret value from.
+type secretValueFlags struct {
+	literal  string
+	fromFile string
+	generate int
+}
+
+func addSecretValueFlags(cmd *cobra.Command, f *secretValueFlags) {
+	cmd.Flags().StringVar(&f.literal, "literal", "", "New secret value (prefer --from-file to keep it out of shell history)")
+	cmd.Flags().StringVar(&f.fromFile, "from-file", "", "Read the new secret value from a file (- reads stdin; one trailing newline is dropped)")
+	cmd.Flags().IntVar(&f.generate, "generate", 0, "Generate a random URL-safe value of this length")
+}
+
+// value returns the new secret value. fallback is the generated length used when no source flag
+// was set; zero makes a source flag mandatory.
+func (f secretValueFlags) value(cmd *cobra.Command, fallback int) (string, error) {
+	set := 0
+	for _, name := range []string{"literal", "from-file", "generate"} {
+		if cmd.Flags().Changed(name) {
+			set++
+		}
+	}
+	if set > 1 {
+		return "", fmt.Errorf("--literal, --from-file, and --generate are mutually exclusive")
+	}
+	switch {
+	case cmd.Flags().Changed("literal"):
+		if f.literal == "" {
+			return "", fmt.Errorf("--literal must not be empty")
+		}
+		return f.literal, nil
+	case cmd.Flags().Changed("from-file"):
+		var raw []byte
+		var err error
+		if f.fromFile == "-" {
+			raw, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			raw, err = os.ReadFile(f.fromFile)
+		}
+		if err != nil {
+			return "", fmt.Errorf("read secret value: %w", err)
+		}
+		value := strings.TrimSuffix(strings.TrimSuffix(string(raw), "\n"), "\r")
+		if value == "" {
+			return "", fmt.Errorf("secret value from %s is empty", f.fromFile)
+		}
+		return value, nil
+	case cmd.Flags().Changed("generate"):
+		return generateSecretValue(f.generate)
+	case fallback > 0:
+		return generateSecretValue(fallback)
+	default:
+		return "", fmt.Errorf("one of --literal, --from-file, or --generate is required")
+	}
+}
+
+func generateSecretValue(length int) (string, error) {
+	if length < 8 || length > 4096 {
+		return "", fmt.Errorf("--generate must be between 8 and 4096")
+	}
+	buf := make([]byte, length)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)[:length], nil
+}
+
+// normalizeSecretRef accepts provider/path#key shorthand alongside secret:// references.
+func normalizeSecretRef(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.HasPrefix(raw, "secret://") {
+		return raw
+	}
+	return "secret://" + raw
+}
+
+func newSecretsGetCommand() *cobra.Command {
+	var secretProvider string
+	var secretConfig string
+	var noNewline bool
+
+	cmd := &cobra.Command{
+		Use:   "get REF",
+		Short: "Print the value of a secret reference",
+		Long: `Resolve a secret reference against the configured providers and print its value.
+REF is provider/path[#key] or a full secret:// reference; secret:///path uses --secret-provider.`,
+		Example: `  ktl secrets get vault/app/db#password
+  ktl secrets get local/db/password --no-newline | pbcopy`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			resolver, _, err := buildDeploySecretResolver(ctx, deploySecretConfig{
+				Chart:      ".",
+				ConfigPath: secretConfig,
+				Provider:   secretProvider,
+				Mode:       secretstore.ResolveModeValue,
+				ErrOut:     cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return err
+			}
+			value, _, err := resolver.ResolveString(ctx, normalizeSecretRef(args[0]))
+			if err != nil {
+				return err
+			}
+			if noNewline {
+				_, err = fmt.Fprint(cmd.OutOrStdout(), value)
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), value)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&secretProvider, "secret-provider", "", "Default secret provider for secret:///path references")
+	cmd.Flags().StringVar(&secretConfig, "secret-config", "", "Secrets provider config file (defaults to ~/.ktl/config.yaml and repo .ktl.yaml)")
+	cmd.Flags().BoolVar(&noNewline, "no-newline", false, "Do not print a trailing newline")
+	decorateCommandHelp(cmd, "Get Flags")
+	return cmd
+}
+
+func newSecretsSetCommand() *cobra.Command {
+	var secretProvider string
+	var secretConfig string
+	var value secretValueFlags
+
+	cmd := &cobra.Command{
+		Use:   "set REF",
+		Short: "Write a new value to a secret reference",
+		Long: `Write a value to a secret through its provider (file and vault providers support writes).
+Vault writes keep the other keys of the secret. Each write is recorded in the ktl audit log
+without the value.`,
+		Example: `  # Read the value from stdin
+  printf '%s' "$DB_PASSWORD" | ktl secrets set vault/app/db#password --from-file -
+
+  # Generate a random 40 character value
+  ktl secrets set local/api/token --generate 40`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			secret, err := value.value(cmd, 0)
+			if err != nil {
+				return err
+			}
+			resolver, _, err := buildDeploySecretResolver(ctx, deploySecretConfig{
+				Chart:      ".",
+				ConfigPath: secretConfig,
+				Provider:   secretProvider,
+				Mode:       secretstore.ResolveModeValue,
+				ErrOut:     cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return err
+			}
+			ref, err := resolver.WriteString(ctx, normalizeSecretRef(args[0]), secret)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Updated %s (len=%d)\n", ref.Reference(), len(secret))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&secretProvider, "secret-provider", "", "Default secret provider for secret:///path references")
+	cmd.Flags().StringVar(&secretConfig, "secret-config", "", "Secrets provider config file (defaults to ~/.ktl/config.yaml and repo .ktl.yaml)")
+	addSecretValueFlags(cmd, &value)
+	decorateCommandHelp(cmd, "Set Flags")
+	return cmd
+}
+
+func newSecretsRotateCommand(kubeconfig, kubeContext *string) *cobra.Command {
+	var secretProvider string
+	var secretConfig string
+	var refs []string
+	var releases []string
+	var namespace string
+	var value secretValueFlags
+
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Replace secrets and restart the workloads of the releases that use them",
+		Long: `Write a new value to each --ref (a fresh random value per reference unless --literal or
+--from-file is set), then roll the Deployments, StatefulSets, and DaemonSets of every
+--restart-release (matched by app.kubernetes.io/instance) so pods pick up the new secret.
+The rotation is recorded in the ktl audit log without the values.`,
+		Example: `  ktl secrets rotate --ref vault/app/db#password --restart-release payments -n prod
+  ktl secrets rotate --ref local/api/token --generate 64`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if len(refs) == 0 {
+				return fmt.Errorf("--ref is required")
+			}
+			resolver, _, err := buildDeploySecretResolver(ctx, deploySecretConfig{
+				Chart:      ".",
+				ConfigPath: secretConfig,
+				Provider:   secretProvider,
+				Mode:       secretstore.ResolveModeValue,
+				ErrOut:     cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return err
+			}
+			var kClient *kube.Client
+			if len(releases) > 0 {
+				// Connect before writing so a bad kube context cannot leave a rotated secret
+				// with stale pods behind.
+				kClient, err = kube.New(ctx, derefString(kubeconfig), derefString(kubeContext))
+				if err != nil {
+					return err
+				}
+				if namespace == "" {
+					namespace = kClient.Namespace
+				}
+				if namespace == "" {
+					namespace = "default"
+				}
+			}
+			for _, raw := range refs {
+				secret, err := value.value(cmd, 32)
+				if err != nil {
+					return err
+				}
+				ref, err := resolver.WriteString(ctx, normalizeSecretRef(raw), secret)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Rotated %s (len=%d)\n", ref.Reference(), len(secret))
+			}
+			for _, release := range releases {
+				restarted, err := restartReleaseWorkloads(ctx, kClient, namespace, release)
+				if err != nil {
+					return fmt.Errorf("restart release %s: %w", release, err)
+				}
+				if len(restarted) == 0 {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: no workloads found for release %s in namespace %s\n", release, namespace)
+					continue
+				}
+				for _, name := range restarted {
+					fmt.Fprintf(cmd.OutOrStdout(), "Restarted %s (release %s)\n", name, release)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&secretProvider, "secret-provider", "", "Default secret provider for secret:///path references")
+	cmd.Flags().StringVar(&secretConfig, "secret-config", "", "Secrets provider config file (defaults to ~/.ktl/config.yaml and repo .ktl.yaml)")
+	cmd.Flags().StringArrayVar(&refs, "ref", nil, "Secret reference to rotate (provider/path#key or secret://...; can be repeated)")
+	cmd.Flags().StringArrayVar(&releases, "restart-release", nil, "Helm release whose workloads are restarted after the rotation (can be repeated)")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the restarted releases")
+	addSecretValueFlags(cmd, &value)
+	decorateCommandHelp(cmd, "Rotate Flags")
+	return cmd
+}
+
+// restartReleaseWorkloads bumps the restartedAt pod-template annotation (what kubectl rollout
+// restart does) on every workload of release and returns the restarted Kind/name list.
+func restartReleaseWorkloads(ctx context.Context, kClient *kube.Client, namespace, release string) ([]string, error) {
+	opts := metav1.ListOptions{LabelSelector: "app.kubernetes.io/instance=" + release}
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation, time.Now().UTC().Format(time.RFC3339)))
+	apps := kClient.Clientset.AppsV1()
+	var restarted []string
+
+	deployments, err := apps.Deployments(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range deployments.Items {
+		if _, err := apps.Deployments(namespace).Patch(ctx, item.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return restarted, err
+		}
+		restarted = append(restarted, "Deployment/"+item.Name)
+	}
+	statefulSets, err := apps.StatefulSets(namespace).List(ctx, opts)
+	if err != nil {
+		return restarted, err
+	}
+	for _, item := range statefulSets.Items {
+		if _, err := apps.StatefulSets(namespace).Patch(ctx, item.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return restarted, err
+		}
+		restarted = append(restarted, "StatefulSet/"+item.Name)
+	}
+	daemonSets, err := apps.DaemonSets(namespace).List(ctx, opts)
+	if err != nil {
+		return restarted, err
+	}
+	for _, item := range daemonSets.Items {
+		if _, err := apps.DaemonSets(namespace).Patch(ctx, item.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return restarted, err
+		}
+		restarted = append(restarted, "DaemonSet/"+item.Name)
+	}
+	return restarted, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRestartReleaseWorkloads(t *testing.T) {
+	labels := map[string]string{"app.kubernetes.io/instance": "payments"}
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "prod", Labels: labels}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "prod", Labels: labels}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "prod"}},
+	)
+	ctx := context.Background()
+	restarted, err := restartReleaseWorkloads(ctx, &kube.Client{Clientset: client}, "prod", "payments")
+	if err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+	if strings.Join(restarted, ",") != "Deployment/api,StatefulSet/db" {
+		t.Fatalf("restarted=%v", restarted)
+	}
+	api, err := client.AppsV1().Deployments("prod").Get(ctx, "api", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.Spec.Template.Annotations[restartedAtAnnotation] == "" {
+		t.Fatalf("expected restartedAt annotation, got %v", api.Spec.Template.Annotations)
+	}
+	other, _ := client.AppsV1().Deployments("prod").Get(ctx, "other", metav1.GetOptions{})
+	if len(other.Spec.Template.Annotations) != 0 {
+		t.Fatalf("unrelated deployment was restarted: %v", other.Spec.Template.Annotations)
+	}
+}
+
+func TestSecretValueFlags(t *testing.T) {
+	cmd := newSecretsSetCommand()
+	cmd.SetIn(strings.NewReader("s3cr3t\n"))
+	if err := cmd.ParseFlags([]string{"--from-file", "-"}); err != nil {
+		t.Fatal(err)
+	}
+	var f secretValueFlags
+	f.fromFile = "-"
+	got, err := f.value(cmd, 0)
+	if err != nil || got != "s3cr3t" {
+		t.Fatalf("value=%q err=%v", got, err)
+	}
+
+	rotate := newSecretsRotateCommand(nil, nil)
+	generated, err := secretValueFlags{}.value(rotate, 32)
+	if err != nil || len(generated) != 32 {
+		t.Fatalf("generated=%q err=%v", generated, err)
+	}
+	if _, err := (secretValueFlags{}).value(newSecretsSetCommand(), 0); err == nil {
+		t.Fatalf("expected set without a value source to fail")
+	}
+	both := newSecretsSetCommand()
+	if err := both.ParseFlags([]string{"--literal", "x", "--generate", "16"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (secretValueFlags{literal: "x", generate: 16}).value(both, 0); err == nil {
+		t.Fatalf("expected conflicting value flags to fail")
+	}
+}
+
+func TestSecretsSetThenGet(t *testing.T) {
+	configPath := writeSecretsTestConfig(t)
+
+	set := newRootCommand()
+	var setOut bytes.Buffer
+	set.SetOut(&setOut)
+	set.SetErr(&bytes.Buffer{})
+	set.SetArgs([]string{"secrets", "set", "file/api/token", "--secret-config", configPath, "--literal", "rotated"})
+	if err := set.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if !strings.Contains(setOut.String(), "Updated secret://file/api/token (len=7)") {
+		t.Fatalf("set output=%q", setOut.String())
+	}
+
+	get := newRootCommand()
+	var getOut bytes.Buffer
+	get.SetOut(&getOut)
+	get.SetErr(&bytes.Buffer{})
+	get.SetArgs([]string{"secrets", "get", "secret:///api/token", "--secret-config", configPath})
+	if err := get.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if getOut.String() != "rotated\n" {
+		t.Fatalf("get output=%q", getOut.String())
+	}
+}
@@ -0,0 +1,207 @@
+// File: cmd/ktl/self_update.go
+// Brief: CLI command wiring and implementation for 'self update'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/kubekattle/ktl/internal/selfupdate"
+	"github.com/kubekattle/ktl/internal/version"
+	"github.com/spf13/cobra"
+)
+
+type selfUpdateCheck struct {
+	Current   string   `json:"current"`
+	Latest    string   `json:"latest"`
+	Available bool     `json:"updateAvailable"`
+	Newer     []string `json:"newer,omitempty"`
+	Channel   string   `json:"channel"`
+}
+
+func newSelfUpdateCommand() *cobra.Command {
+	var (
+		check      bool
+		channelURL string
+		target     string
+		prerelease bool
+		signature  = string(selfupdate.SignatureRequire)
+		identity   string
+		output     = "text"
+		force      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update the ktl binary to the latest release",
+		Long: `Checks the release channel (GitHub releases by default, or an internal mirror that serves
+the same JSON via --channel-url / KTL_UPDATE_CHANNEL), verifies the platform archive against the
+release checksums and the checksums' sigstore signature (cosign must be on PATH), then swaps the
+running binary atomically and prints the release notes between the two versions. Unsigned
+releases are refused unless --verify-signature=skip is passed.
+
+KTL_UPDATE_TOKEN (or GITHUB_TOKEN) is sent only to the channel's host, never to asset downloads.
+
+--check only reports; it exits non-zero when a newer release exists so CI images can fail fast.`,
+		Example: `  # Update to the latest stable release
+  ktl self-update
+
+  # CI: fail when the image ships an outdated ktl
+  ktl self-update --check
+
+  # Pin a version from an internal mirror that does not sign its releases
+  ktl self-update --to v0.9.0 --channel-url https://artifacts.example.com/ktl/releases.json --verify-signature skip`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			out := cmd.OutOrStdout()
+			errOut := cmd.ErrOrStderr()
+			if strings.TrimSpace(channelURL) == "" {
+				channelURL = os.Getenv("KTL_UPDATE_CHANNEL")
+			}
+			token := os.Getenv("KTL_UPDATE_TOKEN")
+			if token == "" {
+				token = os.Getenv("GITHUB_TOKEN")
+			}
+			client := selfupdate.NewClient(channelURL, token)
+			releases, err := client.Releases(ctx)
+			if err != nil {
+				return err
+			}
+			current := version.Get().Version
+			plan, err := selfupdate.ChooseUpdate(current, releases, target, prerelease)
+			if err != nil {
+				return err
+			}
+
+			if check {
+				report := selfUpdateCheck{
+					Current:   current,
+					Latest:    plan.Target.Tag,
+					Available: plan.Available,
+					Channel:   client.ChannelURL,
+				}
+				for _, rel := range plan.Newer {
+					report.Newer = append(report.Newer, rel.Tag)
+				}
+				if output == "json" {
+					enc := json.NewEncoder(out)
+					enc.SetIndent("", "  ")
+					if err := enc.Encode(report); err != nil {
+						return err
+					}
+				} else if plan.Available {
+					fmt.Fprintf(out, "ktl %s is available (current %s)\n", plan.Target.Tag, current)
+					writeSelfUpdateChangelog(out, plan.Newer)
+				} else {
+					fmt.Fprintf(out, "ktl %s is up to date\n", current)
+				}
+				if plan.Available {
+					return fmt.Errorf("update available: %s -> %s (run ktl self-update)", current, plan.Target.Tag)
+				}
+				return nil
+			}
+
+			if !plan.Available {
+				fmt.Fprintf(out, "ktl %s is up to date\n", current)
+				return nil
+			}
+			if _, err := semver.NewVersion(current); err != nil && !force {
+				return fmt.Errorf("this ktl is a development build (%s); pass --force to replace it with %s", current, plan.Target.Tag)
+			}
+			exe, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("locate running binary: %w", err)
+			}
+			if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+				exe = resolved
+			}
+			fmt.Fprintf(errOut, "Downloading ktl %s for %s/%s from %s\n", plan.Target.Tag, runtime.GOOS, runtime.GOARCH, client.ChannelURL)
+			art, err := client.Download(ctx, plan.Target, selfupdate.DownloadOptions{
+				Tool:           "ktl",
+				GOOS:           runtime.GOOS,
+				GOARCH:         runtime.GOARCH,
+				Signature:      selfupdate.SignatureMode(signature),
+				IdentityRegexp: identity,
+			})
+			if err != nil {
+				return err
+			}
+			writeSelfUpdateWarnings(errOut, art)
+			if err := selfupdate.ReplaceExecutable(exe, art.Binary, runtime.GOOS); err != nil {
+				if errors.Is(err, os.ErrPermission) {
+					return fmt.Errorf("replace %s: %w (re-run with permission to write %s)", exe, err, filepath.Dir(exe))
+				}
+				return fmt.Errorf("replace %s: %w", exe, err)
+			}
+			verified := "checksum verified, signature NOT verified"
+			if art.Signed {
+				verified = "checksum and signature verified"
+			}
+			fmt.Fprintf(out, "Updated %s: %s -> %s (%s, sha256 %s)\n", exe, current, plan.Target.Tag, verified, art.SHA256)
+			writeSelfUpdateChangelog(out, plan.Newer)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&check, "check", false, "Only report whether a newer release exists (exits 1 when one does)")
+	cmd.Flags().StringVar(&channelURL, "channel-url", "", "Release channel URL returning GitHub-releases JSON (default GitHub; also via KTL_UPDATE_CHANNEL)")
+	cmd.Flags().StringVar(&target, "to", "", "Install this release tag instead of the latest (allows downgrades)")
+	cmd.Flags().BoolVar(&prerelease, "prerelease", false, "Consider pre-releases when picking the latest version")
+	cmd.Flags().Var(newEnumStringValue(&signature, string(selfupdate.SignatureRequire), string(selfupdate.SignatureSkip)), "verify-signature", "Sigstore verification of the release checksums: require (needs cosign on PATH) or skip to rely on checksums only")
+	cmd.Flags().StringVar(&identity, "signature-identity", "", "Expected signing identity regexp (default: the ktl release workflow for the tag)")
+	cmd.Flags().Var(newEnumStringValue(&output, "text", "json"), "output", "Output format for --check: text or json")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace development builds too")
+	decorateCommandHelp(cmd, "Update Flags")
+	return cmd
+}
+
+// writeSelfUpdateWarnings prints the download warnings. An unsigned update gets a prominent
+// block: the checksums come from the same place as the binary, so they only catch corruption.
+func writeSelfUpdateWarnings(w io.Writer, art *selfupdate.Artifact) {
+	if art.Signed {
+		for _, msg := range art.Warnings {
+			fmt.Fprintf(w, "WARNING: %s\n", msg)
+		}
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "WARNING: the release signature was NOT verified.")
+	for _, msg := range art.Warnings {
+		fmt.Fprintf(w, "WARNING:   %s\n", msg)
+	}
+	fmt.Fprintln(w, "WARNING: The checksums were downloaded from the same release as the binary, so they detect")
+	fmt.Fprintln(w, "WARNING: corrupted downloads but not a tampered release. Drop --verify-signature=skip to")
+	fmt.Fprintln(w, "WARNING: refuse unsigned updates.")
+	fmt.Fprintln(w)
+}
+
+func writeSelfUpdateChangelog(w io.Writer, releases []selfupdate.Release) {
+	if len(releases) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\nChanges:")
+	for _, rel := range releases {
+		title := rel.Tag
+		if date := strings.TrimSpace(rel.Published); len(date) >= 10 {
+			title += " (" + date[:10] + ")"
+		}
+		fmt.Fprintf(w, "\n## %s\n", title)
+		notes := strings.TrimSpace(rel.Notes)
+		if notes == "" {
+			fmt.Fprintln(w, "(no release notes)")
+			continue
+		}
+		fmt.Fprintln(w, notes)
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/selfupdate"
+)
+
+func TestWriteSelfUpdateWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	writeSelfUpdateWarnings(&buf, &selfupdate.Artifact{Warnings: []string{"signature verification skipped (--verify-signature=skip)"}})
+	out := buf.String()
+	for _, want := range []string{"WARNING: the release signature was NOT verified.", "signature verification skipped", "Drop --verify-signature=skip"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	writeSelfUpdateWarnings(&buf, &selfupdate.Artifact{Signed: true})
+	if buf.Len() != 0 {
+		t.Fatalf("expected no warnings for a signed update, got %q", buf.String())
+	}
+}
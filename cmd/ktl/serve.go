@@ -0,0 +1,150 @@
+// File: cmd/ktl/serve.go
+// Brief: CLI command wiring and implementation for 'serve'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/portalapi"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+func newServeCommand(kubeconfig *string, kubeContext *string, logLevel *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run long-lived ktl servers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newServeAPICommand(kubeconfig, kubeContext, logLevel))
+	return cmd
+}
+
+func newServeAPICommand(kubeconfig *string, kubeContext *string, logLevel *string) *cobra.Command {
+	var listen string
+	var token string
+	var tokenFile string
+	var plansDir string
+	var stackRoots []string
+	var noReleases bool
+
+	cmd := &cobra.Command{
+		Use:   "api",
+		Short: "Serve read-only JSON endpoints for developer portals",
+		Long: `Serve read-only JSON endpoints (release inventory, newest saved plan per release, and stack run history)
+for an internal developer portal such as a Backstage plugin.
+
+Every /api request must send "Authorization: Bearer <token>".
+
+Endpoints:
+  GET /healthz
+  GET /api/v1/releases[?namespace=NS]
+  GET /api/v1/plans
+  GET /api/v1/plans/{namespace}/{release}
+  GET /api/v1/stacks/runs[?limit=N]`,
+		Example: `  # Serve the portal API with a token from the environment
+  KTL_API_TOKEN=s3cr3t ktl serve api --listen :8085 --plans-dir ./plans --stack-root ./stacks/prod`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			resolvedToken := strings.TrimSpace(token)
+			if resolvedToken == "" && strings.TrimSpace(tokenFile) != "" {
+				data, err := os.ReadFile(tokenFile)
+				if err != nil {
+					return fmt.Errorf("read --token-file: %w", err)
+				}
+				resolvedToken = strings.TrimSpace(string(data))
+			}
+			if resolvedToken == "" {
+				resolvedToken = strings.TrimSpace(os.Getenv("KTL_API_TOKEN"))
+			}
+			if resolvedToken == "" {
+				return fmt.Errorf("an API token is required (--token, --token-file, or KTL_API_TOKEN)")
+			}
+			logger, err := buildLogger(derefString(logLevel))
+			if err != nil {
+				return err
+			}
+			opts := portalapi.Options{
+				Addr:       listen,
+				Token:      resolvedToken,
+				PlansDir:   plansDir,
+				StackRoots: stackRoots,
+				Logger:     logger,
+			}
+			if !noReleases {
+				opts.Releases = helmReleaseLister(derefString(kubeconfig), derefString(kubeContext))
+			}
+			server, err := portalapi.New(opts)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Serving ktl portal API on %s\n", listen)
+			return server.Run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", ":8085", "Address to listen on")
+	cmd.Flags().StringVar(&token, "token", "", "Bearer token clients must present (also via KTL_API_TOKEN)")
+	cmd.Flags().StringVar(&tokenFile, "token-file", "", "Read the bearer token from this file")
+	cmd.Flags().StringVar(&plansDir, "plans-dir", "", "Directory of saved plan documents (ktl apply plan --format json|html --output ...)")
+	cmd.Flags().StringArrayVar(&stackRoots, "stack-root", nil, "Stack root whose run history is served (repeatable)")
+	cmd.Flags().BoolVar(&noReleases, "no-releases", false, "Disable the release inventory endpoint (no cluster access)")
+	decorateCommandHelp(cmd, "Serve API Flags")
+	return cmd
+}
+
+// helmReleaseLister lists every Helm release (all namespaces, all states) on each call.
+func helmReleaseLister(kubeconfig, kubeContext string) portalapi.ReleaseLister {
+	return func(ctx context.Context) ([]portalapi.Release, error) {
+		settings := cli.New()
+		if strings.TrimSpace(kubeconfig) != "" {
+			settings.KubeConfig = kubeconfig
+		}
+		if strings.TrimSpace(kubeContext) != "" {
+			settings.KubeContext = kubeContext
+		}
+		actionCfg := new(action.Configuration)
+		if err := actionCfg.Init(settings.RESTClientGetter(), "", os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+			return nil, fmt.Errorf("init helm action config: %w", err)
+		}
+		client := action.NewList(actionCfg)
+		client.All = true
+		client.AllNamespaces = true
+		client.SetStateMask()
+		releases, err := runWithCancel(ctx, client.Run)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]portalapi.Release, 0, len(releases))
+		for _, rel := range releases {
+			if rel == nil {
+				continue
+			}
+			item := portalapi.Release{
+				Name:       rel.Name,
+				Namespace:  rel.Namespace,
+				Revision:   rel.Version,
+				Chart:      formatChartName(rel.Chart),
+				AppVersion: formatAppVersion(rel.Chart),
+			}
+			if rel.Info != nil {
+				item.Status = rel.Info.Status.String()
+				item.Updated = rel.Info.LastDeployed.Time
+			}
+			out = append(out, item)
+		}
+		return out, nil
+	}
+}
@@ -20,6 +20,7 @@ func newStackCommand(kubeconfig *string, kubeContext *string, logLevel *string,
 	var rootDir string
 	var configPath string
 	var profile string
+	var overlays []string
 	var clusters []string
 	var tags []string
 	var fromPaths []string
@@ -45,6 +46,7 @@ func newStackCommand(kubeconfig *string, kubeContext *string, logLevel *string,
 	cmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to stack root directory or stack.yaml/release.yaml")
 	cmd.PersistentFlags().StringVar(&rootDir, "root", ".", "Stack root directory (deprecated: use --config)")
 	cmd.PersistentFlags().StringVar(&profile, "profile", "", "Profile overlay name (defaults to stack.yaml.defaultProfile when present)")
+	cmd.PersistentFlags().StringSliceVarP(&overlays, "overlay", "f", nil, "Overlay file applied on top of the stack (repeatable; later files win)")
 	cmd.PersistentFlags().StringSliceVar(&clusters, "cluster", nil, "Filter the universe by cluster name (repeatable or comma-separated)")
 	cmd.PersistentFlags().StringSliceVar(&tags, "tag", nil, "Select releases by tag (repeatable or comma-separated)")
 	cmd.PersistentFlags().StringSliceVar(&fromPaths, "from-path", nil, "Select releases under a directory subtree (repeatable or comma-separated)")
@@ -83,6 +85,7 @@ func newStackCommand(kubeconfig *string, kubeContext *string, logLevel *string,
 	common := stackCommandCommon{
 		rootDir:              &rootDir,
 		profile:              &profile,
+		overlays:             &overlays,
 		clusters:             &clusters,
 		output:               &output,
 		planOnly:             &planOnly,
@@ -133,6 +136,18 @@ func newStackCommand(kubeconfig *string, kubeContext *string, logLevel *string,
 	decorateCommandHelp(cmd, "Stack Flags")
 
 	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := enforceReadOnlyProfile(cmd); err != nil {
+			return err
+		}
+		if err := applyInheritedImpersonation(cmd.Flags()); err != nil {
+			return err
+		}
+		if err := applyInheritedNetwork(cmd.Flags(), cmd.ErrOrStderr()); err != nil {
+			return err
+		}
+		if err := startInheritedTracing(cmd); err != nil {
+			return err
+		}
 		// Important: the repo already uses KTL_CONFIG for the global config file path.
 		// The CLI env binding layer may set this flag from that env var even when the
 		// user did not intend to target `ktl stack`. Only honor --config when it was
@@ -161,13 +176,29 @@ func newStackCommand(kubeconfig *string, kubeContext *string, logLevel *string,
 		return nil
 	}
 
+	_ = cmd.RegisterFlagCompletionFunc("release", func(c *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if cfg := strings.TrimSpace(configPath); cfg != "" {
+			if info, err := os.Stat(cfg); err == nil && info.IsDir() {
+				rootDir = cfg
+			} else {
+				rootDir = filepath.Dir(cfg)
+			}
+		}
+		return completeStackReleases(c, common, toComplete)
+	})
+
 	cmd.AddCommand(newStackPlanCommand(common))
 	cmd.AddCommand(newStackGraphCommand(common))
 	cmd.AddCommand(newStackExplainCommand(common))
+	cmd.AddCommand(newStackRenderCommand(common))
+	cmd.AddCommand(newStackLintCommand(common))
 
 	cmd.AddCommand(newStackSealCommand(&rootDir, &profile, &clusters, &inferDeps, &inferConfigRefs, &tags, &fromPaths, &releases, &gitRange, &gitIncludeDeps, &gitIncludeDependents, &includeDeps, &includeDependents, &allowMissingDeps))
 	cmd.AddCommand(newStackStatusCommand(&rootDir))
 	cmd.AddCommand(newStackRunsCommand(common))
+	cmd.AddCommand(newStackErrorsCommand(common))
+	cmd.AddCommand(newStackReplayCommand())
+	cmd.AddCommand(newStackDebugCommand(common))
 	cmd.AddCommand(newStackAuditCommand(&rootDir))
 	cmd.AddCommand(newStackExportCommand(&rootDir))
 	cmd.AddCommand(newStackKeygenCommand(&rootDir))
@@ -175,6 +206,7 @@ func newStackCommand(kubeconfig *string, kubeContext *string, logLevel *string,
 	cmd.AddCommand(newStackVerifyCommand(&rootDir))
 	cmd.AddCommand(newStackApplyCommand(common))
 	cmd.AddCommand(newStackDeleteCommand(common))
+	cmd.AddCommand(newStackReconcileCommand(common))
 	cmd.AddCommand(newStackRerunFailedCommand(&rootDir, &profile, &clusters, &inferDeps, &inferConfigRefs, &tags, &fromPaths, &releases, &gitRange, &gitIncludeDeps, &gitIncludeDependents, &includeDeps, &includeDependents, &allowMissingDeps, &secretProvider, &secretConfig, kubeconfig, kubeContext, logLevel, remoteAgent))
 	return cmd
 }
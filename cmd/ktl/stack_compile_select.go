@@ -21,7 +21,7 @@ func compileInferSelect(cmd *cobra.Command, common stackCommandCommon) (*stack.U
 func compileInferSelectWithConfig(cmd *cobra.Command, common stackCommandCommon, cfg stackCommandConfig) (*stack.Universe, *stack.Plan, stackCommandConfig, error) {
 	u := cfg.Universe
 
-	p, err := stack.Compile(u, stack.CompileOptions{Profile: cfg.Profile})
+	p, err := stack.Compile(u, stack.CompileOptions{Profile: cfg.Profile, Overlays: cfg.Overlays})
 	if err != nil {
 		return nil, nil, stackCommandConfig{}, err
 	}
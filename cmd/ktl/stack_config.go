@@ -34,6 +34,7 @@ type stackResumeDefaultOverrides struct {
 type stackCommandConfig struct {
 	RootDir  string
 	Profile  string
+	Overlays []string
 	Universe *stack.Universe
 
 	StackCLI stack.StackCLIResolved
@@ -125,6 +126,13 @@ func resolveStackCommandConfig(cmd *cobra.Command, common stackCommandCommon) (s
 		}
 	}
 
+	overlays := splitCSV(derefStringSlice(common.overlays))
+	if cmd != nil && !flagChanged(cmd, "overlay") {
+		if v := strings.TrimSpace(os.Getenv("KTL_STACK_OVERLAYS")); v != "" {
+			overlays = splitCSV([]string{v})
+		}
+	}
+
 	u, err := stack.Discover(root)
 	if err != nil {
 		return stackCommandConfig{}, err
@@ -141,6 +149,7 @@ func resolveStackCommandConfig(cmd *cobra.Command, common stackCommandCommon) (s
 	cfg := stackCommandConfig{
 		RootDir:  root,
 		Profile:  profile,
+		Overlays: overlays,
 		Universe: u,
 		StackCLI: stackCLI,
 
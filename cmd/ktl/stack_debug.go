@@ -0,0 +1,236 @@
+// File: cmd/ktl/stack_debug.go
+// Brief: `ktl stack debug` command wiring.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/spf13/cobra"
+)
+
+func newStackDebugCommand(common stackCommandCommon) *cobra.Command {
+	logTail := 40
+	var manifestPath string
+	var rerun bool
+	var yes bool
+	var allowDrift bool
+	cmd := &cobra.Command{
+		Use:   "debug <run-id> <node-id>",
+		Short: "Reconstruct a recorded node's inputs and failure context",
+		Long: `Load one node of a recorded run from the sqlite state store: the chart, values, --set and hook
+environment it ran with, the error and time it failed, the tail of its Helm log and the diff
+computed before it was applied. The node is re-rendered locally from the frozen plan, and inputs
+that changed on disk since the run are listed.
+
+Helm logs and diffs are only recorded for runs started with --helm-logs.
+
+On a terminal ktl offers to re-run just that node against the cluster; --rerun does so directly
+(with confirmation unless --yes). Use "last" as the run id for the most recent run.`,
+		Example: `  ktl stack debug last dev/default/api
+  ktl stack debug 2026-01-02T03-04-05Z api --manifest /tmp/api.yaml
+  ktl stack debug last api --rerun --yes`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootDir := strings.TrimSpace(derefString(common.rootDir))
+			if !flagChanged(cmd, "root") {
+				if v := strings.TrimSpace(os.Getenv("KTL_STACK_ROOT")); v != "" {
+					rootDir = v
+				}
+			}
+			if rootDir == "" {
+				rootDir = "."
+			}
+			runID := strings.TrimSpace(args[0])
+			if runID == "last" {
+				var err error
+				runID, err = stack.LoadMostRecentRun(rootDir)
+				if err != nil {
+					return err
+				}
+			}
+			d, err := stack.LoadNodeDebug(rootDir, runID, args[1], logTail)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			printNodeDebug(out, d)
+
+			secretOptions, err := buildStackSecretOptions(cmd.Context(), d.RootDir, derefString(common.secretProvider), derefString(common.secretConfig), cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, "Render (frozen inputs):")
+			manifest, renderErr := stack.RenderNode(cmd.Context(), d.Node, derefString(common.kubeconfig), derefString(common.kubeContext), stack.InferDepsOptions{Secrets: secretOptions})
+			switch {
+			case renderErr != nil:
+				fmt.Fprintf(out, "  failed: %v\n", renderErr)
+			case manifestPath != "":
+				if err := os.WriteFile(manifestPath, []byte(manifest), 0o644); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "  %d documents written to %s\n", countManifestDocs(manifest), manifestPath)
+			default:
+				fmt.Fprintf(out, "  ok: %d documents (save them with --manifest FILE)\n", countManifestDocs(manifest))
+			}
+
+			if !rerun {
+				dec, err := approvalMode(cmd, false, false)
+				if err != nil || !dec.InteractiveTTY {
+					fmt.Fprintf(out, "\nRe-run this node: ktl stack debug %s %s --rerun\n", d.RunID, d.Node.ID)
+					return nil
+				}
+				fmt.Fprintln(out)
+				if err := confirmAction(cmd.Context(), cmd.InOrStdin(), cmd.ErrOrStderr(), dec, fmt.Sprintf("Re-run %s against the cluster? Type 'yes' to continue:", d.Node.ID), confirmModeYes, ""); err != nil {
+					return nil
+				}
+			} else {
+				dec, err := approvalMode(cmd, yes, false)
+				if err != nil {
+					return err
+				}
+				if err := confirmAction(cmd.Context(), cmd.InOrStdin(), cmd.ErrOrStderr(), dec, fmt.Sprintf("Re-run %s against the cluster? Type 'yes' to continue:", d.Node.ID), confirmModeYes, ""); err != nil {
+					return err
+				}
+			}
+			if len(d.Drift) > 0 && !allowDrift {
+				return fmt.Errorf("cannot re-run: inputs changed since run %s (rerun with --allow-drift)", d.RunID)
+			}
+
+			command := d.Command
+			if command == "" {
+				command = "apply"
+			}
+			if command == "apply" {
+				if err := refuseUnderRepoApprovalPolicy(cmd.Context(), "ktl stack debug --rerun", d.RootDir); err != nil {
+					return err
+				}
+			}
+			runErr := stack.Run(cmd.Context(), stack.RunOptions{
+				Command:         command,
+				Plan:            stack.FilterByNodeIDs(d.Plan, []string{d.Node.ID}),
+				Concurrency:     1,
+				FailFast:        true,
+				AutoApprove:     true,
+				Secrets:         secretOptions,
+				Lock:            true,
+				Kubeconfig:      common.kubeconfig,
+				KubeContext:     common.kubeContext,
+				LogLevel:        common.logLevel,
+				RemoteAgentAddr: common.remoteAgent,
+				FailMode:        chooseFailMode(true),
+				MaxAttempts:     1,
+				InitialAttempts: map[string]int{d.Node.ID: d.Attempt},
+				HelmLogs:        true,
+			}, out, cmd.ErrOrStderr())
+			writeInterruptSummary(cmd.ErrOrStderr(), runErr)
+			return runErr
+		},
+	}
+	cmd.Flags().IntVar(&logTail, "log-tail", logTail, "Helm log lines to show (0 shows all)")
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Write the re-rendered manifest to this file")
+	cmd.Flags().BoolVar(&rerun, "rerun", false, "Re-run just this node against the cluster")
+	cmd.Flags().BoolVar(&yes, "yes", false, "With --rerun: skip the confirmation prompt")
+	cmd.Flags().BoolVar(&allowDrift, "allow-drift", false, "With --rerun: allow re-running when inputs changed since the run (unsafe)")
+	return cmd
+}
+
+func printNodeDebug(w io.Writer, d *stack.NodeDebug) {
+	n := d.Node
+	status := d.Status
+	if status == "" {
+		status = "unknown"
+	}
+	fmt.Fprintf(w, "Run %s (%s) · node %s · %s", d.RunID, orDash(d.Command), n.ID, status)
+	if d.Attempt > 0 {
+		fmt.Fprintf(w, " (attempt %d)", d.Attempt)
+	}
+	fmt.Fprintln(w)
+	if d.Error != nil {
+		at := ""
+		if d.FailedAt != "" {
+			at = " at " + d.FailedAt
+		}
+		class := ""
+		if d.Error.Class != "" {
+			class = "[" + d.Error.Class + "] "
+		}
+		fmt.Fprintf(w, "Failed%s: %s%s\n", at, class, d.Error.Message)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Inputs:")
+	fmt.Fprintf(w, "  cluster:   %s (namespace %s)\n", orDash(n.Cluster.Name), orDash(n.Namespace))
+	chart := n.Chart
+	if n.ChartVersion != "" {
+		chart += "@" + n.ChartVersion
+	}
+	if in := n.EffectiveInput; in != nil && in.Chart.Digest != "" {
+		chart += " (" + in.Chart.Digest + ")"
+	}
+	fmt.Fprintf(w, "  chart:     %s\n", orDash(chart))
+	digests := map[string]string{}
+	if n.EffectiveInput != nil {
+		for _, v := range n.EffectiveInput.Values {
+			digests[v.Path] = v.Digest
+		}
+	}
+	for _, v := range n.Values {
+		if dg := digests[v]; dg != "" {
+			fmt.Fprintf(w, "  values:    %s (%s)\n", v, dg)
+		} else {
+			fmt.Fprintf(w, "  values:    %s\n", v)
+		}
+	}
+	for _, kv := range sortedSetPairs(n.Set) {
+		fmt.Fprintf(w, "  set:       %s\n", kv)
+	}
+	for _, kv := range d.HookEnv {
+		fmt.Fprintf(w, "  hook env:  %s\n", kv)
+	}
+	if len(d.Drift) == 0 {
+		fmt.Fprintln(w, "  (unchanged on disk since the run)")
+	} else {
+		fmt.Fprintln(w, "  changed since the run:")
+		for _, line := range d.Drift {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+
+	fmt.Fprintln(w)
+	if len(d.HelmLogs) == 0 {
+		fmt.Fprintln(w, "Helm log: not recorded (run with --helm-logs to capture it)")
+	} else {
+		fmt.Fprintf(w, "Helm log (last %d lines):\n", len(d.HelmLogs))
+		for _, line := range d.HelmLogs {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+
+	fmt.Fprintln(w)
+	if strings.TrimSpace(d.Diff) == "" {
+		fmt.Fprintln(w, "Diff at failure time: not recorded (run with --helm-logs to capture it)")
+	} else {
+		fmt.Fprintln(w, "Diff at failure time:")
+		for _, line := range strings.Split(strings.TrimRight(d.Diff, "\n"), "\n") {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
+
+func countManifestDocs(manifest string) int {
+	count := 0
+	for _, doc := range strings.Split("\n"+manifest, "\n---") {
+		if strings.Contains(doc, "kind:") {
+			count++
+		}
+	}
+	return count
+}
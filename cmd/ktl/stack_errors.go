@@ -0,0 +1,72 @@
+// File: cmd/ktl/stack_errors.go
+// Brief: `ktl stack errors` command wiring.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/spf13/cobra"
+)
+
+func newStackErrorsCommand(common stackCommandCommon) *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "errors",
+		Short: "List recurring node failures by error digest across runs",
+		Long: `List node failures recorded in the sqlite state store, grouped by error digest and ordered by
+how often they occurred. Copy a digest into runner.flaky in stack.yaml to annotate (and retry) a
+known flaky failure in later runs.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootDir := strings.TrimSpace(derefString(common.rootDir))
+			if !flagChanged(cmd, "root") {
+				if v := strings.TrimSpace(os.Getenv("KTL_STACK_ROOT")); v != "" {
+					rootDir = v
+				}
+			}
+			if rootDir == "" {
+				rootDir = "."
+			}
+
+			var rules []stack.FlakyRule
+			if u, err := stack.Discover(rootDir); err == nil {
+				if r, err := stack.ResolveRunnerConfig(u, derefString(common.profile)); err == nil {
+					rules = r.Flaky
+				}
+			}
+			entries, err := stack.ListErrorDigests(rootDir, limit, rules)
+			if err != nil {
+				return err
+			}
+			outFormat := strings.ToLower(strings.TrimSpace(derefString(common.output)))
+			if cfg, err := resolveStackCommandConfig(cmd, common); err == nil {
+				printStackConfigWarnings(cmd, cfg.Warnings)
+				outFormat = cfg.Output
+			} else if !isNoStackRootError(err) {
+				return err
+			} else if !flagChanged(cmd, "output") {
+				if v := strings.TrimSpace(os.Getenv("KTL_STACK_OUTPUT")); v != "" {
+					outFormat = strings.ToLower(v)
+				}
+			}
+
+			switch outFormat {
+			case "", "table":
+				return stack.PrintErrorDigestsTable(cmd.OutOrStdout(), entries)
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			default:
+				return fmt.Errorf("unknown --output %q (expected table|json)", outFormat)
+			}
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of digests to list (most frequent first)")
+	return cmd
+}
@@ -0,0 +1,70 @@
+// File: cmd/ktl/stack_lint.go
+// Brief: CLI command wiring and implementation for 'stack lint'.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/spf13/cobra"
+)
+
+func newStackLintCommand(common stackCommandCommon) *cobra.Command {
+	format := "text"
+	var strict bool
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Statically check stack.yaml and release.yaml files",
+		Long: `Check the stack without contacting any cluster and report every problem at once:
+
+  error    duplicate release names per cluster, needs that do not resolve, dependency cycles,
+           values files that do not exist, releases that cannot be resolved
+  warning  releases that can never run (they depend on a cycle or a missing need), hooks
+           without a timeout, critical releases without verify.enabled
+
+Findings are printed as file:line: severity: message [rule]. The command exits non-zero when
+any error is found (or any warning with --strict). Use --format sarif to upload the findings to
+GitHub code scanning or another SARIF consumer.`,
+		Example: `  ktl stack lint
+  ktl stack lint --config ./stacks/prod --strict
+  ktl stack lint --format sarif > stack-lint.sarif`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := resolveStackCommandConfig(cmd, common)
+			if err != nil {
+				return err
+			}
+			report := stack.Lint(cfg.Universe, stack.LintOptions{Profile: cfg.Profile})
+			out := cmd.OutOrStdout()
+			switch format {
+			case "json":
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			case "sarif":
+				if err := stack.WriteLintSARIF(out, report); err != nil {
+					return err
+				}
+			default:
+				for _, f := range report.Findings {
+					fmt.Fprintln(out, f.String())
+				}
+				fmt.Fprintf(out, "Checked %d release(s): %d error(s), %d warning(s)\n", report.Releases, report.Errors, report.Warnings)
+			}
+			if report.Errors > 0 || (strict && report.Warnings > 0) {
+				return fmt.Errorf("stack lint found %d error(s), %d warning(s)", report.Errors, report.Warnings)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Var(newEnumStringValue(&format, "text", "json", "sarif"), "format", "Output format: text, json, or sarif")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Exit non-zero on warnings too")
+	decorateCommandHelp(cmd, "Lint Flags")
+	return cmd
+}
@@ -0,0 +1,194 @@
+// File: cmd/ktl/stack_reconcile.go
+// Brief: `ktl stack reconcile` command wiring (GitOps pull mode).
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/spf13/cobra"
+)
+
+func newStackReconcileCommand(common stackCommandCommon) *cobra.Command {
+	var repo string
+	var ref string
+	var subPath string
+	var interval time.Duration
+	var checkoutDir string
+	var once bool
+	var noCache bool
+	var leaderElect bool
+	var leaseNamespace string
+	var leaseName string
+	var identity string
+
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Continuously pull a git repo and apply stack drift (GitOps pull mode)",
+		Long: `Clone or pull a git repository on an interval, compile the stack at --path, and apply it.
+
+Releases whose rendered manifest digest already matches the deployed release are skipped, so each
+pass only applies what changed. Run several replicas with --leader-elect so only one applies at a time.`,
+		Example: `  # Reconcile stacks/prod from main every 5 minutes
+  ktl stack reconcile --repo https://github.com/acme/platform.git --path stacks/prod --interval 5m
+
+  # Run as a replicated in-cluster Deployment
+  ktl stack reconcile --repo https://github.com/acme/platform.git --path stacks/prod --leader-elect --leader-election-namespace ktl-system`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repo = strings.TrimSpace(repo)
+			if repo == "" {
+				return fmt.Errorf("--repo is required")
+			}
+			if !once && interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+			if strings.TrimSpace(checkoutDir) == "" {
+				dir, err := defaultReconcileCheckoutDir(repo, ref)
+				if err != nil {
+					return err
+				}
+				checkoutDir = dir
+			}
+			cleanPath := filepath.Clean(strings.TrimSpace(subPath))
+			if filepath.IsAbs(cleanPath) || cleanPath == ".." || strings.HasPrefix(cleanPath, ".."+string(filepath.Separator)) {
+				return fmt.Errorf("--path must be relative to the repository root (got %q)", subPath)
+			}
+			if identity == "" {
+				host, _ := os.Hostname()
+				identity = fmt.Sprintf("%s-%d", host, os.Getpid())
+			}
+
+			errOut := cmd.ErrOrStderr()
+			src := stack.GitSource{URL: repo, Ref: ref, Dir: checkoutDir}
+			opts := stackRunCLIOptions{
+				ContinueOnError: true,
+				Yes:             true,
+				CacheApply:      !noCache,
+				Retry:           2,
+				Lock:            true,
+				LockTTL:         30 * time.Minute,
+				LockOwner:       identity,
+			}
+			var outMu sync.Mutex
+			lineObserver := stack.RunEventObserverFunc(func(ev stack.RunEvent) {
+				outMu.Lock()
+				defer outMu.Unlock()
+				node := strings.TrimSpace(ev.NodeID)
+				if node == "" {
+					node = "-"
+				}
+				fmt.Fprintf(errOut, "%s\t%s\t%s\t%d\t%s\n", ev.TS, ev.Type, node, ev.Attempt, strings.TrimSpace(ev.Message))
+			})
+
+			reconcile := func(ctx context.Context) (string, error) {
+				revision, err := stack.SyncGitCheckout(ctx, src)
+				if err != nil {
+					return "", err
+				}
+				*common.rootDir = filepath.Join(checkoutDir, cleanPath)
+				// The checkout path wins over KTL_STACK_ROOT.
+				if f := cmd.Flags().Lookup("root"); f != nil {
+					f.Changed = true
+				}
+				_, p, _, err := compileInferSelect(cmd, common)
+				if err != nil {
+					return revision, err
+				}
+				if err := refuseUnderRepoApprovalPolicy(ctx, "ktl stack reconcile", p.StackRoot); err != nil {
+					return revision, err
+				}
+				effective, adaptive, err := resolveRunnerFromFlags(cmd, p.Runner, opts.runnerOverrides())
+				if err != nil {
+					return revision, err
+				}
+				secretOptions, err := buildStackSecretOptions(ctx, p.StackRoot, derefString(common.secretProvider), derefString(common.secretConfig), errOut)
+				if err != nil {
+					return revision, err
+				}
+				runOpts := buildRunOptions(stackRunApply, common, p, opts, effective, adaptive, secretOptions)
+				runOpts.EventObservers = append(runOpts.EventObservers, lineObserver)
+				return revision, stack.Run(ctx, runOpts, cmd.OutOrStdout(), errOut)
+			}
+			loop := func(ctx context.Context) error {
+				return stack.RunReconcileLoop(ctx, stack.ReconcileLoopOptions{
+					Interval:  interval,
+					Once:      once,
+					Reconcile: reconcile,
+					OnResult: func(res stack.ReconcileResult) {
+						rev := res.Revision
+						if len(rev) > 12 {
+							rev = rev[:12]
+						}
+						if res.Err != nil {
+							fmt.Fprintf(errOut, "reconcile #%d failed after %s (revision %s): %v\n", res.Iteration, res.Duration.Round(time.Millisecond), dashIfEmpty(rev), res.Err)
+							return
+						}
+						fmt.Fprintf(errOut, "reconcile #%d applied revision %s in %s\n", res.Iteration, rev, res.Duration.Round(time.Millisecond))
+					},
+				})
+			}
+
+			if !leaderElect {
+				return loop(cmd.Context())
+			}
+			client, err := kube.New(cmd.Context(), derefString(common.kubeconfig), derefString(common.kubeContext))
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(leaseNamespace) == "" {
+				leaseNamespace = client.Namespace
+			}
+			if strings.TrimSpace(leaseNamespace) == "" {
+				leaseNamespace = "default"
+			}
+			if strings.TrimSpace(leaseName) == "" {
+				leaseName = "ktl-stack-reconcile-" + shortHash(repo+"#"+cleanPath)
+			}
+			return kube.RunWithLeaderElection(cmd.Context(), client.Clientset, kube.LeaderElectionOptions{
+				Namespace: leaseNamespace,
+				Name:      leaseName,
+				Identity:  identity,
+				OnNewLeader: func(id string) {
+					fmt.Fprintf(errOut, "leader for %s/%s: %s\n", leaseNamespace, leaseName, id)
+				},
+			}, loop)
+		},
+	}
+
+	cmd.Flags().StringVar(&repo, "repo", "", "Git repository URL to pull the stack from")
+	cmd.Flags().StringVar(&ref, "ref", "", "Branch or tag to track (defaults to the remote's default branch)")
+	cmd.Flags().StringVar(&subPath, "path", ".", "Stack root directory inside the repository")
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Minute, "Time between reconcile passes")
+	cmd.Flags().StringVar(&checkoutDir, "checkout-dir", "", "Local checkout directory (defaults to ~/.ktl/reconcile/<hash>)")
+	cmd.Flags().BoolVar(&once, "once", false, "Run a single reconcile pass and exit")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Apply every release each pass instead of skipping digest matches")
+	cmd.Flags().BoolVar(&leaderElect, "leader-elect", false, "Use a Kubernetes Lease so only one replica reconciles at a time")
+	cmd.Flags().StringVar(&leaseNamespace, "leader-election-namespace", "", "Namespace of the leader election Lease (defaults to the context namespace)")
+	cmd.Flags().StringVar(&leaseName, "leader-election-id", "", "Name of the leader election Lease (defaults to ktl-stack-reconcile-<hash of repo and path>)")
+	cmd.Flags().StringVar(&identity, "identity", "", "Replica identity for leader election and the stack lock (defaults to hostname-pid)")
+	return cmd
+}
+
+func defaultReconcileCheckoutDir(repo, ref string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir for --checkout-dir: %w", err)
+	}
+	return filepath.Join(home, ".ktl", "reconcile", shortHash(repo+"@"+ref)), nil
+}
+
+func shortHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,86 @@
+// File: cmd/ktl/stack_render.go
+// Brief: `ktl stack render` command wiring.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newStackRenderCommand(common stackCommandCommon) *cobra.Command {
+	var showMerged bool
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render the selected releases' manifests, or the effective stack with --show-merged",
+		Long:  "Render each selected release client-side (charts and manifests directories) and print the manifests.\n\nWith --show-merged, print the effective stack instead: every release with defaults, the profile, -f overlay files, and the clusters section already applied.",
+		Example: `  # What would prod deploy?
+  ktl stack render -f envs/prod.yaml --show-merged
+
+  # Compare two environments
+  diff <(ktl stack render -f envs/staging.yaml --show-merged) <(ktl stack render -f envs/prod.yaml --show-merged)`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, p, cfg, err := compileInferSelect(cmd, common)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if showMerged {
+				fmt.Fprintf(out, "# Effective stack %s (profile: %s", p.StackName, dashIfEmpty(p.Profile))
+				if len(cfg.Overlays) > 0 {
+					fmt.Fprintf(out, ", overlays: %s", strings.Join(cfg.Overlays, ", "))
+				}
+				fmt.Fprintln(out, ")")
+				enc := yaml.NewEncoder(out)
+				enc.SetIndent(2)
+				if err := enc.Encode(stack.MergedStack(p)); err != nil {
+					return err
+				}
+				return enc.Close()
+			}
+
+			secretOptions, err := buildStackSecretOptions(cmd.Context(), p.StackRoot, derefString(common.secretProvider), derefString(common.secretConfig), nil)
+			if err != nil {
+				return err
+			}
+			if secretOptions == nil {
+				secretOptions = &deploy.SecretOptions{}
+			}
+			for _, id := range renderOrder(p) {
+				node := p.ByID[id]
+				if node == nil {
+					continue
+				}
+				manifest, err := stack.RenderNode(cmd.Context(), node, derefString(common.kubeconfig), derefString(common.kubeContext), stack.InferDepsOptions{Secrets: secretOptions})
+				if err != nil {
+					return fmt.Errorf("render %s: %w", node.ID, err)
+				}
+				if strings.TrimSpace(manifest) == "" {
+					continue
+				}
+				fmt.Fprintf(out, "---\n# Source: %s\n%s\n", node.ID, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(manifest), "---")))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&showMerged, "show-merged", false, "Print the effective stack (after defaults, profile, overlays, and clusters) instead of manifests")
+	return cmd
+}
+
+// renderOrder prints releases in apply order so the output reads like a deployment.
+func renderOrder(p *stack.Plan) []string {
+	if len(p.Order) == len(p.Nodes) {
+		return p.Order
+	}
+	ids := make([]string, 0, len(p.Nodes))
+	for _, n := range p.Nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
@@ -0,0 +1,110 @@
+// File: cmd/ktl/stack_replay.go
+// Brief: `ktl stack replay` command wiring.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/kubekattle/ktl/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newStackReplayCommand() *cobra.Command {
+	speed := 1.0
+	maxGap := 2 * time.Second
+	var width int
+	var noteWrap bool
+	var expandFailed bool
+	cmd := &cobra.Command{
+		Use:   "replay <events.jsonl>",
+		Short: "Re-render a recorded stack run in the run console",
+		Long: `Replay a run recorded with --events-file (or --output json) through the stack run console, at the
+recorded pace scaled by --speed. Long idle stretches are shortened to --max-gap.
+
+On a terminal the console updates in place; otherwise (or with --speed 0) the events are applied
+without waiting and only the final console frame is printed.`,
+		Example: `  ktl stack apply --events-file run.jsonl
+  ktl stack replay run.jsonl --speed 4
+  ktl stack replay run.jsonl --speed 0 --width 160 > final.txt`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if speed < 0 {
+				return fmt.Errorf("--speed must be >= 0")
+			}
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			events, err := stack.ReadEventsFile(f)
+			_ = f.Close()
+			if err != nil {
+				return fmt.Errorf("read %s: %w", args[0], err)
+			}
+			if len(events) == 0 {
+				return fmt.Errorf("%s contains no run events", args[0])
+			}
+
+			out := cmd.OutOrStdout()
+			live := isTerminalWriter(out) && speed > 0
+			if width <= 0 {
+				if w, ok := ui.TerminalWidth(out); ok {
+					width = w
+				} else {
+					width = 120
+				}
+			}
+			clock := &stack.ReplayClock{}
+			consoleOut := out
+			if !live {
+				consoleOut = nil
+			}
+			console := stack.NewRunConsole(consoleOut, nil, stack.ReplayCommand(events), stack.RunConsoleOptions{
+				Enabled:      true,
+				Width:        width,
+				Color:        isTerminalWriter(out),
+				Now:          clock.Now,
+				NoteWrap:     noteWrap,
+				ExpandFailed: expandFailed,
+			})
+			replayOpts := stack.ReplayOptions{Clock: clock}
+			if live {
+				replayOpts.Speed = speed
+				replayOpts.MaxGap = maxGap
+				stopResize := watchConsoleResize(func() {
+					if w, ok := ui.TerminalWidth(out); ok {
+						console.SetWidth(w)
+					}
+				})
+				defer stopResize()
+			}
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			err = stack.ReplayEvents(ctx, events, console, replayOpts)
+			if live {
+				console.Done()
+			} else {
+				fmt.Fprintln(out, strings.Join(console.SnapshotLines(), "\n"))
+			}
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		},
+	}
+	cmd.Flags().Float64Var(&speed, "speed", speed, "Playback speed multiplier (2 = twice as fast; 0 prints only the final frame)")
+	cmd.Flags().DurationVar(&maxGap, "max-gap", maxGap, "Longest pause between two events during playback (0 keeps recorded gaps)")
+	cmd.Flags().IntVar(&width, "width", width, "Console width (default: terminal width, or 120)")
+	cmd.Flags().BoolVar(&noteWrap, "note-wrap", noteWrap, "Wrap long notes and failure messages instead of truncating them")
+	cmd.Flags().BoolVar(&expandFailed, "expand-failed", expandFailed, "Show the nodes of collapsed console groups that have a failure")
+	return cmd
+}
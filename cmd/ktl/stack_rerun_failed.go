@@ -51,11 +51,14 @@ func newStackRerunFailedCommand(rootDir, profile *string, clusters *[]string, in
 				}
 			}
 			p = stack.FilterByNodeStatus(p, loaded.StatusByID, []string{"failed"})
+			if err := refuseUnderRepoApprovalPolicy(cmd.Context(), "ktl stack rerun-failed", p.StackRoot); err != nil {
+				return err
+			}
 			secretOptions, err := buildStackSecretOptions(cmd.Context(), p.StackRoot, derefString(secretProvider), derefString(secretConfig), cmd.ErrOrStderr())
 			if err != nil {
 				return err
 			}
-			return stack.Run(cmd.Context(), stack.RunOptions{
+			runErr := stack.Run(cmd.Context(), stack.RunOptions{
 				Command:                "apply",
 				Plan:                   p,
 				Concurrency:            concurrency,
@@ -89,6 +92,8 @@ func newStackRerunFailedCommand(rootDir, profile *string, clusters *[]string, in
 					AllowMissingDeps:     *allowMissingDeps,
 				},
 			}, cmd.OutOrStdout(), cmd.ErrOrStderr())
+			writeInterruptSummary(cmd.ErrOrStderr(), runErr)
+			return runErr
 		},
 	}
 	cmd.Flags().BoolVar(&yes, "yes", false, "Skip confirmation prompts")
@@ -4,8 +4,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -22,6 +26,7 @@ import (
 type stackCommandCommon struct {
 	rootDir              *string
 	profile              *string
+	overlays             *[]string
 	clusters             *[]string
 	output               *string
 	planOnly             *bool
@@ -73,6 +78,7 @@ func newStackRunCommand(kind stackRunKind, common stackCommandCommon) *cobra.Com
 		VerifyBundle:           true,
 		ConsoleHooks:           true,
 		ConsoleFilter:          "all",
+		ConsoleGroup:           "auto",
 	}
 
 	short := "Apply the selected stack releases in DAG order"
@@ -88,6 +94,26 @@ func newStackRunCommand(kind stackRunKind, common stackCommandCommon) *cobra.Com
 			var p *stack.Plan
 			var cleanup func()
 
+			if err := validateWindowOverride(opts.OverrideWindow, opts.OverrideReason); err != nil {
+				return err
+			}
+			// enforceWindows refuses applies to contexts/namespaces outside their deploy.windows, and
+			// any apply while the repo requires a second approver.
+			enforceWindows := func(p *stack.Plan) error {
+				if kind != stackRunApply || opts.DryRun || p == nil {
+					return nil
+				}
+				if err := refuseUnderRepoApprovalPolicy(cmd.Context(), "ktl stack apply", p.StackRoot); err != nil {
+					return err
+				}
+				deployCfg, err := loadDeployConfig(cmd.Context())
+				if err != nil {
+					return err
+				}
+				targets := stackWindowTargets(p, derefString(common.kubeconfig), derefString(common.kubeContext))
+				return enforceDeployWindows(cmd, cmd.ErrOrStderr(), deployCfg.Windows, targets, opts.OverrideWindow, opts.OverrideReason, time.Now())
+			}
+
 			runSelector := buildRunSelector(common)
 			planOutput := strings.ToLower(strings.TrimSpace(*common.output))
 			cfg, cfgErr := resolveStackCommandConfig(cmd, common)
@@ -202,8 +228,23 @@ func newStackRunCommand(kind stackRunKind, common stackCommandCommon) *cobra.Com
 						ShowHelmLogs:    showHelmPanel,
 						CaptureHelmLogs: captureHelm,
 						HelmLogsMode:    consoleHelm,
+						GroupBy:         opts.ConsoleGroup,
+						ExpandGroups:    opts.ExpandGroups,
+						ExpandFailed:    opts.ExpandFailed,
+						NoteWrap:        opts.NoteWrap,
 					})
 					observers = append(observers, console)
+					stopResize := watchConsoleResize(func() {
+						w, ok := ui.TerminalWidth(errOut)
+						if !ok {
+							return
+						}
+						if (opts.ConsoleWide || opts.ConsoleDetails) && w < 160 {
+							w = 160
+						}
+						console.SetWidth(w)
+					})
+					defer stopResize()
 				} else {
 					observers = append(observers, stack.RunEventObserverFunc(func(ev stack.RunEvent) {
 						encMu.Lock()
@@ -250,11 +291,95 @@ func newStackRunCommand(kind stackRunKind, common stackCommandCommon) *cobra.Com
 					fmt.Fprintf(errOut, "Serving ktl websocket stack stream on %s\n", addr)
 				}
 
+				var pager *stack.OncallNotifier
+				if provider := strings.TrimSpace(opts.NotifyOncall); provider != "" {
+					key := strings.TrimSpace(opts.OncallKey)
+					if key == "" {
+						key = strings.TrimSpace(os.Getenv("KTL_ONCALL_KEY"))
+					}
+					stackName := ""
+					if p != nil {
+						stackName = strings.TrimSpace(p.StackName)
+					}
+					var err error
+					pager, err = stack.NewOncallNotifier(stack.OncallOptions{
+						Provider: provider,
+						URL:      opts.OncallURL,
+						Key:      key,
+						Classes:  opts.OncallClasses,
+						Stack:    stackName,
+						Command:  string(kind),
+					})
+					if err != nil {
+						return fmt.Errorf("--notify-oncall: %w", err)
+					}
+					observers = append(observers, pager)
+				}
+
+				var junit *stack.JUnitReport
+				if strings.TrimSpace(opts.JUnitPath) != "" {
+					stackName := ""
+					if p != nil {
+						stackName = strings.TrimSpace(p.StackName)
+					}
+					junit = stack.NewJUnitReport(stack.JUnitOptions{Stack: stackName, Command: string(kind), Hooks: opts.JUnitHooks})
+					observers = append(observers, junit)
+				}
+
+				var htmlReport *stack.RunReport
+				if strings.TrimSpace(opts.HTMLReport) != "" {
+					htmlReport = stack.NewRunReport(p, string(kind))
+					observers = append(observers, htmlReport)
+				}
+
+				var eventsFile *stack.EventsFile
+				if path := strings.TrimSpace(opts.EventsFile); path != "" {
+					f, err := stack.OpenEventsFile(path)
+					if err != nil {
+						return fmt.Errorf("--events-file: %w", err)
+					}
+					eventsFile = f
+					observers = append(observers, eventsFile)
+				}
+
 				runOpts.EventObservers = append(runOpts.EventObservers, observers...)
+				runErr := stack.Run(cmd.Context(), runOpts, out, errOut)
 				if console != nil {
-					defer console.Done()
+					console.Done()
+				}
+				if eventsFile != nil {
+					if err := eventsFile.Close(); err != nil {
+						fmt.Fprintf(errOut, "Warning: --events-file %s: %v\n", opts.EventsFile, err)
+					}
+				}
+				writeInterruptSummary(errOut, runErr)
+				if junit != nil {
+					if err := writeRunReportFile(opts.JUnitPath, "--junit", junit.Write); err != nil {
+						if runErr == nil {
+							return err
+						}
+						fmt.Fprintf(errOut, "Warning: %v\n", err)
+					}
 				}
-				return stack.Run(cmd.Context(), runOpts, out, errOut)
+				if htmlReport != nil {
+					if err := writeRunReportFile(opts.HTMLReport, "--html-report", htmlReport.WriteHTML); err != nil {
+						if runErr == nil {
+							return err
+						}
+						fmt.Fprintf(errOut, "Warning: %v\n", err)
+					} else {
+						fmt.Fprintf(errOut, "Wrote run report to %s\n", opts.HTMLReport)
+					}
+				}
+				if runErr != nil && pager != nil && !errors.Is(runErr, context.Canceled) {
+					sent, err := pager.Send(context.WithoutCancel(cmd.Context()))
+					if err != nil {
+						fmt.Fprintf(errOut, "Warning: on-call notification failed: %v\n", err)
+					} else if sent > 0 {
+						fmt.Fprintf(errOut, "Paged on-call (%s) for %d failure(s)\n", opts.NotifyOncall, sent)
+					}
+				}
+				return runErr
 			}
 
 			if strings.TrimSpace(opts.SealedDir) != "" || strings.TrimSpace(opts.FromBundle) != "" {
@@ -336,6 +461,9 @@ func newStackRunCommand(kind stackRunKind, common stackCommandCommon) *cobra.Com
 				runOpts.ResumeStepsByID = stepsByID
 				runOpts.InitialAttempts = initialAttempts
 				runOpts.Selector = runSelector
+				if err := enforceWindows(p); err != nil {
+					return err
+				}
 				return runWithViews(p, runOpts)
 			} else {
 				var pp *stack.Plan
@@ -362,6 +490,10 @@ func newStackRunCommand(kind stackRunKind, common stackCommandCommon) *cobra.Com
 				}
 			}
 
+			if err := enforceWindows(p); err != nil {
+				return err
+			}
+
 			if kind == stackRunDelete && !opts.Yes {
 				if opts.DeleteConfirmThreshold <= 0 {
 					opts.DeleteConfirmThreshold = 20
@@ -424,6 +556,12 @@ type stackRunCLIOptions struct {
 	DryRun                 bool
 	Diff                   bool
 	CacheApply             bool
+	SkipUnchanged          bool
+	ChartCacheDir          string
+	NoGitMetadata          bool
+	ApproveRisks           []string
+	OverrideWindow         bool
+	OverrideReason         string
 	HelmLogs               string
 	Resume                 bool
 	RunID                  string
@@ -431,6 +569,9 @@ type stackRunCLIOptions struct {
 	AllowDrift             bool
 	RerunFailed            bool
 	Retry                  int
+	ClusterPreflight       string
+	MaxFailures            int
+	FailureDomain          string
 
 	RunnerKubeQPS                 float32
 	RunnerKubeBurst               int
@@ -458,6 +599,16 @@ type stackRunCLIOptions struct {
 
 	WSListenAddr string
 
+	JUnitPath  string
+	JUnitHooks bool
+	HTMLReport string
+	EventsFile string
+
+	NotifyOncall  string
+	OncallURL     string
+	OncallKey     string
+	OncallClasses []string
+
 	ConsoleWide        bool
 	ConsoleDetails     bool
 	ConsoleDetailsTail int
@@ -466,6 +617,10 @@ type stackRunCLIOptions struct {
 	ConsoleNoisyPhases bool
 	ConsoleFilter      string
 	ConsoleHelm        string
+	ConsoleGroup       string
+	ExpandGroups       []string
+	ExpandFailed       bool
+	NoteWrap           bool
 }
 
 func (o stackRunCLIOptions) runnerOverrides() stackRunnerOverrides {
@@ -482,17 +637,22 @@ func (o stackRunCLIOptions) runnerOverrides() stackRunnerOverrides {
 		AdaptiveRampSuccesses:   o.RunnerAdaptiveRampSuccesses,
 		AdaptiveRampFailureRate: o.RunnerAdaptiveRampFailureRate,
 		AdaptiveCooldownSevere:  o.RunnerAdaptiveCooldownSevere,
+		MaxFailures:             o.MaxFailures,
+		FailureDomain:           o.FailureDomain,
 	}
 }
 
 func addStackRunFlags(cmd *cobra.Command, kind stackRunKind, opts *stackRunCLIOptions) {
 	cmd.Flags().BoolVar(&opts.FailFast, "fail-fast", opts.FailFast, "Stop scheduling new releases on first error")
 	cmd.Flags().BoolVar(&opts.ContinueOnError, "continue-on-error", opts.ContinueOnError, "Continue scheduling independent releases after failures")
+	cmd.Flags().IntVar(&opts.MaxFailures, stackFlagMaxFailures, opts.MaxFailures, "Stop scheduling new releases after this many release failures (0 disables; default from runner.maxFailures in stack.yaml)")
+	cmd.Flags().Var(newEnumStringValue(&opts.FailureDomain, "", stack.FailureDomainRun, stack.FailureDomainCluster), stackFlagFailureDomain, "Where failures stop scheduling: run (everything) or cluster (only the failing cluster; other clusters continue)")
 	cmd.Flags().BoolVar(&opts.Yes, "yes", opts.Yes, "Skip confirmation prompts")
 
 	cmd.Flags().StringVar(&opts.HelmLogs, "helm-logs", opts.HelmLogs, "Helm log capture + TTY rendering mode: off|on|all (default off)")
 	cmd.Flags().Lookup("helm-logs").NoOptDefVal = "on"
 
+	cmd.Flags().BoolVar(&opts.SkipUnchanged, "skip-unchanged", opts.SkipUnchanged, "Skip releases already deployed from identical inputs (chart, values, hooks, images), as recorded on the release by any machine; releases changed by other tools or edited live, and releases using secret/value-source references or pinned digests, are applied")
 	cmd.Flags().BoolVar(&opts.CacheApply, "cache-apply", opts.CacheApply, "Skip Helm apply when the release manifest digest matches the cached desired digest (unsafe: does not detect live drift)")
 
 	cmd.Flags().BoolVar(&opts.Resume, "resume", opts.Resume, "Resume the most recent run (uses its frozen plan unless --replan is set)")
@@ -501,6 +661,7 @@ func addStackRunFlags(cmd *cobra.Command, kind stackRunKind, opts *stackRunCLIOp
 	cmd.Flags().BoolVar(&opts.AllowDrift, "allow-drift", opts.AllowDrift, "Allow resume even when inputs changed since the plan was written (unsafe)")
 	cmd.Flags().BoolVar(&opts.RerunFailed, "rerun-failed", opts.RerunFailed, "When resuming, schedule only failed nodes")
 	cmd.Flags().IntVar(&opts.Retry, "retry", opts.Retry, "Maximum attempts per release (includes the initial attempt)")
+	cmd.Flags().Var(newEnumStringValue(&opts.ClusterPreflight, "off", "deny", "skip"), "cluster-preflight", "Check cluster health before scheduling: off|deny|skip (default from runner.preflight in stack.yaml)")
 
 	cmd.Flags().IntVar(&opts.Concurrency, "concurrency", opts.Concurrency, "Maximum number of concurrent releases to run")
 	cmd.Flags().BoolVar(&opts.ProgressiveConcurrency, "progressive-concurrency", opts.ProgressiveConcurrency, "Start at 1 worker, then ramp up/down based on successes/failures")
@@ -513,10 +674,23 @@ func addStackRunFlags(cmd *cobra.Command, kind stackRunKind, opts *stackRunCLIOp
 	if kind == stackRunApply {
 		cmd.Flags().BoolVar(&opts.DryRun, "dry-run", opts.DryRun, "Preview changes without applying them")
 		cmd.Flags().BoolVar(&opts.Diff, "diff", opts.Diff, "Print a manifest diff during apply")
+		cmd.Flags().BoolVar(&opts.NoGitMetadata, "no-git-metadata", opts.NoGitMetadata, "Do not record the stack's git commit, branch, dirty state, and author on releases")
+		cmd.Flags().StringSliceVar(&opts.ApproveRisks, "approve-risk", opts.ApproveRisks, "Approve plan risk tags listed in a release's apply.requireApproval (e.g. image-change,replica-decrease, or all)")
+		cmd.Flags().BoolVar(&opts.OverrideWindow, "override-window", opts.OverrideWindow, "Apply outside the deploy windows configured under deploy.windows in .ktl.yaml (requires --reason; recorded in the audit log)")
+		cmd.Flags().StringVar(&opts.OverrideReason, "reason", opts.OverrideReason, "Why the deploy window is overridden (with --override-window)")
+		cmd.Flags().StringVar(&opts.ChartCacheDir, "chart-cache-dir", opts.ChartCacheDir, "Keep downloaded chart archives (exact versions) in this directory and reuse them across runs")
 	}
 	if kind == stackRunDelete {
 		cmd.Flags().IntVar(&opts.DeleteConfirmThreshold, "delete-confirm-threshold", opts.DeleteConfirmThreshold, "Prompt when deleting at least this many releases (0 disables)")
 	}
+	cmd.Flags().Var(newEnumStringValue(&opts.NotifyOncall, append([]string{""}, stack.OncallProviders...)...), "notify-oncall", "Page on-call when the run fails: pagerduty|opsgenie|webhook")
+	cmd.Flags().StringVar(&opts.OncallURL, "oncall-url", opts.OncallURL, "On-call endpoint (required for webhook; defaults to the provider's events API)")
+	cmd.Flags().StringVar(&opts.OncallKey, "oncall-key", opts.OncallKey, "PagerDuty routing key or Opsgenie API key (defaults to $KTL_ONCALL_KEY)")
+	cmd.Flags().StringSliceVar(&opts.OncallClasses, "oncall-classes", opts.OncallClasses, "Only page for these failure classes: HOOK_FAILED, WAIT_TIMEOUT, HELM_ERROR (default all)")
+	cmd.Flags().StringVar(&opts.JUnitPath, "junit", opts.JUnitPath, "Write a JUnit XML report (one test case per release, with durations and failures) to this path for CI")
+	cmd.Flags().BoolVar(&opts.JUnitHooks, "junit-hooks", opts.JUnitHooks, "With --junit: also report each hook run as a test case")
+	cmd.Flags().StringVar(&opts.EventsFile, "events-file", opts.EventsFile, "Append every run event to this JSON Lines file (replay it with ktl stack replay)")
+	cmd.Flags().StringVar(&opts.HTMLReport, "html-report", opts.HTMLReport, "Write a self-contained HTML run report (graph, per-release status and durations, failures, hook output) to this path")
 	cmd.Flags().Var(&validatedStringValue{dest: &opts.WSListenAddr, name: "--ws-listen", allowEmpty: true, validator: validateWSListenAddr}, "ws-listen", "Expose the stack run event stream over WebSocket at this address (e.g. :9090)")
 
 	// Minimal-flag UX: keep knobs configurable via stack.yaml/env; hide overrides but keep them working.
@@ -588,6 +762,10 @@ func addStackConsoleFlags(cmd *cobra.Command, opts *stackRunCLIOptions) {
 	cmd.Flags().StringVar(&opts.ConsoleFilter, "console-filter", opts.ConsoleFilter, "Filter nodes shown in console table: all|running|failed")
 	cmd.Flags().StringVar(&opts.ConsoleHelm, "console-helm", opts.ConsoleHelm, "Console HELM LOGS panel mode: off|on|all (capture is controlled by --helm-logs)")
 	cmd.Flags().Lookup("console-helm").NoOptDefVal = "on"
+	cmd.Flags().Var(newEnumStringValue(&opts.ConsoleGroup, "auto", "none", "group", "cluster"), "console-group", "Collapse the console node table into per-group summaries: auto|none|group|cluster (auto groups by execution group above 40 nodes)")
+	cmd.Flags().StringSliceVar(&opts.ExpandGroups, "expand-group", opts.ExpandGroups, "Show the nodes of these collapsed console groups (execution group number or cluster name; repeatable)")
+	cmd.Flags().BoolVar(&opts.ExpandFailed, "expand-failed", opts.ExpandFailed, "Show the nodes of every collapsed console group that has a failure")
+	cmd.Flags().BoolVar(&opts.NoteWrap, "note-wrap", opts.NoteWrap, "Wrap long console notes and failure messages over continuation lines instead of truncating them")
 
 	// Auto-responsive defaults are preferred; keep these as escape hatches but
 	// hide them to avoid bloating the primary UX.
@@ -599,6 +777,7 @@ func addStackConsoleFlags(cmd *cobra.Command, opts *stackRunCLIOptions) {
 	_ = cmd.Flags().MarkHidden("console-noisy-phases")
 	_ = cmd.Flags().MarkHidden("console-filter")
 	_ = cmd.Flags().MarkHidden("console-helm")
+	_ = cmd.Flags().MarkHidden("console-group")
 }
 
 func buildRunOptions(kind stackRunKind, common stackCommandCommon, plan *stack.Plan, opts stackRunCLIOptions, effective stack.RunnerResolved, adaptive *stack.AdaptiveConcurrencyOptions, secrets *deploy.SecretOptions) stack.RunOptions {
@@ -610,6 +789,21 @@ func buildRunOptions(kind stackRunKind, common stackCommandCommon, plan *stack.P
 	case "true", "1":
 		helmLogsMode = "on"
 	}
+	var gitMeta *deploy.GitMetadata
+	if kind == stackRunApply && !opts.NoGitMetadata && plan != nil {
+		gitMeta = deploy.DetectGitMetadata(plan.StackRoot)
+	}
+	preflight := stack.DefaultClusterHealthOptions()
+	if effective.Preflight != nil {
+		preflight = *effective.Preflight
+	}
+	switch opts.ClusterPreflight {
+	case "off":
+		preflight.Enabled = false
+	case "deny", "skip":
+		preflight.Enabled = true
+		preflight.OnFailure = opts.ClusterPreflight
+	}
 	return stack.RunOptions{
 		Command:                    string(kind),
 		Plan:                       plan,
@@ -620,7 +814,11 @@ func buildRunOptions(kind stackRunKind, common stackCommandCommon, plan *stack.P
 		DryRun:                     kind == stackRunApply && opts.DryRun,
 		Diff:                       kind == stackRunApply && opts.Diff,
 		CacheApply:                 kind == stackRunApply && opts.CacheApply,
+		SkipUnchanged:              kind == stackRunApply && opts.SkipUnchanged,
+		ChartCacheDir:              strings.TrimSpace(opts.ChartCacheDir),
 		Secrets:                    secrets,
+		GitMetadata:                gitMeta,
+		ApprovedRisks:              opts.ApproveRisks,
 		HelmLogs:                   helmLogsMode != "off",
 		KubeQPS:                    effective.KubeQPS,
 		KubeBurst:                  effective.KubeBurst,
@@ -628,6 +826,7 @@ func buildRunOptions(kind stackRunKind, common stackCommandCommon, plan *stack.P
 		MaxConcurrencyByKind:       effective.Limits.MaxParallelKind,
 		ParallelismGroupLimit:      effective.Limits.ParallelismGroupLimit,
 		Adaptive:                   adaptive,
+		Flaky:                      effective.Flaky,
 		Lock:                       opts.Lock,
 		LockOwner:                  opts.LockOwner,
 		LockTTL:                    opts.LockTTL,
@@ -639,7 +838,10 @@ func buildRunOptions(kind stackRunKind, common stackCommandCommon, plan *stack.P
 		RunID:                      strings.TrimSpace(opts.RunID),
 		FailMode:                   chooseFailMode(failFast),
 		MaxAttempts:                maxAttemptsFromRetry(opts.Retry),
+		MaxFailures:                effective.MaxFailures,
+		FailureDomain:              effective.FailureDomain,
 		Selector:                   buildRunSelector(common),
+		ClusterPreflight:           preflight,
 	}
 }
 
@@ -657,3 +859,19 @@ func buildRunSelector(common stackCommandCommon) stack.RunSelector {
 		AllowMissingDeps:     *common.allowMissingDeps,
 	}
 }
+
+// writeRunReportFile writes a run report even when the run failed, so CI can show what failed.
+func writeRunReportFile(path, flag string, write func(io.Writer) error) error {
+	f, err := os.Create(strings.TrimSpace(path))
+	if err != nil {
+		return fmt.Errorf("%s: %w", flag, err)
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%s: write %s: %w", flag, path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("%s: %w", flag, err)
+	}
+	return nil
+}
@@ -22,6 +22,8 @@ const (
 	stackFlagAdaptiveRampSuccesses   = "adaptive-ramp-successes"
 	stackFlagAdaptiveRampFailureRate = "adaptive-ramp-max-failure-rate"
 	stackFlagAdaptiveCooldownSevere  = "adaptive-cooldown-severe"
+	stackFlagMaxFailures             = "max-failures"
+	stackFlagFailureDomain           = "failure-domain"
 )
 
 func parseMaxParallelKind(args []string) (map[string]int, error) {
@@ -67,6 +69,9 @@ type stackRunnerOverrides struct {
 	AdaptiveRampSuccesses   int
 	AdaptiveRampFailureRate float64
 	AdaptiveCooldownSevere  int
+
+	MaxFailures   int
+	FailureDomain string
 }
 
 func resolveRunnerFromFlags(cmd *cobra.Command, base stack.RunnerResolved, overrides stackRunnerOverrides) (stack.RunnerResolved, *stack.AdaptiveConcurrencyOptions, error) {
@@ -111,6 +116,12 @@ func resolveRunnerFromFlags(cmd *cobra.Command, base stack.RunnerResolved, overr
 	if cmd.Flags().Changed(stackFlagAdaptiveCooldownSevere) {
 		effective.Adaptive.CooldownSevere = overrides.AdaptiveCooldownSevere
 	}
+	if cmd.Flags().Changed(stackFlagMaxFailures) {
+		effective.MaxFailures = overrides.MaxFailures
+	}
+	if cmd.Flags().Changed(stackFlagFailureDomain) {
+		effective.FailureDomain = overrides.FailureDomain
+	}
 	if err := stack.ValidateRunnerResolved(effective); err != nil {
 		return stack.RunnerResolved{}, nil, err
 	}
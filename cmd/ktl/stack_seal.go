@@ -59,7 +59,8 @@ func newStackSealCommand(rootDir, profile *string, clusters *[]string, inferDeps
 			if err != nil {
 				return err
 			}
-			p, err := stack.Compile(u, stack.CompileOptions{Profile: *profile})
+			overlays, _ := cmd.Flags().GetStringSlice("overlay")
+			p, err := stack.Compile(u, stack.CompileOptions{Profile: *profile, Overlays: overlays})
 			if err != nil {
 				return err
 			}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/kubekattle/ktl/internal/deploy"
 	"github.com/kubekattle/ktl/internal/secretstore"
+	"github.com/kubekattle/ktl/internal/valuesource"
 )
 
 func buildStackSecretOptions(ctx context.Context, root string, secretProvider string, secretConfig string, errOut io.Writer) (*deploy.SecretOptions, error) {
@@ -23,5 +24,9 @@ func buildStackSecretOptions(ctx context.Context, root string, secretProvider st
 	if err != nil {
 		return nil, err
 	}
-	return &deploy.SecretOptions{Resolver: resolver, AuditSink: auditSink}, nil
+	valueSources, err := valuesource.LoadFromApp(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	return &deploy.SecretOptions{Resolver: resolver, AuditSink: auditSink, ValueSources: valueSources}, nil
 }
@@ -0,0 +1,183 @@
+// File: cmd/ktl/sync.go
+// Brief: CLI command wiring and implementation for 'sync'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubekattle/ktl/internal/devsync"
+	"github.com/kubekattle/ktl/internal/kube"
+)
+
+type syncOptions struct {
+	release   string
+	workload  string
+	namespace string
+	selector  string
+	container string
+	localDir  string
+	remoteDir string
+	excludes  []string
+	reloadCmd string
+	interval  time.Duration
+	once      bool
+	noInitial bool
+}
+
+func newSyncCommand(kubeconfig, kubeContext *string) *cobra.Command {
+	opts := syncOptions{
+		localDir: ".",
+		interval: 500 * time.Millisecond,
+	}
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Sync local files into running pods for hot reload",
+		Long: `Watch a local directory and copy changed files into the matching pods via exec+tar.
+
+Pods are selected by Helm release (app.kubernetes.io/instance) and/or --selector. The remote
+container must provide tar (and sh when --reload-cmd is set). Pods that start later, or whose
+container restarts, get the full tree before they receive further changes.`,
+		Example: `  # Mirror ./src into /app/src for every pod in release foo
+  ktl sync --release foo --local ./src --remote /app/src
+
+  # Restart the interpreter after each change
+  ktl sync --release foo --local ./src --remote /app/src --reload-cmd 'kill -HUP 1'
+
+  # One-shot copy, skipping caches
+  ktl sync --release foo --local ./src --remote /app/src --once --exclude '__pycache__'`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runSync(cmd, kubeconfig, kubeContext, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.release, "release", "", "Helm release whose pods receive files (matches app.kubernetes.io/instance)")
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "Namespace of the target pods (defaults to the kubeconfig namespace)")
+	cmd.Flags().StringVarP(&opts.selector, "selector", "l", "", "Additional label selector for target pods")
+	cmd.Flags().StringVar(&opts.workload, "for", "", "Target the pods of a workload (e.g. deployment/checkout, statefulset/db)")
+	cmd.Flags().StringVarP(&opts.container, "container", "c", "", "Container to sync into (defaults to the first container)")
+	cmd.Flags().StringVar(&opts.localDir, "local", opts.localDir, "Local directory to watch")
+	cmd.Flags().StringVar(&opts.remoteDir, "remote", "", "Destination directory inside the container")
+	cmd.Flags().StringArrayVar(&opts.excludes, "exclude", nil, "Exclude paths matching this .dockerignore-style pattern (repeatable)")
+	cmd.Flags().StringVar(&opts.reloadCmd, "reload-cmd", "", "Shell command to run in each container after files change")
+	cmd.Flags().DurationVar(&opts.interval, "interval", opts.interval, "Polling interval for local changes")
+	cmd.Flags().BoolVar(&opts.once, "once", false, "Copy the full tree once and exit")
+	cmd.Flags().BoolVar(&opts.noInitial, "no-initial-sync", false, "Skip the initial full copy to the pods running at startup and only sync subsequent changes")
+	decorateCommandHelp(cmd, "Sync Flags")
+	return cmd
+}
+
+func runSync(cmd *cobra.Command, kubeconfig, kubeContext *string, opts syncOptions) error {
+	if strings.TrimSpace(opts.release) == "" && strings.TrimSpace(opts.selector) == "" && strings.TrimSpace(opts.workload) == "" {
+		return errors.New("--release, --selector, or --for is required")
+	}
+	if strings.TrimSpace(opts.remoteDir) == "" {
+		return errors.New("--remote is required")
+	}
+	if info, err := os.Stat(opts.localDir); err != nil {
+		return fmt.Errorf("--local: %w", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("--local %s is not a directory", opts.localDir)
+	}
+	if opts.once && opts.noInitial {
+		return errors.New("--once cannot be combined with --no-initial-sync")
+	}
+
+	ctx := cmd.Context()
+	var kc, kctx string
+	if kubeconfig != nil {
+		kc = *kubeconfig
+	}
+	if kubeContext != nil {
+		kctx = *kubeContext
+	}
+	client, err := kube.New(ctx, kc, kctx)
+	if err != nil {
+		return err
+	}
+	namespace := strings.TrimSpace(opts.namespace)
+	if namespace == "" {
+		namespace = client.Namespace
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	selector, err := podSelectorFor(ctx, client.Clientset, namespace, opts.release, opts.selector, opts.workload)
+	if err != nil {
+		return err
+	}
+	resolve := func(ctx context.Context) ([]devsync.Target, error) {
+		return devsync.ResolveTargets(ctx, client.Clientset, namespace, selector, opts.container)
+	}
+
+	syncer := devsync.New(client, devsync.Options{
+		LocalDir:  opts.localDir,
+		RemoteDir: opts.remoteDir,
+		Excludes:  opts.excludes,
+		ReloadCmd: opts.reloadCmd,
+		Interval:  opts.interval,
+		Out:       cmd.ErrOrStderr(),
+	})
+
+	if opts.once {
+		snap, err := devsync.Scan(opts.localDir, opts.excludes)
+		if err != nil {
+			return err
+		}
+		targets, err := resolve(ctx)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("no running pods match %q in namespace %s", selector, namespace)
+		}
+		return syncer.Apply(ctx, targets, devsync.Diff(nil, snap))
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Watching %s -> %s (pods: %s in %s). Press Ctrl+C to stop.\n", opts.localDir, opts.remoteDir, selector, namespace)
+	if err := syncer.Watch(ctx, resolve, !opts.noInitial); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+func syncSelector(release, selector string) string {
+	var parts []string
+	if r := strings.TrimSpace(release); r != "" {
+		parts = append(parts, "app.kubernetes.io/instance="+r)
+	}
+	if s := strings.TrimSpace(selector); s != "" {
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ",")
+}
+
+// podSelectorFor combines --release/--selector with the pod selector of a --for workload,
+// which is read from the workload object so it keeps matching pods across rollouts.
+func podSelectorFor(ctx context.Context, client kubernetes.Interface, namespace, release, selector, workload string) (string, error) {
+	base := syncSelector(release, selector)
+	if strings.TrimSpace(workload) == "" {
+		return base, nil
+	}
+	ref, err := kube.ParseWorkloadRef(workload)
+	if err != nil {
+		return "", err
+	}
+	sel, err := kube.WorkloadSelector(ctx, client, namespace, ref)
+	if err != nil {
+		return "", err
+	}
+	return kube.CombineSelectors(sel.String(), base), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/config"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodSelectorForWorkload(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "prod"},
+			Spec: appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "checkout"},
+			}},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "prod"},
+			Spec: appsv1.StatefulSetSpec{Selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"db"}}},
+			}},
+		},
+	)
+	ctx := context.Background()
+
+	got, err := podSelectorFor(ctx, client, "prod", "foo", "", "deploy/checkout")
+	if err != nil {
+		t.Fatalf("deployment: %v", err)
+	}
+	if got != "app=checkout,app.kubernetes.io/instance=foo" {
+		t.Fatalf("unexpected deployment selector %q", got)
+	}
+	got, err = podSelectorFor(ctx, client, "prod", "", "", "sts/db")
+	if err != nil {
+		t.Fatalf("statefulset: %v", err)
+	}
+	if got != "tier in (db)" {
+		t.Fatalf("unexpected statefulset selector %q", got)
+	}
+	if got, err := podSelectorFor(ctx, client, "prod", "foo", "", ""); err != nil || got != "app.kubernetes.io/instance=foo" {
+		t.Fatalf("expected plain release selector, got %q (%v)", got, err)
+	}
+	if _, err := podSelectorFor(ctx, client, "prod", "", "", "deployment/missing"); err == nil {
+		t.Fatalf("expected error for missing workload")
+	}
+	if _, err := podSelectorFor(ctx, client, "prod", "", "", "cronjob/nightly"); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Fatalf("expected unsupported kind error, got %v", err)
+	}
+}
+
+func TestApplyWorkloadSelectorRequiresSingleNamespace(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	opts := config.NewOptions()
+	opts.AllNamespaces = true
+	var out strings.Builder
+	if err := applyWorkloadSelector(context.Background(), &out, client, opts, "deployment/checkout", nil); err == nil {
+		t.Fatalf("expected namespace error")
+	}
+}
@@ -0,0 +1,165 @@
+// File: cmd/ktl/telemetry.go
+// Brief: CLI command wiring and implementation for 'telemetry'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/telemetry"
+	"github.com/kubekattle/ktl/internal/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// recordUsage reports executed to the telemetry endpoint when the user opted in. Errors are
+// dropped: telemetry must never change what the command printed or its exit status.
+func recordUsage(executed *cobra.Command, startedAt time.Time, runErr error) {
+	if executed == nil || executed.Hidden || strings.HasPrefix(executed.Name(), "__") {
+		return
+	}
+	if runErr != nil && errors.Is(runErr, pflag.ErrHelp) {
+		return
+	}
+	path, err := telemetry.UsageSettingsPath()
+	if err != nil {
+		return
+	}
+	status, err := telemetry.ResolveUsage(path, nil)
+	if err != nil || !status.Enabled {
+		return
+	}
+	result, class := usageResult(runErr)
+	ev := telemetry.NewUsageEvent(executed.CommandPath(), time.Since(startedAt), result, class, version.Get().Version)
+	_ = telemetry.SendUsage(context.Background(), status.Endpoint, ev)
+}
+
+// usageResult maps an error onto a coarse class; error messages are never sent.
+func usageResult(err error) (string, string) {
+	switch {
+	case err == nil:
+		return "success", ""
+	case errors.Is(err, context.Canceled):
+		return "cancelled", ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return "failure", "timeout"
+	case apierrors.IsUnauthorized(err):
+		return "failure", "unauthorized"
+	case apierrors.IsForbidden(err):
+		return "failure", "forbidden"
+	case apierrors.IsNotFound(err):
+		return "failure", "not_found"
+	case apierrors.IsConflict(err):
+		return "failure", "conflict"
+	case apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err):
+		return "failure", "timeout"
+	default:
+		return "failure", "other"
+	}
+}
+
+func newTelemetryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage opt-in anonymous usage metrics",
+		Long: `ktl can report anonymous usage metrics so maintainers can prioritize slow paths. It is off
+until you run ktl telemetry on. Each command sends one JSON event with the command path (for
+example "ktl apply"), duration, result (success/failure/cancelled), a coarse error class, and the
+ktl version and OS/architecture. Arguments, flag values, names, hosts, and clusters are never sent.
+
+Environment:
+  DO_NOT_TRACK=1          always disables telemetry
+  KTL_TELEMETRY=on|off    overrides the saved choice
+  KTL_TELEMETRY_ENDPOINT  overrides the saved endpoint`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newTelemetryStatusCommand(), newTelemetryToggleCommand(true), newTelemetryToggleCommand(false))
+	return cmd
+}
+
+func newTelemetryStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:           "status",
+		Short:         "Show whether usage metrics are sent and where",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			path, err := telemetry.UsageSettingsPath()
+			if err != nil {
+				return err
+			}
+			status, err := telemetry.ResolveUsage(path, nil)
+			if err != nil {
+				return err
+			}
+			writeTelemetryStatus(cmd.OutOrStdout(), status)
+			return nil
+		},
+	}
+}
+
+func writeTelemetryStatus(w io.Writer, status telemetry.UsageStatus) {
+	state := "off"
+	if status.Enabled {
+		state = "on"
+	}
+	fmt.Fprintf(w, "Telemetry: %s\n", state)
+	fmt.Fprintf(w, "Reason:    %s\n", status.Reason)
+	fmt.Fprintf(w, "Endpoint:  %s\n", dashIfEmpty(status.Endpoint))
+	fmt.Fprintf(w, "Settings:  %s\n", status.Path)
+}
+
+func newTelemetryToggleCommand(enable bool) *cobra.Command {
+	var endpoint string
+	use, short := "off", "Stop sending usage metrics"
+	if enable {
+		use, short = "on", "Opt in to anonymous usage metrics"
+	}
+	cmd := &cobra.Command{
+		Use:           use,
+		Short:         short,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			path, err := telemetry.UsageSettingsPath()
+			if err != nil {
+				return err
+			}
+			settings, err := telemetry.LoadUsageSettings(path)
+			if err != nil {
+				return err
+			}
+			settings.Enabled = enable
+			if strings.TrimSpace(endpoint) != "" {
+				settings.Endpoint = strings.TrimSpace(endpoint)
+			}
+			if err := telemetry.SaveUsageSettings(path, settings); err != nil {
+				return err
+			}
+			status, err := telemetry.ResolveUsage(path, nil)
+			if err != nil {
+				return err
+			}
+			writeTelemetryStatus(cmd.OutOrStdout(), status)
+			return nil
+		},
+	}
+	if enable {
+		cmd.Flags().StringVar(&endpoint, "endpoint", "", "URL that receives usage events as JSON POSTs")
+		decorateCommandHelp(cmd, "Telemetry Flags")
+	}
+	return cmd
+}
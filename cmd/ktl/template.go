@@ -0,0 +1,232 @@
+// File: cmd/ktl/template.go
+// Brief: CLI command wiring and implementation for 'template'.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/secretstore"
+	"github.com/kubekattle/ktl/internal/valuesource"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+func newTemplateCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
+	var chart string
+	var release string
+	var version string
+	var namespace string
+	var valuesFiles []string
+	var setValues []string
+	var setStringValues []string
+	var setFileValues []string
+	var setJSONValues []string
+	var setLiteralValues []string
+	var secretProvider string
+	var secretConfig string
+	var includeCRDs bool
+	var skipHooks bool
+	var useCluster bool
+	var outputFile string
+	var outputDir string
+	var splitByKind bool
+	var postRender postRenderFlags
+
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Render a chart locally, including post-renderers, without touching the cluster",
+		Long: `Render the chart like helm template and print the manifests. The deploy.postRenderers from
+.ktl.yaml (executables, kustomize patches, image rewrites, and metadata injection) run over
+the output exactly as they do for ktl apply plan and ktl apply, so what you preview here is
+what ships. As with Helm, post-renderers do not see hook resources.
+
+Rendering is offline by default. Use --use-cluster to render against the cluster's API versions.`,
+		Example: `  # Print the manifests
+  ktl template --chart ./chart --release web -n prod -f values/prod.yaml
+
+  # One file per kind, for review or GitOps
+  ktl template --chart ./chart --release web --output-dir out/ --split-by-kind
+
+  # Add a one-off post-renderer after the ones from .ktl.yaml
+  ktl template --chart ./chart --release web --post-renderer ./hack/inject-sidecar.sh`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if strings.TrimSpace(chart) == "" {
+				return fmt.Errorf("--chart is required")
+			}
+			if strings.TrimSpace(release) == "" {
+				return fmt.Errorf("--release is required")
+			}
+			if outputFile != "" && outputDir != "" {
+				return fmt.Errorf("--output-file and --output-dir are mutually exclusive")
+			}
+			if splitByKind && outputDir == "" {
+				return fmt.Errorf("--split-by-kind requires --output-dir")
+			}
+			settings := cli.New()
+			if kc := derefString(kubeconfig); kc != "" {
+				settings.KubeConfig = kc
+			}
+			if kctx := derefString(kubeContext); kctx != "" {
+				settings.KubeContext = kctx
+			}
+			if namespace == "" {
+				namespace = "default"
+			}
+			settings.SetNamespace(namespace)
+
+			postRenderer, err := loadPostRenderer(ctx, settings, postRender)
+			if err != nil {
+				return err
+			}
+			actionCfg := new(action.Configuration)
+			if err := actionCfg.Init(settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
+				return fmt.Errorf("init helm action config: %w", err)
+			}
+			secretResolver, secretAuditSink, err := buildDeploySecretResolver(ctx, deploySecretConfig{
+				Chart:      chart,
+				ConfigPath: secretConfig,
+				Provider:   secretProvider,
+				Mode:       secretstore.ResolveModeMask,
+				ErrOut:     cmd.ErrOrStderr(),
+			})
+			if err != nil {
+				return err
+			}
+			valueSources, err := valuesource.LoadFromApp(ctx, chart)
+			if err != nil {
+				return err
+			}
+			secretOptions := &deploy.SecretOptions{Resolver: secretResolver, AuditSink: secretAuditSink, ValueSources: valueSources, Mask: true}
+			resolvedValues, cleanupValues, err := resolveValuesFlag(cmd, settings, valuesFiles, secretOptions)
+			if err != nil {
+				return err
+			}
+			defer cleanupValues()
+			rendered, err := deploy.RenderTemplate(ctx, actionCfg, settings, deploy.TemplateOptions{
+				Chart:            chart,
+				Version:          version,
+				ReleaseName:      release,
+				Namespace:        namespace,
+				ValuesFiles:      resolvedValues,
+				SetValues:        setValues,
+				SetStringValues:  setStringValues,
+				SetFileValues:    setFileValues,
+				SetJSONValues:    setJSONValues,
+				SetLiteralValues: setLiteralValues,
+				Secrets:          secretOptions,
+				IncludeCRDs:      includeCRDs,
+				UseCluster:       useCluster,
+				PostRenderer:     postRenderer,
+			})
+			if err != nil {
+				return err
+			}
+			printImageReport(cmd.ErrOrStderr(), postRender, postRenderer)
+			manifest := strings.TrimSpace(rendered.Manifest)
+			if !skipHooks && strings.TrimSpace(rendered.HookManifest) != "" {
+				manifest = strings.TrimSpace(manifest + "\n" + rendered.HookManifest)
+			}
+
+			switch {
+			case outputDir != "":
+				files, err := writeTemplateDir(outputDir, release, manifest, splitByKind)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %d file(s) for release %s to %s\n", files, release, outputDir)
+				return nil
+			case outputFile != "" && outputFile != "-":
+				if err := os.WriteFile(outputFile, []byte(manifest+"\n"), 0o644); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Wrote manifests for release %s to %s\n", release, outputFile)
+				return nil
+			default:
+				return writeTemplateManifest(cmd.OutOrStdout(), manifest)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&chart, "chart", "", "Chart reference (path, repo/name, or OCI ref)")
+	cmd.Flags().StringVar(&release, "release", "", "Helm release name")
+	cmd.Flags().StringVar(&version, "version", "", "Chart version (default: latest)")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace for the Helm release (default: default)")
+	cmd.Flags().StringSliceVarP(&valuesFiles, "values", "f", nil, "Values files to render with (can be repeated; - reads stdin, https:// and oci:// are fetched, append #sha256=<hex> to pin)")
+	cmd.Flags().StringArrayVar(&setValues, "set", nil, "Set values on the command line (key=val)")
+	cmd.Flags().StringArrayVar(&setStringValues, "set-string", nil, "Set STRING values on the command line")
+	cmd.Flags().StringArrayVar(&setFileValues, "set-file", nil, "Set values from files (key=path)")
+	cmd.Flags().StringArrayVar(&setJSONValues, "set-json", nil, "Set JSON values on the command line (key=<json>)")
+	cmd.Flags().StringArrayVar(&setLiteralValues, "set-literal", nil, "Set a literal STRING value on the command line (no escaping or list parsing)")
+	cmd.Flags().StringVar(&secretProvider, "secret-provider", "", "Secret provider name for secret:// references")
+	cmd.Flags().StringVar(&secretConfig, "secret-config", "", "Secrets provider config file (defaults to ~/.ktl/config.yaml and repo .ktl.yaml)")
+	cmd.Flags().BoolVar(&includeCRDs, "include-crds", false, "Include chart CRDs (crds/ directory) in the output")
+	cmd.Flags().BoolVar(&skipHooks, "no-hooks", false, "Omit hook resources from the output")
+	cmd.Flags().BoolVar(&useCluster, "use-cluster", false, "Render against the cluster's API versions and capabilities")
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "Write the manifests to this file instead of stdout")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Write the manifests to <release>.yaml in this directory")
+	cmd.Flags().BoolVar(&splitByKind, "split-by-kind", false, "With --output-dir, write one <kind>.yaml file per resource kind")
+	addPostRenderFlags(cmd, &postRender)
+	decorateCommandHelp(cmd, "Template Flags")
+	return cmd
+}
+
+func writeTemplateManifest(w io.Writer, manifest string) error {
+	if manifest == "" {
+		return nil
+	}
+	if !strings.HasPrefix(manifest, "---") {
+		manifest = "---\n" + manifest
+	}
+	_, err := fmt.Fprintln(w, manifest)
+	return err
+}
+
+// writeTemplateDir writes the manifest to dir, one file per kind when split is set, and returns
+// the number of files written.
+func writeTemplateDir(dir, release, manifest string, split bool) (int, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, err
+	}
+	if !split {
+		var b strings.Builder
+		if err := writeTemplateManifest(&b, manifest); err != nil {
+			return 0, err
+		}
+		return 1, os.WriteFile(filepath.Join(dir, release+".yaml"), []byte(b.String()), 0o644)
+	}
+	byKind := map[string][]string{}
+	for _, doc := range parseManifestDocs(manifest) {
+		kind := strings.ToLower(doc.Key.Kind)
+		if kind == "" {
+			kind = "unknown"
+		}
+		byKind[kind] = append(byKind[kind], doc.Body)
+	}
+	kinds := make([]string, 0, len(byKind))
+	for kind := range byKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		var b strings.Builder
+		for _, body := range byKind[kind] {
+			fmt.Fprintf(&b, "---\n%s\n", strings.TrimSpace(body))
+		}
+		if err := os.WriteFile(filepath.Join(dir, kind+".yaml"), []byte(b.String()), 0o644); err != nil {
+			return 0, err
+		}
+	}
+	return len(kinds), nil
+}
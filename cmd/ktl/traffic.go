@@ -0,0 +1,215 @@
+// File: cmd/ktl/traffic.go
+// Brief: CLI command wiring and implementation for 'traffic'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kubekattle/ktl/internal/capture"
+	"github.com/kubekattle/ktl/internal/caststream"
+	"github.com/kubekattle/ktl/internal/castutil"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/traffic"
+)
+
+type trafficTapOptions struct {
+	release   string
+	workload  string
+	namespace string
+	selector  string
+	port      string
+	listen    string
+	capture   string
+	uiAddr    string
+	quiet     bool
+}
+
+func newTrafficCommand(kubeconfig, kubeContext *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "traffic",
+		Short: "Inspect HTTP traffic flowing into a release",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newTrafficTapCommand(kubeconfig, kubeContext))
+	return cmd
+}
+
+func newTrafficTapCommand(kubeconfig, kubeContext *string) *cobra.Command {
+	opts := trafficTapOptions{listen: "127.0.0.1:8081"}
+	cmd := &cobra.Command{
+		Use:   "tap [POD_QUERY]",
+		Short: "Port-forward to a release through a recording HTTP proxy",
+		Long: `Port-forward to a pod of a release through a local recording proxy.
+
+Point clients at the --listen address; every request/response pair is forwarded to the pod and its
+metadata (method, path, status, latency, sizes, headers) is printed, optionally stored in a capture
+DB (--capture), and streamed to a timeline UI (--ui). Bodies are never recorded and credential
+headers are redacted.`,
+		Example: `  # Tap the http port of release foo and print each request
+  ktl traffic tap --release foo -n prod --port http
+
+  # Record into a capture DB and watch the timeline in a browser
+  ktl traffic tap --release foo --port 8080 --capture tap.sqlite --ui :8080`,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := ""
+			if len(args) == 1 {
+				query = args[0]
+			}
+			return runTrafficTap(cmd, kubeconfig, kubeContext, query, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.release, "release", "", "Select pods of this Helm release (matches app.kubernetes.io/instance)")
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "Namespace of the target pod (defaults to the kubeconfig namespace)")
+	cmd.Flags().StringVarP(&opts.selector, "selector", "l", "", "Additional label selector for candidate pods")
+	cmd.Flags().StringVar(&opts.workload, "for", "", "Select pods of a workload (e.g. deployment/checkout, statefulset/db)")
+	cmd.Flags().StringVar(&opts.port, "port", "", "Container port to tap (name or number)")
+	cmd.Flags().StringVar(&opts.listen, "listen", opts.listen, "Local address for the recording proxy")
+	cmd.Flags().StringVar(&opts.capture, "capture", "", "Store exchange metadata in this capture SQLite DB")
+	cmd.Flags().StringVar(&opts.uiAddr, "ui", "", "Serve the traffic timeline UI on this address (e.g. :8080)")
+	cmd.Flags().Lookup("ui").NoOptDefVal = ":8080"
+	cmd.Flags().BoolVar(&opts.quiet, "quiet", false, "Do not print each exchange to stderr")
+	_ = cmd.MarkFlagRequired("port")
+	decorateCommandHelp(cmd, "Traffic Flags")
+	return cmd
+}
+
+func runTrafficTap(cmd *cobra.Command, kubeconfig, kubeContext *string, query string, opts trafficTapOptions) error {
+	if strings.TrimSpace(opts.release) == "" && strings.TrimSpace(opts.selector) == "" && strings.TrimSpace(opts.workload) == "" && strings.TrimSpace(query) == "" {
+		return errors.New("select a pod with POD_QUERY, --release, --selector, or --for")
+	}
+	ctx := cmd.Context()
+	var kc, kctx string
+	if kubeconfig != nil {
+		kc = *kubeconfig
+	}
+	if kubeContext != nil {
+		kctx = *kubeContext
+	}
+	client, err := kube.New(ctx, kc, kctx)
+	if err != nil {
+		return err
+	}
+	namespace := strings.TrimSpace(opts.namespace)
+	if namespace == "" {
+		namespace = client.Namespace
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	errOut := cmd.ErrOrStderr()
+
+	selector, err := podSelectorFor(ctx, client.Clientset, namespace, opts.release, opts.selector, opts.workload)
+	if err != nil {
+		return err
+	}
+	pod, matched, err := selectDebugPod(ctx, client.Clientset, namespace, selector, query)
+	if err != nil {
+		return err
+	}
+	if matched > 1 {
+		fmt.Fprintf(errOut, "%d pods matched; tapping %s\n", matched, pod.Name)
+	}
+	remotePort, err := kube.ResolveContainerPort(pod, strings.TrimSpace(opts.port))
+	if err != nil {
+		return err
+	}
+	label := fmt.Sprintf("%s/%s:%d", namespace, pod.Name, remotePort)
+
+	var observers []traffic.Observer
+	if !opts.quiet {
+		observers = append(observers, traffic.ObserverFunc(func(ex traffic.Exchange) {
+			fmt.Fprintf(errOut, "%s %s\n", ex.Start.Local().Format("15:04:05"), ex.Summary())
+		}))
+	}
+	if path := strings.TrimSpace(opts.capture); path != "" {
+		host, _ := os.Hostname()
+		rec, err := capture.Open(path, capture.SessionMeta{
+			Command: "ktl traffic tap",
+			Args:    os.Args[1:],
+			Host:    host,
+			Entities: capture.Entities{
+				KubeContext: kctx,
+				Namespace:   namespace,
+				Release:     strings.TrimSpace(opts.release),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := rec.Close(); err != nil {
+				fmt.Fprintf(errOut, "warning: close capture: %v\n", err)
+				return
+			}
+			fmt.Fprintf(errOut, "Capture written to %s\n", path)
+		}()
+		observers = append(observers, rec)
+	}
+	if addr := strings.TrimSpace(opts.uiAddr); addr != "" {
+		logger, err := buildLogger("info")
+		if err != nil {
+			return err
+		}
+		uiServer := caststream.New(addr, caststream.ModeWeb, label, logger.WithName("traffic-ui"), caststream.WithTrafficUI())
+		if err := castutil.StartCastServer(ctx, uiServer, "ktl traffic timeline", logger.WithName("traffic-ui"), errOut); err != nil {
+			return err
+		}
+		fmt.Fprintf(errOut, "Serving traffic timeline on %s\n", addr)
+		observers = append(observers, uiServer)
+	}
+
+	pf, err := client.StartPortForward(ctx, namespace, pod.Name, 0, remotePort)
+	if err != nil {
+		return fmt.Errorf("port-forward %s: %w", label, err)
+	}
+	defer func() { _ = pf.Close() }()
+	proxy, err := traffic.NewProxy(fmt.Sprintf("http://127.0.0.1:%d", pf.LocalPort), label, observers...)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", opts.listen)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", opts.listen, err)
+	}
+	srv := &http.Server{Handler: proxy, ReadHeaderTimeout: 10 * time.Second}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(ln) }()
+	fmt.Fprintf(errOut, "Tapping %s via http://%s (Ctrl+C to stop)\n", label, ln.Addr())
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+	case err := <-pf.Done():
+		if err == nil {
+			err = errors.New("port-forward closed")
+		}
+		runErr = fmt.Errorf("port-forward %s: %w", label, err)
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			runErr = err
+		}
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = srv.Shutdown(shutdownCtx)
+	return runErr
+}
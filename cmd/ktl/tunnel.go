@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/kubekattle/ktl/internal/devsync"
 	"github.com/kubekattle/ktl/internal/kube"
 	"github.com/kubekattle/ktl/internal/stack"
 	"github.com/spf13/cobra"
@@ -52,6 +53,9 @@ type Tunnel struct {
 	ListenAddr  string `json:"listenAddr"`
 	KubeContext string `json:"kubeContext"`
 	Health      string `json:"health"`
+	// Workload is set for --for targets; a running pod is picked from its selector on every
+	// (re)connect.
+	Workload *kube.WorkloadRef `json:"workload,omitempty"`
 
 	// Chaos
 	Latency   time.Duration `json:"latency"`
@@ -123,6 +127,7 @@ func newTunnelCommand(kubeconfig, kubeContext *string) *cobra.Command {
 	var stackConfig string
 	var latency time.Duration
 	var errorRate float64
+	var workloads []string
 	cmd := &cobra.Command{
 		Use:   "tunnel [SERVICE_OR_POD...]",
 		Short: "Smart, resilient port-forwarding for multiple services",
@@ -133,9 +138,10 @@ Examples:
   ktl tunnel app --hosts         # Add 'app.local' to /etc/hosts (requires sudo)
   ktl tunnel db --exec "npm run migrate"  # Run script when tunnel is ready
   ktl tunnel db --env-from deployment/app --exec "go run ." # Run local app with remote env
-  ktl tunnel app --web           # Start web dashboard`,
+  ktl tunnel app --web           # Start web dashboard
+  ktl tunnel --for deployment/checkout  # Forward to a running pod of a workload`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runTunnel(cmd.Context(), kubeconfig, kubeContext, namespace, args, share, deps, hosts, execCmd, envFrom, web, stackConfig, latency, errorRate)
+			return runTunnel(cmd.Context(), kubeconfig, kubeContext, namespace, args, workloads, share, deps, hosts, execCmd, envFrom, web, stackConfig, latency, errorRate)
 		},
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Kubernetes namespace")
@@ -148,12 +154,13 @@ Examples:
 	cmd.Flags().StringVar(&stackConfig, "config", "", "Path to stack.yaml (used with --deps)")
 	cmd.Flags().DurationVar(&latency, "latency", 0, "Inject artificial latency (e.g. 500ms)")
 	cmd.Flags().Float64Var(&errorRate, "error-rate", 0, "Inject artificial errors (0.0 - 1.0)")
+	cmd.Flags().StringArrayVar(&workloads, "for", nil, "Forward to a running pod of a workload (e.g. deployment/checkout, statefulset/db; repeatable)")
 
 	cmd.AddCommand(newTunnelSaveCommand())
 	cmd.AddCommand(newTunnelListCommand())
 	cmd.AddCommand(newTunnelReverseCommand(kubeconfig, kubeContext))
 	cmd.AddCommand(newTunnelInterceptCommand(kubeconfig, kubeContext))
-	cmd.AddCommand(newSyncCommand(kubeconfig, kubeContext))
+	cmd.AddCommand(newTunnelSyncCommand(kubeconfig, kubeContext))
 	cmd.AddCommand(newShareCommand(kubeconfig, kubeContext))
 
 	return cmd
@@ -270,13 +277,14 @@ func runShare(ctx context.Context, kubeconfig, kubeContext *string, namespace st
 	return runReverseTunnel(ctx, kubeconfig, kubeContext, namespace, serviceName, localPort)
 }
 
-func newSyncCommand(kubeconfig, kubeContext *string) *cobra.Command {
+func newTunnelSyncCommand(kubeconfig, kubeContext *string) *cobra.Command {
 	var namespace string
+	var container string
 	cmd := &cobra.Command{
 		Use:   "sync [LOCAL_DIR] [POD_NAME:REMOTE_DIR]",
 		Short: "Live file synchronization to a pod (Hot Reload)",
 		Long: `Watches a local directory and synchronizes changes to a remote pod directory in real-time.
-Requires 'tar' to be available in the remote container.
+Requires 'tar' to be available in the remote container. Use 'ktl sync --release' to target every pod of a release.
 
 Example:
   ktl tunnel sync ./src my-app:/app/src`,
@@ -290,14 +298,15 @@ Example:
 			podName := remoteParts[0]
 			remoteDir := remoteParts[1]
 
-			return runSync(cmd.Context(), kubeconfig, kubeContext, namespace, localDir, podName, remoteDir)
+			return runTunnelSync(cmd.Context(), kubeconfig, kubeContext, namespace, container, localDir, podName, remoteDir)
 		},
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Kubernetes namespace")
+	cmd.Flags().StringVarP(&container, "container", "c", "", "Container to sync into (defaults to the first container)")
 	return cmd
 }
 
-func runSync(ctx context.Context, kubeconfig, kubeContext *string, namespace, localDir, podName, remoteDir string) error {
+func runTunnelSync(ctx context.Context, kubeconfig, kubeContext *string, namespace, container, localDir, podName, remoteDir string) error {
 	kClient, err := kube.New(ctx, *kubeconfig, *kubeContext)
 	if err != nil {
 		return err
@@ -310,92 +319,27 @@ func runSync(ctx context.Context, kubeconfig, kubeContext *string, namespace, lo
 	}
 
 	// Verify Pod exists
-	_, err = kClient.Clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	pod, err := kClient.Clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("pod %s not found: %w", podName, err)
 	}
-
-	fmt.Printf("Syncing %s -> %s:/%s/%s\n", localDir, namespace, podName, remoteDir)
-	fmt.Println("Performing initial sync...")
-
-	// Initial Sync using Tar
-	if err := syncDir(ctx, kClient, namespace, podName, localDir, remoteDir); err != nil {
-		return fmt.Errorf("initial sync failed: %w", err)
-	}
-	color.New(color.FgGreen).Println("Initial sync complete.")
-
-	// Watch Loop (Mock implementation since we lack fsnotify in go.mod,
-	// but we can implement a simple poller or just use this command for one-off sync for now.
-	// Actually, let's implement a simple 2-second poller for MVP).
-
-	fmt.Println("Watching for changes (polling every 2s)...")
-	lastMod := time.Now()
-
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			// Check if any file changed after lastMod
-			changed := false
-			filepath.Walk(localDir, func(path string, info os.FileInfo, err error) error {
-				if err != nil {
-					return nil
-				}
-				if !info.IsDir() && info.ModTime().After(lastMod) {
-					changed = true
-					return io.EOF // Stop walking
-				}
-				return nil
-			})
-
-			if changed {
-				fmt.Println("Change detected. Syncing...")
-				if err := syncDir(ctx, kClient, namespace, podName, localDir, remoteDir); err != nil {
-					color.New(color.FgRed).Printf("Sync failed: %v\n", err)
-				} else {
-					color.New(color.FgGreen).Println("Synced.")
-				}
-				lastMod = time.Now()
-			}
-		}
-	}
-}
-
-func syncDir(ctx context.Context, kClient *kube.Client, ns, pod, local, remote string) error {
-	// tar -czf - . | kubectl exec -i pod -- tar -xzf - -C remote
-
-	// Create Tar Pipe
-	r, w := io.Pipe()
-
-	go func() {
-		defer w.Close()
-		// Simple tar implementation using "os/exec" tar if available, or go archive/tar
-		// Let's use local tar command for speed/simplicity if on mac/linux
-		cmd := exec.Command("tar", "-czf", "-", "-C", local, ".")
-		cmd.Stdout = w
-		if err := cmd.Run(); err != nil {
-			// w.CloseWithError(err)
-		}
-	}()
-
-	// Exec Remote
-	// We need kClient.Exec logic.
-	// We haven't implemented a clean kClient.Exec helper yet in 'kube' package visible here?
-	// The `internal/kube/exec.go` exists. Let's check imports.
-	// `kube` package in `cmd/ktl/tunnel.go` refers to `internal/kube`.
-
-	// We need to implement Exec in `internal/kube` or just use `kubectl` exec wrapper for MVP.
-	// Using kubectl wrapper is safer for a quick feature.
-
-	cmd := exec.Command("kubectl", "exec", "-i", "-n", ns, pod, "--", "tar", "-xzf", "-", "-C", remote)
-	cmd.Stdin = r
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("kubectl exec failed: %v, output: %s", err, string(out))
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+	target := devsync.Target{Namespace: namespace, Pod: podName, Container: container, OS: kube.ResolvePodOS(ctx, kClient.Clientset, pod)}
+
+	fmt.Printf("Syncing %s -> %s:%s\n", localDir, target, remoteDir)
+	syncer := devsync.New(kClient, devsync.Options{
+		LocalDir:  localDir,
+		RemoteDir: remoteDir,
+		Interval:  2 * time.Second,
+		Out:       os.Stdout,
+	})
+	err = syncer.Watch(ctx, func(context.Context) ([]devsync.Target, error) {
+		return []devsync.Target{target}, nil
+	}, true)
+	if err != nil && ctx.Err() == nil {
+		return err
 	}
 	return nil
 }
@@ -936,7 +880,16 @@ func newTunnelListCommand() *cobra.Command {
 	}
 }
 
-func runTunnel(ctx context.Context, kubeconfig, kubeContext *string, namespace string, targets []string, share bool, deps bool, hosts bool, execCmd string, envFrom string, web bool, stackConfig string, latency time.Duration, errorRate float64) error {
+func runTunnel(ctx context.Context, kubeconfig, kubeContext *string, namespace string, targets []string, workloads []string, share bool, deps bool, hosts bool, execCmd string, envFrom string, web bool, stackConfig string, latency time.Duration, errorRate float64) error {
+	refs := make([]kube.WorkloadRef, 0, len(workloads))
+	for _, raw := range workloads {
+		ref, err := kube.ParseWorkloadRef(raw)
+		if err != nil {
+			return fmt.Errorf("--for: %w", err)
+		}
+		refs = append(refs, ref)
+	}
+
 	// Check for profile expansion
 	if len(targets) == 1 {
 		profiles, _ := loadTunnelProfiles()
@@ -987,7 +940,7 @@ func runTunnel(ctx context.Context, kubeconfig, kubeContext *string, namespace s
 		fmt.Printf("Loaded %d environment variables.\n", len(fetchedEnv))
 	}
 
-	if len(targets) == 0 {
+	if len(targets) == 0 && len(refs) == 0 {
 		var err error
 		targets, err = selectTargets(ctx, kClient, namespace)
 		if err != nil {
@@ -999,9 +952,14 @@ func runTunnel(ctx context.Context, kubeconfig, kubeContext *string, namespace s
 	}
 
 	tunnelsMu.Lock()
-	tunnels = make([]*Tunnel, len(targets))
-	for i, t := range targets {
-		tunnels[i] = parseTarget(t, namespace)
+	tunnels = make([]*Tunnel, 0, len(targets)+len(refs))
+	for _, t := range targets {
+		tunnels = append(tunnels, parseTarget(t, namespace))
+	}
+	for _, ref := range refs {
+		tunnels = append(tunnels, &Tunnel{Name: ref.Name, Target: ref.String(), Namespace: namespace, Workload: &ref})
+	}
+	for i := range tunnels {
 		if share {
 			tunnels[i].ListenAddr = "0.0.0.0"
 		} else {
@@ -1404,6 +1362,9 @@ func monitorHealth(ctx context.Context, t *Tunnel) {
 }
 
 func resolveTarget(ctx context.Context, kClient *kube.Client, t *Tunnel) (string, int, error) {
+	if t.Workload != nil {
+		return resolveWorkloadPod(ctx, kClient, t)
+	}
 	// 1. Try as Service first
 	svcName := t.Name
 	// If it looks like pod/foo, skip service check
@@ -1456,6 +1417,29 @@ func resolvePod(ctx context.Context, kClient *kube.Client, name string, t *Tunne
 	return pod.Name, 80, nil // Fallback
 }
 
+// resolveWorkloadPod picks a running pod of t's --for workload, the same selection ktl logs, debug,
+// and sync use.
+func resolveWorkloadPod(ctx context.Context, kClient *kube.Client, t *Tunnel) (string, int, error) {
+	selector, err := kube.WorkloadSelector(ctx, kClient.Clientset, t.Namespace, *t.Workload)
+	if err != nil {
+		return "", 0, err
+	}
+	pods, err := kClient.Clientset.CoreV1().Pods(t.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return "", 0, fmt.Errorf("list pods of %s: %w", t.Workload, err)
+	}
+	for _, p := range pods.Items {
+		if p.Status.Phase != corev1.PodRunning || p.DeletionTimestamp != nil {
+			continue
+		}
+		if len(p.Spec.Containers) > 0 && len(p.Spec.Containers[0].Ports) > 0 {
+			return p.Name, int(p.Spec.Containers[0].Ports[0].ContainerPort), nil
+		}
+		return p.Name, 80, nil // Fallback, as for pods
+	}
+	return "", 0, fmt.Errorf("no running pods for %s", t.Workload)
+}
+
 func getFreePort() (int, error) {
 	addr, err := net.ResolveTCPAddr("tcp", "localhost:0")
 	if err != nil {
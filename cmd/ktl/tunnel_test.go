@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolveTargetForWorkload(t *testing.T) {
+	pod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "prod", Labels: map[string]string{"app": "checkout"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "app",
+				Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+			}}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	client := &kube.Client{Clientset: fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "prod"},
+			Spec: appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "checkout"},
+			}},
+		},
+		pod("checkout-pending", corev1.PodPending),
+		pod("checkout-running", corev1.PodRunning),
+	)}
+
+	ref, err := kube.ParseWorkloadRef("deploy/checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tun := &Tunnel{Name: ref.Name, Target: ref.String(), Namespace: "prod", Workload: &ref}
+	podName, port, err := resolveTarget(context.Background(), client, tun)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if podName != "checkout-running" || port != 8080 {
+		t.Fatalf("expected the running pod on 8080, got %s:%d", podName, port)
+	}
+
+	missing := kube.WorkloadRef{Kind: "deployment", Name: "missing"}
+	if _, _, err := resolveTarget(context.Background(), client, &Tunnel{Name: "missing", Namespace: "prod", Workload: &missing}); err == nil {
+		t.Fatalf("expected an error for a missing workload")
+	}
+}
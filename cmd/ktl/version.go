@@ -4,17 +4,45 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"time"
 
+	"github.com/kubekattle/ktl/internal/kube"
 	"github.com/kubekattle/ktl/internal/version"
 	"github.com/spf13/cobra"
 )
 
-func newVersionCommand() *cobra.Command {
+// versionReport is the `ktl version --output json` document.
+type versionReport struct {
+	Client   version.Info   `json:"client"`
+	Server   *serverVersion `json:"server,omitempty"`
+	Warnings []string       `json:"warnings,omitempty"`
+}
+
+type serverVersion struct {
+	GitVersion string `json:"gitVersion,omitempty"`
+	Major      string `json:"major,omitempty"`
+	Minor      string `json:"minor,omitempty"`
+	Platform   string `json:"platform,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func newVersionCommand(kubeconfig *string, kubeContext *string) *cobra.Command {
 	var short bool
+	output := "text"
+	var server bool
 	cmd := &cobra.Command{
-		Use:           "version",
-		Short:         "Print the ktl client version information",
+		Use:   "version",
+		Short: "Print the ktl client version information",
+		Long: `Print the ktl version, git commit, build date, the embedded Helm and client-go versions, and the
+Kubernetes versions this build supports. With --server, ktl also asks the cluster for its version
+and warns when it falls outside the supported range.`,
+		Example: `  ktl version
+  ktl version --output json
+  ktl version --server --context prod-eu`,
 		Args:          cobra.NoArgs,
 		SilenceUsage:  true,
 		SilenceErrors: true,
@@ -24,22 +52,80 @@ func newVersionCommand() *cobra.Command {
 				fmt.Fprintln(cmd.OutOrStdout(), info.Version)
 				return nil
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Client Version: %s\n", info.Version)
-			if info.GitCommit != "" && info.GitCommit != "unknown" {
-				fmt.Fprintf(cmd.OutOrStdout(), "GitCommit: %s\n", info.GitCommit)
+			report := versionReport{Client: info}
+			if server {
+				report.Server = fetchServerVersion(cmd.Context(), derefString(kubeconfig), derefString(kubeContext))
+				if report.Server.Error == "" {
+					if warn := info.Kubernetes.CheckSkew(report.Server.Major, report.Server.Minor); warn != "" {
+						report.Warnings = append(report.Warnings, warn)
+					}
+				}
 			}
-			if info.GitTreeState != "" && info.GitTreeState != "unknown" {
-				fmt.Fprintf(cmd.OutOrStdout(), "GitTreeState: %s\n", info.GitTreeState)
+			if output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				writeVersionText(cmd.OutOrStdout(), report)
 			}
-			if info.BuildDate != "" && info.BuildDate != "unknown" {
-				fmt.Fprintf(cmd.OutOrStdout(), "BuildDate: %s\n", info.BuildDate)
+			for _, warn := range report.Warnings {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", warn)
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "GoVersion: %s\n", info.GoVersion)
-			fmt.Fprintf(cmd.OutOrStdout(), "Platform: %s\n", info.Platform)
 			return nil
 		},
 	}
 	cmd.Flags().BoolVar(&short, "short", false, "Print just the version number")
+	cmd.Flags().VarP(newEnumStringValue(&output, "text", "json"), "output", "o", "Output format: text or json")
+	cmd.Flags().BoolVar(&server, "server", false, "Also query the cluster version and warn about version skew")
 	decorateCommandHelp(cmd, "Version Flags")
 	return cmd
 }
+
+func writeVersionText(out io.Writer, report versionReport) {
+	info := report.Client
+	fmt.Fprintf(out, "Client Version: %s\n", info.Version)
+	if info.GitCommit != "" && info.GitCommit != "unknown" {
+		fmt.Fprintf(out, "GitCommit: %s\n", info.GitCommit)
+	}
+	if info.GitTreeState != "" && info.GitTreeState != "unknown" {
+		fmt.Fprintf(out, "GitTreeState: %s\n", info.GitTreeState)
+	}
+	if info.BuildDate != "" && info.BuildDate != "unknown" {
+		fmt.Fprintf(out, "BuildDate: %s\n", info.BuildDate)
+	}
+	fmt.Fprintf(out, "GoVersion: %s\n", info.GoVersion)
+	fmt.Fprintf(out, "Platform: %s\n", info.Platform)
+	fmt.Fprintf(out, "Helm: %s\n", info.HelmVersion)
+	fmt.Fprintf(out, "client-go: %s\n", info.ClientGoVersion)
+	if info.Kubernetes.Min != "" {
+		fmt.Fprintf(out, "Supported Kubernetes: %s-%s\n", info.Kubernetes.Min, info.Kubernetes.Max)
+	}
+	if s := report.Server; s != nil {
+		if s.Error != "" {
+			fmt.Fprintf(out, "Server Version: unavailable (%s)\n", s.Error)
+		} else {
+			fmt.Fprintf(out, "Server Version: %s\n", s.GitVersion)
+		}
+	}
+}
+
+// fetchServerVersion asks the API server for its version; failures are reported in the result so
+// `ktl version --server` still prints the client side.
+func fetchServerVersion(ctx context.Context, kubeconfig, kubeContext string) *serverVersion {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	client, err := kube.New(ctx, kubeconfig, kubeContext)
+	if err != nil {
+		return &serverVersion{Error: err.Error()}
+	}
+	v, err := client.Clientset.Discovery().ServerVersion()
+	if err != nil {
+		return &serverVersion{Error: err.Error()}
+	}
+	return &serverVersion{GitVersion: v.GitVersion, Major: v.Major, Minor: v.Minor, Platform: v.Platform}
+}
@@ -0,0 +1,457 @@
+// File: cmd/ktl/watch.go
+// Brief: CLI command wiring and implementation for 'watch'.
+
+// Package main provides the ktl CLI entrypoints.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/kubekattle/ktl/internal/ui"
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/restmapper"
+)
+
+type watchOptions struct {
+	namespace  string
+	selector   string
+	untilReady bool
+	untilGone  bool
+	interval   time.Duration
+	timeout    time.Duration
+}
+
+func newWatchCommand(kubeconfig, kubeContext *string) *cobra.Command {
+	var opts watchOptions
+	cmd := &cobra.Command{
+		Use:   "watch EXPRESSION",
+		Short: "Watch resources in a live table with readiness, restarts, and conditions",
+		Long: `Watch renders a continuously updating table for a kubectl-style resource expression:
+readiness, status, restarts (summed over the workload's pods), age, and the message of the
+first unhealthy condition. On a terminal the table redraws in place; otherwise a line is
+printed whenever a row changes.
+
+The expression is TYPE[,TYPE...][/NAME] [NAME...] with optional -n/--namespace,
+-A/--all-namespaces, -l/--selector, and --field-selector.
+
+--until-ready and --until-gone turn watch into a script-friendly wait: it exits 0 once every
+matched object is Ready (and at least one matches) or once nothing matches.`,
+		Example: `  # Watch the deployments and statefulsets of one release
+  ktl watch 'deploy,sts -n prod -l app.kubernetes.io/instance=foo'
+
+  # Block a pipeline until the rollout is ready
+  ktl watch 'deploy/checkout -n prod' --until-ready --timeout 5m
+
+  # Wait for a namespace teardown to finish
+  ktl watch 'pods -n preview-42' --until-gone`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expr, err := kube.ParseWatchExpr(strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			if expr.Namespace == "" && !expr.AllNamespaces {
+				expr.Namespace = strings.TrimSpace(opts.namespace)
+			}
+			if expr.Selector == "" {
+				expr.Selector = strings.TrimSpace(opts.selector)
+			}
+			if opts.untilReady && opts.untilGone {
+				return fmt.Errorf("--until-ready and --until-gone are mutually exclusive")
+			}
+			if opts.interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+			return runWatch(cmd.Context(), cmd.OutOrStdout(), kubeconfig, kubeContext, expr, opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "Namespace to watch when the expression has none")
+	cmd.Flags().StringVarP(&opts.selector, "selector", "l", "", "Label selector to use when the expression has none")
+	cmd.Flags().BoolVar(&opts.untilReady, "until-ready", false, "Exit 0 once every matched object is Ready")
+	cmd.Flags().BoolVar(&opts.untilGone, "until-gone", false, "Exit 0 once no object matches the expression")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 2*time.Second, "How often to refresh the table")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 0, "Fail if the --until condition is not met within this duration (0 waits forever)")
+	decorateCommandHelp(cmd, "Watch Flags")
+	return cmd
+}
+
+// watchTarget is one resource type of the expression, resolved against discovery.
+type watchTarget struct {
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+// watchRow is one object in the watch table.
+type watchRow struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Ready     string
+	Status    string
+	Restarts  int64
+	Age       time.Duration
+	Message   string
+}
+
+func (r watchRow) key() string {
+	return r.Kind + "/" + r.Namespace + "/" + r.Name
+}
+
+func runWatch(ctx context.Context, out io.Writer, kubeconfig, kubeContext *string, expr kube.WatchExpr, opts watchOptions) error {
+	kClient, err := kube.New(ctx, *kubeconfig, *kubeContext)
+	if err != nil {
+		return err
+	}
+	if expr.Namespace == "" && !expr.AllNamespaces {
+		expr.Namespace = kClient.Namespace
+		if expr.Namespace == "" {
+			expr.Namespace = "default"
+		}
+	}
+	mapper := restmapper.NewShortcutExpander(kClient.RESTMapper, kClient.Discovery, nil)
+	targets := make([]watchTarget, 0, len(expr.Resources))
+	for _, res := range expr.Resources {
+		gvr, err := mapper.ResourceFor(schema.GroupVersionResource{Resource: res})
+		if err != nil {
+			return fmt.Errorf("resolve resource type %q: %w", res, err)
+		}
+		gvk, err := mapper.KindFor(gvr)
+		if err != nil {
+			return fmt.Errorf("resolve kind of %q: %w", res, err)
+		}
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return fmt.Errorf("resolve scope of %q: %w", res, err)
+		}
+		targets = append(targets, watchTarget{gvr: gvr, namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace})
+	}
+
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	var surface *stack.ConsoleSurface
+	width := 0
+	if isTerminalWriter(out) {
+		surface = stack.NewConsoleSurface(out)
+		width, _ = ui.TerminalWidth(out)
+	}
+	stream := &watchStream{out: out, seen: map[string]watchRow{}}
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	for {
+		rows, err := collectWatchRows(ctx, kClient, targets, expr, time.Now())
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		if err == nil {
+			done := (opts.untilReady && watchAllReady(rows)) || (opts.untilGone && len(rows) == 0)
+			if surface != nil {
+				surface.Render(watchSections(expr, rows, opts, width))
+				if done {
+					surface.Finish()
+				}
+			} else {
+				stream.write(rows, expr.AllNamespaces)
+			}
+			if done {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			if surface != nil {
+				surface.Finish()
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && (opts.untilReady || opts.untilGone) {
+				want := "ready"
+				if opts.untilGone {
+					want = "gone"
+				}
+				return fmt.Errorf("timed out after %s waiting for %s to be %s", opts.timeout, strings.Join(expr.Resources, ","), want)
+			}
+			if opts.untilReady || opts.untilGone {
+				return ctx.Err()
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func collectWatchRows(ctx context.Context, kClient *kube.Client, targets []watchTarget, expr kube.WatchExpr, now time.Time) ([]watchRow, error) {
+	listOpts := metav1.ListOptions{LabelSelector: expr.Selector, FieldSelector: expr.FieldSelector}
+	names := map[string]bool{}
+	for _, n := range expr.Names {
+		names[n] = true
+	}
+	var objects []unstructured.Unstructured
+	needPods := false
+	for _, t := range targets {
+		res := kClient.Dynamic.Resource(t.gvr)
+		var list *unstructured.UnstructuredList
+		var err error
+		if t.namespaced {
+			list, err = res.Namespace(expr.Namespace).List(ctx, listOpts)
+		} else {
+			list, err = res.List(ctx, listOpts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", t.gvr.Resource, err)
+		}
+		for _, obj := range list.Items {
+			if len(names) > 0 && !names[obj.GetName()] {
+				continue
+			}
+			if _, ok, _ := unstructured.NestedMap(obj.Object, "spec", "selector"); ok {
+				needPods = true
+			}
+			objects = append(objects, obj)
+		}
+	}
+	var pods []corev1.Pod
+	if needPods {
+		list, err := kClient.Clientset.CoreV1().Pods(expr.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("list pods: %w", err)
+		}
+		pods = list.Items
+	}
+	rows := make([]watchRow, 0, len(objects))
+	for i := range objects {
+		rows = append(rows, buildWatchRow(&objects[i], pods, now))
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].key() < rows[j].key() })
+	return rows, nil
+}
+
+// buildWatchRow evaluates one object; pods are candidates for the restart count of workloads
+// with a spec.selector.
+func buildWatchRow(obj *unstructured.Unstructured, pods []corev1.Pod, now time.Time) watchRow {
+	row := watchRow{Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName(), Ready: "-"}
+	if ts := obj.GetCreationTimestamp(); !ts.IsZero() {
+		row.Age = now.Sub(ts.Time)
+	}
+	rs := deploy.StatusFromObject(obj)
+	row.Status = rs.Status
+
+	nested := func(fields ...string) int64 {
+		v, _, _ := unstructured.NestedInt64(obj.Object, fields...)
+		return v
+	}
+	desired := func(fields ...string) int64 {
+		if v, ok, _ := unstructured.NestedInt64(obj.Object, fields...); ok {
+			return v
+		}
+		return 1
+	}
+	switch obj.GetKind() {
+	case "Deployment", "StatefulSet", "ReplicaSet":
+		row.Ready = fmt.Sprintf("%d/%d", nested("status", "readyReplicas"), desired("spec", "replicas"))
+	case "DaemonSet":
+		row.Ready = fmt.Sprintf("%d/%d", nested("status", "numberReady"), nested("status", "desiredNumberScheduled"))
+	case "Job":
+		row.Ready = fmt.Sprintf("%d/%d", nested("status", "succeeded"), desired("spec", "completions"))
+	case "Pod":
+		var pod corev1.Pod
+		if runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &pod) == nil {
+			ready := 0
+			for _, cs := range pod.Status.ContainerStatuses {
+				if cs.Ready {
+					ready++
+				}
+				row.Restarts += int64(cs.RestartCount)
+			}
+			row.Ready = fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers))
+			if pod.Status.Phase == corev1.PodRunning && ready < len(pod.Spec.Containers) {
+				row.Status = "Progressing"
+			}
+		}
+	}
+	if raw, ok, _ := unstructured.NestedMap(obj.Object, "spec", "selector"); ok && obj.GetKind() != "Pod" {
+		var sel metav1.LabelSelector
+		if runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &sel) == nil {
+			if selector, err := metav1.LabelSelectorAsSelector(&sel); err == nil && !selector.Empty() {
+				row.Restarts = podRestarts(pods, obj.GetNamespace(), selector)
+			}
+		}
+	}
+	if obj.GetDeletionTimestamp() != nil {
+		row.Status = "Terminating"
+	}
+	row.Message = watchConditionMessage(obj)
+	if row.Message == "" && row.Status != "Ready" {
+		row.Message = strings.TrimSpace(strings.TrimSpace(rs.Reason+" ") + rs.Message)
+	}
+	return row
+}
+
+func podRestarts(pods []corev1.Pod, namespace string, selector labels.Selector) int64 {
+	var total int64
+	for _, p := range pods {
+		if p.Namespace != namespace || !selector.Matches(labels.Set(p.Labels)) {
+			continue
+		}
+		for _, cs := range p.Status.ContainerStatuses {
+			total += int64(cs.RestartCount)
+		}
+	}
+	return total
+}
+
+// watchConditionMessage returns "Type: message" for the first unhealthy status condition:
+// a positive condition (Available, Ready, Progressing, ...) that is not True, or a negative one
+// (ReplicaFailure, Failed, Stalled, Degraded) that is.
+func watchConditionMessage(obj *unstructured.Unstructured) string {
+	conds, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, raw := range conds {
+		c, _ := raw.(map[string]any)
+		typ, _ := c["type"].(string)
+		status, _ := c["status"].(string)
+		msg, _ := c["message"].(string)
+		if strings.TrimSpace(msg) == "" {
+			continue
+		}
+		negative := false
+		switch typ {
+		case "ReplicaFailure", "Failed", "Stalled", "Degraded":
+			negative = true
+		}
+		if (negative && status == "True") || (!negative && status != "True") {
+			return typ + ": " + strings.TrimSpace(msg)
+		}
+	}
+	return ""
+}
+
+func watchAllReady(rows []watchRow) bool {
+	if len(rows) == 0 {
+		return false
+	}
+	for _, r := range rows {
+		if r.Status != "Ready" {
+			return false
+		}
+	}
+	return true
+}
+
+// watchTableLines renders rows as aligned table lines, header first.
+func watchTableLines(rows []watchRow, showNamespace bool) []string {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	if showNamespace {
+		fmt.Fprint(tw, "NAMESPACE\t")
+	}
+	fmt.Fprintln(tw, "NAME\tREADY\tSTATUS\tRESTARTS\tAGE\tMESSAGE")
+	for _, r := range rows {
+		if showNamespace {
+			ns := r.Namespace
+			if ns == "" {
+				ns = "-"
+			}
+			fmt.Fprintf(tw, "%s\t", ns)
+		}
+		fmt.Fprintf(tw, "%s/%s\t%s\t%s\t%d\t%s\t%s\n", strings.ToLower(r.Kind), r.Name, r.Ready, r.Status, r.Restarts, duration.HumanDuration(r.Age), r.Message)
+	}
+	_ = tw.Flush()
+	return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+}
+
+func watchSections(expr kube.WatchExpr, rows []watchRow, opts watchOptions, width int) []stack.ConsoleSection {
+	lines := watchTableLines(rows, expr.AllNamespaces)
+	if len(rows) == 0 {
+		lines = []string{"No matching resources."}
+	}
+	ready := 0
+	for _, r := range rows {
+		if r.Status == "Ready" {
+			ready++
+		}
+	}
+	scope := "-n " + expr.Namespace
+	if expr.AllNamespaces {
+		scope = "-A"
+	}
+	footer := fmt.Sprintf("%s %s · %d/%d ready · every %s", strings.Join(expr.Resources, ","), scope, ready, len(rows), opts.interval)
+	switch {
+	case opts.untilReady:
+		footer += " · until ready"
+	case opts.untilGone:
+		footer += " · until gone"
+	}
+	// Wrapped lines would break the surface's cursor math, so clip to the terminal width.
+	if width > 0 {
+		for i := range lines {
+			lines[i] = runewidth.Truncate(lines[i], width, "…")
+		}
+		footer = runewidth.Truncate(footer, width, "…")
+	}
+	return []stack.ConsoleSection{
+		{Name: "table", Lines: lines},
+		{Name: "footer", Lines: []string{footer}},
+	}
+}
+
+// watchStream prints kubectl-style watch output for non-terminal writers: the table once, then
+// one line per row whose state changed (age aside) and a DELETED line per vanished row.
+type watchStream struct {
+	out     io.Writer
+	started bool
+	seen    map[string]watchRow
+}
+
+func (s *watchStream) write(rows []watchRow, showNamespace bool) {
+	var changed []watchRow
+	current := make(map[string]watchRow, len(rows))
+	for _, r := range rows {
+		current[r.key()] = r
+		prev, ok := s.seen[r.key()]
+		prev.Age = r.Age
+		if !ok || prev != r {
+			changed = append(changed, r)
+		}
+	}
+	for key, r := range s.seen {
+		if _, ok := current[key]; !ok {
+			r.Status, r.Message = "Deleted", ""
+			changed = append(changed, r)
+		}
+	}
+	s.seen = current
+	if len(changed) == 0 && s.started {
+		return
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i].key() < changed[j].key() })
+	lines := watchTableLines(changed, showNamespace)
+	if s.started {
+		lines = lines[1:]
+	}
+	s.started = true
+	for _, line := range lines {
+		fmt.Fprintln(s.out, line)
+	}
+}
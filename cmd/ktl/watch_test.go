@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseWatchExpr(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want kube.WatchExpr
+	}{
+		{
+			raw:  "deploy,sts -n prod -l app.kubernetes.io/instance=foo",
+			want: kube.WatchExpr{Resources: []string{"deploy", "sts"}, Namespace: "prod", Selector: "app.kubernetes.io/instance=foo"},
+		},
+		{
+			raw:  "deploy/checkout --namespace=prod",
+			want: kube.WatchExpr{Resources: []string{"deploy"}, Names: []string{"checkout"}, Namespace: "prod"},
+		},
+		{
+			raw:  "pods a b -A --field-selector status.phase=Running",
+			want: kube.WatchExpr{Resources: []string{"pods"}, Names: []string{"a", "b"}, AllNamespaces: true, FieldSelector: "status.phase=Running"},
+		},
+	}
+	for _, tc := range cases {
+		got, err := kube.ParseWatchExpr(tc.raw)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.raw, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%q: got %+v, want %+v", tc.raw, got, tc.want)
+		}
+	}
+	for _, bad := range []string{"", "-n prod", "deploy -n", "deploy -o wide", "deploy -A -n prod", "deploy/"} {
+		if _, err := kube.ParseWatchExpr(bad); err == nil {
+			t.Fatalf("%q: expected error", bad)
+		}
+	}
+}
+
+func watchTestDeployment(ready int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]any{
+			"name":              "checkout",
+			"namespace":         "prod",
+			"creationTimestamp": "2024-01-01T00:00:00Z",
+		},
+		"spec": map[string]any{
+			"replicas": int64(3),
+			"selector": map[string]any{"matchLabels": map[string]any{"app": "checkout"}},
+		},
+		"status": map[string]any{
+			"readyReplicas": ready,
+			"conditions": []any{
+				map[string]any{"type": "Available", "status": "False", "message": "Deployment does not have minimum availability."},
+			},
+		},
+	}}
+}
+
+func TestBuildWatchRowSumsPodRestartsAndReportsConditions(t *testing.T) {
+	pod := func(name, app string, restarts int32) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "prod", Labels: map[string]string{"app": app}},
+			Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{RestartCount: restarts}}},
+		}
+	}
+	pods := []corev1.Pod{pod("checkout-1", "checkout", 2), pod("checkout-2", "checkout", 1), pod("other-1", "other", 7)}
+	now := time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC)
+
+	row := buildWatchRow(watchTestDeployment(1), pods, now)
+	if row.Ready != "1/3" || row.Status != "Progressing" || row.Restarts != 3 || row.Age != 5*time.Minute {
+		t.Fatalf("unexpected row: %+v", row)
+	}
+	if row.Message != "Available: Deployment does not have minimum availability." {
+		t.Fatalf("unexpected message: %q", row.Message)
+	}
+	if watchAllReady([]watchRow{row}) {
+		t.Fatalf("expected not ready")
+	}
+
+	ready := watchTestDeployment(3)
+	_ = unstructured.SetNestedSlice(ready.Object, nil, "status", "conditions")
+	row = buildWatchRow(ready, pods, now)
+	if row.Status != "Ready" || row.Message != "" {
+		t.Fatalf("unexpected ready row: %+v", row)
+	}
+	if !watchAllReady([]watchRow{row}) || watchAllReady(nil) {
+		t.Fatalf("unexpected readiness evaluation")
+	}
+}
+
+func TestWatchStreamPrintsOnlyChangedRows(t *testing.T) {
+	var out bytes.Buffer
+	s := &watchStream{out: &out, seen: map[string]watchRow{}}
+	a := watchRow{Kind: "Deployment", Namespace: "prod", Name: "a", Ready: "0/1", Status: "Pending"}
+	b := watchRow{Kind: "Deployment", Namespace: "prod", Name: "b", Ready: "1/1", Status: "Ready"}
+	s.write([]watchRow{a, b}, false)
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 3 || !strings.HasPrefix(lines[0], "NAME") {
+		t.Fatalf("unexpected initial output:\n%s", out.String())
+	}
+
+	out.Reset()
+	b.Age = time.Minute
+	s.write([]watchRow{a, b}, false)
+	if out.Len() != 0 {
+		t.Fatalf("age-only change should print nothing, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	a.Ready, a.Status = "1/1", "Ready"
+	s.write([]watchRow{a}, false)
+	got := out.String()
+	if !strings.Contains(got, "deployment/a") || !strings.Contains(got, "deployment/b") || !strings.Contains(got, "Deleted") || strings.Contains(got, "NAME") {
+		t.Fatalf("unexpected update output:\n%s", got)
+	}
+}
@@ -0,0 +1,161 @@
+// File: internal/agent/build_jobs.go
+// Brief: Internal agent package implementation for 'build jobs'.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	apiv1 "github.com/kubekattle/ktl/pkg/api/ktl/api/v1"
+)
+
+const (
+	buildJobStateRunning   = "running"
+	buildJobStateSucceeded = "succeeded"
+	buildJobStateFailed    = "failed"
+	buildJobStateCanceled  = "canceled"
+
+	// buildJobRetention is how long a finished build stays attachable.
+	buildJobRetention = time.Hour
+	// buildJobMaxEvents caps the replay buffer of one build; older events are dropped first.
+	buildJobMaxEvents = 50000
+)
+
+// buildJob is one RunBuild invocation. It buffers the build's events so clients can attach,
+// disconnect, and reattach while the build runs.
+type buildJob struct {
+	id      string
+	started time.Time
+	cancel  context.CancelFunc
+
+	mu       sync.Mutex
+	events   []*apiv1.BuildEvent
+	dropped  uint64 // events trimmed from the front of events
+	state    string
+	finished time.Time
+	changed  chan struct{}
+}
+
+func newBuildJob(id string, cancel context.CancelFunc) *buildJob {
+	return &buildJob{
+		id:      id,
+		started: time.Now(),
+		cancel:  cancel,
+		state:   buildJobStateRunning,
+		changed: make(chan struct{}),
+	}
+}
+
+// publish assigns ev the job's next sequence number and wakes attached streams.
+func (j *buildJob) publish(ev *apiv1.BuildEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	ev.Sequence = j.dropped + uint64(len(j.events)) + 1
+	j.events = append(j.events, ev)
+	if len(j.events) > buildJobMaxEvents {
+		trim := len(j.events) - buildJobMaxEvents
+		j.events = append([]*apiv1.BuildEvent(nil), j.events[trim:]...)
+		j.dropped += uint64(trim)
+	}
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+func (j *buildJob) finish(state string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.state = state
+	j.finished = time.Now()
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+func (j *buildJob) snapshot() *apiv1.BuildJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return &apiv1.BuildJob{JobId: j.id, State: j.state, StartedUnixNano: j.started.UnixNano()}
+}
+
+func (j *buildJob) expired(now time.Time) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.state != buildJobStateRunning && now.Sub(j.finished) > buildJobRetention
+}
+
+// stream sends a BuildJob event, then every event after fromSequence until the build finishes
+// or ctx ends.
+func (j *buildJob) stream(ctx context.Context, fromSequence uint64, send func(*apiv1.BuildEvent) error) error {
+	if err := send(&apiv1.BuildEvent{
+		TimestampUnixNano: time.Now().UnixNano(),
+		Body:              &apiv1.BuildEvent_Job{Job: j.snapshot()},
+	}); err != nil {
+		return err
+	}
+	next := fromSequence
+	for {
+		j.mu.Lock()
+		if next < j.dropped {
+			next = j.dropped
+		}
+		if last := j.dropped + uint64(len(j.events)); next > last {
+			next = last
+		}
+		pending := j.events[next-j.dropped:]
+		running := j.state == buildJobStateRunning
+		changed := j.changed
+		j.mu.Unlock()
+
+		for _, ev := range pending {
+			if err := send(ev); err != nil {
+				return err
+			}
+			next = ev.Sequence
+		}
+		if len(pending) == 0 && !running {
+			return nil
+		}
+		if len(pending) > 0 {
+			continue
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// buildJobs tracks running and recently finished builds by job ID.
+type buildJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*buildJob
+}
+
+func (r *buildJobs) start(id string, cancel context.CancelFunc) (*buildJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.jobs == nil {
+		r.jobs = map[string]*buildJob{}
+	}
+	now := time.Now()
+	for key, job := range r.jobs {
+		if job.expired(now) {
+			delete(r.jobs, key)
+		}
+	}
+	if existing, ok := r.jobs[id]; ok && existing.snapshot().GetState() == buildJobStateRunning {
+		return nil, fmt.Errorf("build job %s is already running", id)
+	}
+	job := newBuildJob(id, cancel)
+	r.jobs[id] = job
+	return job, nil
+}
+
+func (r *buildJobs) get(id string) *buildJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.jobs[id]
+}
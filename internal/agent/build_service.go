@@ -8,9 +8,9 @@ package agent
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -29,6 +29,8 @@ type BuildServer struct {
 	Service buildsvc.Service
 	Mirror  *MirrorServer
 	Logger  logr.Logger
+
+	jobs buildJobs
 }
 
 // RunBuild executes a remote build and streams progress.
@@ -39,10 +41,24 @@ func (s *BuildServer) RunBuild(req *apiv1.RunBuildRequest, stream apiv1.BuildSer
 	ctx := stream.Context()
 	opts := convert.BuildOptionsFromProto(req.GetOptions())
 	sessionID := strings.TrimSpace(req.GetSessionId())
+	jobID := sessionID
+	if jobID == "" {
+		jobID = fmt.Sprintf("build-%d", time.Now().UnixNano())
+	}
 	producer := "build"
 	if strings.TrimSpace(req.GetRequester()) != "" {
 		producer = "build:" + strings.TrimSpace(req.GetRequester())
 	}
+	runCtx := ctx
+	if req.GetDetachOnDisconnect() {
+		runCtx = context.WithoutCancel(ctx)
+	}
+	runCtx, cancel := context.WithCancel(runCtx)
+	job, err := s.jobs.start(jobID, cancel)
+	if err != nil {
+		cancel()
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
 	if s.Mirror != nil && sessionID != "" {
 		tags := map[string]string{
 			"build.context_dir": strings.TrimSpace(opts.ContextDir),
@@ -53,74 +69,99 @@ func (s *BuildServer) RunBuild(req *apiv1.RunBuildRequest, stream apiv1.BuildSer
 		}, tags)
 		_ = s.Mirror.UpsertSessionStatus(ctx, sessionID, MirrorSessionStatus{State: MirrorSessionStateRunning})
 	}
-	var runErr error
-	defer func() {
-		if s.Mirror == nil || sessionID == "" {
-			return
-		}
-		st := MirrorSessionStatus{
-			State:             MirrorSessionStateDone,
-			ExitCode:          0,
-			CompletedUnixNano: time.Now().UTC().UnixNano(),
-		}
-		if runErr != nil {
-			if errors.Is(runErr, context.Canceled) {
-				st.State = MirrorSessionStateDone
-				st.ExitCode = 130
-				st.ErrorMessage = "canceled"
-			} else {
-				st.State = MirrorSessionStateError
-				st.ExitCode = 1
-				st.ErrorMessage = runErr.Error()
-			}
-		}
-		_ = s.Mirror.UpsertSessionStatus(context.Background(), sessionID, st)
-	}()
-	observer := &buildStreamObserver{stream: stream, mirror: s.Mirror, sessionID: sessionID, producer: producer}
+	observer := &buildStreamObserver{job: job, mirror: s.Mirror, sessionID: sessionID, producer: producer}
 	opts.Observers = append(opts.Observers, observer)
 	opts.Streams.Err = io.Discard
 	opts.Streams.Out = io.Discard
-	result, err := s.Service.Run(ctx, opts)
-	runErr = err
-	res := convert.BuildResultToProto(result, err)
-	if res != nil {
-		ev := &apiv1.BuildEvent{
+	go s.runJob(runCtx, job, opts, observer)
+	return job.stream(ctx, 0, stream.Send)
+}
+
+// runJob runs the build behind job and records its outcome; it outlives the RunBuild stream
+// when the client asked to detach on disconnect.
+func (s *BuildServer) runJob(ctx context.Context, job *buildJob, opts buildsvc.Options, observer *buildStreamObserver) {
+	defer job.cancel()
+	result, runErr := s.Service.Run(ctx, opts)
+	state := buildJobStateSucceeded
+	st := MirrorSessionStatus{
+		State:             MirrorSessionStateDone,
+		ExitCode:          0,
+		CompletedUnixNano: time.Now().UTC().UnixNano(),
+	}
+	if runErr != nil {
+		if errors.Is(runErr, context.Canceled) {
+			state = buildJobStateCanceled
+			st.State = MirrorSessionStateDone
+			st.ExitCode = 130
+			st.ErrorMessage = "canceled"
+		} else {
+			state = buildJobStateFailed
+			st.State = MirrorSessionStateError
+			st.ExitCode = 1
+			st.ErrorMessage = runErr.Error()
+		}
+	}
+	if res := convert.BuildResultToProto(result, runErr); res != nil {
+		observer.publish(&apiv1.BuildEvent{
 			TimestampUnixNano: time.Now().UnixNano(),
 			Body:              &apiv1.BuildEvent_Result{Result: res},
-		}
-		_ = stream.Send(ev)
-		if s.Mirror != nil && sessionID != "" {
-			_, _, _ = s.Mirror.ingestFrame(context.Background(), &apiv1.MirrorFrame{
-				SessionId: sessionID,
-				Producer:  producer,
-				Payload:   &apiv1.MirrorFrame_Build{Build: ev},
-			})
-		}
+		}, true)
+	}
+	if s.Mirror != nil && observer.sessionID != "" {
+		_ = s.Mirror.UpsertSessionStatus(context.Background(), observer.sessionID, st)
+	}
+	job.finish(state)
+}
+
+// AttachBuild streams a build started by RunBuild, replaying events after from_sequence.
+func (s *BuildServer) AttachBuild(req *apiv1.AttachBuildRequest, stream apiv1.BuildService_AttachBuildServer) error {
+	if s == nil {
+		return status.Error(codes.Unavailable, "build service not configured")
+	}
+	job := s.jobs.get(strings.TrimSpace(req.GetJobId()))
+	if job == nil {
+		return status.Errorf(codes.NotFound, "build job %q not found (finished jobs are kept for %s)", req.GetJobId(), buildJobRetention)
 	}
-	return err
+	return job.stream(stream.Context(), req.GetFromSequence(), stream.Send)
+}
+
+// CancelBuild cancels a running build.
+func (s *BuildServer) CancelBuild(ctx context.Context, req *apiv1.CancelBuildRequest) (*apiv1.BuildJob, error) {
+	if s == nil {
+		return nil, status.Error(codes.Unavailable, "build service not configured")
+	}
+	job := s.jobs.get(strings.TrimSpace(req.GetJobId()))
+	if job == nil {
+		return nil, status.Errorf(codes.NotFound, "build job %q not found", req.GetJobId())
+	}
+	job.cancel()
+	return job.snapshot(), nil
 }
 
 type buildStreamObserver struct {
-	stream    apiv1.BuildService_RunBuildServer
+	job       *buildJob
 	mirror    *MirrorServer
 	sessionID string
 	producer  string
-	mu        sync.Mutex
 }
 
 func (b *buildStreamObserver) ObserveLog(rec tailer.LogRecord) {
-	if b == nil || b.stream == nil {
+	if b == nil || b.job == nil {
 		return
 	}
-	line := convert.ToProtoLogRecord(rec)
-	event := &apiv1.BuildEvent{
+	b.publish(&apiv1.BuildEvent{
 		TimestampUnixNano: rec.Timestamp.UnixNano(),
-		Body:              &apiv1.BuildEvent_Log{Log: line},
+		Body:              &apiv1.BuildEvent_Log{Log: convert.ToProtoLogRecord(rec)},
+	}, true)
+	if typed, ok := convert.BuildTypedEventFromRecord(rec); ok {
+		b.publish(typed, false)
 	}
-	b.mu.Lock()
-	_ = b.stream.Send(event)
-	b.mu.Unlock()
-	if b.mirror != nil && b.sessionID != "" {
+}
+
+// publish appends event to the job and, when mirror is set, to the session's mirror frames.
+func (b *buildStreamObserver) publish(event *apiv1.BuildEvent, mirror bool) {
+	b.job.publish(event)
+	if mirror && b.mirror != nil && b.sessionID != "" {
 		_, _, _ = b.mirror.ingestFrame(context.Background(), &apiv1.MirrorFrame{
 			SessionId: b.sessionID,
 			Producer:  b.producer,
@@ -0,0 +1,173 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/tailer"
+	"github.com/kubekattle/ktl/internal/workflows/buildsvc"
+	apiv1 "github.com/kubekattle/ktl/pkg/api/ktl/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// blockingBuildService emits a graph record and a log line, then waits for release or
+// cancellation.
+type blockingBuildService struct {
+	release chan struct{}
+}
+
+func (s *blockingBuildService) Run(ctx context.Context, opts buildsvc.Options) (*buildsvc.Result, error) {
+	now := time.Now()
+	for _, obs := range opts.Observers {
+		obs.ObserveLog(tailer.LogRecord{Timestamp: now, Source: "graph", Raw: `{"nodes":[{"id":"sha256:b","label":"RUN make","status":"running"}],"edges":[{"from":"sha256:a","to":"sha256:b"}]}`})
+		obs.ObserveLog(tailer.LogRecord{Timestamp: now, Source: "build", Raw: "step 1", Rendered: "step 1"})
+	}
+	select {
+	case <-s.release:
+		return &buildsvc.Result{Digest: "sha256:feed", Tags: []string{"example.com/app:dev"}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func startBuildServer(t *testing.T, svc buildsvc.Service) apiv1.BuildServiceClient {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	grpcSrv := grpc.NewServer()
+	apiv1.RegisterBuildServiceServer(grpcSrv, &BuildServer{Service: svc})
+	go func() { _ = grpcSrv.Serve(ln) }()
+	t.Cleanup(grpcSrv.Stop)
+	conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return apiv1.NewBuildServiceClient(conn)
+}
+
+func TestBuildServerAttachAfterDisconnect(t *testing.T) {
+	svc := &blockingBuildService{release: make(chan struct{})}
+	client := startBuildServer(t, svc)
+
+	runCtx, disconnect := context.WithCancel(context.Background())
+	stream, err := client.RunBuild(runCtx, &apiv1.RunBuildRequest{SessionId: "job-1", DetachOnDisconnect: true})
+	if err != nil {
+		t.Fatalf("RunBuild: %v", err)
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("recv: %v", err)
+	}
+	if first.GetJob().GetJobId() != "job-1" || first.GetJob().GetState() != buildJobStateRunning {
+		t.Fatalf("expected running job event first, got %v", first)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("recv: %v", err)
+	}
+	disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	attach, err := client.AttachBuild(ctx, &apiv1.AttachBuildRequest{JobId: "job-1"})
+	if err != nil {
+		t.Fatalf("AttachBuild: %v", err)
+	}
+	close(svc.release)
+
+	var (
+		lastSeq  uint64
+		progress *apiv1.BuildProgress
+		result   *apiv1.BuildResult
+		logs     int
+	)
+	for {
+		ev, err := attach.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("attach recv: %v", err)
+		}
+		if ev.GetJob() != nil {
+			continue
+		}
+		if ev.GetSequence() <= lastSeq {
+			t.Fatalf("sequence went from %d to %d", lastSeq, ev.GetSequence())
+		}
+		lastSeq = ev.GetSequence()
+		switch {
+		case ev.GetLog() != nil:
+			logs++
+		case ev.GetProgress() != nil:
+			progress = ev.GetProgress()
+		case ev.GetResult() != nil:
+			result = ev.GetResult()
+		}
+	}
+	if logs != 2 {
+		t.Fatalf("expected both log records to be replayed, got %d", logs)
+	}
+	if progress == nil || len(progress.GetVertices()) != 1 || progress.GetVertices()[0].GetInputs()[0] != "sha256:a" {
+		t.Fatalf("unexpected progress event %v", progress)
+	}
+	if result.GetDigest() != "sha256:feed" || result.GetError() != "" {
+		t.Fatalf("unexpected result %v", result)
+	}
+}
+
+func TestBuildServerCancelBuild(t *testing.T) {
+	svc := &blockingBuildService{release: make(chan struct{})}
+	client := startBuildServer(t, svc)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.RunBuild(ctx, &apiv1.RunBuildRequest{SessionId: "job-2", DetachOnDisconnect: true})
+	if err != nil {
+		t.Fatalf("RunBuild: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("recv: %v", err)
+	}
+	if _, err := client.CancelBuild(ctx, &apiv1.CancelBuildRequest{JobId: "job-2"}); err != nil {
+		t.Fatalf("CancelBuild: %v", err)
+	}
+	var result *apiv1.BuildResult
+	for {
+		ev, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("recv: %v", err)
+		}
+		if ev.GetResult() != nil {
+			result = ev.GetResult()
+		}
+	}
+	if result == nil || result.GetError() == "" {
+		t.Fatalf("expected a failed result after cancel, got %v", result)
+	}
+
+	attach, err := client.AttachBuild(ctx, &apiv1.AttachBuildRequest{JobId: "job-2", FromSequence: 1000})
+	if err != nil {
+		t.Fatalf("AttachBuild: %v", err)
+	}
+	ev, err := attach.Recv()
+	if err != nil {
+		t.Fatalf("attach recv: %v", err)
+	}
+	if ev.GetJob().GetState() != buildJobStateCanceled {
+		t.Fatalf("expected canceled job, got %v", ev.GetJob())
+	}
+	if _, err := client.CancelBuild(ctx, &apiv1.CancelBuildRequest{JobId: "missing"}); err == nil {
+		t.Fatal("expected NotFound for an unknown job")
+	}
+}
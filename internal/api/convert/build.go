@@ -6,9 +6,11 @@
 package convert
 
 import (
+	"encoding/json"
 	"io"
 	"strings"
 
+	"github.com/kubekattle/ktl/internal/tailer"
 	"github.com/kubekattle/ktl/internal/workflows/buildsvc"
 	apiv1 "github.com/kubekattle/ktl/pkg/api/ktl/api/v1"
 )
@@ -46,6 +48,24 @@ func BuildOptionsToProto(opts buildsvc.Options) *apiv1.BuildOptions {
 		RemoveIntermediate: opts.RemoveIntermediate,
 		Quiet:              opts.Quiet,
 		DockerContext:      opts.DockerContext,
+		Hermetic:           opts.Hermetic,
+		AllowNetwork:       opts.AllowNetwork,
+		AllowUnpinnedBases: opts.AllowUnpinnedBases,
+		PolicyRef:          opts.PolicyRef,
+		PolicyMode:         opts.PolicyMode,
+		SecretsMode:        opts.SecretsMode,
+		SecretsConfigRef:   opts.SecretsConfigRef,
+		AttestSbom:         opts.AttestSBOM,
+		AttestProvenance:   opts.AttestProvenance,
+		AttestationDir:     opts.AttestationDir,
+		RequireSandbox:     opts.RequireSandbox,
+		GitSkipSubmodules:  opts.GitSkipSubmodules,
+		GitKeepDir:         opts.GitKeepDir,
+		GitLfs:             opts.GitLFS,
+		Labels:             append([]string(nil), opts.Labels...),
+		InjectLabels:       opts.InjectLabels,
+		InjectBuildArgs:    opts.InjectBuildArgs,
+		ImageVersion:       opts.ImageVersion,
 	}
 }
 
@@ -85,6 +105,24 @@ func BuildOptionsFromProto(pb *apiv1.BuildOptions) buildsvc.Options {
 		RemoveIntermediate: pb.GetRemoveIntermediate(),
 		Quiet:              pb.GetQuiet(),
 		DockerContext:      pb.GetDockerContext(),
+		Hermetic:           pb.GetHermetic(),
+		AllowNetwork:       pb.GetAllowNetwork(),
+		AllowUnpinnedBases: pb.GetAllowUnpinnedBases(),
+		PolicyRef:          pb.GetPolicyRef(),
+		PolicyMode:         pb.GetPolicyMode(),
+		SecretsMode:        pb.GetSecretsMode(),
+		SecretsConfigRef:   pb.GetSecretsConfigRef(),
+		AttestSBOM:         pb.GetAttestSbom(),
+		AttestProvenance:   pb.GetAttestProvenance(),
+		AttestationDir:     pb.GetAttestationDir(),
+		RequireSandbox:     pb.GetRequireSandbox(),
+		GitSkipSubmodules:  pb.GetGitSkipSubmodules(),
+		GitKeepDir:         pb.GetGitKeepDir(),
+		GitLFS:             pb.GetGitLfs(),
+		Labels:             append([]string(nil), pb.GetLabels()...),
+		InjectLabels:       pb.GetInjectLabels(),
+		InjectBuildArgs:    pb.GetInjectBuildArgs(),
+		ImageVersion:       pb.GetImageVersion(),
 		Streams: buildsvc.Streams{
 			In:  strings.NewReader(""),
 			Out: io.Discard,
@@ -109,3 +147,62 @@ func BuildResultToProto(res *buildsvc.Result, err error) *apiv1.BuildResult {
 	}
 	return br
 }
+
+// BuildTypedEventFromRecord returns the typed form of a build log record: the solve graph
+// snapshots ("graph" records) become BuildProgress and phase records become BuildPhase. Other
+// records have no typed form.
+func BuildTypedEventFromRecord(rec tailer.LogRecord) (*apiv1.BuildEvent, bool) {
+	ev := &apiv1.BuildEvent{TimestampUnixNano: rec.Timestamp.UnixNano()}
+	switch rec.Source {
+	case "graph":
+		var snapshot struct {
+			Nodes []struct {
+				ID            string `json:"id"`
+				Label         string `json:"label"`
+				Status        string `json:"status"`
+				Cached        bool   `json:"cached"`
+				StartedUnix   int64  `json:"startedUnix"`
+				CompletedUnix int64  `json:"completedUnix"`
+				Current       int64  `json:"current"`
+				Total         int64  `json:"total"`
+				Error         string `json:"error"`
+			} `json:"nodes"`
+			Edges []struct {
+				From string `json:"from"`
+				To   string `json:"to"`
+			} `json:"edges"`
+		}
+		if err := json.Unmarshal([]byte(rec.Raw), &snapshot); err != nil {
+			return nil, false
+		}
+		inputs := map[string][]string{}
+		for _, edge := range snapshot.Edges {
+			inputs[edge.To] = append(inputs[edge.To], edge.From)
+		}
+		progress := &apiv1.BuildProgress{}
+		for _, node := range snapshot.Nodes {
+			progress.Vertices = append(progress.Vertices, &apiv1.BuildVertex{
+				Id:            node.ID,
+				Name:          node.Label,
+				Status:        node.Status,
+				Cached:        node.Cached,
+				StartedUnix:   node.StartedUnix,
+				CompletedUnix: node.CompletedUnix,
+				Current:       node.Current,
+				Total:         node.Total,
+				Error:         node.Error,
+				Inputs:        inputs[node.ID],
+			})
+		}
+		ev.Body = &apiv1.BuildEvent_Progress{Progress: progress}
+	case "phase":
+		ev.Body = &apiv1.BuildEvent_Phase{Phase: &apiv1.BuildPhase{
+			Name:    rec.Pod,
+			State:   rec.Container,
+			Message: rec.Rendered,
+		}}
+	default:
+		return nil, false
+	}
+	return ev, true
+}
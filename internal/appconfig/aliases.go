@@ -0,0 +1,27 @@
+package appconfig
+
+import "sort"
+
+// AliasNames returns the configured alias names in sorted order.
+func (c Config) AliasNames() []string {
+	names := make([]string, 0, len(c.Aliases))
+	for name := range c.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func mergeAliases(a, b map[string]string) map[string]string {
+	if len(b) == 0 {
+		return a
+	}
+	out := make(map[string]string, len(a)+len(b))
+	for name, expansion := range a {
+		out[name] = expansion
+	}
+	for name, expansion := range b {
+		out[name] = expansion
+	}
+	return out
+}
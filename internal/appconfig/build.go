@@ -24,11 +24,26 @@ type BuildConfig struct {
 	Push          *bool  `yaml:"push,omitempty"`
 	Load          *bool  `yaml:"load,omitempty"`
 	RemoteBuild   string `yaml:"remoteBuild,omitempty"`
+	// Version is the image version recorded in the org.opencontainers.image.version label.
+	Version string `yaml:"version,omitempty"`
+	// Labels are added to every image built from the repo; --label wins on conflicts.
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// InjectLabels adds the OCI created, revision, source, and version labels (default true).
+	InjectLabels *bool `yaml:"injectLabels,omitempty"`
+	// InjectBuildArgs also passes BUILD_DATE, VCS_REF, VCS_URL, and VERSION as build args.
+	InjectBuildArgs *bool `yaml:"injectBuildArgs,omitempty"`
 }
 
 type Config struct {
 	Build   BuildConfig   `yaml:"build,omitempty"`
 	Secrets SecretsConfig `yaml:"secrets,omitempty"`
+	Logs    LogsConfig    `yaml:"logs,omitempty"`
+	Deploy  DeployConfig  `yaml:"deploy,omitempty"`
+	// ValueSources are named external lookups referenced from values as valuefrom://<name>/<key>.
+	ValueSources map[string]ValueSource `yaml:"valueSources,omitempty"`
+	// Aliases maps a user-defined command name to the ktl arguments it expands to, for example
+	// pprod: "apply --chart ./chart --release foo -n prod --diff".
+	Aliases map[string]string `yaml:"aliases,omitempty"`
 }
 
 func DefaultGlobalPath() string {
@@ -87,6 +102,8 @@ func loadOne(path string) (Config, error) {
 	if err := yaml.Unmarshal(raw, &cfg); err != nil {
 		return Config{}, err
 	}
+	resolveDeployPaths(&cfg.Deploy, filepath.Dir(path))
+	resolveValueSourcePaths(cfg.ValueSources, filepath.Dir(path))
 	return cfg, nil
 }
 
@@ -94,6 +111,10 @@ func merge(a, b Config) Config {
 	out := a
 	out.Build = mergeBuild(a.Build, b.Build)
 	out.Secrets = mergeSecrets(a.Secrets, b.Secrets)
+	out.Logs = mergeLogs(a.Logs, b.Logs)
+	out.Deploy = mergeDeploy(a.Deploy, b.Deploy)
+	out.Aliases = mergeAliases(a.Aliases, b.Aliases)
+	out.ValueSources = mergeValueSources(a.ValueSources, b.ValueSources)
 	return out
 }
 
@@ -138,5 +159,24 @@ func mergeBuild(a, b BuildConfig) BuildConfig {
 	if b.RemoteBuild != "" {
 		out.RemoteBuild = b.RemoteBuild
 	}
+	if b.Version != "" {
+		out.Version = b.Version
+	}
+	if len(b.Labels) > 0 {
+		labels := make(map[string]string, len(a.Labels)+len(b.Labels))
+		for k, v := range a.Labels {
+			labels[k] = v
+		}
+		for k, v := range b.Labels {
+			labels[k] = v
+		}
+		out.Labels = labels
+	}
+	if b.InjectLabels != nil {
+		out.InjectLabels = b.InjectLabels
+	}
+	if b.InjectBuildArgs != nil {
+		out.InjectBuildArgs = b.InjectBuildArgs
+	}
 	return out
 }
@@ -0,0 +1,159 @@
+package appconfig
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DeployConfig holds defaults for ktl template, apply plan, and apply.
+type DeployConfig struct {
+	// PostRenderers run in order over every rendered manifest, like helm --post-renderer.
+	PostRenderers []PostRendererConfig `yaml:"postRenderers,omitempty"`
+	// ApprovalPolicy is the minimum ktl apply --approval-policy: none (default)|risky|always. Only the
+	// repo .ktl.yaml value is enforced; commands that cannot check a token per release refuse to
+	// deploy while it is set.
+	ApprovalPolicy string `yaml:"approvalPolicy,omitempty"`
+	// Approvers are the people whose `ktl approve` tokens satisfy an approval policy. Only approvers
+	// in the repo .ktl.yaml are trusted.
+	Approvers []Approver `yaml:"approvers,omitempty"`
+	// Windows restrict when ktl apply, ktl stack apply, ktl promote, and ktl bundle apply may change
+	// a kube context or namespace.
+	Windows []DeployWindow `yaml:"windows,omitempty"`
+	// Owners record who owns the releases ktl apply deploys; the first matching rule wins.
+	Owners []ReleaseOwnerRule `yaml:"owners,omitempty"`
+}
+
+// ReleaseOwnerRule names the team, Slack channel, and on-call contact of the releases it selects.
+type ReleaseOwnerRule struct {
+	// Releases are release name globs (e.g. payments-*); empty selects every release.
+	Releases []string `yaml:"releases,omitempty"`
+	// Namespaces are namespace globs; empty selects every namespace.
+	Namespaces []string `yaml:"namespaces,omitempty"`
+	Team       string   `yaml:"team,omitempty"`
+	Slack      string   `yaml:"slack,omitempty"`
+	// Oncall is a pager schedule URL, rotation name, or person to page.
+	Oncall string `yaml:"oncall,omitempty"`
+}
+
+// DeployWindow allows deploys to the selected contexts and namespaces only during the minutes
+// matched by Schedule. Targets no window selects can be deployed at any time.
+type DeployWindow struct {
+	Name string `yaml:"name,omitempty"`
+	// Contexts are kube context globs (e.g. prod-*); empty selects every context.
+	Contexts []string `yaml:"contexts,omitempty"`
+	// Namespaces are namespace globs; empty selects every namespace.
+	Namespaces []string `yaml:"namespaces,omitempty"`
+	// Schedule is a cron expression (minute hour day-of-month month day-of-week) of the allowed
+	// minutes, e.g. "* 9-16 * * mon-thu".
+	Schedule string `yaml:"schedule"`
+	// Timezone is an IANA zone such as Europe/Berlin (default: local time).
+	Timezone string `yaml:"timezone,omitempty"`
+}
+
+// Approver is a person allowed to approve applies, identified by the ed25519 public key printed
+// by `ktl approve keygen`.
+type Approver struct {
+	Name      string `yaml:"name"`
+	PublicKey string `yaml:"publicKey"`
+}
+
+// PostRendererConfig is an executable (exec), a set of kustomize patches, an image rewrite, or
+// metadata injection rules.
+type PostRendererConfig struct {
+	Name string `yaml:"name,omitempty"`
+	// Exec is a program that reads the manifests on stdin and writes the result to stdout.
+	Exec string   `yaml:"exec,omitempty"`
+	Args []string `yaml:"args,omitempty"`
+	// Patches are kustomize patches (strategic merge or JSON 6902) applied in-process.
+	Patches []KustomizePatch `yaml:"patches,omitempty"`
+	// Images rewrites container image references.
+	Images *ImageRewriteConfig `yaml:"images,omitempty"`
+	// Inject adds labels, annotations, and namespaces to matching objects.
+	Inject []InjectRule `yaml:"inject,omitempty"`
+}
+
+// InjectRule adds metadata to every rendered object it selects.
+type InjectRule struct {
+	// Kinds limits the rule to these kinds (case-insensitive); empty selects every kind.
+	Kinds []string `yaml:"kinds,omitempty"`
+	// Selector is a label selector evaluated against the object's labels before injection.
+	Selector    string            `yaml:"selector,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	// PodTemplate also applies labels and annotations to pod templates, for sidecar opt-ins.
+	PodTemplate bool `yaml:"podTemplate,omitempty"`
+	// Namespace is set on namespaced objects that do not declare one.
+	Namespace string `yaml:"namespace,omitempty"`
+	// Overwrite replaces values the chart already set; by default they are kept.
+	Overwrite bool `yaml:"overwrite,omitempty"`
+}
+
+// ImageRewriteConfig rewrites the image of every container, init container, and ephemeral
+// container in the rendered manifests. Mirrors apply first, then digest pinning.
+type ImageRewriteConfig struct {
+	// Mirrors replace a registry or repository prefix, e.g. docker.io -> mirror.example.com/hub.
+	Mirrors []ImageMirror `yaml:"mirrors,omitempty" json:"mirrors,omitempty"`
+	// Digests pins images (name:tag -> sha256:...) without contacting a registry.
+	Digests map[string]string `yaml:"digests,omitempty" json:"digests,omitempty"`
+	// PinDigests resolves every remaining tag to the digest its registry serves.
+	PinDigests bool `yaml:"pinDigests,omitempty" json:"pinDigests,omitempty"`
+}
+
+// ImageMirror maps an image prefix to its replacement.
+type ImageMirror struct {
+	From string `yaml:"from" json:"from"`
+	To   string `yaml:"to" json:"to"`
+}
+
+// KustomizePatch mirrors a kustomization.yaml patches entry.
+type KustomizePatch struct {
+	Path   string       `yaml:"path,omitempty"`
+	Patch  string       `yaml:"patch,omitempty"`
+	Target *PatchTarget `yaml:"target,omitempty"`
+}
+
+// PatchTarget selects the resources a patch applies to.
+type PatchTarget struct {
+	Group              string `yaml:"group,omitempty"`
+	Version            string `yaml:"version,omitempty"`
+	Kind               string `yaml:"kind,omitempty"`
+	Name               string `yaml:"name,omitempty"`
+	Namespace          string `yaml:"namespace,omitempty"`
+	LabelSelector      string `yaml:"labelSelector,omitempty"`
+	AnnotationSelector string `yaml:"annotationSelector,omitempty"`
+}
+
+func mergeDeploy(a, b DeployConfig) DeployConfig {
+	out := a
+	if len(b.PostRenderers) > 0 {
+		out.PostRenderers = b.PostRenderers
+	}
+	if b.ApprovalPolicy != "" {
+		out.ApprovalPolicy = b.ApprovalPolicy
+	}
+	if len(b.Approvers) > 0 {
+		out.Approvers = b.Approvers
+	}
+	if len(b.Windows) > 0 {
+		out.Windows = b.Windows
+	}
+	if len(b.Owners) > 0 {
+		out.Owners = b.Owners
+	}
+	return out
+}
+
+// resolveDeployPaths makes relative exec and patch paths relative to the config file's directory.
+func resolveDeployPaths(cfg *DeployConfig, dir string) {
+	for i := range cfg.PostRenderers {
+		pr := &cfg.PostRenderers[i]
+		if exec := strings.TrimSpace(pr.Exec); exec != "" && strings.ContainsRune(exec, '/') && !filepath.IsAbs(exec) {
+			pr.Exec = filepath.Join(dir, exec)
+		}
+		for j := range pr.Patches {
+			if p := strings.TrimSpace(pr.Patches[j].Path); p != "" && !filepath.IsAbs(p) {
+				pr.Patches[j].Path = filepath.Join(dir, p)
+			}
+		}
+	}
+}
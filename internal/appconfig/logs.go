@@ -0,0 +1,56 @@
+package appconfig
+
+import "sort"
+
+// LogsConfig holds `ktl logs` settings, currently the named tail profiles.
+type LogsConfig struct {
+	Profiles map[string]LogProfile `yaml:"profiles,omitempty"`
+}
+
+// LogProfile is a saved log query invoked as `ktl logs @<name>`.
+// Unset fields leave the corresponding flag at its default; explicit flags always win.
+type LogProfile struct {
+	Description       string   `yaml:"description,omitempty"`
+	Query             string   `yaml:"query,omitempty"`
+	Namespaces        []string `yaml:"namespaces,omitempty"`
+	AllNamespaces     *bool    `yaml:"allNamespaces,omitempty"`
+	Selector          string   `yaml:"selector,omitempty"`
+	Workload          string   `yaml:"for,omitempty"`
+	Containers        []string `yaml:"containers,omitempty"`
+	ExcludeContainers []string `yaml:"excludeContainers,omitempty"`
+	ExcludePods       []string `yaml:"excludePods,omitempty"`
+	Exclude           string   `yaml:"exclude,omitempty"`
+	Highlight         []string `yaml:"highlight,omitempty"`
+	Filter            string   `yaml:"filter,omitempty"`
+	Template          string   `yaml:"template,omitempty"`
+	Output            string   `yaml:"output,omitempty"`
+	Since             string   `yaml:"since,omitempty"`
+	Tail              *int64   `yaml:"tail,omitempty"`
+	Events            *bool    `yaml:"events,omitempty"`
+	Anomalies         *bool    `yaml:"anomalies,omitempty"`
+}
+
+// ProfileNames returns the configured profile names in sorted order.
+func (c LogsConfig) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func mergeLogs(a, b LogsConfig) LogsConfig {
+	out := a
+	if len(b.Profiles) > 0 {
+		profiles := make(map[string]LogProfile, len(a.Profiles)+len(b.Profiles))
+		for name, p := range a.Profiles {
+			profiles[name] = p
+		}
+		for name, p := range b.Profiles {
+			profiles[name] = p
+		}
+		out.Profiles = profiles
+	}
+	return out
+}
@@ -4,6 +4,30 @@ package appconfig
 type SecretsConfig struct {
 	DefaultProvider string                    `yaml:"defaultProvider,omitempty"`
 	Providers       map[string]SecretProvider `yaml:"providers,omitempty"`
+	// Registries maps a chart registry or repository host to pull credentials.
+	Registries map[string]RegistryCredential `yaml:"registries,omitempty"`
+	// Seal holds defaults for ktl secrets seal.
+	Seal SealConfig `yaml:"seal,omitempty"`
+}
+
+// SealConfig selects how ktl secrets seal encrypts manifests: with the sealed-secrets controller
+// certificate (fetched from the cluster unless Cert is set) or with sops age/KMS recipients.
+type SealConfig struct {
+	Format              string   `yaml:"format,omitempty"`
+	Cert                string   `yaml:"cert,omitempty"`
+	ControllerNamespace string   `yaml:"controllerNamespace,omitempty"`
+	ControllerName      string   `yaml:"controllerName,omitempty"`
+	Age                 []string `yaml:"age,omitempty"`
+	KMS                 []string `yaml:"kms,omitempty"`
+}
+
+// RegistryCredential authenticates chart pulls from one host. Username and Password may be
+// secret:// references.
+type RegistryCredential struct {
+	Username    string `yaml:"username,omitempty"`
+	Password    string `yaml:"password,omitempty"`
+	UsernameEnv string `yaml:"usernameEnv,omitempty"`
+	PasswordEnv string `yaml:"passwordEnv,omitempty"`
 }
 
 // SecretProvider defines a single secret provider.
@@ -41,5 +65,37 @@ func mergeSecrets(a, b SecretsConfig) SecretsConfig {
 			out.Providers[name] = cfg
 		}
 	}
+	if len(b.Registries) > 0 {
+		if out.Registries == nil {
+			out.Registries = map[string]RegistryCredential{}
+		}
+		for host, cred := range b.Registries {
+			out.Registries[host] = cred
+		}
+	}
+	out.Seal = mergeSeal(a.Seal, b.Seal)
+	return out
+}
+
+func mergeSeal(a, b SealConfig) SealConfig {
+	out := a
+	if b.Format != "" {
+		out.Format = b.Format
+	}
+	if b.Cert != "" {
+		out.Cert = b.Cert
+	}
+	if b.ControllerNamespace != "" {
+		out.ControllerNamespace = b.ControllerNamespace
+	}
+	if b.ControllerName != "" {
+		out.ControllerName = b.ControllerName
+	}
+	if len(b.Age) > 0 {
+		out.Age = append([]string(nil), b.Age...)
+	}
+	if len(b.KMS) > 0 {
+		out.KMS = append([]string(nil), b.KMS...)
+	}
 	return out
 }
@@ -0,0 +1,62 @@
+package appconfig
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ValueSource is a named external lookup referenced from chart values as valuefrom://<name>/<key>.
+type ValueSource struct {
+	Type string `yaml:"type"` // exec|http|consul|etcd
+	// Command runs for exec sources with the key appended as the last argument (and in
+	// KTL_VALUE_KEY); its trimmed stdout is the value.
+	Command []string `yaml:"command,omitempty"`
+	// URL is the http endpoint; "{key}" is replaced by the key, otherwise the key is appended as
+	// a path segment.
+	URL string `yaml:"url,omitempty"`
+	// Address is the Consul or etcd endpoint, e.g. http://127.0.0.1:8500.
+	Address string `yaml:"address,omitempty"`
+	// Prefix is prepended to every key before the lookup.
+	Prefix string `yaml:"prefix,omitempty"`
+	// Headers are sent with http requests.
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// TokenEnv names an environment variable holding a token: a bearer token for http, the ACL
+	// token for Consul.
+	TokenEnv string `yaml:"tokenEnv,omitempty"`
+	// Timeout bounds a single lookup (default 10s).
+	Timeout string `yaml:"timeout,omitempty"`
+	// CacheTTL keeps looked-up values for this long within one ktl invocation (default: the whole
+	// invocation; 0s disables caching).
+	CacheTTL string `yaml:"cacheTTL,omitempty"`
+	// Format decodes looked-up values: text keeps them as strings, json and yaml keep their type.
+	Format string `yaml:"format,omitempty"` // text (default)|json|yaml
+}
+
+func mergeValueSources(a, b map[string]ValueSource) map[string]ValueSource {
+	if len(b) == 0 {
+		return a
+	}
+	out := make(map[string]ValueSource, len(a)+len(b))
+	for name, src := range a {
+		out[name] = src
+	}
+	for name, src := range b {
+		out[name] = src
+	}
+	return out
+}
+
+// resolveValueSourcePaths makes relative exec commands relative to the config file's directory.
+func resolveValueSourcePaths(sources map[string]ValueSource, dir string) {
+	for name, src := range sources {
+		if len(src.Command) == 0 {
+			continue
+		}
+		if exec := strings.TrimSpace(src.Command[0]); strings.ContainsRune(exec, '/') && !filepath.IsAbs(exec) {
+			cmd := append([]string(nil), src.Command...)
+			cmd[0] = filepath.Join(dir, exec)
+			src.Command = cmd
+			sources[name] = src
+		}
+	}
+}
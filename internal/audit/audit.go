@@ -0,0 +1,246 @@
+// File: internal/audit/audit.go
+// Brief: Append-only JSONL audit log of mutating ktl operations.
+
+// Package audit records every mutating ktl action (who, where, what, result) to a local JSONL file
+// and optionally forwards entries to a remote HTTP endpoint.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvPath overrides the local audit log path.
+	EnvPath = "KTL_AUDIT_LOG"
+	// EnvEndpoint enables forwarding each entry as a JSON POST to this URL.
+	EnvEndpoint = "KTL_AUDIT_ENDPOINT"
+	// EnvToken is sent as a bearer token to EnvEndpoint.
+	EnvToken = "KTL_AUDIT_TOKEN"
+	// EnvDisable turns auditing off when set to 0/false/off.
+	EnvDisable = "KTL_AUDIT"
+)
+
+const (
+	ResultSuccess   = "success"
+	ResultFailure   = "failure"
+	ResultCancelled = "cancelled"
+)
+
+// Entry is one audited command invocation.
+type Entry struct {
+	Time        time.Time         `json:"time"`
+	User        string            `json:"user,omitempty"`
+	Host        string            `json:"host,omitempty"`
+	Command     string            `json:"command"`
+	Args        []string          `json:"args,omitempty"`
+	Flags       map[string]string `json:"flags,omitempty"`
+	KubeContext string            `json:"kubeContext,omitempty"`
+	Namespace   string            `json:"namespace,omitempty"`
+	DryRun      bool              `json:"dryRun,omitempty"`
+	Result      string            `json:"result"`
+	Error       string            `json:"error,omitempty"`
+	DurationMS  int64             `json:"durationMs"`
+	Approval    *Approval         `json:"approval,omitempty"`
+	// WindowOverride is set when the command deployed outside a maintenance window.
+	WindowOverride *WindowOverride `json:"windowOverride,omitempty"`
+}
+
+// WindowOverride records why a deploy ran outside its maintenance window.
+type WindowOverride struct {
+	Reason   string    `json:"reason"`
+	Windows  []string  `json:"windows"`
+	Targets  []string  `json:"targets,omitempty"`
+	NextSlot time.Time `json:"nextSlot,omitempty"`
+}
+
+// Approval records the second approver of an apply made under an approval policy.
+type Approval struct {
+	Policy     string    `json:"policy"`
+	PlanDigest string    `json:"planDigest"`
+	Approver   string    `json:"approver"`
+	IssuedAt   time.Time `json:"issuedAt"`
+	RiskTags   []string  `json:"riskTags,omitempty"`
+}
+
+// Options configure where entries are written.
+type Options struct {
+	Path     string
+	Endpoint string
+	Token    string
+	Disabled bool
+}
+
+// DefaultPath returns ~/.ktl/audit.jsonl.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir for audit log: %w", err)
+	}
+	return filepath.Join(home, ".ktl", "audit.jsonl"), nil
+}
+
+// OptionsFromEnv resolves Options from KTL_AUDIT* environment variables.
+func OptionsFromEnv() Options {
+	opts := Options{
+		Path:     strings.TrimSpace(os.Getenv(EnvPath)),
+		Endpoint: strings.TrimSpace(os.Getenv(EnvEndpoint)),
+		Token:    strings.TrimSpace(os.Getenv(EnvToken)),
+	}
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvDisable))) {
+	case "0", "false", "off", "no":
+		opts.Disabled = true
+	}
+	if opts.Path == "" {
+		opts.Path, _ = DefaultPath()
+	}
+	return opts
+}
+
+// Append writes e to the local log and, when configured, the remote endpoint. Both sinks are
+// attempted; errors are joined.
+func Append(ctx context.Context, opts Options, e Entry) error {
+	if opts.Disabled {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	if path := strings.TrimSpace(opts.Path); path != "" {
+		if err := appendLine(path, line); err != nil {
+			errs = append(errs, fmt.Errorf("write audit log: %w", err))
+		}
+	}
+	if endpoint := strings.TrimSpace(opts.Endpoint); endpoint != "" {
+		if err := post(ctx, endpoint, opts.Token, line); err != nil {
+			errs = append(errs, fmt.Errorf("send audit entry: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func appendLine(path string, line []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func post(ctx context.Context, endpoint, token string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("POST %s: %s", req.URL.Redacted(), resp.Status)
+	}
+	return nil
+}
+
+// Read returns every entry in the log at path, oldest first. A missing file yields no entries.
+// Malformed lines are skipped so a partially written line never hides the rest of the log.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var out []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil || e.Command == "" {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
+
+// Filter selects entries for `ktl audit search`. Empty fields match everything.
+type Filter struct {
+	Command  string
+	User     string
+	Context  string
+	Result   string
+	Contains string
+	Since    time.Time
+}
+
+// Match reports whether e satisfies f. Command matches by substring; User, Context, and Result
+// are exact (case-insensitive); Contains searches the serialized entry.
+func (f Filter) Match(e Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if f.Command != "" && !strings.Contains(strings.ToLower(e.Command), strings.ToLower(f.Command)) {
+		return false
+	}
+	if f.User != "" && !strings.EqualFold(e.User, f.User) {
+		return false
+	}
+	if f.Context != "" && !strings.EqualFold(e.KubeContext, f.Context) {
+		return false
+	}
+	if f.Result != "" && !strings.EqualFold(e.Result, f.Result) {
+		return false
+	}
+	if f.Contains != "" {
+		raw, _ := json.Marshal(e)
+		if !strings.Contains(strings.ToLower(string(raw)), strings.ToLower(f.Contains)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Search returns entries matching f, oldest first, keeping only the newest limit (0 = all).
+func Search(entries []Entry, f Filter, limit int) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if f.Match(e) {
+			out = append(out, e)
+		}
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendReadAndSearch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "audit.jsonl")
+	opts := Options{Path: path}
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := []Entry{
+		{Time: base, User: "alice", Command: "ktl apply", KubeContext: "prod", Result: ResultSuccess, Flags: map[string]string{"release": "checkout"}},
+		{Time: base.Add(time.Hour), User: "bob", Command: "ktl stack delete", KubeContext: "dev", Result: ResultFailure, Error: "boom"},
+		{Time: base.Add(2 * time.Hour), User: "alice", Command: "ktl apply", KubeContext: "prod", Result: ResultFailure},
+	}
+	for _, e := range entries {
+		if err := Append(context.Background(), opts, e); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	// A torn trailing line must not hide earlier entries.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"command":"ktl ap`)
+	_ = f.Close()
+
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(got))
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 audit log, got %v (%v)", info.Mode().Perm(), err)
+	}
+
+	if res := Search(got, Filter{Command: "apply", Result: "failure"}, 0); len(res) != 1 || !res[0].Time.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("unexpected command/result search: %+v", res)
+	}
+	if res := Search(got, Filter{Contains: "CHECKOUT"}, 0); len(res) != 1 || res[0].User != "alice" {
+		t.Fatalf("unexpected text search: %+v", res)
+	}
+	if res := Search(got, Filter{Since: base.Add(30 * time.Minute)}, 1); len(res) != 1 || res[0].User != "alice" {
+		t.Fatalf("expected newest entry only, got %+v", res)
+	}
+	if missing, err := Read(filepath.Join(t.TempDir(), "missing.jsonl")); err != nil || missing != nil {
+		t.Fatalf("expected empty read for missing log, got %v %v", missing, err)
+	}
+}
+
+func TestAppendForwardsToEndpoint(t *testing.T) {
+	var received Entry
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	err := Append(context.Background(), Options{Endpoint: srv.URL, Token: "tok"}, Entry{Command: "ktl delete", Result: ResultSuccess})
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if received.Command != "ktl delete" || received.Time.IsZero() || auth != "Bearer tok" {
+		t.Fatalf("unexpected forwarded entry %+v auth=%q", received, auth)
+	}
+	if err := Append(context.Background(), Options{Endpoint: srv.URL, Disabled: true}, Entry{Command: "x"}); err != nil {
+		t.Fatalf("disabled append should be a no-op: %v", err)
+	}
+}
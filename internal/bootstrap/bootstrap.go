@@ -0,0 +1,156 @@
+// File: internal/bootstrap/bootstrap.go
+// Brief: bootstrap.yaml schema and loading for `ktl bootstrap`.
+
+// Package bootstrap applies an ordered bundle of foundational cluster components (CRDs,
+// namespaces, RBAC, controller charts) before stacks are applied.
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFileName is the bundle file looked up when no path is given.
+const DefaultFileName = "bootstrap.yaml"
+
+// Bundle is the parsed bootstrap.yaml.
+type Bundle struct {
+	APIVersion string `yaml:"apiVersion,omitempty"`
+	Kind       string `yaml:"kind,omitempty"`
+	Name       string `yaml:"name,omitempty"`
+	Steps      []Step `yaml:"steps"`
+
+	// Dir is the directory containing the bundle; relative paths resolve against it.
+	Dir string `yaml:"-"`
+}
+
+// Step is one ordered bootstrap step. Exactly one of Namespaces, Manifests, or Chart is set.
+type Step struct {
+	Name string `yaml:"name"`
+
+	// Namespaces are created (server-side applied) if missing.
+	Namespaces []string `yaml:"namespaces,omitempty"`
+	// Manifests are files or directories of YAML applied with server-side apply.
+	Manifests []string `yaml:"manifests,omitempty"`
+	// Namespace is the default namespace for namespaced manifest objects and the chart release.
+	Namespace string     `yaml:"namespace,omitempty"`
+	Chart     *ChartStep `yaml:"chart,omitempty"`
+
+	WaitFor WaitFor `yaml:"waitFor,omitempty"`
+}
+
+// ChartStep installs or upgrades a Helm release.
+type ChartStep struct {
+	Chart           string            `yaml:"chart"`
+	Version         string            `yaml:"version,omitempty"`
+	Release         string            `yaml:"release"`
+	Values          []string          `yaml:"values,omitempty"`
+	Set             map[string]string `yaml:"set,omitempty"`
+	CreateNamespace *bool             `yaml:"createNamespace,omitempty"`
+	Wait            *bool             `yaml:"wait,omitempty"`
+	Timeout         time.Duration     `yaml:"timeout,omitempty"`
+}
+
+// WaitFor gates the next step until the listed resources are ready.
+type WaitFor struct {
+	// CRDs are CustomResourceDefinition names that must report Established.
+	CRDs    []string      `yaml:"crds,omitempty"`
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// Kind reports which action the step performs.
+func (s Step) Kind() string {
+	switch {
+	case s.Chart != nil:
+		return "chart"
+	case len(s.Manifests) > 0:
+		return "manifests"
+	case len(s.Namespaces) > 0:
+		return "namespaces"
+	default:
+		return "wait"
+	}
+}
+
+// Load reads and validates a bootstrap bundle. A directory argument loads its bootstrap.yaml.
+func Load(path string) (*Bundle, error) {
+	if strings.TrimSpace(path) == "" {
+		path = DefaultFileName
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, DefaultFileName)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b Bundle
+	if err := yaml.Unmarshal(raw, &b); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if b.Kind != "" && b.Kind != "Bootstrap" {
+		return nil, fmt.Errorf("%s: kind must be Bootstrap (got %q)", path, b.Kind)
+	}
+	if b.APIVersion != "" && b.APIVersion != "ktl.dev/v1" {
+		return nil, fmt.Errorf("%s: apiVersion must be ktl.dev/v1 (got %q)", path, b.APIVersion)
+	}
+	abs, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	b.Dir = abs
+	if err := b.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &b, nil
+}
+
+func (b *Bundle) validate() error {
+	if len(b.Steps) == 0 {
+		return fmt.Errorf("steps is empty")
+	}
+	seen := map[string]bool{}
+	for i, s := range b.Steps {
+		name := strings.TrimSpace(s.Name)
+		if name == "" {
+			return fmt.Errorf("steps[%d].name is required", i)
+		}
+		if seen[name] {
+			return fmt.Errorf("steps[%d]: duplicate step name %q", i, name)
+		}
+		seen[name] = true
+		actions := 0
+		if len(s.Namespaces) > 0 {
+			actions++
+		}
+		if len(s.Manifests) > 0 {
+			actions++
+		}
+		if s.Chart != nil {
+			actions++
+			if strings.TrimSpace(s.Chart.Chart) == "" || strings.TrimSpace(s.Chart.Release) == "" {
+				return fmt.Errorf("steps[%d] (%s): chart.chart and chart.release are required", i, name)
+			}
+		}
+		if actions > 1 {
+			return fmt.Errorf("steps[%d] (%s): set only one of namespaces, manifests, or chart", i, name)
+		}
+		if actions == 0 && len(s.WaitFor.CRDs) == 0 {
+			return fmt.Errorf("steps[%d] (%s): nothing to do", i, name)
+		}
+	}
+	return nil
+}
+
+// resolvePath makes p absolute relative to the bundle directory.
+func (b *Bundle) resolvePath(p string) string {
+	if filepath.IsAbs(p) || b.Dir == "" {
+		return p
+	}
+	return filepath.Join(b.Dir, p)
+}
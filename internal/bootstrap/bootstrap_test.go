@@ -0,0 +1,98 @@
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadBundle(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, DefaultFileName), `apiVersion: ktl.dev/v1
+kind: Bootstrap
+steps:
+  - name: crds
+    manifests: [./crds]
+    waitFor:
+      crds: [certificates.cert-manager.io]
+      timeout: 2m
+  - name: namespaces
+    namespaces: [ingress, cert-manager]
+  - name: ingress
+    namespace: ingress
+    chart:
+      chart: ./charts/ingress
+      release: ingress-nginx
+      set:
+        controller.replicaCount: "2"
+`)
+	b, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(b.Steps) != 3 || b.Dir != dir {
+		t.Fatalf("unexpected bundle %+v", b)
+	}
+	kinds := []string{b.Steps[0].Kind(), b.Steps[1].Kind(), b.Steps[2].Kind()}
+	if strings.Join(kinds, ",") != "manifests,namespaces,chart" {
+		t.Fatalf("unexpected step kinds %v", kinds)
+	}
+	if b.Steps[0].WaitFor.Timeout != 2*time.Minute {
+		t.Fatalf("expected waitFor timeout, got %s", b.Steps[0].WaitFor.Timeout)
+	}
+}
+
+func TestLoadBundleRejectsAmbiguousSteps(t *testing.T) {
+	cases := map[string]string{
+		"empty":     "steps: []\n",
+		"noname":    "steps:\n  - namespaces: [a]\n",
+		"duplicate": "steps:\n  - name: a\n    namespaces: [a]\n  - name: a\n    namespaces: [b]\n",
+		"multiple":  "steps:\n  - name: a\n    namespaces: [a]\n    manifests: [x.yaml]\n",
+		"nothing":   "steps:\n  - name: a\n",
+		"chart":     "steps:\n  - name: a\n    chart:\n      chart: ./c\n",
+		"kind":      "kind: Stack\nsteps:\n  - name: a\n    namespaces: [a]\n",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "bootstrap.yaml")
+			writeFile(t, path, content)
+			if _, err := Load(path); err == nil {
+				t.Fatalf("expected validation error")
+			}
+		})
+	}
+}
+
+func TestManifestFilesExpandsDirectoriesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "crds", "b.yaml"), "")
+	writeFile(t, filepath.Join(dir, "crds", "a.yml"), "")
+	writeFile(t, filepath.Join(dir, "crds", "nested", "c.json"), "")
+	writeFile(t, filepath.Join(dir, "crds", "README.md"), "")
+	writeFile(t, filepath.Join(dir, "rbac.yaml"), "")
+	b := &Bundle{Dir: dir}
+	files, err := manifestFiles(b, []string{"crds", "rbac.yaml"})
+	if err != nil {
+		t.Fatalf("manifestFiles: %v", err)
+	}
+	var rel []string
+	for _, f := range files {
+		r, _ := filepath.Rel(dir, f)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	if got := strings.Join(rel, ","); got != "crds/a.yml,crds/b.yaml,crds/nested/c.json,rbac.yaml" {
+		t.Fatalf("unexpected files %s", got)
+	}
+}
@@ -0,0 +1,191 @@
+// File: internal/bootstrap/run.go
+// Brief: Ordered execution of bootstrap steps with CRD readiness gates.
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+)
+
+// DefaultWaitTimeout bounds waitFor gates that do not set their own timeout.
+const DefaultWaitTimeout = 5 * time.Minute
+
+// ChartInstaller installs or upgrades the release described by chart. Relative local chart paths
+// and values files are already resolved against the bundle directory.
+type ChartInstaller func(ctx context.Context, namespace string, chart ChartStep) error
+
+// Options configure Run.
+type Options struct {
+	Client       *kube.Client
+	InstallChart ChartInstaller
+	// DryRun server-side dry-runs manifests and skips waitFor gates. InstallChart is expected to
+	// honor dry-run on its own.
+	DryRun bool
+	// FieldManager defaults to "ktl-bootstrap".
+	FieldManager string
+	Out          io.Writer
+}
+
+// StepResult reports one executed step.
+type StepResult struct {
+	Name     string
+	Kind     string
+	Applied  []string
+	Duration time.Duration
+	Err      error
+}
+
+// Run executes the bundle steps in order and stops at the first failure. Every step is
+// idempotent (server-side apply / helm upgrade --install), so re-running a bundle is safe.
+func Run(ctx context.Context, b *Bundle, opts Options) ([]StepResult, error) {
+	if b == nil {
+		return nil, fmt.Errorf("bootstrap bundle is required")
+	}
+	out := opts.Out
+	if out == nil {
+		out = io.Discard
+	}
+	fieldManager := strings.TrimSpace(opts.FieldManager)
+	if fieldManager == "" {
+		fieldManager = "ktl-bootstrap"
+	}
+	results := make([]StepResult, 0, len(b.Steps))
+	for i, step := range b.Steps {
+		started := time.Now()
+		res := StepResult{Name: step.Name, Kind: step.Kind()}
+		fmt.Fprintf(out, "[%d/%d] %s (%s)\n", i+1, len(b.Steps), step.Name, res.Kind)
+		applied, err := runStep(ctx, b, step, opts, fieldManager, out)
+		res.Applied = applied
+		res.Duration = time.Since(started)
+		res.Err = err
+		results = append(results, res)
+		if err != nil {
+			return results, fmt.Errorf("step %s: %w", step.Name, err)
+		}
+	}
+	return results, nil
+}
+
+func runStep(ctx context.Context, b *Bundle, step Step, opts Options, fieldManager string, out io.Writer) ([]string, error) {
+	var applied []string
+	applyOpts := kube.ApplyOptions{FieldManager: fieldManager, Namespace: step.Namespace, Force: true, DryRun: opts.DryRun}
+	switch step.Kind() {
+	case "namespaces":
+		var doc strings.Builder
+		for _, ns := range step.Namespaces {
+			fmt.Fprintf(&doc, "---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: %s\n", strings.TrimSpace(ns))
+		}
+		objs, err := kube.ApplyManifest(ctx, opts.Client, []byte(doc.String()), applyOpts)
+		if err != nil {
+			return nil, err
+		}
+		applied = appliedNames(objs)
+	case "manifests":
+		files, err := manifestFiles(b, step.Manifests)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				return applied, err
+			}
+			objs, err := kube.ApplyManifest(ctx, opts.Client, raw, applyOpts)
+			applied = append(applied, appliedNames(objs)...)
+			if err != nil {
+				return applied, fmt.Errorf("%s: %w", file, err)
+			}
+		}
+	case "chart":
+		if opts.InstallChart == nil {
+			return nil, fmt.Errorf("chart steps are not supported without a chart installer")
+		}
+		chart := *step.Chart
+		if isLocalPath(chart.Chart) {
+			chart.Chart = b.resolvePath(chart.Chart)
+		}
+		chart.Values = make([]string, 0, len(step.Chart.Values))
+		for _, v := range step.Chart.Values {
+			chart.Values = append(chart.Values, b.resolvePath(v))
+		}
+		if err := opts.InstallChart(ctx, step.Namespace, chart); err != nil {
+			return nil, err
+		}
+		applied = []string{"release/" + chart.Release}
+	}
+	for _, line := range applied {
+		fmt.Fprintf(out, "  applied %s\n", line)
+	}
+	if opts.DryRun || len(step.WaitFor.CRDs) == 0 {
+		return applied, nil
+	}
+	timeout := step.WaitFor.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	for _, crd := range step.WaitFor.CRDs {
+		fmt.Fprintf(out, "  waiting for crd/%s\n", crd)
+		if err := kube.WaitForCRDEstablished(ctx, opts.Client.Dynamic, crd, timeout); err != nil {
+			return applied, err
+		}
+	}
+	return applied, nil
+}
+
+// manifestFiles expands files and directories (recursively) into a sorted list of YAML/JSON files.
+func manifestFiles(b *Bundle, paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		p = b.resolvePath(p)
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		var found []string
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".yaml", ".yml", ".json":
+				found = append(found, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+func appliedNames(objs []kube.AppliedObject) []string {
+	out := make([]string, 0, len(objs))
+	for _, o := range objs {
+		out = append(out, o.String())
+	}
+	return out
+}
+
+func isLocalPath(ref string) bool {
+	return strings.HasPrefix(ref, "./") || strings.HasPrefix(ref, "../") || strings.HasPrefix(ref, "/")
+}
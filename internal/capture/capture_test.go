@@ -12,6 +12,7 @@ import (
 
 	"github.com/kubekattle/ktl/internal/deploy"
 	"github.com/kubekattle/ktl/internal/tailer"
+	"github.com/kubekattle/ktl/internal/traffic"
 )
 
 func TestRecorderWritesSessionAndEvents(t *testing.T) {
@@ -91,3 +92,29 @@ func TestRecorderWritesSessionAndEvents(t *testing.T) {
 		t.Fatalf("expected 1 artifact, got %d", artifactCount)
 	}
 }
+
+func TestRecorderStoresHTTPExchanges(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "tap.sqlite")
+	rec, err := Open(dbPath, SessionMeta{Command: "ktl traffic tap"})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	rec.ObserveExchange(traffic.Exchange{Start: time.Now(), Target: "prod/api-0:8080", Method: "GET", Path: "/ok", Status: 200})
+	rec.ObserveExchange(traffic.Exchange{Start: time.Now(), Target: "prod/api-0:8080", Method: "GET", Path: "/boom", Status: 503})
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	var httpCount, errorCount int
+	if err := db.QueryRow(`SELECT COUNT(*), SUM(level = 'error') FROM ktl_capture_events WHERE kind = 'http'`).Scan(&httpCount, &errorCount); err != nil {
+		t.Fatalf("count http events: %v", err)
+	}
+	if httpCount != 2 || errorCount != 1 {
+		t.Fatalf("expected 2 http events (1 error), got %d (%d errors)", httpCount, errorCount)
+	}
+}
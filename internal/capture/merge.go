@@ -0,0 +1,207 @@
+package capture
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MergeStats summarizes a Merge.
+type MergeStats struct {
+	Sessions int
+	// Rekeyed sessions got a new session ID because the output already had theirs.
+	Rekeyed   int
+	Events    int64
+	Artifacts int64
+}
+
+// mergeTables are copied per session, in foreign-key order. Autoincrement ids are not copied.
+var mergeTables = []string{"ktl_capture_sessions", "ktl_capture_events", "ktl_capture_artifacts", "ktl_capture_tags"}
+
+// Merge copies every session from inputs into the capture database at out, creating it if
+// needed. A session whose ID is already present in out is given a new ID, so the same capture can
+// be merged twice without clobbering anything. Inputs written by older ktl versions are read as-is.
+func Merge(ctx context.Context, out string, inputs []string) (MergeStats, error) {
+	var stats MergeStats
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return stats, fmt.Errorf("output capture path is required")
+	}
+	if len(inputs) == 0 {
+		return stats, fmt.Errorf("at least one input capture is required")
+	}
+	outAbs, _ := filepath.Abs(out)
+	for _, in := range inputs {
+		if _, err := os.Stat(in); err != nil {
+			return stats, fmt.Errorf("open capture: %w", err)
+		}
+		if inAbs, _ := filepath.Abs(in); inAbs == outAbs {
+			return stats, fmt.Errorf("%s is both an input and the output", in)
+		}
+	}
+	if dir := filepath.Dir(out); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return stats, fmt.Errorf("create capture dir: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", out)
+	if err != nil {
+		return stats, fmt.Errorf("open sqlite: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	if err := migrate(ctx, db); err != nil {
+		_ = db.Close()
+		return stats, err
+	}
+	for _, in := range inputs {
+		if err := mergeOne(ctx, db, in, &stats); err != nil {
+			_ = db.Close()
+			return stats, fmt.Errorf("merge %s: %w", in, err)
+		}
+	}
+	_, checkpointErr := db.ExecContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE);`)
+	if err := db.Close(); err != nil {
+		return stats, err
+	}
+	if checkpointErr == nil {
+		_ = cleanupWALSidecars(out)
+	}
+	return stats, checkpointErr
+}
+
+func mergeOne(ctx context.Context, db *sql.DB, path string, stats *MergeStats) error {
+	if _, err := db.ExecContext(ctx, `ATTACH DATABASE ? AS src`, path); err != nil {
+		return fmt.Errorf("attach: %w", err)
+	}
+	defer db.ExecContext(context.Background(), `DETACH DATABASE src`)
+
+	columns := map[string][]string{}
+	for _, table := range mergeTables {
+		cols, err := sharedColumns(ctx, db, table)
+		if err != nil {
+			return err
+		}
+		columns[table] = cols
+	}
+	if len(columns["ktl_capture_sessions"]) == 0 {
+		return fmt.Errorf("not a ktl capture (no sessions table)")
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT session_id FROM src.ktl_capture_sessions ORDER BY started_at`)
+	if err != nil {
+		return fmt.Errorf("list sessions: %w", err)
+	}
+	var sessions []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		sessions = append(sessions, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, id := range sessions {
+		newID := id
+		var exists int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM main.ktl_capture_sessions WHERE session_id = ?`, id).Scan(&exists); err != nil {
+			return err
+		}
+		if exists > 0 {
+			if newID, err = randomID(); err != nil {
+				return err
+			}
+			stats.Rekeyed++
+		}
+		for _, table := range mergeTables {
+			cols := columns[table]
+			if len(cols) == 0 {
+				continue
+			}
+			selected := make([]string, len(cols))
+			for i, c := range cols {
+				selected[i] = c
+				if c == "session_id" {
+					selected[i] = "?"
+				}
+			}
+			res, err := tx.ExecContext(ctx, fmt.Sprintf(
+				`INSERT INTO main.%s(%s) SELECT %s FROM src.%s WHERE session_id = ? ORDER BY rowid`,
+				table, strings.Join(cols, ", "), strings.Join(selected, ", "), table,
+			), newID, id)
+			if err != nil {
+				return fmt.Errorf("copy %s: %w", table, err)
+			}
+			n, _ := res.RowsAffected()
+			switch table {
+			case "ktl_capture_events":
+				stats.Events += n
+			case "ktl_capture_artifacts":
+				stats.Artifacts += n
+			}
+		}
+		stats.Sessions++
+	}
+	return tx.Commit()
+}
+
+// sharedColumns returns the columns of table present in both the output and the attached source,
+// minus the autoincrement id.
+func sharedColumns(ctx context.Context, db *sql.DB, table string) ([]string, error) {
+	src, err := tableColumns(ctx, db, "src", table)
+	if err != nil {
+		return nil, err
+	}
+	have := map[string]bool{}
+	for _, c := range src {
+		have[c] = true
+	}
+	dst, err := tableColumns(ctx, db, "main", table)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, c := range dst {
+		if c != "id" && have[c] {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func tableColumns(ctx context.Context, db *sql.DB, schema, table string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`PRAGMA %s.table_info(%s)`, schema, table))
+	if err != nil {
+		return nil, fmt.Errorf("inspect %s.%s: %w", schema, table, err)
+	}
+	defer rows.Close()
+	var cols []string
+	for rows.Next() {
+		var (
+			cid     int
+			name    string
+			typ     string
+			notNull int
+			dflt    sql.NullString
+			pk      int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			return nil, err
+		}
+		cols = append(cols, name)
+	}
+	return cols, rows.Err()
+}
@@ -0,0 +1,66 @@
+package capture
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/tailer"
+)
+
+func writeTestCapture(t *testing.T, path, command string, logs int) {
+	t.Helper()
+	rec, err := Open(path, SessionMeta{Command: command, Tags: map[string]string{"train": "2026-10"}})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for i := 0; i < logs; i++ {
+		rec.ObserveLog(tailer.LogRecord{Timestamp: time.Now().UTC(), Source: "pod", Rendered: command})
+	}
+	if err := rec.RecordArtifact(context.Background(), "rendered_manifest", "kind: ConfigMap\n"); err != nil {
+		t.Fatalf("RecordArtifact: %v", err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestMergeRekeysDuplicateSessions(t *testing.T) {
+	dir := t.TempDir()
+	a, b, out := filepath.Join(dir, "a.sqlite"), filepath.Join(dir, "b.sqlite"), filepath.Join(dir, "out", "merged.sqlite")
+	writeTestCapture(t, a, "ktl apply", 2)
+	writeTestCapture(t, b, "ktl logs", 3)
+
+	stats, err := Merge(context.Background(), out, []string{a, b, a})
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if stats.Sessions != 3 || stats.Rekeyed != 1 || stats.Events != 7 || stats.Artifacts != 3 {
+		t.Fatalf("stats=%+v", stats)
+	}
+
+	db, err := sql.Open("sqlite", out)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	var sessions, orphans, tags int
+	if err := db.QueryRow(`SELECT COUNT(DISTINCT session_id) FROM ktl_capture_sessions`).Scan(&sessions); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM ktl_capture_events e LEFT JOIN ktl_capture_sessions s ON s.session_id = e.session_id WHERE s.session_id IS NULL`).Scan(&orphans); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM ktl_capture_tags WHERE key = 'train'`).Scan(&tags); err != nil {
+		t.Fatal(err)
+	}
+	if sessions != 3 || orphans != 0 || tags != 3 {
+		t.Fatalf("sessions=%d orphans=%d tags=%d", sessions, orphans, tags)
+	}
+
+	if _, err := Merge(context.Background(), a, []string{a}); err == nil {
+		t.Fatalf("expected merging a capture into itself to fail")
+	}
+}
@@ -0,0 +1,157 @@
+package capture
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Report is a canned query over a capture database.
+type Report struct {
+	Name        string
+	Description string
+	SQL         string
+}
+
+// Reports are the canned queries available to `ktl capture query --report`.
+var Reports = map[string]Report{
+	"slowest-phases": {
+		Name:        "slowest-phases",
+		Description: "Deploy phases ordered by duration, slowest first",
+		SQL: `
+SELECT s.command AS command,
+       COALESCE(s.release, '') AS release,
+       json_extract(e.payload_json, '$.phase.name') AS phase,
+       MAX(ROUND((julianday(json_extract(e.payload_json, '$.phase.completedAt')) -
+                  julianday(json_extract(e.payload_json, '$.phase.startedAt'))) * 86400.0, 3)) AS seconds,
+       json_extract(e.payload_json, '$.phase.status') AS status
+FROM ktl_capture_events e
+JOIN ktl_capture_sessions s ON s.session_id = e.session_id
+WHERE e.kind = 'deploy' AND e.source = 'phase'
+  AND COALESCE(json_extract(e.payload_json, '$.phase.startedAt'), '') <> ''
+  AND COALESCE(json_extract(e.payload_json, '$.phase.completedAt'), '') <> ''
+GROUP BY e.session_id, phase
+ORDER BY seconds DESC
+LIMIT 25`,
+	},
+	"error-summary": {
+		Name:        "error-summary",
+		Description: "Error events and log lines grouped by source and message",
+		SQL: `
+SELECT e.kind AS kind,
+       COALESCE(NULLIF(e.source, ''), '-') AS source,
+       COALESCE(NULLIF(e.namespace, ''), '-') AS namespace,
+       COALESCE(NULLIF(e.pod, ''), '-') AS pod,
+       substr(trim(e.message), 1, 160) AS message,
+       COUNT(*) AS count,
+       MIN(e.ts) AS first_seen,
+       MAX(e.ts) AS last_seen
+FROM ktl_capture_events e
+WHERE lower(COALESCE(e.level, '')) IN ('error', 'fatal', 'panic')
+   OR (COALESCE(e.level, '') = '' AND e.kind = 'log' AND (e.message LIKE '%error%' OR e.message LIKE '%panic%' OR e.message LIKE '%fatal%'))
+GROUP BY kind, source, namespace, pod, substr(trim(e.message), 1, 160)
+ORDER BY count DESC, last_seen DESC
+LIMIT 50`,
+	},
+}
+
+// ReportNames returns the canned report names in sorted order.
+func ReportNames() []string {
+	names := make([]string, 0, len(Reports))
+	for name := range Reports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// QueryResult is a query's column names and rows. Values are nil, string, int64, float64 or bool.
+type QueryResult struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// Records returns the rows as text, with NULL as "".
+func (r QueryResult) Records() [][]string {
+	out := make([][]string, len(r.Rows))
+	for i, row := range r.Rows {
+		rec := make([]string, len(row))
+		for j, v := range row {
+			switch t := v.(type) {
+			case nil:
+			case string:
+				rec[j] = t
+			case int64:
+				rec[j] = strconv.FormatInt(t, 10)
+			case float64:
+				rec[j] = strconv.FormatFloat(t, 'f', -1, 64)
+			default:
+				rec[j] = fmt.Sprint(t)
+			}
+		}
+		out[i] = rec
+	}
+	return out
+}
+
+// OpenReadOnly opens an existing capture database for queries. Statements that write are rejected.
+func OpenReadOnly(path string) (*sql.DB, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("capture path is required")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("open capture: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=query_only(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// Query runs query against a capture database.
+func Query(ctx context.Context, db *sql.DB, query string, args ...any) (QueryResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return QueryResult{}, fmt.Errorf("query is empty")
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return QueryResult{}, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return QueryResult{}, err
+	}
+	out := QueryResult{Columns: cols}
+	for rows.Next() {
+		values := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return QueryResult{}, err
+		}
+		row := make([]any, len(cols))
+		for i, v := range values {
+			switch t := v.(type) {
+			case []byte:
+				row[i] = string(t)
+			case time.Time:
+				row[i] = t.UTC().Format(time.RFC3339Nano)
+			default:
+				row[i] = t
+			}
+		}
+		out.Rows = append(out.Rows, row)
+	}
+	return out, rows.Err()
+}
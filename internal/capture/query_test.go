@@ -0,0 +1,65 @@
+package capture
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/tailer"
+)
+
+func TestQueryReports(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "cap.sqlite")
+	rec, err := Open(dbPath, SessionMeta{Command: "ktl apply", Entities: Entities{Release: "web"}})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	phase := func(name string, d time.Duration) {
+		rec.HandleDeployEvent(deploy.StreamEvent{
+			Kind:  deploy.StreamEventPhase,
+			Phase: &deploy.PhasePayload{Name: name, State: "done", Status: "succeeded", StartedAt: start.Format(time.RFC3339Nano), CompletedAt: start.Add(d).Format(time.RFC3339Nano)},
+		})
+	}
+	phase("render", 1500*time.Millisecond)
+	phase("wait", 42*time.Second)
+	rec.HandleDeployEvent(deploy.StreamEvent{Kind: deploy.StreamEventPhase, Phase: &deploy.PhasePayload{Name: "hooks", StartedAt: start.Format(time.RFC3339Nano)}})
+	for i := 0; i < 3; i++ {
+		rec.ObserveLog(tailer.LogRecord{Timestamp: start, Source: "pod", Namespace: "prod", Pod: "web-1", Rendered: "ERROR connection refused"})
+	}
+	rec.ObserveLog(tailer.LogRecord{Timestamp: start, Source: "pod", Namespace: "prod", Pod: "web-1", Rendered: "listening on :8080"})
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	db, err := OpenReadOnly(dbPath)
+	if err != nil {
+		t.Fatalf("OpenReadOnly: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	res, err := Query(ctx, db, Reports["slowest-phases"].SQL)
+	if err != nil {
+		t.Fatalf("slowest-phases: %v", err)
+	}
+	recs := res.Records()
+	if len(recs) != 2 || recs[0][2] != "wait" || recs[0][3] != "42" || recs[1][3] != "1.5" || recs[0][1] != "web" {
+		t.Fatalf("slowest-phases columns=%v rows=%v", res.Columns, recs)
+	}
+
+	res, err = Query(ctx, db, Reports["error-summary"].SQL)
+	if err != nil {
+		t.Fatalf("error-summary: %v", err)
+	}
+	if len(res.Rows) != 1 || res.Rows[0][5] != int64(3) || !strings.Contains(res.Rows[0][4].(string), "connection refused") {
+		t.Fatalf("error-summary rows=%v", res.Rows)
+	}
+
+	if _, err := Query(ctx, db, "DELETE FROM ktl_capture_events"); err == nil {
+		t.Fatalf("expected writes to be rejected")
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/kubekattle/ktl/internal/deploy"
 	"github.com/kubekattle/ktl/internal/tailer"
+	"github.com/kubekattle/ktl/internal/traffic"
 )
 
 type Entities struct {
@@ -208,6 +209,51 @@ func (r *Recorder) HandleDeployEvent(evt deploy.StreamEvent) {
 	_ = r.RecordDeployEvent(context.Background(), evt)
 }
 
+func (r *Recorder) ObserveExchange(ex traffic.Exchange) {
+	_ = r.RecordHTTPExchange(context.Background(), ex)
+}
+
+// RecordHTTPExchange stores tapped HTTP request/response metadata as an 'http' event.
+func (r *Recorder) RecordHTTPExchange(ctx context.Context, ex traffic.Exchange) error {
+	if r == nil {
+		return nil
+	}
+	ts := ex.Start.UTC()
+	if ts.IsZero() {
+		ts = r.now()
+	}
+	seq := r.nextSeq()
+	level := "info"
+	if ex.Error != "" || ex.Status >= 500 {
+		level = "error"
+	} else if ex.Status >= 400 {
+		level = "warn"
+	}
+	payloadType, payloadBlob, payloadJSON := encodePayload(mustJSON(ex))
+	return r.enqueue(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+INSERT INTO ktl_capture_events(
+  session_id, seq, ts, ts_ns, kind,
+  level, source, namespace, pod, container,
+  message, payload_type, payload_blob, payload_json
+)
+VALUES(?, ?, ?, ?, 'http', ?, ?, '', '', '', ?, ?, ?, ?)
+`,
+			r.sessionID,
+			seq,
+			ts.Format(time.RFC3339Nano),
+			ts.UnixNano(),
+			level,
+			ex.Target,
+			ex.Summary(),
+			payloadType,
+			payloadBlob,
+			payloadJSON,
+		)
+		return err
+	})
+}
+
 func (r *Recorder) RecordArtifact(ctx context.Context, name, text string) error {
 	if r == nil {
 		return nil
@@ -76,6 +76,15 @@ func (s *deployState) Record(event deploy.StreamEvent) {
 		if cp.Log == nil || cp.Log.Message == "" {
 			return
 		}
+		if key := cp.Log.Key; key != "" {
+			// Repeats of a correlated event supersede the earlier entry so replays stay compact.
+			for i, prev := range s.logs {
+				if prev.Log != nil && prev.Log.Key == key {
+					s.logs = append(s.logs[:i], s.logs[i+1:]...)
+					break
+				}
+			}
+		}
 		s.logs = append(s.logs, &cp)
 		if overflow := len(s.logs) - maxCachedLogs; overflow > 0 {
 			s.logs = s.logs[overflow:]
@@ -192,6 +201,12 @@ func cloneStreamEvent(event deploy.StreamEvent) deploy.StreamEvent {
 		if len(event.Summary.Secrets) > 0 {
 			cp.Secrets = append([]deploy.SecretRef(nil), event.Summary.Secrets...)
 		}
+		if len(event.Summary.Storage) > 0 {
+			cp.Storage = append([]deploy.ResourceStatus(nil), event.Summary.Storage...)
+		}
+		if len(event.Summary.Reachability) > 0 {
+			cp.Reachability = append([]deploy.ProbeResult(nil), event.Summary.Reachability...)
+		}
 		cloned.Summary = &cp
 	}
 	if event.Health != nil {
@@ -112,3 +112,23 @@ func TestDeployStateTrimsLogs(t *testing.T) {
 		t.Fatalf("expected %d cached logs, got %d", maxCachedLogs, got)
 	}
 }
+
+func TestDeployStateCollapsesCorrelatedEvents(t *testing.T) {
+	state := newDeployState()
+	for i, msg := range []string{"BackOff", "Pulled", "BackOff (x2)"} {
+		key := "apps|pod|web-0|backoff|"
+		if i == 1 {
+			key = "apps|pod|web-0|pulled|"
+		}
+		state.Record(deploy.StreamEvent{
+			Kind: deploy.StreamEventLog,
+			Log:  &deploy.LogPayload{Level: "warn", Message: msg, Key: key},
+		})
+	}
+	if got := len(state.logs); got != 2 {
+		t.Fatalf("expected 2 cached logs, got %d", got)
+	}
+	if last := state.logs[1].Log.Message; last != "BackOff (x2)" {
+		t.Fatalf("expected latest repeat last, got %q", last)
+	}
+}
@@ -0,0 +1,168 @@
+// File: internal/caststream/plan_preview.go
+// Brief: Internal caststream package implementation for 'plan_preview'.
+
+package caststream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithPlanPreview switches the server into plan preview mode (used by `ktl apply plan --serve`):
+// the index serves the latest visualization and viewers reload when a new plan is published.
+func WithPlanPreview() Option {
+	return func(s *Server) {
+		if s == nil {
+			return
+		}
+		s.acceptLogs = false
+		s.acceptDeploy = false
+		if s.planPreview == nil {
+			s.planPreview = &planPreviewState{}
+		}
+	}
+}
+
+// planPreviewEvent is the WebSocket message sent after every re-plan.
+type planPreviewEvent struct {
+	Type      string    `json:"type"`
+	Version   int       `json:"version"`
+	Error     string    `json:"error,omitempty"`
+	Changed   []string  `json:"changed,omitempty"`
+	Timestamp time.Time `json:"ts"`
+}
+
+// PublishPlan replaces the served visualization with html and tells viewers to reload. changed
+// lists the files that triggered the re-plan.
+func (s *Server) PublishPlan(html string, changed []string) {
+	if s == nil || s.planPreview == nil {
+		return
+	}
+	s.publishPlanEvent(s.planPreview.SetPlan(html, changed))
+}
+
+// PublishPlanError keeps the last good visualization and shows err in the viewers.
+func (s *Server) PublishPlanError(err error, changed []string) {
+	if s == nil || s.planPreview == nil || err == nil {
+		return
+	}
+	s.publishPlanEvent(s.planPreview.SetError(err, changed))
+}
+
+func (s *Server) publishPlanEvent(event planPreviewEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error(err, "encode plan preview payload")
+		return
+	}
+	s.hub.Broadcast(payload)
+}
+
+func (s *Server) handlePlanPreview(w http.ResponseWriter) {
+	html, version := s.planPreview.Page()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write([]byte(injectPlanPreviewScript(html, version)))
+}
+
+// planPreviewState holds the latest visualization and the last event for late-joining viewers.
+type planPreviewState struct {
+	mu      sync.Mutex
+	html    string
+	version int
+	last    *planPreviewEvent
+}
+
+func (p *planPreviewState) SetPlan(html string, changed []string) planPreviewEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.html = html
+	p.version++
+	event := planPreviewEvent{Type: "plan", Version: p.version, Changed: changed, Timestamp: time.Now().UTC()}
+	p.last = &event
+	return event
+}
+
+func (p *planPreviewState) SetError(err error, changed []string) planPreviewEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	event := planPreviewEvent{Type: "error", Version: p.version, Error: err.Error(), Changed: changed, Timestamp: time.Now().UTC()}
+	p.last = &event
+	return event
+}
+
+func (p *planPreviewState) Page() (string, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.html == "" {
+		return planPreviewPendingHTML, p.version
+	}
+	return p.html, p.version
+}
+
+func (p *planPreviewState) Replay(out chan<- []byte) {
+	p.mu.Lock()
+	last := p.last
+	p.mu.Unlock()
+	if last == nil {
+		return
+	}
+	payload, err := json.Marshal(last)
+	if err != nil {
+		return
+	}
+	safeEnqueue(out, payload)
+}
+
+const planPreviewPendingHTML = `<!doctype html>
+<html>
+<head><meta charset="utf-8"><title>ktl plan preview</title></head>
+<body style="font-family: system-ui, sans-serif; padding: 2rem;">
+  <p>Planning&hellip; this page reloads when the first plan is ready.</p>
+</body>
+</html>`
+
+// planPreviewScript reloads the page when a newer plan is published and shows re-plan errors in a
+// banner, so a broken template does not blank the last good visualization.
+const planPreviewScript = `<div id="ktlPlanPreviewStatus" role="status" style="position:fixed;right:12px;bottom:12px;z-index:9999;max-width:40rem;padding:8px 12px;border-radius:6px;font:12px/1.4 system-ui,sans-serif;background:#1f2933;color:#f5f7fa;opacity:.9;white-space:pre-wrap">Live preview: connecting&hellip;</div>
+<script>
+(function () {
+  var loaded = %d;
+  var status = document.getElementById('ktlPlanPreviewStatus');
+  function show(text, failed) {
+    status.textContent = text;
+    status.style.background = failed ? '#9b1c1c' : '#1f2933';
+  }
+  function connect() {
+    var ws = new WebSocket((location.protocol === 'https:' ? 'wss://' : 'ws://') + location.host + '/ws');
+    ws.onopen = function () { show('Live preview: watching for changes', false); };
+    ws.onmessage = function (msg) {
+      var event = {};
+      try { event = JSON.parse(msg.data); } catch (e) { return; }
+      if (event.type === 'plan' && event.version !== loaded) {
+        location.reload();
+      } else if (event.type === 'error') {
+        show('Re-plan failed (showing the last good plan):\n' + event.error, true);
+      }
+    };
+    ws.onclose = function () {
+      show('Live preview: disconnected, retrying', true);
+      setTimeout(connect, 2000);
+    };
+  }
+  connect();
+})();
+</script>
+`
+
+func injectPlanPreviewScript(html string, version int) string {
+	script := fmt.Sprintf(planPreviewScript, version)
+	if idx := strings.LastIndex(html, "</body>"); idx >= 0 {
+		return html[:idx] + script + html[idx:]
+	}
+	return html + script
+}
@@ -0,0 +1,65 @@
+package caststream
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestPlanPreviewServesLatestPlan(t *testing.T) {
+	s := New(":0", ModeWeb, "", logr.Discard(), WithPlanPreview())
+	index := func() string {
+		rec := httptest.NewRecorder()
+		s.handleIndex(rec, httptest.NewRequest("GET", "/", nil))
+		return rec.Body.String()
+	}
+	if body := index(); !strings.Contains(body, "Planning") || !strings.Contains(body, "var loaded = 0;") {
+		t.Fatalf("expected the pending page, got:\n%s", body)
+	}
+
+	c := &client{send: make(chan []byte, 4), logger: logr.Discard()}
+	s.hub.Register(c)
+	next := func() planPreviewEvent {
+		t.Helper()
+		select {
+		case msg := <-c.send:
+			var event planPreviewEvent
+			if err := json.Unmarshal(msg, &event); err != nil {
+				t.Fatal(err)
+			}
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a plan event")
+		}
+		return planPreviewEvent{}
+	}
+
+	s.PublishPlan("<html><body><p>plan one</p></body></html>", nil)
+	if event := next(); event.Type != "plan" || event.Version != 1 {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	body := index()
+	if !strings.Contains(body, "plan one") || !strings.Contains(body, "var loaded = 1;") || !strings.HasSuffix(body, "</body></html>") {
+		t.Fatalf("expected the plan with the reload script before </body>, got:\n%s", body)
+	}
+
+	s.PublishPlanError(errors.New("template: deployment.yaml: bad"), []string{"chart/templates/deployment.yaml"})
+	if event := next(); event.Type != "error" || event.Version != 1 || event.Changed[0] != "chart/templates/deployment.yaml" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if !strings.Contains(index(), "plan one") {
+		t.Fatal("expected a failed re-plan to keep the last good plan")
+	}
+
+	late := make(chan []byte, 1)
+	s.planPreview.Replay(late)
+	var replayed planPreviewEvent
+	if err := json.Unmarshal(<-late, &replayed); err != nil || replayed.Type != "error" {
+		t.Fatalf("expected late viewers to get the last event, got %+v (%v)", replayed, err)
+	}
+}
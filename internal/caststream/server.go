@@ -3,7 +3,8 @@
 
 // Package caststream hosts lightweight remote streaming servers used by ktl.
 // It can expose log streams over WebSocket (e.g. `ktl logs --ws-listen`) and
-// render the deploy viewer HTML shell used by `ktl apply --ui` / `ktl delete --ui`.
+// render the deploy viewer HTML shell used by `ktl apply --ui` / `ktl delete --ui`, or serve the
+// live plan preview used by `ktl apply plan --visualize --serve`.
 package caststream
 
 import (
@@ -62,6 +63,8 @@ type Server struct {
 	acceptDeploy   bool
 	deployState    *deployState
 	deployTemplate *template.Template
+	trafficState   *trafficState
+	planPreview    *planPreviewState
 }
 
 func New(addr string, mode Mode, clusterInfo string, logger logr.Logger, opts ...Option) *Server {
@@ -139,7 +142,15 @@ func (s *Server) HandleDeployEvent(event deploy.StreamEvent) {
 }
 
 func (s *Server) handleIndex(w http.ResponseWriter, _ *http.Request) {
+	if s.planPreview != nil {
+		s.handlePlanPreview(w)
+		return
+	}
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if s.trafficState != nil {
+		_, _ = w.Write([]byte(s.renderTemplate(trafficTemplate, deployTemplateData{ClusterInfo: s.clusterInfo})))
+		return
+	}
 	formatted := s.renderDeployIndex(template.HTMLEscapeString(s.clusterInfo))
 	_, _ = w.Write([]byte(formatted))
 }
@@ -156,6 +167,12 @@ func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
 	if s.deployState != nil {
 		go s.deployState.Replay(client.send)
 	}
+	if s.trafficState != nil {
+		go s.trafficState.Replay(client.send)
+	}
+	if s.planPreview != nil {
+		go s.planPreview.Replay(client.send)
+	}
 	client.readLoop(func() {
 		s.hub.Unregister(client)
 	})
@@ -345,6 +362,11 @@ var (
 
 	//go:embed templates/deploy_viewer.html
 	deployViewerHTML string
+
+	//go:embed templates/traffic_timeline.html
+	trafficTimelineHTML string
+
+	trafficTemplate = template.Must(template.New("traffic_timeline").Parse(trafficTimelineHTML))
 )
 
 func stripANSI(text string) string {
@@ -0,0 +1,74 @@
+// File: internal/caststream/traffic_state.go
+// Brief: Internal caststream package implementation for 'traffic_state'.
+
+package caststream
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/kubekattle/ktl/internal/traffic"
+)
+
+const trafficHistoryLimit = 500
+
+// WithTrafficUI switches the server into traffic timeline mode (used by `ktl traffic tap --ui`).
+func WithTrafficUI() Option {
+	return func(s *Server) {
+		if s == nil {
+			return
+		}
+		s.acceptLogs = false
+		s.acceptDeploy = false
+		if s.trafficState == nil {
+			s.trafficState = &trafficState{}
+		}
+	}
+}
+
+// ObserveExchange satisfies traffic.Observer so tapped HTTP exchanges stream to the timeline UI.
+func (s *Server) ObserveExchange(ex traffic.Exchange) {
+	if s == nil || s.trafficState == nil {
+		return
+	}
+	payload, err := encodeTrafficPayload(ex)
+	if err != nil {
+		s.logger.Error(err, "encode traffic cast payload")
+		return
+	}
+	s.trafficState.Record(payload)
+	s.hub.Broadcast(payload)
+}
+
+func encodeTrafficPayload(ex traffic.Exchange) ([]byte, error) {
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		traffic.Exchange
+	}{Type: "http", Exchange: ex})
+}
+
+// trafficState keeps a bounded history so late-joining viewers see recent exchanges.
+type trafficState struct {
+	mu      sync.Mutex
+	history [][]byte
+}
+
+func (s *trafficState) Record(payload []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = append(s.history, payload)
+	if len(s.history) > trafficHistoryLimit {
+		s.history = append([][]byte(nil), s.history[len(s.history)-trafficHistoryLimit:]...)
+	}
+}
+
+func (s *trafficState) Replay(out chan<- []byte) {
+	s.mu.Lock()
+	history := append([][]byte(nil), s.history...)
+	s.mu.Unlock()
+	for _, payload := range history {
+		if !safeEnqueue(out, payload) {
+			return
+		}
+	}
+}
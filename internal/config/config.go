@@ -24,12 +24,17 @@ type Options struct {
 	Namespaces            []string
 	AllNamespaces         bool
 	LabelSelector         string
+	Workload              string
 	FieldSelector         string
 	ContainerFilters      []string
 	ExcludeContainers     []string
 	ExcludePods           []string
 	ExcludeLine           string
 	HighlightTerms        []string
+	Anomalies             bool
+	AnomalyWarmup         int
+	Summary               bool
+	SummaryInterval       time.Duration
 	DiffContainer         bool
 	Follow                bool
 	NoFollow              bool
@@ -67,14 +72,20 @@ type Options struct {
 	NodeLogAll            bool
 	NodeLogsOnly          bool
 	WSListenAddr          string
+	Encode                string
+	EncodeEndpoint        string
+	EncodeHeaders         []string
+	EncodeHeaderMap       map[string]string
+	EncodeResource        map[string]string
 }
 
 const defaultTemplate = "[{{.Timestamp}}] {{.PodDisplay}} {{.ContainerTag}} {{.Message}}"
 const rawTemplate = "{{.Raw}}"
-const jsonTemplate = "{{printf \"{\\\"timestamp\\\":\\\"%s\\\",\\\"namespace\\\":\\\"%s\\\",\\\"pod\\\":\\\"%s\\\",\\\"container\\\":\\\"%s\\\",\\\"message\\\":%q}\" .Timestamp .Namespace .PodName .ContainerName .Message}}"
-const extJSONTemplate = "{{printf \"{\\\"timestamp\\\":\\\"%s\\\",\\\"namespace\\\":\\\"%s\\\",\\\"pod\\\":\\\"%s\\\",\\\"container\\\":\\\"%s\\\",\\\"message\\\":%q,\\\"raw\\\":%q}\" .Timestamp .Namespace .PodName .ContainerName .Message .Raw}}"
-const ppExtJSONTemplate = "{{printf \"{\\\"ts\\\":\\\"%s\\\",\\\"ns\\\":\\\"%s\\\",\\\"pod_name\\\":\\\"%s\\\",\\\"container_name\\\":\\\"%s\\\",\\\"msg\\\":%q,\\\"raw\\\":%q}\" .Timestamp .Namespace .PodName .ContainerName .Message .Raw}}"
+const jsonTemplate = "{{printf \"{\\\"timestamp\\\":\\\"%s\\\",\\\"namespace\\\":\\\"%s\\\",\\\"pod\\\":\\\"%s\\\",\\\"container\\\":\\\"%s\\\",\\\"message\\\":%q\" .Timestamp .Namespace .PodName .ContainerName .Message}}{{if .Anomaly}},\"anomaly\":{{printf \"%q\" .Anomaly}}{{end}}}"
+const extJSONTemplate = "{{printf \"{\\\"timestamp\\\":\\\"%s\\\",\\\"namespace\\\":\\\"%s\\\",\\\"pod\\\":\\\"%s\\\",\\\"container\\\":\\\"%s\\\",\\\"message\\\":%q,\\\"raw\\\":%q\" .Timestamp .Namespace .PodName .ContainerName .Message .Raw}}{{if .Anomaly}},\"anomaly\":{{printf \"%q\" .Anomaly}}{{end}}}"
+const ppExtJSONTemplate = "{{printf \"{\\\"ts\\\":\\\"%s\\\",\\\"ns\\\":\\\"%s\\\",\\\"pod_name\\\":\\\"%s\\\",\\\"container_name\\\":\\\"%s\\\",\\\"msg\\\":%q,\\\"raw\\\":%q\" .Timestamp .Namespace .PodName .ContainerName .Message .Raw}}{{if .Anomaly}},\"anomaly\":{{printf \"%q\" .Anomaly}}{{end}}}"
 const defaultNodeLogFile = "kubelet.log"
+const defaultAnomalyWarmup = 200
 
 // DefaultTemplate exposes the default log template so other packages can compare against it safely.
 func DefaultTemplate() string {
@@ -104,6 +115,7 @@ func NewOptions() *Options {
 		Template:        defaultTemplate,
 		ColorMode:       "auto",
 		OutputFormat:    "default",
+		AnomalyWarmup:   defaultAnomalyWarmup,
 	}
 }
 
@@ -121,6 +133,8 @@ func (o *Options) BindFlags(fs *pflag.FlagSet) []string {
 	names = append(names, "namespace")
 	fs.StringVarP(&o.LabelSelector, "selector", "l", "", "Label selector to filter pods")
 	names = append(names, "selector")
+	fs.StringVar(&o.Workload, "for", "", "Tail the pods of a workload (e.g. deployment/checkout, statefulset/db); its selector is resolved from the cluster")
+	names = append(names, "for")
 	fs.StringSliceVarP(&o.ContainerFilters, "container", "c", nil, "Regex filter for container names (repeat to OR multiple)")
 	names = append(names, "container")
 	fs.StringSliceVarP(&o.ExcludeContainers, "exclude-container", "C", nil, "Regex for container names to exclude")
@@ -131,6 +145,14 @@ func (o *Options) BindFlags(fs *pflag.FlagSet) []string {
 	names = append(names, "exclude")
 	fs.StringArrayVarP(&o.HighlightTerms, "highlight", "H", nil, "Log lines to highlight (regular expression)")
 	names = append(names, "highlight")
+	fs.BoolVar(&o.Anomalies, "anomalies", false, "Flag novel/rare log lines and error-rate spikes against a baseline built during the session")
+	names = append(names, "anomalies")
+	fs.IntVar(&o.AnomalyWarmup, "anomaly-warmup", defaultAnomalyWarmup, "Number of lines used to build the baseline before --anomalies starts flagging")
+	names = append(names, "anomaly-warmup")
+	fs.BoolVar(&o.Summary, "summary", false, "Print a summary (lines, errors/warnings, top repeated messages, restarts) to stderr when the session ends")
+	names = append(names, "summary")
+	fs.DurationVar(&o.SummaryInterval, "summary-interval", 0, "Also print the session summary every interval (implies --summary)")
+	names = append(names, "summary-interval")
 	fs.StringArrayVar(&o.ConditionArgs, "condition", nil, "Filter pods by condition, e.g. ready=false")
 	names = append(names, "condition")
 	fs.BoolVarP(&o.Follow, "follow", "f", true, "Follow log output")
@@ -153,7 +175,7 @@ func (o *Options) BindFlags(fs *pflag.FlagSet) []string {
 	names = append(names, "timestamps")
 	fs.StringVarP(&o.TimestampFormat, "timestamp-format", "F", TimestampFormatYouTube, "Go time format string for timestamps (use \"youtube\" for H:MM:SS)")
 	names = append(names, "timestamp-format")
-	fs.StringVarP(&o.Template, "template", "p", defaultTemplate, "Go template for log lines; available fields: Timestamp, Namespace, PodName, ContainerName, Message, Raw")
+	fs.StringVarP(&o.Template, "template", "p", defaultTemplate, "Go template for log lines; available fields: Timestamp, Namespace, PodName, ContainerName, Message, Raw, Anomaly")
 	names = append(names, "template")
 	fs.StringVar(&o.TemplateFile, "template-file", "", "Path to a Go template file for log output")
 	names = append(names, "template-file")
@@ -185,7 +207,7 @@ func (o *Options) BindFlags(fs *pflag.FlagSet) []string {
 	names = append(names, "stdin")
 	fs.BoolVar(&o.NodeLogs, "node-logs", false, "Also stream node/system logs (defaults to kubelet.log) from nodes hosting matched pods")
 	names = append(names, "node-logs")
-	fs.StringSliceVar(&o.NodeLogFiles, "node-log", nil, "Specific node/system log files (relative to /var/log) to stream via the kubelet proxy; repeat for multiple")
+	fs.StringSliceVar(&o.NodeLogFiles, "node-log", nil, "Specific node/system log files (relative to /var/log, or C:\\var\\log on Windows nodes) to stream via the kubelet proxy; repeat for multiple")
 	names = append(names, "node-log")
 	fs.BoolVar(&o.NodeLogAll, "node-log-all", false, "Stream node logs from every node instead of only those hosting matched pods")
 	names = append(names, "node-log-all")
@@ -193,6 +215,12 @@ func (o *Options) BindFlags(fs *pflag.FlagSet) []string {
 	names = append(names, "node-log-only")
 	fs.StringVar(&o.WSListenAddr, "ws-listen", "", "Expose a raw WebSocket log feed at this address (e.g. :9090)")
 	names = append(names, "ws-listen")
+	fs.StringVar(&o.Encode, "encode", "", "Emit lines with a built-in encoder instead of the template: logfmt, jsonl (JSON Lines with resource attributes), or otlp (export to --endpoint)")
+	names = append(names, "encode")
+	fs.StringVar(&o.EncodeEndpoint, "endpoint", "", "OTLP/HTTP collector endpoint for --encode otlp (e.g. http://localhost:4318)")
+	names = append(names, "endpoint")
+	fs.StringArrayVar(&o.EncodeHeaders, "endpoint-header", nil, "Header sent with OTLP export requests (KEY=VALUE); repeatable")
+	names = append(names, "endpoint-header")
 	return names
 }
 
@@ -259,6 +287,18 @@ func (o *Options) Validate() error {
 	if o.TailLines < -1 {
 		return fmt.Errorf("--tail cannot be less than -1")
 	}
+	if o.AnomalyWarmup < 0 {
+		return fmt.Errorf("--anomaly-warmup cannot be negative")
+	}
+	if o.SummaryInterval < 0 {
+		return fmt.Errorf("--summary-interval cannot be negative")
+	}
+	if o.SummaryInterval > 0 {
+		o.Summary = true
+	}
+	if err := o.validateEncode(); err != nil {
+		return err
+	}
 	if strings.TrimSpace(o.TemplateFile) != "" {
 		data, err := os.ReadFile(o.TemplateFile)
 		if err != nil {
@@ -411,3 +451,33 @@ func parseConditionStatus(val string) (corev1.ConditionStatus, error) {
 		return corev1.ConditionFalse, fmt.Errorf("invalid condition status %q (use true/false/unknown)", val)
 	}
 }
+
+func (o *Options) validateEncode() error {
+	o.Encode = strings.ToLower(strings.TrimSpace(o.Encode))
+	switch o.Encode {
+	case "", "logfmt", "jsonl", "otlp":
+	default:
+		return fmt.Errorf("invalid --encode value %q (allowed: logfmt, jsonl, otlp)", o.Encode)
+	}
+	if o.Encode == "otlp" && strings.TrimSpace(o.EncodeEndpoint) == "" {
+		return fmt.Errorf("--encode otlp requires --endpoint")
+	}
+	if o.Encode != "otlp" && (strings.TrimSpace(o.EncodeEndpoint) != "" || len(o.EncodeHeaders) > 0) {
+		return fmt.Errorf("--endpoint and --endpoint-header require --encode otlp")
+	}
+	if o.Encode != "" && o.JSONOutput {
+		return fmt.Errorf("cannot combine --encode with --json")
+	}
+	if len(o.EncodeHeaders) > 0 {
+		o.EncodeHeaderMap = make(map[string]string, len(o.EncodeHeaders))
+		for _, raw := range o.EncodeHeaders {
+			key, value, ok := strings.Cut(raw, "=")
+			key = strings.TrimSpace(key)
+			if !ok || key == "" {
+				return fmt.Errorf("invalid --endpoint-header %q (expected KEY=VALUE)", raw)
+			}
+			o.EncodeHeaderMap[key] = strings.TrimSpace(value)
+		}
+	}
+	return nil
+}
@@ -142,3 +142,35 @@ func TestValidateNodeLogOnlyEnablesDefaults(t *testing.T) {
 		t.Fatalf("expected node log files to be configured")
 	}
 }
+
+func TestValidateEncode(t *testing.T) {
+	opts := NewOptions()
+	opts.Encode = "OTLP"
+	if err := opts.Validate(); err == nil {
+		t.Fatalf("expected --encode otlp without --endpoint to fail")
+	}
+
+	opts = NewOptions()
+	opts.Encode = "otlp"
+	opts.EncodeEndpoint = "http://collector:4318"
+	opts.EncodeHeaders = []string{"Authorization=Bearer abc"}
+	if err := opts.Validate(); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	if opts.Encode != "otlp" || opts.EncodeHeaderMap["Authorization"] != "Bearer abc" {
+		t.Fatalf("unexpected encode settings: %q %v", opts.Encode, opts.EncodeHeaderMap)
+	}
+
+	opts = NewOptions()
+	opts.Encode = "logfmt"
+	opts.EncodeEndpoint = "http://collector:4318"
+	if err := opts.Validate(); err == nil {
+		t.Fatalf("expected --endpoint without --encode otlp to fail")
+	}
+
+	opts = NewOptions()
+	opts.Encode = "xml"
+	if err := opts.Validate(); err == nil {
+		t.Fatalf("expected unknown encoder to fail")
+	}
+}
@@ -0,0 +1,588 @@
+// File: internal/configdoctor/doctor.go
+// Brief: Cross-file validation of .ktl.yaml, stack.yaml/release.yaml, and verify configs.
+
+// Package configdoctor loads every repo-level ktl config together and checks the references
+// between them (values files, secret URIs, chart paths, hook scripts), reporting each problem
+// with the file and line it came from.
+package configdoctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/secretstore"
+	"github.com/kubekattle/ktl/internal/stack"
+	"github.com/kubekattle/ktl/internal/valuesource"
+	verifyconfig "github.com/kubekattle/ktl/internal/verify/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Severities of an Issue.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is one problem found in a config file. Line is 0 when the problem is not tied to a line.
+type Issue struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (i Issue) String() string {
+	loc := i.File
+	if i.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", i.File, i.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s", loc, i.Severity, i.Message)
+}
+
+// Report lists the checked files and the issues found in them.
+type Report struct {
+	Root     string   `json:"root"`
+	Files    []string `json:"files"`
+	Issues   []Issue  `json:"issues"`
+	Errors   int      `json:"errors"`
+	Warnings int      `json:"warnings"`
+}
+
+// Options select what Run checks.
+type Options struct {
+	// Root is the repository root; .ktl.yaml, stack.yaml, and verify configs are found under it.
+	Root string
+	// StackDir overrides the stack root (default: Root when it holds a stack.yaml).
+	StackDir string
+	// VerifyConfigs are checked in addition to the verify*.yaml files discovered under Root.
+	VerifyConfigs []string
+	// GlobalConfig is the user config (~/.ktl/config.yaml); missing files are skipped.
+	GlobalConfig string
+	// Offline skips secret lookups against remote providers such as Vault.
+	Offline bool
+}
+
+type doctor struct {
+	ctx      context.Context
+	opts     Options
+	root     string
+	report   Report
+	seen     map[string]bool
+	resolver *secretstore.Resolver
+	// providerTypes maps provider names to their type, so Offline can skip remote lookups.
+	providerTypes map[string]string
+	docs          map[string]*yaml.Node
+}
+
+// Run validates the configs under opts.Root.
+func Run(ctx context.Context, opts Options) Report {
+	root, err := filepath.Abs(strings.TrimSpace(opts.Root))
+	if err != nil || strings.TrimSpace(opts.Root) == "" {
+		root, _ = os.Getwd()
+	}
+	d := &doctor{ctx: ctx, opts: opts, root: root, seen: map[string]bool{}, docs: map[string]*yaml.Node{}, providerTypes: map[string]string{}}
+	d.report.Root = root
+	d.report.Issues = []Issue{}
+	d.checkAppConfig()
+	d.checkStack()
+	d.checkVerifyConfigs()
+	sort.SliceStable(d.report.Issues, func(i, j int) bool {
+		a, b := d.report.Issues[i], d.report.Issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	for _, issue := range d.report.Issues {
+		if issue.Severity == SeverityError {
+			d.report.Errors++
+		} else {
+			d.report.Warnings++
+		}
+	}
+	sort.Strings(d.report.Files)
+	return d.report
+}
+
+func (d *doctor) rel(path string) string {
+	if rel, err := filepath.Rel(d.root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+func (d *doctor) addFile(path string) {
+	if rel := d.rel(path); !d.seen[rel] {
+		d.seen[rel] = true
+		d.report.Files = append(d.report.Files, rel)
+	}
+}
+
+func (d *doctor) add(severity, file string, line int, format string, args ...any) {
+	d.report.Issues = append(d.report.Issues, Issue{File: d.rel(file), Line: line, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// parse reads path into a yaml.v3 document node, caching it for later line lookups.
+func (d *doctor) parse(path string) (*yaml.Node, []byte, bool) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		d.add(SeverityError, path, 0, "%v", err)
+		return nil, nil, false
+	}
+	d.addFile(path)
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		d.add(SeverityError, path, yamlErrorLine(err), "invalid YAML: %s", trimYAMLError(err))
+		return nil, nil, false
+	}
+	d.docs[path] = &doc
+	return &doc, raw, true
+}
+
+// strict decodes raw into out rejecting unknown fields, reporting them as issues with lines.
+func (d *doctor) strict(path string, raw []byte, out any, severity string) {
+	dec := yaml.NewDecoder(strings.NewReader(string(raw)))
+	dec.KnownFields(true)
+	if err := dec.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			for _, msg := range typeErr.Errors {
+				d.add(severity, path, yamlErrorLine(fmt.Errorf("%s", msg)), "%s", trimYAMLError(fmt.Errorf("%s", msg)))
+			}
+			return
+		}
+		d.add(SeverityError, path, yamlErrorLine(err), "%s", trimYAMLError(err))
+	}
+}
+
+func (d *doctor) checkAppConfig() {
+	for _, path := range []string{strings.TrimSpace(d.opts.GlobalConfig), appconfig.DefaultRepoPath(d.root)} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		_, raw, ok := d.parse(path)
+		if !ok {
+			continue
+		}
+		var cfg appconfig.Config
+		d.strict(path, raw, &cfg, SeverityError)
+		loaded, err := appconfig.Load(d.ctx, "", path)
+		if err != nil {
+			continue
+		}
+		if _, err := deploy.BuildPostRenderer(d.ctx, loaded.Deploy.PostRenderers, nil); err != nil {
+			d.add(SeverityError, path, d.line(path, "deploy", "postRenderers"), "deploy.postRenderers: %v", err)
+		}
+		if _, err := valuesource.New(loaded.ValueSources); err != nil {
+			d.add(SeverityError, path, d.line(path, "valueSources"), "valueSources: %v", err)
+		}
+	}
+	cfg, err := appconfig.Load(d.ctx, strings.TrimSpace(d.opts.GlobalConfig), appconfig.DefaultRepoPath(d.root))
+	if err != nil {
+		return
+	}
+	resolver, err := secretstore.NewResolver(secretstore.ConfigFromApp(cfg.Secrets), secretstore.ResolverOptions{BaseDir: d.root})
+	if err != nil {
+		path := appconfig.DefaultRepoPath(d.root)
+		d.add(SeverityError, path, d.line(path, "secrets", "providers"), "secrets: %v", err)
+		return
+	}
+	d.resolver = resolver
+	for name, provider := range cfg.Secrets.Providers {
+		d.providerTypes[strings.TrimSpace(name)] = strings.ToLower(strings.TrimSpace(provider.Type))
+	}
+}
+
+func (d *doctor) checkStack() {
+	stackDir := strings.TrimSpace(d.opts.StackDir)
+	if stackDir == "" {
+		stackDir = d.root
+	} else if abs, err := filepath.Abs(stackDir); err == nil {
+		stackDir = abs
+	}
+	if _, err := os.Stat(filepath.Join(stackDir, "stack.yaml")); err != nil {
+		if strings.TrimSpace(d.opts.StackDir) != "" {
+			d.add(SeverityError, filepath.Join(stackDir, "stack.yaml"), 0, "stack.yaml not found")
+		}
+		return
+	}
+	var files []string
+	_ = filepath.WalkDir(stackDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if skipDir(entry.Name()) && path != stackDir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if name := entry.Name(); name == "stack.yaml" || name == "release.yaml" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	for _, path := range files {
+		doc, raw, ok := d.parse(path)
+		if !ok {
+			continue
+		}
+		if filepath.Base(path) == "stack.yaml" {
+			d.strict(path, raw, &stack.StackFile{}, SeverityWarning)
+		} else {
+			d.strict(path, raw, &stack.ReleaseFile{}, SeverityWarning)
+		}
+		d.checkRefs(path, doc)
+	}
+	u, err := stack.Discover(stackDir)
+	if err != nil {
+		d.add(SeverityError, filepath.Join(stackDir, "stack.yaml"), 0, "%s", d.stripPaths(err.Error()))
+		return
+	}
+	if _, err := stack.Compile(u, stack.CompileOptions{}); err != nil {
+		d.add(SeverityError, filepath.Join(stackDir, "stack.yaml"), 0, "%s", d.stripPaths(err.Error()))
+	}
+}
+
+// checkRefs walks a stack or release document for chart, values, and script hook references.
+func (d *doctor) checkRefs(path string, doc *yaml.Node) {
+	dir := filepath.Dir(path)
+	walkMappings(doc, func(key string, value *yaml.Node) {
+		switch key {
+		case "chart":
+			if value.Kind == yaml.ScalarNode {
+				d.checkChart(path, dir, value)
+			}
+		case "values":
+			if value.Kind == yaml.SequenceNode {
+				for _, item := range value.Content {
+					d.checkValuesFile(path, dir, item)
+				}
+			}
+		case "script":
+			d.checkScript(path, dir, value)
+		}
+	})
+}
+
+func (d *doctor) checkChart(path, dir string, value *yaml.Node) {
+	ref := strings.TrimSpace(value.Value)
+	if !isLocalRef(ref) {
+		return
+	}
+	target := resolveRel(dir, ref)
+	info, err := os.Stat(target)
+	switch {
+	case err != nil:
+		d.add(SeverityError, path, value.Line, "chart %q not found", ref)
+	case info.IsDir():
+		if _, err := os.Stat(filepath.Join(target, "Chart.yaml")); err != nil {
+			d.add(SeverityError, path, value.Line, "chart %q has no Chart.yaml", ref)
+		}
+	}
+}
+
+func (d *doctor) checkValuesFile(path, dir string, item *yaml.Node) {
+	if item.Kind != yaml.ScalarNode {
+		return
+	}
+	ref := strings.TrimSpace(item.Value)
+	if ref == "" || ref == deploy.ValuesStdin || deploy.IsRemoteValuesSource(ref) {
+		return
+	}
+	ref, _ = deploy.SplitValuesPin(ref)
+	target := resolveRel(dir, ref)
+	if _, err := os.Stat(target); err != nil {
+		d.add(SeverityError, path, item.Line, "values file %q not found", ref)
+		return
+	}
+	d.checkSecretRefs(target)
+}
+
+// checkSecretRefs validates the secret:// references in a values file against the providers.
+func (d *doctor) checkSecretRefs(path string) {
+	if d.seen[d.rel(path)] {
+		return
+	}
+	doc, _, ok := d.parse(path)
+	if !ok {
+		return
+	}
+	walkScalars(doc, func(value *yaml.Node) {
+		if !strings.HasPrefix(strings.TrimSpace(value.Value), "secret://") {
+			return
+		}
+		ref := strings.TrimSpace(value.Value)
+		if d.resolver == nil {
+			d.add(SeverityError, path, value.Line, "%s: no secret providers are configured", ref)
+			return
+		}
+		parsed, _, err := secretstore.ParseRef(ref, d.resolver.DefaultProvider())
+		if err != nil {
+			d.add(SeverityError, path, value.Line, "%v", err)
+			return
+		}
+		if _, ok := d.resolver.Provider(parsed.Provider); !ok {
+			d.add(SeverityError, path, value.Line, "%s: secret provider %q is not configured", ref, parsed.Provider)
+			return
+		}
+		if d.opts.Offline && d.providerTypes[parsed.Provider] != "file" {
+			return
+		}
+		err = secretstore.ValidateRefs(d.ctx, d.resolver, map[string]any{"ref": ref}, secretstore.ValidationOptions{MaxIssues: 1})
+		if verr, ok := err.(*secretstore.ValidationError); ok {
+			for _, issue := range verr.Issues {
+				d.add(SeverityError, path, value.Line, "%s: %s", ref, d.stripPaths(issue.Message))
+			}
+		} else if err != nil {
+			d.add(SeverityError, path, value.Line, "%s: %v", ref, err)
+		}
+	})
+}
+
+func (d *doctor) checkScript(path, dir string, value *yaml.Node) {
+	if value.Kind != yaml.MappingNode {
+		return
+	}
+	workDir := dir
+	var command *yaml.Node
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		switch value.Content[i].Value {
+		case "command":
+			command = value.Content[i+1]
+		case "workDir":
+			workDir = resolveRel(dir, value.Content[i+1].Value)
+		}
+	}
+	if command == nil || command.Kind != yaml.SequenceNode || len(command.Content) == 0 {
+		return
+	}
+	bin := command.Content[0]
+	name := strings.TrimSpace(bin.Value)
+	if !strings.ContainsRune(name, '/') {
+		if _, err := exec.LookPath(name); err != nil {
+			d.add(SeverityWarning, path, bin.Line, "hook command %q is not on PATH", name)
+		}
+		return
+	}
+	target := resolveRel(workDir, name)
+	info, err := os.Stat(target)
+	switch {
+	case err != nil:
+		d.add(SeverityError, path, bin.Line, "hook script %q not found", name)
+	case info.IsDir():
+		d.add(SeverityError, path, bin.Line, "hook script %q is a directory", name)
+	case info.Mode().Perm()&0o111 == 0:
+		d.add(SeverityError, path, bin.Line, "hook script %q is not executable (chmod +x %s)", name, d.rel(target))
+	}
+}
+
+func (d *doctor) checkVerifyConfigs() {
+	paths := append([]string(nil), d.opts.VerifyConfigs...)
+	_ = filepath.WalkDir(d.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if skipDir(entry.Name()) && path != d.root {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		name := entry.Name()
+		if strings.Contains(name, "verify") && (strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")) && looksLikeVerifyConfig(path) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	done := map[string]bool{}
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil || done[abs] {
+			continue
+		}
+		done[abs] = true
+		_, raw, ok := d.parse(abs)
+		if !ok {
+			continue
+		}
+		d.strict(abs, raw, &verifyconfig.Config{}, SeverityWarning)
+		cfg, baseDir, err := verifyconfig.Load(abs)
+		if err != nil {
+			d.add(SeverityError, abs, 0, "%v", err)
+			continue
+		}
+		if err := cfg.Validate(baseDir); err != nil {
+			d.add(SeverityError, abs, d.line(abs, "target"), "%v", err)
+		}
+		dir := filepath.Dir(abs)
+		if chart := d.node(abs, "target", "chart", "chart"); chart != nil {
+			d.checkChart(abs, dir, chart)
+		}
+		if values := d.node(abs, "target", "chart", "values"); values != nil && values.Kind == yaml.SequenceNode {
+			for _, item := range values.Content {
+				d.checkValuesFile(abs, dir, item)
+			}
+		}
+		for _, key := range [][]string{{"target", "manifest"}, {"verify", "rulesDir"}} {
+			if n := d.node(abs, key...); n != nil && strings.TrimSpace(n.Value) != "" {
+				if _, err := os.Stat(resolveRel(dir, n.Value)); err != nil {
+					d.add(SeverityError, abs, n.Line, "%s %q not found", strings.Join(key, "."), n.Value)
+				}
+			}
+		}
+	}
+}
+
+// node returns the value node at keys in a parsed document.
+func (d *doctor) node(path string, keys ...string) *yaml.Node {
+	doc := d.docs[path]
+	if doc == nil || len(doc.Content) == 0 {
+		return nil
+	}
+	cur := doc.Content[0]
+	for _, key := range keys {
+		if cur.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(cur.Content); i += 2 {
+			if cur.Content[i].Value == key {
+				next = cur.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		cur = next
+	}
+	return cur
+}
+
+// line returns the line of keys in path, falling back to the deepest key that exists.
+func (d *doctor) line(path string, keys ...string) int {
+	for n := len(keys); n > 0; n-- {
+		if node := d.node(path, keys[:n]...); node != nil {
+			return node.Line
+		}
+	}
+	return 0
+}
+
+func (d *doctor) stripPaths(msg string) string {
+	return strings.ReplaceAll(msg, d.root+string(filepath.Separator), "")
+}
+
+func walkMappings(node *yaml.Node, fn func(key string, value *yaml.Node)) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			fn(node.Content[i].Value, node.Content[i+1])
+		}
+	}
+	for _, child := range node.Content {
+		walkMappings(child, fn)
+	}
+}
+
+func walkScalars(node *yaml.Node, fn func(value *yaml.Node)) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.ScalarNode {
+		fn(node)
+		return
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 1; i < len(node.Content); i += 2 {
+			walkScalars(node.Content[i], fn)
+		}
+		return
+	}
+	for _, child := range node.Content {
+		walkScalars(child, fn)
+	}
+}
+
+func looksLikeVerifyConfig(path string) bool {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var top map[string]any
+	if yaml.Unmarshal(raw, &top) != nil {
+		return false
+	}
+	target, ok := top["target"].(map[string]any)
+	if !ok {
+		return false
+	}
+	_, ok = target["kind"]
+	return ok
+}
+
+func skipDir(name string) bool {
+	switch name {
+	case ".git", ".ktl", "node_modules", "vendor", "bin", "dist", "templates", "charts":
+		return true
+	}
+	return false
+}
+
+// isLocalRef reports whether a chart reference names a filesystem path rather than repo/name or
+// an OCI/HTTP URL.
+func isLocalRef(ref string) bool {
+	if ref == "" || strings.Contains(ref, "://") {
+		return false
+	}
+	return strings.HasPrefix(ref, ".") || strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, "~")
+}
+
+func resolveRel(dir, p string) string {
+	p = strings.TrimSpace(p)
+	if strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, p[2:])
+		}
+	}
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+	return filepath.Join(dir, p)
+}
+
+var yamlLineRe = regexp.MustCompile(`line (\d+)`)
+
+func yamlErrorLine(err error) int {
+	if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	return 0
+}
+
+func trimYAMLError(err error) string {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	if loc := yamlLineRe.FindStringIndex(msg); loc != nil && loc[0] == 0 {
+		msg = strings.TrimLeft(msg[loc[1]:], ": ")
+	}
+	return msg
+}
@@ -0,0 +1,106 @@
+package configdoctor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunReportsCrossFileProblems(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".ktl.yaml"), `secrets:
+  defaultProvider: local
+  providers:
+    local:
+      type: file
+      path: secrets.yaml
+deploy:
+  postRenderers:
+    - name: broken
+logz: {}
+`, 0o644)
+	writeFile(t, filepath.Join(root, "secrets.yaml"), "db:\n  password: s3cr3t\n", 0o600)
+	writeFile(t, filepath.Join(root, "chart", "Chart.yaml"), "apiVersion: v2\nname: web\nversion: 0.1.0\n", 0o644)
+	writeFile(t, filepath.Join(root, "values", "dev.yaml"), "db:\n  password: secret://local/db/password\n  user: secret://local/db/user\n", 0o644)
+	writeFile(t, filepath.Join(root, "hooks", "notify.sh"), "#!/bin/sh\n", 0o644)
+	writeFile(t, filepath.Join(root, "stack.yaml"), `name: demo
+defaults:
+  cluster: {name: dev}
+releases:
+  - name: web
+    chart: ./chart
+    values:
+      - values/dev.yaml
+      - values/missing.yaml
+  - name: api
+    chart: ./charts-missing/api
+hooks:
+  postApply:
+    - name: notify
+      type: script
+      script:
+        command: ["./hooks/notify.sh"]
+`, 0o644)
+	writeFile(t, filepath.Join(root, "verify.yaml"), `version: v1
+target:
+  kind: chart
+  chart:
+    chart: ./chart
+    release: web
+    values:
+      - values/prod.yaml
+`, 0o644)
+
+	report := Run(context.Background(), Options{Root: root, Offline: true})
+	var lines []string
+	for _, issue := range report.Issues {
+		lines = append(lines, issue.String())
+	}
+	got := strings.Join(lines, "\n")
+	for _, want := range []string{
+		".ktl.yaml:9: error: deploy.postRenderers: broken: set exactly one of exec, patches, images, or inject",
+		".ktl.yaml:10: error: field logz not found",
+		"stack.yaml:9: error: values file \"values/missing.yaml\" not found",
+		"stack.yaml:11: error: chart \"./charts-missing/api\" not found",
+		"stack.yaml:17: error: hook script \"./hooks/notify.sh\" is not executable",
+		"values/dev.yaml:3: error: secret://local/db/user:",
+		"verify.yaml:8: error: values file \"values/prod.yaml\" not found",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "values/dev.yaml:2") {
+		t.Fatalf("resolvable secret reported:\n%s", got)
+	}
+	if report.Errors != len(report.Issues)-report.Warnings || report.Errors < 7 {
+		t.Fatalf("errors=%d warnings=%d issues=%d", report.Errors, report.Warnings, len(report.Issues))
+	}
+}
+
+func TestRunCleanRepo(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "chart", "Chart.yaml"), "apiVersion: v2\nname: web\nversion: 0.1.0\n", 0o644)
+	writeFile(t, filepath.Join(root, "values.yaml"), "replicas: 1\n", 0o644)
+	writeFile(t, filepath.Join(root, "stack.yaml"), "name: demo\ndefaults:\n  cluster: {name: dev}\nreleases:\n  - name: web\n    chart: ./chart\n    values: [values.yaml]\n", 0o644)
+
+	report := Run(context.Background(), Options{Root: root})
+	if len(report.Issues) != 0 {
+		t.Fatalf("unexpected issues: %+v", report.Issues)
+	}
+	if strings.Join(report.Files, ",") != "stack.yaml,values.yaml" {
+		t.Fatalf("files=%v", report.Files)
+	}
+}
@@ -0,0 +1,289 @@
+// File: internal/configschema/generate.go
+// Brief: Reflection-based JSON Schema generation from the config structs' yaml tags.
+
+package configschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	draft07    = "http://json-schema.org/draft-07/schema#"
+	modulePath = "github.com/kubekattle/ktl"
+)
+
+// durationPattern accepts what time.ParseDuration does, e.g. 90s, 5m, or 1h30m.
+const durationPattern = `^-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// enumComment matches trailing field comments such as "kubectl|script|http" or
+// "helm (default)|manifests|task", which the config structs use to document allowed values.
+var enumComment = regexp.MustCompile(`^[\w.-]+( \(default\))?(\|[\w.-]+( \(default\))?)+$`)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Generate builds the schema for k from its Go struct. When srcRoot points at the ktl module
+// root, field doc comments become descriptions and "a|b|c" comments become enums.
+func Generate(k Kind, srcRoot string) ([]byte, error) {
+	g := &generator{
+		srcRoot: srcRoot,
+		docs:    map[string]map[string]string{},
+		defs:    map[string]any{},
+		names:   map[reflect.Type]string{},
+	}
+	root := g.object(k.typ, typeKey(k.typ))
+	root["$schema"] = draft07
+	root["$id"] = k.URL()
+	root["title"] = k.Title
+	root["description"] = k.Description
+	if len(g.defs) > 0 {
+		root["definitions"] = g.defs
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type generator struct {
+	srcRoot string
+	// docs caches parsed comments per package path, keyed by Type or Type.Field.
+	docs  map[string]map[string]string
+	defs  map[string]any
+	names map[reflect.Type]string
+}
+
+// schema returns the schema for t, referencing named structs through definitions.
+func (g *generator) schema(t reflect.Type) map[string]any {
+	if t == durationType {
+		return map[string]any{"type": []string{"string", "integer"}, "pattern": durationPattern}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t, "")
+		}
+		return map[string]any{"$ref": "#/definitions/" + g.define(t)}
+	default:
+		return map[string]any{}
+	}
+}
+
+// define adds the named struct t to definitions and returns its definition name.
+func (g *generator) define(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	for _, taken := range g.names {
+		if taken == name {
+			name = path.Base(t.PkgPath()) + "." + t.Name()
+			break
+		}
+	}
+	g.names[t] = name
+	g.defs[name] = map[string]any{} // placeholder for recursive types
+	g.defs[name] = g.object(t, typeKey(t))
+	return name
+}
+
+// object describes a struct. docKey is "pkg.Type" (or "pkg.Type.Field" for anonymous structs)
+// and prefixes the doc comment lookups for its fields.
+func (g *generator) object(t reflect.Type, docKey string) map[string]any {
+	props := map[string]any{}
+	var required []string
+	open := false
+	g.collect(t, docKey, props, &required, &open)
+	out := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		out["required"] = required
+	}
+	if !open {
+		out["additionalProperties"] = false
+	}
+	if doc := g.doc(docKey); doc != "" && t.Name() != "" {
+		out["description"] = doc
+	}
+	return out
+}
+
+func (g *generator) collect(t reflect.Type, docKey string, props map[string]any, required *[]string, open *bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(","+opts+",", ",inline,") {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			switch ft.Kind() {
+			case reflect.Struct:
+				g.collect(ft, typeKey(ft), props, required, open)
+			case reflect.Map:
+				*open = true
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fieldKey := ""
+		if docKey != "" {
+			fieldKey = docKey + "." + f.Name
+		}
+		var prop map[string]any
+		if f.Type.Kind() == reflect.Struct && f.Type.Name() == "" {
+			prop = g.object(f.Type, fieldKey)
+		} else {
+			prop = g.schema(f.Type)
+		}
+		if doc := g.doc(fieldKey); doc != "" {
+			if enumComment.MatchString(doc) && prop["type"] == "string" {
+				prop["enum"] = enumValues(doc)
+			} else if _, isRef := prop["$ref"]; isRef {
+				prop = map[string]any{"allOf": []any{prop}, "description": doc}
+			} else {
+				prop["description"] = doc
+			}
+		}
+		props[name] = prop
+		if !strings.Contains(","+opts+",", ",omitempty,") && f.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func enumValues(doc string) []string {
+	parts := strings.Split(doc, "|")
+	for i, p := range parts {
+		parts[i] = strings.TrimSuffix(p, " (default)")
+	}
+	return parts
+}
+
+func typeKey(t reflect.Type) string {
+	if t.Name() == "" {
+		return ""
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// doc returns the comment for "pkgpath.Type[.Field...]", parsing the package source on first use.
+func (g *generator) doc(key string) string {
+	if key == "" || g.srcRoot == "" {
+		return ""
+	}
+	dot := strings.LastIndex(key, "/")
+	if dot < 0 {
+		return ""
+	}
+	pkgEnd := dot + strings.Index(key[dot:], ".")
+	pkgPath, rest := key[:pkgEnd], key[pkgEnd+1:]
+	docs, ok := g.docs[pkgPath]
+	if !ok {
+		docs = parseDocs(filepath.Join(g.srcRoot, filepath.FromSlash(strings.TrimPrefix(pkgPath, modulePath+"/"))))
+		g.docs[pkgPath] = docs
+	}
+	return docs[rest]
+}
+
+// parseDocs reads the doc and trailing comments of every struct type and field in dir.
+func parseDocs(dir string) map[string]string {
+	out := map[string]string{}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return out
+	}
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				doc := ts.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				if text := commentText(doc); text != "" {
+					out[ts.Name.Name] = text
+				}
+				collectFieldDocs(out, ts.Name.Name, st)
+			}
+		}
+	}
+	return out
+}
+
+func collectFieldDocs(out map[string]string, prefix string, st *ast.StructType) {
+	for _, field := range st.Fields.List {
+		text := commentText(field.Doc)
+		if text == "" {
+			text = commentText(field.Comment)
+		}
+		for _, name := range field.Names {
+			if text != "" {
+				out[prefix+"."+name.Name] = text
+			}
+			if inner, ok := field.Type.(*ast.StructType); ok {
+				collectFieldDocs(out, prefix+"."+name.Name, inner)
+			}
+		}
+	}
+}
+
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
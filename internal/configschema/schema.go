@@ -0,0 +1,124 @@
+// File: internal/configschema/schema.go
+// Brief: Embedded JSON Schemas for .ktl.yaml, stack.yaml, release.yaml, and verify configs.
+
+// Package configschema publishes JSON Schemas for ktl's config files. The schemas are generated
+// from the Go structs that load each file (see Generate) and embedded in the binary, so
+// `ktl config schema` and editors using yaml-language-server see exactly what this build accepts.
+package configschema
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/stack"
+	verifyconfig "github.com/kubekattle/ktl/internal/verify/config"
+)
+
+// BaseURL is where the schemas in schemas/ are published; the $id of each schema lives under it.
+const BaseURL = "https://raw.githubusercontent.com/kubekattle/ktl/main/internal/configschema/schemas/"
+
+// Kind is one config file format with a schema.
+type Kind struct {
+	// Name is the argument to `ktl config schema`.
+	Name        string
+	Title       string
+	Description string
+	// Files are the file names the schema applies to, for editor fileMatch settings.
+	Files []string
+
+	typ reflect.Type
+}
+
+var kinds = []Kind{
+	{
+		Name:        "ktl",
+		Title:       "ktl config",
+		Description: "Repo (.ktl.yaml) and user (~/.ktl/config.yaml) configuration for ktl.",
+		Files:       []string{".ktl.yaml"},
+		typ:         reflect.TypeOf(appconfig.Config{}),
+	},
+	{
+		Name:        "stack",
+		Title:       "ktl stack",
+		Description: "A ktl stack: defaults, clusters, profiles, runner settings, and inline releases.",
+		Files:       []string{"stack.yaml"},
+		typ:         reflect.TypeOf(stack.StackFile{}),
+	},
+	{
+		Name:        "release",
+		Title:       "ktl stack release",
+		Description: "A single release of a ktl stack, kept in its own directory.",
+		Files:       []string{"release.yaml"},
+		typ:         reflect.TypeOf(stack.ReleaseFile{}),
+	},
+	{
+		Name:        "verify",
+		Title:       "ktl verify config",
+		Description: "Target, rules, and output settings for ktl verify.",
+		Files:       []string{"verify.yaml", "verify-*.yaml"},
+		typ:         reflect.TypeOf(verifyconfig.Config{}),
+	},
+}
+
+//go:embed schemas/*.schema.json
+var embedded embed.FS
+
+// Kinds returns every config kind with a schema.
+func Kinds() []Kind {
+	return append([]Kind(nil), kinds...)
+}
+
+// Lookup finds a kind by name or by one of its file names (".ktl.yaml", "stack.yaml", ...).
+func Lookup(name string) (Kind, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, k := range kinds {
+		if k.Name == name {
+			return k, true
+		}
+		for _, file := range k.Files {
+			if ok, _ := path.Match(file, path.Base(name)); ok {
+				return k, true
+			}
+		}
+	}
+	return Kind{}, false
+}
+
+// Names lists the kind names in a stable order.
+func Names() []string {
+	names := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		names = append(names, k.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FileName is the schema's file name in schemas/ and in directories written by ktl.
+func (k Kind) FileName() string {
+	return k.Name + ".schema.json"
+}
+
+// URL is the published location of the schema.
+func (k Kind) URL() string {
+	return BaseURL + k.FileName()
+}
+
+// Modeline is the comment yaml-language-server reads to pick the schema for a file.
+func (k Kind) Modeline() string {
+	return "# yaml-language-server: $schema=" + k.URL()
+}
+
+// Schema returns the embedded schema for the kind.
+func Schema(name string) ([]byte, error) {
+	k, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown config kind %q (want one of %s)", name, strings.Join(Names(), ", "))
+	}
+	return embedded.ReadFile("schemas/" + k.FileName())
+}
@@ -0,0 +1,129 @@
+package configschema
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"sigs.k8s.io/yaml"
+)
+
+const srcRoot = "../.."
+
+func TestEmbeddedSchemasUpToDate(t *testing.T) {
+	for _, k := range Kinds() {
+		got, err := Generate(k, srcRoot)
+		if err != nil {
+			t.Fatalf("generate %s: %v", k.Name, err)
+		}
+		path := filepath.Join("schemas", k.FileName())
+		if os.Getenv("KTL_UPDATE_GOLDENS") == "1" {
+			if err := os.WriteFile(path, got, 0o644); err != nil {
+				t.Fatalf("write %s: %v", path, err)
+			}
+		}
+		want, err := Schema(k.Name)
+		if err != nil {
+			t.Fatalf("embedded %s: %v", k.Name, err)
+		}
+		if !bytes.Equal(got, want) && os.Getenv("KTL_UPDATE_GOLDENS") != "1" {
+			t.Fatalf("%s is stale; regenerate with KTL_UPDATE_GOLDENS=1 go test ./internal/configschema", path)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	cases := map[string]string{
+		"ktl":                      "ktl",
+		".ktl.yaml":                "ktl",
+		"stacks/prod/stack.yaml":   "stack",
+		"release.yaml":             "release",
+		"ci/verify-prod.yaml":      "verify",
+		"VERIFY":                   "verify",
+		"values.yaml":              "",
+		"":                         "",
+		"apps/web/release.yaml.j2": "",
+	}
+	for in, want := range cases {
+		k, ok := Lookup(in)
+		if got := k.Name; got != want || ok != (want != "") {
+			t.Errorf("Lookup(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	if !strings.HasPrefix(Kinds()[0].Modeline(), "# yaml-language-server: $schema=https://") {
+		t.Fatalf("unexpected modeline %q", Kinds()[0].Modeline())
+	}
+}
+
+// TestSchemasAcceptRepoFixtures validates the stack, release, verify, and init template files in
+// the repo against the schemas, so a schema that is stricter than the loaders fails here.
+func TestSchemasAcceptRepoFixtures(t *testing.T) {
+	compiled := map[string]*jsonschema.Schema{}
+	c := jsonschema.NewCompiler()
+	for _, k := range Kinds() {
+		raw, err := Schema(k.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("%s: %v", k.Name, err)
+		}
+		if err := c.AddResource(k.URL(), doc); err != nil {
+			t.Fatalf("%s: %v", k.Name, err)
+		}
+		if compiled[k.Name], err = c.Compile(k.URL()); err != nil {
+			t.Fatalf("compile %s: %v", k.Name, err)
+		}
+	}
+
+	files := map[string]string{}
+	for _, tpl := range []string{"platform.yaml", "secure.yaml"} {
+		files[filepath.Join(srcRoot, "cmd", "ktl", "templates", "init", tpl)] = "ktl"
+	}
+	err := filepath.WalkDir(srcRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == ".github" || d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if k, ok := Lookup(d.Name()); ok && k.Name != "ktl" {
+			files[path] = k.Name
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 10 {
+		t.Fatalf("expected repo fixtures, found %d", len(files))
+	}
+	for path, kind := range files {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		asJSON, err := yaml.YAMLToJSON(raw)
+		if err != nil {
+			continue // fixtures for parse errors
+		}
+		inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(asJSON))
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if inst == nil {
+			continue
+		}
+		if err := compiled[kind].Validate(inst); err != nil {
+			t.Errorf("%s does not match the %s schema: %v", path, kind, err)
+		}
+	}
+}
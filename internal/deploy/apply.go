@@ -11,8 +11,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kubekattle/ktl/internal/netconfig"
 	"github.com/kubekattle/ktl/internal/secretstore"
+	"github.com/kubekattle/ktl/internal/tracing"
 	"github.com/pmezard/go-difflib/difflib"
+	"go.opentelemetry.io/otel/attribute"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
@@ -20,6 +23,7 @@ import (
 	cliValues "helm.sh/helm/v3/pkg/cli/values"
 	"helm.sh/helm/v3/pkg/getter"
 	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/postrender"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
 )
@@ -34,6 +38,8 @@ type InstallOptions struct {
 	SetValues         []string
 	SetStringValues   []string
 	SetFileValues     []string
+	SetJSONValues     []string
+	SetLiteralValues  []string
 	Secrets           *SecretOptions
 	Timeout           time.Duration
 	Wait              bool
@@ -43,6 +49,23 @@ type InstallOptions struct {
 	Diff              bool
 	UpgradeOnly       bool
 	ProgressObservers []ProgressObserver
+	// GitMetadata, when set, is recorded as release labels and the release description.
+	GitMetadata *GitMetadata
+	// Owner, when set, is recorded as owner annotations and the team label. Without it the
+	// owner of the previous revision is kept.
+	Owner *ReleaseOwner
+	// Cache, when set, reuses the chart download and resolved values of earlier renders in the
+	// same invocation and records dry-run renders for RunCache.PreviewManifest.
+	Cache *RunCache
+	// Charts, when set, shares chart downloads with other releases of the same run.
+	Charts *ChartCache
+	// PostRenderer, when set, rewrites the rendered manifests before they are applied.
+	PostRenderer postrender.PostRenderer
+	// InputDigest, when set, is recorded on the applied release (InputDigestLabel) so a later
+	// stack apply --skip-unchanged can tell the release is already up to date.
+	InputDigest string
+	// Retry retries helm upgrade/install after transient API server and webhook errors.
+	Retry RetryPolicy
 }
 
 type InstallResult struct {
@@ -54,6 +77,16 @@ type InstallResult struct {
 
 // InstallOrUpgrade renders the chart and applies it using Helm's upgrade --install semantics.
 func InstallOrUpgrade(ctx context.Context, actionCfg *action.Configuration, settings *cli.EnvSettings, opts InstallOptions) (*InstallResult, error) {
+	ctx, span := tracing.Start(ctx, "deploy "+opts.ReleaseName,
+		attribute.String("ktl.release", opts.ReleaseName),
+		attribute.String("ktl.chart", opts.Chart),
+		attribute.Bool("ktl.dry_run", opts.DryRun))
+	result, err := installOrUpgrade(ctx, actionCfg, settings, opts)
+	tracing.End(span, err)
+	return result, err
+}
+
+func installOrUpgrade(ctx context.Context, actionCfg *action.Configuration, settings *cli.EnvSettings, opts InstallOptions) (*InstallResult, error) {
 	if opts.Chart == "" {
 		return nil, fmt.Errorf("chart reference is required")
 	}
@@ -69,10 +102,19 @@ func InstallOrUpgrade(ctx context.Context, actionCfg *action.Configuration, sett
 	}
 
 	observers := append([]ProgressObserver(nil), opts.ProgressObservers...)
+	if tracing.Enabled() {
+		phases := newPhaseTracer(ctx)
+		defer phases.finish()
+		observers = append(observers, phases)
+	}
 	notifyPhaseStarted(observers, PhaseRender)
 
-	chartPathOptions := action.ChartPathOptions{Version: opts.Version}
-	chartPath, err := chartPathOptions.LocateChart(opts.Chart, settings)
+	chartOpts, err := chartPathOptions(ctx, settings, opts.Chart, "", opts.Version, opts.Secrets)
+	if err != nil {
+		notifyPhaseCompleted(observers, PhaseRender, "failed", err.Error())
+		return nil, fmt.Errorf("locate chart: %w", err)
+	}
+	chartPath, err := opts.Cache.locateChart(chartOpts, opts.Chart, settings, opts.Charts)
 	if err != nil {
 		notifyPhaseCompleted(observers, PhaseRender, "failed", err.Error())
 		return nil, fmt.Errorf("locate chart: %w", err)
@@ -86,8 +128,16 @@ func InstallOrUpgrade(ctx context.Context, actionCfg *action.Configuration, sett
 		notifyPhaseCompleted(observers, PhaseRender, "failed", err.Error())
 		return nil, fmt.Errorf("chart not installable: %w", err)
 	}
+	if err := ensureVendoredDependencies(chartRequested); err != nil {
+		notifyPhaseCompleted(observers, PhaseRender, "failed", err.Error())
+		return nil, err
+	}
 
-	vals, err := buildValues(ctx, settings, opts.ValuesFiles, opts.SetValues, opts.SetStringValues, opts.SetFileValues, opts.Secrets)
+	if err := CheckSetValueTypes(chartRequested, opts.SetValues, opts.SetStringValues, opts.SetJSONValues, opts.SetLiteralValues); err != nil {
+		notifyPhaseCompleted(observers, PhaseRender, "failed", err.Error())
+		return nil, err
+	}
+	vals, err := opts.Cache.buildValues(ctx, settings, opts.ValuesFiles, opts.SetValues, opts.SetStringValues, opts.SetFileValues, opts.SetJSONValues, opts.SetLiteralValues, opts.Secrets)
 	if err != nil {
 		notifyPhaseCompleted(observers, PhaseRender, "failed", err.Error())
 		return nil, err
@@ -106,6 +156,17 @@ func InstallOrUpgrade(ctx context.Context, actionCfg *action.Configuration, sett
 	upgrade.Atomic = opts.Atomic
 	upgrade.Install = true
 	upgrade.DryRun = opts.DryRun || opts.Diff
+	owner := opts.Owner
+	if owner.Empty() && actionCfg.Releases != nil {
+		if last, err := actionCfg.Releases.Last(opts.ReleaseName); err == nil {
+			if annotations, err := StoredReleaseAnnotations(ctx, actionCfg, last); err == nil {
+				owner = ReleaseOwnerOf(last, annotations)
+			}
+		}
+	}
+	upgrade.Labels = mergeReleaseLabels(opts.GitMetadata.ReleaseLabels(), owner.ReleaseLabels())
+	upgrade.Description = opts.GitMetadata.Description()
+	upgrade.PostRenderer = opts.PostRenderer
 
 	diffEnabled := opts.Diff
 	if diffEnabled {
@@ -129,7 +190,14 @@ func InstallOrUpgrade(ctx context.Context, actionCfg *action.Configuration, sett
 		}
 	}
 
-	release, err := upgrade.RunWithContext(ctx, opts.ReleaseName, chartRequested, vals)
+	var release *release.Release
+	err = retryTransient(ctx, observers, "helm upgrade", opts.Retry, func(int) error {
+		helmCtx, helmSpan := tracing.Start(ctx, "helm upgrade", attribute.String("ktl.release", opts.ReleaseName), attribute.String("k8s.namespace.name", namespace))
+		var runErr error
+		release, runErr = upgrade.RunWithContext(helmCtx, opts.ReleaseName, chartRequested, vals)
+		tracing.End(helmSpan, runErr)
+		return runErr
+	})
 	installPerformed := false
 	if err != nil {
 		if !opts.UpgradeOnly && isNoDeployedReleaseErr(err) {
@@ -143,7 +211,18 @@ func InstallOrUpgrade(ctx context.Context, actionCfg *action.Configuration, sett
 			install.Atomic = opts.Atomic
 			install.CreateNamespace = opts.CreateNamespace
 			install.DryRun = upgrade.DryRun
-			release, err = install.RunWithContext(ctx, chartRequested, vals)
+			install.Labels = upgrade.Labels
+			install.Description = upgrade.Description
+			install.PostRenderer = opts.PostRenderer
+			err = retryTransient(ctx, observers, "helm install", opts.Retry, func(attempt int) error {
+				// A failed attempt leaves a failed release behind; Replace lets the retry reuse its name.
+				install.Replace = attempt > 1
+				helmCtx, helmSpan := tracing.Start(ctx, "helm install", attribute.String("ktl.release", opts.ReleaseName), attribute.String("k8s.namespace.name", namespace))
+				var runErr error
+				release, runErr = install.RunWithContext(helmCtx, chartRequested, vals)
+				tracing.End(helmSpan, runErr)
+				return runErr
+			})
 			if err != nil {
 				notifyPhaseCompleted(observers, PhaseInstall, "failed", err.Error())
 				if opts.Wait {
@@ -181,6 +260,11 @@ func InstallOrUpgrade(ctx context.Context, actionCfg *action.Configuration, sett
 	}
 
 	result := &InstallResult{Release: release}
+	if upgrade.DryRun {
+		opts.Cache.rememberPreview(release)
+	} else if err := recordReleaseMetadata(ctx, actionCfg, release, opts.InputDigest, owner); err != nil {
+		notifyEvent(observers, "warn", fmt.Sprintf("Could not record the manifest digest and owner on release %s: %v", opts.ReleaseName, err))
+	}
 	if opts.Diff {
 		result.ManifestDiff = diffManifests(previousManifest, release.Manifest)
 		if kc, ok := actionCfg.KubeClient.(*kube.Client); ok && kc != nil {
@@ -209,18 +293,26 @@ func InstallOrUpgrade(ctx context.Context, actionCfg *action.Configuration, sett
 	return result, nil
 }
 
-func buildValues(ctx context.Context, settings *cli.EnvSettings, files, setVals, setStringVals, setFileVals []string, secrets *SecretOptions) (map[string]interface{}, error) {
+func buildValues(ctx context.Context, settings *cli.EnvSettings, files, setVals, setStringVals, setFileVals, setJSONVals, setLiteralVals []string, secrets *SecretOptions) (map[string]interface{}, error) {
 	valOpts := &cliValues.Options{
-		ValueFiles:   files,
-		Values:       setVals,
-		StringValues: setStringVals,
-		FileValues:   setFileVals,
-	}
-	providers := getter.All(settings)
+		ValueFiles:    files,
+		Values:        setVals,
+		StringValues:  setStringVals,
+		FileValues:    setFileVals,
+		JSONValues:    setJSONVals,
+		LiteralValues: setLiteralVals,
+	}
+	providers := getter.All(settings, netconfig.GetterOptions()...)
 	vals, err := valOpts.MergeValues(providers)
 	if err != nil {
 		return nil, fmt.Errorf("merge values: %w", err)
 	}
+	if err := resolveTerraformRefs(ctx, vals, secrets != nil && secrets.Mask); err != nil {
+		return nil, err
+	}
+	if err := resolveValueSourceRefs(ctx, vals, secrets); err != nil {
+		return nil, err
+	}
 	if secrets == nil || secrets.Resolver == nil {
 		refs := secretstore.FindRefs(vals)
 		if len(refs) > 0 {
@@ -281,6 +373,18 @@ func ensureInstallable(ch *chart.Chart) error {
 	return fmt.Errorf("%s charts are not installable", chartType)
 }
 
+// ensureVendoredDependencies fails in air-gapped mode when a dependency listed in Chart.yaml is
+// missing from charts/, since it could only be fetched from a repository.
+func ensureVendoredDependencies(ch *chart.Chart) error {
+	if !netconfig.Airgapped() || ch.Metadata == nil || len(ch.Metadata.Dependencies) == 0 {
+		return nil
+	}
+	if err := action.CheckDependencies(ch, ch.Metadata.Dependencies); err != nil {
+		return fmt.Errorf("%w: %v; run helm dependency build while online and ship the charts/ directory", netconfig.ErrAirgap, err)
+	}
+	return nil
+}
+
 func isNoDeployedReleaseErr(err error) bool {
 	if err == nil {
 		return false
@@ -0,0 +1,149 @@
+// File: internal/deploy/approval.go
+// Brief: Internal deploy package implementation for 'approval'.
+
+// approval.go implements the two-person rule for applies: a plan digest that names exactly what
+// will be applied, and signed approval tokens for it issued by `ktl approve`.
+package deploy
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+)
+
+// Approval policies for ktl apply --approval-policy.
+const (
+	ApprovalPolicyNone   = "none"
+	ApprovalPolicyRisky  = "risky"
+	ApprovalPolicyAlways = "always"
+)
+
+const approvalTokenPrefix = "ktlapprove1"
+
+// Approval is the signed content of an approval token.
+type Approval struct {
+	PlanDigest string    `json:"planDigest"`
+	Approver   string    `json:"approver"`
+	Host       string    `json:"host,omitempty"`
+	IssuedAt   time.Time `json:"issuedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+
+	// SignerKey is the configured public key that verified the token (set by VerifyApproval).
+	SignerKey ed25519.PublicKey `json:"-"`
+}
+
+// ParseApprovalPolicy validates an --approval-policy value; "" is none.
+func ParseApprovalPolicy(raw string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(raw)); v {
+	case "", ApprovalPolicyNone:
+		return ApprovalPolicyNone, nil
+	case ApprovalPolicyRisky, ApprovalPolicyAlways:
+		return v, nil
+	default:
+		return "", fmt.Errorf("invalid approval policy %q (expected none, risky, or always)", raw)
+	}
+}
+
+// approvalPolicyRank orders policies from loosest to strictest.
+var approvalPolicyRank = map[string]int{ApprovalPolicyNone: 0, ApprovalPolicyRisky: 1, ApprovalPolicyAlways: 2}
+
+// StricterApprovalPolicy returns the strictest of the parsed policies, so a repo policy acts as a
+// floor that --approval-policy can tighten but not loosen.
+func StricterApprovalPolicy(policies ...string) string {
+	out := ApprovalPolicyNone
+	for _, p := range policies {
+		if approvalPolicyRank[p] > approvalPolicyRank[out] {
+			out = p
+		}
+	}
+	return out
+}
+
+// ApprovalRequired reports whether policy requires a second approver for a plan with risky
+// changes.
+func ApprovalRequired(policy string, risky bool) bool {
+	switch policy {
+	case ApprovalPolicyAlways:
+		return true
+	case ApprovalPolicyRisky:
+		return risky
+	}
+	return false
+}
+
+// PlanDigest identifies a rendered release: any change to the manifest, release, or namespace
+// yields a different digest, so an approval cannot be reused for another apply.
+func PlanDigest(release, namespace, manifest string) string {
+	manifest = strings.TrimSpace(strings.ReplaceAll(manifest, "\r\n", "\n"))
+	sum := sha256.Sum256([]byte(release + "\n" + namespace + "\n" + manifest + "\n"))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// SignApproval encodes a as a token signed with priv.
+func SignApproval(a Approval, priv ed25519.PrivateKey) (string, error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		return "", errors.New("approval key is not an ed25519 private key")
+	}
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return "", err
+	}
+	sig := ed25519.Sign(priv, payload)
+	enc := base64.RawURLEncoding
+	return approvalTokenPrefix + "." + enc.EncodeToString(payload) + "." + enc.EncodeToString(sig), nil
+}
+
+// VerifyApproval checks that token approves digest, has not expired at now, and is signed by one
+// of approvers. The returned approval names the configured approver, not the name in the token.
+func VerifyApproval(token, digest string, approvers []appconfig.Approver, now time.Time) (*Approval, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 || parts[0] != approvalTokenPrefix {
+		return nil, errors.New("malformed approval token")
+	}
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed approval token: %w", err)
+	}
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed approval token: %w", err)
+	}
+	signer := ""
+	var signerKey ed25519.PublicKey
+	for _, ap := range approvers {
+		pub, err := base64.StdEncoding.DecodeString(strings.TrimSpace(ap.PublicKey))
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			continue
+		}
+		if ed25519.Verify(ed25519.PublicKey(pub), payload, sig) {
+			signer = strings.TrimSpace(ap.Name)
+			signerKey = ed25519.PublicKey(pub)
+			break
+		}
+	}
+	if signer == "" {
+		return nil, errors.New("approval token is not signed by a configured approver (deploy.approvers in .ktl.yaml)")
+	}
+	var a Approval
+	if err := json.Unmarshal(payload, &a); err != nil {
+		return nil, fmt.Errorf("malformed approval token: %w", err)
+	}
+	if a.PlanDigest != digest {
+		return nil, fmt.Errorf("approval token is for plan %s, not %s", a.PlanDigest, digest)
+	}
+	if !a.ExpiresAt.IsZero() && now.After(a.ExpiresAt) {
+		return nil, fmt.Errorf("approval token from %s expired at %s", signer, a.ExpiresAt.Format(time.RFC3339))
+	}
+	a.Approver = signer
+	a.SignerKey = signerKey
+	return &a, nil
+}
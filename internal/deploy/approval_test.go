@@ -0,0 +1,89 @@
+package deploy
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+)
+
+func testApprover(t *testing.T, name string) (appconfig.Approver, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return appconfig.Approver{Name: name, PublicKey: base64.StdEncoding.EncodeToString(pub)}, priv
+}
+
+func TestPlanDigest(t *testing.T) {
+	a := PlanDigest("web", "prod", "kind: Service\r\n")
+	if a != PlanDigest("web", "prod", "kind: Service\n\n") {
+		t.Fatalf("expected line endings and trailing whitespace to be ignored")
+	}
+	if a == PlanDigest("web", "staging", "kind: Service\n") || a == PlanDigest("web", "prod", "kind: Deployment\n") {
+		t.Fatalf("expected namespace and manifest to change the digest")
+	}
+	if !strings.HasPrefix(a, "sha256:") || len(a) != len("sha256:")+64 {
+		t.Fatalf("digest = %q", a)
+	}
+}
+
+func TestVerifyApproval(t *testing.T) {
+	alice, alicePriv := testApprover(t, "alice")
+	_, malloryPriv := testApprover(t, "mallory")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	digest := PlanDigest("web", "prod", "kind: Service\n")
+	sign := func(priv ed25519.PrivateKey, digest string, expires time.Time) string {
+		token, err := SignApproval(Approval{PlanDigest: digest, Approver: "bob", IssuedAt: now, ExpiresAt: expires}, priv)
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return token
+	}
+
+	got, err := VerifyApproval(sign(alicePriv, digest, now.Add(time.Hour)), digest, []appconfig.Approver{alice}, now)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !got.SignerKey.Equal(alicePriv.Public()) {
+		t.Fatalf("expected the verifying key to be returned")
+	}
+	if got.Approver != "alice" {
+		t.Fatalf("expected the configured name to win over the token's claim, got %q", got.Approver)
+	}
+
+	cases := map[string]struct {
+		token string
+		want  string
+	}{
+		"untrusted key": {sign(malloryPriv, digest, now.Add(time.Hour)), "not signed by a configured approver"},
+		"other plan":    {sign(alicePriv, PlanDigest("web", "prod", "kind: Pod\n"), now.Add(time.Hour)), "is for plan"},
+		"expired":       {sign(alicePriv, digest, now.Add(-time.Minute)), "expired"},
+		"malformed":     {"ktlapprove1.nope", "malformed"},
+	}
+	for name, tc := range cases {
+		if _, err := VerifyApproval(tc.token, digest, []appconfig.Approver{alice}, now); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestApprovalRequired(t *testing.T) {
+	if _, err := ParseApprovalPolicy("sometimes"); err == nil {
+		t.Fatalf("expected invalid policy to be rejected")
+	}
+	if got := StricterApprovalPolicy(ApprovalPolicyNone, ApprovalPolicyRisky); got != ApprovalPolicyRisky {
+		t.Fatalf("expected the repo policy to act as a floor, got %q", got)
+	}
+	if got := StricterApprovalPolicy(ApprovalPolicyAlways, ApprovalPolicyRisky); got != ApprovalPolicyAlways {
+		t.Fatalf("expected the flag to tighten the repo policy, got %q", got)
+	}
+	if ApprovalRequired(ApprovalPolicyNone, true) || ApprovalRequired(ApprovalPolicyRisky, false) || !ApprovalRequired(ApprovalPolicyRisky, true) || !ApprovalRequired(ApprovalPolicyAlways, false) {
+		t.Fatalf("unexpected ApprovalRequired results")
+	}
+}
@@ -0,0 +1,418 @@
+// File: internal/deploy/bundle.go
+// Brief: Internal deploy package implementation for 'bundle'.
+
+// bundle.go packs a deployed release into a self-contained archive (chart, values, pinned
+// images, plan) that can be reviewed and applied in a cluster with no access to the chart
+// repository, the image registries, or the secrets backend of the source.
+package deploy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"sigs.k8s.io/yaml"
+)
+
+// BundleAPIVersion identifies the bundle.json layout.
+const BundleAPIVersion = "ktl.dev/bundle/v1"
+
+const (
+	BundleManifestFile = "bundle.json"
+	BundleChartFile    = "chart.tgz"
+	BundleValuesFile   = "values.yaml"
+	BundleImagesFile   = "images.txt"
+	BundlePlanFile     = "plan.json"
+)
+
+// maxBundleFileSize bounds each archive member read by ReadBundle.
+const maxBundleFileSize = 256 << 20
+
+// BundleManifest describes the contents of a release bundle.
+type BundleManifest struct {
+	APIVersion   string            `json:"apiVersion"`
+	Release      string            `json:"release"`
+	Namespace    string            `json:"namespace"`
+	Chart        string            `json:"chart"`
+	ChartVersion string            `json:"chartVersion"`
+	AppVersion   string            `json:"appVersion,omitempty"`
+	Source       BundleSource      `json:"source"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	SecretRefs   []BundleSecretRef `json:"secretRefs,omitempty"`
+	Images       []BundleImage     `json:"images,omitempty"`
+	// Files maps each archive member to its SHA-256; ReadBundle rejects mismatches.
+	Files map[string]string `json:"files"`
+}
+
+// BundleSource is the deployed release a bundle was exported from.
+type BundleSource struct {
+	Context  string `json:"context,omitempty"`
+	Cluster  string `json:"cluster,omitempty"`
+	Revision int    `json:"revision"`
+}
+
+// BundleSecretRef is a values key whose value was stripped from the bundle and replaced by a
+// secret:// reference, resolved by the target environment's secrets provider on apply.
+type BundleSecretRef struct {
+	Path      string `json:"path"`
+	Reference string `json:"reference"`
+}
+
+// BundleImage is a container image of the release and the digest it was pinned to at export.
+type BundleImage struct {
+	Image  string `json:"image"`
+	Digest string `json:"digest,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Bundle is a release bundle in memory.
+type Bundle struct {
+	Manifest BundleManifest
+	Chart    []byte
+	Values   []byte
+	Plan     []byte
+}
+
+// ImageDigests returns the pinned images as name:tag -> digest for the images post-renderer.
+// Images exported by digest, or whose digest could not be resolved, are left out.
+func (m BundleManifest) ImageDigests() map[string]string {
+	out := map[string]string{}
+	for _, img := range m.Images {
+		if img.Digest == "" || strings.Contains(img.Image, "@") {
+			continue
+		}
+		out[img.Image] = img.Digest
+	}
+	return out
+}
+
+// UnpinnedImages lists the images whose digest could not be resolved at export.
+func (m BundleManifest) UnpinnedImages() []string {
+	var out []string
+	for _, img := range m.Images {
+		if img.Digest == "" {
+			out = append(out, img.Image)
+		}
+	}
+	return out
+}
+
+// WriteBundle writes b to w as a gzipped tar and records the member digests in its manifest.
+func WriteBundle(w io.Writer, b *Bundle) error {
+	if b == nil {
+		return fmt.Errorf("bundle is nil")
+	}
+	members := b.members()
+	b.Manifest.APIVersion = BundleAPIVersion
+	b.Manifest.Files = map[string]string{}
+	for _, m := range members {
+		if len(m.data) == 0 {
+			continue
+		}
+		sum := sha256.Sum256(m.data)
+		b.Manifest.Files[m.name] = hex.EncodeToString(sum[:])
+	}
+	return writeBundleArchive(w, b)
+}
+
+type bundleMember struct {
+	name string
+	data []byte
+}
+
+func (b *Bundle) members() []bundleMember {
+	return []bundleMember{
+		{BundleChartFile, b.Chart},
+		{BundleValuesFile, b.Values},
+		{BundleImagesFile, []byte(formatBundleImages(b.Manifest.Images))},
+		{BundlePlanFile, b.Plan},
+	}
+}
+
+// writeBundleArchive writes b with its manifest as-is.
+func writeBundleArchive(w io.Writer, b *Bundle) error {
+	members := b.members()
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", BundleManifestFile, err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	modTime := b.Manifest.CreatedAt
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+	write := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	if err := write(BundleManifestFile, append(manifest, '\n')); err != nil {
+		return err
+	}
+	for _, m := range members {
+		if len(m.data) == 0 {
+			continue
+		}
+		if err := write(m.name, m.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ReadBundle reads a bundle written by WriteBundle and verifies every member against the
+// digests recorded in bundle.json.
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read bundle: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxBundleFileSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("read bundle %s: %w", hdr.Name, err)
+		}
+		if len(data) > maxBundleFileSize {
+			return nil, fmt.Errorf("bundle member %s exceeds %d bytes", hdr.Name, maxBundleFileSize)
+		}
+		files[hdr.Name] = data
+	}
+
+	raw, ok := files[BundleManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("not a ktl bundle: %s is missing", BundleManifestFile)
+	}
+	b := &Bundle{}
+	if err := json.Unmarshal(raw, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", BundleManifestFile, err)
+	}
+	if b.Manifest.APIVersion != BundleAPIVersion {
+		return nil, fmt.Errorf("unsupported bundle apiVersion %q (want %s)", b.Manifest.APIVersion, BundleAPIVersion)
+	}
+	for name, want := range b.Manifest.Files {
+		data, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("bundle member %s is missing", name)
+		}
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); got != want {
+			return nil, fmt.Errorf("bundle member %s was modified (sha256 %s, recorded %s)", name, got, want)
+		}
+	}
+	for name := range files {
+		if _, ok := b.Manifest.Files[name]; !ok && name != BundleManifestFile {
+			return nil, fmt.Errorf("bundle member %s is not listed in %s", name, BundleManifestFile)
+		}
+	}
+	b.Chart = files[BundleChartFile]
+	b.Values = files[BundleValuesFile]
+	b.Plan = files[BundlePlanFile]
+	if len(b.Chart) == 0 {
+		return nil, fmt.Errorf("bundle has no %s", BundleChartFile)
+	}
+	return b, nil
+}
+
+// PackChart serializes ch as a Helm chart archive.
+func PackChart(ch *chart.Chart) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "ktl-bundle-chart-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	path, err := chartutil.Save(ch, dir)
+	if err != nil {
+		return nil, fmt.Errorf("package chart: %w", err)
+	}
+	return os.ReadFile(path)
+}
+
+// StripSecretValues returns a copy of values where every string under a key that looks like a
+// credential (password, token, clientSecret, ...), and every dotted path in extra, is replaced
+// by a secret:///<prefix>/<path> reference. Existing secret:// references are kept and listed.
+func StripSecretValues(values map[string]any, prefix string, extra []string) (map[string]any, []BundleSecretRef) {
+	forced := map[string]bool{}
+	for _, p := range extra {
+		if p = strings.Trim(strings.TrimSpace(p), "."); p != "" {
+			forced[p] = true
+		}
+	}
+	prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+	var refs []BundleSecretRef
+	var walk func(v any, path []string) any
+	walk = func(v any, path []string) any {
+		switch t := v.(type) {
+		case map[string]any:
+			out := make(map[string]any, len(t))
+			for k, child := range t {
+				out[k] = walk(child, append(append([]string(nil), path...), k))
+			}
+			return out
+		case []any:
+			out := make([]any, len(t))
+			for i, child := range t {
+				out[i] = walk(child, append(append([]string(nil), path...), fmt.Sprint(i)))
+			}
+			return out
+		case string:
+			dotted := strings.Join(path, ".")
+			if strings.HasPrefix(t, "secret://") {
+				refs = append(refs, BundleSecretRef{Path: dotted, Reference: t})
+				return t
+			}
+			if t == "" || len(path) == 0 || (!forced[dotted] && !secretValueKey(path[len(path)-1])) {
+				return t
+			}
+			ref := "secret:///" + strings.Join(path, "/")
+			if prefix != "" {
+				ref = "secret:///" + prefix + "/" + strings.Join(path, "/")
+			}
+			refs = append(refs, BundleSecretRef{Path: dotted, Reference: ref})
+			return ref
+		default:
+			return v
+		}
+	}
+	out, _ := walk(values, nil).(map[string]any)
+	if out == nil {
+		out = map[string]any{}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Path < refs[j].Path })
+	return out, refs
+}
+
+var secretValueKeySuffixes = []string{"password", "passwd", "secret", "token", "apikey", "privatekey", "credentials", "dsn"}
+
+// secretValueKey reports whether a values key names a credential. Keys such as existingSecret
+// or secretName name a Kubernetes Secret rather than hold one and are not matched.
+func secretValueKey(key string) bool {
+	k := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	if strings.HasPrefix(k, "existing") {
+		return false
+	}
+	for _, suffix := range secretValueKeySuffixes {
+		if strings.HasSuffix(k, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// EncodeBundleValues marshals values as YAML.
+func EncodeBundleValues(values map[string]any) ([]byte, error) {
+	if len(values) == 0 {
+		return []byte("{}\n"), nil
+	}
+	return yaml.Marshal(values)
+}
+
+// ManifestImages lists the distinct container images referenced by manifest, sorted.
+func ManifestImages(manifest string) []string {
+	seen := map[string]bool{}
+	for _, doc := range splitYAMLDocs(manifest) {
+		var obj map[string]any
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj == nil {
+			continue
+		}
+		walkContainers(obj, func(c map[string]any) {
+			if image, _ := c["image"].(string); strings.TrimSpace(image) != "" {
+				seen[strings.TrimSpace(image)] = true
+			}
+		})
+	}
+	out := make([]string, 0, len(seen))
+	for image := range seen {
+		out = append(out, image)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// PinBundleImages resolves the digest of every image. Images already referenced by digest keep
+// it; resolution failures are recorded on the image instead of failing the export.
+func PinBundleImages(ctx context.Context, images []string) []BundleImage {
+	return pinBundleImages(ctx, images, resolveImageDigest)
+}
+
+func pinBundleImages(ctx context.Context, images []string, resolve func(context.Context, reference.NamedTagged) (string, error)) []BundleImage {
+	out := make([]BundleImage, 0, len(images))
+	for _, image := range images {
+		img := BundleImage{Image: image}
+		named, err := reference.ParseNormalizedNamed(image)
+		if err != nil {
+			img.Error = err.Error()
+			out = append(out, img)
+			continue
+		}
+		if canonical, ok := named.(reference.Canonical); ok {
+			img.Digest = canonical.Digest().String()
+			out = append(out, img)
+			continue
+		}
+		tagged, _ := reference.TagNameOnly(named).(reference.NamedTagged)
+		rctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		digest, err := resolve(rctx, tagged)
+		cancel()
+		if err != nil {
+			img.Error = err.Error()
+		} else {
+			img.Digest = digest
+		}
+		out = append(out, img)
+	}
+	return out
+}
+
+func formatBundleImages(images []BundleImage) string {
+	var buf bytes.Buffer
+	for _, img := range images {
+		switch {
+		case strings.Contains(img.Image, "@"):
+			buf.WriteString(img.Image + "\n")
+		case img.Digest != "":
+			named, err := reference.ParseNormalizedNamed(img.Image)
+			if err != nil {
+				buf.WriteString(img.Image + "\n")
+				continue
+			}
+			fmt.Fprintf(&buf, "%s@%s\n", reference.TagNameOnly(named).String(), img.Digest)
+		default:
+			fmt.Fprintf(&buf, "%s # unpinned: %s\n", img.Image, img.Error)
+		}
+	}
+	return buf.String()
+}
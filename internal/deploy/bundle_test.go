@@ -0,0 +1,165 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/distribution/reference"
+)
+
+func TestStripSecretValuesReplacesCredentials(t *testing.T) {
+	values := map[string]any{
+		"replicas": float64(2),
+		"db": map[string]any{
+			"host":           "db.internal",
+			"password":       "hunter2",
+			"existingSecret": "db-creds",
+		},
+		"oauth": map[string]any{"client_secret": "abc", "clientId": "shop"},
+		"extra": []any{map[string]any{"apiKey": "k1"}},
+		"smtp":  map[string]any{"user": "mailer"},
+		"vault": "secret://vault/shop/token",
+	}
+	got, refs := StripSecretValues(values, "shop", []string{"smtp.user"})
+
+	db := got["db"].(map[string]any)
+	if db["password"] != "secret:///shop/db/password" {
+		t.Fatalf("db.password = %v", db["password"])
+	}
+	if db["existingSecret"] != "db-creds" || db["host"] != "db.internal" {
+		t.Fatalf("non-credential keys changed: %v", db)
+	}
+	if got["replicas"] != float64(2) {
+		t.Fatalf("replicas = %v", got["replicas"])
+	}
+	if values["db"].(map[string]any)["password"] != "hunter2" {
+		t.Fatalf("input values were modified")
+	}
+	want := []BundleSecretRef{
+		{Path: "db.password", Reference: "secret:///shop/db/password"},
+		{Path: "extra.0.apiKey", Reference: "secret:///shop/extra/0/apiKey"},
+		{Path: "oauth.client_secret", Reference: "secret:///shop/oauth/client_secret"},
+		{Path: "smtp.user", Reference: "secret:///shop/smtp/user"},
+		{Path: "vault", Reference: "secret://vault/shop/token"},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Fatalf("refs = %#v", refs)
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	b := &Bundle{
+		Manifest: BundleManifest{
+			Release:      "shop",
+			Namespace:    "prod",
+			Chart:        "shop",
+			ChartVersion: "1.2.3",
+			CreatedAt:    time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+			Images: []BundleImage{
+				{Image: "nginx:1.25", Digest: "sha256:" + strings.Repeat("a", 64)},
+				{Image: "ghcr.io/acme/api:v1", Error: "unauthorized"},
+			},
+		},
+		Chart:  []byte("chart-bytes"),
+		Values: []byte("replicas: 2\n"),
+		Plan:   []byte(`{"release":"shop"}`),
+	}
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, b); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := ReadBundle(&buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(got.Chart) != "chart-bytes" || string(got.Values) != "replicas: 2\n" || string(got.Plan) != `{"release":"shop"}` {
+		t.Fatalf("unexpected contents: %+v", got)
+	}
+	if got.Manifest.APIVersion != BundleAPIVersion || len(got.Manifest.Files) != 4 {
+		t.Fatalf("manifest = %+v", got.Manifest)
+	}
+	if d := got.Manifest.ImageDigests(); len(d) != 1 || d["nginx:1.25"] == "" {
+		t.Fatalf("digests = %v", d)
+	}
+	if u := got.Manifest.UnpinnedImages(); !reflect.DeepEqual(u, []string{"ghcr.io/acme/api:v1"}) {
+		t.Fatalf("unpinned = %v", u)
+	}
+
+	if _, err := ReadBundle(bytes.NewReader([]byte("not a bundle"))); err == nil {
+		t.Fatalf("expected error for a non-gzip input")
+	}
+}
+
+func TestBundleReadRejectsModifiedMember(t *testing.T) {
+	b := &Bundle{Manifest: BundleManifest{Release: "shop"}, Chart: []byte("chart-bytes")}
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, b); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// Record a different digest for the chart, as if the archive had been repacked.
+	b.Manifest.Files[BundleChartFile] = strings.Repeat("0", 64)
+	var repacked bytes.Buffer
+	if err := writeBundleArchive(&repacked, b); err != nil {
+		t.Fatalf("repack: %v", err)
+	}
+	if _, err := ReadBundle(&repacked); err == nil || !strings.Contains(err.Error(), "was modified") {
+		t.Fatalf("expected a digest mismatch, got %v", err)
+	}
+}
+
+func TestManifestImagesAndPinning(t *testing.T) {
+	manifest := `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: migrate
+          image: ghcr.io/acme/api:v1
+      containers:
+        - name: api
+          image: ghcr.io/acme/api:v1
+        - name: proxy
+          image: envoyproxy/envoy@sha256:` + strings.Repeat("b", 64) + `
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: report
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: report
+              image: busybox
+`
+	images := ManifestImages(manifest)
+	want := []string{"busybox", "envoyproxy/envoy@sha256:" + strings.Repeat("b", 64), "ghcr.io/acme/api:v1"}
+	if !reflect.DeepEqual(images, want) {
+		t.Fatalf("images = %v", images)
+	}
+	resolve := func(_ context.Context, ref reference.NamedTagged) (string, error) {
+		if ref.Tag() == "latest" {
+			return "", errors.New("offline")
+		}
+		return "sha256:" + strings.Repeat("c", 64), nil
+	}
+	pinned := pinBundleImages(context.Background(), images, resolve)
+	if pinned[0].Error != "offline" || pinned[1].Digest != "sha256:"+strings.Repeat("b", 64) || pinned[2].Digest != "sha256:"+strings.Repeat("c", 64) {
+		t.Fatalf("pinned = %+v", pinned)
+	}
+	text := formatBundleImages(pinned)
+	if !strings.Contains(text, "ghcr.io/acme/api:v1@sha256:") || !strings.Contains(text, "busybox # unpinned: offline") {
+		t.Fatalf("images.txt:\n%s", text)
+	}
+}
@@ -0,0 +1,178 @@
+// File: internal/deploy/chart_cache.go
+// Brief: Internal deploy package implementation for 'chart cache'.
+
+// chart_cache.go shares chart downloads between the releases of a stack run, and optionally
+// across runs through a cache directory.
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"golang.org/x/sync/singleflight"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+// ChartCache deduplicates chart downloads across concurrent renders: callers asking for the same
+// ref, repo and version while a download is in flight wait for it instead of starting another.
+// With a directory, archives for exact versions are kept there and reused by later runs. A nil
+// *ChartCache locates every chart directly. It is safe for concurrent use.
+type ChartCache struct {
+	dir string
+
+	group singleflight.Group
+	mu    sync.Mutex
+	paths map[string]string
+	stats ChartCacheStats
+
+	// locate is opts.LocateChart; tests replace it.
+	locate func(opts action.ChartPathOptions, ref string, settings *cli.EnvSettings) (string, error)
+}
+
+// ChartCacheStats counts how chart lookups were served.
+type ChartCacheStats struct {
+	Downloads int `json:"downloads"`
+	// Shared lookups reused a chart located earlier in this run, or waited on an in-flight download.
+	Shared int `json:"shared"`
+	// Persistent lookups were served from the cache directory.
+	Persistent int `json:"persistent"`
+}
+
+// NewChartCache returns a cache for one run. dir, when set, persists archives across runs.
+func NewChartCache(dir string) *ChartCache {
+	return &ChartCache{
+		dir:   strings.TrimSpace(dir),
+		paths: map[string]string{},
+		locate: func(opts action.ChartPathOptions, ref string, settings *cli.EnvSettings) (string, error) {
+			return opts.LocateChart(ref, settings)
+		},
+	}
+}
+
+// Stats returns the lookup counters so far.
+func (c *ChartCache) Stats() ChartCacheStats {
+	if c == nil {
+		return ChartCacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Locate returns a local path for the chart, downloading it at most once per run. opts must
+// already have the network settings applied.
+func (c *ChartCache) Locate(opts action.ChartPathOptions, ref string, settings *cli.EnvSettings) (string, error) {
+	if c == nil {
+		return opts.LocateChart(ref, settings)
+	}
+	if _, err := os.Stat(ref); err == nil {
+		// Local charts need no download and must not be served stale from the cache directory.
+		return c.locate(opts, ref, settings)
+	}
+	key := strings.Join([]string{ref, opts.RepoURL, opts.Version}, "\x00")
+	c.mu.Lock()
+	if path, ok := c.paths[key]; ok {
+		c.stats.Shared++
+		c.mu.Unlock()
+		return path, nil
+	}
+	c.mu.Unlock()
+
+	ran := false
+	v, err, _ := c.group.Do(key, func() (any, error) {
+		ran = true
+		persisted := c.persistentPath(key, ref, opts.Version)
+		if persisted != "" {
+			if _, err := os.Stat(persisted); err == nil {
+				c.remember(key, persisted, func(s *ChartCacheStats) { s.Persistent++ })
+				return persisted, nil
+			}
+		}
+		path, err := c.locate(opts, ref, settings)
+		if err != nil {
+			return "", err
+		}
+		if persisted != "" {
+			if err := copyFileAtomic(path, persisted); err != nil {
+				return "", fmt.Errorf("chart cache: %w", err)
+			}
+			path = persisted
+		}
+		c.remember(key, path, func(s *ChartCacheStats) { s.Downloads++ })
+		return path, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if !ran {
+		// Waited on another caller's download.
+		c.mu.Lock()
+		c.stats.Shared++
+		c.mu.Unlock()
+	}
+	return v.(string), nil
+}
+
+func (c *ChartCache) remember(key, path string, count func(*ChartCacheStats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paths[key] = path
+	count(&c.stats)
+}
+
+var chartCacheNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// persistentPath is where an archive is kept across runs. Only exact versions are persisted: a
+// range or an empty version may resolve to a newer chart next time.
+func (c *ChartCache) persistentPath(key, ref, version string) string {
+	if c.dir == "" {
+		return ""
+	}
+	if _, err := semver.StrictNewVersion(strings.TrimPrefix(strings.TrimSpace(version), "v")); err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	name := chartCacheNameUnsafe.ReplaceAllString(filepath.Base(ref), "_")
+	return filepath.Join(c.dir, fmt.Sprintf("%s-%s-%s.tgz", name, strings.TrimPrefix(version, "v"), hex.EncodeToString(sum[:])[:12]))
+}
+
+func copyFileAtomic(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory, expected a chart archive", src)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".chart-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, in); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
@@ -0,0 +1,84 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+func countingChartCache(t *testing.T, dir string, downloads *atomic.Int32) *ChartCache {
+	t.Helper()
+	src := t.TempDir()
+	c := NewChartCache(dir)
+	c.locate = func(opts action.ChartPathOptions, ref string, _ *cli.EnvSettings) (string, error) {
+		downloads.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		path := filepath.Join(src, filepath.Base(ref)+"-"+opts.Version+".tgz")
+		return path, os.WriteFile(path, []byte("chart "+ref), 0o644)
+	}
+	return c
+}
+
+func TestChartCacheDedupsConcurrentDownloads(t *testing.T) {
+	var downloads atomic.Int32
+	c := countingChartCache(t, "", &downloads)
+	opts := action.ChartPathOptions{Version: "1.2.3"}
+
+	var wg sync.WaitGroup
+	paths := make([]string, 20)
+	for i := range paths {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			path, err := c.Locate(opts, "oci://ghcr.io/acme/api", cli.New())
+			if err != nil {
+				t.Errorf("locate: %v", err)
+			}
+			paths[i] = path
+		}(i)
+	}
+	wg.Wait()
+	if n := downloads.Load(); n != 1 {
+		t.Fatalf("expected one download, got %d", n)
+	}
+	for _, p := range paths {
+		if p != paths[0] {
+			t.Fatalf("expected the same path for every caller, got %q", paths)
+		}
+	}
+	if st := c.Stats(); st.Downloads != 1 || st.Shared != 19 {
+		t.Fatalf("stats=%+v", st)
+	}
+	if _, err := c.Locate(action.ChartPathOptions{Version: "1.2.4"}, "oci://ghcr.io/acme/api", cli.New()); err != nil || downloads.Load() != 2 {
+		t.Fatalf("expected a second version to download separately, err=%v downloads=%d", err, downloads.Load())
+	}
+}
+
+func TestChartCachePersistsExactVersions(t *testing.T) {
+	dir := t.TempDir()
+	var downloads atomic.Int32
+	first, err := countingChartCache(t, dir, &downloads).Locate(action.ChartPathOptions{Version: "1.2.3"}, "acme/api", cli.New())
+	if err != nil {
+		t.Fatalf("locate: %v", err)
+	}
+	if filepath.Dir(first) != dir {
+		t.Fatalf("expected archive in the cache dir, got %s", first)
+	}
+
+	next := countingChartCache(t, dir, &downloads)
+	again, err := next.Locate(action.ChartPathOptions{Version: "1.2.3"}, "acme/api", cli.New())
+	if err != nil || again != first || downloads.Load() != 1 || next.Stats().Persistent != 1 {
+		t.Fatalf("expected reuse from the cache dir: path=%s err=%v downloads=%d", again, err, downloads.Load())
+	}
+
+	ranged, err := next.Locate(action.ChartPathOptions{Version: "^1.2.0"}, "acme/api", cli.New())
+	if err != nil || filepath.Dir(ranged) == dir || downloads.Load() != 2 {
+		t.Fatalf("expected a version range to bypass the cache dir: path=%s err=%v", ranged, err)
+	}
+}
@@ -0,0 +1,269 @@
+// File: internal/deploy/chart_tests.go
+// Brief: Internal deploy package implementation for 'chart tests'.
+
+// chart_tests.go runs a release's helm.sh/hook: test hooks (the equivalent of helm test) and
+// streams the test pods' logs while they run (--run-chart-tests / ktl test).
+package deploy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrChartTestsFailed reports that at least one chart test ran and failed.
+var ErrChartTestsFailed = errors.New("chart tests failed")
+
+// ChartTestOptions configures RunChartTests.
+type ChartTestOptions struct {
+	Release   string
+	Namespace string
+	Timeout   time.Duration
+	// Filter limits the run to the named tests; names prefixed with ! are skipped instead.
+	Filter []string
+	// Logs, when set, receives every test pod log line as it is written, prefixed with the pod name.
+	Logs io.Writer
+}
+
+// ChartTestResult is the outcome of one test hook.
+type ChartTestResult struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Phase    string `json:"phase"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// ChartTestReport summarizes a chart test run for the console and the deploy summary.
+type ChartTestReport struct {
+	Release   string            `json:"release"`
+	Namespace string            `json:"namespace"`
+	Passed    int               `json:"passed"`
+	Failed    int               `json:"failed"`
+	Tests     []ChartTestResult `json:"tests,omitempty"`
+	Duration  string            `json:"duration,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// OK reports whether every test that ran succeeded.
+func (r *ChartTestReport) OK() bool {
+	return r != nil && r.Failed == 0 && r.Error == ""
+}
+
+// RunChartTests runs the test hooks of the release's latest revision and waits for them. The
+// report is returned even when tests fail; the error then wraps ErrChartTestsFailed.
+func RunChartTests(ctx context.Context, actionCfg *action.Configuration, clientset kubernetes.Interface, opts ChartTestOptions) (*ChartTestReport, error) {
+	report := &ChartTestReport{Release: opts.Release, Namespace: opts.Namespace}
+	include, exclude := splitChartTestFilter(opts.Filter)
+
+	current, err := actionCfg.Releases.Last(opts.Release)
+	if err != nil {
+		return nil, fmt.Errorf("release %s: %w", opts.Release, err)
+	}
+	var pods []string
+	for _, h := range chartTestHooks(current, include, exclude) {
+		if h.Kind == "Pod" {
+			pods = append(pods, h.Name)
+		}
+	}
+
+	tester := action.NewReleaseTesting(actionCfg)
+	tester.Namespace = opts.Namespace
+	tester.Timeout = opts.Timeout
+	if len(include) > 0 {
+		tester.Filters[action.IncludeNameFilter] = include
+	}
+	if len(exclude) > 0 {
+		tester.Filters[action.ExcludeNameFilter] = exclude
+	}
+
+	started := time.Now()
+	// helm deletes the previous run's pod before creating a new one. Remember the UIDs of pods left
+	// from earlier runs so followers skip them; comparing creation times against the local clock
+	// would drop this run's logs whenever the client clock runs ahead of the API server.
+	stale := map[string]types.UID{}
+	if opts.Logs != nil && clientset != nil {
+		for _, pod := range pods {
+			if p, err := clientset.CoreV1().Pods(opts.Namespace).Get(ctx, pod, metav1.GetOptions{}); err == nil {
+				stale[pod] = p.UID
+			}
+		}
+	}
+	// Followers wait for their pod until the run ends, then get a moment to drain the lines of
+	// pods that just finished.
+	waitCtx, stopWaiting := context.WithCancel(ctx)
+	defer stopWaiting()
+	streamCtx, stopStreams := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	if opts.Logs != nil && clientset != nil {
+		out := &lockedLineWriter{w: opts.Logs}
+		for _, pod := range pods {
+			wg.Add(1)
+			go func(pod string) {
+				defer wg.Done()
+				streamChartTestLogs(waitCtx, streamCtx, clientset, opts.Namespace, pod, stale[pod], out)
+			}(pod)
+		}
+	}
+	rel, runErr := tester.Run(opts.Release)
+	stopWaiting()
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(2 * time.Second):
+	}
+	stopStreams()
+	wg.Wait()
+	report.Duration = time.Since(started).Round(time.Millisecond).String()
+
+	if rel == nil {
+		rel = current
+	}
+	for _, h := range chartTestHooks(rel, include, exclude) {
+		res := ChartTestResult{Name: h.Name, Kind: h.Kind, Phase: string(release.HookPhaseUnknown)}
+		if h.LastRun.Phase != "" {
+			res.Phase = string(h.LastRun.Phase)
+		}
+		if !h.LastRun.StartedAt.IsZero() && !h.LastRun.CompletedAt.IsZero() {
+			res.Duration = h.LastRun.CompletedAt.Time.Sub(h.LastRun.StartedAt.Time).Round(time.Millisecond).String()
+		}
+		switch h.LastRun.Phase {
+		case release.HookPhaseSucceeded:
+			report.Passed++
+		case release.HookPhaseFailed:
+			report.Failed++
+		}
+		report.Tests = append(report.Tests, res)
+	}
+	if runErr != nil {
+		report.Error = runErr.Error()
+	}
+	switch {
+	case report.Failed > 0 && runErr != nil:
+		return report, fmt.Errorf("%w: %v", ErrChartTestsFailed, runErr)
+	case report.Failed > 0:
+		return report, fmt.Errorf("%w: %d of %d failed", ErrChartTestsFailed, report.Failed, len(report.Tests))
+	case runErr != nil:
+		// Nothing failed, so the tests could not run (cluster unreachable, timeout before start...).
+		return report, fmt.Errorf("run chart tests: %w", runErr)
+	}
+	return report, nil
+}
+
+func splitChartTestFilter(filter []string) (include, exclude []string) {
+	for _, f := range filter {
+		f = strings.TrimSpace(f)
+		switch {
+		case f == "" || f == "!":
+		case strings.HasPrefix(f, "!"):
+			exclude = append(exclude, strings.TrimPrefix(f, "!"))
+		default:
+			include = append(include, f)
+		}
+	}
+	return include, exclude
+}
+
+// chartTestHooks returns the release's test hooks that the filter selects, in execution order.
+func chartTestHooks(rel *release.Release, include, exclude []string) []*release.Hook {
+	if rel == nil {
+		return nil
+	}
+	var out []*release.Hook
+	for _, h := range rel.Hooks {
+		if h == nil || !hookHasEvent(h, release.HookTest) {
+			continue
+		}
+		if containsString(exclude, h.Name) || (len(include) > 0 && !containsString(include, h.Name)) {
+			continue
+		}
+		out = append(out, h)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Weight != out[j].Weight {
+			return out[i].Weight < out[j].Weight
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func hookHasEvent(h *release.Hook, event release.HookEvent) bool {
+	for _, e := range h.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// streamChartTestLogs waits (until waitCtx ends) for the test pod created by this run to start and
+// follows its logs until the pod finishes or streamCtx is cancelled. staleUID is the UID of the pod
+// left by the previous run, if any.
+func streamChartTestLogs(waitCtx, streamCtx context.Context, clientset kubernetes.Interface, namespace, pod string, staleUID types.UID, out *lockedLineWriter) {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for waiting := true; ; {
+		p, err := clientset.CoreV1().Pods(namespace).Get(streamCtx, pod, metav1.GetOptions{})
+		if err == nil && (staleUID == "" || p.UID != staleUID) && p.Status.Phase != corev1.PodPending {
+			break
+		}
+		if !waiting {
+			return
+		}
+		select {
+		case <-waitCtx.Done():
+			// One last look: the pod may have finished between two polls.
+			waiting = false
+		case <-ticker.C:
+		}
+	}
+	stream, err := clientset.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{Follow: true}).Stream(streamCtx)
+	if err != nil {
+		out.Line(pod, fmt.Sprintf("(logs unavailable: %v)", err))
+		return
+	}
+	defer stream.Close()
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		out.Line(pod, scanner.Text())
+	}
+}
+
+// lockedLineWriter serializes prefixed lines from concurrent log followers.
+type lockedLineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedLineWriter) Line(pod, line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.w, "[%s] %s\n", pod, line)
+}
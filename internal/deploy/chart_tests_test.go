@@ -0,0 +1,114 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func chartTestConfig(t *testing.T, kube interface{}) *action.Configuration {
+	t.Helper()
+	mem := driver.NewMemory()
+	mem.SetNamespace("prod")
+	cfg := &action.Configuration{Releases: storage.Init(mem), Log: func(string, ...interface{}) {}}
+	switch k := kube.(type) {
+	case *kubefake.PrintingKubeClient:
+		cfg.KubeClient = k
+	case *kubefake.FailingKubeClient:
+		cfg.KubeClient = k
+	}
+	testPod := func(name string, weight int) *release.Hook {
+		return &release.Hook{
+			Name:     name,
+			Kind:     "Pod",
+			Path:     "templates/tests/" + name + ".yaml",
+			Manifest: "apiVersion: v1\nkind: Pod\nmetadata:\n  name: " + name + "\n",
+			Events:   []release.HookEvent{release.HookTest},
+			Weight:   weight,
+		}
+	}
+	rel := &release.Release{
+		Name:      "shop",
+		Namespace: "prod",
+		Version:   1,
+		Info:      &release.Info{Status: release.StatusDeployed},
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "shop", Version: "1.0.0"}},
+		Hooks: []*release.Hook{
+			testPod("shop-test-db", 1),
+			testPod("shop-test-http", 0),
+			{Name: "shop-migrate", Kind: "Job", Events: []release.HookEvent{release.HookPreUpgrade}, Manifest: "kind: Job\n"},
+		},
+	}
+	if err := cfg.Releases.Create(rel); err != nil {
+		t.Fatalf("store release: %v", err)
+	}
+	return cfg
+}
+
+func TestRunChartTests_ReportsAndStreamsLogs(t *testing.T) {
+	cfg := chartTestConfig(t, &kubefake.PrintingKubeClient{Out: io.Discard})
+	// The previous run's pod is still there when the run starts; helm replaces it with a new pod
+	// whose creation time, by the API server's clock, is well before the client's.
+	old := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop-test-http", Namespace: "prod", UID: "run-1", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour))},
+		Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+	}
+	clientset := k8sfake.NewSimpleClientset(old)
+	var gets int
+	clientset.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		get, ok := action.(k8stesting.GetAction)
+		if !ok || get.GetSubresource() != "" || get.GetName() != "shop-test-http" {
+			return false, nil, nil
+		}
+		gets++
+		if gets == 1 {
+			return true, old.DeepCopy(), nil
+		}
+		fresh := old.DeepCopy()
+		fresh.UID = "run-2"
+		return true, fresh, nil
+	})
+	var logs bytes.Buffer
+	report, err := RunChartTests(context.Background(), cfg, clientset, ChartTestOptions{Release: "shop", Namespace: "prod", Timeout: time.Minute, Logs: &logs})
+	if err != nil {
+		t.Fatalf("RunChartTests: %v", err)
+	}
+	if !report.OK() || report.Passed != 2 || len(report.Tests) != 2 || report.Tests[0].Name != "shop-test-http" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if !strings.Contains(logs.String(), "[shop-test-http] fake logs") {
+		t.Fatalf("expected streamed test pod logs, got %q", logs.String())
+	}
+
+	report, err = RunChartTests(context.Background(), cfg, nil, ChartTestOptions{Release: "shop", Namespace: "prod", Timeout: time.Minute, Filter: []string{"!shop-test-db"}})
+	if err != nil || len(report.Tests) != 1 || report.Tests[0].Name != "shop-test-http" {
+		t.Fatalf("expected the excluded test to be skipped, got %+v, %v", report, err)
+	}
+}
+
+func TestRunChartTests_FailureWrapsErrChartTestsFailed(t *testing.T) {
+	cfg := chartTestConfig(t, &kubefake.FailingKubeClient{PrintingKubeClient: kubefake.PrintingKubeClient{Out: io.Discard}, WatchUntilReadyError: errors.New("pod shop-test-http failed")})
+	report, err := RunChartTests(context.Background(), cfg, nil, ChartTestOptions{Release: "shop", Namespace: "prod", Timeout: time.Minute})
+	if !errors.Is(err, ErrChartTestsFailed) {
+		t.Fatalf("expected ErrChartTestsFailed, got %v", err)
+	}
+	if report == nil || report.OK() || report.Failed == 0 || !strings.Contains(report.Error, "shop-test-http failed") {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
@@ -0,0 +1,310 @@
+// File: internal/deploy/conflicts.go
+// Brief: Internal deploy package implementation for 'conflicts'.
+
+// conflicts.go finds fields that were edited in the cluster since the last apply and that the
+// next apply would overwrite, and maps them back to the values keys that render them.
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/distribution/reference"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/strvals"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// LiveConflict is one field whose live value was edited outside Helm and which the new render
+// sets to something else.
+type LiveConflict struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Field is the dotted path of the field; list items with a name are addressed as [name].
+	Field       string `json:"field"`
+	LastApplied any    `json:"lastApplied"`
+	Live        any    `json:"live"`
+	Desired     any    `json:"desired"`
+	// ValuesPath and ValuesLive, when set, are the values key that renders the field and the
+	// value that makes the chart render the live value instead.
+	ValuesPath string `json:"valuesPath,omitempty"`
+	ValuesLive any    `json:"valuesLive,omitempty"`
+}
+
+// Resource formats the conflicting object as Kind/name.
+func (c LiveConflict) Resource() string {
+	return c.Kind + "/" + c.Name
+}
+
+// SetArg returns the --set argument that keeps the live value, or "" when no values key
+// renders the field.
+func (c LiveConflict) SetArg() string {
+	if c.ValuesPath == "" {
+		return ""
+	}
+	return c.ValuesPath + "=" + fmt.Sprint(c.ValuesLive)
+}
+
+// SetString reports whether SetArg must be passed as --set-string, so a string value such as
+// a "1.25" tag is not parsed as a number.
+func (c LiveConflict) SetString() bool {
+	_, ok := c.ValuesLive.(string)
+	return ok
+}
+
+// FindLiveConflicts compares the last applied manifest, the live objects, and the desired
+// manifest field by field. A field conflicts when it was rendered by the last apply, its live
+// value differs from what was applied, and the desired manifest sets a value other than the
+// live one. Hooks and objects not owned by the release are skipped.
+func FindLiveConflicts(ctx context.Context, releaseName, lastApplied, desired string, get DriftLiveGetter) ([]LiveConflict, error) {
+	if strings.TrimSpace(lastApplied) == "" || get == nil {
+		return nil, nil
+	}
+	desiredObjs := map[string]*unstructured.Unstructured{}
+	for _, doc := range splitManifestDocs(desired) {
+		u, target, ok := parseManifestDoc(doc)
+		if !ok {
+			continue
+		}
+		desiredObjs[conflictKey(target)] = u
+	}
+	var out []LiveConflict
+	for _, doc := range splitManifestDocs(lastApplied) {
+		base, target, ok := parseManifestDoc(doc)
+		if !ok || isHookResource(base) || !hasHelmOwnership(releaseName, base) {
+			continue
+		}
+		want, ok := desiredObjs[conflictKey(target)]
+		if !ok {
+			continue
+		}
+		live, err := get(ctx, target)
+		if err != nil {
+			return nil, fmt.Errorf("get %s/%s: %w", target.Kind, target.Name, err)
+		}
+		if live == nil || !hasHelmOwnership(releaseName, live) {
+			continue
+		}
+		baseFields := flattenFields(normalizeForDrift(base).Object)
+		liveFields := flattenFields(normalizeForDrift(live).Object)
+		wantFields := flattenFields(normalizeForDrift(want).Object)
+		paths := make([]string, 0, len(baseFields))
+		for path := range baseFields {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			applied := baseFields[path]
+			liveVal, inLive := liveFields[path]
+			wantVal, inWant := wantFields[path]
+			if !inLive || !inWant || fieldEqual(applied, liveVal) || fieldEqual(wantVal, liveVal) {
+				continue
+			}
+			out = append(out, LiveConflict{
+				Kind:        target.Kind,
+				Namespace:   pickNamespace(target.Namespace, live.GetNamespace()),
+				Name:        target.Name,
+				Field:       path,
+				LastApplied: applied,
+				Live:        liveVal,
+				Desired:     wantVal,
+			})
+		}
+	}
+	return out, nil
+}
+
+func conflictKey(t resourceTarget) string {
+	return t.Group + "/" + t.Kind + "/" + t.Namespace + "/" + t.Name
+}
+
+// flattenFields maps every scalar (and every list of scalars) in obj to its dotted path. List
+// items that carry a name are keyed by it, so reordering a container list is not a conflict.
+func flattenFields(obj map[string]any) map[string]any {
+	out := map[string]any{}
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		switch t := v.(type) {
+		case map[string]any:
+			for k, child := range t {
+				p := k
+				if prefix != "" {
+					p = prefix + "." + k
+				}
+				walk(p, child)
+			}
+		case []any:
+			if !listOfMaps(t) {
+				out[prefix] = t
+				return
+			}
+			for i, item := range t {
+				m := item.(map[string]any)
+				if name, ok := m["name"].(string); ok && name != "" {
+					walk(fmt.Sprintf("%s[%s]", prefix, name), m)
+				} else {
+					walk(fmt.Sprintf("%s[%d]", prefix, i), m)
+				}
+			}
+		default:
+			out[prefix] = t
+		}
+	}
+	walk("", obj)
+	return out
+}
+
+func listOfMaps(list []any) bool {
+	if len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		if _, ok := item.(map[string]any); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldEqual compares field values, treating numbers of different Go types as equal when they
+// print the same (YAML renders decode as int64, live objects as int64 or float64).
+func fieldEqual(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	return isScalar(a) && isScalar(b) && fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func isScalar(v any) bool {
+	switch v.(type) {
+	case string, bool, int, int32, int64, float32, float64:
+		return true
+	}
+	return false
+}
+
+// SuggestConflictValues fills ValuesPath and ValuesLive for every conflict whose desired value
+// comes from exactly one values key. values are the coalesced values of the render. Image fields
+// also match a values key holding just the tag.
+func SuggestConflictValues(conflicts []LiveConflict, values map[string]any) {
+	leaves := scalarValueLeaves(values)
+	for i := range conflicts {
+		c := &conflicts[i]
+		if !isScalar(c.Desired) || !isScalar(c.Live) {
+			continue
+		}
+		field := lastFieldSegment(c.Field)
+		want, keep := c.Desired, c.Live
+		if field == "image" {
+			if wantTag, liveTag, ok := imageTagChange(fmt.Sprint(c.Desired), fmt.Sprint(c.Live)); ok {
+				want, keep = wantTag, liveTag
+				field = "tag"
+			}
+		}
+		if path := matchValuesLeaf(leaves, field, want); path != "" {
+			c.ValuesPath = path
+			c.ValuesLive = keep
+		}
+	}
+}
+
+// ConflictValuesPatch returns the values that keep the live value of every conflict in keep,
+// as a nested map ready to be written as a values file.
+func ConflictValuesPatch(keep []LiveConflict) (map[string]any, error) {
+	out := map[string]any{}
+	for _, c := range keep {
+		arg := c.SetArg()
+		if arg == "" {
+			continue
+		}
+		parse := strvals.ParseInto
+		if c.SetString() {
+			parse = strvals.ParseIntoString
+		}
+		if err := parse(arg, out); err != nil {
+			return nil, fmt.Errorf("%s: %w", arg, err)
+		}
+	}
+	return out, nil
+}
+
+func scalarValueLeaves(values map[string]any) map[string]any {
+	out := map[string]any{}
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		switch t := v.(type) {
+		case map[string]any:
+			for k, child := range t {
+				p := k
+				if prefix != "" {
+					p = prefix + "." + k
+				}
+				walk(p, child)
+			}
+		case chartutil.Values:
+			walk(prefix, map[string]any(t))
+		default:
+			if isScalar(t) {
+				out[prefix] = t
+			}
+		}
+	}
+	walk("", values)
+	return out
+}
+
+// matchValuesLeaf returns the values path whose value equals want. When several keys hold the
+// value, the one whose name shares a stem with field wins; ambiguous matches return "".
+func matchValuesLeaf(leaves map[string]any, field string, want any) string {
+	var all, named []string
+	stem := strings.TrimSuffix(strings.ToLower(field), "s")
+	for path, v := range leaves {
+		if !fieldEqual(v, want) {
+			continue
+		}
+		all = append(all, path)
+		key := strings.ToLower(path[strings.LastIndex(path, ".")+1:])
+		if stem != "" && (strings.Contains(key, stem) || strings.Contains(stem, key)) {
+			named = append(named, path)
+		}
+	}
+	switch {
+	case len(named) == 1:
+		return named[0]
+	case len(named) == 0 && len(all) == 1:
+		return all[0]
+	}
+	return ""
+}
+
+func lastFieldSegment(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		path = path[i+1:]
+	}
+	if i := strings.Index(path, "["); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// imageTagChange reports the tags of two references to the same repository with different tags.
+func imageTagChange(desired, live string) (string, string, bool) {
+	d, err := reference.ParseNormalizedNamed(desired)
+	if err != nil {
+		return "", "", false
+	}
+	l, err := reference.ParseNormalizedNamed(live)
+	if err != nil || d.Name() != l.Name() {
+		return "", "", false
+	}
+	dt, ok1 := d.(reference.Tagged)
+	lt, ok2 := l.(reference.Tagged)
+	if !ok1 || !ok2 {
+		return "", "", false
+	}
+	return dt.Tag(), lt.Tag(), true
+}
@@ -0,0 +1,98 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const conflictDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: prod
+  labels:
+    app.kubernetes.io/managed-by: Helm
+  annotations:
+    meta.helm.sh/release-name: api
+spec:
+  replicas: %s
+  template:
+    spec:
+      containers:
+        - name: api
+          image: ghcr.io/acme/api:%s
+          env:
+            - name: LOG_LEVEL
+              value: %s
+`
+
+func conflictDoc(replicas, tag, logLevel string) string {
+	return fmt.Sprintf(conflictDeployment, replicas, tag, logLevel)
+}
+
+func TestFindLiveConflictsAndSuggestValues(t *testing.T) {
+	lastApplied := conflictDoc("2", "v1", "info")
+	live := conflictDoc("5", "v1-hotfix", "info")
+	desired := conflictDoc("2", "v2", "debug")
+
+	get := func(_ context.Context, target resourceTarget) (*unstructured.Unstructured, error) {
+		var obj map[string]any
+		if err := yaml.Unmarshal([]byte(live), &obj); err != nil {
+			return nil, err
+		}
+		return &unstructured.Unstructured{Object: obj}, nil
+	}
+	conflicts, err := FindLiveConflicts(context.Background(), "api", lastApplied, desired, get)
+	if err != nil {
+		t.Fatalf("find conflicts: %v", err)
+	}
+	var fields []string
+	for _, c := range conflicts {
+		fields = append(fields, c.Field)
+	}
+	// LOG_LEVEL changes only in the chart, so it is not a conflict.
+	want := []string{"spec.replicas", "spec.template.spec.containers[api].image"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("fields = %v", fields)
+	}
+
+	values := map[string]any{
+		"replicaCount": float64(2),
+		"minAvailable": float64(2),
+		"image":        map[string]any{"repository": "ghcr.io/acme/api", "tag": "v2"},
+		"logLevel":     "debug",
+	}
+	SuggestConflictValues(conflicts, values)
+	if got := conflicts[0].SetArg(); got != "replicaCount=5" {
+		t.Fatalf("replicas set = %q", got)
+	}
+	if got := conflicts[1].SetArg(); got != "image.tag=v1-hotfix" {
+		t.Fatalf("image set = %q", got)
+	}
+	patch, err := ConflictValuesPatch(conflicts)
+	if err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	wantPatch := map[string]any{"replicaCount": int64(5), "image": map[string]any{"tag": "v1-hotfix"}}
+	if !reflect.DeepEqual(patch, wantPatch) {
+		t.Fatalf("patch = %#v", patch)
+	}
+}
+
+func TestSuggestConflictValuesSkipsAmbiguousKeys(t *testing.T) {
+	conflicts := []LiveConflict{{Kind: "Deployment", Name: "api", Field: "spec.template.spec.containers[api].env[TIMEOUT].value", Desired: "30", Live: "60"}}
+	SuggestConflictValues(conflicts, map[string]any{"timeout": "30", "retries": "30", "grace": "30"})
+	if conflicts[0].ValuesPath != "" {
+		t.Fatalf("expected no suggestion for an ambiguous value, got %s", conflicts[0].ValuesPath)
+	}
+	conflicts[0].Field = "spec.template.spec.containers[api].env[TIMEOUT].timeout"
+	SuggestConflictValues(conflicts, map[string]any{"timeout": "30", "retries": "30"})
+	if conflicts[0].SetArg() != "timeout=60" {
+		t.Fatalf("set = %q", conflicts[0].SetArg())
+	}
+}
@@ -19,14 +19,17 @@ type DriftOptions struct {
 	IgnoreMissing        bool
 	MaxConcurrency       int
 	PerObjectTimeout     time.Duration
+	// DesiredFieldsOnly ignores live fields the desired object does not set (server defaults,
+	// fields filled in by controllers), so only edits to rendered fields count as drift.
+	DesiredFieldsOnly bool
 }
 
 type DriftItem struct {
-	Kind      string
-	Namespace string
-	Name      string
-	Reason    string
-	Diff      string
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+	Diff      string `json:"diff,omitempty"`
 }
 
 type DriftReport struct {
@@ -132,6 +135,9 @@ func CheckReleaseDriftWithOptions(ctx context.Context, releaseName string, manif
 
 			baseNorm := normalizeForDrift(j.base)
 			liveNorm := normalizeForDrift(live)
+			if opts.DesiredFieldsOnly {
+				liveNorm.Object = pruneToDesired(liveNorm.Object, baseNorm.Object).(map[string]interface{})
+			}
 
 			eq, diff, derr := diffUnstructured(baseNorm, liveNorm)
 			if derr != nil {
@@ -308,6 +314,37 @@ func removeServiceNodePorts(svc *unstructured.Unstructured) {
 	}
 }
 
+// pruneToDesired drops map keys from live that desired does not have. Lists of equal length are
+// pruned element-wise; other values are kept as they are.
+func pruneToDesired(live, desired interface{}) interface{} {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		out := make(map[string]interface{}, len(d))
+		for k, dv := range d {
+			if lv, ok := l[k]; ok {
+				out[k] = pruneToDesired(lv, dv)
+			}
+		}
+		return out
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return live
+		}
+		out := make([]interface{}, len(l))
+		for i := range l {
+			out[i] = pruneToDesired(l[i], d[i])
+		}
+		return out
+	default:
+		return live
+	}
+}
+
 func diffUnstructured(expected *unstructured.Unstructured, actual *unstructured.Unstructured) (bool, string, error) {
 	expJSON, err := json.MarshalIndent(expected.Object, "", "  ")
 	if err != nil {
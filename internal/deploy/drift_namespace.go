@@ -0,0 +1,166 @@
+// File: internal/deploy/drift_namespace.go
+// Brief: Internal deploy package implementation for 'drift namespace'.
+
+// drift_namespace.go re-renders every release in a namespace from its stored chart and values
+// and diffs the result against live objects, so manual edits show up across a whole namespace.
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/postrender"
+	"helm.sh/helm/v3/pkg/release"
+	"sigs.k8s.io/yaml"
+)
+
+// NamespaceDriftOptions configures CheckNamespaceDrift.
+type NamespaceDriftOptions struct {
+	Namespace string
+	// Render produces the desired manifest of a stored release. Nil uses RenderStoredRelease.
+	Render func(ctx context.Context, rel *release.Release) (string, error)
+	Get    DriftLiveGetter
+	Drift  DriftOptions
+}
+
+// ReleaseDriftResult is the drift of one release. Error is set when the release could not be
+// rendered or compared; Items is then empty.
+type ReleaseDriftResult struct {
+	Release   string      `json:"release"`
+	Namespace string      `json:"namespace"`
+	Revision  int         `json:"revision"`
+	Chart     string      `json:"chart,omitempty"`
+	Items     []DriftItem `json:"items,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// NamespaceDriftReport is the consolidated drift of all deployed releases in a namespace.
+type NamespaceDriftReport struct {
+	Namespace   string               `json:"namespace"`
+	GeneratedAt time.Time            `json:"generatedAt"`
+	Releases    []ReleaseDriftResult `json:"releases"`
+}
+
+// Drifted returns the number of releases with drift or errors.
+func (r NamespaceDriftReport) Drifted() int {
+	n := 0
+	for _, rel := range r.Releases {
+		if len(rel.Items) > 0 || rel.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// CheckNamespaceDrift compares every deployed release in actionCfg's namespace with the live
+// cluster. Per-release failures are recorded in the report rather than aborting the scan.
+func CheckNamespaceDrift(ctx context.Context, actionCfg *action.Configuration, opts NamespaceDriftOptions) (NamespaceDriftReport, error) {
+	report := NamespaceDriftReport{Namespace: opts.Namespace, GeneratedAt: time.Now().UTC()}
+	if actionCfg == nil {
+		return report, fmt.Errorf("helm action config is required")
+	}
+	render := opts.Render
+	if render == nil {
+		render = func(ctx context.Context, rel *release.Release) (string, error) {
+			return RenderStoredRelease(ctx, actionCfg, rel, nil)
+		}
+	}
+	driftOpts := opts.Drift
+	driftOpts.RequireHelmOwnership = true
+	driftOpts.DesiredFieldsOnly = true
+
+	list := action.NewList(actionCfg)
+	list.Deployed = true
+	list.SetStateMask()
+	releases, err := list.Run()
+	if err != nil {
+		return report, fmt.Errorf("list releases: %w", err)
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].Name < releases[j].Name })
+	for _, rel := range releases {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		result := ReleaseDriftResult{Release: rel.Name, Namespace: rel.Namespace, Revision: rel.Version}
+		if rel.Chart != nil && rel.Chart.Metadata != nil {
+			result.Chart = strings.TrimSuffix(rel.Chart.Metadata.Name+"-"+rel.Chart.Metadata.Version, "-")
+		}
+		manifest, err := render(ctx, rel)
+		if err != nil {
+			result.Error = fmt.Sprintf("render: %v", err)
+			report.Releases = append(report.Releases, result)
+			continue
+		}
+		manifest, err = stampHelmOwnership(manifest, rel.Name, rel.Namespace)
+		if err != nil {
+			result.Error = err.Error()
+			report.Releases = append(report.Releases, result)
+			continue
+		}
+		drift, err := CheckReleaseDriftWithOptions(ctx, rel.Name, manifest, opts.Get, driftOpts)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		sort.Slice(drift.Items, func(i, j int) bool {
+			a, b := drift.Items[i], drift.Items[j]
+			return a.Kind+"/"+a.Namespace+"/"+a.Name < b.Kind+"/"+b.Namespace+"/"+b.Name
+		})
+		result.Items = drift.Items
+		report.Releases = append(report.Releases, result)
+	}
+	return report, nil
+}
+
+// RenderStoredRelease renders rel's stored chart with its stored values, the way an upgrade with
+// unchanged inputs would, without touching the cluster. postRenderer may be nil.
+func RenderStoredRelease(ctx context.Context, actionCfg *action.Configuration, rel *release.Release, postRenderer postrender.PostRenderer) (string, error) {
+	if rel == nil || rel.Chart == nil {
+		return "", fmt.Errorf("release has no stored chart")
+	}
+	upgrade := action.NewUpgrade(actionCfg)
+	upgrade.Namespace = rel.Namespace
+	upgrade.DryRun = true
+	upgrade.DryRunOption = "server"
+	upgrade.PostRenderer = postRenderer
+	rendered, err := upgrade.RunWithContext(ctx, rel.Name, rel.Chart, rel.Config)
+	if err != nil {
+		return "", err
+	}
+	return rendered.Manifest, nil
+}
+
+// stampHelmOwnership adds the ownership metadata Helm sets when it applies objects, which a
+// dry-run render lacks, so rendered objects compare cleanly against live ones.
+func stampHelmOwnership(manifest, releaseName, namespace string) (string, error) {
+	var b strings.Builder
+	for _, doc := range splitManifestDocs(manifest) {
+		u, _, ok := parseManifestDoc(doc)
+		if !ok {
+			continue
+		}
+		labels := u.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels["app.kubernetes.io/managed-by"] = "Helm"
+		u.SetLabels(labels)
+		ann := u.GetAnnotations()
+		if ann == nil {
+			ann = map[string]string{}
+		}
+		ann["meta.helm.sh/release-name"] = releaseName
+		ann["meta.helm.sh/release-namespace"] = namespace
+		u.SetAnnotations(ann)
+		data, err := yaml.Marshal(u.Object)
+		if err != nil {
+			return "", fmt.Errorf("encode %s/%s: %w", u.GetKind(), u.GetName(), err)
+		}
+		b.WriteString("---\n")
+		b.Write(data)
+	}
+	return b.String(), nil
+}
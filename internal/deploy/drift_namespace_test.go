@@ -0,0 +1,77 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func TestCheckNamespaceDrift_ReportsEditedReleasesOnly(t *testing.T) {
+	mem := driver.NewMemory()
+	mem.SetNamespace("prod")
+	cfg := &action.Configuration{Releases: storage.Init(mem), KubeClient: &kubefake.PrintingKubeClient{Out: io.Discard}}
+	for _, name := range []string{"web", "api", "broken"} {
+		rel := &release.Release{
+			Name:      name,
+			Namespace: "prod",
+			Version:   1,
+			Info:      &release.Info{Status: release.StatusDeployed},
+			Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: name, Version: "1.0.0"}},
+			Manifest:  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n  namespace: prod\ndata:\n  replicas: \"2\"\n",
+		}
+		if err := cfg.Releases.Create(rel); err != nil {
+			t.Fatalf("store release: %v", err)
+		}
+	}
+
+	live := map[string]string{
+		// Edited by hand; the extra server-side annotation must not count as drift.
+		"web": "replicas: \"5\"",
+		"api": "replicas: \"2\"",
+	}
+	get := func(_ context.Context, target resourceTarget) (*unstructured.Unstructured, error) {
+		doc := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + target.Name + "\n  namespace: prod\n" +
+			"  labels:\n    app.kubernetes.io/managed-by: Helm\n" +
+			"  annotations:\n    meta.helm.sh/release-name: " + target.Name + "\n    meta.helm.sh/release-namespace: prod\n    example.com/touched-by: controller\n" +
+			"data:\n  " + live[target.Name] + "\n"
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, err
+		}
+		return &unstructured.Unstructured{Object: obj}, nil
+	}
+	render := func(_ context.Context, rel *release.Release) (string, error) {
+		if rel.Name == "broken" {
+			return "", errors.New("chart missing")
+		}
+		return rel.Manifest, nil
+	}
+
+	report, err := CheckNamespaceDrift(context.Background(), cfg, NamespaceDriftOptions{Namespace: "prod", Render: render, Get: get})
+	if err != nil {
+		t.Fatalf("CheckNamespaceDrift: %v", err)
+	}
+	if len(report.Releases) != 3 || report.Drifted() != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	api, broken, web := report.Releases[0], report.Releases[1], report.Releases[2]
+	if api.Release != "api" || len(api.Items) != 0 || api.Error != "" {
+		t.Fatalf("expected api in sync, got %+v", api)
+	}
+	if broken.Error != "render: chart missing" {
+		t.Fatalf("expected render error for broken, got %+v", broken)
+	}
+	if len(web.Items) != 1 || web.Items[0].Reason != "changed" || web.Chart != "web-1.0.0" {
+		t.Fatalf("expected web to drift, got %+v", web)
+	}
+}
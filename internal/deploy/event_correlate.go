@@ -0,0 +1,149 @@
+// File: internal/deploy/event_correlate.go
+// Brief: Internal deploy package implementation for 'event correlate'.
+
+// event_correlate.go folds repeating Kubernetes events into one counted entry, ties each event to
+// the release resource it concerns, and maps event types/reasons onto stream severities.
+package deploy
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kubekattle/ktl/internal/tailer"
+)
+
+// maxCorrelatedEvents bounds the de-duplication table for long-running streams.
+const maxCorrelatedEvents = 2048
+
+// failureEventReasons are Warning reasons that mean the rollout cannot make progress on its own.
+var failureEventReasons = map[string]struct{}{
+	"failed":                   {},
+	"backoff":                  {},
+	"crashloopbackoff":         {},
+	"errimagepull":             {},
+	"imagepullbackoff":         {},
+	"inspectfailed":            {},
+	"failedcreate":             {},
+	"failedmount":              {},
+	"failedattachvolume":       {},
+	"failedscheduling":         {},
+	"backofflimitexceeded":     {},
+	"deadlineexceeded":         {},
+	"failedcreatepodsandbox":   {},
+	"evicted":                  {},
+	"oomkilling":               {},
+	"progressdeadlineexceeded": {},
+}
+
+// ResourceID returns the identifier shared by deploy plans, resource snapshots, and correlated
+// events: "<namespace>|<kind>|<name>" in lower case, with "cluster" for cluster-scoped objects.
+func ResourceID(kind, namespace, name string) string {
+	ns := strings.TrimSpace(namespace)
+	if ns == "" {
+		ns = "cluster"
+	}
+	return fmt.Sprintf("%s|%s|%s", strings.ToLower(ns), strings.ToLower(strings.TrimSpace(kind)), strings.ToLower(strings.TrimSpace(name)))
+}
+
+// EventSeverity maps a Kubernetes event type and reason onto the stream levels (info, warn,
+// error) that consoles colour green/grey, yellow, and red.
+func EventSeverity(eventType, reason string) string {
+	if !strings.EqualFold(strings.TrimSpace(eventType), "warning") {
+		return "info"
+	}
+	if _, ok := failureEventReasons[strings.ToLower(strings.TrimSpace(reason))]; ok {
+		return "error"
+	}
+	return "warn"
+}
+
+type correlatedEvent struct {
+	count int
+	seq   uint64
+}
+
+// eventCorrelator counts repeats of the same event (same object, reason, and message).
+type eventCorrelator struct {
+	mu      sync.Mutex
+	seen    map[string]*correlatedEvent
+	counter uint64
+}
+
+func newEventCorrelator() *eventCorrelator {
+	return &eventCorrelator{seen: make(map[string]*correlatedEvent)}
+}
+
+// observe returns the payload for ev. Repeats keep the same Key and carry the running count, so
+// consumers replace the earlier entry instead of appending another line.
+func (c *eventCorrelator) observe(ev *tailer.EventInfo) *LogPayload {
+	resourceID := ResourceID(ev.Kind, ev.Namespace, ev.Name)
+	key := resourceID + "|" + strings.ToLower(strings.TrimSpace(ev.Reason)) + "|" + strings.TrimSpace(ev.Message)
+
+	c.mu.Lock()
+	c.counter++
+	entry, ok := c.seen[key]
+	if !ok {
+		if len(c.seen) >= maxCorrelatedEvents {
+			c.evictOldestLocked()
+		}
+		entry = &correlatedEvent{}
+		c.seen[key] = entry
+	}
+	entry.count++
+	entry.seq = c.counter
+	if int(ev.Count) > entry.count {
+		entry.count = int(ev.Count)
+	}
+	count := entry.count
+	c.mu.Unlock()
+
+	payload := &LogPayload{
+		Level:      EventSeverity(ev.Type, ev.Reason),
+		Message:    formatCorrelatedEvent(ev, count),
+		Source:     "event",
+		Namespace:  ev.Namespace,
+		Key:        key,
+		Count:      count,
+		Reason:     strings.TrimSpace(ev.Reason),
+		ResourceID: resourceID,
+	}
+	if strings.EqualFold(ev.Kind, "pod") {
+		payload.Pod = ev.Name
+	}
+	return payload
+}
+
+func (c *eventCorrelator) evictOldestLocked() {
+	var (
+		oldestKey string
+		oldestSeq uint64
+	)
+	for key, entry := range c.seen {
+		if oldestKey == "" || entry.seq < oldestSeq {
+			oldestKey, oldestSeq = key, entry.seq
+		}
+	}
+	delete(c.seen, oldestKey)
+}
+
+func formatCorrelatedEvent(ev *tailer.EventInfo, count int) string {
+	ns := strings.TrimSpace(ev.Namespace)
+	if ns == "" {
+		ns = "-"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s/%s", strings.TrimSpace(ev.Kind), ns, strings.TrimSpace(ev.Name))
+	if reason := strings.TrimSpace(ev.Reason); reason != "" {
+		b.WriteString(": ")
+		b.WriteString(reason)
+	}
+	if msg := strings.TrimSpace(ev.Message); msg != "" {
+		b.WriteString(": ")
+		b.WriteString(msg)
+	}
+	if count > 1 {
+		fmt.Fprintf(&b, " (x%d)", count)
+	}
+	return b.String()
+}
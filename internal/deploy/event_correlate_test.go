@@ -0,0 +1,70 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/tailer"
+)
+
+type recordingObserver struct {
+	events []StreamEvent
+}
+
+func (r *recordingObserver) HandleDeployEvent(evt StreamEvent) {
+	r.events = append(r.events, evt)
+}
+
+func TestObserveLogCorrelatesRepeatingEvents(t *testing.T) {
+	b := NewStreamBroadcaster("demo", "apps", "chart")
+	obs := &recordingObserver{}
+	b.AddObserver(obs)
+	obs.events = nil
+
+	backoff := &tailer.EventInfo{Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Kind: "Pod", Namespace: "apps", Name: "web-0"}
+	b.ObserveLog(tailer.LogRecord{Raw: "raw", Event: backoff})
+	b.ObserveLog(tailer.LogRecord{Raw: "raw", Event: backoff})
+	b.ObserveLog(tailer.LogRecord{Raw: "raw", Event: &tailer.EventInfo{Type: "Normal", Reason: "Pulled", Kind: "Pod", Namespace: "apps", Name: "web-0"}})
+
+	if len(obs.events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(obs.events))
+	}
+	first, second, pulled := obs.events[0].Log, obs.events[1].Log, obs.events[2].Log
+	if first.Key == "" || first.Key != second.Key {
+		t.Fatalf("expected repeats to share a key, got %q and %q", first.Key, second.Key)
+	}
+	if second.Count != 2 || second.Message != "Pod apps/web-0: BackOff: Back-off restarting failed container (x2)" {
+		t.Fatalf("unexpected repeat payload: %+v", second)
+	}
+	if second.Level != "error" || pulled.Level != "info" {
+		t.Fatalf("unexpected severities %q/%q", second.Level, pulled.Level)
+	}
+	if pulled.Key == first.Key {
+		t.Fatalf("distinct reasons must not share a key")
+	}
+	if want := "apps|pod|web-0"; first.ResourceID != want || first.Pod != "web-0" {
+		t.Fatalf("expected resource %q, got %+v", want, first)
+	}
+}
+
+func TestEventCorrelatorPrefersAPICount(t *testing.T) {
+	c := newEventCorrelator()
+	got := c.observe(&tailer.EventInfo{Type: "Warning", Reason: "FailedMount", Kind: "Pod", Name: "db-0", Count: 7})
+	if got.Count != 7 || got.ResourceID != "cluster|pod|db-0" {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+}
+
+func TestEventSeverity(t *testing.T) {
+	cases := map[[2]string]string{
+		{"Normal", "Scheduled"}:         "info",
+		{"Warning", "Unhealthy"}:        "warn",
+		{"Warning", "FailedScheduling"}: "error",
+		{"warning", "ImagePullBackOff"}: "error",
+		{"", "BackOff"}:                 "info",
+	}
+	for in, want := range cases {
+		if got := EventSeverity(in[0], in[1]); got != want {
+			t.Fatalf("EventSeverity(%q, %q) = %q, want %q", in[0], in[1], got, want)
+		}
+	}
+}
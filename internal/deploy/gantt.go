@@ -0,0 +1,199 @@
+// File: internal/deploy/gantt.go
+// Brief: Internal deploy package implementation for 'gantt'.
+
+// gantt.go turns recorded deploy stream events into a Gantt-style timeline of phases and
+// per-resource readiness, and renders it as a static SVG for reports.
+package deploy
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Gantt lanes.
+const (
+	GanttLanePhase    = "phase"
+	GanttLaneResource = "resource"
+)
+
+// GanttBar is one row of the deploy timeline. Resource bars run from the first status snapshot
+// that listed the object until it was first Ready (or Failed); bars still open at the end of the
+// recording end at the last event.
+type GanttBar struct {
+	Lane   string    `json:"lane"`
+	Name   string    `json:"name"`
+	Status string    `json:"status"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// Duration is End-Start.
+func (b GanttBar) Duration() time.Duration { return b.End.Sub(b.Start) }
+
+// BuildGantt derives timeline bars from stream events. Phases come first in start order,
+// then resources in start order.
+func BuildGantt(events []StreamEvent) []GanttBar {
+	type timed struct {
+		ts  time.Time
+		evt StreamEvent
+	}
+	ordered := make([]timed, 0, len(events))
+	for _, evt := range events {
+		ts, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(evt.Timestamp))
+		if err != nil {
+			continue
+		}
+		ordered = append(ordered, timed{ts: ts, evt: evt})
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].ts.Before(ordered[j].ts) })
+	if len(ordered) == 0 {
+		return nil
+	}
+	last := ordered[len(ordered)-1].ts
+
+	phases := map[string]*GanttBar{}
+	resources := map[string]*GanttBar{}
+	closed := map[string]bool{}
+	for _, t := range ordered {
+		if p := t.evt.Phase; p != nil && strings.TrimSpace(p.Name) != "" {
+			start, err := time.Parse(time.RFC3339Nano, p.StartedAt)
+			if err != nil {
+				continue
+			}
+			bar := phases[p.Name]
+			if bar == nil {
+				bar = &GanttBar{Lane: GanttLanePhase, Name: p.Name}
+				phases[p.Name] = bar
+			}
+			bar.Start = start
+			bar.Status = p.Status
+			bar.End = time.Time{}
+			if end, err := time.Parse(time.RFC3339Nano, p.CompletedAt); err == nil && p.CompletedAt != "" {
+				bar.End = end
+			}
+		}
+		for _, rs := range t.evt.Resources {
+			key := strings.Trim(rs.Kind+"/"+rs.Namespace+"/"+rs.Name, "/")
+			if closed[key] {
+				continue
+			}
+			bar := resources[key]
+			if bar == nil {
+				name := rs.Kind + "/" + rs.Name
+				if rs.Namespace != "" {
+					name = rs.Namespace + "/" + name
+				}
+				bar = &GanttBar{Lane: GanttLaneResource, Name: name, Start: t.ts}
+				resources[key] = bar
+			}
+			bar.Status = rs.Status
+			bar.End = t.ts
+			if rs.Status == "Ready" || rs.Status == "Failed" {
+				closed[key] = true
+			}
+		}
+	}
+
+	out := make([]GanttBar, 0, len(phases)+len(resources))
+	for _, group := range []map[string]*GanttBar{phases, resources} {
+		start := len(out)
+		for _, bar := range group {
+			b := *bar
+			if b.End.IsZero() || b.End.Before(b.Start) {
+				b.End = last
+				if b.Lane == GanttLanePhase && (b.Status == "" || b.Status == "running") {
+					b.Status = "running"
+				}
+			}
+			out = append(out, b)
+		}
+		rows := out[start:]
+		sort.Slice(rows, func(i, j int) bool {
+			if !rows[i].Start.Equal(rows[j].Start) {
+				return rows[i].Start.Before(rows[j].Start)
+			}
+			return rows[i].Name < rows[j].Name
+		})
+	}
+	return out
+}
+
+// RenderGanttSVG renders bars as a self-contained SVG. It returns "" when there are no bars.
+func RenderGanttSVG(bars []GanttBar) string {
+	if len(bars) == 0 {
+		return ""
+	}
+	const (
+		labelW = 260
+		chartW = 700
+		rowH   = 22
+		top    = 24
+	)
+	begin, end := bars[0].Start, bars[0].End
+	for _, b := range bars {
+		if b.Start.Before(begin) {
+			begin = b.Start
+		}
+		if b.End.After(end) {
+			end = b.End
+		}
+	}
+	span := end.Sub(begin)
+	if span <= 0 {
+		span = time.Second
+	}
+	x := func(t time.Time) float64 { return labelW + float64(t.Sub(begin))/float64(span)*chartW }
+	height := top + len(bars)*rowH + 8
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" class="ktl-gantt" width="%d" height="%d" viewBox="0 0 %d %d" font-family="-apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif" font-size="12">`, labelW+chartW+16, height, labelW+chartW+16, height)
+	for i := 0; i <= 4; i++ {
+		gx := labelW + float64(i)*chartW/4
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="rgba(15,23,42,0.12)"/>`, gx, top-6, gx, height-4)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" fill="rgba(15,23,42,0.6)" text-anchor="middle">+%s</text>`, gx, top-10, (span * time.Duration(i) / 4).Round(100*time.Millisecond))
+	}
+	for i, bar := range bars {
+		y := top + i*rowH
+		x0, x1 := x(bar.Start), x(bar.End)
+		if x1-x0 < 2 {
+			x1 = x0 + 2
+		}
+		label := bar.Name
+		if len(label) > 38 {
+			label = "…" + label[len(label)-37:]
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="#0f172a" text-anchor="end">%s</text>`, labelW-8, y+15, html.EscapeString(label))
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%d" rx="4" fill="%s"><title>%s · %s · %s</title></rect>`,
+			x0, y+4, x1-x0, rowH-8, ganttColor(bar), html.EscapeString(bar.Name), html.EscapeString(dashIfBlank(bar.Status)), bar.Duration().Round(100*time.Millisecond))
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+func ganttColor(bar GanttBar) string {
+	switch strings.ToLower(bar.Status) {
+	case "succeeded", "ready":
+		return "#22c55e"
+	case "failed":
+		return "#ef4444"
+	case "skipped":
+		return "#cbd5e1"
+	case "pending", "progressing", "running":
+		if bar.Lane == GanttLanePhase {
+			return "#2563eb"
+		}
+		return "#fbbf24"
+	default:
+		return "#94a3b8"
+	}
+}
+
+func dashIfBlank(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,52 @@
+package deploy
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildGantt(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ts := func(d time.Duration) string { return t0.Add(d).Format(time.RFC3339Nano) }
+	events := []StreamEvent{
+		{Kind: StreamEventPhase, Timestamp: ts(0), Phase: &PhasePayload{Name: PhaseRender, Status: "running", StartedAt: ts(0)}},
+		{Kind: StreamEventPhase, Timestamp: ts(time.Second), Phase: &PhasePayload{Name: PhaseRender, Status: "succeeded", StartedAt: ts(0), CompletedAt: ts(time.Second)}},
+		{Kind: StreamEventPhase, Timestamp: ts(time.Second), Phase: &PhasePayload{Name: PhaseWait, Status: "running", StartedAt: ts(time.Second)}},
+		{Kind: StreamEventResources, Timestamp: ts(2 * time.Second), Resources: []ResourceStatus{
+			{Kind: "Deployment", Namespace: "prod", Name: "web", Status: "Progressing"},
+			{Kind: "Job", Namespace: "prod", Name: "migrate", Status: "Pending"},
+		}},
+		{Kind: StreamEventResources, Timestamp: ts(5 * time.Second), Resources: []ResourceStatus{
+			{Kind: "Deployment", Namespace: "prod", Name: "web", Status: "Ready"},
+			{Kind: "Job", Namespace: "prod", Name: "migrate", Status: "Pending"},
+		}},
+		{Kind: StreamEventResources, Timestamp: ts(9 * time.Second), Resources: []ResourceStatus{
+			{Kind: "Deployment", Namespace: "prod", Name: "web", Status: "Progressing"},
+			{Kind: "Job", Namespace: "prod", Name: "migrate", Status: "Failed"},
+		}},
+	}
+
+	bars := BuildGantt(events)
+	var got []string
+	for _, b := range bars {
+		got = append(got, b.Lane+":"+b.Name+":"+b.Status+":"+b.Duration().String())
+	}
+	want := []string{
+		"phase:render:succeeded:1s",
+		"phase:wait:running:8s",
+		"resource:prod/Deployment/web:Ready:3s",
+		"resource:prod/Job/migrate:Failed:7s",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("bars:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	svg := RenderGanttSVG(bars)
+	if !strings.HasPrefix(svg, "<svg") || strings.Count(svg, "<rect") != 4 || !strings.Contains(svg, "prod/Job/migrate · Failed · 7s") {
+		t.Fatalf("unexpected svg: %s", svg)
+	}
+	if RenderGanttSVG(nil) != "" {
+		t.Fatalf("expected no svg without bars")
+	}
+}
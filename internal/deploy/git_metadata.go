@@ -0,0 +1,154 @@
+// File: internal/deploy/git_metadata.go
+// Brief: Internal deploy package implementation for 'git metadata'.
+
+// git_metadata.go detects the git checkout behind a chart so applies can record which commit they came from.
+package deploy
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	GitCommitLabel = "ktl.dev/git-commit"
+	GitDirtyLabel  = "ktl.dev/git-dirty"
+	GitBranchLabel = "ktl.dev/git-branch"
+)
+
+// GitMetadata describes the checkout a release was applied from.
+type GitMetadata struct {
+	Commit string `json:"commit"`
+	Dirty  bool   `json:"dirty,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Author string `json:"author,omitempty"`
+}
+
+// DetectGitMetadata returns the git metadata for the work tree containing path.
+// It returns nil when path is not a local directory/file inside a git work tree (for example a
+// repo/name or OCI chart reference) or when git is unavailable.
+func DetectGitMetadata(path string) *GitMetadata {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	dir := path
+	if !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+	if out, err := gitOutput(dir, "rev-parse", "--is-inside-work-tree"); err != nil || out != "true" {
+		return nil
+	}
+	commit, err := gitOutput(dir, "rev-parse", "HEAD")
+	if err != nil || commit == "" {
+		return nil
+	}
+	meta := &GitMetadata{Commit: commit}
+	if status, err := gitOutput(dir, "status", "--porcelain"); err == nil {
+		meta.Dirty = status != ""
+	}
+	if branch, err := gitOutput(dir, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && branch != "HEAD" {
+		meta.Branch = branch
+	}
+	if author, err := gitOutput(dir, "log", "-1", "--pretty=format:%an <%ae>"); err == nil {
+		meta.Author = author
+	}
+	return meta
+}
+
+// Clone returns a copy of m (nil-safe).
+func (m *GitMetadata) Clone() *GitMetadata {
+	if m == nil {
+		return nil
+	}
+	c := *m
+	return &c
+}
+
+// ReleaseLabels returns Helm release labels for m. Values are sanitized to valid Kubernetes label
+// values because the storage driver copies release labels onto its Secret/ConfigMap.
+func (m *GitMetadata) ReleaseLabels() map[string]string {
+	if m == nil || m.Commit == "" {
+		return nil
+	}
+	labels := map[string]string{
+		GitCommitLabel: sanitizeLabelValue(m.Commit),
+		GitDirtyLabel:  strconv.FormatBool(m.Dirty),
+	}
+	if branch := sanitizeLabelValue(m.Branch); branch != "" {
+		labels[GitBranchLabel] = branch
+	}
+	return labels
+}
+
+// Description renders m as a Helm release description (shown by `helm history`).
+func (m *GitMetadata) Description() string {
+	if m == nil || m.Commit == "" {
+		return ""
+	}
+	commit := m.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	var b strings.Builder
+	b.WriteString("Applied by ktl from git ")
+	b.WriteString(commit)
+	if m.Dirty {
+		b.WriteString(" (dirty)")
+	}
+	if m.Branch != "" {
+		b.WriteString(" on ")
+		b.WriteString(m.Branch)
+	}
+	if m.Author != "" {
+		b.WriteString(" by ")
+		b.WriteString(m.Author)
+	}
+	return b.String()
+}
+
+// Fields flattens m into string key/values for capture session metadata.
+func (m *GitMetadata) Fields() map[string]string {
+	if m == nil || m.Commit == "" {
+		return nil
+	}
+	fields := map[string]string{
+		"git.commit": m.Commit,
+		"git.dirty":  strconv.FormatBool(m.Dirty),
+	}
+	if m.Branch != "" {
+		fields["git.branch"] = m.Branch
+	}
+	if m.Author != "" {
+		fields["git.author"] = m.Author
+	}
+	return fields
+}
+
+var invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func sanitizeLabelValue(v string) string {
+	v = invalidLabelValueChars.ReplaceAllString(strings.TrimSpace(v), "-")
+	if len(v) > 63 {
+		v = v[:63]
+	}
+	return strings.Trim(v, "-_.")
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,63 @@
+package deploy
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectGitMetadata(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v (%s)", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "feature/login")
+	git("config", "user.name", "Dev One")
+	git("config", "user.email", "dev@example.com")
+	chartDir := filepath.Join(dir, "charts", "web")
+	if err := os.MkdirAll(chartDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte("name: web\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	meta := DetectGitMetadata(chartDir)
+	if meta == nil || len(meta.Commit) != 40 || meta.Dirty {
+		t.Fatalf("expected clean commit metadata, got %+v", meta)
+	}
+	if meta.Branch != "feature/login" || meta.Author != "Dev One <dev@example.com>" {
+		t.Fatalf("unexpected branch/author: %+v", meta)
+	}
+	labels := meta.ReleaseLabels()
+	if labels[GitBranchLabel] != "feature-login" || labels[GitDirtyLabel] != "false" || labels[GitCommitLabel] != meta.Commit {
+		t.Fatalf("unexpected labels: %+v", labels)
+	}
+	if desc := meta.Description(); !strings.Contains(desc, meta.Commit[:12]) || !strings.Contains(desc, "feature/login") {
+		t.Fatalf("unexpected description: %q", desc)
+	}
+
+	if err := os.WriteFile(filepath.Join(chartDir, "values.yaml"), []byte("a: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if meta := DetectGitMetadata(filepath.Join(chartDir, "Chart.yaml")); meta == nil || !meta.Dirty {
+		t.Fatalf("expected dirty metadata, got %+v", meta)
+	}
+	if meta := DetectGitMetadata("bitnami/nginx"); meta != nil {
+		t.Fatalf("expected nil for non-local chart ref, got %+v", meta)
+	}
+	if meta := DetectGitMetadata(t.TempDir()); meta != nil {
+		t.Fatalf("expected nil outside a work tree, got %+v", meta)
+	}
+}
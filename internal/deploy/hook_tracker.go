@@ -0,0 +1,396 @@
+// File: internal/deploy/hook_tracker.go
+// Brief: Internal deploy package implementation for 'hook tracker'.
+
+// hook_tracker.go follows the Job and Pod hooks of a release while Helm runs them, so a slow or
+// failing migration shows up in the deploy console instead of as an opaque upgrade timeout.
+package deploy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/release"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Hook states reported by HookTracker.
+const (
+	HookPending   = "Pending"
+	HookRunning   = "Running"
+	HookSucceeded = "Succeeded"
+	HookFailed    = "Failed"
+)
+
+// hookLogTail is how many log lines of a failed hook pod are kept.
+const hookLogTail = 20
+
+// HookStatus is the observed state of one Job or Pod hook.
+type HookStatus struct {
+	Event     string
+	Weight    int
+	Kind      string
+	Namespace string
+	Name      string
+	State     string
+	Message   string
+	StartedAt time.Time
+	Duration  time.Duration
+	// Logs holds the last lines of the failed pod's logs once the hook failed.
+	Logs []string
+}
+
+// Label returns "Kind/name".
+func (s HookStatus) Label() string {
+	return s.Kind + "/" + s.Name
+}
+
+// HookUpdateFunc consumes hook status snapshots in execution order.
+type HookUpdateFunc func([]HookStatus)
+
+// HookEventsFor returns the hook events Helm runs for an install or an upgrade.
+func HookEventsFor(upgrade bool) []string {
+	if upgrade {
+		return []string{release.HookPreUpgrade.String(), release.HookPostUpgrade.String()}
+	}
+	return []string{release.HookPreInstall.String(), release.HookPostInstall.String()}
+}
+
+// HookTracker polls the Job and Pod hooks of a release and reports their state. Hooks of other
+// kinds (ConfigMaps, ServiceAccounts, ...) finish as soon as Helm creates them and are ignored.
+type HookTracker struct {
+	client   kubernetes.Interface
+	interval time.Duration
+	update   HookUpdateFunc
+	onChange func(prev, next HookStatus)
+
+	// stale holds the UIDs of hook objects left over from earlier revisions (see Prime).
+	stale map[types.UID]bool
+
+	mu    sync.Mutex
+	hooks []HookStatus
+	seen  []bool
+	gone  []string
+}
+
+// NewHookTracker tracks the Job and Pod hooks among steps that run for events. hookManifest is
+// the rendered hook manifest (TemplateResult.HookManifest) and supplies each hook's namespace.
+func NewHookTracker(client kubernetes.Interface, namespace string, steps []HookStep, hookManifest string, events []string, update HookUpdateFunc) *HookTracker {
+	namespaces := map[string]string{}
+	for _, target := range targetsFromManifest(hookManifest) {
+		namespaces[target.Kind+"/"+target.Name] = strings.TrimSpace(target.Namespace)
+	}
+	wanted := map[string]bool{}
+	for _, e := range events {
+		wanted[e] = true
+	}
+	t := &HookTracker{client: client, interval: 2 * time.Second, update: update, stale: map[types.UID]bool{}}
+	for _, step := range steps {
+		if !wanted[step.Event] || (step.Kind != "Job" && step.Kind != "Pod") {
+			continue
+		}
+		ns := namespaces[step.Kind+"/"+step.Name]
+		if ns == "" {
+			ns = namespace
+		}
+		t.hooks = append(t.hooks, HookStatus{Event: step.Event, Weight: step.Weight, Kind: step.Kind, Namespace: ns, Name: step.Name, State: HookPending})
+		t.gone = append(t.gone, goneState(step.DeletePolicies))
+	}
+	t.seen = make([]bool, len(t.hooks))
+	return t
+}
+
+// goneState is what a hook that was running and then disappeared most likely ended as, given
+// its helm.sh/hook-delete-policy.
+func goneState(policies []string) string {
+	var onSuccess, onFailure bool
+	for _, p := range policies {
+		switch p {
+		case release.HookSucceeded.String():
+			onSuccess = true
+		case release.HookFailed.String():
+			onFailure = true
+		}
+	}
+	switch {
+	case onSuccess && !onFailure:
+		return HookSucceeded
+	case onFailure && !onSuccess:
+		return HookFailed
+	default:
+		return ""
+	}
+}
+
+// WithInterval overrides the polling interval.
+func (t *HookTracker) WithInterval(interval time.Duration) *HookTracker {
+	t.interval = interval
+	return t
+}
+
+// OnChange registers fn to be called whenever a hook changes state.
+func (t *HookTracker) OnChange(fn func(prev, next HookStatus)) *HookTracker {
+	t.onChange = fn
+	return t
+}
+
+// Empty reports whether the release has no Job or Pod hooks for the tracked events.
+func (t *HookTracker) Empty() bool {
+	return t == nil || len(t.hooks) == 0
+}
+
+// Snapshot returns the current hook states.
+func (t *HookTracker) Snapshot() []HookStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]HookStatus(nil), t.hooks...)
+}
+
+// Prime records the hook objects that already exist (kept by hook-delete-policy from an earlier
+// revision) so they are not mistaken for this revision's hooks. Call it before Helm starts.
+func (t *HookTracker) Prime(ctx context.Context) {
+	if t.Empty() || t.client == nil {
+		return
+	}
+	for _, h := range t.hooks {
+		var uid types.UID
+		switch h.Kind {
+		case "Job":
+			if job, err := t.client.BatchV1().Jobs(h.Namespace).Get(ctx, h.Name, metav1.GetOptions{}); err == nil {
+				uid = job.UID
+			}
+		case "Pod":
+			if pod, err := t.client.CoreV1().Pods(h.Namespace).Get(ctx, h.Name, metav1.GetOptions{}); err == nil {
+				uid = pod.UID
+			}
+		}
+		if uid != "" {
+			t.stale[uid] = true
+		}
+	}
+}
+
+// Run polls until ctx is canceled.
+func (t *HookTracker) Run(ctx context.Context) {
+	if t.Empty() || t.client == nil {
+		return
+	}
+	if t.update != nil {
+		t.update(t.Snapshot())
+	}
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if t.Poll(ctx) && t.update != nil {
+				t.update(t.Snapshot())
+			}
+		}
+	}
+}
+
+// Poll refreshes every unfinished hook once and reports whether anything changed.
+func (t *HookTracker) Poll(ctx context.Context) bool {
+	changed := false
+	for i := range t.Snapshot() {
+		t.mu.Lock()
+		prev := t.hooks[i]
+		seen := t.seen[i]
+		gone := t.gone[i]
+		t.mu.Unlock()
+		if prev.State == HookSucceeded || prev.State == HookFailed {
+			continue
+		}
+		next, found := t.observe(ctx, prev)
+		if !found {
+			if !seen || gone == "" {
+				continue
+			}
+			next = prev
+			next.State = gone
+			next.Message = "hook deleted by Helm (helm.sh/hook-delete-policy)"
+			if !prev.StartedAt.IsZero() {
+				next.Duration = time.Since(prev.StartedAt).Round(time.Second)
+			}
+		}
+		if next.State == prev.State && next.Message == prev.Message {
+			continue
+		}
+		t.mu.Lock()
+		t.hooks[i] = next
+		t.seen[i] = t.seen[i] || found
+		t.mu.Unlock()
+		changed = true
+		if t.onChange != nil && next.State != prev.State {
+			t.onChange(prev, next)
+		}
+	}
+	return changed
+}
+
+// observe reads the hook from the cluster. Objects from an earlier revision count as not found,
+// as do read errors.
+func (t *HookTracker) observe(ctx context.Context, h HookStatus) (HookStatus, bool) {
+	switch h.Kind {
+	case "Job":
+		job, err := t.client.BatchV1().Jobs(h.Namespace).Get(ctx, h.Name, metav1.GetOptions{})
+		if err != nil || t.stale[job.UID] {
+			return h, false
+		}
+		return t.jobStatus(ctx, h, job), true
+	case "Pod":
+		pod, err := t.client.CoreV1().Pods(h.Namespace).Get(ctx, h.Name, metav1.GetOptions{})
+		if err != nil || t.stale[pod.UID] {
+			return h, false
+		}
+		return t.podStatus(ctx, h, pod), true
+	}
+	return h, false
+}
+
+func (t *HookTracker) jobStatus(ctx context.Context, h HookStatus, job *batchv1.Job) HookStatus {
+	h.StartedAt = job.CreationTimestamp.Time
+	if job.Status.StartTime != nil {
+		h.StartedAt = job.Status.StartTime.Time
+	}
+	h.State = HookRunning
+	h.Message = fmt.Sprintf("%d active, %d succeeded, %d failed", job.Status.Active, job.Status.Succeeded, job.Status.Failed)
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			h.State = HookSucceeded
+			h.Message = "completed"
+		case batchv1.JobFailed:
+			h.State = HookFailed
+			h.Message = strings.TrimSpace(cond.Reason + ": " + cond.Message)
+		}
+	}
+	if h.State == HookRunning && job.Status.Failed > 0 {
+		if pod := t.latestJobPod(ctx, job, corev1.PodFailed); pod != nil {
+			h.Message += "; last failure: " + podFailureReason(pod)
+		}
+	}
+	if h.State == HookSucceeded || h.State == HookFailed {
+		end := time.Now()
+		if job.Status.CompletionTime != nil {
+			end = job.Status.CompletionTime.Time
+		}
+		h.Duration = end.Sub(h.StartedAt).Round(time.Second)
+	}
+	if h.State == HookFailed {
+		if pod := t.latestJobPod(ctx, job, corev1.PodFailed); pod != nil {
+			h.Logs = t.podLogs(ctx, pod)
+		}
+	}
+	return h
+}
+
+func (t *HookTracker) podStatus(ctx context.Context, h HookStatus, pod *corev1.Pod) HookStatus {
+	h.StartedAt = pod.CreationTimestamp.Time
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		h.State = HookSucceeded
+		h.Message = "completed"
+	case corev1.PodFailed:
+		h.State = HookFailed
+		h.Message = podFailureReason(pod)
+		h.Logs = t.podLogs(ctx, pod)
+	case corev1.PodRunning:
+		h.State = HookRunning
+		h.Message = "running"
+	default:
+		h.State = HookRunning
+		h.Message = strings.ToLower(string(pod.Status.Phase))
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+				h.Message = cs.State.Waiting.Reason
+			}
+		}
+	}
+	if h.State == HookSucceeded || h.State == HookFailed {
+		h.Duration = time.Since(h.StartedAt).Round(time.Second)
+	}
+	return h
+}
+
+// latestJobPod returns the newest pod of job in phase.
+func (t *HookTracker) latestJobPod(ctx context.Context, job *batchv1.Job, phase corev1.PodPhase) *corev1.Pod {
+	pods, err := t.client.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + job.Name})
+	if err != nil {
+		return nil
+	}
+	var matched []corev1.Pod
+	for _, p := range pods.Items {
+		if p.Status.Phase == phase {
+			matched = append(matched, p)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreationTimestamp.After(matched[j].CreationTimestamp.Time)
+	})
+	return &matched[0]
+}
+
+// podFailureReason describes why the first failed container of pod exited.
+func podFailureReason(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if term := cs.State.Terminated; term != nil && term.ExitCode != 0 {
+			msg := fmt.Sprintf("container %s exited %d", cs.Name, term.ExitCode)
+			if term.Reason != "" {
+				msg += " (" + term.Reason + ")"
+			}
+			return msg
+		}
+	}
+	if pod.Status.Reason != "" {
+		return strings.TrimSpace(pod.Status.Reason + ": " + pod.Status.Message)
+	}
+	return "pod failed"
+}
+
+// podLogs returns the last hookLogTail lines of the failed container of pod (or its first
+// container).
+func (t *HookTracker) podLogs(ctx context.Context, pod *corev1.Pod) []string {
+	container := ""
+	for _, cs := range pod.Status.ContainerStatuses {
+		if term := cs.State.Terminated; term != nil && term.ExitCode != 0 {
+			container = cs.Name
+			break
+		}
+	}
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+	tail := int64(hookLogTail)
+	stream, err := t.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: container, TailLines: &tail}).Stream(ctx)
+	if err != nil {
+		return []string{fmt.Sprintf("(logs unavailable: %v)", err)}
+	}
+	defer stream.Close()
+	var lines []string
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > hookLogTail {
+			lines = lines[1:]
+		}
+	}
+	return lines
+}
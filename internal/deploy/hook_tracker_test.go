@@ -0,0 +1,116 @@
+package deploy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewHookTrackerSelectsJobAndPodHooks(t *testing.T) {
+	steps := []HookStep{
+		{Event: "pre-install", Kind: "Job", Name: "seed"},
+		{Event: "pre-upgrade", Kind: "ConfigMap", Name: "migrate-config"},
+		{Event: "pre-upgrade", Kind: "Job", Name: "migrate"},
+		{Event: "post-upgrade", Kind: "Pod", Name: "smoke"},
+	}
+	manifest := "---\n# Source: chart/templates/smoke.yaml\napiVersion: v1\nkind: Pod\nmetadata:\n  name: smoke\n  namespace: qa\n"
+	tracker := NewHookTracker(fake.NewSimpleClientset(), "prod", steps, manifest, HookEventsFor(true), nil)
+	got := tracker.Snapshot()
+	if len(got) != 2 || got[0].Label() != "Job/migrate" || got[0].Namespace != "prod" || got[1].Label() != "Pod/smoke" || got[1].Namespace != "qa" {
+		t.Fatalf("hooks = %+v", got)
+	}
+	if got[0].State != HookPending {
+		t.Fatalf("state = %q", got[0].State)
+	}
+	if !NewHookTracker(nil, "prod", steps[:2], "", HookEventsFor(true), nil).Empty() {
+		t.Fatalf("expected no trackable hooks")
+	}
+}
+
+func TestHookTrackerPoll(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "prod", UID: "old"},
+		Status:     batchv1.JobStatus{Succeeded: 1, Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}},
+	})
+	steps := []HookStep{{Event: "pre-upgrade", Kind: "Job", Name: "migrate", DeletePolicies: []string{"before-hook-creation"}}}
+	var changes []string
+	tracker := NewHookTracker(client, "prod", steps, "", HookEventsFor(true), nil).OnChange(func(_, next HookStatus) {
+		changes = append(changes, next.State)
+	})
+	tracker.Prime(ctx)
+	if tracker.Poll(ctx) {
+		t.Fatalf("expected the job from the previous revision to be ignored: %+v", tracker.Snapshot())
+	}
+
+	jobs := client.BatchV1().Jobs("prod")
+	if err := jobs.Delete(ctx, "migrate", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "prod", UID: "new"},
+		Status:     batchv1.JobStatus{Active: 1},
+	}
+	if _, err := jobs.Create(ctx, job, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !tracker.Poll(ctx) || tracker.Snapshot()[0].State != HookRunning {
+		t.Fatalf("expected running: %+v", tracker.Snapshot())
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate-abcde", Namespace: "prod", Labels: map[string]string{"job-name": "migrate"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "migrate"}}},
+		Status: corev1.PodStatus{Phase: corev1.PodFailed, ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "migrate",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 3, Reason: "Error"}},
+		}}},
+	}
+	if _, err := client.CoreV1().Pods("prod").Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	job.Status = batchv1.JobStatus{Failed: 1, Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"}}}
+	if _, err := jobs.UpdateStatus(ctx, job, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !tracker.Poll(ctx) {
+		t.Fatalf("expected a change")
+	}
+	got := tracker.Snapshot()[0]
+	if got.State != HookFailed || !strings.HasPrefix(got.Message, "BackoffLimitExceeded") {
+		t.Fatalf("hook = %+v", got)
+	}
+	if len(got.Logs) == 0 {
+		t.Fatalf("expected the failed pod's logs")
+	}
+	if strings.Join(changes, ",") != "Running,Failed" {
+		t.Fatalf("changes = %v", changes)
+	}
+	if tracker.Poll(ctx) {
+		t.Fatalf("finished hooks should not be polled again")
+	}
+}
+
+func TestHookTrackerDeletedHook(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "smoke", Namespace: "prod"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	})
+	steps := []HookStep{{Event: "post-install", Kind: "Pod", Name: "smoke", DeletePolicies: []string{"hook-succeeded"}}}
+	tracker := NewHookTracker(client, "prod", steps, "", HookEventsFor(false), nil)
+	if !tracker.Poll(ctx) || tracker.Snapshot()[0].State != HookRunning {
+		t.Fatalf("expected running: %+v", tracker.Snapshot())
+	}
+	if err := client.CoreV1().Pods("prod").Delete(ctx, "smoke", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !tracker.Poll(ctx) || tracker.Snapshot()[0].State != HookSucceeded {
+		t.Fatalf("expected a hook deleted on success to count as succeeded: %+v", tracker.Snapshot())
+	}
+}
@@ -0,0 +1,329 @@
+// File: internal/deploy/image_rewrite.go
+// Brief: Internal deploy package implementation for 'image rewrite'.
+
+// image_rewrite.go implements the built-in images post-renderer: registry mirroring and
+// tag-to-digest pinning across every container in the rendered manifests, with a report of each
+// rewrite for dry runs.
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/netconfig"
+	godigest "github.com/opencontainers/go-digest"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/postrender"
+	"oras.land/oras-go/v2/registry/remote"
+	"sigs.k8s.io/yaml"
+)
+
+// ImageRewrite is one container image changed by the images post-renderer.
+type ImageRewrite struct {
+	Resource  string `json:"resource"`
+	Container string `json:"container"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+}
+
+type imageRewriter struct {
+	ctx     context.Context
+	mirrors []appconfig.ImageMirror
+	digests map[string]string
+	pin     bool
+	resolve func(ctx context.Context, ref reference.NamedTagged) (string, error)
+
+	mu       sync.Mutex
+	resolved map[string]string
+	report   []ImageRewrite
+}
+
+func newImageRewriter(ctx context.Context, cfg appconfig.ImageRewriteConfig) (*imageRewriter, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	r := &imageRewriter{ctx: ctx, pin: cfg.PinDigests, digests: map[string]string{}, resolved: map[string]string{}, resolve: resolveImageDigest}
+	for i, m := range cfg.Mirrors {
+		from := strings.TrimSuffix(strings.TrimSpace(m.From), "/")
+		to := strings.TrimSuffix(strings.TrimSpace(m.To), "/")
+		if from == "" || to == "" {
+			return nil, fmt.Errorf("images.mirrors[%d]: from and to are required", i)
+		}
+		r.mirrors = append(r.mirrors, appconfig.ImageMirror{From: from, To: to})
+	}
+	for image, digest := range cfg.Digests {
+		named, err := reference.ParseNormalizedNamed(strings.TrimSpace(image))
+		if err != nil {
+			return nil, fmt.Errorf("images.digests[%s]: %w", image, err)
+		}
+		digest = strings.TrimSpace(digest)
+		if err := godigest.Digest(digest).Validate(); err != nil {
+			return nil, fmt.Errorf("images.digests[%s]: %w", image, err)
+		}
+		r.digests[reference.TagNameOnly(named).String()] = digest
+	}
+	return r, nil
+}
+
+// Run implements postrender.PostRenderer.
+func (r *imageRewriter) Run(in *bytes.Buffer) (*bytes.Buffer, error) {
+	r.mu.Lock()
+	r.report = nil
+	r.mu.Unlock()
+	var out bytes.Buffer
+	for _, doc := range splitYAMLDocs(in.String()) {
+		rewritten, err := r.rewriteDoc(doc)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&out, "---\n%s\n", strings.TrimSpace(rewritten))
+	}
+	return &out, nil
+}
+
+// Rewrites returns the rewrites made by the last Run, ordered by resource and container.
+func (r *imageRewriter) Rewrites() []ImageRewrite {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := append([]ImageRewrite(nil), r.report...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Resource != out[j].Resource {
+			return out[i].Resource < out[j].Resource
+		}
+		return out[i].Container < out[j].Container
+	})
+	return out
+}
+
+func (r *imageRewriter) rewriteDoc(doc string) (string, error) {
+	var obj map[string]any
+	if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj == nil {
+		return doc, nil
+	}
+	resource := imageResourceName(obj)
+	changed := false
+	var walkErr error
+	walkContainers(obj, func(c map[string]any) {
+		image, _ := c["image"].(string)
+		if walkErr != nil || strings.TrimSpace(image) == "" {
+			return
+		}
+		next, err := r.rewriteImage(image)
+		if err != nil {
+			walkErr = fmt.Errorf("%s: %w", resource, err)
+			return
+		}
+		if next == image {
+			return
+		}
+		c["image"] = next
+		changed = true
+		name, _ := c["name"].(string)
+		r.mu.Lock()
+		r.report = append(r.report, ImageRewrite{Resource: resource, Container: name, From: image, To: next})
+		r.mu.Unlock()
+	})
+	if walkErr != nil || !changed {
+		return doc, walkErr
+	}
+	return reencodeDoc(doc, obj)
+}
+
+func (r *imageRewriter) rewriteImage(image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(strings.TrimSpace(image))
+	if err != nil {
+		return "", fmt.Errorf("parse image %q: %w", image, err)
+	}
+	named = reference.TagNameOnly(named)
+	original := named
+	if mirrored, ok := r.mirror(named); ok {
+		named = mirrored
+	}
+	if _, digested := named.(reference.Digested); !digested {
+		// Pin against the registry the cluster will pull from: a mirror may hold a different
+		// manifest (or none) for the same tag.
+		tagged, _ := named.(reference.NamedTagged)
+		digest := r.digests[original.String()]
+		if digest == "" && r.pin && tagged != nil {
+			if digest, err = r.lookupDigest(tagged); err != nil {
+				return "", err
+			}
+		}
+		if digest != "" {
+			canonical, err := reference.WithDigest(named, godigest.Digest(digest))
+			if err != nil {
+				return "", fmt.Errorf("pin %s: %w", image, err)
+			}
+			named = canonical
+		}
+	}
+	if named.String() == original.String() {
+		return image, nil
+	}
+	return named.String(), nil
+}
+
+func (r *imageRewriter) mirror(named reference.Named) (reference.Named, bool) {
+	full := named.String()
+	for _, m := range r.mirrors {
+		// Prefixes match the normalized name, so Docker Hub images are docker.io/library/...
+		from := m.From
+		if full != from && !strings.HasPrefix(full, from+"/") && !strings.HasPrefix(full, from+":") && !strings.HasPrefix(full, from+"@") {
+			continue
+		}
+		next, err := reference.ParseNormalizedNamed(m.To + strings.TrimPrefix(full, from))
+		if err != nil {
+			continue
+		}
+		return next, true
+	}
+	return named, false
+}
+
+func (r *imageRewriter) lookupDigest(ref reference.NamedTagged) (string, error) {
+	key := ref.String()
+	r.mu.Lock()
+	digest, ok := r.resolved[key]
+	r.mu.Unlock()
+	if ok {
+		return digest, nil
+	}
+	ctx, cancel := context.WithTimeout(r.ctx, 30*time.Second)
+	defer cancel()
+	digest, err := r.resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("resolve digest for %s: %w", key, err)
+	}
+	r.mu.Lock()
+	r.resolved[key] = digest
+	r.mu.Unlock()
+	return digest, nil
+}
+
+func resolveImageDigest(ctx context.Context, ref reference.NamedTagged) (string, error) {
+	host := reference.Domain(ref)
+	if err := netconfig.CheckURL("https://" + host); err != nil {
+		return "", err
+	}
+	if host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+	repo, err := remote.NewRepository(host + "/" + reference.Path(ref))
+	if err != nil {
+		return "", err
+	}
+	repo.Client = registryAuthClient(cli.New(), nil)
+	desc, err := repo.Resolve(ctx, ref.Tag())
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
+
+// ImageRewrites returns the rewrites made by any images step of pr during its last run.
+func ImageRewrites(pr postrender.PostRenderer) []ImageRewrite {
+	switch v := pr.(type) {
+	case postRenderChain:
+		var out []ImageRewrite
+		for _, step := range v {
+			out = append(out, ImageRewrites(step.PostRenderer)...)
+		}
+		return out
+	case *imageRewriter:
+		return v.Rewrites()
+	}
+	return nil
+}
+
+// PrintImageRewrites writes the image rewrite report as a table.
+func PrintImageRewrites(w io.Writer, rewrites []ImageRewrite) {
+	if len(rewrites) == 0 {
+		fmt.Fprintln(w, "No images rewritten")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+	fmt.Fprintln(tw, "RESOURCE\tCONTAINER\tFROM\tTO")
+	for _, rw := range rewrites {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", rw.Resource, rw.Container, rw.From, rw.To)
+	}
+}
+
+// walkContainers calls fn for every entry of any containers, initContainers, or
+// ephemeralContainers list, which covers pods, workload templates, CronJobs, and most CRDs.
+func walkContainers(v any, fn func(map[string]any)) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if list, ok := child.([]any); ok && (k == "containers" || k == "initContainers" || k == "ephemeralContainers") {
+				for _, item := range list {
+					if c, ok := item.(map[string]any); ok {
+						fn(c)
+					}
+				}
+				continue
+			}
+			walkContainers(child, fn)
+		}
+	case []any:
+		for _, item := range t {
+			walkContainers(item, fn)
+		}
+	}
+}
+
+func imageResourceName(obj map[string]any) string {
+	kind, _ := obj["kind"].(string)
+	name := ""
+	if meta, ok := obj["metadata"].(map[string]any); ok {
+		name, _ = meta["name"].(string)
+	}
+	return kind + "/" + name
+}
+
+// reencodeDoc marshals obj, keeping the leading comments of doc ("# Source:" lines) so plan
+// reports can still map resources to templates.
+func reencodeDoc(doc string, obj map[string]any) (string, error) {
+	raw, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	var header strings.Builder
+	for _, line := range strings.Split(doc, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			break
+		}
+		header.WriteString(line + "\n")
+	}
+	return header.String() + string(raw), nil
+}
+
+// splitYAMLDocs splits a multi-document manifest on "---" lines, dropping empty documents.
+func splitYAMLDocs(manifest string) []string {
+	var docs []string
+	var cur strings.Builder
+	flush := func() {
+		if doc := strings.TrimSpace(cur.String()); doc != "" {
+			docs = append(docs, doc)
+		}
+		cur.Reset()
+	}
+	for _, line := range strings.Split(manifest, "\n") {
+		if strings.HasPrefix(line, "---") {
+			flush()
+			continue
+		}
+		cur.WriteString(line + "\n")
+	}
+	flush()
+	return docs
+}
@@ -0,0 +1,110 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/kubekattle/ktl/internal/appconfig"
+)
+
+const imageRewriteInput = `# Source: demo/templates/web.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          image: busybox:1.36
+      containers:
+        - name: app
+          image: ghcr.io/acme/web:v2
+        - name: proxy
+          image: envoyproxy/envoy@sha256:0000000000000000000000000000000000000000000000000000000000000000
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: backup
+              image: registry.internal/tools/backup:1.0
+`
+
+func TestImageRewriter_MirrorsAndPins(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "caller")
+	r, err := newImageRewriter(ctx, appconfig.ImageRewriteConfig{
+		Mirrors: []appconfig.ImageMirror{
+			{From: "docker.io/library", To: "mirror.example.com/hub"},
+			{From: "ghcr.io/acme", To: "registry.prod.example.com/acme"},
+		},
+		Digests:    map[string]string{"busybox:1.36": "sha256:1111111111111111111111111111111111111111111111111111111111111111"},
+		PinDigests: true,
+	})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	var lookups []string
+	r.resolve = func(ctx context.Context, ref reference.NamedTagged) (string, error) {
+		if ctx.Value(ctxKey{}) != "caller" {
+			t.Fatalf("resolve for %s did not receive the caller's context", ref)
+		}
+		lookups = append(lookups, ref.String())
+		return "sha256:2222222222222222222222222222222222222222222222222222222222222222", nil
+	}
+
+	out, err := r.Run(bytes.NewBufferString(imageRewriteInput))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"# Source: demo/templates/web.yaml",
+		"image: mirror.example.com/hub/busybox:1.36@sha256:1111",
+		"image: registry.prod.example.com/acme/web:v2@sha256:2222",
+		"image: envoyproxy/envoy@sha256:0000",
+		"image: registry.internal/tools/backup:1.0@sha256:2222",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output missing %q:\n%s", want, got)
+		}
+	}
+	sort.Strings(lookups)
+	if strings.Join(lookups, ",") != "registry.internal/tools/backup:1.0,registry.prod.example.com/acme/web:v2" {
+		t.Fatalf("lookups=%v, want web resolved against its mirror and backup against its own registry", lookups)
+	}
+
+	report := ImageRewrites(postRenderChain{{name: "images", PostRenderer: r}})
+	if len(report) != 3 {
+		t.Fatalf("report=%+v", report)
+	}
+	if report[0].Resource != "CronJob/backup" || report[1].Container != "app" || report[2].From != "busybox:1.36" {
+		t.Fatalf("unexpected report order: %+v", report)
+	}
+	var table bytes.Buffer
+	PrintImageRewrites(&table, report)
+	if !strings.Contains(table.String(), "Deployment/web") {
+		t.Fatalf("table=%s", table.String())
+	}
+}
+
+func TestImageRewriter_Validation(t *testing.T) {
+	if _, err := newImageRewriter(context.Background(), appconfig.ImageRewriteConfig{Mirrors: []appconfig.ImageMirror{{From: "docker.io"}}}); err == nil {
+		t.Fatalf("expected mirror without to to fail")
+	}
+	if _, err := newImageRewriter(context.Background(), appconfig.ImageRewriteConfig{Digests: map[string]string{"nginx:1": "abc"}}); err == nil {
+		t.Fatalf("expected invalid digest to fail")
+	}
+}
@@ -0,0 +1,74 @@
+// File: internal/deploy/interrupt.go
+// Brief: Internal deploy package implementation for 'interrupt'.
+
+// interrupt.go describes the partial state an interrupted (Ctrl+C) apply leaves behind.
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// InterruptSummary lists what an interrupted run finished, what it left in an unknown state, and
+// what it never started, plus the command that picks the work up again.
+type InterruptSummary struct {
+	Completed []string `json:"completed,omitempty"`
+	// Failed and Blocked list work that failed, or was skipped because a dependency failed,
+	// before the interrupt.
+	Failed     []string `json:"failed,omitempty"`
+	Blocked    []string `json:"blocked,omitempty"`
+	Unknown    []string `json:"unknown,omitempty"`
+	NotStarted []string `json:"notStarted,omitempty"`
+	Resume     string   `json:"resume,omitempty"`
+	// Hint explains the unknown entries (for example the Helm release status after cancellation).
+	Hint string `json:"hint,omitempty"`
+}
+
+// Write prints the summary in the CLI's plain-text form.
+func (s *InterruptSummary) Write(w io.Writer) {
+	if s == nil || w == nil {
+		return
+	}
+	counts := []string{fmt.Sprintf("%d completed", len(s.Completed))}
+	if len(s.Failed) > 0 {
+		counts = append(counts, fmt.Sprintf("%d failed", len(s.Failed)))
+	}
+	if len(s.Blocked) > 0 {
+		counts = append(counts, fmt.Sprintf("%d blocked", len(s.Blocked)))
+	}
+	counts = append(counts, fmt.Sprintf("%d in unknown state", len(s.Unknown)), fmt.Sprintf("%d not started", len(s.NotStarted)))
+	fmt.Fprintf(w, "Interrupted: %s\n", strings.Join(counts, ", "))
+	writeList := func(label string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "  %-12s %s\n", label+":", strings.Join(items, ", "))
+	}
+	writeList("completed", s.Completed)
+	writeList("failed", s.Failed)
+	writeList("blocked", s.Blocked)
+	writeList("unknown", s.Unknown)
+	writeList("not started", s.NotStarted)
+	if hint := strings.TrimSpace(s.Hint); hint != "" {
+		fmt.Fprintf(w, "  %s\n", hint)
+	}
+	if resume := strings.TrimSpace(s.Resume); resume != "" {
+		fmt.Fprintf(w, "Resume with: %s\n", resume)
+	}
+}
+
+// InterruptedError is returned when a run stops because its context was cancelled. It unwraps to
+// context.Canceled so callers keep treating it as a cancellation (exit code 130).
+type InterruptedError struct {
+	Summary *InterruptSummary
+}
+
+func (e *InterruptedError) Error() string {
+	return "interrupted"
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return context.Canceled
+}
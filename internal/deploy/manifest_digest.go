@@ -1,11 +1,40 @@
 package deploy
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/metadata"
+)
+
+// ManifestDigestAnnotation is the annotation ktl sets on the Helm storage object (the Secret or
+// ConfigMap holding one revision) of every release revision it applies: the digest of the manifest
+// it rendered. Revisions made by other tools (for example helm upgrade) don't carry it.
+const ManifestDigestAnnotation = "ktl.dev/manifest-digest"
+
+// InputDigestLabel is the release label holding a short digest of the inputs (chart, values,
+// hooks, images) a release was applied from, so a later run on any machine can tell whether
+// applying again would change anything.
+const InputDigestLabel = "ktl.dev/input-digest"
+
+// Drift states reported by ReleaseDrift.
+const (
+	DriftInSync   = "in-sync"
+	DriftModified = "modified"
+	DriftUnknown  = "unknown"
 )
 
 // DigestNormalizedManifest computes a stable digest for a Helm manifest after applying
@@ -56,3 +85,198 @@ func FormatDigestMismatch(want, got string) string {
 	}
 	return fmt.Sprintf("digest mismatch (want=%s got=%s)", want, got)
 }
+
+// ShortManifestDigest turns a DigestNormalizedManifest digest into a label value (labels are
+// limited to 63 characters and may not contain ':').
+func ShortManifestDigest(digest string) string {
+	short := strings.TrimPrefix(strings.TrimSpace(digest), "sha256:")
+	if len(short) > 40 {
+		short = short[:40]
+	}
+	return short
+}
+
+// ReleaseDrift compares the manifest digest ktl recorded when it applied a release with a fresh
+// render of it (see RenderStoredRelease). Revisions ktl did not apply (recorded is empty) and
+// renders that failed (rendered is empty) are DriftUnknown.
+func ReleaseDrift(recorded, rendered string) string {
+	recorded = strings.TrimSpace(recorded)
+	if recorded == "" || strings.TrimSpace(rendered) == "" {
+		return DriftUnknown
+	}
+	digest, _, err := DigestNormalizedManifest(rendered)
+	if err != nil {
+		return DriftUnknown
+	}
+	if digest != recorded {
+		return DriftModified
+	}
+	return DriftInSync
+}
+
+// ReleaseInputUnchanged reports whether rel is deployed from inputDigest and nothing changed it
+// since: the release is in the deployed state, carries the same InputDigestLabel, and recorded
+// (its ManifestDigestAnnotation) matches its manifest, so the revision is the one ktl applied.
+func ReleaseInputUnchanged(rel *release.Release, inputDigest, recorded string) bool {
+	if rel == nil || rel.Info == nil || rel.Info.Status != release.StatusDeployed {
+		return false
+	}
+	want := ShortManifestDigest(inputDigest)
+	if want == "" || strings.TrimSpace(rel.Labels[InputDigestLabel]) != want {
+		return false
+	}
+	return ReleaseDrift(recorded, rel.Manifest) == DriftInSync
+}
+
+// RecordedManifestDigests returns the ManifestDigestAnnotation of each release revision stored in
+// namespace ("" for all namespaces), keyed by ReleaseStorageKey. Only object metadata is fetched.
+// Storage drivers without Kubernetes objects (memory, SQL) yield an empty map.
+func RecordedManifestDigests(ctx context.Context, actionCfg *action.Configuration, namespace string) (map[string]string, error) {
+	annotations, err := ReleaseStorageAnnotations(ctx, actionCfg, namespace)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	for key, ann := range annotations {
+		if digest := strings.TrimSpace(ann[ManifestDigestAnnotation]); digest != "" {
+			out[key] = digest
+		}
+	}
+	return out, nil
+}
+
+// RecordedManifestDigest returns rel's ManifestDigestAnnotation, or "" when ktl did not apply
+// this revision.
+func RecordedManifestDigest(ctx context.Context, actionCfg *action.Configuration, rel *release.Release) (string, error) {
+	ann, err := StoredReleaseAnnotations(ctx, actionCfg, rel)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(ann[ManifestDigestAnnotation]), nil
+}
+
+// ReleaseStorageAnnotations returns the annotations of every release storage object in namespace
+// ("" for all namespaces), keyed by ReleaseStorageKey, with a single metadata-only list.
+func ReleaseStorageAnnotations(ctx context.Context, actionCfg *action.Configuration, namespace string) (map[string]map[string]string, error) {
+	client, gvr, ok, err := releaseStorageClient(actionCfg)
+	if err != nil || !ok {
+		return map[string]map[string]string{}, err
+	}
+	out := map[string]map[string]string{}
+	opts := metav1.ListOptions{LabelSelector: "owner=helm"}
+	for {
+		list, err := client.Resource(gvr).Namespace(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("list release storage: %w", err)
+		}
+		for _, item := range list.Items {
+			if len(item.Annotations) > 0 {
+				out[item.Namespace+"/"+item.Name] = item.Annotations
+			}
+		}
+		if list.Continue == "" {
+			return out, nil
+		}
+		opts.Continue = list.Continue
+	}
+}
+
+// StoredReleaseAnnotations returns the annotations of the storage object holding rel's revision,
+// or nil when there is none.
+func StoredReleaseAnnotations(ctx context.Context, actionCfg *action.Configuration, rel *release.Release) (map[string]string, error) {
+	client, gvr, ok, err := releaseStorageClient(actionCfg)
+	if err != nil || !ok || rel == nil {
+		return nil, err
+	}
+	obj, err := client.Resource(gvr).Namespace(rel.Namespace).Get(ctx, releaseStorageName(rel), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get release storage: %w", err)
+	}
+	return obj.Annotations, nil
+}
+
+// ReleaseStorageKey is the namespace/name of the Helm storage object holding rel's revision.
+func ReleaseStorageKey(rel *release.Release) string {
+	return rel.Namespace + "/" + releaseStorageName(rel)
+}
+
+func releaseStorageName(rel *release.Release) string {
+	return fmt.Sprintf("sh.helm.release.v1.%s.v%d", rel.Name, rel.Version)
+}
+
+// releaseStorageClient returns a metadata client for the Secrets or ConfigMaps actionCfg stores
+// releases in. ok is false for storage drivers without Kubernetes objects.
+func releaseStorageClient(actionCfg *action.Configuration) (client metadata.Interface, gvr schema.GroupVersionResource, ok bool, err error) {
+	if actionCfg == nil || actionCfg.Releases == nil {
+		return nil, gvr, false, nil
+	}
+	switch actionCfg.Releases.Name() {
+	case driver.SecretsDriverName:
+		gvr = corev1.SchemeGroupVersion.WithResource("secrets")
+	case driver.ConfigMapsDriverName:
+		gvr = corev1.SchemeGroupVersion.WithResource("configmaps")
+	default:
+		return nil, gvr, false, nil
+	}
+	client, err = newReleaseStorageClient(actionCfg)
+	if err != nil || client == nil {
+		return nil, gvr, false, err
+	}
+	return client, gvr, true, nil
+}
+
+// newReleaseStorageClient builds the metadata client behind releaseStorageClient; tests swap it.
+var newReleaseStorageClient = func(actionCfg *action.Configuration) (metadata.Interface, error) {
+	if actionCfg.RESTClientGetter == nil {
+		return nil, nil
+	}
+	cfg, err := actionCfg.RESTClientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	return metadata.NewForConfig(cfg)
+}
+
+// recordReleaseMetadata stores the input digest label (when inputDigest is set) on an applied
+// release, then the ManifestDigestAnnotation and owner annotations on its storage object. The
+// annotations go last because Helm rewrites the whole storage object, annotations included, on
+// every update. Owner annotations live on the storage object rather than the chart, so they stay
+// out of .Chart.Annotations and exported chart archives.
+func recordReleaseMetadata(ctx context.Context, actionCfg *action.Configuration, rel *release.Release, inputDigest string, owner *ReleaseOwner) error {
+	if actionCfg == nil || actionCfg.Releases == nil || rel == nil {
+		return nil
+	}
+	digest, _, err := DigestNormalizedManifest(rel.Manifest)
+	if err != nil {
+		return err
+	}
+	if rel.Labels == nil {
+		rel.Labels = map[string]string{}
+	}
+	if inputDigest = strings.TrimSpace(inputDigest); inputDigest != "" {
+		rel.Labels[InputDigestLabel] = ShortManifestDigest(inputDigest)
+	} else {
+		// A release applied without an input digest must not keep a stale one.
+		delete(rel.Labels, InputDigestLabel)
+	}
+	if err := actionCfg.Releases.Update(rel); err != nil {
+		return err
+	}
+	client, gvr, ok, err := releaseStorageClient(actionCfg)
+	if err != nil || !ok {
+		return err
+	}
+	annotations := map[string]any{ManifestDigestAnnotation: digest}
+	for key, v := range owner.annotations() {
+		annotations[key] = v
+	}
+	patch, err := json.Marshal(map[string]any{"metadata": map[string]any{"annotations": annotations}})
+	if err != nil {
+		return err
+	}
+	_, err = client.Resource(gvr).Namespace(rel.Namespace).Patch(ctx, releaseStorageName(rel), types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
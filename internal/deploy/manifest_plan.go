@@ -33,6 +33,8 @@ type PlanChange struct {
 	Name      string
 	IsHook    bool
 	Hook      string
+	// Risks tags aspects of the change that need extra approval (see ClassifyRisks).
+	Risks []PlanRisk
 }
 
 type PlanSummary struct {
@@ -114,6 +116,7 @@ func SummarizeManifestPlan(previousManifest, proposedManifest string) (*PlanSumm
 		prevObj, ok := prevByKey[key]
 		if !ok {
 			ch := obj.toPlanChange(PlanAdd)
+			ch.Risks = ClassifyRisks(nil, obj.Normalized)
 			if ch.IsHook {
 				summary.Hooks.Add++
 				summary.Hooks.Changes = append(summary.Hooks.Changes, ch)
@@ -129,6 +132,7 @@ func SummarizeManifestPlan(previousManifest, proposedManifest string) (*PlanSumm
 				action = PlanReplace
 			}
 			ch := obj.toPlanChange(action)
+			ch.Risks = ClassifyRisks(prevObj.Normalized, obj.Normalized)
 			if ch.IsHook {
 				if action == PlanReplace {
 					summary.Hooks.Replace++
@@ -151,6 +155,7 @@ func SummarizeManifestPlan(previousManifest, proposedManifest string) (*PlanSumm
 			continue
 		}
 		ch := obj.toPlanChange(PlanDestroy)
+		ch.Risks = ClassifyRisks(obj.Normalized, nil)
 		if ch.IsHook {
 			summary.Hooks.Destroy++
 			summary.Hooks.Changes = append(summary.Hooks.Changes, ch)
@@ -199,7 +204,9 @@ func SummarizeManifestPlan(previousManifest, proposedManifest string) (*PlanSumm
 		summary.Changes = kept
 		for alt := range seenReplace {
 			obj := nextByAltKey[alt]
-			summary.Changes = append(summary.Changes, obj.toPlanChange(PlanReplace))
+			ch := obj.toPlanChange(PlanReplace)
+			ch.Risks = ClassifyRisks(prevByAltKey[alt].Normalized, obj.Normalized)
+			summary.Changes = append(summary.Changes, ch)
 		}
 	}
 
@@ -50,6 +50,7 @@ func SummarizeManifestPlanWithHelmKube(client *kube.Client, previousManifest, pr
 		prevObj, ok := prevByKey[key]
 		if !ok {
 			ch := obj.toPlanChange(PlanAdd)
+			ch.Risks = ClassifyRisks(nil, obj.Normalized)
 			if ch.IsHook {
 				summary.Hooks.Add++
 				summary.Hooks.Changes = append(summary.Hooks.Changes, ch)
@@ -65,6 +66,7 @@ func SummarizeManifestPlanWithHelmKube(client *kube.Client, previousManifest, pr
 				action = PlanReplace
 			}
 			ch := obj.toPlanChange(action)
+			ch.Risks = ClassifyRisks(prevObj.Normalized, obj.Normalized)
 			if ch.IsHook {
 				if action == PlanReplace {
 					summary.Hooks.Replace++
@@ -87,6 +89,7 @@ func SummarizeManifestPlanWithHelmKube(client *kube.Client, previousManifest, pr
 			continue
 		}
 		ch := obj.toPlanChange(PlanDestroy)
+		ch.Risks = ClassifyRisks(obj.Normalized, nil)
 		if ch.IsHook {
 			summary.Hooks.Destroy++
 			summary.Hooks.Changes = append(summary.Hooks.Changes, ch)
@@ -132,7 +135,9 @@ func SummarizeManifestPlanWithHelmKube(client *kube.Client, previousManifest, pr
 		summary.Changes = kept
 		for alt := range seenReplace {
 			obj := nextByAltKey[alt]
-			summary.Changes = append(summary.Changes, obj.toPlanChange(PlanReplace))
+			ch := obj.toPlanChange(PlanReplace)
+			ch.Risks = ClassifyRisks(prevByAltKey[alt].Normalized, obj.Normalized)
+			summary.Changes = append(summary.Changes, ch)
 		}
 	}
 
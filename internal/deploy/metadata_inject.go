@@ -0,0 +1,260 @@
+// File: internal/deploy/metadata_inject.go
+// Brief: Internal deploy package implementation for 'metadata inject'.
+
+// metadata_inject.go implements the built-in inject post-renderer: required labels, annotations,
+// and namespaces added to every rendered object matching a rule, in place of a mutating webhook.
+package deploy
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// clusterScopedKinds never receive an injected namespace. The list is only consulted when no
+// RESTMapper is available or the cluster does not know the kind; see metadataInjector.clusterScoped.
+var clusterScopedKinds = map[string]bool{
+	"apiservice":                       true,
+	"certificatesigningrequest":        true,
+	"clusterissuer":                    true,
+	"clusterrole":                      true,
+	"clusterrolebinding":               true,
+	"clustertrustbundle":               true,
+	"csidriver":                        true,
+	"csinode":                          true,
+	"customresourcedefinition":         true,
+	"flowschema":                       true,
+	"ingressclass":                     true,
+	"mutatingadmissionpolicy":          true,
+	"mutatingadmissionpolicybinding":   true,
+	"mutatingwebhookconfiguration":     true,
+	"namespace":                        true,
+	"node":                             true,
+	"persistentvolume":                 true,
+	"priorityclass":                    true,
+	"prioritylevelconfiguration":       true,
+	"runtimeclass":                     true,
+	"storageclass":                     true,
+	"validatingadmissionpolicy":        true,
+	"validatingadmissionpolicybinding": true,
+	"validatingwebhookconfiguration":   true,
+	"volumeattachment":                 true,
+}
+
+type injectRule struct {
+	appconfig.InjectRule
+	kinds    map[string]bool
+	selector labels.Selector
+}
+
+type metadataInjector struct {
+	rules []injectRule
+	// mapper, when set, decides which kinds are cluster-scoped. It is dropped after the first
+	// discovery error so offline renders fall back to clusterScopedKinds without retrying.
+	mapper meta.RESTMapper
+
+	mu     sync.Mutex
+	scopes map[schema.GroupKind]bool
+}
+
+func newMetadataInjector(rules []appconfig.InjectRule, mapper meta.RESTMapper) (*metadataInjector, error) {
+	out := &metadataInjector{mapper: mapper, scopes: map[schema.GroupKind]bool{}}
+	for i, r := range rules {
+		if len(r.Labels) == 0 && len(r.Annotations) == 0 && strings.TrimSpace(r.Namespace) == "" {
+			return nil, fmt.Errorf("inject[%d]: set labels, annotations, or namespace", i)
+		}
+		rule := injectRule{InjectRule: r, kinds: map[string]bool{}, selector: labels.Everything()}
+		for _, k := range r.Kinds {
+			if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+				rule.kinds[k] = true
+			}
+		}
+		if sel := strings.TrimSpace(r.Selector); sel != "" {
+			parsed, err := labels.Parse(sel)
+			if err != nil {
+				return nil, fmt.Errorf("inject[%d].selector: %w", i, err)
+			}
+			rule.selector = parsed
+		}
+		out.rules = append(out.rules, rule)
+	}
+	return out, nil
+}
+
+// Run implements postrender.PostRenderer.
+func (m *metadataInjector) Run(in *bytes.Buffer) (*bytes.Buffer, error) {
+	docs := splitYAMLDocs(in.String())
+	objs := make([]map[string]any, len(docs))
+	// CRDs shipped in the same manifest are not known to the cluster yet; take their scope
+	// from the definition.
+	crdScopes := map[schema.GroupKind]bool{}
+	for i, doc := range docs {
+		var obj map[string]any
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj == nil {
+			continue
+		}
+		objs[i] = obj
+		if gk, cluster, ok := crdScope(obj); ok {
+			crdScopes[gk] = cluster
+		}
+	}
+	var out bytes.Buffer
+	for i, doc := range docs {
+		if obj := objs[i]; obj != nil && m.inject(obj, crdScopes) {
+			next, err := reencodeDoc(doc, obj)
+			if err != nil {
+				return nil, err
+			}
+			doc = next
+		}
+		fmt.Fprintf(&out, "---\n%s\n", strings.TrimSpace(doc))
+	}
+	return &out, nil
+}
+
+// inject applies every matching rule to obj and reports whether anything changed. Rules are
+// matched against the labels the chart rendered, not ones injected by earlier rules.
+func (m *metadataInjector) inject(obj map[string]any, crdScopes map[schema.GroupKind]bool) bool {
+	kind, _ := obj["kind"].(string)
+	if kind == "" {
+		return false
+	}
+	meta := childMap(obj, "metadata")
+	current := labels.Set(stringMap(meta["labels"]))
+	changed := false
+	for _, r := range m.rules {
+		if len(r.kinds) > 0 && !r.kinds[strings.ToLower(kind)] {
+			continue
+		}
+		if !r.selector.Matches(current) {
+			continue
+		}
+		changed = mergeStrings(meta, "labels", r.Labels, r.Overwrite) || changed
+		changed = mergeStrings(meta, "annotations", r.Annotations, r.Overwrite) || changed
+		if ns := strings.TrimSpace(r.Namespace); ns != "" && !m.clusterScoped(obj, crdScopes) {
+			if existing, _ := meta["namespace"].(string); existing == "" || (r.Overwrite && existing != ns) {
+				meta["namespace"] = ns
+				changed = true
+			}
+		}
+		if r.PodTemplate {
+			if tpl := podTemplateMeta(obj); tpl != nil {
+				changed = mergeStrings(tpl, "labels", r.Labels, r.Overwrite) || changed
+				changed = mergeStrings(tpl, "annotations", r.Annotations, r.Overwrite) || changed
+			}
+		}
+	}
+	return changed
+}
+
+// clusterScoped reports whether obj's kind is cluster-scoped: from a CRD in the same manifest,
+// then the RESTMapper, then clusterScopedKinds for kinds neither knows.
+func (m *metadataInjector) clusterScoped(obj map[string]any, crdScopes map[schema.GroupKind]bool) bool {
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return clusterScopedKinds[strings.ToLower(kind)]
+	}
+	gk := schema.GroupKind{Group: gv.Group, Kind: kind}
+	if cluster, ok := crdScopes[gk]; ok {
+		return cluster
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cluster, ok := m.scopes[gk]; ok {
+		return cluster
+	}
+	cluster := clusterScopedKinds[strings.ToLower(kind)]
+	if m.mapper != nil {
+		mapping, err := m.mapper.RESTMapping(gk, gv.Version)
+		switch {
+		case err == nil:
+			cluster = mapping.Scope.Name() == meta.RESTScopeNameRoot
+		case !meta.IsNoMatchError(err):
+			m.mapper = nil
+		}
+	}
+	m.scopes[gk] = cluster
+	return cluster
+}
+
+// crdScope returns the group/kind a CustomResourceDefinition defines and whether it is
+// cluster-scoped.
+func crdScope(obj map[string]any) (schema.GroupKind, bool, bool) {
+	if kind, _ := obj["kind"].(string); kind != "CustomResourceDefinition" {
+		return schema.GroupKind{}, false, false
+	}
+	spec, _ := obj["spec"].(map[string]any)
+	names, _ := spec["names"].(map[string]any)
+	group, _ := spec["group"].(string)
+	kind, _ := names["kind"].(string)
+	scope, _ := spec["scope"].(string)
+	if group == "" || kind == "" {
+		return schema.GroupKind{}, false, false
+	}
+	return schema.GroupKind{Group: group, Kind: kind}, strings.EqualFold(scope, "Cluster"), true
+}
+
+// podTemplateMeta returns the pod template metadata of workloads (spec.template) and CronJobs
+// (spec.jobTemplate.spec.template), creating it when the chart left it out.
+func podTemplateMeta(obj map[string]any) map[string]any {
+	spec, ok := obj["spec"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	if jt, ok := spec["jobTemplate"].(map[string]any); ok {
+		if spec, ok = jt["spec"].(map[string]any); !ok {
+			return nil
+		}
+	}
+	tpl, ok := spec["template"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	return childMap(tpl, "metadata")
+}
+
+func childMap(parent map[string]any, key string) map[string]any {
+	child, ok := parent[key].(map[string]any)
+	if !ok {
+		child = map[string]any{}
+		parent[key] = child
+	}
+	return child
+}
+
+func stringMap(v any) map[string]string {
+	out := map[string]string{}
+	if m, ok := v.(map[string]any); ok {
+		for k, val := range m {
+			if s, ok := val.(string); ok {
+				out[k] = s
+			}
+		}
+	}
+	return out
+}
+
+func mergeStrings(meta map[string]any, key string, values map[string]string, overwrite bool) bool {
+	if len(values) == 0 {
+		return false
+	}
+	dst := childMap(meta, key)
+	changed := false
+	for k, v := range values {
+		if existing, ok := dst[k]; ok && (!overwrite || existing == v) {
+			continue
+		}
+		dst[k] = v
+		changed = true
+	}
+	return changed
+}
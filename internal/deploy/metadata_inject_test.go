@@ -0,0 +1,151 @@
+package deploy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+const injectInput = `# Source: demo/templates/web.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+    team: legacy
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: nginx
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: web-reader
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  labels:
+    app: other
+`
+
+func TestMetadataInjector(t *testing.T) {
+	pr, err := BuildPostRenderer(context.Background(), []appconfig.PostRendererConfig{{Name: "ownership", Inject: []appconfig.InjectRule{
+		{Labels: map[string]string{"team": "payments", "cost-center": "cc-42"}, Namespace: "prod"},
+		{Kinds: []string{"deployment"}, Selector: "app=web", PodTemplate: true, Annotations: map[string]string{"sidecar.istio.io/inject": "true"}},
+	}}}, nil)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	out, err := PostRenderManifest(pr, injectInput)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !strings.Contains(out, "# Source: demo/templates/web.yaml") {
+		t.Fatalf("lost source comment:\n%s", out)
+	}
+	docs := splitYAMLDocs(out)
+	if len(docs) != 3 {
+		t.Fatalf("docs=%d:\n%s", len(docs), out)
+	}
+	var workload, role, cm map[string]any
+	for i, dst := range []*map[string]any{&workload, &role, &cm} {
+		if err := yaml.Unmarshal([]byte(docs[i]), dst); err != nil {
+			t.Fatal(err)
+		}
+	}
+	meta := workload["metadata"].(map[string]any)
+	labels := meta["labels"].(map[string]any)
+	if labels["team"] != "legacy" || labels["cost-center"] != "cc-42" || meta["namespace"] != "prod" {
+		t.Fatalf("deployment metadata=%v", meta)
+	}
+	tpl := workload["spec"].(map[string]any)["template"].(map[string]any)["metadata"].(map[string]any)
+	if tpl["annotations"].(map[string]any)["sidecar.istio.io/inject"] != "true" {
+		t.Fatalf("pod template metadata=%v", tpl)
+	}
+	if _, ok := role["metadata"].(map[string]any)["namespace"]; ok {
+		t.Fatalf("cluster-scoped object got a namespace: %v", role["metadata"])
+	}
+	if _, ok := cm["metadata"].(map[string]any)["annotations"]; ok {
+		t.Fatalf("selector should not match other: %v", cm["metadata"])
+	}
+}
+
+func TestMetadataInjectorClusterScope(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "CSINode"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Tenant"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	manifest := `apiVersion: storage.k8s.io/v1
+kind: CSINode
+metadata:
+  name: node-a
+---
+apiVersion: example.com/v1
+kind: Tenant
+metadata:
+  name: acme
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: gadgets.example.com
+spec:
+  group: example.com
+  scope: Cluster
+  names:
+    kind: Gadget
+---
+apiVersion: example.com/v1
+kind: Gadget
+metadata:
+  name: g
+---
+apiVersion: flowcontrol.apiserver.k8s.io/v1
+kind: FlowSchema
+metadata:
+  name: fs
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`
+	pr, err := BuildPostRenderer(context.Background(), []appconfig.PostRendererConfig{{Inject: []appconfig.InjectRule{{Namespace: "prod"}}}}, mapper)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	out, err := PostRenderManifest(pr, manifest)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for _, doc := range splitYAMLDocs(out) {
+		var obj map[string]any
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			t.Fatal(err)
+		}
+		ns, _ := obj["metadata"].(map[string]any)["namespace"].(string)
+		if want := map[string]string{"ConfigMap": "prod"}[obj["kind"].(string)]; ns != want {
+			t.Fatalf("%s: namespace %q, want %q", obj["kind"], ns, want)
+		}
+	}
+}
+
+func TestMetadataInjector_Validation(t *testing.T) {
+	if _, err := newMetadataInjector([]appconfig.InjectRule{{Kinds: []string{"Deployment"}}}, nil); err == nil {
+		t.Fatalf("expected rule without metadata to fail")
+	}
+	if _, err := newMetadataInjector([]appconfig.InjectRule{{Labels: map[string]string{"a": "b"}, Selector: "a in ("}}, nil); err == nil {
+		t.Fatalf("expected bad selector to fail")
+	}
+}
@@ -0,0 +1,121 @@
+// File: internal/deploy/owners.go
+// Brief: Internal deploy package implementation for 'owners'.
+
+// owners.go records who owns a release (team, Slack channel, on-call) so on-call can find the
+// owners of a broken release with ktl owners or ktl list --owner.
+package deploy
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+const (
+	// OwnerTeamLabel is the sanitized team, as a release label for label selectors.
+	OwnerTeamLabel = "ktl.dev/owner-team"
+
+	// The owner annotations are stored on the release's storage object (the Secret or ConfigMap
+	// holding the revision), which keeps the values verbatim (Slack channels and on-call URLs are
+	// not valid label values) and keeps them out of the chart.
+	OwnerTeamAnnotation   = "ktl.dev/owner-team"
+	OwnerSlackAnnotation  = "ktl.dev/owner-slack"
+	OwnerOncallAnnotation = "ktl.dev/owner-oncall"
+)
+
+// ReleaseOwner is the team that owns a release and how to reach it.
+type ReleaseOwner struct {
+	Team   string `json:"team,omitempty"`
+	Slack  string `json:"slack,omitempty"`
+	Oncall string `json:"oncall,omitempty"`
+}
+
+// Empty reports whether o names no owner (nil-safe).
+func (o *ReleaseOwner) Empty() bool {
+	return o == nil || (strings.TrimSpace(o.Team) == "" && strings.TrimSpace(o.Slack) == "" && strings.TrimSpace(o.Oncall) == "")
+}
+
+// ReleaseLabels returns the owner labels for the release.
+func (o *ReleaseOwner) ReleaseLabels() map[string]string {
+	if o.Empty() {
+		return nil
+	}
+	if team := sanitizeLabelValue(o.Team); team != "" {
+		return map[string]string{OwnerTeamLabel: team}
+	}
+	return nil
+}
+
+func (o *ReleaseOwner) annotations() map[string]string {
+	out := map[string]string{}
+	if o.Empty() {
+		return out
+	}
+	for key, v := range map[string]string{OwnerTeamAnnotation: o.Team, OwnerSlackAnnotation: o.Slack, OwnerOncallAnnotation: o.Oncall} {
+		if v = strings.TrimSpace(v); v != "" {
+			out[key] = v
+		}
+	}
+	return out
+}
+
+// MatchesTeam reports whether the release is owned by team (case-insensitive).
+func (o *ReleaseOwner) MatchesTeam(team string) bool {
+	team = strings.TrimSpace(team)
+	if o.Empty() || team == "" {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(o.Team), team) || strings.EqualFold(sanitizeLabelValue(o.Team), sanitizeLabelValue(team))
+}
+
+// ReleaseOwnerOf reads the owner recorded on rel from annotations, the annotations of its storage
+// object (see StoredReleaseAnnotations), falling back to the team label for releases whose storage
+// object carries none. It returns nil when no owner was recorded.
+func ReleaseOwnerOf(rel *release.Release, annotations map[string]string) *ReleaseOwner {
+	if rel == nil {
+		return nil
+	}
+	owner := &ReleaseOwner{
+		Team:   annotations[OwnerTeamAnnotation],
+		Slack:  annotations[OwnerSlackAnnotation],
+		Oncall: annotations[OwnerOncallAnnotation],
+	}
+	if owner.Team == "" {
+		owner.Team = rel.Labels[OwnerTeamLabel]
+	}
+	if owner.Empty() {
+		return nil
+	}
+	return owner
+}
+
+// MatchReleaseOwner returns the owner of the first deploy.owners rule in .ktl.yaml that selects
+// the release in namespace, or nil. Empty pattern lists select everything.
+func MatchReleaseOwner(rules []appconfig.ReleaseOwnerRule, releaseName, namespace string) *ReleaseOwner {
+	for _, r := range rules {
+		if !globAny(r.Releases, releaseName) || !globAny(r.Namespaces, namespace) {
+			continue
+		}
+		owner := &ReleaseOwner{Team: strings.TrimSpace(r.Team), Slack: strings.TrimSpace(r.Slack), Oncall: strings.TrimSpace(r.Oncall)}
+		if !owner.Empty() {
+			return owner
+		}
+	}
+	return nil
+}
+
+func mergeReleaseLabels(sets ...map[string]string) map[string]string {
+	var out map[string]string
+	for _, s := range sets {
+		if len(s) == 0 {
+			continue
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		maps.Copy(out, s)
+	}
+	return out
+}
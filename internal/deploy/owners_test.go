@@ -0,0 +1,138 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/metadata"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestMatchReleaseOwner(t *testing.T) {
+	rules := []appconfig.ReleaseOwnerRule{
+		{Releases: []string{"payments-*"}, Namespaces: []string{"prod"}, Team: "Team Payments", Slack: "#payments-oncall", Oncall: "https://pager.example.com/payments"},
+		{Releases: []string{"payments-*"}},
+		{Team: "platform"},
+	}
+	if got := MatchReleaseOwner(rules, "payments-api", "prod"); got == nil || got.Slack != "#payments-oncall" {
+		t.Fatalf("expected the payments owner, got %+v", got)
+	}
+	if got := MatchReleaseOwner(rules, "payments-api", "staging"); got == nil || got.Team != "platform" {
+		t.Fatalf("expected rules without an owner to be skipped, got %+v", got)
+	}
+	if got := MatchReleaseOwner(rules[:2], "search", "prod"); got != nil {
+		t.Fatalf("expected no owner, got %+v", got)
+	}
+
+	owner := &ReleaseOwner{Team: "Team Payments", Slack: "#payments-oncall"}
+	if labels := owner.ReleaseLabels(); labels[OwnerTeamLabel] != "Team-Payments" {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+	if !owner.MatchesTeam("team payments") || !owner.MatchesTeam("Team-Payments") || owner.MatchesTeam("search") {
+		t.Fatalf("unexpected team matching for %+v", owner)
+	}
+
+	annotations := map[string]string{OwnerTeamAnnotation: "Team Payments", OwnerSlackAnnotation: "#payments-oncall"}
+	if got := ReleaseOwnerOf(&release.Release{}, annotations); got == nil || *got != *owner {
+		t.Fatalf("expected %+v, got %+v", owner, got)
+	}
+	if got := ReleaseOwnerOf(&release.Release{Labels: map[string]string{OwnerTeamLabel: "search"}}, nil); got == nil || got.Team != "search" {
+		t.Fatalf("expected the team label fallback, got %+v", got)
+	}
+	if got := ReleaseOwnerOf(&release.Release{}, nil); got != nil {
+		t.Fatalf("expected no owner, got %+v", got)
+	}
+}
+
+func TestInstallOrUpgradeRecordsOwner(t *testing.T) {
+	dir := t.TempDir()
+	chartDir := filepath.Join(dir, "api")
+	if err := os.MkdirAll(filepath.Join(chartDir, "templates"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, body := range map[string]string{
+		"Chart.yaml":               "apiVersion: v2\nname: api\nversion: 0.1.0\n",
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: api\n",
+	} {
+		if err := os.WriteFile(filepath.Join(chartDir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Secret storage with a fake metadata client that sees the Secrets Helm writes, so the owner
+	// annotations land on the storage object as they would in a cluster.
+	secrets := k8sfake.NewSimpleClientset().CoreV1().Secrets("prod")
+	meta := metadatafake.NewSimpleMetadataClient(metadatafake.NewTestScheme())
+	meta.PrependReactor("patch", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.PatchAction).GetName()
+		if _, err := meta.Tracker().Get(action.GetResource(), "prod", name); err != nil {
+			obj := &metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}, ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "prod"}}
+			if err := meta.Tracker().Create(action.GetResource(), obj, "prod"); err != nil {
+				return true, nil, err
+			}
+		}
+		return false, nil, nil
+	})
+	restore := newReleaseStorageClient
+	newReleaseStorageClient = func(*action.Configuration) (metadata.Interface, error) { return meta, nil }
+	t.Cleanup(func() { newReleaseStorageClient = restore })
+	cfg := &action.Configuration{
+		Releases:     storage.Init(driver.NewSecrets(secrets)),
+		KubeClient:   &kubefake.PrintingKubeClient{},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          func(string, ...interface{}) {},
+	}
+	apply := func(owner *ReleaseOwner) (*release.Release, *ReleaseOwner) {
+		t.Helper()
+		res, err := InstallOrUpgrade(context.Background(), cfg, cli.New(), InstallOptions{
+			Chart:       chartDir,
+			ReleaseName: "api",
+			Namespace:   "prod",
+			Timeout:     time.Minute,
+			Owner:       owner,
+		})
+		if err != nil {
+			t.Fatalf("apply: %v", err)
+		}
+		stored, err := cfg.Releases.Get("api", res.Release.Version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Chart != nil && stored.Chart.Metadata != nil && len(stored.Chart.Metadata.Annotations) > 0 {
+			t.Fatalf("expected the chart to stay free of owner annotations: %v", stored.Chart.Metadata.Annotations)
+		}
+		annotations, err := StoredReleaseAnnotations(context.Background(), cfg, stored)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stored, ReleaseOwnerOf(stored, annotations)
+	}
+
+	payments := &ReleaseOwner{Team: "payments", Slack: "#payments-oncall", Oncall: "https://pager.example.com/payments"}
+	rel, got := apply(payments)
+	if got == nil || *got != *payments || rel.Labels[OwnerTeamLabel] != "payments" {
+		t.Fatalf("expected the owner on revision 1, got %+v (labels %v)", got, rel.Labels)
+	}
+	rel, got = apply(nil)
+	if rel.Version != 2 || got == nil || *got != *payments {
+		t.Fatalf("expected revision 2 to keep the owner, got %+v", got)
+	}
+	rel, got = apply(&ReleaseOwner{Team: "search"})
+	if got == nil || got.Team != "search" || got.Slack != "" || rel.Labels[OwnerTeamLabel] != "search" {
+		t.Fatalf("expected the new owner to replace the old one, got %+v (labels %v)", got, rel.Labels)
+	}
+}
@@ -0,0 +1,344 @@
+// File: internal/deploy/plan_risk.go
+// Brief: Internal deploy package implementation for 'plan risk'.
+
+// plan_risk.go tags plan changes that deserve a second look before they are applied, such as
+// image changes, replica decreases, or a loosened securityContext.
+package deploy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RiskTag names a class of risky change. Tags are stable identifiers that policy gates (for
+// example a stack release's apply.requireApproval) key off.
+type RiskTag string
+
+const (
+	RiskImageChange             RiskTag = "image-change"
+	RiskReplicaDecrease         RiskTag = "replica-decrease"
+	RiskProbeRemoval            RiskTag = "probe-removal"
+	RiskSecurityContextLoosened RiskTag = "security-context-loosened"
+	RiskPDBDeletion             RiskTag = "pdb-deletion"
+	RiskStorageClassChange      RiskTag = "storage-class-change"
+)
+
+// RiskTags lists every tag in display order.
+var RiskTags = []RiskTag{
+	RiskImageChange,
+	RiskReplicaDecrease,
+	RiskProbeRemoval,
+	RiskSecurityContextLoosened,
+	RiskPDBDeletion,
+	RiskStorageClassChange,
+}
+
+// ParseRiskTag validates a tag name (case-insensitive).
+func ParseRiskTag(raw string) (RiskTag, error) {
+	v := strings.ToLower(strings.TrimSpace(raw))
+	for _, tag := range RiskTags {
+		if string(tag) == v {
+			return tag, nil
+		}
+	}
+	names := make([]string, 0, len(RiskTags))
+	for _, tag := range RiskTags {
+		names = append(names, string(tag))
+	}
+	return "", fmt.Errorf("unknown risk tag %q (expected one of %s)", raw, strings.Join(names, ", "))
+}
+
+// PlanRisk is one risky aspect of a change.
+type PlanRisk struct {
+	Tag    RiskTag `json:"tag"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// ClassifyRisks returns the risks of changing prev into next. prev is nil for creations and
+// next is nil for deletions. Creations are compared against an empty pod spec, so only settings
+// that are risky on their own (privileged containers, host namespaces, added capabilities) are
+// tagged.
+func ClassifyRisks(prev, next *unstructured.Unstructured) []PlanRisk {
+	var out []PlanRisk
+	if prev == nil {
+		if next == nil {
+			return nil
+		}
+		if nextPod := riskPodSpec(next.Object); nextPod != nil {
+			out = securityRisks(map[string]any{}, nextPod)
+		}
+		return out
+	}
+	kind := prev.GetKind()
+	if next == nil {
+		if kind == "PodDisruptionBudget" {
+			out = append(out, PlanRisk{Tag: RiskPDBDeletion, Detail: "removes disruption safeguards"})
+		}
+		return out
+	}
+
+	if before, ok := nestedInt(prev.Object, "spec", "replicas"); ok {
+		if after, ok := nestedInt(next.Object, "spec", "replicas"); ok && after < before {
+			out = append(out, PlanRisk{Tag: RiskReplicaDecrease, Detail: fmt.Sprintf("replicas %d -> %d", before, after)})
+		}
+	}
+
+	switch kind {
+	case "PersistentVolumeClaim":
+		if d := storageClassChange(prev.Object, next.Object, "spec"); d != "" {
+			out = append(out, PlanRisk{Tag: RiskStorageClassChange, Detail: d})
+		}
+	case "StatefulSet":
+		prevTpl := namedItems(prev.Object, "spec", "volumeClaimTemplates")
+		nextTpl := namedItems(next.Object, "spec", "volumeClaimTemplates")
+		for _, name := range sortedKeys(prevTpl) {
+			if after, ok := nextTpl[name]; ok {
+				if d := storageClassChange(prevTpl[name], after, "spec"); d != "" {
+					out = append(out, PlanRisk{Tag: RiskStorageClassChange, Detail: "volumeClaimTemplate " + name + ": " + d})
+				}
+			}
+		}
+	}
+
+	prevPod, nextPod := riskPodSpec(prev.Object), riskPodSpec(next.Object)
+	if prevPod == nil || nextPod == nil {
+		return out
+	}
+	prevContainers, nextContainers := podContainers(prevPod), podContainers(nextPod)
+	for _, name := range sortedKeys(nextContainers) {
+		after := nextContainers[name]
+		before, ok := prevContainers[name]
+		afterImage, _ := after["image"].(string)
+		if !ok {
+			out = append(out, PlanRisk{Tag: RiskImageChange, Detail: fmt.Sprintf("container %s added (%s)", name, afterImage)})
+			continue
+		}
+		if beforeImage, _ := before["image"].(string); beforeImage != afterImage {
+			out = append(out, PlanRisk{Tag: RiskImageChange, Detail: fmt.Sprintf("container %s: %s -> %s", name, beforeImage, afterImage)})
+		}
+	}
+	for _, name := range sortedKeys(prevContainers) {
+		after, ok := nextContainers[name]
+		if !ok {
+			continue
+		}
+		before := prevContainers[name]
+		for _, probe := range []string{"livenessProbe", "readinessProbe", "startupProbe"} {
+			if before[probe] != nil && after[probe] == nil {
+				out = append(out, PlanRisk{Tag: RiskProbeRemoval, Detail: fmt.Sprintf("container %s: %s removed", name, probe)})
+			}
+		}
+	}
+	return append(out, securityRisks(prevPod, nextPod)...)
+}
+
+// securityRisks tags securityContext and host-namespace settings of nextPod that are less
+// restrictive than prevPod's. Containers new in nextPod are compared against an empty one.
+func securityRisks(prevPod, nextPod map[string]any) []PlanRisk {
+	var out []PlanRisk
+	prevContainers, nextContainers := podContainers(prevPod), podContainers(nextPod)
+	for _, name := range sortedKeys(nextContainers) {
+		for _, d := range containerSecurityLoosened(prevContainers[name], nextContainers[name]) {
+			out = append(out, PlanRisk{Tag: RiskSecurityContextLoosened, Detail: "container " + name + ": " + d})
+		}
+	}
+	for _, d := range podSecurityLoosened(prevPod, nextPod) {
+		out = append(out, PlanRisk{Tag: RiskSecurityContextLoosened, Detail: d})
+	}
+	return out
+}
+
+// RiskyChanges returns the changes, hooks included, that carry at least one risk.
+func (s *PlanSummary) RiskyChanges() []PlanChange {
+	if s == nil {
+		return nil
+	}
+	var out []PlanChange
+	for _, list := range [][]PlanChange{s.Changes, s.Hooks.Changes} {
+		for _, ch := range list {
+			if len(ch.Risks) > 0 {
+				out = append(out, ch)
+			}
+		}
+	}
+	return out
+}
+
+// RiskTagsPresent returns the distinct risk tags of the summary in RiskTags order.
+func (s *PlanSummary) RiskTagsPresent() []RiskTag {
+	seen := map[RiskTag]bool{}
+	for _, ch := range s.RiskyChanges() {
+		for _, r := range ch.Risks {
+			seen[r.Tag] = true
+		}
+	}
+	var out []RiskTag
+	for _, tag := range RiskTags {
+		if seen[tag] {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// riskPodSpec returns the pod spec of Pods, workloads (spec.template.spec), and CronJobs
+// (spec.jobTemplate.spec.template.spec).
+func riskPodSpec(obj map[string]any) map[string]any {
+	spec, ok := obj["spec"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	if kind, _ := obj["kind"].(string); kind == "Pod" {
+		return spec
+	}
+	if jt, ok := spec["jobTemplate"].(map[string]any); ok {
+		if spec, ok = jt["spec"].(map[string]any); !ok {
+			return nil
+		}
+	}
+	tpl, ok := spec["template"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	podSpec, _ := tpl["spec"].(map[string]any)
+	return podSpec
+}
+
+// podContainers indexes containers and init containers by name (init containers as init:<name>).
+func podContainers(podSpec map[string]any) map[string]map[string]any {
+	out := namedItems(podSpec, "containers")
+	for name, c := range namedItems(podSpec, "initContainers") {
+		out["init:"+name] = c
+	}
+	return out
+}
+
+func namedItems(obj map[string]any, fields ...string) map[string]map[string]any {
+	out := map[string]map[string]any{}
+	list, _, _ := unstructured.NestedSlice(obj, fields...)
+	for _, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if name, _ := m["name"].(string); name != "" {
+			out[name] = m
+		} else if meta, ok := m["metadata"].(map[string]any); ok {
+			if name, _ := meta["name"].(string); name != "" {
+				out[name] = m
+			}
+		}
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func storageClassChange(prev, next map[string]any, fields ...string) string {
+	path := append(append([]string{}, fields...), "storageClassName")
+	before, _, _ := unstructured.NestedString(prev, path...)
+	after, _, _ := unstructured.NestedString(next, path...)
+	if before == after {
+		return ""
+	}
+	return fmt.Sprintf("storageClassName %s -> %s", orNone(before), orNone(after))
+}
+
+// containerSecurityLoosened lists securityContext settings of a container that became less
+// restrictive.
+func containerSecurityLoosened(before, after map[string]any) []string {
+	prev, _ := before["securityContext"].(map[string]any)
+	next, _ := after["securityContext"].(map[string]any)
+	var out []string
+	if boolField(prev, "runAsNonRoot") && !boolField(next, "runAsNonRoot") {
+		out = append(out, "runAsNonRoot no longer true")
+	}
+	if boolField(prev, "readOnlyRootFilesystem") && !boolField(next, "readOnlyRootFilesystem") {
+		out = append(out, "readOnlyRootFilesystem no longer true")
+	}
+	if !boolField(prev, "privileged") && boolField(next, "privileged") {
+		out = append(out, "privileged enabled")
+	}
+	if v, ok := prev["allowPrivilegeEscalation"].(bool); ok && !v {
+		if w, ok := next["allowPrivilegeEscalation"].(bool); !ok || w {
+			out = append(out, "allowPrivilegeEscalation no longer false")
+		}
+	}
+	prevAdd := stringSet(prev, "capabilities", "add")
+	for _, c := range sortedKeys(stringSet(next, "capabilities", "add")) {
+		if !prevAdd[c] {
+			out = append(out, "capability "+c+" added")
+		}
+	}
+	prevDrop := stringSet(prev, "capabilities", "drop")
+	nextDrop := stringSet(next, "capabilities", "drop")
+	for _, c := range sortedKeys(prevDrop) {
+		if !nextDrop[c] {
+			out = append(out, "capability "+c+" no longer dropped")
+		}
+	}
+	return out
+}
+
+// podSecurityLoosened lists pod-level settings that became less restrictive.
+func podSecurityLoosened(prevPod, nextPod map[string]any) []string {
+	var out []string
+	for _, field := range []string{"hostNetwork", "hostPID", "hostIPC"} {
+		if !boolField(prevPod, field) && boolField(nextPod, field) {
+			out = append(out, field+" enabled")
+		}
+	}
+	prev, _ := prevPod["securityContext"].(map[string]any)
+	next, _ := nextPod["securityContext"].(map[string]any)
+	if boolField(prev, "runAsNonRoot") && !boolField(next, "runAsNonRoot") {
+		out = append(out, "pod runAsNonRoot no longer true")
+	}
+	return out
+}
+
+func boolField(m map[string]any, key string) bool {
+	v, _ := m[key].(bool)
+	return v
+}
+
+func stringSet(m map[string]any, fields ...string) map[string]bool {
+	out := map[string]bool{}
+	list, _, _ := unstructured.NestedStringSlice(m, fields...)
+	for _, v := range list {
+		out[v] = true
+	}
+	return out
+}
+
+func nestedInt(obj map[string]any, fields ...string) (int64, bool) {
+	v, found, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	if !found {
+		return 0, false
+	}
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case float64:
+		return int64(n), true
+	}
+	return 0, false
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(default)"
+	}
+	return s
+}
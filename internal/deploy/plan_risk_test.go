@@ -0,0 +1,167 @@
+package deploy
+
+import (
+	"reflect"
+	"testing"
+)
+
+const riskPrev = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: prod
+spec:
+  replicas: 4
+  template:
+    spec:
+      containers:
+        - name: api
+          image: ghcr.io/acme/api:v1
+          readinessProbe:
+            httpGet: {path: /ready, port: 8080}
+          securityContext:
+            runAsNonRoot: true
+            allowPrivilegeEscalation: false
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: api
+  namespace: prod
+spec:
+  minAvailable: 1
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  namespace: prod
+spec:
+  storageClassName: fast
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: prod
+data:
+  a: "1"
+`
+
+const riskNext = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: prod
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: api
+          image: ghcr.io/acme/api:v2
+          securityContext:
+            privileged: true
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  namespace: prod
+spec:
+  storageClassName: slow
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: prod
+data:
+  a: "2"
+`
+
+func TestSummarizeManifestPlanTagsRisks(t *testing.T) {
+	summary, err := SummarizeManifestPlan(riskPrev, riskNext)
+	if err != nil {
+		t.Fatalf("summarize: %v", err)
+	}
+	got := map[string][]RiskTag{}
+	for _, ch := range summary.RiskyChanges() {
+		for _, r := range ch.Risks {
+			got[ch.Kind] = append(got[ch.Kind], r.Tag)
+		}
+	}
+	want := map[string][]RiskTag{
+		"Deployment": {
+			RiskReplicaDecrease,
+			RiskImageChange,
+			RiskProbeRemoval,
+			RiskSecurityContextLoosened,
+			RiskSecurityContextLoosened,
+			RiskSecurityContextLoosened,
+		},
+		"PodDisruptionBudget":   {RiskPDBDeletion},
+		"PersistentVolumeClaim": {RiskStorageClassChange},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("risks = %v", got)
+	}
+	if tags := summary.RiskTagsPresent(); len(tags) != len(RiskTags) {
+		t.Fatalf("tags = %v", tags)
+	}
+}
+
+func TestSummarizeManifestPlanTagsRiskyCreations(t *testing.T) {
+	next := `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+  namespace: ops
+spec:
+  template:
+    spec:
+      hostNetwork: true
+      containers:
+        - name: agent
+          image: ghcr.io/acme/agent:v1
+          securityContext:
+            privileged: true
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: ops
+spec:
+  template:
+    spec:
+      containers:
+        - name: web
+          image: ghcr.io/acme/web:v1
+`
+	summary, err := SummarizeManifestPlan("", next)
+	if err != nil {
+		t.Fatalf("summarize: %v", err)
+	}
+	risky := summary.RiskyChanges()
+	if len(risky) != 1 || risky[0].Name != "agent" || len(risky[0].Risks) != 2 {
+		t.Fatalf("expected only the privileged, host-network DaemonSet to be tagged, got %+v", risky)
+	}
+	for _, r := range risky[0].Risks {
+		if r.Tag != RiskSecurityContextLoosened {
+			t.Fatalf("unexpected risk %+v", r)
+		}
+	}
+}
+
+func TestParseRiskTag(t *testing.T) {
+	if tag, err := ParseRiskTag(" Image-Change "); err != nil || tag != RiskImageChange {
+		t.Fatalf("tag = %q, err = %v", tag, err)
+	}
+	if _, err := ParseRiskTag("scary"); err == nil {
+		t.Fatalf("expected an error for an unknown tag")
+	}
+}
@@ -0,0 +1,169 @@
+// File: internal/deploy/post_render.go
+// Brief: Internal deploy package implementation for 'post render'.
+
+// post_render.go builds the Helm post-renderer pipeline from the deploy.postRenderers section of
+// .ktl.yaml: executables run like helm --post-renderer, while kustomize patches, image rewrites,
+// and metadata injection run in-process. Template, plan, and apply all use the same pipeline so the preview
+// matches what ships.
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/postrender"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// BuildPostRenderer returns a post-renderer running each configured step in order, or nil when
+// none are configured. ctx bounds the registry lookups made by images steps that pin digests.
+// mapper, when non-nil, tells inject steps which kinds are cluster-scoped.
+func BuildPostRenderer(ctx context.Context, steps []appconfig.PostRendererConfig, mapper meta.RESTMapper) (postrender.PostRenderer, error) {
+	var chain postRenderChain
+	for i, step := range steps {
+		label := strings.TrimSpace(step.Name)
+		if label == "" {
+			label = fmt.Sprintf("postRenderers[%d]", i)
+		}
+		exec := strings.TrimSpace(step.Exec)
+		kinds := 0
+		for _, set := range []bool{exec != "", len(step.Patches) > 0, step.Images != nil, len(step.Inject) > 0} {
+			if set {
+				kinds++
+			}
+		}
+		if kinds != 1 {
+			return nil, fmt.Errorf("%s: set exactly one of exec, patches, images, or inject", label)
+		}
+		var pr postrender.PostRenderer
+		var err error
+		switch {
+		case exec != "":
+			pr, err = postrender.NewExec(exec, step.Args...)
+		case len(step.Patches) > 0:
+			pr, err = newKustomizePostRenderer(step.Patches)
+		case step.Images != nil:
+			pr, err = newImageRewriter(ctx, *step.Images)
+		default:
+			pr, err = newMetadataInjector(step.Inject, mapper)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", label, err)
+		}
+		chain = append(chain, namedPostRenderer{name: label, PostRenderer: pr})
+	}
+	if len(chain) == 0 {
+		return nil, nil
+	}
+	return chain, nil
+}
+
+// PostRenderManifest runs pr over a rendered manifest outside of a Helm action.
+func PostRenderManifest(pr postrender.PostRenderer, manifest string) (string, error) {
+	if pr == nil || strings.TrimSpace(manifest) == "" {
+		return manifest, nil
+	}
+	out, err := pr.Run(bytes.NewBufferString(manifest))
+	if err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+type postRenderChain []namedPostRenderer
+
+func (c postRenderChain) Run(in *bytes.Buffer) (*bytes.Buffer, error) {
+	cur := in
+	for _, step := range c {
+		out, err := step.Run(cur)
+		if err != nil {
+			return nil, fmt.Errorf("post-renderer %s: %w", step.name, err)
+		}
+		cur = out
+	}
+	return cur, nil
+}
+
+type namedPostRenderer struct {
+	name string
+	postrender.PostRenderer
+}
+
+type kustomizePostRenderer struct {
+	patches []map[string]any
+}
+
+func newKustomizePostRenderer(patches []appconfig.KustomizePatch) (*kustomizePostRenderer, error) {
+	out := make([]map[string]any, 0, len(patches))
+	for i, p := range patches {
+		body := p.Patch
+		if path := strings.TrimSpace(p.Path); path != "" {
+			if strings.TrimSpace(body) != "" {
+				return nil, fmt.Errorf("patches[%d]: set either path or patch, not both", i)
+			}
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("patches[%d]: %w", i, err)
+			}
+			body = string(raw)
+		}
+		if strings.TrimSpace(body) == "" {
+			return nil, fmt.Errorf("patches[%d]: path or patch is required", i)
+		}
+		entry := map[string]any{"patch": body}
+		if t := p.Target; t != nil {
+			target := map[string]string{}
+			for k, v := range map[string]string{
+				"group":              t.Group,
+				"version":            t.Version,
+				"kind":               t.Kind,
+				"name":               t.Name,
+				"namespace":          t.Namespace,
+				"labelSelector":      t.LabelSelector,
+				"annotationSelector": t.AnnotationSelector,
+			} {
+				if strings.TrimSpace(v) != "" {
+					target[k] = strings.TrimSpace(v)
+				}
+			}
+			entry["target"] = target
+		}
+		out = append(out, entry)
+	}
+	return &kustomizePostRenderer{patches: out}, nil
+}
+
+func (k *kustomizePostRenderer) Run(in *bytes.Buffer) (*bytes.Buffer, error) {
+	fs := filesys.MakeFsInMemory()
+	if err := fs.WriteFile("/render/resources.yaml", in.Bytes()); err != nil {
+		return nil, err
+	}
+	kustomization, err := yaml.Marshal(map[string]any{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  []string{"resources.yaml"},
+		"patches":    k.patches,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.WriteFile("/render/kustomization.yaml", kustomization); err != nil {
+		return nil, err
+	}
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fs, "/render")
+	if err != nil {
+		return nil, fmt.Errorf("kustomize: %w", err)
+	}
+	out, err := resMap.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewBuffer(out), nil
+}
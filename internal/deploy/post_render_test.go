@@ -0,0 +1,70 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+)
+
+const postRenderInput = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+data:
+  a: "1"
+`
+
+func TestBuildPostRenderer_KustomizePatches(t *testing.T) {
+	dir := t.TempDir()
+	patchPath := filepath.Join(dir, "labels.yaml")
+	if err := os.WriteFile(patchPath, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\ndata:\n  b: \"2\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pr, err := BuildPostRenderer(context.Background(), []appconfig.PostRendererConfig{
+		{Name: "replicas", Patches: []appconfig.KustomizePatch{{
+			Target: &appconfig.PatchTarget{Kind: "Deployment"},
+			Patch:  "- op: replace\n  path: /spec/replicas\n  value: 3\n",
+		}}},
+		{Name: "config", Patches: []appconfig.KustomizePatch{{Path: patchPath}}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	out, err := PostRenderManifest(pr, postRenderInput)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for _, want := range []string{"replicas: 3", `a: "1"`, `b: "2"`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestBuildPostRenderer_Validation(t *testing.T) {
+	if pr, err := BuildPostRenderer(context.Background(), nil, nil); pr != nil || err != nil {
+		t.Fatalf("expected nil renderer for no steps, got %v, %v", pr, err)
+	}
+	cases := map[string]appconfig.PostRendererConfig{
+		"empty":        {Name: "x"},
+		"both":         {Exec: "cat", Patches: []appconfig.KustomizePatch{{Patch: "x"}}},
+		"missing exec": {Exec: "/nonexistent/ktl-post-renderer"},
+		"empty patch":  {Patches: []appconfig.KustomizePatch{{}}},
+	}
+	for name, step := range cases {
+		if _, err := BuildPostRenderer(context.Background(), []appconfig.PostRendererConfig{step}, nil); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
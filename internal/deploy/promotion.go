@@ -0,0 +1,78 @@
+// File: internal/deploy/promotion.go
+// Brief: Internal deploy package implementation for 'promotion'.
+
+// promotion.go records where a promoted release came from as annotations on its objects.
+package deploy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	PromotedFromAnnotation   = "ktl.dev/promoted-from"
+	PromotionChainAnnotation = "ktl.dev/promotion-chain"
+	PromotedAtAnnotation     = "ktl.dev/promoted-at"
+)
+
+// PromotionSource is the deployed release a promotion copies its chart version and values from.
+type PromotionSource struct {
+	Env       string
+	Cluster   string
+	Namespace string
+	Release   string
+	Revision  int
+}
+
+// String formats s as env:cluster/namespace/release@revision.
+func (s PromotionSource) String() string {
+	return fmt.Sprintf("%s:%s/%s/%s@%d", s.Env, s.Cluster, s.Namespace, s.Release, s.Revision)
+}
+
+// ReadPromotionChain returns the promotion chain recorded on the first object of manifest that
+// carries one, oldest hop first. A release that was never promoted has no chain.
+func ReadPromotionChain(manifest string) []string {
+	for _, doc := range splitYAMLDocs(manifest) {
+		var obj map[string]any
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj == nil {
+			continue
+		}
+		meta, _ := obj["metadata"].(map[string]any)
+		chain := strings.TrimSpace(stringMap(meta["annotations"])[PromotionChainAnnotation])
+		if chain == "" {
+			continue
+		}
+		var out []string
+		for _, hop := range strings.Split(chain, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				out = append(out, hop)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// PromotionAnnotations returns the annotations for a release promoted from src: the source
+// itself, the chain extended by env@revision of the source, and the promotion time.
+func PromotionAnnotations(src PromotionSource, chain []string, at time.Time) map[string]string {
+	hops := append(append([]string(nil), chain...), fmt.Sprintf("%s@%d", src.Env, src.Revision))
+	return map[string]string{
+		PromotedFromAnnotation:   src.String(),
+		PromotionChainAnnotation: strings.Join(hops, ","),
+		PromotedAtAnnotation:     at.UTC().Format(time.RFC3339),
+	}
+}
+
+// PromotionPostRenderStep injects annotations into every rendered object, replacing values a
+// previous promotion left behind.
+func PromotionPostRenderStep(annotations map[string]string) appconfig.PostRendererConfig {
+	return appconfig.PostRendererConfig{
+		Name:   "promotion",
+		Inject: []appconfig.InjectRule{{Annotations: annotations, Overwrite: true}},
+	}
+}
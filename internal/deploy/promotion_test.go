@@ -0,0 +1,61 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+)
+
+func TestPromotionAnnotationsExtendChain(t *testing.T) {
+	src := PromotionSource{Env: "staging", Cluster: "eu", Namespace: "apps", Release: "api", Revision: 7}
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	manifest := `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  annotations:
+    ktl.dev/promotion-chain: "dev@12"
+`
+	chain := ReadPromotionChain(manifest)
+	if !reflect.DeepEqual(chain, []string{"dev@12"}) {
+		t.Fatalf("chain = %v", chain)
+	}
+	got := PromotionAnnotations(src, chain, at)
+	want := map[string]string{
+		PromotedFromAnnotation:   "staging:eu/apps/api@7",
+		PromotionChainAnnotation: "dev@12,staging@7",
+		PromotedAtAnnotation:     "2026-03-01T12:00:00Z",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("annotations = %v, want %v", got, want)
+	}
+	if ReadPromotionChain("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: x\n") != nil {
+		t.Fatalf("expected no chain for a release that was never promoted")
+	}
+
+	pr, err := BuildPostRenderer(context.Background(), []appconfig.PostRendererConfig{PromotionPostRenderStep(got)}, nil)
+	if err != nil {
+		t.Fatalf("build post-renderer: %v", err)
+	}
+	out, err := pr.Run(bytes.NewBufferString(manifest))
+	if err != nil {
+		t.Fatalf("post-render: %v", err)
+	}
+	if strings.Count(out.String(), "ktl.dev/promoted-from: staging:eu/apps/api@7") != 2 {
+		t.Fatalf("expected every object to be annotated:\n%s", out.String())
+	}
+	if next := ReadPromotionChain(out.String()); !reflect.DeepEqual(next, []string{"dev@12", "staging@7"}) {
+		t.Fatalf("expected the previous chain to be replaced, got %v", next)
+	}
+}
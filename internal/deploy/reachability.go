@@ -0,0 +1,350 @@
+// File: internal/deploy/reachability.go
+// Brief: Internal deploy package implementation for 'reachability'.
+
+// reachability.go probes the hosts a release exposes through Ingress and Gateway API
+// HTTPRoute objects once an apply succeeded (--probe-reachability / --require-reachable).
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/releaseutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// ProbeTarget is one URL derived from an Ingress rule or HTTPRoute hostname.
+type ProbeTarget struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+}
+
+// ProbeResult is the outcome of probing one target from the client.
+type ProbeResult struct {
+	ProbeTarget
+	StatusCode int    `json:"statusCode,omitempty"`
+	LatencyMS  int64  `json:"latencyMs"`
+	Reachable  bool   `json:"reachable"`
+	Error      string `json:"error,omitempty"`
+	// CertExpiresAt is the leaf certificate's NotAfter for HTTPS targets.
+	CertExpiresAt *time.Time `json:"certExpiresAt,omitempty"`
+}
+
+// ProbeOptions tunes ProbeReachability.
+type ProbeOptions struct {
+	Timeout time.Duration
+	// Client overrides the HTTP client (tests); redirects are never followed.
+	Client *http.Client
+}
+
+// GatewayGetter fetches a Gateway API Gateway. ReachabilityTargets uses it for HTTPRoute parents
+// that are not part of the manifest.
+type GatewayGetter func(namespace, name string) (*unstructured.Unstructured, error)
+
+// DynamicGatewayGetter returns a GatewayGetter reading gateway.networking.k8s.io/v1 Gateways.
+func DynamicGatewayGetter(ctx context.Context, client dynamic.Interface) GatewayGetter {
+	if client == nil {
+		return nil
+	}
+	return func(namespace, name string) (*unstructured.Unstructured, error) {
+		return client.Resource(gatewayGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+}
+
+var gatewayGVR = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}
+
+// ReachabilityTargets lists the probe URLs of the Ingress and HTTPRoute objects in a rendered
+// manifest. Wildcard hosts are skipped. Ingress hosts covered by spec.tls use https. HTTPRoute
+// hostnames take the scheme (and a non-default port) from the listeners of their parent
+// Gateways, found in the manifest or through gateways; https when no listener is known.
+func ReachabilityTargets(manifest, defaultNamespace string, gateways GatewayGetter) []ProbeTarget {
+	var out []ProbeTarget
+	seen := map[string]bool{}
+	add := func(obj *unstructured.Unstructured, scheme, host, path string) {
+		host = strings.TrimSpace(host)
+		if host == "" || strings.Contains(host, "*") {
+			return
+		}
+		if path == "" || strings.ContainsAny(path, "*()[]$^") {
+			path = "/"
+		}
+		url := scheme + "://" + host + path
+		if seen[url] {
+			return
+		}
+		seen[url] = true
+		out = append(out, ProbeTarget{Kind: obj.GetKind(), Namespace: objectNamespace(obj, defaultNamespace), Name: obj.GetName(), URL: url})
+	}
+	var objs []*unstructured.Unstructured
+	rendered := map[string]*unstructured.Unstructured{}
+	for _, doc := range releaseutil.SplitManifests(manifest) {
+		var raw map[string]any
+		if err := yaml.Unmarshal([]byte(doc), &raw); err != nil || raw == nil {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: raw}
+		objs = append(objs, obj)
+		if obj.GetKind() == "Gateway" && strings.HasPrefix(obj.GetAPIVersion(), "gateway.networking.k8s.io/") {
+			rendered[objectNamespace(obj, defaultNamespace)+"/"+obj.GetName()] = obj
+		}
+	}
+	lookup := func(namespace, name string) *unstructured.Unstructured {
+		if gw := rendered[namespace+"/"+name]; gw != nil {
+			return gw
+		}
+		if gateways == nil {
+			return nil
+		}
+		gw, err := gateways(namespace, name)
+		if err != nil {
+			return nil
+		}
+		return gw
+	}
+	for _, obj := range objs {
+		switch obj.GetKind() {
+		case "Ingress":
+			tlsHosts := map[string]bool{}
+			tls, _, _ := unstructured.NestedSlice(obj.Object, "spec", "tls")
+			for _, entry := range tls {
+				m, _ := entry.(map[string]any)
+				hosts, _, _ := unstructured.NestedStringSlice(m, "hosts")
+				for _, h := range hosts {
+					tlsHosts[h] = true
+				}
+			}
+			rules, _, _ := unstructured.NestedSlice(obj.Object, "spec", "rules")
+			for _, entry := range rules {
+				rule, _ := entry.(map[string]any)
+				host, _, _ := unstructured.NestedString(rule, "host")
+				scheme := "http"
+				if tlsHosts[host] {
+					scheme = "https"
+				}
+				path := ""
+				if paths, _, _ := unstructured.NestedSlice(rule, "http", "paths"); len(paths) > 0 {
+					p, _ := paths[0].(map[string]any)
+					path, _, _ = unstructured.NestedString(p, "path")
+				}
+				add(obj, scheme, host, path)
+			}
+		case "HTTPRoute":
+			listeners := routeListeners(obj, objectNamespace(obj, defaultNamespace), lookup)
+			hosts, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "hostnames")
+			for _, host := range hosts {
+				scheme, port := listenerScheme(listeners, host)
+				if port != "" {
+					host += ":" + port
+				}
+				add(obj, scheme, host, "")
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].URL < out[j].URL })
+	return out
+}
+
+func objectNamespace(obj *unstructured.Unstructured, defaultNamespace string) string {
+	if ns := obj.GetNamespace(); ns != "" {
+		return ns
+	}
+	return defaultNamespace
+}
+
+// gatewayListener is the part of a Gateway listener that decides how a route host is reached.
+type gatewayListener struct {
+	protocol string
+	hostname string
+	port     int64
+}
+
+// routeListeners returns the listeners of the Gateways route attaches to, narrowed by the
+// parentRef sectionName and port. Parents that cannot be found are skipped.
+func routeListeners(route *unstructured.Unstructured, namespace string, lookup func(namespace, name string) *unstructured.Unstructured) []gatewayListener {
+	var out []gatewayListener
+	parents, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	for _, entry := range parents {
+		ref, _ := entry.(map[string]any)
+		group, found, _ := unstructured.NestedString(ref, "group")
+		if !found {
+			group = gatewayGVR.Group
+		}
+		kind, found, _ := unstructured.NestedString(ref, "kind")
+		if !found {
+			kind = "Gateway"
+		}
+		name, _, _ := unstructured.NestedString(ref, "name")
+		if group != gatewayGVR.Group || kind != "Gateway" || name == "" {
+			continue
+		}
+		ns, _, _ := unstructured.NestedString(ref, "namespace")
+		if ns == "" {
+			ns = namespace
+		}
+		gw := lookup(ns, name)
+		if gw == nil {
+			continue
+		}
+		section, _, _ := unstructured.NestedString(ref, "sectionName")
+		port := nestedPort(ref)
+		listeners, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+		for _, item := range listeners {
+			l, _ := item.(map[string]any)
+			lname, _, _ := unstructured.NestedString(l, "name")
+			lport := nestedPort(l)
+			if (section != "" && lname != section) || (port != 0 && lport != port) {
+				continue
+			}
+			protocol, _, _ := unstructured.NestedString(l, "protocol")
+			hostname, _, _ := unstructured.NestedString(l, "hostname")
+			out = append(out, gatewayListener{protocol: strings.ToUpper(protocol), hostname: hostname, port: lport})
+		}
+	}
+	return out
+}
+
+// nestedPort reads m["port"], which YAML decoding leaves as a float64.
+func nestedPort(m map[string]any) int64 {
+	switch v := m["port"].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// listenerScheme picks how to reach host through listeners: https when an HTTPS listener serves
+// it, http when only HTTP listeners do, and https when none is known. port is set when the
+// listener does not use the scheme's default port.
+func listenerScheme(listeners []gatewayListener, host string) (scheme, port string) {
+	var httpPort int64
+	for _, l := range listeners {
+		if !listenerHostMatches(l.hostname, host) {
+			continue
+		}
+		switch l.protocol {
+		case "HTTPS":
+			if l.port != 0 && l.port != 443 {
+				return "https", strconv.FormatInt(l.port, 10)
+			}
+			return "https", ""
+		case "HTTP":
+			if httpPort == 0 {
+				httpPort = l.port
+			}
+		}
+	}
+	if httpPort != 0 {
+		if httpPort != 80 {
+			return "http", strconv.FormatInt(httpPort, 10)
+		}
+		return "http", ""
+	}
+	return "https", ""
+}
+
+// listenerHostMatches reports whether a listener hostname ("" or "*.example.com" wildcards
+// included) accepts host.
+func listenerHostMatches(listener, host string) bool {
+	switch {
+	case listener == "" || strings.EqualFold(listener, host):
+		return true
+	case strings.HasPrefix(listener, "*."):
+		suffix := strings.ToLower(listener[1:])
+		h := strings.ToLower(host)
+		return strings.HasSuffix(h, suffix) && len(h) > len(suffix)
+	}
+	return false
+}
+
+// ProbeReachability GETs every target concurrently. A target is reachable when it answers with
+// a status below 500; redirects and auth challenges count, since they prove the route works.
+func ProbeReachability(ctx context.Context, targets []ProbeTarget, opts ProbeOptions) []ProbeResult {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+	}
+	probeClient := *client
+	probeClient.Timeout = timeout
+	probeClient.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	results := make([]ProbeResult, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = probeTarget(ctx, &probeClient, target)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func probeTarget(ctx context.Context, client *http.Client, target ProbeTarget) ProbeResult {
+	res := ProbeResult{ProbeTarget: target}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.URL, nil)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	req.Header.Set("User-Agent", "ktl-reachability-probe")
+	start := time.Now()
+	resp, err := client.Do(req)
+	res.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	defer resp.Body.Close()
+	res.StatusCode = resp.StatusCode
+	res.Reachable = resp.StatusCode < 500
+	if !res.Reachable {
+		res.Error = resp.Status
+	}
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		notAfter := resp.TLS.PeerCertificates[0].NotAfter
+		res.CertExpiresAt = &notAfter
+	}
+	return res
+}
+
+// Line renders a result for terminal output, e.g.
+// "https://shop.example.com/ 200 84ms cert expires in 41d".
+func (r ProbeResult) Line(now time.Time) string {
+	var b strings.Builder
+	b.WriteString(r.URL)
+	if r.StatusCode > 0 {
+		fmt.Fprintf(&b, " %d", r.StatusCode)
+	}
+	fmt.Fprintf(&b, " %dms", r.LatencyMS)
+	if r.CertExpiresAt != nil {
+		days := int(r.CertExpiresAt.Sub(now).Hours() / 24)
+		if days < 0 {
+			b.WriteString(" cert expired")
+		} else {
+			fmt.Fprintf(&b, " cert expires in %dd", days)
+		}
+	}
+	if r.Error != "" {
+		b.WriteString(" (" + r.Error + ")")
+	}
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestReachabilityTargets(t *testing.T) {
+	manifest := `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: shop
+spec:
+  tls:
+    - hosts: [shop.example.com]
+  rules:
+    - host: shop.example.com
+      http:
+        paths:
+          - path: /api
+    - host: admin.example.com
+    - host: "*.example.com"
+    - http:
+        paths:
+          - path: /
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: web
+  namespace: edge
+spec:
+  hostnames: [web.example.com, shop.example.com]
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: shop
+`
+	got := ReachabilityTargets(manifest, "prod", nil)
+	want := []ProbeTarget{
+		{Kind: "Ingress", Namespace: "prod", Name: "shop", URL: "http://admin.example.com/"},
+		{Kind: "HTTPRoute", Namespace: "edge", Name: "web", URL: "https://shop.example.com/"},
+		{Kind: "Ingress", Namespace: "prod", Name: "shop", URL: "https://shop.example.com/api"},
+		{Kind: "HTTPRoute", Namespace: "edge", Name: "web", URL: "https://web.example.com/"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("target %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReachabilityTargetsUseGatewayListeners(t *testing.T) {
+	manifest := `---
+apiVersion: gateway.networking.k8s.io/v1
+kind: Gateway
+metadata:
+  name: internal
+spec:
+  listeners:
+    - name: plain
+      protocol: HTTP
+      port: 80
+    - name: tls
+      protocol: HTTPS
+      port: 8443
+      hostname: "*.internal.example.com"
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: api
+spec:
+  parentRefs:
+    - name: internal
+      sectionName: tls
+  hostnames: [api.internal.example.com]
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: web
+spec:
+  parentRefs:
+    - name: edge
+      namespace: infra
+  hostnames: [web.example.com]
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: orphan
+spec:
+  parentRefs:
+    - name: missing
+  hostnames: [orphan.example.com]
+`
+	var looked []string
+	gateways := func(namespace, name string) (*unstructured.Unstructured, error) {
+		looked = append(looked, namespace+"/"+name)
+		if namespace != "infra" || name != "edge" {
+			return nil, fmt.Errorf("gateway %s/%s not found", namespace, name)
+		}
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "Gateway",
+			"spec": map[string]any{"listeners": []any{
+				map[string]any{"name": "http", "protocol": "HTTP", "port": int64(80)},
+			}},
+		}}, nil
+	}
+	got := ReachabilityTargets(manifest, "prod", gateways)
+	var urls []string
+	for _, target := range got {
+		urls = append(urls, target.URL)
+	}
+	want := "http://web.example.com/,https://api.internal.example.com:8443/,https://orphan.example.com/"
+	if strings.Join(urls, ",") != want {
+		t.Fatalf("got %v, want %s", urls, want)
+	}
+	if strings.Join(looked, ",") != "infra/edge,prod/missing" {
+		t.Fatalf("expected only parents outside the manifest to be looked up, got %v", looked)
+	}
+}
+
+func TestProbeReachability(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login", http.StatusFound)
+	}))
+	defer tlsServer.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+
+	targets := []ProbeTarget{
+		{Kind: "Ingress", Name: "ok", URL: tlsServer.URL + "/"},
+		{Kind: "Ingress", Name: "bad", URL: broken.URL + "/"},
+		{Kind: "Ingress", Name: "down", URL: "http://127.0.0.1:1/"},
+	}
+	results := ProbeReachability(context.Background(), targets, ProbeOptions{Timeout: 5 * time.Second, Client: tlsServer.Client()})
+
+	ok := results[0]
+	if !ok.Reachable || ok.StatusCode != http.StatusFound || ok.CertExpiresAt == nil {
+		t.Fatalf("expected the redirecting TLS host to be reachable with a cert expiry: %+v", ok)
+	}
+	if line := ok.Line(time.Now()); !strings.Contains(line, " 302 ") || !strings.Contains(line, "cert expires in") {
+		t.Fatalf("unexpected line: %q", line)
+	}
+	if results[1].Reachable || results[1].StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected a 502 to be unreachable: %+v", results[1])
+	}
+	if results[2].Reachable || results[2].Error == "" {
+		t.Fatalf("expected a refused connection to be unreachable: %+v", results[2])
+	}
+}
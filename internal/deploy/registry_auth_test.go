@@ -0,0 +1,48 @@
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/secretstore"
+	"helm.sh/helm/v3/pkg/cli"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestChartPathOptionsUsesRegistryCredentials(t *testing.T) {
+	resolver, err := secretstore.NewResolver(secretstore.Config{
+		Registries: map[string]secretstore.RegistryCredential{
+			"charts.example.com": {Username: "ci", Password: "p4ss"},
+			"ghcr.io":            {Username: "bot", Password: "t0k3n"},
+		},
+	}, secretstore.ResolverOptions{})
+	if err != nil {
+		t.Fatalf("new resolver: %v", err)
+	}
+	secrets := &SecretOptions{Resolver: resolver}
+	settings := cli.New()
+	settings.RegistryConfig = t.TempDir() + "/registry.json"
+
+	opts, err := chartPathOptions(context.Background(), settings, "web", "https://charts.example.com/stable", "1.0.0", secrets)
+	if err != nil {
+		t.Fatalf("chart path options: %v", err)
+	}
+	if opts.Username != "ci" || opts.Password != "p4ss" || opts.Version != "1.0.0" {
+		t.Fatalf("opts=%+v", opts)
+	}
+	if _, err := chartPathOptions(context.Background(), settings, "oci://ghcr.io/acme/web", "", "1.0.0", secrets); err != nil {
+		t.Fatalf("oci chart path options: %v", err)
+	}
+
+	fallbackCalls := 0
+	credFn := registryCredentialFunc(resolver, func(context.Context, string) (auth.Credential, error) {
+		fallbackCalls++
+		return auth.Credential{Username: "from-login"}, nil
+	})
+	if cred, err := credFn(context.Background(), "ghcr.io"); err != nil || cred.Username != "bot" || cred.Password != "t0k3n" {
+		t.Fatalf("ghcr cred=%+v err=%v", cred, err)
+	}
+	if cred, err := credFn(context.Background(), "quay.io"); err != nil || cred.Username != "from-login" || fallbackCalls != 1 {
+		t.Fatalf("expected fallback for quay.io, cred=%+v err=%v", cred, err)
+	}
+}
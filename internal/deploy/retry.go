@@ -0,0 +1,98 @@
+// File: internal/deploy/retry.go
+// Brief: Internal deploy package implementation for 'retry'.
+
+// retry.go retries helm upgrade/install when the API server or an admission webhook fails in a
+// way that is known to clear up on its own.
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// MaxRetryBackoff caps the delay between helm attempts.
+const MaxRetryBackoff = 30 * time.Second
+
+// RetryPolicy retries helm upgrade/install after transient errors.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts, including the first (values <= 1 disable retries).
+	Attempts int
+	// Backoff is the delay before the first retry; it doubles for each further retry up to
+	// MaxRetryBackoff.
+	Backoff time.Duration
+}
+
+// TransientErrorReason names the transient failure behind err (conflict, etcd leader change,
+// webhook timeout, ...), or returns "" when err should not be retried. Only errors that say
+// nothing about the release itself qualify; --wait timeouts and failed hooks do not.
+func TransientErrorReason(err error) string {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return ""
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "failed calling webhook") &&
+		(strings.Contains(msg, "context deadline exceeded") || strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out")):
+		return "webhook timeout"
+	case strings.Contains(msg, "failed calling webhook") &&
+		(strings.Contains(msg, "connection refused") || strings.Contains(msg, "no endpoints available")):
+		return "webhook unavailable"
+	case strings.Contains(msg, "etcdserver: leader changed") || strings.Contains(msg, "etcdserver: request timed out") || strings.Contains(msg, "etcdserver: no leader"):
+		return "etcd leader change"
+	case apierrors.IsConflict(err) || strings.Contains(msg, "the object has been modified") || strings.Contains(msg, "operation cannot be fulfilled"):
+		return "conflict"
+	case apierrors.IsTooManyRequests(err) || strings.Contains(msg, "too many requests"):
+		return "rate limited"
+	case apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || strings.Contains(msg, "the server is currently unable to handle the request"):
+		return "api server unavailable"
+	case strings.Contains(msg, "connection reset by peer") || strings.Contains(msg, "http2: client connection lost") || strings.Contains(msg, "tls handshake timeout"):
+		return "connection reset"
+	default:
+		return ""
+	}
+}
+
+// retryDelay returns the backoff before retry n (1-based).
+func (p RetryPolicy) retryDelay(n int) time.Duration {
+	d := p.Backoff
+	if d <= 0 {
+		return 0
+	}
+	for i := 1; i < n && d < MaxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > MaxRetryBackoff {
+		d = MaxRetryBackoff
+	}
+	return d
+}
+
+// retryTransient runs fn until it succeeds, fails with a non-transient error, or the policy's
+// attempts are used up. Each retry is reported to observers as a warning event.
+func retryTransient(ctx context.Context, observers []ProgressObserver, op string, policy RetryPolicy, fn func(attempt int) error) error {
+	attempts := policy.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn(attempt)
+		reason := TransientErrorReason(err)
+		if err == nil || reason == "" || attempt >= attempts || ctx.Err() != nil {
+			return err
+		}
+		delay := policy.retryDelay(attempt)
+		notifyEvent(observers, "warn", fmt.Sprintf("%s failed with a transient error (%s), retrying in %s (attempt %d/%d): %v", op, reason, delay, attempt+1, attempts, err))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
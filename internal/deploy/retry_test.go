@@ -0,0 +1,105 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestTransientErrorReason(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "app", errors.New("stale")), "conflict"},
+		{fmt.Errorf("upgrade failed: Operation cannot be fulfilled on deployments.apps \"api\": the object has been modified"), "conflict"},
+		{errors.New("create: etcdserver: leader changed"), "etcd leader change"},
+		{errors.New(`Internal error occurred: failed calling webhook "validate.kyverno.svc": Post "https://kyverno-svc:443/validate": context deadline exceeded`), "webhook timeout"},
+		{errors.New(`failed calling webhook "mutate.istio.io": no endpoints available for service "istiod"`), "webhook unavailable"},
+		{apierrors.NewTooManyRequests("slow down", 1), "rate limited"},
+		{errors.New("read tcp 10.0.0.1:443: connection reset by peer"), "connection reset"},
+		{errors.New("resource Deployment/api not ready. status: InProgress: context deadline exceeded"), ""},
+		{errors.New("pre-upgrade hooks failed: job failed: BackoffLimitExceeded"), ""},
+		{fmt.Errorf("helm upgrade: %w", context.Canceled), ""},
+		{nil, ""},
+	}
+	for _, tc := range cases {
+		if got := TransientErrorReason(tc.err); got != tc.want {
+			t.Errorf("TransientErrorReason(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: 2 * time.Second}
+	for n, want := range map[int]time.Duration{1: 2 * time.Second, 2: 4 * time.Second, 3: 8 * time.Second, 10: MaxRetryBackoff} {
+		if got := p.retryDelay(n); got != want {
+			t.Errorf("retryDelay(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+type eventRecorder struct{ events []string }
+
+func (r *eventRecorder) PhaseStarted(string)                   {}
+func (r *eventRecorder) PhaseCompleted(string, string, string) {}
+func (r *eventRecorder) SetDiff(string)                        {}
+func (r *eventRecorder) EmitEvent(level, message string) {
+	r.events = append(r.events, level+": "+message)
+}
+
+func TestRetryTransient(t *testing.T) {
+	obs := &eventRecorder{}
+	conflict := errors.New("the object has been modified; please apply your changes to the latest version")
+	var attempts []int
+	err := retryTransient(context.Background(), []ProgressObserver{obs}, "helm upgrade", RetryPolicy{Attempts: 3}, func(attempt int) error {
+		attempts = append(attempts, attempt)
+		if attempt < 3 {
+			return conflict
+		}
+		return nil
+	})
+	if err != nil || len(attempts) != 3 {
+		t.Fatalf("err=%v attempts=%v", err, attempts)
+	}
+	if len(obs.events) != 2 || !strings.HasPrefix(obs.events[1], "warn: helm upgrade failed with a transient error (conflict), retrying in 0s (attempt 3/3)") {
+		t.Fatalf("events = %q", obs.events)
+	}
+
+	attempts = nil
+	err = retryTransient(context.Background(), nil, "helm upgrade", RetryPolicy{Attempts: 3}, func(attempt int) error {
+		attempts = append(attempts, attempt)
+		return errors.New("rendered manifests contain a resource that already exists")
+	})
+	if err == nil || len(attempts) != 1 {
+		t.Fatalf("expected permanent errors not to be retried: err=%v attempts=%v", err, attempts)
+	}
+
+	attempts = nil
+	err = retryTransient(context.Background(), nil, "helm upgrade", RetryPolicy{Attempts: 2}, func(attempt int) error {
+		attempts = append(attempts, attempt)
+		return conflict
+	})
+	if !errors.Is(err, conflict) || len(attempts) != 2 {
+		t.Fatalf("expected the last error after all attempts: err=%v attempts=%v", err, attempts)
+	}
+}
+
+func TestRetryTransientStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := retryTransient(ctx, nil, "helm install", RetryPolicy{Attempts: 5, Backoff: time.Hour}, func(int) error {
+		calls++
+		cancel()
+		return errors.New("etcdserver: request timed out")
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("err=%v calls=%d", err, calls)
+	}
+}
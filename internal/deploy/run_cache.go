@@ -0,0 +1,137 @@
+// File: internal/deploy/run_cache.go
+// Brief: Internal deploy package implementation for 'run cache'.
+
+// run_cache.go shares chart downloads, resolved values, and the preview render between the
+// renders of a single ktl invocation.
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kubekattle/ktl/internal/netconfig"
+	"github.com/mitchellh/copystructure"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// RunCache memoizes work that ktl apply otherwise repeats for the drift guard, the plan preview,
+// the tracker render, and the real install/upgrade: locating (downloading) the chart, merging
+// values and resolving secrets, and the dry-run render. A nil *RunCache disables caching. It is
+// meant for one invocation; values files and secrets are not re-read once cached.
+type RunCache struct {
+	mu      sync.Mutex
+	charts  map[string]string
+	values  map[string]map[string]interface{}
+	preview *release.Release
+}
+
+// NewRunCache returns an empty cache.
+func NewRunCache() *RunCache {
+	return &RunCache{charts: map[string]string{}, values: map[string]map[string]interface{}{}}
+}
+
+func (c *RunCache) locateChart(opts action.ChartPathOptions, ref string, settings *cli.EnvSettings, charts *ChartCache) (string, error) {
+	if err := netconfig.CheckChartRef(ref, opts.RepoURL); err != nil {
+		return "", err
+	}
+	netconfig.ApplyChartPathOptions(&opts)
+	if c == nil {
+		return charts.Locate(opts, ref, settings)
+	}
+	key := strings.Join([]string{ref, opts.RepoURL, opts.Version}, "\x00")
+	c.mu.Lock()
+	path, ok := c.charts[key]
+	c.mu.Unlock()
+	if ok {
+		return path, nil
+	}
+	path, err := charts.Locate(opts, ref, settings)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.charts[key] = path
+	c.mu.Unlock()
+	return path, nil
+}
+
+func (c *RunCache) buildValues(ctx context.Context, settings *cli.EnvSettings, files, setVals, setStringVals, setFileVals, setJSONVals, setLiteralVals []string, secrets *SecretOptions) (map[string]interface{}, error) {
+	if c == nil {
+		return buildValues(ctx, settings, files, setVals, setStringVals, setFileVals, setJSONVals, setLiteralVals, secrets)
+	}
+	key := fmt.Sprintf("%q|%q|%q|%q|%q|%q|%p", files, setVals, setStringVals, setFileVals, setJSONVals, setLiteralVals, secrets)
+	c.mu.Lock()
+	cached, ok := c.values[key]
+	c.mu.Unlock()
+	if !ok {
+		vals, err := buildValues(ctx, settings, files, setVals, setStringVals, setFileVals, setJSONVals, setLiteralVals, secrets)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.values[key] = vals
+		c.mu.Unlock()
+		cached = vals
+	}
+	// Helm coalesces chart defaults into the map it is given; hand out copies so every render
+	// starts from the same user values.
+	copied, err := copystructure.Copy(cached)
+	if err != nil {
+		return nil, fmt.Errorf("copy cached values: %w", err)
+	}
+	return copied.(map[string]interface{}), nil
+}
+
+func (c *RunCache) rememberPreview(rel *release.Release) {
+	if c == nil || rel == nil {
+		return
+	}
+	c.mu.Lock()
+	c.preview = rel
+	c.mu.Unlock()
+}
+
+// PreviewManifest returns the manifest of the last dry-run install/upgrade rendered through this
+// cache, optionally prefixed with the chart's CRDs (as helm template --include-crds does).
+func (c *RunCache) PreviewManifest(includeCRDs bool) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	rel := c.preview
+	c.mu.Unlock()
+	if rel == nil {
+		return "", false
+	}
+	if !includeCRDs || rel.Chart == nil {
+		return rel.Manifest, true
+	}
+	var b strings.Builder
+	for _, crd := range rel.Chart.CRDObjects() {
+		if crd.File == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", crd.Filename, string(crd.File.Data))
+	}
+	b.WriteString(rel.Manifest)
+	return b.String(), true
+}
+
+// PreviewHooks returns the hooks of the last dry-run install/upgrade rendered through this cache
+// in execution order, with their rendered manifest.
+func (c *RunCache) PreviewHooks() ([]HookStep, string, bool) {
+	if c == nil {
+		return nil, "", false
+	}
+	c.mu.Lock()
+	rel := c.preview
+	c.mu.Unlock()
+	if rel == nil {
+		return nil, "", false
+	}
+	return HookExecutionOrder(rel.Hooks), renderHookManifest(rel.Hooks), true
+}
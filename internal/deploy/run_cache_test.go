@@ -0,0 +1,83 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestRunCacheReusesValuesAndChartPath(t *testing.T) {
+	dir := t.TempDir()
+	valuesPath := filepath.Join(dir, "values.yaml")
+	if err := os.WriteFile(valuesPath, []byte("replicas: 2\nimage:\n  tag: v1\n"), 0o600); err != nil {
+		t.Fatalf("write values: %v", err)
+	}
+	chartDir := filepath.Join(dir, "web")
+	if err := os.MkdirAll(chartDir, 0o755); err != nil {
+		t.Fatalf("mkdir chart: %v", err)
+	}
+
+	cache := NewRunCache()
+	settings := cli.New()
+	first, err := cache.buildValues(context.Background(), settings, []string{valuesPath}, []string{"image.tag=v2"}, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("build values: %v", err)
+	}
+	first["image"].(map[string]interface{})["tag"] = "mutated"
+	if err := os.Remove(valuesPath); err != nil {
+		t.Fatalf("remove values: %v", err)
+	}
+	second, err := cache.buildValues(context.Background(), settings, []string{valuesPath}, []string{"image.tag=v2"}, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("expected cached values after the file was removed: %v", err)
+	}
+	if got := second["image"].(map[string]interface{})["tag"]; got != "v2" {
+		t.Fatalf("expected an independent copy with tag v2, got %v", got)
+	}
+
+	path, err := cache.locateChart(action.ChartPathOptions{}, chartDir, settings, nil)
+	if err != nil {
+		t.Fatalf("locate chart: %v", err)
+	}
+	if err := os.RemoveAll(chartDir); err != nil {
+		t.Fatalf("remove chart: %v", err)
+	}
+	again, err := cache.locateChart(action.ChartPathOptions{}, chartDir, settings, nil)
+	if err != nil || again != path {
+		t.Fatalf("expected cached chart path %q, got %q err=%v", path, again, err)
+	}
+
+	var nilCache *RunCache
+	if _, ok := nilCache.PreviewManifest(true); ok {
+		t.Fatalf("nil cache should not report a preview")
+	}
+}
+
+func TestRunCachePreviewManifestIncludesCRDs(t *testing.T) {
+	cache := NewRunCache()
+	if _, ok := cache.PreviewManifest(false); ok {
+		t.Fatalf("expected no preview before a dry-run")
+	}
+	cache.rememberPreview(&release.Release{
+		Manifest: "---\n# Source: web/templates/cm.yaml\nkind: ConfigMap\n",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "web"},
+			Files:    []*chart.File{{Name: "crds/widget.yaml", Data: []byte("kind: CustomResourceDefinition")}},
+		},
+	})
+	plain, ok := cache.PreviewManifest(false)
+	if !ok || strings.Contains(plain, "CustomResourceDefinition") {
+		t.Fatalf("unexpected manifest without CRDs: %q", plain)
+	}
+	withCRDs, _ := cache.PreviewManifest(true)
+	if !strings.HasPrefix(withCRDs, "---\n# Source: web/crds/widget.yaml\nkind: CustomResourceDefinition") || !strings.HasSuffix(withCRDs, plain) {
+		t.Fatalf("expected CRDs before the manifest, got %q", withCRDs)
+	}
+}
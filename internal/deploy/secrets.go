@@ -1,12 +1,22 @@
 package deploy
 
-import "github.com/kubekattle/ktl/internal/secretstore"
+import (
+	"context"
+
+	"github.com/kubekattle/ktl/internal/secretstore"
+	"github.com/kubekattle/ktl/internal/valuesource"
+)
 
 // SecretOptions configures deploy-time secret resolution.
 type SecretOptions struct {
 	Resolver  *secretstore.Resolver
 	AuditSink func(secretstore.AuditReport)
 	Validate  bool
+	// ValueSources resolves valuefrom:// references (see valueSources in .ktl.yaml).
+	ValueSources *valuesource.Resolver
+	// Mask replaces Terraform outputs marked sensitive with a placeholder, for plans and previews
+	// that print rendered values.
+	Mask bool
 }
 
 // SecretRef represents a resolved secret reference for reporting/UI purposes.
@@ -16,3 +26,13 @@ type SecretRef struct {
 	Reference string `json:"reference,omitempty"`
 	Masked    bool   `json:"masked,omitempty"`
 }
+
+// resolveValueSourceRefs replaces valuefrom:// references in vals. References without configured
+// value sources fail with the values path that holds them.
+func resolveValueSourceRefs(ctx context.Context, vals map[string]interface{}, secrets *SecretOptions) error {
+	var r *valuesource.Resolver
+	if secrets != nil {
+		r = secrets.ValueSources
+	}
+	return r.ResolveValues(ctx, vals)
+}
@@ -20,6 +20,8 @@ import (
 
 // ResourceStatus captures the readiness state of a Kubernetes object managed by a release.
 type ResourceStatus struct {
+	// ID is the ResourceID of the object; set on stream snapshots so events can be matched to rows.
+	ID        string `json:"id,omitempty"`
 	Kind      string `json:"kind"`
 	Namespace string `json:"namespace"`
 	Name      string `json:"name"`
@@ -141,6 +143,131 @@ func podStatus(pod *corev1.Pod) ResourceStatus {
 	return rs
 }
 
+// Storage reasons set on PVC and StatefulSet rows; StorageIssues collects rows carrying them.
+const (
+	ReasonVolumeNotBound     = "VolumeNotBound"
+	ReasonVolumeLost         = "VolumeLost"
+	ReasonVolumeResizing     = "VolumeResizing"
+	ReasonVolumeResizeFailed = "VolumeResizeFailed"
+)
+
+func pvcStatus(pvc *corev1.PersistentVolumeClaim) ResourceStatus {
+	rs := baseStatus("PersistentVolumeClaim", pvc.ObjectMeta)
+	switch pvc.Status.Phase {
+	case corev1.ClaimLost:
+		rs.Status = "Failed"
+		rs.Reason = ReasonVolumeLost
+		rs.Message = fmt.Sprintf("Bound volume %s no longer exists", pvc.Spec.VolumeName)
+		return rs
+	case corev1.ClaimBound:
+	default:
+		rs.Status = "Pending"
+		rs.Reason = ReasonVolumeNotBound
+		class := "default"
+		if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+			class = *pvc.Spec.StorageClassName
+		}
+		rs.Message = fmt.Sprintf("Waiting for a volume (storage class %s)", class)
+		if node := pvc.Annotations["volume.kubernetes.io/selected-node"]; node != "" {
+			rs.Message += " on node " + node
+		}
+		return rs
+	}
+
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	rs.Status = "Ready"
+	rs.Message = fmt.Sprintf("Bound to %s (%s)", pvc.Spec.VolumeName, capacity.String())
+	for _, state := range pvc.Status.AllocatedResourceStatuses {
+		switch state {
+		case corev1.PersistentVolumeClaimControllerResizeInfeasible, corev1.PersistentVolumeClaimNodeResizeInfeasible:
+			rs.Status = "Failed"
+			rs.Reason = ReasonVolumeResizeFailed
+			rs.Message = "Volume resize failed: " + pvcConditionMessage(pvc, string(state))
+			return rs
+		}
+	}
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	resizing := !capacity.IsZero() && requested.Cmp(capacity) > 0
+	stage := ""
+	for _, cond := range pvc.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case corev1.PersistentVolumeClaimResizing, corev1.PersistentVolumeClaimFileSystemResizePending:
+			resizing = true
+			stage = string(cond.Type)
+		}
+	}
+	if resizing {
+		rs.Status = "Progressing"
+		rs.Reason = ReasonVolumeResizing
+		rs.Message = fmt.Sprintf("Resizing %s -> %s", capacity.String(), requested.String())
+		if stage != "" {
+			rs.Message += " (" + stage + ")"
+		}
+	}
+	return rs
+}
+
+func pvcConditionMessage(pvc *corev1.PersistentVolumeClaim, fallback string) string {
+	for _, cond := range pvc.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue && strings.TrimSpace(cond.Message) != "" {
+			return strings.TrimSpace(cond.Message)
+		}
+	}
+	return fallback
+}
+
+// annotateStatefulSetVolumes copies storage problems of a StatefulSet's claims
+// (<template>-<statefulset>-<ordinal>) onto the StatefulSet row, so a rollout stuck on a
+// volume says so instead of only reporting missing ready pods.
+func annotateStatefulSetVolumes(rows []ResourceStatus) {
+	for i := range rows {
+		sts := &rows[i]
+		if sts.Kind != "StatefulSet" || sts.Status == "Ready" {
+			continue
+		}
+		for _, pvc := range rows {
+			if pvc.Kind != "PersistentVolumeClaim" || pvc.Namespace != sts.Namespace || pvc.Status == "Ready" || !isStatefulSetClaim(pvc.Name, sts.Name) {
+				continue
+			}
+			sts.Reason = pvc.Reason
+			sts.Message = fmt.Sprintf("%s; volume %s: %s", sts.Message, pvc.Name, pvc.Message)
+			if pvc.Status == "Failed" {
+				sts.Status = "Failed"
+			}
+			break
+		}
+	}
+}
+
+func isStatefulSetClaim(claim, statefulSet string) bool {
+	idx := strings.LastIndex(claim, "-")
+	if idx <= 0 || idx == len(claim)-1 {
+		return false
+	}
+	for _, r := range claim[idx+1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return strings.HasSuffix(claim[:idx], "-"+statefulSet)
+}
+
+// StorageIssues returns the rows with a storage problem: unbound, lost, resizing, or
+// failed-to-resize claims, and StatefulSets waiting on one.
+func StorageIssues(rows []ResourceStatus) []ResourceStatus {
+	var out []ResourceStatus
+	for _, rs := range rows {
+		switch rs.Reason {
+		case ReasonVolumeNotBound, ReasonVolumeLost, ReasonVolumeResizing, ReasonVolumeResizeFailed:
+			out = append(out, rs)
+		}
+	}
+	return out
+}
+
 func pdbStatus(pdb *policyv1.PodDisruptionBudget) ResourceStatus {
 	rs := baseStatus("PodDisruptionBudget", pdb.ObjectMeta)
 	current := pdb.Status.CurrentHealthy
@@ -0,0 +1,84 @@
+package deploy
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPVC(name string, phase corev1.PersistentVolumeClaimPhase, requested, capacity string) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "prod"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			VolumeName: "pv-" + name,
+			Resources:  corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(requested)}},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+	if capacity != "" {
+		pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+	}
+	return pvc
+}
+
+func TestPVCStatus(t *testing.T) {
+	class := "fast-ssd"
+	pending := testPVC("data-db-0", corev1.ClaimPending, "10Gi", "")
+	pending.Spec.StorageClassName = &class
+	pending.Annotations = map[string]string{"volume.kubernetes.io/selected-node": "node-a"}
+
+	resizing := testPVC("data-db-1", corev1.ClaimBound, "20Gi", "10Gi")
+	resizing.Status.Conditions = []corev1.PersistentVolumeClaimCondition{{Type: corev1.PersistentVolumeClaimFileSystemResizePending, Status: corev1.ConditionTrue}}
+
+	infeasible := testPVC("data-db-2", corev1.ClaimBound, "20Gi", "10Gi")
+	infeasible.Status.AllocatedResourceStatuses = map[corev1.ResourceName]corev1.ClaimResourceStatus{
+		corev1.ResourceStorage: corev1.PersistentVolumeClaimControllerResizeInfeasible,
+	}
+	infeasible.Status.Conditions = []corev1.PersistentVolumeClaimCondition{{Type: corev1.PersistentVolumeClaimResizing, Status: corev1.ConditionTrue, Message: "storage class does not allow expansion"}}
+
+	cases := []struct {
+		pvc                     *corev1.PersistentVolumeClaim
+		status, reason, message string
+	}{
+		{testPVC("ok", corev1.ClaimBound, "10Gi", "10Gi"), "Ready", "", "Bound to pv-ok (10Gi)"},
+		{pending, "Pending", ReasonVolumeNotBound, "Waiting for a volume (storage class fast-ssd) on node node-a"},
+		{resizing, "Progressing", ReasonVolumeResizing, "Resizing 10Gi -> 20Gi (FileSystemResizePending)"},
+		{infeasible, "Failed", ReasonVolumeResizeFailed, "Volume resize failed: storage class does not allow expansion"},
+		{testPVC("gone", corev1.ClaimLost, "10Gi", ""), "Failed", ReasonVolumeLost, "Bound volume pv-gone no longer exists"},
+	}
+	for _, tc := range cases {
+		got := pvcStatus(tc.pvc)
+		if got.Status != tc.status || got.Reason != tc.reason || got.Message != tc.message {
+			t.Fatalf("%s: got %s/%s %q, want %s/%s %q", tc.pvc.Name, got.Status, got.Reason, got.Message, tc.status, tc.reason, tc.message)
+		}
+	}
+}
+
+func TestAnnotateStatefulSetVolumes(t *testing.T) {
+	rows := []ResourceStatus{
+		{Kind: "StatefulSet", Namespace: "prod", Name: "db", Status: "Pending", Message: "0/1 pods ready"},
+		{Kind: "StatefulSet", Namespace: "prod", Name: "cache", Status: "Ready", Message: "1/1 pods ready"},
+		{Kind: "PersistentVolumeClaim", Namespace: "prod", Name: "data-db-0", Status: "Failed", Reason: ReasonVolumeLost, Message: "Bound volume pv-1 no longer exists"},
+		{Kind: "PersistentVolumeClaim", Namespace: "prod", Name: "data-other-db-0", Status: "Ready", Message: "Bound"},
+		{Kind: "PersistentVolumeClaim", Namespace: "prod", Name: "data-cache-0", Status: "Pending", Reason: ReasonVolumeNotBound, Message: "Waiting"},
+	}
+	annotateStatefulSetVolumes(rows)
+	if rows[0].Status != "Failed" || rows[0].Reason != ReasonVolumeLost || !strings.Contains(rows[0].Message, "volume data-db-0: Bound volume pv-1 no longer exists") {
+		t.Fatalf("expected the StatefulSet to carry its claim's failure, got %+v", rows[0])
+	}
+	if rows[1].Reason != "" {
+		t.Fatalf("ready StatefulSets are left alone, got %+v", rows[1])
+	}
+	issues := StorageIssues(rows)
+	if len(issues) != 3 || issues[0].Name != "db" {
+		t.Fatalf("unexpected storage issues: %+v", issues)
+	}
+	for _, claim := range []string{"data-db", "db-0", "data-db-x"} {
+		if isStatefulSetClaim(claim, "db") {
+			t.Fatalf("%q is not a claim of db", claim)
+		}
+	}
+}
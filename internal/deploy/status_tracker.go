@@ -31,12 +31,14 @@ import (
 // StatusUpdateFunc consumes resource status snapshots.
 type StatusUpdateFunc func([]ResourceStatus)
 
-// ResourceTracker periodically lists release resources and reports their status.
+// ResourceTracker reports the status of release resources, either from watch-fed informer caches
+// (the default) or by polling.
 type ResourceTracker struct {
 	client           *kube.Client
 	releaseName      string
 	defaultNamespace string
 	interval         time.Duration
+	mode             TrackerMode
 	updateFn         StatusUpdateFunc
 	targets          []resourceTarget
 	namespaces       []string
@@ -72,6 +74,7 @@ func NewResourceTracker(client *kube.Client, namespace, release, manifest string
 		releaseName:      strings.TrimSpace(release),
 		defaultNamespace: strings.TrimSpace(namespace),
 		interval:         2 * time.Second,
+		mode:             TrackerModeWatch,
 		updateFn:         update,
 		targets:          targets,
 		namespaces:       nsList,
@@ -84,14 +87,18 @@ func (t *ResourceTracker) WithInterval(interval time.Duration) *ResourceTracker
 	return t
 }
 
-// Run starts the tracker loop until the context is canceled.
+// Run starts the tracker loop until the context is canceled. In watch mode it falls back to
+// polling when informers cannot sync (e.g. RBAC allows get but not list/watch).
 func (t *ResourceTracker) Run(ctx context.Context) {
 	if t.updateFn == nil || strings.TrimSpace(t.releaseName) == "" {
 		return
 	}
+	t.updateFn(nil)
+	if t.mode != TrackerModePoll && t.runWatch(ctx) {
+		return
+	}
 	ticker := time.NewTicker(t.interval)
 	defer ticker.Stop()
-	t.updateFn(nil)
 	for {
 		select {
 		case <-ctx.Done():
@@ -114,6 +121,7 @@ func (t *ResourceTracker) collect(ctx context.Context) []ResourceStatus {
 	} else {
 		rows = append(rows, t.collectDependents(ctx, seen)...)
 	}
+	annotateStatefulSetVolumes(rows)
 	sort.Slice(rows, func(i, j int) bool {
 		return sortKey(rows[i]) < sortKey(rows[j])
 	})
@@ -242,6 +250,11 @@ func (t *ResourceTracker) collectDependents(ctx context.Context, seen map[string
 				t.appendIfNew(&rows, seen, podStatus(&podList.Items[i]))
 			}
 		}
+		if pvcList, err := clientset.CoreV1().PersistentVolumeClaims(ns).List(ctx, opts); err == nil {
+			for i := range pvcList.Items {
+				t.appendIfNew(&rows, seen, pvcStatus(&pvcList.Items[i]))
+			}
+		}
 		if pdbList, err := clientset.PolicyV1().PodDisruptionBudgets(ns).List(ctx, opts); err == nil {
 			for i := range pdbList.Items {
 				t.appendIfNew(&rows, seen, pdbStatus(&pdbList.Items[i]))
@@ -298,6 +311,12 @@ func (t *ResourceTracker) typedClient() kubernetes.Interface {
 	return t.client.Clientset
 }
 
+// StatusFromObject evaluates the readiness of a live object the way release tracking does.
+// Kinds without a readiness rule are reported Ready.
+func StatusFromObject(obj *unstructured.Unstructured) *ResourceStatus {
+	return statusFromUnstructured(obj)
+}
+
 func statusFromUnstructured(obj *unstructured.Unstructured) *ResourceStatus {
 	if obj == nil {
 		return nil
@@ -339,6 +358,12 @@ func statusFromUnstructured(obj *unstructured.Unstructured) *ResourceStatus {
 			rs := podStatus(&pod)
 			return &rs
 		}
+	case "persistentvolumeclaim":
+		var pvc corev1.PersistentVolumeClaim
+		if convert(obj, &pvc) == nil {
+			rs := pvcStatus(&pvc)
+			return &rs
+		}
 	case "poddisruptionbudget":
 		var pdb policyv1.PodDisruptionBudget
 		if convert(obj, &pdb) == nil {
@@ -0,0 +1,306 @@
+// File: internal/deploy/status_tracker_watch.go
+// Brief: Internal deploy package implementation for 'status tracker watch'.
+
+// status_tracker_watch.go drives ResourceTracker from shared informers so large releases are
+// tracked with one list+watch per resource type instead of a GET per object every tick.
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// TrackerMode selects how ResourceTracker observes the cluster.
+type TrackerMode string
+
+const (
+	// TrackerModeWatch keeps informer caches up to date via watches and re-renders on every event.
+	TrackerModeWatch TrackerMode = "watch"
+	// TrackerModePoll re-reads every tracked object on a fixed interval.
+	TrackerModePoll TrackerMode = "poll"
+)
+
+const (
+	trackerDebounce      = 100 * time.Millisecond
+	trackerResyncPeriod  = 15 * time.Second
+	trackerCacheSyncWait = 10 * time.Second
+)
+
+var (
+	trackerWorkloadGVRs = []schema.GroupVersionResource{
+		{Group: "apps", Version: "v1", Resource: "deployments"},
+		{Group: "apps", Version: "v1", Resource: "statefulsets"},
+		{Group: "apps", Version: "v1", Resource: "daemonsets"},
+		{Group: "batch", Version: "v1", Resource: "jobs"},
+		{Group: "batch", Version: "v1", Resource: "cronjobs"},
+	}
+	trackerDependentGVRs = []schema.GroupVersionResource{
+		{Version: "v1", Resource: "pods"},
+		{Version: "v1", Resource: "persistentvolumeclaims"},
+		{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"},
+		{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"},
+	}
+)
+
+// ParseTrackerMode validates a --tracker value. Empty selects watch.
+func ParseTrackerMode(value string) (TrackerMode, error) {
+	switch TrackerMode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", TrackerModeWatch:
+		return TrackerModeWatch, nil
+	case TrackerModePoll:
+		return TrackerModePoll, nil
+	default:
+		return "", fmt.Errorf("invalid tracker mode %q (expected watch or poll)", value)
+	}
+}
+
+// WithMode selects watch (default) or poll tracking.
+func (t *ResourceTracker) WithMode(mode TrackerMode) *ResourceTracker {
+	t.mode = mode
+	return t
+}
+
+type watchedTarget struct {
+	target    resourceTarget
+	gvr       schema.GroupVersionResource
+	namespace string
+	err       error
+}
+
+// watchState is the informer-backed view used by runWatch.
+type watchState struct {
+	targets    []watchedTarget
+	informers  map[string]cache.SharedIndexInformer
+	dependents []string
+	workloads  []string
+	// fallback holds GET results for targets missing from the (label-filtered) caches, refreshed
+	// on every resync.
+	fallback map[string]*ResourceStatus
+}
+
+func informerID(gvr schema.GroupVersionResource, namespace string) string {
+	return gvr.String() + "|" + namespace
+}
+
+// runWatch tracks through informers. It returns false, without emitting anything, when the
+// informers cannot be started or synced (for example when RBAC forbids list/watch), so the
+// caller can fall back to polling.
+func (t *ResourceTracker) runWatch(ctx context.Context) bool {
+	if t.client == nil || t.client.Dynamic == nil || t.client.RESTMapper == nil || t.selector() == "" {
+		return false
+	}
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	state := &watchState{informers: map[string]cache.SharedIndexInformer{}, fallback: map[string]*ResourceStatus{}}
+	factories := map[string]dynamicinformer.DynamicSharedInformerFactory{}
+	factoryFor := func(namespace string) dynamicinformer.DynamicSharedInformerFactory {
+		if f, ok := factories[namespace]; ok {
+			return f
+		}
+		selector := t.selector()
+		f := dynamicinformer.NewFilteredDynamicSharedInformerFactory(t.client.Dynamic, trackerResyncPeriod, namespace, func(opts *metav1.ListOptions) {
+			opts.LabelSelector = selector
+		})
+		factories[namespace] = f
+		return f
+	}
+	signal := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case signal <- struct{}{}:
+		default:
+		}
+	}
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	}
+	watchGVR := func(gvr schema.GroupVersionResource, namespace string) string {
+		id := informerID(gvr, namespace)
+		if _, ok := state.informers[id]; ok {
+			return id
+		}
+		informer := factoryFor(namespace).ForResource(gvr).Informer()
+		if _, err := informer.AddEventHandler(handler); err != nil {
+			return ""
+		}
+		state.informers[id] = informer
+		return id
+	}
+
+	for _, target := range t.targets {
+		if strings.TrimSpace(target.Name) == "" || strings.TrimSpace(target.Kind) == "" {
+			continue
+		}
+		gvk := schemaFromTarget(target)
+		mapping, err := t.client.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			state.targets = append(state.targets, watchedTarget{target: target, err: err})
+			continue
+		}
+		ns := ""
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			ns = target.Namespace
+			if ns == "" {
+				ns = t.effectiveNamespace()
+			}
+		}
+		watchGVR(mapping.Resource, ns)
+		state.targets = append(state.targets, watchedTarget{target: target, gvr: mapping.Resource, namespace: ns})
+	}
+	for _, ns := range t.trackedNamespaces() {
+		if len(state.targets) == 0 {
+			for _, gvr := range trackerWorkloadGVRs {
+				state.workloads = append(state.workloads, watchGVR(gvr, ns))
+			}
+		}
+		for _, gvr := range trackerDependentGVRs {
+			state.dependents = append(state.dependents, watchGVR(gvr, ns))
+		}
+	}
+
+	for _, f := range factories {
+		f.Start(watchCtx.Done())
+	}
+	syncCtx, syncCancel := context.WithTimeout(watchCtx, trackerCacheSyncWait)
+	synced := true
+	for _, informer := range state.informers {
+		if !cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced) {
+			synced = false
+			break
+		}
+	}
+	syncCancel()
+	if !synced {
+		if ctx.Err() != nil {
+			t.updateFn(nil)
+			return true
+		}
+		return false
+	}
+
+	t.refreshFallback(watchCtx, state)
+	t.updateFn(t.collectWatched(state))
+	resync := time.NewTicker(trackerResyncPeriod)
+	defer resync.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			t.updateFn(nil)
+			return true
+		case <-resync.C:
+			t.refreshFallback(watchCtx, state)
+			t.updateFn(t.collectWatched(state))
+		case <-signal:
+			// Coalesce bursts (a rollout touches many pods at once) into one render.
+			timer := time.NewTimer(trackerDebounce)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				t.updateFn(nil)
+				return true
+			case <-timer.C:
+			}
+			t.updateFn(t.collectWatched(state))
+		}
+	}
+}
+
+// refreshFallback GETs targets that the label-filtered caches do not contain (charts that do not
+// set app.kubernetes.io/instance on every object).
+func (t *ResourceTracker) refreshFallback(ctx context.Context, state *watchState) {
+	for _, wt := range state.targets {
+		if wt.err != nil {
+			continue
+		}
+		if _, ok := lookupCached(state, wt); ok {
+			delete(state.fallback, targetID(wt))
+			continue
+		}
+		target := wt.target
+		if target.Namespace == "" {
+			target.Namespace = wt.namespace
+		}
+		state.fallback[targetID(wt)] = t.statusForTarget(ctx, target)
+	}
+}
+
+func targetID(wt watchedTarget) string {
+	return informerID(wt.gvr, wt.namespace) + "|" + wt.target.Name
+}
+
+func lookupCached(state *watchState, wt watchedTarget) (*unstructured.Unstructured, bool) {
+	informer := state.informers[informerID(wt.gvr, wt.namespace)]
+	if informer == nil {
+		return nil, false
+	}
+	key := wt.target.Name
+	if wt.namespace != "" {
+		key = wt.namespace + "/" + key
+	}
+	item, exists, err := informer.GetIndexer().GetByKey(key)
+	if err != nil || !exists {
+		return nil, false
+	}
+	obj, ok := item.(*unstructured.Unstructured)
+	return obj, ok
+}
+
+func (t *ResourceTracker) collectWatched(state *watchState) []ResourceStatus {
+	seen := make(map[string]struct{})
+	rows := make([]ResourceStatus, 0, len(state.targets)+8)
+	for _, wt := range state.targets {
+		var status *ResourceStatus
+		switch {
+		case wt.err != nil:
+			meta := metav1.ObjectMeta{Name: wt.target.Name, Namespace: wt.target.Namespace}
+			rs := genericStatus(wt.target.Kind, meta, fmt.Sprintf("REST mapping unavailable: %v", wt.err))
+			status = &rs
+		default:
+			if obj, ok := lookupCached(state, wt); ok {
+				status = statusFromUnstructured(obj)
+			} else {
+				status = state.fallback[targetID(wt)]
+			}
+		}
+		if status != nil {
+			t.appendIfNew(&rows, seen, *status)
+		}
+	}
+	for _, id := range state.workloads {
+		appendFromInformer(t, &rows, seen, state.informers[id])
+	}
+	for _, id := range state.dependents {
+		appendFromInformer(t, &rows, seen, state.informers[id])
+	}
+	annotateStatefulSetVolumes(rows)
+	sort.Slice(rows, func(i, j int) bool {
+		return sortKey(rows[i]) < sortKey(rows[j])
+	})
+	return rows
+}
+
+func appendFromInformer(t *ResourceTracker, rows *[]ResourceStatus, seen map[string]struct{}, informer cache.SharedIndexInformer) {
+	if informer == nil {
+		return
+	}
+	for _, item := range informer.GetStore().List() {
+		if obj, ok := item.(*unstructured.Unstructured); ok {
+			if status := statusFromUnstructured(obj); status != nil {
+				t.appendIfNew(rows, seen, *status)
+			}
+		}
+	}
+}
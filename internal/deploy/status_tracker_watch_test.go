@@ -0,0 +1,111 @@
+package deploy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/restmapper"
+)
+
+func TestParseTrackerMode(t *testing.T) {
+	for in, want := range map[string]TrackerMode{"": TrackerModeWatch, "watch": TrackerModeWatch, " POLL ": TrackerModePoll} {
+		got, err := ParseTrackerMode(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseTrackerMode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseTrackerMode("stream"); err == nil {
+		t.Fatalf("expected an error for an unknown mode")
+	}
+}
+
+func TestResourceTrackerWatchModeFollowsEvents(t *testing.T) {
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	dep := trackerObject("apps/v1", "Deployment", "web", map[string]string{"app.kubernetes.io/instance": "web"})
+	_ = unstructured.SetNestedField(dep.Object, int64(2), "spec", "replicas")
+	// The ConfigMap lacks the instance label, so it is only visible through the GET fallback.
+	cm := trackerObject("v1", "ConfigMap", "web-config", nil)
+
+	listKinds := map[schema.GroupVersionResource]string{deployments: "DeploymentList", {Version: "v1", Resource: "configmaps"}: "ConfigMapList"}
+	for _, gvr := range trackerDependentGVRs {
+		listKinds[gvr] = gvr.Resource + "List"
+	}
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, dep, cm)
+
+	disc := fake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	disc.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap", Namespaced: true}}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true}}},
+	}
+	client := &kube.Client{
+		Dynamic:    dyn,
+		RESTMapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(disc)),
+		Namespace:  "prod",
+	}
+	manifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  namespace: prod\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web-config\n  namespace: prod\n"
+
+	updates := make(chan []ResourceStatus, 16)
+	tracker := NewResourceTracker(client, "prod", "web", manifest, func(rows []ResourceStatus) {
+		if rows != nil {
+			updates <- rows
+		}
+	}).WithInterval(time.Hour) // a poll would never fire within the test
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracker.Run(ctx)
+
+	first := waitForRows(t, updates, func(rows []ResourceStatus) bool { return len(rows) == 2 })
+	for _, row := range first {
+		if row.Kind == "ConfigMap" && row.Status != "Ready" {
+			t.Fatalf("expected the unlabeled ConfigMap via fallback, got %+v", row)
+		}
+	}
+
+	_ = unstructured.SetNestedField(dep.Object, int64(2), "status", "readyReplicas")
+	if _, err := dyn.Resource(deployments).Namespace("prod").Update(ctx, dep, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("update deployment: %v", err)
+	}
+	waitForRows(t, updates, func(rows []ResourceStatus) bool {
+		for _, row := range rows {
+			if row.Kind == "Deployment" && row.Message == "2/2 pods ready" {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func trackerObject(apiVersion, kind, name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace("prod")
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func waitForRows(t *testing.T, updates <-chan []ResourceStatus, ok func([]ResourceStatus) bool) []ResourceStatus {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case rows := <-updates:
+			if ok(rows) {
+				return rows
+			}
+		case <-deadline:
+			t.Fatalf("timed out waiting for tracker update")
+		}
+	}
+}
@@ -78,6 +78,12 @@ type LogPayload struct {
 	Namespace string `json:"namespace,omitempty"`
 	Pod       string `json:"pod,omitempty"`
 	Container string `json:"container,omitempty"`
+	// Key identifies a Kubernetes event across repeats; a payload with a Key seen before
+	// supersedes the earlier one and carries the running Count.
+	Key        string `json:"key,omitempty"`
+	Count      int    `json:"count,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	ResourceID string `json:"resourceId,omitempty"`
 }
 
 // DiffPayload describes a rendered diff, if requested.
@@ -101,6 +107,15 @@ type SummaryPayload struct {
 	History        []HistoryBreadcrumb `json:"history,omitempty"`
 	LastSuccessful *HistoryBreadcrumb  `json:"lastSuccessful,omitempty"`
 	Secrets        []SecretRef         `json:"secrets,omitempty"`
+	Git            *GitMetadata        `json:"git,omitempty"`
+	Interrupted    *InterruptSummary   `json:"interrupted,omitempty"`
+	// Storage lists the claims (and StatefulSets waiting on them) with a storage problem in the
+	// last resource snapshot. EmitSummary fills it when left nil.
+	Storage []ResourceStatus `json:"storage,omitempty"`
+	// Reachability holds the post-apply Ingress/HTTPRoute probes (--probe-reachability).
+	Reachability []ProbeResult `json:"reachability,omitempty"`
+	// ChartTests holds the post-apply chart test results (--run-chart-tests).
+	ChartTests *ChartTestReport `json:"chartTests,omitempty"`
 }
 
 // HealthSnapshot aggregates readiness stats for the release.
@@ -125,6 +140,8 @@ type StreamBroadcaster struct {
 	phases           map[string]*PhasePayload
 	start            time.Time
 	resourceLogState map[string]string
+	events           *eventCorrelator
+	lastResources    []ResourceStatus
 }
 
 // NewStreamBroadcaster constructs a deploy stream broadcaster for the given release.
@@ -135,6 +152,7 @@ func NewStreamBroadcaster(release, namespace, chart string) *StreamBroadcaster {
 		chart:            strings.TrimSpace(chart),
 		phases:           make(map[string]*PhasePayload),
 		resourceLogState: make(map[string]string),
+		events:           newEventCorrelator(),
 		start:            time.Now(),
 	}
 	for _, name := range defaultDeployPhases {
@@ -248,6 +266,16 @@ func (b *StreamBroadcaster) UpdateResources(rows []ResourceStatus) {
 	}
 	cp := make([]ResourceStatus, len(rows))
 	copy(cp, rows)
+	for i := range cp {
+		if cp[i].ID == "" {
+			cp[i].ID = ResourceID(cp[i].Kind, cp[i].Namespace, cp[i].Name)
+		}
+	}
+	if len(cp) > 0 {
+		b.mu.Lock()
+		b.lastResources = cp
+		b.mu.Unlock()
+	}
 	b.broadcast(StreamEvent{Kind: StreamEventResources, Resources: cp})
 	b.broadcast(StreamEvent{Kind: StreamEventHealth, Health: summarizeHealth(cp)})
 	b.emitResourceLogs(cp)
@@ -273,6 +301,11 @@ func (b *StreamBroadcaster) EmitSummary(summary SummaryPayload) {
 	if summary.PhaseDurations == nil {
 		summary.PhaseDurations = b.phaseDurations()
 	}
+	if summary.Storage == nil {
+		b.mu.Lock()
+		summary.Storage = StorageIssues(b.lastResources)
+		b.mu.Unlock()
+	}
 	b.broadcast(StreamEvent{Kind: StreamEventSummary, Summary: &summary})
 }
 
@@ -300,6 +333,14 @@ func (b *StreamBroadcaster) ObserveLog(record tailer.LogRecord) {
 	if b == nil || !b.HasObservers() {
 		return
 	}
+	if record.Event != nil && b.events != nil {
+		b.broadcast(StreamEvent{
+			Kind:      StreamEventLog,
+			Timestamp: timestamp(record.Timestamp),
+			Log:       b.events.observe(record.Event),
+		})
+		return
+	}
 	message := strings.TrimSpace(record.Rendered)
 	if message == "" {
 		message = strings.TrimSpace(record.Raw)
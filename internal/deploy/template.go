@@ -8,11 +8,16 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"strings"
 
+	"github.com/kubekattle/ktl/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/postrender"
+	"helm.sh/helm/v3/pkg/release"
 )
 
 // TemplateOptions controls rendering behavior for helm template equivalents.
@@ -26,11 +31,22 @@ type TemplateOptions struct {
 	SetValues       []string
 	SetStringValues []string
 	SetFileValues   []string
-	Secrets         *SecretOptions
-	IncludeCRDs     bool
+	// SetJSONValues and SetLiteralValues mirror helm's --set-json and --set-literal.
+	SetJSONValues    []string
+	SetLiteralValues []string
+	Secrets          *SecretOptions
+	IncludeCRDs      bool
 	// UseCluster toggles between "client-only" rendering (fast, offline) and cluster-aware
 	// rendering (uses discovery to match actual API versions/capabilities).
 	UseCluster bool
+	// ValueProvenance records the merged values with the source of each leaf (plan reports).
+	ValueProvenance bool
+	// Cache, when set, reuses the chart download and resolved values within one invocation.
+	Cache *RunCache
+	// Charts, when set, shares chart downloads with other releases of the same run.
+	Charts *ChartCache
+	// PostRenderer, when set, rewrites the rendered manifests (see BuildPostRenderer).
+	PostRenderer postrender.PostRenderer
 }
 
 // TemplateResult holds rendered manifests and optional notes.
@@ -39,6 +55,10 @@ type TemplateResult struct {
 	Notes        string
 	ChartVersion string
 	Templates    map[string]string
+	Values       []ValueProvenance
+	Hooks        []HookStep
+	// HookManifest holds the rendered hook resources, which Helm keeps out of Manifest.
+	HookManifest string
 }
 
 // RenderTemplate renders the provided chart without applying it to the cluster.
@@ -57,8 +77,11 @@ func RenderTemplate(ctx context.Context, actionCfg *action.Configuration, settin
 		namespace = "default"
 	}
 
-	chartOpts := action.ChartPathOptions{RepoURL: opts.RepoURL, Version: opts.Version}
-	chartPath, err := chartOpts.LocateChart(opts.Chart, settings)
+	chartOpts, err := chartPathOptions(ctx, settings, opts.Chart, opts.RepoURL, opts.Version, opts.Secrets)
+	if err != nil {
+		return nil, fmt.Errorf("locate chart: %w", err)
+	}
+	chartPath, err := opts.Cache.locateChart(chartOpts, opts.Chart, settings, opts.Charts)
 	if err != nil {
 		return nil, fmt.Errorf("locate chart: %w", err)
 	}
@@ -69,8 +92,14 @@ func RenderTemplate(ctx context.Context, actionCfg *action.Configuration, settin
 	if err := ensureInstallable(chartRequested); err != nil {
 		return nil, fmt.Errorf("chart not installable: %w", err)
 	}
+	if err := ensureVendoredDependencies(chartRequested); err != nil {
+		return nil, err
+	}
 
-	vals, err := buildValues(ctx, settings, opts.ValuesFiles, opts.SetValues, opts.SetStringValues, opts.SetFileValues, opts.Secrets)
+	if err := CheckSetValueTypes(chartRequested, opts.SetValues, opts.SetStringValues, opts.SetJSONValues, opts.SetLiteralValues); err != nil {
+		return nil, err
+	}
+	vals, err := opts.Cache.buildValues(ctx, settings, opts.ValuesFiles, opts.SetValues, opts.SetStringValues, opts.SetFileValues, opts.SetJSONValues, opts.SetLiteralValues, opts.Secrets)
 	if err != nil {
 		return nil, err
 	}
@@ -82,8 +111,11 @@ func RenderTemplate(ctx context.Context, actionCfg *action.Configuration, settin
 	installer.Replace = true
 	installer.ClientOnly = !opts.UseCluster
 	installer.IncludeCRDs = opts.IncludeCRDs
+	installer.PostRenderer = opts.PostRenderer
 
-	rel, err := installer.RunWithContext(ctx, chartRequested, vals)
+	helmCtx, helmSpan := tracing.Start(ctx, "helm template", attribute.String("ktl.release", opts.ReleaseName), attribute.String("ktl.chart", opts.Chart))
+	rel, err := installer.RunWithContext(helmCtx, chartRequested, vals)
+	tracing.End(helmSpan, err)
 	if err != nil {
 		return nil, fmt.Errorf("helm template: %w", err)
 	}
@@ -91,12 +123,33 @@ func RenderTemplate(ctx context.Context, actionCfg *action.Configuration, settin
 	templateSources := make(map[string]string)
 	collectTemplates(chartRequested, "", templateSources)
 
-	return &TemplateResult{
+	result := &TemplateResult{
 		Manifest:     rel.Manifest,
 		Notes:        rel.Info.Notes,
 		ChartVersion: chartRequested.Metadata.Version,
 		Templates:    templateSources,
-	}, nil
+		Hooks:        HookExecutionOrder(rel.Hooks),
+	}
+	result.HookManifest = renderHookManifest(rel.Hooks)
+	if opts.ValueProvenance {
+		result.Values, err = ValuesProvenance(settings, chartRequested, opts.ValuesFiles, opts.SetValues, opts.SetStringValues, opts.SetFileValues, opts.SetJSONValues, opts.SetLiteralValues)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// renderHookManifest joins the rendered hook resources into one manifest.
+func renderHookManifest(hooks []*release.Hook) string {
+	var b strings.Builder
+	for _, hook := range hooks {
+		if hook == nil || strings.TrimSpace(hook.Manifest) == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", hook.Path, strings.TrimSpace(hook.Manifest))
+	}
+	return b.String()
 }
 
 func collectTemplates(ch *chart.Chart, prefix string, out map[string]string) {
@@ -0,0 +1,191 @@
+// File: internal/deploy/tfstate.go
+// Brief: Internal deploy package implementation for 'tfstate'.
+
+// tfstate.go resolves tfstate:// value references from Terraform state outputs at render time.
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/netconfig"
+)
+
+const (
+	tfstatePrefix      = "tfstate://"
+	tfstateHTTPPrefix  = "tfstate+http://"
+	tfstateHTTPSPrefix = "tfstate+https://"
+
+	// TerraformStateTokenEnv holds a bearer token sent when fetching remote state
+	// (Terraform Cloud/Enterprise state downloads or the http backend).
+	TerraformStateTokenEnv = "KTL_TFSTATE_TOKEN"
+)
+
+// IsTerraformRef reports whether v is a Terraform state output reference:
+//
+//	tfstate://path/to/terraform.tfstate#outputs.db_endpoint
+//	tfstate+https://host/state#outputs.cluster.endpoint
+func IsTerraformRef(v string) bool {
+	v = strings.TrimSpace(v)
+	return strings.HasPrefix(v, tfstatePrefix) || strings.HasPrefix(v, tfstateHTTPPrefix) || strings.HasPrefix(v, tfstateHTTPSPrefix)
+}
+
+type terraformStateResolver struct {
+	client *http.Client
+	token  string
+	mask   bool
+	states map[string]map[string]terraformOutput
+}
+
+type terraformOutput struct {
+	Value     interface{} `json:"value"`
+	Sensitive bool        `json:"sensitive"`
+}
+
+// resolveTerraformRefs replaces every tfstate reference in vals with the referenced output value.
+// A reference must be the whole value; the output keeps its Terraform type (string, number, map, list).
+// With mask set, outputs marked sensitive resolve to a placeholder instead.
+func resolveTerraformRefs(ctx context.Context, vals map[string]interface{}, mask bool) error {
+	r := &terraformStateResolver{
+		client: netconfig.HTTPClient(30 * time.Second),
+		token:  strings.TrimSpace(os.Getenv(TerraformStateTokenEnv)),
+		mask:   mask,
+		states: map[string]map[string]terraformOutput{},
+	}
+	_, err := r.walk(ctx, "", vals)
+	return err
+}
+
+func (r *terraformStateResolver) walk(ctx context.Context, path string, v interface{}) (interface{}, error) {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		for k, child := range typed {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			next, err := r.walk(ctx, childPath, child)
+			if err != nil {
+				return nil, err
+			}
+			typed[k] = next
+		}
+		return typed, nil
+	case []interface{}:
+		for i, child := range typed {
+			next, err := r.walk(ctx, fmt.Sprintf("%s[%d]", path, i), child)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = next
+		}
+		return typed, nil
+	case string:
+		if !IsTerraformRef(typed) {
+			return typed, nil
+		}
+		out, err := r.resolve(ctx, strings.TrimSpace(typed))
+		if err != nil {
+			return nil, fmt.Errorf("values %s: %w", path, err)
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+func (r *terraformStateResolver) resolve(ctx context.Context, ref string) (interface{}, error) {
+	location, fragment, ok := strings.Cut(ref, "#")
+	if !ok || strings.TrimSpace(fragment) == "" {
+		return nil, fmt.Errorf("%s: missing #outputs.<name> selector", ref)
+	}
+	selector := strings.Split(strings.TrimSpace(fragment), ".")
+	if len(selector) < 2 || selector[0] != "outputs" || selector[1] == "" {
+		return nil, fmt.Errorf("%s: selector must look like #outputs.<name>[.<key>...]", ref)
+	}
+	outputs, err := r.load(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref, err)
+	}
+	output, ok := outputs[selector[1]]
+	if !ok {
+		return nil, fmt.Errorf("%s: output %q not found in state", ref, selector[1])
+	}
+	if output.Sensitive && r.mask {
+		return "[sensitive:" + strings.Join(selector, ".") + "]", nil
+	}
+	value := output.Value
+	for i, key := range selector[2:] {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: %s is not an object", ref, strings.Join(selector[:i+2], "."))
+		}
+		if value, ok = m[key]; !ok {
+			return nil, fmt.Errorf("%s: key %q not found", ref, key)
+		}
+	}
+	return value, nil
+}
+
+func (r *terraformStateResolver) load(ctx context.Context, location string) (map[string]terraformOutput, error) {
+	if outputs, ok := r.states[location]; ok {
+		return outputs, nil
+	}
+	var data []byte
+	var err error
+	switch {
+	case strings.HasPrefix(location, tfstateHTTPPrefix) && r.token != "":
+		return nil, fmt.Errorf("refusing to send %s over plain HTTP; use tfstate+https://", TerraformStateTokenEnv)
+	case strings.HasPrefix(location, tfstateHTTPPrefix), strings.HasPrefix(location, tfstateHTTPSPrefix):
+		data, err = r.fetch(ctx, strings.TrimPrefix(location, "tfstate+"))
+	default:
+		path := strings.TrimPrefix(location, tfstatePrefix)
+		if strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("state path is empty")
+		}
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read terraform state: %w", err)
+	}
+	var state struct {
+		Version int                        `json:"version"`
+		Outputs map[string]terraformOutput `json:"outputs"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parse terraform state: %w", err)
+	}
+	if state.Version != 0 && state.Version < 4 {
+		return nil, fmt.Errorf("terraform state version %d is not supported (need 4+)", state.Version)
+	}
+	r.states[location] = state.Outputs
+	return state.Outputs, nil
+}
+
+func (r *terraformStateResolver) fetch(ctx context.Context, url string) ([]byte, error) {
+	if err := netconfig.CheckURL(url); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", req.URL.Redacted(), resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+}
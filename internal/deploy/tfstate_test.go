@@ -0,0 +1,100 @@
+package deploy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testTerraformState = `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "outputs": {
+    "db_endpoint": {"value": "db.internal:5432", "type": "string"},
+    "replicas": {"value": 3, "type": "number"},
+    "cluster": {"value": {"name": "prod", "oidc": {"issuer": "https://oidc"}}, "type": ["object", {}]}
+  },
+  "resources": []
+}`
+
+func TestResolveTerraformRefsFromLocalState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+	if err := os.WriteFile(path, []byte(testTerraformState), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	vals := map[string]interface{}{
+		"db":       map[string]interface{}{"host": "tfstate://" + path + "#outputs.db_endpoint"},
+		"replicas": "tfstate://" + path + "#outputs.replicas",
+		"issuers":  []interface{}{"tfstate://" + path + "#outputs.cluster.oidc.issuer", "static"},
+	}
+	if err := resolveTerraformRefs(context.Background(), vals, false); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got := vals["db"].(map[string]interface{})["host"]; got != "db.internal:5432" {
+		t.Fatalf("unexpected db.host %v", got)
+	}
+	if got := vals["replicas"]; got != float64(3) {
+		t.Fatalf("expected numeric replicas, got %#v", got)
+	}
+	if got := vals["issuers"].([]interface{}); got[0] != "https://oidc" || got[1] != "static" {
+		t.Fatalf("unexpected issuers %v", got)
+	}
+
+	bad := map[string]interface{}{"x": "tfstate://" + path + "#outputs.missing"}
+	if err := resolveTerraformRefs(context.Background(), bad, false); err == nil || !strings.Contains(err.Error(), "values x") {
+		t.Fatalf("expected missing output error, got %v", err)
+	}
+	bad = map[string]interface{}{"x": "tfstate://" + path + "#resources.foo"}
+	if err := resolveTerraformRefs(context.Background(), bad, false); err == nil {
+		t.Fatalf("expected selector error")
+	}
+}
+
+func TestResolveTerraformRefsFromRemoteState(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(testTerraformState))
+	}))
+	defer srv.Close()
+	r := &terraformStateResolver{client: srv.Client(), token: "tok", states: map[string]map[string]terraformOutput{}}
+	vals := map[string]interface{}{"cluster": "tfstate+" + srv.URL + "/state#outputs.cluster.name"}
+	if _, err := r.walk(context.Background(), "", vals); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if vals["cluster"] != "prod" {
+		t.Fatalf("unexpected cluster %v", vals["cluster"])
+	}
+
+	t.Setenv(TerraformStateTokenEnv, "tok")
+	plain := map[string]interface{}{"cluster": "tfstate+http://state.example.com/state#outputs.cluster.name"}
+	if err := resolveTerraformRefs(context.Background(), plain, false); err == nil || !strings.Contains(err.Error(), "over plain HTTP") {
+		t.Fatalf("expected the token to be refused over http, got %v", err)
+	}
+}
+
+func TestResolveTerraformRefsMasksSensitiveOutputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+	state := `{"version": 4, "outputs": {"db_password": {"value": "hunter2", "type": "string", "sensitive": true}}}`
+	if err := os.WriteFile(path, []byte(state), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ref := "tfstate://" + path + "#outputs.db_password"
+	masked := map[string]interface{}{"password": ref}
+	if err := resolveTerraformRefs(context.Background(), masked, true); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if masked["password"] != "[sensitive:outputs.db_password]" {
+		t.Fatalf("expected the sensitive output to be masked, got %v", masked["password"])
+	}
+	applied := map[string]interface{}{"password": ref}
+	if err := resolveTerraformRefs(context.Background(), applied, false); err != nil || applied["password"] != "hunter2" {
+		t.Fatalf("expected the real value when applying, got %v (%v)", applied["password"], err)
+	}
+}
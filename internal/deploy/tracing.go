@@ -0,0 +1,72 @@
+// File: internal/deploy/tracing.go
+// Brief: Internal deploy package implementation for 'tracing'.
+
+// tracing.go turns deploy phases into OpenTelemetry spans when --otel-endpoint is set.
+package deploy
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kubekattle/ktl/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// phaseTracer is a ProgressObserver that opens a span per phase under the deploy span in ctx.
+// Phases overlap (wait spans the whole upgrade), so each is tracked by name.
+type phaseTracer struct {
+	ctx   context.Context
+	mu    sync.Mutex
+	spans map[string]trace.Span
+}
+
+func newPhaseTracer(ctx context.Context) *phaseTracer {
+	return &phaseTracer{ctx: ctx, spans: map[string]trace.Span{}}
+}
+
+func (t *phaseTracer) PhaseStarted(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.spans[name]; ok {
+		return
+	}
+	_, span := tracing.Start(t.ctx, "deploy phase "+name, attribute.String("ktl.deploy.phase", name))
+	t.spans[name] = span
+}
+
+func (t *phaseTracer) PhaseCompleted(name, status, message string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span, ok := t.spans[name]
+	if !ok {
+		// Phases skipped up front never started; they have no duration worth a span.
+		return
+	}
+	delete(t.spans, name)
+	span.SetAttributes(attribute.String("ktl.deploy.phase.status", status))
+	if message != "" {
+		span.SetAttributes(attribute.String("ktl.deploy.phase.message", message))
+	}
+	if status == "failed" {
+		span.SetStatus(codes.Error, message)
+	}
+	span.End()
+}
+
+func (t *phaseTracer) EmitEvent(level, message string) {
+	trace.SpanFromContext(t.ctx).AddEvent(message, trace.WithAttributes(attribute.String("level", level)))
+}
+
+func (t *phaseTracer) SetDiff(string) {}
+
+// finish ends phases left open by an early return.
+func (t *phaseTracer) finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for name, span := range t.spans {
+		span.End()
+		delete(t.spans, name)
+	}
+}
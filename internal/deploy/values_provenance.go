@@ -0,0 +1,221 @@
+// File: internal/deploy/values_provenance.go
+// Brief: Internal deploy package implementation for 'values provenance'.
+
+// values_provenance.go flattens the merged chart values and records which source set each leaf.
+package deploy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/netconfig"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	cliValues "helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/release"
+	"sigs.k8s.io/yaml"
+)
+
+// ValueSourceChartDefaults labels leaves that come from the chart's own values.yaml.
+const ValueSourceChartDefaults = "chart defaults"
+
+// ValueProvenance is one leaf of the merged values and the last source that set it.
+type ValueProvenance struct {
+	Path   string `json:"path"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// HookStep is one Helm hook in the order Helm executes it for an event.
+type HookStep struct {
+	Event          string   `json:"event"`
+	Order          int      `json:"order"`
+	Weight         int      `json:"weight"`
+	Kind           string   `json:"kind"`
+	Name           string   `json:"name"`
+	Path           string   `json:"path,omitempty"`
+	DeletePolicies []string `json:"deletePolicies,omitempty"`
+}
+
+// hookEventOrder lists hook events in release lifecycle order.
+var hookEventOrder = []release.HookEvent{
+	release.HookPreInstall,
+	release.HookPostInstall,
+	release.HookPreUpgrade,
+	release.HookPostUpgrade,
+	release.HookPreRollback,
+	release.HookPostRollback,
+	release.HookPreDelete,
+	release.HookPostDelete,
+	release.HookTest,
+}
+
+// ValuesProvenance merges the chart defaults with the user-supplied values and reports every
+// leaf with its origin. Secret references are reported unresolved so the output is safe to share.
+func ValuesProvenance(settings *cli.EnvSettings, ch *chart.Chart, files, setVals, setStringVals, setFileVals, setJSONVals, setLiteralVals []string) ([]ValueProvenance, error) {
+	providers := getter.All(settings, netconfig.GetterOptions()...)
+	type layer struct {
+		source string
+		opts   cliValues.Options
+	}
+	var layers []layer
+	for _, f := range files {
+		layers = append(layers, layer{source: "values file " + f, opts: cliValues.Options{ValueFiles: []string{f}}})
+	}
+	for _, v := range setJSONVals {
+		layers = append(layers, layer{source: "--set-json " + v, opts: cliValues.Options{JSONValues: []string{v}}})
+	}
+	for _, v := range setVals {
+		layers = append(layers, layer{source: "--set " + v, opts: cliValues.Options{Values: []string{v}}})
+	}
+	for _, v := range setStringVals {
+		layers = append(layers, layer{source: "--set-string " + v, opts: cliValues.Options{StringValues: []string{v}}})
+	}
+	for _, v := range setFileVals {
+		layers = append(layers, layer{source: "--set-file " + v, opts: cliValues.Options{FileValues: []string{v}}})
+	}
+	for _, v := range setLiteralVals {
+		layers = append(layers, layer{source: "--set-literal " + v, opts: cliValues.Options{LiteralValues: []string{v}}})
+	}
+
+	sources := map[string]string{}
+	for _, l := range layers {
+		vals, err := l.opts.MergeValues(providers)
+		if err != nil {
+			return nil, fmt.Errorf("merge values (%s): %w", l.source, err)
+		}
+		for path := range flattenValues("", vals) {
+			sources[path] = l.source
+		}
+	}
+
+	userVals, err := MergeUserValues(settings, files, setVals, setStringVals, setFileVals, setJSONVals, setLiteralVals)
+	if err != nil {
+		return nil, err
+	}
+	merged := userVals
+	if ch != nil {
+		coalesced, err := chartutil.CoalesceValues(ch, userVals)
+		if err != nil {
+			return nil, fmt.Errorf("coalesce chart values: %w", err)
+		}
+		merged = coalesced
+	}
+
+	leaves := flattenValues("", merged)
+	out := make([]ValueProvenance, 0, len(leaves))
+	for path, value := range leaves {
+		source, ok := sources[path]
+		if !ok {
+			source = ValueSourceChartDefaults
+		}
+		out = append(out, ValueProvenance{Path: path, Value: value, Source: source})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out, nil
+}
+
+// MergeUserValues merges values files and --set style overrides the way helm does, without chart
+// defaults and with secret and valuefrom:// references left unresolved.
+func MergeUserValues(settings *cli.EnvSettings, files, setVals, setStringVals, setFileVals, setJSONVals, setLiteralVals []string) (map[string]interface{}, error) {
+	combined := cliValues.Options{ValueFiles: files, JSONValues: setJSONVals, Values: setVals, StringValues: setStringVals, FileValues: setFileVals, LiteralValues: setLiteralVals}
+	vals, err := combined.MergeValues(getter.All(settings, netconfig.GetterOptions()...))
+	if err != nil {
+		return nil, fmt.Errorf("merge values: %w", err)
+	}
+	return vals, nil
+}
+
+// flattenValues maps dotted leaf paths (list items as [i]) to their YAML-encoded values.
+func flattenValues(prefix string, v interface{}) map[string]string {
+	out := map[string]string{}
+	var walk func(path string, v interface{})
+	walk = func(path string, v interface{}) {
+		switch typed := v.(type) {
+		case map[string]interface{}:
+			if len(typed) == 0 && path != "" {
+				out[path] = "{}"
+				return
+			}
+			for k, child := range typed {
+				next := k
+				if path != "" {
+					next = path + "." + k
+				}
+				walk(next, child)
+			}
+		case chartutil.Values:
+			walk(path, map[string]interface{}(typed))
+		case []interface{}:
+			if len(typed) == 0 {
+				out[path] = "[]"
+				return
+			}
+			for i, child := range typed {
+				walk(fmt.Sprintf("%s[%d]", path, i), child)
+			}
+		default:
+			out[path] = scalarString(typed)
+		}
+	}
+	walk(prefix, v)
+	return out
+}
+
+func scalarString(v interface{}) string {
+	switch typed := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return typed
+	}
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// HookExecutionOrder lists hooks per lifecycle event in the order Helm runs them
+// (ascending weight, then name).
+func HookExecutionOrder(hooks []*release.Hook) []HookStep {
+	var out []HookStep
+	for _, event := range hookEventOrder {
+		var matched []*release.Hook
+		for _, h := range hooks {
+			if h == nil {
+				continue
+			}
+			for _, e := range h.Events {
+				if e == event {
+					matched = append(matched, h)
+					break
+				}
+			}
+		}
+		sort.SliceStable(matched, func(i, j int) bool {
+			if matched[i].Weight == matched[j].Weight {
+				return matched[i].Name < matched[j].Name
+			}
+			return matched[i].Weight < matched[j].Weight
+		})
+		for i, h := range matched {
+			step := HookStep{
+				Event:  event.String(),
+				Order:  i + 1,
+				Weight: h.Weight,
+				Kind:   h.Kind,
+				Name:   h.Name,
+				Path:   h.Path,
+			}
+			for _, p := range h.DeletePolicies {
+				step.DeletePolicies = append(step.DeletePolicies, p.String())
+			}
+			out = append(out, step)
+		}
+	}
+	return out
+}
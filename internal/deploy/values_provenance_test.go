@@ -0,0 +1,79 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestValuesProvenanceTracksLastSource(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	prod := filepath.Join(dir, "prod.yaml")
+	if err := os.WriteFile(base, []byte("image:\n  tag: v1\nreplicas: 2\n"), 0o600); err != nil {
+		t.Fatalf("write base: %v", err)
+	}
+	if err := os.WriteFile(prod, []byte("replicas: 4\n"), 0o600); err != nil {
+		t.Fatalf("write prod: %v", err)
+	}
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "demo", Version: "0.1.0"},
+		Values: map[string]interface{}{
+			"image":    map[string]interface{}{"repository": "nginx", "tag": "latest"},
+			"replicas": 1,
+			"db":       map[string]interface{}{"password": ""},
+		},
+	}
+	got, err := ValuesProvenance(cli.New(), ch, []string{base, prod}, []string{"image.tag=v2"}, nil, []string{}, nil, nil)
+	if err != nil {
+		t.Fatalf("values provenance: %v", err)
+	}
+	want := map[string][2]string{
+		"db.password":      {"", ValueSourceChartDefaults},
+		"image.repository": {"nginx", ValueSourceChartDefaults},
+		"image.tag":        {"v2", "--set image.tag=v2"},
+		"replicas":         {"4", "values file " + prod},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d leaves, got %+v", len(want), got)
+	}
+	for _, v := range got {
+		exp, ok := want[v.Path]
+		if !ok {
+			t.Fatalf("unexpected leaf %+v", v)
+		}
+		if v.Value != exp[0] || v.Source != exp[1] {
+			t.Fatalf("leaf %s: expected %q from %q, got %q from %q", v.Path, exp[0], exp[1], v.Value, v.Source)
+		}
+	}
+}
+
+func TestHookExecutionOrderSortsByEventAndWeight(t *testing.T) {
+	hooks := []*release.Hook{
+		{Name: "migrate", Kind: "Job", Weight: 5, Events: []release.HookEvent{release.HookPreUpgrade, release.HookPreInstall}},
+		{Name: "smoke", Kind: "Pod", Events: []release.HookEvent{release.HookTest}},
+		{Name: "b-config", Kind: "ConfigMap", Weight: -1, Events: []release.HookEvent{release.HookPreInstall}, DeletePolicies: []release.HookDeletePolicy{release.HookBeforeHookCreation}},
+		{Name: "a-config", Kind: "ConfigMap", Weight: -1, Events: []release.HookEvent{release.HookPreInstall}},
+	}
+	steps := HookExecutionOrder(hooks)
+	var got []string
+	for _, s := range steps {
+		got = append(got, s.Event+":"+s.Name)
+	}
+	want := []string{"pre-install:a-config", "pre-install:b-config", "pre-install:migrate", "pre-upgrade:migrate", "test:smoke"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+	if steps[1].Order != 2 || len(steps[1].DeletePolicies) != 1 {
+		t.Fatalf("unexpected step metadata: %+v", steps[1])
+	}
+}
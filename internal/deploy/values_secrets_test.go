@@ -2,12 +2,16 @@ package deploy
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/kubekattle/ktl/internal/appconfig"
 	"github.com/kubekattle/ktl/internal/secretstore"
+	"github.com/kubekattle/ktl/internal/valuesource"
 	"helm.sh/helm/v3/pkg/cli"
 )
 
@@ -27,7 +31,7 @@ func TestBuildValuesResolvesSecretRefs(t *testing.T) {
 		t.Fatalf("new resolver: %v", err)
 	}
 	var audit secretstore.AuditReport
-	values, err := buildValues(context.Background(), cli.New(), nil, []string{"db.password=secret://local/db/password"}, nil, nil, &SecretOptions{
+	values, err := buildValues(context.Background(), cli.New(), nil, []string{"db.password=secret://local/db/password"}, nil, nil, nil, nil, &SecretOptions{
 		Resolver: resolver,
 		AuditSink: func(report secretstore.AuditReport) {
 			audit = report
@@ -49,7 +53,7 @@ func TestBuildValuesResolvesSecretRefs(t *testing.T) {
 }
 
 func TestBuildValuesErrorsWithoutResolver(t *testing.T) {
-	_, err := buildValues(context.Background(), cli.New(), nil, []string{"db.password=secret://local/db/password"}, nil, nil, nil)
+	_, err := buildValues(context.Background(), cli.New(), nil, []string{"db.password=secret://local/db/password"}, nil, nil, nil, nil, nil)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -57,3 +61,28 @@ func TestBuildValuesErrorsWithoutResolver(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestBuildValuesResolvesValueSourceRefs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("3"))
+	}))
+	defer srv.Close()
+	sources, err := valuesource.New(map[string]appconfig.ValueSource{
+		"inventory": {Type: "http", URL: srv.URL, Format: "json"},
+	})
+	if err != nil {
+		t.Fatalf("new value sources: %v", err)
+	}
+	values, err := buildValues(context.Background(), cli.New(), nil, nil, []string{"replicas=valuefrom://inventory/api/replicas"}, nil, nil, nil, &SecretOptions{ValueSources: sources})
+	if err != nil {
+		t.Fatalf("build values: %v", err)
+	}
+	if got := values["replicas"]; got != float64(3) {
+		t.Fatalf("replicas=%#v, want 3", got)
+	}
+
+	_, err = buildValues(context.Background(), cli.New(), nil, nil, []string{"replicas=valuefrom://inventory/api/replicas"}, nil, nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "values replicas") || !strings.Contains(err.Error(), "no value sources configured") {
+		t.Fatalf("expected an error without value sources, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+// File: internal/deploy/values_sources.go
+// Brief: Internal deploy package implementation for 'values sources'.
+
+// values_sources.go turns `-f` arguments that are not local files (stdin, https://, oci://) into
+// temporary files, verifying an optional `#sha256=<hex>` pin, so the rest of the deploy path can
+// keep treating values as paths.
+package deploy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/netconfig"
+	"github.com/kubekattle/ktl/internal/secretstore"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"helm.sh/helm/v3/pkg/cli"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// ValuesStdin is the `-f` argument that reads values from standard input.
+const ValuesStdin = "-"
+
+const valuesPinPrefix = "#sha256="
+
+// maxValuesSourceBytes bounds a single fetched values document.
+const maxValuesSourceBytes = 16 << 20
+
+// SplitValuesPin separates a values source from its `#sha256=<hex>` pin, if any.
+func SplitValuesPin(src string) (ref, pin string) {
+	src = strings.TrimSpace(src)
+	if i := strings.LastIndex(src, valuesPinPrefix); i >= 0 {
+		return src[:i], strings.ToLower(strings.TrimSpace(src[i+len(valuesPinPrefix):]))
+	}
+	return src, ""
+}
+
+// IsRemoteValuesSource reports whether src must be fetched rather than read from disk.
+func IsRemoteValuesSource(src string) bool {
+	ref, _ := SplitValuesPin(src)
+	ref = strings.ToLower(ref)
+	return strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "oci://")
+}
+
+// CheckValuesSource validates a values source without reading it: pins must be well formed, and
+// plain http:// is refused because values often carry credentials and an unpinned document
+// could be swapped in transit.
+func CheckValuesSource(src string) error {
+	ref, pin := SplitValuesPin(src)
+	if strings.HasPrefix(strings.ToLower(ref), "http://") {
+		return fmt.Errorf("values %s: plain http:// is not supported; use https://", ref)
+	}
+	if pin != "" {
+		if b, err := hex.DecodeString(pin); err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("values %s: pin must be #sha256=<64 hex chars>", ref)
+		}
+	}
+	return nil
+}
+
+// ValuesSourceOptions configures ResolveValuesSources.
+type ValuesSourceOptions struct {
+	Settings *cli.EnvSettings
+	// Stdin backs the "-" source. It is read at most once.
+	Stdin   io.Reader
+	Secrets *SecretOptions
+	// Dir receives the materialized files. Defaults to a new temporary directory removed by cleanup.
+	Dir string
+	// HTTPClient fetches https:// sources. Defaults to the proxy-aware netconfig client.
+	HTTPClient *http.Client
+}
+
+// ResolveValuesSources returns sources with stdin and remote entries replaced by local files. Local
+// paths are passed through, after checking their pin when one is given. cleanup removes any
+// temporary directory it created and is never nil.
+func ResolveValuesSources(ctx context.Context, sources []string, opts ValuesSourceOptions) (files []string, cleanup func(), err error) {
+	cleanup = func() {}
+	dir := strings.TrimSpace(opts.Dir)
+	usedStdin := false
+	for _, src := range sources {
+		if err := CheckValuesSource(src); err != nil {
+			return nil, cleanup, err
+		}
+		ref, pin := SplitValuesPin(src)
+		var data []byte
+		switch {
+		case ref == ValuesStdin:
+			if usedStdin {
+				return nil, cleanup, fmt.Errorf("values from stdin (-f -) can only be given once")
+			}
+			usedStdin = true
+			if opts.Stdin == nil {
+				return nil, cleanup, fmt.Errorf("values from stdin requested but no stdin is available")
+			}
+			data, err = io.ReadAll(io.LimitReader(opts.Stdin, maxValuesSourceBytes+1))
+		case IsRemoteValuesSource(ref):
+			data, err = fetchValuesSource(ctx, ref, opts)
+		default:
+			if pin != "" {
+				data, err = os.ReadFile(ref)
+				if err == nil {
+					err = verifyValuesPin(ref, data, pin)
+				}
+				if err != nil {
+					return nil, cleanup, err
+				}
+			}
+			files = append(files, ref)
+			continue
+		}
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("values %s: %w", valuesSourceLabel(ref), err)
+		}
+		if len(data) > maxValuesSourceBytes {
+			return nil, cleanup, fmt.Errorf("values %s: larger than %d bytes", valuesSourceLabel(ref), maxValuesSourceBytes)
+		}
+		if err := verifyValuesPin(valuesSourceLabel(ref), data, pin); err != nil {
+			return nil, cleanup, err
+		}
+		if dir == "" {
+			tmp, err := os.MkdirTemp("", "ktl-values-*")
+			if err != nil {
+				return nil, cleanup, err
+			}
+			dir = tmp
+			cleanup = func() { _ = os.RemoveAll(tmp) }
+		}
+		path, err := WriteValuesCacheFile(dir, data)
+		if err != nil {
+			return nil, cleanup, err
+		}
+		files = append(files, path)
+	}
+	return files, cleanup, nil
+}
+
+// WriteValuesCacheFile stores data in dir under its content digest and returns the path.
+func WriteValuesCacheFile(dir string, data []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create values dir: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	path := filepath.Join(dir, hex.EncodeToString(sum[:])+".yaml")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("write values: %w", err)
+	}
+	return path, nil
+}
+
+// fetchValuesSource downloads an https:// or oci:// values document. OCI artifacts use their
+// first YAML or JSON layer (or the first layer) and authenticate like chart pulls.
+func fetchValuesSource(ctx context.Context, ref string, opts ValuesSourceOptions) ([]byte, error) {
+	if err := netconfig.CheckURL(ref); err != nil {
+		return nil, err
+	}
+	settings := opts.Settings
+	if settings == nil {
+		settings = cli.New()
+	}
+	if strings.HasPrefix(strings.ToLower(ref), "oci://") {
+		var resolver *secretstore.Resolver
+		if opts.Secrets != nil {
+			resolver = opts.Secrets.Resolver
+		}
+		return fetchOCIValues(ctx, settings, resolver, strings.TrimPrefix(ref, "oci://"))
+	}
+	client := opts.HTTPClient
+	if client == nil {
+		client = netconfig.HTTPClient(60 * time.Second)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET returned %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxValuesSourceBytes+1))
+}
+
+func fetchOCIValues(ctx context.Context, settings *cli.EnvSettings, resolver *secretstore.Resolver, ref string) ([]byte, error) {
+	repo, err := remote.NewRepository(ref)
+	if err != nil {
+		return nil, err
+	}
+	repo.Client = registryAuthClient(settings, resolver)
+	desc, raw, err := oras.FetchBytes(ctx, repo, repo.Reference.Reference, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return nil, err
+	}
+	if desc.MediaType != ocispec.MediaTypeImageManifest {
+		return nil, fmt.Errorf("unsupported artifact media type %s (expected an OCI image manifest)", desc.MediaType)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("artifact has no layers")
+	}
+	layer := manifest.Layers[0]
+	for _, l := range manifest.Layers {
+		if strings.Contains(l.MediaType, "yaml") || strings.Contains(l.MediaType, "json") {
+			layer = l
+			break
+		}
+	}
+	if layer.Size > maxValuesSourceBytes {
+		return nil, fmt.Errorf("layer is larger than %d bytes", maxValuesSourceBytes)
+	}
+	return content.FetchAll(ctx, repo, layer)
+}
+
+func verifyValuesPin(label string, data []byte, pin string) error {
+	if pin == "" {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != pin {
+		return fmt.Errorf("values %s: sha256 mismatch (pinned %s, got %s)", label, pin, got)
+	}
+	return nil
+}
+
+func valuesSourceLabel(ref string) string {
+	if ref == ValuesStdin {
+		return "from stdin"
+	}
+	return ref
+}
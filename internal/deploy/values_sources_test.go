@@ -0,0 +1,85 @@
+package deploy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestResolveValuesSources_StdinAndURL(t *testing.T) {
+	const remote = "replicaCount: 3\n"
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(remote))
+	}))
+	defer srv.Close()
+
+	local := filepath.Join(t.TempDir(), "base.yaml")
+	if err := os.WriteFile(local, []byte("a: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sources := []string{local, ValuesStdin, srv.URL + "/values.yaml#sha256=" + sha256Hex(remote)}
+	files, cleanup, err := ResolveValuesSources(context.Background(), sources, ValuesSourceOptions{Stdin: strings.NewReader("image: {tag: v2}\n"), HTTPClient: srv.Client()})
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if len(files) != 3 || files[0] != local {
+		t.Fatalf("files=%v", files)
+	}
+	for i, want := range []string{"image: {tag: v2}\n", remote} {
+		got, err := os.ReadFile(files[i+1])
+		if err != nil || string(got) != want {
+			t.Fatalf("file %d=%q,%v want %q", i+1, got, err, want)
+		}
+	}
+	dir := filepath.Dir(files[1])
+	cleanup()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected cleanup to remove %s", dir)
+	}
+}
+
+func TestResolveValuesSources_Errors(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("x: 1\n"))
+	}))
+	defer srv.Close()
+	local := filepath.Join(t.TempDir(), "v.yaml")
+	if err := os.WriteFile(local, []byte("x: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string][]string{
+		"stdin twice":  {ValuesStdin, ValuesStdin},
+		"bad pin":      {srv.URL + "#sha256=abc"},
+		"url mismatch": {srv.URL + "#sha256=" + sha256Hex("other")},
+		"local pin":    {local + "#sha256=" + sha256Hex("other")},
+		"plain http":   {"http://config.example.com/values.yaml"},
+	}
+	for name, sources := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, cleanup, err := ResolveValuesSources(context.Background(), sources, ValuesSourceOptions{Stdin: strings.NewReader(""), HTTPClient: srv.Client()})
+			defer cleanup()
+			if err == nil {
+				t.Fatalf("expected error for %v", sources)
+			}
+		})
+	}
+
+	files, cleanup, err := ResolveValuesSources(context.Background(), []string{local + "#sha256=" + sha256Hex("x: 1\n")}, ValuesSourceOptions{})
+	defer cleanup()
+	if err != nil || len(files) != 1 || files[0] != local {
+		t.Fatalf("pinned local=%v,%v", files, err)
+	}
+}
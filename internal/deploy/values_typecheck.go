@@ -0,0 +1,318 @@
+// File: internal/deploy/values_typecheck.go
+// Brief: Internal deploy package implementation for 'values typecheck'.
+
+// values_typecheck.go checks --set style overrides against the chart's values.schema.json before
+// rendering, so "true" vs true and 1.20 vs "1.20" surprises name the flag that caused them.
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/strvals"
+)
+
+const valuesSchemaMismatch = "values do not match the chart schema"
+
+// SetValueTypeError is one --set override whose parsed type does not match the chart schema.
+type SetValueTypeError struct {
+	Flag     string
+	Arg      string
+	Path     string
+	Expected []string
+	Got      interface{}
+}
+
+func (e SetValueTypeError) Error() string {
+	return fmt.Sprintf("%s %s: values.schema.json expects %s at %s, got %s%s", e.Flag, e.Arg, strings.Join(e.Expected, " or "), e.Path, describeSetValue(e.Got), typeHint(e.Flag, e.Expected))
+}
+
+// CheckSetValueTypes parses each --set, --set-string, --set-json and --set-literal override on its
+// own and compares every leaf with the type the chart's values.schema.json (or a subchart's)
+// declares at that path. Paths the schema does not describe are not checked.
+func CheckSetValueTypes(ch *chart.Chart, setVals, setStringVals, setJSONVals, setLiteralVals []string) error {
+	if ch == nil || !chartHasSchema(ch) {
+		return nil
+	}
+	parsers := []struct {
+		flag  string
+		args  []string
+		parse func(string, map[string]interface{}) error
+	}{
+		{"--set", setVals, strvals.ParseInto},
+		{"--set-string", setStringVals, strvals.ParseIntoString},
+		{"--set-json", setJSONVals, strvals.ParseJSON},
+		{"--set-literal", setLiteralVals, strvals.ParseLiteralInto},
+	}
+	var problems []string
+	for _, p := range parsers {
+		for _, arg := range p.args {
+			parsed := map[string]interface{}{}
+			if err := p.parse(arg, parsed); err != nil {
+				return fmt.Errorf("failed parsing %s data: %w", p.flag, err)
+			}
+			for path, val := range setValueLeaves("", parsed) {
+				if val == nil {
+					// null removes the key; there is nothing to type-check.
+					continue
+				}
+				expected := schemaTypesAt(ch, splitValuesPath(path))
+				if len(expected) == 0 || schemaAccepts(expected, val) {
+					continue
+				}
+				problems = append(problems, SetValueTypeError{Flag: p.flag, Arg: arg, Path: path, Expected: expected, Got: val}.Error())
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("%s:\n  %s", valuesSchemaMismatch, strings.Join(problems, "\n  "))
+}
+
+// IsValuesSchemaError reports whether err is a values type error from CheckSetValueTypes or a
+// values.schema.json validation failure reported by helm.
+func IsValuesSchemaError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, valuesSchemaMismatch) || strings.Contains(msg, "values don't meet the specifications of the schema")
+}
+
+func chartHasSchema(ch *chart.Chart) bool {
+	if len(ch.Schema) > 0 {
+		return true
+	}
+	for _, dep := range ch.Dependencies() {
+		if chartHasSchema(dep) {
+			return true
+		}
+	}
+	return false
+}
+
+// setValueLeaves flattens parsed overrides to path -> value. Lists and maps produced by --set-json
+// are leaves too, so their type is checked as a whole.
+func setValueLeaves(prefix string, vals map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	for k, v := range vals {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if child, ok := v.(map[string]interface{}); ok && len(child) > 0 {
+			for p, leaf := range setValueLeaves(path, child) {
+				out[p] = leaf
+			}
+			continue
+		}
+		if list, ok := v.([]interface{}); ok {
+			for i, item := range list {
+				if item == nil {
+					continue
+				}
+				itemPath := fmt.Sprintf("%s[%d]", path, i)
+				if child, ok := item.(map[string]interface{}); ok && len(child) > 0 {
+					for p, leaf := range setValueLeaves(itemPath, child) {
+						out[p] = leaf
+					}
+					continue
+				}
+				out[itemPath] = item
+			}
+			if len(list) > 0 {
+				continue
+			}
+		}
+		out[path] = v
+	}
+	return out
+}
+
+// splitValuesPath splits a.b[0].c into a, b, [0], c.
+func splitValuesPath(path string) []string {
+	var parts []string
+	for _, seg := range strings.Split(path, ".") {
+		for {
+			i := strings.IndexByte(seg, '[')
+			if i < 0 {
+				break
+			}
+			if i > 0 {
+				parts = append(parts, seg[:i])
+			}
+			j := strings.IndexByte(seg, ']')
+			if j < i {
+				break
+			}
+			parts = append(parts, seg[i:j+1])
+			seg = seg[j+1:]
+		}
+		if seg != "" {
+			parts = append(parts, seg)
+		}
+	}
+	return parts
+}
+
+// schemaTypesAt returns the JSON schema types allowed at path, descending into subchart schemas
+// when the parent schema does not describe the subchart key.
+func schemaTypesAt(ch *chart.Chart, path []string) []string {
+	if len(path) == 0 {
+		return nil
+	}
+	if len(ch.Schema) > 0 {
+		var schema map[string]interface{}
+		if err := json.Unmarshal(ch.Schema, &schema); err == nil {
+			if node := schemaNodeAt(schema, path); node != nil {
+				return schemaTypes(node)
+			}
+		}
+	}
+	for _, dep := range ch.Dependencies() {
+		if dep.Name() == path[0] {
+			return schemaTypesAt(dep, path[1:])
+		}
+	}
+	return nil
+}
+
+func schemaNodeAt(node map[string]interface{}, path []string) map[string]interface{} {
+	for _, seg := range path {
+		var next map[string]interface{}
+		if strings.HasPrefix(seg, "[") {
+			next, _ = node["items"].(map[string]interface{})
+		} else {
+			if props, ok := node["properties"].(map[string]interface{}); ok {
+				next, _ = props[seg].(map[string]interface{})
+			}
+			if next == nil {
+				next, _ = node["additionalProperties"].(map[string]interface{})
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+func schemaTypes(node map[string]interface{}) []string {
+	var types []string
+	switch typed := node["type"].(type) {
+	case string:
+		types = append(types, typed)
+	case []interface{}:
+		for _, t := range typed {
+			if s, ok := t.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		alts, ok := node[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, alt := range alts {
+			altNode, ok := alt.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			altTypes := schemaTypes(altNode)
+			if len(altTypes) == 0 {
+				// An alternative without a type accepts anything.
+				return nil
+			}
+			types = append(types, altTypes...)
+		}
+	}
+	return types
+}
+
+func schemaAccepts(types []string, v interface{}) bool {
+	for _, t := range types {
+		switch t {
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "integer":
+			switch n := v.(type) {
+			case int, int64:
+				return true
+			case float64:
+				if n == math.Trunc(n) {
+					return true
+				}
+			}
+		case "number":
+			switch v.(type) {
+			case int, int64, float64:
+				return true
+			}
+		case "object":
+			if _, ok := v.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]interface{}); ok {
+				return true
+			}
+		case "null":
+			if v == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func describeSetValue(v interface{}) string {
+	switch typed := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("string %q", typed)
+	case bool:
+		return fmt.Sprintf("boolean %t", typed)
+	case int, int64, float64:
+		return fmt.Sprintf("number %v", typed)
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func typeHint(flag string, expected []string) string {
+	wantsString := false
+	for _, t := range expected {
+		wantsString = wantsString || t == "string"
+	}
+	switch {
+	case flag == "--set-string" || flag == "--set-literal":
+		return " (use --set or --set-json for typed values)"
+	case flag == "--set-json" && wantsString:
+		return " (quote it to pass a JSON string)"
+	case flag == "--set-json":
+		return " (JSON strings are quoted; drop the quotes for numbers and booleans)"
+	case wantsString:
+		return " (use --set-string or --set-literal to keep it a string)"
+	default:
+		return " (use --set-json for an exact JSON value)"
+	}
+}
@@ -0,0 +1,66 @@
+package deploy
+
+import (
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestCheckSetValueTypes(t *testing.T) {
+	sub := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "redis", Version: "1.0.0"},
+		Schema:   []byte(`{"properties": {"port": {"type": "integer"}}}`),
+	}
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "demo", Version: "0.1.0"},
+		Schema: []byte(`{
+  "type": "object",
+  "properties": {
+    "enabled": {"type": "boolean"},
+    "replicas": {"type": "integer"},
+    "image": {"properties": {"tag": {"type": "string"}}},
+    "ports": {"type": "array", "items": {"properties": {"number": {"type": "integer"}}}},
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+    "timeout": {"anyOf": [{"type": "string"}, {"type": "integer"}]}
+  }
+}`),
+	}
+	ch.AddDependency(sub)
+
+	ok := [][]string{
+		{"enabled=true", "replicas=3", "ports[0].number=80", "timeout=30", "unknown.key=whatever", "enabled=null"},
+		{"image.tag=1.20", "labels.team=core", "timeout=30s"},
+		{"replicas=3", "ports=[{\"number\": 8080}]", "redis.port=6379"},
+		{"image.tag=v1,2"},
+	}
+	if err := CheckSetValueTypes(ch, ok[0], ok[1], ok[2], ok[3]); err != nil {
+		t.Fatalf("expected matching overrides to pass, got %v", err)
+	}
+
+	err := CheckSetValueTypes(ch,
+		[]string{"image.tag=123", "labels.tier=false"},
+		[]string{"enabled=true"},
+		[]string{"replicas=\"3\"", "redis.port=\"6379\""},
+		[]string{"enabled=false"},
+	)
+	if !IsValuesSchemaError(err) {
+		t.Fatalf("expected a values schema error, got %v", err)
+	}
+	for _, want := range []string{
+		`--set image.tag=123: values.schema.json expects string at image.tag, got number 123 (use --set-string or --set-literal to keep it a string)`,
+		`--set labels.tier=false: values.schema.json expects string at labels.tier, got boolean false`,
+		`--set-string enabled=true: values.schema.json expects boolean at enabled, got string "true" (use --set or --set-json for typed values)`,
+		`--set-json replicas="3": values.schema.json expects integer at replicas, got string "3" (JSON strings are quoted; drop the quotes for numbers and booleans)`,
+		`--set-json redis.port="6379": values.schema.json expects integer at redis.port`,
+		`--set-literal enabled=false: values.schema.json expects boolean at enabled`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("missing %q in:\n%v", want, err)
+		}
+	}
+
+	if err := CheckSetValueTypes(&chart.Chart{Metadata: &chart.Metadata{Name: "plain"}}, []string{"enabled=true"}, nil, nil, nil); err != nil {
+		t.Fatalf("charts without a schema are not checked, got %v", err)
+	}
+}
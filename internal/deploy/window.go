@@ -0,0 +1,258 @@
+// File: internal/deploy/window.go
+// Brief: Internal deploy package implementation for 'window'.
+
+// window.go enforces maintenance windows: cron-like schedules, per kube context and namespace,
+// outside of which applies are refused.
+package deploy
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+)
+
+// Schedule is a five-field cron expression (minute hour day-of-month month day-of-week) whose
+// matching minutes are the times a deploy is allowed. "* 9-16 * * mon-fri" allows weekdays from
+// 09:00 to 16:59.
+type Schedule struct {
+	expr    string
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}},
+	{name: "day-of-week", min: 0, max: 7, names: map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}},
+}
+
+// ParseSchedule parses a five-field cron expression. Fields accept *, lists, ranges, steps
+// (*/15, 9-17/2), and month and weekday names; 0 and 7 are both Sunday.
+func ParseSchedule(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("schedule %q: expected 5 fields (minute hour day-of-month month day-of-week), got %d", expr, len(fields))
+	}
+	var bits [5]uint64
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", expr, err)
+		}
+		bits[i] = b
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &Schedule{
+		expr:    strings.Join(fields, " "),
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*" || fields[2] == "?",
+		dowStar: fields[4] == "*" || fields[4] == "?",
+	}, nil
+}
+
+func parseCronField(raw string, f cronField) (uint64, error) {
+	var out uint64
+	for _, part := range strings.Split(raw, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step in %q", f.name, part)
+			}
+			rng, step = part[:i], n
+		}
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*" || rng == "?":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = cronValue(a, f); err != nil {
+				return 0, err
+			}
+			if hi, err = cronValue(b, f); err != nil {
+				return 0, err
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("%s: range %q is reversed", f.name, rng)
+			}
+		default:
+			v, err := cronValue(rng, f)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			out |= 1 << uint(v)
+		}
+	}
+	return out, nil
+}
+
+func cronValue(raw string, f cronField) (int, error) {
+	if v, ok := f.names[strings.ToLower(raw)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s: %q is not between %d and %d", f.name, raw, f.min, f.max)
+	}
+	return v, nil
+}
+
+// String returns the normalized expression.
+func (s *Schedule) String() string { return s.expr }
+
+// Matches reports whether the minute containing t is allowed.
+func (s *Schedule) Matches(t time.Time) bool {
+	return s.minute&(1<<uint(t.Minute())) != 0 &&
+		s.hour&(1<<uint(t.Hour())) != 0 &&
+		s.month&(1<<uint(t.Month())) != 0 &&
+		s.dayMatches(t)
+}
+
+// dayMatches follows cron: when both day fields are restricted, either may match.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first allowed minute at or after t, searching up to five years ahead.
+func (s *Schedule) Next(t time.Time) (time.Time, bool) {
+	// Truncate works on absolute time, so it would misalign wall-clock minutes and hours in zones
+	// whose offset is not a whole number of hours; build every step from the wall clock instead.
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// DeployWindow is a compiled deploy.windows entry of .ktl.yaml.
+type DeployWindow struct {
+	Name       string
+	Contexts   []string
+	Namespaces []string
+	Schedule   *Schedule
+	Location   *time.Location
+}
+
+// CompileWindows validates the configured windows.
+func CompileWindows(cfg []appconfig.DeployWindow) ([]DeployWindow, error) {
+	out := make([]DeployWindow, 0, len(cfg))
+	for i, w := range cfg {
+		label := strings.TrimSpace(w.Name)
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		sched, err := ParseSchedule(w.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("deploy.windows %s: %w", label, err)
+		}
+		loc := time.Local
+		if tz := strings.TrimSpace(w.Timezone); tz != "" {
+			if loc, err = time.LoadLocation(tz); err != nil {
+				return nil, fmt.Errorf("deploy.windows %s: timezone: %w", label, err)
+			}
+		}
+		for _, pattern := range append(append([]string{}, w.Contexts...), w.Namespaces...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("deploy.windows %s: invalid pattern %q", label, pattern)
+			}
+		}
+		out = append(out, DeployWindow{Name: label, Contexts: w.Contexts, Namespaces: w.Namespaces, Schedule: sched, Location: loc})
+	}
+	return out, nil
+}
+
+// Selects reports whether the window governs deploys to namespace in kubeContext. Empty pattern
+// lists select everything.
+func (w DeployWindow) Selects(kubeContext, namespace string) bool {
+	return globAny(w.Contexts, kubeContext) && globAny(w.Namespaces, namespace)
+}
+
+func globAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.TrimSpace(p), value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WindowCheck is the outcome of checking a deploy target against its windows.
+type WindowCheck struct {
+	// Windows names the windows that select the target; none means deploys are always allowed.
+	Windows []string
+	Allowed bool
+	// Next is the start of the next allowed slot when the deploy is not allowed now (zero when
+	// no window opens in the next five years).
+	Next time.Time
+}
+
+// CheckWindows reports whether a deploy to namespace in kubeContext is allowed at now. A target
+// is allowed when no window selects it or any selecting window is open.
+func CheckWindows(windows []DeployWindow, kubeContext, namespace string, now time.Time) WindowCheck {
+	res := WindowCheck{Allowed: true}
+	for _, w := range windows {
+		if !w.Selects(kubeContext, namespace) {
+			continue
+		}
+		res.Windows = append(res.Windows, w.Name)
+		local := now.In(w.Location)
+		if w.Schedule.Matches(local) {
+			return WindowCheck{Windows: []string{w.Name}, Allowed: true}
+		}
+		res.Allowed = false
+		if next, ok := w.Schedule.Next(local); ok && (res.Next.IsZero() || next.Before(res.Next)) {
+			res.Next = next
+		}
+	}
+	return res
+}
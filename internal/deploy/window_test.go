@@ -0,0 +1,121 @@
+package deploy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+)
+
+func TestParseScheduleMatches(t *testing.T) {
+	s, err := ParseSchedule("*/30 9-16 * * mon-thu")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	cases := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), true},   // Monday
+		{time.Date(2026, 3, 2, 9, 15, 0, 0, time.UTC), false}, // not on a 30-minute step
+		{time.Date(2026, 3, 5, 16, 30, 0, 0, time.UTC), true}, // Thursday
+		{time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 3, 6, 10, 0, 0, 0, time.UTC), false}, // Friday
+	}
+	for _, tc := range cases {
+		if got := s.Matches(tc.at); got != tc.want {
+			t.Errorf("Matches(%s) = %v, want %v", tc.at.Format(time.RFC3339), got, tc.want)
+		}
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "* 17-9 * * *", "* * * * funday", "*/0 * * * *"} {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("ParseSchedule(%q): expected error", expr)
+		}
+	}
+}
+
+func TestScheduleDayFieldsUseOr(t *testing.T) {
+	s, err := ParseSchedule("* * 1 * sun")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !s.Matches(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)) || !s.Matches(time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the 1st of the month and Sundays to match")
+	}
+	if s.Matches(time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected a Tuesday that is not the 1st not to match")
+	}
+	seven, _ := ParseSchedule("* * * * 7")
+	if !seven.Matches(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected 7 to mean Sunday")
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	s, err := ParseSchedule("* 9-16 * * mon-fri")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	// Friday 17:20 -> Monday 09:00.
+	next, ok := s.Next(time.Date(2026, 3, 6, 17, 20, 30, 0, time.UTC))
+	if !ok || !next.Equal(time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Next = %s, %v", next, ok)
+	}
+	// Half-hour offset: 08:10 in Kolkata -> 09:00 local, not 09:30.
+	kolkata := time.FixedZone("IST", 5*3600+1800)
+	next, ok = s.Next(time.Date(2026, 3, 6, 8, 10, 0, 0, kolkata))
+	if !ok || !next.Equal(time.Date(2026, 3, 6, 9, 0, 0, 0, kolkata)) {
+		t.Fatalf("Next in a half-hour zone = %s, %v", next, ok)
+	}
+	never, _ := ParseSchedule("* * 31 feb *")
+	if _, ok := never.Next(time.Date(2026, 3, 6, 17, 20, 0, 0, time.UTC)); ok {
+		t.Fatalf("expected no slot for February 31st")
+	}
+}
+
+func TestCheckWindows(t *testing.T) {
+	windows, err := CompileWindows([]appconfig.DeployWindow{
+		{Name: "prod-business-hours", Contexts: []string{"prod-*"}, Schedule: "* 9-16 * * mon-fri", Timezone: "Europe/Berlin"},
+		{Name: "payments-night", Contexts: []string{"prod-*"}, Namespaces: []string{"payments"}, Schedule: "* 2-3 * * *", Timezone: "Europe/Berlin"},
+	})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	// Saturday 12:00 UTC = 13:00 in Berlin.
+	sat := time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC)
+	if res := CheckWindows(windows, "staging", "web", sat); !res.Allowed || len(res.Windows) != 0 {
+		t.Fatalf("unselected target: %+v", res)
+	}
+	res := CheckWindows(windows, "prod-eu", "web", sat)
+	if res.Allowed || strings.Join(res.Windows, ",") != "prod-business-hours" {
+		t.Fatalf("prod on saturday: %+v", res)
+	}
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	if want := time.Date(2026, 3, 9, 9, 0, 0, 0, berlin); !res.Next.Equal(want) {
+		t.Fatalf("Next = %s, want %s", res.Next, want)
+	}
+	// payments is selected by both windows; the night window opens first.
+	res = CheckWindows(windows, "prod-eu", "payments", sat)
+	if res.Allowed || len(res.Windows) != 2 {
+		t.Fatalf("payments on saturday: %+v", res)
+	}
+	if want := time.Date(2026, 3, 8, 2, 0, 0, 0, berlin); !res.Next.Equal(want) {
+		t.Fatalf("Next = %s, want %s", res.Next, want)
+	}
+	if res := CheckWindows(windows, "prod-eu", "payments", time.Date(2026, 3, 7, 1, 30, 0, 0, time.UTC)); !res.Allowed {
+		t.Fatalf("expected the night window to allow payments: %+v", res)
+	}
+}
+
+func TestCompileWindowsErrors(t *testing.T) {
+	if _, err := CompileWindows([]appconfig.DeployWindow{{Name: "bad", Schedule: "* * * * *", Timezone: "Mars/Olympus"}}); err == nil || !strings.Contains(err.Error(), "deploy.windows bad") {
+		t.Fatalf("expected timezone error, got %v", err)
+	}
+	if _, err := CompileWindows([]appconfig.DeployWindow{{Schedule: "* * *"}}); err == nil || !strings.Contains(err.Error(), "#1") {
+		t.Fatalf("expected schedule error, got %v", err)
+	}
+}
@@ -0,0 +1,407 @@
+// File: internal/devsync/devsync.go
+// Brief: Internal devsync package implementation for 'devsync'.
+
+// Package devsync mirrors local file changes into running pods for dev-loop hot reload.
+package devsync
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/moby/patternmatcher"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Executor runs a command inside a pod container (satisfied by *kube.Client).
+type Executor interface {
+	Exec(ctx context.Context, namespace, pod, container string, command []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+// Target identifies a container that receives synced files.
+type Target struct {
+	Namespace string
+	Pod       string
+	Container string
+	// OS is the pod's operating system (kube.OSLinux or kube.OSWindows); empty means Linux.
+	OS string
+	// UID and Restarts identify the container instance: a recreated pod or restarted container
+	// has lost synced files and gets the full tree again.
+	UID      string
+	Restarts int32
+}
+
+func (t Target) String() string {
+	if t.Container == "" {
+		return t.Namespace + "/" + t.Pod
+	}
+	return t.Namespace + "/" + t.Pod + ":" + t.Container
+}
+
+// Options configures a Syncer.
+type Options struct {
+	LocalDir  string
+	RemoteDir string
+	Excludes  []string
+	ReloadCmd string
+	Interval  time.Duration
+	Out       io.Writer
+}
+
+// Stamp captures the attributes used to detect file changes.
+type Stamp struct {
+	Size    int64
+	ModTime time.Time
+	Mode    fs.FileMode
+}
+
+// Snapshot maps slash-separated relative paths to their stamps.
+type Snapshot map[string]Stamp
+
+// Change lists files to upload and delete in one sync cycle.
+type Change struct {
+	Upload []string
+	Delete []string
+}
+
+// Empty reports whether the change carries no work.
+func (c Change) Empty() bool { return len(c.Upload) == 0 && len(c.Delete) == 0 }
+
+// Scan walks root and returns a snapshot of regular files not matched by excludes.
+func Scan(root string, excludes []string) (Snapshot, error) {
+	matcher, err := patternmatcher.New(excludes)
+	if err != nil {
+		return nil, fmt.Errorf("parse excludes: %w", err)
+	}
+	snap := Snapshot{}
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if skip, _ := matcher.MatchesOrParentMatches(rel); skip {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if skip, _ := matcher.MatchesOrParentMatches(rel); skip {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		snap[rel] = Stamp{Size: info.Size(), ModTime: info.ModTime(), Mode: info.Mode().Perm()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// Diff compares two snapshots and returns the sorted files to upload and delete.
+func Diff(prev, cur Snapshot) Change {
+	var change Change
+	for rel, stamp := range cur {
+		old, ok := prev[rel]
+		if !ok || old.Size != stamp.Size || !old.ModTime.Equal(stamp.ModTime) || old.Mode != stamp.Mode {
+			change.Upload = append(change.Upload, rel)
+		}
+	}
+	for rel := range prev {
+		if _, ok := cur[rel]; !ok {
+			change.Delete = append(change.Delete, rel)
+		}
+	}
+	sort.Strings(change.Upload)
+	sort.Strings(change.Delete)
+	return change
+}
+
+// WriteTar streams the given files (relative to root) as an uncompressed tar archive.
+func WriteTar(w io.Writer, root string, files []string) error {
+	tw := tar.NewWriter(w)
+	for _, rel := range files {
+		abs := filepath.Join(root, filepath.FromSlash(rel))
+		info, err := os.Stat(abs)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		f, err := os.Open(abs)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// ResolveTargets lists running pods matching selector and pairs them with container.
+func ResolveTargets(ctx context.Context, client kubernetes.Interface, namespace, selector, container string) ([]Target, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+	var targets []Target
+	nodeOS := map[string]string{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		name := container
+		if name == "" && len(pod.Spec.Containers) > 0 {
+			name = pod.Spec.Containers[0].Name
+		}
+		if name != "" && !podHasContainer(pod, name) {
+			continue
+		}
+		podOS, ok := nodeOS[pod.Spec.NodeName]
+		if !ok {
+			podOS = kube.ResolvePodOS(ctx, client, &pod)
+			if kube.PodOS(&pod) == "" {
+				nodeOS[pod.Spec.NodeName] = podOS
+			}
+		}
+		targets = append(targets, Target{Namespace: pod.Namespace, Pod: pod.Name, Container: name, OS: podOS, UID: string(pod.UID), Restarts: containerRestarts(pod, name)})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Pod < targets[j].Pod })
+	return targets, nil
+}
+
+func containerRestarts(pod corev1.Pod, name string) int32 {
+	for _, st := range pod.Status.ContainerStatuses {
+		if st.Name == name {
+			return st.RestartCount
+		}
+	}
+	return 0
+}
+
+func podHasContainer(pod corev1.Pod, name string) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Syncer pushes local changes to a set of targets.
+type Syncer struct {
+	exec Executor
+	opts Options
+}
+
+// New returns a Syncer that uses exec to reach pods.
+func New(exec Executor, opts Options) *Syncer {
+	if opts.Interval <= 0 {
+		opts.Interval = 500 * time.Millisecond
+	}
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	return &Syncer{exec: exec, opts: opts}
+}
+
+// Apply uploads and deletes files in every target, then runs the reload command if configured.
+func (s *Syncer) Apply(ctx context.Context, targets []Target, change Change) error {
+	if change.Empty() {
+		return nil
+	}
+	var errs []error
+	for _, target := range targets {
+		if err := s.applyTarget(ctx, target, change); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", target, err))
+			fmt.Fprintf(s.opts.Out, "sync failed: %s: %v\n", target, err)
+			continue
+		}
+		fmt.Fprintf(s.opts.Out, "synced %d file(s), removed %d -> %s\n", len(change.Upload), len(change.Delete), target)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Syncer) applyTarget(ctx context.Context, target Target, change Change) error {
+	remote := s.opts.RemoteDir
+	if len(change.Upload) > 0 {
+		var buf bytes.Buffer
+		if err := WriteTar(&buf, s.opts.LocalDir, change.Upload); err != nil {
+			return fmt.Errorf("pack files: %w", err)
+		}
+		var stderr bytes.Buffer
+		cmd := []string{"tar", "-xmf", "-", "-C", remote}
+		if err := s.exec.Exec(ctx, target.Namespace, target.Pod, target.Container, cmd, &buf, io.Discard, &stderr); err != nil {
+			return fmt.Errorf("upload: %w%s", err, stderrSuffix(stderr.String()))
+		}
+	}
+	if len(change.Delete) > 0 {
+		cmd := deleteCommand(target.OS, remote, change.Delete)
+		var stderr bytes.Buffer
+		if err := s.exec.Exec(ctx, target.Namespace, target.Pod, target.Container, cmd, nil, io.Discard, &stderr); err != nil {
+			return fmt.Errorf("delete: %w%s", err, stderrSuffix(stderr.String()))
+		}
+	}
+	if reload := strings.TrimSpace(s.opts.ReloadCmd); reload != "" {
+		var stderr bytes.Buffer
+		if err := s.exec.Exec(ctx, target.Namespace, target.Pod, target.Container, kube.ShellCommand(target.OS, reload), nil, s.opts.Out, &stderr); err != nil {
+			return fmt.Errorf("reload: %w%s", err, stderrSuffix(stderr.String()))
+		}
+	}
+	return nil
+}
+
+// deleteCommand removes rels under remote with the tools available on the container's OS.
+func deleteCommand(targetOS, remote string, rels []string) []string {
+	if kube.IsWindows(targetOS) {
+		quoted := make([]string, 0, len(rels))
+		for _, rel := range rels {
+			quoted = append(quoted, `"`+kube.ContainerPath(targetOS, remote, rel)+`"`)
+		}
+		return kube.ShellCommand(targetOS, "del /F /Q "+strings.Join(quoted, " "))
+	}
+	cmd := []string{"rm", "-f", "--"}
+	for _, rel := range rels {
+		cmd = append(cmd, kube.ContainerPath(targetOS, remote, rel))
+	}
+	return cmd
+}
+
+// targetRefresh is how often Watch re-resolves targets while nothing changes locally, so replaced
+// pods get the tree without waiting for the next edit.
+const targetRefresh = 5 * time.Second
+
+// Watch polls LocalDir and applies changes to the targets returned by resolve until ctx ends.
+// When initial is true, the first cycle uploads the full tree. Targets that appear later (new or
+// restarted pods) always get the full tree before they receive diffs.
+func (s *Syncer) Watch(ctx context.Context, resolve func(context.Context) ([]Target, error), initial bool) error {
+	prev := Snapshot{}
+	// synced holds the targets whose files match prev.
+	synced := map[Target]bool{}
+	if !initial {
+		snap, err := Scan(s.opts.LocalDir, s.opts.Excludes)
+		if err != nil {
+			return err
+		}
+		prev = snap
+		targets, err := resolve(ctx)
+		if err != nil {
+			return err
+		}
+		for _, target := range targets {
+			synced[target] = true
+		}
+	}
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+	waiting := false
+	var resolvedAt time.Time
+	for {
+		cur, err := Scan(s.opts.LocalDir, s.opts.Excludes)
+		if err != nil {
+			return err
+		}
+		change := Diff(prev, cur)
+		if !change.Empty() || time.Since(resolvedAt) >= targetRefresh {
+			targets, err := resolve(ctx)
+			if err != nil {
+				return err
+			}
+			resolvedAt = time.Now()
+			switch {
+			case len(targets) == 0:
+				if !waiting {
+					fmt.Fprintln(s.opts.Out, "no running pods matched; waiting")
+					waiting = true
+				}
+			case !s.syncTargets(ctx, targets, synced, change, cur):
+				// Failures were reported per target; keep prev so the next cycle retries.
+			default:
+				waiting = false
+				prev = cur
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncTargets brings every target up to cur: targets in synced get change, new ones get the full
+// tree. Targets that are gone are dropped from synced. It reports whether every target succeeded.
+func (s *Syncer) syncTargets(ctx context.Context, targets []Target, synced map[Target]bool, change Change, cur Snapshot) bool {
+	live := make(map[Target]bool, len(targets))
+	var known []Target
+	ok := true
+	for _, target := range targets {
+		live[target] = true
+		if synced[target] {
+			known = append(known, target)
+			continue
+		}
+		if s.Apply(ctx, []Target{target}, Diff(nil, cur)) != nil {
+			ok = false
+			continue
+		}
+		synced[target] = true
+	}
+	for target := range synced {
+		if !live[target] {
+			delete(synced, target)
+		}
+	}
+	if s.Apply(ctx, known, change) != nil {
+		ok = false
+	}
+	return ok
+}
+
+func stderrSuffix(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	return ": " + s
+}
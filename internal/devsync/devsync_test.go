@@ -0,0 +1,176 @@
+package devsync
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanAndDiff(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, body string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	write("app.py", "print(1)")
+	write("lib/util.py", "x = 1")
+	write("__pycache__/app.cpython.pyc", "bytecode")
+
+	first, err := Scan(dir, []string{"__pycache__"})
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if _, ok := first["__pycache__/app.cpython.pyc"]; ok {
+		t.Fatalf("expected excluded directory to be skipped")
+	}
+	initial := Diff(nil, first)
+	if want := []string{"app.py", "lib/util.py"}; !reflect.DeepEqual(initial.Upload, want) {
+		t.Fatalf("initial upload = %v, want %v", initial.Upload, want)
+	}
+
+	write("app.py", "print(22)")
+	if err := os.Remove(filepath.Join(dir, "lib", "util.py")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	second, err := Scan(dir, []string{"__pycache__"})
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	change := Diff(first, second)
+	if !reflect.DeepEqual(change.Upload, []string{"app.py"}) || !reflect.DeepEqual(change.Delete, []string{"lib/util.py"}) {
+		t.Fatalf("unexpected change: %+v", change)
+	}
+	if !Diff(second, second).Empty() {
+		t.Fatalf("expected no change for identical snapshots")
+	}
+}
+
+type recordingExec struct {
+	commands [][]string
+	archives []string
+	// uploads records "<pod>:<archive>" for every upload.
+	uploads []string
+}
+
+func (r *recordingExec) Exec(_ context.Context, _, pod, _ string, command []string, stdin io.Reader, _, _ io.Writer) error {
+	r.commands = append(r.commands, append([]string(nil), command...))
+	if stdin != nil {
+		tr := tar.NewReader(stdin)
+		var names []string
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			names = append(names, hdr.Name)
+		}
+		r.archives = append(r.archives, strings.Join(names, ","))
+		r.uploads = append(r.uploads, pod+":"+strings.Join(names, ","))
+	}
+	return nil
+}
+
+func TestSyncerApplyUploadsDeletesAndReloads(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.rb"), []byte("puts 1"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	exec := &recordingExec{}
+	var out bytes.Buffer
+	syncer := New(exec, Options{LocalDir: dir, RemoteDir: "/app", ReloadCmd: "touch tmp/restart.txt", Interval: time.Millisecond, Out: &out})
+	target := Target{Namespace: "dev", Pod: "web-0", Container: "web"}
+	if err := syncer.Apply(context.Background(), []Target{target}, Change{Upload: []string{"main.rb"}, Delete: []string{"old.rb"}}); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	want := [][]string{
+		{"tar", "-xmf", "-", "-C", "/app"},
+		{"rm", "-f", "--", "/app/old.rb"},
+		{"sh", "-c", "touch tmp/restart.txt"},
+	}
+	if !reflect.DeepEqual(exec.commands, want) {
+		t.Fatalf("commands = %v, want %v", exec.commands, want)
+	}
+	if len(exec.archives) != 1 || exec.archives[0] != "main.rb" {
+		t.Fatalf("unexpected archive contents: %v", exec.archives)
+	}
+	if !strings.Contains(out.String(), "dev/web-0:web") {
+		t.Fatalf("expected target in output, got %q", out.String())
+	}
+}
+
+func TestSyncerApplyWindowsTarget(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.dll"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	exec := &recordingExec{}
+	syncer := New(exec, Options{LocalDir: dir, RemoteDir: `C:\app`, ReloadCmd: "iisreset", Interval: time.Millisecond})
+	target := Target{Namespace: "dev", Pod: "web-0", Container: "web", OS: "windows"}
+	if err := syncer.Apply(context.Background(), []Target{target}, Change{Upload: []string{"app.dll"}, Delete: []string{"bin/old.dll", "a b.txt"}}); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	want := [][]string{
+		{"tar", "-xmf", "-", "-C", `C:\app`},
+		{"cmd.exe", "/S", "/C", `del /F /Q "C:\app\bin\old.dll" "C:\app\a b.txt"`},
+		{"cmd.exe", "/S", "/C", "iisreset"},
+	}
+	if !reflect.DeepEqual(exec.commands, want) {
+		t.Fatalf("commands = %q, want %q", exec.commands, want)
+	}
+}
+
+func TestWatchUploadsFullTreeToNewTargets(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(1)"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	a := Target{Namespace: "dev", Pod: "web-a", Container: "web", UID: "a"}
+	b := Target{Namespace: "dev", Pod: "web-b", Container: "web", UID: "b"}
+	restarted := b
+	restarted.Restarts = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	resolve := func(context.Context) ([]Target, error) {
+		calls++
+		switch calls {
+		case 1:
+			return []Target{a}, nil
+		case 2:
+			// Edit a file so the next cycle has a diff for the known targets.
+			if err := os.WriteFile(filepath.Join(dir, "new.py"), []byte("print(2)"), 0o644); err != nil {
+				t.Errorf("write: %v", err)
+			}
+			return []Target{a, b}, nil
+		default:
+			cancel()
+			return []Target{a, restarted}, nil
+		}
+	}
+	exec := &recordingExec{}
+	syncer := New(exec, Options{LocalDir: dir, RemoteDir: "/app", Interval: time.Millisecond})
+	if err := syncer.Watch(ctx, resolve, false); err != context.Canceled {
+		t.Fatalf("watch: %v", err)
+	}
+	want := []string{"web-b:app.py", "web-b:app.py,new.py", "web-a:new.py"}
+	if !reflect.DeepEqual(exec.uploads, want) {
+		t.Fatalf("uploads = %v, want %v", exec.uploads, want)
+	}
+}
@@ -31,16 +31,56 @@ func Catalog() []VarInfo {
 			Name:        "KTL_SECRET_CONFIG",
 			Description: "Path to a secrets provider config file for resolving secret:// references.",
 		},
+		{
+			Category:    "Audit",
+			Name:        "KTL_AUDIT_LOG",
+			Description: "Path of the JSONL audit log of mutating commands (defaults to ~/.ktl/audit.jsonl).",
+		},
+		{
+			Category:    "Audit",
+			Name:        "KTL_AUDIT_ENDPOINT",
+			Description: "Also POST each audit entry as JSON to this URL.",
+		},
+		{
+			Category:    "Audit",
+			Name:        "KTL_AUDIT_TOKEN",
+			Description: "Bearer token sent to KTL_AUDIT_ENDPOINT.",
+		},
+		{
+			Category:    "Audit",
+			Name:        "KTL_AUDIT",
+			Description: "Set to off/0/false to disable audit logging.",
+		},
 		{
 			Category:    "Output",
 			Name:        "NO_COLOR",
 			Description: "Disable ANSI color output (any non-empty value).",
 		},
+		{
+			Category:    "Output",
+			Name:        "KTL_LANG",
+			Description: "Language of error hints and console labels: en (default), de, or es. Locale forms such as de_DE.UTF-8 are accepted.",
+		},
+		{
+			Category:    "Output",
+			Name:        "KTL_CONSOLE",
+			Description: "Console style (equivalent to --console): auto, or plain for sequential screen-reader-friendly progress lines.",
+		},
+		{
+			Category:    "CLI",
+			Name:        "KTL_COMPLETION_CACHE_TTL",
+			Description: "How long shell completions reuse cluster lookups (namespaces, releases), as a Go duration. Default 60s; 0 disables the cache.",
+		},
 		{
 			Category:    "CLI",
 			Name:        "KTL_YES",
 			Description: "Auto-approve confirmations (equivalent to passing --yes).",
 		},
+		{
+			Category:    "CLI",
+			Name:        "KTL_EXECUTION_PROFILE",
+			Description: "Execution profile (equivalent to --profile): dev, ci, secure (only read-only commands run), or remote.",
+		},
 		{
 			Category:    "Logging",
 			Name:        "KTL_KUBE_LOG_LEVEL",
@@ -59,7 +99,7 @@ func Catalog() []VarInfo {
 		{
 			Category:    "Profiling",
 			Name:        "KTL_PROFILE",
-			Description: "Enable profiling modes for ktl itself (e.g. startup writes CPU/heap profiles to the working directory).",
+			Description: "Enable profiling modes for ktl itself (e.g. startup writes CPU/heap profiles to the working directory and prints build/execute timings).",
 		},
 		{
 			Category:    "Features",
@@ -198,6 +238,11 @@ func Catalog() []VarInfo {
 			Name:        "KTL_STACK_PROFILE",
 			Description: "Default stack profile overlay for `ktl stack ...` when --profile is not provided.",
 		},
+		{
+			Category:    "Stack",
+			Name:        "KTL_STACK_OVERLAYS",
+			Description: "Default stack overlay files for `ktl stack ...` when -f/--overlay is not provided (comma-separated).",
+		},
 		{
 			Category:    "Stack",
 			Name:        "KTL_STACK_OUTPUT",
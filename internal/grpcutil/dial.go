@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/kubekattle/ktl/internal/netconfig"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 )
@@ -19,6 +20,9 @@ import (
 // It uses grpc.NewClient under the hood to avoid deprecated grpc.DialContext.
 func Dial(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	target = strings.TrimSpace(target)
+	if err := netconfig.CheckDialTarget(target); err != nil {
+		return nil, err
+	}
 	if target != "" && !strings.Contains(target, "://") {
 		// Preserve grpc.DialContext's default "passthrough" resolver behavior.
 		target = "passthrough:///" + target
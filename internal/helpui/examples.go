@@ -6,6 +6,14 @@ var curatedExamples = map[string][]string{
 	"ktl logs": {
 		"# Tail pods matching a regex in a namespace\nktl logs 'checkout-.*' -n prod-payments",
 		"# Highlight errors\nktl logs 'checkout-.*' -n prod-payments --highlight ERROR",
+		"# Flag novel lines and error spikes during an incident\nktl logs 'checkout-.*' -n prod-payments --anomalies",
+		"# Run a named tail profile from .ktl.yaml\nktl logs @checkout-errors",
+		"# Follow a workload's pods across rollouts\nktl logs --for deployment/checkout -n prod-payments",
+		"# Ship a tail session to an OpenTelemetry collector\nktl logs 'checkout-.*' -n prod-payments --encode otlp --endpoint http://localhost:4318",
+		"# Print per-container error counts and top repeated messages when the tail ends\nktl logs 'checkout-.*' -n prod-payments --summary",
+	},
+	"ktl logs profiles list": {
+		"# List named tail profiles from .ktl.yaml and ~/.ktl/config.yaml\nktl logs profiles list",
 	},
 	"ktl init": {
 		"# Create a repo-local .ktl.yaml\nktl init",
@@ -26,6 +34,22 @@ var curatedExamples = map[string][]string{
 	"ktl build": {
 		"# Build an image from a directory\nktl build --context . --tag ghcr.io/acme/app:dev",
 		"# Share the build stream over WebSocket\nktl build --context . --ws-listen :9085",
+		"# Report context size and .dockerignore exclusions before building\nktl build . --context-report --max-context-size 500Mi",
+	},
+	"ktl sync": {
+		"# Hot-reload ./src into running pods of a release\nktl sync --release foo --local ./src --remote /app/src",
+		"# Run a reload command after each change\nktl sync --release foo --local ./src --remote /app/src --reload-cmd 'kill -HUP 1'",
+	},
+	"ktl debug": {
+		"# Attach a debug shell to a distroless pod of a release\nktl debug --release foo -n prod",
+		"# Debug a disposable copy with a shared process namespace\nktl debug --release foo -n prod --copy --image nicolaka/netshoot",
+	},
+	"ktl traffic tap": {
+		"# Tap a release's http port and print each request\nktl traffic tap --release foo -n prod --port http",
+		"# Record into a capture DB and serve the timeline UI\nktl traffic tap --release foo --port http --capture tap.sqlite --ui :8080",
+	},
+	"ktl serve api": {
+		"# Serve releases, saved plans, and stack runs to a developer portal\nKTL_API_TOKEN=s3cr3t ktl serve api --listen :8085 --plans-dir ./plans --stack-root ./stacks/prod",
 	},
 	"ktl help": {
 		"# Launch the interactive help UI\nktl help --ui",
@@ -34,6 +58,7 @@ var curatedExamples = map[string][]string{
 	"ktl apply plan": {
 		"# Preview a Helm upgrade\nktl apply plan --chart ./chart --release foo -n default",
 		"# Render a shareable HTML visualization\nktl apply plan --visualize --chart ./chart --release foo -n default",
+		"# Live preview that re-plans when the chart changes\nktl apply plan --visualize --serve :8088 --chart ./chart --release foo -n default",
 		"# Preview with secret references\nktl apply plan --chart ./chart --release foo -n default --secret-provider local",
 		"# Preview with Vault-backed secrets\nktl apply plan --chart ./chart --release foo -n default --secret-provider vault",
 		"# Compare against a saved baseline\nktl apply plan --chart ./chart --release foo -n default --compare-to ./plan.json",
@@ -44,6 +69,31 @@ var curatedExamples = map[string][]string{
 		"# Run the deploy viewer\nktl apply --chart ./chart --release foo -n default --ui",
 		"# Deploy with secret references\nktl apply --chart ./chart --release foo -n default --secret-provider local",
 		"# Deploy with Vault-backed secrets\nktl apply --chart ./chart --release foo -n default --secret-provider vault",
+		"# Deploy without recording git commit metadata\nktl apply --chart ./chart --release foo -n default --no-git-metadata",
+		"# Feed a Terraform output into values\nktl apply --chart ./chart --release foo -n default --set db.host=tfstate://infra/terraform.tfstate#outputs.db_endpoint",
+	},
+	"ktl bootstrap": {
+		"# Apply the ordered bundle in ./bootstrap.yaml\nktl bootstrap",
+		"# Preview a bundle with server-side dry-run\nktl bootstrap --file clusters/prod/bootstrap.yaml --dry-run",
+	},
+	"ktl audit tail": {
+		"# Show the last 20 mutating commands\nktl audit tail",
+		"# Stream new audit entries as JSON\nktl audit tail --follow --output json",
+	},
+	"ktl audit search": {
+		"# Failed applies against prod in the last day\nktl audit search --command apply --kube-context prod --result failure --since 24h",
+	},
+	"ktl ctx": {
+		"# Pick a kubeconfig context with the fuzzy picker\nktl ctx",
+		"# Switch ktl (not kubectl) to staging in this shell only\neval \"$(ktl ctx --env staging)\"",
+	},
+	"ktl ns": {
+		"# Switch the default namespace of the current context\nktl ns payments",
+		"# Go back to the previous namespace\nktl ns -",
+	},
+	"ktl rbac plan": {
+		"# Minimal Role/ClusterRole for a CI deployer\nktl rbac plan --chart ./charts/web --release web-prod -n prod --service-account ci/deployer",
+		"# Resolve CRD kinds via discovery and write to a file\nktl rbac plan --chart ./charts/operator --release operator --use-cluster -o rbac.yaml",
 	},
 	"ktl delete": {
 		"# Delete a release\nktl delete --release foo -n default",
@@ -92,6 +142,10 @@ var curatedExamples = map[string][]string{
 		"# Delete the selected releases (reverse DAG order)\nktl stack delete --config ./stacks/prod --yes",
 		"# Prompt only when deleting 50+ releases\nktl stack delete --config ./stacks/prod --delete-confirm-threshold 50",
 	},
+	"ktl stack reconcile": {
+		"# Pull stacks/prod from git every 5 minutes and apply drift\nktl stack reconcile --repo https://github.com/acme/platform.git --path stacks/prod --interval 5m",
+		"# Replicated in-cluster mode (one active replica via a Lease)\nktl stack reconcile --repo https://github.com/acme/platform.git --path stacks/prod --leader-elect",
+	},
 	"ktl stack status": {
 		"# Tail the most recent run\nktl stack status --config ./stacks/prod --follow",
 		"# Show a specific run ID (see `ktl stack runs`)\nktl stack status --config ./stacks/prod --run-id 2025-12-30T12-34-56.000000000Z --follow",
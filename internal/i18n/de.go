@@ -0,0 +1,64 @@
+// File: internal/i18n/de.go
+// Brief: Internal i18n package implementation for 'de'.
+
+package i18n
+
+var german = map[Key]string{
+	ErrorLabel:           "Fehler:",
+	HintLabel:            "Hinweis:",
+	HintDeadlineExceeded: "erhöhen Sie --duration oder prüfen Sie die Netzwerkverbindung zum Cluster.",
+	HintUnauthorized:     "die kubeconfig-Anmeldedaten wurden abgelehnt. Prüfen Sie den aktiven Benutzer mit 'kubectl config view'.",
+	HintForbidden:        "fehlende Kubernetes-Berechtigungen. Die von ktl benötigten Verben stehen in docs/rbac.md.",
+
+	DeployDeploying:   "Release wird ausgerollt",
+	DeployRelease:     "Release %s",
+	DeployChart:       "Chart %s",
+	DeployWaiting:     "Warte auf Release-Ressourcen...",
+	DeployColResource: "Ressource",
+	DeployColAction:   "Aktion",
+	DeployColStatus:   "Status",
+	DeployColMessage:  "Meldung",
+	DeployHooks:       "Hooks",
+	DeployStorage:     "Speicher",
+	DeployDetails:     "Details ▸ %s (mit --console-details ausklappen)",
+	DeployDetailsNone: "nichts anzuzeigen",
+	DeployPhases:      "Phasen: %s",
+	DeployAttention:   "Achtung",
+
+	PhaseKey("render"):     "Rendern",
+	PhaseKey("diff"):       "Diff",
+	PhaseKey("upgrade"):    "Upgrade",
+	PhaseKey("install"):    "Installation",
+	PhaseKey("wait"):       "Warten",
+	PhaseKey("post-hooks"): "Post-Hooks",
+	PhaseKey("destroy"):    "Löschen",
+
+	PlainRelease:      "Release %s",
+	PlainNamespace:    "im Namespace %s",
+	PlainChart:        "Chart %s",
+	PlainChartVersion: "Chart %s Version %s",
+	PlainPhase:        "Phase %s: %s",
+	PlainWarning:      "Warnung",
+	PlainError:        "Fehler",
+	PlainResource:     "%s %s im Namespace %s: %s",
+	PlainHook:         "Hook %s %s %s: %s",
+	PlainHookAfter:    "nach %s",
+	PlainHookLogs:     "Letzte Logzeilen von Hook %s:",
+	PlainHookLogsEnd:  "Ende der Logs von Hook %s.",
+	PlainUnknown:      "unbekannt",
+
+	StackHooksHeader:     "STACK-HOOKS",
+	StackFailures:        "FEHLER (%d)",
+	StackHooks:           "HOOKS",
+	StackHelmLogs:        "HELM-LOGS",
+	StackDetails:         "DETAILS",
+	StackColNode:         "Knoten",
+	StackColStatus:       "Status",
+	StackColAttempt:      "Vers",
+	StackColPhase:        "Phase",
+	StackColNote:         "Notiz",
+	StackHintHookFailed:  "Hook-Zusammenfassung/-Ausgabe prüfen (und when=)",
+	StackHintWaitTimeout: "Blocker untersuchen; ggf. apply.timeout erhöhen",
+	StackHintRateLimit:   "geringere Parallelität versuchen oder erneut ausführen",
+	StackHintHelmError:   "Helm-Logs prüfen; mit --helm-logs=all erneut ausführen",
+}
@@ -0,0 +1,64 @@
+// File: internal/i18n/es.go
+// Brief: Internal i18n package implementation for 'es'.
+
+package i18n
+
+var spanish = map[Key]string{
+	ErrorLabel:           "Error:",
+	HintLabel:            "Sugerencia:",
+	HintDeadlineExceeded: "aumente --duration o verifique la conectividad de red con el clúster.",
+	HintUnauthorized:     "se rechazaron las credenciales del kubeconfig. Ejecute 'kubectl config view' para confirmar el usuario activo.",
+	HintForbidden:        "faltan permisos de Kubernetes. Consulte docs/rbac.md para ver los verbos que requiere ktl.",
+
+	DeployDeploying:   "Desplegando release",
+	DeployRelease:     "Release %s",
+	DeployChart:       "Chart %s",
+	DeployWaiting:     "Esperando los recursos del release...",
+	DeployColResource: "Recurso",
+	DeployColAction:   "Acción",
+	DeployColStatus:   "Estado",
+	DeployColMessage:  "Mensaje",
+	DeployHooks:       "Hooks",
+	DeployStorage:     "Almacenamiento",
+	DeployDetails:     "Detalles ▸ %s (añada --console-details para expandir)",
+	DeployDetailsNone: "nada que mostrar",
+	DeployPhases:      "Fases: %s",
+	DeployAttention:   "Atención",
+
+	PhaseKey("render"):     "Renderizar",
+	PhaseKey("diff"):       "Diff",
+	PhaseKey("upgrade"):    "Actualizar",
+	PhaseKey("install"):    "Instalar",
+	PhaseKey("wait"):       "Esperar",
+	PhaseKey("post-hooks"): "Post-hooks",
+	PhaseKey("destroy"):    "Eliminar",
+
+	PlainRelease:      "Release %s",
+	PlainNamespace:    "en el namespace %s",
+	PlainChart:        "chart %s",
+	PlainChartVersion: "chart %s versión %s",
+	PlainPhase:        "Fase %s: %s",
+	PlainWarning:      "Advertencia",
+	PlainError:        "Error",
+	PlainResource:     "%s %s en el namespace %s: %s",
+	PlainHook:         "Hook %s %s %s: %s",
+	PlainHookAfter:    "tras %s",
+	PlainHookLogs:     "Últimas líneas de log del hook %s:",
+	PlainHookLogsEnd:  "Fin de los logs del hook %s.",
+	PlainUnknown:      "desconocido",
+
+	StackHooksHeader:     "HOOKS DEL STACK",
+	StackFailures:        "FALLOS (%d)",
+	StackHooks:           "HOOKS",
+	StackHelmLogs:        "LOGS DE HELM",
+	StackDetails:         "DETALLES",
+	StackColNode:         "Nodo",
+	StackColStatus:       "Estado",
+	StackColAttempt:      "Int",
+	StackColPhase:        "Fase",
+	StackColNote:         "Nota",
+	StackHintHookFailed:  "revise el resumen/salida del hook (y when=)",
+	StackHintWaitTimeout: "inspeccione los bloqueos; considere aumentar apply.timeout",
+	StackHintRateLimit:   "pruebe con menos concurrencia o vuelva a ejecutar",
+	StackHintHelmError:   "revise los logs de helm; vuelva a ejecutar con --helm-logs=all",
+}
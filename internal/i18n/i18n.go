@@ -0,0 +1,108 @@
+// File: internal/i18n/i18n.go
+// Brief: Internal i18n package implementation for 'i18n'.
+
+// Package i18n holds the message catalog for user-facing CLI text (errors, hints, console
+// labels). The locale comes from KTL_LANG; English is the default and the fallback for any
+// message a catalog does not translate.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Key identifies a catalog message.
+type Key string
+
+// DefaultLocale is used when KTL_LANG is unset or names an unsupported language.
+const DefaultLocale = "en"
+
+var catalogs = map[string]map[Key]string{
+	"en": english,
+	"de": german,
+	"es": spanish,
+}
+
+var (
+	mu     sync.RWMutex
+	locale string
+	loaded bool
+)
+
+// Locales returns the supported locales in alphabetical order.
+func Locales() []string {
+	out := make([]string, 0, len(catalogs))
+	for name := range catalogs {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Normalize maps a locale string such as "de_DE.UTF-8" or "es-MX" to a supported language, or
+// DefaultLocale when none matches.
+func Normalize(value string) string {
+	lang := strings.ToLower(strings.TrimSpace(value))
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	return DefaultLocale
+}
+
+// Locale returns the active locale, reading KTL_LANG on first use.
+func Locale() string {
+	mu.RLock()
+	if loaded {
+		defer mu.RUnlock()
+		return locale
+	}
+	mu.RUnlock()
+	mu.Lock()
+	defer mu.Unlock()
+	if !loaded {
+		locale = Normalize(os.Getenv("KTL_LANG"))
+		loaded = true
+	}
+	return locale
+}
+
+// SetLocale overrides the active locale and returns the previous one.
+func SetLocale(value string) string {
+	prev := Locale()
+	mu.Lock()
+	locale = Normalize(value)
+	mu.Unlock()
+	return prev
+}
+
+// T formats the message for key in the active locale, falling back to English and then to the
+// key itself.
+func T(key Key, args ...any) string {
+	msg, ok := catalogs[Locale()][key]
+	if !ok {
+		msg, ok = english[key]
+	}
+	if !ok {
+		msg = string(key)
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Lookup returns the message for key in the active locale and whether any catalog defines it;
+// callers with computed keys use it to fall back to their own text.
+func Lookup(key Key) (string, bool) {
+	if msg, ok := catalogs[Locale()][key]; ok {
+		return msg, true
+	}
+	msg, ok := english[key]
+	return msg, ok
+}
@@ -0,0 +1,62 @@
+package i18n
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsTranslateEveryKeyWithSameVerbs(t *testing.T) {
+	for _, name := range Locales() {
+		catalog := catalogs[name]
+		for key, en := range english {
+			msg, ok := catalog[key]
+			if !ok {
+				t.Errorf("%s: missing %s", name, key)
+				continue
+			}
+			if got, want := strings.Join(verbPattern.FindAllString(msg, -1), " "), strings.Join(verbPattern.FindAllString(en, -1), " "); got != want {
+				t.Errorf("%s: %s uses verbs %q, English uses %q", name, key, got, want)
+			}
+		}
+		for key := range catalog {
+			if _, ok := english[key]; !ok {
+				t.Errorf("%s: %s is not in the English catalog", name, key)
+			}
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"":            "en",
+		"de":          "de",
+		"de_DE.UTF-8": "de",
+		"ES-mx":       "es",
+		"fr_FR":       "en",
+	}
+	for in, want := range cases {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTFallsBackToEnglishAndKey(t *testing.T) {
+	prev := SetLocale("de")
+	t.Cleanup(func() { SetLocale(prev) })
+
+	if got := T(StackFailures, 2); got != "FEHLER (2)" {
+		t.Fatalf("unexpected German text %q", got)
+	}
+	delete(german, DeployStorage)
+	t.Cleanup(func() { german[DeployStorage] = "Speicher" })
+	if got := T(DeployStorage); got != "Storage" {
+		t.Fatalf("expected English fallback, got %q", got)
+	}
+	if got := T(Key("no.such.key")); got != "no.such.key" {
+		t.Fatalf("expected key fallback, got %q", got)
+	}
+}
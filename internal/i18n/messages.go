@@ -0,0 +1,128 @@
+// File: internal/i18n/messages.go
+// Brief: Internal i18n package implementation for 'messages'.
+
+package i18n
+
+// Error output (cmd/ktl handleError).
+const (
+	ErrorLabel           Key = "error.label"
+	HintLabel            Key = "hint.label"
+	HintDeadlineExceeded Key = "hint.deadline-exceeded"
+	HintUnauthorized     Key = "hint.unauthorized"
+	HintForbidden        Key = "hint.forbidden"
+)
+
+// Deploy console (ktl apply / ktl delete).
+const (
+	DeployDeploying   Key = "deploy.deploying"
+	DeployRelease     Key = "deploy.release"
+	DeployChart       Key = "deploy.chart"
+	DeployWaiting     Key = "deploy.waiting"
+	DeployColResource Key = "deploy.col.resource"
+	DeployColAction   Key = "deploy.col.action"
+	DeployColStatus   Key = "deploy.col.status"
+	DeployColMessage  Key = "deploy.col.message"
+	DeployHooks       Key = "deploy.hooks"
+	DeployStorage     Key = "deploy.storage"
+	DeployDetails     Key = "deploy.details"
+	DeployDetailsNone Key = "deploy.details.none"
+	DeployPhases      Key = "deploy.phases"
+	DeployAttention   Key = "deploy.attention"
+
+	PlainRelease      Key = "plain.release"
+	PlainNamespace    Key = "plain.namespace"
+	PlainChart        Key = "plain.chart"
+	PlainChartVersion Key = "plain.chart-version"
+	PlainPhase        Key = "plain.phase"
+	PlainWarning      Key = "plain.warning"
+	PlainError        Key = "plain.error"
+	PlainResource     Key = "plain.resource"
+	PlainHook         Key = "plain.hook"
+	PlainHookAfter    Key = "plain.hook.after"
+	PlainHookLogs     Key = "plain.hook.logs"
+	PlainHookLogsEnd  Key = "plain.hook.logs-end"
+	PlainUnknown      Key = "plain.unknown"
+)
+
+// PhaseKey returns the key of a deploy phase label ("render", "wait", ...).
+func PhaseKey(name string) Key {
+	return Key("phase." + name)
+}
+
+// Stack console (ktl stack apply / delete).
+const (
+	StackHooksHeader     Key = "stack.stack-hooks"
+	StackFailures        Key = "stack.failures"
+	StackHooks           Key = "stack.hooks"
+	StackHelmLogs        Key = "stack.helm-logs"
+	StackDetails         Key = "stack.details"
+	StackColNode         Key = "stack.col.node"
+	StackColStatus       Key = "stack.col.status"
+	StackColAttempt      Key = "stack.col.attempt"
+	StackColPhase        Key = "stack.col.phase"
+	StackColNote         Key = "stack.col.note"
+	StackHintHookFailed  Key = "stack.hint.hook-failed"
+	StackHintWaitTimeout Key = "stack.hint.wait-timeout"
+	StackHintRateLimit   Key = "stack.hint.rate-limit"
+	StackHintHelmError   Key = "stack.hint.helm-error"
+)
+
+var english = map[Key]string{
+	ErrorLabel:           "Error:",
+	HintLabel:            "Hint:",
+	HintDeadlineExceeded: "increase --duration or verify network connectivity to the cluster.",
+	HintUnauthorized:     "kubeconfig credentials were rejected. Run 'kubectl config view' to confirm the active user.",
+	HintForbidden:        "missing Kubernetes permissions. See docs/rbac.md for the verbs ktl requires.",
+
+	DeployDeploying:   "Deploying release",
+	DeployRelease:     "Release %s",
+	DeployChart:       "Chart %s",
+	DeployWaiting:     "Waiting for release resources...",
+	DeployColResource: "Resource",
+	DeployColAction:   "Action",
+	DeployColStatus:   "Status",
+	DeployColMessage:  "Message",
+	DeployHooks:       "Hooks",
+	DeployStorage:     "Storage",
+	DeployDetails:     "Details ▸ %s (add --console-details to expand)",
+	DeployDetailsNone: "nothing to show",
+	DeployPhases:      "Phases: %s",
+	DeployAttention:   "Attention",
+
+	PhaseKey("render"):     "Render",
+	PhaseKey("diff"):       "Diff",
+	PhaseKey("upgrade"):    "Upgrade",
+	PhaseKey("install"):    "Install",
+	PhaseKey("wait"):       "Wait",
+	PhaseKey("post-hooks"): "Post-Hooks",
+	PhaseKey("destroy"):    "Destroy",
+
+	PlainRelease:      "Release %s",
+	PlainNamespace:    "in namespace %s",
+	PlainChart:        "chart %s",
+	PlainChartVersion: "chart %s version %s",
+	PlainPhase:        "Phase %s: %s",
+	PlainWarning:      "Warning",
+	PlainError:        "Error",
+	PlainResource:     "%s %s in namespace %s: %s",
+	PlainHook:         "Hook %s %s %s: %s",
+	PlainHookAfter:    "after %s",
+	PlainHookLogs:     "Last log lines of hook %s:",
+	PlainHookLogsEnd:  "End of logs for hook %s.",
+	PlainUnknown:      "unknown",
+
+	StackHooksHeader:     "STACK HOOKS",
+	StackFailures:        "FAILURES (%d)",
+	StackHooks:           "HOOKS",
+	StackHelmLogs:        "HELM LOGS",
+	StackDetails:         "DETAILS",
+	StackColNode:         "Node",
+	StackColStatus:       "Status",
+	StackColAttempt:      "Att",
+	StackColPhase:        "Phase",
+	StackColNote:         "Note",
+	StackHintHookFailed:  "check hook summary/output (and when=)",
+	StackHintWaitTimeout: "inspect blockers; consider increasing apply.timeout",
+	StackHintRateLimit:   "try lower concurrency or rerun",
+	StackHintHelmError:   "check helm logs; rerun with --helm-logs=all",
+}
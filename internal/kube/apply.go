@@ -0,0 +1,144 @@
+// File: internal/kube/apply.go
+// Brief: Internal kube package implementation for 'apply'.
+
+// apply.go server-side applies raw multi-document YAML manifests through the dynamic client.
+package kube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+// ApplyOptions tune ApplyManifest.
+type ApplyOptions struct {
+	// FieldManager defaults to "ktl".
+	FieldManager string
+	// Namespace is used for namespaced objects that do not set metadata.namespace.
+	Namespace string
+	// Force takes ownership of conflicting fields.
+	Force  bool
+	DryRun bool
+}
+
+// AppliedObject identifies one object applied by ApplyManifest.
+type AppliedObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (o AppliedObject) String() string {
+	if o.Namespace == "" {
+		return o.Kind + "/" + o.Name
+	}
+	return o.Namespace + "/" + o.Kind + "/" + o.Name
+}
+
+// DecodeManifest splits a multi-document YAML/JSON manifest into objects, skipping empty documents
+// and expanding v1 List kinds.
+func DecodeManifest(manifest []byte) ([]*unstructured.Unstructured, error) {
+	dec := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 4096)
+	var out []*unstructured.Unstructured
+	for {
+		var raw map[string]interface{}
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decode manifest: %w", err)
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: raw}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("decode list: %w", err)
+			}
+			for i := range list.Items {
+				out = append(out, &list.Items[i])
+			}
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+			return nil, fmt.Errorf("manifest object is missing apiVersion/kind")
+		}
+		out = append(out, obj)
+	}
+	return out, nil
+}
+
+// ApplyManifest server-side applies every object in manifest in document order. The REST mapper is
+// reset after CustomResourceDefinitions are applied so later documents can use the new kinds.
+func ApplyManifest(ctx context.Context, client *Client, manifest []byte, opts ApplyOptions) ([]AppliedObject, error) {
+	if client == nil || client.Dynamic == nil || client.RESTMapper == nil {
+		return nil, fmt.Errorf("kube client missing dynamic/mapper")
+	}
+	objs, err := DecodeManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	fieldManager := strings.TrimSpace(opts.FieldManager)
+	if fieldManager == "" {
+		fieldManager = "ktl"
+	}
+	applied := make([]AppliedObject, 0, len(objs))
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		mapping, err := client.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			// The kind may have been registered moments ago by a CRD in the same bundle.
+			client.RESTMapper.Reset()
+			if mapping, err = client.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+				return applied, fmt.Errorf("map %s %s: %w", gvk.Kind, obj.GetName(), err)
+			}
+		}
+		var res dynamic.ResourceInterface = client.Dynamic.Resource(mapping.Resource)
+		ns := ""
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			ns = obj.GetNamespace()
+			if ns == "" {
+				ns = opts.Namespace
+			}
+			if ns == "" {
+				ns = "default"
+			}
+			obj.SetNamespace(ns)
+			res = client.Dynamic.Resource(mapping.Resource).Namespace(ns)
+		}
+		body, err := json.Marshal(obj.Object)
+		if err != nil {
+			return applied, fmt.Errorf("encode %s/%s: %w", gvk.Kind, obj.GetName(), err)
+		}
+		patchOpts := metav1.PatchOptions{FieldManager: fieldManager}
+		if opts.Force {
+			force := true
+			patchOpts.Force = &force
+		}
+		if opts.DryRun {
+			patchOpts.DryRun = []string{metav1.DryRunAll}
+		}
+		if _, err := res.Patch(ctx, obj.GetName(), types.ApplyPatchType, body, patchOpts); err != nil {
+			return applied, fmt.Errorf("apply %s/%s: %w", gvk.Kind, obj.GetName(), err)
+		}
+		applied = append(applied, AppliedObject{APIVersion: obj.GetAPIVersion(), Kind: gvk.Kind, Namespace: ns, Name: obj.GetName()})
+		if gvk.Kind == "CustomResourceDefinition" {
+			client.RESTMapper.Reset()
+		}
+	}
+	return applied, nil
+}
@@ -7,10 +7,15 @@ package kube
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/kubekattle/ktl/internal/netconfig"
+	"github.com/kubekattle/ktl/internal/tracing"
 	"github.com/mitchellh/go-homedir"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
@@ -28,14 +33,77 @@ type Client struct {
 	RESTConfig *rest.Config
 	Clientset  kubernetes.Interface
 	Dynamic    dynamic.Interface
+	// Metrics is built on first MetricsClient call; set it to inject a client.
 	Metrics    metricsclient.Interface
 	RESTMapper *restmapper.DeferredDiscoveryRESTMapper
-	Namespace  string
-	APIStats   *APIRequestStats
+	// Discovery is the in-memory cached discovery client backing RESTMapper.
+	Discovery discovery.CachedDiscoveryInterface
+	Namespace string
+	APIStats  *APIRequestStats
+
+	httpClient  *http.Client
+	metricsOnce sync.Once
+	metricsErr  error
+}
+
+// MetricsClient returns the metrics.k8s.io client, building it on first use: only a few commands
+// read pod metrics, so New does not construct it up front.
+func (c *Client) MetricsClient() (metricsclient.Interface, error) {
+	c.metricsOnce.Do(func() {
+		if c.Metrics != nil {
+			return
+		}
+		if c.httpClient == nil {
+			c.Metrics, c.metricsErr = metricsclient.NewForConfig(c.RESTConfig)
+			return
+		}
+		c.Metrics, c.metricsErr = metricsclient.NewForConfigAndClient(c.RESTConfig, c.httpClient)
+	})
+	if c.metricsErr != nil {
+		return nil, fmt.Errorf("create metrics client: %w", c.metricsErr)
+	}
+	return c.Metrics, nil
+}
+
+// Impersonation identifies the user and groups every client built by New acts as (kubectl --as/--as-group).
+type Impersonation struct {
+	User   string
+	Groups []string
+}
+
+var (
+	impersonationMu sync.RWMutex
+	impersonation   Impersonation
+)
+
+// SetImpersonation makes subsequent New calls impersonate imp. The zero value disables impersonation.
+func SetImpersonation(imp Impersonation) {
+	impersonationMu.Lock()
+	defer impersonationMu.Unlock()
+	impersonation = Impersonation{User: imp.User, Groups: append([]string(nil), imp.Groups...)}
+}
+
+// CurrentImpersonation returns the impersonation configured via SetImpersonation.
+func CurrentImpersonation() Impersonation {
+	impersonationMu.RLock()
+	defer impersonationMu.RUnlock()
+	return Impersonation{User: impersonation.User, Groups: append([]string(nil), impersonation.Groups...)}
+}
+
+// ClientOptions adjusts clients built by NewWithOptions.
+type ClientOptions struct {
+	// ExecEnv is added to the environment of the kubeconfig user's exec credential plugin
+	// (for example AWS_PROFILE for aws eks get-token).
+	ExecEnv map[string]string
 }
 
 // New builds a Kubernetes client configuration honoring the provided kubeconfig path and context.
 func New(ctx context.Context, kubeconfigPath, contextName string) (*Client, error) {
+	return NewWithOptions(ctx, kubeconfigPath, contextName, ClientOptions{})
+}
+
+// NewWithOptions is New with per-client overrides.
+func NewWithOptions(ctx context.Context, kubeconfigPath, contextName string, opts ClientOptions) (*Client, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	if kubeconfigPath != "" {
 		expanded, err := homedir.Expand(kubeconfigPath)
@@ -56,6 +124,10 @@ func New(ctx context.Context, kubeconfigPath, contextName string) (*Client, erro
 	if contextName != "" {
 		overrides.CurrentContext = contextName
 	}
+	if imp := CurrentImpersonation(); imp.User != "" {
+		overrides.AuthInfo.Impersonate = imp.User
+		overrides.AuthInfo.ImpersonateGroups = imp.Groups
+	}
 	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
 	namespace, _, err := clientConfig.Namespace()
 	if err != nil {
@@ -66,6 +138,8 @@ func New(ctx context.Context, kubeconfigPath, contextName string) (*Client, erro
 		return nil, fmt.Errorf("build rest config: %w", err)
 	}
 	rest.SetDefaultWarningHandler(rest.NoWarnings{})
+	netconfig.ApplyREST(restConfig)
+	ApplyExecEnv(restConfig, opts.ExecEnv)
 
 	// Aggressive defaults for snappy startup.
 	restConfig.Timeout = 30 * time.Second
@@ -73,35 +147,67 @@ func New(ctx context.Context, kubeconfigPath, contextName string) (*Client, erro
 	restConfig.Burst = 100
 	apiStats := NewAPIRequestStats()
 	AttachAPITelemetry(restConfig, apiStats)
+	// The client outlives any one operation, so requests without a span of their own are
+	// parented to the command span rather than to whatever ctx built the client.
+	tracing.WrapREST(nil, restConfig)
 
-	clientset, err := kubernetes.NewForConfig(restConfig)
+	// One HTTP client (and transport) serves every API client built from this config.
+	httpClient, err := rest.HTTPClientFor(restConfig)
 	if err != nil {
-		return nil, fmt.Errorf("create typed client: %w", err)
+		return nil, fmt.Errorf("create http client: %w", err)
 	}
 
-	dyn, err := dynamic.NewForConfig(restConfig)
+	clientset, err := kubernetes.NewForConfigAndClient(restConfig, httpClient)
 	if err != nil {
-		return nil, fmt.Errorf("create dynamic client: %w", err)
+		return nil, fmt.Errorf("create typed client: %w", err)
 	}
 
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	dyn, err := dynamic.NewForConfigAndClient(restConfig, httpClient)
 	if err != nil {
-		return nil, fmt.Errorf("create discovery client: %w", err)
+		return nil, fmt.Errorf("create dynamic client: %w", err)
 	}
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
 
-	metrics, err := metricsclient.NewForConfig(restConfig)
+	discoveryClient, err := discovery.NewDiscoveryClientForConfigAndClient(restConfig, httpClient)
 	if err != nil {
-		return nil, fmt.Errorf("create metrics client: %w", err)
+		return nil, fmt.Errorf("create discovery client: %w", err)
 	}
+	cachedDiscovery := memory.NewMemCacheClient(discoveryClient)
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery)
 
 	return &Client{
 		RESTConfig: restConfig,
 		Clientset:  clientset,
 		Dynamic:    dyn,
-		Metrics:    metrics,
 		RESTMapper: mapper,
+		Discovery:  cachedDiscovery,
 		Namespace:  namespace,
 		APIStats:   apiStats,
+		httpClient: httpClient,
 	}, nil
 }
+
+// ApplyExecEnv sets env on cfg's exec credential plugin, replacing variables the kubeconfig
+// already defines. Configs without an exec plugin are left untouched.
+func ApplyExecEnv(cfg *rest.Config, env map[string]string) {
+	if cfg == nil || cfg.ExecProvider == nil || len(env) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]api.ExecEnvVar, 0, len(cfg.ExecProvider.Env)+len(keys))
+	for _, v := range cfg.ExecProvider.Env {
+		if _, ok := env[v.Name]; !ok {
+			out = append(out, v)
+		}
+	}
+	for _, k := range keys {
+		out = append(out, api.ExecEnvVar{Name: k, Value: env[k]})
+	}
+	// Copy so the parsed kubeconfig shared with other clients is not modified.
+	exec := *cfg.ExecProvider
+	exec.Env = out
+	cfg.ExecProvider = &exec
+}
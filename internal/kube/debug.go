@@ -0,0 +1,197 @@
+// File: internal/kube/debug.go
+// Brief: Internal kube package implementation for 'debug'.
+
+// debug.go injects ephemeral debug containers (or debug pod copies) and attaches to them.
+package kube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// DefaultDebugImage is used when no debug image is configured.
+const DefaultDebugImage = "busybox:1.36"
+
+// DebugContainerOptions describes the debug container to inject.
+type DebugContainerOptions struct {
+	Image           string
+	Command         []string
+	TargetContainer string
+	ImagePullPolicy corev1.PullPolicy
+	Env             []corev1.EnvVar
+	// OS is the target pod's operating system; Windows pods get a Windows image and cmd.exe.
+	OS string
+}
+
+// NewDebugContainerName returns a unique, DNS-safe debug container name.
+func NewDebugContainerName() string {
+	return "ktl-debug-" + rand.String(5)
+}
+
+// BuildEphemeralContainer returns an interactive ephemeral container sharing the target's process namespace.
+func BuildEphemeralContainer(name string, opts DebugContainerOptions) corev1.EphemeralContainer {
+	image := strings.TrimSpace(opts.Image)
+	if image == "" {
+		image = DefaultDebugImage
+		if IsWindows(opts.OS) {
+			image = DefaultWindowsDebugImage
+		}
+	}
+	command := opts.Command
+	if len(command) == 0 {
+		command = DefaultShell(opts.OS)
+	}
+	target := opts.TargetContainer
+	if IsWindows(opts.OS) {
+		// Windows containers cannot join another container's process namespace.
+		target = ""
+	}
+	return corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     name,
+			Image:                    image,
+			Command:                  command,
+			ImagePullPolicy:          opts.ImagePullPolicy,
+			Env:                      opts.Env,
+			Stdin:                    true,
+			TTY:                      true,
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+		TargetContainerName: target,
+	}
+}
+
+// BuildDebugPodCopy returns a copy of pod with a shared process namespace and the debug container appended.
+// The copy drops node binding, probes, and owner references so it schedules as a standalone pod.
+// Windows copies (osName) keep separate process namespaces, which Windows does not share, and are
+// pinned to Windows nodes.
+func BuildDebugPodCopy(pod *corev1.Pod, copyName, osName string, ec corev1.EphemeralContainer) *corev1.Pod {
+	spec := *pod.Spec.DeepCopy()
+	spec.NodeName = ""
+	if IsWindows(osName) {
+		spec.ShareProcessNamespace = nil
+		if PodOS(pod) == "" {
+			selector := make(map[string]string, len(spec.NodeSelector)+1)
+			for k, v := range spec.NodeSelector {
+				selector[k] = v
+			}
+			selector[osLabel] = OSWindows
+			spec.NodeSelector = selector
+		}
+	} else {
+		share := true
+		spec.ShareProcessNamespace = &share
+	}
+	spec.EphemeralContainers = nil
+	spec.RestartPolicy = corev1.RestartPolicyNever
+	for i := range spec.Containers {
+		spec.Containers[i].LivenessProbe = nil
+		spec.Containers[i].ReadinessProbe = nil
+		spec.Containers[i].StartupProbe = nil
+	}
+	spec.Containers = append(spec.Containers, corev1.Container{
+		Name:                     ec.Name,
+		Image:                    ec.Image,
+		Command:                  ec.Command,
+		ImagePullPolicy:          ec.ImagePullPolicy,
+		Env:                      ec.Env,
+		Stdin:                    true,
+		TTY:                      true,
+		TerminationMessagePolicy: ec.TerminationMessagePolicy,
+	})
+	labels := map[string]string{"app.kubernetes.io/managed-by": "ktl-debug"}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        copyName,
+			Namespace:   pod.Namespace,
+			Labels:      labels,
+			Annotations: map[string]string{"ktl.dev/debug-source": pod.Name},
+		},
+		Spec: spec,
+	}
+}
+
+// AddEphemeralContainer appends ec to the pod's ephemeral containers via the ephemeralcontainers subresource.
+func (c *Client) AddEphemeralContainer(ctx context.Context, namespace, podName string, ec corev1.EphemeralContainer) error {
+	pods := c.Clientset.CoreV1().Pods(namespace)
+	pod, err := pods.Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get pod %s/%s: %w", namespace, podName, err)
+	}
+	updated := pod.DeepCopy()
+	updated.Spec.EphemeralContainers = append(updated.Spec.EphemeralContainers, ec)
+	if _, err := pods.UpdateEphemeralContainers(ctx, podName, updated, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("add ephemeral container (requires Kubernetes 1.25+ and RBAC for pods/ephemeralcontainers): %w", err)
+	}
+	return nil
+}
+
+// WaitForContainerRunning blocks until the named (ephemeral or regular) container is running.
+func (c *Client) WaitForContainerRunning(ctx context.Context, namespace, podName, container string, timeout time.Duration) error {
+	return wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		pod, err := c.Clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		statuses := append(append([]corev1.ContainerStatus(nil), pod.Status.ContainerStatuses...), pod.Status.EphemeralContainerStatuses...)
+		for _, st := range statuses {
+			if st.Name != container {
+				continue
+			}
+			if st.State.Running != nil {
+				return true, nil
+			}
+			if t := st.State.Terminated; t != nil {
+				return false, fmt.Errorf("container %s terminated: %s %s", container, t.Reason, t.Message)
+			}
+			if w := st.State.Waiting; w != nil && (w.Reason == "ErrImagePull" || w.Reason == "ImagePullBackOff" || w.Reason == "InvalidImageName") {
+				return false, fmt.Errorf("container %s cannot start: %s %s", container, w.Reason, w.Message)
+			}
+		}
+		return false, nil
+	})
+}
+
+// Attach connects stdin/stdout (and stderr when tty is false) to a running container.
+func (c *Client) Attach(ctx context.Context, namespace, pod, container string, stdin io.Reader, stdout, stderr io.Writer, tty bool, sizes remotecommand.TerminalSizeQueue) error {
+	req := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("attach")
+	req.VersionedParams(&corev1.PodAttachOptions{
+		Container: container,
+		Stdin:     stdin != nil,
+		Stdout:    stdout != nil,
+		Stderr:    stderr != nil && !tty,
+		TTY:       tty,
+	}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(c.RESTConfig, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("create executor: %w", err)
+	}
+	streamOpts := remotecommand.StreamOptions{
+		Stdin:             stdin,
+		Stdout:            stdout,
+		Tty:               tty,
+		TerminalSizeQueue: sizes,
+	}
+	if !tty {
+		streamOpts.Stderr = stderr
+	}
+	if err := executor.StreamWithContext(ctx, streamOpts); err != nil {
+		return fmt.Errorf("attach: %w", err)
+	}
+	return nil
+}
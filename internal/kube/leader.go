@@ -0,0 +1,160 @@
+// File: internal/kube/leader.go
+// Brief: Internal kube package implementation for 'leader'.
+
+// leader.go wraps Lease-based leader election so only one replica of a long-running ktl loop acts.
+package kube
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// LeaderElectionOptions identifies the Lease used to elect a leader.
+type LeaderElectionOptions struct {
+	Namespace string
+	Name      string
+	// Identity defaults to hostname-pid.
+	Identity      string
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+	// OnNewLeader is called whenever leadership changes hands (including to this replica).
+	OnNewLeader func(identity string)
+}
+
+// RunWithLeaderElection invokes run only while this replica holds the Lease. run's context is
+// cancelled as soon as leadership is lost or ctx is done; the replica then waits for run to return
+// before releasing the Lease or campaigning again. Once run returns while still leading (after a
+// single pass, or because ctx is done) RunWithLeaderElection returns run's error; it returns nil
+// when ctx is done while this replica is not leading.
+func RunWithLeaderElection(ctx context.Context, client kubernetes.Interface, opts LeaderElectionOptions, run func(context.Context) error) error {
+	if client == nil {
+		return fmt.Errorf("leader election requires a kubernetes client")
+	}
+	if strings.TrimSpace(opts.Namespace) == "" || strings.TrimSpace(opts.Name) == "" {
+		return fmt.Errorf("leader election requires a lease namespace and name")
+	}
+	identity := strings.TrimSpace(opts.Identity)
+	if identity == "" {
+		host, _ := os.Hostname()
+		identity = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	lease := opts.LeaseDuration
+	if lease <= 0 {
+		lease = 30 * time.Second
+	}
+	renew := opts.RenewDeadline
+	if renew <= 0 {
+		renew = 20 * time.Second
+	}
+	retry := opts.RetryPeriod
+	if retry <= 0 {
+		retry = 5 * time.Second
+	}
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Namespace: opts.Namespace, Name: opts.Name},
+		Client:     client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+	cfg := leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   lease,
+		RenewDeadline:   renew,
+		RetryPeriod:     retry,
+		ReleaseOnCancel: true,
+		Name:            opts.Name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStoppedLeading: func() {},
+			OnNewLeader: func(id string) {
+				if opts.OnNewLeader != nil {
+					opts.OnNewLeader(id)
+				}
+			},
+		},
+	}
+	for ctx.Err() == nil {
+		done, err := campaign(ctx, cfg, run)
+		if done {
+			return err
+		}
+	}
+	return nil
+}
+
+// leaderTerm is how one invocation of run ended.
+type leaderTerm struct {
+	err  error
+	lost bool
+}
+
+// campaign runs a single election round. done reports that run returned on its own, or that the
+// elector could not be built, rather than run being stopped by lost leadership.
+func campaign(ctx context.Context, cfg leaderelection.LeaderElectionConfig, run func(context.Context) error) (done bool, err error) {
+	// The elector keeps renewing until run has returned, so it gets a context that only we cancel:
+	// after run returns, or when ctx is done before this replica starts leading.
+	electCtx, cancelElect := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelElect()
+
+	var (
+		mu      sync.Mutex
+		started bool
+	)
+	term := make(chan leaderTerm, 1)
+	stopWatch := context.AfterFunc(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if !started {
+			cancelElect()
+		}
+	})
+	defer stopWatch()
+
+	cfg.Callbacks.OnStartedLeading = func(leadCtx context.Context) {
+		mu.Lock()
+		if leadCtx.Err() != nil {
+			mu.Unlock()
+			return
+		}
+		started = true
+		mu.Unlock()
+
+		runCtx, cancelRun := context.WithCancel(leadCtx)
+		stopRun := context.AfterFunc(ctx, cancelRun)
+		runErr := run(runCtx)
+		stopRun()
+		cancelRun()
+		// leadCtx is only cancelled behind our back when the elector failed to renew.
+		term <- leaderTerm{err: runErr, lost: leadCtx.Err() != nil}
+		// Step down (and release the Lease) only now that run has finished.
+		cancelElect()
+	}
+	elector, err := leaderelection.NewLeaderElector(cfg)
+	if err != nil {
+		return true, fmt.Errorf("leader election: %w", err)
+	}
+	elector.Run(electCtx)
+
+	// Run returns when the lease is lost, released, or never acquired. OnStartedLeading may still be
+	// pending or running; close the round and wait for run before campaigning again.
+	mu.Lock()
+	cancelElect()
+	leading := started
+	mu.Unlock()
+	if !leading {
+		return false, nil
+	}
+	t := <-term
+	if t.lost && ctx.Err() == nil {
+		return false, nil
+	}
+	return true, t.err
+}
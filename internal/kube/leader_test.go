@@ -0,0 +1,106 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testLeaderOptions() LeaderElectionOptions {
+	return LeaderElectionOptions{
+		Namespace:     "ktl",
+		Name:          "reconcile",
+		Identity:      "replica-a",
+		LeaseDuration: time.Second,
+		RenewDeadline: 500 * time.Millisecond,
+		RetryPeriod:   100 * time.Millisecond,
+	}
+}
+
+func leaseHolder(t *testing.T, client *fake.Clientset) string {
+	t.Helper()
+	lease, err := client.CoordinationV1().Leases("ktl").Get(context.Background(), "reconcile", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get lease: %v", err)
+	}
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
+
+func TestRunWithLeaderElectionReturnsAfterSinglePass(t *testing.T) {
+	client := fake.NewClientset()
+	errPass := errors.New("reconcile failed")
+	passes := 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- RunWithLeaderElection(ctx, client, testLeaderOptions(), func(context.Context) error {
+			passes++
+			return errPass
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, errPass) {
+			t.Fatalf("expected the pass error, got %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("leader election did not return after run finished (--once)")
+	}
+	if passes != 1 {
+		t.Fatalf("expected one pass, got %d", passes)
+	}
+	if holder := leaseHolder(t, client); holder != "" {
+		t.Fatalf("expected the lease to be released, still held by %q", holder)
+	}
+}
+
+func TestRunWithLeaderElectionHoldsLeaseUntilRunReturns(t *testing.T) {
+	client := fake.NewClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	leading := make(chan struct{})
+	holderAfterCancel := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- RunWithLeaderElection(ctx, client, testLeaderOptions(), func(runCtx context.Context) error {
+			close(leading)
+			<-runCtx.Done()
+			// Simulate an apply that takes a moment to wind down after cancellation.
+			time.Sleep(200 * time.Millisecond)
+			holderAfterCancel <- leaseHolder(t, client)
+			return nil
+		})
+	}()
+
+	select {
+	case <-leading:
+	case <-time.After(10 * time.Second):
+		t.Fatal("never became leader")
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("leader election did not return after cancellation")
+	}
+	if holder := <-holderAfterCancel; holder != "replica-a" {
+		t.Fatalf("expected the lease to be held while run winds down, got %q", holder)
+	}
+	if holder := leaseHolder(t, client); holder != "" {
+		t.Fatalf("expected the lease to be released after run returned, still held by %q", holder)
+	}
+}
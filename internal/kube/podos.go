@@ -0,0 +1,91 @@
+// File: internal/kube/podos.go
+// Brief: Internal kube package implementation for 'podos'.
+
+// podos.go detects whether a pod runs on Windows and picks the matching shell, debug image, and
+// path conventions so exec-based helpers work on mixed-OS clusters.
+package kube
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// OSLinux and OSWindows are the values of the kubernetes.io/os label and pod.spec.os.name.
+	OSLinux   = "linux"
+	OSWindows = "windows"
+
+	// DefaultWindowsDebugImage is used for debug containers on Windows pods; it must match the
+	// node's Windows Server build (ltsc2022 covers Windows Server 2022 nodes).
+	DefaultWindowsDebugImage = "mcr.microsoft.com/windows/nanoserver:ltsc2022"
+
+	osLabel = "kubernetes.io/os"
+)
+
+// PodOS returns the pod's operating system from spec.os or a kubernetes.io/os node selector.
+// It returns "" when the spec does not say; see ResolvePodOS for a node lookup.
+func PodOS(pod *corev1.Pod) string {
+	if pod == nil {
+		return ""
+	}
+	if pod.Spec.OS != nil && pod.Spec.OS.Name != "" {
+		return strings.ToLower(string(pod.Spec.OS.Name))
+	}
+	if osName := strings.TrimSpace(pod.Spec.NodeSelector[osLabel]); osName != "" {
+		return strings.ToLower(osName)
+	}
+	return ""
+}
+
+// ResolvePodOS is PodOS with a fallback to the kubernetes.io/os label of the pod's node. Pods that
+// cannot be resolved are assumed to run Linux.
+func ResolvePodOS(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod) string {
+	if osName := PodOS(pod); osName != "" {
+		return osName
+	}
+	if client != nil && pod != nil && pod.Spec.NodeName != "" {
+		node, err := client.CoreV1().Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{})
+		if err == nil {
+			if osName := strings.TrimSpace(node.Labels[osLabel]); osName != "" {
+				return strings.ToLower(osName)
+			}
+		}
+	}
+	return OSLinux
+}
+
+// IsWindows reports whether osName names Windows.
+func IsWindows(osName string) bool {
+	return strings.EqualFold(strings.TrimSpace(osName), OSWindows)
+}
+
+// DefaultShell is the interactive shell for containers on osName.
+func DefaultShell(osName string) []string {
+	if IsWindows(osName) {
+		return []string{"cmd.exe"}
+	}
+	return []string{"sh"}
+}
+
+// ShellCommand wraps script so it runs through the container's shell on osName.
+func ShellCommand(osName, script string) []string {
+	if IsWindows(osName) {
+		return []string{"cmd.exe", "/S", "/C", script}
+	}
+	return []string{"sh", "-c", script}
+}
+
+// ContainerPath joins a slash-separated relative path onto dir using osName conventions.
+func ContainerPath(osName, dir, rel string) string {
+	if !IsWindows(osName) {
+		return path.Join(dir, rel)
+	}
+	dir = strings.TrimRight(strings.ReplaceAll(dir, "/", `\`), `\`)
+	rel = strings.TrimLeft(strings.ReplaceAll(rel, "/", `\`), `\`)
+	return dir + `\` + rel
+}
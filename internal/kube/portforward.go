@@ -0,0 +1,123 @@
+// File: internal/kube/portforward.go
+// Brief: Internal kube package implementation for 'portforward'.
+
+// portforward.go opens local port-forwards to pods for tap/proxy helpers.
+package kube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForward is an active port-forward to a pod.
+type PortForward struct {
+	LocalPort int
+	stop      chan struct{}
+	stopOnce  sync.Once
+	done      chan error
+}
+
+func (p *PortForward) halt() {
+	p.stopOnce.Do(func() { close(p.stop) })
+}
+
+// Close stops forwarding and waits for the forwarder to exit.
+func (p *PortForward) Close() error {
+	if p == nil {
+		return nil
+	}
+	p.halt()
+	return <-p.done
+}
+
+// Done is closed (after delivering the terminal error) when forwarding stops.
+func (p *PortForward) Done() <-chan error {
+	return p.done
+}
+
+// StartPortForward forwards 127.0.0.1:localPort (0 picks a free port) to remotePort on the pod.
+// It returns once the listener is ready.
+func (c *Client) StartPortForward(ctx context.Context, namespace, pod string, localPort, remotePort int) (*PortForward, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(c.RESTConfig)
+	if err != nil {
+		return nil, err
+	}
+	req := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	stop := make(chan struct{})
+	ready := make(chan struct{})
+	ports := []string{fmt.Sprintf("%d:%d", localPort, remotePort)}
+	fw, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, ports, stop, ready, io.Discard, io.Discard)
+	if err != nil {
+		return nil, fmt.Errorf("create port-forward: %w", err)
+	}
+	pf := &PortForward{stop: stop, done: make(chan error, 1)}
+	go func() {
+		pf.done <- fw.ForwardPorts()
+		close(pf.done)
+	}()
+	select {
+	case <-ready:
+	case err := <-pf.done:
+		if err == nil {
+			err = fmt.Errorf("port-forward exited before becoming ready")
+		}
+		return nil, err
+	case <-ctx.Done():
+		pf.halt()
+		<-pf.done
+		return nil, ctx.Err()
+	}
+	forwarded, err := fw.GetPorts()
+	if err != nil {
+		_ = pf.Close()
+		return nil, fmt.Errorf("resolve forwarded port: %w", err)
+	}
+	if len(forwarded) == 0 {
+		_ = pf.Close()
+		return nil, fmt.Errorf("resolve forwarded port: no forwarded ports reported")
+	}
+	pf.LocalPort = int(forwarded[0].Local)
+	go func() {
+		select {
+		case <-ctx.Done():
+			pf.halt()
+		case <-stop:
+		}
+	}()
+	return pf, nil
+}
+
+// ResolveContainerPort maps a named or numeric port to a container port on pod.
+func ResolveContainerPort(pod *corev1.Pod, port string) (int, error) {
+	if pod == nil {
+		return 0, fmt.Errorf("pod is required")
+	}
+	if n, err := strconv.Atoi(port); err == nil {
+		if n <= 0 || n > 65535 {
+			return 0, fmt.Errorf("invalid port %d", n)
+		}
+		return n, nil
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == port {
+				return int(p.ContainerPort), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("pod %s has no container port named %q", pod.Name, port)
+}
@@ -0,0 +1,48 @@
+// File: internal/kube/rest_getter.go
+// Brief: Internal kube package implementation for 'rest getter'.
+
+// rest_getter.go lets Helm actions share a Client's discovery cache and RESTMapper.
+package kube
+
+import (
+	"github.com/kubekattle/ktl/internal/netconfig"
+	"github.com/kubekattle/ktl/internal/tracing"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+type sharedDiscoveryGetter struct {
+	genericclioptions.RESTClientGetter
+	client *Client
+}
+
+// HelmRESTClientGetter wraps base (normally Helm's settings.RESTClientGetter()) so every Helm action
+// built from it reuses this client's discovery cache and RESTMapper instead of running discovery
+// again. REST config and kubeconfig loading still come from base, with the --proxy/--tls-ca-file
+// settings layered on top.
+func (c *Client) HelmRESTClientGetter(base genericclioptions.RESTClientGetter) genericclioptions.RESTClientGetter {
+	if c == nil || c.Discovery == nil || c.RESTMapper == nil || base == nil {
+		return base
+	}
+	return &sharedDiscoveryGetter{RESTClientGetter: base, client: c}
+}
+
+func (g *sharedDiscoveryGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	return g.client.Discovery, nil
+}
+
+func (g *sharedDiscoveryGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	return g.client.RESTMapper, nil
+}
+
+func (g *sharedDiscoveryGetter) ToRESTConfig() (*rest.Config, error) {
+	cfg, err := g.RESTClientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	netconfig.ApplyREST(cfg)
+	tracing.WrapREST(nil, cfg)
+	return cfg, nil
+}
@@ -0,0 +1,148 @@
+// File: internal/kube/wait.go
+// Brief: Internal kube package implementation for 'wait'.
+
+// wait.go polls the API server until CustomResourceDefinitions are established and admission
+// webhooks have ready backends.
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// DefaultWaitPollInterval is how often wait helpers re-check the API server.
+const DefaultWaitPollInterval = 2 * time.Second
+
+// WaitForCRDEstablished blocks until the named CRD (e.g. certificates.cert-manager.io) reports
+// Established=True, ctx is done, or timeout elapses (0 waits until ctx is done).
+func WaitForCRDEstablished(ctx context.Context, client dynamic.Interface, name string, timeout time.Duration) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("crd name is required")
+	}
+	return pollUntil(ctx, timeout, fmt.Sprintf("crd/%s to be established", name), func(ctx context.Context) (bool, error) {
+		obj, err := client.Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return crdEstablished(obj), nil
+	})
+}
+
+func crdEstablished(obj *unstructured.Unstructured) bool {
+	conds, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conds {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if m["type"] == "Established" && m["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+// WaitForWebhookReady blocks until a Validating or MutatingWebhookConfiguration with the given name
+// exists and every service-backed webhook in it has at least one ready endpoint.
+func WaitForWebhookReady(ctx context.Context, client kubernetes.Interface, name string, timeout time.Duration) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("webhook configuration name is required")
+	}
+	return pollUntil(ctx, timeout, fmt.Sprintf("webhook/%s to be ready", name), func(ctx context.Context) (bool, error) {
+		var services []*admissionv1.ServiceReference
+		found := false
+		if cfg, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, name, metav1.GetOptions{}); err == nil {
+			found = true
+			for _, wh := range cfg.Webhooks {
+				services = append(services, wh.ClientConfig.Service)
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		if cfg, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, name, metav1.GetOptions{}); err == nil {
+			found = true
+			for _, wh := range cfg.Webhooks {
+				services = append(services, wh.ClientConfig.Service)
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		if !found {
+			return false, nil
+		}
+		for _, svc := range services {
+			if svc == nil {
+				// URL-based webhooks are outside the cluster; nothing to wait for.
+				continue
+			}
+			ready, err := serviceHasReadyEndpoint(ctx, client, svc.Namespace, svc.Name)
+			if err != nil || !ready {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+func serviceHasReadyEndpoint(ctx context.Context, client kubernetes.Interface, namespace, service string) (bool, error) {
+	slices, err := client.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + service,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, slice := range slices.Items {
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// pollUntil calls check every DefaultWaitPollInterval until it reports done. Transient API errors
+// are retried; the last one is included in the timeout error.
+func pollUntil(ctx context.Context, timeout time.Duration, what string, check func(context.Context) (bool, error)) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var lastErr error
+	for {
+		done, err := check(ctx)
+		if done {
+			return nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("timed out waiting for %s: %w", what, lastErr)
+			}
+			return fmt.Errorf("timed out waiting for %s", what)
+		case <-time.After(DefaultWaitPollInterval):
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// File: internal/kube/watch_expr.go
+// Brief: Internal kube package implementation for 'watch_expr'.
+
+// watch_expr.go parses `ktl watch` expressions: a kubectl-style resource list with
+// namespace and selector flags, e.g. "deploy,sts -n prod -l app.kubernetes.io/instance=foo".
+package kube
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WatchExpr is a parsed `ktl watch` expression.
+type WatchExpr struct {
+	// Resources are the requested resource types as typed (kinds, plurals, or short names).
+	Resources []string
+	// Names restricts the watch to these object names; empty watches every match.
+	Names         []string
+	Namespace     string
+	AllNamespaces bool
+	Selector      string
+	FieldSelector string
+}
+
+// ParseWatchExpr parses TYPE[,TYPE...][/NAME] [NAME...] followed by any of -n/--namespace,
+// -A/--all-namespaces, -l/--selector, and --field-selector (space or = separated).
+func ParseWatchExpr(raw string) (WatchExpr, error) {
+	var expr WatchExpr
+	tokens := strings.Fields(raw)
+	if len(tokens) == 0 {
+		return expr, fmt.Errorf("empty watch expression (expected e.g. 'deploy,sts -n prod')")
+	}
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if !strings.HasPrefix(tok, "-") {
+			if err := expr.addPositional(tok); err != nil {
+				return expr, err
+			}
+			continue
+		}
+		flag, value, hasValue := strings.Cut(tok, "=")
+		switch flag {
+		case "-A", "--all-namespaces":
+			if hasValue {
+				return expr, fmt.Errorf("%s does not take a value", flag)
+			}
+			expr.AllNamespaces = true
+			continue
+		case "-n", "--namespace", "-l", "--selector", "--field-selector":
+		default:
+			return expr, fmt.Errorf("unsupported flag %q in watch expression (supported: -n, -A, -l, --field-selector)", flag)
+		}
+		if !hasValue {
+			if i+1 >= len(tokens) {
+				return expr, fmt.Errorf("%s requires a value", flag)
+			}
+			i++
+			value = tokens[i]
+		}
+		switch flag {
+		case "-n", "--namespace":
+			expr.Namespace = value
+		case "-l", "--selector":
+			expr.Selector = value
+		case "--field-selector":
+			expr.FieldSelector = value
+		}
+	}
+	if len(expr.Resources) == 0 {
+		return expr, fmt.Errorf("watch expression %q names no resource type", raw)
+	}
+	if expr.AllNamespaces && expr.Namespace != "" {
+		return expr, fmt.Errorf("--namespace and --all-namespaces are mutually exclusive")
+	}
+	return expr, nil
+}
+
+func (e *WatchExpr) addPositional(tok string) error {
+	if len(e.Resources) > 0 {
+		if strings.Contains(tok, "/") || strings.Contains(tok, ",") {
+			return fmt.Errorf("unexpected %q: resource types come first, followed by plain names", tok)
+		}
+		e.Names = append(e.Names, tok)
+		return nil
+	}
+	types, name, hasName := strings.Cut(tok, "/")
+	for _, t := range strings.Split(types, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			return fmt.Errorf("invalid resource list %q", tok)
+		}
+		e.Resources = append(e.Resources, t)
+	}
+	if hasName {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid resource %q (expected type/name)", tok)
+		}
+		e.Names = append(e.Names, name)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// File: internal/kube/workload.go
+// Brief: Internal kube package implementation for 'workload'.
+
+// workload.go resolves `--for kind/name` workload references to their pod label selectors.
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// WorkloadRef names a pod-owning workload such as deployment/checkout.
+type WorkloadRef struct {
+	Kind string
+	Name string
+}
+
+func (r WorkloadRef) String() string {
+	return r.Kind + "/" + r.Name
+}
+
+var workloadKindAliases = map[string]string{
+	"deploy":       "deployment",
+	"deployment":   "deployment",
+	"deployments":  "deployment",
+	"sts":          "statefulset",
+	"statefulset":  "statefulset",
+	"statefulsets": "statefulset",
+	"ds":           "daemonset",
+	"daemonset":    "daemonset",
+	"daemonsets":   "daemonset",
+	"rs":           "replicaset",
+	"replicaset":   "replicaset",
+	"replicasets":  "replicaset",
+	"job":          "job",
+	"jobs":         "job",
+}
+
+// ParseWorkloadRef parses kind/name (e.g. deployment/checkout, sts/db).
+func ParseWorkloadRef(raw string) (WorkloadRef, error) {
+	kind, name, ok := strings.Cut(strings.TrimSpace(raw), "/")
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	name = strings.TrimSpace(name)
+	if !ok || kind == "" || name == "" {
+		return WorkloadRef{}, fmt.Errorf("invalid workload %q (expected kind/name, e.g. deployment/checkout)", raw)
+	}
+	canonical, known := workloadKindAliases[kind]
+	if !known {
+		return WorkloadRef{}, fmt.Errorf("unsupported workload kind %q (supported: deployment, statefulset, daemonset, replicaset, job)", kind)
+	}
+	return WorkloadRef{Kind: canonical, Name: name}, nil
+}
+
+// WorkloadSelector fetches the workload and returns its pod label selector.
+func WorkloadSelector(ctx context.Context, client kubernetes.Interface, namespace string, ref WorkloadRef) (labels.Selector, error) {
+	var selector *metav1.LabelSelector
+	switch ref.Kind {
+	case "deployment":
+		obj, err := client.AppsV1().Deployments(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get %s: %w", ref, err)
+		}
+		selector = obj.Spec.Selector
+	case "statefulset":
+		obj, err := client.AppsV1().StatefulSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get %s: %w", ref, err)
+		}
+		selector = obj.Spec.Selector
+	case "daemonset":
+		obj, err := client.AppsV1().DaemonSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get %s: %w", ref, err)
+		}
+		selector = obj.Spec.Selector
+	case "replicaset":
+		obj, err := client.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get %s: %w", ref, err)
+		}
+		selector = obj.Spec.Selector
+	case "job":
+		obj, err := client.BatchV1().Jobs(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get %s: %w", ref, err)
+		}
+		selector = obj.Spec.Selector
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q", ref.Kind)
+	}
+	if selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0) {
+		return nil, fmt.Errorf("%s has no pod selector", ref)
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("convert %s selector: %w", ref, err)
+	}
+	return sel, nil
+}
+
+// CombineSelectors ANDs label selector strings, skipping empty ones.
+func CombineSelectors(selectors ...string) string {
+	var parts []string
+	for _, s := range selectors {
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ",")
+}
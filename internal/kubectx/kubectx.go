@@ -0,0 +1,281 @@
+// File: internal/kubectx/kubectx.go
+// Brief: Kubeconfig context/namespace switching with a recently-used history.
+
+// Package kubectx switches the kubeconfig current context and namespace, keeps a short history of
+// recently used values, and ranks candidates for fuzzy pickers.
+package kubectx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// MaxHistory bounds how many recent contexts (and namespaces per context) are remembered.
+const MaxHistory = 10
+
+// Kubeconfig is a loaded kubeconfig that can be modified and written back.
+type Kubeconfig struct {
+	rules  *clientcmd.ClientConfigLoadingRules
+	Config *clientcmdapi.Config
+}
+
+// LoadKubeconfig loads the kubeconfig at path, or the default chain ($KUBECONFIG, ~/.kube/config)
+// when path is empty.
+func LoadKubeconfig(path string) (*Kubeconfig, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if p := strings.TrimSpace(path); p != "" {
+		rules.ExplicitPath = p
+	}
+	cfg, err := rules.GetStartingConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig: %w", err)
+	}
+	return &Kubeconfig{rules: rules, Config: cfg}, nil
+}
+
+// Contexts returns the context names in the kubeconfig, sorted.
+func (k *Kubeconfig) Contexts() []string {
+	names := make([]string, 0, len(k.Config.Contexts))
+	for name := range k.Config.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CurrentContext returns the kubeconfig current-context.
+func (k *Kubeconfig) CurrentContext() string {
+	return k.Config.CurrentContext
+}
+
+// Namespace returns the namespace configured for context (empty when unset).
+func (k *Kubeconfig) Namespace(context string) string {
+	if c, ok := k.Config.Contexts[context]; ok && c != nil {
+		return c.Namespace
+	}
+	return ""
+}
+
+// UseContext sets current-context and writes the kubeconfig file that defines it.
+func (k *Kubeconfig) UseContext(name string) error {
+	if _, ok := k.Config.Contexts[name]; !ok {
+		return fmt.Errorf("context %q not found in kubeconfig", name)
+	}
+	k.Config.CurrentContext = name
+	return clientcmd.ModifyConfig(k.rules, *k.Config, true)
+}
+
+// SetNamespace sets the default namespace of context and writes the kubeconfig.
+func (k *Kubeconfig) SetNamespace(context, namespace string) error {
+	c, ok := k.Config.Contexts[context]
+	if !ok || c == nil {
+		return fmt.Errorf("context %q not found in kubeconfig", context)
+	}
+	c.Namespace = namespace
+	return clientcmd.ModifyConfig(k.rules, *k.Config, true)
+}
+
+// History remembers recently used contexts and namespaces, most recent first.
+type History struct {
+	Contexts   []string            `json:"contexts,omitempty"`
+	Namespaces map[string][]string `json:"namespaces,omitempty"`
+}
+
+// DefaultHistoryPath returns ~/.ktl/ctx-history.json.
+func DefaultHistoryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(home, ".ktl", "ctx-history.json"), nil
+}
+
+// LoadHistory reads the history at path. A missing file yields an empty history.
+func LoadHistory(path string) (*History, error) {
+	h := &History{}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return h, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, h); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// Save writes the history to path.
+func (h *History) Save(path string) error {
+	raw, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(raw, '\n'), 0o600)
+}
+
+// RecordContext moves name to the front of the context history.
+func (h *History) RecordContext(name string) {
+	h.Contexts = pushRecent(h.Contexts, name)
+}
+
+// RecordNamespace moves namespace to the front of context's namespace history.
+func (h *History) RecordNamespace(context, namespace string) {
+	if h.Namespaces == nil {
+		h.Namespaces = map[string][]string{}
+	}
+	h.Namespaces[context] = pushRecent(h.Namespaces[context], namespace)
+}
+
+// PreviousContext returns the most recent context other than current (for `ktl ctx -`).
+func (h *History) PreviousContext(current string) string {
+	return previous(h.Contexts, current)
+}
+
+// PreviousNamespace returns the most recent namespace of context other than current.
+func (h *History) PreviousNamespace(context, current string) string {
+	return previous(h.Namespaces[context], current)
+}
+
+func previous(list []string, current string) string {
+	for _, item := range list {
+		if item != current {
+			return item
+		}
+	}
+	return ""
+}
+
+func pushRecent(list []string, item string) []string {
+	item = strings.TrimSpace(item)
+	if item == "" {
+		return list
+	}
+	out := []string{item}
+	for _, existing := range list {
+		if existing != item && len(out) < MaxHistory {
+			out = append(out, existing)
+		}
+	}
+	return out
+}
+
+// Rank returns the candidates matching query (a case-insensitive subsequence), best match first.
+// Ties prefer entries in recent (earlier is better), then alphabetical order. An empty query
+// returns every candidate with recent entries first.
+func Rank(query string, candidates, recent []string) []string {
+	recency := map[string]int{}
+	for i, r := range recent {
+		if _, ok := recency[r]; !ok {
+			recency[r] = i
+		}
+	}
+	type scored struct {
+		name  string
+		score int
+	}
+	var matches []scored
+	for _, c := range candidates {
+		if s, ok := fuzzyScore(query, c); ok {
+			matches = append(matches, scored{name: c, score: s})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		ri, iok := recency[matches[i].name]
+		rj, jok := recency[matches[j].name]
+		if iok != jok {
+			return iok
+		}
+		if iok && ri != rj {
+			return ri < rj
+		}
+		return matches[i].name < matches[j].name
+	})
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, m.name)
+	}
+	return out
+}
+
+// fuzzyScore reports whether query is a subsequence of candidate and scores the match: exact
+// matches win, then prefixes, then consecutive runs and matches at word boundaries (- _ . / :).
+func fuzzyScore(query, candidate string) (int, bool) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	c := strings.ToLower(candidate)
+	if q == "" {
+		return 0, true
+	}
+	if q == c {
+		return 1000, true
+	}
+	score := 0
+	if strings.HasPrefix(c, q) {
+		score += 500
+	} else if strings.Contains(c, q) {
+		score += 250
+	}
+	qi := 0
+	prevMatch := -2
+	for ci := 0; ci < len(c) && qi < len(q); ci++ {
+		if c[ci] != q[qi] {
+			continue
+		}
+		score += 10
+		if prevMatch == ci-1 {
+			score += 15
+		}
+		if ci == 0 || strings.ContainsRune("-_./:@", rune(c[ci-1])) {
+			score += 20
+		}
+		prevMatch = ci
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// Prefer shorter candidates when everything else is equal.
+	return score - len(c), true
+}
+
+// ShellSnippet returns shell code that exports KTL_CONTEXT and/or KTL_NAMESPACE so subsequent ktl
+// commands in the same shell resolve to them. shell is posix (default), fish, or powershell.
+func ShellSnippet(shell, context, namespace string) (string, error) {
+	type kv struct{ key, value string }
+	var vars []kv
+	if context != "" {
+		vars = append(vars, kv{"KTL_CONTEXT", context})
+	}
+	if namespace != "" {
+		vars = append(vars, kv{"KTL_NAMESPACE", namespace})
+	}
+	var b strings.Builder
+	for _, v := range vars {
+		switch strings.ToLower(strings.TrimSpace(shell)) {
+		case "", "posix", "sh", "bash", "zsh":
+			fmt.Fprintf(&b, "export %s='%s'\n", v.key, strings.ReplaceAll(v.value, "'", `'\''`))
+		case "fish":
+			fmt.Fprintf(&b, "set -gx %s '%s'\n", v.key, strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(v.value))
+		case "powershell", "pwsh":
+			fmt.Fprintf(&b, "$env:%s = '%s'\n", v.key, strings.ReplaceAll(v.value, "'", "''"))
+		default:
+			return "", fmt.Errorf("unsupported shell %q (expected posix, fish, or powershell)", shell)
+		}
+	}
+	return b.String(), nil
+}
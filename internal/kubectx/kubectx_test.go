@@ -0,0 +1,133 @@
+package kubectx
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: c
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: dev
+  context:
+    cluster: c
+    user: u
+- name: prod-eu
+  context:
+    cluster: c
+    user: u
+    namespace: payments
+- name: prod-us
+  context:
+    cluster: c
+    user: u
+current-context: dev
+users:
+- name: u
+  user:
+    token: t
+`
+
+func TestUseContextAndSetNamespaceRewriteKubeconfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	kc, err := LoadKubeconfig(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := kc.Contexts(); !reflect.DeepEqual(got, []string{"dev", "prod-eu", "prod-us"}) {
+		t.Fatalf("unexpected contexts %v", got)
+	}
+	if err := kc.UseContext("prod-eu"); err != nil {
+		t.Fatalf("use context: %v", err)
+	}
+	if err := kc.SetNamespace("prod-eu", "checkout"); err != nil {
+		t.Fatalf("set namespace: %v", err)
+	}
+	if err := kc.UseContext("missing"); err == nil {
+		t.Fatalf("expected unknown context error")
+	}
+	reloaded, err := LoadKubeconfig(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if reloaded.CurrentContext() != "prod-eu" || reloaded.Namespace("prod-eu") != "checkout" {
+		t.Fatalf("kubeconfig not updated: current=%q ns=%q", reloaded.CurrentContext(), reloaded.Namespace("prod-eu"))
+	}
+}
+
+func TestHistoryKeepsMostRecentFirst(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	h, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("load missing: %v", err)
+	}
+	for i := 0; i < MaxHistory+3; i++ {
+		h.RecordContext(string(rune('a' + i)))
+	}
+	h.RecordContext("c")
+	h.RecordNamespace("dev", "default")
+	h.RecordNamespace("dev", "payments")
+	if err := h.Save(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	h, err = LoadHistory(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(h.Contexts) != MaxHistory || h.Contexts[0] != "c" || h.Contexts[1] != "m" {
+		t.Fatalf("unexpected context history %v", h.Contexts)
+	}
+	if got := h.PreviousContext("c"); got != "m" {
+		t.Fatalf("expected previous context m, got %q", got)
+	}
+	if got := h.PreviousNamespace("dev", "payments"); got != "default" {
+		t.Fatalf("expected previous namespace default, got %q", got)
+	}
+}
+
+func TestRankPrefersExactPrefixAndRecent(t *testing.T) {
+	candidates := []string{"prod-us", "prod-eu", "staging-eu", "dev"}
+	if got := Rank("peu", candidates, nil); !reflect.DeepEqual(got, []string{"prod-eu"}) {
+		t.Fatalf("unexpected fuzzy match %v", got)
+	}
+	if got := Rank("prod", candidates, []string{"prod-us"}); got[0] != "prod-us" || len(got) != 2 {
+		t.Fatalf("expected recent prod-us first, got %v", got)
+	}
+	if got := Rank("eu", candidates, nil); len(got) != 2 {
+		t.Fatalf("expected two eu matches, got %v", got)
+	}
+	if got := Rank("", candidates, []string{"staging-eu"}); got[0] != "staging-eu" || len(got) != 4 {
+		t.Fatalf("expected recent first for empty query, got %v", got)
+	}
+}
+
+func TestShellSnippet(t *testing.T) {
+	posix, err := ShellSnippet("", "it's", "ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if posix != "export KTL_CONTEXT='it'\\''s'\nexport KTL_NAMESPACE='ns'\n" {
+		t.Fatalf("unexpected posix snippet %q", posix)
+	}
+	fish, _ := ShellSnippet("fish", "prod", "")
+	if fish != "set -gx KTL_CONTEXT 'prod'\n" {
+		t.Fatalf("unexpected fish snippet %q", fish)
+	}
+	pwsh, _ := ShellSnippet("powershell", "", "default")
+	if !strings.HasPrefix(pwsh, "$env:KTL_NAMESPACE = 'default'") {
+		t.Fatalf("unexpected powershell snippet %q", pwsh)
+	}
+	if _, err := ShellSnippet("tcsh", "x", ""); err == nil {
+		t.Fatalf("expected unsupported shell error")
+	}
+}
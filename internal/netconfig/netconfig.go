@@ -0,0 +1,467 @@
+// File: internal/netconfig/netconfig.go
+// Brief: Process-wide proxy and TLS trust settings.
+
+// Package netconfig applies the --proxy, --tls-ca-file, and --airgap flags to every outbound
+// connection ktl makes: Kubernetes clients, Helm chart repositories and OCI registries, and plain
+// HTTP fetches such as plan --compare URLs. --insecure-skip-tls-verify is narrower: it only
+// reaches the deployment targets (the Kubernetes API, chart repositories, and registries), so
+// telemetry, tracing, audit webhooks, value sources, and self-update keep verifying certificates.
+package netconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/getter"
+	"k8s.io/client-go/rest"
+)
+
+// Options are the network overrides for one ktl invocation. The zero value keeps the defaults
+// (HTTP(S)_PROXY/NO_PROXY from the environment and the system trust store).
+type Options struct {
+	// Proxy is an http, https, or socks5 URL used for every connection not excluded by NO_PROXY.
+	Proxy string
+	// CAFile is a PEM bundle trusted in addition to the system roots (and, for Kubernetes, in
+	// addition to the kubeconfig's certificate-authority).
+	CAFile string
+	// InsecureSkipVerify disables TLS certificate verification for the deployment targets: the
+	// Kubernetes API (ApplyREST), chart repositories (ApplyChartPathOptions), and
+	// registries (TargetTransport).
+	InsecureSkipVerify bool
+	// Airgap refuses every outbound request except to the Kubernetes API server, loopback
+	// addresses, and AirgapAllow hosts, and restricts charts to local paths.
+	Airgap bool
+	// AirgapAllow lists hosts (or .domain suffixes) still reachable in air-gapped mode.
+	AirgapAllow []string
+}
+
+// ErrAirgap is wrapped by every error caused by air-gapped mode.
+var ErrAirgap = errors.New("blocked by air-gapped mode (--airgap)")
+
+// Active reports whether o changes anything.
+func (o Options) Active() bool {
+	return strings.TrimSpace(o.Proxy) != "" || strings.TrimSpace(o.CAFile) != "" || o.InsecureSkipVerify || o.Airgap
+}
+
+type state struct {
+	opts     Options
+	proxyURL *url.URL
+	caPEM    []byte
+	roots    *x509.CertPool
+	// allowed holds the air-gap allow list plus the API servers seen by ApplyREST.
+	allowed []string
+}
+
+var (
+	mu      sync.RWMutex
+	current state
+)
+
+// Set validates opts, loads the CA bundle, and makes opts the process-wide setting. It also
+// exports HTTPS_PROXY/HTTP_PROXY so helpers that read the environment (Helm getters, client-go)
+// agree, and points http.DefaultTransport, which http.DefaultClient builds on, at the proxy and CA
+// bundle. http.DefaultTransport keeps verifying certificates even with InsecureSkipVerify. Set
+// must run before the first outbound request.
+func Set(opts Options) error {
+	opts.Proxy = strings.TrimSpace(opts.Proxy)
+	opts.CAFile = strings.TrimSpace(opts.CAFile)
+	next := state{opts: opts}
+	for _, host := range opts.AirgapAllow {
+		if host = normalizeHost(host); host != "" {
+			next.allowed = append(next.allowed, host)
+		}
+	}
+	if opts.Proxy != "" {
+		u, err := ParseProxy(opts.Proxy)
+		if err != nil {
+			return err
+		}
+		next.proxyURL = u
+	}
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return fmt.Errorf("read --tls-ca-file: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil || roots == nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("--tls-ca-file %s contains no PEM certificates", opts.CAFile)
+		}
+		next.caPEM = pem
+		next.roots = roots
+	}
+	if next.proxyURL != nil {
+		for _, key := range []string{"HTTPS_PROXY", "HTTP_PROXY", "https_proxy", "http_proxy"} {
+			if err := os.Setenv(key, next.proxyURL.String()); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.InsecureSkipVerify {
+		if err := os.Setenv("HELM_KUBEINSECURE_SKIP_TLS_VERIFY", "true"); err != nil {
+			return err
+		}
+	}
+
+	mu.Lock()
+	current = next
+	mu.Unlock()
+
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		configureTransport(t, false)
+	}
+	return nil
+}
+
+// Current returns the options configured via Set.
+func Current() Options {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current.opts
+}
+
+// ParseProxy validates a --proxy value. A bare host:port is treated as http://host:port.
+func ParseProxy(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, errors.New("--proxy is empty")
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse --proxy: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("--proxy scheme %q is not supported (use http, https, or socks5)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("--proxy %q has no host", raw)
+	}
+	return u, nil
+}
+
+// Warnings lists the messages ktl prints before running with opts; disabling verification is
+// never silent.
+func Warnings(opts Options) []string {
+	var out []string
+	if opts.InsecureSkipVerify {
+		out = append(out, "--insecure-skip-tls-verify is set: TLS certificates are NOT verified for the Kubernetes API, Helm chart repositories, or registries in this command. Anyone on the network path can impersonate these servers.")
+	}
+	if strings.TrimSpace(opts.Proxy) != "" {
+		out = append(out, fmt.Sprintf("all outbound connections not matched by NO_PROXY go through proxy %s", redactProxy(opts.Proxy)))
+	}
+	return out
+}
+
+func redactProxy(raw string) string {
+	u, err := ParseProxy(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
+
+// TLSConfig returns the client TLS configuration for the deployment targets under the current
+// settings, or nil when the defaults apply.
+func TLSConfig() *tls.Config {
+	return tlsConfig(true)
+}
+
+// tlsConfig builds the client TLS configuration; insecure reports whether the caller is a
+// deployment target that --insecure-skip-tls-verify applies to.
+func tlsConfig(insecure bool) *tls.Config {
+	mu.RLock()
+	defer mu.RUnlock()
+	insecure = insecure && current.opts.InsecureSkipVerify
+	if current.roots == nil && !insecure {
+		return nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: current.roots}
+	if insecure {
+		cfg.InsecureSkipVerify = true // #nosec G402 -- explicit --insecure-skip-tls-verify
+	}
+	return cfg
+}
+
+// ProxyFunc returns the proxy selector for the current settings: the --proxy URL for hosts not
+// excluded by NO_PROXY, or the environment otherwise. In air-gapped mode it fails requests to
+// hosts CheckURL rejects.
+func ProxyFunc() func(*http.Request) (*url.URL, error) {
+	mu.RLock()
+	proxyURL := current.proxyURL
+	airgap := current.opts.Airgap
+	mu.RUnlock()
+	selectProxy := http.ProxyFromEnvironment
+	if proxyURL != nil {
+		selectProxy = func(req *http.Request) (*url.URL, error) {
+			if bypassProxy(req.URL.Hostname(), os.Getenv("NO_PROXY")+","+os.Getenv("no_proxy")) {
+				return nil, nil
+			}
+			return proxyURL, nil
+		}
+	}
+	if !airgap {
+		return selectProxy
+	}
+	// The transport consults Proxy before dialing, which makes it the one hook every request
+	// built on these transports passes through.
+	return func(req *http.Request) (*url.URL, error) {
+		if err := CheckURL(req.URL.String()); err != nil {
+			return nil, err
+		}
+		return selectProxy(req)
+	}
+}
+
+// Airgapped reports whether air-gapped mode is on.
+func Airgapped() bool {
+	return Current().Airgap
+}
+
+// CheckURL returns an error wrapping ErrAirgap when air-gapped mode forbids fetching raw.
+func CheckURL(raw string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+	if !current.opts.Airgap {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("%w: cannot fetch %q", ErrAirgap, raw)
+	}
+	host := normalizeHost(u.Hostname())
+	if isLoopback(host) || bypassProxy(host, strings.Join(current.allowed, ",")) {
+		return nil
+	}
+	return fmt.Errorf("%w: refusing to fetch %s; use a local copy or allow the host with --airgap-allow %s", ErrAirgap, u.Redacted(), host)
+}
+
+// CheckDialTarget is CheckURL for a gRPC dial target ("host:port", optionally with a resolver
+// scheme such as dns:///host:port).
+func CheckDialTarget(target string) error {
+	target = strings.TrimSpace(target)
+	if _, rest, ok := strings.Cut(target, ":///"); ok {
+		target = rest
+	}
+	return CheckURL("grpc://" + target)
+}
+
+// CheckGitRemote is CheckURL for a git remote. Local paths and file:// URLs are always allowed;
+// scp-style remotes (git@host:org/repo.git) are checked by host.
+func CheckGitRemote(remote string) error {
+	if !Airgapped() {
+		return nil
+	}
+	remote = strings.TrimSpace(remote)
+	if strings.HasPrefix(remote, "file://") {
+		return nil
+	}
+	if strings.Contains(remote, "://") {
+		return CheckURL(remote)
+	}
+	if host, _, ok := strings.Cut(remote, ":"); ok && !strings.ContainsAny(host, `/\`) && len(host) > 1 {
+		if _, after, found := strings.Cut(host, "@"); found {
+			host = after
+		}
+		return CheckURL("ssh://" + host)
+	}
+	return nil
+}
+
+// GitConfigArgs returns the "-c" options that make a git subprocess use --proxy and --tls-ca-file.
+// Like Helm, git replaces the system roots with http.sslCAInfo, so the bundle must cover every
+// remote reached.
+func GitConfigArgs() []string {
+	cur := Current()
+	var args []string
+	if cur.Proxy != "" {
+		if u, err := ParseProxy(cur.Proxy); err == nil {
+			args = append(args, "-c", "http.proxy="+u.String())
+		}
+	}
+	if cur.CAFile != "" {
+		args = append(args, "-c", "http.sslCAInfo="+cur.CAFile)
+	}
+	return args
+}
+
+// RootCAs returns the system roots plus --tls-ca-file, or nil when no CA bundle is configured.
+// The pool is a copy the caller may extend.
+func RootCAs() *x509.CertPool {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current.roots == nil {
+		return nil
+	}
+	return current.roots.Clone()
+}
+
+// CheckChartRef returns an error wrapping ErrAirgap when air-gapped mode forbids resolving the
+// chart: only local chart directories and archives, or charts on allow-listed hosts, are usable.
+func CheckChartRef(ref, repoURL string) error {
+	if !Airgapped() {
+		return nil
+	}
+	if strings.TrimSpace(repoURL) != "" {
+		return CheckURL(repoURL)
+	}
+	ref = strings.TrimSpace(ref)
+	if strings.Contains(ref, "://") {
+		return CheckURL(ref)
+	}
+	if _, err := os.Stat(ref); err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: chart %q is not a local path; pull it (helm pull) and its dependencies (helm dependency build) before going offline", ErrAirgap, ref)
+}
+
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if u, err := url.Parse(host); err == nil && u.Hostname() != "" && strings.Contains(host, "://") {
+		host = u.Hostname()
+	}
+	return strings.Trim(host, "[]")
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func bypassProxy(host, noProxy string) bool {
+	host = strings.ToLower(strings.TrimSpace(host))
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(entry)), ".")
+		switch {
+		case entry == "":
+			continue
+		case entry == "*", host == entry, strings.HasSuffix(host, "."+entry):
+			return true
+		}
+	}
+	return false
+}
+
+func configureTransport(t *http.Transport, insecure bool) {
+	t.Proxy = ProxyFunc()
+	if cfg := tlsConfig(insecure); cfg != nil {
+		t.TLSClientConfig = cfg
+	}
+}
+
+func newTransport(insecure bool) *http.Transport {
+	var t *http.Transport
+	if base, ok := http.DefaultTransport.(*http.Transport); ok {
+		t = base.Clone()
+	} else {
+		t = &http.Transport{}
+	}
+	configureTransport(t, insecure)
+	return t
+}
+
+// Transport returns a new transport honoring --proxy, --tls-ca-file, and --airgap. It always
+// verifies certificates; use TargetTransport for registries and chart repositories.
+func Transport() *http.Transport {
+	return newTransport(false)
+}
+
+// TargetTransport is Transport plus --insecure-skip-tls-verify, for connections to the
+// registries and chart repositories a command deploys from.
+func TargetTransport() *http.Transport {
+	return newTransport(true)
+}
+
+// HTTPClient returns a client built on Transport.
+func HTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: Transport(), Timeout: timeout}
+}
+
+// ApplyREST applies the settings to a Kubernetes client config. The CA bundle is appended to the
+// cluster's own CA so --tls-ca-file can add a proxy's CA without breaking the API server's.
+func ApplyREST(cfg *rest.Config) {
+	if cfg == nil {
+		return
+	}
+	if Current().Proxy != "" {
+		cfg.Proxy = ProxyFunc()
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if current.opts.Airgap {
+		// The cluster is the one remote endpoint air-gapped mode keeps reachable.
+		if u, err := url.Parse(cfg.Host); err == nil && u.Hostname() != "" {
+			current.allowed = append(current.allowed, normalizeHost(u.Hostname()))
+		} else if host := normalizeHost(strings.Split(cfg.Host, ":")[0]); host != "" {
+			current.allowed = append(current.allowed, host)
+		}
+	}
+	if current.opts.InsecureSkipVerify {
+		cfg.Insecure = true
+		cfg.CAData = nil
+		cfg.CAFile = ""
+		return
+	}
+	if len(current.caPEM) == 0 {
+		return
+	}
+	var bundle []byte
+	switch {
+	case len(cfg.CAData) > 0:
+		bundle = append(append([]byte(nil), cfg.CAData...), '\n')
+	case cfg.CAFile != "":
+		raw, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			// Leave the kubeconfig CA in place; client-go reports the read error itself.
+			return
+		}
+		bundle = append(raw, '\n')
+	}
+	cfg.CAData = append(bundle, current.caPEM...)
+	cfg.CAFile = ""
+}
+
+// ApplyChartPathOptions applies the settings to Helm chart lookups (repository index and chart
+// downloads). Helm replaces the system roots with CaFile, so a custom CA must cover every
+// repository reached, which is the case behind a TLS-intercepting proxy.
+func ApplyChartPathOptions(opts *action.ChartPathOptions) {
+	if opts == nil {
+		return
+	}
+	cur := Current()
+	if opts.CaFile == "" {
+		opts.CaFile = cur.CAFile
+	}
+	if cur.InsecureSkipVerify {
+		opts.InsecureSkipTLSverify = true
+	}
+}
+
+// GetterOptions returns Helm getter options for values files fetched over HTTP, honoring the
+// current settings. Values URLs are not deployment targets, so they keep verifying certificates.
+func GetterOptions() []getter.Option {
+	if !Current().Active() {
+		return nil
+	}
+	return []getter.Option{getter.WithTransport(Transport())}
+}
@@ -0,0 +1,236 @@
+package netconfig
+
+import (
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"k8s.io/client-go/rest"
+)
+
+// setForTest applies opts and restores the process-wide state afterwards.
+func setForTest(t *testing.T, opts Options) {
+	t.Helper()
+	for _, key := range []string{"HTTPS_PROXY", "HTTP_PROXY", "https_proxy", "http_proxy", "NO_PROXY", "no_proxy", "HELM_KUBEINSECURE_SKIP_TLS_VERIFY"} {
+		t.Setenv(key, "")
+	}
+	base := http.DefaultTransport.(*http.Transport)
+	saved := base.Clone()
+	t.Cleanup(func() {
+		mu.Lock()
+		current = state{}
+		mu.Unlock()
+		base.Proxy = saved.Proxy
+		base.TLSClientConfig = saved.TLSClientConfig
+	})
+	if err := Set(opts); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+}
+
+func writeServerCA(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(path, block, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCAFileTrustedByDefaultAndCustomClients(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	setForTest(t, Options{CAFile: writeServerCA(t, srv)})
+	for name, client := range map[string]*http.Client{
+		"default": {Transport: http.DefaultTransport.(*http.Transport).Clone(), Timeout: 5 * time.Second},
+		"custom":  HTTPClient(5 * time.Second),
+	} {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("%s client: %v", name, err)
+		}
+		resp.Body.Close()
+	}
+}
+
+func TestSetRejectsBadInput(t *testing.T) {
+	bad := filepath.Join(t.TempDir(), "bad.pem")
+	if err := os.WriteFile(bad, []byte("not a cert"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(Options{CAFile: bad}); err == nil || !strings.Contains(err.Error(), "no PEM certificates") {
+		t.Fatalf("expected PEM error, got %v", err)
+	}
+	if err := Set(Options{Proxy: "ftp://proxy:21"}); err == nil {
+		t.Fatalf("expected unsupported scheme error")
+	}
+	if u, err := ParseProxy("proxy.internal:3128"); err != nil || u.String() != "http://proxy.internal:3128" {
+		t.Fatalf("expected bare host to default to http, got %v %v", u, err)
+	}
+}
+
+func TestProxyHonorsNoProxy(t *testing.T) {
+	var hits atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte("via proxy"))
+	}))
+	defer proxy.Close()
+
+	setForTest(t, Options{Proxy: proxy.URL})
+	if got := os.Getenv("HTTPS_PROXY"); got != proxy.URL {
+		t.Fatalf("expected HTTPS_PROXY=%s, got %q", proxy.URL, got)
+	}
+	resp, err := HTTPClient(5 * time.Second).Get("http://charts.example.invalid/index.yaml")
+	if err != nil {
+		t.Fatalf("get via proxy: %v", err)
+	}
+	resp.Body.Close()
+	if hits.Load() != 1 {
+		t.Fatalf("expected request to go through the proxy, hits=%d", hits.Load())
+	}
+
+	if !bypassProxy("api.corp.example", "localhost,.corp.example") || bypassProxy("corp.example.org", ".corp.example") {
+		t.Fatalf("unexpected NO_PROXY matching")
+	}
+}
+
+func TestApplyRESTAppendsCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	caFile := writeServerCA(t, srv)
+	setForTest(t, Options{CAFile: caFile, Proxy: "http://proxy.internal:3128"})
+
+	cfg := &rest.Config{TLSClientConfig: rest.TLSClientConfig{CAData: []byte("cluster-ca")}}
+	ApplyREST(cfg)
+	if !strings.HasPrefix(string(cfg.CAData), "cluster-ca\n") || !strings.Contains(string(cfg.CAData), "BEGIN CERTIFICATE") {
+		t.Fatalf("expected cluster CA followed by the custom CA, got %q", cfg.CAData)
+	}
+	if cfg.Proxy == nil {
+		t.Fatalf("expected proxy to be set")
+	}
+
+	cpo := action.ChartPathOptions{}
+	ApplyChartPathOptions(&cpo)
+	if cpo.CaFile != caFile || cpo.InsecureSkipTLSverify {
+		t.Fatalf("unexpected chart options %+v", cpo)
+	}
+}
+
+func TestInsecureSkipVerify(t *testing.T) {
+	setForTest(t, Options{InsecureSkipVerify: true})
+	cfg := &rest.Config{TLSClientConfig: rest.TLSClientConfig{CAFile: "/etc/ca.pem"}}
+	ApplyREST(cfg)
+	if !cfg.Insecure || cfg.CAFile != "" {
+		t.Fatalf("expected insecure REST config, got %+v", cfg.TLSClientConfig)
+	}
+	if tlsCfg := TLSConfig(); tlsCfg == nil || !tlsCfg.InsecureSkipVerify {
+		t.Fatalf("expected insecure TLS config")
+	}
+	if tr := TargetTransport(); tr.TLSClientConfig == nil || !tr.TLSClientConfig.InsecureSkipVerify {
+		t.Fatalf("expected registry transport to skip verification")
+	}
+	for name, tr := range map[string]*http.Transport{
+		"default": http.DefaultTransport.(*http.Transport),
+		"custom":  Transport(),
+	} {
+		if tr.TLSClientConfig != nil && tr.TLSClientConfig.InsecureSkipVerify {
+			t.Fatalf("%s transport must keep verifying certificates", name)
+		}
+	}
+	if os.Getenv("HELM_KUBEINSECURE_SKIP_TLS_VERIFY") != "true" {
+		t.Fatalf("expected Helm kube client to be told to skip verification")
+	}
+	if w := Warnings(Current()); len(w) != 1 || !strings.Contains(w[0], "NOT verified") {
+		t.Fatalf("expected loud warning, got %v", w)
+	}
+}
+
+func TestAirgapBlocksUnlistedHosts(t *testing.T) {
+	setForTest(t, Options{Airgap: true, AirgapAllow: []string{".registry.internal"}})
+
+	_, err := http.Get("https://charts.example.com/index.yaml")
+	if !errors.Is(err, ErrAirgap) || !strings.Contains(err.Error(), "--airgap-allow charts.example.com") {
+		t.Fatalf("expected air-gap error with hint, got %v", err)
+	}
+	for _, allowed := range []string{"https://harbor.registry.internal/v2/", "http://127.0.0.1:8080/", "http://localhost/x"} {
+		if err := CheckURL(allowed); err != nil {
+			t.Fatalf("expected %s to be allowed, got %v", allowed, err)
+		}
+	}
+
+	if err := CheckURL("https://10.0.0.1:6443/api"); err == nil {
+		t.Fatalf("expected API server to be blocked before a client is built")
+	}
+	ApplyREST(&rest.Config{Host: "https://10.0.0.1:6443"})
+	if err := CheckURL("https://10.0.0.1:6443/api"); err != nil {
+		t.Fatalf("expected API server to be reachable, got %v", err)
+	}
+}
+
+func TestAirgapChartRefs(t *testing.T) {
+	setForTest(t, Options{Airgap: true, AirgapAllow: []string{"charts.internal"}})
+	local := t.TempDir()
+	if err := CheckChartRef(local, ""); err != nil {
+		t.Fatalf("expected local chart to be allowed, got %v", err)
+	}
+	if err := CheckChartRef("oci://charts.internal/team/api", ""); err != nil {
+		t.Fatalf("expected allow-listed OCI chart, got %v", err)
+	}
+	for _, ref := range []string{"bitnami/nginx", "oci://ghcr.io/org/chart"} {
+		if err := CheckChartRef(ref, ""); !errors.Is(err, ErrAirgap) {
+			t.Fatalf("expected %s to be blocked, got %v", ref, err)
+		}
+	}
+	if err := CheckChartRef("nginx", "https://charts.bitnami.com/bitnami"); !errors.Is(err, ErrAirgap) {
+		t.Fatalf("expected --repo URL to be blocked, got %v", err)
+	}
+}
+
+func TestAirgapDialTargetsAndGitRemotes(t *testing.T) {
+	setForTest(t, Options{Airgap: true, AirgapAllow: []string{"git.internal"}})
+	for _, target := range []string{"127.0.0.1:7443", "dns:///localhost:7443"} {
+		if err := CheckDialTarget(target); err != nil {
+			t.Fatalf("expected %s to be allowed, got %v", target, err)
+		}
+	}
+	if err := CheckDialTarget("agent.example.com:7443"); !errors.Is(err, ErrAirgap) {
+		t.Fatalf("expected the remote agent to be blocked, got %v", err)
+	}
+	for _, remote := range []string{t.TempDir(), "file:///srv/repo.git", "https://git.internal/org/repo.git", "git@git.internal:org/repo.git"} {
+		if err := CheckGitRemote(remote); err != nil {
+			t.Fatalf("expected %s to be allowed, got %v", remote, err)
+		}
+	}
+	for _, remote := range []string{"https://github.com/org/repo.git", "git@github.com:org/repo.git"} {
+		if err := CheckGitRemote(remote); !errors.Is(err, ErrAirgap) {
+			t.Fatalf("expected %s to be blocked, got %v", remote, err)
+		}
+	}
+}
+
+func TestGitConfigArgs(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	ca := writeServerCA(t, srv)
+	setForTest(t, Options{Proxy: "proxy.internal:3128", CAFile: ca})
+	got := strings.Join(GitConfigArgs(), " ")
+	if got != "-c http.proxy=http://proxy.internal:3128 -c http.sslCAInfo="+ca {
+		t.Fatalf("unexpected git args %q", got)
+	}
+	if RootCAs() == nil {
+		t.Fatalf("expected the CA bundle to be exposed as a pool")
+	}
+}
@@ -0,0 +1,106 @@
+// File: internal/portalapi/plans.go
+// Brief: Internal portalapi package implementation for 'plans'.
+
+package portalapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PlanSummary describes the newest saved plan for one release.
+type PlanSummary struct {
+	Release     string         `json:"release"`
+	Namespace   string         `json:"namespace"`
+	ChartRef    string         `json:"chartReference,omitempty"`
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Summary     map[string]int `json:"summary,omitempty"`
+	File        string         `json:"file"`
+}
+
+type savedPlan struct {
+	PlanSummary
+	document []byte
+}
+
+var planDataScript = regexp.MustCompile(`(?s)<script[^>]+id=["']ktlPlanData["'][^>]*>(.*?)</script>`)
+
+// latestPlans scans dir for plan JSON/HTML documents and keeps the newest per release.
+func latestPlans(dir string) (map[string]savedPlan, error) {
+	out := map[string]savedPlan{}
+	if strings.TrimSpace(dir) == "" {
+		return out, nil
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".json" && ext != ".html" {
+			return nil
+		}
+		plan, ok := readPlan(path, ext)
+		if !ok {
+			return nil
+		}
+		key := planKey(plan.Namespace, plan.Release)
+		if prev, exists := out[key]; !exists || plan.GeneratedAt.After(prev.GeneratedAt) {
+			out[key] = plan
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("scan plans dir: %w", err)
+	}
+	return out, nil
+}
+
+// readPlan loads path and reports whether it is a ktl plan document.
+func readPlan(path, ext string) (savedPlan, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return savedPlan{}, false
+	}
+	if ext == ".html" {
+		match := planDataScript.FindSubmatch(data)
+		if match == nil {
+			return savedPlan{}, false
+		}
+		data = []byte(strings.TrimSpace(string(match[1])))
+	}
+	var doc struct {
+		Release     string         `json:"release"`
+		Namespace   string         `json:"namespace"`
+		ChartRef    string         `json:"chartReference"`
+		GeneratedAt time.Time      `json:"generatedAt"`
+		Summary     map[string]int `json:"summary"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil || doc.Release == "" || doc.GeneratedAt.IsZero() {
+		return savedPlan{}, false
+	}
+	return savedPlan{
+		PlanSummary: PlanSummary{
+			Release:     doc.Release,
+			Namespace:   doc.Namespace,
+			ChartRef:    doc.ChartRef,
+			GeneratedAt: doc.GeneratedAt,
+			Summary:     doc.Summary,
+			File:        path,
+		},
+		document: data,
+	}, true
+}
+
+func planKey(namespace, release string) string {
+	return namespace + "/" + release
+}
@@ -0,0 +1,224 @@
+// File: internal/portalapi/server.go
+// Brief: Internal portalapi package implementation for 'server'.
+
+// Package portalapi serves read-only JSON views of releases, saved plans, and stack run
+// history for internal developer portals (e.g. a Backstage plugin).
+package portalapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/kubekattle/ktl/internal/stack"
+)
+
+const (
+	defaultRunLimit = 20
+	maxRunLimit     = 500
+)
+
+// Release is one Helm release in the inventory.
+type Release struct {
+	Name       string    `json:"name"`
+	Namespace  string    `json:"namespace"`
+	Revision   int       `json:"revision"`
+	Status     string    `json:"status"`
+	Chart      string    `json:"chart"`
+	AppVersion string    `json:"appVersion,omitempty"`
+	Updated    time.Time `json:"updated,omitempty"`
+}
+
+// ReleaseLister returns the current release inventory.
+type ReleaseLister func(ctx context.Context) ([]Release, error)
+
+// Options configures the API server.
+type Options struct {
+	Addr string
+	// Token is required on every /api request as `Authorization: Bearer <token>`.
+	Token string
+	// Releases lists Helm releases; nil disables the releases endpoint.
+	Releases ReleaseLister
+	// PlansDir holds saved `ktl apply plan --format json|html --output` documents.
+	PlansDir string
+	// StackRoots are stack directories whose sqlite state stores provide run history.
+	StackRoots []string
+	Logger     logr.Logger
+}
+
+// Server exposes the portal API.
+type Server struct {
+	opts Options
+}
+
+// New validates opts and returns a server.
+func New(opts Options) (*Server, error) {
+	if strings.TrimSpace(opts.Token) == "" {
+		return nil, fmt.Errorf("an API token is required")
+	}
+	return &Server{opts: opts}, nil
+}
+
+// Handler returns the HTTP handler with all routes mounted.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = fmt.Fprint(w, "ok")
+	})
+	mux.Handle("/api/v1/releases", s.guard(s.handleReleases))
+	mux.Handle("/api/v1/plans", s.guard(s.handlePlans))
+	mux.Handle("/api/v1/plans/", s.guard(s.handlePlan))
+	mux.Handle("/api/v1/stacks/runs", s.guard(s.handleStackRuns))
+	return mux
+}
+
+// Run serves until ctx is cancelled.
+func (s *Server) Run(ctx context.Context) error {
+	srv := &http.Server{Addr: s.opts.Addr, Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	s.opts.Logger.Info("portal API listening", "addr", s.opts.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// guard enforces read-only methods and bearer-token auth.
+func (s *Server) guard(next http.HandlerFunc) http.Handler {
+	want := []byte(s.opts.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, "read-only API: only GET is supported")
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ktl"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next(w, r)
+	})
+}
+
+func (s *Server) handleReleases(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Releases == nil {
+		writeError(w, http.StatusNotFound, "release inventory is not configured")
+		return
+	}
+	releases, err := s.opts.Releases(r.Context())
+	if err != nil {
+		s.opts.Logger.Error(err, "list releases")
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("list releases: %v", err))
+		return
+	}
+	if ns := strings.TrimSpace(r.URL.Query().Get("namespace")); ns != "" {
+		filtered := releases[:0]
+		for _, rel := range releases {
+			if rel.Namespace == ns {
+				filtered = append(filtered, rel)
+			}
+		}
+		releases = filtered
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		if releases[i].Namespace != releases[j].Namespace {
+			return releases[i].Namespace < releases[j].Namespace
+		}
+		return releases[i].Name < releases[j].Name
+	})
+	writeJSON(w, map[string]any{"releases": releases})
+}
+
+func (s *Server) handlePlans(w http.ResponseWriter, _ *http.Request) {
+	plans, err := latestPlans(s.opts.PlansDir)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	summaries := make([]PlanSummary, 0, len(plans))
+	for _, p := range plans {
+		summaries = append(summaries, p.PlanSummary)
+	}
+	writeJSON(w, map[string]any{"plans": summaries})
+}
+
+// handlePlan serves /api/v1/plans/{namespace}/{release}: the newest saved plan document.
+func (s *Server) handlePlan(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/plans/"), "/")
+	namespace, release, ok := strings.Cut(rest, "/")
+	if !ok || namespace == "" || release == "" || strings.Contains(release, "/") {
+		writeError(w, http.StatusNotFound, "expected /api/v1/plans/{namespace}/{release}")
+		return
+	}
+	plans, err := latestPlans(s.opts.PlansDir)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	plan, found := plans[planKey(namespace, release)]
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no saved plan for %s/%s", namespace, release))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(plan.document)
+}
+
+func (s *Server) handleStackRuns(w http.ResponseWriter, r *http.Request) {
+	limit := defaultRunLimit
+	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(n, maxRunLimit)
+	}
+	type stackRuns struct {
+		Root  string                `json:"root"`
+		Runs  []stack.RunIndexEntry `json:"runs"`
+		Error string                `json:"error,omitempty"`
+	}
+	out := make([]stackRuns, 0, len(s.opts.StackRoots))
+	for _, root := range s.opts.StackRoots {
+		entry := stackRuns{Root: root, Runs: []stack.RunIndexEntry{}}
+		runs, err := stack.ListRuns(root, limit)
+		if err != nil {
+			entry.Error = err.Error()
+		} else if runs != nil {
+			entry.Runs = runs
+		}
+		out = append(out, entry)
+	}
+	writeJSON(w, map[string]any{"stacks": out})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
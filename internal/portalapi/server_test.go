@@ -0,0 +1,112 @@
+package portalapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T, plansDir string) http.Handler {
+	t.Helper()
+	srv, err := New(Options{
+		Token:    "tok",
+		PlansDir: plansDir,
+		Releases: func(context.Context) ([]Release, error) {
+			return []Release{
+				{Name: "web", Namespace: "prod", Revision: 3, Status: "deployed"},
+				{Name: "api", Namespace: "dev", Revision: 1, Status: "failed"},
+			}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	return srv.Handler()
+}
+
+func do(t *testing.T, h http.Handler, method, path, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerRequiresTokenAndReadOnly(t *testing.T) {
+	if _, err := New(Options{}); err == nil {
+		t.Fatalf("expected an error without a token")
+	}
+	h := newTestServer(t, "")
+	if rec := do(t, h, http.MethodGet, "/api/v1/releases", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+	if rec := do(t, h, http.MethodGet, "/api/v1/releases", "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with wrong token, got %d", rec.Code)
+	}
+	if rec := do(t, h, http.MethodPost, "/api/v1/releases", "tok"); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for POST, got %d", rec.Code)
+	}
+	if rec := do(t, h, http.MethodGet, "/healthz", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected unauthenticated healthz, got %d", rec.Code)
+	}
+}
+
+func TestServerReleasesFilterAndSort(t *testing.T) {
+	h := newTestServer(t, "")
+	rec := do(t, h, http.MethodGet, "/api/v1/releases", "tok")
+	var body struct {
+		Releases []Release `json:"releases"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v (%s)", err, rec.Body.String())
+	}
+	if len(body.Releases) != 2 || body.Releases[0].Namespace != "dev" {
+		t.Fatalf("expected releases sorted by namespace, got %+v", body.Releases)
+	}
+	rec = do(t, h, http.MethodGet, "/api/v1/releases?namespace=prod", "tok")
+	body.Releases = nil
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	if len(body.Releases) != 1 || body.Releases[0].Name != "web" {
+		t.Fatalf("expected namespace filter, got %+v", body.Releases)
+	}
+}
+
+func TestServerServesNewestPlanPerRelease(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	write("old.json", `{"release":"web","namespace":"prod","generatedAt":"2025-01-01T00:00:00Z","summary":{"creates":1},"changes":[]}`)
+	write("new.html", `<html><script id="ktlPlanData" type="application/json">{"release":"web","namespace":"prod","generatedAt":"2025-02-01T00:00:00Z","summary":{"updates":2},"changes":null}</script></html>`)
+	write("other.json", `{"not":"a plan"}`)
+	h := newTestServer(t, dir)
+
+	rec := do(t, h, http.MethodGet, "/api/v1/plans", "tok")
+	var list struct {
+		Plans []PlanSummary `json:"plans"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(list.Plans) != 1 || list.Plans[0].Summary["updates"] != 2 || !strings.HasSuffix(list.Plans[0].File, "new.html") {
+		t.Fatalf("expected newest plan per release, got %+v", list.Plans)
+	}
+
+	rec = do(t, h, http.MethodGet, "/api/v1/plans/prod/web", "tok")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"updates":2`) {
+		t.Fatalf("expected plan document, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(t, h, http.MethodGet, "/api/v1/plans/prod/missing", "tok"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing plan, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,482 @@
+// File: internal/rbac/plan.go
+// Brief: Minimal Role/ClusterRole calculation for rendered Helm releases.
+
+// Package rbac computes the least-privilege RBAC rules a CI identity needs to let ktl install,
+// upgrade, watch, and uninstall a rendered release.
+package rbac
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// ManageVerbs are required on every object ktl applies: Helm reads, creates, patches/updates, and
+// deletes release objects, and ktl's plan/drift checks read them back.
+var ManageVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+// ObserveVerbs are required on the objects ktl watches while a release rolls out.
+var ObserveVerbs = []string{"get", "list", "watch"}
+
+// Options configure Plan.
+type Options struct {
+	// Name prefixes the generated Role/ClusterRole (defaults to "ktl-<release>").
+	Name    string
+	Release string
+	// Namespace is the release namespace; namespaced objects without metadata.namespace land here.
+	Namespace string
+	// HelmDriver selects where release state is stored: secret (default), configmap, or memory.
+	HelmDriver string
+	// CreateNamespace adds namespace create permissions for --create-namespace.
+	CreateNamespace bool
+	// ServiceAccount, when set (as "name" or "namespace/name"), adds RoleBindings/ClusterRoleBindings.
+	ServiceAccount string
+	// Mapper resolves kinds to resources and scope (e.g. for CRDs). When nil a built-in table of
+	// core kinds is used and unknown kinds are guessed (lowercase plural, namespaced).
+	Mapper meta.RESTMapper
+}
+
+// Result is the computed set of RBAC objects.
+type Result struct {
+	Roles               []rbacv1.Role
+	ClusterRole         *rbacv1.ClusterRole
+	RoleBindings        []rbacv1.RoleBinding
+	ClusterRoleBinding  *rbacv1.ClusterRoleBinding
+	UnresolvedResources []string
+}
+
+type ruleKey struct {
+	group string
+	verbs string
+}
+
+type ruleSet map[ruleKey]map[string]struct{}
+
+func (s ruleSet) add(group, resource string, verbs []string) {
+	key := ruleKey{group: group, verbs: strings.Join(verbs, ",")}
+	if s[key] == nil {
+		s[key] = map[string]struct{}{}
+	}
+	s[key][resource] = struct{}{}
+}
+
+// rules unions the verbs granted per resource, then groups resources that share an API group and
+// verb set into one rule.
+func (s ruleSet) rules() []rbacv1.PolicyRule {
+	granted := map[string]map[string]bool{}
+	for key, resources := range s {
+		for res := range resources {
+			id := key.group + "/" + res
+			if granted[id] == nil {
+				granted[id] = map[string]bool{}
+			}
+			for _, v := range strings.Split(key.verbs, ",") {
+				granted[id][v] = true
+			}
+		}
+	}
+	merged := map[ruleKey][]string{}
+	for id, verbs := range granted {
+		group, res, _ := strings.Cut(id, "/")
+		list := make([]string, 0, len(verbs))
+		for v := range verbs {
+			list = append(list, v)
+		}
+		key := ruleKey{group: group, verbs: strings.Join(orderVerbs(list), ",")}
+		merged[key] = append(merged[key], res)
+	}
+	out := make([]rbacv1.PolicyRule, 0, len(merged))
+	for key, resources := range merged {
+		sort.Strings(resources)
+		out = append(out, rbacv1.PolicyRule{
+			APIGroups: []string{key.group},
+			Resources: resources,
+			Verbs:     strings.Split(key.verbs, ","),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].APIGroups[0] != out[j].APIGroups[0] {
+			return out[i].APIGroups[0] < out[j].APIGroups[0]
+		}
+		return out[i].Resources[0] < out[j].Resources[0]
+	})
+	return out
+}
+
+var verbOrder = map[string]int{"get": 0, "list": 1, "watch": 2, "create": 3, "update": 4, "patch": 5, "delete": 6}
+
+func orderVerbs(verbs []string) []string {
+	sort.Slice(verbs, func(i, j int) bool {
+		oi, iok := verbOrder[verbs[i]]
+		oj, jok := verbOrder[verbs[j]]
+		if iok && jok {
+			return oi < oj
+		}
+		if iok != jok {
+			return iok
+		}
+		return verbs[i] < verbs[j]
+	})
+	return verbs
+}
+
+type builtinKind struct {
+	resource   string
+	namespaced bool
+}
+
+// builtinKinds maps common group/kind pairs to their resource and scope for offline planning.
+var builtinKinds = map[schema.GroupKind]builtinKind{
+	{Kind: "ConfigMap"}:                                               {"configmaps", true},
+	{Kind: "Secret"}:                                                  {"secrets", true},
+	{Kind: "Service"}:                                                 {"services", true},
+	{Kind: "ServiceAccount"}:                                          {"serviceaccounts", true},
+	{Kind: "PersistentVolumeClaim"}:                                   {"persistentvolumeclaims", true},
+	{Kind: "Pod"}:                                                     {"pods", true},
+	{Kind: "Endpoints"}:                                               {"endpoints", true},
+	{Kind: "LimitRange"}:                                              {"limitranges", true},
+	{Kind: "ResourceQuota"}:                                           {"resourcequotas", true},
+	{Kind: "Namespace"}:                                               {"namespaces", false},
+	{Kind: "PersistentVolume"}:                                        {"persistentvolumes", false},
+	{Kind: "Node"}:                                                    {"nodes", false},
+	{Group: "apps", Kind: "Deployment"}:                               {"deployments", true},
+	{Group: "apps", Kind: "StatefulSet"}:                              {"statefulsets", true},
+	{Group: "apps", Kind: "DaemonSet"}:                                {"daemonsets", true},
+	{Group: "apps", Kind: "ReplicaSet"}:                               {"replicasets", true},
+	{Group: "batch", Kind: "Job"}:                                     {"jobs", true},
+	{Group: "batch", Kind: "CronJob"}:                                 {"cronjobs", true},
+	{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"}:           {"horizontalpodautoscalers", true},
+	{Group: "policy", Kind: "PodDisruptionBudget"}:                    {"poddisruptionbudgets", true},
+	{Group: "networking.k8s.io", Kind: "Ingress"}:                     {"ingresses", true},
+	{Group: "networking.k8s.io", Kind: "NetworkPolicy"}:               {"networkpolicies", true},
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:                {"ingressclasses", false},
+	{Group: "rbac.authorization.k8s.io", Kind: "Role"}:                {"roles", true},
+	{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}:         {"rolebindings", true},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:         {"clusterroles", false},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:  {"clusterrolebindings", false},
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: {"customresourcedefinitions", false},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}: {"validatingwebhookconfigurations", false},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:   {"mutatingwebhookconfigurations", false},
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                 {"storageclasses", false},
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                             {"priorityclasses", false},
+	{Group: "monitoring.coreos.com", Kind: "ServiceMonitor"}:                        {"servicemonitors", true},
+	{Group: "monitoring.coreos.com", Kind: "PrometheusRule"}:                        {"prometheusrules", true},
+}
+
+// Plan computes the RBAC objects required to manage objs with ktl.
+func Plan(objs []*unstructured.Unstructured, opts Options) (*Result, error) {
+	release := strings.TrimSpace(opts.Release)
+	if release == "" {
+		return nil, fmt.Errorf("release name is required")
+	}
+	namespace := strings.TrimSpace(opts.Namespace)
+	if namespace == "" {
+		namespace = "default"
+	}
+	name := strings.TrimSpace(opts.Name)
+	if name == "" {
+		name = "ktl-" + release
+	}
+
+	namespaced := map[string]ruleSet{namespace: {}}
+	cluster := ruleSet{}
+	var unresolved []string
+
+	chartKinds := crdKinds(objs)
+	for _, obj := range objs {
+		if obj == nil || obj.GetKind() == "" {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		resource, isNamespaced, ok := resolveKind(gvk, opts.Mapper, chartKinds)
+		if !ok {
+			unresolved = append(unresolved, gvk.GroupKind().String())
+		}
+		if !isNamespaced {
+			cluster.add(gvk.Group, resource, ManageVerbs)
+			continue
+		}
+		ns := strings.TrimSpace(obj.GetNamespace())
+		if ns == "" {
+			ns = namespace
+		}
+		if namespaced[ns] == nil {
+			namespaced[ns] = ruleSet{}
+		}
+		namespaced[ns].add(gvk.Group, resource, ManageVerbs)
+	}
+	chartNamespaced, chartCluster, err := chartRBACRules(objs, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for ns := range chartNamespaced {
+		if namespaced[ns] == nil {
+			namespaced[ns] = ruleSet{}
+		}
+	}
+
+	// Helm release storage lives in the release namespace.
+	switch strings.ToLower(strings.TrimSpace(opts.HelmDriver)) {
+	case "", "secret", "secrets":
+		namespaced[namespace].add("", "secrets", ManageVerbs)
+	case "configmap", "configmaps":
+		namespaced[namespace].add("", "configmaps", ManageVerbs)
+	case "memory":
+	default:
+		return nil, fmt.Errorf("unsupported helm driver %q (expected secret, configmap, or memory)", opts.HelmDriver)
+	}
+	// Rollout tracking, --wait, and failure diagnostics read pods, replica sets, and events.
+	namespaced[namespace].add("", "pods", ObserveVerbs)
+	namespaced[namespace].add("", "events", ObserveVerbs)
+	namespaced[namespace].add("apps", "replicasets", ObserveVerbs)
+	if opts.CreateNamespace {
+		cluster.add("", "namespaces", []string{"get", "create"})
+	} else {
+		cluster.add("", "namespaces", []string{"get"})
+	}
+
+	res := &Result{UnresolvedResources: dedupe(unresolved)}
+	nsList := make([]string, 0, len(namespaced))
+	for ns := range namespaced {
+		nsList = append(nsList, ns)
+	}
+	sort.Strings(nsList)
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "ktl",
+		"ktl.dev/release":              release,
+	}
+	for _, ns := range nsList {
+		res.Roles = append(res.Roles, rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: labels},
+			Rules:      append(namespaced[ns].rules(), chartNamespaced[ns]...),
+		})
+	}
+	if len(cluster) > 0 {
+		res.ClusterRole = &rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Rules:      append(cluster.rules(), chartCluster...),
+		}
+	}
+
+	if sa := strings.TrimSpace(opts.ServiceAccount); sa != "" {
+		saNamespace, saName := namespace, sa
+		if before, after, ok := strings.Cut(sa, "/"); ok {
+			saNamespace, saName = before, after
+		}
+		if saNamespace == "" || saName == "" {
+			return nil, fmt.Errorf("invalid service account %q (expected name or namespace/name)", sa)
+		}
+		subject := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: saName, Namespace: saNamespace}
+		for _, role := range res.Roles {
+			res.RoleBindings = append(res.RoleBindings, rbacv1.RoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: role.Namespace, Labels: labels},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name},
+				Subjects:   []rbacv1.Subject{subject},
+			})
+		}
+		if res.ClusterRole != nil {
+			res.ClusterRoleBinding = &rbacv1.ClusterRoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: name},
+				Subjects:   []rbacv1.Subject{subject},
+			}
+		}
+	}
+	return res, nil
+}
+
+// chartRBACRules returns the extra rules needed to create the chart's own RBAC objects. The API
+// server only lets an identity create a Role or binding that grants what it already holds, so the
+// chart's Role/ClusterRole rules are copied verbatim, roles the chart binds without defining get
+// bind (by name), and aggregated ClusterRoles, whose rules are filled in later, get escalate.
+func chartRBACRules(objs []*unstructured.Unstructured, namespace string) (map[string][]rbacv1.PolicyRule, []rbacv1.PolicyRule, error) {
+	namespaced := map[string][]rbacv1.PolicyRule{}
+	var cluster []rbacv1.PolicyRule
+	roles := map[string]bool{}
+	clusterRoles := map[string]bool{}
+	objNamespace := func(obj *unstructured.Unstructured) string {
+		if ns := strings.TrimSpace(obj.GetNamespace()); ns != "" {
+			return ns
+		}
+		return namespace
+	}
+	for _, obj := range rbacObjects(objs, "Role", "ClusterRole") {
+		switch obj.GetKind() {
+		case "Role":
+			var role rbacv1.Role
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &role); err != nil {
+				return nil, nil, fmt.Errorf("decode Role %s: %w", obj.GetName(), err)
+			}
+			ns := objNamespace(obj)
+			roles[ns+"/"+role.Name] = true
+			namespaced[ns] = appendRules(namespaced[ns], role.Rules...)
+		case "ClusterRole":
+			var role rbacv1.ClusterRole
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &role); err != nil {
+				return nil, nil, fmt.Errorf("decode ClusterRole %s: %w", obj.GetName(), err)
+			}
+			if role.AggregationRule != nil {
+				cluster = appendRules(cluster, namedRule("clusterroles", "escalate", role.Name))
+				continue
+			}
+			clusterRoles[role.Name] = true
+			cluster = appendRules(cluster, role.Rules...)
+		}
+	}
+	for _, obj := range rbacObjects(objs, "RoleBinding", "ClusterRoleBinding") {
+		kind, _, _ := unstructured.NestedString(obj.Object, "roleRef", "kind")
+		name, _, _ := unstructured.NestedString(obj.Object, "roleRef", "name")
+		if name == "" {
+			continue
+		}
+		if obj.GetKind() == "ClusterRoleBinding" {
+			if kind == "ClusterRole" && !clusterRoles[name] {
+				cluster = appendRules(cluster, namedRule("clusterroles", "bind", name))
+			}
+			continue
+		}
+		ns := objNamespace(obj)
+		switch {
+		case kind == "Role" && !roles[ns+"/"+name]:
+			namespaced[ns] = appendRules(namespaced[ns], namedRule("roles", "bind", name))
+		case kind == "ClusterRole" && !clusterRoles[name]:
+			namespaced[ns] = appendRules(namespaced[ns], namedRule("clusterroles", "bind", name))
+		}
+	}
+	return namespaced, cluster, nil
+}
+
+func rbacObjects(objs []*unstructured.Unstructured, kinds ...string) []*unstructured.Unstructured {
+	var out []*unstructured.Unstructured
+	for _, obj := range objs {
+		if obj == nil || obj.GroupVersionKind().Group != rbacv1.GroupName {
+			continue
+		}
+		for _, kind := range kinds {
+			if obj.GetKind() == kind {
+				out = append(out, obj)
+				break
+			}
+		}
+	}
+	return out
+}
+
+func namedRule(resource, verb, name string) rbacv1.PolicyRule {
+	return rbacv1.PolicyRule{APIGroups: []string{rbacv1.GroupName}, Resources: []string{resource}, Verbs: []string{verb}, ResourceNames: []string{name}}
+}
+
+// appendRules appends rules not already present in list.
+func appendRules(list []rbacv1.PolicyRule, rules ...rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	for _, rule := range rules {
+		dup := false
+		for _, have := range list {
+			if have.String() == rule.String() {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			list = append(list, rule)
+		}
+	}
+	return list
+}
+
+// crdKinds indexes the custom resource kinds defined by CRDs in the same manifest, so charts that
+// ship their own CRDs resolve offline.
+func crdKinds(objs []*unstructured.Unstructured) map[schema.GroupKind]builtinKind {
+	out := map[schema.GroupKind]builtinKind{}
+	for _, obj := range objs {
+		if obj == nil || obj.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+		plural, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "plural")
+		scope, _, _ := unstructured.NestedString(obj.Object, "spec", "scope")
+		if kind == "" || plural == "" {
+			continue
+		}
+		out[schema.GroupKind{Group: group, Kind: kind}] = builtinKind{resource: plural, namespaced: scope != "Cluster"}
+	}
+	return out
+}
+
+// resolveKind returns the plural resource and scope for gvk. ok is false when the result was
+// guessed because neither the mapper, the manifest's CRDs, nor the built-in table knows the kind.
+func resolveKind(gvk schema.GroupVersionKind, mapper meta.RESTMapper, chartKinds map[schema.GroupKind]builtinKind) (string, bool, bool) {
+	if mapper != nil {
+		if mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+			return mapping.Resource.Resource, mapping.Scope.Name() == meta.RESTScopeNameNamespace, true
+		}
+	}
+	if known, ok := chartKinds[gvk.GroupKind()]; ok {
+		return known.resource, known.namespaced, true
+	}
+	if known, ok := builtinKinds[gvk.GroupKind()]; ok {
+		return known.resource, known.namespaced, true
+	}
+	plural, _ := meta.UnsafeGuessKindToResource(gvk)
+	return plural.Resource, true, false
+}
+
+// YAML renders the result as a multi-document manifest suitable for kubectl apply.
+func (r *Result) YAML() ([]byte, error) {
+	if r == nil {
+		return nil, nil
+	}
+	var docs []interface{}
+	if r.ClusterRole != nil {
+		docs = append(docs, r.ClusterRole)
+	}
+	if r.ClusterRoleBinding != nil {
+		docs = append(docs, r.ClusterRoleBinding)
+	}
+	for i := range r.Roles {
+		docs = append(docs, &r.Roles[i])
+	}
+	for i := range r.RoleBindings {
+		docs = append(docs, &r.RoleBindings[i])
+	}
+	var out []byte
+	for _, doc := range docs {
+		raw, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		raw = []byte(strings.Replace(string(raw), "  creationTimestamp: null\n", "", 1))
+		out = append(out, "---\n"...)
+		out = append(out, raw...)
+	}
+	return out, nil
+}
+
+func dedupe(items []string) []string {
+	if len(items) == 0 {
+		return nil
+	}
+	seen := map[string]struct{}{}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
+		out = append(out, item)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,197 @@
+package rbac
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+const manifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+  namespace: platform
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: web-reader
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: w
+`
+
+func TestPlanSplitsScopesAndAddsHelmStorage(t *testing.T) {
+	objs, err := kube.DecodeManifest([]byte(manifest))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	res, err := Plan(objs, Options{Release: "web", Namespace: "prod", ServiceAccount: "ci/deployer", CreateNamespace: true})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if len(res.Roles) != 2 || res.Roles[0].Namespace != "platform" || res.Roles[1].Namespace != "prod" {
+		t.Fatalf("expected roles for platform and prod, got %+v", res.Roles)
+	}
+	prod := res.Roles[1]
+	if !hasRule(prod.Rules, "", "secrets", "create") || !hasRule(prod.Rules, "", "services", "patch") || !hasRule(prod.Rules, "apps", "deployments", "delete") {
+		t.Fatalf("prod role missing manage rules: %+v", prod.Rules)
+	}
+	if !hasRule(prod.Rules, "", "pods", "watch") || hasRule(prod.Rules, "", "pods", "delete") {
+		t.Fatalf("pods should be observe-only: %+v", prod.Rules)
+	}
+	if !hasRule(prod.Rules, "example.com", "widgets", "create") {
+		t.Fatalf("expected guessed widgets rule: %+v", prod.Rules)
+	}
+	if len(res.UnresolvedResources) != 1 || res.UnresolvedResources[0] != "Widget.example.com" {
+		t.Fatalf("unexpected unresolved list %v", res.UnresolvedResources)
+	}
+	if res.ClusterRole == nil || !hasRule(res.ClusterRole.Rules, "rbac.authorization.k8s.io", "clusterroles", "create") || !hasRule(res.ClusterRole.Rules, "", "namespaces", "create") {
+		t.Fatalf("unexpected cluster role %+v", res.ClusterRole)
+	}
+	if hasRule(res.ClusterRole.Rules, "", "namespaces", "delete") {
+		t.Fatalf("namespaces should not be deletable: %+v", res.ClusterRole.Rules)
+	}
+	if len(res.RoleBindings) != 2 || res.ClusterRoleBinding == nil || res.ClusterRoleBinding.Subjects[0].Namespace != "ci" {
+		t.Fatalf("unexpected bindings %+v %+v", res.RoleBindings, res.ClusterRoleBinding)
+	}
+	raw, err := res.YAML()
+	if err != nil {
+		t.Fatalf("yaml: %v", err)
+	}
+	out := string(raw)
+	if strings.Count(out, "---\n") != 6 || strings.Contains(out, "creationTimestamp") {
+		t.Fatalf("unexpected YAML:\n%s", out)
+	}
+}
+
+func TestPlanResolvesKindsFromBundledCRDs(t *testing.T) {
+	objs, err := kube.DecodeManifest([]byte(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: gadgets.example.com
+spec:
+  group: example.com
+  scope: Cluster
+  names:
+    kind: Gadget
+    plural: gadgets
+---
+apiVersion: example.com/v1
+kind: Gadget
+metadata:
+  name: g
+`))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	res, err := Plan(objs, Options{Release: "ops", HelmDriver: "configmap"})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if len(res.UnresolvedResources) != 0 {
+		t.Fatalf("expected Gadget to resolve from its CRD, got %v", res.UnresolvedResources)
+	}
+	if res.ClusterRole == nil || !hasRule(res.ClusterRole.Rules, "example.com", "gadgets", "patch") {
+		t.Fatalf("expected cluster-scoped gadgets rule: %+v", res.ClusterRole)
+	}
+	if !hasRule(res.Roles[0].Rules, "", "configmaps", "create") || hasRule(res.Roles[0].Rules, "", "secrets", "get") {
+		t.Fatalf("expected configmap release storage only: %+v", res.Roles[0].Rules)
+	}
+}
+
+func TestPlanCoversTheChartsOwnRBAC(t *testing.T) {
+	objs, err := kube.DecodeManifest([]byte(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: operator
+rules:
+  - apiGroups: [""]
+    resources: [leases]
+    verbs: [get, update]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: operator
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: Role, name: operator}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: operator-view
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: ClusterRole, name: view}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: operator-aggregate
+aggregationRule:
+  clusterRoleSelectors:
+    - matchLabels: {example.com/aggregate: "true"}
+`))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	res, err := Plan(objs, Options{Release: "op", Namespace: "ops"})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	role := res.Roles[0].Rules
+	if !hasRule(role, "", "leases", "update") {
+		t.Fatalf("expected the chart Role's rules to be copied: %+v", role)
+	}
+	if !hasRule(role, "rbac.authorization.k8s.io", "clusterroles", "bind") || hasRule(role, "rbac.authorization.k8s.io", "roles", "bind") {
+		t.Fatalf("expected bind only on the ClusterRole the chart does not define: %+v", role)
+	}
+	if res.ClusterRole == nil || !hasRule(res.ClusterRole.Rules, "rbac.authorization.k8s.io", "clusterroles", "escalate") {
+		t.Fatalf("expected escalate on the aggregated ClusterRole: %+v", res.ClusterRole)
+	}
+}
+
+func TestPlanRejectsUnknownDriver(t *testing.T) {
+	if _, err := Plan(nil, Options{Release: "web", HelmDriver: "sql"}); err == nil {
+		t.Fatalf("expected unsupported driver error")
+	}
+}
+
+func hasRule(rules []rbacv1.PolicyRule, group, resource, verb string) bool {
+	for _, r := range rules {
+		if len(r.APIGroups) != 1 || r.APIGroups[0] != group {
+			continue
+		}
+		if !contains(r.Resources, resource) {
+			continue
+		}
+		if contains(r.Verbs, verb) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -34,9 +34,30 @@ func ConfigFromApp(cfg appconfig.SecretsConfig) Config {
 			AWSHeaderValue:      provider.AWSHeaderValue,
 		}
 	}
+	var registries map[string]RegistryCredential
+	if len(cfg.Registries) > 0 {
+		registries = make(map[string]RegistryCredential, len(cfg.Registries))
+		for host, cred := range cfg.Registries {
+			registries[host] = RegistryCredential{
+				Username:    cred.Username,
+				Password:    cred.Password,
+				UsernameEnv: cred.UsernameEnv,
+				PasswordEnv: cred.PasswordEnv,
+			}
+		}
+	}
 	return Config{
 		DefaultProvider: cfg.DefaultProvider,
 		Providers:       providers,
+		Registries:      registries,
+		Seal: SealConfig{
+			Format:              cfg.Seal.Format,
+			Cert:                cfg.Seal.Cert,
+			ControllerNamespace: cfg.Seal.ControllerNamespace,
+			ControllerName:      cfg.Seal.ControllerName,
+			Age:                 cfg.Seal.Age,
+			KMS:                 cfg.Seal.KMS,
+		},
 	}
 }
 
@@ -4,6 +4,30 @@ package secretstore
 type Config struct {
 	DefaultProvider string                    `yaml:"defaultProvider,omitempty" json:"defaultProvider,omitempty"`
 	Providers       map[string]ProviderConfig `yaml:"providers,omitempty" json:"providers,omitempty"`
+	// Registries maps an OCI registry or Helm repository host (host or host:port) to the
+	// credentials used to pull charts from it.
+	Registries map[string]RegistryCredential `yaml:"registries,omitempty" json:"registries,omitempty"`
+	// Seal holds defaults for ktl secrets seal.
+	Seal SealConfig `yaml:"seal,omitempty" json:"seal,omitempty"`
+}
+
+// SealConfig selects the format and keys used to seal secret manifests.
+type SealConfig struct {
+	Format              string   `yaml:"format,omitempty" json:"format,omitempty"`
+	Cert                string   `yaml:"cert,omitempty" json:"cert,omitempty"`
+	ControllerNamespace string   `yaml:"controllerNamespace,omitempty" json:"controllerNamespace,omitempty"`
+	ControllerName      string   `yaml:"controllerName,omitempty" json:"controllerName,omitempty"`
+	Age                 []string `yaml:"age,omitempty" json:"age,omitempty"`
+	KMS                 []string `yaml:"kms,omitempty" json:"kms,omitempty"`
+}
+
+// RegistryCredential authenticates chart pulls. Username and Password are literals or secret://
+// references; UsernameEnv and PasswordEnv name environment variables and take precedence.
+type RegistryCredential struct {
+	Username    string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password    string `yaml:"password,omitempty" json:"password,omitempty"`
+	UsernameEnv string `yaml:"usernameEnv,omitempty" json:"usernameEnv,omitempty"`
+	PasswordEnv string `yaml:"passwordEnv,omitempty" json:"passwordEnv,omitempty"`
 }
 
 // ProviderConfig captures provider-specific settings.
@@ -30,7 +54,7 @@ type ProviderConfig struct {
 
 // Empty reports whether the configuration declares any providers or defaults.
 func (c Config) Empty() bool {
-	return c.DefaultProvider == "" && len(c.Providers) == 0
+	return c.DefaultProvider == "" && len(c.Providers) == 0 && len(c.Registries) == 0
 }
 
 // MergeConfig merges two configs, preferring non-empty values from b.
@@ -47,5 +71,31 @@ func MergeConfig(a, b Config) Config {
 			out.Providers[name] = cfg
 		}
 	}
+	if len(b.Registries) > 0 {
+		if out.Registries == nil {
+			out.Registries = map[string]RegistryCredential{}
+		}
+		for host, cred := range b.Registries {
+			out.Registries[host] = cred
+		}
+	}
+	if b.Seal.Format != "" {
+		out.Seal.Format = b.Seal.Format
+	}
+	if b.Seal.Cert != "" {
+		out.Seal.Cert = b.Seal.Cert
+	}
+	if b.Seal.ControllerNamespace != "" {
+		out.Seal.ControllerNamespace = b.Seal.ControllerNamespace
+	}
+	if b.Seal.ControllerName != "" {
+		out.Seal.ControllerName = b.Seal.ControllerName
+	}
+	if len(b.Seal.Age) > 0 {
+		out.Seal.Age = b.Seal.Age
+	}
+	if len(b.Seal.KMS) > 0 {
+		out.Seal.KMS = b.Seal.KMS
+	}
 	return out
 }
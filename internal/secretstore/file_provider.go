@@ -125,6 +125,52 @@ func (p *fileProvider) List(ctx context.Context, secretPath string) ([]string, e
 	}
 }
 
+// Write sets secretPath to value, creating intermediate maps, and rewrites the secrets file.
+func (p *fileProvider) Write(ctx context.Context, secretPath string, value string) error {
+	_ = ctx
+	secretPath = strings.TrimSpace(secretPath)
+	parts := []string{}
+	for _, part := range strings.Split(strings.TrimPrefix(secretPath, "/"), "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("secret path is required")
+	}
+	current := p.data
+	for _, part := range parts[:len(parts)-1] {
+		switch typed := current[part].(type) {
+		case map[string]interface{}:
+			current = typed
+		case nil:
+			next := map[string]interface{}{}
+			current[part] = next
+			current = next
+		default:
+			return fmt.Errorf("secret path %q crosses a non-map value in %s", secretPath, p.path)
+		}
+	}
+	current[parts[len(parts)-1]] = value
+	raw, err := yaml.Marshal(p.data)
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(p.path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, mode); err != nil {
+		return fmt.Errorf("write secrets file %q: %w", p.path, err)
+	}
+	if err := os.Rename(tmp, p.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write secrets file %q: %w", p.path, err)
+	}
+	return nil
+}
+
 func sortedKeys(data map[string]interface{}) []string {
 	keys := make([]string, 0, len(data))
 	for key := range data {
@@ -0,0 +1,77 @@
+package secretstore
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// RegistryCredential returns the pull credentials configured for a registry or repository host
+// (host or host:port). A host:port entry wins over a host entry. ok is false when nothing is
+// configured. Secret references resolve to their values regardless of the resolver's mode and are
+// not recorded in the values audit.
+func (r *Resolver) RegistryCredential(ctx context.Context, hostport string) (username, password string, ok bool, err error) {
+	if r == nil || len(r.registries) == 0 {
+		return "", "", false, nil
+	}
+	key := normalizeRegistryHost(hostport)
+	cred, found := r.registries[key]
+	if !found {
+		if host, _, splitErr := net.SplitHostPort(key); splitErr == nil {
+			cred, found = r.registries[host]
+		}
+	}
+	if !found {
+		return "", "", false, nil
+	}
+	if username, err = r.registryField(ctx, key, "username", cred.Username, cred.UsernameEnv); err != nil {
+		return "", "", false, err
+	}
+	if password, err = r.registryField(ctx, key, "password", cred.Password, cred.PasswordEnv); err != nil {
+		return "", "", false, err
+	}
+	return username, password, true, nil
+}
+
+func (r *Resolver) registryField(ctx context.Context, host, field, value, env string) (string, error) {
+	if env = strings.TrimSpace(env); env != "" {
+		v, set := os.LookupEnv(env)
+		if !set {
+			return "", fmt.Errorf("registry %s: %s env %s is not set", host, field, env)
+		}
+		return v, nil
+	}
+	ref, isRef, err := ParseRef(value, r.defaultProvider)
+	if !isRef {
+		return value, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("registry %s: %s: %w", host, field, err)
+	}
+	provider := r.providers[ref.Provider]
+	if provider == nil {
+		return "", fmt.Errorf("registry %s: %s: secret provider %q is not configured", host, field, ref.Provider)
+	}
+	v, err := provider.Resolve(ctx, ref.Path)
+	if err != nil {
+		return "", fmt.Errorf("registry %s: %s: %w", host, field, err)
+	}
+	return v, nil
+}
+
+// normalizeRegistryHost accepts a bare host, host:port, or a URL (oci://, https://).
+func normalizeRegistryHost(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if strings.Contains(raw, "://") {
+		if u, err := url.Parse(raw); err == nil {
+			raw = u.Host
+		}
+	}
+	if i := strings.Index(raw, "/"); i >= 0 {
+		raw = raw[:i]
+	}
+	return raw
+}
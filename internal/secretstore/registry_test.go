@@ -0,0 +1,52 @@
+package secretstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolverRegistryCredential(t *testing.T) {
+	secretsPath := filepath.Join(t.TempDir(), "secrets.yaml")
+	if err := os.WriteFile(secretsPath, []byte("ghcr:\n  token: gh-t0k3n\n"), 0o600); err != nil {
+		t.Fatalf("write secrets file: %v", err)
+	}
+	t.Setenv("KTL_TEST_REGISTRY_PASSWORD", "env-pass")
+
+	resolver, err := NewResolver(Config{
+		Providers: map[string]ProviderConfig{
+			"local": {Type: "file", Path: secretsPath},
+		},
+		Registries: map[string]RegistryCredential{
+			"GHCR.io":                   {Username: "ci-bot", Password: "secret://local/ghcr/token"},
+			"oci://registry.local:5000": {Username: "admin", PasswordEnv: "KTL_TEST_REGISTRY_PASSWORD"},
+			"charts.example.com":        {Username: "u", PasswordEnv: "KTL_TEST_REGISTRY_UNSET"},
+		},
+	}, ResolverOptions{Mode: ResolveModeMask})
+	if err != nil {
+		t.Fatalf("new resolver: %v", err)
+	}
+	ctx := context.Background()
+
+	user, pass, ok, err := resolver.RegistryCredential(ctx, "ghcr.io:443")
+	if err != nil || !ok || user != "ci-bot" || pass != "gh-t0k3n" {
+		t.Fatalf("ghcr: user=%q pass=%q ok=%v err=%v", user, pass, ok, err)
+	}
+	if !resolver.Audit().Empty() {
+		t.Fatalf("registry credentials must not be recorded in the values audit")
+	}
+	if _, pass, ok, _ := resolver.RegistryCredential(ctx, "registry.local:5000"); !ok || pass != "env-pass" {
+		t.Fatalf("registry.local: pass=%q ok=%v", pass, ok)
+	}
+	if _, _, ok, _ := resolver.RegistryCredential(ctx, "registry.local"); ok {
+		t.Fatalf("a host:port entry must not match other ports")
+	}
+	if _, _, _, err := resolver.RegistryCredential(ctx, "charts.example.com"); err == nil || !strings.Contains(err.Error(), "KTL_TEST_REGISTRY_UNSET is not set") {
+		t.Fatalf("expected unset env error, got %v", err)
+	}
+	if _, _, ok, err := resolver.RegistryCredential(ctx, "docker.io"); ok || err != nil {
+		t.Fatalf("expected no credentials for docker.io, ok=%v err=%v", ok, err)
+	}
+}
@@ -25,6 +25,11 @@ type Lister interface {
 	List(ctx context.Context, path string) ([]string, error)
 }
 
+// Writer exposes optional updates of a secret path.
+type Writer interface {
+	Write(ctx context.Context, path string, value string) error
+}
+
 // ResolverOptions customize resolver behavior.
 type ResolverOptions struct {
 	DefaultProvider string
@@ -56,6 +61,7 @@ type Resolver struct {
 	defaultProvider string
 	mode            ResolveMode
 	mask            string
+	registries      map[string]RegistryCredential
 	cache           map[string]string
 	seen            map[string]struct{}
 	audit           []AuditEntry
@@ -97,11 +103,20 @@ func NewResolver(cfg Config, opts ResolverOptions) (*Resolver, error) {
 	if defaultProvider == "" {
 		defaultProvider = strings.TrimSpace(cfg.DefaultProvider)
 	}
+	registries := make(map[string]RegistryCredential, len(cfg.Registries))
+	for host, cred := range cfg.Registries {
+		key := normalizeRegistryHost(host)
+		if key == "" {
+			return nil, fmt.Errorf("registry host cannot be empty")
+		}
+		registries[key] = cred
+	}
 	return &Resolver{
 		providers:       providers,
 		defaultProvider: defaultProvider,
 		mode:            mode,
 		mask:            strings.TrimSpace(opts.Mask),
+		registries:      registries,
 		cache:           map[string]string{},
 		seen:            map[string]struct{}{},
 	}, nil
@@ -236,6 +251,35 @@ func (r *Resolver) ResolveString(ctx context.Context, value string) (string, boo
 	return val, true, nil
 }
 
+// WriteString stores value at a secret reference through the provider's Writer and returns the
+// parsed reference. Later resolutions of the same reference observe the new value.
+func (r *Resolver) WriteString(ctx context.Context, value string, secret string) (Ref, error) {
+	if r == nil {
+		return Ref{}, fmt.Errorf("secret resolver is not configured")
+	}
+	ref, ok, err := ParseRef(value, r.defaultProvider)
+	if !ok {
+		return Ref{}, fmt.Errorf("reference %q is not a secret:// reference", value)
+	}
+	if err != nil {
+		return Ref{}, err
+	}
+	provider := r.providers[ref.Provider]
+	if provider == nil {
+		return Ref{}, fmt.Errorf("secret provider %q is not configured", ref.Provider)
+	}
+	writer, ok := provider.(Writer)
+	if !ok {
+		return Ref{}, fmt.Errorf("secret provider %q does not support writing", ref.Provider)
+	}
+	if err := writer.Write(ctx, ref.Path, secret); err != nil {
+		return Ref{}, err
+	}
+	r.cache[ref.Provider+"|"+ref.Path] = secret
+	r.record(ref)
+	return ref, nil
+}
+
 func (r *Resolver) resolveRef(ctx context.Context, ref Ref) (string, error) {
 	key := ref.Provider + "|" + ref.Path
 	if cached, ok := r.cache[key]; ok {
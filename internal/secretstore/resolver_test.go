@@ -144,3 +144,49 @@ func TestResolverMaskMode(t *testing.T) {
 		t.Fatalf("expected masked audit entry")
 	}
 }
+
+func TestResolverWriteString(t *testing.T) {
+	tempDir := t.TempDir()
+	secretsPath := filepath.Join(tempDir, "secrets.yaml")
+	if err := os.WriteFile(secretsPath, []byte("db:\n  password: old\n"), 0o600); err != nil {
+		t.Fatalf("write secrets file: %v", err)
+	}
+	resolver, err := NewResolver(Config{
+		Providers: map[string]ProviderConfig{
+			"local": {Type: "file", Path: secretsPath},
+		},
+	}, ResolverOptions{Mode: ResolveModeValue})
+	if err != nil {
+		t.Fatalf("new resolver: %v", err)
+	}
+	ctx := context.Background()
+	if _, _, err := resolver.ResolveString(ctx, "secret://local/db/password"); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if _, err := resolver.WriteString(ctx, "secret://local/db/password", "new"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := resolver.WriteString(ctx, "secret://local/api/token", "t0k3n"); err != nil {
+		t.Fatalf("write new path: %v", err)
+	}
+	if got, _, _ := resolver.ResolveString(ctx, "secret://local/db/password"); got != "new" {
+		t.Fatalf("cached value=%q, want new", got)
+	}
+
+	reloaded, err := NewResolver(Config{
+		Providers: map[string]ProviderConfig{
+			"local": {Type: "file", Path: secretsPath},
+		},
+	}, ResolverOptions{Mode: ResolveModeValue})
+	if err != nil {
+		t.Fatalf("reload resolver: %v", err)
+	}
+	for ref, want := range map[string]string{"secret://local/db/password": "new", "secret://local/api/token": "t0k3n"} {
+		if got, _, err := reloaded.ResolveString(ctx, ref); err != nil || got != want {
+			t.Fatalf("%s=%q (%v), want %q", ref, got, err, want)
+		}
+	}
+	if _, err := resolver.WriteString(ctx, "secret://local/db/password/nested", "x"); err == nil {
+		t.Fatalf("expected writing below a string value to fail")
+	}
+}
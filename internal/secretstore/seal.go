@@ -0,0 +1,262 @@
+package secretstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Seal formats supported by SealSecret.
+const (
+	SealFormatSealedSecret = "sealedsecret"
+	SealFormatSOPS         = "sops"
+)
+
+// SealScope mirrors the sealed-secrets scopes, which bind ciphertext to a name and namespace.
+type SealScope string
+
+const (
+	SealScopeStrict        SealScope = "strict"
+	SealScopeNamespaceWide SealScope = "namespace-wide"
+	SealScopeClusterWide   SealScope = "cluster-wide"
+)
+
+// Default location of the sealed-secrets controller.
+const (
+	DefaultSealControllerNamespace = "kube-system"
+	DefaultSealControllerName      = "sealed-secrets-controller"
+)
+
+// SealRequest describes the Secret to seal.
+type SealRequest struct {
+	Namespace string
+	Name      string
+	Type      string
+	Scope     SealScope
+	Data      map[string]string
+}
+
+// ParseSealScope validates a scope name; empty means strict.
+func ParseSealScope(raw string) (SealScope, error) {
+	switch scope := SealScope(strings.ToLower(strings.TrimSpace(raw))); scope {
+	case "":
+		return SealScopeStrict, nil
+	case SealScopeStrict, SealScopeNamespaceWide, SealScopeClusterWide:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("unsupported seal scope %q (want strict, namespace-wide, or cluster-wide)", raw)
+	}
+}
+
+// ParseSealingCert extracts the RSA public key from a PEM certificate as served by the
+// sealed-secrets controller (/v1/cert.pem).
+func ParseSealingCert(raw []byte) (*rsa.PublicKey, error) {
+	for {
+		var block *pem.Block
+		block, raw = pem.Decode(raw)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM certificate found")
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse sealing certificate: %w", err)
+		}
+		key, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("sealing certificate does not hold an RSA key")
+		}
+		return key, nil
+	}
+}
+
+// SealSecret renders a bitnami.com/v1alpha1 SealedSecret whose values only the controller holding
+// the private half of key can decrypt.
+func SealSecret(key *rsa.PublicKey, req SealRequest) ([]byte, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	label := sealLabel(req)
+	encrypted := make(map[string]string, len(req.Data))
+	for _, k := range sortedDataKeys(req.Data) {
+		ciphertext, err := hybridEncrypt(rand.Reader, key, []byte(req.Data[k]), label)
+		if err != nil {
+			return nil, fmt.Errorf("seal %s: %w", k, err)
+		}
+		encrypted[k] = base64.StdEncoding.EncodeToString(ciphertext)
+	}
+	meta := map[string]interface{}{"name": req.Name, "namespace": req.Namespace}
+	switch req.Scope {
+	case SealScopeNamespaceWide:
+		meta["annotations"] = map[string]string{"sealedsecrets.bitnami.com/namespace-wide": "true"}
+	case SealScopeClusterWide:
+		meta["annotations"] = map[string]string{"sealedsecrets.bitnami.com/cluster-wide": "true"}
+	}
+	obj := map[string]interface{}{
+		"apiVersion": "bitnami.com/v1alpha1",
+		"kind":       "SealedSecret",
+		"metadata":   meta,
+		"spec": map[string]interface{}{
+			"encryptedData": encrypted,
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"name": req.Name, "namespace": req.Namespace},
+				"type":     req.secretType(),
+			},
+		},
+	}
+	return yaml.Marshal(obj)
+}
+
+// sealLabel is the OAEP label the controller expects for the request's scope.
+func sealLabel(req SealRequest) []byte {
+	switch req.Scope {
+	case SealScopeNamespaceWide:
+		return []byte(req.Namespace)
+	case SealScopeClusterWide:
+		return nil
+	default:
+		return []byte(req.Namespace + "/" + req.Name)
+	}
+}
+
+// hybridEncrypt matches the sealed-secrets wire format: a two byte length, the RSA-OAEP wrapped
+// AES-256 session key, then the AES-GCM ciphertext under a zero nonce (each key is used once).
+func hybridEncrypt(rnd io.Reader, key *rsa.PublicKey, plaintext, label []byte) ([]byte, error) {
+	sessionKey := make([]byte, 32)
+	if _, err := io.ReadFull(rnd, sessionKey); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := rsa.EncryptOAEP(sha256.New(), rnd, key, sessionKey, label)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 2, 2+len(wrapped)+len(plaintext)+aead.Overhead())
+	binary.BigEndian.PutUint16(out, uint16(len(wrapped)))
+	out = append(out, wrapped...)
+	return aead.Seal(out, make([]byte, aead.NonceSize()), plaintext, nil), nil
+}
+
+// SecretManifest renders the plain v1 Secret that SOPS encrypts.
+func SecretManifest(req SealRequest) ([]byte, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	data := make(map[string]string, len(req.Data))
+	for k, v := range req.Data {
+		data[k] = v
+	}
+	return yaml.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": req.Name, "namespace": req.Namespace},
+		"type":       req.secretType(),
+		"stringData": data,
+	})
+}
+
+// SOPSOptions select the recipients for SOPSEncrypt.
+type SOPSOptions struct {
+	Binary string
+	Age    []string
+	KMS    []string
+}
+
+// SOPSEncrypt encrypts the Secret's stringData with the sops binary, leaving metadata readable
+// so the file still diffs and reviews like a manifest.
+func SOPSEncrypt(ctx context.Context, req SealRequest, opts SOPSOptions) ([]byte, error) {
+	if len(opts.Age) == 0 && len(opts.KMS) == 0 {
+		return nil, fmt.Errorf("sops sealing needs at least one age recipient or KMS key")
+	}
+	manifest, err := SecretManifest(req)
+	if err != nil {
+		return nil, err
+	}
+	sopsBin := strings.TrimSpace(opts.Binary)
+	if sopsBin == "" {
+		sopsBin = "sops"
+	}
+	dir, err := os.MkdirTemp("", "ktl-seal-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	plain := filepath.Join(dir, req.Name+".yaml")
+	if err := os.WriteFile(plain, manifest, 0o600); err != nil {
+		return nil, err
+	}
+	args := []string{"--encrypt", "--encrypted-regex", "^(data|stringData)$"}
+	if len(opts.Age) > 0 {
+		args = append(args, "--age", strings.Join(opts.Age, ","))
+	}
+	if len(opts.KMS) > 0 {
+		args = append(args, "--kms", strings.Join(opts.KMS, ","))
+	}
+	args = append(args, plain)
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, sopsBin, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("sops: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("sops: %w", err)
+	}
+	return stdout.Bytes(), nil
+}
+
+func (req SealRequest) validate() error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("secret name is required")
+	}
+	if strings.TrimSpace(req.Namespace) == "" {
+		return fmt.Errorf("secret namespace is required")
+	}
+	if len(req.Data) == 0 {
+		return fmt.Errorf("secret has no data")
+	}
+	return nil
+}
+
+func (req SealRequest) secretType() string {
+	if t := strings.TrimSpace(req.Type); t != "" {
+		return t
+	}
+	return "Opaque"
+}
+
+func sortedDataKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,124 @@
+package secretstore
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func openSealed(t *testing.T, key *rsa.PrivateKey, ciphertext, label []byte) string {
+	t.Helper()
+	n := int(binary.BigEndian.Uint16(ciphertext))
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, ciphertext[2:2+n], label)
+	if err != nil {
+		t.Fatalf("unwrap session key: %v", err)
+	}
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext[2+n:], nil)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	return string(plain)
+}
+
+func TestSealSecretRoundTrip(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		scope SealScope
+		label string
+	}{
+		{SealScopeStrict, "prod/db"},
+		{SealScopeNamespaceWide, "prod"},
+		{SealScopeClusterWide, ""},
+	} {
+		raw, err := SealSecret(&key.PublicKey, SealRequest{Namespace: "prod", Name: "db", Scope: tc.scope, Data: map[string]string{"password": "s3cr3t"}})
+		if err != nil {
+			t.Fatalf("%s: seal: %v", tc.scope, err)
+		}
+		var obj struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Annotations map[string]string `json:"annotations"`
+			} `json:"metadata"`
+			Spec struct {
+				EncryptedData map[string]string `json:"encryptedData"`
+				Template      struct {
+					Type string `json:"type"`
+				} `json:"template"`
+			} `json:"spec"`
+		}
+		if err := yaml.Unmarshal(raw, &obj); err != nil {
+			t.Fatal(err)
+		}
+		if obj.Kind != "SealedSecret" || obj.Spec.Template.Type != "Opaque" {
+			t.Fatalf("%s: unexpected manifest:\n%s", tc.scope, raw)
+		}
+		if tc.scope != SealScopeStrict && obj.Metadata.Annotations["sealedsecrets.bitnami.com/"+string(tc.scope)] != "true" {
+			t.Fatalf("%s: missing scope annotation: %v", tc.scope, obj.Metadata.Annotations)
+		}
+		ciphertext, err := base64.StdEncoding.DecodeString(obj.Spec.EncryptedData["password"])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var label []byte
+		if tc.label != "" {
+			label = []byte(tc.label)
+		}
+		if got := openSealed(t, key, ciphertext, label); got != "s3cr3t" {
+			t.Fatalf("%s: decrypted %q", tc.scope, got)
+		}
+	}
+	if _, err := SealSecret(&key.PublicKey, SealRequest{Namespace: "prod", Name: "db"}); err == nil {
+		t.Fatalf("expected empty data to fail")
+	}
+}
+
+func TestSOPSEncryptInvokesBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script stub")
+	}
+	dir := t.TempDir()
+	stub := filepath.Join(dir, "sops")
+	script := "#!/bin/sh\necho \"args: $*\"\nfor last; do :; done\ncat \"$last\"\n"
+	if err := os.WriteFile(stub, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	out, err := SOPSEncrypt(context.Background(), SealRequest{Namespace: "prod", Name: "db", Data: map[string]string{"password": "s3cr3t"}}, SOPSOptions{
+		Binary: stub,
+		Age:    []string{"age1a", "age1b"},
+	})
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	got := string(out)
+	for _, want := range []string{"--encrypted-regex ^(data|stringData)$", "--age age1a,age1b", "kind: Secret", "password: s3cr3t"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output missing %q:\n%s", want, got)
+		}
+	}
+	if _, err := SOPSEncrypt(context.Background(), SealRequest{Namespace: "prod", Name: "db", Data: map[string]string{"a": "b"}}, SOPSOptions{Binary: stub}); err == nil {
+		t.Fatalf("expected missing recipients to fail")
+	}
+}
@@ -2,11 +2,14 @@ package secretstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 
 	vault "github.com/hashicorp/vault/api"
+	"github.com/kubekattle/ktl/internal/netconfig"
 )
 
 type vaultProvider struct {
@@ -29,8 +32,20 @@ func newVaultProvider(cfg ProviderConfig) (*vaultProvider, error) {
 		return nil, err
 	}
 
+	if err := netconfig.CheckURL(address); err != nil {
+		return nil, err
+	}
+
 	apiCfg := vault.DefaultConfig()
 	apiCfg.Address = address
+	// Keep Vault's own transport (VAULT_CACERT and friends still apply) but route it through
+	// --proxy/--airgap, and trust --tls-ca-file unless a Vault CA was configured.
+	if t, ok := apiCfg.HttpClient.Transport.(*http.Transport); ok {
+		t.Proxy = netconfig.ProxyFunc()
+		if roots := netconfig.RootCAs(); roots != nil && t.TLSClientConfig != nil && t.TLSClientConfig.RootCAs == nil {
+			t.TLSClientConfig.RootCAs = roots
+		}
+	}
 	client, err := vault.NewClient(apiCfg)
 	if err != nil {
 		return nil, err
@@ -123,6 +138,71 @@ func (p *vaultProvider) List(ctx context.Context, secretPath string) ([]string,
 	return coerceStringList(rawKeys)
 }
 
+// Write stores value under the key selected by secretPath (or the provider's default key),
+// keeping the other keys of the secret intact. Only a missing secret is treated as empty;
+// any other read failure aborts the write. KV v2 writes use check-and-set against the
+// version just read so a concurrent writer is not silently overwritten.
+func (p *vaultProvider) Write(ctx context.Context, secretPath string, value string) error {
+	if p == nil {
+		return fmt.Errorf("vault provider is not initialized")
+	}
+	path, key := splitVaultPath(secretPath)
+	if path == "" {
+		return fmt.Errorf("vault secret path is required")
+	}
+	if err := p.ensureAuth(ctx); err != nil {
+		return err
+	}
+	if key == "" {
+		key = p.key
+	}
+	if key == "" {
+		key = "value"
+	}
+	switch p.kvVersion {
+	case 1:
+		fullPath := fmt.Sprintf("%s/%s", p.mount, path)
+		existing, err := p.client.Logical().ReadWithContext(ctx, fullPath)
+		if err != nil {
+			return fmt.Errorf("read vault secret %s before write: %w", path, err)
+		}
+		data := map[string]interface{}{}
+		if existing != nil {
+			for k, v := range existing.Data {
+				data[k] = v
+			}
+		}
+		data[key] = value
+		_, err = p.client.Logical().WriteWithContext(ctx, fullPath, data)
+		return err
+	case 2:
+		kv := p.client.KVv2(p.mount)
+		data := map[string]interface{}{}
+		// cas=0 only lets the write through if the secret still doesn't exist.
+		cas := 0
+		existing, err := kv.Get(ctx, path)
+		switch {
+		case errors.Is(err, vault.ErrSecretNotFound):
+		case err != nil:
+			return fmt.Errorf("read vault secret %s before write: %w", path, err)
+		default:
+			for k, v := range existing.Data {
+				data[k] = v
+			}
+			if existing.VersionMetadata != nil {
+				cas = existing.VersionMetadata.Version
+			}
+		}
+		data[key] = value
+		if _, err := kv.Put(ctx, path, data, vault.WithCheckAndSet(cas)); err != nil {
+			return fmt.Errorf("write vault secret %s (check-and-set version %d): %w", path, cas, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("vault kvVersion must be 1 or 2")
+	}
+}
+
 func (p *vaultProvider) read(ctx context.Context, path string) (map[string]interface{}, error) {
 	path = strings.Trim(strings.TrimSpace(path), "/")
 	if path == "" {
@@ -135,3 +135,109 @@ func TestVaultAuthConfigAWSRequiresRole(t *testing.T) {
 		t.Fatalf("expected error for missing awsRole")
 	}
 }
+
+func TestVaultProviderWriteKV2KeepsOtherKeys(t *testing.T) {
+	var written map[string]interface{}
+	var options map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/app/db" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"old","user":"app"},"metadata":{"version":7}}}`))
+		case http.MethodPut, http.MethodPost:
+			var body struct {
+				Data    map[string]interface{} `json:"data"`
+				Options map[string]interface{} `json:"options"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			written = body.Data
+			options = body.Options
+			_, _ = w.Write([]byte(`{"data":{"version":8}}`))
+		}
+	}))
+	defer server.Close()
+
+	provider, err := newVaultProvider(ProviderConfig{
+		Type:      "vault",
+		Address:   server.URL,
+		Token:     "token",
+		KVVersion: 2,
+	})
+	if err != nil {
+		t.Fatalf("newVaultProvider: %v", err)
+	}
+	if err := provider.Write(context.Background(), "app/db#password", "new"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if written["password"] != "new" || written["user"] != "app" {
+		t.Fatalf("written=%v", written)
+	}
+	if options["cas"] != float64(7) {
+		t.Fatalf("expected check-and-set against version 7, got options=%v", options)
+	}
+}
+
+func TestVaultProviderWriteKV2AbortsOnReadError(t *testing.T) {
+	wrote := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			wrote = true
+		}
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+	}))
+	defer server.Close()
+
+	provider, err := newVaultProvider(ProviderConfig{
+		Type:      "vault",
+		Address:   server.URL,
+		Token:     "token",
+		KVVersion: 2,
+	})
+	if err != nil {
+		t.Fatalf("newVaultProvider: %v", err)
+	}
+	if err := provider.Write(context.Background(), "app/db#password", "new"); err == nil {
+		t.Fatalf("expected the read failure to abort the write")
+	}
+	if wrote {
+		t.Fatalf("write was attempted after a failed read; other keys would have been lost")
+	}
+}
+
+func TestVaultProviderWriteKV2CreatesMissingSecret(t *testing.T) {
+	var options map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			var body struct {
+				Options map[string]interface{} `json:"options"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			options = body.Options
+			_, _ = w.Write([]byte(`{"data":{"version":1}}`))
+		}
+	}))
+	defer server.Close()
+
+	provider, err := newVaultProvider(ProviderConfig{
+		Type:      "vault",
+		Address:   server.URL,
+		Token:     "token",
+		KVVersion: 2,
+	})
+	if err != nil {
+		t.Fatalf("newVaultProvider: %v", err)
+	}
+	if err := provider.Write(context.Background(), "app/db", "new"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if options["cas"] != float64(0) {
+		t.Fatalf("expected cas=0 for a new secret, got options=%v", options)
+	}
+}
@@ -0,0 +1,448 @@
+// File: internal/selfupdate/selfupdate.go
+// Brief: Release channel lookup, artifact verification, and binary replacement for ktl self-update.
+
+// Package selfupdate finds newer ktl releases on a release channel (the GitHub releases API or an
+// internal mirror serving the same JSON), verifies the downloaded archive against the release
+// checksums and the checksums' sigstore bundle (with cosign), and swaps the running binary
+// atomically.
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// DefaultChannelURL lists the public GitHub releases of ktl.
+const DefaultChannelURL = "https://api.github.com/repos/kubekattle/ktl/releases"
+
+const (
+	maxArchiveBytes  = 256 << 20
+	maxMetadataBytes = 16 << 20
+
+	sigstoreIssuer = "https://token.actions.githubusercontent.com"
+)
+
+// SignatureMode controls sigstore verification of the release checksums.
+type SignatureMode string
+
+const (
+	// SignatureRequire fails when the signature cannot be verified, including when cosign is
+	// missing. It is the default.
+	SignatureRequire SignatureMode = "require"
+	// SignatureSkip only verifies checksums.
+	SignatureSkip SignatureMode = "skip"
+)
+
+// Asset is a downloadable file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Release is one entry of the channel, in the GitHub releases API shape.
+type Release struct {
+	Tag        string  `json:"tag_name"`
+	Name       string  `json:"name,omitempty"`
+	Notes      string  `json:"body,omitempty"`
+	Draft      bool    `json:"draft,omitempty"`
+	Prerelease bool    `json:"prerelease,omitempty"`
+	Published  string  `json:"published_at,omitempty"`
+	Assets     []Asset `json:"assets,omitempty"`
+}
+
+func (r Release) asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Client talks to a release channel.
+type Client struct {
+	ChannelURL string
+	HTTP       *http.Client
+	// Token is sent as a bearer token (GITHUB_TOKEN or an internal mirror credential), only to
+	// the host of ChannelURL.
+	Token string
+}
+
+// NewClient returns a client for channelURL (DefaultChannelURL when empty).
+func NewClient(channelURL, token string) *Client {
+	channelURL = strings.TrimSpace(channelURL)
+	if channelURL == "" {
+		channelURL = DefaultChannelURL
+	}
+	return &Client{ChannelURL: channelURL, HTTP: &http.Client{Timeout: 60 * time.Second}, Token: strings.TrimSpace(token)}
+}
+
+// Releases returns the published releases on the channel.
+func (c *Client) Releases(ctx context.Context) ([]Release, error) {
+	raw, err := c.get(ctx, c.ChannelURL, maxMetadataBytes)
+	if err != nil {
+		return nil, fmt.Errorf("fetch release channel %s: %w", c.ChannelURL, err)
+	}
+	var releases []Release
+	if err := json.Unmarshal(raw, &releases); err != nil {
+		return nil, fmt.Errorf("decode release channel %s: %w", c.ChannelURL, err)
+	}
+	return releases, nil
+}
+
+func (c *Client) get(ctx context.Context, rawURL string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "ktl-self-update")
+	if strings.Contains(rawURL, "api.github.com") {
+		req.Header.Set("Accept", "application/vnd.github+json")
+	}
+	if c.Token != "" && c.channelHost(req.URL) {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("GET %s: %s", rawURL, resp.Status)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(raw)) > limit {
+		return nil, fmt.Errorf("GET %s: response exceeds %d bytes", rawURL, limit)
+	}
+	return raw, nil
+}
+
+// channelHost reports whether u is on the channel's host. Asset downloads usually live elsewhere
+// (GitHub's CDN, a mirror's object store) and must not receive the channel credential.
+func (c *Client) channelHost(u *url.URL) bool {
+	channel, err := url.Parse(c.ChannelURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, channel.Scheme) && strings.EqualFold(u.Host, channel.Host)
+}
+
+// Plan is the outcome of comparing the running version with the channel.
+type Plan struct {
+	Current string
+	Target  Release
+	// Newer lists every release after Current up to and including Target, newest first.
+	Newer []Release
+	// Available is false when Current is already at (or past) Target.
+	Available bool
+}
+
+// ChooseUpdate picks the newest stable release (or the release tagged pin) and the changelog
+// delta from current. A non-semver current version (a dev build) is treated as older than
+// every release.
+func ChooseUpdate(current string, releases []Release, pin string, prerelease bool) (*Plan, error) {
+	type candidate struct {
+		rel Release
+		ver *semver.Version
+	}
+	var all []candidate
+	for _, rel := range releases {
+		if rel.Draft {
+			continue
+		}
+		v, err := semver.NewVersion(rel.Tag)
+		if err != nil {
+			continue
+		}
+		all = append(all, candidate{rel: rel, ver: v})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ver.GreaterThan(all[j].ver) })
+
+	var target *candidate
+	pin = strings.TrimSpace(pin)
+	for i := range all {
+		c := &all[i]
+		if pin != "" {
+			if pv, err := semver.NewVersion(pin); err == nil && c.ver.Equal(pv) {
+				target = c
+				break
+			}
+			continue
+		}
+		if c.rel.Prerelease && !prerelease {
+			continue
+		}
+		target = c
+		break
+	}
+	if target == nil {
+		if pin != "" {
+			return nil, fmt.Errorf("release %s not found on the channel", pin)
+		}
+		return nil, errors.New("no releases found on the channel")
+	}
+
+	plan := &Plan{Current: current, Target: target.rel}
+	cur, err := semver.NewVersion(strings.TrimSpace(current))
+	if err != nil {
+		plan.Available = true
+		plan.Newer = []Release{target.rel}
+		return plan, nil
+	}
+	plan.Available = !cur.Equal(target.ver) && (pin != "" || target.ver.GreaterThan(cur))
+	if !plan.Available {
+		return plan, nil
+	}
+	for _, c := range all {
+		if c.ver.GreaterThan(target.ver) || !c.ver.GreaterThan(cur) {
+			continue
+		}
+		if c.rel.Prerelease && !prerelease && c.rel.Tag != target.rel.Tag {
+			continue
+		}
+		plan.Newer = append(plan.Newer, c.rel)
+	}
+	return plan, nil
+}
+
+// ArchiveName returns the release archive for tool on goos/goarch, as built by the release workflow.
+func ArchiveName(tool, goos, goarch, tag string) string {
+	return fmt.Sprintf("%s-%s-%s-%s.tar.gz", tool, goos, goarch, tag)
+}
+
+// ChecksumsName returns the per-platform checksum file published next to the archives.
+func ChecksumsName(goos, goarch, tag string) string {
+	return fmt.Sprintf("checksums-%s-%s-%s.txt", goos, goarch, tag)
+}
+
+// Artifact is a downloaded and verified release binary.
+type Artifact struct {
+	Archive  string
+	SHA256   string
+	Signed   bool
+	Binary   []byte
+	Warnings []string
+}
+
+// DownloadOptions configures Download.
+type DownloadOptions struct {
+	Tool      string
+	GOOS      string
+	GOARCH    string
+	Signature SignatureMode
+	// IdentityRegexp overrides the expected signing identity (defaults to the release workflow of
+	// the tagged ref).
+	IdentityRegexp string
+	TempDir        string
+}
+
+// Download fetches the platform archive for rel, checks it against the release checksums and
+// (per opts.Signature) their sigstore bundle, and extracts the binary.
+func (c *Client) Download(ctx context.Context, rel Release, opts DownloadOptions) (*Artifact, error) {
+	archiveName := ArchiveName(opts.Tool, opts.GOOS, opts.GOARCH, rel.Tag)
+	archiveAsset, ok := rel.asset(archiveName)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s asset", rel.Tag, archiveName)
+	}
+	checksumsName := ChecksumsName(opts.GOOS, opts.GOARCH, rel.Tag)
+	checksumsAsset, ok := rel.asset(checksumsName)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s asset; refusing to install an unverified binary", rel.Tag, checksumsName)
+	}
+	checksums, err := c.get(ctx, checksumsAsset.URL, maxMetadataBytes)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", checksumsName, err)
+	}
+	art := &Artifact{Archive: archiveName}
+	if err := c.verifySignature(ctx, rel, checksumsName, checksums, opts, art); err != nil {
+		return nil, err
+	}
+	archive, err := c.get(ctx, archiveAsset.URL, maxArchiveBytes)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", archiveName, err)
+	}
+	sum, err := VerifyChecksum(archive, checksums, archiveName)
+	if err != nil {
+		return nil, err
+	}
+	art.SHA256 = sum
+	binaryName := opts.Tool
+	if opts.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	bin, err := ExtractBinary(archive, binaryName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", archiveName, err)
+	}
+	art.Binary = bin
+	return art, nil
+}
+
+func (c *Client) verifySignature(ctx context.Context, rel Release, checksumsName string, checksums []byte, opts DownloadOptions, art *Artifact) error {
+	if opts.Signature == SignatureSkip {
+		art.Warnings = append(art.Warnings, "signature verification skipped (--verify-signature=skip)")
+		return nil
+	}
+	bundleAsset, ok := rel.asset(checksumsName + ".sigstore.json")
+	if !ok {
+		return fmt.Errorf("release %s has no sigstore bundle for %s; refusing to install an unsigned binary (--verify-signature=skip to install anyway)", rel.Tag, checksumsName)
+	}
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return fmt.Errorf("cosign not found in PATH, cannot verify the release signature; install cosign or pass --verify-signature=skip to rely on checksums only")
+	}
+	bundle, err := c.get(ctx, bundleAsset.URL, maxMetadataBytes)
+	if err != nil {
+		return fmt.Errorf("download %s: %w", bundleAsset.Name, err)
+	}
+	dir, err := os.MkdirTemp(opts.TempDir, "ktl-self-update-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	blobPath := filepath.Join(dir, checksumsName)
+	bundlePath := blobPath + ".sigstore.json"
+	if err := os.WriteFile(blobPath, checksums, 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(bundlePath, bundle, 0o600); err != nil {
+		return err
+	}
+	identity := strings.TrimSpace(opts.IdentityRegexp)
+	if identity == "" {
+		identity = fmt.Sprintf(`^https://github.com/kubekattle/ktl/.github/workflows/release\.yml@refs/tags/%s$`, regexp.QuoteMeta(rel.Tag))
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "cosign", "verify-blob",
+		"--bundle", bundlePath,
+		"--certificate-identity-regexp", identity,
+		"--certificate-oidc-issuer", sigstoreIssuer,
+		blobPath)
+	cmd.Stdout = io.Discard
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("signature verification of %s failed: %w: %s", checksumsName, err, strings.TrimSpace(stderr.String()))
+	}
+	art.Signed = true
+	return nil
+}
+
+// VerifyChecksum checks data against the sha256sum-formatted entry for name and returns the digest.
+func VerifyChecksum(data, checksums []byte, name string) (string, error) {
+	sum := sha256.Sum256(data)
+	got := hex.EncodeToString(sum[:])
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		if !strings.EqualFold(fields[0], got) {
+			return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, fields[0], got)
+		}
+		return got, nil
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+// ExtractBinary returns the regular file named name from a gzipped tarball.
+func ExtractBinary(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("archive does not contain %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || filepath.Base(hdr.Name) != name {
+			continue
+		}
+		bin, err := io.ReadAll(io.LimitReader(tr, maxArchiveBytes))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+		return bin, nil
+	}
+}
+
+// ReplaceExecutable atomically replaces the file at path with bin. The new file is written next
+// to the target and renamed over it, so a crash leaves either the old or the new binary. On
+// Windows, where a running executable cannot be overwritten, the old binary is moved aside to
+// <path>.old first.
+func ReplaceExecutable(path string, bin []byte, goos string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".new-")
+	if err != nil {
+		return fmt.Errorf("stage new binary in %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	cleanup := func() { _ = os.Remove(tmpPath) }
+	if _, err := tmp.Write(bin); err != nil {
+		tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()|0o111); err != nil {
+		cleanup()
+		return err
+	}
+	if goos == "windows" {
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			cleanup()
+			return fmt.Errorf("move current binary aside: %w", err)
+		}
+		if err := os.Rename(tmpPath, path); err != nil {
+			_ = os.Rename(old, path)
+			cleanup()
+			return err
+		}
+		return nil
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		cleanup()
+		return err
+	}
+	return nil
+}
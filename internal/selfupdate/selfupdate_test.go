@@ -0,0 +1,210 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChooseUpdate(t *testing.T) {
+	releases := []Release{
+		{Tag: "v1.0.0", Notes: "one"},
+		{Tag: "v1.2.0", Notes: "two"},
+		{Tag: "v1.3.0-rc.1", Prerelease: true},
+		{Tag: "v1.1.0", Notes: "between"},
+		{Tag: "v2.0.0", Draft: true},
+		{Tag: "nightly"},
+	}
+
+	plan, err := ChooseUpdate("v1.0.0", releases, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Available || plan.Target.Tag != "v1.2.0" {
+		t.Fatalf("expected v1.2.0 update, got %+v", plan)
+	}
+	if got := tags(plan.Newer); got != "v1.2.0,v1.1.0" {
+		t.Fatalf("unexpected changelog delta %s", got)
+	}
+
+	plan, err = ChooseUpdate("v1.2.0", releases, "", false)
+	if err != nil || plan.Available {
+		t.Fatalf("expected up to date, got %+v err=%v", plan, err)
+	}
+
+	plan, err = ChooseUpdate("v1.2.0", releases, "", true)
+	if err != nil || !plan.Available || plan.Target.Tag != "v1.3.0-rc.1" {
+		t.Fatalf("expected prerelease update, got %+v err=%v", plan, err)
+	}
+
+	plan, err = ChooseUpdate("v1.2.0", releases, "v1.0.0", false)
+	if err != nil || !plan.Available || plan.Target.Tag != "v1.0.0" || len(plan.Newer) != 0 {
+		t.Fatalf("expected pinned downgrade, got %+v err=%v", plan, err)
+	}
+
+	plan, err = ChooseUpdate("dev", releases, "", false)
+	if err != nil || !plan.Available || plan.Target.Tag != "v1.2.0" {
+		t.Fatalf("expected dev build to be outdated, got %+v err=%v", plan, err)
+	}
+
+	if _, err := ChooseUpdate("v1.0.0", releases, "v9.9.9", false); err == nil {
+		t.Fatalf("expected missing pin error")
+	}
+}
+
+func tags(rels []Release) string {
+	var out []string
+	for _, r := range rels {
+		out = append(out, r.Tag)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive")
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	checksums := []byte("deadbeef  other.tar.gz\n" + digest + "  ktl-linux-amd64-v1.0.0.tar.gz\n")
+	got, err := VerifyChecksum(data, checksums, "ktl-linux-amd64-v1.0.0.tar.gz")
+	if err != nil || got != digest {
+		t.Fatalf("expected match, got %q err=%v", got, err)
+	}
+	if _, err := VerifyChecksum([]byte("tampered"), checksums, "ktl-linux-amd64-v1.0.0.tar.gz"); err == nil || !strings.Contains(err.Error(), "mismatch") {
+		t.Fatalf("expected mismatch, got %v", err)
+	}
+	if _, err := VerifyChecksum(data, checksums, "missing.tar.gz"); err == nil {
+		t.Fatalf("expected missing entry error")
+	}
+}
+
+func buildArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDownloadVerifiesAndExtracts(t *testing.T) {
+	t.Setenv("PATH", t.TempDir()) // no cosign
+	archive := buildArchive(t, map[string]string{"./README.txt": "hi", "./ktl": "new-binary"})
+	sum := sha256.Sum256(archive)
+	name := ArchiveName("ktl", "linux", "amd64", "v1.1.0")
+	checksums := hex.EncodeToString(sum[:]) + "  " + name + "\n"
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/releases", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]Release{{
+			Tag: "v1.1.0",
+			Assets: []Asset{
+				{Name: name, URL: srv.URL + "/archive"},
+				{Name: ChecksumsName("linux", "amd64", "v1.1.0"), URL: srv.URL + "/checksums"},
+			},
+		}})
+	})
+	mux.HandleFunc("/archive", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write(archive) })
+	mux.HandleFunc("/checksums", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(checksums)) })
+
+	client := NewClient(srv.URL+"/releases", "")
+	releases, err := client.Releases(context.Background())
+	if err != nil || len(releases) != 1 {
+		t.Fatalf("releases: %v %v", releases, err)
+	}
+	// Signatures are required by default: an unsigned release is refused, not installed with a warning.
+	opts := DownloadOptions{Tool: "ktl", GOOS: "linux", GOARCH: "amd64"}
+	if _, err := client.Download(context.Background(), releases[0], opts); err == nil || !strings.Contains(err.Error(), "--verify-signature=skip") {
+		t.Fatalf("expected unsigned release to be refused by default, got %v", err)
+	}
+
+	opts.Signature = SignatureSkip
+	art, err := client.Download(context.Background(), releases[0], opts)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if string(art.Binary) != "new-binary" || art.Signed || len(art.Warnings) == 0 {
+		t.Fatalf("unexpected artifact %+v", art)
+	}
+
+	archive = buildArchive(t, map[string]string{"./ktl": "tampered"})
+	if _, err := client.Download(context.Background(), releases[0], opts); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+}
+
+func TestTokenOnlySentToChannelHost(t *testing.T) {
+	var assetAuth string
+	assets := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assetAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("checksums"))
+	}))
+	defer assets.Close()
+	var channelAuth string
+	channel := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		channelAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer channel.Close()
+
+	client := NewClient(channel.URL+"/releases", "secret-token")
+	if _, err := client.Releases(context.Background()); err != nil {
+		t.Fatalf("releases: %v", err)
+	}
+	if channelAuth != "Bearer secret-token" {
+		t.Fatalf("expected the token on the channel request, got %q", channelAuth)
+	}
+	if _, err := client.get(context.Background(), assets.URL+"/checksums", maxMetadataBytes); err != nil {
+		t.Fatalf("get asset: %v", err)
+	}
+	if assetAuth != "" {
+		t.Fatalf("token leaked to the asset host: %q", assetAuth)
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ktl")
+	if err := os.WriteFile(path, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReplaceExecutable(path, []byte("new"), "linux"); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil || string(raw) != "new" {
+		t.Fatalf("expected replaced binary, got %q err=%v", raw, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm()&0o111 == 0 {
+		t.Fatalf("expected executable mode, got %v err=%v", info.Mode(), err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("expected staging file to be gone, found %d entries", len(entries))
+	}
+}
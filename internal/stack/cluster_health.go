@@ -0,0 +1,251 @@
+// File: internal/stack/cluster_health.go
+// Brief: Cluster health preflight gate that runs before a stack run schedules anything.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ClusterHealthOptions are the resolved preflight thresholds.
+type ClusterHealthOptions struct {
+	Enabled              bool          `json:"enabled"`
+	MinReadyNodesPercent int           `json:"minReadyNodesPercent"`
+	MaxPendingCSRs       int           `json:"maxPendingCSRs"`
+	MaxEvictedPods       int           `json:"maxEvictedPods"`
+	OnFailure            string        `json:"onFailure"` // deny|skip
+	Timeout              time.Duration `json:"timeout"`
+}
+
+func DefaultClusterHealthOptions() ClusterHealthOptions {
+	return ClusterHealthOptions{
+		MinReadyNodesPercent: 90,
+		MaxPendingCSRs:       5,
+		MaxEvictedPods:       20,
+		OnFailure:            "deny",
+		Timeout:              30 * time.Second,
+	}
+}
+
+func (o ClusterHealthOptions) Validate() error {
+	if o.MinReadyNodesPercent < 0 || o.MinReadyNodesPercent > 100 {
+		return fmt.Errorf("minReadyNodesPercent must be in [0,100] (got %d)", o.MinReadyNodesPercent)
+	}
+	if o.MaxPendingCSRs < 0 {
+		return fmt.Errorf("maxPendingCSRs must be >= 0 (got %d)", o.MaxPendingCSRs)
+	}
+	if o.MaxEvictedPods < 0 {
+		return fmt.Errorf("maxEvictedPods must be >= 0 (got %d)", o.MaxEvictedPods)
+	}
+	switch o.OnFailure {
+	case "deny", "skip":
+	default:
+		return fmt.Errorf("onFailure must be deny|skip (got %q)", o.OnFailure)
+	}
+	return nil
+}
+
+// ClusterHealthReport is the preflight result for one cluster. It is emitted as the fields of a
+// CLUSTER_PREFLIGHT run event.
+type ClusterHealthReport struct {
+	Cluster       string   `json:"cluster"`
+	Reachable     bool     `json:"reachable"`
+	ServerVersion string   `json:"serverVersion,omitempty"`
+	NodesReady    int      `json:"nodesReady"`
+	NodesTotal    int      `json:"nodesTotal"`
+	PendingCSRs   int      `json:"pendingCSRs"`
+	EvictedPods   int      `json:"evictedPods"`
+	Problems      []string `json:"problems,omitempty"`
+	// Notes are checks that could not run (usually RBAC) and did not count against the cluster.
+	Notes []string `json:"notes,omitempty"`
+}
+
+func (r ClusterHealthReport) OK() bool { return len(r.Problems) == 0 }
+
+func (r ClusterHealthReport) summary() string {
+	if !r.Reachable {
+		return strings.Join(r.Problems, "; ")
+	}
+	s := fmt.Sprintf("nodes %d/%d ready, %d pending CSRs, %d evicted pods", r.NodesReady, r.NodesTotal, r.PendingCSRs, r.EvictedPods)
+	if len(r.Problems) > 0 {
+		s += ": " + strings.Join(r.Problems, "; ")
+	}
+	return s
+}
+
+func (r ClusterHealthReport) fields(status string) map[string]any {
+	return map[string]any{
+		"cluster":       r.Cluster,
+		"status":        status,
+		"reachable":     r.Reachable,
+		"serverVersion": r.ServerVersion,
+		"nodesReady":    r.NodesReady,
+		"nodesTotal":    r.NodesTotal,
+		"pendingCSRs":   r.PendingCSRs,
+		"evictedPods":   r.EvictedPods,
+		"problems":      r.Problems,
+		"notes":         r.Notes,
+	}
+}
+
+// checkClusterHealth runs the preflight checks against one cluster.
+func checkClusterHealth(ctx context.Context, cluster string, client kubernetes.Interface, opts ClusterHealthOptions) ClusterHealthReport {
+	r := ClusterHealthReport{Cluster: cluster}
+	v, err := client.Discovery().ServerVersion()
+	if err != nil {
+		r.Problems = append(r.Problems, fmt.Sprintf("API unreachable: %v", err))
+		return r
+	}
+	r.Reachable = true
+	r.ServerVersion = v.GitVersion
+
+	if nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err != nil {
+		r.Notes = append(r.Notes, healthCheckNote("nodes", err))
+	} else {
+		r.NodesTotal = len(nodes.Items)
+		for _, n := range nodes.Items {
+			if nodeReady(n) {
+				r.NodesReady++
+			}
+		}
+		if r.NodesTotal == 0 {
+			r.Problems = append(r.Problems, "no nodes registered")
+		} else if pct := r.NodesReady * 100 / r.NodesTotal; pct < opts.MinReadyNodesPercent {
+			r.Problems = append(r.Problems, fmt.Sprintf("%d%% of nodes ready (< %d%%)", pct, opts.MinReadyNodesPercent))
+		}
+	}
+
+	if csrs, err := client.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{}); err != nil {
+		r.Notes = append(r.Notes, healthCheckNote("certificatesigningrequests", err))
+	} else {
+		for _, csr := range csrs.Items {
+			if csrPending(csr) {
+				r.PendingCSRs++
+			}
+		}
+		if r.PendingCSRs > opts.MaxPendingCSRs {
+			r.Problems = append(r.Problems, fmt.Sprintf("%d pending CSRs (> %d)", r.PendingCSRs, opts.MaxPendingCSRs))
+		}
+	}
+
+	if pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{FieldSelector: "status.phase=Failed"}); err != nil {
+		r.Notes = append(r.Notes, healthCheckNote("pods", err))
+	} else {
+		for _, p := range pods.Items {
+			if p.Status.Phase == corev1.PodFailed && p.Status.Reason == "Evicted" {
+				r.EvictedPods++
+			}
+		}
+		if r.EvictedPods > opts.MaxEvictedPods {
+			r.Problems = append(r.Problems, fmt.Sprintf("%d evicted pods (> %d)", r.EvictedPods, opts.MaxEvictedPods))
+		}
+	}
+	return r
+}
+
+func healthCheckNote(resource string, err error) string {
+	if apierrors.IsForbidden(err) {
+		return fmt.Sprintf("%s: not checked (forbidden)", resource)
+	}
+	return fmt.Sprintf("%s: not checked (%v)", resource, err)
+}
+
+func nodeReady(n corev1.Node) bool {
+	for _, c := range n.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func csrPending(csr certificatesv1.CertificateSigningRequest) bool {
+	for _, c := range csr.Status.Conditions {
+		switch c.Type {
+		case certificatesv1.CertificateApproved, certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			return false
+		}
+	}
+	return true
+}
+
+// runClusterPreflight checks every cluster in the run and records a CLUSTER_PREFLIGHT event for
+// each. It returns the unhealthy clusters with a one-line reason.
+func runClusterPreflight(ctx context.Context, run *runState, opts ClusterHealthOptions, newClient func(context.Context, *ResolvedRelease) (kubernetes.Interface, error), errOut func(string)) map[string]string {
+	first := map[string]*ResolvedRelease{}
+	for _, n := range run.Nodes {
+		if _, ok := first[n.Cluster.Name]; !ok {
+			first[n.Cluster.Name] = n.ResolvedRelease
+		}
+	}
+	clusters := make([]string, 0, len(first))
+	for name := range first {
+		clusters = append(clusters, name)
+	}
+	sort.Strings(clusters)
+
+	failed := map[string]string{}
+	for _, name := range clusters {
+		checkCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		var report ClusterHealthReport
+		client, err := newClient(checkCtx, first[name])
+		if err != nil {
+			report = ClusterHealthReport{Cluster: name, Problems: []string{fmt.Sprintf("API unreachable: %v", err)}}
+		} else {
+			report = checkClusterHealth(checkCtx, name, client, opts)
+		}
+		cancel()
+
+		status := "passed"
+		if !report.OK() {
+			status = "denied"
+			if opts.OnFailure == "skip" {
+				status = "skipped"
+			}
+			failed[name] = report.summary()
+			if errOut != nil {
+				errOut(fmt.Sprintf("cluster preflight: %s %s: %s", name, status, report.summary()))
+			}
+		}
+		run.AppendEvent("", ClusterPreflight, 0, fmt.Sprintf("cluster %s: %s (%s)", name, status, report.summary()), report.fields(status), nil)
+	}
+	return failed
+}
+
+// preflightClient builds a client for a node's cluster the same way Helm operations do.
+func preflightClient(defaultKubeconfig, defaultContext *string) func(context.Context, *ResolvedRelease) (kubernetes.Interface, error) {
+	return func(ctx context.Context, node *ResolvedRelease) (kubernetes.Interface, error) {
+		kubeconfigPath := expandTilde(node.Cluster.Kubeconfig)
+		if kubeconfigPath == "" && defaultKubeconfig != nil {
+			kubeconfigPath = strings.TrimSpace(*defaultKubeconfig)
+		}
+		kubeCtx := strings.TrimSpace(node.Cluster.Context)
+		if kubeCtx == "" && defaultContext != nil {
+			kubeCtx = strings.TrimSpace(*defaultContext)
+		}
+		kc, err := kube.NewWithOptions(ctx, kubeconfigPath, kubeCtx, kube.ClientOptions{ExecEnv: node.Cluster.ExecCredentialEnv})
+		if err != nil {
+			return nil, err
+		}
+		return kc.Clientset, nil
+	}
+}
+
+func formatPreflightFailures(names []string, failed map[string]string) string {
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+" ("+failed[name]+")")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,136 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func healthNode(name string, ready bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}},
+	}
+}
+
+func TestCheckClusterHealth_Thresholds(t *testing.T) {
+	objs := []runtime.Object{
+		healthNode("n1", true),
+		healthNode("n2", true),
+		healthNode("n3", false),
+		&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "pending"}},
+		&certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "approved"},
+			Status:     certificatesv1.CertificateSigningRequestStatus{Conditions: []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateApproved}}},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "evicted", Namespace: "apps"}, Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "crashed", Namespace: "apps"}, Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Error"}},
+	}
+
+	opts := DefaultClusterHealthOptions()
+	opts.MaxPendingCSRs = 0
+	r := checkClusterHealth(context.Background(), "c1", fake.NewSimpleClientset(objs...), opts)
+	if !r.Reachable || r.NodesReady != 2 || r.NodesTotal != 3 || r.PendingCSRs != 1 || r.EvictedPods != 1 {
+		t.Fatalf("report=%+v", r)
+	}
+	if want := []string{"66% of nodes ready (< 90%)", "1 pending CSRs (> 0)"}; strings.Join(r.Problems, "|") != strings.Join(want, "|") {
+		t.Fatalf("problems=%q want %q", r.Problems, want)
+	}
+
+	opts = DefaultClusterHealthOptions()
+	opts.MinReadyNodesPercent = 60
+	if r := checkClusterHealth(context.Background(), "c1", fake.NewSimpleClientset(objs...), opts); !r.OK() {
+		t.Fatalf("expected cluster to pass at 60%%, got %+v", r)
+	}
+}
+
+func TestRun_ClusterPreflight(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "charts", "cm", "Chart.yaml"), "apiVersion: v2\nname: cm\nversion: 0.1.0\n")
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: preflight
+defaults:
+  namespace: ns
+releases:
+  - name: a
+    chart: ./charts/cm
+    cluster: { name: good }
+  - name: b
+    chart: ./charts/cm
+    cluster: { name: bad }
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	p, err := Compile(u, CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	newClient := func(_ context.Context, node *ResolvedRelease) (kubernetes.Interface, error) {
+		if node.Cluster.Name == "bad" {
+			return fake.NewSimpleClientset(healthNode("n1", false)), nil
+		}
+		return fake.NewSimpleClientset(healthNode("n1", true)), nil
+	}
+
+	for _, mode := range []string{"deny", "skip"} {
+		t.Run(mode, func(t *testing.T) {
+			var preflight []RunEvent
+			exec := &recordingExecutor{}
+			opts := DefaultClusterHealthOptions()
+			opts.Enabled = true
+			opts.OnFailure = mode
+			err := Run(context.Background(), RunOptions{
+				Command:             "apply",
+				Plan:                p,
+				Concurrency:         2,
+				Executor:            exec,
+				ClusterPreflight:    opts,
+				clusterHealthClient: newClient,
+				EventObservers: []RunEventObserver{RunEventObserverFunc(func(ev RunEvent) {
+					if ev.Type == string(ClusterPreflight) {
+						preflight = append(preflight, ev)
+					}
+				})},
+			}, ioDiscard{}, ioDiscard{})
+
+			if len(preflight) != 2 || preflight[0].Fields["cluster"] != "bad" || preflight[1].Fields["status"] != "passed" {
+				t.Fatalf("preflight events=%+v err=%v", preflight, err)
+			}
+			if mode == "deny" {
+				if err == nil || !strings.Contains(err.Error(), "cluster preflight denied the run: bad (nodes 0/1 ready") {
+					t.Fatalf("expected deny error, got %v", err)
+				}
+				if ran := exec.calledNames(); len(ran) != 0 {
+					t.Fatalf("expected nothing to run, got %v", ran)
+				}
+				return
+			}
+			if preflight[0].Fields["status"] != "skipped" {
+				t.Fatalf("expected bad cluster to be skipped, got %v", preflight[0].Fields)
+			}
+			if err == nil || !strings.Contains(err.Error(), "cluster preflight skipped bad") {
+				t.Fatalf("expected skip error, got %v", err)
+			}
+			if ran := exec.calledNames(); fmt.Sprint(ran) != "[a]" {
+				t.Fatalf("expected only the healthy cluster to run, got %v", ran)
+			}
+		})
+	}
+}
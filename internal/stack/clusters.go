@@ -0,0 +1,175 @@
+// File: internal/stack/clusters.go
+// Brief: Per-cluster kubeconfig/context/exec-credential settings and compile-time preflight.
+
+package stack
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// resolveClusters merges the root stack's clusters section with the selected profile's.
+// Relative kubeconfig paths are resolved against the stack root.
+func resolveClusters(u *Universe, profile string) map[string]ClusterTarget {
+	if u == nil {
+		return nil
+	}
+	sf, ok := u.Stacks[u.RootDir]
+	if !ok {
+		return nil
+	}
+	out := map[string]ClusterTarget{}
+	merge := func(src map[string]ClusterTarget) {
+		for name, ct := range src {
+			cur := out[name]
+			cur.Name = name
+			if ct.Kubeconfig != "" {
+				cur.Kubeconfig = ct.Kubeconfig
+				if !strings.HasPrefix(strings.TrimSpace(ct.Kubeconfig), "~") {
+					cur.Kubeconfig = resolvePath(u.RootDir, ct.Kubeconfig)
+				}
+			}
+			if ct.Context != "" {
+				cur.Context = ct.Context
+			}
+			mergeExecCredentialEnv(&cur, ct.ExecCredentialEnv)
+			out[name] = cur
+		}
+	}
+	merge(sf.Clusters)
+	if profile = strings.TrimSpace(profile); profile != "" {
+		if sp, ok := sf.Profiles[profile]; ok {
+			merge(sp.Clusters)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// applyClusterConfig applies a clusters entry to a node. The entry is the most specific
+// statement about how to reach that cluster, so it wins over defaults and release settings.
+func applyClusterConfig(n *ResolvedRelease, ct ClusterTarget) {
+	if ct.Kubeconfig != "" {
+		n.Cluster.Kubeconfig = ct.Kubeconfig
+	}
+	if ct.Context != "" {
+		n.Cluster.Context = ct.Context
+	}
+	mergeExecCredentialEnv(&n.Cluster, ct.ExecCredentialEnv)
+}
+
+// preflightClusters checks every clusters entry used by the plan: the kubeconfig must load, the
+// context must exist, and execCredentialEnv needs an exec credential plugin that is on PATH.
+// All problems are reported at once so a multi-cluster stack can be fixed in one pass.
+func preflightClusters(clusters map[string]ClusterTarget, byCluster map[string][]*ResolvedRelease) error {
+	names := make([]string, 0, len(clusters))
+	for name := range clusters {
+		if _, used := byCluster[name]; used {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var problems []string
+	for _, name := range names {
+		if msg := preflightCluster(clusters[name]); msg != "" {
+			problems = append(problems, fmt.Sprintf("clusters.%s: %s", name, msg))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("cluster preflight failed:\n  " + strings.Join(problems, "\n  "))
+}
+
+func preflightCluster(ct ClusterTarget) string {
+	var cfg *clientcmdapi.Config
+	source := "the default kubeconfig"
+	if ct.Kubeconfig != "" {
+		path := expandTilde(ct.Kubeconfig)
+		source = path
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Sprintf("kubeconfig %s not found (fix the path or run your SSO login to create it)", path)
+		}
+		loaded, err := clientcmd.LoadFromFile(path)
+		if err != nil {
+			return fmt.Sprintf("kubeconfig %s: %v", path, err)
+		}
+		cfg = loaded
+	} else {
+		loaded, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+		if err != nil {
+			return fmt.Sprintf("load default kubeconfig: %v", err)
+		}
+		cfg = loaded
+	}
+
+	contextName := ct.Context
+	if contextName == "" {
+		contextName = cfg.CurrentContext
+	}
+	kubeCtx := cfg.Contexts[contextName]
+	if kubeCtx == nil {
+		if ct.Context == "" && ct.Kubeconfig == "" && len(ct.ExecCredentialEnv) == 0 {
+			// Nothing pinned: the machine's kubeconfig is resolved at run time.
+			return ""
+		}
+		available := make([]string, 0, len(cfg.Contexts))
+		for name := range cfg.Contexts {
+			available = append(available, name)
+		}
+		sort.Strings(available)
+		if contextName == "" {
+			return fmt.Sprintf("%s has no current-context; set context (available: %s)", source, strings.Join(available, ", "))
+		}
+		return fmt.Sprintf("context %q not found in %s (available: %s)", contextName, source, strings.Join(available, ", "))
+	}
+	if len(ct.ExecCredentialEnv) == 0 {
+		return ""
+	}
+	user := cfg.AuthInfos[kubeCtx.AuthInfo]
+	if user == nil || user.Exec == nil {
+		return fmt.Sprintf("execCredentialEnv is set but user %q of context %q has no exec credential plugin", kubeCtx.AuthInfo, contextName)
+	}
+	command := user.Exec.Command
+	if !filepath.IsAbs(command) && strings.ContainsRune(command, filepath.Separator) && user.LocationOfOrigin != "" {
+		// client-go resolves relative plugin paths against the kubeconfig's directory.
+		command = filepath.Join(filepath.Dir(user.LocationOfOrigin), command)
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		hint := strings.TrimSpace(user.Exec.InstallHint)
+		if hint == "" {
+			hint = "install it or fix users." + kubeCtx.AuthInfo + ".exec.command"
+		}
+		return fmt.Sprintf("exec credential plugin %q for context %q not found: %s", filepath.Base(user.Exec.Command), contextName, hint)
+	}
+	return ""
+}
+
+// withExecCredentialEnv makes Helm's REST client getter pass env to the exec credential plugin.
+func withExecCredentialEnv(getter genericclioptions.RESTClientGetter, env map[string]string) {
+	cfgFlags, ok := getter.(*genericclioptions.ConfigFlags)
+	if !ok || cfgFlags == nil || len(env) == 0 {
+		return
+	}
+	prev := cfgFlags.WrapConfigFn
+	cfgFlags.WrapConfigFn = func(cfg *rest.Config) *rest.Config {
+		if prev != nil {
+			cfg = prev(cfg)
+		}
+		kube.ApplyExecEnv(cfg, env)
+		return cfg
+	}
+}
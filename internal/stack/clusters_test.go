@@ -0,0 +1,137 @@
+package stack
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const testKubeconfig = `
+apiVersion: v1
+kind: Config
+clusters:
+  - name: eu
+    cluster: { server: https://eu.example.com }
+contexts:
+  - name: eu-admin
+    context: { cluster: eu, user: sso }
+  - name: eu-static
+    context: { cluster: eu, user: static }
+users:
+  - name: sso
+    user:
+      exec:
+        apiVersion: client.authentication.k8s.io/v1
+        command: %s
+        installHint: brew install kubelogin
+  - name: static
+    user: { token: abc }
+`
+
+func writeClusterStack(t *testing.T, execCommand string, clusters string) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "kube", "eu.yaml"), strings.Replace(testKubeconfig, "%s", execCommand, 1))
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+clusters:
+`+clusters+`
+defaults:
+  namespace: apps
+releases:
+  - name: api
+    chart: ./chart
+    cluster: { name: eu, execCredentialEnv: { TENANT: release } }
+  - name: web
+    chart: ./chart
+    cluster: { name: us, kubeconfig: /tmp/us.yaml }
+`)
+	return root
+}
+
+func TestCompile_ClustersSectionOverrides(t *testing.T) {
+	root := writeClusterStack(t, "sh", `
+  eu:
+    kubeconfig: ./kube/eu.yaml
+    context: eu-admin
+    execCredentialEnv: { AWS_PROFILE: eu-prod, TENANT: cluster }
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	p, err := Compile(u, CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	api := p.ByID["eu/apps/api"]
+	if api.Cluster.Kubeconfig != filepath.Join(root, "kube", "eu.yaml") || api.Cluster.Context != "eu-admin" {
+		t.Fatalf("cluster=%+v", api.Cluster)
+	}
+	if env := api.Cluster.ExecCredentialEnv; env["AWS_PROFILE"] != "eu-prod" || env["TENANT"] != "cluster" {
+		t.Fatalf("env=%v", env)
+	}
+	if web := p.ByID["us/apps/web"]; web.Cluster.Kubeconfig != "/tmp/us.yaml" || web.Cluster.ExecCredentialEnv != nil {
+		t.Fatalf("expected clusters outside the section to be untouched, got %+v", web.Cluster)
+	}
+
+	cfg := &rest.Config{ExecProvider: &clientcmdapi.ExecConfig{Command: "sh", Env: []clientcmdapi.ExecEnvVar{{Name: "TENANT", Value: "kubeconfig"}, {Name: "KEEP", Value: "1"}}}}
+	shared := cfg.ExecProvider
+	kube.ApplyExecEnv(cfg, api.Cluster.ExecCredentialEnv)
+	got := map[string]string{}
+	for _, v := range cfg.ExecProvider.Env {
+		got[v.Name] = v.Value
+	}
+	if len(got) != 3 || got["TENANT"] != "cluster" || got["KEEP"] != "1" || len(shared.Env) != 2 {
+		t.Fatalf("exec env=%v shared=%v", got, shared.Env)
+	}
+}
+
+func TestCompile_ClusterPreflightErrors(t *testing.T) {
+	root := writeClusterStack(t, "kubelogin-not-installed", `
+  eu:
+    kubeconfig: ./kube/eu.yaml
+    context: eu-admin
+    execCredentialEnv: { AWS_PROFILE: eu-prod }
+  us:
+    kubeconfig: ./kube/missing.yaml
+  unused:
+    kubeconfig: ./kube/also-missing.yaml
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	_, err = Compile(u, CompileOptions{})
+	if err == nil {
+		t.Fatalf("expected preflight error")
+	}
+	msg := err.Error()
+	for _, want := range []string{
+		`clusters.eu: exec credential plugin "kubelogin-not-installed" for context "eu-admin" not found: brew install kubelogin`,
+		"clusters.us: kubeconfig " + filepath.Join(root, "kube", "missing.yaml") + " not found",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("expected %q in:\n%s", want, msg)
+		}
+	}
+	if strings.Contains(msg, "unused") {
+		t.Fatalf("unused clusters should not be checked:\n%s", msg)
+	}
+
+	for ct, want := range map[string]string{
+		"eu-missing": `context "eu-missing" not found in ` + filepath.Join(root, "kube", "eu.yaml") + " (available: eu-admin, eu-static)",
+		"eu-static":  `execCredentialEnv is set but user "static" of context "eu-static" has no exec credential plugin`,
+	} {
+		got := preflightCluster(ClusterTarget{Kubeconfig: filepath.Join(root, "kube", "eu.yaml"), Context: ct, ExecCredentialEnv: map[string]string{"A": "b"}})
+		if got != want {
+			t.Fatalf("preflight(%s)=%q want %q", ct, got, want)
+		}
+	}
+}
@@ -12,16 +12,20 @@ import (
 
 type CompileOptions struct {
 	Profile string
+	// Overlays are StackOverlay files applied in order on top of the stack (later files win).
+	Overlays []string
 }
 
 type Plan struct {
-	StackRoot string                        `json:"stackRoot"`
-	StackName string                        `json:"stackName"`
-	Profile   string                        `json:"profile"`
-	Nodes     []*ResolvedRelease            `json:"nodes"`
-	Order     []string                      `json:"order,omitempty"`
-	Runner    RunnerResolved                `json:"runner,omitempty"`
-	Hooks     StackHooksConfig              `json:"hooks,omitempty"`
+	StackRoot string             `json:"stackRoot"`
+	StackName string             `json:"stackName"`
+	Profile   string             `json:"profile"`
+	Nodes     []*ResolvedRelease `json:"nodes"`
+	Order     []string           `json:"order,omitempty"`
+	Runner    RunnerResolved     `json:"runner,omitempty"`
+	Hooks     StackHooksConfig   `json:"hooks,omitempty"`
+	// Disabled lists releases and hooks dropped by enabled expressions or overlays.
+	Disabled  []DisabledNode                `json:"disabled,omitempty"`
 	ByID      map[string]*ResolvedRelease   `json:"-"`
 	ByCluster map[string][]*ResolvedRelease `json:"-"`
 }
@@ -37,6 +41,11 @@ func Compile(u *Universe, opts CompileOptions) (*Plan, error) {
 		return nil, err
 	}
 
+	overlays, err := loadStackOverlays(opts.Overlays)
+	if err != nil {
+		return nil, err
+	}
+	clusters := mergeOverlayClusters(resolveClusters(u, profile), overlays)
 	nodes := make([]*ResolvedRelease, 0, len(u.Releases))
 	for _, dr := range u.Releases {
 		node, err := resolveRelease(u, dr, profile)
@@ -45,6 +54,27 @@ func Compile(u *Universe, opts CompileOptions) (*Plan, error) {
 		}
 		nodes = append(nodes, node)
 	}
+	if err := applyStackOverlays(nodes, overlays); err != nil {
+		return nil, err
+	}
+	if err := checkValuesSources(nodes); err != nil {
+		return nil, err
+	}
+	nodes, disabled, err := applyEnabledConditions(nodes, profile)
+	if err != nil {
+		return nil, err
+	}
+	stackHooks, hooksOff, err := filterEnabledHooks(filterHooksRunOnce(stackHooks, true), &conditionContext{profile: profile}, "")
+	if err != nil {
+		return nil, fmt.Errorf("stack hooks: %w", err)
+	}
+	disabled = append(disabled, hooksOff...)
+	sort.SliceStable(disabled, func(i, j int) bool { return disabled[i].ID < disabled[j].ID })
+	for _, node := range nodes {
+		if ct, ok := clusters[node.Cluster.Name]; ok {
+			applyClusterConfig(node, ct)
+		}
+	}
 	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
 
 	byID := make(map[string]*ResolvedRelease, len(nodes))
@@ -63,6 +93,10 @@ func Compile(u *Universe, opts CompileOptions) (*Plan, error) {
 		}
 	}
 
+	if err := preflightClusters(clusters, byCluster); err != nil {
+		return nil, err
+	}
+
 	for clusterName, list := range byCluster {
 		seenName := map[string]string{}
 		for _, n := range list {
@@ -78,7 +112,8 @@ func Compile(u *Universe, opts CompileOptions) (*Plan, error) {
 		StackName: u.StackName,
 		Profile:   profile,
 		Nodes:     nodes,
-		Hooks:     filterHooksRunOnce(stackHooks, true),
+		Hooks:     stackHooks,
+		Disabled:  disabled,
 		ByID:      byID,
 		ByCluster: byCluster,
 	}
@@ -103,21 +138,28 @@ func resolveRelease(u *Universe, dr discoveredRelease, profile string) (*Resolve
 	switch {
 	case dr.FromFile != nil:
 		leaf = ReleaseSpec{
-			Name:         dr.FromFile.Name,
-			Chart:        dr.FromFile.Chart,
-			ChartVersion: dr.FromFile.ChartVersion,
-			Wave:         dr.FromFile.Wave,
-			Critical:     dr.FromFile.Critical,
-			Parallelism:  dr.FromFile.Parallelism,
-			Cluster:      dr.FromFile.Cluster,
-			Namespace:    dr.FromFile.Namespace,
-			Values:       dr.FromFile.Values,
-			Set:          dr.FromFile.Set,
-			Tags:         dr.FromFile.Tags,
-			Needs:        dr.FromFile.Needs,
-			Apply:        dr.FromFile.Apply,
-			Delete:       dr.FromFile.Delete,
-			Hooks:        dr.FromFile.Hooks,
+			Name:           dr.FromFile.Name,
+			Type:           dr.FromFile.Type,
+			Enabled:        dr.FromFile.Enabled,
+			Chart:          dr.FromFile.Chart,
+			ChartVersion:   dr.FromFile.ChartVersion,
+			Manifests:      dr.FromFile.Manifests,
+			Task:           dr.FromFile.Task,
+			Wave:           dr.FromFile.Wave,
+			Critical:       dr.FromFile.Critical,
+			Parallelism:    dr.FromFile.Parallelism,
+			Cluster:        dr.FromFile.Cluster,
+			Namespace:      dr.FromFile.Namespace,
+			Values:         dr.FromFile.Values,
+			Set:            dr.FromFile.Set,
+			Tags:           dr.FromFile.Tags,
+			Needs:          dr.FromFile.Needs,
+			WaitFor:        dr.FromFile.WaitFor,
+			WaitForTimeout: dr.FromFile.WaitForTimeout,
+			Apply:          dr.FromFile.Apply,
+			Delete:         dr.FromFile.Delete,
+			Owner:          dr.FromFile.Owner,
+			Hooks:          dr.FromFile.Hooks,
 		}
 	case dr.FromInline != nil:
 		leaf = *dr.FromInline
@@ -128,8 +170,8 @@ func resolveRelease(u *Universe, dr discoveredRelease, profile string) (*Resolve
 	if strings.TrimSpace(leaf.Name) == "" {
 		return nil, fmt.Errorf("%s: release name is required", dr.Dir)
 	}
-	if strings.TrimSpace(leaf.Chart) == "" {
-		return nil, fmt.Errorf("%s: chart is required for release %s", dr.Dir, leaf.Name)
+	if msg := nodeSourceProblem(leaf.Type, leaf.Chart, leaf.Manifests, leaf.Task); msg != "" {
+		return nil, fmt.Errorf("%s: %s for release %s", dr.Dir, msg, leaf.Name)
 	}
 
 	n := &ResolvedRelease{
@@ -167,6 +209,14 @@ func resolveRelease(u *Universe, dr discoveredRelease, profile string) (*Resolve
 		return nil, err
 	}
 	mergeReleaseOverride(n, dr.Dir, leaf)
+	for _, raw := range n.WaitFor {
+		if _, err := ParseWaitFor(raw); err != nil {
+			return nil, fmt.Errorf("%s: release %s: %w", dr.Dir, leaf.Name, err)
+		}
+	}
+	if _, err := parseRiskTags(n.Apply.RequireApproval); err != nil {
+		return nil, fmt.Errorf("%s: release %s: apply.requireApproval: %w", dr.Dir, leaf.Name, err)
+	}
 
 	if n.Namespace == "" {
 		n.Namespace = "default"
@@ -0,0 +1,243 @@
+// File: internal/stack/conditions.go
+// Brief: `enabled:` expressions (CEL) on releases and hooks.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+// DisabledNode records a release or hook that was dropped from the plan at compile time.
+type DisabledNode struct {
+	ID     string `json:"id"`
+	Kind   string `json:"kind"` // release|hook
+	Reason string `json:"reason"`
+}
+
+// conditionContext is what an `enabled:` expression can see:
+//
+//	values     the release's merged values files and set entries
+//	profile    the selected profile
+//	cluster, namespace, release, tags
+//	env        the process environment
+type conditionContext struct {
+	profile string
+	node    *ResolvedRelease
+
+	values map[string]any
+}
+
+func (c *conditionContext) activation() (map[string]any, error) {
+	vars := map[string]any{
+		"profile":   c.profile,
+		"env":       environMap(),
+		"values":    map[string]any{},
+		"cluster":   "",
+		"namespace": "",
+		"release":   "",
+		"tags":      []string{},
+	}
+	if c.node == nil {
+		return vars, nil
+	}
+	if c.values == nil {
+		files, err := releaseValuesFiles(context.Background(), c.node, nil)
+		if err != nil {
+			return nil, err
+		}
+		opts := values.Options{ValueFiles: files, Values: flattenSet(c.node.Set)}
+		merged, err := opts.MergeValues(getter.Providers{})
+		if err != nil {
+			return nil, fmt.Errorf("load values: %w", err)
+		}
+		c.values = merged
+	}
+	vars["values"] = c.values
+	vars["cluster"] = c.node.Cluster.Name
+	vars["namespace"] = c.node.Namespace
+	vars["release"] = c.node.Name
+	if c.node.Tags != nil {
+		vars["tags"] = c.node.Tags
+	}
+	return vars, nil
+}
+
+var conditionEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("values", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("profile", cel.StringType),
+		cel.Variable("cluster", cel.StringType),
+		cel.Variable("namespace", cel.StringType),
+		cel.Variable("release", cel.StringType),
+		cel.Variable("tags", cel.ListType(cel.StringType)),
+		cel.Variable("env", cel.MapType(cel.StringType, cel.StringType)),
+	)
+})
+
+// conditionExpr strips the optional ${...} wrapper.
+func conditionExpr(raw string) string {
+	expr := strings.TrimSpace(raw)
+	if strings.HasPrefix(expr, "${") && strings.HasSuffix(expr, "}") {
+		expr = strings.TrimSpace(expr[2 : len(expr)-1])
+	}
+	return expr
+}
+
+// evalCondition evaluates an `enabled:` expression. An empty expression is true.
+func evalCondition(raw string, c *conditionContext) (bool, error) {
+	expr := conditionExpr(raw)
+	if expr == "" {
+		return true, nil
+	}
+	if b, err := strconv.ParseBool(expr); err == nil {
+		return b, nil
+	}
+	env, err := conditionEnv()
+	if err != nil {
+		return false, err
+	}
+	ast, iss := env.Compile(expr)
+	if iss != nil && iss.Err() != nil {
+		return false, fmt.Errorf("enabled %q: %w", raw, iss.Err())
+	}
+	if t := ast.OutputType(); t != cel.BoolType && t != cel.DynType {
+		return false, fmt.Errorf("enabled %q: must evaluate to a bool (got %s)", raw, t)
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return false, fmt.Errorf("enabled %q: %w", raw, err)
+	}
+	vars, err := c.activation()
+	if err != nil {
+		return false, fmt.Errorf("enabled %q: %w", raw, err)
+	}
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such key") {
+			return false, fmt.Errorf("enabled %q: %w (guard optional keys with has(), e.g. has(values.features.search) && values.features.search)", raw, err)
+		}
+		return false, fmt.Errorf("enabled %q: %w", raw, err)
+	}
+	b, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("enabled %q: must evaluate to a bool (got %v)", raw, out.Value())
+	}
+	return b, nil
+}
+
+// applyEnabledConditions drops releases whose enabled expression (or overlay) is false, prunes
+// needs on them, and drops disabled hooks. Node IDs are recomputed since overlays may have moved
+// releases.
+func applyEnabledConditions(nodes []*ResolvedRelease, profile string) ([]*ResolvedRelease, []DisabledNode, error) {
+	var disabled []DisabledNode
+	out := make([]*ResolvedRelease, 0, len(nodes))
+	off := map[string]map[string]bool{}
+	for _, n := range nodes {
+		n.ID = fmt.Sprintf("%s/%s/%s", n.Cluster.Name, n.Namespace, n.Name)
+		c := &conditionContext{profile: profile, node: n}
+		ok, err := evalCondition(n.Enabled, c)
+		if err != nil {
+			return nil, nil, fmt.Errorf("release %s: %w", n.ID, err)
+		}
+		if !ok {
+			if off[n.Cluster.Name] == nil {
+				off[n.Cluster.Name] = map[string]bool{}
+			}
+			off[n.Cluster.Name][n.Name] = true
+			disabled = append(disabled, DisabledNode{ID: n.ID, Kind: "release", Reason: disabledReason(n.Enabled, n.enabledBy)})
+			continue
+		}
+		hooks, hooksOff, err := filterEnabledHooks(n.Hooks, c, n.ID+" ")
+		if err != nil {
+			return nil, nil, fmt.Errorf("release %s: %w", n.ID, err)
+		}
+		n.Hooks = hooks
+		disabled = append(disabled, hooksOff...)
+		out = append(out, n)
+	}
+	for _, n := range out {
+		if len(n.Needs) == 0 || len(off[n.Cluster.Name]) == 0 {
+			continue
+		}
+		needs := make([]string, 0, len(n.Needs))
+		for _, dep := range n.Needs {
+			if !off[n.Cluster.Name][dep] {
+				needs = append(needs, dep)
+			}
+		}
+		n.Needs = needs
+	}
+	return out, disabled, nil
+}
+
+func disabledReason(expr, by string) string {
+	if by != "" {
+		return "enabled: false in overlay " + by
+	}
+	if _, err := strconv.ParseBool(conditionExpr(expr)); err == nil {
+		return "enabled: false"
+	}
+	return fmt.Sprintf("enabled: %s is false", strings.TrimSpace(expr))
+}
+
+func filterEnabledHooks(cfg StackHooksConfig, c *conditionContext, idPrefix string) (StackHooksConfig, []DisabledNode, error) {
+	var disabled []DisabledNode
+	filter := func(phase string, in []HookSpec) ([]HookSpec, error) {
+		if len(in) == 0 {
+			return in, nil
+		}
+		out := make([]HookSpec, 0, len(in))
+		for i, h := range in {
+			ok, err := evalCondition(h.Enabled, c)
+			if err != nil {
+				return nil, fmt.Errorf("hooks.%s[%d]: %w", phase, i, err)
+			}
+			if ok {
+				out = append(out, h)
+				continue
+			}
+			name := strings.TrimSpace(h.Name)
+			if name == "" {
+				name = strconv.Itoa(i)
+			}
+			disabled = append(disabled, DisabledNode{ID: idPrefix + "hooks." + phase + "/" + name, Kind: "hook", Reason: disabledReason(h.Enabled, "")})
+		}
+		if len(out) == 0 {
+			return nil, nil
+		}
+		return out, nil
+	}
+	var err error
+	if cfg.PreApply, err = filter("preApply", cfg.PreApply); err != nil {
+		return cfg, nil, err
+	}
+	if cfg.PostApply, err = filter("postApply", cfg.PostApply); err != nil {
+		return cfg, nil, err
+	}
+	if cfg.PreDelete, err = filter("preDelete", cfg.PreDelete); err != nil {
+		return cfg, nil, err
+	}
+	if cfg.PostDelete, err = filter("postDelete", cfg.PostDelete); err != nil {
+		return cfg, nil, err
+	}
+	return cfg, disabled, nil
+}
+
+func environMap() map[string]string {
+	out := map[string]string{}
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package stack
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompile_EnabledExpressions(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "values", "prod.yaml"), "features:\n  search: false\n  billing: true\n")
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+defaults:
+  cluster: { name: c1 }
+  namespace: apps
+  values: [./values/prod.yaml]
+hooks:
+  postApply:
+    - name: notify
+      runOnce: true
+      enabled: profile == "prod"
+      type: script
+      script: { command: [echo, hi] }
+releases:
+  - name: search
+    chart: ./charts/search
+    enabled: ${values.features.search}
+  - name: billing
+    chart: ./charts/billing
+    enabled: values.features.billing && "payments" in tags
+    tags: [payments]
+    hooks:
+      preApply:
+        - name: smoke
+          enabled: "false"
+          type: script
+          script: { command: [true] }
+  - name: api
+    chart: ./charts/api
+    needs: [search, billing]
+    set: { features.search: "true" }
+    enabled: values.features.search
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	p, err := Compile(u, CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if len(p.Nodes) != 2 || p.ByID["c1/apps/search"] != nil {
+		t.Fatalf("expected search to be disabled, got %v", p.Order)
+	}
+	api := p.ByID["c1/apps/api"]
+	if api == nil || strings.Join(api.Needs, ",") != "billing" {
+		t.Fatalf("expected needs on disabled search to be pruned, got %+v", api)
+	}
+	if billing := p.ByID["c1/apps/billing"]; len(billing.Hooks.PreApply) != 0 {
+		t.Fatalf("expected smoke hook to be dropped, got %+v", billing.Hooks)
+	}
+	if len(p.Hooks.PostApply) != 0 {
+		t.Fatalf("expected notify hook to be dropped outside prod")
+	}
+	want := []DisabledNode{
+		{ID: "c1/apps/billing hooks.preApply/smoke", Kind: "hook", Reason: "enabled: false"},
+		{ID: "c1/apps/search", Kind: "release", Reason: "enabled: ${values.features.search} is false"},
+		{ID: "hooks.postApply/notify", Kind: "hook", Reason: `enabled: profile == "prod" is false`},
+	}
+	if len(p.Disabled) != len(want) {
+		t.Fatalf("disabled=%+v", p.Disabled)
+	}
+	for i := range want {
+		if p.Disabled[i] != want[i] {
+			t.Fatalf("disabled[%d]=%+v want %+v", i, p.Disabled[i], want[i])
+		}
+	}
+
+	var out bytes.Buffer
+	if err := PrintPlanTable(&out, p); err != nil {
+		t.Fatalf("print: %v", err)
+	}
+	if !strings.Contains(out.String(), "DISABLED") || !strings.Contains(out.String(), "enabled: ${values.features.search} is false") {
+		t.Fatalf("expected disabled section in plan:\n%s", out.String())
+	}
+
+	prod, err := Compile(u, CompileOptions{Profile: "prod"})
+	if err != nil {
+		t.Fatalf("compile prod: %v", err)
+	}
+	if len(prod.Hooks.PostApply) != 1 {
+		t.Fatalf("expected notify hook in prod, got %+v", prod.Hooks)
+	}
+}
+
+func TestEvalCondition_Errors(t *testing.T) {
+	c := &conditionContext{node: &ResolvedRelease{Name: "api"}, values: map[string]any{"features": map[string]any{}}}
+	for expr, want := range map[string]string{
+		"values.features.search": "guard optional keys with has()",
+		"release + 1":            "no matching overload",
+		`release`:                "must evaluate to a bool",
+	} {
+		if _, err := evalCondition(expr, c); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected %q, got %v", expr, want, err)
+		}
+	}
+	if ok, err := evalCondition("has(values.features.search) && values.features.search", c); err != nil || ok {
+		t.Fatalf("has() guard: ok=%v err=%v", ok, err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kubekattle/ktl/internal/i18n"
 	"github.com/mattn/go-runewidth"
 )
 
@@ -56,6 +57,22 @@ type RunConsoleOptions struct {
 
 	// HelmLogTail caps stored log lines per node (0 uses a default).
 	HelmLogTail int
+
+	// GroupBy collapses the node table into one summary row per group.
+	// Supported values: auto|none|group|cluster. "auto" groups by execution group
+	// once the table holds more than runConsoleAutoGroupNodes nodes.
+	GroupBy string
+
+	// ExpandGroups lists groups rendered node by node under their summary row,
+	// by execution group number or cluster name.
+	ExpandGroups []string
+
+	// ExpandFailed expands every group that contains a failed node.
+	ExpandFailed bool
+
+	// NoteWrap wraps long notes and failure messages over continuation lines
+	// instead of truncating them, and adds the error message to failed nodes' notes.
+	NoteWrap bool
 }
 
 // RunConsole renders stack run events into a single in-place updating TTY view.
@@ -77,8 +94,8 @@ type RunConsole struct {
 	command    string
 	targetConc int
 	runStage   string
-	sections   []runConsoleSection
-	totalLines int
+	surface    *ConsoleSurface
+	done       bool
 }
 
 type runConsoleNodeState struct {
@@ -139,11 +156,6 @@ type runConsoleHookEntry struct {
 	status   string // started|succeeded|failed|skipped
 }
 
-type runConsoleSection struct {
-	name  string
-	lines []string
-}
-
 type runConsoleHelmLogEntry struct {
 	seq     int64
 	offset  int
@@ -161,6 +173,7 @@ func NewRunConsole(out io.Writer, plan *Plan, command string, opts RunConsoleOpt
 	c := &RunConsole{
 		out:        out,
 		opts:       opts,
+		surface:    NewConsoleSurface(out),
 		plan:       plan,
 		command:    strings.TrimSpace(command),
 		startedAt:  opts.Now(),
@@ -216,10 +229,8 @@ func (c *RunConsole) Done() {
 	}
 	c.mu.Lock()
 	c.renderLocked()
-	if c.totalLines > 0 {
-		fmt.Fprint(c.out, "\x1b[K\n")
-		c.totalLines++
-	}
+	c.surface.Finish()
+	c.done = true
 	c.mu.Unlock()
 }
 
@@ -232,9 +243,9 @@ func (c *RunConsole) SnapshotLines() []string {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	sections := c.buildSectionsLocked()
-	out := make([]string, 0, runConsoleCountLines(sections))
+	out := make([]string, 0, consoleLineCount(sections))
 	for _, section := range sections {
-		out = append(out, section.lines...)
+		out = append(out, section.Lines...)
 	}
 	return out
 }
@@ -803,74 +814,37 @@ func (c *RunConsole) renderLocked() {
 		return
 	}
 	newSections := c.buildSectionsLocked()
-	c.applyDiffLocked(newSections)
+	c.surface.Render(newSections)
 }
 
-func (c *RunConsole) buildSectionsLocked() []runConsoleSection {
-	var sections []runConsoleSection
-	sections = append(sections, runConsoleSection{name: "header", lines: c.renderHeaderLocked()})
+func (c *RunConsole) buildSectionsLocked() []ConsoleSection {
+	var sections []ConsoleSection
+	sections = append(sections, ConsoleSection{Name: "header", Lines: c.renderHeaderLocked()})
 	if lines := c.renderStackRailLocked(); len(lines) > 0 {
-		sections = append(sections, runConsoleSection{name: "stack-rail", lines: lines})
+		sections = append(sections, ConsoleSection{Name: "stack-rail", Lines: lines})
 	}
 	if len(c.failures) > 0 {
-		sections = append(sections, runConsoleSection{name: "failures", lines: c.renderFailuresLocked()})
+		sections = append(sections, ConsoleSection{Name: "failures", Lines: c.renderFailuresLocked()})
 	}
-	sections = append(sections, runConsoleSection{name: "nodes", lines: c.renderNodesLocked()})
+	sections = append(sections, ConsoleSection{Name: "nodes", Lines: c.renderNodesLocked()})
 	if c.opts.ShowHooks {
 		if lines := c.renderHooksLocked(); len(lines) > 0 {
-			sections = append(sections, runConsoleSection{name: "hooks", lines: lines})
+			sections = append(sections, ConsoleSection{Name: "hooks", Lines: lines})
 		}
 	}
 	if c.opts.ShowDetails {
 		if lines := c.renderDetailsLocked(); len(lines) > 0 {
-			sections = append(sections, runConsoleSection{name: "details", lines: lines})
+			sections = append(sections, ConsoleSection{Name: "details", Lines: lines})
 		}
 	}
 	if c.opts.ShowHelmLogs {
 		if lines := c.renderHelmLogsLocked(); len(lines) > 0 {
-			sections = append(sections, runConsoleSection{name: "helm-logs", lines: lines})
+			sections = append(sections, ConsoleSection{Name: "helm-logs", Lines: lines})
 		}
 	}
 	return sections
 }
 
-func (c *RunConsole) applyDiffLocked(newSections []runConsoleSection) {
-	newTotal := runConsoleCountLines(newSections)
-	if len(c.sections) == 0 {
-		c.writeSections(newSections)
-		c.sections = runConsoleCloneSections(newSections)
-		c.totalLines = newTotal
-		return
-	}
-	idx := runConsoleDiffIndex(c.sections, newSections)
-	if idx == -1 && newTotal == c.totalLines {
-		return
-	}
-	if idx == -1 {
-		idx = len(newSections)
-	}
-	startLine := runConsoleSumLines(c.sections[:idx])
-	linesBelow := c.totalLines - startLine
-	if linesBelow > 0 {
-		fmt.Fprintf(c.out, "\x1b[%dF", linesBelow)
-	}
-	fmt.Fprint(c.out, "\x1b[J")
-	c.writeSections(newSections[idx:])
-	c.sections = runConsoleCloneSections(newSections)
-	c.totalLines = newTotal
-}
-
-func (c *RunConsole) writeSections(sections []runConsoleSection) {
-	for _, section := range sections {
-		for _, line := range section.lines {
-			fmt.Fprintf(c.out, "%s\x1b[K\n", line)
-		}
-	}
-	if len(sections) == 0 {
-		fmt.Fprint(c.out, "\x1b[K\n")
-	}
-}
-
 func (c *RunConsole) renderHeaderLocked() []string {
 	stackName := ""
 	if c.plan != nil {
@@ -982,7 +956,7 @@ func (c *RunConsole) renderStackRailLocked() []string {
 	}
 	ns := c.nodes[runConsoleStackNodeID]
 	if ns == nil {
-		return []string{runConsoleAnsiDimBold(c.opts.Color, runConsoleTrimToWidth("STACK HOOKS", width))}
+		return []string{runConsoleAnsiDimBold(c.opts.Color, runConsoleTrimToWidth(i18n.T(i18n.StackHooksHeader), width))}
 	}
 
 	exp := expectedStackHooksForCommand(c.plan, c.command)
@@ -1024,7 +998,7 @@ func (c *RunConsole) renderStackRailLocked() []string {
 	line := "stack " + statusTag + " " + runConsoleAnsiDim(c.opts.Color, rest)
 
 	return []string{
-		runConsoleAnsiDimBold(c.opts.Color, runConsoleTrimToWidth("STACK HOOKS", width)),
+		runConsoleAnsiDimBold(c.opts.Color, runConsoleTrimToWidth(i18n.T(i18n.StackHooksHeader), width)),
 		runConsoleTrimToWidth(line, width),
 	}
 }
@@ -1036,7 +1010,7 @@ func (c *RunConsole) renderFailuresLocked() []string {
 	}
 	const maxLines = 8
 
-	header := fmt.Sprintf("FAILURES (%d)", len(c.failures))
+	header := i18n.T(i18n.StackFailures, len(c.failures))
 	lines := []string{runConsoleAnsiRedBold(c.opts.Color, runConsoleTrimToWidth(header, width))}
 
 	// Most recent failures first for the sticky rail.
@@ -1078,14 +1052,20 @@ func (c *RunConsole) renderFailuresLocked() []string {
 			msg = msg + " · hint " + hint
 		}
 
-		line := runConsoleBulletFit(width, []string{
+		segments := []string{
 			runConsoleTrimToWidth(f.nodeID, 28),
 			fmt.Sprintf("a%d", f.attempt),
 			runConsoleTrimToWidth(class, 18),
 			digestShort,
 			msg,
-		})
-		lines = append(lines, runConsoleAnsiRed(c.opts.Color, line))
+		}
+		if c.opts.NoteWrap {
+			for _, line := range runConsoleBulletWrap(width, segments) {
+				lines = append(lines, runConsoleAnsiRed(c.opts.Color, line))
+			}
+		} else {
+			lines = append(lines, runConsoleAnsiRed(c.opts.Color, runConsoleBulletFit(width, segments)))
+		}
 		shown++
 	}
 	if extra := len(c.failures) - shown; extra > 0 {
@@ -1112,13 +1092,13 @@ func remediationHint(class string) string {
 	c := strings.ToUpper(strings.TrimSpace(class))
 	switch c {
 	case "HOOK_FAILED":
-		return "check hook summary/output (and when=)"
+		return i18n.T(i18n.StackHintHookFailed)
 	case "WAIT_TIMEOUT":
-		return "inspect blockers; consider increasing apply.timeout"
+		return i18n.T(i18n.StackHintWaitTimeout)
 	case "HELM_RATE_LIMIT", "KUBE_RATE_LIMIT":
-		return "try lower concurrency or rerun"
+		return i18n.T(i18n.StackHintRateLimit)
 	case "HELM_ERROR":
-		return "check helm logs; rerun with --helm-logs=all"
+		return i18n.T(i18n.StackHintHelmError)
 	default:
 		return ""
 	}
@@ -1138,11 +1118,11 @@ func (c *RunConsole) renderNodesLocked() []string {
 	col := runConsoleColumnWidths(width)
 	lines := make([]string, 0, len(order)+3)
 	lines = append(lines, strings.TrimRight(runConsoleJoinRow(width,
-		runConsoleFormatCell("Node", col.node, runConsoleAlignLeft),
-		runConsoleFormatCell("Status", col.status, runConsoleAlignLeft),
-		runConsoleFormatCell("Att", col.attempt, runConsoleAlignRight),
-		runConsoleFormatCell("Phase", col.phase, runConsoleAlignLeft),
-		runConsoleFormatCell("Note", col.note, runConsoleAlignLeft),
+		runConsoleFormatCell(i18n.T(i18n.StackColNode), col.node, runConsoleAlignLeft),
+		runConsoleFormatCell(i18n.T(i18n.StackColStatus), col.status, runConsoleAlignLeft),
+		runConsoleFormatCell(i18n.T(i18n.StackColAttempt), col.attempt, runConsoleAlignRight),
+		runConsoleFormatCell(i18n.T(i18n.StackColPhase), col.phase, runConsoleAlignLeft),
+		runConsoleFormatCell(i18n.T(i18n.StackColNote), col.note, runConsoleAlignLeft),
 	), " "))
 	lines = append(lines, strings.Repeat("-", width))
 
@@ -1152,64 +1132,99 @@ func (c *RunConsole) renderNodesLocked() []string {
 	}
 
 	now := c.now()
-	for _, id := range order {
-		ns := c.nodes[id]
-		if ns == nil {
-			ns = &runConsoleNodeState{id: id, status: "planned"}
-		}
-		nodeLabel := id
-		if width <= 100 {
-			if v := strings.TrimSpace(labels[id]); v != "" {
-				nodeLabel = v
+	if groups := c.nodeGroupsLocked(order); groups != nil {
+		for _, g := range groups {
+			expanded := c.groupExpandedLocked(g)
+			lines = append(lines, c.groupRowLocked(g, expanded, width, col))
+			if !expanded {
+				continue
 			}
-		} else if col.node > 0 && runewidth.StringWidth(nodeLabel) > col.node {
-			nodeLabel = c.compactNodeLabelLocked(id)
-		}
-		statusCell := runConsoleStatusCell(strings.ToUpper(ns.status))
-		attempt := ns.attempt
-		phase := strings.TrimSpace(ns.phase)
-		if phase == "" {
-			phase = "-"
-		}
-		if id != runConsoleStackNodeID && !c.opts.Verbose && !c.opts.ShowNoisyPhases && ns.status != "failed" && isNoisyPhase(phase) {
-			phase = "-"
-		}
-		note := c.nodeProgressNoteLocked(id, ns, width)
-		if strings.TrimSpace(ns.wait) != "" {
-			note = strings.TrimSpace(ns.wait)
-		} else if ns.lastError != nil && strings.TrimSpace(ns.lastError.Class) != "" {
-			cls := strings.TrimSpace(ns.lastError.Class)
-			if strings.TrimSpace(note) == "" {
-				note = cls
-			} else {
-				note = fmt.Sprintf("%s · %s", note, cls)
+			for _, id := range g.ids {
+				lines = append(lines, c.nodeRowLocked(id, "  ", width, col, labels, now)...)
 			}
 		}
-		if width <= 100 && strings.TrimSpace(ns.lastBlocker) != "" {
-			blk := "blk " + strings.TrimSpace(ns.lastBlocker)
-			if strings.TrimSpace(note) == "" {
-				note = blk
-			} else {
-				note = note + " " + blk
-			}
+		return lines
+	}
+	for _, id := range order {
+		lines = append(lines, c.nodeRowLocked(id, "", width, col, labels, now)...)
+	}
+	return lines
+}
+
+// nodeRowLocked renders one node table row, plus note continuation rows with NoteWrap; indent
+// prefixes the node label (used under an expanded group header).
+func (c *RunConsole) nodeRowLocked(id string, indent string, width int, col runConsoleCols, labels map[string]string, now time.Time) []string {
+	ns := c.nodes[id]
+	if ns == nil {
+		ns = &runConsoleNodeState{id: id, status: "planned"}
+	}
+	nodeLabel := id
+	if width <= 100 {
+		if v := strings.TrimSpace(labels[id]); v != "" {
+			nodeLabel = v
+		}
+	} else if col.node > 0 && runewidth.StringWidth(indent+nodeLabel) > col.node {
+		nodeLabel = c.compactNodeLabelLocked(id)
+	}
+	statusCell := runConsoleStatusCell(strings.ToUpper(ns.status))
+	attempt := ns.attempt
+	phase := strings.TrimSpace(ns.phase)
+	if phase == "" {
+		phase = "-"
+	}
+	if id != runConsoleStackNodeID && !c.opts.Verbose && !c.opts.ShowNoisyPhases && ns.status != "failed" && isNoisyPhase(phase) {
+		phase = "-"
+	}
+	note := c.nodeProgressNoteLocked(id, ns, width)
+	if strings.TrimSpace(ns.wait) != "" {
+		note = strings.TrimSpace(ns.wait)
+	} else if ns.lastError != nil && strings.TrimSpace(ns.lastError.Class) != "" {
+		cls := strings.TrimSpace(ns.lastError.Class)
+		if strings.TrimSpace(note) == "" {
+			note = cls
+		} else {
+			note = fmt.Sprintf("%s · %s", note, cls)
 		}
-		elapsed := ""
-		if !ns.startedAt.IsZero() && (ns.status == "running" || ns.status == "retrying") {
-			elapsed = now.Sub(ns.startedAt).Round(100 * time.Millisecond).String()
+		if c.opts.NoteWrap && ns.status == "failed" {
+			if msg := strings.TrimSpace(ns.lastError.Message); msg != "" {
+				note = note + ": " + msg
+			}
 		}
-		if elapsed != "" {
-			phase = fmt.Sprintf("%s (%s)", phase, elapsed)
+	}
+	if width <= 100 && strings.TrimSpace(ns.lastBlocker) != "" {
+		blk := "blk " + strings.TrimSpace(ns.lastBlocker)
+		if strings.TrimSpace(note) == "" {
+			note = blk
+		} else {
+			note = note + " " + blk
 		}
+	}
+	elapsed := ""
+	if !ns.startedAt.IsZero() && (ns.status == "running" || ns.status == "retrying") {
+		elapsed = now.Sub(ns.startedAt).Round(100 * time.Millisecond).String()
+	}
+	if elapsed != "" {
+		phase = fmt.Sprintf("%s (%s)", phase, elapsed)
+	}
 
-		lines = append(lines, strings.TrimRight(runConsoleJoinRow(width,
-			runConsoleFormatCell(nodeLabel, col.node, runConsoleAlignLeft),
-			runConsoleFormatStatusCell(c.opts.Color, col.status, statusCell),
-			runConsoleFormatCell(fmt.Sprintf("%d", attempt), col.attempt, runConsoleAlignRight),
-			runConsoleFormatCell(phase, col.phase, runConsoleAlignLeft),
-			runConsoleFormatCell(note, col.note, runConsoleAlignLeft),
+	notes := []string{note}
+	if c.opts.NoteWrap {
+		notes = runConsoleWrap(note, col.note, runConsoleNoteWrapLines)
+	}
+	rows := []string{strings.TrimRight(runConsoleJoinRow(width,
+		indent+runConsoleFormatCell(nodeLabel, col.node-len(indent), runConsoleAlignLeft),
+		runConsoleFormatStatusCell(c.opts.Color, col.status, statusCell),
+		runConsoleFormatCell(fmt.Sprintf("%d", attempt), col.attempt, runConsoleAlignRight),
+		runConsoleFormatCell(phase, col.phase, runConsoleAlignLeft),
+		runConsoleFormatCell(notes[0], col.note, runConsoleAlignLeft),
+	), " ")}
+	for _, cont := range notes[1:] {
+		rows = append(rows, strings.TrimRight(runConsoleJoinRow(width,
+			strings.Repeat(" ", col.node), strings.Repeat(" ", col.status), strings.Repeat(" ", col.attempt), strings.Repeat(" ", col.phase),
+			runConsoleFormatCell(cont, col.note, runConsoleAlignLeft),
 		), " "))
 	}
-	return lines
+	return rows
 }
 
 func (c *RunConsole) buildNodeLabelsLocked(ids []string) map[string]string {
@@ -1510,7 +1525,7 @@ func (c *RunConsole) renderHelmLogsLocked() []string {
 	}
 
 	lines := make([]string, 0, len(order)*6)
-	lines = append(lines, runConsoleAnsiDimBold(c.opts.Color, runConsoleTrimToWidth("HELM LOGS", width)))
+	lines = append(lines, runConsoleAnsiDimBold(c.opts.Color, runConsoleTrimToWidth(i18n.T(i18n.StackHelmLogs), width)))
 
 	for _, id := range order {
 		entries := c.helmLogs[id]
@@ -1597,7 +1612,7 @@ func (c *RunConsole) renderHooksLocked() []string {
 		width = 120
 	}
 
-	lines := []string{runConsoleAnsiDimBold(c.opts.Color, runConsoleTrimToWidth("HOOKS", width))}
+	lines := []string{runConsoleAnsiDimBold(c.opts.Color, runConsoleTrimToWidth(i18n.T(i18n.StackHooks), width))}
 
 	maxLines := 14
 	tail := c.opts.HookTail
@@ -1731,7 +1746,7 @@ func (c *RunConsole) renderDetailsLocked() []string {
 		targets = targets[:2]
 	}
 
-	lines := []string{runConsoleAnsiDimBold(c.opts.Color, runConsoleTrimToWidth("DETAILS", width))}
+	lines := []string{runConsoleAnsiDimBold(c.opts.Color, runConsoleTrimToWidth(i18n.T(i18n.StackDetails), width))}
 	for _, t := range targets {
 		id := t.id
 		ns := c.nodes[id]
@@ -2320,55 +2335,6 @@ func runConsoleTrimToWidthKeepLeft(s string, width int) string {
 	return string(out) + "…"
 }
 
-func runConsoleCountLines(sections []runConsoleSection) int {
-	total := 0
-	for _, section := range sections {
-		total += len(section.lines)
-	}
-	return total
-}
-
-func runConsoleSumLines(sections []runConsoleSection) int {
-	total := 0
-	for _, section := range sections {
-		total += len(section.lines)
-	}
-	return total
-}
-
-func runConsoleDiffIndex(oldSections, newSections []runConsoleSection) int {
-	max := len(oldSections)
-	if len(newSections) < max {
-		max = len(newSections)
-	}
-	for i := 0; i < max; i++ {
-		if oldSections[i].name != newSections[i].name {
-			return i
-		}
-		if len(oldSections[i].lines) != len(newSections[i].lines) {
-			return i
-		}
-		for j := range oldSections[i].lines {
-			if oldSections[i].lines[j] != newSections[i].lines[j] {
-				return i
-			}
-		}
-	}
-	if len(oldSections) != len(newSections) {
-		return max
-	}
-	return -1
-}
-
-func runConsoleCloneSections(sections []runConsoleSection) []runConsoleSection {
-	out := make([]runConsoleSection, 0, len(sections))
-	for _, section := range sections {
-		lines := append([]string(nil), section.lines...)
-		out = append(out, runConsoleSection{name: section.name, lines: lines})
-	}
-	return out
-}
-
 func runConsoleAnsi(enabled bool, code string, s string) string {
 	if !enabled {
 		return s
@@ -0,0 +1,150 @@
+// File: internal/stack/console_groups.go
+// Brief: Collapsible node groups for large stacks in the RunConsole node table.
+
+package stack
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// runConsoleAutoGroupNodes is the node count above which GroupBy=auto collapses the table.
+const runConsoleAutoGroupNodes = 40
+
+type runConsoleGroup struct {
+	key   string
+	label string
+	rank  int
+	ids   []string
+
+	ok      int
+	failed  int
+	running int
+	pending int
+	skipped int
+}
+
+// groupByLocked returns the effective grouping for n visible nodes: "", "group", or "cluster".
+func (c *RunConsole) groupByLocked(n int) string {
+	switch strings.ToLower(strings.TrimSpace(c.opts.GroupBy)) {
+	case "none", "off":
+		return ""
+	case "group", "execution-group":
+		return "group"
+	case "cluster":
+		return "cluster"
+	default:
+		if n > runConsoleAutoGroupNodes {
+			return "group"
+		}
+		return ""
+	}
+}
+
+// nodeGroupsLocked splits order into groups, listing each group's nodes by node ID so expanded
+// groups read the same on every render. It returns nil when the table is not grouped.
+func (c *RunConsole) nodeGroupsLocked(order []string) []*runConsoleGroup {
+	by := c.groupByLocked(len(order))
+	if by == "" {
+		return nil
+	}
+	byKey := map[string]*runConsoleGroup{}
+	var groups []*runConsoleGroup
+	for _, id := range order {
+		meta := c.metaByID[id]
+		key, label, rank := strconv.Itoa(meta.executionGroup), "group "+strconv.Itoa(meta.executionGroup), meta.executionGroup
+		if by == "cluster" {
+			key, label, rank = meta.cluster, "cluster "+meta.cluster, 0
+		}
+		g := byKey[key]
+		if g == nil {
+			g = &runConsoleGroup{key: key, label: label, rank: rank}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.ids = append(g.ids, id)
+		switch strings.ToLower(strings.TrimSpace(c.getStatus(id))) {
+		case "succeeded":
+			g.ok++
+		case "failed":
+			g.failed++
+		case "running", "retrying":
+			g.running++
+		case "skipped":
+			g.skipped++
+		default:
+			g.pending++
+		}
+	}
+	for _, g := range groups {
+		sort.Strings(g.ids)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].rank != groups[j].rank {
+			return groups[i].rank < groups[j].rank
+		}
+		return groups[i].key < groups[j].key
+	})
+	return groups
+}
+
+func (c *RunConsole) groupExpandedLocked(g *runConsoleGroup) bool {
+	if c.opts.ExpandFailed && g.failed > 0 {
+		return true
+	}
+	for _, key := range c.opts.ExpandGroups {
+		if strings.TrimSpace(key) == g.key {
+			return true
+		}
+	}
+	return false
+}
+
+// groupRowLocked renders a group's summary row: an aggregate status and per-status counts.
+func (c *RunConsole) groupRowLocked(g *runConsoleGroup, expanded bool, width int, col runConsoleCols) string {
+	marker := "▸"
+	if expanded {
+		marker = "▾"
+	}
+	label := fmt.Sprintf("%s %s (%d)", marker, g.label, len(g.ids))
+
+	var status string
+	switch {
+	case g.failed > 0:
+		status = "FAILED"
+	case g.running > 0:
+		status = "RUNNING"
+	case g.pending > 0 && g.ok+g.skipped > 0:
+		status = "QUEUED"
+	case g.pending > 0:
+		status = "PLANNED"
+	case g.ok > 0:
+		status = "SUCCEEDED"
+	default:
+		status = "SKIPPED"
+	}
+
+	parts := []string{fmt.Sprintf("%d ok", g.ok)}
+	if g.failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", g.failed))
+	}
+	if g.running > 0 {
+		parts = append(parts, fmt.Sprintf("%d running", g.running))
+	}
+	if g.pending > 0 {
+		parts = append(parts, fmt.Sprintf("%d pending", g.pending))
+	}
+	if g.skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", g.skipped))
+	}
+
+	return strings.TrimRight(runConsoleJoinRow(width,
+		runConsoleAnsiBold(c.opts.Color, runConsoleFormatCell(label, col.node, runConsoleAlignLeft)),
+		runConsoleFormatStatusCell(c.opts.Color, col.status, runConsoleStatusCell(status)),
+		runConsoleFormatCell("", col.attempt, runConsoleAlignRight),
+		runConsoleFormatCell("-", col.phase, runConsoleAlignLeft),
+		runConsoleFormatCell(strings.Join(parts, " · "), col.note, runConsoleAlignLeft),
+	), " ")
+}
@@ -0,0 +1,80 @@
+package stack
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func groupedTestPlan(n int) *Plan {
+	p := &Plan{StackName: "big"}
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("svc-%02d", i)
+		cluster := "eu"
+		if i%2 == 1 {
+			cluster = "us"
+		}
+		p.Nodes = append(p.Nodes, &ResolvedRelease{
+			ID:             cluster + "/apps/" + name,
+			Name:           name,
+			Cluster:        ClusterTarget{Name: cluster},
+			Namespace:      "apps",
+			ExecutionGroup: i / 20,
+		})
+	}
+	return p
+}
+
+func TestRunConsole_GroupsLargeStacks(t *testing.T) {
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	newConsole := func(opts RunConsoleOptions) *RunConsole {
+		opts.Enabled = true
+		opts.Width = 120
+		opts.Now = func() time.Time { return clock }
+		c := NewRunConsole(nil, groupedTestPlan(50), "apply", opts)
+		emit := func(ev RunEvent) {
+			c.mu.Lock()
+			c.applyEventLocked(ev)
+			c.mu.Unlock()
+		}
+		ts := clock.Format(time.RFC3339Nano)
+		emit(RunEvent{TS: ts, RunID: "r-1", NodeID: "eu/apps/svc-00", Type: string(NodeSucceeded), Attempt: 1})
+		emit(RunEvent{TS: ts, RunID: "r-1", NodeID: "us/apps/svc-21", Type: string(NodeFailed), Attempt: 1, Error: &RunError{Class: "HELM_ERROR", Message: "boom"}})
+		return c
+	}
+	nodeLines := func(c *RunConsole) []string {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.renderNodesLocked()[2:]
+	}
+
+	lines := nodeLines(newConsole(RunConsoleOptions{}))
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 collapsed group rows, got %d:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if !strings.Contains(lines[0], "▸ group 0 (20)") || !strings.Contains(lines[0], "1 ok · 19 pending") {
+		t.Fatalf("unexpected group 0 row %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "FAILED") || !strings.Contains(lines[1], "0 ok · 1 failed · 19 pending") {
+		t.Fatalf("unexpected group 1 row %q", lines[1])
+	}
+
+	lines = nodeLines(newConsole(RunConsoleOptions{ExpandFailed: true, ExpandGroups: []string{"2"}}))
+	if len(lines) != 3+20+10 {
+		t.Fatalf("expected groups 1 and 2 expanded, got %d lines:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if !strings.Contains(lines[1], "▾ group 1 (20)") || !strings.HasPrefix(lines[2], "  eu/apps/svc-20") {
+		t.Fatalf("expected group 1 nodes under its header, got %q / %q", lines[1], lines[2])
+	}
+
+	lines = nodeLines(newConsole(RunConsoleOptions{GroupBy: "cluster"}))
+	if len(lines) != 2 || !strings.Contains(lines[0], "cluster eu (25)") || !strings.Contains(lines[1], "cluster us (25)") {
+		t.Fatalf("unexpected cluster groups:\n%s", strings.Join(lines, "\n"))
+	}
+
+	lines = nodeLines(newConsole(RunConsoleOptions{GroupBy: "none"}))
+	if len(lines) != 50 {
+		t.Fatalf("expected an ungrouped table, got %d rows", len(lines))
+	}
+}
@@ -0,0 +1,123 @@
+// File: internal/stack/console_layout.go
+// Brief: RunConsole width changes and note wrapping.
+
+package stack
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// runConsoleNoteWrapLines caps how many lines a wrapped note or failure message may take.
+const runConsoleNoteWrapLines = 6
+
+// SetWidth re-lays out the console for a new terminal width (e.g. after SIGWINCH) and redraws
+// it from a cleared screen. It is a no-op once Done has been called.
+func (c *RunConsole) SetWidth(width int) {
+	if c == nil || !c.opts.Enabled || width <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.done || width == c.opts.Width {
+		return
+	}
+	c.opts.Width = width
+	c.surface.Reset()
+	c.renderLocked()
+}
+
+// runConsoleWrap breaks s into lines of at most width cells at spaces, splitting words that do
+// not fit on their own. Past maxLines the last line is truncated with an ellipsis.
+func runConsoleWrap(s string, width int, maxLines int) []string {
+	words := strings.Fields(s)
+	if width <= 0 || len(words) == 0 {
+		return []string{strings.TrimSpace(s)}
+	}
+	var lines []string
+	cur := ""
+	flush := func() {
+		if cur != "" {
+			lines = append(lines, cur)
+			cur = ""
+		}
+	}
+	for _, w := range words {
+		for runewidth.StringWidth(w) > width {
+			flush()
+			head, rest := runConsoleSplitAtWidth(w, width)
+			lines = append(lines, head)
+			w = rest
+		}
+		switch {
+		case cur == "":
+			cur = w
+		case runewidth.StringWidth(cur)+1+runewidth.StringWidth(w) <= width:
+			cur += " " + w
+		default:
+			flush()
+			cur = w
+		}
+	}
+	flush()
+	if maxLines > 0 && len(lines) > maxLines {
+		last := strings.Join(lines[maxLines-1:], " ")
+		lines = append(lines[:maxLines-1], runConsoleTrimToWidth(last, width))
+	}
+	return lines
+}
+
+func runConsoleSplitAtWidth(s string, width int) (string, string) {
+	w := 0
+	for i, r := range s {
+		rw := runewidth.RuneWidth(r)
+		if w+rw > width && i > 0 {
+			return s[:i], s[i:]
+		}
+		w += rw
+	}
+	return s, ""
+}
+
+// runConsoleBulletWrap is the wrapping counterpart of runConsoleBulletFit: the last segment
+// (the message) continues on lines indented under its start, or under a small indent when the
+// prefix leaves too little room.
+func runConsoleBulletWrap(width int, segments []string) []string {
+	const sep = " • "
+	const minMsg = 24
+	if width <= 0 {
+		width = 120
+	}
+	if len(segments) == 0 {
+		return nil
+	}
+	seg := append([]string(nil), segments...)
+	for i := range seg {
+		seg[i] = strings.TrimSpace(seg[i])
+		if seg[i] == "" {
+			seg[i] = "-"
+		}
+	}
+	prefix := strings.Join(seg[:len(seg)-1], sep)
+	if prefix != "" {
+		prefix += sep
+	}
+	msg := seg[len(seg)-1]
+	indent := runewidth.StringWidth(prefix)
+	if width-indent < minMsg {
+		indent = 4
+		body := runConsoleWrap(msg, width-indent, runConsoleNoteWrapLines)
+		lines := []string{runConsoleTrimToWidth(strings.TrimSuffix(prefix, sep), width)}
+		for _, l := range body {
+			lines = append(lines, strings.Repeat(" ", indent)+l)
+		}
+		return lines
+	}
+	body := runConsoleWrap(msg, width-indent, runConsoleNoteWrapLines)
+	lines := []string{prefix + body[0]}
+	for _, l := range body[1:] {
+		lines = append(lines, strings.Repeat(" ", indent)+l)
+	}
+	return lines
+}
@@ -0,0 +1,71 @@
+package stack
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestRunConsoleWrap(t *testing.T) {
+	got := runConsoleWrap("helm upgrade failed: context deadline exceeded", 20, 0)
+	want := []string{"helm upgrade failed:", "context deadline", "exceeded"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected wrap %q", got)
+	}
+	got = runConsoleWrap("abcdefghijklmnop", 6, 0)
+	if strings.Join(got, "|") != "abcdef|ghijkl|mnop" {
+		t.Fatalf("expected long words to split, got %q", got)
+	}
+	got = runConsoleWrap(strings.Repeat("word ", 40), 12, 3)
+	if len(got) != 3 || !strings.HasSuffix(got[2], "…") {
+		t.Fatalf("expected 3 lines ending in an ellipsis, got %q", got)
+	}
+}
+
+func TestRunConsole_NoteWrapAndResize(t *testing.T) {
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	msg := "render failed: template: api/templates/deployment.yaml:42:17: executing \"api/templates/deployment.yaml\" at <.Values.image.tag>: nil pointer evaluating interface {}.tag"
+	var out bytes.Buffer
+	c := NewRunConsole(&out, testPlan(), "apply", RunConsoleOptions{
+		Enabled:  true,
+		Width:    120,
+		Now:      func() time.Time { return clock },
+		NoteWrap: true,
+	})
+	c.ObserveRunEvent(RunEvent{
+		TS: clock.Format(time.RFC3339Nano), RunID: "r-1", NodeID: "dev/c", Type: string(NodeFailed), Attempt: 1,
+		Message: msg, Error: &RunError{Class: "RENDER_ERROR", Message: msg},
+	})
+
+	lines := c.SnapshotLines()
+	joined := strings.Join(lines, "\n")
+	for _, l := range lines {
+		if runewidth.StringWidth(l) > 120 {
+			t.Fatalf("line wider than the console: %q", l)
+		}
+	}
+	if !strings.Contains(joined, "nil pointer evaluating") {
+		t.Fatalf("expected the full error message to be wrapped into the view:\n%s", joined)
+	}
+
+	out.Reset()
+	c.SetWidth(80)
+	if !strings.HasPrefix(out.String(), "\x1b[H\x1b[2J") {
+		t.Fatalf("expected a full redraw after resize, got %q", out.String())
+	}
+	for _, l := range c.SnapshotLines() {
+		if runewidth.StringWidth(l) > 80 {
+			t.Fatalf("line wider than the resized console: %q", l)
+		}
+	}
+
+	c.Done()
+	out.Reset()
+	c.SetWidth(100)
+	if out.Len() != 0 {
+		t.Fatalf("expected no redraw after Done, got %q", out.String())
+	}
+}
@@ -0,0 +1,131 @@
+// File: internal/stack/console_surface.go
+// Brief: In-place TTY surface that redraws only from the first changed section.
+
+package stack
+
+import (
+	"fmt"
+	"io"
+)
+
+// ConsoleSection is a named block of lines on a ConsoleSurface. Sections are the diff unit:
+// when one changes, it and everything below it is redrawn.
+type ConsoleSection struct {
+	Name  string
+	Lines []string
+}
+
+// ConsoleSurface renders successive frames of sections to a TTY in place, moving the cursor
+// back only as far as the first section that changed. RunConsole and `ktl watch` draw on it.
+// It is not safe for concurrent use; callers serialize Render calls.
+type ConsoleSurface struct {
+	out        io.Writer
+	sections   []ConsoleSection
+	totalLines int
+}
+
+// NewConsoleSurface returns a surface writing ANSI cursor sequences to out.
+func NewConsoleSurface(out io.Writer) *ConsoleSurface {
+	return &ConsoleSurface{out: out}
+}
+
+// Render draws sections, replacing the previous frame.
+func (s *ConsoleSurface) Render(sections []ConsoleSection) {
+	if s == nil || s.out == nil {
+		return
+	}
+	newTotal := consoleLineCount(sections)
+	if len(s.sections) == 0 {
+		s.writeSections(sections)
+		s.sections = cloneConsoleSections(sections)
+		s.totalLines = newTotal
+		return
+	}
+	idx := consoleDiffIndex(s.sections, sections)
+	if idx == -1 && newTotal == s.totalLines {
+		return
+	}
+	if idx == -1 {
+		idx = len(sections)
+	}
+	startLine := consoleLineCount(s.sections[:idx])
+	linesBelow := s.totalLines - startLine
+	if linesBelow > 0 {
+		fmt.Fprintf(s.out, "\x1b[%dF", linesBelow)
+	}
+	fmt.Fprint(s.out, "\x1b[J")
+	s.writeSections(sections[idx:])
+	s.sections = cloneConsoleSections(sections)
+	s.totalLines = newTotal
+}
+
+// Reset clears the screen and forgets the previous frame, so the next Render draws from the
+// top. Use it after a terminal resize: the terminal may have reflowed the old frame, so moving
+// the cursor back by its line count no longer lands on its first line.
+func (s *ConsoleSurface) Reset() {
+	if s == nil || s.out == nil {
+		return
+	}
+	if s.totalLines > 0 {
+		fmt.Fprint(s.out, "\x1b[H\x1b[2J")
+	}
+	s.sections = nil
+	s.totalLines = 0
+}
+
+// Finish leaves the cursor below the last frame so later output does not overwrite it.
+func (s *ConsoleSurface) Finish() {
+	if s == nil || s.out == nil || s.totalLines == 0 {
+		return
+	}
+	fmt.Fprint(s.out, "\x1b[K\n")
+	s.totalLines++
+}
+
+func (s *ConsoleSurface) writeSections(sections []ConsoleSection) {
+	for _, section := range sections {
+		for _, line := range section.Lines {
+			fmt.Fprintf(s.out, "%s\x1b[K\n", line)
+		}
+	}
+	if len(sections) == 0 {
+		fmt.Fprint(s.out, "\x1b[K\n")
+	}
+}
+
+func consoleLineCount(sections []ConsoleSection) int {
+	total := 0
+	for _, section := range sections {
+		total += len(section.Lines)
+	}
+	return total
+}
+
+func consoleDiffIndex(oldSections, newSections []ConsoleSection) int {
+	n := min(len(oldSections), len(newSections))
+	for i := 0; i < n; i++ {
+		if oldSections[i].Name != newSections[i].Name {
+			return i
+		}
+		if len(oldSections[i].Lines) != len(newSections[i].Lines) {
+			return i
+		}
+		for j := range oldSections[i].Lines {
+			if oldSections[i].Lines[j] != newSections[i].Lines[j] {
+				return i
+			}
+		}
+	}
+	if len(oldSections) != len(newSections) {
+		return n
+	}
+	return -1
+}
+
+func cloneConsoleSections(sections []ConsoleSection) []ConsoleSection {
+	out := make([]ConsoleSection, 0, len(sections))
+	for _, section := range sections {
+		out = append(out, ConsoleSection{Name: section.Name, Lines: append([]string(nil), section.Lines...)})
+	}
+	return out
+}
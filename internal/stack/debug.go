@@ -0,0 +1,149 @@
+// File: internal/stack/debug.go
+// Brief: Reconstruction of a recorded node's inputs and failure context for `ktl stack debug`.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NodeDebug is what a recorded run knows about one node: the frozen plan inputs, how the node
+// ended, and the Helm log tail and diff captured for its last attempt.
+type NodeDebug struct {
+	RootDir string
+	RunID   string
+	Command string
+	// Plan is the run's frozen plan; Node is one of its nodes.
+	Plan *Plan
+	Node *ResolvedRelease
+
+	Status  string
+	Attempt int
+	// FailedAt is the timestamp of the node's last NODE_FAILED event, if any.
+	FailedAt string
+	Error    *RunError
+
+	// HookEnv holds the KTL_* (and kubeconfig) variables hooks of this node ran with.
+	HookEnv []string
+	// HelmLogs is the tail of HELM_LOG lines recorded for the last attempt.
+	HelmLogs []string
+	// Diff is the NODE_DIFF recorded for the last attempt (only stored with --helm-logs).
+	Diff string
+	// Drift lists input changes between the recorded run and the stack on disk.
+	Drift []string
+}
+
+// LoadNodeDebug reads a node's recorded inputs and failure context from the run's state.
+// logTail caps HelmLogs (0 keeps every line).
+func LoadNodeDebug(root, runID, nodeID string, logTail int) (*NodeDebug, error) {
+	run, err := LoadRun(root, runID)
+	if err != nil {
+		return nil, err
+	}
+	node := findPlanNode(run.Plan, nodeID)
+	if node == nil {
+		return nil, fmt.Errorf("run %s has no node %q", run.RunID, nodeID)
+	}
+
+	s, err := openStackStateStore(run.RootDir, true)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	events, err := s.ListEvents(context.Background(), run.RunID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &NodeDebug{
+		RootDir: run.RootDir,
+		RunID:   run.RunID,
+		Plan:    run.Plan,
+		Node:    node,
+		Status:  run.StatusByID[node.ID],
+		Attempt: run.AttemptByID[node.ID],
+	}
+	d.collectEvents(events, logTail)
+	d.HookEnv = debugHookEnv(run.Plan, run.RunID, d.Command, node)
+
+	drift, err := DriftReport(&Plan{StackRoot: run.RootDir, Nodes: []*ResolvedRelease{node}})
+	if err != nil {
+		d.Drift = []string{fmt.Sprintf("%s inputs could not be recomputed: %v", node.ID, err)}
+	} else {
+		d.Drift = drift
+	}
+	return d, nil
+}
+
+func (d *NodeDebug) collectEvents(events []RunEvent, logTail int) {
+	for _, ev := range events {
+		if ev.Type == string(RunStarted) {
+			d.Command = fieldString(ev.Fields, "command")
+		}
+		if ev.NodeID != d.Node.ID {
+			continue
+		}
+		if d.Attempt > 0 && ev.Attempt > 0 && ev.Attempt != d.Attempt {
+			continue
+		}
+		switch RunEventType(ev.Type) {
+		case NodeFailed:
+			d.FailedAt = ev.TS
+			d.Error = ev.Error
+			if d.Error == nil && strings.TrimSpace(ev.Message) != "" {
+				d.Error = &RunError{Message: strings.TrimSpace(ev.Message)}
+			}
+		case HelmLog:
+			for _, line := range strings.Split(ev.Message, "\n") {
+				if strings.TrimSpace(line) != "" {
+					d.HelmLogs = append(d.HelmLogs, strings.TrimRight(line, "\r\t "))
+				}
+			}
+		case NodeDiff:
+			d.Diff = ev.Message
+		}
+	}
+	if logTail > 0 && len(d.HelmLogs) > logTail {
+		d.HelmLogs = d.HelmLogs[len(d.HelmLogs)-logTail:]
+	}
+}
+
+func findPlanNode(p *Plan, id string) *ResolvedRelease {
+	if p == nil {
+		return nil
+	}
+	id = strings.TrimSpace(id)
+	for _, n := range p.Nodes {
+		if n.ID == id {
+			return n
+		}
+	}
+	// Accept a bare release name when it is unambiguous.
+	var match *ResolvedRelease
+	for _, n := range p.Nodes {
+		if n.Name == id {
+			if match != nil {
+				return nil
+			}
+			match = n
+		}
+	}
+	return match
+}
+
+// debugHookEnv returns the variables buildHookEnv adds for a node hook, without the inherited
+// process environment or per-hook script env.
+func debugHookEnv(p *Plan, runID, command string, node *ResolvedRelease) []string {
+	hc := hookRunContext{
+		run:     &runState{RunID: runID},
+		opts:    RunOptions{Command: command, Plan: p},
+		node:    &runNode{ResolvedRelease: node},
+		baseDir: filepath.Clean(node.Dir),
+	}
+	env := buildHookEnv(hc, HookSpec{})
+	return env[len(os.Environ()):]
+}
@@ -0,0 +1,83 @@
+package stack
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadNodeDebug_CollectsLastAttempt(t *testing.T) {
+	root := t.TempDir()
+	ctx := context.Background()
+	s, err := openStackStateStore(root, false)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	p := &Plan{
+		StackRoot: root,
+		StackName: "demo",
+		Nodes: []*ResolvedRelease{
+			{ID: "c1/ns/api", Name: "api", Dir: filepath.Join(root, "api"), Cluster: ClusterTarget{Name: "c1"}, Namespace: "ns", Chart: "./chart", Set: map[string]string{"image.tag": "v2"}},
+			{ID: "c1/ns/db", Name: "db", Cluster: ClusterTarget{Name: "c1"}, Namespace: "ns"},
+		},
+	}
+	r := &runState{RunID: "run-1", Plan: p, Command: "apply", Nodes: wrapRunNodes(p.Nodes), Concurrency: 1, FailMode: "fail-fast"}
+	if err := s.CreateRun(ctx, r, p); err != nil {
+		t.Fatalf("CreateRun: %v", err)
+	}
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []RunEvent{
+		{Type: string(RunStarted), Fields: map[string]any{"command": "apply"}},
+		{NodeID: "c1/ns/api", Type: string(NodeRunning), Attempt: 1},
+		{NodeID: "c1/ns/api", Type: string(HelmLog), Attempt: 1, Message: "first attempt log"},
+		{NodeID: "c1/ns/api", Type: string(NodeFailed), Attempt: 1, Error: &RunError{Class: "HELM_ERROR", Message: "old failure"}},
+		{NodeID: "c1/ns/api", Type: string(NodeRunning), Attempt: 2},
+		{NodeID: "c1/ns/api", Type: string(NodeDiff), Attempt: 2, Message: "+ replicas: 3"},
+		{NodeID: "c1/ns/db", Type: string(HelmLog), Attempt: 2, Message: "other node"},
+		{NodeID: "c1/ns/api", Type: string(HelmLog), Attempt: 2, Message: "line a\nline b"},
+		{NodeID: "c1/ns/api", Type: string(HelmLog), Attempt: 2, Message: "line c"},
+		{NodeID: "c1/ns/api", Type: string(NodeFailed), Attempt: 2, Error: &RunError{Class: "TIMEOUT", Message: "timed out waiting"}},
+	}
+	for i, ev := range events {
+		ev.RunID = "run-1"
+		ev.Seq = int64(i + 1)
+		ev.TS = t0.Add(time.Duration(i) * time.Second).Format(time.RFC3339Nano)
+		if err := s.AppendEvent(ctx, "run-1", ev); err != nil {
+			t.Fatalf("AppendEvent: %v", err)
+		}
+	}
+	_ = s.Close()
+
+	d, err := LoadNodeDebug(root, "run-1", "api", 2)
+	if err != nil {
+		t.Fatalf("LoadNodeDebug: %v", err)
+	}
+	if d.Node.ID != "c1/ns/api" || d.Command != "apply" || d.Attempt != 2 || d.Status != "failed" {
+		t.Fatalf("unexpected node summary: id=%s command=%s attempt=%d status=%s", d.Node.ID, d.Command, d.Attempt, d.Status)
+	}
+	if d.Error == nil || d.Error.Class != "TIMEOUT" || d.FailedAt != t0.Add(9*time.Second).Format(time.RFC3339Nano) {
+		t.Fatalf("expected the last attempt's failure, got %+v at %s", d.Error, d.FailedAt)
+	}
+	if strings.Join(d.HelmLogs, "|") != "line b|line c" {
+		t.Fatalf("unexpected helm log tail %q", d.HelmLogs)
+	}
+	if d.Diff != "+ replicas: 3" {
+		t.Fatalf("unexpected diff %q", d.Diff)
+	}
+	env := strings.Join(d.HookEnv, "\n")
+	for _, want := range []string{"KTL_STACK_RUN_ID=run-1", "KTL_STACK_COMMAND=apply", "KTL_RELEASE_ID=c1/ns/api", "KTL_CLUSTER_NAME=c1"} {
+		if !strings.Contains(env, want) {
+			t.Fatalf("hook env missing %s:\n%s", want, env)
+		}
+	}
+
+	if _, err := LoadNodeDebug(root, "run-1", "nope", 0); err == nil {
+		t.Fatalf("expected an error for an unknown node")
+	}
+	fp := FilterByNodeIDs(p, []string{"c1/ns/api"})
+	if len(fp.Nodes) != 1 || fp.ByID["c1/ns/api"] == nil {
+		t.Fatalf("unexpected filtered plan %+v", fp.Nodes)
+	}
+}
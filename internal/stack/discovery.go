@@ -127,8 +127,8 @@ func readStackFile(path string) (*StackFile, error) {
 		if strings.TrimSpace(sf.Releases[i].Name) == "" {
 			return nil, fmt.Errorf("%s: releases[%d].name is required", path, i)
 		}
-		if strings.TrimSpace(sf.Releases[i].Chart) == "" {
-			return nil, fmt.Errorf("%s: releases[%d].chart is required", path, i)
+		if msg := nodeSourceProblem(sf.Releases[i].Type, sf.Releases[i].Chart, sf.Releases[i].Manifests, sf.Releases[i].Task); msg != "" {
+			return nil, fmt.Errorf("%s: releases[%d].%s", path, i, msg)
 		}
 	}
 	return &sf, nil
@@ -152,8 +152,8 @@ func readReleaseFile(path string) (*ReleaseFile, error) {
 	if strings.TrimSpace(rf.Name) == "" {
 		return nil, errors.New(path + ": name is required")
 	}
-	if strings.TrimSpace(rf.Chart) == "" {
-		return nil, errors.New(path + ": chart is required")
+	if msg := nodeSourceProblem(rf.Type, rf.Chart, rf.Manifests, rf.Task); msg != "" {
+		return nil, errors.New(path + ": " + msg)
 	}
 	return &rf, nil
 }
@@ -0,0 +1,281 @@
+// File: internal/stack/env_diff.go
+// Brief: Comparing a stack compiled for two environments (profile + overlay sets).
+
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+// EnvSide is one environment of an env diff: a profile plus overlay files, compiled against
+// the same stack.
+type EnvSide struct {
+	Label    string   `json:"label"`
+	Profile  string   `json:"profile,omitempty"`
+	Overlays []string `json:"overlays,omitempty"`
+}
+
+func (s EnvSide) String() string {
+	var parts []string
+	if s.Profile != "" && s.Profile != s.Label {
+		parts = append(parts, "profile "+s.Profile)
+	}
+	if len(s.Overlays) > 0 {
+		parts = append(parts, "overlays "+strings.Join(s.Overlays, ", "))
+	}
+	if len(parts) == 0 {
+		return s.Label
+	}
+	return fmt.Sprintf("%s (%s)", s.Label, strings.Join(parts, "; "))
+}
+
+// Release statuses in an EnvDiff.
+const (
+	EnvDiffOnlyLeft  = "only-left"
+	EnvDiffOnlyRight = "only-right"
+	EnvDiffChanged   = "changed"
+)
+
+// Value changes in an EnvDiff.
+const (
+	EnvValueAdded   = "added"
+	EnvValueRemoved = "removed"
+	EnvValueChanged = "changed"
+)
+
+// EnvDiff lists what differs between two compiled environments of one stack.
+type EnvDiff struct {
+	APIVersion string           `json:"apiVersion"`
+	StackRoot  string           `json:"stackRoot"`
+	StackName  string           `json:"stackName,omitempty"`
+	Left       EnvSide          `json:"left"`
+	Right      EnvSide          `json:"right"`
+	Releases   []ReleaseEnvDiff `json:"releases"`
+	Unchanged  int              `json:"unchanged"`
+}
+
+// ReleaseEnvDiff is one release that is missing on a side or differs between the sides.
+type ReleaseEnvDiff struct {
+	// Release is the release name, or name@cluster when the name is deployed to several clusters.
+	Release     string         `json:"release"`
+	Status      string         `json:"status"`
+	Fields      []EnvFieldDiff `json:"fields,omitempty"`
+	Values      []EnvValueDiff `json:"values,omitempty"`
+	ValuesError string         `json:"valuesError,omitempty"`
+}
+
+// EnvFieldDiff is a release setting (cluster, namespace, chart, ...) with different values.
+type EnvFieldDiff struct {
+	Field string `json:"field"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+// EnvValueDiff is a Helm values key (dotted, list items as [i]) that differs. Left and Right
+// are only filled in when EnvDiffOptions.ShowValues is set.
+type EnvValueDiff struct {
+	Key    string `json:"key"`
+	Change string `json:"change"`
+	Left   string `json:"left,omitempty"`
+	Right  string `json:"right,omitempty"`
+}
+
+// EnvDiffOptions controls DiffEnvironments.
+type EnvDiffOptions struct {
+	// ShowValues includes the values themselves, not just the differing keys. Values files
+	// often carry credentials, so this is off by default.
+	ShowValues bool
+}
+
+// DiffEnvironments compiles u for both sides and compares the resulting releases: presence,
+// cluster, namespace, type, chart, chart version, tags, needs, and the merged Helm values
+// (values files plus set) of each release.
+func DiffEnvironments(u *Universe, left, right EnvSide, opts EnvDiffOptions) (*EnvDiff, error) {
+	lp, err := Compile(u, CompileOptions{Profile: left.Profile, Overlays: left.Overlays})
+	if err != nil {
+		return nil, fmt.Errorf("compile %s: %w", left.Label, err)
+	}
+	rp, err := Compile(u, CompileOptions{Profile: right.Profile, Overlays: right.Overlays})
+	if err != nil {
+		return nil, fmt.Errorf("compile %s: %w", right.Label, err)
+	}
+	out := &EnvDiff{
+		APIVersion: "ktl.dev/env-diff/v1",
+		StackRoot:  lp.StackRoot,
+		StackName:  lp.StackName,
+		Left:       left,
+		Right:      right,
+	}
+	lnodes, rnodes := envDiffKeys(lp.Nodes), envDiffKeys(rp.Nodes)
+	keys := make([]string, 0, len(lnodes)+len(rnodes))
+	for k := range lnodes {
+		keys = append(keys, k)
+	}
+	for k := range rnodes {
+		if _, ok := lnodes[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		l, r := lnodes[key], rnodes[key]
+		switch {
+		case r == nil:
+			out.Releases = append(out.Releases, ReleaseEnvDiff{Release: key, Status: EnvDiffOnlyLeft})
+		case l == nil:
+			out.Releases = append(out.Releases, ReleaseEnvDiff{Release: key, Status: EnvDiffOnlyRight})
+		default:
+			d := diffReleaseEnv(key, l, r, opts)
+			if len(d.Fields) == 0 && len(d.Values) == 0 && d.ValuesError == "" {
+				out.Unchanged++
+				continue
+			}
+			out.Releases = append(out.Releases, d)
+		}
+	}
+	return out, nil
+}
+
+// envDiffKeys indexes nodes by release name, falling back to name@cluster for names that are
+// deployed to more than one cluster.
+func envDiffKeys(nodes []*ResolvedRelease) map[string]*ResolvedRelease {
+	count := map[string]int{}
+	for _, n := range nodes {
+		count[n.Name]++
+	}
+	out := make(map[string]*ResolvedRelease, len(nodes))
+	for _, n := range nodes {
+		key := n.Name
+		if count[n.Name] > 1 {
+			key = n.Name + "@" + n.Cluster.Name
+		}
+		out[key] = n
+	}
+	return out
+}
+
+func diffReleaseEnv(key string, l, r *ResolvedRelease, opts EnvDiffOptions) ReleaseEnvDiff {
+	d := ReleaseEnvDiff{Release: key, Status: EnvDiffChanged}
+	fields := []struct {
+		name        string
+		left, right string
+	}{
+		{"cluster", l.Cluster.Name, r.Cluster.Name},
+		{"namespace", l.Namespace, r.Namespace},
+		{"type", l.Type, r.Type},
+		{"chart", l.Chart, r.Chart},
+		{"chartVersion", l.ChartVersion, r.ChartVersion},
+		{"tags", strings.Join(l.Tags, ","), strings.Join(r.Tags, ",")},
+		{"needs", strings.Join(l.Needs, ","), strings.Join(r.Needs, ",")},
+	}
+	for _, f := range fields {
+		if f.left != f.right {
+			d.Fields = append(d.Fields, EnvFieldDiff{Field: f.name, Left: f.left, Right: f.right})
+		}
+	}
+	lv, lerr := mergedReleaseValues(l)
+	rv, rerr := mergedReleaseValues(r)
+	if lerr != nil || rerr != nil {
+		errs := make([]string, 0, 2)
+		for _, err := range []error{lerr, rerr} {
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		d.ValuesError = strings.Join(errs, "; ")
+		return d
+	}
+	d.Values = diffFlatValues(lv, rv, opts.ShowValues)
+	return d
+}
+
+// mergedReleaseValues merges a release's values files and set entries the way helm does and
+// flattens the result to dotted keys.
+func mergedReleaseValues(n *ResolvedRelease) (map[string]string, error) {
+	files, err := releaseValuesFiles(context.Background(), n, nil)
+	if err != nil {
+		return nil, err
+	}
+	opts := values.Options{ValueFiles: files, Values: flattenSet(n.Set)}
+	merged, err := opts.MergeValues(getter.Providers{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: load values: %w", n.Name, err)
+	}
+	return flattenEnvValues(merged), nil
+}
+
+// DiffValues compares two Helm values trees by dotted leaf key (list items as [i]). Left and
+// Right are only filled in when showValues is set.
+func DiffValues(left, right map[string]any, showValues bool) []EnvValueDiff {
+	return diffFlatValues(flattenEnvValues(left), flattenEnvValues(right), showValues)
+}
+
+// flattenEnvValues maps dotted leaf paths (list items as [i]) to their JSON-encoded values.
+func flattenEnvValues(v map[string]any) map[string]string {
+	out := map[string]string{}
+	var walk func(path string, v any)
+	walk = func(path string, v any) {
+		switch typed := v.(type) {
+		case map[string]any:
+			if len(typed) == 0 && path != "" {
+				out[path] = "{}"
+				return
+			}
+			for k, child := range typed {
+				next := k
+				if path != "" {
+					next = path + "." + k
+				}
+				walk(next, child)
+			}
+		case []any:
+			if len(typed) == 0 {
+				out[path] = "[]"
+				return
+			}
+			for i, child := range typed {
+				walk(fmt.Sprintf("%s[%d]", path, i), child)
+			}
+		default:
+			raw, err := json.Marshal(typed)
+			if err != nil {
+				raw = []byte(fmt.Sprint(typed))
+			}
+			out[path] = string(raw)
+		}
+	}
+	walk("", v)
+	return out
+}
+
+func diffFlatValues(l, r map[string]string, showValues bool) []EnvValueDiff {
+	var out []EnvValueDiff
+	for k, lv := range l {
+		rv, ok := r[k]
+		switch {
+		case !ok:
+			out = append(out, EnvValueDiff{Key: k, Change: EnvValueRemoved, Left: lv})
+		case rv != lv:
+			out = append(out, EnvValueDiff{Key: k, Change: EnvValueChanged, Left: lv, Right: rv})
+		}
+	}
+	for k, rv := range r {
+		if _, ok := l[k]; !ok {
+			out = append(out, EnvValueDiff{Key: k, Change: EnvValueAdded, Right: rv})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	if !showValues {
+		for i := range out {
+			out[i].Left, out[i].Right = "", ""
+		}
+	}
+	return out
+}
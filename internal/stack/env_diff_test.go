@@ -0,0 +1,76 @@
+package stack
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffEnvironments(t *testing.T) {
+	root := writeOverlayStack(t)
+	writeFile(t, filepath.Join(root, "values", "common.yaml"), "image:\n  tag: \"1.0\"\nreplicas: 1\n")
+	writeFile(t, filepath.Join(root, "values", "prod-db.yaml"), "image:\n  tag: \"1.1\"\nbackup:\n  enabled: true\n")
+	writeFile(t, filepath.Join(root, "envs", "staging.yaml"), `
+releases:
+  cache:
+    set: { debug: "true" }
+`)
+	writeFile(t, filepath.Join(root, "envs", "prod.yaml"), `
+releases:
+  db:
+    chartVersion: 2.1.0
+    values: [../values/prod-db.yaml]
+  cache:
+    enabled: false
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	staging := EnvSide{Label: "staging", Overlays: []string{filepath.Join(root, "envs", "staging.yaml")}}
+	prod := EnvSide{Label: "prod", Overlays: []string{filepath.Join(root, "envs", "prod.yaml")}}
+
+	d, err := DiffEnvironments(u, staging, prod, EnvDiffOptions{})
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	// Disabling cache in prod also drops it from api@c1's needs; api@c2 is identical.
+	if d.Unchanged != 1 || len(d.Releases) != 3 {
+		t.Fatalf("expected api@c1, cache, and db to differ, got %+v", d)
+	}
+	api, cache, db := d.Releases[0], d.Releases[1], d.Releases[2]
+	if api.Release != "api@c1" || len(api.Fields) != 1 || api.Fields[0].Field != "needs" {
+		t.Fatalf("unexpected api diff %+v", api)
+	}
+	if cache.Release != "cache" || cache.Status != EnvDiffOnlyLeft {
+		t.Fatalf("unexpected cache diff %+v", cache)
+	}
+	if db.Release != "db" || len(db.Fields) != 1 || db.Fields[0].Field != "chartVersion" || db.Fields[0].Right != "2.1.0" {
+		t.Fatalf("unexpected db fields %+v", db.Fields)
+	}
+	if len(db.Values) != 2 || db.Values[0].Key != "backup.enabled" || db.Values[0].Change != EnvValueAdded || db.Values[1].Key != "image.tag" || db.Values[1].Right != "" {
+		t.Fatalf("expected value keys without values, got %+v", db.Values)
+	}
+
+	d, err = DiffEnvironments(u, staging, prod, EnvDiffOptions{ShowValues: true})
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := PrintEnvDiff(&buf, d); err != nil {
+		t.Fatalf("print: %v", err)
+	}
+	for _, want := range []string{"- cache  only in staging", "chartVersion: 1.0.0 → 2.1.0", `~ values.image.tag: "1.0" → "1.1"`, "3 release(s) differ, 1 unchanged"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+	buf.Reset()
+	if err := PrintEnvDiffHTML(&buf, d); err != nil {
+		t.Fatalf("print html: %v", err)
+	}
+	if !strings.Contains(buf.String(), "<code>backup.enabled</code>") {
+		t.Fatalf("expected values key in html output")
+	}
+}
@@ -0,0 +1,209 @@
+// File: internal/stack/error_digests.go
+// Brief: Cross-run error digest counts and known-flaky failure rules.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// FlakyRule is a resolved runner.flaky entry.
+type FlakyRule struct {
+	Digest string `json:"digest,omitempty"`
+	Match  string `json:"match,omitempty"`
+	Link   string `json:"link,omitempty"`
+	Retry  bool   `json:"retry"`
+
+	re *regexp.Regexp
+}
+
+// Matches reports whether err is covered by the rule. Digest matches on prefix, so the short
+// form printed by `ktl stack errors` works.
+func (r FlakyRule) Matches(err *RunError) bool {
+	if err == nil {
+		return false
+	}
+	if d := strings.TrimSpace(r.Digest); d != "" {
+		return strings.TrimSpace(err.Digest) != "" && strings.HasPrefix(err.Digest, d)
+	}
+	if m := strings.TrimSpace(r.Match); m != "" {
+		re := r.re
+		if re == nil {
+			// Rules loaded from a sealed plan carry only the pattern.
+			re, _ = regexp.Compile(m)
+		}
+		return re != nil && re.MatchString(err.Message)
+	}
+	return false
+}
+
+func (r FlakyRule) annotation() string {
+	if strings.TrimSpace(r.Link) == "" {
+		return "known flaky"
+	}
+	return "known flaky, tracked in " + strings.TrimSpace(r.Link)
+}
+
+func resolveFlakyRules(cfg []RunnerFlaky) ([]FlakyRule, error) {
+	out := make([]FlakyRule, 0, len(cfg))
+	for i, f := range cfg {
+		rule := FlakyRule{
+			Digest: strings.TrimSpace(f.Digest),
+			Match:  strings.TrimSpace(f.Match),
+			Link:   strings.TrimSpace(f.Link),
+			Retry:  f.Retry == nil || *f.Retry,
+		}
+		if (rule.Digest == "") == (rule.Match == "") {
+			return nil, fmt.Errorf("runner.flaky[%d]: set exactly one of digest or match", i)
+		}
+		if rule.Digest != "" && !strings.HasPrefix(rule.Digest, "sha256:") {
+			rule.Digest = "sha256:" + rule.Digest
+		}
+		if rule.Match != "" {
+			re, err := regexp.Compile(rule.Match)
+			if err != nil {
+				return nil, fmt.Errorf("runner.flaky[%d].match: %w", i, err)
+			}
+			rule.re = re
+		}
+		out = append(out, rule)
+	}
+	return out, nil
+}
+
+func matchFlakyRule(rules []FlakyRule, err *RunError) (FlakyRule, bool) {
+	for _, r := range rules {
+		if r.Matches(err) {
+			return r, true
+		}
+	}
+	return FlakyRule{}, false
+}
+
+// ErrorDigestEntry is one distinct node failure and how often it has been seen across runs.
+type ErrorDigestEntry struct {
+	Digest     string `json:"digest"`
+	Class      string `json:"class"`
+	Message    string `json:"message"`
+	Count      int64  `json:"count"`
+	FirstSeen  string `json:"firstSeen"`
+	LastSeen   string `json:"lastSeen"`
+	LastRunID  string `json:"lastRunId"`
+	LastNodeID string `json:"lastNodeId"`
+	// Flaky is the matching runner.flaky link (or "known flaky") when annotated by the caller.
+	Flaky string `json:"flaky,omitempty"`
+}
+
+func (s *stackStateStore) recordErrorDigest(ctx context.Context, runID, nodeID string, tsNS int64, err *RunError) error {
+	if err == nil || strings.TrimSpace(err.Digest) == "" {
+		return nil
+	}
+	_, execErr := s.db.ExecContext(ctx, `
+INSERT INTO ktl_stack_error_digests (digest, class, message, count, first_seen_ns, last_seen_ns, last_run_id, last_node_id)
+VALUES (?, ?, ?, 1, ?, ?, ?, ?)
+ON CONFLICT(digest) DO UPDATE SET
+  count = count + 1,
+  last_seen_ns = excluded.last_seen_ns,
+  last_run_id = excluded.last_run_id,
+  last_node_id = excluded.last_node_id
+`, strings.TrimSpace(err.Digest), strings.TrimSpace(err.Class), strings.TrimSpace(err.Message), tsNS, tsNS, runID, nodeID)
+	return execErr
+}
+
+func (s *stackStateStore) ListErrorDigests(ctx context.Context, limit int) ([]ErrorDigestEntry, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	// Read-only stores written before digests were tracked have no table yet.
+	if cols, err := s.tableColumns(ctx, "ktl_stack_error_digests"); err != nil || len(cols) == 0 {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `
+SELECT digest, class, message, count, first_seen_ns, last_seen_ns, last_run_id, last_node_id
+FROM ktl_stack_error_digests
+ORDER BY count DESC, last_seen_ns DESC
+LIMIT ?
+`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ErrorDigestEntry
+	for rows.Next() {
+		var e ErrorDigestEntry
+		var firstNS, lastNS int64
+		if err := rows.Scan(&e.Digest, &e.Class, &e.Message, &e.Count, &firstNS, &lastNS, &e.LastRunID, &e.LastNodeID); err != nil {
+			return nil, err
+		}
+		e.FirstSeen = time.Unix(0, firstNS).UTC().Format(time.RFC3339)
+		e.LastSeen = time.Unix(0, lastNS).UTC().Format(time.RFC3339)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// ListErrorDigests returns the most frequent node failures recorded in the stack state store.
+// Entries covered by rules are annotated with the rule's link.
+func ListErrorDigests(root string, limit int, rules []FlakyRule) ([]ErrorDigestEntry, error) {
+	root = strings.TrimSpace(root)
+	if root == "" {
+		root = "."
+	}
+	if _, err := os.Stat(filepath.Join(root, stackStateSQLiteRelPath)); err != nil {
+		return nil, fmt.Errorf("no runs found (expected %s)", filepath.Join(root, stackStateSQLiteRelPath))
+	}
+	s, err := openStackStateStore(root, true)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	entries, err := s.ListErrorDigests(context.Background(), limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if r, ok := matchFlakyRule(rules, &RunError{Digest: entries[i].Digest, Message: entries[i].Message}); ok {
+			entries[i].Flaky = r.Link
+			if entries[i].Flaky == "" {
+				entries[i].Flaky = "known flaky"
+			}
+		}
+	}
+	return entries, nil
+}
+
+// ShortErrorDigest is the digest form used in tables and accepted by runner.flaky[].digest.
+func ShortErrorDigest(d string) string {
+	d = strings.TrimPrefix(strings.TrimSpace(d), "sha256:")
+	if len(d) > 16 {
+		d = d[:16]
+	}
+	return d
+}
+
+func PrintErrorDigestsTable(w io.Writer, entries []ErrorDigestEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+
+	fmt.Fprintln(tw, "DIGEST\tCLASS\tCOUNT\tLAST SEEN\tLAST NODE\tFLAKY\tMESSAGE")
+	for _, e := range entries {
+		msg := strings.Join(strings.Fields(e.Message), " ")
+		if len(msg) > 80 {
+			msg = msg[:79] + "…"
+		}
+		flaky := e.Flaky
+		if flaky == "" {
+			flaky = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n", ShortErrorDigest(e.Digest), e.Class, e.Count, e.LastSeen, e.LastNodeID, flaky, msg)
+	}
+	return nil
+}
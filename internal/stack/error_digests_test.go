@@ -0,0 +1,103 @@
+package stack
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestErrorDigests_CountAcrossRunsAndFlakyAnnotation(t *testing.T) {
+	root := t.TempDir()
+	ctx := context.Background()
+	s, err := openStackStateStore(root, false)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	p := &Plan{
+		StackRoot: root,
+		StackName: "demo",
+		Nodes: []*ResolvedRelease{
+			{ID: "c1/ns/app", Name: "app", Cluster: ClusterTarget{Name: "c1"}, Namespace: "ns"},
+		},
+	}
+	flake := &RunError{Class: "OTHER", Message: "webhook x509: certificate signed by unknown authority"}
+	flake.Digest = computeRunErrorDigest(flake.Class, flake.Message)
+	other := &RunError{Class: "TIMEOUT", Message: "timed out"}
+	other.Digest = computeRunErrorDigest(other.Class, other.Message)
+
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, runID := range []string{"run-1", "run-2"} {
+		r := &runState{RunID: runID, Plan: p, Command: "apply", Nodes: wrapRunNodes(p.Nodes), Concurrency: 1, FailMode: "fail-fast"}
+		if err := s.CreateRun(ctx, r, p); err != nil {
+			t.Fatalf("CreateRun: %v", err)
+		}
+		ev := RunEvent{TS: t0.Add(time.Duration(i) * time.Hour).Format(time.RFC3339Nano), RunID: runID, NodeID: "c1/ns/app", Type: string(NodeFailed), Attempt: 1, Error: flake}
+		if err := s.AppendEvent(ctx, runID, ev); err != nil {
+			t.Fatalf("AppendEvent: %v", err)
+		}
+	}
+	if err := s.AppendEvent(ctx, "run-2", RunEvent{TS: t0.Format(time.RFC3339Nano), RunID: "run-2", NodeID: "c1/ns/app", Type: string(NodeFailed), Attempt: 2, Error: other}); err != nil {
+		t.Fatalf("AppendEvent: %v", err)
+	}
+	_ = s.Close()
+
+	rules, err := resolveFlakyRules([]RunnerFlaky{{Digest: ShortErrorDigest(flake.Digest), Link: "https://issues.example/42"}})
+	if err != nil {
+		t.Fatalf("resolveFlakyRules: %v", err)
+	}
+	entries, err := ListErrorDigests(root, 10, rules)
+	if err != nil {
+		t.Fatalf("ListErrorDigests: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries=%+v", entries)
+	}
+	if entries[0].Digest != flake.Digest || entries[0].Count != 2 || entries[0].LastRunID != "run-2" || entries[0].Flaky != "https://issues.example/42" {
+		t.Fatalf("unexpected top entry: %+v", entries[0])
+	}
+	if entries[1].Count != 1 || entries[1].Flaky != "" {
+		t.Fatalf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestResolveRunnerConfig_Flaky(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+runner:
+  flaky:
+    - match: "x509: certificate"
+      link: https://issues.example/1
+profiles:
+  ci:
+    runner:
+      flaky:
+        - digest: 0123abcd
+          retry: false
+releases: []
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	ci, err := ResolveRunnerConfig(u, "ci")
+	if err != nil {
+		t.Fatalf("ResolveRunnerConfig: %v", err)
+	}
+	if len(ci.Flaky) != 2 || !ci.Flaky[0].Retry || ci.Flaky[1].Retry || ci.Flaky[1].Digest != "sha256:0123abcd" {
+		t.Fatalf("flaky=%+v", ci.Flaky)
+	}
+	rule, ok := matchFlakyRule(ci.Flaky, &RunError{Message: "Internal error: x509: certificate has expired"})
+	if !ok || rule.annotation() != "known flaky, tracked in https://issues.example/1" {
+		t.Fatalf("match=%+v ok=%v", rule, ok)
+	}
+	if _, err := resolveFlakyRules([]RunnerFlaky{{Match: "("}}); err == nil {
+		t.Fatalf("expected invalid regexp to fail")
+	}
+	if _, err := resolveFlakyRules([]RunnerFlaky{{Link: "x"}}); err == nil {
+		t.Fatalf("expected rule without digest or match to fail")
+	}
+}
@@ -0,0 +1,168 @@
+// File: internal/stack/events_file.go
+// Brief: JSONL run event sink (--events-file) and replay of recorded runs.
+
+package stack
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventsFile appends every observed RunEvent to a JSON Lines file, one event per line. Write
+// errors are remembered and returned by Close so a full disk does not interrupt the run.
+type EventsFile struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+	err error
+}
+
+// OpenEventsFile opens path for appending, creating it when needed.
+func OpenEventsFile(path string) (*EventsFile, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetEscapeHTML(false)
+	return &EventsFile{f: f, enc: enc}, nil
+}
+
+func (e *EventsFile) ObserveRunEvent(ev RunEvent) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return
+	}
+	e.err = e.enc.Encode(ev)
+}
+
+// Close flushes the file and returns the first write error, if any.
+func (e *EventsFile) Close() error {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	closeErr := e.f.Close()
+	if e.err != nil {
+		return e.err
+	}
+	return closeErr
+}
+
+// ReadEventsFile decodes a JSON Lines file written by EventsFile (or `ktl stack apply --output
+// json`). Blank lines are skipped; a malformed line is reported with its line number.
+func ReadEventsFile(r io.Reader) ([]RunEvent, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var events []RunEvent
+	for line := 1; sc.Scan(); line++ {
+		raw := strings.TrimSpace(sc.Text())
+		if raw == "" {
+			continue
+		}
+		var ev RunEvent
+		if err := json.Unmarshal([]byte(raw), &ev); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		events = append(events, ev)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ReplayOptions configures ReplayEvents.
+type ReplayOptions struct {
+	// Speed multiplies the recorded pace; 0 replays without waiting.
+	Speed float64
+	// MaxGap caps a single wait between events (after Speed is applied); 0 disables the cap.
+	MaxGap time.Duration
+	// Sleep waits between events. Defaults to a context-aware time.Sleep.
+	Sleep func(ctx context.Context, d time.Duration) error
+	// Clock, when set, is advanced to each event's timestamp before the event is delivered, so
+	// elapsed times rendered by the observer match the recording.
+	Clock *ReplayClock
+}
+
+// ReplayClock is a settable clock for replays; pass its Now to RunConsoleOptions.Now.
+type ReplayClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *ReplayClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *ReplayClock) set(t time.Time) {
+	c.mu.Lock()
+	c.t = t
+	c.mu.Unlock()
+}
+
+// ReplayEvents delivers events to obs in order, waiting between them according to their
+// recorded timestamps scaled by opts.Speed. It stops early when ctx is cancelled.
+func ReplayEvents(ctx context.Context, events []RunEvent, obs RunEventObserver, opts ReplayOptions) error {
+	sleep := opts.Sleep
+	if sleep == nil {
+		sleep = func(ctx context.Context, d time.Duration) error {
+			t := time.NewTimer(d)
+			defer t.Stop()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-t.C:
+				return nil
+			}
+		}
+	}
+	var prev time.Time
+	for _, ev := range events {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ts, ok := parseRFC3339(ev.TS)
+		if ok {
+			if opts.Speed > 0 && !prev.IsZero() && ts.After(prev) {
+				wait := time.Duration(float64(ts.Sub(prev)) / opts.Speed)
+				if opts.MaxGap > 0 && wait > opts.MaxGap {
+					wait = opts.MaxGap
+				}
+				if err := sleep(ctx, wait); err != nil {
+					return err
+				}
+			}
+			prev = ts
+			if opts.Clock != nil {
+				opts.Clock.set(ts)
+			}
+		}
+		obs.ObserveRunEvent(ev)
+	}
+	return nil
+}
+
+// ReplayCommand returns the stack command (apply/delete) recorded in the RUN_STARTED event.
+func ReplayCommand(events []RunEvent) string {
+	for _, ev := range events {
+		if ev.Type == string(RunStarted) {
+			return fieldString(ev.Fields, "command")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package stack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventsFile_RoundTripAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.jsonl")
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	at := func(d time.Duration) string { return t0.Add(d).Format(time.RFC3339Nano) }
+	events := []RunEvent{
+		{TS: at(0), RunID: "r-1", Type: string(RunStarted), Fields: map[string]any{"command": "apply"}},
+		{TS: at(0), RunID: "r-1", NodeID: "dev/apps/api", Type: string(NodeMeta), Fields: map[string]any{"cluster": "dev", "namespace": "apps", "name": "api"}},
+		{TS: at(time.Second), RunID: "r-1", NodeID: "dev/apps/api", Type: string(NodeRunning), Attempt: 1},
+		{TS: at(time.Minute), RunID: "r-1", NodeID: "dev/apps/api", Type: string(NodeFailed), Attempt: 1, Error: &RunError{Class: "HELM_ERROR", Message: "boom"}},
+	}
+
+	// Two runs append to the same file.
+	for _, batch := range [][]RunEvent{events[:2], events[2:]} {
+		sink, err := OpenEventsFile(path)
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		for _, ev := range batch {
+			sink.ObserveRunEvent(ev)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	got, err := ReadEventsFile(f)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(got) != len(events) || got[3].Error == nil || got[3].Error.Class != "HELM_ERROR" {
+		t.Fatalf("unexpected events %+v", got)
+	}
+	if ReplayCommand(got) != "apply" {
+		t.Fatalf("expected the recorded command, got %q", ReplayCommand(got))
+	}
+
+	clock := &ReplayClock{}
+	console := NewRunConsole(nil, nil, ReplayCommand(got), RunConsoleOptions{Enabled: true, Width: 120, Now: clock.Now})
+	var waits []time.Duration
+	err = ReplayEvents(context.Background(), got, console, ReplayOptions{
+		Speed:  2,
+		MaxGap: 5 * time.Second,
+		Clock:  clock,
+		Sleep: func(_ context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(waits) != 2 || waits[0] != 500*time.Millisecond || waits[1] != 5*time.Second {
+		t.Fatalf("unexpected waits %v", waits)
+	}
+	if !clock.Now().Equal(t0.Add(time.Minute)) {
+		t.Fatalf("expected the clock at the last event, got %s", clock.Now())
+	}
+	snapshot := strings.Join(console.SnapshotLines(), "\n")
+	if !strings.Contains(snapshot, "dev/apps/api") || !strings.Contains(snapshot, "FAILED") {
+		t.Fatalf("expected the failed node in the replayed console:\n%s", snapshot)
+	}
+}
+
+func TestReadEventsFile_ReportsBadLine(t *testing.T) {
+	_, err := ReadEventsFile(strings.NewReader("{\"type\":\"RUN_STARTED\"}\n\nnot json\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("expected a line 3 error, got %v", err)
+	}
+}
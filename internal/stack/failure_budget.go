@@ -0,0 +1,83 @@
+// File: internal/stack/failure_budget.go
+// Brief: Failure limits (--max-failures) and failure-domain isolation for stack runs.
+
+package stack
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Failure domains: where a node failure stops scheduling.
+const (
+	// FailureDomainRun counts failures across the whole run; reaching the limit stops it.
+	FailureDomainRun = "run"
+	// FailureDomainCluster counts failures per cluster; reaching the limit stops scheduling on
+	// that cluster only while the other clusters carry on.
+	FailureDomainCluster = "cluster"
+)
+
+// ValidateFailureDomain accepts "" (the run) and the FailureDomain constants.
+func ValidateFailureDomain(domain string) error {
+	switch strings.ToLower(strings.TrimSpace(domain)) {
+	case "", FailureDomainRun, FailureDomainCluster:
+		return nil
+	default:
+		return fmt.Errorf("failure domain must be run or cluster (got %q)", domain)
+	}
+}
+
+// failureBudget counts final node failures (after retries) per failure domain.
+type failureBudget struct {
+	mu      sync.Mutex
+	domain  string
+	limit   int
+	counts  map[string]int
+	tripped map[string]bool
+}
+
+// newFailureBudget returns the budget for a run. maxFailures wins; otherwise fail-fast, and
+// cluster isolation on its own, stop a domain on its first failure. A zero limit never trips.
+func newFailureBudget(domain string, maxFailures int, failFast bool) *failureBudget {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if domain == "" {
+		domain = FailureDomainRun
+	}
+	limit := maxFailures
+	if limit <= 0 && (failFast || domain == FailureDomainCluster) {
+		limit = 1
+	}
+	return &failureBudget{domain: domain, limit: limit, counts: map[string]int{}, tripped: map[string]bool{}}
+}
+
+// record counts a failure of node and reports whether it used up its domain's budget. It
+// reports true once per domain; key is the cluster name for cluster isolation, else "".
+func (b *failureBudget) record(node *runNode) (key string, count int, tripped bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.domain == FailureDomainCluster {
+		key = strings.TrimSpace(node.Cluster.Name)
+	}
+	b.counts[key]++
+	count = b.counts[key]
+	if b.limit > 0 && count >= b.limit && !b.tripped[key] {
+		b.tripped[key] = true
+		return key, count, true
+	}
+	return key, count, false
+}
+
+func (b *failureBudget) reason(key string, count int) string {
+	if b.domain == FailureDomainCluster {
+		return fmt.Sprintf("cluster %s reached its failure limit (%d of %d)", orUnnamed(key), count, b.limit)
+	}
+	return fmt.Sprintf("run reached its failure limit (%d of %d)", count, b.limit)
+}
+
+func orUnnamed(cluster string) string {
+	if cluster == "" {
+		return "(default)"
+	}
+	return cluster
+}
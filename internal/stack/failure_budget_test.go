@@ -0,0 +1,88 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_FailureBudget(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "charts", "cm", "Chart.yaml"), "apiVersion: v2\nname: cm\nversion: 0.1.0\n")
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: regions
+defaults:
+  namespace: ns
+releases:
+  - { name: a1, chart: ./charts/cm, cluster: { name: east } }
+  - { name: a2, chart: ./charts/cm, cluster: { name: east } }
+  - { name: b1, chart: ./charts/cm, cluster: { name: west } }
+  - { name: b2, chart: ./charts/cm, cluster: { name: west } }
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	p, err := Compile(u, CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	cases := []struct {
+		name        string
+		domain      string
+		maxFailures int
+		failOn      []string
+		wantRan     string
+		// wantLimit is the cluster of the FAILURE_LIMIT_REACHED event ("" for the run); nil
+		// expects no event.
+		wantLimit *string
+	}{
+		{name: "cluster isolation", domain: FailureDomainCluster, failOn: []string{"a1"}, wantRan: "[a1 b1 b2]", wantLimit: strPtr("east")},
+		{name: "max failures across the run", maxFailures: 2, failOn: []string{"a1", "b1"}, wantRan: "[a1 a2 b1]", wantLimit: strPtr("")},
+		{name: "no limit", failOn: []string{"a1", "b1"}, wantRan: "[a1 a2 b1 b2]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			exec := &recordingExecutor{failOn: map[string]error{}}
+			for _, name := range tc.failOn {
+				exec.failOn[name] = errors.New("boom")
+			}
+			var limits []RunEvent
+			err := Run(context.Background(), RunOptions{
+				Command:       "apply",
+				Plan:          p,
+				Concurrency:   1,
+				Executor:      exec,
+				MaxFailures:   tc.maxFailures,
+				FailureDomain: tc.domain,
+				EventObservers: []RunEventObserver{RunEventObserverFunc(func(ev RunEvent) {
+					if ev.Type == string(FailureLimitReached) {
+						limits = append(limits, ev)
+					}
+				})},
+			}, ioDiscard{}, ioDiscard{})
+			if err == nil {
+				t.Fatalf("expected the run to fail")
+			}
+			if ran := fmt.Sprint(exec.calledNames()); ran != tc.wantRan {
+				t.Fatalf("ran %s, want %s", ran, tc.wantRan)
+			}
+			if tc.wantLimit == nil {
+				if len(limits) != 0 {
+					t.Fatalf("unexpected failure limit events: %+v", limits)
+				}
+				return
+			}
+			if len(limits) != 1 || limits[0].Fields["cluster"] != *tc.wantLimit {
+				t.Fatalf("failure limit events = %+v", limits)
+			}
+		})
+	}
+}
+
+func strPtr(v string) *string { return &v }
@@ -32,6 +32,7 @@ func FilterByClusters(p *Plan, clusters []string) *Plan {
 		Nodes:     nodes,
 		Runner:    p.Runner,
 		Hooks:     p.Hooks,
+		Disabled:  p.Disabled,
 		ByID:      map[string]*ResolvedRelease{},
 		ByCluster: map[string][]*ResolvedRelease{},
 	}
@@ -21,6 +21,29 @@ func FilterByNodeStatus(p *Plan, statusByID map[string]string, wantStatuses []st
 			nodes = append(nodes, n)
 		}
 	}
+	return filteredPlan(p, nodes)
+}
+
+// FilterByNodeIDs keeps only the listed nodes, treating their other needs as satisfied
+// (`ktl stack debug --rerun`).
+func FilterByNodeIDs(p *Plan, ids []string) *Plan {
+	if p == nil {
+		return nil
+	}
+	want := map[string]struct{}{}
+	for _, id := range ids {
+		want[id] = struct{}{}
+	}
+	var nodes []*ResolvedRelease
+	for _, n := range p.Nodes {
+		if _, ok := want[n.ID]; ok {
+			nodes = append(nodes, n)
+		}
+	}
+	return filteredPlan(p, nodes)
+}
+
+func filteredPlan(p *Plan, nodes []*ResolvedRelease) *Plan {
 	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
 	out := &Plan{
 		StackRoot: p.StackRoot,
@@ -61,6 +61,9 @@ func mapChangedFiles(u *Universe, p *Plan, changedFiles []string) map[string][]s
 			}
 			addOwnerDir(chartPath, n.ID)
 		}
+		if n.Manifests != nil && isExistingPath(n.Manifests.Path) {
+			addOwnerDir(n.Manifests.Path, n.ID)
+		}
 	}
 	for dir := range ownerDirs {
 		sort.Strings(ownerDirs[dir])
@@ -13,6 +13,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/netconfig"
 	"github.com/kubekattle/ktl/internal/version"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
@@ -58,8 +60,15 @@ func ComputeEffectiveInputHashWithOptions(n *ResolvedRelease, opts EffectiveInpu
 		return "", nil, err
 	}
 
-	settings := cli.New()
-	chartInput, err := digestChart(n.Chart, n.ChartVersion, settings)
+	var chartInput EffectiveChartInput
+	switch {
+	case n.IsManifests():
+		chartInput, err = digestManifests(n.Manifests)
+	case n.IsTask():
+		chartInput, err = digestTask(n.Task)
+	default:
+		chartInput, err = digestChart(n.Chart, n.ChartVersion, cli.New())
+	}
 	if err != nil {
 		return "", nil, err
 	}
@@ -185,6 +194,10 @@ func digestValues(paths []string, includeContents bool) ([]FileDigest, error) {
 	out := make([]FileDigest, 0, len(paths))
 	for _, p := range paths {
 		d := FileDigest{Path: p}
+		// A pinned remote source is identified by its pin, which is checked whenever it is fetched.
+		if _, pin := deploy.SplitValuesPin(p); pin != "" && !isLocalPath(p) {
+			d.Digest = "sha256:" + pin
+		}
 		if includeContents && isLocalPath(p) {
 			b, err := os.ReadFile(p)
 			if err != nil {
@@ -207,7 +220,11 @@ func digestChart(chartRef string, chartVersion string, settings *cli.EnvSettings
 
 	chartPath := ref
 	if !isExistingPath(ref) {
+		if err := netconfig.CheckChartRef(ref, ""); err != nil {
+			return EffectiveChartInput{}, err
+		}
 		cpo := action.ChartPathOptions{Version: v}
+		netconfig.ApplyChartPathOptions(&cpo)
 		located, err := cpo.LocateChart(ref, settings)
 		if err != nil {
 			return EffectiveChartInput{}, fmt.Errorf("locate chart %s: %w", ref, err)
@@ -318,12 +335,19 @@ func digestApply(n *ResolvedRelease) EffectiveApplyInput {
 	write(fmt.Sprintf("wait=%t", wait))
 	write(fmt.Sprintf("createNamespace=%t", createNamespace))
 	write("timeout=" + timeout.String())
+	images := ""
+	if n.Apply.Images != nil {
+		raw, _ := json.Marshal(n.Apply.Images)
+		images = string(raw)
+		write("images=" + images)
+	}
 
 	return EffectiveApplyInput{
 		Atomic:          atomic,
 		Wait:            wait,
 		CreateNamespace: createNamespace,
 		Timeout:         timeout.String(),
+		Images:          images,
 		Digest:          "sha256:" + hex.EncodeToString(h.Sum(nil)),
 	}
 }
@@ -406,6 +430,13 @@ func digestVerify(n *ResolvedRelease) EffectiveVerifyInput {
 			write(fmt.Sprintf("allowMissing=%t", r.AllowMissing))
 		}
 	}
+	if n.Verify.Config != "" {
+		write("config=" + n.Verify.Config)
+	}
+	if n.Verify.Rules != nil {
+		raw, _ := json.Marshal(n.Verify.Rules)
+		write("rules=" + string(raw))
+	}
 
 	return EffectiveVerifyInput{
 		Enabled:        enabled,
@@ -421,6 +452,8 @@ func digestVerify(n *ResolvedRelease) EffectiveVerifyInput {
 			}
 			return append([]VerifyConditionRequirement(nil), n.Verify.RequireConditions...)
 		}(),
+		Config: n.Verify.Config,
+		Rules:  n.Verify.Rules,
 		Digest: "sha256:" + hex.EncodeToString(h.Sum(nil)),
 	}
 }
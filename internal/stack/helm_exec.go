@@ -14,10 +14,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kubekattle/ktl/internal/appconfig"
 	"github.com/kubekattle/ktl/internal/deploy"
 	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/postrender"
+	"helm.sh/helm/v3/pkg/release"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/rest"
 )
@@ -33,6 +38,8 @@ type helmExecutor struct {
 	dryRun     bool
 	diff       bool
 	cacheApply bool
+	// skipUnchanged skips applying releases whose recorded input digest matches the node's.
+	skipUnchanged bool
 
 	helmLogs bool
 
@@ -41,7 +48,11 @@ type helmExecutor struct {
 
 	clients clientCache
 
-	secrets *deploy.SecretOptions
+	secrets     *deploy.SecretOptions
+	gitMetadata *deploy.GitMetadata
+	charts      *deploy.ChartCache
+	// approvedRisks approves risk tags listed in a release's apply.requireApproval.
+	approvedRisks []string
 }
 
 type NodeExecutor interface {
@@ -53,8 +64,8 @@ type clientCache struct {
 	m  map[string]*kube.Client
 }
 
-func (c *clientCache) get(ctx context.Context, kubeconfigPath, kubeContext string) (*kube.Client, error) {
-	key := kubeconfigPath + "\n" + kubeContext
+func (c *clientCache) get(ctx context.Context, kubeconfigPath, kubeContext string, execEnv map[string]string) (*kube.Client, error) {
+	key := kubeconfigPath + "\n" + kubeContext + "\n" + strings.Join(sortedEnv(execEnv), "\n")
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.m == nil {
@@ -63,7 +74,7 @@ func (c *clientCache) get(ctx context.Context, kubeconfigPath, kubeContext strin
 	if v, ok := c.m[key]; ok {
 		return v, nil
 	}
-	cli, err := kube.New(ctx, kubeconfigPath, kubeContext)
+	cli, err := kube.NewWithOptions(ctx, kubeconfigPath, kubeContext, kube.ClientOptions{ExecEnv: execEnv})
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +83,18 @@ func (c *clientCache) get(ctx context.Context, kubeconfigPath, kubeContext strin
 }
 
 func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command string) error {
+	ctx, span := tracing.Start(ctx, "stack node "+node.ID,
+		attribute.String("ktl.stack.node", node.ID),
+		attribute.String("ktl.stack.command", command),
+		attribute.String("ktl.stack.cluster", node.Cluster.Name),
+		attribute.String("k8s.namespace.name", node.Namespace),
+		attribute.Int("ktl.stack.attempt", node.Attempt))
+	err := e.execNode(ctx, node, command)
+	tracing.End(span, err)
+	return err
+}
+
+func (e *helmExecutor) execNode(ctx context.Context, node *runNode, command string) error {
 	kubeconfigPath := ""
 	if node.Cluster.Kubeconfig != "" {
 		kubeconfigPath = expandTilde(node.Cluster.Kubeconfig)
@@ -85,10 +108,17 @@ func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command strin
 		kubeCtx = strings.TrimSpace(*e.kubeContext)
 	}
 
-	kubeClient, err := e.clients.get(ctx, kubeconfigPath, kubeCtx)
+	if node.IsTask() {
+		return e.runTaskNode(ctx, node, command, kubeconfigPath, kubeCtx)
+	}
+
+	kubeClient, err := e.clients.get(ctx, kubeconfigPath, kubeCtx, node.Cluster.ExecCredentialEnv)
 	if err != nil {
 		return wrapNodeErr(node.ResolvedRelease, err)
 	}
+	if node.IsManifests() {
+		return e.runManifestsNode(ctx, kubeClient, node, command, stackClusterCacheKey(node.Cluster.Name, kubeconfigPath, kubeCtx))
+	}
 
 	settings := cli.New()
 	if kubeconfigPath != "" {
@@ -122,6 +152,8 @@ func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command strin
 			if e.kubeBurst > 0 {
 				cfg.Burst = e.kubeBurst
 			}
+			kube.ApplyExecEnv(cfg, node.Cluster.ExecCredentialEnv)
+			tracing.WrapREST(ctx, cfg)
 			return cfg
 		}
 	}
@@ -143,7 +175,7 @@ func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command strin
 		return wrapNodeErr(node.ResolvedRelease, fmt.Errorf("init helm action config: %w", err))
 	}
 
-	obs := &stackEventObserver{run: e.run, node: node}
+	obs := &stackEventObserver{run: e.run, node: node, persistDiff: e.helmLogs}
 	switch command {
 	case "apply":
 		timeout := 5 * time.Minute
@@ -162,6 +194,10 @@ func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command strin
 		if node.Apply.CreateNamespace != nil {
 			createNamespace = *node.Apply.CreateNamespace
 		}
+		postRenderer, err := nodePostRenderer(ctx, node.ResolvedRelease)
+		if err != nil {
+			return wrapNodeErr(node.ResolvedRelease, err)
+		}
 
 		if node.resume != nil && node.resume.VerifyOnly {
 			obs.PhaseCompleted(deploy.PhaseRender, "skipped", "Resume verify-only skipped")
@@ -182,6 +218,36 @@ func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command strin
 			return nil
 		}
 
+		// A digest error only disables skipping and recording for this node.
+		inputDigest, digestErr := NodeInputDigest(node.ResolvedRelease)
+		if e.skipUnchanged && !e.dryRun && digestErr != nil {
+			obs.EmitEvent("info", fmt.Sprintf("Not skipping: %v", digestErr))
+		}
+		if e.skipUnchanged && !e.dryRun && inputDigest != "" {
+			rel, err := action.NewGet(actionCfg).Run(node.Name)
+			var recorded string
+			if err == nil {
+				recorded, err = deploy.RecordedManifestDigest(ctx, actionCfg, rel)
+			}
+			if err == nil && deploy.ReleaseInputUnchanged(rel, inputDigest, recorded) && releaseLiveInSync(ctx, kubeClient, rel) {
+				msg := fmt.Sprintf("Input digest unchanged since revision %d", rel.Version)
+				for _, phase := range []string{deploy.PhaseRender, deploy.PhaseDiff, deploy.PhaseUpgrade, deploy.PhaseInstall, deploy.PhaseWait, deploy.PhasePostHooks} {
+					obs.PhaseCompleted(phase, "skipped", msg)
+				}
+				return nil
+			}
+		}
+		valuesFiles, err := releaseValuesFiles(ctx, node.ResolvedRelease, e.secrets)
+		if err != nil {
+			return wrapNodeErr(node.ResolvedRelease, err)
+		}
+
+		if !e.dryRun {
+			if err := waitForNodeDependencies(ctx, e.run, kubeClient, node); err != nil {
+				return wrapNodeErr(node.ResolvedRelease, err)
+			}
+		}
+
 		if e.cacheApply && !e.dryRun {
 			clusterKey := stackClusterCacheKey(node.Cluster.Name, kubeconfigPath, kubeCtx)
 			key, keyErr := applyCacheKeyForNode(clusterKey, node, command)
@@ -189,14 +255,16 @@ func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command strin
 				dec, decErr := CheckApplyCache(ctx, e.run.store, key, e.run.RunID,
 					func(ctx context.Context) (string, bool, error) {
 						res, err := deploy.RenderTemplate(ctx, actionCfg, settings, deploy.TemplateOptions{
-							Chart:       node.Chart,
-							Version:     node.ChartVersion,
-							ReleaseName: node.Name,
-							Namespace:   node.Namespace,
-							ValuesFiles: node.Values,
-							SetValues:   flattenSet(node.Set),
-							UseCluster:  true,
-							Secrets:     e.secrets,
+							Chart:        node.Chart,
+							Version:      node.ChartVersion,
+							ReleaseName:  node.Name,
+							Namespace:    node.Namespace,
+							ValuesFiles:  valuesFiles,
+							SetValues:    flattenSet(node.Set),
+							UseCluster:   true,
+							Secrets:      e.secrets,
+							Charts:       e.charts,
+							PostRenderer: postRenderer,
 						})
 						if err != nil {
 							return "", false, err
@@ -335,6 +403,31 @@ func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command strin
 		}
 
 		setPairs := flattenSet(node.Set)
+		if len(node.Apply.RequireApproval) > 0 && !e.dryRun {
+			preview, err := deploy.InstallOrUpgrade(ctx, actionCfg, settings, deploy.InstallOptions{
+				Chart:        node.Chart,
+				Version:      node.ChartVersion,
+				ReleaseName:  node.Name,
+				Namespace:    node.Namespace,
+				ValuesFiles:  valuesFiles,
+				SetValues:    setPairs,
+				Secrets:      e.secrets,
+				Timeout:      timeout,
+				DryRun:       true,
+				Diff:         true,
+				Charts:       e.charts,
+				PostRenderer: postRenderer,
+			})
+			if err != nil {
+				return wrapNodeErr(node.ResolvedRelease, fmt.Errorf("risk gate: %w", err))
+			}
+			if tags := preview.PlanSummary.RiskTagsPresent(); len(tags) > 0 && e.run != nil {
+				e.run.EmitEphemeralEvent(node.ID, NodeLog, node.Attempt, fmt.Sprintf("plan risks: %v", tags), map[string]any{"kind": "plan-risks"})
+			}
+			if err := checkRiskGate(node.Apply.RequireApproval, e.approvedRisks, preview.PlanSummary); err != nil {
+				return wrapNodeErr(node.ResolvedRelease, err)
+			}
+		}
 		diffEnabled := e.diff
 		if node.resume != nil && node.resume.SkipDiff {
 			diffEnabled = false
@@ -344,7 +437,7 @@ func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command strin
 			Version:           node.ChartVersion,
 			ReleaseName:       node.Name,
 			Namespace:         node.Namespace,
-			ValuesFiles:       node.Values,
+			ValuesFiles:       valuesFiles,
 			SetValues:         setPairs,
 			Secrets:           e.secrets,
 			Timeout:           timeout,
@@ -355,6 +448,11 @@ func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command strin
 			Diff:              diffEnabled,
 			UpgradeOnly:       false,
 			ProgressObservers: []deploy.ProgressObserver{obs},
+			GitMetadata:       e.gitMetadata,
+			Owner:             releaseOwner(node.Owner),
+			Charts:            e.charts,
+			PostRenderer:      postRenderer,
+			InputDigest:       inputDigest,
 		})
 		if err != nil {
 			if wait && !e.dryRun {
@@ -429,7 +527,9 @@ func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command strin
 		}
 		uninstall := action.NewUninstall(actionCfg)
 		uninstall.Timeout = timeout
+		_, helmSpan := tracing.Start(ctx, "helm uninstall", attribute.String("ktl.release", node.Name))
 		_, err := uninstall.Run(node.Name)
+		tracing.End(helmSpan, err)
 		if err != nil {
 			if e.run != nil {
 				e.run.AppendEvent(node.ID, PhaseCompleted, node.Attempt, "destroy failure", map[string]any{"phase": "destroy", "status": "failure"}, nil)
@@ -446,7 +546,9 @@ func (e *helmExecutor) RunNode(ctx context.Context, node *runNode, command strin
 }
 
 func maybeVerify(ctx context.Context, run *runState, clusterKey string, kubeClient *kube.Client, obs *stackEventObserver, node *runNode, manifest string, v VerifyOptions, dryRun bool) error {
-	if dryRun || !verifyEnabled(v) {
+	checkEvents := verifyEnabled(v)
+	checkRules := verifyRulesConfigured(v)
+	if dryRun || (!checkEvents && !checkRules) {
 		return nil
 	}
 	timeout := verifyTimeout(v)
@@ -468,7 +570,21 @@ func maybeVerify(ctx context.Context, run *runState, clusterKey string, kubeClie
 			lastEventRVJSON = entry.LastEventRVJSON
 		}
 	}
-	res, err := verifyKubeRelease(tryCtx, kubeClient, node.Namespace, node.Name, manifest, v, sinceNS, lastEventRVJSON)
+	var (
+		res verifyResult
+		err error
+	)
+	if checkEvents {
+		res, err = verifyKubeRelease(tryCtx, kubeClient, node.Namespace, node.Name, manifest, v, sinceNS, lastEventRVJSON)
+	}
+	if err == nil && checkRules {
+		var msg string
+		msg, err = verifyReleaseRules(tryCtx, kubeClient, node.Namespace, manifest, v)
+		if res.Message != "" && msg != "" {
+			res.Message += "; "
+		}
+		res.Message += msg
+	}
 	if err != nil {
 		if obs != nil {
 			obs.PhaseCompleted("verify", "failed", err.Error())
@@ -567,6 +683,8 @@ func stackClusterCacheKey(clusterName, kubeconfigPath, kubeContext string) strin
 type stackEventObserver struct {
 	run  *runState
 	node *runNode
+	// persistDiff also stores diffs as durable NODE_DIFF events (see RunOptions.HelmLogs).
+	persistDiff bool
 }
 
 func (o *stackEventObserver) PhaseStarted(name string) {
@@ -594,6 +712,13 @@ func (o *stackEventObserver) PhaseCompleted(name, status, message string) {
 	}, nil)
 }
 
+// releaseLiveInSync reports whether the live objects still match rel's manifest, so an edit
+// made with kubectl is not mistaken for an unchanged release.
+func releaseLiveInSync(ctx context.Context, kubeClient *kube.Client, rel *release.Release) bool {
+	report, err := deploy.CheckReleaseDrift(ctx, rel.Name, rel.Manifest, deploy.DriftLiveGetterFromKube(kubeClient))
+	return err == nil && report.Empty()
+}
+
 func (o *stackEventObserver) EmitEvent(level, message string) {
 	if o == nil || o.run == nil || o.node == nil {
 		return
@@ -609,6 +734,9 @@ func (o *stackEventObserver) EmitEvent(level, message string) {
 	o.run.EmitEphemeralEvent(o.node.ID, NodeLog, o.node.Attempt, fmt.Sprintf("%s: %s", level, message), map[string]any{"level": level})
 }
 
+// nodeDiffMaxBytes caps a persisted NODE_DIFF message so huge charts do not bloat the state store.
+const nodeDiffMaxBytes = 256 << 10
+
 func (o *stackEventObserver) SetDiff(diff string) {
 	if o == nil || o.run == nil || o.node == nil {
 		return
@@ -618,6 +746,12 @@ func (o *stackEventObserver) SetDiff(diff string) {
 		return
 	}
 	o.run.EmitEphemeralEvent(o.node.ID, NodeLog, o.node.Attempt, "diff:\n"+diff, map[string]any{"kind": "diff"})
+	if o.persistDiff {
+		if len(diff) > nodeDiffMaxBytes {
+			diff = diff[:nodeDiffMaxBytes] + "\n… (diff truncated)"
+		}
+		o.run.AppendEvent(o.node.ID, NodeDiff, o.node.Attempt, diff, nil, nil)
+	}
 }
 
 func flattenSet(m map[string]string) []string {
@@ -669,3 +803,19 @@ func expandTilde(path string) string {
 	}
 	return p
 }
+
+// nodePostRenderer builds the images post-renderer from the release's apply.images, if set.
+func nodePostRenderer(ctx context.Context, node *ResolvedRelease) (postrender.PostRenderer, error) {
+	if node == nil || node.Apply.Images == nil {
+		return nil, nil
+	}
+	return deploy.BuildPostRenderer(ctx, []appconfig.PostRendererConfig{{Name: "apply.images", Images: node.Apply.Images}}, nil)
+}
+
+// releaseOwner converts the stack owner to the one recorded on the release.
+func releaseOwner(o *OwnerSpec) *deploy.ReleaseOwner {
+	if o == nil {
+		return nil
+	}
+	return &deploy.ReleaseOwner{Team: o.Team, Slack: o.Slack, Oncall: o.Oncall}
+}
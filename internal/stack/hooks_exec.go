@@ -329,6 +329,9 @@ func runKubectlHook(ctx context.Context, hc hookRunContext, hook HookSpec, desc
 
 	cmd := exec.CommandContext(ctx, "kubectl", args...)
 	cmd.Dir = chooseWorkDir(hc, hook)
+	if env := hookExecCredentialEnv(hc, hook); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	out, err := cmd.CombinedOutput()
 	emitHookOutput(hc, desc, out)
 	if err != nil {
@@ -511,6 +514,7 @@ func buildHookEnv(hc hookRunContext, hook HookSpec) []string {
 	if kctx != "" {
 		env = append(env, "KUBE_CONTEXT="+kctx)
 	}
+	env = append(env, hookExecCredentialEnv(hc, hook)...)
 
 	if hc.node != nil {
 		env = append(env,
@@ -554,3 +558,12 @@ func hooksForRunOnce(p *Plan, command string, pre bool) []HookSpec {
 		return nil
 	}
 }
+
+// hookExecCredentialEnv returns the node cluster's execCredentialEnv when the hook talks to that
+// cluster, so kubectl's exec credential plugin sees the same settings as ktl's own clients.
+func hookExecCredentialEnv(hc hookRunContext, hook HookSpec) []string {
+	if hc.node == nil || strings.TrimSpace(hook.Kubeconfig) != "" || strings.TrimSpace(hook.Context) != "" {
+		return nil
+	}
+	return sortedEnv(hc.node.Cluster.ExecCredentialEnv)
+}
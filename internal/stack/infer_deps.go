@@ -285,7 +285,19 @@ type provider struct {
 	reasons []InferredReason
 }
 
-func renderAndExtractFacts(ctx context.Context, node *ResolvedRelease, defaultKubeconfig string, defaultKubeContext string, opts InferDepsOptions) ([]*unstructured.Unstructured, string, *releaseFacts, error) {
+// renderNodeManifest renders a node client-side: the chart for Helm nodes, the directory for
+// manifests nodes. Task nodes own no objects and render nothing.
+func renderNodeManifest(ctx context.Context, node *ResolvedRelease, defaultKubeconfig string, defaultKubeContext string, opts InferDepsOptions) (string, error) {
+	if node.IsManifests() {
+		return renderManifests(ctx, node, opts.Secrets)
+	}
+	if node.IsTask() {
+		return "", nil
+	}
+	valuesFiles, err := releaseValuesFiles(ctx, node, opts.Secrets)
+	if err != nil {
+		return "", err
+	}
 	kubeconfigPath := strings.TrimSpace(expandTilde(node.Cluster.Kubeconfig))
 	if kubeconfigPath == "" {
 		kubeconfigPath = strings.TrimSpace(defaultKubeconfig)
@@ -306,28 +318,42 @@ func renderAndExtractFacts(ctx context.Context, node *ResolvedRelease, defaultKu
 	}
 	// Init helm action config once per render; client-only still expects Configuration to be initialized.
 	actionCfg := new(action.Configuration)
+	withExecCredentialEnv(settings.RESTClientGetter(), node.Cluster.ExecCredentialEnv)
 	if err := actionCfg.Init(settings.RESTClientGetter(), "default", os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
-		return nil, "", nil, fmt.Errorf("init helm action config: %w", err)
+		return "", fmt.Errorf("init helm action config: %w", err)
 	}
 
+	postRenderer, err := nodePostRenderer(ctx, node)
+	if err != nil {
+		return "", err
+	}
 	rendered, err := deploy.RenderTemplate(ctx, actionCfg, settings, deploy.TemplateOptions{
-		Chart:       node.Chart,
-		Version:     node.ChartVersion,
-		ReleaseName: node.Name,
-		Namespace:   node.Namespace,
-		ValuesFiles: node.Values,
-		SetValues:   flattenSet(node.Set),
-		IncludeCRDs: true,
-		UseCluster:  false,
-		Secrets:     opts.Secrets,
+		Chart:        node.Chart,
+		Version:      node.ChartVersion,
+		ReleaseName:  node.Name,
+		Namespace:    node.Namespace,
+		ValuesFiles:  valuesFiles,
+		SetValues:    flattenSet(node.Set),
+		IncludeCRDs:  true,
+		UseCluster:   false,
+		Secrets:      opts.Secrets,
+		PostRenderer: postRenderer,
 	})
 	if err != nil {
-		return nil, "", nil, err
+		return "", err
 	}
 	manifest := ""
 	if rendered != nil {
 		manifest = rendered.Manifest
 	}
+	return manifest, nil
+}
+
+func renderAndExtractFacts(ctx context.Context, node *ResolvedRelease, defaultKubeconfig string, defaultKubeContext string, opts InferDepsOptions) ([]*unstructured.Unstructured, string, *releaseFacts, error) {
+	manifest, err := renderNodeManifest(ctx, node, defaultKubeconfig, defaultKubeContext, opts)
+	if err != nil {
+		return nil, "", nil, err
+	}
 
 	objs, err := parseManifestObjects(manifest)
 	if err != nil {
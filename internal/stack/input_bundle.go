@@ -16,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kubekattle/ktl/internal/netconfig"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
@@ -31,9 +32,10 @@ type InputBundleManifest struct {
 }
 
 type InputBundleNode struct {
-	ID       string             `json:"id"`
-	ChartDir string             `json:"chartDir"`
-	Values   []InputBundleValue `json:"values,omitempty"`
+	ID           string             `json:"id"`
+	ChartDir     string             `json:"chartDir"`
+	ManifestsDir string             `json:"manifestsDir,omitempty"`
+	Values       []InputBundleValue `json:"values,omitempty"`
 }
 
 type InputBundleValue struct {
@@ -100,28 +102,47 @@ func WriteInputBundle(ctx context.Context, outPath string, planHash string, node
 		chartDir := path.Join("nodes", nodeKey, "chart")
 		valuesDir := path.Join("nodes", nodeKey, "values")
 
-		ch, err := loadHelmChartForBundle(n.Chart, n.ChartVersion, settings)
-		if err != nil {
-			_ = tw.Close()
-			_ = gw.Close()
-			_ = f.Close()
-			_ = os.Remove(tmp)
-			return nil, "", err
+		nodeEntry := InputBundleNode{ID: n.ID}
+		switch {
+		case n.IsTask():
+			// Tasks reference an image or a local command; there is no chart to bundle.
+		case n.IsManifests():
+			nodeEntry.ManifestsDir = path.Join("nodes", nodeKey, "manifests")
+			if err := writeManifestsToTar(tw, nodeEntry.ManifestsDir, n.Manifests.Path); err != nil {
+				_ = tw.Close()
+				_ = gw.Close()
+				_ = f.Close()
+				_ = os.Remove(tmp)
+				return nil, "", err
+			}
+		default:
+			ch, err := loadHelmChartForBundle(n.Chart, n.ChartVersion, settings)
+			if err != nil {
+				_ = tw.Close()
+				_ = gw.Close()
+				_ = f.Close()
+				_ = os.Remove(tmp)
+				return nil, "", err
+			}
+			if err := writeChartToTar(tw, chartDir, ch); err != nil {
+				_ = tw.Close()
+				_ = gw.Close()
+				_ = f.Close()
+				_ = os.Remove(tmp)
+				return nil, "", err
+			}
+			nodeEntry.ChartDir = chartDir
 		}
-		if err := writeChartToTar(tw, chartDir, ch); err != nil {
+
+		valuesFiles, err := releaseValuesFiles(ctx, n, nil)
+		if err != nil {
 			_ = tw.Close()
 			_ = gw.Close()
 			_ = f.Close()
 			_ = os.Remove(tmp)
 			return nil, "", err
 		}
-
-		nodeEntry := InputBundleNode{
-			ID:       n.ID,
-			ChartDir: chartDir,
-		}
-
-		for i, vp := range n.Values {
+		for i, vp := range valuesFiles {
 			b, err := os.ReadFile(vp)
 			if err != nil {
 				_ = tw.Close()
@@ -140,7 +161,7 @@ func WriteInputBundle(ctx context.Context, outPath string, planHash string, node
 				return nil, "", err
 			}
 			nodeEntry.Values = append(nodeEntry.Values, InputBundleValue{
-				OriginalPath: vp,
+				OriginalPath: n.Values[i],
 				BundlePath:   dst,
 				Digest:       "sha256:" + hex.EncodeToString(sum[:]),
 			})
@@ -287,7 +308,13 @@ func ApplyInputBundleToPlan(p *Plan, bundleRoot string, manifest *InputBundleMan
 		if !ok {
 			return &BundleMissingNodeError{NodeID: n.ID}
 		}
-		n.Chart = filepath.Join(bundleRoot, filepath.FromSlash(entry.ChartDir))
+		if entry.ManifestsDir != "" && n.Manifests != nil {
+			m := *n.Manifests
+			m.Path = filepath.Join(bundleRoot, filepath.FromSlash(entry.ManifestsDir))
+			n.Manifests = &m
+		} else if entry.ChartDir != "" {
+			n.Chart = filepath.Join(bundleRoot, filepath.FromSlash(entry.ChartDir))
+		}
 		var vals []string
 		for _, v := range entry.Values {
 			vals = append(vals, filepath.Join(bundleRoot, filepath.FromSlash(v.BundlePath)))
@@ -315,7 +342,11 @@ func loadHelmChartForBundle(ref string, version string, settings *cli.EnvSetting
 	v := strings.TrimSpace(version)
 	chartPath := ref
 	if !isExistingPath(ref) {
+		if err := netconfig.CheckChartRef(ref, ""); err != nil {
+			return nil, err
+		}
 		cpo := action.ChartPathOptions{Version: v}
+		netconfig.ApplyChartPathOptions(&cpo)
 		located, err := cpo.LocateChart(ref, settings)
 		if err != nil {
 			return nil, fmt.Errorf("locate chart %s: %w", ref, err)
@@ -329,6 +360,19 @@ func loadHelmChartForBundle(ref string, version string, settings *cli.EnvSetting
 	return ch, nil
 }
 
+func writeManifestsToTar(tw *tar.Writer, dstDir string, srcDir string) error {
+	files, err := readManifestFiles(srcDir)
+	if err != nil {
+		return err
+	}
+	for _, mf := range files {
+		if err := writeFileToTar(tw, path.Join(dstDir, mf.Rel), mf.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func writeChartToTar(tw *tar.Writer, chartDir string, ch *chart.Chart) error {
 	if tw == nil || ch == nil {
 		return nil
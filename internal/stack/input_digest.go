@@ -0,0 +1,102 @@
+// File: internal/stack/input_digest.go
+// Brief: Machine-independent digest of a node's deploy inputs for --skip-unchanged.
+
+package stack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+)
+
+// NodeInputDigest digests what a node deploys: chart contents and version, values file
+// contents, set entries, apply options (image overrides included), and hooks. Unlike
+// EffectiveInputHash it leaves out the git commit, the ktl version, file paths, and kubeconfig
+// details, so two checkouts of the same inputs agree on any machine. It needs the node's
+// EffectiveInput (see ComputeEffectiveInputHash).
+//
+// Inputs only known at deploy time (secret:// and valuefrom:// references, images.pinDigests,
+// unpinned https:// and oci:// values) would not change the digest when they change, so such
+// nodes get an error instead.
+func NodeInputDigest(n *ResolvedRelease) (string, error) {
+	if n == nil || n.EffectiveInput == nil {
+		return "", fmt.Errorf("node has no effective input")
+	}
+	if reason := deployTimeInput(n); reason != "" {
+		return "", fmt.Errorf("no input digest: %s is resolved at deploy time", reason)
+	}
+	in := n.EffectiveInput
+	h := sha256.New()
+	write := func(s string) {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	writeJSON := func(label string, v any) error {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("digest %s: %w", label, err)
+		}
+		write(label + "=" + string(raw))
+		return nil
+	}
+	write("ktl.stack-input-digest.v1")
+	write("release=" + strings.TrimSpace(n.Name))
+	write("namespace=" + defaultNamespace(n.Namespace))
+	write("chart=" + in.Chart.Digest)
+	write("chartVersion=" + in.Chart.ResolvedVersion)
+	for _, v := range in.Values {
+		// Remote values have no content digest; their URL is the same everywhere.
+		if v.Digest != "" {
+			write("values=" + v.Digest)
+		} else {
+			write("values=" + v.Path)
+		}
+	}
+	write("set=" + in.SetDigest)
+	if err := writeJSON("apply", n.Apply); err != nil {
+		return "", err
+	}
+	if err := writeJSON("hooks", n.Hooks); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// deployTimeInputRefs are value references resolved while deploying.
+var deployTimeInputRefs = []string{"secret://", "valuefrom://"}
+
+// deployTimeInput names the first input of n that is resolved at deploy time, or "".
+func deployTimeInput(n *ResolvedRelease) string {
+	if n.Apply.Images != nil && n.Apply.Images.PinDigests {
+		return "apply.images.pinDigests"
+	}
+	hasRef := func(s string) bool {
+		for _, ref := range deployTimeInputRefs {
+			if strings.Contains(s, ref) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, path := range n.Values {
+		if ref, pin := deploy.SplitValuesPin(path); pin == "" && deploy.IsRemoteValuesSource(ref) {
+			return "unpinned remote values " + ref
+		}
+		raw, err := os.ReadFile(path)
+		if err == nil && hasRef(string(raw)) {
+			return "a secret or value-source reference in " + filepath.Base(path)
+		}
+	}
+	for key, value := range n.Set {
+		if hasRef(value) {
+			return "a secret or value-source reference in set." + key
+		}
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package stack
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+)
+
+func TestNodeInputDigest_SameInputsAcrossCheckouts(t *testing.T) {
+	digest := func(root, values string) string {
+		t.Helper()
+		writeFile(t, filepath.Join(root, "chart", "Chart.yaml"), "apiVersion: v2\nname: demo\nversion: 0.1.0\n")
+		writeFile(t, filepath.Join(root, "chart", "templates", "cm.yaml"), "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: demo\n")
+		writeFile(t, filepath.Join(root, "values.yaml"), values)
+		writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+defaults:
+  cluster: { name: c1 }
+  namespace: ns1
+releases:
+  - name: app
+    chart: ./chart
+    values: [values.yaml]
+    apply:
+      images:
+        mirrors:
+          - from: docker.io
+            to: mirror.example.com
+`)
+		u, err := Discover(root)
+		if err != nil {
+			t.Fatalf("discover: %v", err)
+		}
+		p, err := Compile(u, CompileOptions{})
+		if err != nil {
+			t.Fatalf("compile: %v", err)
+		}
+		n := p.Nodes[0]
+		_, input, err := ComputeEffectiveInputHash(root, n, true)
+		if err != nil {
+			t.Fatalf("hash: %v", err)
+		}
+		n.EffectiveInput = input
+		d, err := NodeInputDigest(n)
+		if err != nil {
+			t.Fatalf("digest: %v", err)
+		}
+		return d
+	}
+
+	a := digest(t.TempDir(), "replicaCount: 1\n")
+	b := digest(t.TempDir(), "replicaCount: 1\n")
+	if a != b {
+		t.Fatalf("same inputs in different checkouts digest differently: %s vs %s", a, b)
+	}
+	if c := digest(t.TempDir(), "replicaCount: 2\n"); c == a {
+		t.Fatalf("expected a values change to change the digest")
+	}
+}
+
+func TestNodeInputDigest_RefusesDeployTimeInputs(t *testing.T) {
+	root := t.TempDir()
+	plain := filepath.Join(root, "values.yaml")
+	withSecret := filepath.Join(root, "secret.yaml")
+	withSource := filepath.Join(root, "source.yaml")
+	writeFile(t, plain, "replicaCount: 1\n")
+	writeFile(t, withSecret, "db:\n  password: secret://vault/app/db#password\n")
+	writeFile(t, withSource, "endpoint: valuefrom://consul/app/endpoint\n")
+
+	node := func(values ...string) *ResolvedRelease {
+		return &ResolvedRelease{Name: "app", Values: values, EffectiveInput: &EffectiveInput{}}
+	}
+	if _, err := NodeInputDigest(node(plain)); err != nil {
+		t.Fatalf("plain values: %v", err)
+	}
+	for _, tc := range []struct {
+		name string
+		node *ResolvedRelease
+		want string
+	}{
+		{name: "secret ref", node: node(plain, withSecret), want: "secret.yaml"},
+		{name: "value source ref", node: node(withSource), want: "source.yaml"},
+		{name: "set ref", node: &ResolvedRelease{Name: "app", Set: map[string]string{"token": "secret://vault/app#token"}, EffectiveInput: &EffectiveInput{}}, want: "set.token"},
+		{name: "pinned digests", node: &ResolvedRelease{Name: "app", Apply: ApplyOptions{Images: &appconfig.ImageRewriteConfig{PinDigests: true}}, EffectiveInput: &EffectiveInput{}}, want: "pinDigests"},
+	} {
+		if _, err := NodeInputDigest(tc.node); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected a deploy-time input error naming %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+// File: internal/stack/junit.go
+// Brief: JUnit XML report of a stack run (--junit).
+
+package stack
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JUnitOptions configures a JUnitReport.
+type JUnitOptions struct {
+	Stack   string
+	Command string
+	// Hooks adds a test case per hook run, next to the node test cases.
+	Hooks bool
+}
+
+// JUnitReport collects run events and writes them as a JUnit XML report: one test case per
+// node, classed by cluster and namespace, so CI systems can render and track stack runs.
+// Failed attempts before a retry are reported as flakyFailure entries when the node finally
+// succeeded and as rerunFailure entries when it did not.
+type JUnitReport struct {
+	opts JUnitOptions
+
+	mu        sync.Mutex
+	runID     string
+	startedAt time.Time
+	endedAt   time.Time
+	nodes     map[string]*junitNode
+	hooks     []*junitHook
+	openHooks map[string]*junitHook
+}
+
+type junitNode struct {
+	id        string
+	classname string
+	name      string
+	startedAt time.Time
+	endedAt   time.Time
+	attempts  int
+	status    string // succeeded|failed|blocked
+	message   string
+	class     string
+	retried   []junitFailure
+}
+
+type junitHook struct {
+	node      string
+	name      string
+	startedAt time.Time
+	endedAt   time.Time
+	status    string // succeeded|failed|skipped
+	message   string
+}
+
+// NewJUnitReport returns a report to register as a run event observer.
+func NewJUnitReport(opts JUnitOptions) *JUnitReport {
+	return &JUnitReport{opts: opts, nodes: map[string]*junitNode{}, openHooks: map[string]*junitHook{}}
+}
+
+// ObserveRunEvent implements RunEventObserver.
+func (r *JUnitReport) ObserveRunEvent(ev RunEvent) {
+	if r == nil {
+		return
+	}
+	ts, _ := time.Parse(time.RFC3339Nano, ev.TS)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ev.RunID != "" {
+		r.runID = ev.RunID
+	}
+	switch RunEventType(ev.Type) {
+	case RunStarted:
+		r.startedAt = ts
+	case RunCompleted:
+		r.endedAt = ts
+	case NodeMeta:
+		n := r.nodeLocked(ev.NodeID)
+		cluster, _ := ev.Fields["cluster"].(string)
+		namespace, _ := ev.Fields["namespace"].(string)
+		if name, _ := ev.Fields["name"].(string); name != "" {
+			n.name = name
+		}
+		n.classname = junitClassname(r.opts.Stack, cluster, namespace)
+	case NodeRunning:
+		n := r.nodeLocked(ev.NodeID)
+		if n.startedAt.IsZero() {
+			n.startedAt = ts
+		}
+		n.attempts = max(n.attempts, ev.Attempt)
+	case NodeSucceeded:
+		n := r.nodeLocked(ev.NodeID)
+		n.status, n.endedAt = "succeeded", ts
+	case NodeFailed:
+		n := r.nodeLocked(ev.NodeID)
+		n.status, n.endedAt, n.message, n.class = "failed", ts, strings.TrimSpace(ev.Message), ""
+		if ev.Error != nil {
+			n.class = ev.Error.Class
+			if msg := strings.TrimSpace(ev.Error.Message); msg != "" {
+				n.message = msg
+			}
+		}
+	case RetryScheduled:
+		n := r.nodeLocked(ev.NodeID)
+		if n.status == "failed" {
+			n.retried = append(n.retried, junitFailure{Message: n.message, Type: n.class, Text: n.message})
+			n.status, n.message, n.class = "", "", ""
+		}
+	case NodeBlocked:
+		n := r.nodeLocked(ev.NodeID)
+		n.status, n.message = "blocked", strings.TrimSpace(ev.Message)
+	case HookStarted, HookSucceeded, HookFailed, HookSkipped:
+		if r.opts.Hooks {
+			r.observeHookLocked(ev, ts)
+		}
+	}
+}
+
+func (r *JUnitReport) observeHookLocked(ev RunEvent, ts time.Time) {
+	hook, _ := ev.Fields["hook"].(string)
+	phase, _ := ev.Fields["phase"].(string)
+	key := fmt.Sprintf("%s\n%s\n%s\n%d", ev.NodeID, phase, hook, ev.Attempt)
+	h := r.openHooks[key]
+	if h == nil {
+		h = &junitHook{node: ev.NodeID, name: strings.TrimSpace(phase + " " + hook), startedAt: ts}
+		r.hooks = append(r.hooks, h)
+	}
+	switch RunEventType(ev.Type) {
+	case HookStarted:
+		r.openHooks[key] = h
+		return
+	case HookSucceeded:
+		h.status = "succeeded"
+	case HookFailed:
+		h.status = "failed"
+	case HookSkipped:
+		h.status = "skipped"
+	}
+	h.endedAt, h.message = ts, strings.TrimSpace(ev.Message)
+	delete(r.openHooks, key)
+}
+
+func (r *JUnitReport) nodeLocked(id string) *junitNode {
+	if n, ok := r.nodes[id]; ok {
+		return n
+	}
+	n := &junitNode{id: id, name: id, classname: junitClassname(r.opts.Stack, "", "")}
+	r.nodes[id] = n
+	return n
+}
+
+func junitClassname(stackName string, parts ...string) string {
+	out := []string{"ktl"}
+	if s := strings.TrimSpace(stackName); s != "" {
+		out = append(out, s)
+	}
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, ".")
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Classname string         `xml:"classname,attr"`
+	Name      string         `xml:"name,attr"`
+	Time      string         `xml:"time,attr"`
+	Failure   *junitFailure  `xml:"failure,omitempty"`
+	Flaky     []junitFailure `xml:"flakyFailure,omitempty"`
+	Reruns    []junitFailure `xml:"rerunFailure,omitempty"`
+	Skipped   *junitSkipped  `xml:"skipped,omitempty"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// Write renders the collected events as JUnit XML.
+func (r *JUnitReport) Write(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	suiteName := strings.TrimSpace("ktl stack " + r.opts.Command)
+	if s := strings.TrimSpace(r.opts.Stack); s != "" {
+		suiteName += " " + s
+	}
+	suite := junitTestSuite{Name: suiteName, Time: junitSeconds(r.startedAt, r.endedAt)}
+	if !r.startedAt.IsZero() {
+		suite.Timestamp = r.startedAt.UTC().Format("2006-01-02T15:04:05")
+	}
+	if r.runID != "" {
+		suite.Properties = append(suite.Properties, junitProperty{Name: "runId", Value: r.runID})
+	}
+
+	ids := make([]string, 0, len(r.nodes))
+	for id := range r.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		n := r.nodes[id]
+		tc := junitTestCase{Classname: n.classname, Name: n.name, Time: junitSeconds(n.startedAt, n.endedAt), Flaky: n.retried}
+		if n.attempts > 1 {
+			tc.SystemOut = fmt.Sprintf("node %s: %d attempts", n.id, n.attempts)
+		}
+		switch n.status {
+		case "succeeded":
+		case "failed":
+			tc.Failure = &junitFailure{Message: firstLine(n.message), Type: n.class, Text: n.message}
+			tc.Flaky, tc.Reruns = nil, n.retried
+			suite.Failures++
+		case "blocked":
+			tc.Skipped = &junitSkipped{Message: "blocked: " + n.message}
+			suite.Skipped++
+		default:
+			tc.Skipped = &junitSkipped{Message: "not run"}
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	for _, h := range r.hooks {
+		classname := junitClassname(r.opts.Stack, "hooks")
+		if n, ok := r.nodes[h.node]; ok {
+			classname = n.classname + "." + n.name
+		}
+		tc := junitTestCase{Classname: classname, Name: h.name, Time: junitSeconds(h.startedAt, h.endedAt)}
+		switch h.status {
+		case "succeeded":
+		case "failed":
+			tc.Failure = &junitFailure{Message: firstLine(h.message), Type: "HOOK_FAILED", Text: h.message}
+			suite.Failures++
+		case "skipped":
+			tc.Skipped = &junitSkipped{Message: h.message}
+			suite.Skipped++
+		default:
+			tc.Skipped = &junitSkipped{Message: "interrupted"}
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+
+	doc := junitTestSuites{
+		Name:     suiteName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitSeconds(start, end time.Time) string {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return "0.000"
+	}
+	return fmt.Sprintf("%.3f", end.Sub(start).Seconds())
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
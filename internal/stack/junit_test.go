@@ -0,0 +1,61 @@
+package stack
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func TestJUnitReport(t *testing.T) {
+	r := NewJUnitReport(JUnitOptions{Stack: "shop", Command: "apply", Hooks: true})
+	ev := func(ts, node string, typ RunEventType, attempt int, msg string, fields map[string]any, runErr *RunError) {
+		r.ObserveRunEvent(RunEvent{TS: ts, RunID: "run-1", NodeID: node, Type: string(typ), Attempt: attempt, Message: msg, Fields: fields, Error: runErr})
+	}
+	meta := func(name string) map[string]any {
+		return map[string]any{"cluster": "east", "namespace": "shop", "name": name}
+	}
+	ev("2026-01-01T10:00:00Z", "east/shop/api", NodeMeta, 0, "", meta("api"), nil)
+	ev("2026-01-01T10:00:00Z", "east/shop/db", NodeMeta, 0, "", meta("db"), nil)
+	ev("2026-01-01T10:00:00Z", "east/shop/web", NodeMeta, 0, "", meta("web"), nil)
+	ev("2026-01-01T10:00:00Z", "", RunStarted, 0, "", nil, nil)
+	ev("2026-01-01T10:00:01Z", "east/shop/db", NodeRunning, 1, "", nil, nil)
+	ev("2026-01-01T10:00:03Z", "east/shop/db", NodeFailed, 1, "timeout", nil, &RunError{Class: "TIMEOUT", Message: "wait: timeout"})
+	ev("2026-01-01T10:00:03Z", "east/shop/db", RetryScheduled, 2, "", nil, nil)
+	ev("2026-01-01T10:00:04Z", "east/shop/db", NodeRunning, 2, "", nil, nil)
+	ev("2026-01-01T10:00:06Z", "east/shop/db", NodeSucceeded, 2, "", nil, nil)
+	ev("2026-01-01T10:00:06Z", "east/shop/api", NodeRunning, 1, "", nil, nil)
+	ev("2026-01-01T10:00:06Z", "east/shop/api", HookStarted, 1, "", map[string]any{"hook": "smoke", "phase": "post-apply"}, nil)
+	ev("2026-01-01T10:00:07Z", "east/shop/api", HookFailed, 1, "post-apply smoke: exit 1", map[string]any{"hook": "smoke", "phase": "post-apply"}, nil)
+	ev("2026-01-01T10:00:07Z", "east/shop/api", NodeFailed, 1, "hook failed", nil, &RunError{Class: "OTHER", Message: "post-apply smoke: exit 1"})
+	ev("2026-01-01T10:00:07Z", "east/shop/web", NodeBlocked, 0, "dependency failed", nil, nil)
+	ev("2026-01-01T10:00:08Z", "", RunCompleted, 0, "failed", nil, nil)
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var doc junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("parse report: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 4 || doc.Failures != 2 || doc.Skipped != 1 || doc.Time != "8.000" {
+		t.Fatalf("totals tests=%d failures=%d skipped=%d time=%s\n%s", doc.Tests, doc.Failures, doc.Skipped, doc.Time, buf.String())
+	}
+	cases := map[string]junitTestCase{}
+	for _, tc := range doc.Suites[0].Cases {
+		cases[tc.Classname+"/"+tc.Name] = tc
+	}
+	db := cases["ktl.shop.east.shop/db"]
+	if db.Failure != nil || len(db.Flaky) != 1 || db.Flaky[0].Type != "TIMEOUT" || db.Time != "5.000" {
+		t.Fatalf("db should pass as flaky after 5s: %+v", db)
+	}
+	if api := cases["ktl.shop.east.shop/api"]; api.Failure == nil || api.Failure.Type != "OTHER" {
+		t.Fatalf("api should fail: %+v", api)
+	}
+	if web := cases["ktl.shop.east.shop/web"]; web.Skipped == nil {
+		t.Fatalf("web should be skipped as blocked: %+v", web)
+	}
+	if hook := cases["ktl.shop.east.shop.api/post-apply smoke"]; hook.Failure == nil || hook.Time != "1.000" {
+		t.Fatalf("hook test case: %+v", hook)
+	}
+}
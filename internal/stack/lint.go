@@ -0,0 +1,349 @@
+// File: internal/stack/lint.go
+// Brief: Static analysis of a stack (ktl stack lint).
+
+package stack
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+)
+
+// LintSeverity ranks a lint finding.
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+	LintInfo    LintSeverity = "info"
+)
+
+// Lint rule IDs.
+const (
+	LintRuleInvalidRelease        = "invalid-release"
+	LintRuleDuplicateName         = "duplicate-release-name"
+	LintRuleMissingNeed           = "missing-need"
+	LintRuleCycle                 = "cycle"
+	LintRuleUnreachable           = "unreachable"
+	LintRuleMissingValuesFile     = "missing-values-file"
+	LintRuleHookWithoutTimeout    = "hook-without-timeout"
+	LintRuleCriticalWithoutVerify = "critical-without-verify"
+)
+
+// LintRules describes every rule Lint can report, keyed by rule ID.
+var LintRules = map[string]string{
+	LintRuleInvalidRelease:        "Release definition cannot be resolved.",
+	LintRuleDuplicateName:         "Two releases share a name in the same cluster.",
+	LintRuleMissingNeed:           "A needs entry does not name a release in the same cluster.",
+	LintRuleCycle:                 "Releases depend on each other in a cycle.",
+	LintRuleUnreachable:           "Release can never run because a dependency is missing or in a cycle.",
+	LintRuleMissingValuesFile:     "A local values file does not exist.",
+	LintRuleHookWithoutTimeout:    "Hook has no timeout and can block a run indefinitely.",
+	LintRuleCriticalWithoutVerify: "Critical release does not enable post-apply verification.",
+}
+
+// LintOptions configures Lint.
+type LintOptions struct {
+	Profile string
+}
+
+// LintFinding is one problem found by Lint. File is relative to the stack root.
+type LintFinding struct {
+	RuleID   string       `json:"ruleId"`
+	Severity LintSeverity `json:"severity"`
+	File     string       `json:"file,omitempty"`
+	Line     int          `json:"line,omitempty"`
+	Node     string       `json:"node,omitempty"`
+	Message  string       `json:"message"`
+}
+
+func (f LintFinding) String() string {
+	loc := f.File
+	if loc == "" {
+		loc = stackFileName
+	}
+	if f.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", loc, f.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", loc, f.Severity, f.Message, f.RuleID)
+}
+
+// LintReport is the result of Lint.
+type LintReport struct {
+	StackRoot string        `json:"stackRoot"`
+	StackName string        `json:"stackName"`
+	Profile   string        `json:"profile,omitempty"`
+	Releases  int           `json:"releases"`
+	Errors    int           `json:"errors"`
+	Warnings  int           `json:"warnings"`
+	Findings  []LintFinding `json:"findings"`
+}
+
+type lintNode struct {
+	rel  *ResolvedRelease
+	file string
+	line int
+}
+
+// Lint checks a discovered stack without touching any cluster. Unlike Compile it does not stop at
+// the first problem: every release is examined and all findings are returned, sorted by file and
+// line, so a stack can be fixed in one pass.
+func Lint(u *Universe, opts LintOptions) LintReport {
+	profile := strings.TrimSpace(opts.Profile)
+	if profile == "" {
+		profile = strings.TrimSpace(u.DefaultProfile)
+	}
+	rep := LintReport{StackRoot: u.RootDir, StackName: u.StackName, Profile: profile}
+	add := func(f LintFinding) { rep.Findings = append(rep.Findings, f) }
+
+	var resolved []*ResolvedRelease
+	fileOf := map[*ResolvedRelease]string{}
+	for _, dr := range u.Releases {
+		file := lintReleaseFile(u.RootDir, dr)
+		node, err := resolveRelease(u, dr, profile)
+		if err != nil {
+			add(LintFinding{RuleID: LintRuleInvalidRelease, Severity: LintError, File: file, Message: err.Error()})
+			continue
+		}
+		fileOf[node] = file
+		resolved = append(resolved, node)
+	}
+	enabled, _, err := applyEnabledConditions(resolved, profile)
+	if err != nil {
+		add(LintFinding{RuleID: LintRuleInvalidRelease, Severity: LintError, Message: err.Error()})
+		enabled = resolved
+	}
+	rep.Releases = len(enabled)
+
+	nodes := make([]*lintNode, 0, len(enabled))
+	byCluster := map[string]map[string]*lintNode{}
+	for _, rel := range enabled {
+		n := &lintNode{rel: rel, file: fileOf[rel]}
+		n.line = lintFindLine(filepath.Join(u.RootDir, n.file), rel.Name)
+		nodes = append(nodes, n)
+		names := byCluster[rel.Cluster.Name]
+		if names == nil {
+			names = map[string]*lintNode{}
+			byCluster[rel.Cluster.Name] = names
+		}
+		if prev, ok := names[rel.Name]; ok {
+			add(LintFinding{
+				RuleID: LintRuleDuplicateName, Severity: LintError, File: n.file, Line: n.line, Node: rel.ID,
+				Message: fmt.Sprintf("release name %q is already used in cluster %q by %s", rel.Name, rel.Cluster.Name, prev.file),
+			})
+			continue
+		}
+		names[rel.Name] = n
+	}
+
+	lintGraph(nodes, byCluster, add)
+
+	for _, n := range nodes {
+		rel := n.rel
+		for _, v := range rel.Values {
+			ref, _ := deploy.SplitValuesPin(v)
+			if ref == "" || ref == "-" || strings.Contains(ref, "://") {
+				continue
+			}
+			if _, err := os.Stat(ref); err != nil {
+				add(LintFinding{
+					RuleID: LintRuleMissingValuesFile, Severity: LintError, File: n.file, Line: n.line, Node: rel.ID,
+					Message: fmt.Sprintf("release %s: values file %s does not exist", rel.Name, lintRel(u.RootDir, ref)),
+				})
+			}
+		}
+		lintHooks(u.RootDir, rel.Hooks, "release "+rel.Name+" ", n.file, rel.ID, add)
+		if rel.Critical && (rel.Verify.Enabled == nil || !*rel.Verify.Enabled) {
+			add(LintFinding{
+				RuleID: LintRuleCriticalWithoutVerify, Severity: LintWarning, File: n.file, Line: n.line, Node: rel.ID,
+				Message: fmt.Sprintf("release %s is critical but verify.enabled is not true", rel.Name),
+			})
+		}
+	}
+
+	if hooks, err := ResolveStackHooksConfig(u, profile); err != nil {
+		add(LintFinding{RuleID: LintRuleInvalidRelease, Severity: LintError, File: stackFileName, Message: err.Error()})
+	} else {
+		lintHooks(u.RootDir, hooks, "stack ", stackFileName, "", add)
+	}
+
+	sort.SliceStable(rep.Findings, func(i, j int) bool {
+		a, b := rep.Findings[i], rep.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.RuleID < b.RuleID
+	})
+	for _, f := range rep.Findings {
+		switch f.Severity {
+		case LintError:
+			rep.Errors++
+		case LintWarning:
+			rep.Warnings++
+		}
+	}
+	return rep
+}
+
+// lintGraph reports needs that do not resolve, dependency cycles, and releases that can never
+// run because something they (transitively) need is missing or stuck in a cycle.
+func lintGraph(nodes []*lintNode, byCluster map[string]map[string]*lintNode, add func(LintFinding)) {
+	blocked := map[*lintNode]bool{}
+	deps := map[*lintNode][]*lintNode{}
+	for _, n := range nodes {
+		names := byCluster[n.rel.Cluster.Name]
+		for _, dep := range n.rel.Needs {
+			target, ok := names[dep]
+			if !ok {
+				add(LintFinding{
+					RuleID: LintRuleMissingNeed, Severity: LintError, File: n.file, Line: n.line, Node: n.rel.ID,
+					Message: fmt.Sprintf("release %s needs %q, which is not a release in cluster %q", n.rel.Name, dep, n.rel.Cluster.Name),
+				})
+				blocked[n] = true
+				continue
+			}
+			deps[n] = append(deps[n], target)
+		}
+	}
+
+	// Depth-first search; a back edge closes a cycle made of the nodes on the stack above it.
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[*lintNode]int{}
+	var stack []*lintNode
+	var visit func(n *lintNode)
+	visit = func(n *lintNode) {
+		state[n] = visiting
+		stack = append(stack, n)
+		for _, d := range deps[n] {
+			switch state[d] {
+			case unvisited:
+				visit(d)
+			case visiting:
+				start := len(stack) - 1
+				for stack[start] != d {
+					start--
+				}
+				cycle := stack[start:]
+				path := make([]string, 0, len(cycle)+1)
+				for _, c := range cycle {
+					path = append(path, c.rel.Name)
+					blocked[c] = true
+				}
+				path = append(path, d.rel.Name)
+				add(LintFinding{
+					RuleID: LintRuleCycle, Severity: LintError, File: d.file, Line: d.line, Node: d.rel.ID,
+					Message: fmt.Sprintf("dependency cycle in cluster %q: %s", d.rel.Cluster.Name, strings.Join(path, " -> ")),
+				})
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[n] = done
+	}
+	for _, n := range nodes {
+		if state[n] == unvisited {
+			visit(n)
+		}
+	}
+
+	var reaches func(n *lintNode, seen map[*lintNode]bool) *lintNode
+	reaches = func(n *lintNode, seen map[*lintNode]bool) *lintNode {
+		for _, d := range deps[n] {
+			if blocked[d] {
+				return d
+			}
+			if seen[d] {
+				continue
+			}
+			seen[d] = true
+			if b := reaches(d, seen); b != nil {
+				return b
+			}
+		}
+		return nil
+	}
+	for _, n := range nodes {
+		if blocked[n] {
+			continue
+		}
+		if b := reaches(n, map[*lintNode]bool{n: true}); b != nil {
+			add(LintFinding{
+				RuleID: LintRuleUnreachable, Severity: LintWarning, File: n.file, Line: n.line, Node: n.rel.ID,
+				Message: fmt.Sprintf("release %s can never run: it depends on %s, which is blocked", n.rel.Name, b.rel.Name),
+			})
+		}
+	}
+}
+
+func lintHooks(root string, cfg StackHooksConfig, what, file, node string, add func(LintFinding)) {
+	check := func(phase string, hooks []HookSpec) {
+		for i, h := range hooks {
+			if h.Timeout != nil && *h.Timeout > 0 {
+				continue
+			}
+			name := strings.TrimSpace(h.Name)
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			line := 0
+			if h.Name != "" {
+				line = lintFindLine(filepath.Join(root, file), h.Name)
+			}
+			add(LintFinding{
+				RuleID: LintRuleHookWithoutTimeout, Severity: LintWarning, File: file, Line: line, Node: node,
+				Message: fmt.Sprintf("%s%s hook %s has no timeout", what, phase, name),
+			})
+		}
+	}
+	check("preApply", cfg.PreApply)
+	check("postApply", cfg.PostApply)
+	check("preDelete", cfg.PreDelete)
+	check("postDelete", cfg.PostDelete)
+}
+
+// lintReleaseFile returns the file a discovered release was declared in, relative to root.
+func lintReleaseFile(root string, dr discoveredRelease) string {
+	name := stackFileName
+	if dr.FromFile != nil {
+		name = releaseFileName
+	}
+	return lintRel(root, filepath.Join(dr.Dir, name))
+}
+
+func lintRel(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// lintFindLine returns the first line of path that reads `name: <name>` (optionally as a list
+// item or quoted), or 0. It is a best-effort location for editors and SARIF viewers, not a YAML
+// parse.
+func lintFindLine(path, name string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(sc.Text()), "-"))
+		value, ok := strings.CutPrefix(text, "name:")
+		if ok && strings.Trim(strings.TrimSpace(value), `"'`) == name {
+			return line
+		}
+	}
+	return 0
+}
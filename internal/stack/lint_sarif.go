@@ -0,0 +1,127 @@
+// File: internal/stack/lint_sarif.go
+// Brief: SARIF 2.1.0 output for ktl stack lint.
+
+package stack
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/kubekattle/ktl/internal/version"
+)
+
+// Minimal SARIF 2.1.0 document for GitHub code scanning and other CI consumers: the rule index
+// plus one result per finding, located at the stack file that declares the release.
+
+type lintSARIFLog struct {
+	Version string         `json:"version"`
+	Schema  string         `json:"$schema"`
+	Runs    []lintSARIFRun `json:"runs"`
+}
+
+type lintSARIFRun struct {
+	Tool    lintSARIFTool     `json:"tool"`
+	Results []lintSARIFResult `json:"results"`
+}
+
+type lintSARIFTool struct {
+	Driver lintSARIFDriver `json:"driver"`
+}
+
+type lintSARIFDriver struct {
+	Name           string          `json:"name"`
+	Version        string          `json:"version,omitempty"`
+	InformationURI string          `json:"informationUri,omitempty"`
+	Rules          []lintSARIFRule `json:"rules"`
+}
+
+type lintSARIFRule struct {
+	ID               string           `json:"id"`
+	ShortDescription lintSARIFMessage `json:"shortDescription"`
+}
+
+type lintSARIFResult struct {
+	RuleID    string              `json:"ruleId"`
+	Level     string              `json:"level"`
+	Message   lintSARIFMessage    `json:"message"`
+	Locations []lintSARIFLocation `json:"locations,omitempty"`
+}
+
+type lintSARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type lintSARIFLocation struct {
+	PhysicalLocation lintSARIFPhysical `json:"physicalLocation"`
+}
+
+type lintSARIFPhysical struct {
+	ArtifactLocation lintSARIFArtifact `json:"artifactLocation"`
+	Region           *lintSARIFRegion  `json:"region,omitempty"`
+}
+
+type lintSARIFArtifact struct {
+	URI string `json:"uri"`
+}
+
+type lintSARIFRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteLintSARIF writes rep as a SARIF 2.1.0 log. Every known rule is listed so code scanning
+// can close alerts for rules that no longer fire.
+func WriteLintSARIF(w io.Writer, rep LintReport) error {
+	ids := make([]string, 0, len(LintRules))
+	for id := range LintRules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	rules := make([]lintSARIFRule, 0, len(ids))
+	for _, id := range ids {
+		rules = append(rules, lintSARIFRule{ID: id, ShortDescription: lintSARIFMessage{Text: LintRules[id]}})
+	}
+
+	results := make([]lintSARIFResult, 0, len(rep.Findings))
+	for _, f := range rep.Findings {
+		res := lintSARIFResult{RuleID: f.RuleID, Level: lintSARIFLevel(f.Severity), Message: lintSARIFMessage{Text: f.Message}}
+		file := f.File
+		if file == "" {
+			file = stackFileName
+		}
+		loc := lintSARIFLocation{PhysicalLocation: lintSARIFPhysical{ArtifactLocation: lintSARIFArtifact{URI: file}}}
+		if f.Line > 0 {
+			loc.PhysicalLocation.Region = &lintSARIFRegion{StartLine: f.Line}
+		}
+		res.Locations = []lintSARIFLocation{loc}
+		results = append(results, res)
+	}
+
+	doc := lintSARIFLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []lintSARIFRun{{
+			Tool: lintSARIFTool{Driver: lintSARIFDriver{
+				Name:           "ktl stack lint",
+				Version:        version.Get().Version,
+				InformationURI: "https://github.com/kubekattle/ktl",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func lintSARIFLevel(sev LintSeverity) string {
+	switch sev {
+	case LintError:
+		return "error"
+	case LintWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
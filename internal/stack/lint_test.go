@@ -0,0 +1,149 @@
+package stack
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint_ReportsAllRules(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "values", "common.yaml"), "replicas: 1\n")
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+defaults:
+  cluster: { name: c1 }
+  namespace: apps
+hooks:
+  preApply:
+    - name: announce
+      runOnce: true
+      type: script
+      script: { command: [echo, hi] }
+releases:
+  - name: db
+    chart: ./charts/db
+    values: [./values/common.yaml, ./values/missing.yaml]
+    critical: true
+  - name: a
+    chart: ./charts/a
+    needs: [b]
+  - name: b
+    chart: ./charts/b
+    needs: [a]
+  - name: web
+    chart: ./charts/web
+    needs: [a, db]
+  - name: worker
+    chart: ./charts/worker
+    needs: [queue]
+  - name: db
+    chart: ./charts/db2
+`)
+	writeFile(t, filepath.Join(root, "svc", "release.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Release
+name: svc
+chart: ./chart
+hooks:
+  postApply:
+    - name: smoke
+      type: script
+      timeout: 30s
+      script: { command: [true] }
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	rep := Lint(u, LintOptions{})
+
+	got := map[string][]string{}
+	for _, f := range rep.Findings {
+		got[f.RuleID] = append(got[f.RuleID], f.Message)
+		if f.File == "svc/release.yaml" {
+			t.Fatalf("unexpected finding for the clean release: %s", f)
+		}
+	}
+	want := map[string]int{
+		LintRuleDuplicateName:         1,
+		LintRuleMissingNeed:           1,
+		LintRuleCycle:                 1,
+		LintRuleUnreachable:           1,
+		LintRuleMissingValuesFile:     1,
+		LintRuleHookWithoutTimeout:    1,
+		LintRuleCriticalWithoutVerify: 1,
+	}
+	for rule, n := range want {
+		if len(got[rule]) != n {
+			t.Fatalf("rule %s: expected %d finding(s), got %v (all: %v)", rule, n, got[rule], rep.Findings)
+		}
+	}
+	if !strings.Contains(got[LintRuleCycle][0], "a -> b -> a") {
+		t.Fatalf("unexpected cycle message %q", got[LintRuleCycle][0])
+	}
+	if !strings.Contains(got[LintRuleUnreachable][0], "release web") {
+		t.Fatalf("unexpected unreachable message %q", got[LintRuleUnreachable][0])
+	}
+	if !strings.Contains(got[LintRuleMissingValuesFile][0], "values/missing.yaml") {
+		t.Fatalf("unexpected values message %q", got[LintRuleMissingValuesFile][0])
+	}
+	if rep.Errors != 4 || rep.Warnings != 3 {
+		t.Fatalf("expected 4 errors and 3 warnings, got %d/%d", rep.Errors, rep.Warnings)
+	}
+	for _, f := range rep.Findings {
+		if f.RuleID == LintRuleHookWithoutTimeout && (f.File != "stack.yaml" || f.Line != 10) {
+			t.Fatalf("expected the hook finding at stack.yaml:10, got %s", f)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLintSARIF(&buf, rep); err != nil {
+		t.Fatalf("sarif: %v", err)
+	}
+	var doc struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+				Level  string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("decode sarif: %v", err)
+	}
+	if doc.Version != "2.1.0" || len(doc.Runs) != 1 || len(doc.Runs[0].Results) != len(rep.Findings) {
+		t.Fatalf("unexpected sarif document: %s", buf.String())
+	}
+}
+
+func TestLint_CleanStack(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+defaults:
+  cluster: { name: c1 }
+  namespace: apps
+releases:
+  - name: db
+    chart: ./charts/db
+  - name: api
+    chart: ./charts/api
+    needs: [db]
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	rep := Lint(u, LintOptions{})
+	if len(rep.Findings) != 0 || rep.Releases != 2 {
+		t.Fatalf("expected a clean report, got %+v", rep)
+	}
+}
@@ -0,0 +1,615 @@
+// File: internal/stack/manifests.go
+// Brief: `type: manifests` nodes: plain YAML directories applied with server-side apply.
+
+package stack
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/netconfig"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	cliValues "helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/getter"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	manifestsFieldManager      = "ktl-stack"
+	manifestsInventoryType     = corev1.SecretType("ktl.dev/manifests-inventory")
+	manifestsInventoryKey      = "inventory.json"
+	manifestsManifestKey       = "manifest.yaml.gz"
+	legacyManifestsManifestKey = "manifest.yaml"
+	// manifestsInventoryMaxBytes leaves headroom under the 1 MiB object size limit.
+	manifestsInventoryMaxBytes = 900 << 10
+)
+
+// manifestInventory is what a manifests node stores in its inventory Secret: the objects it owns
+// and the manifest last applied (Secret values redacted), which plays the role of a Helm release
+// manifest for plans.
+type manifestInventory struct {
+	Objects  []kube.AppliedObject
+	Manifest string
+}
+
+// nodeSourceProblem describes what is wrong with a node's chart/manifests/task source, or returns ""
+// when the node is valid. Messages start with the offending field so callers can prefix a path.
+func nodeSourceProblem(nodeType, chartRef string, m *ManifestsSpec, t *TaskSpec) string {
+	switch strings.TrimSpace(nodeType) {
+	case "", NodeTypeHelm:
+		if strings.TrimSpace(chartRef) == "" {
+			return "chart is required"
+		}
+	case NodeTypeManifests:
+		if m == nil || strings.TrimSpace(m.Path) == "" {
+			return "manifests.path is required for type manifests"
+		}
+		if strings.TrimSpace(chartRef) != "" {
+			return "chart cannot be combined with type manifests"
+		}
+	case NodeTypeTask:
+		if t == nil || (strings.TrimSpace(t.Image) == "" && len(t.Run) == 0) {
+			return "task.image or task.run is required for type task"
+		}
+		if strings.TrimSpace(t.Image) != "" && len(t.Run) > 0 {
+			return "task.image and task.run are mutually exclusive"
+		}
+		if strings.TrimSpace(chartRef) != "" {
+			return "chart cannot be combined with type task"
+		}
+	default:
+		return fmt.Sprintf("type must be %s, %s, or %s (got %q)", NodeTypeHelm, NodeTypeManifests, NodeTypeTask, nodeType)
+	}
+	return ""
+}
+
+type manifestFile struct {
+	Rel  string
+	Data []byte
+}
+
+// readManifestFiles returns the YAML/JSON files under dir in lexical order of their
+// slash-separated relative paths.
+func readManifestFiles(dir string) ([]manifestFile, error) {
+	st, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("manifests path: %w", err)
+	}
+	if !st.IsDir() {
+		return nil, fmt.Errorf("manifests path %s is not a directory", dir)
+	}
+	var out []manifestFile
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		out = append(out, manifestFile{Rel: filepath.ToSlash(rel), Data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Rel < out[j].Rel })
+	if len(out) == 0 {
+		return nil, fmt.Errorf("manifests path %s contains no .yaml, .yml, or .json files", dir)
+	}
+	return out, nil
+}
+
+// renderManifests returns the node's manifests as one multi-document YAML stream, rendered
+// through Helm's template engine when manifests.template is set.
+func renderManifests(ctx context.Context, node *ResolvedRelease, secrets *deploy.SecretOptions) (string, error) {
+	if node == nil || node.Manifests == nil {
+		return "", fmt.Errorf("node has no manifests")
+	}
+	files, err := readManifestFiles(node.Manifests.Path)
+	if err != nil {
+		return "", err
+	}
+	docs := make([]string, 0, len(files))
+	if !node.Manifests.Template {
+		for _, f := range files {
+			docs = append(docs, string(f.Data))
+		}
+		return joinManifestDocs(docs), nil
+	}
+
+	valuesFiles, err := releaseValuesFiles(ctx, node, secrets)
+	if err != nil {
+		return "", err
+	}
+	valOpts := &cliValues.Options{ValueFiles: valuesFiles, Values: flattenSet(node.Set)}
+	userVals, err := valOpts.MergeValues(getter.All(cli.New(), netconfig.GetterOptions()...))
+	if err != nil {
+		return "", fmt.Errorf("merge values: %w", err)
+	}
+	ch := &chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: node.Name, Version: "0.0.0"}}
+	for _, f := range files {
+		ch.Templates = append(ch.Templates, &chart.File{Name: "templates/" + f.Rel, Data: f.Data})
+	}
+	vals, err := chartutil.ToRenderValues(ch, userVals, chartutil.ReleaseOptions{
+		Name:      node.Name,
+		Namespace: node.Namespace,
+		IsInstall: true,
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	rendered, err := engine.Render(ch, vals)
+	if err != nil {
+		return "", fmt.Errorf("render manifests: %w", err)
+	}
+	for _, f := range files {
+		if out, ok := rendered[node.Name+"/templates/"+f.Rel]; ok {
+			docs = append(docs, out)
+		}
+	}
+	return joinManifestDocs(docs), nil
+}
+
+func joinManifestDocs(docs []string) string {
+	var b strings.Builder
+	for _, doc := range docs {
+		doc = strings.TrimSpace(doc)
+		if doc == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n---\n")
+		}
+		b.WriteString(strings.TrimPrefix(doc, "---\n"))
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// digestManifests fingerprints a manifests node's files and options for the effective input hash.
+func digestManifests(m *ManifestsSpec) (EffectiveChartInput, error) {
+	if m == nil {
+		return EffectiveChartInput{}, fmt.Errorf("manifests path is required")
+	}
+	files, err := readManifestFiles(m.Path)
+	if err != nil {
+		return EffectiveChartInput{}, err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "template=%t\nprune=%t\n", m.Template, manifestsPrune(m))
+	for _, f := range files {
+		sum := sha256.Sum256(f.Data)
+		fmt.Fprintf(h, "%s\x00%s\n", f.Rel, hex.EncodeToString(sum[:]))
+	}
+	return EffectiveChartInput{
+		Ref:    m.Path,
+		Digest: "sha256:" + hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+func manifestsPrune(m *ManifestsSpec) bool {
+	if m == nil || m.Prune == nil {
+		return true
+	}
+	return *m.Prune
+}
+
+// nodeSourceLabel is what plans and tables print in the chart column for non-Helm nodes.
+func nodeSourceLabel(n *ResolvedRelease) string {
+	if n.IsManifests() && n.Manifests != nil {
+		return "manifests:" + n.Manifests.Path
+	}
+	if n.IsTask() && n.Task != nil {
+		if n.Task.Image != "" {
+			return "task:" + n.Task.Image
+		}
+		return "task:" + strings.Join(n.Task.Run, " ")
+	}
+	return n.Chart
+}
+
+// runManifestsNode applies or deletes a manifests node. Objects applied by the node are recorded
+// in an inventory Secret (ktl-manifests-<name>) so later runs can prune removed objects and
+// delete can remove everything the node owns.
+func (e *helmExecutor) runManifestsNode(ctx context.Context, kubeClient *kube.Client, node *runNode, command, clusterKey string) error {
+	obs := &stackEventObserver{run: e.run, node: node, persistDiff: e.helmLogs}
+	switch command {
+	case "apply":
+		return e.applyManifestsNode(ctx, kubeClient, obs, node, clusterKey)
+	case "delete":
+		if e.run != nil {
+			e.run.AppendEvent(node.ID, PhaseStarted, node.Attempt, "destroy", map[string]any{"phase": "destroy"}, nil)
+		}
+		inv, err := loadManifestInventory(ctx, kubeClient, node.Namespace, node.Name)
+		if err == nil {
+			err = deleteManifestObjects(ctx, kubeClient, inv.Objects)
+		}
+		if err == nil {
+			err = deleteManifestInventory(ctx, kubeClient, node.Namespace, node.Name)
+		}
+		if err != nil {
+			if e.run != nil {
+				e.run.AppendEvent(node.ID, PhaseCompleted, node.Attempt, "destroy failure", map[string]any{"phase": "destroy", "status": "failure"}, nil)
+			}
+			return wrapNodeErr(node.ResolvedRelease, err)
+		}
+		if e.run != nil {
+			e.run.AppendEvent(node.ID, PhaseCompleted, node.Attempt, "destroy success", map[string]any{"phase": "destroy", "status": "success"}, nil)
+		}
+		return nil
+	default:
+		return wrapNodeErr(node.ResolvedRelease, fmt.Errorf("unknown command %q", command))
+	}
+}
+
+func (e *helmExecutor) applyManifestsNode(ctx context.Context, kubeClient *kube.Client, obs *stackEventObserver, node *runNode, clusterKey string) error {
+	timeout := 5 * time.Minute
+	if node.Apply.Timeout != nil {
+		timeout = *node.Apply.Timeout
+	}
+	wait := true
+	if node.Apply.Wait != nil {
+		wait = *node.Apply.Wait
+	}
+
+	obs.PhaseStarted(deploy.PhaseRender)
+	manifest, err := renderManifests(ctx, node.ResolvedRelease, e.secrets)
+	if err != nil {
+		obs.PhaseCompleted(deploy.PhaseRender, "failed", err.Error())
+		return wrapNodeErr(node.ResolvedRelease, err)
+	}
+	obs.PhaseCompleted(deploy.PhaseRender, "succeeded", "Manifests rendered")
+
+	if node.resume != nil && node.resume.VerifyOnly {
+		obs.PhaseCompleted(deploy.PhaseInstall, "skipped", "Resume verify-only skipped")
+		obs.PhaseCompleted(deploy.PhaseWait, "skipped", "Resume verify-only skipped")
+		if err := maybeVerify(ctx, e.run, clusterKey, kubeClient, obs, node, manifest, node.Verify, e.dryRun); err != nil {
+			return wrapNodeErr(node.ResolvedRelease, err)
+		}
+		return nil
+	}
+	if !e.dryRun {
+		if err := waitForNodeDependencies(ctx, e.run, kubeClient, node); err != nil {
+			return wrapNodeErr(node.ResolvedRelease, err)
+		}
+		if node.Apply.CreateNamespace != nil && *node.Apply.CreateNamespace {
+			if err := ensureNamespace(ctx, kubeClient, node.Namespace); err != nil {
+				return wrapNodeErr(node.ResolvedRelease, err)
+			}
+		}
+	}
+
+	obs.PhaseStarted(deploy.PhaseInstall)
+	applied, applyErr := kube.ApplyManifest(ctx, kubeClient, []byte(manifest), kube.ApplyOptions{
+		FieldManager: manifestsFieldManager,
+		Namespace:    node.Namespace,
+		Force:        true,
+		DryRun:       e.dryRun,
+	})
+	if e.dryRun {
+		if applyErr != nil {
+			obs.PhaseCompleted(deploy.PhaseInstall, "failed", applyErr.Error())
+			return wrapNodeErr(node.ResolvedRelease, applyErr)
+		}
+		obs.PhaseCompleted(deploy.PhaseInstall, "succeeded", fmt.Sprintf("Server dry-run applied %d objects", len(applied)))
+		return nil
+	}
+
+	prev, err := loadManifestInventory(ctx, kubeClient, node.Namespace, node.Name)
+	if err != nil {
+		obs.PhaseCompleted(deploy.PhaseInstall, "failed", err.Error())
+		return wrapNodeErr(node.ResolvedRelease, err)
+	}
+	if applyErr != nil {
+		// Keep ownership of everything touched so far so a later run can still prune it.
+		_ = saveManifestInventory(ctx, kubeClient, node.Namespace, node.Name, manifestInventory{Objects: mergeInventory(prev.Objects, applied), Manifest: prev.Manifest})
+		obs.PhaseCompleted(deploy.PhaseInstall, "failed", applyErr.Error())
+		return wrapNodeErr(node.ResolvedRelease, applyErr)
+	}
+	pruned := 0
+	if manifestsPrune(node.Manifests) {
+		stale := staleInventory(prev.Objects, applied)
+		if err := deleteManifestObjects(ctx, kubeClient, stale); err != nil {
+			obs.PhaseCompleted(deploy.PhaseInstall, "failed", err.Error())
+			return wrapNodeErr(node.ResolvedRelease, err)
+		}
+		pruned = len(stale)
+	} else {
+		applied = mergeInventory(prev.Objects, applied)
+	}
+	if err := saveManifestInventory(ctx, kubeClient, node.Namespace, node.Name, manifestInventory{Objects: applied, Manifest: manifest}); err != nil {
+		obs.PhaseCompleted(deploy.PhaseInstall, "failed", err.Error())
+		return wrapNodeErr(node.ResolvedRelease, err)
+	}
+	obs.PhaseCompleted(deploy.PhaseInstall, "succeeded", fmt.Sprintf("Applied %d objects, pruned %d", len(applied), pruned))
+
+	if wait {
+		obs.PhaseStarted(deploy.PhaseWait)
+		if err := waitForManifestsReady(ctx, e.run, obs, kubeClient, node, manifest, timeout); err != nil {
+			return wrapNodeErr(node.ResolvedRelease, err)
+		}
+	} else {
+		obs.PhaseCompleted(deploy.PhaseWait, "skipped", "Wait disabled")
+	}
+	if err := maybeVerify(ctx, e.run, clusterKey, kubeClient, obs, node, manifest, node.Verify, e.dryRun); err != nil {
+		return wrapNodeErr(node.ResolvedRelease, err)
+	}
+	return nil
+}
+
+func waitForManifestsReady(ctx context.Context, run *runState, obs *stackEventObserver, kubeClient *kube.Client, node *runNode, manifest string, timeout time.Duration) error {
+	tracker := deploy.NewResourceTracker(kubeClient, node.Namespace, node.Name, manifest, nil)
+	deadline := time.Now().Add(timeout)
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		rows := tracker.Snapshot(ctx)
+		if allReleaseResourcesReady(rows) {
+			obs.PhaseCompleted(deploy.PhaseWait, "succeeded", "Manifest resources ready")
+			return nil
+		}
+		if time.Now().After(deadline) {
+			for _, b := range deploy.TopBlockers(rows, 6) {
+				if run != nil {
+					run.EmitEphemeralEvent(node.ID, NodeLog, node.Attempt, fmt.Sprintf("%s/%s\t%s\t%s", b.Kind, b.Name, b.Status, strings.TrimSpace(b.Reason)), map[string]any{"kind": "top-blocker"})
+				}
+			}
+			err := fmt.Errorf("wait: timeout after %s", timeout.String())
+			obs.PhaseCompleted(deploy.PhaseWait, "failed", err.Error())
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func ensureNamespace(ctx context.Context, kubeClient *kube.Client, name string) error {
+	if kubeClient == nil || kubeClient.Clientset == nil || strings.TrimSpace(name) == "" {
+		return nil
+	}
+	_, err := kubeClient.Clientset.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("create namespace %s: %w", name, err)
+	}
+	return nil
+}
+
+func manifestInventoryName(node string) string {
+	return "ktl-manifests-" + node
+}
+
+func inventoryKey(o kube.AppliedObject) string {
+	group := schema.FromAPIVersionAndKind(o.APIVersion, o.Kind).Group
+	return group + "/" + o.Kind + "/" + o.Namespace + "/" + o.Name
+}
+
+// staleInventory returns the objects in prev that are not in current.
+func staleInventory(prev, current []kube.AppliedObject) []kube.AppliedObject {
+	keep := make(map[string]struct{}, len(current))
+	for _, o := range current {
+		keep[inventoryKey(o)] = struct{}{}
+	}
+	var stale []kube.AppliedObject
+	for _, o := range prev {
+		if _, ok := keep[inventoryKey(o)]; !ok {
+			stale = append(stale, o)
+		}
+	}
+	return stale
+}
+
+func mergeInventory(prev, current []kube.AppliedObject) []kube.AppliedObject {
+	out := append([]kube.AppliedObject(nil), current...)
+	return append(out, staleInventory(prev, current)...)
+}
+
+func loadManifestInventory(ctx context.Context, kubeClient *kube.Client, namespace, node string) (manifestInventory, error) {
+	var inv manifestInventory
+	secret, err := kubeClient.Clientset.CoreV1().Secrets(namespace).Get(ctx, manifestInventoryName(node), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return loadLegacyManifestInventory(ctx, kubeClient, namespace, node)
+	}
+	if err != nil {
+		return inv, fmt.Errorf("read manifests inventory: %w", err)
+	}
+	if raw := secret.Data[manifestsInventoryKey]; len(raw) > 0 {
+		if err := json.Unmarshal(raw, &inv.Objects); err != nil {
+			return inv, fmt.Errorf("parse manifests inventory %s/%s: %w", namespace, secret.Name, err)
+		}
+	}
+	if raw := secret.Data[manifestsManifestKey]; len(raw) > 0 {
+		zr, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return inv, fmt.Errorf("parse manifests inventory %s/%s: %w", namespace, secret.Name, err)
+		}
+		manifest, err := io.ReadAll(zr)
+		if err != nil {
+			return inv, fmt.Errorf("parse manifests inventory %s/%s: %w", namespace, secret.Name, err)
+		}
+		inv.Manifest = string(manifest)
+	}
+	return inv, nil
+}
+
+// loadLegacyManifestInventory reads the ConfigMap inventory written by earlier ktl versions. It
+// is replaced by a Secret on the node's next apply.
+func loadLegacyManifestInventory(ctx context.Context, kubeClient *kube.Client, namespace, node string) (manifestInventory, error) {
+	var inv manifestInventory
+	cm, err := kubeClient.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, manifestInventoryName(node), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return inv, nil
+	}
+	if err != nil {
+		return inv, fmt.Errorf("read manifests inventory: %w", err)
+	}
+	if raw := cm.Data[manifestsInventoryKey]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &inv.Objects); err != nil {
+			return inv, fmt.Errorf("parse manifests inventory %s/%s: %w", namespace, cm.Name, err)
+		}
+	}
+	inv.Manifest = redactManifestSecrets(cm.Data[legacyManifestsManifestKey])
+	return inv, nil
+}
+
+// saveManifestInventory stores inv in the node's inventory Secret. The manifest is kept with
+// Secret values replaced by digests and gzip-compressed; when it is still too large for the
+// object size limit only the object list is kept and the next plan diffs against nothing.
+func saveManifestInventory(ctx context.Context, kubeClient *kube.Client, namespace, node string, inv manifestInventory) error {
+	raw, err := json.Marshal(inv.Objects)
+	if err != nil {
+		return err
+	}
+	var manifest bytes.Buffer
+	zw := gzip.NewWriter(&manifest)
+	if _, err := zw.Write([]byte(redactManifestSecrets(inv.Manifest))); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	data := map[string][]byte{manifestsInventoryKey: raw}
+	if len(raw)+manifest.Len() <= manifestsInventoryMaxBytes {
+		data[manifestsManifestKey] = manifest.Bytes()
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      manifestInventoryName(node),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "ktl",
+				"ktl.dev/stack-node":           node,
+			},
+		},
+		Type: manifestsInventoryType,
+		Data: data,
+	}
+	secrets := kubeClient.Clientset.CoreV1().Secrets(namespace)
+	if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("write manifests inventory: %w", err)
+		}
+		if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("write manifests inventory: %w", err)
+		}
+	}
+	// Drop the plaintext ConfigMap inventory of earlier versions now that the Secret holds it.
+	err = kubeClient.Clientset.CoreV1().ConfigMaps(namespace).Delete(ctx, manifestInventoryName(node), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("delete legacy manifests inventory: %w", err)
+	}
+	return nil
+}
+
+func deleteManifestInventory(ctx context.Context, kubeClient *kube.Client, namespace, node string) error {
+	err := kubeClient.Clientset.CoreV1().Secrets(namespace).Delete(ctx, manifestInventoryName(node), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("delete manifests inventory: %w", err)
+	}
+	err = kubeClient.Clientset.CoreV1().ConfigMaps(namespace).Delete(ctx, manifestInventoryName(node), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("delete manifests inventory: %w", err)
+	}
+	return nil
+}
+
+// redactManifestSecrets replaces every value under data and stringData of Secret objects with
+// its sha256 digest. Diffs between redacted manifests still show which keys changed.
+func redactManifestSecrets(manifest string) string {
+	if !strings.Contains(manifest, "Secret") {
+		return manifest
+	}
+	docs := strings.Split(manifest, "\n---")
+	for i, doc := range docs {
+		var obj map[string]any
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj["kind"] != "Secret" {
+			continue
+		}
+		for _, field := range []string{"data", "stringData"} {
+			values, ok := obj[field].(map[string]any)
+			if !ok {
+				continue
+			}
+			for k, v := range values {
+				sum := sha256.Sum256([]byte(fmt.Sprint(v)))
+				values[k] = "sha256:" + hex.EncodeToString(sum[:])
+			}
+		}
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			continue
+		}
+		docs[i] = "\n" + string(out)
+	}
+	return strings.Join(docs, "\n---")
+}
+
+// deleteManifestObjects deletes objs in reverse apply order. Objects (or kinds) that are already
+// gone are skipped.
+func deleteManifestObjects(ctx context.Context, kubeClient *kube.Client, objs []kube.AppliedObject) error {
+	policy := metav1.DeletePropagationBackground
+	for i := len(objs) - 1; i >= 0; i-- {
+		o := objs[i]
+		gvk := schema.FromAPIVersionAndKind(o.APIVersion, o.Kind)
+		mapping, err := kubeClient.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return fmt.Errorf("map %s: %w", o, err)
+		}
+		res := kubeClient.Dynamic.Resource(mapping.Resource)
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			err = res.Namespace(o.Namespace).Delete(ctx, o.Name, metav1.DeleteOptions{PropagationPolicy: &policy})
+		} else {
+			err = res.Delete(ctx, o.Name, metav1.DeleteOptions{PropagationPolicy: &policy})
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("delete %s: %w", o, err)
+		}
+	}
+	return nil
+}
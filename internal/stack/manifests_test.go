@@ -0,0 +1,196 @@
+package stack
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCompile_ManifestsNode(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+defaults:
+  cluster: { name: c1 }
+  namespace: ns1
+releases:
+  - name: crds
+    type: manifests
+    manifests:
+      path: ./crds
+  - name: app
+    chart: ./chart
+    needs: [crds]
+`)
+	writeFile(t, filepath.Join(root, "crds", "a.yaml"), "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n")
+
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	p, err := Compile(u, CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	byName := map[string]*ResolvedRelease{}
+	for _, n := range p.Nodes {
+		byName[n.Name] = n
+	}
+	crds := byName["crds"]
+	if crds == nil || !crds.IsManifests() || crds.Manifests.Path != filepath.Join(root, "crds") {
+		t.Fatalf("unexpected manifests node %+v", crds)
+	}
+	if byName["app"].IsManifests() {
+		t.Fatalf("expected app to stay a helm node")
+	}
+	if got := nodeSourceLabel(crds); got != "manifests:"+filepath.Join(root, "crds") {
+		t.Fatalf("label=%q", got)
+	}
+}
+
+func TestDiscover_RejectsChartWithManifestsType(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+releases:
+  - name: bad
+    type: manifests
+    chart: ./chart
+    manifests: { path: ./k8s }
+`)
+	if _, err := Discover(root); err == nil || !strings.Contains(err.Error(), "chart cannot be combined") {
+		t.Fatalf("expected type/chart conflict, got %v", err)
+	}
+}
+
+func TestRenderManifests_PlainAndTemplated(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "b", "svc.yaml"), "apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}-svc\n  namespace: {{ .Release.Namespace }}\n")
+	writeFile(t, filepath.Join(dir, "a.yaml"), "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\ndata:\n  replicas: \"{{ .Values.replicas }}\"\n")
+	writeFile(t, filepath.Join(dir, "README.md"), "ignored")
+	writeFile(t, filepath.Join(dir, ".git", "x.yaml"), "ignored: true")
+
+	node := &ResolvedRelease{Name: "web", Namespace: "prod", Set: map[string]string{"replicas": "3"}, Manifests: &ManifestsSpec{Path: dir}}
+	plain, err := renderManifests(context.Background(), node, nil)
+	if err != nil {
+		t.Fatalf("render plain: %v", err)
+	}
+	if strings.Count(plain, "\n---\n") != 1 || strings.Index(plain, "ConfigMap") > strings.Index(plain, "Service") {
+		t.Fatalf("expected two documents in lexical order, got:\n%s", plain)
+	}
+	if !strings.Contains(plain, "{{ .Values.replicas }}") || strings.Contains(plain, "ignored") {
+		t.Fatalf("plain manifests should be passed through untouched:\n%s", plain)
+	}
+
+	node.Manifests.Template = true
+	rendered, err := renderManifests(context.Background(), node, nil)
+	if err != nil {
+		t.Fatalf("render templated: %v", err)
+	}
+	for _, want := range []string{`replicas: "3"`, "name: web-svc", "namespace: prod"} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in:\n%s", want, rendered)
+		}
+	}
+
+	before, err := digestManifests(node.Manifests)
+	if err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	writeFile(t, filepath.Join(dir, "a.yaml"), "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\n")
+	after, err := digestManifests(node.Manifests)
+	if err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	if before.Digest == after.Digest {
+		t.Fatalf("expected digest to change with file contents")
+	}
+}
+
+func TestStaleInventory(t *testing.T) {
+	prev := []kube.AppliedObject{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "ns", Name: "keep"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "ns", Name: "gone"},
+		{APIVersion: "batch/v1", Kind: "Job", Namespace: "ns", Name: "moved"},
+	}
+	current := []kube.AppliedObject{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "ns", Name: "keep"},
+		// Version bumps keep ownership: the group/kind/namespace/name identity is unchanged.
+		{APIVersion: "batch/v2", Kind: "Job", Namespace: "ns", Name: "moved"},
+	}
+	stale := staleInventory(prev, current)
+	if len(stale) != 1 || stale[0].Name != "gone" {
+		t.Fatalf("stale=%v", stale)
+	}
+	if merged := mergeInventory(prev, current); len(merged) != 3 || merged[2].Name != "gone" {
+		t.Fatalf("merged=%v", merged)
+	}
+}
+
+func TestManifestInventoryStoresRedactedManifestInSecret(t *testing.T) {
+	ctx := context.Background()
+	legacy := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ktl-manifests-web", Namespace: "ns"},
+		Data: map[string]string{
+			manifestsInventoryKey:      `[{"apiVersion":"v1","kind":"ConfigMap","namespace":"ns","name":"old"}]`,
+			legacyManifestsManifestKey: "apiVersion: v1\nkind: Secret\nmetadata:\n  name: db\nstringData:\n  password: legacy-pw\n",
+		},
+	}
+	client := fake.NewClientset(legacy)
+	kubeClient := &kube.Client{Clientset: client}
+
+	inv, err := loadManifestInventory(ctx, kubeClient, "ns", "web")
+	if err != nil {
+		t.Fatalf("load legacy: %v", err)
+	}
+	if len(inv.Objects) != 1 || inv.Objects[0].Name != "old" || strings.Contains(inv.Manifest, "legacy-pw") {
+		t.Fatalf("unexpected legacy inventory %+v", inv)
+	}
+
+	manifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\ndata:\n  mode: fast\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: db\ndata:\n  password: czNjcjN0\n"
+	objects := []kube.AppliedObject{{APIVersion: "v1", Kind: "Secret", Namespace: "ns", Name: "db"}}
+	if err := saveManifestInventory(ctx, kubeClient, "ns", "web", manifestInventory{Objects: objects, Manifest: manifest}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := client.CoreV1().ConfigMaps("ns").Get(ctx, "ktl-manifests-web", metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected the plaintext ConfigMap inventory to be removed")
+	}
+	secret, err := client.CoreV1().Secrets("ns").Get(ctx, "ktl-manifests-web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get inventory secret: %v", err)
+	}
+	if secret.Type != manifestsInventoryType || strings.Contains(string(secret.Data[manifestsManifestKey]), "czNjcjN0") {
+		t.Fatalf("unexpected inventory secret %+v", secret)
+	}
+
+	inv, err = loadManifestInventory(ctx, kubeClient, "ns", "web")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(inv.Objects) != 1 || inv.Objects[0].Name != "db" {
+		t.Fatalf("objects=%v", inv.Objects)
+	}
+	if strings.Contains(inv.Manifest, "czNjcjN0") || !strings.Contains(inv.Manifest, "password: sha256:") || !strings.Contains(inv.Manifest, "mode: fast") {
+		t.Fatalf("expected Secret values replaced by digests, got:\n%s", inv.Manifest)
+	}
+	if inv.Manifest != redactManifestSecrets(manifest) {
+		t.Fatalf("stored manifest should match a redacted fresh render")
+	}
+
+	if err := deleteManifestInventory(ctx, kubeClient, "ns", "web"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := client.CoreV1().Secrets("ns").Get(ctx, "ktl-manifests-web", metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected the inventory secret to be deleted")
+	}
+}
@@ -17,6 +17,7 @@ func mergeDefaults(dst *ResolvedRelease, baseDir string, d ReleaseDefaults) {
 	if d.Cluster.Context != "" {
 		dst.Cluster.Context = d.Cluster.Context
 	}
+	mergeExecCredentialEnv(&dst.Cluster, d.Cluster.ExecCredentialEnv)
 	if d.Namespace != "" {
 		dst.Namespace = d.Namespace
 	}
@@ -35,7 +36,28 @@ func mergeDefaults(dst *ResolvedRelease, baseDir string, d ReleaseDefaults) {
 
 	mergeApply(&dst.Apply, d.Apply)
 	mergeDelete(&dst.Delete, d.Delete)
-	mergeVerify(&dst.Verify, d.Verify)
+	mergeVerify(&dst.Verify, baseDir, d.Verify)
+	mergeOwner(dst, d.Owner)
+}
+
+// mergeOwner overrides the owner field by field, so a release can change the Slack channel and
+// keep the team from its defaults.
+func mergeOwner(dst *ResolvedRelease, src *OwnerSpec) {
+	if src == nil {
+		return
+	}
+	if dst.Owner == nil {
+		dst.Owner = &OwnerSpec{}
+	}
+	if src.Team != "" {
+		dst.Owner.Team = src.Team
+	}
+	if src.Slack != "" {
+		dst.Owner.Slack = src.Slack
+	}
+	if src.Oncall != "" {
+		dst.Owner.Oncall = src.Oncall
+	}
 }
 
 func mergeApply(dst *ApplyOptions, src ApplyOptions) {
@@ -51,6 +73,12 @@ func mergeApply(dst *ApplyOptions, src ApplyOptions) {
 	if src.CreateNamespace != nil {
 		dst.CreateNamespace = src.CreateNamespace
 	}
+	if src.Images != nil {
+		dst.Images = src.Images
+	}
+	if src.RequireApproval != nil {
+		dst.RequireApproval = src.RequireApproval
+	}
 }
 
 func mergeDelete(dst *DeleteOptions, src DeleteOptions) {
@@ -59,7 +87,7 @@ func mergeDelete(dst *DeleteOptions, src DeleteOptions) {
 	}
 }
 
-func mergeVerify(dst *VerifyOptions, src VerifyOptions) {
+func mergeVerify(dst *VerifyOptions, baseDir string, src VerifyOptions) {
 	if src.Enabled != nil {
 		dst.Enabled = src.Enabled
 	}
@@ -84,15 +112,43 @@ func mergeVerify(dst *VerifyOptions, src VerifyOptions) {
 	if len(src.RequireConditions) > 0 {
 		dst.RequireConditions = append([]VerifyConditionRequirement(nil), src.RequireConditions...)
 	}
+	if src.Config != "" {
+		dst.Config = resolvePath(baseDir, src.Config)
+	}
+	if src.Rules != nil {
+		rules := *src.Rules
+		rules.RulesDir = resolvePath(baseDir, rules.RulesDir)
+		rules.RulesPath = resolvePaths(baseDir, rules.RulesPath)
+		dst.Rules = &rules
+	}
 }
 
 func mergeReleaseOverride(dst *ResolvedRelease, baseDir string, r ReleaseSpec) {
 	if r.Name != "" {
 		dst.Name = r.Name
 	}
+	if r.Type != "" {
+		dst.Type = r.Type
+	}
+	if r.Enabled != "" {
+		dst.Enabled = r.Enabled
+		dst.enabledBy = ""
+	}
 	if r.Chart != "" {
 		dst.Chart = resolvePath(baseDir, r.Chart)
 	}
+	if r.Manifests != nil {
+		m := *r.Manifests
+		m.Path = resolvePath(baseDir, m.Path)
+		dst.Manifests = &m
+	}
+	if r.Task != nil {
+		t := *r.Task
+		if t.WorkDir != "" {
+			t.WorkDir = resolvePath(baseDir, t.WorkDir)
+		}
+		dst.Task = &t
+	}
 	if r.ChartVersion != "" {
 		dst.ChartVersion = r.ChartVersion
 	}
@@ -114,6 +170,7 @@ func mergeReleaseOverride(dst *ResolvedRelease, baseDir string, r ReleaseSpec) {
 	if r.Cluster.Context != "" {
 		dst.Cluster.Context = r.Cluster.Context
 	}
+	mergeExecCredentialEnv(&dst.Cluster, r.Cluster.ExecCredentialEnv)
 	if r.Namespace != "" {
 		dst.Namespace = r.Namespace
 	}
@@ -134,10 +191,17 @@ func mergeReleaseOverride(dst *ResolvedRelease, baseDir string, r ReleaseSpec) {
 	if len(r.Needs) > 0 {
 		dst.Needs = append([]string(nil), r.Needs...)
 	}
+	if len(r.WaitFor) > 0 {
+		dst.WaitFor = append([]string(nil), r.WaitFor...)
+	}
+	if r.WaitForTimeout != nil {
+		dst.WaitForTimeout = r.WaitForTimeout
+	}
 	mergeHooks(dst, baseDir, r.Hooks)
 	mergeApply(&dst.Apply, r.Apply)
 	mergeDelete(&dst.Delete, r.Delete)
-	mergeVerify(&dst.Verify, r.Verify)
+	mergeVerify(&dst.Verify, baseDir, r.Verify)
+	mergeOwner(dst, r.Owner)
 }
 
 func resolvePaths(baseDir string, vals []string) []string {
@@ -150,3 +214,13 @@ func resolvePaths(baseDir string, vals []string) []string {
 	}
 	return out
 }
+
+func mergeExecCredentialEnv(dst *ClusterTarget, env map[string]string) {
+	if len(env) == 0 {
+		return
+	}
+	if dst.ExecCredentialEnv == nil {
+		dst.ExecCredentialEnv = map[string]string{}
+	}
+	maps.Copy(dst.ExecCredentialEnv, env)
+}
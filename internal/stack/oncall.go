@@ -0,0 +1,279 @@
+// File: internal/stack/oncall.go
+// Brief: Paging on-call responders for failed stack runs.
+
+package stack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Paging failure classes. Node errors are folded into these from the finer retry classes.
+const (
+	OncallHookFailed  = "HOOK_FAILED"
+	OncallWaitTimeout = "WAIT_TIMEOUT"
+	OncallHelmError   = "HELM_ERROR"
+)
+
+// OncallProviders lists the supported --notify-oncall providers.
+var OncallProviders = []string{"pagerduty", "opsgenie", "webhook"}
+
+var oncallDefaultURLs = map[string]string{
+	"pagerduty": "https://events.pagerduty.com/v2/enqueue",
+	"opsgenie":  "https://api.opsgenie.com/v2/alerts",
+}
+
+// OncallOptions configures an OncallNotifier.
+type OncallOptions struct {
+	// Provider is pagerduty, opsgenie, or webhook.
+	Provider string
+	// URL overrides the provider endpoint. Required for webhook.
+	URL string
+	// Key is the PagerDuty routing key or Opsgenie API key.
+	Key string
+	// Classes limits paging to these failure classes; empty pages on every class.
+	Classes []string
+	Stack   string
+	Command string
+	Client  *http.Client
+}
+
+// OncallPage is one page: a failed node (or stack hook) with its error digest and hint.
+type OncallPage struct {
+	RunID   string `json:"runId"`
+	Stack   string `json:"stack,omitempty"`
+	Command string `json:"command,omitempty"`
+	Node    string `json:"node"`
+	Class   string `json:"class"`
+	Digest  string `json:"digest,omitempty"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+	TS      string `json:"ts,omitempty"`
+}
+
+// OncallNotifier collects final failures from run events; Send pages them once the run ends.
+// Failures that were retried successfully are never paged.
+type OncallNotifier struct {
+	opts    OncallOptions
+	classes map[string]bool
+
+	mu      sync.Mutex
+	runID   string
+	hooks   map[string]RunEvent
+	pending map[string]OncallPage
+}
+
+// NewOncallNotifier validates opts and returns a notifier to register as a run event observer.
+func NewOncallNotifier(opts OncallOptions) (*OncallNotifier, error) {
+	opts.Provider = strings.ToLower(strings.TrimSpace(opts.Provider))
+	if _, ok := oncallDefaultURLs[opts.Provider]; !ok && opts.Provider != "webhook" {
+		return nil, fmt.Errorf("unsupported on-call provider %q (expected %s)", opts.Provider, strings.Join(OncallProviders, ", "))
+	}
+	opts.URL = strings.TrimSpace(opts.URL)
+	if opts.URL == "" {
+		opts.URL = oncallDefaultURLs[opts.Provider]
+	}
+	if opts.URL == "" {
+		return nil, fmt.Errorf("on-call provider webhook requires a URL")
+	}
+	opts.Key = strings.TrimSpace(opts.Key)
+	if opts.Key == "" && opts.Provider != "webhook" {
+		return nil, fmt.Errorf("on-call provider %s requires a key", opts.Provider)
+	}
+	classes := map[string]bool{}
+	for _, c := range opts.Classes {
+		c = strings.ToUpper(strings.TrimSpace(c))
+		switch c {
+		case "":
+			continue
+		case OncallHookFailed, OncallWaitTimeout, OncallHelmError:
+			classes[c] = true
+		default:
+			return nil, fmt.Errorf("unknown failure class %q (expected %s, %s, or %s)", c, OncallHookFailed, OncallWaitTimeout, OncallHelmError)
+		}
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &OncallNotifier{
+		opts:    opts,
+		classes: classes,
+		hooks:   map[string]RunEvent{},
+		pending: map[string]OncallPage{},
+	}, nil
+}
+
+// OncallClass folds a run error class into a paging class.
+func OncallClass(class string) string {
+	switch strings.ToUpper(strings.TrimSpace(class)) {
+	case "HOOK_FAILED":
+		return OncallHookFailed
+	case "TIMEOUT", "WAIT_TIMEOUT":
+		return OncallWaitTimeout
+	default:
+		return OncallHelmError
+	}
+}
+
+// ObserveRunEvent implements RunEventObserver.
+func (n *OncallNotifier) ObserveRunEvent(ev RunEvent) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if ev.RunID != "" {
+		n.runID = ev.RunID
+	}
+	switch RunEventType(ev.Type) {
+	case HookFailed:
+		if ev.NodeID == "" {
+			n.addLocked(ev, "(stack hooks)", OncallHookFailed)
+			return
+		}
+		n.hooks[ev.NodeID] = ev
+	case NodeFailed:
+		if hook, ok := n.hooks[ev.NodeID]; ok && hook.Attempt == ev.Attempt {
+			n.addLocked(hook, ev.NodeID, OncallHookFailed)
+			return
+		}
+		class := ""
+		if ev.Error != nil {
+			class = ev.Error.Class
+		}
+		n.addLocked(ev, ev.NodeID, OncallClass(class))
+	case RetryScheduled, NodeRunning, NodeSucceeded:
+		delete(n.pending, ev.NodeID)
+		delete(n.hooks, ev.NodeID)
+	}
+}
+
+func (n *OncallNotifier) addLocked(ev RunEvent, node, class string) {
+	if len(n.classes) > 0 && !n.classes[class] {
+		return
+	}
+	page := OncallPage{
+		RunID:   ev.RunID,
+		Stack:   n.opts.Stack,
+		Command: n.opts.Command,
+		Node:    node,
+		Class:   class,
+		Message: strings.TrimSpace(ev.Message),
+		Hint:    remediationHint(class),
+		TS:      ev.TS,
+	}
+	if ev.Error != nil {
+		page.Digest = ev.Error.Digest
+		if msg := strings.TrimSpace(ev.Error.Message); msg != "" {
+			page.Message = msg
+		}
+	}
+	n.pending[node] = page
+}
+
+// Pages returns the failures that would be paged, ordered by node.
+func (n *OncallNotifier) Pages() []OncallPage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	out := make([]OncallPage, 0, len(n.pending))
+	for _, p := range n.pending {
+		if p.RunID == "" {
+			p.RunID = n.runID
+		}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Node < out[j].Node })
+	return out
+}
+
+// Send pages every collected failure and returns how many were sent.
+func (n *OncallNotifier) Send(ctx context.Context) (int, error) {
+	if n == nil {
+		return 0, nil
+	}
+	sent := 0
+	for _, page := range n.Pages() {
+		if err := n.post(ctx, page); err != nil {
+			return sent, fmt.Errorf("page %s for %s: %w", n.opts.Provider, page.Node, err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (n *OncallNotifier) post(ctx context.Context, page OncallPage) error {
+	summary := strings.Join(strings.Fields(fmt.Sprintf("ktl stack %s %s failed: %s [%s]", page.Stack, page.Command, page.Node, page.Class)), " ")
+	dedup := "ktl-" + page.RunID + "-" + page.Node + "-" + page.Digest
+	var body any
+	header := http.Header{}
+	switch n.opts.Provider {
+	case "pagerduty":
+		body = map[string]any{
+			"routing_key":  n.opts.Key,
+			"event_action": "trigger",
+			"dedup_key":    dedup,
+			"payload": map[string]any{
+				"summary":        summary,
+				"source":         "ktl",
+				"severity":       "critical",
+				"component":      page.Node,
+				"class":          page.Class,
+				"custom_details": page,
+			},
+		}
+	case "opsgenie":
+		header.Set("Authorization", "GenieKey "+n.opts.Key)
+		body = map[string]any{
+			"message":     truncateString(summary, 130),
+			"alias":       dedup,
+			"description": page.Message,
+			"source":      "ktl",
+			"priority":    "P1",
+			"details": map[string]string{
+				"runId":  page.RunID,
+				"node":   page.Node,
+				"class":  page.Class,
+				"digest": page.Digest,
+				"hint":   page.Hint,
+			},
+		}
+	default:
+		if n.opts.Key != "" {
+			header.Set("Authorization", "Bearer "+n.opts.Key)
+		}
+		body = page
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.opts.URL, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
@@ -0,0 +1,90 @@
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestOncallNotifier_PagesFinalFailures(t *testing.T) {
+	var mu sync.Mutex
+	var got []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		mu.Lock()
+		got = append(got, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	n, err := NewOncallNotifier(OncallOptions{Provider: "pagerduty", URL: srv.URL, Key: "rk", Stack: "prod", Command: "apply"})
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	timeoutErr := &RunError{Class: "TIMEOUT", Message: "context deadline exceeded", Digest: "sha256:aa"}
+	events := []RunEvent{
+		{RunID: "r1", Type: string(NodeRunning), NodeID: "c/ns/api", Attempt: 1},
+		{RunID: "r1", Type: string(NodeFailed), NodeID: "c/ns/api", Attempt: 1, Error: timeoutErr},
+		{RunID: "r1", Type: string(RetryScheduled), NodeID: "c/ns/api", Attempt: 2, Error: timeoutErr},
+		{RunID: "r1", Type: string(NodeRunning), NodeID: "c/ns/api", Attempt: 2},
+		{RunID: "r1", Type: string(NodeSucceeded), NodeID: "c/ns/api", Attempt: 2},
+		{RunID: "r1", Type: string(NodeRunning), NodeID: "c/ns/db", Attempt: 1},
+		{RunID: "r1", Type: string(HookFailed), NodeID: "c/ns/db", Attempt: 1, Message: "migrate: exit 1", Error: &RunError{Class: "HOOK_FAILED", Message: "exit 1", Digest: "sha256:bb"}},
+		{RunID: "r1", Type: string(NodeFailed), NodeID: "c/ns/db", Attempt: 1, Error: &RunError{Class: "OTHER", Message: "hook failed", Digest: "sha256:cc"}},
+		{RunID: "r1", Type: string(NodeRunning), NodeID: "c/ns/web", Attempt: 1},
+		{RunID: "r1", Type: string(NodeFailed), NodeID: "c/ns/web", Attempt: 1, Error: &RunError{Class: "TIMEOUT", Message: "timed out waiting", Digest: "sha256:dd"}},
+	}
+	for _, ev := range events {
+		n.ObserveRunEvent(ev)
+	}
+
+	pages := n.Pages()
+	if len(pages) != 2 {
+		t.Fatalf("pages=%+v, want db and web", pages)
+	}
+	if pages[0].Node != "c/ns/db" || pages[0].Class != OncallHookFailed || pages[0].Digest != "sha256:bb" || pages[0].Hint == "" {
+		t.Fatalf("unexpected hook page: %+v", pages[0])
+	}
+	if pages[1].Node != "c/ns/web" || pages[1].Class != OncallWaitTimeout || pages[1].Message != "timed out waiting" {
+		t.Fatalf("unexpected timeout page: %+v", pages[1])
+	}
+
+	sent, err := n.Send(context.Background())
+	if err != nil || sent != 2 {
+		t.Fatalf("Send=%d,%v", sent, err)
+	}
+	if got[0]["routing_key"] != "rk" || got[0]["event_action"] != "trigger" {
+		t.Fatalf("unexpected pagerduty body: %v", got[0])
+	}
+	payload := got[0]["payload"].(map[string]any)
+	if payload["summary"] != "ktl stack prod apply failed: c/ns/db [HOOK_FAILED]" {
+		t.Fatalf("summary=%v", payload["summary"])
+	}
+}
+
+func TestOncallNotifier_ClassFilterAndValidation(t *testing.T) {
+	if _, err := NewOncallNotifier(OncallOptions{Provider: "webhook"}); err == nil {
+		t.Fatalf("expected webhook without URL to fail")
+	}
+	if _, err := NewOncallNotifier(OncallOptions{Provider: "opsgenie"}); err == nil {
+		t.Fatalf("expected opsgenie without key to fail")
+	}
+	if _, err := NewOncallNotifier(OncallOptions{Provider: "webhook", URL: "http://x", Classes: []string{"BOGUS"}}); err == nil {
+		t.Fatalf("expected unknown class to fail")
+	}
+	n, err := NewOncallNotifier(OncallOptions{Provider: "webhook", URL: "http://x", Classes: []string{"hook_failed"}})
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	n.ObserveRunEvent(RunEvent{RunID: "r1", Type: string(NodeFailed), NodeID: "a", Attempt: 1, Error: &RunError{Class: "OTHER"}})
+	if pages := n.Pages(); len(pages) != 0 {
+		t.Fatalf("expected HELM_ERROR to be filtered, got %+v", pages)
+	}
+}
@@ -0,0 +1,190 @@
+// File: internal/stack/overlay.go
+// Brief: Overlay files (-f) layered on top of the base stack at compile time.
+
+package stack
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StackOverlayFile is an environment file passed with `ktl stack -f envs/prod.yaml`. It is
+// applied after stack.yaml, release.yaml, and the selected profile, so it can retarget a
+// shared stack without copying it.
+type StackOverlayFile struct {
+	APIVersionKind `yaml:",inline" json:",inline"`
+
+	// Defaults apply to every release before the per-release entries.
+	Defaults ReleaseOverlay `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+	// Clusters merge on top of the stack's clusters section.
+	Clusters map[string]ClusterTarget `yaml:"clusters,omitempty" json:"clusters,omitempty"`
+	// Releases are keyed by release name (all clusters), cluster/name, or the full
+	// cluster/namespace/name ID.
+	Releases map[string]ReleaseOverlay `yaml:"releases,omitempty" json:"releases,omitempty"`
+
+	path string
+}
+
+// ReleaseOverlay is the subset of a release an overlay may change.
+type ReleaseOverlay struct {
+	// Enabled=false drops the release (and needs on it) from the plan.
+	Enabled      *bool             `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Chart        string            `yaml:"chart,omitempty" json:"chart,omitempty"`
+	ChartVersion string            `yaml:"chartVersion,omitempty" json:"chartVersion,omitempty"`
+	Cluster      ClusterTarget     `yaml:"cluster,omitempty" json:"cluster,omitempty"`
+	Namespace    string            `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	Values       []string          `yaml:"values,omitempty" json:"values,omitempty"`
+	Set          map[string]string `yaml:"set,omitempty" json:"set,omitempty"`
+}
+
+// LoadStackOverlay reads an overlay file. Relative paths inside it are resolved against the
+// overlay's directory when it is applied.
+func LoadStackOverlay(path string) (*StackOverlayFile, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+	var of StackOverlayFile
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(&of); err != nil {
+		return nil, fmt.Errorf("parse overlay %s: %w", path, err)
+	}
+	if of.Kind != "" && of.Kind != "StackOverlay" {
+		return nil, fmt.Errorf("%s: kind must be StackOverlay (got %q)", path, of.Kind)
+	}
+	if of.APIVersion != "" && of.APIVersion != "ktl.dev/v1" {
+		return nil, fmt.Errorf("%s: apiVersion must be ktl.dev/v1 (got %q)", path, of.APIVersion)
+	}
+	of.path = abs
+	return &of, nil
+}
+
+func loadStackOverlays(paths []string) ([]*StackOverlayFile, error) {
+	out := make([]*StackOverlayFile, 0, len(paths))
+	for _, p := range paths {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		of, err := LoadStackOverlay(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, of)
+	}
+	return out, nil
+}
+
+// mergeOverlayClusters layers overlay clusters entries over the resolved clusters section.
+func mergeOverlayClusters(clusters map[string]ClusterTarget, overlays []*StackOverlayFile) map[string]ClusterTarget {
+	for _, of := range overlays {
+		for name, ct := range of.Clusters {
+			if clusters == nil {
+				clusters = map[string]ClusterTarget{}
+			}
+			cur := clusters[name]
+			cur.Name = name
+			if ct.Kubeconfig != "" {
+				cur.Kubeconfig = ct.Kubeconfig
+				if !strings.HasPrefix(strings.TrimSpace(ct.Kubeconfig), "~") {
+					cur.Kubeconfig = resolvePath(filepath.Dir(of.path), ct.Kubeconfig)
+				}
+			}
+			if ct.Context != "" {
+				cur.Context = ct.Context
+			}
+			mergeExecCredentialEnv(&cur, ct.ExecCredentialEnv)
+			clusters[name] = cur
+		}
+	}
+	return clusters
+}
+
+// applyStackOverlays applies overlays in order (later files win). Release keys are matched
+// against the nodes as they were before any overlay moved them to another cluster or namespace;
+// a key that matches nothing is an error so typos do not silently deploy the base configuration.
+// Disabled releases are dropped later, with enabled expressions.
+func applyStackOverlays(nodes []*ResolvedRelease, overlays []*StackOverlayFile) error {
+	origID := make(map[*ResolvedRelease]string, len(nodes))
+	for _, n := range nodes {
+		origID[n] = n.ID
+	}
+	for _, of := range overlays {
+		for _, n := range nodes {
+			applyReleaseOverlay(n, of, of.Defaults)
+		}
+		keys := make([]string, 0, len(of.Releases))
+		for k := range of.Releases {
+			keys = append(keys, k)
+		}
+		// Broader keys first so cluster/name and full IDs win over bare names.
+		sort.Slice(keys, func(i, j int) bool {
+			ci, cj := strings.Count(keys[i], "/"), strings.Count(keys[j], "/")
+			if ci != cj {
+				return ci < cj
+			}
+			return keys[i] < keys[j]
+		})
+		for _, key := range keys {
+			matched := false
+			for _, n := range nodes {
+				if !overlayKeyMatches(key, origID[n], n.Name) {
+					continue
+				}
+				matched = true
+				applyReleaseOverlay(n, of, of.Releases[key])
+			}
+			if !matched {
+				return fmt.Errorf("%s: releases.%s does not match any release in the stack", of.path, key)
+			}
+		}
+	}
+	return nil
+}
+
+func overlayKeyMatches(key, id, name string) bool {
+	key = strings.Trim(strings.TrimSpace(key), "/")
+	switch strings.Count(key, "/") {
+	case 0:
+		return key == name
+	case 1:
+		parts := strings.SplitN(id, "/", 3)
+		return len(parts) == 3 && key == parts[0]+"/"+parts[2]
+	default:
+		return key == id
+	}
+}
+
+func applyReleaseOverlay(n *ResolvedRelease, of *StackOverlayFile, o ReleaseOverlay) {
+	baseDir := filepath.Dir(of.path)
+	if o.Enabled != nil {
+		// An explicit overlay decision replaces the release's enabled expression.
+		n.Enabled = strconv.FormatBool(*o.Enabled)
+		n.enabledBy = of.path
+	}
+	if o.Chart != "" && !n.IsManifests() && !n.IsTask() {
+		n.Chart = resolvePath(baseDir, o.Chart)
+	}
+	cluster := o.Cluster
+	if cluster.Kubeconfig != "" && !strings.HasPrefix(strings.TrimSpace(cluster.Kubeconfig), "~") {
+		cluster.Kubeconfig = resolvePath(baseDir, cluster.Kubeconfig)
+	}
+	mergeReleaseOverride(n, baseDir, ReleaseSpec{
+		ChartVersion: o.ChartVersion,
+		Cluster:      cluster,
+		Namespace:    o.Namespace,
+		Values:       o.Values,
+		Set:          o.Set,
+	})
+}
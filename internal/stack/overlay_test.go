@@ -0,0 +1,129 @@
+package stack
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func writeOverlayStack(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+defaults:
+  cluster: { name: c1 }
+  namespace: apps
+  values: [./values/common.yaml]
+releases:
+  - name: db
+    chart: ./charts/db
+    chartVersion: 1.0.0
+  - name: cache
+    chart: ./charts/cache
+  - name: api
+    chart: ./charts/api
+    needs: [db, cache]
+  - name: api
+    chart: ./charts/api
+    cluster: { name: c2 }
+`)
+	return root
+}
+
+func TestCompile_Overlays(t *testing.T) {
+	root := writeOverlayStack(t)
+	writeFile(t, filepath.Join(root, "envs", "prod.yaml"), `
+apiVersion: ktl.dev/v1
+kind: StackOverlay
+defaults:
+  set: { env: prod }
+clusters:
+  c1: { kubeconfig: ./kube/prod.yaml, context: prod }
+releases:
+  db:
+    chartVersion: 2.1.0
+    values: [./values/db.yaml]
+  cache:
+    enabled: false
+  c2/api:
+    namespace: edge
+    set: { replicas: "5" }
+`)
+	writeFile(t, filepath.Join(root, "envs", "hotfix.yaml"), `
+releases:
+  c1/apps/api:
+    chart: oci://ghcr.io/acme/api
+    set: { env: hotfix }
+`)
+	writeFile(t, filepath.Join(root, "envs", "kube", "prod.yaml"), "apiVersion: v1\nkind: Config\ncontexts:\n  - name: prod\n    context: { cluster: prod }\n")
+
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	p, err := Compile(u, CompileOptions{Overlays: []string{
+		filepath.Join(root, "envs", "prod.yaml"),
+		filepath.Join(root, "envs", "hotfix.yaml"),
+	}})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if len(p.Nodes) != 3 || p.ByID["c1/apps/cache"] != nil {
+		t.Fatalf("expected cache to be disabled, got %v", p.Order)
+	}
+	if len(p.Disabled) != 1 || p.Disabled[0].Reason != "enabled: false in overlay "+filepath.Join(root, "envs", "prod.yaml") {
+		t.Fatalf("disabled=%+v", p.Disabled)
+	}
+	db := p.ByID["c1/apps/db"]
+	if db.ChartVersion != "2.1.0" || len(db.Values) != 2 || db.Values[1] != filepath.Join(root, "envs", "values", "db.yaml") {
+		t.Fatalf("db=%+v", db)
+	}
+	if db.Cluster.Kubeconfig != filepath.Join(root, "envs", "kube", "prod.yaml") || db.Cluster.Context != "prod" {
+		t.Fatalf("expected overlay clusters entry to apply, got %+v", db.Cluster)
+	}
+	api := p.ByID["c1/apps/api"]
+	if strings.Join(api.Needs, ",") != "db" || api.Chart != "oci://ghcr.io/acme/api" || api.Set["env"] != "hotfix" {
+		t.Fatalf("api=%+v", api)
+	}
+	edge := p.ByID["c2/edge/api"]
+	if edge == nil || edge.Set["replicas"] != "5" || edge.Set["env"] != "prod" || edge.Chart != filepath.Join(root, "charts", "api") {
+		t.Fatalf("expected c2/api to move to edge, got %+v", edge)
+	}
+
+	merged, err := yaml.Marshal(MergedStack(p))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	for _, want := range []string{"kind: Stack", "chartVersion: 2.1.0", "namespace: edge", "context: prod"} {
+		if !strings.Contains(string(merged), want) {
+			t.Fatalf("expected %q in merged stack:\n%s", want, merged)
+		}
+	}
+	if strings.Contains(string(merged), "name: cache") {
+		t.Fatalf("disabled release in merged stack:\n%s", merged)
+	}
+}
+
+func TestCompile_OverlayErrors(t *testing.T) {
+	root := writeOverlayStack(t)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	for name, tc := range map[string]struct{ overlay, want string }{
+		"unknown release": {overlay: "releases:\n  c1/dbb: { chartVersion: 2.0.0 }\n", want: "releases.c1/dbb does not match any release"},
+		"unknown field":   {overlay: "releases:\n  db: { needs: [cache] }\n", want: "field needs not found"},
+		"wrong kind":      {overlay: "kind: Stack\n", want: "kind must be StackOverlay"},
+	} {
+		path := filepath.Join(root, "envs", strings.ReplaceAll(name, " ", "-")+".yaml")
+		writeFile(t, path, tc.overlay)
+		if _, err := Compile(u, CompileOptions{Overlays: []string{path}}); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected %q, got %v", name, tc.want, err)
+		}
+	}
+}
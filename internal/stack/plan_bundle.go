@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/kube"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/cli"
 	helmkube "helm.sh/helm/v3/pkg/kube"
@@ -75,6 +76,9 @@ func diffSummaryForNode(ctx context.Context, node *ResolvedRelease, defaultKubec
 	if kubeconfigPath == "" {
 		return nil, fmt.Errorf("missing kubeconfig for %s", node.ID)
 	}
+	if node.IsTask() {
+		return &NodeDiffSummary{}, nil
+	}
 
 	settings := cli.New()
 	settings.KubeConfig = kubeconfigPath
@@ -84,6 +88,7 @@ func diffSummaryForNode(ctx context.Context, node *ResolvedRelease, defaultKubec
 	if node.Namespace != "" {
 		settings.SetNamespace(node.Namespace)
 	}
+	withExecCredentialEnv(settings.RESTClientGetter(), node.Cluster.ExecCredentialEnv)
 	actionCfg := new(action.Configuration)
 	if err := actionCfg.Init(settings.RESTClientGetter(), node.Namespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {}); err != nil {
 		return nil, fmt.Errorf("init helm: %w", err)
@@ -94,28 +99,54 @@ func diffSummaryForNode(ctx context.Context, node *ResolvedRelease, defaultKubec
 		helmClient = kc
 	}
 
-	get := action.NewGet(actionCfg)
-	prevManifest := ""
-	if rel, err := get.Run(node.Name); err == nil && rel != nil {
-		prevManifest = rel.Manifest
-	}
+	prevManifest, nextManifest := "", ""
+	if node.IsManifests() {
+		// The inventory Secret keeps the last applied manifest, the equivalent of a release manifest.
+		// Its Secret values are digests, so the fresh render is redacted the same way before diffing.
+		kubeClient, err := kube.NewWithOptions(ctx, kubeconfigPath, kubeCtx, kube.ClientOptions{ExecEnv: node.Cluster.ExecCredentialEnv})
+		if err != nil {
+			return nil, err
+		}
+		inv, err := loadManifestInventory(ctx, kubeClient, node.Namespace, node.Name)
+		if err != nil {
+			return nil, err
+		}
+		prevManifest = inv.Manifest
+		if nextManifest, err = renderManifests(ctx, node, secrets); err != nil {
+			return nil, err
+		}
+		nextManifest = redactManifestSecrets(nextManifest)
+	} else {
+		get := action.NewGet(actionCfg)
+		if rel, err := get.Run(node.Name); err == nil && rel != nil {
+			prevManifest = rel.Manifest
+		}
 
-	rendered, err := deploy.RenderTemplate(ctx, actionCfg, settings, deploy.TemplateOptions{
-		Chart:       node.Chart,
-		Version:     node.ChartVersion,
-		ReleaseName: node.Name,
-		Namespace:   node.Namespace,
-		ValuesFiles: node.Values,
-		SetValues:   flattenSet(node.Set),
-		UseCluster:  true,
-		Secrets:     secrets,
-	})
-	if err != nil {
-		return nil, err
-	}
-	nextManifest := ""
-	if rendered != nil {
-		nextManifest = rendered.Manifest
+		valuesFiles, err := releaseValuesFiles(ctx, node, secrets)
+		if err != nil {
+			return nil, err
+		}
+		postRenderer, err := nodePostRenderer(ctx, node)
+		if err != nil {
+			return nil, err
+		}
+		rendered, err := deploy.RenderTemplate(ctx, actionCfg, settings, deploy.TemplateOptions{
+			Chart:        node.Chart,
+			Version:      node.ChartVersion,
+			ReleaseName:  node.Name,
+			Namespace:    node.Namespace,
+			ValuesFiles:  valuesFiles,
+			SetValues:    flattenSet(node.Set),
+			UseCluster:   true,
+			Secrets:      secrets,
+			PostRenderer: postRenderer,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if rendered != nil {
+			nextManifest = rendered.Manifest
+		}
 	}
 
 	summary, err := deploy.SummarizeManifestPlanWithHelmKube(helmClient, prevManifest, nextManifest)
@@ -58,7 +58,14 @@ func PrintPlanTable(w io.Writer, p *Plan) error {
 			selectedBy = selectedBy[:140] + "…"
 		}
 		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t%s\t%s\t%v\t%v\t%s\n",
-			n.ExecutionGroup, n.Wave, n.InferredRole, releaseReadyKey(n), n.ID, dir, n.Chart, n.Tags, n.Needs, selectedBy)
+			n.ExecutionGroup, n.Wave, n.InferredRole, releaseReadyKey(n), n.ID, dir, nodeSourceLabel(n), n.Tags, n.Needs, selectedBy)
+	}
+	if len(p.Disabled) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "DISABLED\tKIND\tREASON")
+		for _, d := range p.Disabled {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", d.ID, d.Kind, d.Reason)
+		}
 	}
 	return nil
 }
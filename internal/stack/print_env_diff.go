@@ -0,0 +1,150 @@
+// File: internal/stack/print_env_diff.go
+// Brief: Text and HTML rendering of env diffs.
+
+package stack
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+var envValueMarks = map[string]string{
+	EnvValueAdded:   "+",
+	EnvValueRemoved: "-",
+	EnvValueChanged: "~",
+}
+
+// PrintEnvDiff writes a human-readable env diff: one block per differing release, then a
+// summary line.
+func PrintEnvDiff(w io.Writer, d *EnvDiff) error {
+	if d == nil {
+		return fmt.Errorf("env diff is nil")
+	}
+	fmt.Fprintf(w, "Comparing %s → %s\n", d.Left, d.Right)
+	if d.StackName != "" {
+		fmt.Fprintf(w, "Stack %s (%s)\n", d.StackName, d.StackRoot)
+	}
+	fmt.Fprintln(w)
+	for _, r := range d.Releases {
+		switch r.Status {
+		case EnvDiffOnlyLeft:
+			fmt.Fprintf(w, "- %s  only in %s\n", r.Release, d.Left.Label)
+			continue
+		case EnvDiffOnlyRight:
+			fmt.Fprintf(w, "+ %s  only in %s\n", r.Release, d.Right.Label)
+			continue
+		}
+		fmt.Fprintf(w, "~ %s\n", r.Release)
+		for _, f := range r.Fields {
+			fmt.Fprintf(w, "    %s: %s → %s\n", f.Field, orNone(f.Left), orNone(f.Right))
+		}
+		if r.ValuesError != "" {
+			fmt.Fprintf(w, "    values: %s\n", r.ValuesError)
+		}
+		for _, v := range r.Values {
+			switch {
+			case v.Left == "" && v.Right == "":
+				fmt.Fprintf(w, "    %s values.%s\n", envValueMarks[v.Change], v.Key)
+			case v.Change == EnvValueChanged:
+				fmt.Fprintf(w, "    %s values.%s: %s → %s\n", envValueMarks[v.Change], v.Key, v.Left, v.Right)
+			default:
+				fmt.Fprintf(w, "    %s values.%s: %s\n", envValueMarks[v.Change], v.Key, v.Left+v.Right)
+			}
+		}
+	}
+	if len(d.Releases) > 0 {
+		fmt.Fprintln(w)
+	}
+	_, err := fmt.Fprintf(w, "%d release(s) differ, %d unchanged\n", len(d.Releases), d.Unchanged)
+	return err
+}
+
+func orNone(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// PrintEnvDiffHTML writes a self-contained HTML page for an env diff.
+func PrintEnvDiffHTML(w io.Writer, d *EnvDiff) error {
+	if d == nil {
+		return fmt.Errorf("env diff is nil")
+	}
+	data := struct {
+		Title string
+		Diff  *EnvDiff
+		Marks map[string]string
+	}{
+		Title: fmt.Sprintf("ktl env diff %s → %s", d.Left.Label, d.Right.Label),
+		Diff:  d,
+		Marks: envValueMarks,
+	}
+
+	const tpl = `<!doctype html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <meta name="viewport" content="width=device-width, initial-scale=1" />
+  <title>{{ .Title }}</title>
+  <style>
+    :root { color-scheme: light; }
+    body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; margin: 24px; color: #0f172a; background: #f8fafc; }
+    .panel { background: rgba(255,255,255,0.95); border: 1px solid rgba(15,23,42,0.12); border-radius: 16px; padding: 16px; margin-bottom: 16px; box-shadow: 0 18px 40px rgba(15,23,42,0.08); }
+    h1 { font-size: 20px; margin: 0 0 8px; }
+    h2 { font-size: 14px; margin: 0 0 8px; letter-spacing: .04em; }
+    .meta { font-size: 13px; color: rgba(15,23,42,0.65); }
+    table { border-collapse: collapse; width: 100%; font-size: 13px; }
+    th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid rgba(15,23,42,0.08); vertical-align: top; }
+    th { color: rgba(15,23,42,0.65); font-weight: 500; }
+    code { font-family: ui-monospace, SFMono-Regular, Menlo, Monaco, Consolas, "Liberation Mono", monospace; }
+    .added { color: #15803d; }
+    .removed { color: #b91c1c; }
+    .changed { color: #b45309; }
+  </style>
+</head>
+<body>
+  <div class="panel">
+    <h1>{{ .Title }}</h1>
+    <div class="meta">{{ .Diff.Left }} → {{ .Diff.Right }}</div>
+    <div class="meta">{{ len .Diff.Releases }} release(s) differ, {{ .Diff.Unchanged }} unchanged</div>
+  </div>
+  {{ range .Diff.Releases }}
+  <div class="panel">
+    {{ if eq .Status "only-left" }}
+    <h2 class="removed">- {{ .Release }}</h2>
+    <div class="meta">only in {{ $.Diff.Left.Label }}</div>
+    {{ else if eq .Status "only-right" }}
+    <h2 class="added">+ {{ .Release }}</h2>
+    <div class="meta">only in {{ $.Diff.Right.Label }}</div>
+    {{ else }}
+    <h2 class="changed">~ {{ .Release }}</h2>
+    {{ if .Fields }}
+    <table>
+      <tr><th>Setting</th><th>{{ $.Diff.Left.Label }}</th><th>{{ $.Diff.Right.Label }}</th></tr>
+      {{ range .Fields }}<tr><td>{{ .Field }}</td><td><code>{{ .Left }}</code></td><td><code>{{ .Right }}</code></td></tr>
+      {{ end }}
+    </table>
+    {{ end }}
+    {{ if .ValuesError }}<div class="meta">values: {{ .ValuesError }}</div>{{ end }}
+    {{ if .Values }}
+    <table>
+      <tr><th></th><th>Values key</th><th>{{ $.Diff.Left.Label }}</th><th>{{ $.Diff.Right.Label }}</th></tr>
+      {{ range .Values }}<tr class="{{ .Change }}"><td>{{ index $.Marks .Change }}</td><td><code>{{ .Key }}</code></td><td><code>{{ .Left }}</code></td><td><code>{{ .Right }}</code></td></tr>
+      {{ end }}
+    </table>
+    {{ end }}
+    {{ end }}
+  </div>
+  {{ end }}
+</body>
+</html>`
+
+	t, err := template.New("envdiff").Parse(tpl)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, data)
+}
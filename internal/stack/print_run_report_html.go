@@ -0,0 +1,290 @@
+// File: internal/stack/print_run_report_html.go
+// Brief: Self-contained HTML run report (DAG, node results, failures, hooks).
+
+package stack
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// Node box geometry of the report's DAG (SVG user units).
+const (
+	reportNodeWidth  = 200
+	reportNodeHeight = 46
+	reportColumnGap  = 64
+	reportRowGap     = 14
+	reportMargin     = 12
+)
+
+type reportGraphNode struct {
+	RunReportNode
+	X, Y   int
+	Anchor string
+}
+
+type reportGraphEdge struct {
+	Path string
+}
+
+// WriteHTML renders the report as a single HTML page with no external assets, so it can be
+// attached to CI artifacts as is.
+func (r *RunReport) WriteHTML(w io.Writer) error {
+	if r == nil {
+		return fmt.Errorf("run report is nil")
+	}
+	nodes := r.Nodes()
+	r.mu.Lock()
+	runID, status, started, ended := r.runID, r.status, r.startedAt, r.endedAt
+	stackName := ""
+	if r.plan != nil {
+		stackName = r.plan.StackName
+	}
+	r.mu.Unlock()
+
+	graphNodes, edges, width, height := layoutReportGraph(nodes)
+	counts := map[string]int{}
+	for _, n := range nodes {
+		counts[n.Status]++
+	}
+	var failed []reportGraphNode
+	for _, n := range graphNodes {
+		if n.Status == "failed" {
+			failed = append(failed, n)
+		}
+	}
+	duration := time.Duration(0)
+	if !started.IsZero() && ended.After(started) {
+		duration = ended.Sub(started).Round(time.Millisecond)
+	}
+	data := struct {
+		Title      string
+		RunID      string
+		Status     string
+		StartedAt  string
+		Duration   time.Duration
+		Counts     map[string]int
+		Nodes      []reportGraphNode
+		Failed     []reportGraphNode
+		Edges      []reportGraphEdge
+		Width      int
+		Height     int
+		StackHooks []RunReportHook
+		NodeWidth  int
+		NodeHeight int
+	}{
+		Title:      fmt.Sprintf("ktl stack %s %s", r.command, stackName),
+		RunID:      runID,
+		Status:     status,
+		Duration:   duration,
+		Counts:     counts,
+		Nodes:      graphNodes,
+		Failed:     failed,
+		Edges:      edges,
+		Width:      width,
+		Height:     height,
+		StackHooks: r.StackHooks(),
+		NodeWidth:  reportNodeWidth,
+		NodeHeight: reportNodeHeight,
+	}
+	if !started.IsZero() {
+		data.StartedAt = started.UTC().Format(time.RFC3339)
+	}
+	t, err := template.New("runReport").Funcs(template.FuncMap{
+		"dur": func(n RunReportNode) string {
+			if d := n.Duration(); d > 0 {
+				return d.Round(time.Millisecond).String()
+			}
+			return "-"
+		},
+		"phaseDur": func(p RunReportPhase) string {
+			if p.StartedAt.IsZero() || p.EndedAt.Before(p.StartedAt) {
+				return "-"
+			}
+			return p.EndedAt.Sub(p.StartedAt).Round(time.Millisecond).String()
+		},
+		"add": func(a, b int) int { return a + b },
+	}).Parse(runReportHTMLTemplate)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, data)
+}
+
+// layoutReportGraph places nodes in columns by dependency depth (a node sits one column right
+// of its deepest dependency) and routes each edge from a dependency to its dependent.
+func layoutReportGraph(nodes []RunReportNode) ([]reportGraphNode, []reportGraphEdge, int, int) {
+	byID := map[string]int{}
+	for i, n := range nodes {
+		byID[n.ID] = i
+	}
+	depth := make([]int, len(nodes))
+	state := make([]int, len(nodes)) // 0 unvisited, 1 visiting, 2 done
+	var visit func(i int) int
+	visit = func(i int) int {
+		if state[i] == 2 || state[i] == 1 {
+			return depth[i]
+		}
+		state[i] = 1
+		d := 0
+		for _, dep := range nodes[i].Needs {
+			if j, ok := byID[dep]; ok {
+				d = max(d, visit(j)+1)
+			}
+		}
+		depth[i], state[i] = d, 2
+		return d
+	}
+	rows := map[int]int{}
+	out := make([]reportGraphNode, len(nodes))
+	width, height := 0, 0
+	for i := range nodes {
+		col := visit(i)
+		row := rows[col]
+		rows[col]++
+		x := reportMargin + col*(reportNodeWidth+reportColumnGap)
+		y := reportMargin + row*(reportNodeHeight+reportRowGap)
+		out[i] = reportGraphNode{RunReportNode: nodes[i], X: x, Y: y, Anchor: "node-" + safeID(nodes[i].ID)}
+		width = max(width, x+reportNodeWidth+reportMargin)
+		height = max(height, y+reportNodeHeight+reportMargin)
+	}
+	var edges []reportGraphEdge
+	for _, n := range out {
+		for _, dep := range n.Needs {
+			j, ok := byID[dep]
+			if !ok {
+				continue
+			}
+			from := out[j]
+			x1, y1 := from.X+reportNodeWidth, from.Y+reportNodeHeight/2
+			x2, y2 := n.X, n.Y+reportNodeHeight/2
+			mid := (x1 + x2) / 2
+			edges = append(edges, reportGraphEdge{Path: fmt.Sprintf("M%d %d C%d %d, %d %d, %d %d", x1, y1, mid, y1, mid, y2, x2, y2)})
+		}
+	}
+	return out, edges, width, height
+}
+
+const runReportHTMLTemplate = `<!doctype html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <meta name="viewport" content="width=device-width, initial-scale=1" />
+  <title>{{ .Title }}</title>
+  <style>
+    :root { color-scheme: light; }
+    body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; margin: 24px; color: #0f172a; background: #f8fafc; }
+    .panel { background: rgba(255,255,255,0.95); border: 1px solid rgba(15,23,42,0.12); border-radius: 16px; padding: 16px; margin-bottom: 16px; box-shadow: 0 18px 40px rgba(15,23,42,0.08); }
+    h1 { font-size: 20px; margin: 0 0 8px; }
+    h2 { font-size: 14px; margin: 0 0 8px; text-transform: uppercase; letter-spacing: .14em; color: rgba(15,23,42,0.65); }
+    h3 { font-size: 15px; margin: 16px 0 6px; }
+    .kv { display: grid; grid-template-columns: 160px 1fr; gap: 6px 12px; font-size: 13px; }
+    .k { color: rgba(15,23,42,0.65); }
+    table { border-collapse: collapse; width: 100%; font-size: 13px; }
+    th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid rgba(15,23,42,0.08); vertical-align: top; }
+    th { color: rgba(15,23,42,0.65); font-weight: 600; }
+    code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Monaco, Consolas, "Liberation Mono", monospace; }
+    pre { white-space: pre-wrap; word-break: break-word; background: rgba(15,23,42,0.04); border: 1px solid rgba(15,23,42,0.08); border-radius: 12px; padding: 12px; font-size: 12px; margin: 0 0 8px; }
+    .graph { overflow-x: auto; }
+    .graph text { font-size: 12px; fill: #0f172a; }
+    .graph .sub { font-size: 11px; fill: rgba(15,23,42,0.6); }
+    .graph path { fill: none; stroke: rgba(15,23,42,0.35); stroke-width: 1.5; }
+    .status { display: inline-block; padding: 1px 8px; border-radius: 999px; font-size: 12px; font-weight: 600; }
+    .succeeded { background: #dcfce7; color: #166534; } rect.succeeded { fill: #dcfce7; stroke: #16a34a; }
+    .failed { background: #fee2e2; color: #991b1b; } rect.failed { fill: #fee2e2; stroke: #dc2626; }
+    .blocked { background: #fef3c7; color: #92400e; } rect.blocked { fill: #fef3c7; stroke: #d97706; }
+    .running, .planned, .skipped { background: #e2e8f0; color: #334155; } rect.running, rect.planned { fill: #f1f5f9; stroke: #94a3b8; }
+  </style>
+</head>
+<body>
+  <div class="panel">
+    <h1>{{ .Title }}</h1>
+    <div class="kv">
+      <div class="k">Run</div><div><code>{{ .RunID }}</code></div>
+      <div class="k">Status</div><div>{{ .Status }}</div>
+      {{ if .StartedAt }}<div class="k">Started</div><div>{{ .StartedAt }}</div>{{ end }}
+      <div class="k">Duration</div><div>{{ .Duration }}</div>
+      <div class="k">Releases</div><div>{{ range $status, $n := .Counts }}<span class="status {{ $status }}">{{ $status }} {{ $n }}</span> {{ end }}</div>
+    </div>
+  </div>
+
+  <div class="panel graph">
+    <h2>Graph</h2>
+    <svg width="{{ .Width }}" height="{{ .Height }}" viewBox="0 0 {{ .Width }} {{ .Height }}" xmlns="http://www.w3.org/2000/svg">
+      {{ range .Edges }}<path d="{{ .Path }}" />{{ end }}
+      {{ $w := .NodeWidth }}{{ $h := .NodeHeight }}
+      {{ range .Nodes }}
+      <a href="#{{ .Anchor }}">
+        <rect class="{{ .Status }}" x="{{ .X }}" y="{{ .Y }}" width="{{ $w }}" height="{{ $h }}" rx="8" />
+        <text x="{{ add .X 10 }}" y="{{ add .Y 19 }}">{{ .Name }}</text>
+        <text class="sub" x="{{ add .X 10 }}" y="{{ add .Y 36 }}">{{ .Cluster }}/{{ .Namespace }} · {{ dur .RunReportNode }}</text>
+      </a>
+      {{ end }}
+    </svg>
+  </div>
+
+  {{ if .Failed }}
+  <div class="panel">
+    <h2>Failures</h2>
+    {{ range .Failed }}
+    <h3><a href="#{{ .Anchor }}">{{ .ID }}</a> <span class="status failed">{{ .ErrorClass }}</span></h3>
+    <pre>{{ .Error }}</pre>
+    {{ if .HelmLogs }}<pre>{{ range .HelmLogs }}{{ . }}
+{{ end }}</pre>{{ end }}
+    {{ end }}
+  </div>
+  {{ end }}
+
+  {{ if .StackHooks }}
+  <div class="panel">
+    <h2>Stack hooks</h2>
+    {{ range .StackHooks }}
+    <h3>{{ .Phase }} {{ .Name }} <span class="status {{ .Status }}">{{ .Status }}</span></h3>
+    {{ if .Output }}<pre>{{ range .Output }}{{ . }}
+{{ end }}</pre>{{ else if .Message }}<pre>{{ .Message }}</pre>{{ end }}
+    {{ end }}
+  </div>
+  {{ end }}
+
+  <div class="panel">
+    <h2>Releases</h2>
+    <table>
+      <tr><th>Release</th><th>Status</th><th>Attempts</th><th>Duration</th></tr>
+      {{ range .Nodes }}
+      <tr><td><a href="#{{ .Anchor }}">{{ .ID }}</a></td><td><span class="status {{ .Status }}">{{ .Status }}</span></td><td>{{ .Attempts }}</td><td>{{ dur .RunReportNode }}</td></tr>
+      {{ end }}
+    </table>
+  </div>
+
+  {{ range .Nodes }}
+  <div class="panel" id="{{ .Anchor }}">
+    <h2>{{ .ID }}</h2>
+    <div class="kv">
+      <div class="k">Status</div><div><span class="status {{ .Status }}">{{ .Status }}</span></div>
+      {{ if .Needs }}<div class="k">Needs</div><div>{{ range .Needs }}<code>{{ . }}</code> {{ end }}</div>{{ end }}
+      {{ if .Error }}<div class="k">Error</div><div><code>{{ .Error }}</code></div>{{ end }}
+    </div>
+    {{ if .Phases }}
+    <h3>Phases</h3>
+    <table>
+      <tr><th>Phase</th><th>Status</th><th>Duration</th><th>Message</th></tr>
+      {{ range .Phases }}<tr><td>{{ .Name }}</td><td>{{ .Status }}</td><td>{{ phaseDur . }}</td><td>{{ .Message }}</td></tr>{{ end }}
+    </table>
+    {{ end }}
+    {{ range .Hooks }}
+    <h3>Hook {{ .Phase }} {{ .Name }} <span class="status {{ .Status }}">{{ .Status }}</span></h3>
+    {{ if .Output }}<pre>{{ range .Output }}{{ . }}
+{{ end }}</pre>{{ else if .Message }}<pre>{{ .Message }}</pre>{{ end }}
+    {{ end }}
+    {{ if .HelmLogs }}
+    <h3>Helm log (last lines)</h3>
+    <pre>{{ range .HelmLogs }}{{ . }}
+{{ end }}</pre>
+    {{ end }}
+  </div>
+  {{ end }}
+</body>
+</html>
+`
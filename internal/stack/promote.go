@@ -0,0 +1,177 @@
+// File: internal/stack/promote.go
+// Brief: Planning a release promotion between two environments of a stack.
+
+package stack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Promotion is one release resolved in the base stack and both environments, plus the values
+// keys that belong to an environment and must not be carried across.
+type Promotion struct {
+	// Release is the key the release was selected by: its name, or name@cluster.
+	Release string
+	Source  *ResolvedRelease
+	Target  *ResolvedRelease
+	// EnvKeys are the values keys (dotted, lists as a whole) that the source or the target
+	// environment sets differently from the base stack.
+	EnvKeys []string
+}
+
+// PlanPromotion compiles u without environment and for both sides, finds release in each, and
+// works out which values keys are environment-specific. release is a release name, or
+// name@cluster when the name is deployed to several clusters.
+func PlanPromotion(u *Universe, release string, from, to EnvSide) (*Promotion, error) {
+	release = strings.TrimSpace(release)
+	if release == "" {
+		return nil, fmt.Errorf("release is required")
+	}
+	base, err := Compile(u, CompileOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("compile stack: %w", err)
+	}
+	fp, err := Compile(u, CompileOptions{Profile: from.Profile, Overlays: from.Overlays})
+	if err != nil {
+		return nil, fmt.Errorf("compile %s: %w", from.Label, err)
+	}
+	tp, err := Compile(u, CompileOptions{Profile: to.Profile, Overlays: to.Overlays})
+	if err != nil {
+		return nil, fmt.Errorf("compile %s: %w", to.Label, err)
+	}
+	source, err := findPromotionNode(fp.Nodes, release, from.Label)
+	if err != nil {
+		return nil, err
+	}
+	target, err := findPromotionNode(tp.Nodes, release, to.Label)
+	if err != nil {
+		return nil, err
+	}
+	if source.IsManifests() || source.IsTask() || target.IsManifests() || target.IsTask() {
+		return nil, fmt.Errorf("release %s is not a helm release; only helm releases can be promoted", release)
+	}
+
+	sv, err := mergedReleaseValues(source)
+	if err != nil {
+		return nil, err
+	}
+	tv, err := mergedReleaseValues(target)
+	if err != nil {
+		return nil, err
+	}
+	bv := map[string]string{}
+	// A release that only exists in some environments has no base; all of its values are
+	// environment-specific then.
+	if n, err := findPromotionNode(base.Nodes, release, "base"); err == nil {
+		if bv, err = mergedReleaseValues(n); err != nil {
+			return nil, err
+		}
+	}
+	keys := map[string]struct{}{}
+	for _, side := range []map[string]string{sv, tv} {
+		for _, d := range diffFlatValues(bv, side, false) {
+			keys[promotionKey(d.Key)] = struct{}{}
+		}
+	}
+	p := &Promotion{Release: release, Source: source, Target: target}
+	for k := range keys {
+		p.EnvKeys = append(p.EnvKeys, k)
+	}
+	sort.Strings(p.EnvKeys)
+	return p, nil
+}
+
+func findPromotionNode(nodes []*ResolvedRelease, release, env string) (*ResolvedRelease, error) {
+	byKey := envDiffKeys(nodes)
+	if n := byKey[release]; n != nil {
+		return n, nil
+	}
+	var candidates []string
+	for key, n := range byKey {
+		if n.Name == release {
+			candidates = append(candidates, key)
+		}
+	}
+	if len(candidates) > 0 {
+		sort.Strings(candidates)
+		return nil, fmt.Errorf("release %s is deployed to several clusters in %s; pick one of %s", release, env, strings.Join(candidates, ", "))
+	}
+	return nil, fmt.Errorf("release %s not found in %s", release, env)
+}
+
+// promotionKey trims a flattened values key at its first list index: Helm replaces lists
+// wholesale, so a list is promoted or kept as a unit.
+func promotionKey(key string) string {
+	if i := strings.Index(key, "["); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// PromotedValues returns a copy of the source's deployed user values without the
+// environment-specific keys.
+func (p *Promotion) PromotedValues(deployed map[string]any) map[string]any {
+	out := copyValues(deployed)
+	for _, key := range p.EnvKeys {
+		deleteValuesPath(out, strings.Split(key, "."))
+	}
+	return out
+}
+
+// TargetSet returns the target's set entries that are environment-specific. The others would
+// override the promoted values, since set wins over values files.
+func (p *Promotion) TargetSet() map[string]string {
+	out := map[string]string{}
+	for k, v := range p.Target.Set {
+		if p.isEnvKey(promotionKey(k)) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func (p *Promotion) isEnvKey(key string) bool {
+	for _, k := range p.EnvKeys {
+		if key == k || strings.HasPrefix(key, k+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func copyValues(v map[string]any) map[string]any {
+	out := make(map[string]any, len(v))
+	for k, val := range v {
+		if m, ok := val.(map[string]any); ok {
+			out[k] = copyValues(m)
+			continue
+		}
+		out[k] = val
+	}
+	return out
+}
+
+// deleteValuesPath removes path from v and prunes maps it leaves empty.
+func deleteValuesPath(v map[string]any, path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	child, ok := v[path[0]]
+	if !ok {
+		return false
+	}
+	if len(path) == 1 {
+		delete(v, path[0])
+		return true
+	}
+	m, ok := child.(map[string]any)
+	if !ok || !deleteValuesPath(m, path[1:]) {
+		return false
+	}
+	if len(m) == 0 {
+		delete(v, path[0])
+	}
+	return true
+}
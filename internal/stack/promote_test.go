@@ -0,0 +1,65 @@
+package stack
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlanPromotion(t *testing.T) {
+	root := writeOverlayStack(t)
+	writeFile(t, filepath.Join(root, "values", "common.yaml"), "image:\n  tag: \"1.0\"\nreplicas: 1\n")
+	writeFile(t, filepath.Join(root, "values", "prod-db.yaml"), "replicas: 3\ningress:\n  hosts: [db.example.com]\n")
+	writeFile(t, filepath.Join(root, "envs", "staging.yaml"), `
+releases:
+  db:
+    set: { debug: "true" }
+`)
+	writeFile(t, filepath.Join(root, "envs", "prod.yaml"), `
+releases:
+  db:
+    values: [../values/prod-db.yaml]
+    set: { image.tag: "1.0", region: eu }
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	staging := EnvSide{Label: "staging", Overlays: []string{filepath.Join(root, "envs", "staging.yaml")}}
+	prod := EnvSide{Label: "prod", Overlays: []string{filepath.Join(root, "envs", "prod.yaml")}}
+
+	p, err := PlanPromotion(u, "db", staging, prod)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if want := []string{"debug", "ingress.hosts", "region", "replicas"}; !reflect.DeepEqual(p.EnvKeys, want) {
+		t.Fatalf("env keys = %v, want %v", p.EnvKeys, want)
+	}
+
+	deployed := map[string]any{
+		"image":    map[string]any{"tag": "1.2"},
+		"replicas": 1,
+		"debug":    "true",
+		"ingress":  map[string]any{"hosts": []any{"db.staging.example.com"}},
+	}
+	promoted := p.PromotedValues(deployed)
+	if want := map[string]any{"image": map[string]any{"tag": "1.2"}}; !reflect.DeepEqual(promoted, want) {
+		t.Fatalf("promoted = %v, want %v", promoted, want)
+	}
+	if _, ok := deployed["debug"]; !ok {
+		t.Fatalf("expected deployed values to be left untouched")
+	}
+	// image.tag is not environment-specific, so the target's set must not override the
+	// promoted tag.
+	if set := p.TargetSet(); !reflect.DeepEqual(set, map[string]string{"region": "eu"}) {
+		t.Fatalf("target set = %v", set)
+	}
+
+	if _, err := PlanPromotion(u, "api", staging, prod); err == nil || !strings.Contains(err.Error(), "api@c1, api@c2") {
+		t.Fatalf("expected ambiguous release error, got %v", err)
+	}
+	if _, err := PlanPromotion(u, "api@c2", staging, prod); err != nil {
+		t.Fatalf("plan api@c2: %v", err)
+	}
+}
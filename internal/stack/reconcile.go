@@ -0,0 +1,147 @@
+// File: internal/stack/reconcile.go
+// Brief: Git checkout sync and the periodic reconcile loop behind `ktl stack reconcile`.
+
+package stack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/netconfig"
+)
+
+// GitSource is a remote repository tracked by `ktl stack reconcile`.
+type GitSource struct {
+	URL string
+	// Ref is a branch or tag; empty tracks the remote's default branch.
+	Ref string
+	// Dir is the local checkout directory (created on first sync).
+	Dir string
+}
+
+// SyncGitCheckout clones src.URL into src.Dir (or fetches and hard-resets an existing checkout)
+// and returns the checked-out commit.
+func SyncGitCheckout(ctx context.Context, src GitSource) (string, error) {
+	url := strings.TrimSpace(src.URL)
+	dir := strings.TrimSpace(src.Dir)
+	if url == "" || dir == "" {
+		return "", fmt.Errorf("git source requires a repository URL and checkout dir")
+	}
+	ref := strings.TrimSpace(src.Ref)
+	if err := netconfig.CheckGitRemote(url); err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+			return "", fmt.Errorf("create checkout parent: %w", err)
+		}
+		args := []string{"clone", "--depth", "1"}
+		if ref != "" {
+			args = append(args, "--branch", ref)
+		}
+		args = append(args, url, dir)
+		if _, err := runGit(ctx, "", args...); err != nil {
+			return "", err
+		}
+	} else {
+		fetchRef := ref
+		if fetchRef == "" {
+			fetchRef = "HEAD"
+		}
+		if _, err := runGit(ctx, dir, "remote", "set-url", "origin", url); err != nil {
+			return "", err
+		}
+		if _, err := runGit(ctx, dir, "fetch", "--depth", "1", "origin", fetchRef); err != nil {
+			return "", err
+		}
+		if _, err := runGit(ctx, dir, "reset", "--hard", "FETCH_HEAD"); err != nil {
+			return "", err
+		}
+	}
+	commit, err := runGit(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(commit), nil
+}
+
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	sub := args[0]
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	args = append(netconfig.GitConfigArgs(), args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg != "" {
+			return "", fmt.Errorf("git %s: %s", sub, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", sub, err)
+	}
+	return stdout.String(), nil
+}
+
+// ReconcileResult describes one reconcile iteration.
+type ReconcileResult struct {
+	Iteration int
+	Started   time.Time
+	Duration  time.Duration
+	Revision  string
+	Err       error
+}
+
+// ReconcileLoopOptions configures RunReconcileLoop.
+type ReconcileLoopOptions struct {
+	Interval time.Duration
+	// Once runs a single iteration and returns its error.
+	Once bool
+	// Reconcile performs one sync+apply pass and returns the revision it applied.
+	Reconcile func(ctx context.Context) (string, error)
+	// OnResult observes each iteration (optional).
+	OnResult func(ReconcileResult)
+}
+
+// RunReconcileLoop runs Reconcile immediately and then every Interval until ctx is done.
+// Iteration errors are reported through OnResult and do not stop the loop.
+func RunReconcileLoop(ctx context.Context, opts ReconcileLoopOptions) error {
+	if opts.Reconcile == nil {
+		return fmt.Errorf("reconcile func is required")
+	}
+	if !opts.Once && opts.Interval <= 0 {
+		return fmt.Errorf("reconcile interval must be positive")
+	}
+	for iteration := 1; ; iteration++ {
+		started := time.Now()
+		revision, err := opts.Reconcile(ctx)
+		if opts.OnResult != nil {
+			opts.OnResult(ReconcileResult{
+				Iteration: iteration,
+				Started:   started,
+				Duration:  time.Since(started),
+				Revision:  revision,
+				Err:       err,
+			})
+		}
+		if opts.Once {
+			return err
+		}
+		timer := time.NewTimer(opts.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunReconcileLoopContinuesAfterErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var results []ReconcileResult
+	calls := 0
+	err := RunReconcileLoop(ctx, ReconcileLoopOptions{
+		Interval: time.Millisecond,
+		Reconcile: func(context.Context) (string, error) {
+			calls++
+			if calls == 1 {
+				return "", errors.New("transient")
+			}
+			return "abc123", nil
+		},
+		OnResult: func(res ReconcileResult) {
+			results = append(results, res)
+			if len(results) == 3 {
+				cancel()
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("loop: %v", err)
+	}
+	if len(results) != 3 || results[0].Err == nil || results[1].Revision != "abc123" || results[2].Iteration != 3 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
+
+func TestRunReconcileLoopOnceReturnsError(t *testing.T) {
+	err := RunReconcileLoop(context.Background(), ReconcileLoopOptions{
+		Once:      true,
+		Reconcile: func(context.Context) (string, error) { return "", errors.New("boom") },
+	})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected boom, got %v", err)
+	}
+}
+
+func TestSyncGitCheckoutClonesAndPulls(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	remote := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", remote}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(remote, "stack.yaml"), []byte("name: a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "one")
+
+	dir := filepath.Join(t.TempDir(), "checkout")
+	src := GitSource{URL: "file://" + remote, Ref: "main", Dir: dir}
+	first, err := SyncGitCheckout(context.Background(), src)
+	if err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(remote, "stack.yaml"), []byte("name: b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("commit", "-q", "-am", "two")
+	second, err := SyncGitCheckout(context.Background(), src)
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	if first == "" || first == second {
+		t.Fatalf("expected a new revision after pull, got %q then %q", first, second)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "stack.yaml"))
+	if err != nil || strings.TrimSpace(string(data)) != "name: b" {
+		t.Fatalf("expected updated checkout, got %q (%v)", data, err)
+	}
+}
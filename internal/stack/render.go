@@ -0,0 +1,49 @@
+// File: internal/stack/render.go
+// Brief: Effective-stack and per-node manifest rendering for `ktl stack render`.
+
+package stack
+
+import "context"
+
+// RenderNode renders a node client-side, the same way dependency inference does.
+func RenderNode(ctx context.Context, node *ResolvedRelease, defaultKubeconfig string, defaultKubeContext string, opts InferDepsOptions) (string, error) {
+	return renderNodeManifest(ctx, node, defaultKubeconfig, defaultKubeContext, opts)
+}
+
+// MergedStack flattens a compiled plan back into a single stack file: every release carries its
+// fully merged settings (defaults, profile, overlays, clusters section) with absolute paths, so
+// the output can be diffed between environments or compiled on its own.
+func MergedStack(p *Plan) *StackFile {
+	sf := &StackFile{
+		APIVersionKind: APIVersionKind{APIVersion: "ktl.dev/v1", Kind: "Stack"},
+		Name:           p.StackName,
+		Hooks:          p.Hooks,
+	}
+	for _, n := range p.Nodes {
+		sf.Releases = append(sf.Releases, ReleaseSpec{
+			Name:           n.Name,
+			Type:           n.Type,
+			Chart:          n.Chart,
+			ChartVersion:   n.ChartVersion,
+			Manifests:      n.Manifests,
+			Task:           n.Task,
+			Wave:           n.Wave,
+			Critical:       n.Critical,
+			Parallelism:    n.Parallelism,
+			Cluster:        n.Cluster,
+			Namespace:      n.Namespace,
+			Values:         n.Values,
+			Set:            n.Set,
+			Tags:           n.Tags,
+			Needs:          n.Needs,
+			WaitFor:        n.WaitFor,
+			WaitForTimeout: n.WaitForTimeout,
+			Apply:          n.Apply,
+			Delete:         n.Delete,
+			Verify:         n.Verify,
+			Owner:          n.Owner,
+			Hooks:          n.Hooks,
+		})
+	}
+	return sf
+}
@@ -4,6 +4,7 @@
 package stack
 
 import (
+	"errors"
 	"math/rand"
 	"strings"
 	"time"
@@ -13,6 +14,14 @@ func classifyError(err error) string {
 	if err == nil {
 		return ""
 	}
+	var rulesErr *verifyRulesFailure
+	if errors.As(err, &rulesErr) {
+		return "VERIFY_RULES"
+	}
+	var gateErr *riskGateFailure
+	if errors.As(err, &gateErr) {
+		return "RISK_GATE"
+	}
 	msg := strings.ToLower(err.Error())
 	switch {
 	case strings.Contains(msg, "429") || strings.Contains(msg, "too many requests"):
@@ -0,0 +1,81 @@
+// File: internal/stack/risk_gate.go
+// Brief: apply.requireApproval gates that stop a release whose plan carries unapproved risk tags.
+
+package stack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+)
+
+// riskTagAll matches every risk tag in apply.requireApproval and --approve-risk.
+const riskTagAll = "all"
+
+// parseRiskTags validates tags; "all" expands to every known tag.
+func parseRiskTags(raw []string) (map[deploy.RiskTag]bool, error) {
+	out := map[deploy.RiskTag]bool{}
+	for _, v := range raw {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if strings.EqualFold(v, riskTagAll) {
+			for _, tag := range deploy.RiskTags {
+				out[tag] = true
+			}
+			continue
+		}
+		tag, err := deploy.ParseRiskTag(v)
+		if err != nil {
+			return nil, err
+		}
+		out[tag] = true
+	}
+	return out, nil
+}
+
+// riskGateFailure is a release whose plan carries risk tags that require approval. It is not
+// retried: the plan does not change between attempts.
+type riskGateFailure struct {
+	tags     []string
+	blocking []string
+}
+
+func (e *riskGateFailure) Error() string {
+	return fmt.Sprintf("requires extra approval (re-run with --approve-risk %s):\n  %s", strings.Join(e.tags, ","), strings.Join(e.blocking, "\n  "))
+}
+
+// checkRiskGate fails when summary carries a tag listed in required that approved does not
+// cover. The error lists every blocking change so the approver sees what they sign off on.
+func checkRiskGate(required, approved []string, summary *deploy.PlanSummary) error {
+	need, err := parseRiskTags(required)
+	if err != nil || len(need) == 0 {
+		return err
+	}
+	ok, err := parseRiskTags(approved)
+	if err != nil {
+		return err
+	}
+	failure := &riskGateFailure{}
+	tags := map[string]bool{}
+	for _, ch := range summary.RiskyChanges() {
+		for _, r := range ch.Risks {
+			if !need[r.Tag] || ok[r.Tag] {
+				continue
+			}
+			tags[string(r.Tag)] = true
+			failure.blocking = append(failure.blocking, fmt.Sprintf("%s/%s [%s] %s", ch.Kind, ch.Name, r.Tag, r.Detail))
+		}
+	}
+	if len(failure.blocking) == 0 {
+		return nil
+	}
+	for tag := range tags {
+		failure.tags = append(failure.tags, tag)
+	}
+	sort.Strings(failure.tags)
+	return failure
+}
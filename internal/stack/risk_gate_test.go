@@ -0,0 +1,38 @@
+package stack
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+)
+
+func TestCheckRiskGate(t *testing.T) {
+	summary := &deploy.PlanSummary{Changes: []deploy.PlanChange{
+		{Action: deploy.PlanUpdate, Kind: "Deployment", Name: "api", Risks: []deploy.PlanRisk{
+			{Tag: deploy.RiskImageChange, Detail: "container api: api:v1 -> api:v2"},
+			{Tag: deploy.RiskReplicaDecrease, Detail: "replicas 5 -> 2"},
+		}},
+	}}
+	if err := checkRiskGate(nil, nil, summary); err != nil {
+		t.Fatalf("no gate configured: %v", err)
+	}
+	err := checkRiskGate([]string{"replica-decrease", "pdb-deletion"}, nil, summary)
+	var gateErr *riskGateFailure
+	if !errors.As(err, &gateErr) || !strings.Contains(err.Error(), "--approve-risk replica-decrease") || strings.Contains(err.Error(), "image-change") {
+		t.Fatalf("err = %v", err)
+	}
+	if class := classifyError(err); isRetryableClass(class) {
+		t.Fatalf("risk gate failures must not be retried (class %s)", class)
+	}
+	if err := checkRiskGate([]string{"all"}, []string{"replica-decrease", "image-change"}, summary); err != nil {
+		t.Fatalf("approved: %v", err)
+	}
+	if err := checkRiskGate([]string{"all"}, []string{"all"}, summary); err != nil {
+		t.Fatalf("approved all: %v", err)
+	}
+	if err := checkRiskGate([]string{"replica-drop"}, nil, summary); err == nil {
+		t.Fatalf("expected an error for an unknown tag")
+	}
+}
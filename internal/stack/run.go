@@ -16,6 +16,7 @@ import (
 
 	"github.com/kubekattle/ktl/internal/deploy"
 	"golang.org/x/sync/semaphore"
+	"k8s.io/client-go/kubernetes"
 )
 
 type RunOptions struct {
@@ -27,8 +28,16 @@ type RunOptions struct {
 	DryRun      bool
 	Diff        bool
 	CacheApply  bool
-	Executor    NodeExecutor
-	Secrets     *deploy.SecretOptions
+	// SkipUnchanged skips releases already deployed from the same inputs (see NodeInputDigest).
+	SkipUnchanged bool
+	// ChartCacheDir keeps downloaded chart archives across runs (empty caches for this run only).
+	ChartCacheDir string
+	Executor      NodeExecutor
+	Secrets       *deploy.SecretOptions
+	// GitMetadata is recorded on every applied release (nil disables).
+	GitMetadata *deploy.GitMetadata
+	// ApprovedRisks approves plan risk tags (or "all") listed in a release's apply.requireApproval.
+	ApprovedRisks []string
 
 	HelmLogs bool
 
@@ -58,11 +67,23 @@ type RunOptions struct {
 
 	RunID string
 
-	Selector        RunSelector
-	FailMode        string
-	MaxAttempts     int
+	Selector    RunSelector
+	FailMode    string
+	MaxAttempts int
+	// MaxFailures stops scheduling after this many node failures (0 = no limit beyond FailFast).
+	MaxFailures int
+	// FailureDomain is where FailFast and MaxFailures apply: the run (default) or each cluster.
+	FailureDomain   string
 	InitialAttempts map[string]int
 
+	// ClusterPreflight gates the run on cluster health before anything is scheduled.
+	ClusterPreflight ClusterHealthOptions
+	// clusterHealthClient overrides how preflight reaches a cluster (tests).
+	clusterHealthClient func(context.Context, *ResolvedRelease) (kubernetes.Interface, error)
+
+	// Flaky lists known flaky failures to annotate and retry (runner.flaky).
+	Flaky []FlakyRule
+
 	EventObservers []RunEventObserver
 }
 
@@ -81,6 +102,17 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 	if concurrency <= 0 {
 		concurrency = 1
 	}
+	if opts.ClusterPreflight.Enabled {
+		if err := opts.ClusterPreflight.Validate(); err != nil {
+			return fmt.Errorf("cluster preflight: %w", err)
+		}
+	}
+	if err := ValidateFailureDomain(opts.FailureDomain); err != nil {
+		return err
+	}
+	if opts.MaxFailures < 0 {
+		return fmt.Errorf("max failures must be >= 0 (got %d)", opts.MaxFailures)
+	}
 
 	run := newRunState(opts.Plan, cmd)
 	if opts.RunID != "" {
@@ -128,21 +160,26 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 		return err
 	}
 
+	charts := deploy.NewChartCache(opts.ChartCacheDir)
 	exec := opts.Executor
 	if exec == nil {
 		exec = &helmExecutor{
-			kubeconfig:  opts.Kubeconfig,
-			kubeContext: opts.KubeContext,
-			run:         run,
-			out:         out,
-			errOut:      errOut,
-			dryRun:      opts.DryRun,
-			diff:        opts.Diff,
-			cacheApply:  opts.CacheApply,
-			helmLogs:    opts.HelmLogs,
-			kubeQPS:     opts.KubeQPS,
-			kubeBurst:   opts.KubeBurst,
-			secrets:     opts.Secrets,
+			kubeconfig:    opts.Kubeconfig,
+			kubeContext:   opts.KubeContext,
+			run:           run,
+			out:           out,
+			errOut:        errOut,
+			dryRun:        opts.DryRun,
+			diff:          opts.Diff,
+			cacheApply:    opts.CacheApply,
+			skipUnchanged: opts.SkipUnchanged,
+			helmLogs:      opts.HelmLogs,
+			kubeQPS:       opts.KubeQPS,
+			kubeBurst:     opts.KubeBurst,
+			secrets:       opts.Secrets,
+			gitMetadata:   opts.GitMetadata,
+			approvedRisks: opts.ApprovedRisks,
+			charts:        charts,
 		}
 	}
 	exec = &hookedExecutor{base: exec, run: run, opts: opts, out: out, errOut: errOut}
@@ -266,6 +303,8 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 		b.sem.Release(1)
 	}
 
+	failures := newFailureBudget(opts.FailureDomain, opts.MaxFailures, opts.FailFast)
+	inFlight := newInFlightNodes()
 	var worker func()
 	var wg sync.WaitGroup
 	spawnWorker := func() {
@@ -397,7 +436,34 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 						return
 					}
 				}
-				err := exec.RunNode(ctx, node, cmd)
+				nodeCtx := withBudgetYield(ctx, func(wait func() error) error {
+					held := [3]*budgetSem{semGroup, semKind, semNS}
+					releaseBudget(semNS)
+					releaseBudget(semKind)
+					releaseBudget(semGroup)
+					semGroup, semKind, semNS = nil, nil, nil
+					if err := wait(); err != nil {
+						return err
+					}
+					// Take the slots back in the order above so yielding nodes cannot deadlock.
+					waited := false
+					if err := acquireBudget(ctx, node, held[0], "group", node.Parallelism, &waited); err != nil {
+						return err
+					}
+					semGroup = held[0]
+					if err := acquireBudget(ctx, node, held[1], "kind", node.InferredPrimaryKind, &waited); err != nil {
+						return err
+					}
+					semKind = held[1]
+					if err := acquireBudget(ctx, node, held[2], "namespace", releaseNS, &waited); err != nil {
+						return err
+					}
+					semNS = held[2]
+					return nil
+				})
+				inFlight.start(node.ID)
+				err := exec.RunNode(nodeCtx, node, cmd)
+				inFlight.finish(node.ID, err)
 				if semNS != nil {
 					releaseBudget(semNS)
 				}
@@ -443,7 +509,20 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 				}
 				class := classifyError(err)
 				retryable := isRetryableClass(class)
-				run.AppendEvent(node.ID, NodeFailed, node.Attempt, err.Error(), nil, &RunError{Class: class, Message: err.Error(), Digest: computeRunErrorDigest(class, err.Error())})
+				attempts := maxAttempts
+				runErr := &RunError{Class: class, Message: err.Error(), Digest: computeRunErrorDigest(class, err.Error())}
+				failedMsg := err.Error()
+				var failedFields map[string]any
+				if rule, ok := matchFlakyRule(opts.Flaky, runErr); ok {
+					failedMsg = fmt.Sprintf("%s (%s)", failedMsg, rule.annotation())
+					failedFields = map[string]any{"flaky": true, "flakyLink": rule.Link}
+					if rule.Retry {
+						// Known flakes get one retry even when --retry is left at 1.
+						retryable = true
+						attempts = max(attempts, 2)
+					}
+				}
+				run.AppendEvent(node.ID, NodeFailed, node.Attempt, failedMsg, failedFields, runErr)
 				if adaptive != nil {
 					poolMu.Lock()
 					before := adaptive.Target
@@ -464,7 +543,7 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 					poolMu.Unlock()
 					maybeSpawn()
 				}
-				if retryable && node.Attempt < maxAttempts {
+				if retryable && node.Attempt < attempts {
 					backoff := retryBackoff(node.Attempt)
 					run.AppendEvent(node.ID, RetryScheduled, node.Attempt+1, fmt.Sprintf("backoff=%s", backoff), map[string]any{
 						"backoff": backoff.String(),
@@ -491,9 +570,23 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 				mu.Unlock()
 
 				s.MarkFailed(node.ID, err)
-				if opts.FailFast {
-					s.Stop()
-					return
+				if key, count, tripped := failures.record(node); tripped {
+					reason := failures.reason(key, count)
+					run.AppendEvent("", FailureLimitReached, 0, reason, map[string]any{
+						"domain":   failures.domain,
+						"cluster":  key,
+						"failures": count,
+						"limit":    failures.limit,
+					}, nil)
+					if failures.domain != FailureDomainCluster {
+						s.Stop()
+						return
+					}
+					for _, n := range run.Nodes {
+						if strings.TrimSpace(n.Cluster.Name) == key {
+							s.Block(n.ID, reason)
+						}
+					}
 				}
 				break
 			}
@@ -512,15 +605,61 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 		}, nil)
 	}
 	run.AppendEvent("", RunStarted, 0, fmt.Sprintf("command=%s planned=%d", cmd, len(run.Nodes)), map[string]any{
-		"command":     cmd,
-		"planned":     len(run.Nodes),
-		"stackName":   strings.TrimSpace(run.Plan.StackName),
-		"stackRoot":   strings.TrimSpace(run.Plan.StackRoot),
-		"profile":     strings.TrimSpace(run.Plan.Profile),
-		"concurrency": run.Concurrency,
-		"failMode":    strings.TrimSpace(run.FailMode),
+		"command":       cmd,
+		"planned":       len(run.Nodes),
+		"stackName":     strings.TrimSpace(run.Plan.StackName),
+		"stackRoot":     strings.TrimSpace(run.Plan.StackRoot),
+		"profile":       strings.TrimSpace(run.Plan.Profile),
+		"concurrency":   run.Concurrency,
+		"failMode":      strings.TrimSpace(run.FailMode),
+		"maxFailures":   opts.MaxFailures,
+		"failureDomain": failures.domain,
 	}, nil)
 
+	abortRun := func(err error) error {
+		run.AppendEvent("", RunCompleted, 0, "failed", map[string]any{"status": "failed"}, nil)
+		run.WriteSummarySnapshot(run.BuildSummary("failed", start, s.Snapshot()))
+		if run.store != nil {
+			_, _ = run.store.FinalizeRun(context.Background(), run.RunID, time.Now().UTC().UnixNano(), run.eventPrevHash)
+			_ = run.store.CheckpointPortable(context.Background())
+		}
+		return err
+	}
+
+	if opts.ClusterPreflight.Enabled {
+		newClient := opts.clusterHealthClient
+		if newClient == nil {
+			newClient = preflightClient(opts.Kubeconfig, opts.KubeContext)
+		}
+		failed := runClusterPreflight(ctx, run, opts.ClusterPreflight, newClient, func(line string) { fmt.Fprintln(errOut, line) })
+		if len(failed) > 0 {
+			names := make([]string, 0, len(failed))
+			for name := range failed {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if opts.ClusterPreflight.OnFailure != "skip" {
+				return abortRun(fmt.Errorf("cluster preflight denied the run: %s", formatPreflightFailures(names, failed)))
+			}
+			for _, n := range run.Nodes {
+				if reason, ok := failed[n.Cluster.Name]; ok {
+					s.Block(n.ID, "cluster preflight: "+reason)
+				}
+			}
+			if blocked := s.TakeNewlyBlocked(); len(blocked) > 0 {
+				ids := make([]string, 0, len(blocked))
+				for id := range blocked {
+					ids = append(ids, id)
+				}
+				sort.Strings(ids)
+				for _, id := range ids {
+					run.AppendEvent(id, NodeBlocked, 0, blocked[id], nil, nil)
+				}
+			}
+			firstErr = fmt.Errorf("cluster preflight skipped %s", formatPreflightFailures(names, failed))
+		}
+	}
+
 	// Stack-level runOnce hooks (pre).
 	run.AppendEvent("", StackHooksStarted, 0, "stack hooks: pre-"+cmd, map[string]any{"stage": "pre-" + cmd}, nil)
 	if err := runHookList(ctx, hookRunContext{
@@ -532,14 +671,7 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 		baseDir: run.Plan.StackRoot,
 	}, hooksForRunOnce(run.Plan, cmd, true)); err != nil {
 		run.AppendEvent("", StackHooksCompleted, 0, "stack hooks: pre-"+cmd+" failed", map[string]any{"stage": "pre-" + cmd, "status": "failed"}, nil)
-		firstErr = err
-		run.AppendEvent("", RunCompleted, 0, "failed", map[string]any{"status": "failed"}, nil)
-		run.WriteSummarySnapshot(run.BuildSummary("failed", start, s.Snapshot()))
-		if run.store != nil {
-			_, _ = run.store.FinalizeRun(context.Background(), run.RunID, time.Now().UTC().UnixNano(), run.eventPrevHash)
-			_ = run.store.CheckpointPortable(context.Background())
-		}
-		return firstErr
+		return abortRun(err)
 	}
 	run.AppendEvent("", StackHooksCompleted, 0, "stack hooks: pre-"+cmd+" completed", map[string]any{"stage": "pre-" + cmd, "status": "succeeded"}, nil)
 
@@ -662,7 +794,7 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 	for i := 0; i < targetWorkers; i++ {
 		spawnWorker()
 	}
-	wg.Wait()
+	workersDone := waitWorkers(ctx, &wg, interruptGracePeriod)
 
 	s.FinalizeBlocked()
 	if blocked := s.TakeNewlyBlocked(); len(blocked) > 0 {
@@ -679,6 +811,26 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 			run.AppendEvent(id, NodeBlocked, attempt, blocked[id], nil, nil)
 		}
 	}
+	if ctx.Err() != nil {
+		// Interrupted: skip post hooks (they would run against a cancelled context) and record
+		// what is safe to resume from.
+		summary := buildInterruptSummary(run, s.Snapshot(), inFlight)
+		status := "interrupted"
+		if !workersDone {
+			// Workers that outlived the grace period may still write node events, so the run is
+			// left unsealed and marked abandoned rather than finalized under them.
+			status = "abandoned"
+			summary.Hint = strings.TrimSpace(summary.Hint + fmt.Sprintf(" Some nodes did not stop within %s; the run was abandoned without being finalized.", interruptGracePeriod))
+		}
+		run.AppendEvent("", RunInterrupted, 0, fmt.Sprintf("interrupted: %d completed, %d failed, %d blocked, %d unknown, %d not started", len(summary.Completed), len(summary.Failed), len(summary.Blocked), len(summary.Unknown), len(summary.NotStarted)), interruptFields(summary), nil)
+		run.AppendEvent("", RunCompleted, 0, status, map[string]any{"status": status}, nil)
+		run.WriteSummarySnapshot(run.BuildSummary(status, start, s.Snapshot()))
+		if run.store != nil && workersDone {
+			_, _ = run.store.FinalizeRun(context.Background(), run.RunID, time.Now().UTC().UnixNano(), run.eventPrevHash)
+			_ = run.store.CheckpointPortable(context.Background())
+		}
+		return &deploy.InterruptedError{Summary: summary}
+	}
 	status := "succeeded"
 	if firstErr != nil {
 		status = "failed"
@@ -708,7 +860,11 @@ func Run(ctx context.Context, opts RunOptions, out io.Writer, errOut io.Writer)
 		run.AppendEvent("", StackHooksCompleted, 0, "stack hooks: post-"+cmd+" completed", map[string]any{"stage": "post-" + cmd, "status": "succeeded"}, nil)
 	}
 	run.AppendEvent("", RunFinalized, 0, "finalized", map[string]any{"stage": "finalized"}, nil)
-	run.AppendEvent("", RunCompleted, 0, status, map[string]any{"status": status}, nil)
+	completed := map[string]any{"status": status}
+	if st := charts.Stats(); st != (deploy.ChartCacheStats{}) {
+		completed["chartCache"] = st
+	}
+	run.AppendEvent("", RunCompleted, 0, status, completed, nil)
 	run.WriteSummarySnapshot(run.BuildSummary(status, start, s.Snapshot()))
 	if run.store != nil {
 		_, _ = run.store.FinalizeRun(context.Background(), run.RunID, time.Now().UTC().UnixNano(), run.eventPrevHash)
@@ -924,6 +1080,7 @@ type scheduler struct {
 
 	newlyBlocked []string
 	blockedBy    map[string]string
+	blockedOn    map[string]string
 
 	stopped bool
 }
@@ -931,6 +1088,8 @@ type scheduler struct {
 type schedulerSnapshot struct {
 	Status map[string]string
 	Errors map[string]error
+	// BlockedOn maps nodes blocked by a failed or blocked dependency to that dependency.
+	BlockedOn map[string]string
 }
 
 func newScheduler(nodes []*runNode, command string) *scheduler {
@@ -942,6 +1101,7 @@ func newScheduler(nodes []*runNode, command string) *scheduler {
 		status:     map[string]string{},
 		errs:       map[string]error{},
 		blockedBy:  map[string]string{},
+		blockedOn:  map[string]string{},
 	}
 
 	byKey := map[string]*runNode{}
@@ -1024,17 +1184,16 @@ func (s *scheduler) NextReady() *runNode {
 			continue
 		}
 		// Ensure all deps succeeded.
-		ok := true
-		blockedReason := ""
+		blockedOn := ""
 		for _, depID := range s.deps[id] {
 			if s.status[depID] != "succeeded" {
-				ok = false
-				blockedReason = fmt.Sprintf("blocked by %s (%s)", depID, s.status[depID])
+				blockedOn = depID
 				break
 			}
 		}
-		if !ok {
-			s.setBlocked(id, blockedReason)
+		if blockedOn != "" {
+			s.setBlocked(id, fmt.Sprintf("blocked by %s (%s)", blockedOn, s.status[blockedOn]))
+			s.blockedOn[id] = blockedOn
 			continue
 		}
 		s.status[id] = "running"
@@ -1127,12 +1286,20 @@ func (s *scheduler) FinalizeBlocked() {
 		for _, depID := range s.deps[id] {
 			if s.status[depID] == "failed" || s.status[depID] == "blocked" {
 				s.setBlocked(id, fmt.Sprintf("blocked by %s (%s)", depID, s.status[depID]))
+				s.blockedOn[id] = depID
 				break
 			}
 		}
 	}
 }
 
+// Block marks a planned node as blocked without running it.
+func (s *scheduler) Block(id string, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setBlocked(id, reason)
+}
+
 func (s *scheduler) TakeNewlyBlocked() map[string]string {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -1171,7 +1338,11 @@ func (s *scheduler) Snapshot() schedulerSnapshot {
 	for k, v := range s.errs {
 		errs[k] = v
 	}
-	return schedulerSnapshot{Status: status, Errors: errs}
+	blockedOn := map[string]string{}
+	for k, v := range s.blockedOn {
+		blockedOn[k] = v
+	}
+	return schedulerSnapshot{Status: status, Errors: errs, BlockedOn: blockedOn}
 }
 
 // run errors should stay actionable; prefer returning the first error but attach
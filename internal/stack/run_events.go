@@ -12,6 +12,13 @@ const (
 	RunConcurrency RunEventType = "RUN_CONCURRENCY"
 	RunFinalizing  RunEventType = "RUN_FINALIZING"
 	RunFinalized   RunEventType = "RUN_FINALIZED"
+	// RunInterrupted carries the partial-state summary of a run stopped by Ctrl+C.
+	RunInterrupted RunEventType = "RUN_INTERRUPTED"
+	// FailureLimitReached records that a failure domain (the run, or one cluster) used up its
+	// failure budget and stopped scheduling.
+	FailureLimitReached RunEventType = "FAILURE_LIMIT_REACHED"
+	// ClusterPreflight carries one cluster's health preflight report (see ClusterHealthReport).
+	ClusterPreflight RunEventType = "CLUSTER_PREFLIGHT"
 
 	NodeMeta RunEventType = "NODE_META"
 
@@ -42,6 +49,10 @@ const (
 	// HelmLog is an optional, durable log stream captured from Helm operations.
 	// It is intended to be stored in sqlite when enabled by the caller.
 	HelmLog RunEventType = "HELM_LOG"
+
+	// NodeDiff records the diff computed before a release was applied. Like HelmLog it is only
+	// stored when Helm log capture is enabled; `ktl stack debug` shows it for failed nodes.
+	NodeDiff RunEventType = "NODE_DIFF"
 )
 
 type RunEventObserver interface {
@@ -0,0 +1,149 @@
+// File: internal/stack/run_interrupt.go
+// Brief: Partial-state summary for interrupted stack runs.
+
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+)
+
+// interruptGracePeriod bounds how long Run waits for in-flight nodes to unwind after Ctrl+C.
+// Helm sees the cancellation immediately; the grace only covers it recording the release state.
+var interruptGracePeriod = 10 * time.Second
+
+// inFlightNodes tracks nodes whose Helm operation has started but not returned.
+type inFlightNodes struct {
+	mu          sync.Mutex
+	running     map[string]struct{}
+	interrupted map[string]struct{}
+}
+
+func newInFlightNodes() *inFlightNodes {
+	return &inFlightNodes{running: map[string]struct{}{}, interrupted: map[string]struct{}{}}
+}
+
+func (f *inFlightNodes) start(id string) {
+	f.mu.Lock()
+	f.running[id] = struct{}{}
+	f.mu.Unlock()
+}
+
+func (f *inFlightNodes) finish(id string, err error) {
+	f.mu.Lock()
+	delete(f.running, id)
+	if errors.Is(err, context.Canceled) {
+		f.interrupted[id] = struct{}{}
+	}
+	f.mu.Unlock()
+}
+
+// unknown returns nodes that were cut off mid-operation or are still running.
+func (f *inFlightNodes) unknown() map[string]struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]struct{}, len(f.running)+len(f.interrupted))
+	for id := range f.running {
+		out[id] = struct{}{}
+	}
+	for id := range f.interrupted {
+		out[id] = struct{}{}
+	}
+	return out
+}
+
+// waitWorkers waits for wg. Once ctx is cancelled it waits at most grace longer and reports
+// whether every worker returned.
+func waitWorkers(ctx context.Context, wg *sync.WaitGroup, grace time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+	}
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func buildInterruptSummary(run *runState, snap schedulerSnapshot, inFlight *inFlightNodes) *deploy.InterruptSummary {
+	unknown := inFlight.unknown()
+	summary := &deploy.InterruptSummary{}
+	for _, n := range run.Nodes {
+		if _, ok := unknown[n.ID]; ok {
+			summary.Unknown = append(summary.Unknown, n.ID)
+			continue
+		}
+		switch snap.Status[n.ID] {
+		case "succeeded":
+			summary.Completed = append(summary.Completed, n.ID)
+		case "failed":
+			summary.Failed = append(summary.Failed, n.ID)
+		case "blocked":
+			// Nodes blocked only because an interrupted dependency stopped never had a real failure
+			// upstream; they are simply not started.
+			if blockedByInterrupt(n.ID, snap, unknown) {
+				summary.NotStarted = append(summary.NotStarted, n.ID)
+			} else {
+				summary.Blocked = append(summary.Blocked, n.ID)
+			}
+		case "running":
+			// Dispatched but not yet reported to inFlight; the operation may have started.
+			summary.Unknown = append(summary.Unknown, n.ID)
+		default:
+			summary.NotStarted = append(summary.NotStarted, n.ID)
+		}
+	}
+	for _, list := range [][]string{summary.Completed, summary.Failed, summary.Blocked, summary.Unknown, summary.NotStarted} {
+		sort.Strings(list)
+	}
+	if len(summary.Unknown) > 0 {
+		summary.Hint = "Helm operations for unknown nodes were cancelled; their releases may be pending or failed (check with helm status)."
+	}
+	summary.Resume = fmt.Sprintf("ktl stack %s --resume --run-id %s", run.Command, run.RunID)
+	return summary
+}
+
+// blockedByInterrupt follows id's chain of blocking dependencies and reports whether it ends at a
+// node whose operation was cut off by the interrupt.
+func blockedByInterrupt(id string, snap schedulerSnapshot, unknown map[string]struct{}) bool {
+	seen := map[string]bool{}
+	for !seen[id] {
+		seen[id] = true
+		dep, ok := snap.BlockedOn[id]
+		if !ok {
+			return false
+		}
+		if _, cut := unknown[dep]; cut {
+			return true
+		}
+		id = dep
+	}
+	return false
+}
+
+func interruptFields(s *deploy.InterruptSummary) map[string]any {
+	return map[string]any{
+		"completed":  append([]string(nil), s.Completed...),
+		"failed":     append([]string(nil), s.Failed...),
+		"blocked":    append([]string(nil), s.Blocked...),
+		"unknown":    append([]string(nil), s.Unknown...),
+		"notStarted": append([]string(nil), s.NotStarted...),
+		"resume":     s.Resume,
+	}
+}
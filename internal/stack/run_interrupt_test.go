@@ -0,0 +1,158 @@
+package stack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+)
+
+func TestRun_InterruptedReportsPartialState(t *testing.T) {
+	root := t.TempDir()
+	writeMinimalStackFixture(t, root, "interrupted")
+
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Compile(u, CompileOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var events []RunEvent
+	observer := RunEventObserverFunc(func(ev RunEvent) {
+		if ev.Type == string(NodeRunning) && strings.HasSuffix(ev.NodeID, "/app2") {
+			cancel()
+		}
+		events = append(events, ev)
+	})
+
+	var out, errOut bytes.Buffer
+	runErr := Run(ctx, RunOptions{
+		Command:        "apply",
+		Plan:           p,
+		Concurrency:    1,
+		Executor:       &fakeExecutor{sleepOn: "app2", sleep: 30 * time.Second},
+		EventObservers: []RunEventObserver{observer},
+	}, &out, &errOut)
+
+	var interrupted *deploy.InterruptedError
+	if !errors.As(runErr, &interrupted) {
+		t.Fatalf("expected InterruptedError, got %v", runErr)
+	}
+	if !errors.Is(runErr, context.Canceled) {
+		t.Fatalf("expected error to unwrap to context.Canceled")
+	}
+	s := interrupted.Summary
+	if len(s.Completed) != 1 || !strings.HasSuffix(s.Completed[0], "/app1") {
+		t.Fatalf("unexpected completed nodes: %v", s.Completed)
+	}
+	if len(s.Unknown) != 1 || !strings.HasSuffix(s.Unknown[0], "/app2") {
+		t.Fatalf("unexpected unknown nodes: %v", s.Unknown)
+	}
+	if len(s.NotStarted) != 1 || !strings.HasSuffix(s.NotStarted[0], "/app3") {
+		t.Fatalf("unexpected not-started nodes: %v", s.NotStarted)
+	}
+	if !strings.Contains(s.Resume, "ktl stack apply --resume --run-id ") {
+		t.Fatalf("unexpected resume hint %q", s.Resume)
+	}
+
+	sawInterrupted := false
+	for _, ev := range events {
+		if ev.Type == string(RunInterrupted) {
+			sawInterrupted = true
+		}
+		if ev.Type == string(StackHooksStarted) && strings.Contains(ev.Message, "post-") {
+			t.Fatalf("post hooks must not run after an interrupt")
+		}
+	}
+	if !sawInterrupted {
+		t.Fatalf("expected a %s event", RunInterrupted)
+	}
+}
+
+func TestBuildInterruptSummaryClassifiesByStatus(t *testing.T) {
+	node := func(id string) *runNode { return &runNode{ResolvedRelease: &ResolvedRelease{ID: id}} }
+	run := &runState{RunID: "r1", Command: "apply", Nodes: []*runNode{node("a"), node("b"), node("c"), node("d"), node("e"), node("f"), node("g")}}
+	inFlight := newInFlightNodes()
+	inFlight.start("e")
+	snap := schedulerSnapshot{
+		Status: map[string]string{
+			"a": "succeeded", "b": "failed", "c": "blocked", "d": "planned", "e": "running", "f": "running", "g": "blocked",
+		},
+		// c is blocked by b's real failure; g only by the interrupted e.
+		BlockedOn: map[string]string{"c": "b", "g": "e"},
+	}
+
+	s := buildInterruptSummary(run, snap, inFlight)
+	got := strings.Join([]string{
+		strings.Join(s.Completed, ","), strings.Join(s.Failed, ","), strings.Join(s.Blocked, ","),
+		strings.Join(s.Unknown, ","), strings.Join(s.NotStarted, ","),
+	}, "|")
+	if got != "a|b|c|e,f|d,g" {
+		t.Fatalf("unexpected classification %q", got)
+	}
+	var buf bytes.Buffer
+	s.Write(&buf)
+	if !strings.Contains(buf.String(), "Interrupted: 1 completed, 1 failed, 1 blocked, 2 in unknown state, 2 not started") {
+		t.Fatalf("unexpected summary:\n%s", buf.String())
+	}
+}
+
+type stuckExecutor struct{ release chan struct{} }
+
+func (e *stuckExecutor) RunNode(ctx context.Context, node *runNode, command string) error {
+	if node.Name == "app2" {
+		<-e.release
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestRun_InterruptAbandonsRunWhenWorkersOutliveGrace(t *testing.T) {
+	saved := interruptGracePeriod
+	interruptGracePeriod = 50 * time.Millisecond
+	t.Cleanup(func() { interruptGracePeriod = saved })
+
+	root := t.TempDir()
+	writeMinimalStackFixture(t, root, "abandoned")
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Compile(u, CompileOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	exec := &stuckExecutor{release: make(chan struct{})}
+	defer close(exec.release)
+	var completed string
+	observer := RunEventObserverFunc(func(ev RunEvent) {
+		if ev.Type == string(NodeRunning) && strings.HasSuffix(ev.NodeID, "/app2") {
+			cancel()
+		}
+		if ev.Type == string(RunCompleted) {
+			completed = ev.Message
+		}
+	})
+
+	var out, errOut bytes.Buffer
+	runErr := Run(ctx, RunOptions{Command: "apply", Plan: p, Concurrency: 1, Executor: exec, EventObservers: []RunEventObserver{observer}}, &out, &errOut)
+	var interrupted *deploy.InterruptedError
+	if !errors.As(runErr, &interrupted) {
+		t.Fatalf("expected InterruptedError, got %v", runErr)
+	}
+	if completed != "abandoned" || !strings.Contains(interrupted.Summary.Hint, "abandoned without being finalized") {
+		t.Fatalf("expected the run to be abandoned, got %q / %q", completed, interrupted.Summary.Hint)
+	}
+}
@@ -0,0 +1,228 @@
+// File: internal/stack/run_report.go
+// Brief: Per-node run results collected from run events for the HTML run report.
+
+package stack
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// runReportLogTail bounds the Helm log and hook output lines kept per node.
+const runReportLogTail = 40
+
+// RunReport collects run events into per-node results; WriteHTML renders them with the plan's
+// DAG as a self-contained HTML page (--html-report).
+type RunReport struct {
+	plan    *Plan
+	command string
+
+	mu        sync.Mutex
+	runID     string
+	status    string
+	startedAt time.Time
+	endedAt   time.Time
+	nodes     map[string]*RunReportNode
+	// stackHooks holds the stack-level hooks (events without a node).
+	stackHooks RunReportNode
+}
+
+// RunReportNode is one node's outcome in a RunReport.
+type RunReportNode struct {
+	ID        string
+	Name      string
+	Namespace string
+	Cluster   string
+	Needs     []string
+	// Status is succeeded, failed, blocked, running, or planned (never started).
+	Status     string
+	Attempts   int
+	StartedAt  time.Time
+	EndedAt    time.Time
+	ErrorClass string
+	Error      string
+	Phases     []RunReportPhase
+	Hooks      []RunReportHook
+	HelmLogs   []string
+}
+
+// RunReportPhase is the last recorded state of a node phase.
+type RunReportPhase struct {
+	Name      string
+	Status    string
+	Message   string
+	StartedAt time.Time
+	EndedAt   time.Time
+}
+
+// RunReportHook is one hook run with the tail of its output.
+type RunReportHook struct {
+	Name    string
+	Phase   string
+	Status  string
+	Message string
+	Output  []string
+}
+
+// NewRunReport returns a report to register as a run event observer.
+func NewRunReport(p *Plan, command string) *RunReport {
+	r := &RunReport{plan: p, command: command, nodes: map[string]*RunReportNode{}}
+	if p == nil {
+		return r
+	}
+	g, _ := BuildGraph(p)
+	for _, n := range p.Nodes {
+		node := &RunReportNode{
+			ID:        n.ID,
+			Name:      n.Name,
+			Namespace: n.Namespace,
+			Cluster:   n.Cluster.Name,
+			Status:    "planned",
+		}
+		if g != nil {
+			node.Needs = append([]string(nil), g.deps[n.ID]...)
+			sort.Strings(node.Needs)
+		}
+		r.nodes[n.ID] = node
+	}
+	return r
+}
+
+// ObserveRunEvent implements RunEventObserver.
+func (r *RunReport) ObserveRunEvent(ev RunEvent) {
+	if r == nil {
+		return
+	}
+	ts, _ := time.Parse(time.RFC3339Nano, ev.TS)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ev.RunID != "" {
+		r.runID = ev.RunID
+	}
+	switch RunEventType(ev.Type) {
+	case RunStarted:
+		r.startedAt = ts
+		return
+	case RunCompleted:
+		r.endedAt = ts
+		r.status = strings.TrimSpace(ev.Message)
+		return
+	}
+	n := r.nodes[ev.NodeID]
+	if ev.NodeID == "" {
+		n = &r.stackHooks
+	}
+	if n == nil {
+		return
+	}
+	switch RunEventType(ev.Type) {
+	case NodeRunning:
+		if n.StartedAt.IsZero() {
+			n.StartedAt = ts
+		}
+		n.Status, n.Attempts = "running", max(n.Attempts, ev.Attempt)
+		n.Error, n.ErrorClass = "", ""
+	case NodeSucceeded:
+		n.Status, n.EndedAt = "succeeded", ts
+	case NodeFailed:
+		n.Status, n.EndedAt, n.Error = "failed", ts, strings.TrimSpace(ev.Message)
+		if ev.Error != nil {
+			n.ErrorClass = ev.Error.Class
+			if msg := strings.TrimSpace(ev.Error.Message); msg != "" {
+				n.Error = msg
+			}
+		}
+	case NodeBlocked:
+		n.Status, n.Error = "blocked", strings.TrimSpace(ev.Message)
+	case PhaseStarted:
+		name, _ := ev.Fields["phase"].(string)
+		p := n.phase(name)
+		p.StartedAt, p.Status = ts, "running"
+	case PhaseCompleted:
+		name, _ := ev.Fields["phase"].(string)
+		p := n.phase(name)
+		p.EndedAt = ts
+		p.Status, _ = ev.Fields["status"].(string)
+		p.Message, _ = ev.Fields["message"].(string)
+	case HookStarted, HookSucceeded, HookFailed, HookSkipped:
+		name, _ := ev.Fields["hook"].(string)
+		phase, _ := ev.Fields["phase"].(string)
+		h := n.hook(name, phase)
+		h.Status = strings.ToLower(strings.TrimPrefix(ev.Type, "HOOK_"))
+		if RunEventType(ev.Type) != HookStarted {
+			h.Message = strings.TrimSpace(ev.Message)
+		}
+	case NodeLog:
+		if kind, _ := ev.Fields["kind"].(string); kind == "hook-output" && len(n.Hooks) > 0 {
+			h := &n.Hooks[len(n.Hooks)-1]
+			h.Output = appendTail(h.Output, strings.TrimPrefix(ev.Message, "hook-output "))
+		}
+	case HelmLog:
+		n.HelmLogs = appendTail(n.HelmLogs, ev.Message)
+	}
+}
+
+func (n *RunReportNode) phase(name string) *RunReportPhase {
+	for i := range n.Phases {
+		if n.Phases[i].Name == name {
+			return &n.Phases[i]
+		}
+	}
+	n.Phases = append(n.Phases, RunReportPhase{Name: name})
+	return &n.Phases[len(n.Phases)-1]
+}
+
+func (n *RunReportNode) hook(name, phase string) *RunReportHook {
+	for i := range n.Hooks {
+		if n.Hooks[i].Name == name && n.Hooks[i].Phase == phase {
+			return &n.Hooks[i]
+		}
+	}
+	n.Hooks = append(n.Hooks, RunReportHook{Name: name, Phase: phase})
+	return &n.Hooks[len(n.Hooks)-1]
+}
+
+// Duration is how long the node ran, across retries.
+func (n RunReportNode) Duration() time.Duration {
+	if n.StartedAt.IsZero() || n.EndedAt.IsZero() || n.EndedAt.Before(n.StartedAt) {
+		return 0
+	}
+	return n.EndedAt.Sub(n.StartedAt)
+}
+
+// Nodes returns a snapshot of the node results ordered by ID.
+func (r *RunReport) Nodes() []RunReportNode {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]RunReportNode, 0, len(r.nodes))
+	for _, n := range r.nodes {
+		cp := *n
+		cp.Phases = append([]RunReportPhase(nil), n.Phases...)
+		cp.Hooks = append([]RunReportHook(nil), n.Hooks...)
+		cp.HelmLogs = append([]string(nil), n.HelmLogs...)
+		out = append(out, cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// StackHooks returns the stack-level hook runs.
+func (r *RunReport) StackHooks() []RunReportHook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RunReportHook(nil), r.stackHooks.Hooks...)
+}
+
+func appendTail(lines []string, line string) []string {
+	line = strings.TrimRight(line, "\n")
+	if line == "" {
+		return lines
+	}
+	lines = append(lines, line)
+	if len(lines) > runReportLogTail {
+		lines = lines[len(lines)-runReportLogTail:]
+	}
+	return lines
+}
@@ -0,0 +1,69 @@
+package stack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunReport_WriteHTML(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "charts", "cm", "Chart.yaml"), "apiVersion: v2\nname: cm\nversion: 0.1.0\n")
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: shop
+defaults:
+  namespace: ns
+  cluster: { name: east }
+releases:
+  - { name: db, chart: ./charts/cm }
+  - { name: api, chart: ./charts/cm, needs: [db] }
+  - { name: web, chart: ./charts/cm, needs: [api] }
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	p, err := Compile(u, CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	report := NewRunReport(p, "apply")
+	exec := &recordingExecutor{failOn: map[string]error{"api": errors.New("helm upgrade: <boom>")}}
+	err = Run(context.Background(), RunOptions{
+		Command:        "apply",
+		Plan:           p,
+		Concurrency:    1,
+		Executor:       exec,
+		EventObservers: []RunEventObserver{report},
+	}, ioDiscard{}, ioDiscard{})
+	if err == nil {
+		t.Fatalf("expected the run to fail")
+	}
+
+	status := map[string]string{}
+	for _, n := range report.Nodes() {
+		status[n.Name] = n.Status
+	}
+	if status["db"] != "succeeded" || status["api"] != "failed" || status["web"] != "blocked" {
+		t.Fatalf("node statuses = %v", status)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteHTML(&buf); err != nil {
+		t.Fatalf("write html: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{"<svg", `href="#node-east_ns_api"`, "helm upgrade: &lt;boom&gt;", `class="status blocked"`} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("report is missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<script") || strings.Contains(html, "<link") {
+		t.Fatalf("report must not load external assets")
+	}
+}
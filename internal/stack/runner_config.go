@@ -65,6 +65,13 @@ func ResolveRunnerConfig(u *Universe, profile string) (RunnerResolved, error) {
 	}
 
 	applyRunnerResolved(&base, merged)
+	flaky, err := resolveFlakyRules(merged.Flaky)
+	if err != nil {
+		return RunnerResolved{}, err
+	}
+	if len(flaky) > 0 {
+		base.Flaky = flaky
+	}
 	if err := ValidateRunnerResolved(base); err != nil {
 		return RunnerResolved{}, err
 	}
@@ -117,6 +124,32 @@ func mergeRunner(dst *RunnerConfig, src RunnerConfig) {
 	if src.Adaptive.CooldownSevere != nil {
 		dst.Adaptive.CooldownSevere = src.Adaptive.CooldownSevere
 	}
+	if src.Preflight.Enabled != nil {
+		dst.Preflight.Enabled = src.Preflight.Enabled
+	}
+	if src.Preflight.MinReadyNodesPercent != nil {
+		dst.Preflight.MinReadyNodesPercent = src.Preflight.MinReadyNodesPercent
+	}
+	if src.Preflight.MaxPendingCSRs != nil {
+		dst.Preflight.MaxPendingCSRs = src.Preflight.MaxPendingCSRs
+	}
+	if src.Preflight.MaxEvictedPods != nil {
+		dst.Preflight.MaxEvictedPods = src.Preflight.MaxEvictedPods
+	}
+	if strings.TrimSpace(src.Preflight.OnFailure) != "" {
+		dst.Preflight.OnFailure = src.Preflight.OnFailure
+	}
+	if src.Preflight.Timeout != nil {
+		dst.Preflight.Timeout = src.Preflight.Timeout
+	}
+	if src.MaxFailures != nil {
+		dst.MaxFailures = src.MaxFailures
+	}
+	if strings.TrimSpace(src.FailureDomain) != "" {
+		dst.FailureDomain = src.FailureDomain
+	}
+	// Profiles add flaky rules on top of the stack's.
+	dst.Flaky = append(dst.Flaky, src.Flaky...)
 }
 
 func applyRunnerResolved(dst *RunnerResolved, cfg RunnerConfig) {
@@ -162,6 +195,35 @@ func applyRunnerResolved(dst *RunnerResolved, cfg RunnerConfig) {
 	if strings.TrimSpace(cfg.Adaptive.Mode) != "" {
 		dst.Adaptive.Mode = strings.ToLower(strings.TrimSpace(cfg.Adaptive.Mode))
 	}
+	if cfg.MaxFailures != nil {
+		dst.MaxFailures = *cfg.MaxFailures
+	}
+	if strings.TrimSpace(cfg.FailureDomain) != "" {
+		dst.FailureDomain = strings.ToLower(strings.TrimSpace(cfg.FailureDomain))
+	}
+	if cfg.Preflight != (RunnerPreflight{}) {
+		pf := DefaultClusterHealthOptions()
+		pf.Enabled = true
+		if cfg.Preflight.Enabled != nil {
+			pf.Enabled = *cfg.Preflight.Enabled
+		}
+		if cfg.Preflight.MinReadyNodesPercent != nil {
+			pf.MinReadyNodesPercent = *cfg.Preflight.MinReadyNodesPercent
+		}
+		if cfg.Preflight.MaxPendingCSRs != nil {
+			pf.MaxPendingCSRs = *cfg.Preflight.MaxPendingCSRs
+		}
+		if cfg.Preflight.MaxEvictedPods != nil {
+			pf.MaxEvictedPods = *cfg.Preflight.MaxEvictedPods
+		}
+		if strings.TrimSpace(cfg.Preflight.OnFailure) != "" {
+			pf.OnFailure = strings.ToLower(strings.TrimSpace(cfg.Preflight.OnFailure))
+		}
+		if cfg.Preflight.Timeout != nil {
+			pf.Timeout = *cfg.Preflight.Timeout
+		}
+		dst.Preflight = &pf
+	}
 }
 
 func ValidateRunnerResolved(r RunnerResolved) error {
@@ -192,6 +254,17 @@ func ValidateRunnerResolved(r RunnerResolved) error {
 	if r.Adaptive.CooldownSevere < 0 {
 		return fmt.Errorf("runner.adaptive.cooldownSevere must be >= 0 (got %d)", r.Adaptive.CooldownSevere)
 	}
+	if r.MaxFailures < 0 {
+		return fmt.Errorf("runner.maxFailures must be >= 0 (got %d)", r.MaxFailures)
+	}
+	if err := ValidateFailureDomain(r.FailureDomain); err != nil {
+		return fmt.Errorf("runner.failureDomain: %w", err)
+	}
+	if r.Preflight != nil {
+		if err := r.Preflight.Validate(); err != nil {
+			return fmt.Errorf("runner.preflight: %w", err)
+		}
+	}
 	for kind, v := range r.Limits.MaxParallelKind {
 		if strings.TrimSpace(kind) == "" {
 			return fmt.Errorf("runner.limits.maxParallelKind has empty kind")
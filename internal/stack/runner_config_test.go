@@ -1,6 +1,10 @@
 package stack
 
-import "testing"
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
 
 func pint(v int) *int         { return &v }
 func pbool(v bool) *bool      { return &v }
@@ -91,3 +95,43 @@ func TestValidateRunnerResolved_CatchesBadValues(t *testing.T) {
 		t.Fatalf("expected validation error")
 	}
 }
+
+func TestResolveRunnerConfig_Preflight(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+runner:
+  preflight:
+    minReadyNodesPercent: 75
+    timeout: 10s
+profiles:
+  prod:
+    runner:
+      preflight:
+        onFailure: Skip
+releases: []
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	base, err := ResolveRunnerConfig(u, "")
+	if err != nil {
+		t.Fatalf("ResolveRunnerConfig: %v", err)
+	}
+	if base.Preflight == nil || !base.Preflight.Enabled || base.Preflight.MinReadyNodesPercent != 75 || base.Preflight.Timeout != 10*time.Second || base.Preflight.OnFailure != "deny" {
+		t.Fatalf("preflight=%+v", base.Preflight)
+	}
+	prod, err := ResolveRunnerConfig(u, "prod")
+	if err != nil {
+		t.Fatalf("ResolveRunnerConfig prod: %v", err)
+	}
+	if prod.Preflight.OnFailure != "skip" || prod.Preflight.MaxPendingCSRs != 5 {
+		t.Fatalf("prod preflight=%+v", prod.Preflight)
+	}
+	if none, _ := ResolveRunnerConfig(&Universe{RootDir: "/x", Stacks: map[string]StackFile{"/x": {}}}, ""); none.Preflight != nil {
+		t.Fatalf("expected no preflight when unset, got %+v", none.Preflight)
+	}
+}
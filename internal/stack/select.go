@@ -172,6 +172,7 @@ func Select(u *Universe, p *Plan, clusters []string, sel Selector) (*Plan, error
 		Nodes:     outNodes,
 		Runner:    p.Runner,
 		Hooks:     p.Hooks,
+		Disabled:  p.Disabled,
 		ByID:      map[string]*ResolvedRelease{},
 		ByCluster: map[string][]*ResolvedRelease{},
 	}
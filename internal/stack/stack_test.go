@@ -36,6 +36,7 @@ defaults:
 	writeFile(t, filepath.Join(root, "services", "stack.yaml"), `
 defaults:
   tags: [svc]
+  owner: { team: team-cache, slack: "#cache" }
 `)
 	writeFile(t, filepath.Join(root, "services", "redis", "release.yaml"), `
 apiVersion: ktl.dev/v1
@@ -44,6 +45,7 @@ name: redis
 chart: ./chart
 values: [values-redis.yaml]
 tags: [cache]
+owner: { slack: "#redis-oncall" }
 `)
 
 	u, err := Discover(root)
@@ -75,6 +77,9 @@ tags: [cache]
 	if n.Set["global.cluster"] != "c1" {
 		t.Fatalf("set=%v", n.Set)
 	}
+	if n.Owner == nil || n.Owner.Team != "team-cache" || n.Owner.Slack != "#redis-oncall" {
+		t.Fatalf("owner=%+v", n.Owner)
+	}
 }
 
 func TestSelect_ByTagAndIncludeDeps(t *testing.T) {
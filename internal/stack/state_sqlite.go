@@ -275,6 +275,17 @@ CREATE TABLE IF NOT EXISTS ktl_stack_verify_cache (
   PRIMARY KEY (cluster_key, namespace, release_name)
 );`,
 		`CREATE INDEX IF NOT EXISTS idx_ktl_stack_verify_cache_lookup ON ktl_stack_verify_cache(cluster_key, namespace, release_name);`,
+		`
+CREATE TABLE IF NOT EXISTS ktl_stack_error_digests (
+  digest TEXT PRIMARY KEY,
+  class TEXT NOT NULL DEFAULT '',
+  message TEXT NOT NULL DEFAULT '',
+  count INTEGER NOT NULL DEFAULT 0,
+  first_seen_ns INTEGER NOT NULL DEFAULT 0,
+  last_seen_ns INTEGER NOT NULL DEFAULT 0,
+  last_run_id TEXT NOT NULL DEFAULT '',
+  last_node_id TEXT NOT NULL DEFAULT ''
+);`,
 	}
 	for _, stmt := range stmts {
 		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
@@ -757,6 +768,7 @@ func (s *stackStateStore) AppendEvent(ctx context.Context, runID string, ev RunE
 				nodeErr = errMsg
 				lastErrClass = errClass
 				lastErrDigest = errDigest
+				_ = s.recordErrorDigest(ctx, runID, nodeID, ts.UnixNano(), ev.Error)
 			}
 			updatedNodeAt := time.Now().UTC().UnixNano()
 			_, _ = s.db.ExecContext(ctx, `
@@ -0,0 +1,417 @@
+// File: internal/stack/task.go
+// Brief: `type: task` nodes: run-to-completion Jobs or local commands inside the DAG.
+
+package stack
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	taskPhase          = "task"
+	taskContainerName  = "task"
+	taskNodeLabel      = "ktl.dev/stack-node"
+	taskJobTTLSeconds  = int32(3600)
+	taskPodPollEvery   = 2 * time.Second
+	taskMaxJobNameBase = 52 // leaves room for the generated suffix within the 63-char limit
+)
+
+// digestTask fingerprints a task node's spec for the effective input hash.
+func digestTask(t *TaskSpec) (EffectiveChartInput, error) {
+	if t == nil {
+		return EffectiveChartInput{}, fmt.Errorf("task spec is required")
+	}
+	raw, err := json.Marshal(t)
+	if err != nil {
+		return EffectiveChartInput{}, err
+	}
+	sum := sha256.Sum256(raw)
+	ref := t.Image
+	if ref == "" {
+		ref = strings.Join(t.Run, " ")
+	}
+	return EffectiveChartInput{
+		Ref:    "task:" + ref,
+		Digest: "sha256:" + hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+func taskMaxAttempts(t *TaskSpec) int {
+	if t == nil || t.Retry == nil || *t.Retry < 1 {
+		return 1
+	}
+	return *t.Retry
+}
+
+// runTaskNode runs a task node to completion on apply, retrying failed attempts up to
+// task.retry. Output lines are recorded as HELM_LOG events (source "task") so they show up in
+// the console log panel and in failure hints. Tasks have nothing to undo on delete beyond
+// removing leftover Jobs.
+func (e *helmExecutor) runTaskNode(ctx context.Context, node *runNode, command, kubeconfigPath, kubeCtx string) error {
+	obs := &stackEventObserver{run: e.run, node: node}
+	task := node.Task
+	if task == nil {
+		return wrapNodeErr(node.ResolvedRelease, fmt.Errorf("task spec is required"))
+	}
+
+	var kubeClient *kube.Client
+	if task.Image != "" {
+		var err error
+		kubeClient, err = e.clients.get(ctx, kubeconfigPath, kubeCtx, node.Cluster.ExecCredentialEnv)
+		if err != nil {
+			return wrapNodeErr(node.ResolvedRelease, err)
+		}
+	}
+
+	switch command {
+	case "apply":
+	case "delete":
+		if kubeClient == nil || e.dryRun {
+			obs.PhaseCompleted(taskPhase, "skipped", "Tasks only run on apply")
+			return nil
+		}
+		if err := deleteTaskJobs(ctx, kubeClient, node.Namespace, node.Name); err != nil {
+			return wrapNodeErr(node.ResolvedRelease, err)
+		}
+		obs.PhaseCompleted(taskPhase, "succeeded", "Removed task Jobs")
+		return nil
+	default:
+		return wrapNodeErr(node.ResolvedRelease, fmt.Errorf("unknown command %q", command))
+	}
+
+	if node.resume != nil && node.resume.VerifyOnly {
+		obs.PhaseCompleted(taskPhase, "skipped", "Resume verify-only skipped")
+		return nil
+	}
+	if e.dryRun {
+		if kubeClient != nil {
+			// Let the API server validate the Job without running it.
+			job := buildTaskJob(node.ResolvedRelease)
+			if _, err := kubeClient.Clientset.BatchV1().Jobs(node.Namespace).Create(ctx, job, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}); err != nil {
+				obs.PhaseCompleted(taskPhase, "failed", err.Error())
+				return wrapNodeErr(node.ResolvedRelease, err)
+			}
+		}
+		obs.PhaseCompleted(taskPhase, "skipped", "Dry run: task not executed")
+		return nil
+	}
+	if kubeClient != nil {
+		if err := waitForNodeDependencies(ctx, e.run, kubeClient, node); err != nil {
+			return wrapNodeErr(node.ResolvedRelease, err)
+		}
+	}
+
+	timeout := 5 * time.Minute
+	if node.Apply.Timeout != nil {
+		timeout = *node.Apply.Timeout
+	}
+	if task.Timeout != nil {
+		timeout = *task.Timeout
+	}
+	maxAttempts := taskMaxAttempts(task)
+
+	obs.PhaseStarted(taskPhase)
+	var lastErr error
+	for try := 1; try <= maxAttempts; try++ {
+		tryCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout > 0 {
+			tryCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		logLine := func(line string) { e.emitTaskLog(node, try, line) }
+		if kubeClient != nil {
+			lastErr = runTaskJob(tryCtx, kubeClient, node.ResolvedRelease, timeout, logLine)
+		} else {
+			lastErr = runTaskCommand(tryCtx, node.ResolvedRelease, taskEnv(node, e.run, kubeconfigPath, kubeCtx), logLine)
+		}
+		if lastErr != nil && tryCtx.Err() == context.DeadlineExceeded {
+			lastErr = fmt.Errorf("task timed out after %s: %w", timeout, lastErr)
+		}
+		cancel()
+		if lastErr == nil {
+			obs.PhaseCompleted(taskPhase, "succeeded", fmt.Sprintf("Task completed (attempt %d/%d)", try, maxAttempts))
+			return nil
+		}
+		if ctx.Err() != nil || try == maxAttempts {
+			break
+		}
+		backoff := retryBackoff(try)
+		if e.run != nil {
+			e.run.EmitEphemeralEvent(node.ID, NodeLog, node.Attempt, fmt.Sprintf("task failed (attempt %d/%d): %v (retrying in %s)", try, maxAttempts, lastErr, backoff), map[string]any{"attempt": try, "maxAttempts": maxAttempts, "backoff": backoff.String()})
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+	}
+	obs.PhaseCompleted(taskPhase, "failed", lastErr.Error())
+	return wrapNodeErr(node.ResolvedRelease, lastErr)
+}
+
+func (e *helmExecutor) emitTaskLog(node *runNode, try int, line string) {
+	line = strings.TrimRight(line, "\r\t ")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if e.run == nil {
+		fmt.Fprintf(e.errOut, "[task %s] %s\n", node.Name, line)
+		return
+	}
+	e.run.AppendEvent(node.ID, HelmLog, node.Attempt, line, map[string]any{"source": "task", "taskAttempt": try}, nil)
+}
+
+func taskEnv(node *runNode, run *runState, kubeconfigPath, kubeCtx string) []string {
+	env := append([]string(nil), os.Environ()...)
+	runID := ""
+	if run != nil {
+		runID = strings.TrimSpace(run.RunID)
+	}
+	env = append(env,
+		"KTL_STACK_RUN_ID="+runID,
+		"KTL_RELEASE_ID="+node.ID,
+		"KTL_RELEASE_NAME="+node.Name,
+		"KTL_RELEASE_DIR="+node.Dir,
+		"KTL_RELEASE_NAMESPACE="+node.Namespace,
+		"KTL_CLUSTER_NAME="+node.Cluster.Name,
+	)
+	if kubeconfigPath != "" {
+		env = append(env, "KUBECONFIG="+kubeconfigPath)
+	}
+	if kubeCtx != "" {
+		env = append(env, "KUBE_CONTEXT="+kubeCtx)
+	}
+	env = append(env, sortedEnv(node.Cluster.ExecCredentialEnv)...)
+	return append(env, sortedEnv(node.Task.Env)...)
+}
+
+func sortedEnv(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, k+"="+m[k])
+	}
+	return out
+}
+
+// runTaskCommand runs task.run locally and streams stdout and stderr line by line.
+func runTaskCommand(ctx context.Context, node *ResolvedRelease, env []string, logLine func(string)) error {
+	argv := node.Task.Run
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = node.Dir
+	if node.Task.WorkDir != "" {
+		cmd.Dir = node.Task.WorkDir
+	}
+	cmd.Env = env
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("task %s: %w", strings.Join(argv, " "), err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanLines(pr, logLine)
+	}()
+	err := cmd.Wait()
+	_ = pw.Close()
+	<-done
+	if err != nil {
+		return fmt.Errorf("task %s: %w", strings.Join(argv, " "), err)
+	}
+	return nil
+}
+
+func scanLines(r io.Reader, logLine func(string)) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		logLine(sc.Text())
+	}
+	// Drain so the writer never blocks on an over-long line.
+	_, _ = io.Copy(io.Discard, r)
+}
+
+func buildTaskJob(node *ResolvedRelease) *batchv1.Job {
+	t := node.Task
+	base := node.Name
+	if len(base) > taskMaxJobNameBase {
+		base = base[:taskMaxJobNameBase]
+	}
+	backoffLimit := int32(0)
+	ttl := taskJobTTLSeconds
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "ktl",
+		taskNodeLabel:                  node.Name,
+	}
+	container := corev1.Container{
+		Name:    taskContainerName,
+		Image:   t.Image,
+		Command: t.Command,
+		Args:    t.Args,
+	}
+	for _, kv := range sortedEnv(t.Env) {
+		k, v, _ := strings.Cut(kv, "=")
+		container.Env = append(container.Env, corev1.EnvVar{Name: k, Value: v})
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: strings.TrimSuffix(base, "-") + "-",
+			Namespace:    node.Namespace,
+			Labels:       labels,
+		},
+		Spec: batchv1.JobSpec{
+			// Retries are driven by ktl so every attempt gets its own Job and log stream.
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: t.ServiceAccount,
+					Containers:         []corev1.Container{container},
+				},
+			},
+		},
+	}
+}
+
+// runTaskJob creates one Job for the attempt, follows its pod logs, and waits for the Job to
+// complete or fail.
+func runTaskJob(ctx context.Context, kubeClient *kube.Client, node *ResolvedRelease, timeout time.Duration, logLine func(string)) error {
+	job := buildTaskJob(node)
+	if timeout > 0 {
+		deadline := int64(timeout.Seconds())
+		if deadline < 1 {
+			deadline = 1
+		}
+		job.Spec.ActiveDeadlineSeconds = &deadline
+	}
+	jobs := kubeClient.Clientset.BatchV1().Jobs(node.Namespace)
+	created, err := jobs.Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("create task job: %w", err)
+	}
+	logLine(fmt.Sprintf("job %s/%s created", created.Namespace, created.Name))
+
+	var logsDone chan struct{}
+	for {
+		if logsDone == nil {
+			if pod := taskJobPod(ctx, kubeClient, created); pod != "" {
+				logsDone = make(chan struct{})
+				go func() {
+					defer close(logsDone)
+					streamTaskPodLogs(ctx, kubeClient, created.Namespace, pod, logLine)
+				}()
+			}
+		}
+		cur, err := jobs.Get(ctx, created.Name, metav1.GetOptions{})
+		if err != nil {
+			if ctx.Err() != nil {
+				cleanupTaskJob(kubeClient, created)
+				return ctx.Err()
+			}
+			return fmt.Errorf("get task job: %w", err)
+		}
+		if done, failMsg := taskJobFinished(cur); done {
+			if logsDone != nil {
+				select {
+				case <-logsDone:
+				case <-time.After(5 * time.Second):
+				}
+			}
+			if failMsg != "" {
+				return fmt.Errorf("job %s failed: %s", created.Name, failMsg)
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			cleanupTaskJob(kubeClient, created)
+			return ctx.Err()
+		case <-time.After(taskPodPollEvery):
+		}
+	}
+}
+
+func taskJobFinished(job *batchv1.Job) (bool, string) {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return true, ""
+		case batchv1.JobFailed:
+			msg := strings.TrimSpace(c.Reason + ": " + c.Message)
+			return true, strings.Trim(msg, ": ")
+		}
+	}
+	return false, ""
+}
+
+// taskJobPod returns the Job's pod once its container has started (or finished).
+func taskJobPod(ctx context.Context, kubeClient *kube.Client, job *batchv1.Job) string {
+	pods, err := kubeClient.Clientset.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + job.Name})
+	if err != nil {
+		return ""
+	}
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name == taskContainerName && (cs.State.Running != nil || cs.State.Terminated != nil) {
+				return pod.Name
+			}
+		}
+	}
+	return ""
+}
+
+func streamTaskPodLogs(ctx context.Context, kubeClient *kube.Client, namespace, pod string, logLine func(string)) {
+	stream, err := kubeClient.Clientset.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{Container: taskContainerName, Follow: true}).Stream(ctx)
+	if err != nil {
+		logLine(fmt.Sprintf("logs unavailable for pod %s: %v", pod, err))
+		return
+	}
+	defer stream.Close()
+	scanLines(stream, logLine)
+}
+
+// cleanupTaskJob deletes a Job abandoned by a timeout or cancellation so it does not keep
+// running behind the stack's back.
+func cleanupTaskJob(kubeClient *kube.Client, job *batchv1.Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	policy := metav1.DeletePropagationBackground
+	_ = kubeClient.Clientset.BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &policy})
+}
+
+func deleteTaskJobs(ctx context.Context, kubeClient *kube.Client, namespace, node string) error {
+	policy := metav1.DeletePropagationBackground
+	err := kubeClient.Clientset.BatchV1().Jobs(namespace).DeleteCollection(ctx,
+		metav1.DeleteOptions{PropagationPolicy: &policy},
+		metav1.ListOptions{LabelSelector: taskNodeLabel + "=" + node})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("delete task jobs: %w", err)
+	}
+	return nil
+}
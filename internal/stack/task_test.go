@@ -0,0 +1,125 @@
+package stack
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestNodeSourceProblem_Task(t *testing.T) {
+	cases := map[string]struct {
+		task *TaskSpec
+		want string
+	}{
+		"missing":  {task: nil, want: "task.image or task.run is required"},
+		"both":     {task: &TaskSpec{Image: "migrate:1", Run: []string{"make"}}, want: "mutually exclusive"},
+		"image":    {task: &TaskSpec{Image: "migrate:1"}},
+		"localRun": {task: &TaskSpec{Run: []string{"./migrate.sh"}}},
+	}
+	for name, tc := range cases {
+		got := nodeSourceProblem(NodeTypeTask, "", nil, tc.task)
+		if (tc.want == "" && got != "") || !strings.Contains(got, tc.want) {
+			t.Fatalf("%s: got %q want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestRunTaskNode_LocalCommandRetries(t *testing.T) {
+	dir := t.TempDir()
+	retry := 2
+	node := &runNode{ResolvedRelease: &ResolvedRelease{
+		ID:   "c1/ns/migrate",
+		Name: "migrate",
+		Dir:  dir,
+		Type: NodeTypeTask,
+		Task: &TaskSpec{
+			Run:   []string{"sh", "-c", `echo "run $KTL_RELEASE_NAME $GREETING"; [ -f done ] && exit 0; touch done; exit 3`},
+			Env:   map[string]string{"GREETING": "hello"},
+			Retry: &retry,
+		},
+	}}
+	var errOut bytes.Buffer
+	e := &helmExecutor{errOut: &errOut}
+	if err := e.runTaskNode(context.Background(), node, "apply", "", ""); err != nil {
+		t.Fatalf("expected second attempt to succeed, got %v", err)
+	}
+	if got := strings.Count(errOut.String(), "[task migrate] run migrate hello"); got != 2 {
+		t.Fatalf("expected output from two attempts, got:\n%s", errOut.String())
+	}
+
+	one := 1
+	node.Task.Retry = &one
+	node.Task.Run = []string{"sh", "-c", "exit 7"}
+	if err := e.runTaskNode(context.Background(), node, "apply", "", ""); err == nil || !strings.Contains(err.Error(), "exit status 7") {
+		t.Fatalf("expected failure, got %v", err)
+	}
+}
+
+func TestRunTaskJob_CreatesJobAndWaits(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	var created *batchv1.Job
+	clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batchv1.Job).DeepCopy()
+		job.Name = job.GenerateName + "abcde"
+		created = job
+		return true, job, nil
+	})
+	clientset.PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := created.DeepCopy()
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		return true, job, nil
+	})
+
+	node := &ResolvedRelease{
+		Name:      "db-migrate",
+		Namespace: "prod",
+		Type:      NodeTypeTask,
+		Task: &TaskSpec{
+			Image:          "ghcr.io/acme/migrate:1.2",
+			Args:           []string{"up"},
+			ServiceAccount: "migrator",
+			Env:            map[string]string{"B": "2", "A": "1"},
+		},
+	}
+	var lines []string
+	err := runTaskJob(context.Background(), &kube.Client{Clientset: clientset}, node, 90*time.Second, func(l string) { lines = append(lines, l) })
+	if err != nil {
+		t.Fatalf("runTaskJob: %v", err)
+	}
+	if created == nil || created.Namespace != "prod" || created.GenerateName != "db-migrate-" {
+		t.Fatalf("unexpected job %+v", created)
+	}
+	spec := created.Spec
+	if *spec.BackoffLimit != 0 || *spec.ActiveDeadlineSeconds != 90 || spec.Template.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Fatalf("unexpected job spec %+v", spec)
+	}
+	c := spec.Template.Spec.Containers[0]
+	if c.Image != "ghcr.io/acme/migrate:1.2" || len(c.Env) != 2 || c.Env[0].Name != "A" || spec.Template.Spec.ServiceAccountName != "migrator" {
+		t.Fatalf("unexpected container %+v", c)
+	}
+	if created.Labels[taskNodeLabel] != "db-migrate" {
+		t.Fatalf("labels=%v", created.Labels)
+	}
+	if len(lines) == 0 || !strings.Contains(lines[0], "prod/db-migrate-abcde created") {
+		t.Fatalf("lines=%v", lines)
+	}
+
+	clientset.PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := created.DeepCopy()
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"}}
+		return true, job, nil
+	})
+	err = runTaskJob(context.Background(), &kube.Client{Clientset: clientset}, node, time.Minute, func(string) {})
+	if err == nil || !strings.Contains(err.Error(), "BackoffLimitExceeded") {
+		t.Fatalf("expected job failure, got %v", err)
+	}
+}
@@ -3,7 +3,12 @@
 
 package stack
 
-import "time"
+import (
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/verify"
+)
 
 type APIVersionKind struct {
 	APIVersion string `yaml:"apiVersion,omitempty" json:"apiVersion,omitempty"`
@@ -14,6 +19,9 @@ type ClusterTarget struct {
 	Name       string `yaml:"name,omitempty" json:"name,omitempty"`
 	Kubeconfig string `yaml:"kubeconfig,omitempty" json:"kubeconfig,omitempty"`
 	Context    string `yaml:"context,omitempty" json:"context,omitempty"`
+	// ExecCredentialEnv is added to the environment of the kubeconfig user's exec credential
+	// plugin (e.g. AWS_PROFILE for `aws eks get-token`, or an SSO tenant for kubelogin).
+	ExecCredentialEnv map[string]string `yaml:"execCredentialEnv,omitempty" json:"execCredentialEnv,omitempty"`
 }
 
 type ApplyOptions struct {
@@ -21,12 +29,26 @@ type ApplyOptions struct {
 	Timeout         *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 	Wait            *bool          `yaml:"wait,omitempty" json:"wait,omitempty"`
 	CreateNamespace *bool          `yaml:"createNamespace,omitempty" json:"createNamespace,omitempty"`
+	// Images mirrors registries and pins digests in the rendered manifests (last definition wins).
+	Images *appconfig.ImageRewriteConfig `yaml:"images,omitempty" json:"images,omitempty"`
+	// RequireApproval lists plan risk tags (image-change, replica-decrease, probe-removal,
+	// security-context-loosened, pdb-deletion, storage-class-change, or all) that stop the release
+	// unless the run approves them with --approve-risk.
+	RequireApproval []string `yaml:"requireApproval,omitempty" json:"requireApproval,omitempty"`
 }
 
 type DeleteOptions struct {
 	Timeout *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
+// OwnerSpec declares who owns a release. It is recorded on the applied release and shown by
+// ktl owners.
+type OwnerSpec struct {
+	Team   string `yaml:"team,omitempty" json:"team,omitempty"`
+	Slack  string `yaml:"slack,omitempty" json:"slack,omitempty"`
+	Oncall string `yaml:"oncall,omitempty" json:"oncall,omitempty"`
+}
+
 type VerifyOptions struct {
 	// Enabled toggles post-apply verification for this release.
 	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
@@ -48,6 +70,28 @@ type VerifyOptions struct {
 
 	// RequireConditions enforces status.conditions on matching custom resources (CRs).
 	RequireConditions []VerifyConditionRequirement `yaml:"requireConditions,omitempty" json:"requireConditions,omitempty"`
+
+	// Config references a ktl verify config file whose verify: section (rules, failOn,
+	// selectors) is evaluated against the release's live objects once wait succeeds.
+	Config string `yaml:"config,omitempty" json:"config,omitempty"`
+	// Rules configures the policy rules inline; set fields override those from Config.
+	Rules *VerifyRulesOptions `yaml:"rules,omitempty" json:"rules,omitempty"`
+}
+
+// VerifyRulesOptions selects the verify rules a release must pass after apply. Findings at or
+// above FailOn fail the release and are not retried.
+type VerifyRulesOptions struct {
+	// FailOn is the lowest finding severity that fails the release:
+	// info|low|medium|high|critical (default high).
+	FailOn string `yaml:"failOn,omitempty" json:"failOn,omitempty"`
+	// RulesDir replaces the builtin ruleset.
+	RulesDir string `yaml:"rulesDir,omitempty" json:"rulesDir,omitempty"`
+	// RulesPath adds rule directories to the ruleset.
+	RulesPath []string `yaml:"rulesPath,omitempty" json:"rulesPath,omitempty"`
+	// Selectors limit which objects are evaluated.
+	Selectors *verify.SelectorSet `yaml:"selectors,omitempty" json:"selectors,omitempty"`
+	// RuleSelectors limit individual rules to matching objects.
+	RuleSelectors []verify.RuleSelector `yaml:"ruleSelectors,omitempty" json:"ruleSelectors,omitempty"`
 }
 
 type VerifyConditionRequirement struct {
@@ -58,6 +102,46 @@ type VerifyConditionRequirement struct {
 	AllowMissing  bool   `yaml:"allowMissing,omitempty" json:"allowMissing,omitempty"`
 }
 
+// Node types. An empty type is a Helm release.
+const (
+	NodeTypeHelm      = "helm"
+	NodeTypeManifests = "manifests"
+	NodeTypeTask      = "task"
+)
+
+// ManifestsSpec configures a `type: manifests` node: a directory of plain YAML applied with
+// server-side apply instead of a Helm chart.
+type ManifestsSpec struct {
+	// Path is the directory holding *.yaml, *.yml, and *.json files (read recursively, in
+	// lexical order).
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// Template renders the files with Helm's template engine using the node's values and set
+	// entries (.Values, .Release.Name, .Release.Namespace) before applying them.
+	Template bool `yaml:"template,omitempty" json:"template,omitempty"`
+	// Prune deletes objects applied by a previous run that are no longer in the directory.
+	// Defaults to true.
+	Prune *bool `yaml:"prune,omitempty" json:"prune,omitempty"`
+}
+
+// TaskSpec configures a `type: task` node: a step that runs to completion, either as a Kubernetes
+// Job (image) or as a local command (run), e.g. a database migration between releases.
+type TaskSpec struct {
+	// Image runs the task as a Job in the node's namespace.
+	Image          string   `yaml:"image,omitempty" json:"image,omitempty"`
+	Command        []string `yaml:"command,omitempty" json:"command,omitempty"`
+	Args           []string `yaml:"args,omitempty" json:"args,omitempty"`
+	ServiceAccount string   `yaml:"serviceAccountName,omitempty" json:"serviceAccountName,omitempty"`
+	// Run executes a local command (argv) instead of a Job, from WorkDir or the node directory.
+	Run     []string `yaml:"run,omitempty" json:"run,omitempty"`
+	WorkDir string   `yaml:"workDir,omitempty" json:"workDir,omitempty"`
+
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+	// Timeout bounds each attempt. Defaults to the node's apply timeout (5m).
+	Timeout *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// Retry is the max attempts, including the initial attempt. Defaults to 1.
+	Retry *int `yaml:"retry,omitempty" json:"retry,omitempty"`
+}
+
 type ReleaseDefaults struct {
 	Cluster    ClusterTarget     `yaml:"cluster,omitempty" json:"cluster,omitempty"`
 	Namespace  string            `yaml:"namespace,omitempty" json:"namespace,omitempty"`
@@ -67,15 +151,17 @@ type ReleaseDefaults struct {
 	Delete     DeleteOptions     `yaml:"delete,omitempty" json:"delete,omitempty"`
 	Verify     VerifyOptions     `yaml:"verify,omitempty" json:"verify,omitempty"`
 	Tags       []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Owner      *OwnerSpec        `yaml:"owner,omitempty" json:"owner,omitempty"`
 	Extra      map[string]any    `yaml:",inline" json:"-"`
 	RawIgnored map[string]any    `yaml:"-" json:"-"`
 }
 
 type StackProfile struct {
-	Defaults ReleaseDefaults  `yaml:"defaults,omitempty" json:"defaults,omitempty"`
-	Runner   RunnerConfig     `yaml:"runner,omitempty" json:"runner,omitempty"`
-	CLI      StackCLIConfig   `yaml:"cli,omitempty" json:"cli,omitempty"`
-	Hooks    StackHooksConfig `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	Defaults ReleaseDefaults          `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+	Clusters map[string]ClusterTarget `yaml:"clusters,omitempty" json:"clusters,omitempty"`
+	Runner   RunnerConfig             `yaml:"runner,omitempty" json:"runner,omitempty"`
+	CLI      StackCLIConfig           `yaml:"cli,omitempty" json:"cli,omitempty"`
+	Hooks    StackHooksConfig         `yaml:"hooks,omitempty" json:"hooks,omitempty"`
 }
 
 type StackFile struct {
@@ -85,11 +171,14 @@ type StackFile struct {
 	DefaultProfile string                  `yaml:"defaultProfile,omitempty" json:"defaultProfile,omitempty"`
 	Profiles       map[string]StackProfile `yaml:"profiles,omitempty" json:"profiles,omitempty"`
 
-	Defaults ReleaseDefaults  `yaml:"defaults,omitempty" json:"defaults,omitempty"`
-	Runner   RunnerConfig     `yaml:"runner,omitempty" json:"runner,omitempty"`
-	CLI      StackCLIConfig   `yaml:"cli,omitempty" json:"cli,omitempty"`
-	Hooks    StackHooksConfig `yaml:"hooks,omitempty" json:"hooks,omitempty"`
-	Releases []ReleaseSpec    `yaml:"releases,omitempty" json:"releases,omitempty"`
+	Defaults ReleaseDefaults `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+	// Clusters maps cluster names (as used in cluster.name) to connection settings. Only the
+	// root stack.yaml is read; releases may still override kubeconfig/context themselves.
+	Clusters map[string]ClusterTarget `yaml:"clusters,omitempty" json:"clusters,omitempty"`
+	Runner   RunnerConfig             `yaml:"runner,omitempty" json:"runner,omitempty"`
+	CLI      StackCLIConfig           `yaml:"cli,omitempty" json:"cli,omitempty"`
+	Hooks    StackHooksConfig         `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	Releases []ReleaseSpec            `yaml:"releases,omitempty" json:"releases,omitempty"`
 }
 
 type StackHooksConfig struct {
@@ -100,10 +189,12 @@ type StackHooksConfig struct {
 }
 
 type HookSpec struct {
-	Name    string         `yaml:"name,omitempty" json:"name,omitempty"`
-	Type    string         `yaml:"type,omitempty" json:"type,omitempty"` // kubectl|script|http
-	RunOnce bool           `yaml:"runOnce,omitempty" json:"runOnce,omitempty"`
-	When    string         `yaml:"when,omitempty" json:"when,omitempty"` // success|failure|always
+	Name    string `yaml:"name,omitempty" json:"name,omitempty"`
+	Type    string `yaml:"type,omitempty" json:"type,omitempty"` // kubectl|script|http
+	RunOnce bool   `yaml:"runOnce,omitempty" json:"runOnce,omitempty"`
+	When    string `yaml:"when,omitempty" json:"when,omitempty"` // success|failure|always
+	// Enabled is a CEL expression like a release's enabled; false drops the hook at compile time.
+	Enabled string         `yaml:"enabled,omitempty" json:"enabled,omitempty"`
 	Timeout *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 	Retry   *int           `yaml:"retry,omitempty" json:"retry,omitempty"` // max attempts, includes the initial attempt
 
@@ -198,14 +289,21 @@ type StackLockCLIConfig struct {
 }
 
 type RunnerConfig struct {
-	Concurrency            *int           `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
-	ProgressiveConcurrency *bool          `yaml:"progressiveConcurrency,omitempty" json:"progressiveConcurrency,omitempty"`
-	KubeQPS                *float32       `yaml:"kubeQPS,omitempty" json:"kubeQPS,omitempty"`
-	KubeBurst              *int           `yaml:"kubeBurst,omitempty" json:"kubeBurst,omitempty"`
-	Limits                 RunnerLimits   `yaml:"limits,omitempty" json:"limits,omitempty"`
-	Adaptive               RunnerAdaptive `yaml:"adaptive,omitempty" json:"adaptive,omitempty"`
-	Extra                  map[string]any `yaml:",inline" json:"-"`
-	RawIgnored             map[string]any `yaml:"-" json:"-"`
+	Concurrency            *int            `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+	ProgressiveConcurrency *bool           `yaml:"progressiveConcurrency,omitempty" json:"progressiveConcurrency,omitempty"`
+	KubeQPS                *float32        `yaml:"kubeQPS,omitempty" json:"kubeQPS,omitempty"`
+	KubeBurst              *int            `yaml:"kubeBurst,omitempty" json:"kubeBurst,omitempty"`
+	Limits                 RunnerLimits    `yaml:"limits,omitempty" json:"limits,omitempty"`
+	Adaptive               RunnerAdaptive  `yaml:"adaptive,omitempty" json:"adaptive,omitempty"`
+	Preflight              RunnerPreflight `yaml:"preflight,omitempty" json:"preflight,omitempty"`
+	Flaky                  []RunnerFlaky   `yaml:"flaky,omitempty" json:"flaky,omitempty"`
+	// MaxFailures stops scheduling after this many node failures (0 disables).
+	MaxFailures *int `yaml:"maxFailures,omitempty" json:"maxFailures,omitempty"`
+	// FailureDomain is run (default) or cluster: with cluster, failures stop scheduling only on
+	// the cluster they happened on.
+	FailureDomain string         `yaml:"failureDomain,omitempty" json:"failureDomain,omitempty"`
+	Extra         map[string]any `yaml:",inline" json:"-"`
+	RawIgnored    map[string]any `yaml:"-" json:"-"`
 }
 
 type RunnerLimits struct {
@@ -223,6 +321,32 @@ type RunnerAdaptive struct {
 	CooldownSevere     *int     `yaml:"cooldownSevere,omitempty" json:"cooldownSevere,omitempty"`
 }
 
+// RunnerPreflight gates a run on the health of each target cluster before anything is scheduled.
+type RunnerPreflight struct {
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	// MinReadyNodesPercent is the lowest share of Ready nodes allowed. Defaults to 90.
+	MinReadyNodesPercent *int `yaml:"minReadyNodesPercent,omitempty" json:"minReadyNodesPercent,omitempty"`
+	// MaxPendingCSRs is the most CertificateSigningRequests allowed without a decision. Defaults to 5.
+	MaxPendingCSRs *int `yaml:"maxPendingCSRs,omitempty" json:"maxPendingCSRs,omitempty"`
+	// MaxEvictedPods is the most Evicted pods allowed across the cluster. Defaults to 20.
+	MaxEvictedPods *int `yaml:"maxEvictedPods,omitempty" json:"maxEvictedPods,omitempty"`
+	// OnFailure is deny (fail the run) or skip (block only the unhealthy cluster's releases).
+	OnFailure string `yaml:"onFailure,omitempty" json:"onFailure,omitempty"`
+	// Timeout bounds the checks per cluster. Defaults to 30s.
+	Timeout *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// RunnerFlaky marks a known flaky node failure. Digest is an error digest (or a prefix of one) as
+// listed by `ktl stack errors`; Match is a regular expression tried against the error message.
+type RunnerFlaky struct {
+	Digest string `yaml:"digest,omitempty" json:"digest,omitempty"`
+	Match  string `yaml:"match,omitempty" json:"match,omitempty"`
+	// Link is shown next to the failure, e.g. the issue tracking the flake.
+	Link string `yaml:"link,omitempty" json:"link,omitempty"`
+	// Retry retries the node even when its error class is not normally retried. Defaults to true.
+	Retry *bool `yaml:"retry,omitempty" json:"retry,omitempty"`
+}
+
 type RunnerResolved struct {
 	Concurrency            int                    `json:"concurrency"`
 	ProgressiveConcurrency bool                   `json:"progressiveConcurrency"`
@@ -230,6 +354,11 @@ type RunnerResolved struct {
 	KubeBurst              int                    `json:"kubeBurst,omitempty"`
 	Limits                 RunnerLimitsResolved   `json:"limits,omitempty"`
 	Adaptive               RunnerAdaptiveResolved `json:"adaptive,omitempty"`
+	// Preflight is set when runner.preflight is configured.
+	Preflight     *ClusterHealthOptions `json:"preflight,omitempty"`
+	Flaky         []FlakyRule           `json:"flaky,omitempty"`
+	MaxFailures   int                   `json:"maxFailures,omitempty"`
+	FailureDomain string                `json:"failureDomain,omitempty"`
 }
 
 type RunnerLimitsResolved struct {
@@ -250,9 +379,15 @@ type RunnerAdaptiveResolved struct {
 type ReleaseFile struct {
 	APIVersionKind `yaml:",inline" json:",inline"`
 
-	Name         string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	Type string `yaml:"type,omitempty" json:"type,omitempty"` // helm (default)|manifests|task
+	// Enabled is a CEL expression (optionally wrapped in ${...}) over values, profile, cluster,
+	// namespace, release, tags, and env. A false result drops the release from the plan.
+	Enabled      string            `yaml:"enabled,omitempty" json:"enabled,omitempty"`
 	Chart        string            `yaml:"chart,omitempty" json:"chart,omitempty"`
 	ChartVersion string            `yaml:"chartVersion,omitempty" json:"chartVersion,omitempty"`
+	Manifests    *ManifestsSpec    `yaml:"manifests,omitempty" json:"manifests,omitempty"`
+	Task         *TaskSpec         `yaml:"task,omitempty" json:"task,omitempty"`
 	Wave         int               `yaml:"wave,omitempty" json:"wave,omitempty"`
 	Critical     bool              `yaml:"critical,omitempty" json:"critical,omitempty"`
 	Parallelism  string            `yaml:"parallelismGroup,omitempty" json:"parallelismGroup,omitempty"`
@@ -262,15 +397,25 @@ type ReleaseFile struct {
 	Set          map[string]string `yaml:"set,omitempty" json:"set,omitempty"`
 	Tags         []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
 	Needs        []string          `yaml:"needs,omitempty" json:"needs,omitempty"`
-	Apply        ApplyOptions      `yaml:"apply,omitempty" json:"apply,omitempty"`
-	Delete       DeleteOptions     `yaml:"delete,omitempty" json:"delete,omitempty"`
-	Hooks        StackHooksConfig  `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	// WaitFor lists cluster readiness gates checked before apply: crd/<name> or webhook/<name>.
+	WaitFor        []string         `yaml:"waitFor,omitempty" json:"waitFor,omitempty"`
+	WaitForTimeout *time.Duration   `yaml:"waitForTimeout,omitempty" json:"waitForTimeout,omitempty"`
+	Apply          ApplyOptions     `yaml:"apply,omitempty" json:"apply,omitempty"`
+	Delete         DeleteOptions    `yaml:"delete,omitempty" json:"delete,omitempty"`
+	Owner          *OwnerSpec       `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Hooks          StackHooksConfig `yaml:"hooks,omitempty" json:"hooks,omitempty"`
 }
 
 type ReleaseSpec struct {
-	Name         string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	Type string `yaml:"type,omitempty" json:"type,omitempty"` // helm (default)|manifests|task
+	// Enabled is a CEL expression (optionally wrapped in ${...}) over values, profile, cluster,
+	// namespace, release, tags, and env. A false result drops the release from the plan.
+	Enabled      string            `yaml:"enabled,omitempty" json:"enabled,omitempty"`
 	Chart        string            `yaml:"chart,omitempty" json:"chart,omitempty"`
 	ChartVersion string            `yaml:"chartVersion,omitempty" json:"chartVersion,omitempty"`
+	Manifests    *ManifestsSpec    `yaml:"manifests,omitempty" json:"manifests,omitempty"`
+	Task         *TaskSpec         `yaml:"task,omitempty" json:"task,omitempty"`
 	Wave         int               `yaml:"wave,omitempty" json:"wave,omitempty"`
 	Critical     bool              `yaml:"critical,omitempty" json:"critical,omitempty"`
 	Parallelism  string            `yaml:"parallelismGroup,omitempty" json:"parallelismGroup,omitempty"`
@@ -280,10 +425,14 @@ type ReleaseSpec struct {
 	Set          map[string]string `yaml:"set,omitempty" json:"set,omitempty"`
 	Tags         []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
 	Needs        []string          `yaml:"needs,omitempty" json:"needs,omitempty"`
-	Apply        ApplyOptions      `yaml:"apply,omitempty" json:"apply,omitempty"`
-	Delete       DeleteOptions     `yaml:"delete,omitempty" json:"delete,omitempty"`
-	Verify       VerifyOptions     `yaml:"verify,omitempty" json:"verify,omitempty"`
-	Hooks        StackHooksConfig  `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	// WaitFor lists cluster readiness gates checked before apply: crd/<name> or webhook/<name>.
+	WaitFor        []string         `yaml:"waitFor,omitempty" json:"waitFor,omitempty"`
+	WaitForTimeout *time.Duration   `yaml:"waitForTimeout,omitempty" json:"waitForTimeout,omitempty"`
+	Apply          ApplyOptions     `yaml:"apply,omitempty" json:"apply,omitempty"`
+	Delete         DeleteOptions    `yaml:"delete,omitempty" json:"delete,omitempty"`
+	Verify         VerifyOptions    `yaml:"verify,omitempty" json:"verify,omitempty"`
+	Owner          *OwnerSpec       `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Hooks          StackHooksConfig `yaml:"hooks,omitempty" json:"hooks,omitempty"`
 }
 
 type ResolvedRelease struct {
@@ -293,8 +442,12 @@ type ResolvedRelease struct {
 	Cluster   ClusterTarget `json:"cluster"`
 	Namespace string        `json:"namespace"`
 
+	Type         string            `json:"type,omitempty"`
+	Enabled      string            `json:"enabled,omitempty"`
 	Chart        string            `json:"chart"`
 	ChartVersion string            `json:"chartVersion,omitempty"`
+	Manifests    *ManifestsSpec    `json:"manifests,omitempty"`
+	Task         *TaskSpec         `json:"task,omitempty"`
 	Wave         int               `json:"wave,omitempty"`
 	Critical     bool              `json:"critical,omitempty"`
 	Parallelism  string            `json:"parallelismGroup,omitempty"`
@@ -304,14 +457,21 @@ type ResolvedRelease struct {
 	Tags  []string `json:"tags"`
 	Needs []string `json:"needs"`
 
+	WaitFor        []string       `json:"waitFor,omitempty"`
+	WaitForTimeout *time.Duration `json:"waitForTimeout,omitempty"`
+
 	Apply  ApplyOptions  `json:"apply"`
 	Delete DeleteOptions `json:"delete"`
 	Verify VerifyOptions `json:"verify,omitempty"`
+	Owner  *OwnerSpec    `json:"owner,omitempty"`
 
 	Hooks StackHooksConfig `json:"hooks,omitempty"`
 
 	SelectedBy []string `json:"selectedBy,omitempty"`
 
+	// enabledBy is the overlay file that last set Enabled, for the plan's disabled list.
+	enabledBy string
+
 	InferredNeeds       []InferredNeed `json:"inferredNeeds,omitempty"`
 	InferredRole        string         `json:"inferredRole,omitempty"`
 	InferredPrimaryKind string         `json:"inferredPrimaryKind,omitempty"`
@@ -366,6 +526,7 @@ type EffectiveApplyInput struct {
 	Wait            bool   `json:"wait"`
 	CreateNamespace bool   `json:"createNamespace"`
 	Timeout         string `json:"timeout"`
+	Images          string `json:"images,omitempty"`
 	Digest          string `json:"digest,omitempty"`
 }
 
@@ -383,6 +544,8 @@ type EffectiveVerifyInput struct {
 	DenyReasons       []string                     `json:"denyReasons,omitempty"`
 	AllowReasons      []string                     `json:"allowReasons,omitempty"`
 	RequireConditions []VerifyConditionRequirement `json:"requireConditions,omitempty"`
+	Config            string                       `json:"config,omitempty"`
+	Rules             *VerifyRulesOptions          `json:"rules,omitempty"`
 	Digest            string                       `json:"digest,omitempty"`
 }
 
@@ -390,3 +553,13 @@ type FileDigest struct {
 	Path   string `json:"path"`
 	Digest string `json:"digest"`
 }
+
+// IsManifests reports whether the node applies a manifests directory rather than a Helm chart.
+func (r *ResolvedRelease) IsManifests() bool {
+	return r != nil && r.Type == NodeTypeManifests
+}
+
+// IsTask reports whether the node is a run-to-completion task rather than a deployed release.
+func (r *ResolvedRelease) IsTask() bool {
+	return r != nil && r.Type == NodeTypeTask
+}
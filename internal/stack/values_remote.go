@@ -0,0 +1,74 @@
+// File: internal/stack/values_remote.go
+// Brief: Checking release values sources at compile time and fetching https:// and oci:// ones when used.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+)
+
+// checkValuesSources validates every release's values sources without touching the network.
+// Pins on local files are verified and stripped, so later readers see plain paths; https:// and
+// oci:// sources are kept as written and fetched by releaseValuesFiles when a command needs them.
+func checkValuesSources(nodes []*ResolvedRelease) error {
+	for _, n := range nodes {
+		n.Values = append([]string(nil), n.Values...)
+		for i, src := range n.Values {
+			if err := deploy.CheckValuesSource(src); err != nil {
+				return fmt.Errorf("release %s: %w", n.ID, err)
+			}
+			ref, pin := deploy.SplitValuesPin(src)
+			if deploy.IsRemoteValuesSource(ref) || pin == "" {
+				continue
+			}
+			files, _, err := deploy.ResolveValuesSources(context.Background(), []string{src}, deploy.ValuesSourceOptions{})
+			if err != nil {
+				return fmt.Errorf("release %s: %w", n.ID, err)
+			}
+			n.Values[i] = files[0]
+		}
+	}
+	return nil
+}
+
+// releaseValuesFiles returns n's values as local files, fetching https:// and oci:// sources into
+// the values cache. Pinned sources already in the cache are not refetched, so commands that only
+// read values (enabled expressions, env diff) work offline once a pinned source has been fetched.
+func releaseValuesFiles(ctx context.Context, n *ResolvedRelease, secrets *deploy.SecretOptions) ([]string, error) {
+	out := make([]string, 0, len(n.Values))
+	for _, src := range n.Values {
+		ref, pin := deploy.SplitValuesPin(src)
+		if !deploy.IsRemoteValuesSource(ref) {
+			out = append(out, src)
+			continue
+		}
+		dir := stackValuesCacheDir()
+		if pin != "" {
+			cached := filepath.Join(dir, pin+".yaml")
+			if _, err := os.Stat(cached); err == nil {
+				out = append(out, cached)
+				continue
+			}
+		}
+		files, _, err := deploy.ResolveValuesSources(ctx, []string{src}, deploy.ValuesSourceOptions{Dir: dir, Secrets: secrets})
+		if err != nil {
+			return nil, fmt.Errorf("release %s: %w", n.ID, err)
+		}
+		out = append(out, files[0])
+	}
+	return out, nil
+}
+
+// stackValuesCacheDir holds fetched values documents, named by their sha256.
+func stackValuesCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "ktl", "values")
+}
@@ -0,0 +1,83 @@
+package stack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompile_RemoteValuesAreFetchedOnUse(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	const doc = "replicas: 3\n"
+	sum := sha256.Sum256([]byte(doc))
+	pin := hex.EncodeToString(sum[:])
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "base.yaml"), doc)
+	// config.invalid never resolves, so compiling must not fetch it.
+	remote := "https://config.invalid/prod.yaml#sha256=" + pin
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+defaults:
+  cluster: { name: c1 }
+  namespace: ns1
+releases:
+  - name: app
+    chart: ./chart
+    values:
+      - ./base.yaml#sha256=`+pin+`
+      - `+remote+`
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	p, err := Compile(u, CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	node := p.Nodes[0]
+	if len(node.Values) != 2 || node.Values[0] != filepath.Join(root, "base.yaml") || node.Values[1] != remote {
+		t.Fatalf("values=%v, want the local pin stripped and the remote source kept as written", node.Values)
+	}
+
+	if _, err := releaseValuesFiles(context.Background(), node, nil); err == nil {
+		t.Fatalf("expected fetching an uncached remote source to fail")
+	}
+	cached := filepath.Join(stackValuesCacheDir(), pin+".yaml")
+	writeFile(t, cached, doc)
+	files, err := releaseValuesFiles(context.Background(), node, nil)
+	if err != nil {
+		t.Fatalf("values files: %v", err)
+	}
+	if files[1] != cached {
+		t.Fatalf("files=%v, want the pinned source read from %s", files, cached)
+	}
+
+	for name, values := range map[string]string{
+		"plain http": "http://config.example.com/prod.yaml",
+		"local pin":  "./base.yaml#sha256=" + strings.Repeat("0", 64),
+	} {
+		writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+releases:
+  - name: app
+    chart: ./chart
+    values: [`+values+`]
+`)
+		u, err := Discover(root)
+		if err != nil {
+			t.Fatalf("%s: discover: %v", name, err)
+		}
+		if _, err := Compile(u, CompileOptions{}); err == nil {
+			t.Fatalf("%s: expected compile to fail", name)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// File: internal/stack/verify_rules.go
+// Brief: Post-apply verify rules (verify.config / verify.rules) for stack releases.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/kube"
+	"github.com/kubekattle/ktl/internal/verify"
+	verifyconfig "github.com/kubekattle/ktl/internal/verify/config"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// verifyRulesFailure is a release whose live objects have findings at or above failOn.
+// classifyError never retries it: the same objects produce the same findings.
+type verifyRulesFailure struct {
+	failOn   verify.Severity
+	findings []verify.Finding
+}
+
+func (e *verifyRulesFailure) Error() string {
+	const maxListed = 3
+	parts := make([]string, 0, maxListed)
+	for i, f := range e.findings {
+		if i == maxListed {
+			parts = append(parts, fmt.Sprintf("+%d more", len(e.findings)-maxListed))
+			break
+		}
+		subject := strings.TrimSpace(f.ResourceKey)
+		if subject == "" {
+			subject = strings.TrimSpace(f.Subject.Kind + "/" + f.Subject.Name)
+		}
+		parts = append(parts, fmt.Sprintf("%s %s on %s", f.Severity, f.RuleID, subject))
+	}
+	return fmt.Sprintf("verify: %d finding(s) at or above %s (%s)", len(e.findings), e.failOn, strings.Join(parts, "; "))
+}
+
+func verifyRulesConfigured(v VerifyOptions) bool {
+	if v.Enabled != nil && !*v.Enabled {
+		return false
+	}
+	return strings.TrimSpace(v.Config) != "" || v.Rules != nil
+}
+
+// resolveVerifyRules loads v.Config, overlays v.Rules, and returns the verify options to
+// evaluate a release with.
+func resolveVerifyRules(v VerifyOptions) (verify.Options, error) {
+	var rules verifyconfig.Rules
+	if path := strings.TrimSpace(v.Config); path != "" {
+		cfg, _, err := verifyconfig.Load(path)
+		if err != nil {
+			return verify.Options{}, fmt.Errorf("verify: load config %s: %w", path, err)
+		}
+		rules = cfg.Verify
+	}
+	if r := v.Rules; r != nil {
+		if strings.TrimSpace(r.FailOn) != "" {
+			rules.FailOn = r.FailOn
+		}
+		if strings.TrimSpace(r.RulesDir) != "" {
+			rules.RulesDir = r.RulesDir
+		}
+		rules.RulesPath = append(rules.RulesPath, r.RulesPath...)
+		if r.Selectors != nil {
+			rules.Selectors = *r.Selectors
+		}
+		if len(r.RuleSelectors) > 0 {
+			rules.RuleSelectors = r.RuleSelectors
+		}
+	}
+	failOn := verify.Severity(strings.ToLower(strings.TrimSpace(rules.FailOn)))
+	switch failOn {
+	case "":
+		failOn = verify.SeverityHigh
+	case verify.SeverityInfo, verify.SeverityLow, verify.SeverityMedium, verify.SeverityHigh, verify.SeverityCritical:
+	default:
+		return verify.Options{}, fmt.Errorf("verify: failOn must be info, low, medium, high or critical (got %q)", rules.FailOn)
+	}
+	rulesDir := strings.TrimSpace(rules.RulesDir)
+	if rulesDir == "" {
+		rulesDir = filepath.Join(appconfig.FindRepoRoot("."), "internal", "verify", "rules", "builtin")
+	}
+	return verify.Options{
+		Mode:          verify.ModeBlock,
+		FailOn:        failOn,
+		RulesDir:      rulesDir,
+		ExtraRules:    rules.RulesPath,
+		Selectors:     rules.Selectors,
+		RuleSelectors: rules.RuleSelectors,
+	}, nil
+}
+
+// verifyReleaseRules evaluates the verify rules against the release's live objects (falling
+// back to the applied manifest for objects it cannot read) and fails on findings at or above
+// failOn.
+func verifyReleaseRules(ctx context.Context, kubeClient *kube.Client, defaultNamespace string, manifest string, v VerifyOptions) (string, error) {
+	opts, err := resolveVerifyRules(v)
+	if err != nil {
+		return "", err
+	}
+	objects, err := verify.DecodeK8SYAML(manifest)
+	if err != nil {
+		return "", fmt.Errorf("verify: decode manifest: %w", err)
+	}
+	for i, obj := range objects {
+		if live := liveObject(ctx, kubeClient, defaultNamespace, obj); live != nil {
+			objects[i] = live
+		}
+	}
+	rep, err := verify.VerifyObjects(ctx, objects, opts)
+	if err != nil {
+		return "", fmt.Errorf("verify: evaluate rules: %w", err)
+	}
+	var blocking []verify.Finding
+	for _, f := range rep.Findings {
+		if f.Severity.AtLeast(opts.FailOn) {
+			blocking = append(blocking, f)
+		}
+	}
+	if len(blocking) > 0 {
+		return "", &verifyRulesFailure{failOn: opts.FailOn, findings: blocking}
+	}
+	return fmt.Sprintf("%d object(s) passed verify rules (%d finding(s) below %s)", len(objects), len(rep.Findings), opts.FailOn), nil
+}
+
+func liveObject(ctx context.Context, kubeClient *kube.Client, defaultNamespace string, obj map[string]any) map[string]any {
+	if kubeClient == nil || kubeClient.Dynamic == nil || kubeClient.RESTMapper == nil {
+		return nil
+	}
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	md, _ := obj["metadata"].(map[string]any)
+	name, _ := md["name"].(string)
+	if kind == "" || name == "" {
+		return nil
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil
+	}
+	mapping, err := kubeClient.RESTMapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: kind}, gv.Version)
+	if err != nil {
+		return nil
+	}
+	res := kubeClient.Dynamic.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		ns, _ := md["namespace"].(string)
+		if ns == "" {
+			ns = defaultNamespace
+		}
+		live, err := res.Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		return live.Object
+	}
+	live, err := res.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return live.Object
+}
@@ -0,0 +1,64 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubekattle/ktl/internal/verify"
+)
+
+func TestResolveVerifyRules_InlineOverridesConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "verify.yaml")
+	writeFile(t, cfgPath, `
+target: { kind: namespace, namespace: ns }
+verify:
+  failOn: low
+  rulesPath: [extra]
+`)
+	opts, err := resolveVerifyRules(VerifyOptions{Config: cfgPath, Rules: &VerifyRulesOptions{FailOn: "critical"}})
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if opts.FailOn != verify.SeverityCritical || opts.Mode != verify.ModeBlock {
+		t.Fatalf("failOn=%q mode=%q", opts.FailOn, opts.Mode)
+	}
+	if len(opts.ExtraRules) != 1 || opts.ExtraRules[0] != filepath.Join(dir, "extra") {
+		t.Fatalf("extra rules = %v", opts.ExtraRules)
+	}
+
+	if opts, err := resolveVerifyRules(VerifyOptions{Rules: &VerifyRulesOptions{}}); err != nil || opts.FailOn != verify.SeverityHigh {
+		t.Fatalf("default failOn=%q err=%v", opts.FailOn, err)
+	}
+	if _, err := resolveVerifyRules(VerifyOptions{Rules: &VerifyRulesOptions{FailOn: "severe"}}); err == nil {
+		t.Fatalf("expected an invalid failOn to be rejected")
+	}
+}
+
+func TestVerifyReleaseRules_FindingsFailWithoutRetry(t *testing.T) {
+	manifest := `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: priv
+  namespace: ns
+spec:
+  containers:
+    - name: app
+      image: nginx:1.25
+      securityContext:
+        privileged: true
+`
+	rules := &VerifyRulesOptions{RulesDir: filepath.Join("..", "verify", "rules", "builtin")}
+	_, err := verifyReleaseRules(context.Background(), nil, "ns", manifest, VerifyOptions{Rules: rules})
+	var rulesErr *verifyRulesFailure
+	if !errors.As(err, &rulesErr) {
+		t.Fatalf("expected verify findings to fail the release, got %v", err)
+	}
+	wrapped := wrapNodeErr(&ResolvedRelease{ID: "c/ns/priv"}, err)
+	if class := classifyError(wrapped); isRetryableClass(class) {
+		t.Fatalf("verify findings must not be retried (class %s)", class)
+	}
+}
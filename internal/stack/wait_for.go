@@ -0,0 +1,96 @@
+// File: internal/stack/wait_for.go
+// Brief: waitFor readiness gates (CRDs, admission webhooks) checked before a release is applied.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+)
+
+const defaultWaitForTimeout = 5 * time.Minute
+
+// WaitForDep is one parsed `waitFor` entry.
+type WaitForDep struct {
+	// Kind is "crd" or "webhook".
+	Kind string
+	Name string
+}
+
+func (d WaitForDep) String() string { return d.Kind + "/" + d.Name }
+
+// ParseWaitFor parses crd/<name> (e.g. crd/certificates.cert-manager.io) or webhook/<name>
+// (a Validating/MutatingWebhookConfiguration name).
+func ParseWaitFor(raw string) (WaitForDep, error) {
+	kind, name, ok := strings.Cut(strings.TrimSpace(raw), "/")
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return WaitForDep{}, fmt.Errorf("invalid waitFor %q (expected crd/<name> or webhook/<name>)", raw)
+	}
+	switch kind {
+	case "crd", "customresourcedefinition":
+		return WaitForDep{Kind: "crd", Name: name}, nil
+	case "webhook":
+		return WaitForDep{Kind: "webhook", Name: name}, nil
+	default:
+		return WaitForDep{}, fmt.Errorf("invalid waitFor %q: unknown kind %q (expected crd or webhook)", raw, kind)
+	}
+}
+
+type budgetYieldKey struct{}
+
+// withBudgetYield attaches the worker's budget hand-off to ctx. yield releases the node's
+// concurrency budget slots, runs wait, and takes the slots back before returning.
+func withBudgetYield(ctx context.Context, yield func(wait func() error) error) context.Context {
+	return context.WithValue(ctx, budgetYieldKey{}, yield)
+}
+
+// waitForNodeDependencies polls every waitFor gate of node until it is ready. Gates are checked at
+// run time rather than as DAG edges, so they order releases across execution groups and against
+// resources installed outside the stack. The node's budget slots are handed back while it polls,
+// so a gate that takes minutes does not stall unrelated releases.
+func waitForNodeDependencies(ctx context.Context, run *runState, client *kube.Client, node *runNode) error {
+	if len(node.WaitFor) == 0 {
+		return nil
+	}
+	wait := func() error { return waitForGates(ctx, run, client, node) }
+	if yield, ok := ctx.Value(budgetYieldKey{}).(func(func() error) error); ok && yield != nil {
+		return yield(wait)
+	}
+	return wait()
+}
+
+func waitForGates(ctx context.Context, run *runState, client *kube.Client, node *runNode) error {
+	timeout := defaultWaitForTimeout
+	if node.WaitForTimeout != nil && *node.WaitForTimeout > 0 {
+		timeout = *node.WaitForTimeout
+	}
+	for _, raw := range node.WaitFor {
+		dep, err := ParseWaitFor(raw)
+		if err != nil {
+			return err
+		}
+		if run != nil {
+			run.EmitEphemeralEvent(node.ID, NodeLog, node.Attempt, fmt.Sprintf("waiting for %s (timeout %s)", dep, timeout), map[string]any{"kind": "wait-for", "target": dep.String()})
+		}
+		started := time.Now()
+		switch dep.Kind {
+		case "crd":
+			err = kube.WaitForCRDEstablished(ctx, client.Dynamic, dep.Name, timeout)
+		case "webhook":
+			err = kube.WaitForWebhookReady(ctx, client.Clientset, dep.Name, timeout)
+		}
+		if err != nil {
+			return fmt.Errorf("waitFor %s: %w", dep, err)
+		}
+		if run != nil {
+			run.EmitEphemeralEvent(node.ID, NodeLog, node.Attempt, fmt.Sprintf("%s ready after %s", dep, time.Since(started).Round(time.Millisecond)), map[string]any{"kind": "wait-for", "target": dep.String()})
+		}
+	}
+	return nil
+}
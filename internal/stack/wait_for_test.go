@@ -0,0 +1,192 @@
+package stack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/kube"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseWaitFor(t *testing.T) {
+	dep, err := ParseWaitFor(" crd/certificates.cert-manager.io ")
+	if err != nil || dep.Kind != "crd" || dep.Name != "certificates.cert-manager.io" {
+		t.Fatalf("unexpected crd dep %+v err=%v", dep, err)
+	}
+	dep, err = ParseWaitFor("webhook/cert-manager-webhook")
+	if err != nil || dep.String() != "webhook/cert-manager-webhook" {
+		t.Fatalf("unexpected webhook dep %+v err=%v", dep, err)
+	}
+	for _, bad := range []string{"", "crd/", "certificates.cert-manager.io", "deployment/web"} {
+		if _, err := ParseWaitFor(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestCompile_WaitForIsMergedAndValidated(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+defaults:
+  cluster: { name: c1 }
+releases:
+  - name: issuers
+    chart: ./issuers
+    waitFor: [crd/certificates.cert-manager.io, webhook/cert-manager-webhook]
+    waitForTimeout: 90s
+`)
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	p, err := Compile(u, CompileOptions{})
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	n := p.Nodes[0]
+	if strings.Join(n.WaitFor, ",") != "crd/certificates.cert-manager.io,webhook/cert-manager-webhook" {
+		t.Fatalf("waitFor=%v", n.WaitFor)
+	}
+	if n.WaitForTimeout == nil || *n.WaitForTimeout != 90*time.Second {
+		t.Fatalf("waitForTimeout=%v", n.WaitForTimeout)
+	}
+
+	writeFile(t, filepath.Join(root, "stack.yaml"), `
+apiVersion: ktl.dev/v1
+kind: Stack
+name: demo
+defaults:
+  cluster: { name: c1 }
+releases:
+  - name: issuers
+    chart: ./issuers
+    waitFor: [pod/foo]
+`)
+	u, err = Discover(root)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	if _, err := Compile(u, CompileOptions{}); err == nil || !strings.Contains(err.Error(), "waitFor") {
+		t.Fatalf("expected waitFor validation error, got %v", err)
+	}
+}
+
+func TestWaitForNodeDependenciesReady(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "certificates.cert-manager.io"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Established", "status": "True"}},
+		},
+	}}
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}: "CustomResourceDefinitionList",
+	}, crd)
+	ready := true
+	clientset := fake.NewSimpleClientset(
+		&admissionv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-webhook"},
+			Webhooks: []admissionv1.ValidatingWebhook{{
+				Name:         "webhook.cert-manager.io",
+				ClientConfig: admissionv1.WebhookClientConfig{Service: &admissionv1.ServiceReference{Namespace: "cert-manager", Name: "cert-manager-webhook"}},
+			}},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cert-manager", Name: "cert-manager-webhook-abc", Labels: map[string]string{discoveryv1.LabelServiceName: "cert-manager-webhook"}},
+			Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+		},
+	)
+	timeout := time.Second
+	node := &runNode{ResolvedRelease: &ResolvedRelease{
+		ID:             "c/ns/issuers",
+		WaitFor:        []string{"crd/certificates.cert-manager.io", "webhook/cert-manager-webhook"},
+		WaitForTimeout: &timeout,
+	}}
+	client := &kube.Client{Dynamic: dyn, Clientset: clientset}
+	if err := waitForNodeDependencies(context.Background(), nil, client, node); err != nil {
+		t.Fatalf("expected gates to be ready: %v", err)
+	}
+
+	node.WaitFor = []string{"crd/missing.example.com"}
+	if err := waitForNodeDependencies(context.Background(), nil, client, node); err == nil || !strings.Contains(err.Error(), "crd/missing.example.com") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+// gateExecutor blocks "gated" in a waitFor-style wait until "other" has run.
+type gateExecutor struct{ otherRan chan struct{} }
+
+func (e *gateExecutor) RunNode(ctx context.Context, node *runNode, command string) error {
+	if node.Name != "gated" {
+		close(e.otherRan)
+		return nil
+	}
+	wait := func() error {
+		select {
+		case <-e.otherRan:
+			return nil
+		case <-time.After(5 * time.Second):
+			return errors.New("other release never ran while gated was waiting")
+		}
+	}
+	if yield, ok := ctx.Value(budgetYieldKey{}).(func(func() error) error); ok {
+		return yield(wait)
+	}
+	return wait()
+}
+
+func TestRunReleasesBudgetWhileWaitingForGates(t *testing.T) {
+	root := t.TempDir()
+	writeMinimalStackFixture(t, root, "gates")
+	stackYAML := `apiVersion: ktl.dev/v1
+kind: Stack
+name: gates
+defaults:
+  cluster: { name: c1 }
+  namespace: ns
+releases:
+  - name: gated
+    chart: ./charts/cm
+  - name: other
+    chart: ./charts/cm
+`
+	if err := os.WriteFile(filepath.Join(root, "stack.yaml"), []byte(stackYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	u, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Compile(u, CompileOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out, errOut bytes.Buffer
+	err = Run(context.Background(), RunOptions{
+		Command:                    "apply",
+		Plan:                       p,
+		Concurrency:                2,
+		MaxConcurrencyPerNamespace: 1,
+		Executor:                   &gateExecutor{otherRan: make(chan struct{})},
+	}, &out, &errOut)
+	if err != nil {
+		t.Fatalf("expected the waiting release to hand its namespace slot to the other one: %v", err)
+	}
+}
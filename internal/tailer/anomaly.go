@@ -0,0 +1,175 @@
+// File: internal/tailer/anomaly.go
+// Brief: Internal tailer package implementation for 'anomaly'.
+
+package tailer
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/fatih/color"
+)
+
+// AnomalyKind labels why a log line was flagged by the AnomalyDetector.
+type AnomalyKind string
+
+const (
+	AnomalyNone       AnomalyKind = ""
+	AnomalyNovel      AnomalyKind = "novel"
+	AnomalyRare       AnomalyKind = "rare"
+	AnomalyErrorSpike AnomalyKind = "error-spike"
+)
+
+const (
+	anomalyMaxVocabulary   = 50000
+	anomalyNovelRatio      = 0.3
+	anomalyRareRatio       = 0.5
+	anomalyRareFrequency   = 0.01
+	anomalySpikeSeconds    = 10
+	anomalyBaselineSeconds = 60
+	anomalySpikeFactor     = 3.0
+	anomalySpikeMinErrors  = 5
+)
+
+var anomalyErrorPattern = regexp.MustCompile(`(?i)\b(error|fatal|panic|exception|failed|failure)\b|level=(error|fatal)|"level":\s*"(error|fatal)"`)
+
+// AnomalyDetector builds a token-frequency baseline from the lines it sees and flags
+// lines that are novel or rare relative to that baseline, plus error lines that arrive
+// during a spike in the error rate. It is safe for concurrent use.
+type AnomalyDetector struct {
+	mu         sync.Mutex
+	warmup     int
+	lines      int
+	vocabulary map[string]int
+	// errorBuckets counts error lines per wall-clock second in a ring covering
+	// the spike window plus the baseline window before it.
+	errorBuckets [anomalySpikeSeconds + anomalyBaselineSeconds]int
+	lastSecond   int64
+}
+
+// NewAnomalyDetector returns a detector that stays silent for the first warmup lines.
+func NewAnomalyDetector(warmup int) *AnomalyDetector {
+	if warmup < 0 {
+		warmup = 0
+	}
+	return &AnomalyDetector{warmup: warmup, vocabulary: make(map[string]int)}
+}
+
+// Observe classifies line against the baseline collected so far and then folds it into the baseline.
+func (d *AnomalyDetector) Observe(line string, now time.Time) AnomalyKind {
+	if d == nil {
+		return AnomalyNone
+	}
+	tokens := anomalyTokens(line)
+	isError := anomalyErrorPattern.MatchString(line)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	kind := AnomalyNone
+	if isError && d.recordError(now) {
+		kind = AnomalyErrorSpike
+	}
+	if kind == AnomalyNone && d.lines >= d.warmup && len(tokens) > 0 {
+		novel, rare := 0, 0
+		for _, tok := range tokens {
+			count := d.vocabulary[tok]
+			if count == 0 {
+				novel++
+			}
+			if float64(count)/float64(d.lines+1) < anomalyRareFrequency {
+				rare++
+			}
+		}
+		switch {
+		case float64(novel)/float64(len(tokens)) >= anomalyNovelRatio:
+			kind = AnomalyNovel
+		case float64(rare)/float64(len(tokens)) >= anomalyRareRatio:
+			kind = AnomalyRare
+		}
+	}
+
+	d.lines++
+	for _, tok := range tokens {
+		if _, ok := d.vocabulary[tok]; !ok && len(d.vocabulary) >= anomalyMaxVocabulary {
+			continue
+		}
+		d.vocabulary[tok]++
+	}
+	return kind
+}
+
+// recordError notes an error line and reports whether the recent error rate is a spike
+// compared to the preceding baseline window.
+func (d *AnomalyDetector) recordError(now time.Time) bool {
+	size := int64(len(d.errorBuckets))
+	sec := now.Unix()
+	if d.lastSecond == 0 || sec-d.lastSecond >= size {
+		d.errorBuckets = [len(d.errorBuckets)]int{}
+	} else {
+		for s := d.lastSecond + 1; s <= sec; s++ {
+			d.errorBuckets[s%size] = 0
+		}
+	}
+	if sec > d.lastSecond {
+		d.lastSecond = sec
+	}
+	d.errorBuckets[d.lastSecond%size]++
+
+	recent, baseline := 0, 0
+	for i := int64(0); i < size; i++ {
+		count := d.errorBuckets[(d.lastSecond-i+size)%size]
+		if i < anomalySpikeSeconds {
+			recent += count
+		} else {
+			baseline += count
+		}
+	}
+	if recent < anomalySpikeMinErrors {
+		return false
+	}
+	expected := float64(baseline) * anomalySpikeSeconds / anomalyBaselineSeconds
+	return float64(recent) >= anomalySpikeFactor*expected
+}
+
+// anomalyTokens extracts the words of a line, skipping tokens that carry digits
+// (timestamps, IDs, counters) so they don't make every line look novel.
+func anomalyTokens(line string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(line), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	tokens := fields[:0]
+	seen := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		if len(f) < 3 || strings.IndexFunc(f, unicode.IsDigit) >= 0 {
+			continue
+		}
+		if _, dup := seen[f]; dup {
+			continue
+		}
+		seen[f] = struct{}{}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
+
+var anomalyColors = map[AnomalyKind]*color.Color{
+	AnomalyNovel:      color.New(color.FgMagenta, color.Bold),
+	AnomalyRare:       color.New(color.FgCyan, color.Bold),
+	AnomalyErrorSpike: color.New(color.FgRed, color.Bold),
+}
+
+// AnomalyMarker renders the prefix printed before a flagged line.
+func AnomalyMarker(kind AnomalyKind, colorize bool) string {
+	if kind == AnomalyNone {
+		return ""
+	}
+	tag := "[" + string(kind) + "]"
+	if c := anomalyColors[kind]; colorize && c != nil {
+		tag = c.Sprint(tag)
+	}
+	return tag + " "
+}
@@ -0,0 +1,72 @@
+// File: internal/tailer/anomaly_test.go
+// Brief: Internal tailer package implementation for 'anomaly'.
+
+// anomaly_test.go covers baseline-driven anomaly flagging.
+package tailer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAnomalyDetectorFlagsNovelLinesAfterWarmup(t *testing.T) {
+	d := NewAnomalyDetector(50)
+	now := time.Unix(1700000000, 0)
+	for i := 0; i < 100; i++ {
+		line := fmt.Sprintf("GET /api/orders/%d served in %dms status=200", i, i%40)
+		if kind := d.Observe(line, now); kind != AnomalyNone {
+			t.Fatalf("line %d unexpectedly flagged as %q", i, kind)
+		}
+	}
+	if kind := d.Observe("GET /api/orders/101 served in 12ms status=200", now); kind != AnomalyNone {
+		t.Fatalf("routine line flagged as %q", kind)
+	}
+	if kind := d.Observe("connection pool exhausted; dropping upstream socket", now); kind != AnomalyNovel {
+		t.Fatalf("expected novel line, got %q", kind)
+	}
+}
+
+func TestAnomalyDetectorSilentDuringWarmup(t *testing.T) {
+	d := NewAnomalyDetector(10)
+	if kind := d.Observe("completely unseen words here", time.Now()); kind != AnomalyNone {
+		t.Fatalf("expected no flag during warmup, got %q", kind)
+	}
+}
+
+func TestAnomalyDetectorFlagsErrorSpikes(t *testing.T) {
+	d := NewAnomalyDetector(0)
+	base := time.Unix(1700000000, 0)
+	// One error every 10s establishes a low baseline.
+	for i := 0; i < 6; i++ {
+		if kind := d.Observe("request failed: timeout", base.Add(time.Duration(i)*10*time.Second)); kind == AnomalyErrorSpike {
+			t.Fatalf("baseline error %d flagged as spike", i)
+		}
+	}
+	burst := base.Add(65 * time.Second)
+	var flagged bool
+	for i := 0; i < 8; i++ {
+		if d.Observe("request failed: timeout", burst.Add(time.Duration(i)*100*time.Millisecond)) == AnomalyErrorSpike {
+			flagged = true
+		}
+	}
+	if !flagged {
+		t.Fatalf("expected burst of errors to be flagged as a spike")
+	}
+}
+
+func TestAnomalyMarker(t *testing.T) {
+	if got := AnomalyMarker(AnomalyNone, false); got != "" {
+		t.Fatalf("expected empty marker, got %q", got)
+	}
+	if got := AnomalyMarker(AnomalyRare, false); got != "[rare] " {
+		t.Fatalf("unexpected marker %q", got)
+	}
+}
+
+func TestNilAnomalyDetector(t *testing.T) {
+	var d *AnomalyDetector
+	if kind := d.Observe("anything", time.Now()); kind != AnomalyNone {
+		t.Fatalf("nil detector returned %q", kind)
+	}
+}
@@ -0,0 +1,146 @@
+// File: internal/tailer/encode.go
+// Brief: Internal tailer package implementation for 'encode'.
+
+package tailer
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encoding names accepted by --encode.
+const (
+	EncodeLogfmt = "logfmt"
+	EncodeJSONL  = "jsonl"
+	EncodeOTLP   = "otlp"
+)
+
+// Resource attribute keys follow the OpenTelemetry Kubernetes semantic conventions.
+const (
+	attrNamespace = "k8s.namespace.name"
+	attrPod       = "k8s.pod.name"
+	attrContainer = "k8s.container.name"
+	attrNode      = "k8s.node.name"
+	attrLogFile   = "log.file.name"
+	attrSource    = "ktl.source"
+)
+
+var (
+	severityError = regexp.MustCompile(`(?i)\b(error|err|fatal|panic|critical|crit)\b|level=(error|fatal)|"level":\s*"(error|fatal)"`)
+	severityWarn  = regexp.MustCompile(`(?i)\b(warn|warning)\b|level=warn|"level":\s*"warn`)
+	severityDebug = regexp.MustCompile(`(?i)\b(debug|trace)\b|level=(debug|trace)|"level":\s*"(debug|trace)"`)
+)
+
+// inferSeverity guesses a log level from common textual and structured markers.
+func inferSeverity(line string) string {
+	switch {
+	case severityError.MatchString(line):
+		return "ERROR"
+	case severityWarn.MatchString(line):
+		return "WARN"
+	case severityDebug.MatchString(line):
+		return "DEBUG"
+	default:
+		return "INFO"
+	}
+}
+
+// resourceAttributes returns the OTel-style resource attributes describing where rec came from.
+func resourceAttributes(rec LogRecord) map[string]string {
+	attrs := map[string]string{attrSource: rec.Source}
+	if rec.Source == string(sourceNode) {
+		// Node log records carry the node name in Pod and the log file in Container.
+		if rec.Pod != "" {
+			attrs[attrNode] = rec.Pod
+		}
+		if rec.Container != "" {
+			attrs[attrLogFile] = rec.Container
+		}
+		return attrs
+	}
+	if rec.Namespace != "" {
+		attrs[attrNamespace] = rec.Namespace
+	}
+	if rec.Pod != "" {
+		attrs[attrPod] = rec.Pod
+	}
+	if rec.Container != "" {
+		attrs[attrContainer] = rec.Container
+	}
+	return attrs
+}
+
+// encodeLogfmt renders rec as a single logfmt line.
+func encodeLogfmt(rec LogRecord) string {
+	var b strings.Builder
+	writePair := func(key, value string) {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(value))
+	}
+	writePair("ts", rec.Timestamp.UTC().Format(time.RFC3339Nano))
+	writePair("level", strings.ToLower(inferSeverity(rec.Raw)))
+	writePair("source", rec.Source)
+	if rec.Namespace != "" {
+		writePair("namespace", rec.Namespace)
+	}
+	if rec.Pod != "" {
+		writePair("pod", rec.Pod)
+	}
+	if rec.Container != "" {
+		writePair("container", rec.Container)
+	}
+	if rec.Anomaly != "" {
+		writePair("anomaly", rec.Anomaly)
+	}
+	writePair("msg", rec.Raw)
+	return b.String()
+}
+
+func logfmtValue(v string) string {
+	if v == "" {
+		return `""`
+	}
+	if strings.ContainsAny(v, " =\"\t\r\n\\") || !isPrintableASCII(v) {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+type jsonlRecord struct {
+	Timestamp string            `json:"timestamp"`
+	Severity  string            `json:"severity"`
+	Body      string            `json:"body"`
+	Anomaly   string            `json:"anomaly,omitempty"`
+	Resource  map[string]string `json:"resource"`
+}
+
+// encodeJSONL renders rec as one JSON object carrying OTel-style resource attributes.
+func encodeJSONL(rec LogRecord) (string, error) {
+	data, err := json.Marshal(jsonlRecord{
+		Timestamp: rec.Timestamp.UTC().Format(time.RFC3339Nano),
+		Severity:  inferSeverity(rec.Raw),
+		Body:      rec.Raw,
+		Anomaly:   rec.Anomaly,
+		Resource:  resourceAttributes(rec),
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,149 @@
+// File: internal/tailer/encode_test.go
+// Brief: Internal tailer package implementation for 'encode'.
+
+// encode_test.go covers the logfmt/jsonl encoders and the OTLP exporter.
+package tailer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func testRecord(raw string) LogRecord {
+	return LogRecord{
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Namespace: "prod",
+		Pod:       "checkout-0",
+		Container: "app",
+		Raw:       raw,
+		Source:    "pod",
+	}
+}
+
+func TestEncodeLogfmt(t *testing.T) {
+	got := encodeLogfmt(testRecord(`ERROR payment "declined" for order=7`))
+	want := `ts=2024-05-01T12:00:00Z level=error source=pod namespace=prod pod=checkout-0 container=app msg="ERROR payment \"declined\" for order=7"`
+	if got != want {
+		t.Fatalf("logfmt mismatch:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestEncodeJSONLIncludesResourceAttributes(t *testing.T) {
+	line, err := encodeJSONL(testRecord("warning: slow query"))
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var decoded jsonlRecord
+	if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.Severity != "WARN" || decoded.Body != "warning: slow query" {
+		t.Fatalf("unexpected record: %+v", decoded)
+	}
+	if decoded.Resource[attrNamespace] != "prod" || decoded.Resource[attrPod] != "checkout-0" || decoded.Resource[attrContainer] != "app" {
+		t.Fatalf("unexpected resource attributes: %v", decoded.Resource)
+	}
+}
+
+func TestOTLPExporterBatchesAndRetries(t *testing.T) {
+	var calls atomic.Int32
+	var mu sync.Mutex
+	var payloads []otlpLogsRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" || r.Header.Get("X-Token") != "abc" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var req otlpLogsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		payloads = append(payloads, req)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	exporter, err := NewOTLPExporter(OTLPExporterOptions{
+		Endpoint:           srv.URL + "/",
+		Headers:            map[string]string{"X-Token": "abc"},
+		ResourceAttributes: map[string]string{"k8s.cluster.name": "kind"},
+		Logger:             logr.Discard(),
+	})
+	if err != nil {
+		t.Fatalf("new exporter: %v", err)
+	}
+	exporter.ObserveLog(testRecord("first"))
+	second := testRecord("panic: boom")
+	second.Pod = "checkout-1"
+	exporter.ObserveLog(second)
+	if err := exporter.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(payloads) != 1 || calls.Load() != 2 {
+		t.Fatalf("expected one retried export, got %d payloads after %d calls", len(payloads), calls.Load())
+	}
+	resources := payloads[0].ResourceLogs
+	if len(resources) != 2 {
+		t.Fatalf("expected records grouped into 2 resources, got %d", len(resources))
+	}
+	var sawCluster bool
+	for _, kv := range resources[0].Resource.Attributes {
+		if kv.Key == "k8s.cluster.name" && kv.Value.StringValue == "kind" {
+			sawCluster = true
+		}
+	}
+	if !sawCluster {
+		t.Fatalf("expected extra resource attribute: %+v", resources[0].Resource.Attributes)
+	}
+	rec := resources[1].ScopeLogs[0].LogRecords[0]
+	if rec.SeverityText != "ERROR" || rec.Body.StringValue != "panic: boom" {
+		t.Fatalf("unexpected log record: %+v", rec)
+	}
+}
+
+func TestOTLPExporterCloseWhileObserving(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	exporter, err := NewOTLPExporter(OTLPExporterOptions{Endpoint: srv.URL, Logger: logr.Discard()})
+	if err != nil {
+		t.Fatalf("new exporter: %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				exporter.ObserveLog(testRecord("line"))
+			}
+		}()
+	}
+	// Closing while producers are still sending must not panic.
+	_ = exporter.Close(context.Background())
+	wg.Wait()
+	exporter.ObserveLog(testRecord("after close"))
+}
+
+func TestNewOTLPExporterRejectsBadEndpoint(t *testing.T) {
+	if _, err := NewOTLPExporter(OTLPExporterOptions{Endpoint: "collector:4318"}); err == nil || !strings.Contains(err.Error(), "http") {
+		t.Fatalf("expected scheme error, got %v", err)
+	}
+}
@@ -170,13 +170,28 @@ func (m *nodeLogManager) warnRBAC(err error) {
 	})
 }
 
+// nodeLogPath turns a --node-log value into the kubelet /logs/ path. Kubelet serves /var/log on
+// Linux and C:\var\log on Windows nodes, so Windows-style paths (kubelet\kubelet.log or the full
+// C:\var\log\... form) are accepted too.
+func nodeLogPath(file string) string {
+	p := strings.ReplaceAll(strings.TrimSpace(file), `\`, "/")
+	lower := strings.ToLower(p)
+	for _, prefix := range []string{"c:/var/log/", "/var/log/"} {
+		if strings.HasPrefix(lower, prefix) {
+			p = p[len(prefix):]
+			break
+		}
+	}
+	return strings.TrimLeft(p, "/")
+}
+
 func (m *nodeLogManager) streamOnce(ctx context.Context, key nodeLogKey) error {
 	req := m.tailer.client.CoreV1().RESTClient().
 		Get().
 		Resource("nodes").
 		Name(key.node).
 		SubResource("proxy").
-		Suffix("logs", key.file)
+		Suffix("logs", nodeLogPath(key.file))
 	if m.tailer.opts.Follow {
 		req.Param("follow", "1")
 	}
@@ -215,7 +230,7 @@ func (m *nodeLogManager) streamOnce(ctx context.Context, key nodeLogKey) error {
 			return ctx.Err()
 		default:
 		}
-		line := scanner.Text()
+		line := trimLineEnding(scanner.Text())
 		if m.tailer.opts.ExcludeLineRegex != nil && m.tailer.opts.ExcludeLineRegex.MatchString(line) {
 			continue
 		}
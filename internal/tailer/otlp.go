@@ -0,0 +1,339 @@
+// File: internal/tailer/otlp.go
+// Brief: Internal tailer package implementation for 'otlp'.
+
+package tailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	otlpQueueSize     = 8192
+	otlpBatchSize     = 512
+	otlpFlushInterval = time.Second
+	otlpMaxAttempts   = 5
+	otlpRetryBase     = 250 * time.Millisecond
+	otlpRetryMax      = 5 * time.Second
+	otlpLogsPath      = "/v1/logs"
+)
+
+// OTLPExporterOptions configures the OTLP/HTTP log exporter.
+type OTLPExporterOptions struct {
+	// Endpoint is the collector base URL (e.g. http://localhost:4318) or the full /v1/logs URL.
+	Endpoint string
+	// Headers are added to every export request (e.g. authentication).
+	Headers map[string]string
+	// ResourceAttributes are attached to every exported resource (e.g. k8s.cluster.name).
+	ResourceAttributes map[string]string
+	Client             *http.Client
+	Logger             logr.Logger
+}
+
+// OTLPExporter batches tailed log lines and ships them to an OTLP/HTTP (JSON) collector,
+// retrying transient failures with exponential backoff. It implements LogObserver.
+type OTLPExporter struct {
+	url      string
+	headers  map[string]string
+	resource map[string]string
+	client   *http.Client
+	log      logr.Logger
+	// mu guards closed; ObserveLog holds it for reading while it enqueues so Close
+	// never signals the worker while a send is in flight. queue is never closed.
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan LogRecord
+	stop    chan struct{}
+	done    chan struct{}
+	dropped atomic.Int64
+	failed  atomic.Int64
+	sent    atomic.Int64
+}
+
+// NewOTLPExporter starts an exporter; call Close to flush pending records.
+func NewOTLPExporter(opts OTLPExporterOptions) (*OTLPExporter, error) {
+	endpoint := strings.TrimRight(strings.TrimSpace(opts.Endpoint), "/")
+	if endpoint == "" {
+		return nil, fmt.Errorf("otlp endpoint is required")
+	}
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, fmt.Errorf("otlp endpoint %q must start with http:// or https://", opts.Endpoint)
+	}
+	if !strings.HasSuffix(endpoint, otlpLogsPath) {
+		endpoint += otlpLogsPath
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	e := &OTLPExporter{
+		url:      endpoint,
+		headers:  opts.Headers,
+		resource: opts.ResourceAttributes,
+		client:   client,
+		log:      opts.Logger,
+		queue:    make(chan LogRecord, otlpQueueSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go e.run()
+	return e, nil
+}
+
+// ObserveLog enqueues rec for export, dropping it when the queue is full so tailing never blocks.
+// Records observed after Close are ignored.
+func (e *OTLPExporter) ObserveLog(rec LogRecord) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed {
+		return
+	}
+	select {
+	case e.queue <- rec:
+	default:
+		if e.dropped.Add(1) == 1 {
+			e.log.Info("otlp export queue full; dropping log records")
+		}
+	}
+}
+
+// Close flushes queued records (bounded by ctx) and stops the exporter.
+func (e *OTLPExporter) Close(ctx context.Context) error {
+	e.mu.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.stop)
+	}
+	e.mu.Unlock()
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+		return fmt.Errorf("otlp flush: %w", ctx.Err())
+	}
+	dropped, failed := e.dropped.Load(), e.failed.Load()
+	if dropped > 0 || failed > 0 {
+		return fmt.Errorf("otlp export: %d records sent, %d dropped (queue full), %d failed", e.sent.Load(), dropped, failed)
+	}
+	return nil
+}
+
+func (e *OTLPExporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+	batch := make([]LogRecord, 0, otlpBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			e.failed.Add(int64(len(batch)))
+			e.log.Error(err, "otlp export failed", "records", len(batch))
+		} else {
+			e.sent.Add(int64(len(batch)))
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case rec := <-e.queue:
+			batch = append(batch, rec)
+			if len(batch) >= otlpBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.stop:
+			// No producer can enqueue once stop is closed, so draining what is buffered is complete.
+			for {
+				select {
+				case rec := <-e.queue:
+					batch = append(batch, rec)
+					if len(batch) >= otlpBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *OTLPExporter) send(batch []LogRecord) error {
+	body, err := json.Marshal(buildOTLPRequest(batch, e.resource))
+	if err != nil {
+		return err
+	}
+	backoff := otlpRetryBase
+	var lastErr error
+	for attempt := 1; attempt <= otlpMaxAttempts; attempt++ {
+		retry, err := e.post(body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || attempt == otlpMaxAttempts {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > otlpRetryMax {
+			backoff = otlpRetryMax
+		}
+	}
+	return lastErr
+}
+
+// post sends one export request and reports whether a failure is worth retrying.
+func (e *OTLPExporter) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("otlp collector returned %s", resp.Status)
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true, err
+	default:
+		return false, err
+	}
+}
+
+// OTLP/HTTP JSON payload types (opentelemetry-proto logs.v1, JSON mapping).
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+var otlpSeverityNumbers = map[string]int{"DEBUG": 5, "INFO": 9, "WARN": 13, "ERROR": 17}
+
+// buildOTLPRequest groups records by their resource attributes.
+func buildOTLPRequest(batch []LogRecord, extra map[string]string) otlpLogsRequest {
+	type group struct {
+		attrs   map[string]string
+		records []otlpLogRecord
+	}
+	groups := map[string]*group{}
+	var order []string
+	for _, rec := range batch {
+		attrs := resourceAttributes(rec)
+		for k, v := range extra {
+			attrs[k] = v
+		}
+		key := attrKey(attrs)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{attrs: attrs}
+			groups[key] = g
+			order = append(order, key)
+		}
+		ts := strconv.FormatInt(rec.Timestamp.UnixNano(), 10)
+		severity := inferSeverity(rec.Raw)
+		lr := otlpLogRecord{
+			TimeUnixNano:         ts,
+			ObservedTimeUnixNano: ts,
+			SeverityNumber:       otlpSeverityNumbers[severity],
+			SeverityText:         severity,
+			Body:                 otlpAnyValue{StringValue: rec.Raw},
+		}
+		if rec.Anomaly != "" {
+			lr.Attributes = append(lr.Attributes, otlpKeyValue{Key: "ktl.anomaly", Value: otlpAnyValue{StringValue: rec.Anomaly}})
+		}
+		g.records = append(g.records, lr)
+	}
+	req := otlpLogsRequest{ResourceLogs: make([]otlpResourceLogs, 0, len(order))}
+	for _, key := range order {
+		g := groups[key]
+		req.ResourceLogs = append(req.ResourceLogs, otlpResourceLogs{
+			Resource:  otlpResource{Attributes: sortedKeyValues(g.attrs)},
+			ScopeLogs: []otlpScopeLogs{{Scope: otlpScope{Name: "ktl"}, LogRecords: g.records}},
+		})
+	}
+	return req
+}
+
+func sortedKeyValues(attrs map[string]string) []otlpKeyValue {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, otlpKeyValue{Key: k, Value: otlpAnyValue{StringValue: attrs[k]}})
+	}
+	return out
+}
+
+func attrKey(attrs map[string]string) string {
+	var b strings.Builder
+	for _, kv := range sortedKeyValues(attrs) {
+		b.WriteString(kv.Key)
+		b.WriteByte('=')
+		b.WriteString(kv.Value.StringValue)
+		b.WriteByte(0)
+	}
+	return b.String()
+}
@@ -0,0 +1,206 @@
+// File: internal/tailer/summary.go
+// Brief: Internal tailer package implementation for 'summary'.
+
+package tailer
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	summaryMaxClusters   = 5000
+	summaryMessageMaxLen = 120
+)
+
+var summaryVariablePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`),
+	regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`),
+	regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`),
+	regexp.MustCompile(`\b(0x)?[0-9a-fA-F]*\d[0-9a-fA-F]*\b`),
+	regexp.MustCompile(`"[^"]{24,}"`),
+	regexp.MustCompile(`\d+(\.\d+)?`),
+}
+
+// SessionSummary aggregates what a tail session saw: lines and error/warn counts per
+// container, clustered repeated messages, and container restarts. It implements
+// LogObserver and SelectionObserver and is safe for concurrent use.
+type SessionSummary struct {
+	mu       sync.Mutex
+	started  time.Time
+	lines    int
+	targets  map[containerKey]*summaryTarget
+	clusters map[string]*summaryCluster
+	now      func() time.Time
+}
+
+type summaryTarget struct {
+	lines        int
+	errors       int
+	warnings     int
+	firstRestart int32
+	lastRestart  int32
+	seenRestart  bool
+}
+
+type summaryCluster struct {
+	count    int
+	severity string
+	example  string
+}
+
+// NewSessionSummary starts an empty summary.
+func NewSessionSummary() *SessionSummary {
+	return &SessionSummary{
+		started:  time.Now(),
+		targets:  make(map[containerKey]*summaryTarget),
+		clusters: make(map[string]*summaryCluster),
+		now:      time.Now,
+	}
+}
+
+// ObserveLog counts the line and folds it into its message cluster.
+func (s *SessionSummary) ObserveLog(rec LogRecord) {
+	if s == nil {
+		return
+	}
+	severity := inferSeverity(rec.Raw)
+	template := summaryTemplate(rec.Raw)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lines++
+	target := s.targetLocked(containerKey{Namespace: rec.Namespace, Pod: rec.Pod, Container: rec.Container})
+	target.lines++
+	switch severity {
+	case "ERROR":
+		target.errors++
+	case "WARN":
+		target.warnings++
+	}
+	if template == "" {
+		return
+	}
+	cluster, ok := s.clusters[template]
+	if !ok {
+		if len(s.clusters) >= summaryMaxClusters {
+			return
+		}
+		cluster = &summaryCluster{severity: severity, example: rec.Raw}
+		s.clusters[template] = cluster
+	}
+	cluster.count++
+}
+
+// ObserveSelection records restart counts so restarts during the session can be reported.
+func (s *SessionSummary) ObserveSelection(snap SelectionSnapshot) {
+	if s == nil || snap.ChangeKind != "add" || snap.Pod == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target := s.targetLocked(containerKey{Namespace: snap.Namespace, Pod: snap.Pod, Container: snap.Container})
+	if !target.seenRestart {
+		target.firstRestart = snap.RestartCount
+		target.seenRestart = true
+	}
+	if snap.RestartCount > target.lastRestart {
+		target.lastRestart = snap.RestartCount
+	}
+}
+
+func (s *SessionSummary) targetLocked(key containerKey) *summaryTarget {
+	target, ok := s.targets[key]
+	if !ok {
+		target = &summaryTarget{}
+		s.targets[key] = target
+	}
+	return target
+}
+
+// Write renders the summary tables; top bounds the number of repeated messages shown.
+func (s *SessionSummary) Write(w io.Writer, top int) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elapsed := s.now().Sub(s.started).Round(time.Second)
+	fmt.Fprintf(w, "\nSession summary (%s, %d lines from %d containers)\n", elapsed, s.lines, len(s.targets))
+	if len(s.targets) == 0 {
+		return nil
+	}
+	keys := make([]containerKey, 0, len(s.targets))
+	for k := range s.targets {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		if keys[i].Pod != keys[j].Pod {
+			return keys[i].Pod < keys[j].Pod
+		}
+		return keys[i].Container < keys[j].Container
+	})
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tPOD\tCONTAINER\tLINES\tERRORS\tWARNINGS\tRESTARTS")
+	for _, k := range keys {
+		t := s.targets[k]
+		restarts := t.lastRestart - t.firstRestart
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%d\t%d\n", k.Namespace, k.Pod, k.Container, t.lines, t.errors, t.warnings, restarts)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	clusters := make([]*summaryCluster, 0, len(s.clusters))
+	for _, c := range s.clusters {
+		if c.count > 1 {
+			clusters = append(clusters, c)
+		}
+	}
+	if len(clusters) == 0 || top <= 0 {
+		return nil
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].count != clusters[j].count {
+			return clusters[i].count > clusters[j].count
+		}
+		return clusters[i].example < clusters[j].example
+	})
+	if len(clusters) > top {
+		clusters = clusters[:top]
+	}
+	fmt.Fprintln(w, "\nTop repeated messages")
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "COUNT\tLEVEL\tMESSAGE")
+	for _, c := range clusters {
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", c.count, c.severity, truncateSummary(c.example))
+	}
+	return tw.Flush()
+}
+
+// summaryTemplate normalizes variable parts (IDs, numbers, timestamps, addresses) so
+// repeated messages that differ only by those values cluster together.
+func summaryTemplate(line string) string {
+	out := strings.TrimSpace(line)
+	for _, re := range summaryVariablePatterns {
+		out = re.ReplaceAllString(out, "<*>")
+	}
+	return out
+}
+
+func truncateSummary(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) <= summaryMessageMaxLen {
+		return s
+	}
+	return s[:summaryMessageMaxLen-3] + "..."
+}
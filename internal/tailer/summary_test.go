@@ -0,0 +1,53 @@
+// File: internal/tailer/summary_test.go
+// Brief: Internal tailer package implementation for 'summary'.
+
+// summary_test.go covers end-of-session summary aggregation and clustering.
+package tailer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionSummaryAggregatesAndClusters(t *testing.T) {
+	s := NewSessionSummary()
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.started = start
+	s.now = func() time.Time { return start.Add(90 * time.Second) }
+
+	s.ObserveSelection(SelectionSnapshot{ChangeKind: "add", Namespace: "prod", Pod: "api-0", Container: "app", RestartCount: 2})
+	for i := 0; i < 3; i++ {
+		s.ObserveLog(LogRecord{Namespace: "prod", Pod: "api-0", Container: "app", Raw: "ERROR timeout talking to 10.0.0." + string(rune('1'+i)) + ":5432 after 30s"})
+	}
+	s.ObserveLog(LogRecord{Namespace: "prod", Pod: "api-0", Container: "app", Raw: "warning: cache miss"})
+	s.ObserveLog(LogRecord{Namespace: "prod", Pod: "api-1", Container: "app", Raw: "GET /healthz 200"})
+	s.ObserveSelection(SelectionSnapshot{ChangeKind: "add", Namespace: "prod", Pod: "api-0", Container: "app", RestartCount: 3})
+
+	var out strings.Builder
+	if err := s.Write(&out, 5); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"Session summary (1m30s, 5 lines from 2 containers)",
+		"prod       api-0  app        4      3       1         1",
+		"prod       api-1  app        1      0       0         0",
+		"3      ERROR  ERROR timeout talking to 10.0.0.1:5432 after 30s",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("summary missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "cache miss") {
+		t.Fatalf("single occurrences should not be listed as repeated:\n%s", got)
+	}
+}
+
+func TestSummaryTemplateNormalizesVariables(t *testing.T) {
+	a := summaryTemplate("request 7f3c2a1e-1111-2222-3333-444455556666 took 120ms at 2024-05-01T12:00:00Z")
+	b := summaryTemplate("request 0a0b0c0d-aaaa-bbbb-cccc-ddddeeeeffff took 95ms at 2024-05-01T12:00:07Z")
+	if a != b {
+		t.Fatalf("expected identical templates, got %q vs %q", a, b)
+	}
+}
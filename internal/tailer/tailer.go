@@ -76,6 +76,8 @@ type Tailer struct {
 	nodeLogs           *nodeLogManager
 	defaultTemplate    bool
 	jsonFilter         map[string]string
+	anomalies          *AnomalyDetector
+	otlp               *OTLPExporter
 }
 
 // LogRecord captures a single log line emitted by the tailer along with contextual metadata.
@@ -90,6 +92,21 @@ type LogRecord struct {
 	Source             string
 	SourceGlyph        string
 	RenderedEqualsRaw  bool
+	Anomaly            string
+	// Event is set for Kubernetes event lines so observers can correlate and de-duplicate them
+	// without parsing the rendered text.
+	Event *EventInfo
+}
+
+// EventInfo carries the structured fields of a Kubernetes event.
+type EventInfo struct {
+	Type      string
+	Reason    string
+	Message   string
+	Kind      string
+	Namespace string
+	Name      string
+	Count     int32
 }
 
 // LogObserver receives callbacks whenever the tailer renders a log line.
@@ -193,6 +210,8 @@ type logEntry struct {
 	Raw              string
 	SourceGlyph      string
 	SourceLabel      string
+	Anomaly          string
+	event            *EventInfo
 }
 
 // New creates a Tailer instance.
@@ -265,6 +284,22 @@ func New(client kubernetes.Interface, opts *config.Options, logger logr.Logger,
 	if len(opts.NodeLogFiles) > 0 {
 		t.nodeLogs = newNodeLogManager(t)
 	}
+	if opts.Anomalies {
+		t.anomalies = NewAnomalyDetector(opts.AnomalyWarmup)
+	}
+	if opts.Encode == EncodeOTLP {
+		exporter, err := NewOTLPExporter(OTLPExporterOptions{
+			Endpoint:           opts.EncodeEndpoint,
+			Headers:            opts.EncodeHeaderMap,
+			ResourceAttributes: opts.EncodeResource,
+			Logger:             t.log.WithName("otlp"),
+		})
+		if err != nil {
+			return nil, err
+		}
+		t.otlp = exporter
+		t.observers = append(t.observers, exporter)
+	}
 	return t, nil
 }
 
@@ -272,6 +307,9 @@ func New(client kubernetes.Interface, opts *config.Options, logger logr.Logger,
 func (t *Tailer) Run(ctx context.Context) error {
 	t.ctx, t.cancel = context.WithCancel(ctx)
 	defer t.cancel()
+	if t.otlp != nil {
+		defer t.flushExporter()
+	}
 
 	if len(t.selectionObservers) > 0 {
 		go t.sampleSelection(t.ctx, 10*time.Second)
@@ -867,7 +905,7 @@ func (t *Tailer) streamContainer(ctx context.Context, pod *corev1.Pod, container
 				return
 			default:
 			}
-			line := scanner.Text()
+			line := trimLineEnding(scanner.Text())
 			if t.opts.ExcludeLineRegex != nil && t.opts.ExcludeLineRegex.MatchString(line) {
 				continue
 			}
@@ -1007,7 +1045,18 @@ func isContextErr(err error) bool {
 	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
 }
 
+// trimLineEnding drops carriage returns left after line splitting. Windows containers write CRLF,
+// and console hosts that translate line endings can leave "\r\r\n", which bufio.ScanLines only
+// partially strips.
+func trimLineEnding(line string) string {
+	return strings.TrimRight(line, "\r")
+}
+
 func (t *Tailer) outputLine(src logSource, namespace, pod, container, line string) {
+	t.outputEntry(src, namespace, pod, container, line, nil)
+}
+
+func (t *Tailer) outputEntry(src logSource, namespace, pod, container, line string, event *EventInfo) {
 	if t.opts.NodeLogsOnly && src == sourcePod {
 		return
 	}
@@ -1049,6 +1098,7 @@ func (t *Tailer) outputLine(src logSource, namespace, pod, container, line strin
 			displayPod = override
 		}
 	}
+	anomaly := t.anomalies.Observe(line, wallClock)
 	containerTag := formatContainerTag(container)
 	timestampToken := ""
 	if t.opts.ShowTimestamp {
@@ -1067,8 +1117,15 @@ func (t *Tailer) outputLine(src logSource, namespace, pod, container, line strin
 		Raw:              line,
 		SourceGlyph:      "",
 		SourceLabel:      src.label(),
+		Anomaly:          string(anomaly),
+		event:            event,
 	}
 	rendered := line
+	if t.opts.Encode == EncodeLogfmt || t.opts.Encode == EncodeJSONL {
+		t.notifyLogObservers(entry, line, rendered, wallClock)
+		t.writeEncoded(logRecordFor(entry, line, rendered, wallClock))
+		return
+	}
 	if t.opts.JSONOutput {
 		t.notifyLogObservers(entry, line, rendered, wallClock)
 		fmt.Fprintln(t.writer, line)
@@ -1091,6 +1148,11 @@ func (t *Tailer) outputLine(src logSource, namespace, pod, container, line strin
 	}
 	t.notifyLogObservers(entry, line, rendered, wallClock)
 	colored := t.applyColors(timestampToken, podToken, containerTag, rendered)
+	if t.defaultTemplate {
+		// Only the human-readable line gets a marker; -o json/raw and custom templates carry
+		// .Anomaly instead so their output stays machine-parseable.
+		colored = AnomalyMarker(anomaly, !t.colorsDisabled()) + colored
+	}
 	fmt.Fprintln(t.writer, colored)
 }
 
@@ -1098,7 +1160,14 @@ func (t *Tailer) notifyLogObservers(entry logEntry, raw, rendered string, ts tim
 	if len(t.observers) == 0 {
 		return
 	}
-	record := LogRecord{
+	record := logRecordFor(entry, raw, rendered, ts)
+	for _, observer := range t.observers {
+		observer.ObserveLog(record)
+	}
+}
+
+func logRecordFor(entry logEntry, raw, rendered string, ts time.Time) LogRecord {
+	return LogRecord{
 		Timestamp:          ts,
 		FormattedTimestamp: entry.Timestamp,
 		Namespace:          entry.Namespace,
@@ -1109,9 +1178,34 @@ func (t *Tailer) notifyLogObservers(entry logEntry, raw, rendered string, ts tim
 		Source:             entry.SourceLabel,
 		SourceGlyph:        entry.SourceGlyph,
 		RenderedEqualsRaw:  rendered == raw,
+		Anomaly:            entry.Anomaly,
+		Event:              entry.event,
 	}
-	for _, observer := range t.observers {
-		observer.ObserveLog(record)
+}
+
+// writeEncoded prints rec using the --encode line format (logfmt or jsonl).
+func (t *Tailer) writeEncoded(rec LogRecord) {
+	var line string
+	switch t.opts.Encode {
+	case EncodeLogfmt:
+		line = encodeLogfmt(rec)
+	case EncodeJSONL:
+		encoded, err := encodeJSONL(rec)
+		if err != nil {
+			t.log.Error(err, "encode jsonl record")
+			return
+		}
+		line = encoded
+	}
+	fmt.Fprintln(t.writer, line)
+}
+
+// flushExporter drains the OTLP exporter so lines tailed right before exit are not lost.
+func (t *Tailer) flushExporter() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := t.otlp.Close(ctx); err != nil {
+		t.log.Error(err, "flush otlp exporter")
 	}
 }
 
@@ -1281,7 +1375,19 @@ func (t *Tailer) printEvent(ev *corev1.Event) {
 		return
 	}
 	message, container := t.formatEventMessage(ev)
-	t.outputLine(sourceEvent, ev.InvolvedObject.Namespace, ev.InvolvedObject.Name, container, message)
+	info := &EventInfo{
+		Type:      ev.Type,
+		Reason:    ev.Reason,
+		Message:   strings.TrimSpace(ev.Message),
+		Kind:      ev.InvolvedObject.Kind,
+		Namespace: ev.InvolvedObject.Namespace,
+		Name:      ev.InvolvedObject.Name,
+		Count:     ev.Count,
+	}
+	if ev.Series != nil && ev.Series.Count > info.Count {
+		info.Count = ev.Series.Count
+	}
+	t.outputEntry(sourceEvent, ev.InvolvedObject.Namespace, ev.InvolvedObject.Name, container, message, info)
 }
 
 func (t *Tailer) formatEventMessage(ev *corev1.Event) (string, string) {
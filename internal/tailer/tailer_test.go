@@ -146,3 +146,20 @@ func TestIsRetryableLogStreamErr(t *testing.T) {
 		}
 	})
 }
+
+func TestWindowsLineEndingsAndNodeLogPaths(t *testing.T) {
+	if got := trimLineEnding("started\r\r"); got != "started" {
+		t.Fatalf("trimLineEnding = %q", got)
+	}
+	cases := map[string]string{
+		"kubelet.log":                        "kubelet.log",
+		`kubelet\kubelet.log`:                "kubelet/kubelet.log",
+		`C:\var\log\kubelet\kubelet.log`:     "kubelet/kubelet.log",
+		"/var/log/containerd/containerd.log": "containerd/containerd.log",
+	}
+	for in, want := range cases {
+		if got := nodeLogPath(in); got != want {
+			t.Fatalf("nodeLogPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
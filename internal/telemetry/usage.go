@@ -0,0 +1,175 @@
+// File: internal/telemetry/usage.go
+// Brief: Opt-in anonymous usage metrics.
+
+// usage.go implements opt-in usage telemetry: one event per command (command path, duration,
+// result, and error class) POSTed to a configurable endpoint. Nothing identifying (arguments,
+// flag values, hostnames, user names, cluster names) is ever recorded.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvTelemetry overrides the saved choice: on/1/true or off/0/false.
+	EnvTelemetry = "KTL_TELEMETRY"
+	// EnvEndpoint overrides the saved endpoint.
+	EnvEndpoint = "KTL_TELEMETRY_ENDPOINT"
+	// EnvDoNotTrack is the cross-tool opt-out (https://consoledonottrack.com); it always wins.
+	EnvDoNotTrack = "DO_NOT_TRACK"
+
+	usageSendTimeout = 2 * time.Second
+)
+
+// UsageSettings is the persisted opt-in state (~/.ktl/telemetry.json).
+type UsageSettings struct {
+	Enabled  bool   `json:"enabled"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// UsageStatus is the effective state after applying the environment.
+type UsageStatus struct {
+	Enabled  bool
+	Endpoint string
+	// Reason says which setting decided Enabled.
+	Reason string
+	Path   string
+}
+
+// UsageEvent is the complete payload of one report.
+type UsageEvent struct {
+	Command    string `json:"command"`
+	DurationMS int64  `json:"durationMs"`
+	Result     string `json:"result"`
+	ErrorClass string `json:"errorClass,omitempty"`
+	Version    string `json:"version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+}
+
+// UsageSettingsPath returns ~/.ktl/telemetry.json.
+func UsageSettingsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir for telemetry settings: %w", err)
+	}
+	return filepath.Join(home, ".ktl", "telemetry.json"), nil
+}
+
+// LoadUsageSettings reads path; a missing file means telemetry was never enabled.
+func LoadUsageSettings(path string) (UsageSettings, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return UsageSettings{}, nil
+		}
+		return UsageSettings{}, err
+	}
+	var s UsageSettings
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return UsageSettings{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// SaveUsageSettings writes s to path.
+func SaveUsageSettings(path string, s UsageSettings) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(raw, '\n'), 0o600)
+}
+
+// ResolveUsage combines the saved settings with DO_NOT_TRACK, KTL_TELEMETRY, and
+// KTL_TELEMETRY_ENDPOINT. getenv defaults to os.Getenv.
+func ResolveUsage(path string, getenv func(string) string) (UsageStatus, error) {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	st := UsageStatus{Path: path, Reason: "not enabled; opt in with ktl telemetry on"}
+	settings, err := LoadUsageSettings(path)
+	if err != nil {
+		return st, err
+	}
+	st.Enabled = settings.Enabled
+	st.Endpoint = strings.TrimSpace(settings.Endpoint)
+	if settings.Enabled {
+		st.Reason = "enabled in " + path
+	}
+	if ep := strings.TrimSpace(getenv(EnvEndpoint)); ep != "" {
+		st.Endpoint = ep
+	}
+	switch strings.ToLower(strings.TrimSpace(getenv(EnvTelemetry))) {
+	case "1", "true", "on", "yes":
+		st.Enabled, st.Reason = true, EnvTelemetry+" is set"
+	case "0", "false", "off", "no":
+		st.Enabled, st.Reason = false, EnvTelemetry+" is off"
+	}
+	if doNotTrack(getenv(EnvDoNotTrack)) {
+		st.Enabled, st.Reason = false, EnvDoNotTrack+" is set"
+	}
+	if st.Enabled && st.Endpoint == "" {
+		st.Enabled, st.Reason = false, "no endpoint configured; pass --endpoint to ktl telemetry on or set "+EnvEndpoint
+	}
+	return st, nil
+}
+
+func doNotTrack(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "0", "false", "no", "off":
+		return false
+	}
+	return true
+}
+
+// NewUsageEvent fills the platform fields of an event.
+func NewUsageEvent(command string, duration time.Duration, result, errorClass, version string) UsageEvent {
+	return UsageEvent{
+		Command:    command,
+		DurationMS: duration.Milliseconds(),
+		Result:     result,
+		ErrorClass: errorClass,
+		Version:    version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
+
+// SendUsage POSTs ev to the endpoint, giving up after a short timeout so reporting never holds
+// up the CLI.
+func SendUsage(ctx context.Context, endpoint string, ev UsageEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, usageSendTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("POST %s: %s", req.URL.Redacted(), resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package telemetry
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.json")
+	env := map[string]string{}
+	getenv := func(k string) string { return env[k] }
+
+	st, err := ResolveUsage(path, getenv)
+	if err != nil || st.Enabled {
+		t.Fatalf("expected telemetry off by default, got %+v err=%v", st, err)
+	}
+
+	if err := SaveUsageSettings(path, UsageSettings{Enabled: true, Endpoint: "https://metrics.example.com/v1"}); err != nil {
+		t.Fatal(err)
+	}
+	st, err = ResolveUsage(path, getenv)
+	if err != nil || !st.Enabled || st.Endpoint != "https://metrics.example.com/v1" {
+		t.Fatalf("expected telemetry on, got %+v err=%v", st, err)
+	}
+
+	env[EnvTelemetry] = "off"
+	if st, _ = ResolveUsage(path, getenv); st.Enabled {
+		t.Fatalf("expected %s=off to win, got %+v", EnvTelemetry, st)
+	}
+
+	env[EnvTelemetry] = "on"
+	env[EnvDoNotTrack] = "1"
+	if st, _ = ResolveUsage(path, getenv); st.Enabled || st.Reason != "DO_NOT_TRACK is set" {
+		t.Fatalf("expected DO_NOT_TRACK to win, got %+v", st)
+	}
+
+	delete(env, EnvDoNotTrack)
+	if err := SaveUsageSettings(path, UsageSettings{Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	env[EnvTelemetry] = ""
+	if st, _ = ResolveUsage(path, getenv); st.Enabled {
+		t.Fatalf("expected no endpoint to keep telemetry off, got %+v", st)
+	}
+	env[EnvEndpoint] = "https://override.example.com"
+	if st, _ = ResolveUsage(path, getenv); !st.Enabled || st.Endpoint != "https://override.example.com" {
+		t.Fatalf("expected endpoint override, got %+v", st)
+	}
+}
@@ -0,0 +1,282 @@
+// File: internal/tracing/tracing.go
+// Brief: OpenTelemetry span export for --otel-endpoint.
+
+// Package tracing exports OpenTelemetry spans for deploy phases, Helm calls, Kubernetes API
+// requests, and stack node execution to an OTLP/HTTP collector. Until Setup is called with an
+// endpoint, every helper is a no-op, so instrumented code never has to check a flag.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/netconfig"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// EnvEndpoint sets the collector when --otel-endpoint is not given.
+	EnvEndpoint = "KTL_OTEL_ENDPOINT"
+	// EnvHeaders are extra export headers (k=v,k=v), e.g. an API key for a hosted collector.
+	EnvHeaders = "OTEL_EXPORTER_OTLP_HEADERS"
+
+	instrumentationName = "github.com/kubekattle/ktl"
+	tracesPath          = "/v1/traces"
+	exportTimeout       = 10 * time.Second
+)
+
+var (
+	enabled atomic.Bool
+	// root holds the context of the command span; requests issued without a span (Helm's
+	// client calls mostly use context.Background) are parented to it instead of starting
+	// traces of their own.
+	rootMu  sync.Mutex
+	rootCtx context.Context
+)
+
+// Options configures span export.
+type Options struct {
+	// Endpoint is the collector base URL (e.g. http://localhost:4318) or the full /v1/traces URL.
+	Endpoint string
+	// Headers are added to every export request.
+	Headers        map[string]string
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Setup installs a global tracer provider exporting to opts.Endpoint and returns the function
+// that flushes and stops it; call it before the process exits or buffered spans are lost. An
+// empty endpoint leaves tracing disabled and returns a no-op shutdown.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	endpoint := strings.TrimRight(strings.TrimSpace(opts.Endpoint), "/")
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, fmt.Errorf("otel endpoint %q must start with http:// or https://", opts.Endpoint)
+	}
+	if !strings.HasSuffix(endpoint, tracesPath) {
+		endpoint += tracesPath
+	}
+	headers := ParseHeaders(os.Getenv(EnvHeaders))
+	for k, v := range opts.Headers {
+		headers[k] = v
+	}
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(endpoint),
+		otlptracehttp.WithHeaders(headers),
+		otlptracehttp.WithTimeout(exportTimeout),
+		// Export through the same proxy/CA/air-gap settings as every other fetch.
+		otlptracehttp.WithHTTPClient(netconfig.HTTPClient(exportTimeout)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("otel exporter: %w", err)
+	}
+	name := strings.TrimSpace(opts.ServiceName)
+	if name == "" {
+		name = "ktl"
+	}
+	attrs := []attribute.KeyValue{semconv.ServiceName(name)}
+	if v := strings.TrimSpace(opts.ServiceVersion); v != "" {
+		attrs = append(attrs, semconv.ServiceVersion(v))
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attrs...))
+	if err != nil {
+		res = resource.NewSchemaless(attrs...)
+	}
+	return install(sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))), nil
+}
+
+// install makes tp the global provider. Tests pass a provider with an in-memory exporter.
+func install(tp *sdktrace.TracerProvider) func(context.Context) error {
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	enabled.Store(true)
+	return func(ctx context.Context) error {
+		enabled.Store(false)
+		setRoot(nil)
+		return tp.Shutdown(ctx)
+	}
+}
+
+// Enabled reports whether spans are being exported.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// ParseHeaders parses the OTEL_EXPORTER_OTLP_HEADERS format: comma-separated key=value pairs.
+func ParseHeaders(raw string) map[string]string {
+	out := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if k = strings.TrimSpace(k); !ok || k == "" {
+			continue
+		}
+		out[k] = strings.TrimSpace(v)
+	}
+	return out
+}
+
+// Start starts a span that is a child of any span already in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartRoot starts the span for the whole command. Spans started from contexts that carry no
+// span of their own fall back to it.
+func StartRoot(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx, span := Start(ctx, name, attrs...)
+	if Enabled() {
+		setRoot(ctx)
+	}
+	return ctx, span
+}
+
+// End records err on span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// WrapREST makes cfg create a client span for every Kubernetes API request. A request is
+// parented to the span of the context it was issued with, else to the span in parent (which
+// may be nil), else to the command span.
+func WrapREST(parent context.Context, cfg *rest.Config) {
+	if cfg == nil || !Enabled() {
+		return
+	}
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &roundTripper{base: rt, parent: parent}
+	})
+}
+
+type roundTripper struct {
+	base   http.RoundTripper
+	parent context.Context
+}
+
+func (rt *roundTripper) parentContext(ctx context.Context) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	for _, p := range []context.Context{rt.parent, rootContext()} {
+		if p == nil {
+			continue
+		}
+		if sc := trace.SpanContextFromContext(p); sc.IsValid() {
+			return trace.ContextWithSpanContext(ctx, sc)
+		}
+	}
+	return ctx
+}
+
+func setRoot(ctx context.Context) {
+	rootMu.Lock()
+	defer rootMu.Unlock()
+	rootCtx = ctx
+}
+
+func rootContext() context.Context {
+	rootMu.Lock()
+	defer rootMu.Unlock()
+	return rootCtx
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	info := parseAPIPath(req.URL.Path)
+	name := "kube " + req.Method
+	if info.resource != "" {
+		name += " " + info.resource
+	}
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.ServerAddress(req.URL.Hostname()),
+		attribute.String("k8s.api.path", req.URL.Path),
+	}
+	if info.namespace != "" {
+		attrs = append(attrs, semconv.K8SNamespaceName(info.namespace))
+	}
+	if info.resource != "" {
+		attrs = append(attrs, attribute.String("k8s.resource", info.resource))
+	}
+	if info.name != "" {
+		attrs = append(attrs, attribute.String("k8s.resource.name", info.name))
+	}
+	if req.URL.Query().Get("watch") == "true" {
+		attrs = append(attrs, attribute.Bool("k8s.watch", true))
+	}
+	ctx, span := otel.Tracer(instrumentationName).Start(rt.parentContext(req.Context()), name,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+	// Propagate the span so API servers with tracing enabled join the same trace.
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := rt.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+type apiPath struct {
+	namespace string
+	resource  string
+	name      string
+}
+
+// parseAPIPath splits /api/v1/namespaces/ns/pods/name and /apis/group/version/... into the
+// namespace, resource (with subresource), and object name.
+func parseAPIPath(path string) apiPath {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		parts = parts[3:]
+	default:
+		return apiPath{}
+	}
+	var out apiPath
+	if len(parts) >= 2 && parts[0] == "namespaces" {
+		out.namespace = parts[1]
+		parts = parts[2:]
+		if len(parts) == 0 {
+			// The namespace object itself.
+			return apiPath{resource: "namespaces", name: out.namespace}
+		}
+	}
+	if len(parts) > 0 {
+		out.resource = parts[0]
+	}
+	if len(parts) > 1 {
+		out.name = parts[1]
+	}
+	if len(parts) > 2 {
+		out.resource += "/" + parts[2]
+	}
+	return out
+}
@@ -0,0 +1,126 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/client-go/rest"
+)
+
+func TestParseAPIPath(t *testing.T) {
+	cases := map[string]apiPath{
+		"/api/v1/namespaces/prod/pods":                          {namespace: "prod", resource: "pods"},
+		"/api/v1/namespaces/prod/pods/web-0/log":                {namespace: "prod", resource: "pods/log", name: "web-0"},
+		"/apis/apps/v1/namespaces/prod/deployments/web":         {namespace: "prod", resource: "deployments", name: "web"},
+		"/apis/apps/v1/deployments":                             {resource: "deployments"},
+		"/api/v1/namespaces/prod":                               {resource: "namespaces", name: "prod"},
+		"/api/v1/nodes/n1":                                      {resource: "nodes", name: "n1"},
+		"/version":                                              {},
+		"/apis/networking.k8s.io/v1/namespaces/a/ingresses/b/x": {namespace: "a", resource: "ingresses/x", name: "b"},
+	}
+	for path, want := range cases {
+		if got := parseAPIPath(path); got != want {
+			t.Errorf("parseAPIPath(%q) = %+v, want %+v", path, got, want)
+		}
+	}
+}
+
+func TestWrapRESTRecordsKubeSpans(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	shutdown := install(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)))
+	defer shutdown(context.Background())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("traceparent") == "" {
+			t.Errorf("request is missing the traceparent header")
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	cfg := &rest.Config{Host: srv.URL}
+	WrapREST(nil, cfg)
+	rt, err := rest.TransportFor(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, parent := Start(context.Background(), "deploy")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/apis/apps/v1/namespaces/prod/deployments/web", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	// Without a span on the request, the span passed to WrapREST is the parent.
+	fallback := &rest.Config{Host: srv.URL}
+	WrapREST(ctx, fallback)
+	frt, err := rest.TransportFor(fallback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ = http.NewRequest(http.MethodGet, srv.URL+"/api/v1/namespaces/prod/pods", nil)
+	resp, err = frt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	End(parent, errors.New("boom"))
+
+	spans := exp.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	kube, pods, deploy := spans[0], spans[1], spans[2]
+	if kube.Name != "kube GET deployments" {
+		t.Errorf("kube span name = %q", kube.Name)
+	}
+	if kube.Parent.SpanID() != deploy.SpanContext.SpanID() {
+		t.Errorf("kube span is not a child of the deploy span")
+	}
+	if pods.Name != "kube GET pods" || pods.Parent.SpanID() != deploy.SpanContext.SpanID() {
+		t.Errorf("fallback span %q is not a child of the deploy span", pods.Name)
+	}
+	if kube.Status.Code != codes.Error {
+		t.Errorf("kube span status = %v, want error for a 404", kube.Status.Code)
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range kube.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["k8s.namespace.name"].AsString() != "prod" || attrs["k8s.resource.name"].AsString() != "web" {
+		t.Errorf("unexpected kube span attributes: %v", kube.Attributes)
+	}
+	if deploy.Status.Code != codes.Error || deploy.Status.Description != "boom" {
+		t.Errorf("deploy span status = %+v", deploy.Status)
+	}
+}
+
+func TestSetupWithoutEndpointIsDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Enabled() {
+		t.Fatalf("tracing enabled without an endpoint")
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Setup(context.Background(), Options{Endpoint: "collector:4318"}); err == nil {
+		t.Fatalf("expected an error for an endpoint without a scheme")
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	got := ParseHeaders("api-key = abc, x-team=platform,,bogus")
+	if len(got) != 2 || got["api-key"] != "abc" || got["x-team"] != "platform" {
+		t.Fatalf("ParseHeaders = %v", got)
+	}
+}
@@ -0,0 +1,218 @@
+// File: internal/traffic/traffic.go
+// Brief: Internal traffic package implementation for 'traffic'.
+
+// Package traffic records HTTP request/response metadata flowing through a local tap proxy.
+package traffic
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Exchange is the recorded metadata for one proxied HTTP request/response pair.
+// Bodies are never stored; only their sizes.
+type Exchange struct {
+	ID              string            `json:"id"`
+	Target          string            `json:"target,omitempty"`
+	Start           time.Time         `json:"start"`
+	DurationMS      float64           `json:"durationMs"`
+	Method          string            `json:"method"`
+	Host            string            `json:"host,omitempty"`
+	Path            string            `json:"path"`
+	Query           string            `json:"query,omitempty"`
+	Status          int               `json:"status"`
+	RequestBytes    int64             `json:"requestBytes"`
+	ResponseBytes   int64             `json:"responseBytes"`
+	RequestHeaders  map[string]string `json:"requestHeaders,omitempty"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// Summary renders a one-line description of the exchange.
+func (e Exchange) Summary() string {
+	status := fmt.Sprintf("%d", e.Status)
+	if e.Error != "" {
+		status = "ERR " + e.Error
+	}
+	path := e.Path
+	if e.Query != "" {
+		path += "?" + e.Query
+	}
+	return fmt.Sprintf("%s %s -> %s (%.1fms, %dB/%dB)", e.Method, path, status, e.DurationMS, e.RequestBytes, e.ResponseBytes)
+}
+
+// Observer receives each completed exchange. Implementations must not block.
+type Observer interface {
+	ObserveExchange(Exchange)
+}
+
+// ObserverFunc adapts a function to Observer.
+type ObserverFunc func(Exchange)
+
+// ObserveExchange calls f.
+func (f ObserverFunc) ObserveExchange(ex Exchange) { f(ex) }
+
+var redactedHeaders = map[string]struct{}{
+	"Authorization":       {},
+	"Proxy-Authorization": {},
+	"Cookie":              {},
+	"Set-Cookie":          {},
+	"X-Api-Key":           {},
+	"X-Auth-Token":        {},
+}
+
+// Proxy is a reverse proxy that reports every exchange to its observers.
+type Proxy struct {
+	target    *url.URL
+	label     string
+	proxy     *httputil.ReverseProxy
+	seq       uint64
+	mu        sync.RWMutex
+	observers []Observer
+	now       func() time.Time
+}
+
+// NewProxy returns a recording reverse proxy for target (e.g. http://127.0.0.1:18080).
+func NewProxy(target, label string, observers ...Observer) (*Proxy, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("parse proxy target: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("proxy target %q must include scheme and host", target)
+	}
+	p := &Proxy{target: u, label: label, now: time.Now}
+	p.proxy = httputil.NewSingleHostReverseProxy(u)
+	for _, obs := range observers {
+		if obs != nil {
+			p.observers = append(p.observers, obs)
+		}
+	}
+	return p, nil
+}
+
+// AddObserver registers another observer.
+func (p *Proxy) AddObserver(obs Observer) {
+	if p == nil || obs == nil {
+		return
+	}
+	p.mu.Lock()
+	p.observers = append(p.observers, obs)
+	p.mu.Unlock()
+}
+
+// ServeHTTP proxies the request and records its metadata.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := p.now()
+	ex := Exchange{
+		ID:             fmt.Sprintf("%s-%d", start.UTC().Format("150405"), atomic.AddUint64(&p.seq, 1)),
+		Target:         p.label,
+		Start:          start.UTC(),
+		Method:         r.Method,
+		Host:           r.Host,
+		Path:           r.URL.Path,
+		Query:          r.URL.RawQuery,
+		RequestHeaders: flattenHeaders(r.Header),
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = &countingReader{r: r.Body}
+	}
+	rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+
+	var proxyErr error
+	proxy := *p.proxy
+	proxy.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, err error) {
+		proxyErr = err
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	proxy.ServeHTTP(rec, r)
+
+	ex.Status = rec.status
+	ex.ResponseBytes = rec.n
+	ex.ResponseHeaders = flattenHeaders(rec.Header())
+	ex.DurationMS = float64(p.now().Sub(start).Microseconds()) / 1000
+	if proxyErr != nil {
+		ex.Error = proxyErr.Error()
+	}
+	if body, ok := r.Body.(*countingReader); ok {
+		ex.RequestBytes = body.n
+	}
+	p.mu.RLock()
+	observers := append([]Observer(nil), p.observers...)
+	p.mu.RUnlock()
+	for _, obs := range observers {
+		obs.ObserveExchange(ex)
+	}
+}
+
+func flattenHeaders(h http.Header) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make(map[string]string, len(keys))
+	for _, k := range keys {
+		canonical := http.CanonicalHeaderKey(k)
+		if _, secret := redactedHeaders[canonical]; secret {
+			out[canonical] = "[redacted]"
+			continue
+		}
+		out[canonical] = strings.Join(h[k], ", ")
+	}
+	return out
+}
+
+type countingReader struct {
+	r io.ReadCloser
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) Close() error { return c.r.Close() }
+
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	n           int64
+}
+
+func (w *recordingWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func (w *recordingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,95 @@
+package traffic
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestProxyRecordsExchangeMetadata(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("echo:" + string(body)))
+	}))
+	defer backend.Close()
+
+	var mu sync.Mutex
+	var got []Exchange
+	proxy, err := NewProxy(backend.URL, "prod/api-0:8080", ObserverFunc(func(ex Exchange) {
+		mu.Lock()
+		got = append(got, ex)
+		mu.Unlock()
+	}))
+	if err != nil {
+		t.Fatalf("NewProxy: %v", err)
+	}
+	front := httptest.NewServer(proxy)
+	defer front.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, front.URL+"/orders?id=7", strings.NewReader("hello"))
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "echo:hello" || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("unexpected response %d %q", resp.StatusCode, body)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 exchange, got %d", len(got))
+	}
+	ex := got[0]
+	if ex.Method != http.MethodPost || ex.Path != "/orders" || ex.Query != "id=7" || ex.Status != http.StatusCreated {
+		t.Fatalf("unexpected exchange: %+v", ex)
+	}
+	if ex.RequestBytes != 5 || ex.ResponseBytes != int64(len("echo:hello")) {
+		t.Fatalf("unexpected sizes: req=%d resp=%d", ex.RequestBytes, ex.ResponseBytes)
+	}
+	if ex.Target != "prod/api-0:8080" {
+		t.Fatalf("unexpected target %q", ex.Target)
+	}
+	if ex.RequestHeaders["Authorization"] != "[redacted]" || ex.ResponseHeaders["Set-Cookie"] != "[redacted]" {
+		t.Fatalf("expected credential headers to be redacted: %+v %+v", ex.RequestHeaders, ex.ResponseHeaders)
+	}
+	if ex.ResponseHeaders["Content-Type"] != "text/plain" {
+		t.Fatalf("expected content type to be recorded: %+v", ex.ResponseHeaders)
+	}
+}
+
+func TestProxyRecordsUpstreamErrors(t *testing.T) {
+	backend := httptest.NewServer(http.NotFoundHandler())
+	target := backend.URL
+	backend.Close()
+
+	var got Exchange
+	proxy, err := NewProxy(target, "", ObserverFunc(func(ex Exchange) { got = ex }))
+	if err != nil {
+		t.Fatalf("NewProxy: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusBadGateway || got.Status != http.StatusBadGateway {
+		t.Fatalf("expected 502, got response=%d recorded=%d", rec.Code, got.Status)
+	}
+	if got.Error == "" || !strings.Contains(got.Summary(), "ERR") {
+		t.Fatalf("expected upstream error to be recorded: %+v", got)
+	}
+}
+
+func TestNewProxyRejectsRelativeTarget(t *testing.T) {
+	if _, err := NewProxy("127.0.0.1:8080", ""); err == nil {
+		t.Fatalf("expected error for target without scheme")
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/i18n"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -26,6 +27,9 @@ type DeployConsoleOptions struct {
 	Wide            bool
 	Width           int
 	DetailsExpanded bool
+	// Plain prints sequential progress lines instead of redrawing the panel in place, for screen
+	// readers and terminals without cursor control.
+	Plain bool
 }
 
 type DeployMetadata struct {
@@ -46,10 +50,12 @@ type DeployConsole struct {
 	metadata   DeployMetadata
 	phases     map[string]phaseBadge
 	resources  []deploy.ResourceStatus
+	hooks      []deploy.HookStatus
 	warning    *consoleWarning
 	sections   []consoleSection
 	totalLines int
 	details    bool
+	plain      plainState
 }
 
 type phaseBadge struct {
@@ -76,13 +82,17 @@ func NewDeployConsole(out io.Writer, meta DeployMetadata, opts DeployConsoleOpti
 	for _, name := range phaseOrder {
 		phases[name] = phaseBadge{Name: name, State: "pending"}
 	}
-	return &DeployConsole{
+	c := &DeployConsole{
 		out:      out,
 		opts:     opts,
 		metadata: meta,
 		phases:   phases,
 		details:  opts.DetailsExpanded,
 	}
+	if opts.Enabled && opts.Plain {
+		c.plainMetadataLocked()
+	}
+	return c
 }
 
 func (c *DeployConsole) UpdateMetadata(meta DeployMetadata) {
@@ -91,6 +101,9 @@ func (c *DeployConsole) UpdateMetadata(meta DeployMetadata) {
 	}
 	c.mu.Lock()
 	c.metadata = meta
+	if c.opts.Plain {
+		c.plainMetadataLocked()
+	}
 	c.renderLocked()
 	c.mu.Unlock()
 }
@@ -101,6 +114,23 @@ func (c *DeployConsole) UpdateResources(rows []deploy.ResourceStatus) {
 	}
 	c.mu.Lock()
 	c.resources = cloneStatusRows(rows)
+	if c.opts.Plain {
+		c.plainResourcesLocked(c.resources)
+	}
+	c.renderLocked()
+	c.mu.Unlock()
+}
+
+// UpdateHooks replaces the HOOKS panel with the latest Job/Pod hook states.
+func (c *DeployConsole) UpdateHooks(hooks []deploy.HookStatus) {
+	if c == nil || !c.opts.Enabled {
+		return
+	}
+	c.mu.Lock()
+	c.hooks = append([]deploy.HookStatus(nil), hooks...)
+	if c.opts.Plain {
+		c.plainHooksLocked(c.hooks)
+	}
 	c.renderLocked()
 	c.mu.Unlock()
 }
@@ -148,11 +178,15 @@ func (c *DeployConsole) updatePhase(name, state, message string) {
 		return
 	}
 	c.mu.Lock()
-	badge := c.phases[key]
+	prev := c.phases[key]
+	badge := prev
 	badge.Name = key
 	badge.State = state
 	badge.Message = strings.TrimSpace(message)
 	c.phases[key] = badge
+	if c.opts.Plain {
+		c.plainPhaseLocked(prev, badge)
+	}
 	c.renderLocked()
 	c.mu.Unlock()
 }
@@ -163,6 +197,9 @@ func (c *DeployConsole) setWarning(severity, message string) {
 	}
 	c.mu.Lock()
 	c.warning = &consoleWarning{Severity: severity, Message: message, IssuedAt: time.Now()}
+	if c.opts.Plain {
+		c.plainWarningLocked(*c.warning)
+	}
 	c.renderLocked()
 	c.mu.Unlock()
 }
@@ -180,7 +217,7 @@ func (c *DeployConsole) clearWarning() {
 }
 
 func (c *DeployConsole) renderLocked() {
-	if !c.opts.Enabled || c.out == nil {
+	if !c.opts.Enabled || c.opts.Plain || c.out == nil {
 		return
 	}
 	newSections := c.buildSectionsLocked()
@@ -195,7 +232,13 @@ func (c *DeployConsole) buildSectionsLocked() []consoleSection {
 	if c.warning != nil {
 		sections = append(sections, consoleSection{name: "warning", lines: []string{renderWarning(*c.warning)}})
 	}
+	if lines := c.renderHookLines(); len(lines) > 0 {
+		sections = append(sections, consoleSection{name: "hooks", lines: lines})
+	}
 	sections = append(sections, consoleSection{name: "resources", lines: c.renderResourceLines()})
+	if lines := c.renderStorageLines(); len(lines) > 0 {
+		sections = append(sections, consoleSection{name: "storage", lines: lines})
+	}
 	return sections
 }
 
@@ -239,7 +282,7 @@ func (c *DeployConsole) writeSections(sections []consoleSection) {
 func (c *DeployConsole) renderResourceLines() []string {
 	rows := c.resources
 	if len(rows) == 0 {
-		return []string{"Waiting for release resources..."}
+		return []string{i18n.T(i18n.DeployWaiting)}
 	}
 	width := c.opts.Width
 	if width <= 0 {
@@ -255,7 +298,7 @@ func (c *DeployConsole) renderResourceLines() []string {
 		}
 		return lines
 	}
-	lines = append(lines, fmt.Sprintf("%-40s %-8s %-12s %s", "Resource", "Action", "Status", "Message"))
+	lines = append(lines, fmt.Sprintf("%-40s %-8s %-12s %s", i18n.T(i18n.DeployColResource), i18n.T(i18n.DeployColAction), i18n.T(i18n.DeployColStatus), i18n.T(i18n.DeployColMessage)))
 	lines = append(lines, strings.Repeat("-", 100))
 	for _, row := range rows {
 		resource := fmt.Sprintf("%s %s/%s", row.Kind, row.Namespace, row.Name)
@@ -264,6 +307,53 @@ func (c *DeployConsole) renderResourceLines() []string {
 	return lines
 }
 
+// consoleHookLogLines caps the log tail shown under a failed hook.
+const consoleHookLogLines = 8
+
+// renderHookLines lists the release's Job/Pod hooks with their state; a failed hook also shows
+// the tail of its pod logs, which Helm itself only reports as "job failed".
+func (c *DeployConsole) renderHookLines() []string {
+	if len(c.hooks) == 0 {
+		return nil
+	}
+	lines := []string{color.New(color.Bold).Sprint(i18n.T(i18n.DeployHooks))}
+	for _, h := range c.hooks {
+		elapsed := ""
+		switch {
+		case h.Duration > 0:
+			elapsed = h.Duration.String()
+		case h.State == deploy.HookRunning && !h.StartedAt.IsZero():
+			elapsed = time.Since(h.StartedAt).Round(time.Second).String()
+		}
+		lines = append(lines, fmt.Sprintf("  %-13s %-34s %-12s %-6s %s", h.Event, h.Label(), colorizeStatus(h.State), elapsed, h.Message))
+		if h.State != deploy.HookFailed {
+			continue
+		}
+		logs := h.Logs
+		if len(logs) > consoleHookLogLines {
+			logs = logs[len(logs)-consoleHookLogLines:]
+		}
+		for _, line := range logs {
+			lines = append(lines, color.New(color.FgHiBlack).Sprint("    │ ")+line)
+		}
+	}
+	return lines
+}
+
+// renderStorageLines calls out storage problems below the resource table; a claim that never
+// binds otherwise only shows up as a wait timeout.
+func (c *DeployConsole) renderStorageLines() []string {
+	issues := deploy.StorageIssues(c.resources)
+	if len(issues) == 0 {
+		return nil
+	}
+	lines := []string{color.New(color.FgYellow, color.Bold).Sprint(i18n.T(i18n.DeployStorage))}
+	for _, row := range issues {
+		lines = append(lines, fmt.Sprintf("  • %s %s/%s %s: %s", row.Kind, row.Namespace, row.Name, colorizeStatus(row.Status), row.Message))
+	}
+	return lines
+}
+
 func (c *DeployConsole) renderMetadataLinesLocked() []string {
 	lines := []string{formatMetadataSummary(c.metadata)}
 	detailLines := formatMetadataDetails(c.metadata)
@@ -276,7 +366,7 @@ func (c *DeployConsole) renderMetadataLinesLocked() []string {
 		}
 		return lines
 	}
-	lines = append(lines, fmt.Sprintf("  Details ▸ %s (add --console-details to expand)", summarizeDetailCounts(c.metadata)))
+	lines = append(lines, "  "+i18n.T(i18n.DeployDetails, summarizeDetailCounts(c.metadata)))
 	return lines
 }
 
@@ -288,7 +378,7 @@ func (c *DeployConsole) shouldRenderDetails() bool {
 func formatMetadataSummary(meta DeployMetadata) string {
 	parts := []string{}
 	if meta.Release != "" {
-		parts = append(parts, fmt.Sprintf("Release %s", meta.Release))
+		parts = append(parts, i18n.T(i18n.DeployRelease, meta.Release))
 	}
 	if meta.Namespace != "" {
 		parts = append(parts, fmt.Sprintf("ns/%s", meta.Namespace))
@@ -298,10 +388,10 @@ func formatMetadataSummary(meta DeployMetadata) string {
 		if meta.ChartVersion != "" {
 			chart = fmt.Sprintf("%s@%s", chart, meta.ChartVersion)
 		}
-		parts = append(parts, fmt.Sprintf("Chart %s", chart))
+		parts = append(parts, i18n.T(i18n.DeployChart, chart))
 	}
 	if len(parts) == 0 {
-		return "Deploying release"
+		return i18n.T(i18n.DeployDeploying)
 	}
 	return strings.Join(parts, " | ")
 }
@@ -332,7 +422,7 @@ func summarizeDetailCounts(meta DeployMetadata) string {
 		counts = append(counts, fmt.Sprintf("set-string:%d", len(str)))
 	}
 	if len(counts) == 0 {
-		return "nothing to show"
+		return i18n.T(i18n.DeployDetailsNone)
 	}
 	return strings.Join(counts, ", ")
 }
@@ -358,7 +448,7 @@ func sanitizeList(values []string) []string {
 
 func formatPhases(phases map[string]phaseBadge) string {
 	if len(phases) == 0 {
-		return "Phases: pending"
+		return i18n.T(i18n.DeployPhases, "pending")
 	}
 	chips := make([]string, 0, len(phaseOrder))
 	for _, name := range phaseOrder {
@@ -371,12 +461,12 @@ func formatPhases(phases map[string]phaseBadge) string {
 		}
 		chips = append(chips, renderPhaseChip(badge))
 	}
-	return fmt.Sprintf("Phases: %s", strings.Join(chips, "  "))
+	return i18n.T(i18n.DeployPhases, strings.Join(chips, "  "))
 }
 
 func renderPhaseChip(badge phaseBadge) string {
 	state := strings.ToLower(strings.TrimSpace(badge.State))
-	label := phaseTitleCaser.String(strings.TrimSpace(badge.Name))
+	label := phaseLabel(badge.Name)
 	if label == "" {
 		label = "Phase"
 	}
@@ -402,10 +492,20 @@ func renderPhaseChip(badge phaseBadge) string {
 	return text
 }
 
+// phaseLabel returns the localized label of a deploy phase; phases without a catalog entry are
+// title-cased.
+func phaseLabel(name string) string {
+	name = strings.TrimSpace(name)
+	if label, ok := i18n.Lookup(i18n.PhaseKey(strings.ToLower(name))); ok {
+		return label
+	}
+	return phaseTitleCaser.String(name)
+}
+
 func renderWarning(w consoleWarning) string {
-	prefix := color.New(color.FgHiYellow).Sprint("Attention")
+	prefix := color.New(color.FgHiYellow).Sprint(i18n.T(i18n.DeployAttention))
 	if w.Severity == "error" {
-		prefix = color.New(color.FgHiRed).Sprint("Attention")
+		prefix = color.New(color.FgHiRed).Sprint(i18n.T(i18n.DeployAttention))
 	}
 	age := humanizeAge(time.Since(w.IssuedAt))
 	return fmt.Sprintf("%s (%s): %s", prefix, age, w.Message)
@@ -424,6 +524,19 @@ func colorizeStatus(status string) string {
 	}
 }
 
+// ColorizeSeverity paints text by stream severity: red for error, yellow for warn, and default
+// for info.
+func ColorizeSeverity(level, text string) string {
+	switch normalizeSeverity(level) {
+	case "error":
+		return color.New(color.FgRed).Sprint(text)
+	case "warn":
+		return color.New(color.FgYellow).Sprint(text)
+	default:
+		return text
+	}
+}
+
 func normalizeSeverity(level string) string {
 	lvl := strings.ToLower(strings.TrimSpace(level))
 	switch lvl {
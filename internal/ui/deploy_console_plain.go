@@ -0,0 +1,124 @@
+// File: internal/ui/deploy_console_plain.go
+// Brief: Internal ui package implementation for 'deploy console plain'.
+
+// deploy_console_plain.go renders the deploy console as sequential lines for screen readers and
+// dumb terminals: nothing is redrawn, and a line is printed only when something changes.
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/i18n"
+)
+
+// plainState remembers what the plain console already announced.
+type plainState struct {
+	metadata  string
+	resources map[string]string
+	hooks     map[string]string
+}
+
+func (c *DeployConsole) plainLine(format string, args ...any) {
+	if c.out == nil {
+		return
+	}
+	fmt.Fprintf(c.out, format+"\n", args...)
+}
+
+func (c *DeployConsole) plainMetadataLocked() {
+	meta := c.metadata
+	text := i18n.T(i18n.PlainRelease, orUnknown(meta.Release))
+	if meta.Namespace != "" {
+		text += " " + i18n.T(i18n.PlainNamespace, meta.Namespace)
+	}
+	switch {
+	case meta.Chart != "" && meta.ChartVersion != "":
+		text += ", " + i18n.T(i18n.PlainChartVersion, meta.Chart, meta.ChartVersion)
+	case meta.Chart != "":
+		text += ", " + i18n.T(i18n.PlainChart, meta.Chart)
+	}
+	text += "."
+	for _, line := range formatMetadataDetails(meta) {
+		text += " " + line + "."
+	}
+	if text == c.plain.metadata {
+		return
+	}
+	c.plain.metadata = text
+	c.plainLine("%s", text)
+}
+
+func (c *DeployConsole) plainPhaseLocked(prev, next phaseBadge) {
+	if prev.State == next.State && prev.Message == next.Message {
+		return
+	}
+	line := i18n.T(i18n.PlainPhase, phaseLabel(next.Name), colorizeStatus(next.State))
+	if next.Message != "" {
+		line += ". " + next.Message
+	}
+	c.plainLine("%s", line)
+}
+
+func (c *DeployConsole) plainWarningLocked(w consoleWarning) {
+	label := i18n.T(i18n.PlainWarning)
+	if w.Severity == "error" {
+		label = i18n.T(i18n.PlainError)
+	}
+	c.plainLine("%s: %s", ColorizeSeverity(w.Severity, label), w.Message)
+}
+
+func (c *DeployConsole) plainResourcesLocked(rows []deploy.ResourceStatus) {
+	if c.plain.resources == nil {
+		c.plain.resources = map[string]string{}
+	}
+	for _, row := range rows {
+		key := row.Kind + " " + row.Namespace + "/" + row.Name
+		state := row.Status + "\x00" + row.Message
+		if c.plain.resources[key] == state {
+			continue
+		}
+		c.plain.resources[key] = state
+		line := i18n.T(i18n.PlainResource, row.Kind, row.Name, orUnknown(row.Namespace), colorizeStatus(row.Status))
+		if msg := strings.TrimSpace(row.Message); msg != "" {
+			line += ". " + msg
+		}
+		c.plainLine("%s", line)
+	}
+}
+
+func (c *DeployConsole) plainHooksLocked(hooks []deploy.HookStatus) {
+	if c.plain.hooks == nil {
+		c.plain.hooks = map[string]string{}
+	}
+	for _, h := range hooks {
+		key := h.Event + " " + h.Label()
+		if c.plain.hooks[key] == h.State {
+			continue
+		}
+		c.plain.hooks[key] = h.State
+		line := i18n.T(i18n.PlainHook, h.Event, h.Kind, h.Name, colorizeStatus(h.State))
+		if h.Duration > 0 {
+			line += " " + i18n.T(i18n.PlainHookAfter, h.Duration)
+		}
+		if h.Message != "" && h.State != deploy.HookPending {
+			line += ". " + h.Message
+		}
+		c.plainLine("%s", line)
+		if h.State == deploy.HookFailed && len(h.Logs) > 0 {
+			c.plainLine("%s", i18n.T(i18n.PlainHookLogs, h.Name))
+			for _, l := range h.Logs {
+				c.plainLine("  %s", l)
+			}
+			c.plainLine("%s", i18n.T(i18n.PlainHookLogsEnd, h.Name))
+		}
+	}
+}
+
+func orUnknown(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return i18n.T(i18n.PlainUnknown)
+	}
+	return s
+}
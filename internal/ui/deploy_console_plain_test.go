@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/kubekattle/ktl/internal/deploy"
+	"github.com/kubekattle/ktl/internal/i18n"
+)
+
+func TestDeployConsolePlainPrintsSequentialLines(t *testing.T) {
+	prev := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = prev })
+
+	buf := &bytes.Buffer{}
+	c := NewDeployConsole(buf, DeployMetadata{Release: "api", Namespace: "prod", Chart: "web", ChartVersion: "1.2.3"}, DeployConsoleOptions{Enabled: true, Plain: true})
+
+	c.PhaseStarted("render")
+	c.PhaseStarted("render") // unchanged, not repeated
+	c.PhaseCompleted("render", "succeeded", "")
+	rows := []deploy.ResourceStatus{{Kind: "Deployment", Namespace: "prod", Name: "api", Status: "Progressing", Message: "1/3 ready"}}
+	c.UpdateResources(rows)
+	c.UpdateResources(rows) // unchanged, not repeated
+	c.EmitEvent("warn", "webhook slow")
+	c.UpdateHooks([]deploy.HookStatus{{Event: "pre-upgrade", Kind: "Job", Name: "migrate", State: deploy.HookFailed, Message: "BackoffLimitExceeded", Logs: []string{"boom"}}})
+	c.Done()
+
+	out := buf.String()
+	if strings.Contains(out, "\x1b[") {
+		t.Fatalf("plain console must not emit escape sequences:\n%q", out)
+	}
+	want := []string{
+		"Release api in namespace prod, chart web version 1.2.3.",
+		"Phase Render: running",
+		"Phase Render: succeeded",
+		"Deployment api in namespace prod: Progressing. 1/3 ready",
+		"Warning: webhook slow",
+		"Hook pre-upgrade Job migrate: Failed. BackoffLimitExceeded",
+		"Last log lines of hook migrate:",
+		"  boom",
+		"End of logs for hook migrate.",
+	}
+	got := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected plain output:\n%s", out)
+	}
+}
+
+func TestDeployConsolePlainUsesLocale(t *testing.T) {
+	prevColor := color.NoColor
+	color.NoColor = true
+	prevLocale := i18n.SetLocale("de")
+	t.Cleanup(func() {
+		color.NoColor = prevColor
+		i18n.SetLocale(prevLocale)
+	})
+
+	buf := &bytes.Buffer{}
+	c := NewDeployConsole(buf, DeployMetadata{Release: "api", Namespace: "prod"}, DeployConsoleOptions{Enabled: true, Plain: true})
+	c.PhaseStarted("wait")
+
+	want := "Release api im Namespace prod.\nPhase Warten: running\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected localized output:\n%q", buf.String())
+	}
+}
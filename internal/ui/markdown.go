@@ -0,0 +1,118 @@
+// File: internal/ui/markdown.go
+// Brief: Internal ui package implementation for 'terminal markdown'.
+
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var (
+	mdHeading    = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdBullet     = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdInlineCode = regexp.MustCompile("`([^`]+)`")
+	mdBold       = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+)
+
+// RenderMarkdown formats the common Markdown subset produced by chat assistants (headings, lists,
+// quotes, fenced code, inline code, bold) for a terminal, wrapping prose at width columns. Styling
+// honors color.NoColor. It deliberately avoids a full Markdown/syntax-highlighting stack, which
+// would add noticeably to ktl's startup time.
+func RenderMarkdown(src string, width int) string {
+	if width <= 0 {
+		width = 100
+	}
+	heading := color.New(color.Bold, color.FgHiWhite)
+	code := color.New(color.FgCyan)
+	dim := color.New(color.FgHiBlack)
+
+	var b strings.Builder
+	inFence := false
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if !inFence {
+				if lang := strings.TrimSpace(strings.Trim(trimmed, "`~")); lang != "" {
+					b.WriteString("  " + dim.Sprint(lang) + "\n")
+				}
+			}
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			b.WriteString("    " + code.Sprint(line) + "\n")
+			continue
+		}
+		if trimmed == "" {
+			b.WriteString("\n")
+			continue
+		}
+		if m := mdHeading.FindStringSubmatch(trimmed); m != nil {
+			text := renderInline(m[2], code)
+			if len(m[1]) <= 2 {
+				text = strings.ToUpper(text)
+			}
+			b.WriteString(heading.Sprint(text) + "\n")
+			continue
+		}
+		if trimmed == "---" || trimmed == "***" || trimmed == "___" {
+			b.WriteString(dim.Sprint(strings.Repeat("─", min(width, 40))) + "\n")
+			continue
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			text := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			for _, wrapped := range wrapWords(text, width-2) {
+				b.WriteString(dim.Sprint("│ "+wrapped) + "\n")
+			}
+			continue
+		}
+		prefix, rest := "", trimmed
+		if m := mdBullet.FindStringSubmatch(line); m != nil {
+			prefix = m[1] + "  • "
+			rest = m[2]
+		}
+		indent := strings.Repeat(" ", len([]rune(prefix)))
+		for i, wrapped := range wrapWords(rest, width-len(indent)) {
+			lead := indent
+			if i == 0 {
+				lead = prefix
+			}
+			b.WriteString(lead + renderInline(wrapped, code) + "\n")
+		}
+	}
+	return b.String()
+}
+
+func renderInline(text string, code *color.Color) string {
+	text = mdInlineCode.ReplaceAllStringFunc(text, func(m string) string {
+		return code.Sprint(strings.Trim(m, "`"))
+	})
+	return mdBold.ReplaceAllStringFunc(text, func(m string) string {
+		return color.New(color.Bold).Sprint(strings.Trim(m, "*_"))
+	})
+}
+
+// wrapWords splits text into lines of at most width runes, breaking on spaces.
+func wrapWords(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	if width < 20 {
+		width = 20
+	}
+	var lines []string
+	current := words[0]
+	for _, w := range words[1:] {
+		if len([]rune(current))+1+len([]rune(w)) > width {
+			lines = append(lines, current)
+			current = w
+			continue
+		}
+		current += " " + w
+	}
+	return append(lines, current)
+}
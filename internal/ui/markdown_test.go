@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestRenderMarkdownPlain(t *testing.T) {
+	prev := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = prev })
+
+	src := "# Root cause\n\nThe pod **crashloops** because `DB_URL` is unset.\n\n- check the secret\n- restart\n\n```yaml\nenv:\n  - name: DB_URL\n```\n> rerun ktl analyze afterwards\n"
+	got := RenderMarkdown(src, 100)
+	for _, want := range []string{
+		"ROOT CAUSE\n",
+		"The pod crashloops because DB_URL is unset.\n",
+		"  • check the secret\n",
+		"  yaml\n",
+		"    env:\n",
+		"      - name: DB_URL\n",
+		"│ rerun ktl analyze afterwards\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "```") || strings.Contains(got, "**") {
+		t.Fatalf("markdown markers should be stripped:\n%s", got)
+	}
+}
+
+func TestRenderMarkdownWrapsProse(t *testing.T) {
+	prev := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = prev })
+
+	got := RenderMarkdown("- "+strings.Repeat("word ", 20), 30)
+	lines := strings.Split(strings.TrimRight(got, "\n"), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected wrapped bullet, got %q", got)
+	}
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, "    word") {
+			t.Fatalf("continuation lines should align with the bullet text, got %q", line)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// File: internal/ui/picker.go
+// Brief: Internal ui package implementation for 'fuzzy picker'.
+
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ErrPickerCancelled is returned when the user aborts a picker with Esc or Ctrl-C.
+var ErrPickerCancelled = errors.New("selection cancelled")
+
+// PickerMaxRows bounds how many candidates the picker draws at once.
+const PickerMaxRows = 10
+
+// Picker is an interactive type-to-filter list. Filter returns the candidates for the current
+// query in display order.
+type Picker struct {
+	Prompt string
+	Query  string
+	Filter func(query string) []string
+	// Current is labelled "(current)" in the list (e.g. the active context).
+	Current string
+
+	items    []string
+	total    int
+	selected int
+	drawn    int
+}
+
+// Run draws the picker on out (normally stderr so stdout stays scriptable) and reads keys from in,
+// which must be a terminal. It returns the chosen candidate.
+func (p *Picker) Run(in *os.File, out io.Writer) (string, error) {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("interactive picker requires a terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(fd, state)
+
+	p.refresh()
+	p.render(out)
+	buf := make([]byte, 16)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			p.clear(out)
+			return "", err
+		}
+		done, choice, err := p.HandleKeys(buf[:n])
+		if done || err != nil {
+			p.clear(out)
+			return choice, err
+		}
+		p.render(out)
+	}
+}
+
+// HandleKeys applies raw terminal input to the picker. It reports done when a candidate was
+// chosen (Enter) or the picker was cancelled.
+func (p *Picker) HandleKeys(keys []byte) (bool, string, error) {
+	if p.items == nil && p.total == 0 {
+		p.refresh()
+	}
+	for i := 0; i < len(keys); i++ {
+		switch b := keys[i]; {
+		case b == '\r' || b == '\n':
+			if len(p.items) == 0 {
+				continue
+			}
+			return true, p.items[p.selected], nil
+		case b == 3: // Ctrl-C
+			return true, "", ErrPickerCancelled
+		case b == 27: // Esc or an arrow-key sequence
+			if i+2 < len(keys) && keys[i+1] == '[' {
+				switch keys[i+2] {
+				case 'A':
+					p.move(-1)
+				case 'B':
+					p.move(1)
+				}
+				i += 2
+				continue
+			}
+			return true, "", ErrPickerCancelled
+		case b == 16: // Ctrl-P
+			p.move(-1)
+		case b == 14: // Ctrl-N
+			p.move(1)
+		case b == 127 || b == 8: // Backspace
+			if q := []rune(p.Query); len(q) > 0 {
+				p.Query = string(q[:len(q)-1])
+				p.refresh()
+			}
+		case b == 21: // Ctrl-U
+			p.Query = ""
+			p.refresh()
+		case b >= 32 && b < 127:
+			p.Query += string(rune(b))
+			p.refresh()
+		}
+	}
+	return false, "", nil
+}
+
+// Items returns the candidates matching the current query.
+func (p *Picker) Items() []string {
+	return p.items
+}
+
+// Selected returns the highlighted candidate.
+func (p *Picker) Selected() string {
+	if p.selected < len(p.items) {
+		return p.items[p.selected]
+	}
+	return ""
+}
+
+func (p *Picker) refresh() {
+	if p.Filter != nil {
+		p.items = p.Filter(p.Query)
+		if p.total == 0 {
+			p.total = len(p.Filter(""))
+		}
+	}
+	p.selected = 0
+}
+
+func (p *Picker) move(delta int) {
+	if len(p.items) == 0 {
+		return
+	}
+	p.selected = (p.selected + delta + len(p.items)) % len(p.items)
+}
+
+func (p *Picker) clear(out io.Writer) {
+	// Move to the prompt line and erase everything below it.
+	if p.drawn > 0 {
+		fmt.Fprintf(out, "\r\033[%dA", p.drawn)
+	}
+	fmt.Fprint(out, "\r\033[J")
+	p.drawn = 0
+}
+
+func (p *Picker) render(out io.Writer) {
+	p.clear(out)
+	start := 0
+	if p.selected >= PickerMaxRows {
+		start = p.selected - PickerMaxRows + 1
+	}
+	end := start + PickerMaxRows
+	if end > len(p.items) {
+		end = len(p.items)
+	}
+	for i := start; i < end; i++ {
+		cursor := "  "
+		if i == p.selected {
+			cursor = "> "
+		}
+		line := cursor + p.items[i]
+		if p.items[i] == p.Current && p.Current != "" {
+			line += " (current)"
+		}
+		if i == p.selected {
+			line = "\033[1m" + line + "\033[0m"
+		}
+		fmt.Fprintf(out, "%s\r\n", line)
+	}
+	fmt.Fprintf(out, "  %d/%d\r\n", len(p.items), p.total)
+	p.drawn = end - start + 1
+	fmt.Fprintf(out, "%s %s", p.Prompt, p.Query)
+}
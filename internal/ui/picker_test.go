@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPickerFiltersAndSelects(t *testing.T) {
+	all := []string{"dev", "prod-eu", "prod-us"}
+	p := &Picker{Filter: func(q string) []string {
+		var out []string
+		for _, item := range all {
+			if strings.Contains(item, q) {
+				out = append(out, item)
+			}
+		}
+		return out
+	}}
+	if done, _, _ := p.HandleKeys([]byte("prod")); done {
+		t.Fatalf("typing should not finish the picker")
+	}
+	if got := p.Items(); len(got) != 2 {
+		t.Fatalf("expected 2 filtered items, got %v", got)
+	}
+	p.HandleKeys([]byte("\x1b[B"))
+	if p.Selected() != "prod-us" {
+		t.Fatalf("expected down arrow to select prod-us, got %q", p.Selected())
+	}
+	p.HandleKeys([]byte{127, 127, 127, 127})
+	if p.Query != "" || len(p.Items()) != 3 {
+		t.Fatalf("expected backspace to clear the query, got %q %v", p.Query, p.Items())
+	}
+	done, choice, err := p.HandleKeys([]byte("eu\r"))
+	if !done || err != nil || choice != "prod-eu" {
+		t.Fatalf("expected prod-eu, got done=%v choice=%q err=%v", done, choice, err)
+	}
+	done, _, err = p.HandleKeys([]byte{27})
+	if !done || !errors.Is(err, ErrPickerCancelled) {
+		t.Fatalf("expected Esc to cancel, got done=%v err=%v", done, err)
+	}
+}
@@ -7,6 +7,8 @@ package ui
 import (
 	"fmt"
 	"io"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -14,10 +16,39 @@ import (
 // stop function is called. The stop function prints either "[done]"
 // or "[fail]" depending on the success flag.
 func StartSpinner(w io.Writer, message string) func(success bool) {
+	_, stop := StartSpinnerWithStatus(w, message)
+	return stop
+}
+
+// StartSpinnerWithStatus is StartSpinner plus a setter for a short status
+// suffix (e.g. "42/300") redrawn with the next frame.
+func StartSpinnerWithStatus(w io.Writer, message string) (func(status string), func(success bool)) {
 	frames := []rune{'|', '/', '-', '\\'}
 	done := make(chan struct{})
+	var (
+		mu      sync.Mutex
+		current string
+		width   int
+	)
+	line := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		text := message
+		if current != "" {
+			text += " (" + current + ")"
+		}
+		pad := ""
+		if n := len(text); n < width {
+			pad = strings.Repeat(" ", width-n)
+		} else {
+			width = n
+		}
+		return text + pad
+	}
+	finished := make(chan struct{})
 	go func() {
-		defer fmt.Fprintf(w, "\r%s    \r", message)
+		defer close(finished)
+		defer fmt.Fprintf(w, "\r%s    \r", line())
 		ticker := time.NewTicker(120 * time.Millisecond)
 		defer ticker.Stop()
 		idx := 0
@@ -26,21 +57,29 @@ func StartSpinner(w io.Writer, message string) func(success bool) {
 			case <-done:
 				return
 			case <-ticker.C:
-				fmt.Fprintf(w, "\r%s %c", message, frames[idx])
+				fmt.Fprintf(w, "\r%s %c", line(), frames[idx])
 				idx = (idx + 1) % len(frames)
 			}
 		}
 	}()
-	return func(success bool) {
+	setStatus := func(status string) {
+		mu.Lock()
+		current = status
+		mu.Unlock()
+	}
+	stop := func(success bool) {
 		select {
 		case <-done:
 		default:
 			close(done)
 		}
+		<-finished
 		status := "[done]"
 		if !success {
 			status = "[fail]"
 		}
-		fmt.Fprintf(w, "\r%s %s\n", message, status)
+		setStatus("")
+		fmt.Fprintf(w, "\r%s %s\n", line(), status)
 	}
+	return setStatus, stop
 }
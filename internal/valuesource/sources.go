@@ -0,0 +1,249 @@
+// File: internal/valuesource/sources.go
+// Brief: exec, http, Consul and etcd value source implementations.
+
+package valuesource
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"github.com/kubekattle/ktl/internal/netconfig"
+)
+
+// maxValueBytes bounds a single looked-up value.
+const maxValueBytes = 1 << 20
+
+type execSource struct {
+	command []string
+	prefix  string
+}
+
+func newExecSource(sc appconfig.ValueSource) (*execSource, error) {
+	if len(sc.Command) == 0 || strings.TrimSpace(sc.Command[0]) == "" {
+		return nil, fmt.Errorf("exec source needs a command")
+	}
+	return &execSource{command: sc.Command, prefix: sc.Prefix}, nil
+}
+
+func (s *execSource) Lookup(ctx context.Context, key string) (string, error) {
+	key = s.prefix + key
+	args := append(append([]string(nil), s.command[1:]...), key)
+	cmd := exec.CommandContext(ctx, s.command[0], args...)
+	cmd.Env = append(os.Environ(), "KTL_VALUE_KEY="+key)
+	// Do not wait for grandchildren holding stdout open once the timeout killed the command.
+	cmd.WaitDelay = time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", s.command[0], err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", s.command[0], err)
+	}
+	if stdout.Len() > maxValueBytes {
+		return "", fmt.Errorf("%s: output exceeds %d bytes", s.command[0], maxValueBytes)
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+type httpSource struct {
+	url     string
+	prefix  string
+	headers map[string]string
+	token   string
+	client  *http.Client
+}
+
+func newHTTPSource(sc appconfig.ValueSource) (*httpSource, error) {
+	if strings.TrimSpace(sc.URL) == "" {
+		return nil, fmt.Errorf("http source needs a url")
+	}
+	return &httpSource{
+		url:     strings.TrimSpace(sc.URL),
+		prefix:  sc.Prefix,
+		headers: sc.Headers,
+		token:   envToken(sc.TokenEnv),
+		client:  netconfig.HTTPClient(0),
+	}, nil
+}
+
+func (s *httpSource) Lookup(ctx context.Context, key string) (string, error) {
+	key = s.prefix + key
+	target := s.url
+	if strings.Contains(target, "{key}") {
+		target = strings.ReplaceAll(target, "{key}", url.PathEscape(key))
+	} else {
+		target = strings.TrimRight(target, "/") + "/" + escapeKeyPath(key)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	body, err := doRequest(s.client, req)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(body), "\r\n"), nil
+}
+
+type consulSource struct {
+	address string
+	prefix  string
+	token   string
+	client  *http.Client
+}
+
+func newConsulSource(sc appconfig.ValueSource) (*consulSource, error) {
+	address := strings.TrimSpace(sc.Address)
+	if address == "" {
+		address = strings.TrimSpace(os.Getenv("CONSUL_HTTP_ADDR"))
+	}
+	if address == "" {
+		address = "http://127.0.0.1:8500"
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	tokenEnv := sc.TokenEnv
+	if strings.TrimSpace(tokenEnv) == "" {
+		tokenEnv = "CONSUL_HTTP_TOKEN"
+	}
+	return &consulSource{
+		address: strings.TrimRight(address, "/"),
+		prefix:  sc.Prefix,
+		token:   envToken(tokenEnv),
+		client:  netconfig.HTTPClient(0),
+	}, nil
+}
+
+func (s *consulSource) Lookup(ctx context.Context, key string) (string, error) {
+	target := s.address + "/v1/kv/" + escapeKeyPath(strings.TrimLeft(s.prefix+key, "/")) + "?raw"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+	if s.token != "" {
+		req.Header.Set("X-Consul-Token", s.token)
+	}
+	body, err := doRequest(s.client, req)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// etcdSource reads keys through the etcd v3 JSON gateway (/v3/kv/range).
+type etcdSource struct {
+	address string
+	prefix  string
+	token   string
+	client  *http.Client
+}
+
+func newEtcdSource(sc appconfig.ValueSource) (*etcdSource, error) {
+	address := strings.TrimSpace(sc.Address)
+	if address == "" {
+		address = "http://127.0.0.1:2379"
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	return &etcdSource{
+		address: strings.TrimRight(address, "/"),
+		prefix:  sc.Prefix,
+		token:   envToken(sc.TokenEnv),
+		client:  netconfig.HTTPClient(0),
+	}, nil
+}
+
+func (s *etcdSource) Lookup(ctx context.Context, key string) (string, error) {
+	payload, _ := json.Marshal(map[string]string{"key": base64.StdEncoding.EncodeToString([]byte(s.prefix + key))})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.address+"/v3/kv/range", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", s.token)
+	}
+	body, err := doRequest(s.client, req)
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		KVs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("decode etcd response: %w", err)
+	}
+	if len(resp.KVs) == 0 {
+		return "", ErrNotFound
+	}
+	val, err := base64.StdEncoding.DecodeString(resp.KVs[0].Value)
+	if err != nil {
+		return "", fmt.Errorf("decode etcd value: %w", err)
+	}
+	return string(val), nil
+}
+
+func doRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	if err := netconfig.CheckURL(req.URL.String()); err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", req.Method, req.URL.Redacted(), resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxValueBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxValueBytes {
+		return nil, fmt.Errorf("%s %s: response exceeds %d bytes", req.Method, req.URL.Redacted(), maxValueBytes)
+	}
+	return body, nil
+}
+
+// escapeKeyPath escapes each segment of a slash-separated key.
+func escapeKeyPath(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+func envToken(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	return strings.TrimSpace(os.Getenv(name))
+}
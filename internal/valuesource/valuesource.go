@@ -0,0 +1,343 @@
+// File: internal/valuesource/valuesource.go
+// Brief: valuefrom:// references resolved through named value sources from .ktl.yaml.
+
+// Package valuesource resolves valuefrom://<source>/<key> references in chart values through the
+// value sources configured under valueSources in .ktl.yaml (exec commands, HTTP endpoints, Consul
+// and etcd KV). Lookups are bounded by a per-source timeout and cached for the invocation.
+package valuesource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+	"sigs.k8s.io/yaml"
+)
+
+// Scheme prefixes a value source reference.
+const Scheme = "valuefrom://"
+
+const defaultTimeout = 10 * time.Second
+
+// Source is a value source: it looks up one key and returns its raw value. Implementations
+// return ErrNotFound (possibly wrapped) for missing keys.
+type Source interface {
+	Lookup(ctx context.Context, key string) (string, error)
+}
+
+// ErrNotFound reports a key the source does not have.
+var ErrNotFound = errors.New("key not found")
+
+// Ref is a parsed valuefrom:// reference.
+type Ref struct {
+	Source string
+	Key    string
+}
+
+func (r Ref) String() string {
+	return Scheme + r.Source + "/" + r.Key
+}
+
+// IsRef reports whether v is a valuefrom:// reference.
+func IsRef(v string) bool {
+	return strings.HasPrefix(strings.TrimSpace(v), Scheme)
+}
+
+// ParseRef parses valuefrom://<source>/<key>. The key may contain slashes.
+func ParseRef(v string) (Ref, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(v), Scheme)
+	source, key, _ := strings.Cut(rest, "/")
+	source = strings.TrimSpace(source)
+	key = strings.TrimSpace(key)
+	if source == "" || key == "" {
+		return Ref{}, fmt.Errorf("%s: expected valuefrom://<source>/<key>", strings.TrimSpace(v))
+	}
+	return Ref{Source: source, Key: key}, nil
+}
+
+// FindRefs returns the valuefrom:// references in values, sorted and deduplicated.
+func FindRefs(values interface{}) []string {
+	seen := map[string]struct{}{}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch typed := v.(type) {
+		case map[string]interface{}:
+			for _, child := range typed {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range typed {
+				walk(child)
+			}
+		case string:
+			if IsRef(typed) {
+				seen[strings.TrimSpace(typed)] = struct{}{}
+			}
+		}
+	}
+	walk(values)
+	out := make([]string, 0, len(seen))
+	for ref := range seen {
+		out = append(out, ref)
+	}
+	sort.Strings(out)
+	return out
+}
+
+type source struct {
+	impl    Source
+	timeout time.Duration
+	// ttl < 0 caches for the lifetime of the resolver; 0 disables caching.
+	ttl    time.Duration
+	format string
+}
+
+type cacheEntry struct {
+	done    chan struct{}
+	val     interface{}
+	err     error
+	expires time.Time
+}
+
+// Resolver resolves references against configured sources. It is safe for concurrent use, so one
+// resolver can be shared by every release of a stack; concurrent lookups of the same reference
+// share a single call.
+type Resolver struct {
+	sources map[string]source
+	now     func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*cacheEntry
+}
+
+// New builds a resolver from the valueSources section of the app config.
+func New(cfg map[string]appconfig.ValueSource) (*Resolver, error) {
+	r := &Resolver{sources: map[string]source{}, now: time.Now, cache: map[string]*cacheEntry{}}
+	for name, sc := range cfg {
+		name = strings.TrimSpace(name)
+		if name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("value source name %q is invalid", name)
+		}
+		src, err := newSource(name, sc)
+		if err != nil {
+			return nil, err
+		}
+		r.sources[name] = src
+	}
+	return r, nil
+}
+
+func newSource(name string, sc appconfig.ValueSource) (source, error) {
+	src := source{timeout: defaultTimeout, ttl: -1, format: strings.ToLower(strings.TrimSpace(sc.Format))}
+	if v := strings.TrimSpace(sc.Timeout); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return source{}, fmt.Errorf("value source %q: invalid timeout %q", name, v)
+		}
+		src.timeout = d
+	}
+	if v := strings.TrimSpace(sc.CacheTTL); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return source{}, fmt.Errorf("value source %q: invalid cacheTTL %q", name, v)
+		}
+		src.ttl = d
+	}
+	switch src.format {
+	case "", "text", "json", "yaml":
+	default:
+		return source{}, fmt.Errorf("value source %q: unsupported format %q (expected text|json|yaml)", name, sc.Format)
+	}
+	var err error
+	switch typ := strings.ToLower(strings.TrimSpace(sc.Type)); typ {
+	case "exec":
+		src.impl, err = newExecSource(sc)
+	case "http":
+		src.impl, err = newHTTPSource(sc)
+	case "consul":
+		src.impl, err = newConsulSource(sc)
+	case "etcd":
+		src.impl, err = newEtcdSource(sc)
+	case "":
+		err = fmt.Errorf("missing type")
+	default:
+		err = fmt.Errorf("unsupported type %q (expected exec|http|consul|etcd)", typ)
+	}
+	if err != nil {
+		return source{}, fmt.Errorf("value source %q: %w", name, err)
+	}
+	return src, nil
+}
+
+// Names lists the configured source names.
+func (r *Resolver) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.sources))
+	for name := range r.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve looks up a single reference and decodes it according to the source's format.
+func (r *Resolver) Resolve(ctx context.Context, ref string) (interface{}, error) {
+	parsed, err := ParseRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, fmt.Errorf("%s: no value sources configured (add valueSources to .ktl.yaml)", parsed)
+	}
+	src, ok := r.sources[parsed.Source]
+	if !ok {
+		return nil, fmt.Errorf("%s: value source %q is not configured", parsed, parsed.Source)
+	}
+	key := parsed.String()
+
+	r.mu.Lock()
+	if e, ok := r.cache[key]; ok {
+		select {
+		case <-e.done:
+			if e.err == nil && (e.expires.IsZero() || r.now().Before(e.expires)) {
+				r.mu.Unlock()
+				return cloneValue(e.val), nil
+			}
+		default:
+			r.mu.Unlock()
+			select {
+			case <-e.done:
+				return cloneValue(e.val), e.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	e := &cacheEntry{done: make(chan struct{})}
+	if src.ttl != 0 {
+		r.cache[key] = e
+	}
+	r.mu.Unlock()
+
+	e.val, e.err = r.lookup(ctx, src, parsed)
+	if e.err == nil && src.ttl > 0 {
+		e.expires = r.now().Add(src.ttl)
+	}
+	close(e.done)
+	return cloneValue(e.val), e.err
+}
+
+// cloneValue copies decoded maps and lists so releases sharing a cached value cannot modify each
+// other's values.
+func cloneValue(v interface{}) interface{} {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(typed))
+		for k, child := range typed {
+			out[k] = cloneValue(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(typed))
+		for i, child := range typed {
+			out[i] = cloneValue(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func (r *Resolver) lookup(ctx context.Context, src source, ref Ref) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, src.timeout)
+	defer cancel()
+	raw, err := src.impl.Lookup(ctx, ref.Key)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s: timed out after %s", ref, src.timeout)
+		}
+		return nil, fmt.Errorf("%s: %w", ref, err)
+	}
+	switch src.format {
+	case "json":
+		var v interface{}
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return nil, fmt.Errorf("%s: decode json: %w", ref, err)
+		}
+		return v, nil
+	case "yaml":
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(raw), &v); err != nil {
+			return nil, fmt.Errorf("%s: decode yaml: %w", ref, err)
+		}
+		return v, nil
+	default:
+		return raw, nil
+	}
+}
+
+// ResolveValues replaces every valuefrom:// reference in vals with its looked-up value. A
+// reference must be the whole value.
+func (r *Resolver) ResolveValues(ctx context.Context, vals map[string]interface{}) error {
+	_, err := r.walk(ctx, "", vals)
+	return err
+}
+
+func (r *Resolver) walk(ctx context.Context, path string, v interface{}) (interface{}, error) {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		for k, child := range typed {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			next, err := r.walk(ctx, childPath, child)
+			if err != nil {
+				return nil, err
+			}
+			typed[k] = next
+		}
+		return typed, nil
+	case []interface{}:
+		for i, child := range typed {
+			next, err := r.walk(ctx, fmt.Sprintf("%s[%d]", path, i), child)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = next
+		}
+		return typed, nil
+	case string:
+		if !IsRef(typed) {
+			return typed, nil
+		}
+		out, err := r.Resolve(ctx, typed)
+		if err != nil {
+			return nil, fmt.Errorf("values %s: %w", path, err)
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// LoadFromApp builds a resolver from ~/.ktl/config.yaml and the .ktl.yaml of the repository that
+// contains dir. It returns nil when no value sources are configured.
+func LoadFromApp(ctx context.Context, dir string) (*Resolver, error) {
+	cfg, err := appconfig.Load(ctx, appconfig.DefaultGlobalPath(), appconfig.DefaultRepoPath(appconfig.FindRepoRoot(dir)))
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.ValueSources) == 0 {
+		return nil, nil
+	}
+	return New(cfg.ValueSources)
+}
@@ -0,0 +1,186 @@
+package valuesource
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/internal/appconfig"
+)
+
+func TestParseRef(t *testing.T) {
+	ref, err := ParseRef("valuefrom://consul/apps/api/replicas")
+	if err != nil || ref.Source != "consul" || ref.Key != "apps/api/replicas" {
+		t.Fatalf("unexpected ref %+v, %v", ref, err)
+	}
+	for _, bad := range []string{"valuefrom://consul", "valuefrom:///key", "valuefrom://"} {
+		if _, err := ParseRef(bad); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestResolveValues_HTTPAndConsulWithCache(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch {
+		case r.URL.Path == "/api/values/db-host":
+			if r.Header.Get("Authorization") != "Bearer tok" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("db.internal\n"))
+		case r.URL.Path == "/v1/kv/prod/api/limits":
+			if r.Header.Get("X-Consul-Token") != "acl" || r.URL.RawQuery != "raw" {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"cpu": "500m", "replicas": 3}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("INVENTORY_TOKEN", "tok")
+	t.Setenv("CONSUL_HTTP_TOKEN", "acl")
+
+	r, err := New(map[string]appconfig.ValueSource{
+		"inventory": {Type: "http", URL: srv.URL + "/api/values/{key}", TokenEnv: "INVENTORY_TOKEN"},
+		"consul":    {Type: "consul", Address: srv.URL, Prefix: "prod/", Format: "json"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	vals := map[string]interface{}{
+		"db":        map[string]interface{}{"host": "valuefrom://inventory/db-host"},
+		"hosts":     []interface{}{"valuefrom://inventory/db-host", "static"},
+		"resources": "valuefrom://consul/api/limits",
+	}
+	if err := r.ResolveValues(context.Background(), vals); err != nil {
+		t.Fatalf("ResolveValues: %v", err)
+	}
+	if got := vals["db"].(map[string]interface{})["host"]; got != "db.internal" {
+		t.Fatalf("unexpected db.host %#v", got)
+	}
+	if got := vals["hosts"].([]interface{}); got[0] != "db.internal" || got[1] != "static" {
+		t.Fatalf("unexpected hosts %#v", got)
+	}
+	res := vals["resources"].(map[string]interface{})
+	if res["cpu"] != "500m" || res["replicas"] != float64(3) {
+		t.Fatalf("expected typed json value, got %#v", res)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Fatalf("expected repeated references to be served from cache, got %d requests", n)
+	}
+
+	res["cpu"] = "mutated"
+	again, err := r.Resolve(context.Background(), "valuefrom://consul/api/limits")
+	if err != nil || again.(map[string]interface{})["cpu"] != "500m" {
+		t.Fatalf("cached values must not be shared between callers: %#v, %v", again, err)
+	}
+
+	err = r.ResolveValues(context.Background(), map[string]interface{}{"x": map[string]interface{}{"y": "valuefrom://inventory/missing"}})
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "values x.y") {
+		t.Fatalf("expected a not-found error with the values path, got %v", err)
+	}
+	err = r.ResolveValues(context.Background(), map[string]interface{}{"x": "valuefrom://nope/key"})
+	if err == nil || !strings.Contains(err.Error(), `value source "nope" is not configured`) {
+		t.Fatalf("expected an unknown source error, got %v", err)
+	}
+	var unset *Resolver
+	err = unset.ResolveValues(context.Background(), map[string]interface{}{"x": "valuefrom://inventory/db-host"})
+	if err == nil || !strings.Contains(err.Error(), "no value sources configured") {
+		t.Fatalf("expected an error without value sources, got %v", err)
+	}
+}
+
+func TestResolve_EtcdGateway(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Key string `json:"key"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		key, _ := base64.StdEncoding.DecodeString(req.Key)
+		if r.URL.Path != "/v3/kv/range" || string(key) != "/config/region" {
+			_, _ = w.Write([]byte(`{"header": {}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"kvs": [{"value": "` + base64.StdEncoding.EncodeToString([]byte("eu-west-1")) + `"}]}`))
+	}))
+	defer srv.Close()
+	r, err := New(map[string]appconfig.ValueSource{"etcd": {Type: "etcd", Address: srv.URL, Prefix: "/config/"}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	got, err := r.Resolve(context.Background(), "valuefrom://etcd/region")
+	if err != nil || got != "eu-west-1" {
+		t.Fatalf("unexpected etcd value %#v, %v", got, err)
+	}
+	if _, err := r.Resolve(context.Background(), "valuefrom://etcd/missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestResolve_ExecTimeoutAndTTL(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "lookup.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nif [ \"$1\" = slow ]; then sleep 5; fi\necho \"$KTL_VALUE_KEY-$(date +%s%N)\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	r, err := New(map[string]appconfig.ValueSource{
+		"script": {Type: "exec", Command: []string{script}, Timeout: "200ms", CacheTTL: "1m"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return clock }
+
+	first, err := r.Resolve(context.Background(), "valuefrom://script/region")
+	if err != nil || !strings.HasPrefix(first.(string), "region-") {
+		t.Fatalf("unexpected exec value %#v, %v", first, err)
+	}
+	cached, _ := r.Resolve(context.Background(), "valuefrom://script/region")
+	if cached != first {
+		t.Fatalf("expected a cached value within the TTL, got %v then %v", first, cached)
+	}
+	clock = clock.Add(2 * time.Minute)
+	fresh, _ := r.Resolve(context.Background(), "valuefrom://script/region")
+	if fresh == first {
+		t.Fatalf("expected a new lookup after the TTL expired")
+	}
+
+	if _, err := r.Resolve(context.Background(), "valuefrom://script/slow"); err == nil || !strings.Contains(err.Error(), "timed out after 200ms") {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+}
+
+func TestNew_RejectsInvalidSources(t *testing.T) {
+	cases := map[string]appconfig.ValueSource{
+		"no type":     {},
+		"bad type":    {Type: "zookeeper"},
+		"no command":  {Type: "exec"},
+		"no url":      {Type: "http"},
+		"bad timeout": {Type: "consul", Timeout: "soon"},
+		"bad format":  {Type: "consul", Format: "toml"},
+	}
+	for name, sc := range cases {
+		if _, err := New(map[string]appconfig.ValueSource{"src": sc}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
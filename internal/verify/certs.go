@@ -0,0 +1,296 @@
+package verify
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	CertOK       = "ok"
+	CertExpiring = "expiring"
+	CertExpired  = "expired"
+	CertNotReady = "not-ready"
+	CertMismatch = "mismatch"
+	CertInvalid  = "invalid"
+)
+
+// CertCheck is one certificate source found in a set of objects: a TLS Secret, a cert-manager
+// Certificate, or one webhook's caBundle.
+type CertCheck struct {
+	Kind      string     `json:"kind"`
+	Namespace string     `json:"namespace,omitempty"`
+	Name      string     `json:"name"`
+	Source    string     `json:"source"`
+	Subject   string     `json:"subject,omitempty"`
+	NotAfter  *time.Time `json:"notAfter,omitempty"`
+	Status    string     `json:"status"`
+	Message   string     `json:"message,omitempty"`
+}
+
+// Failing reports whether the check needs attention.
+func (c CertCheck) Failing() bool {
+	return c.Status != CertOK
+}
+
+// InspectCertificates checks the TLS Secrets, cert-manager Certificates, and admission webhook
+// caBundles in objs, the same sources the k8s/*certificate* and k8s/webhook_ca_bundle_* rules
+// evaluate. Certificates expiring within warnWithin of now are reported as expiring.
+func InspectCertificates(objs []map[string]any, now time.Time, warnWithin time.Duration) []CertCheck {
+	secrets := map[string]map[string]any{}
+	certSecrets := map[string]string{}
+	for _, obj := range objs {
+		subj := subjectFromObject(obj)
+		switch subj.Kind {
+		case "Secret":
+			secrets[subj.Namespace+"/"+subj.Name] = obj
+		case "Certificate":
+			if name := nestedString(obj, "spec", "secretName"); name != "" {
+				certSecrets[subj.Namespace+"/"+subj.Name] = subj.Namespace + "/" + name
+			}
+		}
+	}
+
+	var out []CertCheck
+	for _, obj := range objs {
+		subj := subjectFromObject(obj)
+		base := CertCheck{Kind: subj.Kind, Namespace: subj.Namespace, Name: subj.Name}
+		switch subj.Kind {
+		case "Secret":
+			if nestedString(obj, "type") != "kubernetes.io/tls" {
+				continue
+			}
+			check := base
+			check.Source = "tls.crt"
+			out = append(out, inspectBundle(check, secretData(obj, "tls.crt"), now, warnWithin))
+		case "Certificate":
+			if !strings.HasPrefix(nestedString(obj, "apiVersion"), "cert-manager.io/") {
+				continue
+			}
+			out = append(out, inspectCertManager(base, obj, now, warnWithin))
+		case "ValidatingWebhookConfiguration", "MutatingWebhookConfiguration":
+			annotations := annotationsFromObject(obj)
+			servingKey := strings.TrimSpace(annotations["cert-manager.io/inject-ca-from-secret"])
+			if ref := strings.TrimSpace(annotations["cert-manager.io/inject-ca-from"]); servingKey == "" && ref != "" {
+				servingKey = certSecrets[ref]
+			}
+			var servingCA []byte
+			if secret, ok := secrets[servingKey]; ok {
+				servingCA = secretData(secret, "ca.crt")
+			}
+			webhooks, _ := obj["webhooks"].([]any)
+			for _, entry := range webhooks {
+				webhook, _ := entry.(map[string]any)
+				check := base
+				check.Source = "webhook " + nestedString(webhook, "name")
+				raw := nestedString(webhook, "clientConfig", "caBundle")
+				if raw == "" {
+					// No bundle means the API server uses its system roots; only flag it
+					// when cert-manager was asked to inject one.
+					if len(servingCA) > 0 {
+						check.Status = CertMismatch
+						check.Message = fmt.Sprintf("caBundle is empty; expected the ca.crt of Secret %s", servingKey)
+						out = append(out, check)
+					}
+					continue
+				}
+				bundle, err := base64.StdEncoding.DecodeString(raw)
+				if err != nil {
+					bundle = nil
+				}
+				check = inspectBundle(check, bundle, now, warnWithin)
+				if check.Status == CertOK && len(servingCA) > 0 && !bundleContains(bundle, servingCA) {
+					check.Status = CertMismatch
+					check.Message = fmt.Sprintf("caBundle does not contain the ca.crt of Secret %s", servingKey)
+				}
+				out = append(out, check)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].Source < out[j].Source
+	})
+	return out
+}
+
+// inspectBundle reports the earliest-expiring certificate in a PEM bundle, so an expired
+// intermediate is not hidden behind a healthy leaf.
+func inspectBundle(check CertCheck, bundle []byte, now time.Time, warnWithin time.Duration) CertCheck {
+	certs, err := parsePEMCertificates(bundle)
+	if err != nil {
+		check.Status = CertInvalid
+		check.Message = err.Error()
+		return check
+	}
+	earliest := certs[0]
+	for _, c := range certs[1:] {
+		if c.NotAfter.Before(earliest.NotAfter) {
+			earliest = c
+		}
+	}
+	notAfter := earliest.NotAfter.UTC()
+	check.Subject = earliest.Subject.CommonName
+	check.NotAfter = &notAfter
+	check.Status, check.Message = expiryStatus(notAfter, now, warnWithin)
+	return check
+}
+
+func inspectCertManager(check CertCheck, obj map[string]any, now time.Time, warnWithin time.Duration) CertCheck {
+	check.Source = "status.notAfter"
+	check.Subject = nestedString(obj, "spec", "commonName")
+	if check.Subject == "" {
+		if names, ok := nestedValue(obj, "spec", "dnsNames").([]any); ok && len(names) > 0 {
+			check.Subject, _ = names[0].(string)
+		}
+	}
+	readyStatus, readyMessage := "Unknown", ""
+	if conds, ok := nestedValue(obj, "status", "conditions").([]any); ok {
+		for _, entry := range conds {
+			cond, _ := entry.(map[string]any)
+			if nestedString(cond, "type") == "Ready" {
+				readyStatus = nestedString(cond, "status")
+				readyMessage = nestedString(cond, "message")
+			}
+		}
+	}
+	check.Status = CertOK
+	if raw := nestedString(obj, "status", "notAfter"); raw != "" {
+		notAfter, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			check.Status = CertInvalid
+			check.Message = fmt.Sprintf("status.notAfter %q: %v", raw, err)
+			return check
+		}
+		notAfter = notAfter.UTC()
+		check.NotAfter = &notAfter
+		check.Status, check.Message = expiryStatus(notAfter, now, warnWithin)
+	}
+	if readyStatus != "True" && check.Status != CertExpired {
+		if check.Status == CertOK {
+			check.Status = CertNotReady
+		}
+		msg := "Ready=" + readyStatus
+		if readyMessage != "" {
+			msg += ": " + readyMessage
+		}
+		if check.Message != "" {
+			msg = check.Message + "; " + msg
+		}
+		check.Message = msg
+	}
+	return check
+}
+
+func expiryStatus(notAfter, now time.Time, warnWithin time.Duration) (string, string) {
+	left := notAfter.Sub(now)
+	switch {
+	case left <= 0:
+		return CertExpired, fmt.Sprintf("expired %s ago", formatCertDays(-left))
+	case left < warnWithin:
+		return CertExpiring, fmt.Sprintf("expires in %s", formatCertDays(left))
+	default:
+		return CertOK, ""
+	}
+}
+
+func formatCertDays(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days == 0 {
+		return "less than a day"
+	}
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+func parsePEMCertificates(bundle []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := bytes.TrimSpace(bundle)
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificates found")
+	}
+	return certs, nil
+}
+
+// bundleContains reports whether every certificate in want is present in bundle.
+func bundleContains(bundle, want []byte) bool {
+	have, err := parsePEMCertificates(bundle)
+	if err != nil {
+		return false
+	}
+	wantCerts, err := parsePEMCertificates(want)
+	if err != nil {
+		return true
+	}
+	for _, w := range wantCerts {
+		found := false
+		for _, h := range have {
+			if h.Equal(w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// secretData returns a decoded Secret data key; objects come from the API (or rendered
+// manifests) with base64 values, and stringData is honoured for rendered Secrets.
+func secretData(obj map[string]any, key string) []byte {
+	if raw := nestedString(obj, "data", key); raw != "" {
+		if decoded, err := base64.StdEncoding.DecodeString(raw); err == nil {
+			return decoded
+		}
+	}
+	return []byte(nestedString(obj, "stringData", key))
+}
+
+func nestedValue(obj map[string]any, path ...string) any {
+	var cur any = obj
+	for _, p := range path {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = m[p]
+	}
+	return cur
+}
+
+func nestedString(obj map[string]any, path ...string) string {
+	s, _ := nestedValue(obj, path...).(string)
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,69 @@
+package verify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInspectCertificatesUsesRuleFixtures(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	fixture := func(rule, name string) []map[string]any {
+		return decodeFixture(t, verifyTestdata("internal", "verify", "rules", "builtin", "k8s", rule, "test", name))
+	}
+	var objs []map[string]any
+	objs = append(objs, fixture("tls_secret_certificate_expiring", "edge.yaml")...)
+	objs = append(objs, fixture("certmanager_certificate_expiring", "pass.yaml")...)
+	objs = append(objs, fixture("webhook_ca_bundle_mismatch", "edge.yaml")...)
+	objs = append(objs, map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "kubernetes.io/tls",
+		"metadata":   map[string]any{"name": "garbage", "namespace": "default"},
+		"data":       map[string]any{"tls.crt": "bm90IGEgY2VydA=="},
+	})
+
+	byKey := map[string]CertCheck{}
+	for _, c := range InspectCertificates(objs, now, 30*24*time.Hour) {
+		byKey[c.Kind+"/"+c.Name+"/"+c.Source] = c
+	}
+	want := map[string]string{
+		// The leaf is valid until 2124 but the bundled intermediate expired in 2022.
+		"Secret/chain-tls/tls.crt":                                                 CertExpired,
+		"Secret/defaults-webhook-tls/tls.crt":                                      CertOK,
+		"Secret/garbage/tls.crt":                                                   CertInvalid,
+		"Certificate/api/status.notAfter":                                          CertOK,
+		"Certificate/issuing/status.notAfter":                                      CertNotReady,
+		"Certificate/defaults-webhook/status.notAfter":                             CertNotReady,
+		"MutatingWebhookConfiguration/defaults/webhook fresh.defaults.example.com": CertOK,
+		"MutatingWebhookConfiguration/defaults/webhook stale.defaults.example.com": CertMismatch,
+	}
+	for key, status := range want {
+		got, ok := byKey[key]
+		if !ok {
+			t.Fatalf("missing check %s in %+v", key, byKey)
+		}
+		if got.Status != status {
+			t.Fatalf("%s: status %q (%s), want %q", key, got.Status, got.Message, status)
+		}
+	}
+	if len(byKey) != len(want) {
+		t.Fatalf("unexpected checks: %+v", byKey)
+	}
+	if c := byKey["Secret/chain-tls/tls.crt"]; c.Subject != "ktl-test-intermediate" || c.Message != "expired 882 days ago" {
+		t.Fatalf("unexpected chain check: %+v", c)
+	}
+
+	soon := now.Add(10 * 24 * time.Hour)
+	checks := InspectCertificates([]map[string]any{{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata":   map[string]any{"name": "soon", "namespace": "default"},
+		"status": map[string]any{
+			"notAfter":   soon.Format(time.RFC3339),
+			"conditions": []any{map[string]any{"type": "Ready", "status": "True"}},
+		},
+	}}, now, 30*24*time.Hour)
+	if len(checks) != 1 || checks[0].Status != CertExpiring || checks[0].Message != "expires in 10 days" {
+		t.Fatalf("unexpected expiring check: %+v", checks)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/kubekattle/ktl/internal/kube"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func CollectNamespacedObjects(ctx context.Context, client *kube.Client, namespace string) ([]map[string]any, error) {
@@ -116,5 +117,54 @@ func CollectNamespacedObjects(ctx context.Context, client *kube.Client, namespac
 		return nil, err
 	}
 
+	// cert-manager Certificates and webhook configurations feed the certificate rules. Both are
+	// optional: the CRD may not be installed and webhooks are cluster-scoped, so list failures
+	// (missing CRD, RBAC) are skipped rather than failing the whole collection.
+	if client.Dynamic != nil {
+		if list, err := client.Dynamic.Resource(certManagerCertificatesGVR).Namespace(namespace).List(ctx, opts); err == nil {
+			for _, item := range list.Items {
+				objs = append(objs, item.Object)
+			}
+		}
+	}
+	if list, err := client.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, opts); err == nil {
+		if err := addWebhooks("ValidatingWebhookConfiguration", list, namespace, addList); err != nil {
+			return nil, err
+		}
+	}
+	if list, err := client.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, opts); err == nil {
+		if err := addWebhooks("MutatingWebhookConfiguration", list, namespace, addList); err != nil {
+			return nil, err
+		}
+	}
+
 	return objs, nil
 }
+
+var certManagerCertificatesGVR = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+// addWebhooks keeps the webhook configurations with at least one webhook served from namespace.
+func addWebhooks(kind string, list any, namespace string, addList func(string, any, error) error) error {
+	raw, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return err
+	}
+	items, _ := m["items"].([]any)
+	var kept []any
+	for _, it := range items {
+		obj, _ := it.(map[string]any)
+		webhooks, _ := obj["webhooks"].([]any)
+		for _, entry := range webhooks {
+			webhook, _ := entry.(map[string]any)
+			if nestedString(webhook, "clientConfig", "service", "namespace") == namespace {
+				kept = append(kept, obj)
+				break
+			}
+		}
+	}
+	return addList(kind, map[string]any{"items": kept}, nil)
+}
@@ -0,0 +1,34 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.k8s as k8s_lib
+
+CxPolicy[result] {
+  document := input.document[i]
+  document.kind == "Certificate"
+  startswith(object.get(document, "apiVersion", ""), "cert-manager.io/")
+  metadata := document.metadata
+
+  notAfter := object.get(object.get(document, "status", {}), "notAfter", "")
+  notAfter != ""
+  time.parse_rfc3339_ns(notAfter) < time.now_ns() + k8s_lib.cert_expiry_window_ns
+
+  result := {
+    "documentId": document.id,
+    "resourceType": document.kind,
+    "resourceName": metadata.name,
+    "searchKey": sprintf("metadata.name={{%s}}.status.notAfter", [metadata.name]),
+    "issueType": "IncorrectValue",
+    "keyExpectedValue": sprintf("metadata.name={{%s}}.status.notAfter should be at least 30 days away", [metadata.name]),
+    "keyActualValue": sprintf("metadata.name={{%s}}.status.notAfter is %s (Ready=%s)", [metadata.name, notAfter, ready_status(document)]),
+    "searchLine": common_lib.build_search_line([], ["status", "notAfter"]),
+  }
+}
+
+ready_status(document) = status {
+  cond := document.status.conditions[_]
+  cond.type == "Ready"
+  status := cond.status
+} else = "Unknown" {
+  true
+}
@@ -0,0 +1,25 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.k8s as k8s_lib
+
+CxPolicy[result] {
+  document := input.document[i]
+  document.kind == "Secret"
+  object.get(document, "type", "") == "kubernetes.io/tls"
+  metadata := document.metadata
+
+  bundle := object.get(object.get(document, "data", {}), "tls.crt", "")
+  cert := k8s_lib.expiring_certs(bundle)[_]
+
+  result := {
+    "documentId": document.id,
+    "resourceType": document.kind,
+    "resourceName": metadata.name,
+    "searchKey": sprintf("metadata.name={{%s}}.data.tls.crt{{%s}}", [metadata.name, cert.Subject.CommonName]),
+    "issueType": "IncorrectValue",
+    "keyExpectedValue": sprintf("metadata.name={{%s}}.data.tls.crt certificates should be valid for at least 30 more days", [metadata.name]),
+    "keyActualValue": sprintf("metadata.name={{%s}}.data.tls.crt certificate CN=%s expires at %s", [metadata.name, cert.Subject.CommonName, cert.NotAfter]),
+    "searchLine": common_lib.build_search_line([], ["data", "tls.crt"]),
+  }
+}
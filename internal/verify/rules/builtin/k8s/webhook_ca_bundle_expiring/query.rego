@@ -0,0 +1,32 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.k8s as k8s_lib
+
+webhook_kind(kind) {
+  kind == "ValidatingWebhookConfiguration"
+}
+webhook_kind(kind) {
+  kind == "MutatingWebhookConfiguration"
+}
+
+CxPolicy[result] {
+  document := input.document[i]
+  webhook_kind(document.kind)
+  metadata := document.metadata
+
+  webhook := document.webhooks[w]
+  bundle := object.get(object.get(webhook, "clientConfig", {}), "caBundle", "")
+  cert := k8s_lib.expiring_certs(bundle)[_]
+
+  result := {
+    "documentId": document.id,
+    "resourceType": document.kind,
+    "resourceName": metadata.name,
+    "searchKey": sprintf("metadata.name={{%s}}.webhooks.name={{%s}}.clientConfig.caBundle", [metadata.name, webhook.name]),
+    "issueType": "IncorrectValue",
+    "keyExpectedValue": sprintf("webhooks.name={{%s}}.clientConfig.caBundle certificates should be valid for at least 30 more days", [webhook.name]),
+    "keyActualValue": sprintf("webhooks.name={{%s}}.clientConfig.caBundle certificate CN=%s expires at %s", [webhook.name, cert.Subject.CommonName, cert.NotAfter]),
+    "searchLine": common_lib.build_search_line(["webhooks", w], ["clientConfig", "caBundle"]),
+  }
+}
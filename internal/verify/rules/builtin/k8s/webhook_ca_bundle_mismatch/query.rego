@@ -0,0 +1,57 @@
+package Cx
+
+import data.generic.common as common_lib
+
+webhook_kind(kind) {
+  kind == "ValidatingWebhookConfiguration"
+}
+webhook_kind(kind) {
+  kind == "MutatingWebhookConfiguration"
+}
+
+# serving_secret resolves the Secret named by cert-manager's CA injection annotations:
+# inject-ca-from-secret points at the Secret, inject-ca-from at a Certificate whose
+# spec.secretName holds the keypair.
+serving_secret(metadata) = secret {
+  ref := split(metadata.annotations["cert-manager.io/inject-ca-from-secret"], "/")
+  secret := find_secret(ref[0], ref[1])
+} else = secret {
+  ref := split(metadata.annotations["cert-manager.io/inject-ca-from"], "/")
+  cert := input.document[_]
+  cert.kind == "Certificate"
+  object.get(cert.metadata, "namespace", "") == ref[0]
+  cert.metadata.name == ref[1]
+  secret := find_secret(ref[0], cert.spec.secretName)
+}
+
+find_secret(namespace, name) = secret {
+  secret := input.document[_]
+  secret.kind == "Secret"
+  object.get(secret.metadata, "namespace", "") == namespace
+  secret.metadata.name == name
+}
+
+CxPolicy[result] {
+  document := input.document[i]
+  webhook_kind(document.kind)
+  metadata := document.metadata
+
+  secret := serving_secret(metadata)
+  ca := object.get(object.get(secret, "data", {}), "ca.crt", "")
+  ca != ""
+
+  webhook := document.webhooks[w]
+  bundle := object.get(object.get(webhook, "clientConfig", {}), "caBundle", "")
+  bundle != ca
+
+  result := {
+    "documentId": document.id,
+    "resourceType": document.kind,
+    "resourceName": metadata.name,
+    "searchKey": sprintf("metadata.name={{%s}}.webhooks.name={{%s}}.clientConfig.caBundle", [metadata.name, webhook.name]),
+    "issueType": "IncorrectValue",
+    "keyExpectedValue": sprintf("webhooks.name={{%s}}.clientConfig.caBundle should equal Secret %s/%s ca.crt", [webhook.name, secret.metadata.namespace, secret.metadata.name]),
+    "keyActualValue": sprintf("webhooks.name={{%s}}.clientConfig.caBundle does not match Secret %s/%s ca.crt", [webhook.name, secret.metadata.namespace, secret.metadata.name]),
+    "searchLine": common_lib.build_search_line(["webhooks", w], ["clientConfig", "caBundle"]),
+  }
+}
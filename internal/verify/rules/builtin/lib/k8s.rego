@@ -131,3 +131,16 @@ get_search_key(array_vals) = searchKeyPath {
 } else = searchKeyPath {
     searchKeyPath := ""
 }
+
+# certificates within this window of NotAfter (or past it) are reported as expiring
+cert_expiry_window_ns := ((30 * 24) * 3600) * 1000000000
+
+# expiring_certs returns the parsed certificates in a base64 PEM/DER bundle that expire within
+# cert_expiry_window_ns; unparseable bundles yield no certificates
+expiring_certs(bundle) = certs {
+	parsed := crypto.x509.parse_certificates(bundle)
+	deadline := time.now_ns() + cert_expiry_window_ns
+	certs := [c | c := parsed[_]; time.parse_rfc3339_ns(c.NotAfter) < deadline]
+} else = [] {
+	true
+}
@@ -163,16 +163,20 @@ func severityRank(sev Severity) int {
 }
 
 func hasAtLeast(findings []Finding, min Severity) bool {
-	order := map[Severity]int{SeverityInfo: 0, SeverityLow: 1, SeverityMedium: 2, SeverityHigh: 3, SeverityCritical: 4}
-	minN := order[min]
 	for _, f := range findings {
-		if order[f.Severity] >= minN {
+		if f.Severity.AtLeast(min) {
 			return true
 		}
 	}
 	return false
 }
 
+// AtLeast reports whether s is as severe as min or more (an empty min matches everything).
+func (s Severity) AtLeast(min Severity) bool {
+	order := map[Severity]int{SeverityInfo: 0, SeverityLow: 1, SeverityMedium: 2, SeverityHigh: 3, SeverityCritical: 4}
+	return order[s] >= order[min]
+}
+
 func filterNamespacedFindings(findings []Finding) []Finding {
 	// KICS-style rules are mostly workload-focused, but enforce the "namespaced-only"
 	// scope by dropping findings that clearly reference cluster-scoped kinds.
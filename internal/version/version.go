@@ -3,6 +3,9 @@ package version
 import (
 	"fmt"
 	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
 )
 
 // These values are overridden at build time via -ldflags "-X ...".
@@ -13,22 +16,110 @@ var (
 	BuildDate    = "unknown" // RFC3339 UTC preferred
 )
 
+const (
+	helmModule     = "helm.sh/helm/v3"
+	clientGoModule = "k8s.io/client-go"
+)
+
+// KubeSkew is how many Kubernetes minor versions ktl supports on either side of the client-go
+// release it was built with.
+const KubeSkew = 1
+
 type Info struct {
-	Version      string
-	GitCommit    string
-	GitTreeState string
-	BuildDate    string
-	GoVersion    string
-	Platform     string
+	Version      string `json:"version"`
+	GitCommit    string `json:"gitCommit"`
+	GitTreeState string `json:"gitTreeState"`
+	BuildDate    string `json:"buildDate"`
+	GoVersion    string `json:"goVersion"`
+	Platform     string `json:"platform"`
+	// HelmVersion and ClientGoVersion are the versions of the embedded libraries.
+	HelmVersion     string `json:"helmVersion"`
+	ClientGoVersion string `json:"clientGoVersion"`
+	// Kubernetes is the API server range ktl is built and tested against.
+	Kubernetes KubeRange `json:"kubernetes"`
+}
+
+// KubeRange is an inclusive range of Kubernetes minor versions ("1.33").
+type KubeRange struct {
+	Min string `json:"min"`
+	Max string `json:"max"`
 }
 
 func Get() Info {
-	return Info{
-		Version:      Version,
-		GitCommit:    GitCommit,
-		GitTreeState: GitTreeState,
-		BuildDate:    BuildDate,
-		GoVersion:    runtime.Version(),
-		Platform:     fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+	info := Info{
+		Version:         Version,
+		GitCommit:       GitCommit,
+		GitTreeState:    GitTreeState,
+		BuildDate:       BuildDate,
+		GoVersion:       runtime.Version(),
+		Platform:        fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		HelmVersion:     "unknown",
+		ClientGoVersion: "unknown",
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range bi.Deps {
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			switch dep.Path {
+			case helmModule:
+				info.HelmVersion = dep.Version
+			case clientGoModule:
+				info.ClientGoVersion = dep.Version
+			}
+		}
+	}
+	info.Kubernetes = CompatibleKubeRange(info.ClientGoVersion)
+	return info
+}
+
+// CompatibleKubeRange derives the supported Kubernetes range from a client-go version: client-go
+// v0.N.x tracks Kubernetes 1.N, and ktl supports KubeSkew minors around it. It returns an empty
+// range for versions it cannot parse.
+func CompatibleKubeRange(clientGo string) KubeRange {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(clientGo), "v"), ".")
+	if len(parts) < 2 || parts[0] != "0" {
+		return KubeRange{}
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil || minor <= 0 {
+		return KubeRange{}
+	}
+	low := minor - KubeSkew
+	if low < 0 {
+		low = 0
+	}
+	return KubeRange{Min: fmt.Sprintf("1.%d", low), Max: fmt.Sprintf("1.%d", minor+KubeSkew)}
+}
+
+// CheckSkew compares a server's major/minor version (as reported by discovery; minor may carry
+// a "+" suffix) against r and returns a warning when it falls outside, or "" when it is in range
+// or cannot be judged.
+func (r KubeRange) CheckSkew(major, minor string) string {
+	lo, okLo := parseMinor(r.Min)
+	hi, okHi := parseMinor(r.Max)
+	if !okLo || !okHi || strings.TrimSpace(major) != "1" {
+		return ""
+	}
+	m, err := strconv.Atoi(strings.TrimRight(strings.TrimSpace(minor), "+"))
+	if err != nil {
+		return ""
+	}
+	switch {
+	case m < lo:
+		return fmt.Sprintf("server Kubernetes 1.%d is older than the supported range %s-%s; some features may fail", m, r.Min, r.Max)
+	case m > hi:
+		return fmt.Sprintf("server Kubernetes 1.%d is newer than the supported range %s-%s; upgrade ktl if you hit API errors", m, r.Min, r.Max)
+	default:
+		return ""
+	}
+}
+
+func parseMinor(v string) (int, bool) {
+	major, minor, ok := strings.Cut(strings.TrimSpace(v), ".")
+	if !ok || major != "1" {
+		return 0, false
 	}
+	n, err := strconv.Atoi(minor)
+	return n, err == nil
 }
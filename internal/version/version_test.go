@@ -0,0 +1,31 @@
+package version
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompatibleKubeRange(t *testing.T) {
+	if got := CompatibleKubeRange("v0.34.2"); got != (KubeRange{Min: "1.33", Max: "1.35"}) {
+		t.Fatalf("unexpected range %+v", got)
+	}
+	if got := CompatibleKubeRange("unknown"); got != (KubeRange{}) {
+		t.Fatalf("expected empty range, got %+v", got)
+	}
+}
+
+func TestCheckSkew(t *testing.T) {
+	r := KubeRange{Min: "1.33", Max: "1.35"}
+	if warn := r.CheckSkew("1", "34+"); warn != "" {
+		t.Fatalf("expected no warning, got %q", warn)
+	}
+	if warn := r.CheckSkew("1", "31"); !strings.Contains(warn, "older") {
+		t.Fatalf("expected older warning, got %q", warn)
+	}
+	if warn := r.CheckSkew("1", "36"); !strings.Contains(warn, "newer") {
+		t.Fatalf("expected newer warning, got %q", warn)
+	}
+	if warn := (KubeRange{}).CheckSkew("1", "10"); warn != "" {
+		t.Fatalf("expected no warning without a range, got %q", warn)
+	}
+}
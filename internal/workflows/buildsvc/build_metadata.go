@@ -0,0 +1,195 @@
+// File: internal/workflows/buildsvc/build_metadata.go
+// Brief: Internal buildsvc package implementation for injected build labels and args.
+
+package buildsvc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/pkg/buildkit"
+)
+
+// Standard OCI image labels ktl injects (see the OCI image-spec annotations).
+const (
+	labelCreated  = "org.opencontainers.image.created"
+	labelRevision = "org.opencontainers.image.revision"
+	labelSource   = "org.opencontainers.image.source"
+	labelVersion  = "org.opencontainers.image.version"
+)
+
+// buildMetadata is what ktl knows about the source of a build.
+type buildMetadata struct {
+	Created  string
+	Revision string
+	Source   string
+	Version  string
+	Dirty    bool
+}
+
+// labels maps the metadata to OCI labels, skipping unknown values.
+func (m buildMetadata) labels() map[string]string {
+	return nonEmpty(map[string]string{
+		labelCreated:  m.Created,
+		labelRevision: m.Revision,
+		labelSource:   m.Source,
+		labelVersion:  m.Version,
+	})
+}
+
+// buildArgs maps the metadata to the build args Dockerfiles conventionally declare for it.
+func (m buildMetadata) buildArgs() map[string]string {
+	return nonEmpty(map[string]string{
+		"BUILD_DATE": m.Created,
+		"VCS_REF":    m.Revision,
+		"VCS_URL":    m.Source,
+		"VERSION":    m.Version,
+	})
+}
+
+func nonEmpty(in map[string]string) map[string]string {
+	for k, v := range in {
+		if v == "" {
+			delete(in, k)
+		}
+	}
+	return in
+}
+
+// gitOutputRunner runs git with args in dir and returns its trimmed stdout; tests replace it.
+var gitOutputRunner = func(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+var fullCommitPattern = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+// detectBuildMetadata collects the build date, the commit and remote of contextDir (or of the
+// remote git context), and the version. version wins over an exact tag on HEAD. Missing git data
+// is not an error: the corresponding labels are just left out.
+func detectBuildMetadata(ctx context.Context, contextDir string, git *buildkit.GitContext, version string, now time.Time) buildMetadata {
+	meta := buildMetadata{Created: buildTimestamp(now), Version: strings.TrimSpace(version)}
+	if git != nil {
+		meta.Source = redactURLCredentials(git.Remote)
+		ref := git.Ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		if fullCommitPattern.MatchString(ref) {
+			meta.Revision = ref
+		} else if out, err := gitOutputRunner(ctx, "", "ls-remote", git.Remote, ref); err == nil {
+			if fields := strings.Fields(out); len(fields) > 0 {
+				meta.Revision = fields[0]
+			}
+		}
+		return meta
+	}
+	if out, err := gitOutputRunner(ctx, contextDir, "rev-parse", "--is-inside-work-tree"); err != nil || out != "true" {
+		return meta
+	}
+	meta.Revision, _ = gitOutputRunner(ctx, contextDir, "rev-parse", "HEAD")
+	if status, err := gitOutputRunner(ctx, contextDir, "status", "--porcelain"); err == nil {
+		meta.Dirty = status != ""
+	}
+	if remote, err := gitOutputRunner(ctx, contextDir, "remote", "get-url", "origin"); err == nil {
+		meta.Source = redactURLCredentials(remote)
+	}
+	if meta.Version == "" && !meta.Dirty {
+		meta.Version, _ = gitOutputRunner(ctx, contextDir, "describe", "--tags", "--exact-match", "HEAD")
+	}
+	return meta
+}
+
+// buildTimestamp honours SOURCE_DATE_EPOCH so reproducible builds get a stable created label.
+func buildTimestamp(now time.Time) string {
+	if raw := strings.TrimSpace(os.Getenv("SOURCE_DATE_EPOCH")); raw != "" {
+		if secs, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return time.Unix(secs, 0).UTC().Format(time.RFC3339)
+		}
+	}
+	return now.UTC().Format(time.RFC3339)
+}
+
+// redactURLCredentials drops user:token@ from http(s) remotes so tokens used by CI clones do not
+// end up in image labels.
+func redactURLCredentials(remote string) string {
+	remote = strings.TrimSpace(remote)
+	u, err := url.Parse(remote)
+	if err != nil || u.User == nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return remote
+	}
+	u.User = nil
+	return u.String()
+}
+
+// injectBuildMetadata prepends the detected labels (and, with InjectBuildArgs, build args) to
+// opts so explicit --label and --build-arg values still win, and returns what was injected.
+func injectBuildMetadata(opts *Options, meta buildMetadata) (labels, args map[string]string) {
+	explicitLabels, _ := parseKeyValueArgs(opts.Labels)
+	explicitArgs, _ := parseKeyValueArgs(opts.BuildArgs)
+	labels, args = map[string]string{}, map[string]string{}
+	if opts.InjectLabels {
+		for k, v := range meta.labels() {
+			if _, ok := explicitLabels[k]; !ok {
+				labels[k] = v
+			}
+		}
+	}
+	if opts.InjectBuildArgs {
+		for k, v := range meta.buildArgs() {
+			if _, ok := explicitArgs[k]; !ok {
+				args[k] = v
+			}
+		}
+	}
+	opts.Labels = append(keyValueList(labels), opts.Labels...)
+	opts.BuildArgs = append(keyValueList(args), opts.BuildArgs...)
+	return labels, args
+}
+
+func keyValueList(values map[string]string) []string {
+	out := make([]string, 0, len(values))
+	for k, v := range values {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// writeInjectedMetadata prints the injected labels and build args.
+func writeInjectedMetadata(w io.Writer, labels, args map[string]string, dirty bool) {
+	if len(labels) == 0 && len(args) == 0 {
+		return
+	}
+	if len(labels) > 0 {
+		fmt.Fprintln(w, "Injected labels:")
+		for _, kv := range keyValueList(labels) {
+			fmt.Fprintf(w, "  %s\n", kv)
+		}
+	}
+	if len(args) > 0 {
+		fmt.Fprintln(w, "Injected build args:")
+		for _, kv := range keyValueList(args) {
+			fmt.Fprintf(w, "  %s\n", kv)
+		}
+	}
+	if dirty {
+		fmt.Fprintln(w, "warning: the work tree has uncommitted changes; the revision label names HEAD, not what was built")
+	}
+}
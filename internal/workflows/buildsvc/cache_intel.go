@@ -271,7 +271,11 @@ func (c *cacheIntelCollector) report() cacheIntelReport {
 	if cur != nil {
 		_ = c.writeCurrentGraph(context.Background(), cur.DockerfileRel, curGraph)
 	}
+	var allLayers []buildkit.OCILayerInfo
 	if ociDir != "" {
+		if layers, err := buildkit.OCILayers(ociDir); err == nil {
+			allLayers = layers
+		}
 		if layers, err := buildkit.TopOCILayers(ociDir, out.TopN); err == nil {
 			out.Layers = make([]cacheIntelLayer, 0, len(layers))
 			for _, layer := range layers {
@@ -280,10 +284,14 @@ func (c *cacheIntelCollector) report() cacheIntelReport {
 					Digest:      layer.Digest,
 					Size:        layer.Size,
 					MediaType:   layer.MediaType,
+					CreatedBy:   layer.CreatedBy,
 				})
 			}
 		}
 	}
+	out.Steps = buildCacheIntelSteps(vertices, allLayers)
+	out.Stages = summarizeStages(out.Steps, vertices)
+	out.CacheBusters = findCacheBusters(out.Steps, out.stepMissReason, out.TopN)
 	return out
 }
 
@@ -300,7 +308,10 @@ type cacheIntelReport struct {
 	InputsCurrent  *cacheIntelInputsSnapshot `json:"inputsCurrent,omitempty"`
 	Diff           cacheIntelDiff            `json:"diff"`
 
-	Vertices      []cacheIntelVertex       `json:"vertices,omitempty"`
+	Vertices      []cacheIntelVertex       `json:"-"`
+	Steps         []cacheIntelStep         `json:"steps,omitempty"`
+	Stages        []cacheIntelStage        `json:"stages,omitempty"`
+	CacheBusters  []cacheIntelBuster       `json:"cacheBusters,omitempty"`
 	Layers        []cacheIntelLayer        `json:"layers,omitempty"`
 	CacheKeyDiffs []cacheIntelCacheKeyDiff `json:"cacheKeyDiffs,omitempty"`
 }
@@ -310,6 +321,7 @@ type cacheIntelLayer struct {
 	Digest      string `json:"digest"`
 	Size        int64  `json:"size"`
 	MediaType   string `json:"mediaType,omitempty"`
+	CreatedBy   string `json:"createdBy,omitempty"`
 }
 
 type cacheIntelCacheKeyDiff struct {
@@ -385,35 +397,32 @@ func (r cacheIntelReport) writeHuman(w io.Writer) {
 		}
 	}
 
-	misses := r.cacheMissVertices()
-	if len(misses) > 0 {
-		diffByName := map[string]cacheIntelCacheKeyDiff{}
-		for _, d := range r.CacheKeyDiffs {
-			if strings.TrimSpace(d.Name) == "" {
-				continue
+	if len(r.Stages) > 0 {
+		fmt.Fprintln(w, "  Stages:")
+		for _, st := range r.Stages {
+			line := fmt.Sprintf("    - %s: %d steps, %s, cache %d hit / %d miss", st.Name, st.Steps, time.Duration(st.DurationMS)*time.Millisecond, st.CacheHits, st.CacheMisses)
+			if st.LayerSize > 0 {
+				line += ", layers " + formatBytes(st.LayerSize)
 			}
-			diffByName[strings.TrimSpace(d.Name)] = d
+			fmt.Fprintln(w, line)
+		}
+	}
+
+	if len(r.CacheBusters) > 0 {
+		fmt.Fprintln(w, "  Top cache-busting instructions:")
+		for _, b := range r.CacheBusters {
+			fmt.Fprintf(w, "    - [%s %d] %s: %s; reran %d step(s), %s\n", b.Stage, b.Index, b.Instruction, b.Reason, b.InvalidatedSteps, time.Duration(b.RebuildMS)*time.Millisecond)
 		}
+	}
+
+	misses := r.cacheMissVertices()
+	if len(misses) > 0 {
 		fmt.Fprintln(w, "  Cache-missed steps (best-effort reasons):")
 		for i, v := range misses {
 			if i >= r.TopN {
 				break
 			}
-			reason := classifyCacheMiss(v, r.Diff)
-			if d, ok := diffByName[strings.TrimSpace(v.name)]; ok {
-				switch d.Type {
-				case "definition_changed":
-					if len(d.UpstreamNames) > 0 {
-						reason = fmt.Sprintf("cache key changed vs last run (upstream: %s)", strings.Join(d.UpstreamNames, ", "))
-					} else {
-						reason = "cache key changed vs last run"
-					}
-				case "cache_evicted":
-					reason = "cache key stable but result missing (cache evicted/pruned or cache import missing)"
-				case "new_step":
-					reason = "new step (no previous cache key)"
-				}
-			}
+			reason := r.missReason(v)
 			dur := vertexDuration(v).Round(time.Millisecond)
 			if dur < 0 {
 				dur = 0
@@ -462,13 +471,53 @@ func (r cacheIntelReport) writeHuman(w io.Writer) {
 			if i >= min(r.TopN, 5) {
 				break
 			}
+			line := fmt.Sprintf("    - %s (%s)", layer.Digest, formatBytes(layer.Size))
 			if layer.ImageDigest != "" {
-				fmt.Fprintf(w, "    - %s (%s) image %s\n", layer.Digest, formatBytes(layer.Size), shortHash(layer.ImageDigest))
-			} else {
-				fmt.Fprintf(w, "    - %s (%s)\n", layer.Digest, formatBytes(layer.Size))
+				line += " image " + shortHash(layer.ImageDigest)
 			}
+			if createdBy := normalizeInstruction(layer.CreatedBy); createdBy != "" {
+				line += ": " + truncateInstruction(createdBy, 80)
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+}
+
+// missReason explains a cache miss, preferring the solve graph diff against the previous run over the
+// name-based heuristics of classifyCacheMiss.
+func (r cacheIntelReport) missReason(v cacheIntelVertex) string {
+	name := strings.TrimSpace(v.name)
+	for _, d := range r.CacheKeyDiffs {
+		if strings.TrimSpace(d.Name) != name || name == "" {
+			continue
+		}
+		switch d.Type {
+		case "definition_changed":
+			if len(d.UpstreamNames) > 0 {
+				return fmt.Sprintf("cache key changed vs last run (upstream: %s)", strings.Join(d.UpstreamNames, ", "))
+			}
+			return "cache key changed vs last run"
+		case "cache_evicted":
+			return "cache key stable but result missing (cache evicted/pruned or cache import missing)"
+		case "new_step":
+			return "new step (no previous cache key)"
 		}
 	}
+	if _, _, _, instruction, ok := parseVertexStep(name); ok {
+		v.name = instruction
+	}
+	return classifyCacheMiss(v, r.Diff)
+}
+
+func (r cacheIntelReport) stepMissReason(step cacheIntelStep) string {
+	return r.missReason(cacheIntelVertex{name: step.Name})
+}
+
+func truncateInstruction(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit-3] + "..."
 }
 
 func (r cacheIntelReport) cacheMissVertices() []cacheIntelVertex {
@@ -0,0 +1,208 @@
+package buildsvc
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubekattle/ktl/pkg/buildkit"
+)
+
+// cacheIntelStep is one Dockerfile instruction as BuildKit reported it, e.g. "[builder 2/5] RUN make".
+type cacheIntelStep struct {
+	Name        string `json:"name"`
+	Stage       string `json:"stage"`
+	Index       int    `json:"index"`
+	Count       int    `json:"count"`
+	Instruction string `json:"instruction"`
+	DurationMS  int64  `json:"durationMs"`
+	Cached      bool   `json:"cached"`
+	Error       string `json:"error,omitempty"`
+	// Bytes is the largest progress total BuildKit reported for the step (pulls, context transfers).
+	Bytes int64 `json:"bytes,omitempty"`
+	// LayerSize is the compressed size of the layer the step added to the final image, if it could be matched.
+	LayerSize int64 `json:"layerSize,omitempty"`
+}
+
+// cacheIntelStage aggregates the steps of one Dockerfile stage.
+type cacheIntelStage struct {
+	Name        string `json:"name"`
+	Steps       int    `json:"steps"`
+	CacheHits   int    `json:"cacheHits"`
+	CacheMisses int    `json:"cacheMisses"`
+	// DurationMS is wall time from the first step start to the last step completion.
+	DurationMS int64 `json:"durationMs"`
+	Bytes      int64 `json:"bytes,omitempty"`
+	LayerSize  int64 `json:"layerSize,omitempty"`
+}
+
+// cacheIntelBuster is the first cache-missed instruction of a stage: every later step of the stage had to rerun
+// because of it.
+type cacheIntelBuster struct {
+	Stage            string `json:"stage"`
+	Index            int    `json:"index"`
+	Instruction      string `json:"instruction"`
+	Reason           string `json:"reason"`
+	InvalidatedSteps int    `json:"invalidatedSteps"`
+	RebuildMS        int64  `json:"rebuildMs"`
+}
+
+// vertexStepPattern matches BuildKit's "[platform stage n/m] INSTRUCTION" vertex names; platform and stage are
+// optional.
+var vertexStepPattern = regexp.MustCompile(`^\[([^\]]*?)\s*(\d+)/(\d+)\]\s+(.+)$`)
+
+// parseVertexStep splits a vertex name into its stage label, step index, step count, and instruction. Internal
+// vertices ("[internal] load build definition", "exporting to image") are not steps.
+func parseVertexStep(name string) (stage string, index, count int, instruction string, ok bool) {
+	m := vertexStepPattern.FindStringSubmatch(strings.TrimSpace(name))
+	if m == nil {
+		return "", 0, 0, "", false
+	}
+	index, _ = strconv.Atoi(m[2])
+	count, _ = strconv.Atoi(m[3])
+	stage = strings.TrimSpace(m[1])
+	if stage == "" {
+		stage = "default"
+	}
+	return stage, index, count, strings.TrimSpace(m[4]), true
+}
+
+// buildCacheIntelSteps turns the solve vertices into steps ordered by stage and index, and attributes final image
+// layers to the steps that created them.
+func buildCacheIntelSteps(vertices []cacheIntelVertex, layers []buildkit.OCILayerInfo) []cacheIntelStep {
+	steps := make([]cacheIntelStep, 0, len(vertices))
+	for _, v := range vertices {
+		stage, index, count, instruction, ok := parseVertexStep(v.name)
+		if !ok || v.completedAt == nil {
+			continue
+		}
+		dur := vertexDuration(v)
+		if dur < 0 {
+			dur = 0
+		}
+		steps = append(steps, cacheIntelStep{
+			Name:        strings.TrimSpace(v.name),
+			Stage:       stage,
+			Index:       index,
+			Count:       count,
+			Instruction: instruction,
+			DurationMS:  dur.Milliseconds(),
+			Cached:      v.cached,
+			Error:       v.err,
+			Bytes:       v.bytesTotal,
+		})
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		if steps[i].Stage != steps[j].Stage {
+			return steps[i].Stage < steps[j].Stage
+		}
+		return steps[i].Index < steps[j].Index
+	})
+	attributeLayers(steps, layers)
+	return steps
+}
+
+// attributeLayers matches each layer's history entry to the first step with the same instruction that has no layer
+// yet. BuildKit records "RUN /bin/sh -c make # buildkit" for "RUN make", so both sides are normalized first.
+func attributeLayers(steps []cacheIntelStep, layers []buildkit.OCILayerInfo) {
+	for _, layer := range layers {
+		createdBy := normalizeInstruction(layer.CreatedBy)
+		if createdBy == "" {
+			continue
+		}
+		for i := range steps {
+			if steps[i].LayerSize == 0 && normalizeInstruction(steps[i].Instruction) == createdBy {
+				steps[i].LayerSize = layer.Size
+				break
+			}
+		}
+	}
+}
+
+func normalizeInstruction(s string) string {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "# buildkit"))
+	s = strings.Replace(s, "/bin/sh -c ", "", 1)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// summarizeStages aggregates steps per stage, in the order the stages appear in steps.
+func summarizeStages(steps []cacheIntelStep, vertices []cacheIntelVertex) []cacheIntelStage {
+	type span struct{ start, end time.Time }
+	spans := map[string]*span{}
+	for _, v := range vertices {
+		stage, _, _, _, ok := parseVertexStep(v.name)
+		if !ok || v.startedAt == nil || v.completedAt == nil {
+			continue
+		}
+		sp := spans[stage]
+		if sp == nil {
+			sp = &span{start: *v.startedAt, end: *v.completedAt}
+			spans[stage] = sp
+		}
+		if v.startedAt.Before(sp.start) {
+			sp.start = *v.startedAt
+		}
+		if v.completedAt.After(sp.end) {
+			sp.end = *v.completedAt
+		}
+	}
+	out := make([]cacheIntelStage, 0)
+	byName := map[string]int{}
+	for _, step := range steps {
+		idx, ok := byName[step.Stage]
+		if !ok {
+			idx = len(out)
+			byName[step.Stage] = idx
+			out = append(out, cacheIntelStage{Name: step.Stage})
+			if sp := spans[step.Stage]; sp != nil {
+				out[idx].DurationMS = sp.end.Sub(sp.start).Milliseconds()
+			}
+		}
+		st := &out[idx]
+		st.Steps++
+		if step.Cached {
+			st.CacheHits++
+		} else if step.Error == "" {
+			st.CacheMisses++
+		}
+		st.Bytes += step.Bytes
+		st.LayerSize += step.LayerSize
+	}
+	return out
+}
+
+// findCacheBusters returns, per stage, the first instruction that missed the cache, ranked by how much rebuild
+// time it caused (its own duration plus every later missed step of the stage).
+func findCacheBusters(steps []cacheIntelStep, reason func(cacheIntelStep) string, topN int) []cacheIntelBuster {
+	busters := map[string]*cacheIntelBuster{}
+	order := make([]string, 0)
+	for _, step := range steps {
+		if step.Cached || step.Error != "" {
+			continue
+		}
+		b := busters[step.Stage]
+		if b == nil {
+			b = &cacheIntelBuster{
+				Stage:       step.Stage,
+				Index:       step.Index,
+				Instruction: step.Instruction,
+				Reason:      reason(step),
+			}
+			busters[step.Stage] = b
+			order = append(order, step.Stage)
+		}
+		b.InvalidatedSteps++
+		b.RebuildMS += step.DurationMS
+	}
+	out := make([]cacheIntelBuster, 0, len(order))
+	for _, stage := range order {
+		out = append(out, *busters[stage])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].RebuildMS > out[j].RebuildMS })
+	if topN > 0 && len(out) > topN {
+		out = out[:topN]
+	}
+	return out
+}
@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/kubekattle/ktl/pkg/buildkit"
 )
 
 func TestParseDockerfileCopyAddSources(t *testing.T) {
@@ -142,3 +145,67 @@ func TestDiffSolveGraphs_DefinitionChangedWithUpstream(t *testing.T) {
 		t.Fatalf("expected diff for RUN npm ci, got %#v", diffs)
 	}
 }
+
+func TestParseVertexStep(t *testing.T) {
+	cases := []struct {
+		name        string
+		stage       string
+		index       int
+		instruction string
+		ok          bool
+	}{
+		{name: "[builder 2/5] RUN go build ./...", stage: "builder", index: 2, instruction: "RUN go build ./...", ok: true},
+		{name: "[3/3] COPY --from=builder /out /app", stage: "default", index: 3, instruction: "COPY --from=builder /out /app", ok: true},
+		{name: "[linux/arm64 stage-1 1/2] FROM docker.io/library/alpine", stage: "linux/arm64 stage-1", index: 1, instruction: "FROM docker.io/library/alpine", ok: true},
+		{name: "[internal] load build definition from Dockerfile"},
+		{name: "exporting to image"},
+	}
+	for _, tc := range cases {
+		stage, index, _, instruction, ok := parseVertexStep(tc.name)
+		if ok != tc.ok || stage != tc.stage || index != tc.index || instruction != tc.instruction {
+			t.Fatalf("parseVertexStep(%q) = %q %d %q %v", tc.name, stage, index, instruction, ok)
+		}
+	}
+}
+
+func TestCacheIntelStagesAndBusters(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	at := func(sec int) *time.Time {
+		ts := base.Add(time.Duration(sec) * time.Second)
+		return &ts
+	}
+	vertices := []cacheIntelVertex{
+		{name: "[builder 1/4] FROM docker.io/library/golang:1.22", cached: true, startedAt: at(0), completedAt: at(0)},
+		{name: "[builder 2/4] COPY go.mod go.sum ./", cached: true, startedAt: at(0), completedAt: at(0)},
+		{name: "[builder 3/4] COPY . .", startedAt: at(1), completedAt: at(2)},
+		{name: "[builder 4/4] RUN go build -o /out/app", startedAt: at(2), completedAt: at(300)},
+		{name: "[stage-1 1/2] FROM docker.io/library/alpine", cached: true, startedAt: at(0), completedAt: at(0)},
+		{name: "[stage-1 2/2] COPY --from=builder /out/app /app", startedAt: at(300), completedAt: at(301)},
+		{name: "[internal] load build context", startedAt: at(0), completedAt: at(1)},
+	}
+	layers := []buildkit.OCILayerInfo{
+		{Digest: "sha256:aa", Size: 4096, CreatedBy: "COPY /out/app /app # buildkit"},
+		{Digest: "sha256:bb", Size: 2048, CreatedBy: "COPY --from=builder /out/app /app # buildkit"},
+	}
+
+	steps := buildCacheIntelSteps(vertices, layers)
+	if len(steps) != 6 {
+		t.Fatalf("expected 6 steps, got %d", len(steps))
+	}
+	if last := steps[len(steps)-1]; last.Stage != "stage-1" || last.LayerSize != 2048 {
+		t.Fatalf("expected final COPY to own the 2048-byte layer, got %#v", last)
+	}
+
+	stages := summarizeStages(steps, vertices)
+	if len(stages) != 2 || stages[0].Name != "builder" || stages[0].CacheHits != 2 || stages[0].CacheMisses != 2 || stages[0].DurationMS != 300000 {
+		t.Fatalf("unexpected stages: %#v", stages)
+	}
+
+	busters := findCacheBusters(steps, func(step cacheIntelStep) string { return "changed" }, 10)
+	if len(busters) != 2 {
+		t.Fatalf("expected a buster per stage, got %#v", busters)
+	}
+	if b := busters[0]; b.Stage != "builder" || b.Instruction != "COPY . ." || b.InvalidatedSteps != 2 || b.RebuildMS != 299000 {
+		t.Fatalf("unexpected top buster: %#v", b)
+	}
+}
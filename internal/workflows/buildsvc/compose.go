@@ -23,6 +23,10 @@ func (s *service) runComposeBuild(ctx context.Context, composeFiles []string, op
 	if err != nil {
 		return err
 	}
+	labels, err := parseLabels(opts.Labels)
+	if err != nil {
+		return err
+	}
 
 	dockerCfg, err := dockerconfig.LoadConfigFile(opts.AuthFile, streams.ErrWriter())
 	if err != nil {
@@ -47,6 +51,7 @@ func (s *service) runComposeBuild(ctx context.Context, composeFiles []string, op
 		AttestSBOM:           opts.AttestSBOM,
 		Platforms:            buildkit.NormalizePlatforms(expandPlatforms(opts.Platforms)),
 		BuildArgs:            argMap,
+		Labels:               labels,
 		ProgressOutput:       progressOut,
 		DockerConfig:         dockerCfg,
 		Parallelism:          opts.ComposeParallelism,
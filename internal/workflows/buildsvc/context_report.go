@@ -0,0 +1,245 @@
+// File: internal/workflows/buildsvc/context_report.go
+// Brief: Internal buildsvc package implementation for 'context_report'.
+
+// Package buildsvc provides buildsvc helpers.
+
+package buildsvc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+)
+
+// ContextEntry describes a single file or directory in a build context report.
+type ContextEntry struct {
+	Path  string `json:"path"`
+	Bytes int64  `json:"bytes"`
+	Files int    `json:"files,omitempty"`
+	// Skipped marks excluded entries holding directories that were not walked, so Bytes and
+	// Files leave their contents out.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// ContextReport summarizes what a build would ship to BuildKit, honoring .dockerignore.
+type ContextReport struct {
+	ContextDir      string         `json:"contextDir"`
+	Dockerignore    bool           `json:"dockerignore"`
+	IncludedBytes   int64          `json:"includedBytes"`
+	IncludedFiles   int            `json:"includedFiles"`
+	ExcludedBytes   int64          `json:"excludedBytes"`
+	ExcludedFiles   int            `json:"excludedFiles"`
+	ExcludedDirs    int            `json:"excludedDirs,omitempty"`
+	LargestFiles    []ContextEntry `json:"largestFiles,omitempty"`
+	LargestDirs     []ContextEntry `json:"largestDirs,omitempty"`
+	ExcludedEntries []ContextEntry `json:"excludedEntries,omitempty"`
+}
+
+// AnalyzeContext walks contextDir and reports its size, largest files/directories, and the
+// top-level entries excluded by .dockerignore. topN bounds each list (defaults to 10). Excluded
+// directories are not walked unless .dockerignore has ! exceptions that could re-include their
+// contents, so their size is not counted in ExcludedBytes.
+func AnalyzeContext(contextDir string, topN int) (*ContextReport, error) {
+	if topN <= 0 {
+		topN = 10
+	}
+	contextAbs, err := filepath.Abs(contextDir)
+	if err != nil {
+		return nil, err
+	}
+	report := &ContextReport{ContextDir: contextAbs}
+
+	var patterns []string
+	if raw, err := os.ReadFile(filepath.Join(contextAbs, ".dockerignore")); err == nil {
+		report.Dockerignore = true
+		p, err := ignorefile.ReadAll(strings.NewReader(string(raw)))
+		if err != nil {
+			return nil, fmt.Errorf("parse .dockerignore: %w", err)
+		}
+		patterns = p
+	}
+	matcher, err := patternmatcher.New(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("parse .dockerignore: %w", err)
+	}
+
+	var files []ContextEntry
+	dirs := map[string]*ContextEntry{}
+	excluded := map[string]*ContextEntry{}
+	excludedEntry := func(rel string) *ContextEntry {
+		top := topLevelEntry(rel)
+		entry := excluded[top]
+		if entry == nil {
+			entry = &ContextEntry{Path: top}
+			excluded[top] = entry
+		}
+		return entry
+	}
+	err = filepath.WalkDir(contextAbs, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == contextAbs {
+			return nil
+		}
+		rel, err := filepath.Rel(contextAbs, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if matcher.Exclusions() {
+				return nil
+			}
+			ignored, err := matcher.MatchesOrParentMatches(rel)
+			if err != nil {
+				return err
+			}
+			if ignored {
+				report.ExcludedDirs++
+				excludedEntry(rel + "/").Skipped = true
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		size := info.Size()
+		ignored, err := matcher.MatchesOrParentMatches(rel)
+		if err != nil {
+			return err
+		}
+		if ignored {
+			report.ExcludedBytes += size
+			report.ExcludedFiles++
+			entry := excludedEntry(rel)
+			entry.Bytes += size
+			entry.Files++
+			return nil
+		}
+		report.IncludedBytes += size
+		report.IncludedFiles++
+		files = append(files, ContextEntry{Path: rel, Bytes: size})
+		top := topLevelEntry(rel)
+		if top != rel {
+			entry := dirs[top]
+			if entry == nil {
+				entry = &ContextEntry{Path: top}
+				dirs[top] = entry
+			}
+			entry.Bytes += size
+			entry.Files++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.LargestFiles = topContextEntries(files, topN)
+	report.LargestDirs = topContextEntries(flattenContextEntries(dirs), topN)
+	report.ExcludedEntries = topContextEntries(flattenContextEntries(excluded), topN)
+	return report, nil
+}
+
+// CheckMaxSize returns an error when the included context exceeds limit bytes (0 disables the check).
+func (r *ContextReport) CheckMaxSize(limit int64) error {
+	if r == nil || limit <= 0 || r.IncludedBytes <= limit {
+		return nil
+	}
+	hint := "add entries to .dockerignore"
+	if len(r.LargestDirs) > 0 {
+		hint = fmt.Sprintf("largest directory is %s (%s); consider adding it to .dockerignore", r.LargestDirs[0].Path, formatBytes(r.LargestDirs[0].Bytes))
+	}
+	return fmt.Errorf("build context %s is %s, exceeding --max-context-size %s (%s)", r.ContextDir, formatBytes(r.IncludedBytes), formatBytes(limit), hint)
+}
+
+// WriteContextReport renders the report as human-readable text or JSON.
+func WriteContextReport(w io.Writer, r *ContextReport, format string) error {
+	if w == nil || r == nil {
+		return nil
+	}
+	if strings.EqualFold(strings.TrimSpace(format), "json") {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	fmt.Fprintf(w, "Build context: %s\n", r.ContextDir)
+	fmt.Fprintf(w, "  included: %s in %d files\n", formatBytes(r.IncludedBytes), r.IncludedFiles)
+	if r.Dockerignore {
+		fmt.Fprintf(w, "  excluded by .dockerignore: %s in %d files", formatBytes(r.ExcludedBytes), r.ExcludedFiles)
+		if r.ExcludedDirs > 0 {
+			fmt.Fprintf(w, ", plus %d directories not scanned", r.ExcludedDirs)
+		}
+		fmt.Fprintln(w)
+	} else {
+		fmt.Fprintln(w, "  .dockerignore: not found")
+	}
+	writeEntries := func(title string, entries []ContextEntry) {
+		if len(entries) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "  %s:\n", title)
+		for _, e := range entries {
+			switch {
+			case e.Skipped && e.Files == 0:
+				fmt.Fprintf(w, "    %8s  %s (not scanned)\n", "-", e.Path)
+				continue
+			case e.Skipped:
+				fmt.Fprintf(w, "    %8s  %s (%d files, more not scanned)\n", formatBytes(e.Bytes), e.Path, e.Files)
+				continue
+			}
+			if e.Files > 0 {
+				fmt.Fprintf(w, "    %8s  %s (%d files)\n", formatBytes(e.Bytes), e.Path, e.Files)
+				continue
+			}
+			fmt.Fprintf(w, "    %8s  %s\n", formatBytes(e.Bytes), e.Path)
+		}
+	}
+	writeEntries("largest files", r.LargestFiles)
+	writeEntries("largest directories", r.LargestDirs)
+	writeEntries("excluded entries", r.ExcludedEntries)
+	return nil
+}
+
+func topLevelEntry(rel string) string {
+	if idx := strings.IndexByte(rel, '/'); idx >= 0 {
+		return rel[:idx] + "/"
+	}
+	return rel
+}
+
+func flattenContextEntries(in map[string]*ContextEntry) []ContextEntry {
+	out := make([]ContextEntry, 0, len(in))
+	for _, e := range in {
+		out = append(out, *e)
+	}
+	return out
+}
+
+func topContextEntries(entries []ContextEntry, topN int) []ContextEntry {
+	sort.Slice(entries, func(i, j int) bool {
+		// Skipped directories have no size but are usually the biggest exclusions.
+		if entries[i].Skipped != entries[j].Skipped {
+			return entries[i].Skipped
+		}
+		if entries[i].Bytes == entries[j].Bytes {
+			return entries[i].Path < entries[j].Path
+		}
+		return entries[i].Bytes > entries[j].Bytes
+	})
+	if len(entries) > topN {
+		entries = entries[:topN]
+	}
+	return entries
+}
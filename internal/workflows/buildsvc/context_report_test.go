@@ -0,0 +1,115 @@
+package buildsvc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeContextHonorsDockerignore(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	write := func(rel string, size int) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, bytes.Repeat([]byte("x"), size), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	write(".dockerignore", 0)
+	if err := os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("node_modules\n*.log\n"), 0o644); err != nil {
+		t.Fatalf("write dockerignore: %v", err)
+	}
+	write("Dockerfile", 10)
+	write("src/main.go", 100)
+	write("src/util.go", 50)
+	write("node_modules/pkg/index.js", 1000)
+	write("debug.log", 500)
+
+	report, err := AnalyzeContext(dir, 5)
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if !report.Dockerignore {
+		t.Fatalf("expected dockerignore to be detected")
+	}
+	if report.ExcludedFiles != 1 || report.ExcludedBytes != 500 || report.ExcludedDirs != 1 {
+		t.Fatalf("unexpected excluded totals: files=%d bytes=%d dirs=%d", report.ExcludedFiles, report.ExcludedBytes, report.ExcludedDirs)
+	}
+	if report.IncludedBytes != 160+int64(len("node_modules\n*.log\n")) {
+		t.Fatalf("unexpected included bytes: %d", report.IncludedBytes)
+	}
+	if len(report.LargestFiles) == 0 || report.LargestFiles[0].Path != "src/main.go" {
+		t.Fatalf("unexpected largest files: %+v", report.LargestFiles)
+	}
+	if len(report.LargestDirs) != 1 || report.LargestDirs[0].Path != "src/" || report.LargestDirs[0].Files != 2 {
+		t.Fatalf("unexpected largest dirs: %+v", report.LargestDirs)
+	}
+	if len(report.ExcludedEntries) != 2 || report.ExcludedEntries[0].Path != "node_modules/" || !report.ExcludedEntries[0].Skipped {
+		t.Fatalf("unexpected excluded entries: %+v", report.ExcludedEntries)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteContextReport(&buf, report, "human"); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+	if !strings.Contains(buf.String(), "plus 1 directories not scanned") || !strings.Contains(buf.String(), "node_modules/ (not scanned)") {
+		t.Fatalf("unexpected report output:\n%s", buf.String())
+	}
+}
+
+func TestAnalyzeContextWalksExcludedDirsWithExceptions(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for rel, body := range map[string]string{
+		".dockerignore":       "vendor\n!vendor/keep.go\n",
+		"vendor/keep.go":      "package keep",
+		"vendor/drop/drop.go": "package drop",
+		"Dockerfile":          "FROM scratch",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	report, err := AnalyzeContext(dir, 5)
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if report.ExcludedDirs != 0 || report.ExcludedFiles != 1 {
+		t.Fatalf("expected excluded dirs to be walked when exceptions exist: %+v", report)
+	}
+	var kept bool
+	for _, f := range report.LargestFiles {
+		kept = kept || f.Path == "vendor/keep.go"
+	}
+	if !kept {
+		t.Fatalf("expected vendor/keep.go to be included: %+v", report.LargestFiles)
+	}
+}
+
+func TestContextReportCheckMaxSize(t *testing.T) {
+	t.Parallel()
+	report := &ContextReport{ContextDir: "/ctx", IncludedBytes: 2048, LargestDirs: []ContextEntry{{Path: "assets/", Bytes: 2000}}}
+	if err := report.CheckMaxSize(0); err != nil {
+		t.Fatalf("limit 0 should disable the guard: %v", err)
+	}
+	if err := report.CheckMaxSize(4096); err != nil {
+		t.Fatalf("unexpected error under limit: %v", err)
+	}
+	err := report.CheckMaxSize(1024)
+	if err == nil {
+		t.Fatalf("expected error over limit")
+	}
+	if !strings.Contains(err.Error(), "--max-context-size") || !strings.Contains(err.Error(), "assets/") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
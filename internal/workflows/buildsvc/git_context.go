@@ -0,0 +1,107 @@
+// File: internal/workflows/buildsvc/git_context.go
+// Brief: Internal buildsvc package implementation for remote git build contexts.
+
+package buildsvc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubekattle/ktl/pkg/buildkit"
+)
+
+// resolveGitContext parses a remote git context and applies the --git-* options to it. It
+// returns nil for local contexts.
+func resolveGitContext(contextDir string, opts Options) (*buildkit.GitContext, error) {
+	g, ok, err := buildkit.ParseGitContext(contextDir)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if opts.GitSkipSubmodules {
+		g.SkipSubmodules = true
+	}
+	if opts.GitKeepDir {
+		g.KeepGitDir = true
+	}
+	return &g, nil
+}
+
+// needsLocalGitCheckout reports whether a git context has to be fetched by ktl instead of by
+// BuildKit: BuildKit's git source does not run LFS smudge filters, compose, hermetic, and
+// sandboxed builds read the context from the host, and --context-report/--max-context-size
+// measure the checked-out files.
+func needsLocalGitCheckout(opts Options) bool {
+	mode := strings.ToLower(strings.TrimSpace(opts.BuildMode))
+	return opts.GitLFS || opts.Hermetic || opts.RequireSandbox || mode == string(ModeCompose) ||
+		opts.ContextReport || opts.MaxContextSize > 0
+}
+
+// gitRunner runs git with args in dir; tests replace it.
+var gitRunner = func(ctx context.Context, dir string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// fetchGitContext makes a shallow checkout of the context's ref (one commit, no history) in a
+// temporary directory and returns the context root inside it. With lfs set, LFS objects are
+// pulled too, which needs git-lfs on the host.
+func fetchGitContext(ctx context.Context, g buildkit.GitContext, lfs bool, errOut io.Writer) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "ktl-git-context-*")
+	if err != nil {
+		return "", func() {}, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	ref := g.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	fmt.Fprintf(errOut, "Fetching build context %s (shallow)\n", g.String())
+	steps := [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", g.Remote},
+		{"fetch", "-q", "--depth", "1", "origin", ref},
+		{"checkout", "-q", "FETCH_HEAD"},
+	}
+	if !g.SkipSubmodules {
+		steps = append(steps, []string{"submodule", "update", "-q", "--init", "--recursive", "--depth", "1"})
+	}
+	if lfs {
+		steps = append(steps, []string{"lfs", "pull"})
+	}
+	for _, args := range steps {
+		if err := gitRunner(ctx, dir, args...); err != nil {
+			cleanup()
+			if lfs && args[0] == "lfs" {
+				return "", func() {}, fmt.Errorf("%w (is git-lfs installed?)", err)
+			}
+			return "", func() {}, fmt.Errorf("fetch git context %s: %w", g.Remote, err)
+		}
+	}
+	if !g.KeepGitDir {
+		if err := os.RemoveAll(filepath.Join(dir, ".git")); err != nil {
+			cleanup()
+			return "", func() {}, err
+		}
+	}
+	root := filepath.Join(dir, filepath.FromSlash(g.SubDir))
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		cleanup()
+		return "", func() {}, fmt.Errorf("git context %s has no directory %q at %s", g.Remote, g.SubDir, ref)
+	}
+	return root, cleanup, nil
+}
@@ -0,0 +1,90 @@
+package buildsvc
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubekattle/ktl/pkg/buildkit"
+)
+
+func TestNeedsLocalGitCheckout(t *testing.T) {
+	cases := []struct {
+		name string
+		opts Options
+		want bool
+	}{
+		{name: "default", opts: Options{}, want: false},
+		{name: "lfs", opts: Options{GitLFS: true}, want: true},
+		{name: "hermetic", opts: Options{Hermetic: true}, want: true},
+		{name: "sandbox", opts: Options{RequireSandbox: true}, want: true},
+		{name: "compose", opts: Options{BuildMode: "compose"}, want: true},
+		{name: "context report", opts: Options{ContextReport: true}, want: true},
+		{name: "max context size", opts: Options{MaxContextSize: 1 << 20}, want: true},
+	}
+	for _, tc := range cases {
+		if got := needsLocalGitCheckout(tc.opts); got != tc.want {
+			t.Fatalf("%s: needsLocalGitCheckout = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestResolveGitContextAppliesOptions(t *testing.T) {
+	g, err := resolveGitContext("https://github.com/acme/app.git#main:api", Options{GitSkipSubmodules: true, GitKeepDir: true})
+	if err != nil || g == nil {
+		t.Fatalf("resolveGitContext: %v, %v", g, err)
+	}
+	if !g.SkipSubmodules || !g.KeepGitDir || g.SubDir != "api" {
+		t.Fatalf("unexpected git context %+v", g)
+	}
+	if g, err := resolveGitContext(t.TempDir(), Options{}); err != nil || g != nil {
+		t.Fatalf("expected local context, got %v, %v", g, err)
+	}
+}
+
+func TestFetchGitContextShallowSubdir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=ktl", "GIT_AUTHOR_EMAIL=ktl@example.com", "GIT_COMMITTER_NAME=ktl", "GIT_COMMITTER_EMAIL=ktl@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	if err := os.MkdirAll(filepath.Join(repo, "api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "api", "Dockerfile"), []byte("FROM scratch\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+	run("tag", "v1")
+
+	g := buildkit.GitContext{Remote: repo, Ref: "v1", SubDir: "api"}
+	root, cleanup, err := fetchGitContext(context.Background(), g, false, io.Discard)
+	if err != nil {
+		t.Fatalf("fetchGitContext: %v", err)
+	}
+	defer cleanup()
+	if _, err := os.Stat(filepath.Join(root, "Dockerfile")); err != nil {
+		t.Fatalf("expected Dockerfile in checkout: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "..", ".git")); !os.IsNotExist(err) {
+		t.Fatalf("expected .git to be removed, got %v", err)
+	}
+
+	g.SubDir = "missing"
+	if _, _, err := fetchGitContext(context.Background(), g, false, io.Discard); err == nil {
+		t.Fatal("expected error for a missing subdirectory")
+	}
+}
@@ -48,6 +48,20 @@ func parseKeyValueArgs(values []string) (map[string]string, error) {
 	return args, nil
 }
 
+// parseLabels parses KEY=VALUE image labels.
+func parseLabels(values []string) (map[string]string, error) {
+	labels := make(map[string]string, len(values))
+	for _, raw := range values {
+		key, val, ok := strings.Cut(raw, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q (expected KEY=VALUE)", raw)
+		}
+		labels[key] = val
+	}
+	return labels, nil
+}
+
 // ParseKeyValueArgs exposes the argument parser for other command packages.
 func ParseKeyValueArgs(values []string) (map[string]string, error) {
 	return parseKeyValueArgs(values)
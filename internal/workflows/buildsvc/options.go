@@ -92,10 +92,17 @@ func (s Streams) IsTerminal(w io.Writer) bool {
 // Options contains everything needed to execute a ktl build workflow.
 type Options struct {
 	ContextDir         string
+	GitSkipSubmodules  bool
+	GitKeepDir         bool
+	GitLFS             bool
 	Dockerfile         string
 	Tags               []string
 	Platforms          []string
 	BuildArgs          []string
+	Labels             []string
+	InjectLabels       bool
+	InjectBuildArgs    bool
+	ImageVersion       string
 	Secrets            []string
 	CacheFrom          []string
 	CacheTo            []string
@@ -123,6 +130,8 @@ type Options struct {
 	CacheIntelTop      int
 	CacheIntelFormat   string
 	CacheIntelOutput   string
+	ContextReport      bool
+	MaxContextSize     int64
 	LogLevel           string
 	Sign               bool
 	SignKey            string
@@ -181,6 +181,21 @@ func (s *service) Run(ctx context.Context, opts Options) (res *Result, runErr er
 			opts.CacheDir = envCache
 		}
 	}
+	gitContext, err := resolveGitContext(contextDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	sourceGit := gitContext
+	if gitContext != nil && needsLocalGitCheckout(opts) {
+		dir, cleanupCheckout, err := fetchGitContext(ctx, *gitContext, opts.GitLFS, errOut)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanupCheckout()
+		contextDir = dir
+		opts.ContextDir = dir
+		gitContext = nil
+	}
 	cacheDir := opts.CacheDir
 	if cacheDir == "" {
 		cacheDir = buildkit.DefaultCacheDir()
@@ -194,6 +209,11 @@ func (s *service) Run(ctx context.Context, opts Options) (res *Result, runErr er
 	if err != nil {
 		return nil, err
 	}
+	contextName := filepath.Base(contextAbs)
+	if gitContext != nil {
+		contextAbs = gitContext.String()
+		contextName = gitContext.Name()
+	}
 
 	secretGuard, err := newSecretsGuard(ctx, opts.SecretsMode, opts.SecretsReportPath, opts.AttestationDir, opts.SecretsConfigRef)
 	if err != nil {
@@ -203,6 +223,19 @@ func (s *service) Run(ctx context.Context, opts Options) (res *Result, runErr er
 		return nil, err
 	}
 
+	if (opts.ContextReport || opts.MaxContextSize > 0) && !sandboxActive() {
+		report, err := AnalyzeContext(contextAbs, 0)
+		if err != nil {
+			return nil, fmt.Errorf("analyze build context: %w", err)
+		}
+		if opts.ContextReport && !opts.Quiet {
+			_ = WriteContextReport(errOut, report, "human")
+		}
+		if err := report.CheckMaxSize(opts.MaxContextSize); err != nil {
+			return nil, err
+		}
+	}
+
 	gate, err := newPolicyGate(ctx, opts.PolicyRef, opts.PolicyMode, opts.PolicyReportPath, opts.AttestationDir)
 	if err != nil {
 		return nil, err
@@ -213,7 +246,7 @@ func (s *service) Run(ctx context.Context, opts Options) (res *Result, runErr er
 		opts.AttestSBOM = true
 	}
 
-	if injector := getSandboxInjector(); injector != nil {
+	if injector := getSandboxInjector(); injector != nil && gitContext == nil {
 		if handled, err := injector(ctx, &opts, streams, contextAbs); err != nil {
 			return nil, err
 		} else if handled {
@@ -233,7 +266,15 @@ func (s *service) Run(ctx context.Context, opts Options) (res *Result, runErr er
 		}
 	}
 
-	stream := newBuildProgressBroadcaster(filepath.Base(contextAbs))
+	if opts.InjectLabels || opts.InjectBuildArgs {
+		meta := detectBuildMetadata(ctx, contextAbs, sourceGit, opts.ImageVersion, time.Now())
+		injectedLabels, injectedArgs := injectBuildMetadata(&opts, meta)
+		if !opts.Quiet {
+			writeInjectedMetadata(errOut, injectedLabels, injectedArgs, meta.Dirty)
+		}
+	}
+
+	stream := newBuildProgressBroadcaster(contextName)
 	progressObservers := []buildkit.ProgressObserver{stream}
 	diagnosticObservers := []buildkit.BuildDiagnosticObserver{&buildDiagnosticObserver{
 		stream: stream,
@@ -393,9 +434,11 @@ func (s *service) Run(ctx context.Context, opts Options) (res *Result, runErr er
 		}
 	}()
 
-	mode, composeFiles, err := selectBuildMode(contextAbs, opts)
-	if err != nil {
-		return nil, err
+	mode, composeFiles := modeDockerfile, []string(nil)
+	if gitContext == nil {
+		if mode, composeFiles, err = selectBuildMode(contextAbs, opts); err != nil {
+			return nil, err
+		}
 	}
 
 	if mode == modeCompose {
@@ -482,18 +525,21 @@ func (s *service) Run(ctx context.Context, opts Options) (res *Result, runErr er
 
 	var dfMeta dockerfileMeta
 	if gate != nil {
-		dockerfilePath := opts.Dockerfile
-		if dockerfilePath == "" {
-			dockerfilePath = "Dockerfile"
-		}
-		if !filepath.IsAbs(dockerfilePath) {
-			dockerfilePath = filepath.Join(contextAbs, dockerfilePath)
-		}
-		meta, derr := readDockerfileMeta(dockerfilePath)
-		if derr != nil {
-			return nil, fmt.Errorf("policy gate: read dockerfile metadata: %w", derr)
+		// Remote git Dockerfiles are only read by BuildKit, so the pre-build input has no metadata.
+		if gitContext == nil {
+			dockerfilePath := opts.Dockerfile
+			if dockerfilePath == "" {
+				dockerfilePath = "Dockerfile"
+			}
+			if !filepath.IsAbs(dockerfilePath) {
+				dockerfilePath = filepath.Join(contextAbs, dockerfilePath)
+			}
+			meta, derr := readDockerfileMeta(dockerfilePath)
+			if derr != nil {
+				return nil, fmt.Errorf("policy gate: read dockerfile metadata: %w", derr)
+			}
+			dfMeta = meta
 		}
-		dfMeta = meta
 		pre := buildPolicyInput(time.Now(), contextDir, "", append([]string(nil), opts.Tags...), dfMeta, opts.AttestationDir)
 		if stream != nil {
 			stream.emitPhase("policy-pre", "running", "Evaluating pre-build policy")
@@ -520,6 +566,10 @@ func (s *service) Run(ctx context.Context, opts Options) (res *Result, runErr er
 	if err != nil {
 		return nil, err
 	}
+	labels, err := parseLabels(opts.Labels)
+	if err != nil {
+		return nil, err
+	}
 
 	cacheFrom, err := parseCacheSpecs(opts.CacheFrom)
 	if err != nil {
@@ -541,7 +591,7 @@ func (s *service) Run(ctx context.Context, opts Options) (res *Result, runErr er
 		return nil, errors.New("--tag must be provided when using --push")
 	}
 	if len(tags) == 0 {
-		tags = []string{buildkit.DefaultLocalTag(contextDir)}
+		tags = []string{buildkit.DefaultLocalTag(contextName)}
 		fmt.Fprintf(errOut, "Defaulting to local tag %s\n", tags[0])
 	}
 	if stream != nil {
@@ -567,7 +617,7 @@ func (s *service) Run(ctx context.Context, opts Options) (res *Result, runErr er
 	progressOut := resolveConsoleFile(errOut)
 
 	var cacheIntel *cacheIntelCollector
-	if opts.CacheIntel && !opts.Quiet {
+	if opts.CacheIntel && !opts.Quiet && gitContext == nil {
 		dfPath := opts.Dockerfile
 		if dfPath == "" {
 			dfPath = "Dockerfile"
@@ -634,9 +684,11 @@ func (s *service) Run(ctx context.Context, opts Options) (res *Result, runErr er
 		AllowBuilderFallback: opts.Builder == "",
 		DockerContext:        opts.DockerContext,
 		ContextDir:           contextDir,
+		GitContext:           gitContext,
 		DockerfilePath:       opts.Dockerfile,
 		Platforms:            platforms,
 		BuildArgs:            buildArgs,
+		Labels:               labels,
 		Secrets:              secrets,
 		Tags:                 tags,
 		Push:                 opts.Push,
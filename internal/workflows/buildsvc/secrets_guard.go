@@ -14,6 +14,7 @@ import (
 	"github.com/compose-spec/compose-go/v2/loader"
 	composetypes "github.com/compose-spec/compose-go/v2/types"
 	"github.com/kubekattle/ktl/internal/secrets"
+	"github.com/kubekattle/ktl/pkg/buildkit"
 )
 
 type secretsGuard struct {
@@ -159,6 +160,9 @@ func (g *secretsGuard) scanDockerfile(opts Options) ([]secrets.Finding, error) {
 	if contextDir == "" {
 		contextDir = "."
 	}
+	if buildkit.IsGitContext(contextDir) {
+		return nil, nil
+	}
 	dockerfile := strings.TrimSpace(opts.Dockerfile)
 	if dockerfile == "" {
 		dockerfile = filepath.Join(contextDir, "Dockerfile")
@@ -499,6 +499,8 @@ func (x *LogLine) GetRenderedEqualsRaw() bool {
 	return false
 }
 
+// BuildOptions mirrors `ktl build` flags. Paths (context_dir, cache_dir, attestation_dir, ...)
+// are resolved on the agent host; context_dir may also be a git URL with an optional #ref:subdir.
 type BuildOptions struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
 	ContextDir         string                 `protobuf:"bytes,1,opt,name=context_dir,json=contextDir,proto3" json:"context_dir,omitempty"`
@@ -531,6 +533,24 @@ type BuildOptions struct {
 	RemoveIntermediate bool                   `protobuf:"varint,28,opt,name=remove_intermediate,json=removeIntermediate,proto3" json:"remove_intermediate,omitempty"`
 	Quiet              bool                   `protobuf:"varint,29,opt,name=quiet,proto3" json:"quiet,omitempty"`
 	DockerContext      string                 `protobuf:"bytes,30,opt,name=docker_context,json=dockerContext,proto3" json:"docker_context,omitempty"`
+	Hermetic           bool                   `protobuf:"varint,31,opt,name=hermetic,proto3" json:"hermetic,omitempty"`
+	AllowNetwork       bool                   `protobuf:"varint,32,opt,name=allow_network,json=allowNetwork,proto3" json:"allow_network,omitempty"`
+	AllowUnpinnedBases bool                   `protobuf:"varint,33,opt,name=allow_unpinned_bases,json=allowUnpinnedBases,proto3" json:"allow_unpinned_bases,omitempty"`
+	PolicyRef          string                 `protobuf:"bytes,34,opt,name=policy_ref,json=policyRef,proto3" json:"policy_ref,omitempty"`
+	PolicyMode         string                 `protobuf:"bytes,35,opt,name=policy_mode,json=policyMode,proto3" json:"policy_mode,omitempty"`    // enforce|warn
+	SecretsMode        string                 `protobuf:"bytes,36,opt,name=secrets_mode,json=secretsMode,proto3" json:"secrets_mode,omitempty"` // warn|block|off
+	SecretsConfigRef   string                 `protobuf:"bytes,37,opt,name=secrets_config_ref,json=secretsConfigRef,proto3" json:"secrets_config_ref,omitempty"`
+	AttestSbom         bool                   `protobuf:"varint,38,opt,name=attest_sbom,json=attestSbom,proto3" json:"attest_sbom,omitempty"`
+	AttestProvenance   bool                   `protobuf:"varint,39,opt,name=attest_provenance,json=attestProvenance,proto3" json:"attest_provenance,omitempty"`
+	AttestationDir     string                 `protobuf:"bytes,40,opt,name=attestation_dir,json=attestationDir,proto3" json:"attestation_dir,omitempty"`
+	RequireSandbox     bool                   `protobuf:"varint,41,opt,name=require_sandbox,json=requireSandbox,proto3" json:"require_sandbox,omitempty"`
+	GitSkipSubmodules  bool                   `protobuf:"varint,42,opt,name=git_skip_submodules,json=gitSkipSubmodules,proto3" json:"git_skip_submodules,omitempty"`
+	GitKeepDir         bool                   `protobuf:"varint,43,opt,name=git_keep_dir,json=gitKeepDir,proto3" json:"git_keep_dir,omitempty"`
+	GitLfs             bool                   `protobuf:"varint,44,opt,name=git_lfs,json=gitLfs,proto3" json:"git_lfs,omitempty"`
+	Labels             []string               `protobuf:"bytes,45,rep,name=labels,proto3" json:"labels,omitempty"` // KEY=VALUE
+	InjectLabels       bool                   `protobuf:"varint,46,opt,name=inject_labels,json=injectLabels,proto3" json:"inject_labels,omitempty"`
+	InjectBuildArgs    bool                   `protobuf:"varint,47,opt,name=inject_build_args,json=injectBuildArgs,proto3" json:"inject_build_args,omitempty"`
+	ImageVersion       string                 `protobuf:"bytes,48,opt,name=image_version,json=imageVersion,proto3" json:"image_version,omitempty"`
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
@@ -775,13 +795,143 @@ func (x *BuildOptions) GetDockerContext() string {
 	return ""
 }
 
+func (x *BuildOptions) GetHermetic() bool {
+	if x != nil {
+		return x.Hermetic
+	}
+	return false
+}
+
+func (x *BuildOptions) GetAllowNetwork() bool {
+	if x != nil {
+		return x.AllowNetwork
+	}
+	return false
+}
+
+func (x *BuildOptions) GetAllowUnpinnedBases() bool {
+	if x != nil {
+		return x.AllowUnpinnedBases
+	}
+	return false
+}
+
+func (x *BuildOptions) GetPolicyRef() string {
+	if x != nil {
+		return x.PolicyRef
+	}
+	return ""
+}
+
+func (x *BuildOptions) GetPolicyMode() string {
+	if x != nil {
+		return x.PolicyMode
+	}
+	return ""
+}
+
+func (x *BuildOptions) GetSecretsMode() string {
+	if x != nil {
+		return x.SecretsMode
+	}
+	return ""
+}
+
+func (x *BuildOptions) GetSecretsConfigRef() string {
+	if x != nil {
+		return x.SecretsConfigRef
+	}
+	return ""
+}
+
+func (x *BuildOptions) GetAttestSbom() bool {
+	if x != nil {
+		return x.AttestSbom
+	}
+	return false
+}
+
+func (x *BuildOptions) GetAttestProvenance() bool {
+	if x != nil {
+		return x.AttestProvenance
+	}
+	return false
+}
+
+func (x *BuildOptions) GetAttestationDir() string {
+	if x != nil {
+		return x.AttestationDir
+	}
+	return ""
+}
+
+func (x *BuildOptions) GetRequireSandbox() bool {
+	if x != nil {
+		return x.RequireSandbox
+	}
+	return false
+}
+
+func (x *BuildOptions) GetGitSkipSubmodules() bool {
+	if x != nil {
+		return x.GitSkipSubmodules
+	}
+	return false
+}
+
+func (x *BuildOptions) GetGitKeepDir() bool {
+	if x != nil {
+		return x.GitKeepDir
+	}
+	return false
+}
+
+func (x *BuildOptions) GetGitLfs() bool {
+	if x != nil {
+		return x.GitLfs
+	}
+	return false
+}
+
+func (x *BuildOptions) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *BuildOptions) GetInjectLabels() bool {
+	if x != nil {
+		return x.InjectLabels
+	}
+	return false
+}
+
+func (x *BuildOptions) GetInjectBuildArgs() bool {
+	if x != nil {
+		return x.InjectBuildArgs
+	}
+	return false
+}
+
+func (x *BuildOptions) GetImageVersion() string {
+	if x != nil {
+		return x.ImageVersion
+	}
+	return ""
+}
+
 type RunBuildRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Options       *BuildOptions          `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
-	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	Requester     string                 `protobuf:"bytes,3,opt,name=requester,proto3" json:"requester,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Options *BuildOptions          `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	// session_id doubles as the build's job ID; the agent generates one when it is empty.
+	SessionId string `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Requester string `protobuf:"bytes,3,opt,name=requester,proto3" json:"requester,omitempty"`
+	// Keep the build running when the client disconnects, so it can be resumed with
+	// AttachBuild. Otherwise a disconnect cancels the build.
+	DetachOnDisconnect bool `protobuf:"varint,4,opt,name=detach_on_disconnect,json=detachOnDisconnect,proto3" json:"detach_on_disconnect,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *RunBuildRequest) Reset() {
@@ -835,12 +985,21 @@ func (x *RunBuildRequest) GetRequester() string {
 	return ""
 }
 
+func (x *RunBuildRequest) GetDetachOnDisconnect() bool {
+	if x != nil {
+		return x.DetachOnDisconnect
+	}
+	return false
+}
+
+// BuildResult is the last event of a build stream. error is empty on success.
 type BuildResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Tags          []string               `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
-	Digest        string                 `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
-	OciOutputDir  string                 `protobuf:"bytes,3,opt,name=oci_output_dir,json=ociOutputDir,proto3" json:"oci_output_dir,omitempty"`
-	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tags  []string               `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	// digest is the image (or index, for multi-platform builds) digest.
+	Digest        string `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	OciOutputDir  string `protobuf:"bytes,3,opt,name=oci_output_dir,json=ociOutputDir,proto3" json:"oci_output_dir,omitempty"`
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -903,33 +1062,463 @@ func (x *BuildResult) GetError() string {
 	return ""
 }
 
-type BuildEvent struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	TimestampUnixNano int64                  `protobuf:"varint,1,opt,name=timestamp_unix_nano,json=timestampUnixNano,proto3" json:"timestamp_unix_nano,omitempty"`
-	// Types that are valid to be assigned to Body:
-	//
-	//	*BuildEvent_Log
-	//	*BuildEvent_Result
-	Body          isBuildEvent_Body `protobuf_oneof:"body"`
+// BuildJob is the first event of every RunBuild and AttachBuild stream.
+type BuildJob struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	JobId           string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	State           string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"` // running|succeeded|failed|canceled
+	StartedUnixNano int64                  `protobuf:"varint,3,opt,name=started_unix_nano,json=startedUnixNano,proto3" json:"started_unix_nano,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BuildJob) Reset() {
+	*x = BuildJob{}
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildJob) ProtoMessage() {}
+
+func (x *BuildJob) ProtoReflect() protoreflect.Message {
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildJob.ProtoReflect.Descriptor instead.
+func (*BuildJob) Descriptor() ([]byte, []int) {
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BuildJob) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *BuildJob) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *BuildJob) GetStartedUnixNano() int64 {
+	if x != nil {
+		return x.StartedUnixNano
+	}
+	return 0
+}
+
+// BuildVertex is one step of the BuildKit solve graph.
+type BuildVertex struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // vertex digest
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // pending|running|cached|completed|failed
+	Cached        bool                   `protobuf:"varint,4,opt,name=cached,proto3" json:"cached,omitempty"`
+	StartedUnix   int64                  `protobuf:"varint,5,opt,name=started_unix,json=startedUnix,proto3" json:"started_unix,omitempty"`
+	CompletedUnix int64                  `protobuf:"varint,6,opt,name=completed_unix,json=completedUnix,proto3" json:"completed_unix,omitempty"`
+	Current       int64                  `protobuf:"varint,7,opt,name=current,proto3" json:"current,omitempty"`
+	Total         int64                  `protobuf:"varint,8,opt,name=total,proto3" json:"total,omitempty"`
+	Error         string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	Inputs        []string               `protobuf:"bytes,10,rep,name=inputs,proto3" json:"inputs,omitempty"` // IDs of the vertices this one depends on
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildVertex) Reset() {
+	*x = BuildVertex{}
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildVertex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildVertex) ProtoMessage() {}
+
+func (x *BuildVertex) ProtoReflect() protoreflect.Message {
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildVertex.ProtoReflect.Descriptor instead.
+func (*BuildVertex) Descriptor() ([]byte, []int) {
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BuildVertex) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BuildVertex) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BuildVertex) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BuildVertex) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+func (x *BuildVertex) GetStartedUnix() int64 {
+	if x != nil {
+		return x.StartedUnix
+	}
+	return 0
+}
+
+func (x *BuildVertex) GetCompletedUnix() int64 {
+	if x != nil {
+		return x.CompletedUnix
+	}
+	return 0
+}
+
+func (x *BuildVertex) GetCurrent() int64 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *BuildVertex) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *BuildVertex) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BuildVertex) GetInputs() []string {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+// BuildProgress is a snapshot of every vertex seen so far; each one replaces the previous.
+type BuildProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vertices      []*BuildVertex         `protobuf:"bytes,1,rep,name=vertices,proto3" json:"vertices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildProgress) Reset() {
+	*x = BuildProgress{}
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildProgress) ProtoMessage() {}
+
+func (x *BuildProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildProgress.ProtoReflect.Descriptor instead.
+func (*BuildProgress) Descriptor() ([]byte, []int) {
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BuildProgress) GetVertices() []*BuildVertex {
+	if x != nil {
+		return x.Vertices
+	}
+	return nil
+}
+
+// BuildPhase marks ktl's own build stages (policy-pre, solve, export, push, ...).
+type BuildPhase struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"` // running|completed|failed
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildPhase) Reset() {
+	*x = BuildPhase{}
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildPhase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildPhase) ProtoMessage() {}
+
+func (x *BuildPhase) ProtoReflect() protoreflect.Message {
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildPhase.ProtoReflect.Descriptor instead.
+func (*BuildPhase) Descriptor() ([]byte, []int) {
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BuildPhase) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BuildPhase) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *BuildPhase) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// BuildEvent is one message of a build stream. Every event is also sent as a log line (the
+// form ktl's console renders); progress and phase carry the same data in typed form.
+type BuildEvent struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TimestampUnixNano int64                  `protobuf:"varint,1,opt,name=timestamp_unix_nano,json=timestampUnixNano,proto3" json:"timestamp_unix_nano,omitempty"`
+	// Types that are valid to be assigned to Body:
+	//
+	//	*BuildEvent_Log
+	//	*BuildEvent_Result
+	//	*BuildEvent_Job
+	//	*BuildEvent_Progress
+	//	*BuildEvent_Phase
+	Body isBuildEvent_Body `protobuf_oneof:"body"`
+	// sequence numbers the job's events from 1; pass the last one seen to AttachBuild to resume.
+	Sequence      uint64 `protobuf:"varint,7,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildEvent) Reset() {
+	*x = BuildEvent{}
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildEvent) ProtoMessage() {}
+
+func (x *BuildEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildEvent.ProtoReflect.Descriptor instead.
+func (*BuildEvent) Descriptor() ([]byte, []int) {
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BuildEvent) GetTimestampUnixNano() int64 {
+	if x != nil {
+		return x.TimestampUnixNano
+	}
+	return 0
+}
+
+func (x *BuildEvent) GetBody() isBuildEvent_Body {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *BuildEvent) GetLog() *LogLine {
+	if x != nil {
+		if x, ok := x.Body.(*BuildEvent_Log); ok {
+			return x.Log
+		}
+	}
+	return nil
+}
+
+func (x *BuildEvent) GetResult() *BuildResult {
+	if x != nil {
+		if x, ok := x.Body.(*BuildEvent_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *BuildEvent) GetJob() *BuildJob {
+	if x != nil {
+		if x, ok := x.Body.(*BuildEvent_Job); ok {
+			return x.Job
+		}
+	}
+	return nil
+}
+
+func (x *BuildEvent) GetProgress() *BuildProgress {
+	if x != nil {
+		if x, ok := x.Body.(*BuildEvent_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *BuildEvent) GetPhase() *BuildPhase {
+	if x != nil {
+		if x, ok := x.Body.(*BuildEvent_Phase); ok {
+			return x.Phase
+		}
+	}
+	return nil
+}
+
+func (x *BuildEvent) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type isBuildEvent_Body interface {
+	isBuildEvent_Body()
+}
+
+type BuildEvent_Log struct {
+	Log *LogLine `protobuf:"bytes,2,opt,name=log,proto3,oneof"`
+}
+
+type BuildEvent_Result struct {
+	Result *BuildResult `protobuf:"bytes,3,opt,name=result,proto3,oneof"`
+}
+
+type BuildEvent_Job struct {
+	Job *BuildJob `protobuf:"bytes,4,opt,name=job,proto3,oneof"`
+}
+
+type BuildEvent_Progress struct {
+	Progress *BuildProgress `protobuf:"bytes,5,opt,name=progress,proto3,oneof"`
+}
+
+type BuildEvent_Phase struct {
+	Phase *BuildPhase `protobuf:"bytes,6,opt,name=phase,proto3,oneof"`
+}
+
+func (*BuildEvent_Log) isBuildEvent_Body() {}
+
+func (*BuildEvent_Result) isBuildEvent_Body() {}
+
+func (*BuildEvent_Job) isBuildEvent_Body() {}
+
+func (*BuildEvent_Progress) isBuildEvent_Body() {}
+
+func (*BuildEvent_Phase) isBuildEvent_Body() {}
+
+type AttachBuildRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	JobId string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// Replay events after this sequence; 0 replays the whole build.
+	FromSequence  uint64 `protobuf:"varint,2,opt,name=from_sequence,json=fromSequence,proto3" json:"from_sequence,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BuildEvent) Reset() {
-	*x = BuildEvent{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[7]
+func (x *AttachBuildRequest) Reset() {
+	*x = AttachBuildRequest{}
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BuildEvent) String() string {
+func (x *AttachBuildRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BuildEvent) ProtoMessage() {}
+func (*AttachBuildRequest) ProtoMessage() {}
 
-func (x *BuildEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[7]
+func (x *AttachBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -940,58 +1529,68 @@ func (x *BuildEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BuildEvent.ProtoReflect.Descriptor instead.
-func (*BuildEvent) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use AttachBuildRequest.ProtoReflect.Descriptor instead.
+func (*AttachBuildRequest) Descriptor() ([]byte, []int) {
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *BuildEvent) GetTimestampUnixNano() int64 {
+func (x *AttachBuildRequest) GetJobId() string {
 	if x != nil {
-		return x.TimestampUnixNano
+		return x.JobId
 	}
-	return 0
+	return ""
 }
 
-func (x *BuildEvent) GetBody() isBuildEvent_Body {
+func (x *AttachBuildRequest) GetFromSequence() uint64 {
 	if x != nil {
-		return x.Body
+		return x.FromSequence
 	}
-	return nil
+	return 0
 }
 
-func (x *BuildEvent) GetLog() *LogLine {
-	if x != nil {
-		if x, ok := x.Body.(*BuildEvent_Log); ok {
-			return x.Log
-		}
-	}
-	return nil
+type CancelBuildRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BuildEvent) GetResult() *BuildResult {
-	if x != nil {
-		if x, ok := x.Body.(*BuildEvent_Result); ok {
-			return x.Result
-		}
-	}
-	return nil
+func (x *CancelBuildRequest) Reset() {
+	*x = CancelBuildRequest{}
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-type isBuildEvent_Body interface {
-	isBuildEvent_Body()
+func (x *CancelBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-type BuildEvent_Log struct {
-	Log *LogLine `protobuf:"bytes,2,opt,name=log,proto3,oneof"`
-}
+func (*CancelBuildRequest) ProtoMessage() {}
 
-type BuildEvent_Result struct {
-	Result *BuildResult `protobuf:"bytes,3,opt,name=result,proto3,oneof"`
+func (x *CancelBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (*BuildEvent_Log) isBuildEvent_Body() {}
+// Deprecated: Use CancelBuildRequest.ProtoReflect.Descriptor instead.
+func (*CancelBuildRequest) Descriptor() ([]byte, []int) {
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{13}
+}
 
-func (*BuildEvent_Result) isBuildEvent_Body() {}
+func (x *CancelBuildRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
 
 type DeployEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1002,7 +1601,7 @@ type DeployEvent struct {
 
 func (x *DeployEvent) Reset() {
 	*x = DeployEvent{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[8]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1014,7 +1613,7 @@ func (x *DeployEvent) String() string {
 func (*DeployEvent) ProtoMessage() {}
 
 func (x *DeployEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[8]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1027,7 +1626,7 @@ func (x *DeployEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeployEvent.ProtoReflect.Descriptor instead.
 func (*DeployEvent) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{8}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *DeployEvent) GetJson() string {
@@ -1062,7 +1661,7 @@ type DeployApplyOptions struct {
 
 func (x *DeployApplyOptions) Reset() {
 	*x = DeployApplyOptions{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[9]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1074,7 +1673,7 @@ func (x *DeployApplyOptions) String() string {
 func (*DeployApplyOptions) ProtoMessage() {}
 
 func (x *DeployApplyOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[9]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1087,7 +1686,7 @@ func (x *DeployApplyOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeployApplyOptions.ProtoReflect.Descriptor instead.
 func (*DeployApplyOptions) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{9}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *DeployApplyOptions) GetRelease() string {
@@ -1220,7 +1819,7 @@ type DeployApplyRequest struct {
 
 func (x *DeployApplyRequest) Reset() {
 	*x = DeployApplyRequest{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[10]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1232,7 +1831,7 @@ func (x *DeployApplyRequest) String() string {
 func (*DeployApplyRequest) ProtoMessage() {}
 
 func (x *DeployApplyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[10]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1245,7 +1844,7 @@ func (x *DeployApplyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeployApplyRequest.ProtoReflect.Descriptor instead.
 func (*DeployApplyRequest) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{10}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *DeployApplyRequest) GetOptions() *DeployApplyOptions {
@@ -1287,7 +1886,7 @@ type DeployDestroyOptions struct {
 
 func (x *DeployDestroyOptions) Reset() {
 	*x = DeployDestroyOptions{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[11]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1299,7 +1898,7 @@ func (x *DeployDestroyOptions) String() string {
 func (*DeployDestroyOptions) ProtoMessage() {}
 
 func (x *DeployDestroyOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[11]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1312,7 +1911,7 @@ func (x *DeployDestroyOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeployDestroyOptions.ProtoReflect.Descriptor instead.
 func (*DeployDestroyOptions) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{11}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *DeployDestroyOptions) GetRelease() string {
@@ -1396,7 +1995,7 @@ type DeployDestroyRequest struct {
 
 func (x *DeployDestroyRequest) Reset() {
 	*x = DeployDestroyRequest{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[12]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1408,7 +2007,7 @@ func (x *DeployDestroyRequest) String() string {
 func (*DeployDestroyRequest) ProtoMessage() {}
 
 func (x *DeployDestroyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[12]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1421,7 +2020,7 @@ func (x *DeployDestroyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeployDestroyRequest.ProtoReflect.Descriptor instead.
 func (*DeployDestroyRequest) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{12}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *DeployDestroyRequest) GetOptions() *DeployDestroyOptions {
@@ -1465,7 +2064,7 @@ type MirrorFrame struct {
 
 func (x *MirrorFrame) Reset() {
 	*x = MirrorFrame{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[13]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1477,7 +2076,7 @@ func (x *MirrorFrame) String() string {
 func (*MirrorFrame) ProtoMessage() {}
 
 func (x *MirrorFrame) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[13]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1490,7 +2089,7 @@ func (x *MirrorFrame) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorFrame.ProtoReflect.Descriptor instead.
 func (*MirrorFrame) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{13}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *MirrorFrame) GetSessionId() string {
@@ -1618,7 +2217,7 @@ type MirrorAck struct {
 
 func (x *MirrorAck) Reset() {
 	*x = MirrorAck{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[14]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1630,7 +2229,7 @@ func (x *MirrorAck) String() string {
 func (*MirrorAck) ProtoMessage() {}
 
 func (x *MirrorAck) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[14]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1643,7 +2242,7 @@ func (x *MirrorAck) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorAck.ProtoReflect.Descriptor instead.
 func (*MirrorAck) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{14}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *MirrorAck) GetSessionId() string {
@@ -1678,7 +2277,7 @@ type MirrorSubscribeRequest struct {
 
 func (x *MirrorSubscribeRequest) Reset() {
 	*x = MirrorSubscribeRequest{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[15]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1690,7 +2289,7 @@ func (x *MirrorSubscribeRequest) String() string {
 func (*MirrorSubscribeRequest) ProtoMessage() {}
 
 func (x *MirrorSubscribeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[15]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1703,7 +2302,7 @@ func (x *MirrorSubscribeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorSubscribeRequest.ProtoReflect.Descriptor instead.
 func (*MirrorSubscribeRequest) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{15}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *MirrorSubscribeRequest) GetSessionId() string {
@@ -1745,7 +2344,7 @@ type MirrorSessionMeta struct {
 
 func (x *MirrorSessionMeta) Reset() {
 	*x = MirrorSessionMeta{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[16]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1757,7 +2356,7 @@ func (x *MirrorSessionMeta) String() string {
 func (*MirrorSessionMeta) ProtoMessage() {}
 
 func (x *MirrorSessionMeta) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[16]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1770,7 +2369,7 @@ func (x *MirrorSessionMeta) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorSessionMeta.ProtoReflect.Descriptor instead.
 func (*MirrorSessionMeta) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{16}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *MirrorSessionMeta) GetCommand() string {
@@ -1844,7 +2443,7 @@ type MirrorSession struct {
 
 func (x *MirrorSession) Reset() {
 	*x = MirrorSession{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[17]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1856,7 +2455,7 @@ func (x *MirrorSession) String() string {
 func (*MirrorSession) ProtoMessage() {}
 
 func (x *MirrorSession) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[17]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1869,7 +2468,7 @@ func (x *MirrorSession) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorSession.ProtoReflect.Descriptor instead.
 func (*MirrorSession) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{17}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *MirrorSession) GetSessionId() string {
@@ -1933,7 +2532,7 @@ type MirrorSessionStatus struct {
 
 func (x *MirrorSessionStatus) Reset() {
 	*x = MirrorSessionStatus{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[18]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1945,7 +2544,7 @@ func (x *MirrorSessionStatus) String() string {
 func (*MirrorSessionStatus) ProtoMessage() {}
 
 func (x *MirrorSessionStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[18]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1958,7 +2557,7 @@ func (x *MirrorSessionStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorSessionStatus.ProtoReflect.Descriptor instead.
 func (*MirrorSessionStatus) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{18}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *MirrorSessionStatus) GetState() MirrorSessionState {
@@ -2007,7 +2606,7 @@ type MirrorListSessionsRequest struct {
 
 func (x *MirrorListSessionsRequest) Reset() {
 	*x = MirrorListSessionsRequest{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[19]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2019,7 +2618,7 @@ func (x *MirrorListSessionsRequest) String() string {
 func (*MirrorListSessionsRequest) ProtoMessage() {}
 
 func (x *MirrorListSessionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[19]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2032,7 +2631,7 @@ func (x *MirrorListSessionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorListSessionsRequest.ProtoReflect.Descriptor instead.
 func (*MirrorListSessionsRequest) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{19}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *MirrorListSessionsRequest) GetLimit() int32 {
@@ -2086,7 +2685,7 @@ type MirrorListSessionsResponse struct {
 
 func (x *MirrorListSessionsResponse) Reset() {
 	*x = MirrorListSessionsResponse{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[20]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2098,7 +2697,7 @@ func (x *MirrorListSessionsResponse) String() string {
 func (*MirrorListSessionsResponse) ProtoMessage() {}
 
 func (x *MirrorListSessionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[20]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2111,7 +2710,7 @@ func (x *MirrorListSessionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorListSessionsResponse.ProtoReflect.Descriptor instead.
 func (*MirrorListSessionsResponse) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{20}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *MirrorListSessionsResponse) GetSessions() []*MirrorSession {
@@ -2130,7 +2729,7 @@ type MirrorGetSessionRequest struct {
 
 func (x *MirrorGetSessionRequest) Reset() {
 	*x = MirrorGetSessionRequest{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[21]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2142,7 +2741,7 @@ func (x *MirrorGetSessionRequest) String() string {
 func (*MirrorGetSessionRequest) ProtoMessage() {}
 
 func (x *MirrorGetSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[21]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2155,7 +2754,7 @@ func (x *MirrorGetSessionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorGetSessionRequest.ProtoReflect.Descriptor instead.
 func (*MirrorGetSessionRequest) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{21}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *MirrorGetSessionRequest) GetSessionId() string {
@@ -2176,7 +2775,7 @@ type MirrorSetSessionMetaRequest struct {
 
 func (x *MirrorSetSessionMetaRequest) Reset() {
 	*x = MirrorSetSessionMetaRequest{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[22]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2188,7 +2787,7 @@ func (x *MirrorSetSessionMetaRequest) String() string {
 func (*MirrorSetSessionMetaRequest) ProtoMessage() {}
 
 func (x *MirrorSetSessionMetaRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[22]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2201,7 +2800,7 @@ func (x *MirrorSetSessionMetaRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorSetSessionMetaRequest.ProtoReflect.Descriptor instead.
 func (*MirrorSetSessionMetaRequest) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{22}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *MirrorSetSessionMetaRequest) GetSessionId() string {
@@ -2235,7 +2834,7 @@ type MirrorSetSessionStatusRequest struct {
 
 func (x *MirrorSetSessionStatusRequest) Reset() {
 	*x = MirrorSetSessionStatusRequest{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[23]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2247,7 +2846,7 @@ func (x *MirrorSetSessionStatusRequest) String() string {
 func (*MirrorSetSessionStatusRequest) ProtoMessage() {}
 
 func (x *MirrorSetSessionStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[23]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2260,7 +2859,7 @@ func (x *MirrorSetSessionStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorSetSessionStatusRequest.ProtoReflect.Descriptor instead.
 func (*MirrorSetSessionStatusRequest) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{23}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *MirrorSetSessionStatusRequest) GetSessionId() string {
@@ -2286,7 +2885,7 @@ type MirrorDeleteSessionRequest struct {
 
 func (x *MirrorDeleteSessionRequest) Reset() {
 	*x = MirrorDeleteSessionRequest{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[24]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2298,7 +2897,7 @@ func (x *MirrorDeleteSessionRequest) String() string {
 func (*MirrorDeleteSessionRequest) ProtoMessage() {}
 
 func (x *MirrorDeleteSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[24]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2311,7 +2910,7 @@ func (x *MirrorDeleteSessionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorDeleteSessionRequest.ProtoReflect.Descriptor instead.
 func (*MirrorDeleteSessionRequest) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{24}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *MirrorDeleteSessionRequest) GetSessionId() string {
@@ -2330,7 +2929,7 @@ type MirrorDeleteSessionResponse struct {
 
 func (x *MirrorDeleteSessionResponse) Reset() {
 	*x = MirrorDeleteSessionResponse{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[25]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2342,7 +2941,7 @@ func (x *MirrorDeleteSessionResponse) String() string {
 func (*MirrorDeleteSessionResponse) ProtoMessage() {}
 
 func (x *MirrorDeleteSessionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[25]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2355,7 +2954,7 @@ func (x *MirrorDeleteSessionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorDeleteSessionResponse.ProtoReflect.Descriptor instead.
 func (*MirrorDeleteSessionResponse) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{25}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *MirrorDeleteSessionResponse) GetDeleted() bool {
@@ -2376,7 +2975,7 @@ type MirrorExportRequest struct {
 
 func (x *MirrorExportRequest) Reset() {
 	*x = MirrorExportRequest{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[26]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2388,7 +2987,7 @@ func (x *MirrorExportRequest) String() string {
 func (*MirrorExportRequest) ProtoMessage() {}
 
 func (x *MirrorExportRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[26]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2401,7 +3000,7 @@ func (x *MirrorExportRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorExportRequest.ProtoReflect.Descriptor instead.
 func (*MirrorExportRequest) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{26}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *MirrorExportRequest) GetSessionId() string {
@@ -2434,7 +3033,7 @@ type MirrorExportChunk struct {
 
 func (x *MirrorExportChunk) Reset() {
 	*x = MirrorExportChunk{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[27]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2446,7 +3045,7 @@ func (x *MirrorExportChunk) String() string {
 func (*MirrorExportChunk) ProtoMessage() {}
 
 func (x *MirrorExportChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[27]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2459,7 +3058,7 @@ func (x *MirrorExportChunk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MirrorExportChunk.ProtoReflect.Descriptor instead.
 func (*MirrorExportChunk) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{27}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *MirrorExportChunk) GetData() []byte {
@@ -2487,7 +3086,7 @@ type VerifyChartOptions struct {
 
 func (x *VerifyChartOptions) Reset() {
 	*x = VerifyChartOptions{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[28]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2499,7 +3098,7 @@ func (x *VerifyChartOptions) String() string {
 func (*VerifyChartOptions) ProtoMessage() {}
 
 func (x *VerifyChartOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[28]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2512,7 +3111,7 @@ func (x *VerifyChartOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyChartOptions.ProtoReflect.Descriptor instead.
 func (*VerifyChartOptions) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{28}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *VerifyChartOptions) GetChart() string {
@@ -2596,7 +3195,7 @@ type VerifyNamespaceOptions struct {
 
 func (x *VerifyNamespaceOptions) Reset() {
 	*x = VerifyNamespaceOptions{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[29]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2608,7 +3207,7 @@ func (x *VerifyNamespaceOptions) String() string {
 func (*VerifyNamespaceOptions) ProtoMessage() {}
 
 func (x *VerifyNamespaceOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[29]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2621,7 +3220,7 @@ func (x *VerifyNamespaceOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyNamespaceOptions.ProtoReflect.Descriptor instead.
 func (*VerifyNamespaceOptions) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{29}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *VerifyNamespaceOptions) GetNamespace() string {
@@ -2660,7 +3259,7 @@ type VerifyOptions struct {
 
 func (x *VerifyOptions) Reset() {
 	*x = VerifyOptions{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[30]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2672,7 +3271,7 @@ func (x *VerifyOptions) String() string {
 func (*VerifyOptions) ProtoMessage() {}
 
 func (x *VerifyOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[30]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2685,7 +3284,7 @@ func (x *VerifyOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyOptions.ProtoReflect.Descriptor instead.
 func (*VerifyOptions) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{30}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *VerifyOptions) GetMode() string {
@@ -2753,7 +3352,7 @@ type VerifyRequest struct {
 
 func (x *VerifyRequest) Reset() {
 	*x = VerifyRequest{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[31]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2765,7 +3364,7 @@ func (x *VerifyRequest) String() string {
 func (*VerifyRequest) ProtoMessage() {}
 
 func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[31]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2778,7 +3377,7 @@ func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
 func (*VerifyRequest) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{31}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *VerifyRequest) GetOptions() *VerifyOptions {
@@ -2861,7 +3460,7 @@ type VerifyEvent struct {
 
 func (x *VerifyEvent) Reset() {
 	*x = VerifyEvent{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[32]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2873,7 +3472,7 @@ func (x *VerifyEvent) String() string {
 func (*VerifyEvent) ProtoMessage() {}
 
 func (x *VerifyEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[32]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2886,7 +3485,7 @@ func (x *VerifyEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyEvent.ProtoReflect.Descriptor instead.
 func (*VerifyEvent) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{32}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *VerifyEvent) GetTimestampUnixNano() int64 {
@@ -3009,7 +3608,7 @@ type VerifyStarted struct {
 
 func (x *VerifyStarted) Reset() {
 	*x = VerifyStarted{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[33]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3021,7 +3620,7 @@ func (x *VerifyStarted) String() string {
 func (*VerifyStarted) ProtoMessage() {}
 
 func (x *VerifyStarted) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[33]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3034,7 +3633,7 @@ func (x *VerifyStarted) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyStarted.ProtoReflect.Descriptor instead.
 func (*VerifyStarted) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{33}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *VerifyStarted) GetTarget() string {
@@ -3075,7 +3674,7 @@ type VerifyProgress struct {
 
 func (x *VerifyProgress) Reset() {
 	*x = VerifyProgress{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[34]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3087,7 +3686,7 @@ func (x *VerifyProgress) String() string {
 func (*VerifyProgress) ProtoMessage() {}
 
 func (x *VerifyProgress) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[34]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3100,7 +3699,7 @@ func (x *VerifyProgress) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyProgress.ProtoReflect.Descriptor instead.
 func (*VerifyProgress) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{34}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *VerifyProgress) GetPhase() string {
@@ -3128,7 +3727,7 @@ type VerifySubject struct {
 
 func (x *VerifySubject) Reset() {
 	*x = VerifySubject{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[35]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3140,7 +3739,7 @@ func (x *VerifySubject) String() string {
 func (*VerifySubject) ProtoMessage() {}
 
 func (x *VerifySubject) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[35]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3153,7 +3752,7 @@ func (x *VerifySubject) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifySubject.ProtoReflect.Descriptor instead.
 func (*VerifySubject) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{35}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *VerifySubject) GetKind() string {
@@ -3192,7 +3791,7 @@ type VerifyFinding struct {
 
 func (x *VerifyFinding) Reset() {
 	*x = VerifyFinding{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[36]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3204,7 +3803,7 @@ func (x *VerifyFinding) String() string {
 func (*VerifyFinding) ProtoMessage() {}
 
 func (x *VerifyFinding) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[36]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3217,7 +3816,7 @@ func (x *VerifyFinding) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyFinding.ProtoReflect.Descriptor instead.
 func (*VerifyFinding) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{36}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *VerifyFinding) GetRuleId() string {
@@ -3280,7 +3879,7 @@ type VerifySummary struct {
 
 func (x *VerifySummary) Reset() {
 	*x = VerifySummary{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[37]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3292,7 +3891,7 @@ func (x *VerifySummary) String() string {
 func (*VerifySummary) ProtoMessage() {}
 
 func (x *VerifySummary) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[37]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3305,7 +3904,7 @@ func (x *VerifySummary) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifySummary.ProtoReflect.Descriptor instead.
 func (*VerifySummary) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{37}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *VerifySummary) GetTotal() int32 {
@@ -3339,7 +3938,7 @@ type VerifyDone struct {
 
 func (x *VerifyDone) Reset() {
 	*x = VerifyDone{}
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[38]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3351,7 +3950,7 @@ func (x *VerifyDone) String() string {
 func (*VerifyDone) ProtoMessage() {}
 
 func (x *VerifyDone) ProtoReflect() protoreflect.Message {
-	mi := &file_ktl_api_v1_agent_proto_msgTypes[38]
+	mi := &file_ktl_api_v1_agent_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3364,7 +3963,7 @@ func (x *VerifyDone) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyDone.ProtoReflect.Descriptor instead.
 func (*VerifyDone) Descriptor() ([]byte, []int) {
-	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{38}
+	return file_ktl_api_v1_agent_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *VerifyDone) GetPassed() bool {
@@ -3440,7 +4039,7 @@ const file_ktl_api_v1_agent_proto_rawDesc = "" +
 	"\x06source\x18\b \x01(\tR\x06source\x12!\n" +
 	"\fsource_glyph\x18\t \x01(\tR\vsourceGlyph\x12.\n" +
 	"\x13rendered_equals_raw\x18\n" +
-	" \x01(\bR\x11renderedEqualsRaw\"\xdf\a\n" +
+	" \x01(\bR\x11renderedEqualsRaw\"\xfc\f\n" +
 	"\fBuildOptions\x12\x1f\n" +
 	"\vcontext_dir\x18\x01 \x01(\tR\n" +
 	"contextDir\x12\x1e\n" +
@@ -3479,23 +4078,78 @@ const file_ktl_api_v1_agent_proto_rawDesc = "" +
 	"\blog_file\x18\x1b \x01(\tR\alogFile\x12/\n" +
 	"\x13remove_intermediate\x18\x1c \x01(\bR\x12removeIntermediate\x12\x14\n" +
 	"\x05quiet\x18\x1d \x01(\bR\x05quiet\x12%\n" +
-	"\x0edocker_context\x18\x1e \x01(\tR\rdockerContext\"\x82\x01\n" +
+	"\x0edocker_context\x18\x1e \x01(\tR\rdockerContext\x12\x1a\n" +
+	"\bhermetic\x18\x1f \x01(\bR\bhermetic\x12#\n" +
+	"\rallow_network\x18  \x01(\bR\fallowNetwork\x120\n" +
+	"\x14allow_unpinned_bases\x18! \x01(\bR\x12allowUnpinnedBases\x12\x1d\n" +
+	"\n" +
+	"policy_ref\x18\" \x01(\tR\tpolicyRef\x12\x1f\n" +
+	"\vpolicy_mode\x18# \x01(\tR\n" +
+	"policyMode\x12!\n" +
+	"\fsecrets_mode\x18$ \x01(\tR\vsecretsMode\x12,\n" +
+	"\x12secrets_config_ref\x18% \x01(\tR\x10secretsConfigRef\x12\x1f\n" +
+	"\vattest_sbom\x18& \x01(\bR\n" +
+	"attestSbom\x12+\n" +
+	"\x11attest_provenance\x18' \x01(\bR\x10attestProvenance\x12'\n" +
+	"\x0fattestation_dir\x18( \x01(\tR\x0eattestationDir\x12'\n" +
+	"\x0frequire_sandbox\x18) \x01(\bR\x0erequireSandbox\x12.\n" +
+	"\x13git_skip_submodules\x18* \x01(\bR\x11gitSkipSubmodules\x12 \n" +
+	"\fgit_keep_dir\x18+ \x01(\bR\n" +
+	"gitKeepDir\x12\x17\n" +
+	"\agit_lfs\x18, \x01(\bR\x06gitLfs\x12\x16\n" +
+	"\x06labels\x18- \x03(\tR\x06labels\x12#\n" +
+	"\rinject_labels\x18. \x01(\bR\finjectLabels\x12*\n" +
+	"\x11inject_build_args\x18/ \x01(\bR\x0finjectBuildArgs\x12#\n" +
+	"\rimage_version\x180 \x01(\tR\fimageVersion\"\xb4\x01\n" +
 	"\x0fRunBuildRequest\x122\n" +
 	"\aoptions\x18\x01 \x01(\v2\x18.ktl.api.v1.BuildOptionsR\aoptions\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x1c\n" +
-	"\trequester\x18\x03 \x01(\tR\trequester\"u\n" +
+	"\trequester\x18\x03 \x01(\tR\trequester\x120\n" +
+	"\x14detach_on_disconnect\x18\x04 \x01(\bR\x12detachOnDisconnect\"u\n" +
 	"\vBuildResult\x12\x12\n" +
 	"\x04tags\x18\x01 \x03(\tR\x04tags\x12\x16\n" +
 	"\x06digest\x18\x02 \x01(\tR\x06digest\x12$\n" +
 	"\x0eoci_output_dir\x18\x03 \x01(\tR\fociOutputDir\x12\x14\n" +
-	"\x05error\x18\x04 \x01(\tR\x05error\"\xa0\x01\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"c\n" +
+	"\bBuildJob\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12*\n" +
+	"\x11started_unix_nano\x18\x03 \x01(\x03R\x0fstartedUnixNano\"\x89\x02\n" +
+	"\vBuildVertex\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06cached\x18\x04 \x01(\bR\x06cached\x12!\n" +
+	"\fstarted_unix\x18\x05 \x01(\x03R\vstartedUnix\x12%\n" +
+	"\x0ecompleted_unix\x18\x06 \x01(\x03R\rcompletedUnix\x12\x18\n" +
+	"\acurrent\x18\a \x01(\x03R\acurrent\x12\x14\n" +
+	"\x05total\x18\b \x01(\x03R\x05total\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x12\x16\n" +
+	"\x06inputs\x18\n" +
+	" \x03(\tR\x06inputs\"D\n" +
+	"\rBuildProgress\x123\n" +
+	"\bvertices\x18\x01 \x03(\v2\x17.ktl.api.v1.BuildVertexR\bvertices\"P\n" +
+	"\n" +
+	"BuildPhase\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xcf\x02\n" +
 	"\n" +
 	"BuildEvent\x12.\n" +
 	"\x13timestamp_unix_nano\x18\x01 \x01(\x03R\x11timestampUnixNano\x12'\n" +
 	"\x03log\x18\x02 \x01(\v2\x13.ktl.api.v1.LogLineH\x00R\x03log\x121\n" +
-	"\x06result\x18\x03 \x01(\v2\x17.ktl.api.v1.BuildResultH\x00R\x06resultB\x06\n" +
-	"\x04body\"!\n" +
+	"\x06result\x18\x03 \x01(\v2\x17.ktl.api.v1.BuildResultH\x00R\x06result\x12(\n" +
+	"\x03job\x18\x04 \x01(\v2\x14.ktl.api.v1.BuildJobH\x00R\x03job\x127\n" +
+	"\bprogress\x18\x05 \x01(\v2\x19.ktl.api.v1.BuildProgressH\x00R\bprogress\x12.\n" +
+	"\x05phase\x18\x06 \x01(\v2\x16.ktl.api.v1.BuildPhaseH\x00R\x05phase\x12\x1a\n" +
+	"\bsequence\x18\a \x01(\x04R\bsequenceB\x06\n" +
+	"\x04body\"P\n" +
+	"\x12AttachBuildRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12#\n" +
+	"\rfrom_sequence\x18\x02 \x01(\x04R\ffromSequence\"+\n" +
+	"\x12CancelBuildRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"!\n" +
 	"\vDeployEvent\x12\x12\n" +
 	"\x04json\x18\x01 \x01(\tR\x04json\"\xae\x04\n" +
 	"\x12DeployApplyOptions\x12\x18\n" +
@@ -3717,9 +4371,11 @@ const file_ktl_api_v1_agent_proto_rawDesc = "" +
 	"\n" +
 	"LogService\x12;\n" +
 	"\n" +
-	"StreamLogs\x12\x16.ktl.api.v1.LogRequest\x1a\x13.ktl.api.v1.LogLine0\x012Q\n" +
+	"StreamLogs\x12\x16.ktl.api.v1.LogRequest\x1a\x13.ktl.api.v1.LogLine0\x012\xdf\x01\n" +
 	"\fBuildService\x12A\n" +
-	"\bRunBuild\x12\x1b.ktl.api.v1.RunBuildRequest\x1a\x16.ktl.api.v1.BuildEvent0\x012\x9b\x01\n" +
+	"\bRunBuild\x12\x1b.ktl.api.v1.RunBuildRequest\x1a\x16.ktl.api.v1.BuildEvent0\x01\x12G\n" +
+	"\vAttachBuild\x12\x1e.ktl.api.v1.AttachBuildRequest\x1a\x16.ktl.api.v1.BuildEvent0\x01\x12C\n" +
+	"\vCancelBuild\x12\x1e.ktl.api.v1.CancelBuildRequest\x1a\x14.ktl.api.v1.BuildJob2\x9b\x01\n" +
 	"\rDeployService\x12B\n" +
 	"\x05Apply\x12\x1e.ktl.api.v1.DeployApplyRequest\x1a\x17.ktl.api.v1.DeployEvent0\x01\x12F\n" +
 	"\aDestroy\x12 .ktl.api.v1.DeployDestroyRequest\x1a\x17.ktl.api.v1.DeployEvent0\x012\xa5\x05\n" +
@@ -3751,7 +4407,7 @@ func file_ktl_api_v1_agent_proto_rawDescGZIP() []byte {
 }
 
 var file_ktl_api_v1_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_ktl_api_v1_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 44)
+var file_ktl_api_v1_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 50)
 var file_ktl_api_v1_agent_proto_goTypes = []any{
 	(MirrorSessionState)(0),               // 0: ktl.api.v1.MirrorSessionState
 	(*AgentInfoRequest)(nil),              // 1: ktl.api.v1.AgentInfoRequest
@@ -3761,109 +4417,123 @@ var file_ktl_api_v1_agent_proto_goTypes = []any{
 	(*BuildOptions)(nil),                  // 5: ktl.api.v1.BuildOptions
 	(*RunBuildRequest)(nil),               // 6: ktl.api.v1.RunBuildRequest
 	(*BuildResult)(nil),                   // 7: ktl.api.v1.BuildResult
-	(*BuildEvent)(nil),                    // 8: ktl.api.v1.BuildEvent
-	(*DeployEvent)(nil),                   // 9: ktl.api.v1.DeployEvent
-	(*DeployApplyOptions)(nil),            // 10: ktl.api.v1.DeployApplyOptions
-	(*DeployApplyRequest)(nil),            // 11: ktl.api.v1.DeployApplyRequest
-	(*DeployDestroyOptions)(nil),          // 12: ktl.api.v1.DeployDestroyOptions
-	(*DeployDestroyRequest)(nil),          // 13: ktl.api.v1.DeployDestroyRequest
-	(*MirrorFrame)(nil),                   // 14: ktl.api.v1.MirrorFrame
-	(*MirrorAck)(nil),                     // 15: ktl.api.v1.MirrorAck
-	(*MirrorSubscribeRequest)(nil),        // 16: ktl.api.v1.MirrorSubscribeRequest
-	(*MirrorSessionMeta)(nil),             // 17: ktl.api.v1.MirrorSessionMeta
-	(*MirrorSession)(nil),                 // 18: ktl.api.v1.MirrorSession
-	(*MirrorSessionStatus)(nil),           // 19: ktl.api.v1.MirrorSessionStatus
-	(*MirrorListSessionsRequest)(nil),     // 20: ktl.api.v1.MirrorListSessionsRequest
-	(*MirrorListSessionsResponse)(nil),    // 21: ktl.api.v1.MirrorListSessionsResponse
-	(*MirrorGetSessionRequest)(nil),       // 22: ktl.api.v1.MirrorGetSessionRequest
-	(*MirrorSetSessionMetaRequest)(nil),   // 23: ktl.api.v1.MirrorSetSessionMetaRequest
-	(*MirrorSetSessionStatusRequest)(nil), // 24: ktl.api.v1.MirrorSetSessionStatusRequest
-	(*MirrorDeleteSessionRequest)(nil),    // 25: ktl.api.v1.MirrorDeleteSessionRequest
-	(*MirrorDeleteSessionResponse)(nil),   // 26: ktl.api.v1.MirrorDeleteSessionResponse
-	(*MirrorExportRequest)(nil),           // 27: ktl.api.v1.MirrorExportRequest
-	(*MirrorExportChunk)(nil),             // 28: ktl.api.v1.MirrorExportChunk
-	(*VerifyChartOptions)(nil),            // 29: ktl.api.v1.VerifyChartOptions
-	(*VerifyNamespaceOptions)(nil),        // 30: ktl.api.v1.VerifyNamespaceOptions
-	(*VerifyOptions)(nil),                 // 31: ktl.api.v1.VerifyOptions
-	(*VerifyRequest)(nil),                 // 32: ktl.api.v1.VerifyRequest
-	(*VerifyEvent)(nil),                   // 33: ktl.api.v1.VerifyEvent
-	(*VerifyStarted)(nil),                 // 34: ktl.api.v1.VerifyStarted
-	(*VerifyProgress)(nil),                // 35: ktl.api.v1.VerifyProgress
-	(*VerifySubject)(nil),                 // 36: ktl.api.v1.VerifySubject
-	(*VerifyFinding)(nil),                 // 37: ktl.api.v1.VerifyFinding
-	(*VerifySummary)(nil),                 // 38: ktl.api.v1.VerifySummary
-	(*VerifyDone)(nil),                    // 39: ktl.api.v1.VerifyDone
-	nil,                                   // 40: ktl.api.v1.MirrorSession.TagsEntry
-	nil,                                   // 41: ktl.api.v1.MirrorListSessionsRequest.TagsEntry
-	nil,                                   // 42: ktl.api.v1.MirrorSetSessionMetaRequest.TagsEntry
-	nil,                                   // 43: ktl.api.v1.VerifyProgress.CountsByKindEntry
-	nil,                                   // 44: ktl.api.v1.VerifySummary.BySeverityEntry
+	(*BuildJob)(nil),                      // 8: ktl.api.v1.BuildJob
+	(*BuildVertex)(nil),                   // 9: ktl.api.v1.BuildVertex
+	(*BuildProgress)(nil),                 // 10: ktl.api.v1.BuildProgress
+	(*BuildPhase)(nil),                    // 11: ktl.api.v1.BuildPhase
+	(*BuildEvent)(nil),                    // 12: ktl.api.v1.BuildEvent
+	(*AttachBuildRequest)(nil),            // 13: ktl.api.v1.AttachBuildRequest
+	(*CancelBuildRequest)(nil),            // 14: ktl.api.v1.CancelBuildRequest
+	(*DeployEvent)(nil),                   // 15: ktl.api.v1.DeployEvent
+	(*DeployApplyOptions)(nil),            // 16: ktl.api.v1.DeployApplyOptions
+	(*DeployApplyRequest)(nil),            // 17: ktl.api.v1.DeployApplyRequest
+	(*DeployDestroyOptions)(nil),          // 18: ktl.api.v1.DeployDestroyOptions
+	(*DeployDestroyRequest)(nil),          // 19: ktl.api.v1.DeployDestroyRequest
+	(*MirrorFrame)(nil),                   // 20: ktl.api.v1.MirrorFrame
+	(*MirrorAck)(nil),                     // 21: ktl.api.v1.MirrorAck
+	(*MirrorSubscribeRequest)(nil),        // 22: ktl.api.v1.MirrorSubscribeRequest
+	(*MirrorSessionMeta)(nil),             // 23: ktl.api.v1.MirrorSessionMeta
+	(*MirrorSession)(nil),                 // 24: ktl.api.v1.MirrorSession
+	(*MirrorSessionStatus)(nil),           // 25: ktl.api.v1.MirrorSessionStatus
+	(*MirrorListSessionsRequest)(nil),     // 26: ktl.api.v1.MirrorListSessionsRequest
+	(*MirrorListSessionsResponse)(nil),    // 27: ktl.api.v1.MirrorListSessionsResponse
+	(*MirrorGetSessionRequest)(nil),       // 28: ktl.api.v1.MirrorGetSessionRequest
+	(*MirrorSetSessionMetaRequest)(nil),   // 29: ktl.api.v1.MirrorSetSessionMetaRequest
+	(*MirrorSetSessionStatusRequest)(nil), // 30: ktl.api.v1.MirrorSetSessionStatusRequest
+	(*MirrorDeleteSessionRequest)(nil),    // 31: ktl.api.v1.MirrorDeleteSessionRequest
+	(*MirrorDeleteSessionResponse)(nil),   // 32: ktl.api.v1.MirrorDeleteSessionResponse
+	(*MirrorExportRequest)(nil),           // 33: ktl.api.v1.MirrorExportRequest
+	(*MirrorExportChunk)(nil),             // 34: ktl.api.v1.MirrorExportChunk
+	(*VerifyChartOptions)(nil),            // 35: ktl.api.v1.VerifyChartOptions
+	(*VerifyNamespaceOptions)(nil),        // 36: ktl.api.v1.VerifyNamespaceOptions
+	(*VerifyOptions)(nil),                 // 37: ktl.api.v1.VerifyOptions
+	(*VerifyRequest)(nil),                 // 38: ktl.api.v1.VerifyRequest
+	(*VerifyEvent)(nil),                   // 39: ktl.api.v1.VerifyEvent
+	(*VerifyStarted)(nil),                 // 40: ktl.api.v1.VerifyStarted
+	(*VerifyProgress)(nil),                // 41: ktl.api.v1.VerifyProgress
+	(*VerifySubject)(nil),                 // 42: ktl.api.v1.VerifySubject
+	(*VerifyFinding)(nil),                 // 43: ktl.api.v1.VerifyFinding
+	(*VerifySummary)(nil),                 // 44: ktl.api.v1.VerifySummary
+	(*VerifyDone)(nil),                    // 45: ktl.api.v1.VerifyDone
+	nil,                                   // 46: ktl.api.v1.MirrorSession.TagsEntry
+	nil,                                   // 47: ktl.api.v1.MirrorListSessionsRequest.TagsEntry
+	nil,                                   // 48: ktl.api.v1.MirrorSetSessionMetaRequest.TagsEntry
+	nil,                                   // 49: ktl.api.v1.VerifyProgress.CountsByKindEntry
+	nil,                                   // 50: ktl.api.v1.VerifySummary.BySeverityEntry
 }
 var file_ktl_api_v1_agent_proto_depIdxs = []int32{
 	5,  // 0: ktl.api.v1.RunBuildRequest.options:type_name -> ktl.api.v1.BuildOptions
-	4,  // 1: ktl.api.v1.BuildEvent.log:type_name -> ktl.api.v1.LogLine
-	7,  // 2: ktl.api.v1.BuildEvent.result:type_name -> ktl.api.v1.BuildResult
-	10, // 3: ktl.api.v1.DeployApplyRequest.options:type_name -> ktl.api.v1.DeployApplyOptions
-	12, // 4: ktl.api.v1.DeployDestroyRequest.options:type_name -> ktl.api.v1.DeployDestroyOptions
-	4,  // 5: ktl.api.v1.MirrorFrame.log:type_name -> ktl.api.v1.LogLine
-	8,  // 6: ktl.api.v1.MirrorFrame.build:type_name -> ktl.api.v1.BuildEvent
-	9,  // 7: ktl.api.v1.MirrorFrame.deploy:type_name -> ktl.api.v1.DeployEvent
-	33, // 8: ktl.api.v1.MirrorFrame.verify:type_name -> ktl.api.v1.VerifyEvent
-	17, // 9: ktl.api.v1.MirrorSession.meta:type_name -> ktl.api.v1.MirrorSessionMeta
-	40, // 10: ktl.api.v1.MirrorSession.tags:type_name -> ktl.api.v1.MirrorSession.TagsEntry
-	19, // 11: ktl.api.v1.MirrorSession.status:type_name -> ktl.api.v1.MirrorSessionStatus
-	0,  // 12: ktl.api.v1.MirrorSessionStatus.state:type_name -> ktl.api.v1.MirrorSessionState
-	17, // 13: ktl.api.v1.MirrorListSessionsRequest.meta:type_name -> ktl.api.v1.MirrorSessionMeta
-	41, // 14: ktl.api.v1.MirrorListSessionsRequest.tags:type_name -> ktl.api.v1.MirrorListSessionsRequest.TagsEntry
-	0,  // 15: ktl.api.v1.MirrorListSessionsRequest.state:type_name -> ktl.api.v1.MirrorSessionState
-	18, // 16: ktl.api.v1.MirrorListSessionsResponse.sessions:type_name -> ktl.api.v1.MirrorSession
-	17, // 17: ktl.api.v1.MirrorSetSessionMetaRequest.meta:type_name -> ktl.api.v1.MirrorSessionMeta
-	42, // 18: ktl.api.v1.MirrorSetSessionMetaRequest.tags:type_name -> ktl.api.v1.MirrorSetSessionMetaRequest.TagsEntry
-	19, // 19: ktl.api.v1.MirrorSetSessionStatusRequest.status:type_name -> ktl.api.v1.MirrorSessionStatus
-	31, // 20: ktl.api.v1.VerifyRequest.options:type_name -> ktl.api.v1.VerifyOptions
-	29, // 21: ktl.api.v1.VerifyRequest.chart:type_name -> ktl.api.v1.VerifyChartOptions
-	30, // 22: ktl.api.v1.VerifyRequest.namespace:type_name -> ktl.api.v1.VerifyNamespaceOptions
-	34, // 23: ktl.api.v1.VerifyEvent.started:type_name -> ktl.api.v1.VerifyStarted
-	35, // 24: ktl.api.v1.VerifyEvent.progress:type_name -> ktl.api.v1.VerifyProgress
-	37, // 25: ktl.api.v1.VerifyEvent.finding:type_name -> ktl.api.v1.VerifyFinding
-	38, // 26: ktl.api.v1.VerifyEvent.summary:type_name -> ktl.api.v1.VerifySummary
-	39, // 27: ktl.api.v1.VerifyEvent.done:type_name -> ktl.api.v1.VerifyDone
-	43, // 28: ktl.api.v1.VerifyProgress.counts_by_kind:type_name -> ktl.api.v1.VerifyProgress.CountsByKindEntry
-	36, // 29: ktl.api.v1.VerifyFinding.subject:type_name -> ktl.api.v1.VerifySubject
-	44, // 30: ktl.api.v1.VerifySummary.by_severity:type_name -> ktl.api.v1.VerifySummary.BySeverityEntry
-	3,  // 31: ktl.api.v1.LogService.StreamLogs:input_type -> ktl.api.v1.LogRequest
-	6,  // 32: ktl.api.v1.BuildService.RunBuild:input_type -> ktl.api.v1.RunBuildRequest
-	11, // 33: ktl.api.v1.DeployService.Apply:input_type -> ktl.api.v1.DeployApplyRequest
-	13, // 34: ktl.api.v1.DeployService.Destroy:input_type -> ktl.api.v1.DeployDestroyRequest
-	14, // 35: ktl.api.v1.MirrorService.Publish:input_type -> ktl.api.v1.MirrorFrame
-	16, // 36: ktl.api.v1.MirrorService.Subscribe:input_type -> ktl.api.v1.MirrorSubscribeRequest
-	20, // 37: ktl.api.v1.MirrorService.ListSessions:input_type -> ktl.api.v1.MirrorListSessionsRequest
-	22, // 38: ktl.api.v1.MirrorService.GetSession:input_type -> ktl.api.v1.MirrorGetSessionRequest
-	23, // 39: ktl.api.v1.MirrorService.SetSessionMeta:input_type -> ktl.api.v1.MirrorSetSessionMetaRequest
-	24, // 40: ktl.api.v1.MirrorService.SetSessionStatus:input_type -> ktl.api.v1.MirrorSetSessionStatusRequest
-	25, // 41: ktl.api.v1.MirrorService.DeleteSession:input_type -> ktl.api.v1.MirrorDeleteSessionRequest
-	27, // 42: ktl.api.v1.MirrorService.Export:input_type -> ktl.api.v1.MirrorExportRequest
-	32, // 43: ktl.api.v1.VerifyService.Verify:input_type -> ktl.api.v1.VerifyRequest
-	1,  // 44: ktl.api.v1.AgentInfoService.GetInfo:input_type -> ktl.api.v1.AgentInfoRequest
-	4,  // 45: ktl.api.v1.LogService.StreamLogs:output_type -> ktl.api.v1.LogLine
-	8,  // 46: ktl.api.v1.BuildService.RunBuild:output_type -> ktl.api.v1.BuildEvent
-	9,  // 47: ktl.api.v1.DeployService.Apply:output_type -> ktl.api.v1.DeployEvent
-	9,  // 48: ktl.api.v1.DeployService.Destroy:output_type -> ktl.api.v1.DeployEvent
-	15, // 49: ktl.api.v1.MirrorService.Publish:output_type -> ktl.api.v1.MirrorAck
-	14, // 50: ktl.api.v1.MirrorService.Subscribe:output_type -> ktl.api.v1.MirrorFrame
-	21, // 51: ktl.api.v1.MirrorService.ListSessions:output_type -> ktl.api.v1.MirrorListSessionsResponse
-	18, // 52: ktl.api.v1.MirrorService.GetSession:output_type -> ktl.api.v1.MirrorSession
-	18, // 53: ktl.api.v1.MirrorService.SetSessionMeta:output_type -> ktl.api.v1.MirrorSession
-	18, // 54: ktl.api.v1.MirrorService.SetSessionStatus:output_type -> ktl.api.v1.MirrorSession
-	26, // 55: ktl.api.v1.MirrorService.DeleteSession:output_type -> ktl.api.v1.MirrorDeleteSessionResponse
-	28, // 56: ktl.api.v1.MirrorService.Export:output_type -> ktl.api.v1.MirrorExportChunk
-	33, // 57: ktl.api.v1.VerifyService.Verify:output_type -> ktl.api.v1.VerifyEvent
-	2,  // 58: ktl.api.v1.AgentInfoService.GetInfo:output_type -> ktl.api.v1.AgentInfo
-	45, // [45:59] is the sub-list for method output_type
-	31, // [31:45] is the sub-list for method input_type
-	31, // [31:31] is the sub-list for extension type_name
-	31, // [31:31] is the sub-list for extension extendee
-	0,  // [0:31] is the sub-list for field type_name
+	9,  // 1: ktl.api.v1.BuildProgress.vertices:type_name -> ktl.api.v1.BuildVertex
+	4,  // 2: ktl.api.v1.BuildEvent.log:type_name -> ktl.api.v1.LogLine
+	7,  // 3: ktl.api.v1.BuildEvent.result:type_name -> ktl.api.v1.BuildResult
+	8,  // 4: ktl.api.v1.BuildEvent.job:type_name -> ktl.api.v1.BuildJob
+	10, // 5: ktl.api.v1.BuildEvent.progress:type_name -> ktl.api.v1.BuildProgress
+	11, // 6: ktl.api.v1.BuildEvent.phase:type_name -> ktl.api.v1.BuildPhase
+	16, // 7: ktl.api.v1.DeployApplyRequest.options:type_name -> ktl.api.v1.DeployApplyOptions
+	18, // 8: ktl.api.v1.DeployDestroyRequest.options:type_name -> ktl.api.v1.DeployDestroyOptions
+	4,  // 9: ktl.api.v1.MirrorFrame.log:type_name -> ktl.api.v1.LogLine
+	12, // 10: ktl.api.v1.MirrorFrame.build:type_name -> ktl.api.v1.BuildEvent
+	15, // 11: ktl.api.v1.MirrorFrame.deploy:type_name -> ktl.api.v1.DeployEvent
+	39, // 12: ktl.api.v1.MirrorFrame.verify:type_name -> ktl.api.v1.VerifyEvent
+	23, // 13: ktl.api.v1.MirrorSession.meta:type_name -> ktl.api.v1.MirrorSessionMeta
+	46, // 14: ktl.api.v1.MirrorSession.tags:type_name -> ktl.api.v1.MirrorSession.TagsEntry
+	25, // 15: ktl.api.v1.MirrorSession.status:type_name -> ktl.api.v1.MirrorSessionStatus
+	0,  // 16: ktl.api.v1.MirrorSessionStatus.state:type_name -> ktl.api.v1.MirrorSessionState
+	23, // 17: ktl.api.v1.MirrorListSessionsRequest.meta:type_name -> ktl.api.v1.MirrorSessionMeta
+	47, // 18: ktl.api.v1.MirrorListSessionsRequest.tags:type_name -> ktl.api.v1.MirrorListSessionsRequest.TagsEntry
+	0,  // 19: ktl.api.v1.MirrorListSessionsRequest.state:type_name -> ktl.api.v1.MirrorSessionState
+	24, // 20: ktl.api.v1.MirrorListSessionsResponse.sessions:type_name -> ktl.api.v1.MirrorSession
+	23, // 21: ktl.api.v1.MirrorSetSessionMetaRequest.meta:type_name -> ktl.api.v1.MirrorSessionMeta
+	48, // 22: ktl.api.v1.MirrorSetSessionMetaRequest.tags:type_name -> ktl.api.v1.MirrorSetSessionMetaRequest.TagsEntry
+	25, // 23: ktl.api.v1.MirrorSetSessionStatusRequest.status:type_name -> ktl.api.v1.MirrorSessionStatus
+	37, // 24: ktl.api.v1.VerifyRequest.options:type_name -> ktl.api.v1.VerifyOptions
+	35, // 25: ktl.api.v1.VerifyRequest.chart:type_name -> ktl.api.v1.VerifyChartOptions
+	36, // 26: ktl.api.v1.VerifyRequest.namespace:type_name -> ktl.api.v1.VerifyNamespaceOptions
+	40, // 27: ktl.api.v1.VerifyEvent.started:type_name -> ktl.api.v1.VerifyStarted
+	41, // 28: ktl.api.v1.VerifyEvent.progress:type_name -> ktl.api.v1.VerifyProgress
+	43, // 29: ktl.api.v1.VerifyEvent.finding:type_name -> ktl.api.v1.VerifyFinding
+	44, // 30: ktl.api.v1.VerifyEvent.summary:type_name -> ktl.api.v1.VerifySummary
+	45, // 31: ktl.api.v1.VerifyEvent.done:type_name -> ktl.api.v1.VerifyDone
+	49, // 32: ktl.api.v1.VerifyProgress.counts_by_kind:type_name -> ktl.api.v1.VerifyProgress.CountsByKindEntry
+	42, // 33: ktl.api.v1.VerifyFinding.subject:type_name -> ktl.api.v1.VerifySubject
+	50, // 34: ktl.api.v1.VerifySummary.by_severity:type_name -> ktl.api.v1.VerifySummary.BySeverityEntry
+	3,  // 35: ktl.api.v1.LogService.StreamLogs:input_type -> ktl.api.v1.LogRequest
+	6,  // 36: ktl.api.v1.BuildService.RunBuild:input_type -> ktl.api.v1.RunBuildRequest
+	13, // 37: ktl.api.v1.BuildService.AttachBuild:input_type -> ktl.api.v1.AttachBuildRequest
+	14, // 38: ktl.api.v1.BuildService.CancelBuild:input_type -> ktl.api.v1.CancelBuildRequest
+	17, // 39: ktl.api.v1.DeployService.Apply:input_type -> ktl.api.v1.DeployApplyRequest
+	19, // 40: ktl.api.v1.DeployService.Destroy:input_type -> ktl.api.v1.DeployDestroyRequest
+	20, // 41: ktl.api.v1.MirrorService.Publish:input_type -> ktl.api.v1.MirrorFrame
+	22, // 42: ktl.api.v1.MirrorService.Subscribe:input_type -> ktl.api.v1.MirrorSubscribeRequest
+	26, // 43: ktl.api.v1.MirrorService.ListSessions:input_type -> ktl.api.v1.MirrorListSessionsRequest
+	28, // 44: ktl.api.v1.MirrorService.GetSession:input_type -> ktl.api.v1.MirrorGetSessionRequest
+	29, // 45: ktl.api.v1.MirrorService.SetSessionMeta:input_type -> ktl.api.v1.MirrorSetSessionMetaRequest
+	30, // 46: ktl.api.v1.MirrorService.SetSessionStatus:input_type -> ktl.api.v1.MirrorSetSessionStatusRequest
+	31, // 47: ktl.api.v1.MirrorService.DeleteSession:input_type -> ktl.api.v1.MirrorDeleteSessionRequest
+	33, // 48: ktl.api.v1.MirrorService.Export:input_type -> ktl.api.v1.MirrorExportRequest
+	38, // 49: ktl.api.v1.VerifyService.Verify:input_type -> ktl.api.v1.VerifyRequest
+	1,  // 50: ktl.api.v1.AgentInfoService.GetInfo:input_type -> ktl.api.v1.AgentInfoRequest
+	4,  // 51: ktl.api.v1.LogService.StreamLogs:output_type -> ktl.api.v1.LogLine
+	12, // 52: ktl.api.v1.BuildService.RunBuild:output_type -> ktl.api.v1.BuildEvent
+	12, // 53: ktl.api.v1.BuildService.AttachBuild:output_type -> ktl.api.v1.BuildEvent
+	8,  // 54: ktl.api.v1.BuildService.CancelBuild:output_type -> ktl.api.v1.BuildJob
+	15, // 55: ktl.api.v1.DeployService.Apply:output_type -> ktl.api.v1.DeployEvent
+	15, // 56: ktl.api.v1.DeployService.Destroy:output_type -> ktl.api.v1.DeployEvent
+	21, // 57: ktl.api.v1.MirrorService.Publish:output_type -> ktl.api.v1.MirrorAck
+	20, // 58: ktl.api.v1.MirrorService.Subscribe:output_type -> ktl.api.v1.MirrorFrame
+	27, // 59: ktl.api.v1.MirrorService.ListSessions:output_type -> ktl.api.v1.MirrorListSessionsResponse
+	24, // 60: ktl.api.v1.MirrorService.GetSession:output_type -> ktl.api.v1.MirrorSession
+	24, // 61: ktl.api.v1.MirrorService.SetSessionMeta:output_type -> ktl.api.v1.MirrorSession
+	24, // 62: ktl.api.v1.MirrorService.SetSessionStatus:output_type -> ktl.api.v1.MirrorSession
+	32, // 63: ktl.api.v1.MirrorService.DeleteSession:output_type -> ktl.api.v1.MirrorDeleteSessionResponse
+	34, // 64: ktl.api.v1.MirrorService.Export:output_type -> ktl.api.v1.MirrorExportChunk
+	39, // 65: ktl.api.v1.VerifyService.Verify:output_type -> ktl.api.v1.VerifyEvent
+	2,  // 66: ktl.api.v1.AgentInfoService.GetInfo:output_type -> ktl.api.v1.AgentInfo
+	51, // [51:67] is the sub-list for method output_type
+	35, // [35:51] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
 }
 
 func init() { file_ktl_api_v1_agent_proto_init() }
@@ -3871,22 +4541,25 @@ func file_ktl_api_v1_agent_proto_init() {
 	if File_ktl_api_v1_agent_proto != nil {
 		return
 	}
-	file_ktl_api_v1_agent_proto_msgTypes[7].OneofWrappers = []any{
+	file_ktl_api_v1_agent_proto_msgTypes[11].OneofWrappers = []any{
 		(*BuildEvent_Log)(nil),
 		(*BuildEvent_Result)(nil),
+		(*BuildEvent_Job)(nil),
+		(*BuildEvent_Progress)(nil),
+		(*BuildEvent_Phase)(nil),
 	}
-	file_ktl_api_v1_agent_proto_msgTypes[13].OneofWrappers = []any{
+	file_ktl_api_v1_agent_proto_msgTypes[19].OneofWrappers = []any{
 		(*MirrorFrame_Log)(nil),
 		(*MirrorFrame_Build)(nil),
 		(*MirrorFrame_Deploy)(nil),
 		(*MirrorFrame_Verify)(nil),
 		(*MirrorFrame_Raw)(nil),
 	}
-	file_ktl_api_v1_agent_proto_msgTypes[31].OneofWrappers = []any{
+	file_ktl_api_v1_agent_proto_msgTypes[37].OneofWrappers = []any{
 		(*VerifyRequest_Chart)(nil),
 		(*VerifyRequest_Namespace)(nil),
 	}
-	file_ktl_api_v1_agent_proto_msgTypes[32].OneofWrappers = []any{
+	file_ktl_api_v1_agent_proto_msgTypes[38].OneofWrappers = []any{
 		(*VerifyEvent_Started)(nil),
 		(*VerifyEvent_Progress)(nil),
 		(*VerifyEvent_Finding)(nil),
@@ -3900,7 +4573,7 @@ func file_ktl_api_v1_agent_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ktl_api_v1_agent_proto_rawDesc), len(file_ktl_api_v1_agent_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   44,
+			NumMessages:   50,
 			NumExtensions: 0,
 			NumServices:   6,
 		},
@@ -124,7 +124,9 @@ var LogService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	BuildService_RunBuild_FullMethodName = "/ktl.api.v1.BuildService/RunBuild"
+	BuildService_RunBuild_FullMethodName    = "/ktl.api.v1.BuildService/RunBuild"
+	BuildService_AttachBuild_FullMethodName = "/ktl.api.v1.BuildService/AttachBuild"
+	BuildService_CancelBuild_FullMethodName = "/ktl.api.v1.BuildService/CancelBuild"
 )
 
 // BuildServiceClient is the client API for BuildService service.
@@ -132,6 +134,9 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type BuildServiceClient interface {
 	RunBuild(ctx context.Context, in *RunBuildRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BuildEvent], error)
+	// AttachBuild streams a running or recently finished build started by RunBuild.
+	AttachBuild(ctx context.Context, in *AttachBuildRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BuildEvent], error)
+	CancelBuild(ctx context.Context, in *CancelBuildRequest, opts ...grpc.CallOption) (*BuildJob, error)
 }
 
 type buildServiceClient struct {
@@ -161,11 +166,43 @@ func (c *buildServiceClient) RunBuild(ctx context.Context, in *RunBuildRequest,
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BuildService_RunBuildClient = grpc.ServerStreamingClient[BuildEvent]
 
+func (c *buildServiceClient) AttachBuild(ctx context.Context, in *AttachBuildRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BuildEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BuildService_ServiceDesc.Streams[1], BuildService_AttachBuild_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AttachBuildRequest, BuildEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_AttachBuildClient = grpc.ServerStreamingClient[BuildEvent]
+
+func (c *buildServiceClient) CancelBuild(ctx context.Context, in *CancelBuildRequest, opts ...grpc.CallOption) (*BuildJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BuildJob)
+	err := c.cc.Invoke(ctx, BuildService_CancelBuild_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BuildServiceServer is the server API for BuildService service.
 // All implementations must embed UnimplementedBuildServiceServer
 // for forward compatibility.
 type BuildServiceServer interface {
 	RunBuild(*RunBuildRequest, grpc.ServerStreamingServer[BuildEvent]) error
+	// AttachBuild streams a running or recently finished build started by RunBuild.
+	AttachBuild(*AttachBuildRequest, grpc.ServerStreamingServer[BuildEvent]) error
+	CancelBuild(context.Context, *CancelBuildRequest) (*BuildJob, error)
 	mustEmbedUnimplementedBuildServiceServer()
 }
 
@@ -179,6 +216,12 @@ type UnimplementedBuildServiceServer struct{}
 func (UnimplementedBuildServiceServer) RunBuild(*RunBuildRequest, grpc.ServerStreamingServer[BuildEvent]) error {
 	return status.Error(codes.Unimplemented, "method RunBuild not implemented")
 }
+func (UnimplementedBuildServiceServer) AttachBuild(*AttachBuildRequest, grpc.ServerStreamingServer[BuildEvent]) error {
+	return status.Error(codes.Unimplemented, "method AttachBuild not implemented")
+}
+func (UnimplementedBuildServiceServer) CancelBuild(context.Context, *CancelBuildRequest) (*BuildJob, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelBuild not implemented")
+}
 func (UnimplementedBuildServiceServer) mustEmbedUnimplementedBuildServiceServer() {}
 func (UnimplementedBuildServiceServer) testEmbeddedByValue()                      {}
 
@@ -211,19 +254,58 @@ func _BuildService_RunBuild_Handler(srv interface{}, stream grpc.ServerStream) e
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type BuildService_RunBuildServer = grpc.ServerStreamingServer[BuildEvent]
 
+func _BuildService_AttachBuild_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AttachBuildRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BuildServiceServer).AttachBuild(m, &grpc.GenericServerStream[AttachBuildRequest, BuildEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BuildService_AttachBuildServer = grpc.ServerStreamingServer[BuildEvent]
+
+func _BuildService_CancelBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildServiceServer).CancelBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BuildService_CancelBuild_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildServiceServer).CancelBuild(ctx, req.(*CancelBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BuildService_ServiceDesc is the grpc.ServiceDesc for BuildService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var BuildService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "ktl.api.v1.BuildService",
 	HandlerType: (*BuildServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CancelBuild",
+			Handler:    _BuildService_CancelBuild_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "RunBuild",
 			Handler:       _BuildService_RunBuild_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "AttachBuild",
+			Handler:       _BuildService_AttachBuild_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "ktl/api/v1/agent.proto",
 }
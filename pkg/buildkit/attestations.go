@@ -71,6 +71,9 @@ type ociManifest struct {
 	Subject       *struct {
 		Digest string `json:"digest"`
 	} `json:"subject"`
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
 	Layers []struct {
 		MediaType    string            `json:"mediaType"`
 		Digest       string            `json:"digest"`
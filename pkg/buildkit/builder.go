@@ -30,24 +30,44 @@ func BuildDockerfile(ctx context.Context, opts DockerfileBuildOptions) (*BuildRe
 		opts.ContextDir = "."
 	}
 
-	absContext, err := filepath.Abs(opts.ContextDir)
-	if err != nil {
-		return nil, fmt.Errorf("resolve context: %w", err)
-	}
-	if err := ensureDirExists(absContext); err != nil {
-		return nil, fmt.Errorf("context %s: %w", absContext, err)
-	}
+	var (
+		absContext     string
+		dockerfileDir  string
+		dockerfileName string
+		err            error
+	)
+	if opts.GitContext != nil {
+		// Remote contexts have no local directory; OCI layouts land under the working directory.
+		if absContext, err = filepath.Abs("."); err != nil {
+			return nil, fmt.Errorf("resolve working directory: %w", err)
+		}
+		if opts.OCIOutputPath == "" {
+			opts.OCIOutputPath = filepath.Join(DefaultOCIOutputDir(absContext), sanitizePathName(opts.GitContext.Name()))
+		}
+		dockerfileName = filepath.ToSlash(strings.TrimPrefix(opts.DockerfilePath, "./"))
+		if dockerfileName == "" {
+			dockerfileName = "Dockerfile"
+		}
+	} else {
+		absContext, err = filepath.Abs(opts.ContextDir)
+		if err != nil {
+			return nil, fmt.Errorf("resolve context: %w", err)
+		}
+		if err := ensureDirExists(absContext); err != nil {
+			return nil, fmt.Errorf("context %s: %w", absContext, err)
+		}
 
-	dockerfilePath := opts.DockerfilePath
-	if dockerfilePath == "" {
-		dockerfilePath = filepath.Join(absContext, "Dockerfile")
-	}
-	if !filepath.IsAbs(dockerfilePath) {
-		dockerfilePath = filepath.Join(absContext, dockerfilePath)
-	}
-	dockerfileDir, dockerfileName, err := splitDockerfile(dockerfilePath)
-	if err != nil {
-		return nil, err
+		dockerfilePath := opts.DockerfilePath
+		if dockerfilePath == "" {
+			dockerfilePath = filepath.Join(absContext, "Dockerfile")
+		}
+		if !filepath.IsAbs(dockerfilePath) {
+			dockerfilePath = filepath.Join(absContext, dockerfilePath)
+		}
+		dockerfileDir, dockerfileName, err = splitDockerfile(dockerfilePath)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if len(opts.Platforms) > 0 {
@@ -136,11 +156,21 @@ func BuildDockerfile(ctx context.Context, opts DockerfileBuildOptions) (*BuildRe
 	for _, k := range keys {
 		frontendAttrs["build-arg:"+k] = opts.BuildArgs[k]
 	}
+	for k, v := range opts.Labels {
+		frontendAttrs["label:"+k] = v
+	}
 
 	localDirs := map[string]string{
 		"context":    absContext,
 		"dockerfile": dockerfileDir,
 	}
+	if opts.GitContext != nil {
+		frontendAttrs["context"] = opts.GitContext.String()
+		if opts.GitContext.KeepGitDir {
+			frontendAttrs["build-arg:BUILDKIT_CONTEXT_KEEP_GIT_DIR"] = "1"
+		}
+		localDirs = nil
+	}
 
 	attachable, err := buildSessionAttachables(dockerCfg, opts)
 	if err != nil {
@@ -0,0 +1,93 @@
+package buildkit
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/dfgitutil"
+)
+
+// GitContext is a build context that BuildKit fetches from a git repository instead of a local
+// directory, e.g. https://github.com/acme/app.git#v1.2.3:services/api.
+type GitContext struct {
+	// Remote is the repository URL without ref or subdirectory.
+	Remote string
+	// Ref is a branch, tag, or commit; empty means the remote's default branch.
+	Ref string
+	// SubDir is the directory inside the repository used as the context root.
+	SubDir string
+	// SkipSubmodules stops BuildKit from checking out git submodules.
+	SkipSubmodules bool
+	// KeepGitDir keeps the .git directory in the context (for builds that run git commands).
+	KeepGitDir bool
+}
+
+// ParseGitContext reports whether ref names a remote git context (git://, ssh://, git@host:path,
+// or an http(s) URL ending in .git, with an optional #ref:subdir fragment) and parses it.
+// Existing local paths are never treated as git contexts.
+func ParseGitContext(ref string) (GitContext, bool, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || !looksLikeGitContext(ref) {
+		return GitContext{}, false, nil
+	}
+	if _, err := os.Stat(ref); err == nil {
+		return GitContext{}, false, nil
+	}
+	parsed, isGit, err := dfgitutil.ParseGitRef(ref)
+	if !isGit {
+		return GitContext{}, false, nil
+	}
+	if err != nil {
+		return GitContext{}, true, fmt.Errorf("parse git context %q: %w", ref, err)
+	}
+	g := GitContext{Remote: parsed.Remote, Ref: parsed.Ref, SubDir: strings.Trim(parsed.SubDir, "/")}
+	if parsed.Submodules != nil {
+		g.SkipSubmodules = !*parsed.Submodules
+	}
+	if parsed.KeepGitDir != nil {
+		g.KeepGitDir = *parsed.KeepGitDir
+	}
+	return g, true, nil
+}
+
+// IsGitContext reports whether ref parses as a remote git context.
+func IsGitContext(ref string) bool {
+	_, ok, _ := ParseGitContext(ref)
+	return ok
+}
+
+func looksLikeGitContext(ref string) bool {
+	for _, prefix := range []string{"git://", "ssh://", "git@", "https://", "http://"} {
+		if strings.HasPrefix(ref, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the context in the fragment form understood by every dockerfile frontend; the
+// submodules query is only added when submodules are skipped.
+func (g GitContext) String() string {
+	out := g.Remote
+	if g.SkipSubmodules {
+		out += "?" + url.Values{"submodules": {"false"}}.Encode()
+	}
+	if g.Ref != "" || g.SubDir != "" {
+		out += "#" + g.Ref
+		if g.SubDir != "" {
+			out += ":" + g.SubDir
+		}
+	}
+	return out
+}
+
+// Name is a short label for the context: the repository name plus the subdirectory, if any.
+func (g GitContext) Name() string {
+	name := strings.TrimSuffix(g.Remote[strings.LastIndexAny(g.Remote, "/:")+1:], ".git")
+	if g.SubDir != "" {
+		name += "-" + g.SubDir[strings.LastIndex(g.SubDir, "/")+1:]
+	}
+	return name
+}
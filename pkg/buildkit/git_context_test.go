@@ -0,0 +1,67 @@
+package buildkit
+
+import (
+	"testing"
+)
+
+func TestParseGitContext(t *testing.T) {
+	cases := []struct {
+		in   string
+		want GitContext
+		str  string
+		name string
+	}{
+		{
+			in:   "https://github.com/acme/app.git#v1.2.3:services/api",
+			want: GitContext{Remote: "https://github.com/acme/app.git", Ref: "v1.2.3", SubDir: "services/api"},
+			str:  "https://github.com/acme/app.git#v1.2.3:services/api",
+			name: "app-api",
+		},
+		{
+			in:   "git@github.com:acme/app.git#main",
+			want: GitContext{Remote: "git@github.com:acme/app.git", Ref: "main"},
+			str:  "git@github.com:acme/app.git#main",
+			name: "app",
+		},
+		{
+			in:   "git://github.com/acme/app#:deploy",
+			want: GitContext{Remote: "git://github.com/acme/app", SubDir: "deploy"},
+			str:  "git://github.com/acme/app#:deploy",
+			name: "app-deploy",
+		},
+	}
+	for _, tc := range cases {
+		got, ok, err := ParseGitContext(tc.in)
+		if err != nil || !ok {
+			t.Fatalf("ParseGitContext(%q) = %v, %v", tc.in, ok, err)
+		}
+		if got != tc.want {
+			t.Fatalf("ParseGitContext(%q) = %+v, want %+v", tc.in, got, tc.want)
+		}
+		if got.String() != tc.str {
+			t.Fatalf("String() = %q, want %q", got.String(), tc.str)
+		}
+		if got.Name() != tc.name {
+			t.Fatalf("Name() = %q, want %q", got.Name(), tc.name)
+		}
+	}
+}
+
+func TestParseGitContextSkipSubmodulesRoundTrip(t *testing.T) {
+	g := GitContext{Remote: "https://github.com/acme/app.git", Ref: "main", SubDir: "api", SkipSubmodules: true}
+	got, ok, err := ParseGitContext(g.String())
+	if err != nil || !ok {
+		t.Fatalf("ParseGitContext(%q) = %v, %v", g.String(), ok, err)
+	}
+	if got != g {
+		t.Fatalf("round trip = %+v, want %+v", got, g)
+	}
+}
+
+func TestParseGitContextIgnoresLocalPaths(t *testing.T) {
+	for _, in := range []string{".", "./services/api", "/tmp/app", "", t.TempDir()} {
+		if IsGitContext(in) {
+			t.Fatalf("expected %q to be a local context", in)
+		}
+	}
+}
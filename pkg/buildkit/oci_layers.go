@@ -14,18 +14,47 @@ type OCILayerInfo struct {
 	Digest      string
 	Size        int64
 	MediaType   string
+	// CreatedBy is the image config history entry that produced the layer (e.g. "RUN /bin/sh -c make # buildkit"), if known.
+	CreatedBy string
+}
+
+// ociImageConfig is the part of an image config blob needed to attribute layers to instructions.
+type ociImageConfig struct {
+	History []struct {
+		CreatedBy  string `json:"created_by"`
+		EmptyLayer bool   `json:"empty_layer"`
+	} `json:"history"`
 }
 
 // TopOCILayers returns the largest layers (by compressed size) for images present in an OCI layout directory.
 // Best-effort: ignores non-image manifests and any blobs it cannot parse.
 func TopOCILayers(ociLayoutDir string, topN int) ([]OCILayerInfo, error) {
+	if topN <= 0 {
+		topN = 10
+	}
+	layers, err := OCILayers(ociLayoutDir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(layers, func(i, j int) bool {
+		if layers[i].Size == layers[j].Size {
+			return layers[i].Digest < layers[j].Digest
+		}
+		return layers[i].Size > layers[j].Size
+	})
+	if len(layers) > topN {
+		layers = layers[:topN]
+	}
+	return layers, nil
+}
+
+// OCILayers returns every layer of the images in an OCI layout directory, in manifest order, with CreatedBy taken
+// from the image config history. Best-effort like TopOCILayers.
+func OCILayers(ociLayoutDir string) ([]OCILayerInfo, error) {
 	ociLayoutDir = strings.TrimSpace(ociLayoutDir)
 	if ociLayoutDir == "" {
 		return nil, fmt.Errorf("oci layout dir is empty")
 	}
-	if topN <= 0 {
-		topN = 10
-	}
 	var root ociIndex
 	if err := readJSON(filepath.Join(ociLayoutDir, "index.json"), &root); err != nil {
 		return nil, err
@@ -89,16 +118,21 @@ func TopOCILayers(ociLayoutDir string, topN int) ([]OCILayerInfo, error) {
 				return nil
 			}
 		}
-		for _, layer := range man.Layers {
+		createdBy := layerHistory(ociLayoutDir, man.Config.Digest)
+		for i, layer := range man.Layers {
 			if strings.TrimSpace(layer.Digest) == "" || layer.Size <= 0 {
 				continue
 			}
-			layers = append(layers, OCILayerInfo{
+			info := OCILayerInfo{
 				ImageDigest: digest,
 				Digest:      layer.Digest,
 				Size:        layer.Size,
 				MediaType:   layer.MediaType,
-			})
+			}
+			if i < len(createdBy) {
+				info.CreatedBy = createdBy[i]
+			}
+			layers = append(layers, info)
 		}
 		return nil
 	}
@@ -106,14 +140,26 @@ func TopOCILayers(ociLayoutDir string, topN int) ([]OCILayerInfo, error) {
 	if err := walkIndex(root); err != nil {
 		return nil, err
 	}
-	sort.Slice(layers, func(i, j int) bool {
-		if layers[i].Size == layers[j].Size {
-			return layers[i].Digest < layers[j].Digest
+	return layers, nil
+}
+
+// layerHistory returns the created_by of each non-empty history entry of an image config, which lines up with the
+// manifest's layers. It returns nil when the config cannot be read.
+func layerHistory(ociLayoutDir, configDigest string) []string {
+	parsed, err := parseSHA256Digest(configDigest)
+	if err != nil {
+		return nil
+	}
+	var cfg ociImageConfig
+	if err := readJSON(blobPath(ociLayoutDir, parsed), &cfg); err != nil {
+		return nil
+	}
+	out := make([]string, 0, len(cfg.History))
+	for _, h := range cfg.History {
+		if h.EmptyLayer {
+			continue
 		}
-		return layers[i].Size > layers[j].Size
-	})
-	if len(layers) > topN {
-		layers = layers[:topN]
+		out = append(out, strings.TrimSpace(h.CreatedBy))
 	}
-	return layers, nil
+	return out
 }
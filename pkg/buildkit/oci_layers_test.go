@@ -54,3 +54,38 @@ func TestTopOCILayers_ParsesLayers(t *testing.T) {
 		t.Fatalf("unexpected layer: %#v", layers[0])
 	}
 }
+
+func TestOCILayers_AttributesHistory(t *testing.T) {
+	tmp := t.TempDir()
+	blobs := filepath.Join(tmp, "blobs", "sha256")
+	if err := os.MkdirAll(blobs, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	manifestDigest := "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	configDigest := "sha256:dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd"
+	baseLayer := "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	runLayer := "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+
+	writeBlob := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(blobs, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write blob: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "index.json"), []byte(`{"manifests":[{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"`+manifestDigest+`"}]}`), 0o644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+	writeBlob(manifestDigest[len("sha256:"):], `{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"digest":"`+configDigest+`"},"layers":[{"digest":"`+baseLayer+`","size":100},{"digest":"`+runLayer+`","size":200}]}`)
+	writeBlob(configDigest[len("sha256:"):], `{"history":[{"created_by":"/bin/sh -c #(nop) ADD file:abc in /"},{"created_by":"ENV PATH=/bin","empty_layer":true},{"created_by":"RUN /bin/sh -c make # buildkit"}]}`)
+
+	layers, err := OCILayers(tmp)
+	if err != nil {
+		t.Fatalf("OCILayers: %v", err)
+	}
+	if len(layers) != 2 {
+		t.Fatalf("expected 2 layers, got %d", len(layers))
+	}
+	if layers[0].Digest != baseLayer || layers[1].CreatedBy != "RUN /bin/sh -c make # buildkit" {
+		t.Fatalf("unexpected layers: %#v", layers)
+	}
+}
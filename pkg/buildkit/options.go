@@ -41,9 +41,13 @@ type DockerfileBuildOptions struct {
 	AllowBuilderFallback bool
 	DockerContext        string
 	ContextDir           string
+	// GitContext, when set, replaces ContextDir: BuildKit fetches the context (and the Dockerfile,
+	// relative to its root) from the repository, so nothing is uploaded from the client.
+	GitContext           *GitContext
 	DockerfilePath       string
 	Platforms            []string
 	BuildArgs            map[string]string
+	Labels               map[string]string
 	Secrets              []Secret
 	Target               string
 	Tags                 []string
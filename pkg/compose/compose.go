@@ -47,6 +47,7 @@ type ComposeBuildOptions struct {
 	AttestSBOM           bool
 	Platforms            []string
 	BuildArgs            map[string]string
+	Labels               map[string]string
 	CacheExports         []CacheSpec
 	CacheImports         []CacheSpec
 	ExtraOutputs         []OutputSpec
@@ -419,6 +420,7 @@ func (r *composeRunner) buildComposeService(ctx context.Context, project *compos
 		DockerfilePath:       dockerfilePath,
 		Platforms:            servicePlatforms,
 		BuildArgs:            serviceArgs,
+		Labels:               mergeArgs(opts.Labels, svc.Build.Labels),
 		Target:               svc.Build.Target,
 		Tags:                 tags,
 		Push:                 opts.Push,